@@ -36,5 +36,8 @@ func DualTaggerParams() (tagger.DualParams, tagger.Params, tagger.RemoteParams)
 				}
 			},
 			RemoteFilter: types.NewFilterBuilder().Exclude(types.KubernetesPodUID).Build(types.HighCardinality),
+			RemoteFilterExpression: func(c config.Component) string {
+				return c.GetString("clc_runner_remote_tagger_filter")
+			},
 		}
 }
@@ -0,0 +1,160 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package checkschedule implements 'agent check-schedule'.
+package checkschedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/command"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/pkg/api/util"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+// cliParams are the command-line arguments for this subcommand
+type cliParams struct {
+	*command.GlobalParams
+
+	// action is the endpoint to call, one of pause, resume or run-now
+	action  string
+	checkID string
+}
+
+var actionDescriptions = map[string]string{
+	"pause":   "Stop running a check instance on its schedule",
+	"resume":  "Resume running a paused check instance on its schedule",
+	"run-now": "Run a check instance right away, even if it is paused",
+}
+
+var actionResults = map[string]string{
+	"pause":   "paused",
+	"resume":  "resumed",
+	"run-now": "scheduled for an immediate run",
+}
+
+// Commands returns a slice of subcommands for the 'agent' command.
+func Commands(globalParams *command.GlobalParams) []*cobra.Command {
+	cliParams := &cliParams{
+		GlobalParams: globalParams,
+	}
+
+	scheduleCmd := &cobra.Command{
+		Use:   "check-schedule",
+		Short: "Control the schedule of the check instances of the running agent",
+		Long: `Pause, resume or trigger an immediate run of a check instance of the running agent.
+Check instances are designated by their ID, as reported by 'agent status'. A paused check stays
+paused when it is reloaded with the same configuration, until it is resumed or the agent restarts.`,
+	}
+
+	for _, action := range []string{"pause", "resume", "run-now"} {
+		scheduleCmd.AddCommand(&cobra.Command{
+			Use:   action + " <check ID>",
+			Short: actionDescriptions[action],
+			Args:  cobra.ExactArgs(1),
+			RunE: func(_ *cobra.Command, args []string) error {
+				cliParams.action = action
+				cliParams.checkID = args[0]
+				return fxutil.OneShot(controlCheck,
+					fx.Supply(cliParams),
+					fx.Supply(command.GetDefaultCoreBundleParams(cliParams.GlobalParams)),
+					core.Bundle(),
+				)
+			},
+		})
+	}
+
+	scheduleCmd.AddCommand(&cobra.Command{
+		Use:   "paused",
+		Short: "List the paused check instances",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return fxutil.OneShot(listPausedChecks,
+				fx.Supply(cliParams),
+				fx.Supply(command.GetDefaultCoreBundleParams(cliParams.GlobalParams)),
+				core.Bundle(),
+			)
+		},
+	})
+
+	return []*cobra.Command{scheduleCmd}
+}
+
+func controlCheck(_ log.Component, config config.Component, cliParams *cliParams) error {
+	baseURL, err := checksURL(config)
+	if err != nil {
+		return err
+	}
+
+	urlstr := fmt.Sprintf("%s/%s?id=%s", baseURL, cliParams.action, url.QueryEscape(cliParams.checkID))
+	r, err := util.DoPost(util.GetClient(false), urlstr, "application/json", nil)
+	if err != nil {
+		err = unmarshalError(r, err)
+		fmt.Printf("Could not %s check %s: %v\n", cliParams.action, cliParams.checkID, err)
+		return err
+	}
+
+	fmt.Printf("Check %s %s\n", cliParams.checkID, actionResults[cliParams.action])
+	return nil
+}
+
+func listPausedChecks(_ log.Component, config config.Component, _ *cliParams) error {
+	baseURL, err := checksURL(config)
+	if err != nil {
+		return err
+	}
+
+	r, err := util.DoGet(util.GetClient(false), baseURL+"/paused", util.LeaveConnectionOpen)
+	if err != nil {
+		err = unmarshalError(r, err)
+		fmt.Printf("Could not list the paused checks: %v\n", err)
+		return err
+	}
+
+	var ids []string
+	if err := json.Unmarshal(r, &ids); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		fmt.Println("No check is paused")
+		return nil
+	}
+	for _, id := range ids {
+		fmt.Println(id)
+	}
+	return nil
+}
+
+func checksURL(config config.Component) (string, error) {
+	ipcAddress, err := pkgconfigsetup.GetIPCAddress(config)
+	if err != nil {
+		return "", err
+	}
+	if err := util.SetAuthToken(config); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("https://%v:%v/agent/checks", ipcAddress, config.GetInt("cmd_port")), nil
+}
+
+// unmarshalError returns the error embedded in the response body, if any
+func unmarshalError(r []byte, err error) error {
+	var errMap = make(map[string]string)
+	json.Unmarshal(r, &errMap) //nolint:errcheck
+	// If the error has been marshalled into a json object, check it and return it properly
+	if e, found := errMap["error"]; found {
+		return errors.New(e)
+	}
+	return err
+}
@@ -0,0 +1,39 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checkschedule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/command"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func TestControlCommands(t *testing.T) {
+	for _, action := range []string{"pause", "resume", "run-now"} {
+		t.Run(action, func(t *testing.T) {
+			fxutil.TestOneShotSubcommand(t,
+				Commands(&command.GlobalParams{}),
+				[]string{"check-schedule", action, "http_check:web:1234abcd"},
+				controlCheck,
+				func(cliParams *cliParams, _ core.BundleParams) {
+					require.Equal(t, action, cliParams.action)
+					require.Equal(t, "http_check:web:1234abcd", cliParams.checkID)
+				})
+		})
+	}
+}
+
+func TestPausedCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"check-schedule", "paused"},
+		listPausedChecks,
+		func() {})
+}
@@ -0,0 +1,124 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package container implements 'agent container'.
+package container
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/command"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/pkg/api/util"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/process/containertop"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+// cliParams are the command-line arguments for this subcommand
+type cliParams struct {
+	*command.GlobalParams
+
+	containerID string
+	interval    time.Duration
+	jsonOutput  bool
+}
+
+// Commands returns a slice of subcommands for the 'agent' command.
+func Commands(globalParams *command.GlobalParams) []*cobra.Command {
+	cliParams := &cliParams{
+		GlobalParams: globalParams,
+	}
+
+	containerCmd := &cobra.Command{
+		Use:   "container",
+		Short: "Inspect the containers monitored by the running agent",
+		Long:  ``,
+	}
+
+	topCmd := &cobra.Command{
+		Use:   "top <container>",
+		Short: "Print the processes running in a container",
+		Long: `Print the processes running in a container along with their CPU and memory usage,
+as seen by the running agent. The container can be designated by its ID, a unique prefix of
+its ID, or its name.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			cliParams.containerID = args[0]
+			return fxutil.OneShot(containerTop,
+				fx.Supply(cliParams),
+				fx.Supply(command.GetDefaultCoreBundleParams(cliParams.GlobalParams)),
+				core.Bundle(),
+			)
+		},
+	}
+	topCmd.Flags().DurationVarP(&cliParams.interval, "interval", "i", containertop.DefaultInterval, "time between the two samples used to compute the CPU usage")
+	topCmd.Flags().BoolVarP(&cliParams.jsonOutput, "json", "j", false, "print out raw json")
+
+	containerCmd.AddCommand(topCmd)
+
+	return []*cobra.Command{containerCmd}
+}
+
+func containerTop(_ log.Component, config config.Component, cliParams *cliParams) error {
+	ipcAddress, err := pkgconfigsetup.GetIPCAddress(config)
+	if err != nil {
+		return err
+	}
+	if err := util.SetAuthToken(config); err != nil {
+		return err
+	}
+
+	urlstr := fmt.Sprintf("https://%v:%v/agent/container/%s/top?interval=%s",
+		ipcAddress, config.GetInt("cmd_port"), url.PathEscape(cliParams.containerID), cliParams.interval)
+	r, err := util.DoGet(util.GetClient(false), urlstr, util.LeaveConnectionOpen)
+	if err != nil {
+		var errMap = make(map[string]string)
+		json.Unmarshal(r, &errMap) //nolint:errcheck
+		// If the error has been marshalled into a json object, check it and return it properly
+		if e, found := errMap["error"]; found {
+			err = errors.New(e)
+		}
+		fmt.Printf("Could not list the processes of container %s: %v\n", cliParams.containerID, err)
+		return err
+	}
+
+	if cliParams.jsonOutput {
+		fmt.Println(string(r))
+		return nil
+	}
+
+	var top containertop.Response
+	if err := json.Unmarshal(r, &top); err != nil {
+		return err
+	}
+	printTop(os.Stdout, &top)
+	return nil
+}
+
+func printTop(w io.Writer, top *containertop.Response) {
+	fmt.Fprintf(w, "Container %s (%s, %s)\n\n", top.ContainerID, top.ContainerName, top.Runtime)
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "PID\tPPID\tUID\tSTATE\tTHREADS\tCPU%\tRSS\tCOMMAND")
+	for _, p := range top.Processes {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%s\t%d\t%.1f\t%s\t%s\n",
+			p.PID, p.PPID, p.UID, p.State, p.Threads, p.CPUPercent, humanize.IBytes(p.RSS), p.Command)
+	}
+	tw.Flush()
+}
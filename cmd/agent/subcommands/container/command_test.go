@@ -0,0 +1,51 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package container
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/command"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
+	"github.com/DataDog/datadog-agent/pkg/process/containertop"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func TestTopCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"container", "top", "abcdef", "--interval", "3s"},
+		containerTop,
+		func(cliParams *cliParams, _ core.BundleParams, secretParams secrets.Params) {
+			require.Equal(t, "abcdef", cliParams.containerID)
+			require.Equal(t, 3*time.Second, cliParams.interval)
+			require.Equal(t, false, secretParams.Enabled)
+		})
+}
+
+func TestPrintTop(t *testing.T) {
+	var b bytes.Buffer
+	printTop(&b, &containertop.Response{
+		ContainerID:   "abcdef",
+		ContainerName: "web",
+		Runtime:       "containerd",
+		Processes: []containertop.Process{
+			{PID: 11, PPID: 10, State: "R", Threads: 1, CPUPercent: 12.345, RSS: 2 << 20, Command: "worker"},
+		},
+	})
+
+	assert.Equal(t, `Container abcdef (web, containerd)
+
+PID  PPID  UID  STATE  THREADS  CPU%  RSS      COMMAND
+11   10    0    R      1        12.3  2.0 MiB  worker
+`, b.String())
+}
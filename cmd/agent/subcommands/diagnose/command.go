@@ -259,6 +259,20 @@ This command print the inventory-checks metadata payload. This payload is used b
 		},
 	}
 
+	payloadInventoriesFeaturesCmd := &cobra.Command{
+		Use:   "inventory-features",
+		Short: "[internal] Print the Inventory features metadata payload.",
+		Long: `
+This command print the inventory-features metadata payload. This payload reports which Agent subsystems are actually active, and why the enabled ones are not.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return fxutil.OneShot(printPayload,
+				fx.Supply(payloadName("inventory-features")),
+				fx.Supply(command.GetDefaultCoreBundleParams(cliParams.GlobalParams)),
+				core.Bundle(),
+			)
+		},
+	}
+
 	payloadInventoriesPkgSigningCmd := &cobra.Command{
 		Use:   "package-signing",
 		Short: "[internal] Print the Inventory package signing payload.",
@@ -322,6 +336,7 @@ This command print the security-agent metadata payload. This payload is used by
 	showPayloadCommand.AddCommand(payloadInventoriesOtelCmd)
 	showPayloadCommand.AddCommand(payloadInventoriesHaAgentCmd)
 	showPayloadCommand.AddCommand(payloadInventoriesChecksCmd)
+	showPayloadCommand.AddCommand(payloadInventoriesFeaturesCmd)
 	showPayloadCommand.AddCommand(payloadInventoriesPkgSigningCmd)
 	showPayloadCommand.AddCommand(payloadSystemProbeCmd)
 	showPayloadCommand.AddCommand(payloadSecurityAgentCmd)
@@ -86,6 +86,16 @@ func TestShowMetadataInventoryChecksCommand(t *testing.T) {
 		})
 }
 
+func TestShowMetadataInventoryFeaturesCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"diagnose", "show-metadata", "inventory-features"},
+		printPayload,
+		func(_ core.BundleParams, secretParams secrets.Params) {
+			require.Equal(t, false, secretParams.Enabled)
+		})
+}
+
 func TestShowMetadataInventoryOtelCommand(t *testing.T) {
 	fxutil.TestOneShotSubcommand(t,
 		Commands(&command.GlobalParams{}),
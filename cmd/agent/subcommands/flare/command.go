@@ -14,7 +14,9 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
@@ -85,6 +87,8 @@ type cliParams struct {
 	withStreamLogs       time.Duration
 	logLevelDefaultOff   command.LogLevelDefaultOff
 	providerTimeout      time.Duration
+	preview              bool
+	subsystem            string
 }
 
 // Commands returns a slice of subcommands for the 'agent' command.
@@ -178,6 +182,8 @@ func Commands(globalParams *command.GlobalParams) []*cobra.Command {
 	flareCmd.Flags().IntVarP(&cliParams.profileBlockingRate, "profile-blocking-rate", "", 10000, "Set the fraction of goroutine blocking events that are reported in the blocking profile")
 	flareCmd.Flags().DurationVarP(&cliParams.withStreamLogs, "with-stream-logs", "L", 0*time.Second, "Add stream-logs data to the flare. It will collect logs for the amount of seconds passed to the flag")
 	flareCmd.Flags().DurationVarP(&cliParams.providerTimeout, "provider-timeout", "t", 0*time.Second, "Timeout to run each flare provider in seconds. This is not a global timeout for the flare creation process.")
+	flareCmd.Flags().BoolVarP(&cliParams.preview, "preview", "", false, "List the files and the redactions the flare would include, without creating the archive nor sending it")
+	flareCmd.Flags().StringVarP(&cliParams.subsystem, "subsystem", "", "", fmt.Sprintf("Only collect the files relevant to a subsystem, one of: %s", strings.Join(helpers.FlareSubsystems(), ", ")))
 	flareCmd.SetArgs([]string{"caseID"})
 
 	return []*cobra.Command{flareCmd}
@@ -209,10 +215,23 @@ func makeFlare(flareComp flare.Component,
 	fmt.Fprintln(color.Output, color.BlueString("NEW: You can now generate a flare from the comfort of your Datadog UI!"))
 	fmt.Fprintln(color.Output, color.BlueString("See https://docs.datadoghq.com/agent/troubleshooting/send_a_flare/?tab=agentv6v7#send-a-flare-from-the-datadog-site for more info."))
 
+	if err := helpers.ValidateFlareSubsystem(cliParams.subsystem); err != nil {
+		return err
+	}
+	flareArgs := flaretypes.FlareArgs{
+		Preview:   cliParams.preview,
+		Subsystem: cliParams.subsystem,
+	}
+
 	warnings := config.Warnings()
 	if warnings != nil && warnings.Err != nil {
 		fmt.Fprintln(color.Error, color.YellowString("Config parsing warning: %v", warnings.Err))
 	}
+
+	if cliParams.preview {
+		return previewFlare(flareComp, flareArgs, cliParams)
+	}
+
 	caseID := ""
 	if len(cliParams.args) > 0 {
 		caseID = cliParams.args[0]
@@ -268,9 +287,9 @@ func makeFlare(flareComp flare.Component,
 
 	var filePath string
 	if cliParams.forceLocal {
-		filePath, err = createArchive(flareComp, profile, cliParams.providerTimeout, nil)
+		filePath, err = createArchive(flareComp, profile, cliParams.providerTimeout, nil, flareArgs)
 	} else {
-		filePath, err = requestArchive(flareComp, profile, cliParams.providerTimeout)
+		filePath, err = requestArchive(flareComp, profile, cliParams.providerTimeout, flareArgs)
 	}
 
 	if err != nil {
@@ -300,13 +319,74 @@ func makeFlare(flareComp flare.Component,
 	return nil
 }
 
-func requestArchive(flareComp flare.Component, pdata flaretypes.ProfileData, providerTimeout time.Duration) (string, error) {
+// previewFlare prints the content of the flare, as listed in the preview report, without creating nor sending it
+func previewFlare(flareComp flare.Component, flareArgs flaretypes.FlareArgs, cliParams *cliParams) error {
+	var (
+		reportPath string
+		err        error
+	)
+	if cliParams.forceLocal {
+		reportPath, err = createArchive(flareComp, nil, cliParams.providerTimeout, nil, flareArgs)
+	} else {
+		reportPath, err = requestArchive(flareComp, nil, cliParams.providerTimeout, flareArgs)
+	}
+	if err != nil {
+		return err
+	}
+
+	report, err := helpers.ReadPreviewReport(reportPath)
+	if err != nil {
+		fmt.Fprintln(color.Output, color.RedString(fmt.Sprintf("Could not read the flare preview report \"%s\": %s", reportPath, err)))
+		fmt.Fprintln(color.Output, color.RedString("If the agent running in a different container try the '--local' option to preview the flare locally"))
+		return err
+	}
+	defer os.Remove(reportPath)
+
+	printPreviewReport(report)
+	return nil
+}
+
+func printPreviewReport(report helpers.PreviewReport) {
+	fmt.Fprintln(color.Output, color.GreenString("Files included in the flare:"))
+	for _, file := range report.Files {
+		line := fmt.Sprintf("  %s (%d bytes)", file.Path, file.Size)
+		if !file.Scrubbed {
+			line += color.YellowString(" not scrubbed")
+		}
+		if len(file.Redactions) > 0 {
+			names := make([]string, 0, len(file.Redactions))
+			for name := range file.Redactions {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			redactions := make([]string, 0, len(names))
+			for _, name := range names {
+				redactions = append(redactions, fmt.Sprintf("%s: %d", name, file.Redactions[name]))
+			}
+			line += color.BlueString(" redactions [%s]", strings.Join(redactions, ", "))
+		}
+		fmt.Fprintln(color.Output, line)
+	}
+
+	if len(report.Skipped) > 0 {
+		fmt.Fprintln(color.Output, color.GreenString("Files left out of the flare:"))
+		for _, file := range report.Skipped {
+			fmt.Fprintf(color.Output, "  %s: %s\n", file.Path, file.Reason)
+		}
+	}
+
+	fmt.Fprintf(color.Output, "Total size: %d bytes in %d files, before compression\n", report.TotalSize, len(report.Files))
+	fmt.Fprintln(color.Output, color.YellowString("Preview only: no flare was created nor sent."))
+}
+
+func requestArchive(flareComp flare.Component, pdata flaretypes.ProfileData, providerTimeout time.Duration, flareArgs flaretypes.FlareArgs) (string, error) {
 	fmt.Fprintln(color.Output, color.BlueString("Asking the agent to build the flare archive."))
 	c := util.GetClient(false) // FIX: get certificates right then make this true
 	ipcAddress, err := pkgconfigsetup.GetIPCAddress(pkgconfigsetup.Datadog())
 	if err != nil {
 		fmt.Fprintln(color.Output, color.RedString(fmt.Sprintf("Error getting IPC address for the agent: %s", err)))
-		return createArchive(flareComp, pdata, providerTimeout, err)
+		return createArchive(flareComp, pdata, providerTimeout, err, flareArgs)
 	}
 
 	cmdport := pkgconfigsetup.Datadog().GetInt("cmd_port")
@@ -315,18 +395,24 @@ func requestArchive(flareComp flare.Component, pdata flaretypes.ProfileData, pro
 		Host:   net.JoinHostPort(ipcAddress, strconv.Itoa(cmdport)),
 		Path:   "/agent/flare",
 	}
+	q := url.Query()
 	if providerTimeout > 0 {
-		q := url.Query()
 		q.Set("provider_timeout", strconv.FormatInt(int64(providerTimeout), 10))
-		url.RawQuery = q.Encode()
 	}
+	if flareArgs.Preview {
+		q.Set("preview", "true")
+	}
+	if flareArgs.Subsystem != "" {
+		q.Set("subsystem", flareArgs.Subsystem)
+	}
+	url.RawQuery = q.Encode()
 
 	urlstr := url.String()
 
 	// Set session token
 	if err = util.SetAuthToken(pkgconfigsetup.Datadog()); err != nil {
 		fmt.Fprintln(color.Output, color.RedString(fmt.Sprintf("Error: %s", err)))
-		return createArchive(flareComp, pdata, providerTimeout, err)
+		return createArchive(flareComp, pdata, providerTimeout, err, flareArgs)
 	}
 
 	p, err := json.Marshal(pdata)
@@ -344,15 +430,15 @@ func requestArchive(flareComp flare.Component, pdata flaretypes.ProfileData, pro
 			fmt.Fprintln(color.Output, color.RedString("The agent was unable to make the flare. (is it running?)"))
 			err = fmt.Errorf("Error getting flare from running agent: %w", err)
 		}
-		return createArchive(flareComp, pdata, providerTimeout, err)
+		return createArchive(flareComp, pdata, providerTimeout, err, flareArgs)
 	}
 
 	return string(r), nil
 }
 
-func createArchive(flareComp flare.Component, pdata flaretypes.ProfileData, providerTimeout time.Duration, ipcError error) (string, error) {
+func createArchive(flareComp flare.Component, pdata flaretypes.ProfileData, providerTimeout time.Duration, ipcError error, flareArgs flaretypes.FlareArgs) (string, error) {
 	fmt.Fprintln(color.Output, color.YellowString("Initiating flare locally."))
-	filePath, err := flareComp.Create(pdata, providerTimeout, ipcError)
+	filePath, err := flareComp.CreateWithArgs(flareArgs, providerTimeout, ipcError, pdata)
 	if err != nil {
 		fmt.Printf("The flare zipfile failed to be created: %s\n", err)
 		return "", err
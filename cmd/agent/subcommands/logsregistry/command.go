@@ -0,0 +1,147 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Package logsregistry implements 'agent logs-registry'.
+package logsregistry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/command"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/pkg/api/util"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/logs/auditor"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+// cliParams are the command-line arguments for this subcommand
+type cliParams struct {
+	*command.GlobalParams
+
+	// filePath is the file the registry is exported to, or imported from
+	filePath string
+}
+
+// Commands returns a slice of subcommands for the 'agent' command.
+func Commands(globalParams *command.GlobalParams) []*cobra.Command {
+	cliParams := &cliParams{
+		GlobalParams: globalParams,
+	}
+
+	logsRegistryCmd := &cobra.Command{
+		Use:   "logs-registry",
+		Short: "Export or import the logs registry, holding the file offsets and the journald cursors",
+		Long:  ``,
+	}
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the logs registry of the running agent",
+		Long: `Export the logs registry of the running agent, to import it into another agent
+with 'logs-registry import', for instance when replacing the agent of a node.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return fxutil.OneShot(exportRegistry,
+				fx.Supply(cliParams),
+				fx.Supply(command.GetDefaultCoreBundleParams(cliParams.GlobalParams)),
+				core.Bundle(),
+			)
+		},
+	}
+	exportCmd.Flags().StringVarP(&cliParams.filePath, "file", "o", "", "Output the logs registry to a file")
+
+	importCmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import an exported logs registry into the stopped agent",
+		Long: `Import a logs registry exported with 'logs-registry export' into the agent, which must
+be stopped. The imported offsets and journald cursors replace the existing ones, and the logs
+are collected from them once the agent is started.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			cliParams.filePath = args[0]
+			return fxutil.OneShot(importRegistry,
+				fx.Supply(cliParams),
+				fx.Supply(command.GetDefaultCoreBundleParams(cliParams.GlobalParams)),
+				core.Bundle(),
+			)
+		},
+	}
+
+	logsRegistryCmd.AddCommand(exportCmd, importCmd)
+
+	return []*cobra.Command{logsRegistryCmd}
+}
+
+// registryURL returns the URL of the logs registry endpoint of the agent.
+func registryURL(config config.Component) (string, error) {
+	ipcAddress, err := pkgconfigsetup.GetIPCAddress(config)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("https://%v:%v/agent/logs/registry", ipcAddress, config.GetInt("cmd_port")), nil
+}
+
+func exportRegistry(_ log.Component, config config.Component, cliParams *cliParams) error {
+	urlstr, err := registryURL(config)
+	if err != nil {
+		return err
+	}
+	if err := util.SetAuthToken(config); err != nil {
+		return err
+	}
+
+	r, err := util.DoGet(util.GetClient(false), urlstr, util.LeaveConnectionOpen)
+	if err != nil {
+		var errMap = make(map[string]string)
+		json.Unmarshal(r, &errMap) //nolint:errcheck
+		// If the error has been marshalled into a json object, check it and return it properly
+		if e, found := errMap["error"]; found {
+			err = errors.New(e)
+		}
+		fmt.Printf("Could not export the logs registry: %v\nMake sure the agent is running with the logs collection enabled.\n", err)
+		return err
+	}
+
+	if cliParams.filePath == "" {
+		fmt.Println(string(r))
+		return nil
+	}
+	if err := os.WriteFile(cliParams.filePath, r, 0600); err != nil {
+		return fmt.Errorf("error while writing the logs registry to %s: %v", cliParams.filePath, err)
+	}
+	fmt.Println("Logs registry exported to:", cliParams.filePath)
+	return nil
+}
+
+func importRegistry(_ log.Component, config config.Component, cliParams *cliParams) error {
+	// the auditor of a running agent would overwrite the imported registry
+	if urlstr, err := registryURL(config); err == nil {
+		if err := util.SetAuthToken(config); err == nil {
+			if _, err := util.DoGet(util.GetClient(false), urlstr, util.CloseConnection); err == nil {
+				return errors.New("the agent is running, stop it before importing the logs registry")
+			}
+		}
+	}
+
+	b, err := os.ReadFile(cliParams.filePath)
+	if err != nil {
+		return err
+	}
+	runPath := config.GetString("logs_config.run_path")
+	imported, err := auditor.ImportRegistry(runPath, auditor.DefaultRegistryFilename, b)
+	if err != nil {
+		return fmt.Errorf("could not import the logs registry from %s: %v", cliParams.filePath, err)
+	}
+	fmt.Printf("Imported %d entries into the logs registry in %s.\n", imported, runPath)
+	return nil
+}
@@ -65,6 +65,8 @@ import (
 	healthprobefx "github.com/DataDog/datadog-agent/comp/core/healthprobe/fx"
 	log "github.com/DataDog/datadog-agent/comp/core/log/def"
 	lsof "github.com/DataDog/datadog-agent/comp/core/lsof/fx"
+	otlptelemetry "github.com/DataDog/datadog-agent/comp/core/otlptelemetry/def"
+	otlptelemetryfx "github.com/DataDog/datadog-agent/comp/core/otlptelemetry/fx"
 	"github.com/DataDog/datadog-agent/comp/core/pid"
 	"github.com/DataDog/datadog-agent/comp/core/pid/pidimpl"
 	flareprofiler "github.com/DataDog/datadog-agent/comp/core/profiler/fx"
@@ -132,6 +134,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/snmptraps"
 	snmptrapsServer "github.com/DataDog/datadog-agent/comp/snmptraps/server"
 	traceagentStatusImpl "github.com/DataDog/datadog-agent/comp/trace/status/statusimpl"
+	apiutil "github.com/DataDog/datadog-agent/pkg/api/util"
 	pkgcollector "github.com/DataDog/datadog-agent/pkg/collector"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net"
@@ -141,6 +144,8 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/config/remote/data"
 	commonsettings "github.com/DataDog/datadog-agent/pkg/config/settings"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	configUtils "github.com/DataDog/datadog-agent/pkg/config/utils"
+	"github.com/DataDog/datadog-agent/pkg/fips"
 	"github.com/DataDog/datadog-agent/pkg/jmxfetch"
 	proccontainers "github.com/DataDog/datadog-agent/pkg/process/util/containers"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
@@ -152,12 +157,14 @@ import (
 	systemprobeStatus "github.com/DataDog/datadog-agent/pkg/status/systemprobe"
 	pkgTelemetry "github.com/DataDog/datadog-agent/pkg/telemetry"
 	pkgcommon "github.com/DataDog/datadog-agent/pkg/util/common"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/capabilities"
 	"github.com/DataDog/datadog-agent/pkg/util/coredump"
 	"github.com/DataDog/datadog-agent/pkg/util/defaultpaths"
 	"github.com/DataDog/datadog-agent/pkg/util/flavor"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil/logging"
 	"github.com/DataDog/datadog-agent/pkg/util/hostname"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
 	"github.com/DataDog/datadog-agent/pkg/util/installinfo"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/leaderelection"
 	pkglog "github.com/DataDog/datadog-agent/pkg/util/log"
@@ -266,6 +273,7 @@ func run(log log.Component,
 	settings settings.Component,
 	_ option.Option[gui.Component],
 	_ agenttelemetry.Component,
+	_ otlptelemetry.Component,
 ) error {
 	defer func() {
 		stopAgent()
@@ -450,6 +458,10 @@ func getSharedFxOption() fx.Option {
 		fx.Provide(func(demux demultiplexer.Component) (ddgostatsd.ClientInterface, error) {
 			return aggregator.NewStatsdDirect(demux)
 		}),
+		// direct client of the internal statsd clients configured to bypass the dogstatsd server
+		fx.Provide(fx.Annotate(func(demux demultiplexer.Component) (ddgostatsd.ClientInterface, error) {
+			return aggregator.NewStatsdDirect(demux)
+		}, fx.ResultTags(`name:"statsd_direct"`))),
 		process.Bundle(),
 		guiimpl.Module(),
 		agent.Bundle(jmxloggerimpl.NewDefaultParams()),
@@ -457,6 +469,7 @@ func getSharedFxOption() fx.Option {
 			return healthprobe.Options{
 				Port:           config.GetInt("health_port"),
 				LogsGoroutines: config.GetBool("log_all_goroutines_when_unhealthy"),
+				EnableRestarts: config.GetBool("health_supervisor.enabled"),
 			}
 		}),
 		healthprobefx.Module(),
@@ -482,6 +495,7 @@ func getSharedFxOption() fx.Option {
 		}),
 		settingsimpl.Module(),
 		agenttelemetryfx.Module(),
+		otlptelemetryfx.Module(),
 		networkpath.Bundle(),
 		remoteagentregistryfx.Module(),
 		haagentfx.Module(),
@@ -549,6 +563,22 @@ func startAgent(
 	}
 	log.Infof("Hostname is: %s", hostnameDetected)
 
+	// In FIPS mode, refuse to run if a subsystem would use non FIPS-approved crypto
+	// The forwarder is checked against the endpoints it resolved, with the TLS configuration of its clients
+	forwarderTLSConfig := httputils.NewTransport(pkgconfigsetup.Datadog(), httputils.ForwarderDestination).TLSClientConfig
+	keysPerDomain, err := configUtils.GetMultipleEndpoints(pkgconfigsetup.Datadog())
+	if err != nil {
+		log.Warnf("Could not resolve the forwarder endpoints to verify their FIPS compliance: %v", err)
+	}
+	for domain := range keysPerDomain {
+		fips.RegisterEndpoint("forwarder "+domain, domain, forwarderTLSConfig)
+	}
+	fips.RegisterTLSConfig("api_server", apiutil.GetTLSServerConfig())
+	fips.RegisterTLSConfig("ipc_client", apiutil.GetTLSClientConfig())
+	if err := fips.Verify(); err != nil {
+		return log.Errorf("Error while verifying FIPS compliance, exiting: %v", err)
+	}
+
 	// start remote configuration management
 	if pkgconfigsetup.IsRemoteConfigEnabled(pkgconfigsetup.Datadog()) {
 		// Subscribe to `AGENT_TASK` product
@@ -606,6 +636,9 @@ func startAgent(
 	// check for common misconfigurations and report them to log
 	misconfig.ToLog(misconfig.CoreAgent)
 
+	// probe the container runtimes in the background, they are reported by the API and the inventory metadata
+	go capabilities.Get(ctx)
+
 	// start dependent services
 	go startDependentServices()
 
@@ -48,6 +48,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/flare"
 	"github.com/DataDog/datadog-agent/comp/core/gui"
 	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	otlptelemetry "github.com/DataDog/datadog-agent/comp/core/otlptelemetry/def"
 	"github.com/DataDog/datadog-agent/comp/core/secrets"
 	"github.com/DataDog/datadog-agent/comp/core/settings"
 	"github.com/DataDog/datadog-agent/comp/core/status"
@@ -134,6 +135,7 @@ func StartAgentWithDefaults(ctxChan <-chan context.Context) (<-chan error, error
 			settings settings.Component,
 			_ option.Option[gui.Component],
 			_ agenttelemetry.Component,
+			_ otlptelemetry.Component,
 		) error {
 			defer StopAgentWithDefaults()
 
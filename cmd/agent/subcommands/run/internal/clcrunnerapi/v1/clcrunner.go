@@ -11,11 +11,16 @@ package v1
 import (
 	"encoding/json"
 	"net/http"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/shirou/gopsutil/v4/process"
 
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery"
 	"github.com/DataDog/datadog-agent/pkg/api/version"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
 	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
 	"github.com/DataDog/datadog-agent/pkg/status"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -37,6 +42,7 @@ func SetupHandlers(r *mux.Router, ac autodiscovery.Component) {
 		getCLCRunnerStats(w, r, ac)
 	}).Methods("GET")
 	r.HandleFunc("/clcrunner/workers", getCLCRunnerWorkers).Methods("GET")
+	r.HandleFunc("/clcrunner/resources", getCLCRunnerResources).Methods("GET")
 }
 
 // getCLCRunnerStats retrieves Cluster Level Check runners stats
@@ -119,3 +125,71 @@ func getCLCRunnerWorkers(w http.ResponseWriter, _ *http.Request) {
 
 	w.Write(jsonWorkers)
 }
+
+// resourceUsage computes the CPU usage of the runner as an average between two requests
+// so that the Cluster Agent gets the usage over its rebalancing period
+type resourceUsage struct {
+	mu          sync.Mutex
+	lastTime    time.Time
+	lastCPUTime float64
+}
+
+var runnerResourceUsage = &resourceUsage{}
+
+func (r *resourceUsage) get(now time.Time) (types.RunnerResources, error) {
+	var resources types.RunnerResources
+
+	p, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		return resources, err
+	}
+
+	times, err := p.Times()
+	if err != nil {
+		return resources, err
+	}
+
+	memory, err := p.MemoryInfo()
+	if err != nil {
+		return resources, err
+	}
+	resources.MemoryUsage = memory.RSS
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cpuTime := times.User + times.System
+	if !r.lastTime.IsZero() && now.After(r.lastTime) && cpuTime > r.lastCPUTime {
+		resources.CPUUsage = (cpuTime - r.lastCPUTime) / now.Sub(r.lastTime).Seconds()
+	} else if createTime, err := p.CreateTime(); err == nil && now.UnixMilli() > createTime {
+		// First request, use the average since the start of the runner
+		resources.CPUUsage = cpuTime / (float64(now.UnixMilli()-createTime) / 1000)
+	}
+	r.lastTime = now
+	r.lastCPUTime = cpuTime
+
+	return resources, nil
+}
+
+// getCLCRunnerResources retrieves the CPU and memory usage of the Cluster Level Check runner
+func getCLCRunnerResources(w http.ResponseWriter, _ *http.Request) {
+	log.Debug("Got a request for the runner resources")
+	w.Header().Set("Content-Type", "application/json")
+	resources, err := runnerResourceUsage.get(time.Now())
+	if err != nil {
+		log.Errorf("Error getting the runner resource usage: %v", err)
+		body, _ := json.Marshal(map[string]string{"error": err.Error()})
+		http.Error(w, string(body), 500)
+		return
+	}
+
+	jsonResources, err := json.Marshal(resources)
+	if err != nil {
+		log.Errorf("Error marshalling resources. Error: %v, Resources: %v", err, resources)
+		body, _ := json.Marshal(map[string]string{"error": err.Error()})
+		http.Error(w, string(body), 500)
+		return
+	}
+
+	w.Write(jsonResources)
+}
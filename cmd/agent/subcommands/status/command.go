@@ -38,6 +38,8 @@ type cliParams struct {
 
 	jsonStatus         bool
 	prettyPrintJSON    bool
+	versionedJSON      bool
+	schema             bool
 	statusFilePath     string
 	verbose            bool
 	list               bool
@@ -55,7 +57,10 @@ func Commands(globalParams *command.GlobalParams) []*cobra.Command {
 		Long: `Display the current status.
 If no section is specified, this command will display all status sections.
 If a specific section is provided, such as 'collector', it will only display the status of that section.
-The --list flag can be used to list all available status sections.`,
+The --list flag can be used to list all available status sections.
+The --versioned-json flag renders each section with the version of its schema, for the automation parsing the
+status across upgrades. Only the sections flagged as stable are covered by the schema versioning. The --schema flag
+prints the JSON schema of this document.`,
 		RunE: func(_ *cobra.Command, args []string) error {
 			cliParams.args = args
 
@@ -81,6 +86,8 @@ The --list flag can be used to list all available status sections.`,
 	cmd.PersistentFlags().StringVarP(&cliParams.statusFilePath, "file", "o", "", "Output the status command to a file")
 	cmd.PersistentFlags().BoolVarP(&cliParams.verbose, "verbose", "v", false, "print out verbose status")
 	cmd.PersistentFlags().BoolVarP(&cliParams.list, "list", "l", false, "list all available status sections")
+	cmd.PersistentFlags().BoolVar(&cliParams.versionedJSON, "versioned-json", false, "print out the versioned JSON document, with the schema version of each section")
+	cmd.PersistentFlags().BoolVar(&cliParams.schema, "schema", false, "print out the JSON schema of the versioned JSON document")
 
 	return []*cobra.Command{cmd}
 }
@@ -115,6 +122,10 @@ func statusCmd(logger log.Component, config config.Component, _ sysprobeconfig.C
 		return redactError(requestSections(config))
 	}
 
+	if cliParams.schema {
+		return redactError(requestSchema(config, cliParams))
+	}
+
 	if len(cliParams.args) < 1 {
 		return redactError(requestStatus(config, cliParams))
 	}
@@ -128,7 +139,9 @@ func setIpcURL(cliParams *cliParams) url.Values {
 		v.Set("verbose", "true")
 	}
 
-	if cliParams.prettyPrintJSON || cliParams.jsonStatus {
+	if cliParams.versionedJSON {
+		v.Set("format", "versioned-json")
+	} else if cliParams.prettyPrintJSON || cliParams.jsonStatus {
 		v.Set("format", "json")
 	} else {
 		v.Set("format", "text")
@@ -145,7 +158,7 @@ func renderResponse(res []byte, cliParams *cliParams) error {
 		var prettyJSON bytes.Buffer
 		json.Indent(&prettyJSON, res, "", "  ") //nolint:errcheck
 		s = prettyJSON.String()
-	} else if cliParams.jsonStatus {
+	} else if cliParams.jsonStatus || cliParams.versionedJSON || cliParams.schema {
 		s = string(res)
 	} else {
 		s = scrubMessage(string(res))
@@ -160,7 +173,7 @@ func renderResponse(res []byte, cliParams *cliParams) error {
 
 func requestStatus(config config.Component, cliParams *cliParams) error {
 
-	if !cliParams.prettyPrintJSON && !cliParams.jsonStatus {
+	if !cliParams.prettyPrintJSON && !cliParams.jsonStatus && !cliParams.versionedJSON {
 		fmt.Printf("Getting the status from the agent.\n\n")
 	}
 
@@ -215,6 +228,20 @@ func componentStatus(config config.Component, cliParams *cliParams, component st
 	return nil
 }
 
+func requestSchema(config config.Component, cliParams *cliParams) error {
+	endpoint, err := apiutil.NewIPCEndpoint(config, "/agent/status/schema")
+	if err != nil {
+		return err
+	}
+
+	res, err := endpoint.DoGet()
+	if err != nil {
+		return err
+	}
+
+	return renderResponse(res, cliParams)
+}
+
 func requestSections(config config.Component) error {
 	endpoint, err := apiutil.NewIPCEndpoint(config, "/agent/status/sections")
 	if err != nil {
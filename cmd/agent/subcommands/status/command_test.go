@@ -42,3 +42,16 @@ func TestComponentStatusCommand(t *testing.T) {
 			require.Equal(t, false, secretParams.Enabled)
 		})
 }
+
+func TestVersionedJSONStatusCommand(t *testing.T) {
+	defer os.Unsetenv("DD_AUTOCONFIG_FROM_ENVIRONMENT") // undo os.Setenv by RunE
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"status", "--versioned-json", "collector"},
+		statusCmd,
+		func(cliParams *cliParams, _ core.BundleParams, _ secrets.Params) {
+			require.Equal(t, []string{"collector"}, cliParams.args)
+			require.Equal(t, true, cliParams.versionedJSON)
+			require.Equal(t, false, cliParams.schema)
+		})
+}
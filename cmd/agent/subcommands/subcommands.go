@@ -10,8 +10,10 @@ import (
 	"github.com/DataDog/datadog-agent/cmd/agent/command"
 	cmdanalyzelogs "github.com/DataDog/datadog-agent/cmd/agent/subcommands/analyzelogs"
 	cmdcheck "github.com/DataDog/datadog-agent/cmd/agent/subcommands/check"
+	cmdcheckschedule "github.com/DataDog/datadog-agent/cmd/agent/subcommands/checkschedule"
 	cmdconfig "github.com/DataDog/datadog-agent/cmd/agent/subcommands/config"
 	cmdconfigcheck "github.com/DataDog/datadog-agent/cmd/agent/subcommands/configcheck"
+	cmdcontainer "github.com/DataDog/datadog-agent/cmd/agent/subcommands/container"
 	cmdcontrolsvc "github.com/DataDog/datadog-agent/cmd/agent/subcommands/controlsvc"
 	cmddiagnose "github.com/DataDog/datadog-agent/cmd/agent/subcommands/diagnose"
 	cmddogstatsd "github.com/DataDog/datadog-agent/cmd/agent/subcommands/dogstatsd"
@@ -25,6 +27,7 @@ import (
 	cmdintegrations "github.com/DataDog/datadog-agent/cmd/agent/subcommands/integrations"
 	cmdjmx "github.com/DataDog/datadog-agent/cmd/agent/subcommands/jmx"
 	cmdlaunchgui "github.com/DataDog/datadog-agent/cmd/agent/subcommands/launchgui"
+	cmdlogsregistry "github.com/DataDog/datadog-agent/cmd/agent/subcommands/logsregistry"
 	cmdprocesschecks "github.com/DataDog/datadog-agent/cmd/agent/subcommands/processchecks"
 	cmdremoteconfig "github.com/DataDog/datadog-agent/cmd/agent/subcommands/remoteconfig"
 	cmdrun "github.com/DataDog/datadog-agent/cmd/agent/subcommands/run"
@@ -45,8 +48,10 @@ import (
 func AgentSubcommands() []command.SubcommandFactory {
 	return []command.SubcommandFactory{
 		cmdcheck.Commands,
+		cmdcheckschedule.Commands,
 		cmdconfigcheck.Commands,
 		cmdconfig.Commands,
+		cmdcontainer.Commands,
 		cmddiagnose.Commands,
 		cmddogstatsd.Commands,
 		cmddogstatsdcapture.Commands,
@@ -64,6 +69,7 @@ func AgentSubcommands() []command.SubcommandFactory {
 		cmdsnmp.Commands,
 		cmdstatus.Commands,
 		cmdstreamlogs.Commands,
+		cmdlogsregistry.Commands,
 		cmdstreamep.Commands,
 		cmdtaggerlist.Commands,
 		cmdversion.Commands,
@@ -48,10 +48,17 @@ func SetupHandlers(r *mux.Router, wmeta workloadmeta.Component, ac autodiscovery
 	r.HandleFunc("/config/list-runtime", settings.ListConfigurable).Methods("GET")
 	r.HandleFunc("/config/{setting}", settings.GetValue).Methods("GET")
 	r.HandleFunc("/config/{setting}", settings.SetValue).Methods("POST")
+	r.HandleFunc("/config/transaction/staged", settings.GetStagedValues).Methods("GET")
+	r.HandleFunc("/config/transaction/stage", settings.StageValues).Methods("POST")
+	r.HandleFunc("/config/transaction/commit", settings.CommitValues).Methods("POST")
+	r.HandleFunc("/config/transaction/rollback", settings.RollbackValues).Methods("POST")
+	r.HandleFunc("/config/transaction/overrides", settings.ListOverrides).Methods("GET")
+	r.HandleFunc("/config/provenance/all", settings.GetConfigProvenance).Methods("GET")
 	r.HandleFunc("/tagger-list", func(w http.ResponseWriter, r *http.Request) { getTaggerList(w, r, taggerComp) }).Methods("GET")
 	r.HandleFunc("/workload-list", func(w http.ResponseWriter, r *http.Request) {
 		getWorkloadList(w, r, wmeta)
 	}).Methods("GET")
+	installInformersEndpoints(r)
 }
 
 func getStatus(w http.ResponseWriter, r *http.Request, statusComponent status.Component) {
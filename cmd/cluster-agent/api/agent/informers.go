@@ -0,0 +1,38 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package agent
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// installInformersEndpoints registers the endpoint exposing the list and watch traffic to the
+// API server and the footprint of the informer caches
+func installInformersEndpoints(r *mux.Router) {
+	r.HandleFunc("/informers", getInformers).Methods("GET")
+}
+
+func getInformers(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	stats := apiserver.GetInformerStats(pkgconfigsetup.Datadog())
+	jsonStats, err := json.Marshal(stats)
+	if err != nil {
+		httputils.SetJSONError(w, log.Errorf("Unable to marshal informer stats: %s", err), 500)
+		return
+	}
+	w.Write(jsonStats)
+}
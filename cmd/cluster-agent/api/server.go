@@ -25,6 +25,7 @@ import (
 	"time"
 
 	languagedetection "github.com/DataDog/datadog-agent/cmd/cluster-agent/api/v1/languagedetection"
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/api/v1/securityprofiles"
 	"github.com/DataDog/datadog-agent/cmd/cluster-agent/api/v2/series"
 
 	"github.com/gorilla/mux"
@@ -70,6 +71,9 @@ func StartServer(ctx context.Context, w workloadmeta.Component, taggerComp tagge
 	// API V1 Language Detection APIs
 	languagedetection.InstallLanguageDetectionEndpoints(ctx, apiRouter, w, cfg)
 
+	// API V1 Security Profiles APIs
+	securityprofiles.InstallSecurityProfilesEndpoints(ctx, apiRouter, cfg)
+
 	// API V2 Series APIs
 	v2ApiRouter := router.PathPrefix("/api/v2").Subrouter()
 	series.InstallNodeMetricsEndpoints(ctx, v2ApiRouter, cfg)
@@ -136,6 +140,7 @@ func StartServer(ctx context.Context, w workloadmeta.Component, taggerComp tagge
 		grpc.MaxSendMsgSize(maxMessageSize),
 		grpc.MaxRecvMsgSize(maxMessageSize),
 	}
+	opts = append(opts, grpcutil.ServerKeepaliveOptions()...)
 
 	grpcSrv := grpc.NewServer(opts...)
 	// event size should be small enough to fit within the grpc max message size
@@ -197,6 +202,7 @@ func isExternalPath(path string) bool {
 	return strings.HasPrefix(path, "/api/v1/metadata/") && len(strings.Split(path, "/")) == 7 || // support for agents < 6.5.0
 		path == "/version" ||
 		path == "/api/v1/languagedetection" ||
+		path == "/api/v1/security/profiles" ||
 		path == "/api/v2/series" ||
 		strings.HasPrefix(path, "/api/v1/annotations/node/") && len(strings.Split(path, "/")) == 6 ||
 		strings.HasPrefix(path, "/api/v1/cf/apps") && len(strings.Split(path, "/")) == 5 ||
@@ -206,6 +212,7 @@ func isExternalPath(path string) bool {
 		strings.HasPrefix(path, "/api/v1/cluster/id") && len(strings.Split(path, "/")) == 5 ||
 		strings.HasPrefix(path, "/api/v1/clusterchecks/") && len(strings.Split(path, "/")) == 6 ||
 		strings.HasPrefix(path, "/api/v1/endpointschecks/") && len(strings.Split(path, "/")) == 6 ||
+		strings.HasPrefix(path, "/api/v1/remote-config/mirror/api/v0.1/") && len(strings.Split(path, "/")) == 8 ||
 		strings.HasPrefix(path, "/api/v1/metadata/namespace/") && len(strings.Split(path, "/")) == 6 ||
 		strings.HasPrefix(path, "/api/v1/tags/cf/apps/") && len(strings.Split(path, "/")) == 7 ||
 		strings.HasPrefix(path, "/api/v1/tags/namespace/") && len(strings.Split(path, "/")) == 6 ||
@@ -58,6 +58,11 @@ func TestValidateTokenMiddleware(t *testing.T) {
 			"bandit!",
 			http.StatusForbidden,
 		},
+		{
+			"/api/v1/remote-config/mirror/api/v0.1/configurations",
+			"abc123",
+			http.StatusOK,
+		},
 	}
 
 	for i, tt := range tests {
@@ -0,0 +1,13 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+/*
+Package securityprofiles implements the security profiles API handler.
+The runtime security agents of the nodes upload the security profiles that reached the stable state, and the cluster
+agent keeps the latest one of each workload image. When a workload of the same image is scheduled on a node that never
+ran it, the node downloads the profile so that the learning phase is skipped and the anomaly detection is active
+immediately.
+*/
+package securityprofiles
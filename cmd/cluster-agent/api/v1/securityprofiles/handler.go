@@ -0,0 +1,137 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package securityprofiles
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	proto "github.com/DataDog/agent-payload/v5/cws/dumpsv1"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// maxProfileSize is the maximum size of an uploaded security profile
+	maxProfileSize = 32 * 1024 * 1024
+	// cleanupPeriod is the period at which the expired profiles are removed
+	cleanupPeriod = 10 * time.Minute
+)
+
+type securityProfilesHandler struct {
+	enabled bool
+	store   *profileStore
+}
+
+func newSecurityProfilesHandler(cfg config.Component) *securityProfilesHandler {
+	return &securityProfilesHandler{
+		enabled: cfg.GetBool("cluster_agent.security_profiles.enabled"),
+		store: newProfileStore(
+			cfg.GetInt("cluster_agent.security_profiles.max_profiles"),
+			cfg.GetDuration("cluster_agent.security_profiles.ttl"),
+		),
+	}
+}
+
+func (handler *securityProfilesHandler) startCleanupInBackground(ctx context.Context) {
+	go func() {
+		cleanupTicker := time.NewTicker(cleanupPeriod)
+		defer cleanupTicker.Stop()
+		for {
+			select {
+			case <-cleanupTicker.C:
+				handler.store.cleanExpired()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// preHandler is called by both leader and followers and returns true if the request should be forwarded or handled by the leader
+func (handler *securityProfilesHandler) preHandler(w http.ResponseWriter, _ *http.Request) bool {
+	if !handler.enabled {
+		http.Error(w, "Security profiles sharing is disabled on the cluster agent", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
+
+// postHandler is called only by the leader and stores the uploaded profile
+func (handler *securityProfilesHandler) postHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		ProcessedRequests.Inc("post", statusError)
+		http.Error(w, "Request body is empty", http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxProfileSize))
+	if err != nil {
+		ProcessedRequests.Inc("post", statusError)
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	profile := &proto.SecurityProfile{}
+	if err := profile.UnmarshalVT(body); err != nil {
+		ProcessedRequests.Inc("post", statusError)
+		http.Error(w, "Failed to unmarshal request body", http.StatusBadRequest)
+		return
+	}
+
+	imageName := profile.GetSelector().GetImageName()
+	if imageName == "" || len(profile.GetProfileContexts()) == 0 {
+		ProcessedRequests.Inc("post", statusError)
+		http.Error(w, "The security profile has no image name or no version", http.StatusBadRequest)
+		return
+	}
+
+	if handler.store.store(imageName, body, lastSeen(profile)) {
+		log.Debugf("security profile of image %s stored", imageName)
+	} else {
+		log.Debugf("security profile of image %s ignored, the stored profile is more recent", imageName)
+	}
+
+	ProcessedRequests.Inc("post", statusSuccess)
+	StoredProfiles.Set(float64(handler.store.len()))
+	w.WriteHeader(http.StatusOK)
+}
+
+// lastSeen returns the most recent activity of the versions of the profile
+func lastSeen(profile *proto.SecurityProfile) uint64 {
+	var last uint64
+	for _, ctx := range profile.GetProfileContexts() {
+		last = max(last, ctx.GetLastSeen())
+	}
+	return last
+}
+
+// getHandler is called only by the leader and returns the profile of the requested image name
+func (handler *securityProfilesHandler) getHandler(w http.ResponseWriter, r *http.Request) {
+	imageName := r.URL.Query().Get("image_name")
+	if imageName == "" {
+		ProcessedRequests.Inc("get", statusError)
+		http.Error(w, "Missing image_name parameter", http.StatusBadRequest)
+		return
+	}
+
+	data, ok := handler.store.get(imageName)
+	if !ok {
+		ProcessedRequests.Inc("get", statusNotFound)
+		http.Error(w, "No security profile for this image", http.StatusNotFound)
+		return
+	}
+
+	ProcessedRequests.Inc("get", statusSuccess)
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
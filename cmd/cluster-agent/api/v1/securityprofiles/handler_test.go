@@ -0,0 +1,124 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package securityprofiles
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	proto "github.com/DataDog/agent-payload/v5/cws/dumpsv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeProfile(t *testing.T, imageName string, versions ...string) []byte {
+	return encodeProfileSeenAt(t, imageName, 0, versions...)
+}
+
+func encodeProfileSeenAt(t *testing.T, imageName string, lastSeen uint64, versions ...string) []byte {
+	profile := &proto.SecurityProfile{
+		Selector:        &proto.ProfileSelector{ImageName: imageName, ImageTag: "*"},
+		ProfileContexts: make(map[string]*proto.ProfileContext),
+	}
+	for _, version := range versions {
+		profile.ProfileContexts[version] = &proto.ProfileContext{LastSeen: lastSeen}
+	}
+	data, err := profile.MarshalVT()
+	require.NoError(t, err)
+	return data
+}
+
+func TestSecurityProfilesHandler(t *testing.T) {
+	handler := &securityProfilesHandler{
+		enabled: true,
+		store:   newProfileStore(10, time.Hour),
+	}
+
+	post := func(data []byte) int {
+		rec := httptest.NewRecorder()
+		handler.postHandler(rec, httptest.NewRequest(http.MethodPost, "/security/profiles", bytes.NewReader(data)))
+		return rec.Code
+	}
+	get := func(imageName string) (int, []byte) {
+		rec := httptest.NewRecorder()
+		handler.getHandler(rec, httptest.NewRequest(http.MethodGet, "/security/profiles?image_name="+imageName, nil))
+		return rec.Code, rec.Body.Bytes()
+	}
+
+	code, _ := get("nginx")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	profile := encodeProfile(t, "nginx", "1.25")
+	assert.Equal(t, http.StatusOK, post(profile))
+	code, data := get("nginx")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, profile, data)
+
+	// the latest profile replaces the previous one
+	profile = encodeProfile(t, "nginx", "1.25", "1.26")
+	assert.Equal(t, http.StatusOK, post(profile))
+	_, data = get("nginx")
+	assert.Equal(t, profile, data)
+
+	// a profile older than the stored one doesn't replace it
+	profile = encodeProfileSeenAt(t, "nginx", 200, "1.26")
+	assert.Equal(t, http.StatusOK, post(profile))
+	assert.Equal(t, http.StatusOK, post(encodeProfileSeenAt(t, "nginx", 100, "1.25")))
+	_, data = get("nginx")
+	assert.Equal(t, profile, data)
+
+	// invalid profiles
+	assert.Equal(t, http.StatusBadRequest, post([]byte("not a profile")))
+	assert.Equal(t, http.StatusBadRequest, post(encodeProfile(t, "", "1.0")))
+	assert.Equal(t, http.StatusBadRequest, post(encodeProfile(t, "redis")))
+
+	code, _ = get("")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	rec := httptest.NewRecorder()
+	assert.True(t, handler.preHandler(rec, nil))
+	handler.enabled = false
+	assert.False(t, handler.preHandler(rec, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestProfileStore(t *testing.T) {
+	now := time.Now()
+	store := newProfileStore(2, time.Hour)
+	store.now = func() time.Time { return now }
+
+	assert.True(t, store.store("nginx", []byte("nginx"), 1))
+	now = now.Add(time.Minute)
+	assert.True(t, store.store("redis", []byte("redis"), 1))
+	now = now.Add(time.Minute)
+
+	// the least recently updated profile is evicted
+	assert.True(t, store.store("nginx", []byte("nginx-2"), 2))
+	assert.False(t, store.store("nginx", []byte("nginx-old"), 1))
+	now = now.Add(time.Minute)
+	assert.True(t, store.store("postgres", []byte("postgres"), 1))
+	assert.Equal(t, 2, store.len())
+	_, ok := store.get("redis")
+	assert.False(t, ok)
+	data, ok := store.get("nginx")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("nginx-2"), data)
+
+	// the profiles expire after the TTL
+	now = now.Add(time.Hour - time.Second)
+	_, ok = store.get("nginx")
+	assert.False(t, ok)
+	_, ok = store.get("postgres")
+	assert.True(t, ok)
+
+	store.cleanExpired()
+	assert.Equal(t, 1, store.len())
+}
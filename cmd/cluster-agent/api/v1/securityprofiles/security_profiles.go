@@ -0,0 +1,35 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package securityprofiles
+
+import (
+	"context"
+
+	"github.com/gorilla/mux"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/api"
+)
+
+const (
+	postHandlerName = "security-profiles-post-handler"
+	getHandlerName  = "security-profiles-get-handler"
+)
+
+// InstallSecurityProfilesEndpoints installs security profiles endpoints
+func InstallSecurityProfilesEndpoints(ctx context.Context, r *mux.Router, cfg config.Component) {
+	service := newSecurityProfilesHandler(cfg)
+
+	service.startCleanupInBackground(ctx)
+
+	// the profiles are held by the leader, the followers forward the requests
+	postHandler := api.WithLeaderProxyHandler(postHandlerName, service.preHandler, service.postHandler)
+	getHandler := api.WithLeaderProxyHandler(getHandlerName, service.preHandler, service.getHandler)
+	r.HandleFunc("/security/profiles", api.WithTelemetryWrapper(postHandlerName, postHandler)).Methods("POST")
+	r.HandleFunc("/security/profiles", api.WithTelemetryWrapper(getHandlerName, getHandler)).Methods("GET")
+}
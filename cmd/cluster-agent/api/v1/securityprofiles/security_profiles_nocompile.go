@@ -0,0 +1,20 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !kubeapiserver
+
+package securityprofiles
+
+import (
+	"context"
+
+	"github.com/gorilla/mux"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+)
+
+// InstallSecurityProfilesEndpoints installs security profiles endpoints
+func InstallSecurityProfilesEndpoints(_ context.Context, _ *mux.Router, _ config.Component) {
+}
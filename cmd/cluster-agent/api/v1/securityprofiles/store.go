@@ -0,0 +1,109 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package securityprofiles
+
+import (
+	"sync"
+	"time"
+)
+
+type storedProfile struct {
+	data      []byte
+	lastSeen  uint64
+	updatedAt time.Time
+}
+
+// profileStore holds the latest security profile uploaded for each image name
+type profileStore struct {
+	sync.RWMutex
+	profiles    map[string]*storedProfile
+	maxProfiles int
+	ttl         time.Duration
+	now         func() time.Time
+}
+
+func newProfileStore(maxProfiles int, ttl time.Duration) *profileStore {
+	return &profileStore{
+		profiles:    make(map[string]*storedProfile),
+		maxProfiles: maxProfiles,
+		ttl:         ttl,
+		now:         time.Now,
+	}
+}
+
+// store replaces the profile of the given image name, unless the stored profile saw activity more recently than
+// the new one, as the nodes upload their profiles concurrently. lastSeen is the most recent activity of the
+// profile. The least recently updated profile is evicted if the store is full. It returns false if the profile
+// wasn't stored.
+func (s *profileStore) store(imageName string, data []byte, lastSeen uint64) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	now := s.now()
+	previous, ok := s.profiles[imageName]
+	if ok && !s.isExpired(previous) && previous.lastSeen > lastSeen {
+		return false
+	}
+	if !ok && len(s.profiles) >= s.maxProfiles {
+		s.evictOldest()
+	}
+	s.profiles[imageName] = &storedProfile{
+		data:      data,
+		lastSeen:  lastSeen,
+		updatedAt: now,
+	}
+	return true
+}
+
+// evictOldest (thread unsafe) removes the least recently updated profile
+func (s *profileStore) evictOldest() {
+	var (
+		oldestImageName string
+		oldest          time.Time
+	)
+	for imageName, profile := range s.profiles {
+		if oldestImageName == "" || profile.updatedAt.Before(oldest) {
+			oldestImageName, oldest = imageName, profile.updatedAt
+		}
+	}
+	delete(s.profiles, oldestImageName)
+}
+
+// get returns the profile of the given image name, if it didn't expire
+func (s *profileStore) get(imageName string) ([]byte, bool) {
+	s.RLock()
+	defer s.RUnlock()
+
+	profile, ok := s.profiles[imageName]
+	if !ok || s.isExpired(profile) {
+		return nil, false
+	}
+	return profile.data, true
+}
+
+func (s *profileStore) isExpired(profile *storedProfile) bool {
+	return s.ttl > 0 && s.now().Sub(profile.updatedAt) > s.ttl
+}
+
+// cleanExpired removes the profiles that weren't updated during the TTL
+func (s *profileStore) cleanExpired() {
+	s.Lock()
+	defer s.Unlock()
+
+	for imageName, profile := range s.profiles {
+		if s.isExpired(profile) {
+			delete(s.profiles, imageName)
+		}
+	}
+}
+
+func (s *profileStore) len() int {
+	s.RLock()
+	defer s.RUnlock()
+	return len(s.profiles)
+}
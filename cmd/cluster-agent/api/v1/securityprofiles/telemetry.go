@@ -0,0 +1,45 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package securityprofiles
+
+import "github.com/DataDog/datadog-agent/pkg/telemetry"
+
+const subsystem = "security_profiles_dca_handler"
+
+var (
+	// statusSuccess is the value for the "status" tag that represents a successful operation
+	statusSuccess = "success"
+	// statusError is the value for the "status" tag that represents an error
+	statusError = "error"
+	// statusNotFound is the value for the "status" tag that represents a request for an unknown profile
+	statusNotFound = "not_found"
+)
+
+var (
+	commonOpts = telemetry.Options{NoDoubleUnderscoreSep: true}
+)
+
+var (
+	// ProcessedRequests tracks the number requests processed by the handler
+	ProcessedRequests = telemetry.NewCounterWithOpts(
+		subsystem,
+		"processed_requests",
+		[]string{"method", "status"},
+		"Tracks the number of requests processed by the handler",
+		commonOpts,
+	)
+
+	// StoredProfiles tracks the number of security profiles held by the cluster agent
+	StoredProfiles = telemetry.NewGaugeWithOpts(
+		subsystem,
+		"stored_profiles",
+		[]string{},
+		"Tracks the number of security profiles held by the cluster agent",
+		commonOpts,
+	)
+)
@@ -157,6 +157,7 @@ func Commands(globalParams *command.GlobalParams) []*cobra.Command {
 						PythonVersionGetFunc: python.GetPythonVersion,
 					},
 					status.NewInformationProvider(leaderelection.Provider{}),
+					status.NewInformationProvider(apiserver.InformersStatusProvider{}),
 					status.NewInformationProvider(clusteragentMetricsStatus.Provider{}),
 					status.NewInformationProvider(admissionpkg.Provider{}),
 					status.NewInformationProvider(endpointsStatus.Provider{}),
@@ -360,6 +361,9 @@ func start(log log.Component,
 		if config.GetBool("admission_controller.auto_instrumentation.patcher.enabled") {
 			products = append(products, state.ProductAPMTracing)
 		}
+		if config.GetBool("admission_controller.auto_instrumentation.remote_config.enabled") {
+			products = append(products, state.ProductAPMInstrumentation)
+		}
 		if config.GetBool("autoscaling.workload.enabled") {
 			products = append(products, state.ProductContainerAutoscalingSettings, state.ProductContainerAutoscalingValues)
 		}
@@ -378,6 +382,13 @@ func start(log log.Component,
 		}
 	}
 
+	// Serve the remote config repositories to the node agents using the cluster-agent as their mirror
+	if isSet && config.GetBool("remote_configuration.mirror.enabled") {
+		api.ModifyAPIRouter(func(r *mux.Router) {
+			r.HandleFunc("/remote-config/mirror/api/v0.1/{endpoint}", rcserv.ServeMirror).Methods("GET", "POST")
+		})
+	}
+
 	// FIXME: move LoadComponents and AC.LoadAndRun in their own package so we
 	// don't import cmd/agent
 
@@ -484,6 +495,8 @@ func start(log log.Component,
 			StopCh:                       stopCh,
 			ValidatingStopCh:             validatingStopCh,
 			Demultiplexer:                demultiplexer,
+			RcClient:                     rcClient,
+			DatadogClient:                dc,
 		}
 
 		webhooks, err := admissionpkg.StartControllers(admissionCtx, wmeta, pa, datadogConfig)
@@ -34,6 +34,8 @@ const (
 	envProcScanRate = "DD_CWS_INSTRUMENTATION_PROC_SCAN_RATE"
 	// envSeccompDisabled defines the environment variable to disable seccomp
 	envSeccompDisabled = "DD_CWS_INSTRUMENTATION_SECCOMP_DISABLED"
+	// envDNSEnabled defines the environment variable to enable the capture of the DNS requests
+	envDNSEnabled = "DD_CWS_INSTRUMENTATION_DNS_ENABLED"
 )
 
 const (
@@ -57,6 +59,8 @@ const (
 	scanProcEveryOpt = "proc-scan-rate"
 	// disableSeccompOpt disable seccomp
 	disableSeccompOpt = "disable-seccomp"
+	// enableDNSOpt enable the capture of the DNS requests
+	enableDNSOpt = "enable-dns"
 	// pidOpt attach mode
 	pidOpt = "pid"
 	// pidPerTracer number of pid per tracer
@@ -74,6 +78,7 @@ type traceCliParams struct {
 	ProcScanDisabled bool
 	ScanProcEvery    string
 	SeccompDisabled  bool
+	DNSEnabled       bool
 	PIDs             []int
 	PIDPerTracer     int
 }
@@ -108,6 +113,7 @@ func Command() []*cobra.Command {
 				StatsDisabled:    params.StatsDisabled,
 				ProcScanDisabled: params.ProcScanDisabled,
 				SeccompDisabled:  params.SeccompDisabled,
+				DNSEnabled:       params.DNSEnabled,
 			}
 
 			if params.ScanProcEvery != "" {
@@ -182,6 +188,9 @@ func Command() []*cobra.Command {
 						if params.SeccompDisabled {
 							args = append(args, fmt.Sprintf(`--%s`, disableSeccompOpt))
 						}
+						if params.DNSEnabled {
+							args = append(args, fmt.Sprintf(`--%s`, enableDNSOpt))
+						}
 
 						for _, pid := range set {
 							args = append(args, fmt.Sprintf(`--%s`, pidOpt), fmt.Sprintf(`%d`, pid))
@@ -239,6 +248,7 @@ func Command() []*cobra.Command {
 	traceCmd.Flags().BoolVar(&params.ProcScanDisabled, disableProcScanOpt, envToBool(envProcScanDisabled), "disable proc scan")
 	traceCmd.Flags().StringVar(&params.ScanProcEvery, scanProcEveryOpt, os.Getenv(envProcScanRate), "proc scan rate")
 	traceCmd.Flags().BoolVar(&params.SeccompDisabled, disableSeccompOpt, envToBool(envSeccompDisabled), "disable seccomp")
+	traceCmd.Flags().BoolVar(&params.DNSEnabled, enableDNSOpt, envToBool(envDNSEnabled), "enable the capture of the DNS requests")
 	traceCmd.Flags().IntSliceVar(&params.PIDs, pidOpt, nil, "attach tracer to pid")
 	traceCmd.Flags().IntVar(&params.PIDPerTracer, pidPerTracer, math.MaxInt, "maximum number of pid per tracer")
 
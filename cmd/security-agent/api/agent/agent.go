@@ -60,6 +60,12 @@ func (a *Agent) SetupHandlers(r *mux.Router) {
 	r.HandleFunc("/config/list-runtime", a.settings.ListConfigurable).Methods("GET")
 	r.HandleFunc("/config/{setting}", a.settings.GetValue).Methods("GET")
 	r.HandleFunc("/config/{setting}", a.settings.SetValue).Methods("POST")
+	r.HandleFunc("/config/transaction/staged", a.settings.GetStagedValues).Methods("GET")
+	r.HandleFunc("/config/transaction/stage", a.settings.StageValues).Methods("POST")
+	r.HandleFunc("/config/transaction/commit", a.settings.CommitValues).Methods("POST")
+	r.HandleFunc("/config/transaction/rollback", a.settings.RollbackValues).Methods("POST")
+	r.HandleFunc("/config/transaction/overrides", a.settings.ListOverrides).Methods("GET")
+	r.HandleFunc("/config/provenance/all", a.settings.GetConfigProvenance).Methods("GET")
 	r.HandleFunc("/workload-list", func(w http.ResponseWriter, r *http.Request) {
 		verbose := r.URL.Query().Get("verbose") == "true"
 		workloadList(w, verbose, a.wmeta)
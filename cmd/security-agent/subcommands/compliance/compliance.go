@@ -11,6 +11,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/DataDog/datadog-agent/comp/core/config"
@@ -77,12 +78,23 @@ func StartCompliance(log log.Component,
 		enabledConfigurationsExporters = append(enabledConfigurationsExporters, compliance.DBExporter)
 	}
 
+	var remoteBenchmarks *compliance.RemoteBenchmarks
+	if config.GetBool("compliance_config.custom_benchmarks.remote_config.enabled") {
+		remoteBenchmarks, err = compliance.NewRemoteBenchmarks(filepath.Join(config.GetString("run_path"), "compliance", "remote"))
+		if err != nil {
+			log.Errorf("Error creating the remote compliance benchmarks source: %v", err)
+		}
+	}
+
 	reporter := compliance.NewLogReporter(hostname, "compliance-agent", "compliance", endpoints, context, compression)
 	telemetrySender := telemetry.NewSimpleTelemetrySenderFromStatsd(statsdClient)
 
 	agent := compliance.NewAgent(telemetrySender, wmeta, compliance.AgentOptions{
 		ResolverOptions:               resolverOptions,
 		ConfigDir:                     configDir,
+		CustomBenchmarksDir:           config.GetString("compliance_config.custom_benchmarks.dir"),
+		RemoteBenchmarks:              remoteBenchmarks,
+		OpenSCAPResultsDir:            config.GetString("compliance_config.openscap_results.dir"),
 		Reporter:                      reporter,
 		CheckInterval:                 checkInterval,
 		EnabledConfigurationExporters: enabledConfigurationsExporters,
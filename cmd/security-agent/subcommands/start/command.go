@@ -347,14 +347,15 @@ func setupInternalProfiling(config config.Component) error {
 		if traceAgentURL := os.Getenv("TRACE_AGENT_URL"); len(traceAgentURL) > 0 {
 			site = fmt.Sprintf(profiling.ProfilingLocalURLTemplate, traceAgentURL)
 		} else {
-			site = fmt.Sprintf(profiling.ProfilingURLTemplate, cfgSite)
-			if cfgURL != "" {
-				site = cfgURL
-			}
+			site = profiling.IntakeURL(
+				cfgURL,
+				config.GetBool(secAgentKey("internal_profiling.use_trace_agent_proxy")),
+				config.GetInt("apm_config.receiver_port"),
+				cfgSite,
+			)
 		}
 
-		tags := config.GetStringSlice(secAgentKey("internal_profiling.extra_tags"))
-		tags = append(tags, fmt.Sprintf("version:%v", version.AgentVersion))
+		tags := profiling.Tags(config.GetStringSlice(secAgentKey("internal_profiling.extra_tags")), "security-agent", version.AgentVersion)
 
 		profSettings := profiling.Settings{
 			ProfilingURL:         site,
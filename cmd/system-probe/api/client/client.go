@@ -25,6 +25,10 @@ import (
 const (
 	checkLabelName     = "check"
 	telemetrySubsystem = "system_probe__remote_client"
+
+	// TimeoutHeader carries the time left, in milliseconds, before the caller of
+	// a request gives up on it
+	TimeoutHeader = "X-Dd-Sysprobe-Timeout-Ms"
 )
 
 var (
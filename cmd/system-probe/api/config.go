@@ -20,6 +20,12 @@ func setupConfigHandlers(r *mux.Router, settings settings.Component) {
 	r.HandleFunc("/config/list-runtime", settings.ListConfigurable).Methods("GET")
 	r.HandleFunc("/config/{setting}", settings.GetValue).Methods("GET")
 	r.HandleFunc("/config/{setting}", settings.SetValue).Methods("POST")
+	r.HandleFunc("/config/transaction/staged", settings.GetStagedValues).Methods("GET")
+	r.HandleFunc("/config/transaction/stage", settings.StageValues).Methods("POST")
+	r.HandleFunc("/config/transaction/commit", settings.CommitValues).Methods("POST")
+	r.HandleFunc("/config/transaction/rollback", settings.RollbackValues).Methods("POST")
+	r.HandleFunc("/config/transaction/overrides", settings.ListOverrides).Methods("GET")
+	r.HandleFunc("/config/provenance/all", settings.GetConfigProvenance).Methods("GET")
 }
 
 func getAggregatedNamespaces() []string {
@@ -39,6 +39,8 @@ func StartServer(cfg *sysconfigtypes.Config, telemetry telemetry.Component, wmet
 	}
 
 	mux := gorilla.NewRouter()
+	// the handlers stop working on the requests their caller gave up on
+	mux.Use(utils.WithDeadline)
 
 	err = module.Register(cfg, mux, modules.All, wmeta, tagger, telemetry, compression, statsd)
 	if err != nil {
@@ -61,6 +63,7 @@ func StartServer(cfg *sysconfigtypes.Config, telemetry telemetry.Component, wmet
 
 	if runtime.GOOS == "linux" {
 		mux.HandleFunc("/debug/ebpf_btf_loader_info", ebpf.HandleBTFLoaderInfo)
+		mux.HandleFunc("/debug/ebpf_load_outcomes", ebpf.HandleLoadOutcomes)
 		mux.HandleFunc("/debug/dmesg", debug.HandleLinuxDmesg)
 		mux.HandleFunc("/debug/selinux_sestatus", debug.HandleSelinuxSestatus)
 		mux.HandleFunc("/debug/selinux_semodule_list", debug.HandleSelinuxSemoduleList)
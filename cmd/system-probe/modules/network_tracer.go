@@ -95,6 +95,13 @@ func (nt *networkTracer) Register(httpMux *module.Router) error {
 			return
 		}
 		defer cleanup()
+		if err := req.Context().Err(); err != nil {
+			// the caller gave up on the request, marshaling a large connection set would be wasted
+			network.Reclaim(cs)
+			log.Warnf("dropping connections of client %s: %s", id, err)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
 		contentType := req.Header.Get("Accept")
 		marshaler := marshal.GetMarshaler(contentType)
 		writeConnections(w, marshaler, cs)
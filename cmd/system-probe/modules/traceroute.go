@@ -99,8 +99,8 @@ func (t *traceroute) RegisterGRPC(_ grpc.ServiceRegistrar) error {
 func (t *traceroute) Close() {}
 
 func logTracerouteRequests(cfg tracerouteutil.Config, client string, runCount uint64, start time.Time) {
-	args := []interface{}{cfg.DestHostname, client, cfg.DestPort, cfg.MaxTTL, cfg.Timeout, cfg.Protocol, runCount, time.Since(start)}
-	msg := "Got request on /traceroute/%s?client_id=%s&port=%d&maxTTL=%d&timeout=%d&protocol=%s (count: %d): retrieved traceroute in %s"
+	args := []interface{}{cfg.DestHostname, client, cfg.DestPort, cfg.SourcePort, cfg.MaxTTL, cfg.ProbesPerHop, cfg.Timeout, cfg.Protocol, runCount, time.Since(start)}
+	msg := "Got request on /traceroute/%s?client_id=%s&port=%d&source_port=%d&maxTTL=%d&probes_per_hop=%d&timeout=%d&protocol=%s (count: %d): retrieved traceroute in %s"
 	switch {
 	case runCount <= 5, runCount%200 == 0:
 		log.Infof(msg, args...)
@@ -116,10 +116,18 @@ func parseParams(req *http.Request) (tracerouteutil.Config, error) {
 	if err != nil {
 		return tracerouteutil.Config{}, fmt.Errorf("invalid port: %s", err)
 	}
+	sourcePort, err := parseUint(req, "source_port", 16)
+	if err != nil {
+		return tracerouteutil.Config{}, fmt.Errorf("invalid source_port: %s", err)
+	}
 	maxTTL, err := parseUint(req, "max_ttl", 8)
 	if err != nil {
 		return tracerouteutil.Config{}, fmt.Errorf("invalid max_ttl: %s", err)
 	}
+	probesPerHop, err := parseUint(req, "probes_per_hop", 8)
+	if err != nil {
+		return tracerouteutil.Config{}, fmt.Errorf("invalid probes_per_hop: %s", err)
+	}
 	timeout, err := parseUint(req, "timeout", 64)
 	if err != nil {
 		return tracerouteutil.Config{}, fmt.Errorf("invalid timeout: %s", err)
@@ -129,7 +137,9 @@ func parseParams(req *http.Request) (tracerouteutil.Config, error) {
 	return tracerouteutil.Config{
 		DestHostname: host,
 		DestPort:     uint16(port),
+		SourcePort:   uint16(sourcePort),
 		MaxTTL:       uint8(maxTTL),
+		ProbesPerHop: uint8(probesPerHop),
 		Timeout:      time.Duration(timeout),
 		Protocol:     payload.Protocol(protocol),
 	}, nil
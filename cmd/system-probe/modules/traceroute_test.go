@@ -39,17 +39,29 @@ func TestParseParams(t *testing.T) {
 			name: "all config",
 			host: "1.2.3.4",
 			params: map[string]string{
-				"port":    "42",
-				"max_ttl": "35",
-				"timeout": "1000",
+				"port":           "42",
+				"source_port":    "4242",
+				"max_ttl":        "35",
+				"probes_per_hop": "3",
+				"timeout":        "1000",
 			},
 			expectedConfig: tracerouteutil.Config{
 				DestHostname: "1.2.3.4",
 				DestPort:     42,
+				SourcePort:   4242,
 				MaxTTL:       35,
+				ProbesPerHop: 3,
 				Timeout:      1000,
 			},
 		},
+		{
+			name: "invalid probes per hop",
+			host: "1.2.3.4",
+			params: map[string]string{
+				"probes_per_hop": "300",
+			},
+			expectedError: `invalid probes_per_hop: strconv.ParseUint: parsing "300": value out of range`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(_ *testing.T) {
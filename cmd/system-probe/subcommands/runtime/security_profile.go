@@ -180,6 +180,8 @@ func printSecurityProfileMessage(msg *api.SecurityProfileMessage) {
 	}
 	fmt.Printf("%s  event_types: %v\n", prefix, msg.GetEventTypes())
 	fmt.Printf("%s  global_state: %v\n", prefix, msg.GetProfileGlobalState())
+	fmt.Printf("%s  drift_score: %v\n", prefix, msg.GetDriftScore())
+	fmt.Printf("%s  stability: %v\n", prefix, msg.GetStability())
 	fmt.Printf("%s  Versions:\n", prefix)
 	for imageTag, ctx := range msg.GetProfileContexts() {
 		fmt.Printf("%s  - %s:\n", prefix, imageTag)
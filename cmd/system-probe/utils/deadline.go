@@ -0,0 +1,32 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package utils
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DataDog/datadog-agent/cmd/system-probe/api/client"
+)
+
+// WithDeadline bounds the context of the requests by the timeout sent by
+// their caller, if any, so that handlers stop working on the requests their
+// caller already gave up on.
+func WithDeadline(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		timeoutMs, err := strconv.ParseInt(req.Header.Get(client.TimeoutHeader), 10, 64)
+		if err != nil || timeoutMs <= 0 {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(req.Context(), time.Duration(timeoutMs)*time.Millisecond)
+		defer cancel()
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
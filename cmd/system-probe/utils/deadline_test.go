@@ -0,0 +1,38 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/cmd/system-probe/api/client"
+)
+
+func TestWithDeadline(t *testing.T) {
+	var deadline time.Time
+	var hasDeadline bool
+	handler := WithDeadline(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		deadline, hasDeadline = req.Context().Deadline()
+	}))
+
+	req := httptest.NewRequest("GET", "http://sysprobe/network_tracer/connections", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.False(t, hasDeadline)
+
+	req.Header.Set(client.TimeoutHeader, "invalid")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.False(t, hasDeadline)
+
+	req.Header.Set(client.TimeoutHeader, "2000")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, hasDeadline)
+	assert.WithinDuration(t, time.Now().Add(2*time.Second), deadline, time.Second)
+}
@@ -21,9 +21,11 @@ import (
 	"github.com/DataDog/datadog-agent/comp/forwarder/eventplatform"
 	orchestratorforwarder "github.com/DataDog/datadog-agent/comp/forwarder/orchestrator"
 	haagent "github.com/DataDog/datadog-agent/comp/haagent/def"
+	rctypes "github.com/DataDog/datadog-agent/comp/remote-config/rcclient/types"
 	compression "github.com/DataDog/datadog-agent/comp/serializer/metricscompression/def"
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
 	"github.com/DataDog/datadog-agent/pkg/util/hostname"
 )
@@ -69,6 +71,7 @@ type provides struct {
 	SenderManager           sender.SenderManager
 	StatusProvider          status.InformationProvider
 	AggregatorDemultiplexer aggregator.Demultiplexer
+	RCListener              rctypes.ListenerProvider
 }
 
 func newDemultiplexer(deps dependencies) (provides, error) {
@@ -109,6 +112,11 @@ func newDemultiplexer(deps dependencies) (provides, error) {
 			Log: deps.Log,
 		}),
 		AggregatorDemultiplexer: demultiplexer,
+		RCListener: rctypes.ListenerProvider{
+			ListenerProvider: rctypes.RCListener{
+				state.ProductMetricTransformations: onMetricTransformationsUpdate(deps.Log, aggregator.SetRemoteMetricTransformations),
+			},
+		},
 	}, nil
 }
 
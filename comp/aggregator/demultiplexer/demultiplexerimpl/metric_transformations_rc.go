@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package demultiplexerimpl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+)
+
+// metricTransformationsConfig is the content of a METRIC_TRANSFORMATIONS remote configuration
+type metricTransformationsConfig struct {
+	Transformations []aggregator.MetricTransformation `json:"transformations"`
+}
+
+// onMetricTransformationsUpdate returns the callback replacing the metric transformations received
+// through remote config. A configuration containing an invalid transformation is rejected as a whole.
+func onMetricTransformationsUpdate(logger log.Component, setTransformations func([]aggregator.MetricTransformation)) func(map[string]state.RawConfig, func(string, state.ApplyStatus)) {
+	return func(updates map[string]state.RawConfig, applyStateCallback func(string, state.ApplyStatus)) {
+		// the configurations are applied in a stable order, their rules are applied in order
+		configPaths := make([]string, 0, len(updates))
+		for configPath := range updates {
+			configPaths = append(configPaths, configPath)
+		}
+		sort.Strings(configPaths)
+
+		var transformations []aggregator.MetricTransformation
+		for _, configPath := range configPaths {
+			config, err := parseMetricTransformationsConfig(updates[configPath].Config)
+			if err != nil {
+				logger.Warnf("Skipping invalid METRIC_TRANSFORMATIONS update %s: %v", configPath, err)
+				applyStateCallback(configPath, state.ApplyStatus{
+					State: state.ApplyStateError,
+					Error: err.Error(),
+				})
+				continue
+			}
+
+			transformations = append(transformations, config.Transformations...)
+			applyStateCallback(configPath, state.ApplyStatus{
+				State: state.ApplyStateAcknowledged,
+			})
+		}
+
+		setTransformations(transformations)
+		logger.Infof("%d metric transformations applied through remote config", len(transformations))
+	}
+}
+
+func parseMetricTransformationsConfig(raw []byte) (metricTransformationsConfig, error) {
+	var config metricTransformationsConfig
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return config, fmt.Errorf("error unmarshalling payload: %w", err)
+	}
+	for i, transformation := range config.Transformations {
+		if err := transformation.Validate(); err != nil {
+			return config, fmt.Errorf("invalid transformation %d: %w", i, err)
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,47 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package demultiplexerimpl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+)
+
+func TestOnMetricTransformationsUpdate(t *testing.T) {
+	var transformations []aggregator.MetricTransformation
+	onUpdate := onMetricTransformationsUpdate(logmock.New(t), func(rules []aggregator.MetricTransformation) {
+		transformations = rules
+	})
+
+	applied := map[string]state.ApplyStatus{}
+	applyStateCallback := func(path string, status state.ApplyStatus) { applied[path] = status }
+
+	onUpdate(map[string]state.RawConfig{
+		"b":       {Config: []byte(`{"transformations":[{"metrics":["app.*"],"action":"strip_tags","tags":["pod_name"]}]}`)},
+		"a":       {Config: []byte(`{"transformations":[{"metrics":["debug.*"],"action":"drop"}]}`)},
+		"corrupt": {Config: []byte(`{`)},
+		"invalid": {Config: []byte(`{"transformations":[{"metrics":["debug.*"],"action":"drop"},{"metrics":["app.latency"],"action":"rename"}]}`)},
+	}, applyStateCallback)
+
+	assert.Equal(t, state.ApplyStateAcknowledged, applied["a"].State)
+	assert.Equal(t, state.ApplyStateAcknowledged, applied["b"].State)
+	assert.Equal(t, state.ApplyStateError, applied["corrupt"].State)
+	assert.Equal(t, state.ApplyStateError, applied["invalid"].State)
+	assert.Contains(t, applied["invalid"].Error, "new_name")
+	assert.Equal(t, []aggregator.MetricTransformation{
+		{Metrics: []string{"debug.*"}, Action: aggregator.MetricTransformationDrop},
+		{Metrics: []string{"app.*"}, Action: aggregator.MetricTransformationStripTags, Tags: []string{"pod_name"}},
+	}, transformations)
+
+	// the remote transformations are removed when no configuration applies anymore
+	onUpdate(map[string]state.RawConfig{}, applyStateCallback)
+	assert.Empty(t, transformations)
+}
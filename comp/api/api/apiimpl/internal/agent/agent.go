@@ -10,6 +10,8 @@ package agent
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"sort"
@@ -17,6 +19,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/DataDog/datadog-agent/comp/api/api/apiimpl/observability"
 	api "github.com/DataDog/datadog-agent/comp/api/api/def"
 	"github.com/DataDog/datadog-agent/comp/api/api/utils"
 	"github.com/DataDog/datadog-agent/comp/collector/collector"
@@ -28,9 +31,12 @@ import (
 
 	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
 	"github.com/DataDog/datadog-agent/pkg/diagnose"
 	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+	"github.com/DataDog/datadog-agent/pkg/process/containertop"
 	"github.com/DataDog/datadog-agent/pkg/status/health"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/capabilities"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
@@ -43,16 +49,27 @@ func SetupHandlers(
 	collector option.Option[collector.Component],
 	ac autodiscovery.Component,
 	providers []api.EndpointProvider,
+	deprecation observability.DeprecationHandlerFactory,
 	tagger tagger.Component,
 ) *mux.Router {
 	// Register the handlers from the component providers
 	sort.Slice(providers, func(i, j int) bool { return providers[i].Route() < providers[j].Route() })
 	for _, p := range providers {
 		r.HandleFunc(p.Route(), p.HandlerFunc()).Methods(p.Methods()...)
+
+		// Keep serving the previous versions of the endpoint until their sunset date
+		for _, deprecated := range p.DeprecatedRoutes() {
+			r.Handle(deprecated.Route, deprecation.Handler(deprecated, p.Route(), p.HandlerFunc())).Methods(p.Methods()...)
+		}
 	}
 
 	// TODO: move these to a component that is registerable
 	r.HandleFunc("/status/health", getHealth).Methods("GET")
+	r.HandleFunc("/container-runtimes", getContainerRuntimes).Methods("GET")
+	containerTop := containertop.NewCollector(wmeta, pkgconfigsetup.Datadog())
+	r.HandleFunc("/container/{id}/top", func(w http.ResponseWriter, r *http.Request) {
+		getContainerTop(w, r, containerTop)
+	}).Methods("GET")
 	r.HandleFunc("/{component}/status", componentStatusHandler).Methods("POST")
 	r.HandleFunc("/{component}/configs", componentConfigHandler).Methods("GET")
 	r.HandleFunc("/diagnose", func(w http.ResponseWriter, r *http.Request) {
@@ -102,6 +119,51 @@ func getHealth(w http.ResponseWriter, _ *http.Request) {
 	w.Write(jsonHealth)
 }
 
+func getContainerRuntimes(w http.ResponseWriter, r *http.Request) {
+	jsonRuntimes, err := json.Marshal(capabilities.Get(r.Context()))
+	if err != nil {
+		log.Errorf("Error marshalling container runtimes: %v", err)
+		httputils.SetJSONError(w, err, 500)
+		return
+	}
+
+	w.Write(jsonRuntimes)
+}
+
+func getContainerTop(w http.ResponseWriter, r *http.Request, containerTop *containertop.Collector) {
+	interval := containertop.DefaultInterval
+	if v := r.URL.Query().Get("interval"); v != "" {
+		var err error
+		if interval, err = time.ParseDuration(v); err != nil {
+			httputils.SetJSONError(w, fmt.Errorf("invalid interval %q: %w", v, err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	top, err := containerTop.Top(r.Context(), mux.Vars(r)["id"], interval)
+	if err != nil {
+		code := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, containertop.ErrContainerNotFound):
+			code = http.StatusNotFound
+		case errors.Is(err, containertop.ErrAmbiguousContainer):
+			code = http.StatusBadRequest
+		}
+		httputils.SetJSONError(w, err, code)
+		return
+	}
+
+	jsonTop, err := json.Marshal(top)
+	if err != nil {
+		log.Errorf("Error marshalling container processes: %v", err)
+		httputils.SetJSONError(w, err, 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonTop)
+}
+
 func getDiagnose(w http.ResponseWriter, r *http.Request, diagnoseDeps diagnose.SuitesDeps) {
 	var diagCfg diagnosis.Config
 
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	// component dependencies
 	"github.com/stretchr/testify/assert"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/comp/aggregator/demultiplexer"
 	"github.com/DataDog/datadog-agent/comp/aggregator/demultiplexer/demultiplexerimpl"
+	"github.com/DataDog/datadog-agent/comp/api/api/apiimpl/observability"
 	api "github.com/DataDog/datadog-agent/comp/api/api/def"
 	"github.com/DataDog/datadog-agent/comp/collector/collector"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery"
@@ -26,6 +28,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/hostname/hostnameinterface"
 	log "github.com/DataDog/datadog-agent/comp/core/log/def"
 	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/comp/core/telemetry"
 	"github.com/DataDog/datadog-agent/comp/core/telemetry/telemetryimpl"
 	workloadmetafx "github.com/DataDog/datadog-agent/comp/core/workloadmeta/fx"
 
@@ -58,6 +61,7 @@ type handlerdeps struct {
 	Collector      option.Option[collector.Component]
 	Ac             autodiscovery.Mock
 	Tagger         taggermock.Mock
+	Telemetry      telemetry.Component
 }
 
 func getComponentDeps(t *testing.T) handlerdeps {
@@ -94,6 +98,12 @@ func setupRoutes(t *testing.T) *mux.Router {
 		api.NewAgentEndpointProvider(func(w http.ResponseWriter, _ *http.Request) {
 			w.Write([]byte("OK"))
 		}, "/dynamic_route", "GET").Provider,
+		api.NewAgentEndpointProvider(func(w http.ResponseWriter, _ *http.Request) {
+			w.Write([]byte("OK"))
+		}, "/v2/versioned_route", "GET").WithVersion("v2",
+			api.DeprecatedRoute{Route: "/versioned_route", Version: "v1"},
+			api.DeprecatedRoute{Route: "/v0/versioned_route", Version: "v0", Sunset: time.Now().Add(-time.Hour)},
+		).Provider,
 	}
 
 	router := mux.NewRouter()
@@ -105,6 +115,7 @@ func setupRoutes(t *testing.T) *mux.Router {
 		deps.Collector,
 		deps.Ac,
 		apiProviders,
+		observability.NewDeprecationHandlerFactory(deps.Telemetry),
 		deps.Tagger,
 	)
 
@@ -122,6 +133,36 @@ func TestSetupHandlers(t *testing.T) {
 			method:   "GET",
 			wantCode: 200,
 		},
+		{
+			route:    "/v2/versioned_route",
+			method:   "GET",
+			wantCode: 200,
+		},
+		{
+			route:    "/versioned_route",
+			method:   "GET",
+			wantCode: 200,
+		},
+		{
+			route:    "/v0/versioned_route",
+			method:   "GET",
+			wantCode: 410,
+		},
+		{
+			route:    "/versioned_route",
+			method:   "POST",
+			wantCode: 405,
+		},
+		{
+			route:    "/container/unknown/top",
+			method:   "GET",
+			wantCode: 404,
+		},
+		{
+			route:    "/container/unknown/top?interval=fast",
+			method:   "GET",
+			wantCode: 400,
+		},
 	}
 	router := setupRoutes(t)
 	ts := httptest.NewServer(router)
@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package observability
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/benbjohnson/clock"
+
+	api "github.com/DataDog/datadog-agent/comp/api/api/def"
+	"github.com/DataDog/datadog-agent/comp/core/telemetry"
+)
+
+const (
+	// DeprecatedMetricName is the name of the metric counting the requests received on deprecated routes
+	DeprecatedMetricName = "deprecated_route_requests"
+	deprecatedMetricHelp = "Number of requests received on deprecated routes by route and version."
+)
+
+type deprecationHandlerFactory struct {
+	requests telemetry.Counter
+	clock    clock.Clock
+}
+
+// DeprecationHandlerFactory creates handlers serving the deprecated routes of versioned endpoints
+type DeprecationHandlerFactory interface {
+	// Handler returns a handler serving the deprecated route with next, current being the route of the current
+	// version of the endpoint. Responses carry the Deprecation, Sunset and Link headers, and the route answers
+	// 410 Gone once its sunset date is reached.
+	Handler(route api.DeprecatedRoute, current string, next http.Handler) http.Handler
+}
+
+func (d *deprecationHandlerFactory) Handler(route api.DeprecatedRoute, current string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d.requests.Inc(route.Route, route.Version)
+
+		w.Header().Set("Deprecation", "true")
+		if successor := successorPath(r, route.Route, current); successor != "" {
+			w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		}
+
+		if !route.Sunset.IsZero() {
+			w.Header().Set("Sunset", route.Sunset.UTC().Format(http.TimeFormat))
+
+			if !d.clock.Now().Before(route.Sunset) {
+				http.Error(w, fmt.Sprintf("%s was removed on %s, use %s instead", route.Route, route.Sunset.UTC().Format(http.TimeFormat), current), http.StatusGone)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// successorPath returns the full path of the current version of the endpoint, keeping the prefix the deprecated
+// route was served under. Templated routes don't have a successor path as their variables can't be resolved.
+func successorPath(r *http.Request, deprecated string, current string) string {
+	if strings.Contains(deprecated, "{") || strings.Contains(current, "{") {
+		return ""
+	}
+
+	path := extractPath(r)
+	if !strings.HasSuffix(path, deprecated) {
+		return current
+	}
+	return strings.TrimSuffix(path, deprecated) + current
+}
+
+func newDeprecationHandlerFactory(telemetry telemetry.Component, clock clock.Clock) DeprecationHandlerFactory {
+	tags := []string{"route", "version"}
+	requests := telemetry.NewCounter(MetricSubsystem, DeprecatedMetricName, tags, deprecatedMetricHelp)
+
+	return &deprecationHandlerFactory{
+		requests,
+		clock,
+	}
+}
+
+// NewDeprecationHandlerFactory creates a new DeprecationHandlerFactory
+//
+// This function must be called only once for a given telemetry Component,
+// as it creates a new metric, and Prometheus panics if the same metric is registered twice.
+func NewDeprecationHandlerFactory(telemetry telemetry.Component) DeprecationHandlerFactory {
+	return newDeprecationHandlerFactory(telemetry, clock.New())
+}
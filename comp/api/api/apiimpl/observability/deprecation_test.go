@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package observability
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	api "github.com/DataDog/datadog-agent/comp/api/api/def"
+	"github.com/DataDog/datadog-agent/comp/core/telemetry"
+	"github.com/DataDog/datadog-agent/comp/core/telemetry/telemetryimpl"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func TestDeprecationHandler(t *testing.T) {
+	sunset := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		now          time.Time
+		route        api.DeprecatedRoute
+		current      string
+		wantCode     int
+		wantSunset   string
+		wantLink     string
+		wantServedBy bool
+	}{
+		{
+			name:         "before sunset",
+			now:          sunset.Add(-time.Hour),
+			route:        api.DeprecatedRoute{Route: "/config", Version: "v1", Sunset: sunset},
+			current:      "/v2/config",
+			wantCode:     http.StatusOK,
+			wantSunset:   "Tue, 01 Jan 2030 00:00:00 GMT",
+			wantLink:     `</agent/v2/config>; rel="successor-version"`,
+			wantServedBy: true,
+		},
+		{
+			name:         "after sunset",
+			now:          sunset,
+			route:        api.DeprecatedRoute{Route: "/config", Version: "v1", Sunset: sunset},
+			current:      "/v2/config",
+			wantCode:     http.StatusGone,
+			wantSunset:   "Tue, 01 Jan 2030 00:00:00 GMT",
+			wantLink:     `</agent/v2/config>; rel="successor-version"`,
+			wantServedBy: false,
+		},
+		{
+			name:         "no sunset",
+			now:          sunset,
+			route:        api.DeprecatedRoute{Route: "/config/{setting}", Version: "v1"},
+			current:      "/v2/config/{setting}",
+			wantCode:     http.StatusOK,
+			wantServedBy: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := clock.NewMock()
+			clock.Set(tc.now)
+			telemetry := fxutil.Test[telemetry.Mock](t, telemetryimpl.MockModule())
+			factory := newDeprecationHandlerFactory(telemetry, clock)
+
+			served := false
+			var next http.HandlerFunc = func(w http.ResponseWriter, _ *http.Request) {
+				served = true
+				w.WriteHeader(http.StatusOK)
+			}
+
+			mux := http.NewServeMux()
+			mux.Handle("/agent/", http.StripPrefix("/agent", factory.Handler(tc.route, tc.current, next)))
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			resp, err := server.Client().Get(server.URL + "/agent/config")
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, tc.wantCode, resp.StatusCode)
+			assert.Equal(t, tc.wantServedBy, served)
+			assert.Equal(t, "true", resp.Header.Get("Deprecation"))
+			assert.Equal(t, tc.wantSunset, resp.Header.Get("Sunset"))
+			assert.Equal(t, tc.wantLink, resp.Header.Get("Link"))
+
+			metrics, err := telemetry.GetCountMetric(MetricSubsystem, DeprecatedMetricName)
+			require.NoError(t, err)
+			require.Len(t, metrics, 1)
+			assert.EqualValues(t, 1, metrics[0].Value())
+			assert.Equal(t, map[string]string{
+				"route":   tc.route.Route,
+				"version": tc.route.Version,
+			}, metrics[0].Tags())
+		})
+	}
+}
@@ -56,6 +56,7 @@ func (server *apiServer) startCMDServer(
 		grpc.MaxRecvMsgSize(maxMessageSize),
 		grpc.MaxSendMsgSize(maxMessageSize),
 	}
+	opts = append(opts, grpcutil.ServerKeepaliveOptions()...)
 
 	// event size should be small enough to fit within the grpc max message size
 	maxEventSize := maxMessageSize / 2
@@ -114,6 +115,7 @@ func (server *apiServer) startCMDServer(
 				server.collector,
 				server.autoConfig,
 				server.endpointProviders,
+				observability.NewDeprecationHandlerFactory(server.telemetry),
 				server.taggerComp,
 			)))
 	cmdMux.Handle("/check/", http.StripPrefix("/check", check.SetupHandlers(checkMux)))
@@ -9,6 +9,7 @@ package def
 import (
 	"net"
 	"net/http"
+	"time"
 
 	"go.uber.org/fx"
 )
@@ -32,13 +33,33 @@ type EndpointProvider interface {
 
 	Methods() []string
 	Route() string
+
+	// Version returns the version of the endpoint served on Route, empty when
+	// the endpoint isn't versioned.
+	Version() string
+	// DeprecatedRoutes returns the routes of the previous versions of the
+	// endpoint, which are still served until their sunset date.
+	DeprecatedRoutes() []DeprecatedRoute
+}
+
+// DeprecatedRoute is a route of a previous version of an endpoint. It is
+// served by the same handler as the current version, with headers announcing
+// its deprecation, until its sunset date after which it answers 410 Gone.
+type DeprecatedRoute struct {
+	Route   string
+	Version string
+	// Sunset is the date after which the route isn't served anymore. A zero
+	// value means the route is deprecated but has no planned removal.
+	Sunset time.Time
 }
 
 // endpointProvider is the implementation of EndpointProvider interface
 type endpointProvider struct {
-	methods []string
-	route   string
-	handler http.HandlerFunc
+	methods    []string
+	route      string
+	handler    http.HandlerFunc
+	version    string
+	deprecated []DeprecatedRoute
 }
 
 // AuthorizedSet is a type to store the authorized config options for the config API
@@ -97,6 +118,16 @@ func (p endpointProvider) HandlerFunc() http.HandlerFunc {
 	return p.handler
 }
 
+// Version returns the version of the endpoint.
+func (p endpointProvider) Version() string {
+	return p.version
+}
+
+// DeprecatedRoutes returns the routes of the previous versions of the endpoint.
+func (p endpointProvider) DeprecatedRoutes() []DeprecatedRoute {
+	return p.deprecated
+}
+
 // AgentEndpointProvider is the provider for registering endpoints to the internal agent api server
 type AgentEndpointProvider struct {
 	fx.Out
@@ -114,3 +145,17 @@ func NewAgentEndpointProvider(handlerFunc http.HandlerFunc, route string, method
 		},
 	}
 }
+
+// WithVersion returns a copy of the AgentEndpointProvider serving its route as
+// the given version of the endpoint, and the given deprecated routes for the
+// consumers which haven't migrated yet.
+func (p AgentEndpointProvider) WithVersion(version string, deprecated ...DeprecatedRoute) AgentEndpointProvider {
+	provider, ok := p.Provider.(endpointProvider)
+	if !ok {
+		return p
+	}
+
+	provider.version = version
+	provider.deprecated = append([]DeprecatedRoute(nil), deprecated...)
+	return AgentEndpointProvider{Provider: provider}
+}
@@ -13,23 +13,28 @@ import (
 	"github.com/DataDog/datadog-agent/cmd/agent/common/signals"
 	api "github.com/DataDog/datadog-agent/comp/api/api/def"
 	"github.com/DataDog/datadog-agent/pkg/api/version"
+	"github.com/DataDog/datadog-agent/pkg/fips"
 	"github.com/DataDog/datadog-agent/pkg/util/hostname"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
 // Provider provides the common Agent API endpoints
 type Provider struct {
-	VersionEndpoint  api.AgentEndpointProvider
-	HostnameEndpoint api.AgentEndpointProvider
-	StopEndpoint     api.AgentEndpointProvider
+	VersionEndpoint             api.AgentEndpointProvider
+	HostnameEndpoint            api.AgentEndpointProvider
+	HostnameDiagnosticsEndpoint api.AgentEndpointProvider
+	FIPSStatusEndpoint          api.AgentEndpointProvider
+	StopEndpoint                api.AgentEndpointProvider
 }
 
 // CommonEndpointProvider return a filled Provider struct
 func CommonEndpointProvider() Provider {
 	return Provider{
-		VersionEndpoint:  api.NewAgentEndpointProvider(version.Get, "/version", "GET"),
-		HostnameEndpoint: api.NewAgentEndpointProvider(getHostname, "/hostname", "GET"),
-		StopEndpoint:     api.NewAgentEndpointProvider(stopAgent, "/stop", "POST"),
+		VersionEndpoint:             api.NewAgentEndpointProvider(version.Get, "/version", "GET"),
+		HostnameEndpoint:            api.NewAgentEndpointProvider(getHostname, "/hostname", "GET"),
+		HostnameDiagnosticsEndpoint: api.NewAgentEndpointProvider(getHostnameDiagnostics, "/hostname/diagnostics", "GET"),
+		FIPSStatusEndpoint:          api.NewAgentEndpointProvider(getFIPSStatus, "/fips-status", "GET"),
+		StopEndpoint:                api.NewAgentEndpointProvider(stopAgent, "/stop", "POST"),
 	}
 }
 
@@ -45,6 +50,24 @@ func getHostname(w http.ResponseWriter, r *http.Request) {
 	w.Write(j)
 }
 
+// getHostnameDiagnostics returns, as a JSON response, which hostname providers were tried and which one won.
+func getHostnameDiagnostics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	diagnosis, err := hostname.GetDiagnosis(r.Context())
+	if err != nil {
+		log.Warnf("Error getting hostname: %s", err)
+	}
+	j, _ := json.Marshal(diagnosis)
+	w.Write(j)
+}
+
+// getFIPSStatus returns, as a JSON response, the FIPS mode of the Agent and the compliance of its subsystems.
+func getFIPSStatus(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j, _ := json.Marshal(fips.GetReport())
+	w.Write(j)
+}
+
 // StopAgent stops the agent by sending a signal to the stopper channel.
 func stopAgent(w http.ResponseWriter, _ *http.Request) {
 	signals.Stopper <- true
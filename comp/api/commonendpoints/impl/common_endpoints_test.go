@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/DataDog/datadog-agent/cmd/agent/common/signals"
+	"github.com/DataDog/datadog-agent/pkg/fips"
 	"github.com/DataDog/datadog-agent/pkg/util/hostname"
 )
 
@@ -40,6 +41,41 @@ func TestGetHostname(t *testing.T) {
 	require.Equal(t, expectedResponse, rr.Body.Bytes())
 }
 
+func TestGetHostnameDiagnostics(t *testing.T) {
+	req, err := http.NewRequest("GET", "/hostname/diagnostics", nil)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(getHostnameDiagnostics)
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var response hostname.Diagnosis
+	err = json.Unmarshal(rr.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	expected, _ := hostname.GetWithProvider(context.Background())
+	require.Equal(t, expected.Hostname, response.Hostname)
+	require.Equal(t, expected.Provider, response.Provider)
+}
+
+func TestGetFIPSStatus(t *testing.T) {
+	req, err := http.NewRequest("GET", "/fips-status", nil)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(getFIPSStatus)
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var response fips.Report
+	err = json.Unmarshal(rr.Body.Bytes(), &response)
+	require.NoError(t, err)
+	require.Equal(t, fips.Status(), response.Status)
+}
+
 func TestStopAgent(t *testing.T) {
 	// Make a channel to exit the function
 	stopCh := make(chan error)
@@ -60,7 +60,7 @@ func newDatadogFallbackClient(config configComponent.Component, logger logComp.C
 	}
 	for _, e := range endpoints {
 		client := datadog.NewClient(e.APIKey, e.APPKey)
-		client.HttpClient.Transport = httputils.CreateHTTPTransport(config)
+		client.HttpClient.Transport = httputils.NewTransport(config, httputils.DefaultDestination)
 		client.RetryTimeout = 3 * time.Second
 		client.ExtraHeader["User-Agent"] = "Datadog-Cluster-Agent"
 		client.SetBaseUrl(e.URL)
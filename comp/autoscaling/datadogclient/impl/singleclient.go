@@ -50,7 +50,7 @@ func newDatadogSingleClient(cfg configComponent.Component, logger logComp.Compon
 	logger.Infof("Initialized the Datadog Client for HPA with endpoint %q", ddEndpoint)
 
 	client := datadog.NewClient(apiKey, appKey)
-	client.HttpClient.Transport = httputils.CreateHTTPTransport(cfg)
+	client.HttpClient.Transport = httputils.NewTransport(cfg, httputils.DefaultDestination)
 	client.RetryTimeout = 3 * time.Second
 	client.ExtraHeader["User-Agent"] = "Datadog-Cluster-Agent"
 	client.SetBaseUrl(ddEndpoint)
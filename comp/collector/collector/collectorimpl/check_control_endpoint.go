@@ -0,0 +1,70 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package collectorimpl
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+)
+
+// pauseCheckHandler pauses the check given by the id query parameter
+func (c *collectorImpl) pauseCheckHandler(w http.ResponseWriter, r *http.Request) {
+	c.checkControlHandler(w, r, c.PauseCheck)
+}
+
+// resumeCheckHandler resumes the check given by the id query parameter
+func (c *collectorImpl) resumeCheckHandler(w http.ResponseWriter, r *http.Request) {
+	c.checkControlHandler(w, r, c.ResumeCheck)
+}
+
+// runCheckNowHandler triggers an immediate run of the check given by the id query parameter
+func (c *collectorImpl) runCheckNowHandler(w http.ResponseWriter, r *http.Request) {
+	c.checkControlHandler(w, r, c.RunCheckNow)
+}
+
+// checkControlHandler applies the action to the check given by the id query parameter. The ID isn't
+// part of the path since check IDs may contain slashes, e.g. when the instance name is an URL.
+func (c *collectorImpl) checkControlHandler(w http.ResponseWriter, r *http.Request, action func(checkid.ID) error) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		httputils.SetJSONError(w, errors.New("missing id parameter"), http.StatusBadRequest)
+		return
+	}
+
+	if err := action(checkid.ID(id)); err != nil {
+		httputils.SetJSONError(w, err, checkControlErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pausedChecksHandler writes the list of the paused check IDs
+func (c *collectorImpl) pausedChecksHandler(w http.ResponseWriter, _ *http.Request) {
+	ids := c.GetPausedChecks()
+	if ids == nil {
+		ids = []checkid.ID{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(ids); err != nil {
+		httputils.SetJSONError(w, err, http.StatusInternalServerError)
+	}
+}
+
+func checkControlErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errCheckNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errCheckNotPaused):
+		return http.StatusConflict
+	default:
+		return http.StatusServiceUnavailable
+	}
+}
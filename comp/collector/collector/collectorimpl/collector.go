@@ -9,7 +9,9 @@ package collectorimpl
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -46,6 +48,12 @@ const (
 	started
 )
 
+var (
+	errNotRunning     = errors.New("the collector is not running")
+	errCheckNotFound  = errors.New("cannot find a check with this ID")
+	errCheckNotPaused = errors.New("the check is not paused")
+)
+
 type dependencies struct {
 	fx.In
 
@@ -88,6 +96,12 @@ type provides struct {
 	StatusProvider   status.InformationProvider
 	MetadataProvider metadata.Provider
 	APIGetPyStatus   api.AgentEndpointProvider
+	APIGetSBOM       api.AgentEndpointProvider
+	APIScanSBOM      api.AgentEndpointProvider
+	APIPauseCheck    api.AgentEndpointProvider
+	APIResumeCheck   api.AgentEndpointProvider
+	APIRunCheckNow   api.AgentEndpointProvider
+	APIPausedChecks  api.AgentEndpointProvider
 	FlareProvider    flaretypes.Provider
 }
 
@@ -114,6 +128,12 @@ func newProvides(deps dependencies) provides {
 		StatusProvider:   status.NewInformationProvider(collectorStatus.Provider{}),
 		MetadataProvider: agentCheckMetadata,
 		APIGetPyStatus:   api.NewAgentEndpointProvider(getPythonStatus, "/py/status", "GET"),
+		APIGetSBOM:       api.NewAgentEndpointProvider(getContainerImageSBOM, "/sbom/container-image", "GET"),
+		APIScanSBOM:      api.NewAgentEndpointProvider(scanContainerImage, "/sbom/container-image", "POST"),
+		APIPauseCheck:    api.NewAgentEndpointProvider(c.pauseCheckHandler, "/checks/pause", "POST"),
+		APIResumeCheck:   api.NewAgentEndpointProvider(c.resumeCheckHandler, "/checks/resume", "POST"),
+		APIRunCheckNow:   api.NewAgentEndpointProvider(c.runCheckNowHandler, "/checks/run-now", "POST"),
+		APIPausedChecks:  api.NewAgentEndpointProvider(c.pausedChecksHandler, "/checks/paused", "GET"),
 		FlareProvider:    flaretypes.NewProvider(c.fillFlare),
 	}
 }
@@ -195,6 +215,8 @@ func (c *collectorImpl) start(_ context.Context) error {
 
 	// let the runner some visibility into the scheduler
 	run.SetScheduler(sched)
+	// let the health supervisor add workers when the scheduler is stuck
+	sched.SetRunnerRestarter(run.AddReplacementWorker)
 	sched.Run()
 
 	c.scheduler = sched
@@ -404,3 +426,58 @@ func (c *collectorImpl) ReloadAllCheckInstances(name string, newInstances []chec
 	}
 	return killed, nil
 }
+
+// PauseCheck stops running a check on its schedule until ResumeCheck is called. The paused state is
+// kept by the scheduler, so a check reloaded with the same configuration, and therefore the same ID,
+// stays paused.
+func (c *collectorImpl) PauseCheck(id checkid.ID) error {
+	if !c.started() {
+		return errNotRunning
+	}
+
+	if _, found := c.get(id); !found {
+		return fmt.Errorf("%w: %s", errCheckNotFound, id)
+	}
+
+	c.scheduler.Pause(id)
+	return nil
+}
+
+// ResumeCheck resumes running a paused check on its schedule
+func (c *collectorImpl) ResumeCheck(id checkid.ID) error {
+	if !c.started() {
+		return errNotRunning
+	}
+
+	if !c.scheduler.Resume(id) {
+		return fmt.Errorf("%w: %s", errCheckNotPaused, id)
+	}
+	return nil
+}
+
+// RunCheckNow triggers an immediate run of a check, even if it is paused. If the check is
+// already running, the run is skipped by the worker.
+func (c *collectorImpl) RunCheckNow(id checkid.ID) error {
+	if !c.started() {
+		return errNotRunning
+	}
+
+	ch, found := c.get(id)
+	if !found {
+		return fmt.Errorf("%w: %s", errCheckNotFound, id)
+	}
+
+	c.scheduler.RunNow(ch)
+	return nil
+}
+
+// GetPausedChecks returns the IDs of the paused checks
+func (c *collectorImpl) GetPausedChecks() []checkid.ID {
+	if !c.started() {
+		return nil
+	}
+
+	ids := c.scheduler.PausedChecks()
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
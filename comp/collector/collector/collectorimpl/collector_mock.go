@@ -89,3 +89,23 @@ func (c *mockimpl) ReloadAllCheckInstances(_ string, _ []check.Check) ([]checkid
 
 // AddEventReceiver adds a callback to the collector to be called each time a check is added or removed.
 func (c *mockimpl) AddEventReceiver(_ collector.EventReceiver) {}
+
+// PauseCheck stops running a check on its schedule until ResumeCheck is called
+func (c *mockimpl) PauseCheck(_ checkid.ID) error {
+	return nil
+}
+
+// ResumeCheck resumes running a paused check on its schedule
+func (c *mockimpl) ResumeCheck(_ checkid.ID) error {
+	return nil
+}
+
+// RunCheckNow triggers an immediate run of a check
+func (c *mockimpl) RunCheckNow(_ checkid.ID) error {
+	return nil
+}
+
+// GetPausedChecks returns the IDs of the paused checks
+func (c *mockimpl) GetPausedChecks() []checkid.ID {
+	return nil
+}
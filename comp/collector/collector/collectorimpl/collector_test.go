@@ -9,6 +9,8 @@ package collectorimpl
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"sort"
 	"testing"
 	"time"
@@ -276,6 +278,65 @@ func (suite *CollectorTestSuite) TestReloadAllCheckInstances() {
 	assert.Zero(suite.T(), len(suite.c.checks))
 }
 
+func (suite *CollectorTestSuite) TestPauseResumeCheck() {
+	assert.ErrorIs(suite.T(), suite.c.PauseCheck("foo"), errCheckNotFound)
+
+	_, err := suite.c.RunCheck(NewCheckUnique("foo", "TestCheck"))
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.c.PauseCheck("foo"))
+	assert.Equal(suite.T(), []checkid.ID{"foo"}, suite.c.GetPausedChecks())
+
+	// a reloaded check with the same ID stays paused
+	_, err = suite.c.ReloadAllCheckInstances("TestCheck", []check.Check{NewCheckUnique("foo", "TestCheck")})
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), suite.c.scheduler.IsCheckPaused("foo"))
+
+	assert.NoError(suite.T(), suite.c.ResumeCheck("foo"))
+	assert.Empty(suite.T(), suite.c.GetPausedChecks())
+	assert.ErrorIs(suite.T(), suite.c.ResumeCheck("foo"), errCheckNotPaused)
+}
+
+func (suite *CollectorTestSuite) TestRunCheckNow() {
+	assert.ErrorIs(suite.T(), suite.c.RunCheckNow("foo"), errCheckNotFound)
+
+	_, err := suite.c.RunCheck(NewCheckUnique("foo", "TestCheck"))
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.c.PauseCheck("foo"))
+	assert.NoError(suite.T(), suite.c.RunCheckNow("foo"))
+}
+
+func (suite *CollectorTestSuite) TestCheckControlHandlers() {
+	_, err := suite.c.RunCheck(NewCheckUnique("foo/bar", "TestCheck"))
+	assert.NoError(suite.T(), err)
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		query   string
+		code    int
+	}{
+		{"missing id", suite.c.pauseCheckHandler, "", http.StatusBadRequest},
+		{"unknown check", suite.c.pauseCheckHandler, "?id=baz", http.StatusNotFound},
+		{"pause", suite.c.pauseCheckHandler, "?id=foo%2Fbar", http.StatusNoContent},
+		{"run now", suite.c.runCheckNowHandler, "?id=foo%2Fbar", http.StatusNoContent},
+		{"resume", suite.c.resumeCheckHandler, "?id=foo%2Fbar", http.StatusNoContent},
+		{"resume twice", suite.c.resumeCheckHandler, "?id=foo%2Fbar", http.StatusConflict},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			rec := httptest.NewRecorder()
+			tt.handler(rec, httptest.NewRequest(http.MethodPost, "/checks/action"+tt.query, nil))
+			assert.Equal(suite.T(), tt.code, rec.Code)
+		})
+	}
+
+	assert.NoError(suite.T(), suite.c.PauseCheck("foo/bar"))
+	rec := httptest.NewRecorder()
+	suite.c.pausedChecksHandler(rec, httptest.NewRequest(http.MethodGet, "/checks/paused", nil))
+	assert.Equal(suite.T(), http.StatusOK, rec.Code)
+	assert.JSONEq(suite.T(), `["foo/bar"]`, rec.Body.String())
+}
+
 func TestCollectorSuite(t *testing.T) {
 	suite.Run(t, new(CollectorTestSuite))
 }
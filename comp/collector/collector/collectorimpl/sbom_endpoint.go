@@ -0,0 +1,80 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package collectorimpl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/CycloneDX/cyclonedx-go"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/sbom/scanner"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+)
+
+// getContainerImageSBOM writes the SBOM of the container image given by the image_id
+// query parameter in the CycloneDX JSON format
+func getContainerImageSBOM(w http.ResponseWriter, r *http.Request) {
+	s := scanner.GetGlobalScanner()
+	if s == nil {
+		httputils.SetJSONError(w, errors.New("SBOM collection is not enabled"), http.StatusNotFound)
+		return
+	}
+	imageID := r.URL.Query().Get("image_id")
+	if imageID == "" {
+		httputils.SetJSONError(w, errors.New("missing image_id parameter"), http.StatusBadRequest)
+		return
+	}
+
+	sbom, err := s.GetImageSBOM(imageID)
+	if err != nil {
+		httputils.SetJSONError(w, err, imageErrorStatus(err))
+		return
+	}
+
+	switch {
+	case sbom == nil || sbom.Status == workloadmeta.Pending:
+		httputils.SetJSONError(w, fmt.Errorf("no SBOM generated yet for image %s", imageID), http.StatusNotFound)
+	case sbom.Status == workloadmeta.Failed:
+		httputils.SetJSONError(w, fmt.Errorf("SBOM generation failed for image %s: %s", imageID, sbom.Error), http.StatusInternalServerError)
+	default:
+		w.Header().Set("Content-Type", "application/vnd.cyclonedx+json")
+		if err := cyclonedx.NewBOMEncoder(w, cyclonedx.BOMFileFormatJSON).Encode(sbom.CycloneDXBOM); err != nil {
+			httputils.SetJSONError(w, err, http.StatusInternalServerError)
+		}
+	}
+}
+
+// scanContainerImage triggers the generation of the SBOM of the container image given by
+// the image_id query parameter. The collector query parameter optionally selects the SBOM
+// collector to use, e.g. "cri" when the runtime socket isn't mounted.
+func scanContainerImage(w http.ResponseWriter, r *http.Request) {
+	s := scanner.GetGlobalScanner()
+	if s == nil {
+		httputils.SetJSONError(w, errors.New("SBOM collection is not enabled"), http.StatusNotFound)
+		return
+	}
+	imageID := r.URL.Query().Get("image_id")
+	if imageID == "" {
+		httputils.SetJSONError(w, errors.New("missing image_id parameter"), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.ScanImage(imageID, r.URL.Query().Get("collector")); err != nil {
+		httputils.SetJSONError(w, err, imageErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func imageErrorStatus(err error) int {
+	if errors.Is(err, scanner.ErrImageNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -45,6 +45,14 @@ type Component interface {
 	ReloadAllCheckInstances(name string, newInstances []check.Check) ([]checkid.ID, error)
 	// AddEventReceiver adds a callback to the collector to be called each time a check is added or removed.
 	AddEventReceiver(cb EventReceiver)
+	// PauseCheck stops running a check on its schedule until ResumeCheck is called, even if it gets reloaded
+	PauseCheck(id checkid.ID) error
+	// ResumeCheck resumes running a paused check on its schedule
+	ResumeCheck(id checkid.ID) error
+	// RunCheckNow triggers an immediate run of a check, even if it is paused
+	RunCheckNow(id checkid.ID) error
+	// GetPausedChecks returns the IDs of the paused checks
+	GetPausedChecks() []checkid.ID
 }
 
 // NoneModule return a None optional type for Component.
@@ -160,7 +160,7 @@ func httpClientFactory(cfg config.Reader, timeout time.Duration) func() *http.Cl
 		return &http.Client{
 			Timeout: timeout,
 			// reusing core agent HTTP transport to benefit from proxy settings.
-			Transport: httputils.CreateHTTPTransport(cfg),
+			Transport: httputils.NewTransport(cfg, httputils.AgentTelemetryDestination),
 		}
 	}
 }
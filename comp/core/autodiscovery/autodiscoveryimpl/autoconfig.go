@@ -88,6 +88,12 @@ type AutoConfig struct {
 	logs                     logComp.Component
 	telemetryStore           *acTelemetry.Store
 
+	// secretsRefreshed is notified when refreshedSecretsConfigs, the names of
+	// the configs whose secrets were refreshed, is updated
+	secretsRefreshed        chan struct{}
+	refreshedSecretsConfigs map[string]struct{}
+	refreshedSecretsMu      sync.Mutex
+
 	// m covers the `configPollers`, `listenerCandidates`, `listeners`, and `listenerRetryStop`, but
 	// not the values they point to.
 	m sync.RWMutex
@@ -211,6 +217,11 @@ func createNewAutoConfig(schedulerController *scheduler.Controller, secretResolv
 		taggerComp:               taggerComp,
 		logs:                     logs,
 		telemetryStore:           acTelemetry.NewStore(telemetryComp),
+		secretsRefreshed:         make(chan struct{}, 1),
+		refreshedSecretsConfigs:  make(map[string]struct{}),
+	}
+	if secretResolver != nil {
+		secretResolver.SubscribeToRefresh(ac.onSecretRefreshed)
 	}
 	return ac
 }
@@ -234,6 +245,8 @@ func (ac *AutoConfig) serviceListening() {
 			ac.processNewService(ctx, svc)
 		case svc := <-ac.delService:
 			ac.processDelService(ctx, svc)
+		case <-ac.secretsRefreshed:
+			ac.processRefreshedSecrets()
 		}
 	}
 }
@@ -538,6 +551,39 @@ func (ac *AutoConfig) processNewConfig(config integration.Config) integration.Co
 	return changes
 }
 
+// onSecretRefreshed records the config embedding a refreshed secret, the
+// origin of the secret being the name of the config. It is called with the
+// lock of the secret resolver held: the configs are decrypted again by the
+// service listening goroutine.
+func (ac *AutoConfig) onSecretRefreshed(_, origin string, _ []string, _, _ any) {
+	ac.refreshedSecretsMu.Lock()
+	ac.refreshedSecretsConfigs[origin] = struct{}{}
+	ac.refreshedSecretsMu.Unlock()
+
+	select {
+	case ac.secretsRefreshed <- struct{}{}:
+	default:
+	}
+}
+
+// processRefreshedSecrets reschedules the configs whose secrets were
+// refreshed, so that the checks use the new value of the secrets.
+func (ac *AutoConfig) processRefreshedSecrets() {
+	ac.refreshedSecretsMu.Lock()
+	configNames := ac.refreshedSecretsConfigs
+	ac.refreshedSecretsConfigs = make(map[string]struct{})
+	ac.refreshedSecretsMu.Unlock()
+
+	if len(configNames) == 0 {
+		return
+	}
+
+	changes, changedIDsOfSecretsWithConfigs := ac.cfgMgr.processRefreshedSecrets(configNames)
+	ac.applyChanges(changes)
+	ac.deleteMappingsOfCheckIDsWithSecrets(changes.Unschedule)
+	ac.store.setIDsOfChecksWithSecrets(changedIDsOfSecretsWithConfigs)
+}
+
 // AddListeners tries to initialise the listeners listed in the given configs. A first
 // try is done synchronously. If a listener fails with a ErrWillRetry, the initialization
 // will be re-triggered later until success or ErrPermaFail.
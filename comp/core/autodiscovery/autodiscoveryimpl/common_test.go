@@ -9,6 +9,7 @@ import (
 	"context"
 	"reflect"
 
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/common/adexpr"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/listeners"
 	"github.com/DataDog/datadog-agent/pkg/util/containers"
@@ -21,6 +22,7 @@ type dummyService struct {
 	Ports           []listeners.ContainerPort
 	Pid             int
 	Hostname        string
+	ExprAttributes  adexpr.Attributes
 	filterTemplates func(map[string]integration.Config)
 }
 
@@ -94,3 +96,8 @@ func (s *dummyService) FilterTemplates(configs map[string]integration.Config) {
 		(s.filterTemplates)(configs)
 	}
 }
+
+// GetExprAttributes returns the dummy expression attributes
+func (s *dummyService) GetExprAttributes() adexpr.Attributes {
+	return s.ExprAttributes
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/common/adexpr"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/configresolver"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/listeners"
@@ -41,6 +42,11 @@ type configManager interface {
 	// interface apply to only one config.
 	processDelConfigs(configs []integration.Config) integration.ConfigChanges
 
+	// processRefreshedSecrets decrypts again the configs with the given
+	// names, whose secrets were refreshed, rescheduling those whose
+	// decrypted content changed.
+	processRefreshedSecrets(configNames map[string]struct{}) (integration.ConfigChanges, map[checkid.ID]checkid.ID)
+
 	// mapOverLoadedConfigs calls the given function with a map of all
 	// loaded configs (those which have been scheduled but not unscheduled).
 	// The call is made with the manager's lock held, so callers should perform
@@ -88,10 +94,19 @@ type reconcilingConfigManager struct {
 	// identifiers.  It is an index to activeServices.
 	servicesByADID multimap
 
+	// templateExprs contains the compiled ad_identifiers_expr expression of
+	// the templates having one, indexed by their digest.  It is an index to
+	// activeConfigs.
+	templateExprs map[string]*adexpr.Program
+
 	// serviceResolutions maps a serviceID to the resolutions performed for
 	// that service: serviceID -> template digest -> resolved config digest.
 	serviceResolutions map[string]map[string]string
 
+	// decryptedConfigs maps the digest of each non-template config to the
+	// digest of the config scheduled for it, once its secrets are decrypted.
+	decryptedConfigs map[string]string
+
 	// scheduledConfigs contains an entry for each scheduled config, keyed
 	// by its digest.  This is a mix of resolved templates and non-template
 	// configs.  The returned integration.ConfigChanges from interface
@@ -110,7 +125,9 @@ func newReconcilingConfigManager(secretResolver secrets.Component) configManager
 		activeServices:     map[string]serviceAndADIDs{},
 		templatesByADID:    newMultimap(),
 		servicesByADID:     newMultimap(),
+		templateExprs:      map[string]*adexpr.Program{},
 		serviceResolutions: map[string]map[string]string{},
+		decryptedConfigs:   map[string]string{},
 		scheduledConfigs:   map[string]integration.Config{},
 		secretResolver:     secretResolver,
 	}
@@ -189,6 +206,17 @@ func (cm *reconcilingConfigManager) processNewConfig(config integration.Config)
 		return integration.ConfigChanges{}, changedIDsOfSecretsWithConfigs
 	}
 
+	var expr *adexpr.Program
+	if config.ADIdentifiersExpr != "" {
+		var err error
+		if expr, err = adexpr.Compile(config.ADIdentifiersExpr); err != nil {
+			msg := fmt.Sprintf("error compiling template %s: %v", config.Name, err)
+			log.Error(msg)
+			errorStats.setResolveWarning(config.Name, msg)
+			return integration.ConfigChanges{}, changedIDsOfSecretsWithConfigs
+		}
+	}
+
 	// Execute the steps outlined in the comment on reconcilingConfigManager:
 	//
 	//  1. update orctiveConfigs / activeServices
@@ -197,12 +225,12 @@ func (cm *reconcilingConfigManager) processNewConfig(config integration.Config)
 	var changes integration.ConfigChanges
 	if config.IsTemplate() {
 		//  2. update templatesByADID or servicesByADID to match
-		matchingServices := map[string]struct{}{}
+		matchingServices := cm.templateCandidateServices(config)
 		for _, adID := range config.ADIdentifiers {
 			cm.templatesByADID.insert(adID, digest)
-			for _, svcID := range cm.servicesByADID.get(adID) {
-				matchingServices[svcID] = struct{}{}
-			}
+		}
+		if expr != nil {
+			cm.templateExprs[digest] = expr
 		}
 
 		//  3. update serviceResolutions, generating changes
@@ -228,6 +256,7 @@ func (cm *reconcilingConfigManager) processNewConfig(config integration.Config)
 		}
 
 		changes.ScheduleConfig(decryptedConfig)
+		cm.decryptedConfigs[digest] = decryptedConfig.Digest()
 	}
 
 	//  4. update scheduledConfigs
@@ -255,19 +284,24 @@ func (cm *reconcilingConfigManager) processDelConfigs(configs []integration.Conf
 		var changes integration.ConfigChanges
 		if config.IsTemplate() {
 			//  2. update templatesByADID or servicesByADID to match
-			matchingServices := map[string]struct{}{}
+			matchingServices := cm.templateCandidateServices(config)
 			for _, adID := range config.ADIdentifiers {
 				cm.templatesByADID.remove(adID, digest)
-				for _, svcID := range cm.servicesByADID.get(adID) {
-					matchingServices[svcID] = struct{}{}
-				}
 			}
+			delete(cm.templateExprs, digest)
 
 			//  3. update serviceResolutions, generating changes
 			for svcID := range matchingServices {
 				changes.Merge(cm.reconcileService(svcID))
 			}
+		} else if scheduled, found := cm.scheduledConfigs[cm.decryptedConfigs[digest]]; found {
+			// the config scheduled with its secrets decrypted is unscheduled,
+			// as the secrets may have been refreshed since then.
+			delete(cm.decryptedConfigs, digest)
+			changes.UnscheduleConfig(scheduled)
 		} else {
+			delete(cm.decryptedConfigs, digest)
+
 			// Secrets need to be resolved before being unscheduled as otherwise
 			// the computed hashes can be different from the ones computed at schedule time.
 			config, err := decryptConfig(config, cm.secretResolver)
@@ -285,6 +319,66 @@ func (cm *reconcilingConfigManager) processDelConfigs(configs []integration.Conf
 	return allChanges
 }
 
+// processRefreshedSecrets implements configManager#processRefreshedSecrets.
+func (cm *reconcilingConfigManager) processRefreshedSecrets(configNames map[string]struct{}) (integration.ConfigChanges, map[checkid.ID]checkid.ID) {
+	cm.m.Lock()
+	defer cm.m.Unlock()
+
+	var changes integration.ConfigChanges
+	changedIDsOfSecretsWithConfigs := make(map[checkid.ID]checkid.ID)
+
+	for digest, config := range cm.activeConfigs {
+		if _, found := configNames[config.Name]; !found || config.IsTemplate() {
+			continue
+		}
+		scheduled, found := cm.scheduledConfigs[cm.decryptedConfigs[digest]]
+		if !found {
+			continue
+		}
+
+		decryptedConfig, err := decryptConfig(config, cm.secretResolver)
+		if err != nil {
+			log.Errorf("Unable to resolve refreshed secrets for config '%s', keeping the check configuration unchanged, err: %s", config.Name, err.Error())
+			continue
+		}
+		if decryptedConfig.Digest() == scheduled.Digest() {
+			continue
+		}
+
+		log.Infof("Secrets of config '%s' were refreshed, rescheduling it", config.Name)
+		if config.Provider == names.ClusterChecks {
+			for newID, originalID := range changedCheckIDs(config, decryptedConfig) {
+				changedIDsOfSecretsWithConfigs[newID] = originalID
+			}
+		}
+		changes.UnscheduleConfig(scheduled)
+		changes.ScheduleConfig(decryptedConfig)
+		cm.decryptedConfigs[digest] = decryptedConfig.Digest()
+	}
+
+	for svcID, resolutions := range cm.serviceResolutions {
+		svc := cm.activeServices[svcID].svc
+		for templateDigest, resolvedDigest := range resolutions {
+			tpl := cm.activeConfigs[templateDigest]
+			if _, found := configNames[tpl.Name]; !found || svc == nil {
+				continue
+			}
+
+			resolved, ok := cm.resolveTemplateForService(tpl, svc)
+			if !ok || resolved.Digest() == resolvedDigest {
+				continue
+			}
+
+			log.Infof("Secrets of config '%s' for service %s were refreshed, rescheduling it", tpl.Name, svcID)
+			changes.UnscheduleConfig(cm.scheduledConfigs[resolvedDigest])
+			changes.ScheduleConfig(resolved)
+			resolutions[templateDigest] = resolved.Digest()
+		}
+	}
+
+	return cm.applyChanges(changes), changedIDsOfSecretsWithConfigs
+}
+
 // mapOverLoadedConfigs implements configManager#mapOverLoadedConfigs.
 func (cm *reconcilingConfigManager) mapOverLoadedConfigs(f func(map[string]integration.Config)) {
 	cm.m.Lock()
@@ -324,6 +418,23 @@ func (cm *reconcilingConfigManager) reconcileService(svcID string) integration.C
 		}
 	}
 
+	// templates with an ad_identifiers_expr expression are only resolved for
+	// the services matching it, in addition to one of their AD identifiers
+	// when they have some.
+	if svc != nil {
+		for digest, expr := range cm.templateExprs {
+			tpl := cm.activeConfigs[digest]
+			if _, found := expectedResolutions[digest]; !found && len(tpl.ADIdentifiers) > 0 {
+				continue
+			}
+			if matchTemplateExpr(tpl, expr, svc) {
+				expectedResolutions[digest] = tpl
+			} else {
+				delete(expectedResolutions, digest)
+			}
+		}
+	}
+
 	// allow the service to filter those templates, unless we are removing
 	// the service, in which case no resolutions are expected.
 	if svc != nil {
@@ -361,6 +472,43 @@ func (cm *reconcilingConfigManager) reconcileService(svcID string) integration.C
 	return changes
 }
 
+// templateCandidateServices returns the serviceIDs of the services the given
+// template may be resolved for: those having one of its AD identifiers, or all
+// the services if the template only has an ad_identifiers_expr expression.
+//
+// This method must be called with cm.m locked.
+func (cm *reconcilingConfigManager) templateCandidateServices(tpl integration.Config) map[string]struct{} {
+	matchingServices := map[string]struct{}{}
+	if len(tpl.ADIdentifiers) == 0 && tpl.ADIdentifiersExpr != "" {
+		for svcID := range cm.activeServices {
+			matchingServices[svcID] = struct{}{}
+		}
+		return matchingServices
+	}
+
+	for _, adID := range tpl.ADIdentifiers {
+		for _, svcID := range cm.servicesByADID.get(adID) {
+			matchingServices[svcID] = struct{}{}
+		}
+	}
+	return matchingServices
+}
+
+// matchTemplateExpr returns whether the ad_identifiers_expr expression of the
+// template matches the service.  Services not exposing their attributes never
+// match.
+func matchTemplateExpr(tpl integration.Config, expr *adexpr.Program, svc listeners.Service) bool {
+	exprSvc, ok := svc.(listeners.ExprAttributesService)
+	if !ok {
+		return false
+	}
+	match, err := expr.Match(exprSvc.GetExprAttributes())
+	if err != nil {
+		log.Debugf("ad_identifiers_expr of template %s does not match service %s: %v", tpl.Name, svc.GetServiceID(), err)
+	}
+	return match
+}
+
 // resolveTemplateForService resolves a template config for the given service,
 // updating errorStats in the process.  If the resolution fails, this method
 // returns false.
@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/common/adexpr"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/listeners"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/providers/names"
@@ -227,6 +228,59 @@ func (suite *ConfigManagerSuite) TestNewClusterCheckWithSecretsScheduled() {
 	require.True(suite.T(), strings.Contains(string(changes.Unschedule[0].Instances[0]), "barDecoded"))
 }
 
+// A non-template config is rescheduled when its secrets are refreshed with a
+// new value, and the rescheduled config is unscheduled when deleted
+func (suite *ConfigManagerSuite) TestRefreshedSecretsRescheduled() {
+	mockResolver := MockSecretResolver{suite.T(), []mockSecretScenario{
+		{
+			expectedData:   []byte("foo: ENC[bar]"),
+			expectedOrigin: nonTemplateConfigWithSecrets.Name,
+			returnedData:   []byte("foo: barDecoded"),
+			returnedError:  nil,
+		},
+		{
+			expectedData:   []byte{},
+			expectedOrigin: nonTemplateConfigWithSecrets.Name,
+			returnedData:   []byte{},
+			returnedError:  nil,
+		},
+	}}
+	// assign mock secretResolver to the configManager
+	cm := suite.cm.(*reconcilingConfigManager)
+	cm.secretResolver = &mockResolver
+
+	inputNewConfig := deepcopy.Copy(nonTemplateConfigWithSecrets).(integration.Config)
+	changes, _ := suite.cm.processNewConfig(inputNewConfig)
+	assertConfigsMatch(suite.T(), changes.Schedule, matchName(nonTemplateConfigWithSecrets.Name))
+	decodedDigest := changes.Schedule[0].Digest()
+
+	// the secret has the same value, nothing is rescheduled
+	changes, _ = suite.cm.processRefreshedSecrets(map[string]struct{}{nonTemplateConfigWithSecrets.Name: {}})
+	assertConfigsMatch(suite.T(), changes.Schedule)
+	assertConfigsMatch(suite.T(), changes.Unschedule)
+
+	// the secret was rotated, the config is rescheduled
+	mockResolver.scenarios[0].returnedData = []byte("foo: barRotated")
+	changes, _ = suite.cm.processRefreshedSecrets(map[string]struct{}{"other-config": {}})
+	assertConfigsMatch(suite.T(), changes.Schedule)
+	assertConfigsMatch(suite.T(), changes.Unschedule)
+
+	changes, _ = suite.cm.processRefreshedSecrets(map[string]struct{}{nonTemplateConfigWithSecrets.Name: {}})
+	assertConfigsMatch(suite.T(), changes.Unschedule, matchDigest(decodedDigest))
+	assertConfigsMatch(suite.T(), changes.Schedule, matchName(nonTemplateConfigWithSecrets.Name))
+	require.True(suite.T(), strings.Contains(string(changes.Schedule[0].Instances[0]), "barRotated"))
+	rotatedDigest := changes.Schedule[0].Digest()
+	assertLoadedConfigsMatch(suite.T(), suite.cm, matchDigest(rotatedDigest))
+
+	// the rescheduled config is unscheduled, even if its secrets were refreshed again
+	mockResolver.scenarios[0].returnedData = []byte("foo: barRotatedAgain")
+	inputDelConfig := deepcopy.Copy(nonTemplateConfigWithSecrets).(integration.Config)
+	changes = suite.cm.processDelConfigs([]integration.Config{inputDelConfig})
+	assertConfigsMatch(suite.T(), changes.Schedule)
+	assertConfigsMatch(suite.T(), changes.Unschedule, matchDigest(rotatedDigest))
+	assertLoadedConfigsMatch(suite.T(), suite.cm)
+}
+
 // A new template config is not scheduled when there is no matching service, and
 // not unscheduled when removed
 func (suite *ConfigManagerSuite) TestNewTemplateNotScheduled() {
@@ -323,6 +377,54 @@ func (suite *ConfigManagerSuite) TestNewServiceBeforeTemplate_ServiceRemovedFirs
 	assertConfigsMatch(suite.T(), changes.Unschedule)
 }
 
+// A template with an ad_identifiers_expr expression is resolved for the
+// services matching it, and only for those also having one of its AD
+// identifiers when it has some.
+func (suite *ConfigManagerSuite) TestTemplateWithADIdentifiersExpr() {
+	redisProd := &dummyService{ID: "redis-prod", ADIdentifiers: []string{"redis"}, ExprAttributes: adexpr.Attributes{ImageShortName: "redis", Namespace: "prod-cache"}}
+	redisDev := &dummyService{ID: "redis-dev", ADIdentifiers: []string{"redis"}, ExprAttributes: adexpr.Attributes{ImageShortName: "redis", Namespace: "dev"}}
+	otherProd := &dummyService{ID: "other-prod", ADIdentifiers: []string{"other"}, ExprAttributes: adexpr.Attributes{ImageShortName: "redis", Namespace: "prod-other"}}
+
+	for _, svc := range []*dummyService{redisProd, redisDev, otherProd} {
+		changes := suite.cm.processNewService(svc.ADIdentifiers, svc)
+		assertConfigsMatch(suite.T(), changes.Schedule)
+	}
+
+	exprOnly := integration.Config{Name: "expr-only", ADIdentifiersExpr: `container.image.short_name == "redis" && kube.namespace.startsWith("prod-")`}
+	changes, _ := suite.cm.processNewConfig(exprOnly)
+	assertConfigsMatch(suite.T(), changes.Schedule,
+		matchAll(matchName("expr-only"), matchSvc("redis-prod")),
+		matchAll(matchName("expr-only"), matchSvc("other-prod")),
+	)
+
+	withADIDs := integration.Config{Name: "with-adids", ADIdentifiers: []string{"redis"}, ADIdentifiersExpr: `kube.namespace.startsWith("prod-")`}
+	changes, _ = suite.cm.processNewConfig(withADIDs)
+	assertConfigsMatch(suite.T(), changes.Schedule, matchAll(matchName("with-adids"), matchSvc("redis-prod")))
+
+	// a service arriving after the templates is matched against the expressions
+	redisProd2 := &dummyService{ID: "redis-prod-2", ADIdentifiers: []string{"redis"}, ExprAttributes: adexpr.Attributes{ImageShortName: "redis", Namespace: "prod-2"}}
+	changes = suite.cm.processNewService(redisProd2.ADIdentifiers, redisProd2)
+	assertConfigsMatch(suite.T(), changes.Schedule,
+		matchAll(matchName("expr-only"), matchSvc("redis-prod-2")),
+		matchAll(matchName("with-adids"), matchSvc("redis-prod-2")),
+	)
+
+	// an invalid expression is not scheduled
+	invalid := integration.Config{Name: "invalid", ADIdentifiersExpr: `container.image.short_name ==`}
+	changes, _ = suite.cm.processNewConfig(invalid)
+	assertConfigsMatch(suite.T(), changes.Schedule)
+
+	changes = suite.cm.processDelConfigs([]integration.Config{exprOnly, withADIDs, invalid})
+	assertConfigsMatch(suite.T(), changes.Unschedule,
+		matchAll(matchName("expr-only"), matchSvc("redis-prod")),
+		matchAll(matchName("expr-only"), matchSvc("other-prod")),
+		matchAll(matchName("expr-only"), matchSvc("redis-prod-2")),
+		matchAll(matchName("with-adids"), matchSvc("redis-prod")),
+		matchAll(matchName("with-adids"), matchSvc("redis-prod-2")),
+	)
+	assertLoadedConfigsMatch(suite.T(), suite.cm)
+}
+
 // Fuzz the config manager to ensure it doesn't "leak" configs -- that schedule
 // and unschedule calls are always properly paired.
 func (suite *ConfigManagerSuite) TestFuzz() {
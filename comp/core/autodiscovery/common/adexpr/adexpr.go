@@ -0,0 +1,125 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Package adexpr compiles and evaluates the `ad_identifiers_expr` CEL
+// expressions of the autodiscovery templates against the attributes of the
+// discovered services.
+//
+// The expressions have access to the following variables:
+//
+//	container.name                 name of the container
+//	container.image.raw            image as specified in the container spec
+//	container.image.name           image name, including its registry
+//	container.image.short_name     image name without registry nor tag
+//	container.image.tag            image tag
+//	container.labels               map of the container labels
+//	kube.namespace                 namespace of the pod, empty outside Kubernetes
+//	kube.pod_name                  name of the pod, empty outside Kubernetes
+//	kube.labels                    map of the pod labels
+//	kube.annotations               map of the pod annotations
+//
+// e.g. `container.image.short_name == "redis" && kube.namespace.startsWith("prod-")`
+package adexpr
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+)
+
+// Attributes are the attributes of a service an expression is evaluated against
+type Attributes struct {
+	ContainerName   string
+	ImageRaw        string
+	ImageName       string
+	ImageShortName  string
+	ImageTag        string
+	ContainerLabels map[string]string
+
+	Namespace      string
+	PodName        string
+	PodLabels      map[string]string
+	PodAnnotations map[string]string
+}
+
+// activation returns the variables of the expressions for the attributes
+func (a Attributes) activation() map[string]interface{} {
+	return map[string]interface{}{
+		"container": map[string]interface{}{
+			"name": a.ContainerName,
+			"image": map[string]interface{}{
+				"raw":        a.ImageRaw,
+				"name":       a.ImageName,
+				"short_name": a.ImageShortName,
+				"tag":        a.ImageTag,
+			},
+			"labels": nonNilMap(a.ContainerLabels),
+		},
+		"kube": map[string]interface{}{
+			"namespace":   a.Namespace,
+			"pod_name":    a.PodName,
+			"labels":      nonNilMap(a.PodLabels),
+			"annotations": nonNilMap(a.PodAnnotations),
+		},
+	}
+}
+
+func nonNilMap(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
+
+// Program is a compiled expression
+type Program struct {
+	expr string
+	prg  cel.Program
+}
+
+// Compile parses and type-checks an expression, which must evaluate to a boolean
+func Compile(expr string) (*Program, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("container", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("kube", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("invalid ad_identifiers_expr %q: %s", expr, iss.Err())
+	}
+	if !ast.OutputType().IsExactType(cel.BoolType) && !ast.OutputType().IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("invalid ad_identifiers_expr %q: the expression must evaluate to a boolean, not %s", expr, ast.OutputType())
+	}
+
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ad_identifiers_expr %q: %s", expr, err)
+	}
+	return &Program{expr: expr, prg: prg}, nil
+}
+
+// String returns the source of the expression
+func (p *Program) String() string {
+	return p.expr
+}
+
+// Match evaluates the expression against the attributes. An expression that
+// fails to evaluate, e.g. because it reads a label missing from the service,
+// or that doesn't evaluate to a boolean doesn't match.
+func (p *Program) Match(attrs Attributes) (bool, error) {
+	out, _, err := p.prg.Eval(attrs.activation())
+	if err != nil {
+		return false, err
+	}
+	match, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q evaluated to %v instead of a boolean", p.expr, out.Value())
+	}
+	return match, nil
+}
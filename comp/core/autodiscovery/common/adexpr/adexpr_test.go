@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package adexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileErrors(t *testing.T) {
+	_, err := Compile(`container.image.short_name ==`)
+	assert.ErrorContains(t, err, "invalid ad_identifiers_expr")
+
+	_, err = Compile(`unknown.name == "redis"`)
+	assert.ErrorContains(t, err, "undeclared reference to 'unknown'")
+
+	_, err = Compile(`"redis"`)
+	assert.ErrorContains(t, err, "the expression must evaluate to a boolean, not string")
+}
+
+func TestMatch(t *testing.T) {
+	redis := Attributes{
+		ContainerName:   "redis",
+		ImageRaw:        "redis:7.2",
+		ImageName:       "redis",
+		ImageShortName:  "redis",
+		ImageTag:        "7.2",
+		ContainerLabels: map[string]string{"com.example.team": "storage"},
+		Namespace:       "prod-cache",
+		PodName:         "redis-0",
+		PodLabels:       map[string]string{"app": "redis"},
+		PodAnnotations:  map[string]string{"example.com/monitor": "true"},
+	}
+	nginx := Attributes{
+		ContainerName:  "nginx",
+		ImageRaw:       "gcr.io/example/nginx:1.27",
+		ImageName:      "gcr.io/example/nginx",
+		ImageShortName: "nginx",
+		ImageTag:       "1.27",
+	}
+
+	tests := []struct {
+		name  string
+		expr  string
+		attrs Attributes
+		match bool
+		err   bool
+	}{
+		{
+			name:  "image and namespace",
+			expr:  `container.image.short_name == "redis" && kube.namespace.startsWith("prod-")`,
+			attrs: redis,
+			match: true,
+		},
+		{
+			name:  "image and namespace mismatch",
+			expr:  `container.image.short_name == "redis" && kube.namespace.startsWith("prod-")`,
+			attrs: nginx,
+			match: false,
+		},
+		{
+			name:  "label lookup",
+			expr:  `"app" in kube.labels && kube.labels["app"] == "redis"`,
+			attrs: redis,
+			match: true,
+		},
+		{
+			name:  "label lookup without labels",
+			expr:  `"app" in kube.labels && kube.labels["app"] == "redis"`,
+			attrs: nginx,
+			match: false,
+		},
+		{
+			name:  "annotation and container label",
+			expr:  `kube.annotations["example.com/monitor"] == "true" && container.labels["com.example.team"] == "storage"`,
+			attrs: redis,
+			match: true,
+		},
+		{
+			name:  "image registry",
+			expr:  `container.image.name.startsWith("gcr.io/") && container.image.tag.matches("^1\\.")`,
+			attrs: nginx,
+			match: true,
+		},
+		{
+			name:  "missing key",
+			expr:  `kube.labels["app"] == "redis"`,
+			attrs: nginx,
+			match: false,
+			err:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prg, err := Compile(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expr, prg.String())
+
+			match, err := prg.Match(tt.attrs)
+			if tt.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.match, match)
+		})
+	}
+}
@@ -73,6 +73,13 @@ type Config struct {
 	// see ADIdentifiers.  (optional)
 	AdvancedADIdentifiers []AdvancedADIdentifier `json:"advanced_ad_identifiers"` // (include in digest: false)
 
+	// ADIdentifiersExpr is a CEL expression evaluated against the attributes of
+	// the discovered services (image, labels, annotations, namespace...). A
+	// config with an expression is a template: when it has no ADIdentifiers it
+	// is resolved for every service matching the expression, otherwise the
+	// service must match both an identifier and the expression. (optional)
+	ADIdentifiersExpr string `json:"ad_identifiers_expr"` // (include in digest: true)
+
 	// Provider is the name of the config provider that issued the config.  If
 	// this is "", then the config is a service config, representing a service
 	// discovered by a listener.
@@ -194,7 +201,7 @@ func (c *Config) ScrubbedString() string {
 
 // IsTemplate returns if the config has AD identifiers
 func (c *Config) IsTemplate() bool {
-	return len(c.ADIdentifiers) > 0 || len(c.AdvancedADIdentifiers) > 0
+	return len(c.ADIdentifiers) > 0 || len(c.AdvancedADIdentifiers) > 0 || c.ADIdentifiersExpr != ""
 }
 
 // IsCheckConfig returns true if the config is a node-agent check configuration,
@@ -414,6 +421,10 @@ func (c *Config) IntDigest() uint64 {
 	for _, i := range c.ADIdentifiers {
 		_, _ = h.Write([]byte(i))
 	}
+	if c.ADIdentifiersExpr != "" {
+		// only hashed when set to keep the digest of the other configs stable
+		_, _ = h.Write([]byte(c.ADIdentifiersExpr))
+	}
 	_, _ = h.Write([]byte(c.NodeName))
 	_, _ = h.Write([]byte(c.LogsConfig))
 	_, _ = h.Write([]byte(c.ServiceID))
@@ -437,6 +448,10 @@ func (c *Config) FastDigest() uint64 {
 	for _, i := range c.ADIdentifiers {
 		_, _ = h.Write([]byte(i))
 	}
+	if c.ADIdentifiersExpr != "" {
+		// only hashed when set to keep the digest of the other configs stable
+		_, _ = h.Write([]byte(c.ADIdentifiersExpr))
+	}
 	_, _ = h.Write([]byte(c.NodeName))
 	_, _ = h.Write([]byte(c.LogsConfig))
 	_, _ = h.Write([]byte(c.ServiceID))
@@ -478,6 +493,7 @@ func (c *Config) Dump(multiline bool) string {
 	fmt.Fprintf(&b, ws("LogsConfig: %s,"), dataField(c.LogsConfig))
 	fmt.Fprintf(&b, ws("ADIdentifiers: %#v,"), c.ADIdentifiers)
 	fmt.Fprintf(&b, ws("AdvancedADIdentifiers: %#v,"), c.AdvancedADIdentifiers)
+	fmt.Fprintf(&b, ws("ADIdentifiersExpr: %#v,"), c.ADIdentifiersExpr)
 	fmt.Fprintf(&b, ws("Provider: %#v,"), c.Provider)
 	fmt.Fprintf(&b, ws("ServiceID: %#v,"), c.ServiceID)
 	fmt.Fprintf(&b, ws("TaggerEntity: %#v,"), c.TaggerEntity)
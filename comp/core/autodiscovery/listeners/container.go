@@ -120,10 +120,11 @@ func (l *ContainerListener) createContainerService(entity workloadmeta.Entity) {
 			containerImg.RawName,
 			container.Labels,
 		),
-		ports:    ports,
-		pid:      container.PID,
-		hostname: container.Hostname,
-		tagger:   l.tagger,
+		ports:          ports,
+		pid:            container.PID,
+		hostname:       container.Hostname,
+		tagger:         l.tagger,
+		exprAttributes: newContainerExprAttributes(container.Name, containerImg, container.Labels, pod),
 	}
 
 	if pod != nil {
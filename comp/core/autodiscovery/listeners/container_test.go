@@ -14,6 +14,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/common/adexpr"
 	tagger "github.com/DataDog/datadog-agent/comp/core/tagger/def"
 	"github.com/DataDog/datadog-agent/comp/core/tagger/mock"
 	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
@@ -185,6 +186,11 @@ func TestCreateContainerService(t *testing.T) {
 						hosts: map[string]string{},
 						ports: []ContainerPort{},
 						ready: true,
+						exprAttributes: adexpr.Attributes{
+							ContainerName:  containerName,
+							ImageRaw:       "gcr.io/foobar:latest",
+							ImageShortName: "foobar",
+						},
 					},
 				},
 			},
@@ -222,6 +228,11 @@ func TestCreateContainerService(t *testing.T) {
 						hosts: map[string]string{},
 						ports: []ContainerPort{},
 						ready: true,
+						exprAttributes: adexpr.Attributes{
+							ContainerName:  containerName,
+							ImageRaw:       "gcr.io/foobar:latest",
+							ImageShortName: "foobar",
+						},
 					},
 				},
 			},
@@ -250,6 +261,11 @@ func TestCreateContainerService(t *testing.T) {
 							},
 						},
 						ready: true,
+						exprAttributes: adexpr.Attributes{
+							ContainerName:  containerName,
+							ImageRaw:       "foobar",
+							ImageShortName: "foobar",
+						},
 					},
 				},
 			},
@@ -271,6 +287,15 @@ func TestCreateContainerService(t *testing.T) {
 						hosts: map[string]string{"pod": pod.IP},
 						ports: []ContainerPort{},
 						ready: pod.Ready,
+						exprAttributes: adexpr.Attributes{
+							ContainerName:   "foobar",
+							ImageRaw:        "gcr.io/foobar:latest",
+							ImageShortName:  "foobar",
+							ContainerLabels: map[string]string{"io.kubernetes.foo": "bar"},
+							Namespace:       podNamespace,
+							PodName:         podName,
+							PodAnnotations:  pod.Annotations,
+						},
 					},
 				},
 			},
@@ -185,7 +185,8 @@ func (l *KubeletListener) createContainerService(
 			containerImg.RawName,
 			pod.Namespace,
 		),
-		tagger: l.tagger,
+		tagger:         l.tagger,
+		exprAttributes: newContainerExprAttributes(containerName, containerImg, container.Labels, pod),
 	}
 
 	adIdentifier := containerName
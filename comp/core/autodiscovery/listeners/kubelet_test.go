@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/common/adexpr"
 	tagger "github.com/DataDog/datadog-agent/comp/core/tagger/def"
 	"github.com/DataDog/datadog-agent/comp/core/tagger/mock"
 	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
@@ -263,6 +264,13 @@ func TestKubeletCreateContainerService(t *testing.T) {
 							"pod_uid":   podID,
 						},
 						tagger: taggerComponent,
+						exprAttributes: adexpr.Attributes{
+							ContainerName:  containerName,
+							ImageRaw:       "gcr.io/foobar:latest",
+							ImageShortName: "foobar",
+							Namespace:      podNamespace,
+							PodName:        podName,
+						},
 					},
 				},
 			},
@@ -296,6 +304,13 @@ func TestKubeletCreateContainerService(t *testing.T) {
 							"pod_uid":   podID,
 						},
 						tagger: taggerComponent,
+						exprAttributes: adexpr.Attributes{
+							ContainerName:  containerName,
+							ImageRaw:       "foobar",
+							ImageShortName: "foobar",
+							Namespace:      podNamespace,
+							PodName:        podName,
+						},
 					},
 				},
 			},
@@ -351,6 +366,13 @@ func TestKubeletCreateContainerService(t *testing.T) {
 							"pod_uid":   podID,
 						},
 						tagger: taggerComponent,
+						exprAttributes: adexpr.Attributes{
+							ContainerName:  containerName,
+							ImageRaw:       "foobar",
+							ImageShortName: "foobar",
+							Namespace:      podNamespace,
+							PodName:        podName,
+						},
 					},
 				},
 			},
@@ -392,6 +414,13 @@ func TestKubeletCreateContainerService(t *testing.T) {
 							"pod_uid":   podID,
 						},
 						tagger: taggerComponent,
+						exprAttributes: adexpr.Attributes{
+							ContainerName:  containerName,
+							ImageRaw:       "foobar",
+							ImageShortName: "foobar",
+							Namespace:      podNamespace,
+							PodName:        podName,
+						},
 					},
 				},
 			},
@@ -426,6 +455,14 @@ func TestKubeletCreateContainerService(t *testing.T) {
 							"pod_uid":   podID,
 						},
 						tagger: taggerComponent,
+						exprAttributes: adexpr.Attributes{
+							ContainerName:  containerName,
+							ImageRaw:       "foobar",
+							ImageShortName: "foobar",
+							Namespace:      podNamespace,
+							PodName:        podName,
+							PodAnnotations: podWithAnnotations.Annotations,
+						},
 					},
 				},
 			},
@@ -472,6 +509,14 @@ func TestKubeletCreateContainerService(t *testing.T) {
 						},
 						metricsExcluded: true,
 						tagger:          taggerComponent,
+						exprAttributes: adexpr.Attributes{
+							ContainerName:  containerName,
+							ImageRaw:       "foobar",
+							ImageShortName: "foobar",
+							Namespace:      podNamespace,
+							PodName:        podName,
+							PodAnnotations: podWithMetricsExcludeAnnotation.Annotations,
+						},
 					},
 				},
 			},
@@ -507,6 +552,14 @@ func TestKubeletCreateContainerService(t *testing.T) {
 						},
 						logsExcluded: true,
 						tagger:       taggerComponent,
+						exprAttributes: adexpr.Attributes{
+							ContainerName:  containerName,
+							ImageRaw:       "foobar",
+							ImageShortName: "foobar",
+							Namespace:      podNamespace,
+							PodName:        podName,
+							PodAnnotations: podWithLogsExcludeAnnotation.Annotations,
+						},
 					},
 				},
 			},
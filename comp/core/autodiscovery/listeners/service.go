@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/common/adexpr"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/providers/names"
 	taggercommon "github.com/DataDog/datadog-agent/comp/core/tagger/common"
@@ -38,9 +39,11 @@ type service struct {
 	metricsExcluded bool
 	logsExcluded    bool
 	tagger          tagger.Component
+	exprAttributes  adexpr.Attributes
 }
 
 var _ Service = &service{}
+var _ ExprAttributesService = &service{}
 
 // Equal returns whether the two service are equal
 func (s *service) Equal(o Service) bool {
@@ -55,6 +58,7 @@ func (s *service) Equal(o Service) bool {
 		reflect.DeepEqual(s.ports, s2.ports) &&
 		reflect.DeepEqual(s.adIdentifiers, s2.adIdentifiers) &&
 		reflect.DeepEqual(s.checkNames, s2.checkNames) &&
+		reflect.DeepEqual(s.exprAttributes, s2.exprAttributes) &&
 		s.hostname == s2.hostname &&
 		s.pid == s2.pid &&
 		s.ready == s2.ready
@@ -215,3 +219,29 @@ func (s *service) GetExtraConfig(key string) (string, error) {
 
 	return result, nil
 }
+
+// GetExprAttributes returns the attributes of the service matched by the
+// `ad_identifiers_expr` expression of the templates.
+func (s *service) GetExprAttributes() adexpr.Attributes {
+	return s.exprAttributes
+}
+
+// newContainerExprAttributes builds the expression attributes of a container,
+// and of the pod it belongs to if any.
+func newContainerExprAttributes(containerName string, image workloadmeta.ContainerImage, containerLabels map[string]string, pod *workloadmeta.KubernetesPod) adexpr.Attributes {
+	attrs := adexpr.Attributes{
+		ContainerName:   containerName,
+		ImageRaw:        image.RawName,
+		ImageName:       image.Name,
+		ImageShortName:  image.ShortName,
+		ImageTag:        image.Tag,
+		ContainerLabels: containerLabels,
+	}
+	if pod != nil {
+		attrs.Namespace = pod.Namespace
+		attrs.PodName = pod.Name
+		attrs.PodLabels = pod.Labels
+		attrs.PodAnnotations = pod.Annotations
+	}
+	return attrs
+}
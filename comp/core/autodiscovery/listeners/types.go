@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/common/adexpr"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/telemetry"
 	tagger "github.com/DataDog/datadog-agent/comp/core/tagger/def"
@@ -51,6 +52,13 @@ type Service interface {
 	FilterTemplates(map[string]integration.Config)
 }
 
+// ExprAttributesService is implemented by the services whose attributes can
+// be matched by the `ad_identifiers_expr` expression of the templates. The
+// other services are never matched by an expression.
+type ExprAttributesService interface {
+	GetExprAttributes() adexpr.Attributes
+}
+
 // ServiceListener monitors running services and triggers check (un)scheduling
 //
 // It holds a cache of running services, listens to new/killed services and
@@ -28,6 +28,7 @@ import (
 type configFormat struct {
 	ADIdentifiers           []string                           `yaml:"ad_identifiers"`
 	AdvancedADIdentifiers   []integration.AdvancedADIdentifier `yaml:"advanced_ad_identifiers"`
+	ADIdentifiersExpr       string                             `yaml:"ad_identifiers_expr"`
 	ClusterCheck            bool                               `yaml:"cluster_check"`
 	InitConfig              interface{}                        `yaml:"init_config"`
 	MetricConfig            interface{}                        `yaml:"jmx_metrics"`
@@ -295,7 +296,7 @@ func collectEntry(file os.DirEntry, path string, integrationName string, integra
 	}
 
 	// if logs is the only integration, set isLogsOnly to true
-	if entry.conf.LogsConfig != nil && entry.conf.MetricConfig == nil && len(entry.conf.Instances) == 0 && len(entry.conf.ADIdentifiers) == 0 {
+	if entry.conf.LogsConfig != nil && entry.conf.MetricConfig == nil && len(entry.conf.Instances) == 0 && len(entry.conf.ADIdentifiers) == 0 && entry.conf.ADIdentifiersExpr == "" {
 		entry.isLogsOnly = true
 	}
 
@@ -424,6 +425,7 @@ func GetIntegrationConfigFromFile(name, fpath string) (integration.Config, error
 	// Copy auto discovery identifiers
 	conf.ADIdentifiers = cf.ADIdentifiers
 	conf.AdvancedADIdentifiers = cf.AdvancedADIdentifiers
+	conf.ADIdentifiersExpr = cf.ADIdentifiersExpr
 
 	// Copy cluster_check status
 	conf.ClusterCheck = cf.ClusterCheck
@@ -80,6 +80,12 @@ func TestGetIntegrationConfig(t *testing.T) {
 	require.Nil(t, err)
 	assert.Equal(t, config.AdvancedADIdentifiers, []integration.AdvancedADIdentifier{{KubeService: integration.KubeNamespacedName{Name: "svc-name", Namespace: "svc-ns"}}})
 
+	config, err = GetIntegrationConfigFromFile("foo", "tests/ad_expr.yaml")
+	require.Nil(t, err)
+	assert.Equal(t, `container.image.short_name == "redis" && kube.namespace.startsWith("prod-")`, config.ADIdentifiersExpr)
+	assert.Empty(t, config.ADIdentifiers)
+	assert.True(t, config.IsTemplate())
+
 	// autodiscovery: check if we correctly refuse to load if a 'docker_images' section is present
 	config, err = GetIntegrationConfigFromFile("foo", "tests/ad_deprecated.yaml")
 	assert.NotNil(t, err)
@@ -98,7 +104,7 @@ func TestReadConfigFiles(t *testing.T) {
 
 	configs, errors, err := ReadConfigFiles(GetAll)
 	require.Nil(t, err)
-	require.Equal(t, 19, len(configs))
+	require.Equal(t, 20, len(configs))
 	require.Equal(t, 4, len(errors))
 
 	for _, c := range configs {
@@ -109,7 +115,7 @@ func TestReadConfigFiles(t *testing.T) {
 
 	configs, _, err = ReadConfigFiles(WithoutAdvancedAD)
 	require.Nil(t, err)
-	require.Equal(t, 18, len(configs))
+	require.Equal(t, 19, len(configs))
 
 	configs, _, err = ReadConfigFiles(WithAdvancedADOnly)
 	require.Nil(t, err)
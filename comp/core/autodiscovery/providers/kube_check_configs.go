@@ -0,0 +1,371 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/providers/names"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/telemetry"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/util/flavor"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/leaderelection"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// datadogCheckConfigConditionValid is the condition reporting whether
+	// the check configuration of a DatadogCheckConfig could be loaded
+	datadogCheckConfigConditionValid = "Valid"
+
+	datadogCheckConfigReasonValid   = "ConfigValid"
+	datadogCheckConfigReasonInvalid = "ConfigInvalid"
+)
+
+var gvrDatadogCheckConfig = schema.GroupVersionResource{
+	Group:    "datadoghq.com",
+	Version:  "v1alpha1",
+	Resource: "datadogcheckconfigs",
+}
+
+// datadogCheckConfig is a DatadogCheckConfig custom resource, holding a check
+// configuration equivalent to a file of the conf.d directory.
+type datadogCheckConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   datadogCheckConfigSpec   `json:"spec"`
+	Status datadogCheckConfigStatus `json:"status,omitempty"`
+}
+
+type datadogCheckConfigSpec struct {
+	CheckName               string                   `json:"checkName"`
+	ADIdentifiers           []string                 `json:"adIdentifiers,omitempty"`
+	ADIdentifiersExpr       string                   `json:"adIdentifiersExpr,omitempty"`
+	ClusterCheck            bool                     `json:"clusterCheck,omitempty"`
+	InitConfig              map[string]interface{}   `json:"initConfig,omitempty"`
+	Instances               []map[string]interface{} `json:"instances,omitempty"`
+	Logs                    []map[string]interface{} `json:"logs,omitempty"`
+	IgnoreAutodiscoveryTags bool                     `json:"ignoreAutodiscoveryTags,omitempty"`
+	CheckTagCardinality     string                   `json:"checkTagCardinality,omitempty"`
+}
+
+type datadogCheckConfigStatus struct {
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// kubeCheckConfigProvider implements the ConfigProvider interface for the
+// DatadogCheckConfig custom resources, letting check configs be managed through
+// the Kubernetes API instead of conf.d files or pod annotations.
+//
+// It only runs in the Cluster Agent, which dispatches the configs as cluster
+// checks, so that the node agents don't need to watch the resources. The
+// configs of a resource can only target the services of its namespace. The Cluster Agent leader reports in the status of each resource
+// whether its config is valid.
+type kubeCheckConfigProvider struct {
+	sync.RWMutex
+	client         dynamic.Interface
+	lister         cache.GenericLister
+	synced         cache.InformerSynced
+	isLeader       func() bool
+	upToDate       bool
+	configErrors   map[string]ErrorMsgSet
+	telemetryStore *telemetry.Store
+}
+
+// NewKubeCheckConfigProvider returns a new ConfigProvider watching the
+// DatadogCheckConfig resources.  Connectivity is not checked at this stage to
+// allow for retries, Collect will do it.
+func NewKubeCheckConfigProvider(_ *pkgconfigsetup.ConfigurationProviders, telemetryStore *telemetry.Store) (ConfigProvider, error) {
+	// Using GetAPIClient() (no retry)
+	ac, err := apiserver.GetAPIClient()
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to apiserver: %s", err)
+	}
+
+	if flavor.GetFlavor() != flavor.ClusterAgent {
+		return nil, errors.New("the DatadogCheckConfig resources are only watched by the Cluster Agent")
+	}
+
+	// only the leader reports the status of the resources
+	isLeader := func() bool { return true }
+	if pkgconfigsetup.Datadog().GetBool("leader_election") {
+		le, err := leaderelection.GetLeaderEngine()
+		if err != nil {
+			return nil, fmt.Errorf("cannot get leader engine: %s", err)
+		}
+		isLeader = le.IsLeader
+	}
+
+	p, err := newKubeCheckConfigProvider(ac.DynamicCl, ac.DynamicInformerFactory, isLeader, telemetryStore)
+	if err != nil {
+		return nil, err
+	}
+
+	// config providers are never stopped, the informer runs for the
+	// lifetime of the agent
+	ac.DynamicInformerFactory.Start(make(chan struct{}))
+
+	return p, nil
+}
+
+func newKubeCheckConfigProvider(client dynamic.Interface, informerFactory dynamicinformer.DynamicSharedInformerFactory, isLeader func() bool, telemetryStore *telemetry.Store) (*kubeCheckConfigProvider, error) {
+	informer := informerFactory.ForResource(gvrDatadogCheckConfig)
+
+	p := &kubeCheckConfigProvider{
+		client:         client,
+		lister:         informer.Lister(),
+		synced:         informer.Informer().HasSynced,
+		isLeader:       isLeader,
+		configErrors:   make(map[string]ErrorMsgSet),
+		telemetryStore: telemetryStore,
+	}
+
+	if _, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    p.invalidate,
+		UpdateFunc: p.invalidateIfChanged,
+		DeleteFunc: p.invalidate,
+	}); err != nil {
+		return nil, fmt.Errorf("cannot add event handler to DatadogCheckConfig informer: %s", err)
+	}
+
+	return p, nil
+}
+
+// String returns a string representation of the kubeCheckConfigProvider
+func (p *kubeCheckConfigProvider) String() string {
+	return names.KubeCheckConfigs
+}
+
+// Collect retrieves the DatadogCheckConfig resources, builds Config objects and returns them
+func (p *kubeCheckConfigProvider) Collect(ctx context.Context) ([]integration.Config, error) {
+	if !p.synced() {
+		return nil, errors.New("the DatadogCheckConfig resources are not synced yet")
+	}
+
+	p.Lock()
+	p.upToDate = true
+	p.Unlock()
+
+	objs, err := p.lister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []integration.Config
+	configErrors := make(map[string]ErrorMsgSet)
+	for _, obj := range objs {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			log.Errorf("Expected an *unstructured.Unstructured type, got: %T", obj)
+			continue
+		}
+
+		cr := &datadogCheckConfig{}
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), cr)
+		if err == nil {
+			var crConfigs []integration.Config
+			crConfigs, err = buildCheckConfigs(cr)
+			configs = append(configs, crConfigs...)
+		}
+
+		key := u.GetNamespace() + "/" + u.GetName()
+		if err != nil {
+			log.Errorf("Cannot build the check config of DatadogCheckConfig %s: %s", key, err)
+			configErrors[key] = ErrorMsgSet{err.Error(): struct{}{}}
+		}
+
+		if p.isLeader() {
+			if err := p.reportStatus(ctx, u, cr, err); err != nil {
+				log.Warnf("Cannot update the status of DatadogCheckConfig %s: %s", key, err)
+			}
+		}
+	}
+
+	p.Lock()
+	p.configErrors = configErrors
+	p.Unlock()
+
+	if p.telemetryStore != nil {
+		p.telemetryStore.Errors.Set(float64(len(configErrors)), names.KubeCheckConfigs)
+	}
+
+	return configs, nil
+}
+
+// IsUpToDate allows to cache configs as long as no changes are detected in the apiserver
+func (p *kubeCheckConfigProvider) IsUpToDate(context.Context) (bool, error) {
+	p.RLock()
+	defer p.RUnlock()
+	return p.upToDate, nil
+}
+
+func (p *kubeCheckConfigProvider) invalidate(obj interface{}) {
+	if obj != nil {
+		log.Trace("Invalidating configs on new/deleted DatadogCheckConfig")
+		p.Lock()
+		p.upToDate = false
+		p.Unlock()
+	}
+}
+
+func (p *kubeCheckConfigProvider) invalidateIfChanged(old, obj interface{}) {
+	castedObj, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		log.Errorf("Expected an *unstructured.Unstructured type, got: %T", obj)
+		return
+	}
+	castedOld, ok := old.(*unstructured.Unstructured)
+	if !ok {
+		log.Errorf("Expected an *unstructured.Unstructured type, got: %T", old)
+		p.invalidate(obj)
+		return
+	}
+	// the generation only changes with the spec, ignoring our own status updates
+	if castedObj.GetGeneration() != castedOld.GetGeneration() {
+		p.invalidate(obj)
+	}
+}
+
+// GetConfigErrors returns a map of configuration errors for each DatadogCheckConfig
+func (p *kubeCheckConfigProvider) GetConfigErrors() map[string]ErrorMsgSet {
+	p.RLock()
+	defer p.RUnlock()
+
+	configErrors := make(map[string]ErrorMsgSet, len(p.configErrors))
+	for key, errset := range p.configErrors {
+		configErrors[key] = errset
+	}
+	return configErrors
+}
+
+// reportStatus updates the status of the resource with the outcome of the
+// build of its check config, if it changed.
+func (p *kubeCheckConfigProvider) reportStatus(ctx context.Context, u *unstructured.Unstructured, cr *datadogCheckConfig, buildErr error) error {
+	status := cr.Status
+	status.Conditions = append([]metav1.Condition(nil), cr.Status.Conditions...)
+	status.ObservedGeneration = u.GetGeneration()
+
+	condition := metav1.Condition{
+		Type:               datadogCheckConfigConditionValid,
+		Status:             metav1.ConditionTrue,
+		Reason:             datadogCheckConfigReasonValid,
+		Message:            "The check configuration is loaded by autodiscovery",
+		ObservedGeneration: u.GetGeneration(),
+	}
+	if buildErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = datadogCheckConfigReasonInvalid
+		condition.Message = buildErr.Error()
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	if current := meta.FindStatusCondition(cr.Status.Conditions, datadogCheckConfigConditionValid); current != nil &&
+		cr.Status.ObservedGeneration == status.ObservedGeneration &&
+		current.Status == condition.Status &&
+		current.Message == condition.Message &&
+		current.ObservedGeneration == condition.ObservedGeneration {
+		return nil
+	}
+
+	rawStatus, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&status)
+	if err != nil {
+		return err
+	}
+	updated := u.DeepCopy()
+	if err := unstructured.SetNestedMap(updated.Object, rawStatus, "status"); err != nil {
+		return err
+	}
+
+	_, err = p.client.Resource(gvrDatadogCheckConfig).Namespace(u.GetNamespace()).UpdateStatus(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+// buildCheckConfigs builds the check configs of a DatadogCheckConfig resource,
+// which are cluster checks scoped to the namespace of the resource
+func buildCheckConfigs(cr *datadogCheckConfig) ([]integration.Config, error) {
+	spec := cr.Spec
+	if spec.CheckName == "" {
+		return nil, errors.New("spec.checkName is required")
+	}
+	if len(spec.Instances) == 0 && len(spec.Logs) == 0 {
+		return nil, errors.New("at least one of spec.instances and spec.logs is required")
+	}
+	if !spec.ClusterCheck {
+		return nil, errors.New("spec.clusterCheck must be true, the configs are dispatched by the Cluster Agent")
+	}
+	if spec.ADIdentifiersExpr != "" {
+		return nil, errors.New("spec.adIdentifiersExpr is not supported, the services of the cluster checks don't expose the attributes of the expressions")
+	}
+	for _, adID := range spec.ADIdentifiers {
+		if !isNamespaceADIdentifier(adID, cr.Namespace) {
+			return nil, fmt.Errorf("spec.adIdentifiers can only reference the services of namespace %s, got %q", cr.Namespace, adID)
+		}
+	}
+
+	config := integration.Config{
+		Name:                    spec.CheckName,
+		InitConfig:              integration.Data("{}"),
+		ADIdentifiers:           spec.ADIdentifiers,
+		ClusterCheck:            spec.ClusterCheck,
+		IgnoreAutodiscoveryTags: spec.IgnoreAutodiscoveryTags,
+		CheckTagCardinality:     spec.CheckTagCardinality,
+		Source:                  fmt.Sprintf("%s:%s/%s", names.KubeCheckConfigsRegisterName, cr.Namespace, cr.Name),
+	}
+
+	if spec.InitConfig != nil {
+		initConfig, err := json.Marshal(spec.InitConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid spec.initConfig: %s", err)
+		}
+		config.InitConfig = initConfig
+	}
+
+	for i, instance := range spec.Instances {
+		data, err := json.Marshal(instance)
+		if err != nil {
+			return nil, fmt.Errorf("invalid spec.instances[%d]: %s", i, err)
+		}
+		config.Instances = append(config.Instances, data)
+	}
+
+	if len(spec.Logs) > 0 {
+		logsConfig, err := json.Marshal(spec.Logs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid spec.logs: %s", err)
+		}
+		config.LogsConfig = logsConfig
+	}
+
+	return []integration.Config{config}, nil
+}
+
+// isNamespaceADIdentifier returns whether the AD identifier references a
+// service of the namespace
+func isNamespaceADIdentifier(adID, namespace string) bool {
+	prefix := apiserver.EntityForServiceWithNames(namespace, "")
+	return strings.HasPrefix(adID, prefix) && len(adID) > len(prefix)
+}
@@ -0,0 +1,235 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+)
+
+func newFakeDatadogCheckConfig(t *testing.T, ns, name string, generation int64, spec datadogCheckConfigSpec) *unstructured.Unstructured {
+	cr := &datadogCheckConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "datadoghq.com/v1alpha1",
+			Kind:       "DatadogCheckConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  ns,
+			Name:       name,
+			Generation: generation,
+		},
+		Spec: spec,
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cr)
+	require.NoError(t, err)
+	return &unstructured.Unstructured{Object: content}
+}
+
+func TestBuildCheckConfigs(t *testing.T) {
+	tests := []struct {
+		name            string
+		spec            datadogCheckConfigSpec
+		expectedConfigs []integration.Config
+		expectedErr     string
+	}{
+		{
+			name: "service check",
+			spec: datadogCheckConfigSpec{
+				CheckName:     "redisdb",
+				ADIdentifiers: []string{"kube_service://cache/redis"},
+				ClusterCheck:  true,
+				InitConfig:    map[string]interface{}{"service": "cache"},
+				Instances: []map[string]interface{}{
+					{"host": "%%host%%", "port": int64(6379)},
+				},
+			},
+			expectedConfigs: []integration.Config{
+				{
+					Name:          "redisdb",
+					ADIdentifiers: []string{"kube_service://cache/redis"},
+					ClusterCheck:  true,
+					InitConfig:    integration.Data(`{"service":"cache"}`),
+					Instances:     []integration.Data{integration.Data(`{"host":"%%host%%","port":6379}`)},
+					Source:        "kube_check_configs:cache/redis",
+				},
+			},
+		},
+		{
+			name: "cluster check",
+			spec: datadogCheckConfigSpec{
+				CheckName:    "http_check",
+				ClusterCheck: true,
+				Instances: []map[string]interface{}{
+					{"url": "https://example.com"},
+				},
+			},
+			expectedConfigs: []integration.Config{
+				{
+					Name:         "http_check",
+					ClusterCheck: true,
+					InitConfig:   integration.Data("{}"),
+					Instances:    []integration.Data{integration.Data(`{"url":"https://example.com"}`)},
+					Source:       "kube_check_configs:cache/redis",
+				},
+			},
+		},
+		{
+			name: "missing check name",
+			spec: datadogCheckConfigSpec{
+				Instances: []map[string]interface{}{{}},
+			},
+			expectedErr: "spec.checkName is required",
+		},
+		{
+			name: "no instances nor logs",
+			spec: datadogCheckConfigSpec{
+				CheckName: "redisdb",
+			},
+			expectedErr: "at least one of spec.instances and spec.logs is required",
+		},
+		{
+			name: "node check",
+			spec: datadogCheckConfigSpec{
+				CheckName:     "redisdb",
+				ADIdentifiers: []string{"redis"},
+				Instances:     []map[string]interface{}{{}},
+			},
+			expectedErr: "spec.clusterCheck must be true",
+		},
+		{
+			name: "expression",
+			spec: datadogCheckConfigSpec{
+				CheckName:         "redisdb",
+				ADIdentifiersExpr: `container.image.short_name == "redis"`,
+				ClusterCheck:      true,
+				Instances:         []map[string]interface{}{{}},
+			},
+			expectedErr: "spec.adIdentifiersExpr is not supported",
+		},
+		{
+			name: "service of another namespace",
+			spec: datadogCheckConfigSpec{
+				CheckName:     "redisdb",
+				ADIdentifiers: []string{"kube_service://kube-system/redis"},
+				ClusterCheck:  true,
+				Instances:     []map[string]interface{}{{}},
+			},
+			expectedErr: `spec.adIdentifiers can only reference the services of namespace cache, got "kube_service://kube-system/redis"`,
+		},
+		{
+			name: "container identifier",
+			spec: datadogCheckConfigSpec{
+				CheckName:     "redisdb",
+				ADIdentifiers: []string{"redis"},
+				ClusterCheck:  true,
+				Instances:     []map[string]interface{}{{}},
+			},
+			expectedErr: "spec.adIdentifiers can only reference the services of namespace cache",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &datadogCheckConfig{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "cache", Name: "redis"},
+				Spec:       tt.spec,
+			}
+			configs, err := buildCheckConfigs(cr)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedConfigs, configs)
+		})
+	}
+}
+
+func TestKubeCheckConfigProviderCollect(t *testing.T) {
+	valid := newFakeDatadogCheckConfig(t, "cache", "redis", 1, datadogCheckConfigSpec{
+		CheckName:     "redisdb",
+		ADIdentifiers: []string{"kube_service://cache/redis"},
+		ClusterCheck:  true,
+		Instances:     []map[string]interface{}{{"host": "%%host%%"}},
+	})
+	invalid := newFakeDatadogCheckConfig(t, "web", "nginx", 2, datadogCheckConfigSpec{
+		CheckName:    "nginx",
+		ClusterCheck: true,
+	})
+
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvrDatadogCheckConfig: "DatadogCheckConfigList"},
+		valid, invalid,
+	)
+	informerFactory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+
+	for _, leader := range []bool{false, true} {
+		client.ClearActions()
+
+		p, err := newKubeCheckConfigProvider(client, informerFactory, func() bool { return leader }, nil)
+		require.NoError(t, err)
+		p.synced = func() bool { return true }
+		indexer := informerFactory.ForResource(gvrDatadogCheckConfig).Informer().GetIndexer()
+		require.NoError(t, indexer.Add(valid))
+		require.NoError(t, indexer.Add(invalid))
+
+		upToDate, err := p.IsUpToDate(context.TODO())
+		require.NoError(t, err)
+		assert.False(t, upToDate)
+
+		configs, err := p.Collect(context.TODO())
+		require.NoError(t, err)
+		require.Len(t, configs, 1)
+		assert.Equal(t, "redisdb", configs[0].Name)
+		assert.Equal(t, "kube_check_configs:cache/redis", configs[0].Source)
+
+		upToDate, err = p.IsUpToDate(context.TODO())
+		require.NoError(t, err)
+		assert.True(t, upToDate)
+
+		configErrors := p.GetConfigErrors()
+		require.Len(t, configErrors, 1)
+		assert.Contains(t, configErrors, "web/nginx")
+
+		var statusUpdates int
+		for _, action := range client.Actions() {
+			if action.GetVerb() == "update" && action.GetSubresource() == "status" {
+				statusUpdates++
+			}
+		}
+		if !leader {
+			assert.Zero(t, statusUpdates)
+			continue
+		}
+		assert.Equal(t, 2, statusUpdates)
+
+		obj, err := client.Resource(gvrDatadogCheckConfig).Namespace("web").Get(context.TODO(), "nginx", metav1.GetOptions{})
+		require.NoError(t, err)
+		cr := &datadogCheckConfig{}
+		require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), cr))
+		assert.Equal(t, int64(2), cr.Status.ObservedGeneration)
+		condition := meta.FindStatusCondition(cr.Status.Conditions, datadogCheckConfigConditionValid)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, datadogCheckConfigReasonInvalid, condition.Reason)
+		assert.Equal(t, "at least one of spec.instances and spec.logs is required", condition.Message)
+	}
+}
@@ -67,6 +67,10 @@ func NewKubeEndpointsConfigProvider(_ *pkgconfigsetup.ConfigurationProviders, te
 		return nil, fmt.Errorf("cannot connect to apiserver: %s", err)
 	}
 
+	if err := apiserver.CheckInformersEnabled(pkgconfigsetup.Datadog(), "services", "endpoints"); err != nil {
+		return nil, err
+	}
+
 	servicesInformer := ac.InformerFactory.Core().V1().Services()
 	if servicesInformer == nil {
 		return nil, fmt.Errorf("cannot get service informer: %s", err)
@@ -75,6 +75,10 @@ func NewKubeEndpointsFileConfigProvider(*pkgconfigsetup.ConfigurationProviders,
 		return nil, fmt.Errorf("cannot connect to apiserver: %s", err)
 	}
 
+	if err := apiserver.CheckInformersEnabled(pkgconfigsetup.Datadog(), "endpoints"); err != nil {
+		return nil, err
+	}
+
 	epInformer := ac.InformerFactory.Core().V1().Endpoints()
 	if epInformer == nil {
 		return nil, fmt.Errorf("cannot get endpoint informer: %s", err)
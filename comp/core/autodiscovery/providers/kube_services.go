@@ -49,6 +49,10 @@ func NewKubeServiceConfigProvider(_ *pkgconfigsetup.ConfigurationProviders, tele
 		return nil, fmt.Errorf("cannot connect to apiserver: %s", err)
 	}
 
+	if err := apiserver.CheckInformersEnabled(pkgconfigsetup.Datadog(), "services"); err != nil {
+		return nil, err
+	}
+
 	servicesInformer := ac.InformerFactory.Core().V1().Services()
 	if servicesInformer == nil {
 		return nil, fmt.Errorf("cannot get service informer: %s", err)
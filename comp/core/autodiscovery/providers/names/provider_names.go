@@ -22,6 +22,7 @@ const (
 	KubeServicesFile   = "kubernetes-services-file"
 	KubeEndpoints      = "kubernetes-endpoints"
 	KubeEndpointsFile  = "kubernetes-endpoints-file"
+	KubeCheckConfigs   = "kubernetes-check-configs"
 	PrometheusPods     = "prometheus-pods"
 	PrometheusServices = "prometheus-services"
 	RemoteConfig       = "remote-config"
@@ -45,6 +46,7 @@ const (
 	KubeServicesFileRegisterName   = "kube_services_file"
 	KubeEndpointsRegisterName      = "kube_endpoints"
 	KubeEndpointsFileRegisterName  = "kube_endpoints_file"
+	KubeCheckConfigsRegisterName   = "kube_check_configs"
 	PrometheusPodsRegisterName     = "prometheus_pods"
 	PrometheusServicesRegisterName = "prometheus_services"
 	RemoteConfigRegisterName       = "remote_config"
@@ -73,6 +73,10 @@ func NewPrometheusServicesConfigProvider(*pkgconfigsetup.ConfigurationProviders,
 		return nil, fmt.Errorf("cannot connect to apiserver: %s", err)
 	}
 
+	if err := apiserver.CheckInformersEnabled(pkgconfigsetup.Datadog(), "services"); err != nil {
+		return nil, err
+	}
+
 	servicesInformer := ac.InformerFactory.Core().V1().Services()
 	if servicesInformer == nil {
 		return nil, errors.New("cannot get services informer")
@@ -83,6 +87,9 @@ func NewPrometheusServicesConfigProvider(*pkgconfigsetup.ConfigurationProviders,
 
 	collectEndpoints := pkgconfigsetup.Datadog().GetBool("prometheus_scrape.service_endpoints")
 	if collectEndpoints {
+		if err := apiserver.CheckInformersEnabled(pkgconfigsetup.Datadog(), "endpoints"); err != nil {
+			return nil, err
+		}
 		endpointsInformer = ac.InformerFactory.Core().V1().Endpoints()
 		if endpointsInformer == nil {
 			return nil, errors.New("cannot get endpoints informer")
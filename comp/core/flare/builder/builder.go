@@ -155,4 +155,6 @@ type FlareArgs struct {
 	ProfileDuration      time.Duration // Add performance profiling data to the flare. It will collect a heap profile and a CPU profile for the amount of seconds passed to the flag, with a minimum of 30s
 	ProfileMutexFraction int           // Set the fraction of mutex contention events that are reported in the mutex profile
 	ProfileBlockingRate  int           // Set the fraction of goroutine blocking events that are reported in the blocking profile
+	Preview              bool          // List the content of the flare in a report instead of archiving it
+	Subsystem            string        // Only collect the files relevant to the given subsystem (apm, logs or security), the whole Agent when empty
 }
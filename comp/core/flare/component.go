@@ -27,6 +27,11 @@ type Component interface {
 	//
 	// If providerTimeout is 0 or negative, the timeout from the configuration will be used.
 	Create(pdata types.ProfileData, providerTimeout time.Duration, ipcError error) (string, error)
+	// CreateWithArgs creates a new flare locally with the given arguments and returns the path to the flare file, or to
+	// the preview report when flareArgs.Preview is set.
+	//
+	// If providerTimeout is 0 or negative, the timeout from the configuration will be used.
+	CreateWithArgs(flareArgs types.FlareArgs, providerTimeout time.Duration, ipcError error, pdata types.ProfileData) (string, error)
 	// Send sends a flare archive to Datadog.
 	Send(flarePath string, caseID string, email string, source helpers.FlareSource) (string, error)
 }
@@ -39,9 +39,9 @@ import (
 )
 
 // FlareBuilderFactory creates an instance of FlareBuilder
-type flareBuilderFactory func(localFlare bool, flareArgs types.FlareArgs) (types.FlareBuilder, error)
+type flareBuilderFactory func(localFlare bool, flareArgs types.FlareArgs, redaction helpers.RedactionConfig) (types.FlareBuilder, error)
 
-var fbFactory flareBuilderFactory = helpers.NewFlareBuilder
+var fbFactory flareBuilderFactory = helpers.NewFlareBuilderWithRedaction
 
 type dependencies struct {
 	fx.In
@@ -141,7 +141,7 @@ func (f *flare) onAgentTaskEvent(taskType rcclienttypes.TaskType, task rcclientt
 		f.log.Infof("Unrecognized value passed via enable_streamlogs, creating flare without streamlogs enabled: %q", streamlogs)
 	}
 
-	filePath, err := f.CreateWithArgs(flareArgs, 0, nil)
+	filePath, err := f.CreateWithArgs(flareArgs, 0, nil, nil)
 	if err != nil {
 		return true, err
 	}
@@ -180,13 +180,23 @@ func (f *flare) createAndReturnFlarePath(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	flareArgs := types.FlareArgs{}
+	if preview := r.URL.Query().Get("preview"); preview != "" {
+		flareArgs.Preview, _ = strconv.ParseBool(preview)
+	}
+	flareArgs.Subsystem = r.URL.Query().Get("subsystem")
+	if err := helpers.ValidateFlareSubsystem(flareArgs.Subsystem); err != nil {
+		http.Error(w, f.log.Errorf("Invalid subsystem query parameter: %s", err).Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Reset the `server_timeout` deadline for this connection as creating a flare can take some time
 	conn := apiutils.GetConnection(r)
 	_ = conn.SetDeadline(time.Time{})
 
 	var filePath string
 	f.log.Infof("Making a flare")
-	filePath, err := f.Create(profile, providerTimeout, nil)
+	filePath, err := f.create(flareArgs, providerTimeout, nil, profile)
 
 	if err != nil || filePath == "" {
 		if err != nil {
@@ -213,11 +223,12 @@ func (f *flare) Create(pdata types.ProfileData, providerTimeout time.Duration, i
 	return f.create(types.FlareArgs{}, providerTimeout, ipcError, pdata)
 }
 
-// Create creates a new flare and returns the path to the final archive file.
+// CreateWithArgs creates a new flare with the given arguments and returns the path to the final archive file, or to
+// the preview report when flareArgs.Preview is set.
 //
 // If providerTimeout is 0 or negative, the timeout from the configuration will be used.
-func (f *flare) CreateWithArgs(flareArgs types.FlareArgs, providerTimeout time.Duration, ipcError error) (string, error) {
-	return f.create(flareArgs, providerTimeout, ipcError, types.ProfileData{})
+func (f *flare) CreateWithArgs(flareArgs types.FlareArgs, providerTimeout time.Duration, ipcError error, pdata types.ProfileData) (string, error) {
+	return f.create(flareArgs, providerTimeout, ipcError, pdata)
 }
 
 func (f *flare) create(flareArgs types.FlareArgs, providerTimeout time.Duration, ipcError error, pdata types.ProfileData) (string, error) {
@@ -225,7 +236,12 @@ func (f *flare) create(flareArgs types.FlareArgs, providerTimeout time.Duration,
 		providerTimeout = f.config.GetDuration("flare_provider_timeout")
 	}
 
-	fb, err := fbFactory(f.params.local, flareArgs)
+	redaction, err := helpers.NewRedactionConfig(f.config)
+	if err != nil {
+		return "", err
+	}
+
+	fb, err := fbFactory(f.params.local, flareArgs, redaction)
 	if err != nil {
 		return "", err
 	}
@@ -76,12 +76,12 @@ func getFlare(t *testing.T, overrides map[string]interface{}, fillers ...fx.Opti
 
 // CreateFlareBuilderMockFactory generates a FlareBuilderFactory that will output mocked builders when called.
 func setupMockBuilder(t *testing.T) func() {
-	fbFactory = func(localFlare bool, flareArgs types.FlareArgs) (types.FlareBuilder, error) {
+	fbFactory = func(localFlare bool, flareArgs types.FlareArgs, _ helpers.RedactionConfig) (types.FlareBuilder, error) {
 		return helpers.NewFlareBuilderMockWithArgs(t, localFlare, flareArgs), nil
 	}
 
 	return func() {
-		fbFactory = helpers.NewFlareBuilder
+		fbFactory = helpers.NewFlareBuilderWithRedaction
 	}
 }
 func TestFlareCreation(t *testing.T) {
@@ -30,12 +30,22 @@ const (
 	filePerm = 0644
 )
 
-func newBuilder(root string, hostname string, localFlare bool, flareArgs types.FlareArgs) (*builder, error) {
+func newBuilder(root string, hostname string, localFlare bool, flareArgs types.FlareArgs, redaction RedactionConfig) (*builder, error) {
+	if err := redaction.compile(); err != nil {
+		return nil, err
+	}
+	if err := ValidateFlareSubsystem(flareArgs.Subsystem); err != nil {
+		return nil, err
+	}
+
 	fb := &builder{
-		tmpDir:     root,
-		permsInfos: permissionsInfos{},
-		isLocal:    localFlare,
-		flareArgs:  flareArgs,
+		tmpDir:       root,
+		permsInfos:   permissionsInfos{},
+		isLocal:      localFlare,
+		flareArgs:    flareArgs,
+		redaction:    redaction,
+		dirSizes:     map[string]int64{},
+		previewFiles: map[string]previewFileInfo{},
 	}
 
 	fb.flareDir = filepath.Join(fb.tmpDir, hostname)
@@ -82,6 +92,12 @@ func newBuilder(root string, hostname string, localFlare bool, flareArgs types.F
 // pushed to the flare as well as cleanup the temporary directories created. Not calling 'Save' after NewFlareBuilder
 // will leave temporary directory on the file system.
 func NewFlareBuilder(localFlare bool, flareArgs types.FlareArgs) (types.FlareBuilder, error) {
+	return NewFlareBuilderWithRedaction(localFlare, flareArgs, RedactionConfig{})
+}
+
+// NewFlareBuilderWithRedaction returns a new FlareBuilder applying the user-defined redaction to the flare content on
+// top of the default scrubbing. Like for NewFlareBuilder, the Save method must be called once the flare is complete.
+func NewFlareBuilderWithRedaction(localFlare bool, flareArgs types.FlareArgs, redaction RedactionConfig) (types.FlareBuilder, error) {
 	tmpDir, err := os.MkdirTemp("", "")
 	if err != nil {
 		return nil, fmt.Errorf("Could not create temp dir for flare: %s", err)
@@ -104,7 +120,7 @@ func NewFlareBuilder(localFlare bool, flareArgs types.FlareArgs) (types.FlareBui
 		return nil, err
 	}
 
-	return newBuilder(tmpDir, hostname, localFlare, flareArgs)
+	return newBuilder(tmpDir, hostname, localFlare, flareArgs, redaction)
 }
 
 // builder implements the FlareBuilder interface
@@ -125,10 +141,24 @@ type builder struct {
 
 	// specialized scrubber for flare content
 	scrubber *scrubber.Scrubber
+	// redaction is the user-defined redaction applied on top of the scrubber
+	redaction RedactionConfig
+	// dirSizes is the size of the content added to each top-level directory of the flare
+	dirSizes map[string]int64
+	// skipped are the files left out of the flare by the redaction settings
+	skipped []PreviewSkippedFile
+	// previewFiles holds how the files added to the flare were redacted, it's reported in preview mode
+	previewFiles map[string]previewFileInfo
 
 	logFile *os.File
 }
 
+// previewFileInfo describes how a file of the flare was redacted
+type previewFileInfo struct {
+	scrubbed   bool
+	redactions map[string]int
+}
+
 func getArchiveName() string {
 	t := time.Now().UTC()
 	timeString := strings.ReplaceAll(t.Format(time.RFC3339), ":", "-")
@@ -157,6 +187,14 @@ func (fb *builder) Save() (string, error) {
 	defer fb.Unlock()
 	fb.isClosed = true
 
+	// Flare providers can write files directly through PrepareFilePath, the exclusions and the subsystem are enforced
+	// on them here
+	fb.removeExcludedFiles()
+
+	if fb.flareArgs.Preview {
+		return fb.savePreview()
+	}
+
 	archiveName := getArchiveName()
 	archiveTmpPath := filepath.Join(fb.tmpDir, archiveName)
 	archiveFinalPath := filepath.Join(os.TempDir(), archiveName)
@@ -206,6 +244,12 @@ func (fb *builder) Logf(format string, params ...interface{}) error {
 }
 
 func (fb *builder) AddFileFromFunc(destFile string, cb func() ([]byte, error)) error {
+	// The content isn't collected at all for the files left out of the flare
+	if reason := fb.skipReason(destFile); reason != "" {
+		fb.skip(destFile, reason)
+		return nil
+	}
+
 	content, err := cb()
 	if err != nil {
 		return fb.logError("error collecting data for '%s': %s", destFile, err)
@@ -219,6 +263,12 @@ func (fb *builder) addFile(shouldScrub bool, destFile string, content []byte) er
 		return nil
 	}
 
+	if reason := fb.skipReason(destFile); reason != "" {
+		fb.skip(destFile, reason)
+		return nil
+	}
+
+	var redactions map[string]int
 	if shouldScrub {
 		var err error
 
@@ -232,6 +282,7 @@ func (fb *builder) addFile(shouldScrub bool, destFile string, content []byte) er
 		if err != nil {
 			return fb.logError("error scrubbing content for '%s': %s", destFile, err)
 		}
+		content, redactions = fb.redaction.redact(content)
 	}
 
 	fb.Lock()
@@ -241,10 +292,15 @@ func (fb *builder) addFile(shouldScrub bool, destFile string, content []byte) er
 		return nil
 	}
 
+	if !fb.reserveDirSize(destFile, len(content)) {
+		return nil
+	}
+
 	f, err := fb.prepareFilePath(destFile)
 	if err != nil {
 		return err
 	}
+	fb.recordFile(destFile, shouldScrub, redactions)
 
 	if err := os.WriteFile(f, content, filePerm); err != nil {
 		return fb.logError("error writing data to '%s': %s", destFile, err)
@@ -275,11 +331,17 @@ func (fb *builder) copyFileTo(shouldScrub bool, srcFile string, destFile string)
 		return nil
 	}
 
+	if reason := fb.skipReason(destFile); reason != "" {
+		fb.skip(destFile, reason)
+		return nil
+	}
+
 	content, err := os.ReadFile(srcFile)
 	if err != nil {
 		return fb.logError("error reading file '%s' to be copy to '%s': %s", srcFile, destFile, err)
 	}
 
+	var redactions map[string]int
 	if shouldScrub {
 		var err error
 
@@ -292,6 +354,7 @@ func (fb *builder) copyFileTo(shouldScrub bool, srcFile string, destFile string)
 		if err != nil {
 			return fb.logError("error scrubbing content for file '%s': %s", destFile, err)
 		}
+		content, redactions = fb.redaction.redact(content)
 	}
 
 	fb.Lock()
@@ -301,12 +364,17 @@ func (fb *builder) copyFileTo(shouldScrub bool, srcFile string, destFile string)
 		return nil
 	}
 
+	if !fb.reserveDirSize(destFile, len(content)) {
+		return nil
+	}
+
 	fb.permsInfos.add(srcFile)
 
 	path, err := fb.prepareFilePath(destFile)
 	if err != nil {
 		return err
 	}
+	fb.recordFile(destFile, shouldScrub, redactions)
 
 	err = os.WriteFile(path, content, filePerm)
 	if err != nil {
@@ -390,6 +458,86 @@ func (fb *builder) prepareFilePath(path string) (string, error) {
 	return p, nil
 }
 
+// skip logs a file left out of the flare by the redaction settings
+func (fb *builder) skip(destFile string, reason string) {
+	fb.Lock()
+	defer fb.Unlock()
+	fb.skipLocked(destFile, reason)
+}
+
+func (fb *builder) skipLocked(destFile string, reason string) {
+	if fb.isClosed {
+		return
+	}
+
+	destFile = filepath.ToSlash(filepath.Clean(destFile))
+	fb.skipped = append(fb.skipped, PreviewSkippedFile{Path: destFile, Reason: reason})
+	_, _ = fb.logFile.WriteString(fmt.Sprintf("Skipping '%s': %s\n", destFile, reason))
+}
+
+// skipReason returns why the file at the given path is left out of the flare, or an empty string if it's included
+func (fb *builder) skipReason(destFile string) string {
+	if !isInSubsystem(fb.flareArgs.Subsystem, destFile) {
+		return fmt.Sprintf("not part of the '%s' subsystem flare", fb.flareArgs.Subsystem)
+	}
+	if fb.redaction.isExcluded(destFile) {
+		return "excluded by flare.redaction.exclude_files"
+	}
+	return ""
+}
+
+// reserveDirSize accounts the size of a file added to the flare and returns false if the file doesn't fit in the size
+// cap of its top-level directory. It must be called with the builder locked.
+func (fb *builder) reserveDirSize(destFile string, size int) bool {
+	dir := topLevelDir(destFile)
+	if fb.redaction.MaxDirSize <= 0 || dir == "" {
+		return true
+	}
+
+	if fb.dirSizes[dir]+int64(size) > fb.redaction.MaxDirSize {
+		fb.skipLocked(destFile, fmt.Sprintf("the '%s' directory exceeds flare.redaction.max_dir_size (%d bytes)", dir, fb.redaction.MaxDirSize))
+		return false
+	}
+	fb.dirSizes[dir] += int64(size)
+	return true
+}
+
+// recordFile records how a file added to the flare was redacted. It must be called with the builder locked.
+func (fb *builder) recordFile(destFile string, scrubbed bool, redactions map[string]int) {
+	fb.previewFiles[filepath.ToSlash(filepath.Clean(destFile))] = previewFileInfo{
+		scrubbed:   scrubbed,
+		redactions: redactions,
+	}
+}
+
+// removeExcludedFiles removes the files left out of the flare from the flare directory. It must be called with the
+// builder locked.
+func (fb *builder) removeExcludedFiles() {
+	if len(fb.redaction.ExcludeFiles) == 0 && fb.flareArgs.Subsystem == "" {
+		return
+	}
+
+	_ = filepath.Walk(fb.flareDir, func(src string, f os.FileInfo, _ error) error {
+		if f == nil || f.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(fb.flareDir, src)
+		if err != nil {
+			return nil
+		}
+		reason := fb.skipReason(rel)
+		if reason == "" {
+			return nil
+		}
+		if err := os.Remove(src); err == nil {
+			rel = filepath.ToSlash(rel)
+			fb.skipped = append(fb.skipped, PreviewSkippedFile{Path: rel, Reason: reason})
+		}
+		return nil
+	})
+}
+
 func (fb *builder) RegisterFilePerm(path string) {
 	fb.Lock()
 	defer fb.Unlock()
@@ -47,7 +47,7 @@ func NewFlareBuilderMockWithArgs(t *testing.T, local bool, args types.FlareArgs)
 func createMock(t *testing.T, local bool, args types.FlareArgs) *FlareBuilderMock {
 	root := t.TempDir()
 
-	builder, err := newBuilder(root, "test-hostname", local, args)
+	builder, err := newBuilder(root, "test-hostname", local, args, RedactionConfig{})
 	require.NoError(t, err)
 
 	fb := &FlareBuilderMock{
@@ -0,0 +1,140 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/filesystem"
+)
+
+// PreviewReport lists the content of a flare generated in preview mode
+type PreviewReport struct {
+	// Files are the files which would be included in the flare
+	Files []PreviewFile `json:"files"`
+	// Skipped are the files left out of the flare by the exclusions or the directory size caps
+	Skipped []PreviewSkippedFile `json:"skipped"`
+	// TotalSize is the size in bytes of the files which would be included in the flare, before compression
+	TotalSize int64 `json:"total_size"`
+}
+
+// PreviewFile is a file which would be included in the flare
+type PreviewFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	// Scrubbed is true when the default scrubbing was applied to the file
+	Scrubbed bool `json:"scrubbed"`
+	// Redactions is the number of matches of each custom redaction rule in the file
+	Redactions map[string]int `json:"redactions,omitempty"`
+}
+
+// PreviewSkippedFile is a file left out of the flare
+type PreviewSkippedFile struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// ReadPreviewReport reads the preview report written by a flare generated in preview mode
+func ReadPreviewReport(reportPath string) (PreviewReport, error) {
+	var report PreviewReport
+
+	content, err := os.ReadFile(reportPath)
+	if err != nil {
+		return report, err
+	}
+	if err := json.Unmarshal(content, &report); err != nil {
+		return report, fmt.Errorf("invalid flare preview report '%s': %w", reportPath, err)
+	}
+	return report, nil
+}
+
+func getPreviewReportName() string {
+	t := time.Now().UTC()
+	timeString := strings.ReplaceAll(t.Format(time.RFC3339), ":", "-")
+	return fmt.Sprintf("datadog-agent-flare-preview-%s.json", timeString)
+}
+
+// previewReport lists the files written to the flare directory. It must be called with the builder locked.
+func (fb *builder) previewReport() (PreviewReport, error) {
+	report := PreviewReport{
+		Files:   []PreviewFile{},
+		Skipped: fb.skipped,
+	}
+	if report.Skipped == nil {
+		report.Skipped = []PreviewSkippedFile{}
+	}
+
+	err := filepath.Walk(fb.flareDir, func(src string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if f.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(fb.flareDir, src)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		info := fb.previewFiles[rel]
+		report.Files = append(report.Files, PreviewFile{
+			Path:       rel,
+			Size:       f.Size(),
+			Scrubbed:   info.scrubbed,
+			Redactions: info.redactions,
+		})
+		report.TotalSize += f.Size()
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	sort.Slice(report.Files, func(i, j int) bool { return report.Files[i].Path < report.Files[j].Path })
+	return report, nil
+}
+
+// savePreview writes the preview report to the system temporary directory instead of archiving the flare. It must be
+// called with the builder locked.
+func (fb *builder) savePreview() (string, error) {
+	report, err := fb.previewReport()
+	if err != nil {
+		return "", fmt.Errorf("could not list the flare content: %w", err)
+	}
+
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	reportName := getPreviewReportName()
+	reportTmpPath := filepath.Join(fb.tmpDir, reportName)
+	reportFinalPath := filepath.Join(os.TempDir(), reportName)
+
+	// Like the archive, the report is restricted to the current user before being moved to the system temporary
+	// directory since it lists the content of the flare.
+	if err := os.WriteFile(reportTmpPath, content, filePerm); err != nil {
+		return "", err
+	}
+
+	fperm, err := filesystem.NewPermission()
+	if err != nil {
+		return "", err
+	}
+	if err := fperm.RemoveAccessToOtherUsers(reportTmpPath); err != nil {
+		return "", err
+	}
+
+	return reportFinalPath, os.Rename(reportTmpPath, reportFinalPath)
+}
@@ -0,0 +1,144 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package helpers
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	pkgconfigmodel "github.com/DataDog/datadog-agent/pkg/config/model"
+	"github.com/DataDog/datadog-agent/pkg/config/structure"
+)
+
+const defaultRedactionReplacement = "********"
+
+// RedactionRule is a user-defined pattern redacted from the scrubbed flare files
+type RedactionRule struct {
+	// Name identifies the rule in the flare preview, it defaults to the pattern
+	Name string `mapstructure:"name" json:"name" yaml:"name"`
+	// Pattern is the regular expression matching the content to redact
+	Pattern string `mapstructure:"pattern" json:"pattern" yaml:"pattern"`
+	// Replacement replaces every match of the pattern, it defaults to "********"
+	Replacement string `mapstructure:"replacement" json:"replacement" yaml:"replacement"`
+
+	regex *regexp.Regexp
+}
+
+// RedactionConfig holds the user-defined redaction applied while building a flare
+type RedactionConfig struct {
+	// Rules are the custom patterns redacted from the scrubbed files, on top of the default scrubbing
+	Rules []RedactionRule
+	// ExcludeFiles are glob patterns matched against the path of the files in the flare (or their base name),
+	// the matching files are left out of the flare
+	ExcludeFiles []string
+	// MaxDirSize is the maximum size in bytes of each top-level directory of the flare, the files exceeding it are
+	// left out of the flare. 0 means no limit.
+	MaxDirSize int64
+}
+
+// NewRedactionConfig returns the RedactionConfig from the 'flare.redaction' settings of the configuration
+func NewRedactionConfig(cfg pkgconfigmodel.Reader) (RedactionConfig, error) {
+	redaction := RedactionConfig{
+		ExcludeFiles: cfg.GetStringSlice("flare.redaction.exclude_files"),
+		MaxDirSize:   cfg.GetInt64("flare.redaction.max_dir_size"),
+	}
+
+	if cfg.IsSet("flare.redaction.custom_patterns") {
+		if err := structure.UnmarshalKey(cfg, "flare.redaction.custom_patterns", &redaction.Rules); err != nil {
+			return RedactionConfig{}, fmt.Errorf("invalid flare.redaction.custom_patterns: %w", err)
+		}
+	}
+
+	if err := redaction.compile(); err != nil {
+		return RedactionConfig{}, err
+	}
+	return redaction, nil
+}
+
+// compile validates the redaction settings and compiles the patterns of the rules
+func (r *RedactionConfig) compile() error {
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		if rule.Pattern == "" {
+			return fmt.Errorf("flare redaction rule #%d has no pattern", i)
+		}
+
+		regex, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern for the flare redaction rule %q: %w", rule.Pattern, err)
+		}
+		rule.regex = regex
+
+		if rule.Name == "" {
+			rule.Name = rule.Pattern
+		}
+		if rule.Replacement == "" {
+			rule.Replacement = defaultRedactionReplacement
+		}
+	}
+
+	for _, pattern := range r.ExcludeFiles {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid flare file exclusion %q: %w", pattern, err)
+		}
+	}
+
+	if r.MaxDirSize < 0 {
+		return fmt.Errorf("flare.redaction.max_dir_size must be positive, got %d", r.MaxDirSize)
+	}
+	return nil
+}
+
+// redact applies the custom rules to the content and returns the number of redactions of each rule
+func (r *RedactionConfig) redact(content []byte) ([]byte, map[string]int) {
+	var redactions map[string]int
+	for _, rule := range r.Rules {
+		if rule.regex == nil {
+			continue
+		}
+
+		matches := len(rule.regex.FindAllIndex(content, -1))
+		if matches == 0 {
+			continue
+		}
+		content = rule.regex.ReplaceAll(content, []byte(rule.Replacement))
+
+		if redactions == nil {
+			redactions = make(map[string]int)
+		}
+		redactions[rule.Name] += matches
+	}
+	return content, redactions
+}
+
+// isExcluded returns true if the file at the given path in the flare is excluded
+func (r *RedactionConfig) isExcluded(destFile string) bool {
+	destFile = filepath.ToSlash(filepath.Clean(destFile))
+	base := path.Base(destFile)
+	for _, pattern := range r.ExcludeFiles {
+		if match, _ := path.Match(pattern, destFile); match {
+			return true
+		}
+		if match, _ := path.Match(pattern, base); match {
+			return true
+		}
+	}
+	return false
+}
+
+// topLevelDir returns the top-level directory of the file at the given path in the flare, or an empty string for the
+// files at the root of the flare
+func topLevelDir(destFile string) string {
+	destFile = filepath.ToSlash(filepath.Clean(destFile))
+	dir, _, found := strings.Cut(destFile, "/")
+	if !found {
+		return ""
+	}
+	return dir
+}
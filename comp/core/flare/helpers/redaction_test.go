@@ -0,0 +1,193 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flarebuilder "github.com/DataDog/datadog-agent/comp/core/flare/builder"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+)
+
+func getNewBuilderWithRedaction(t *testing.T, flareArgs flarebuilder.FlareArgs, redaction RedactionConfig) *builder {
+	f, err := NewFlareBuilderWithRedaction(false, flareArgs, redaction)
+	require.NoError(t, err)
+
+	fb, success := f.(*builder)
+	require.True(t, success, "FlareBuilder returned by FlareBuilder is not a *builder type")
+	return fb
+}
+
+func TestNewRedactionConfig(t *testing.T) {
+	cfg := configmock.NewFromYAML(t, `
+flare:
+  redaction:
+    custom_patterns:
+      - name: customer_id
+        pattern: 'cust-[0-9]+'
+        replacement: 'cust-XXX'
+      - pattern: 'internal\.example\.com'
+    exclude_files:
+      - "*.pem"
+    max_dir_size: 1024
+`)
+
+	redaction, err := NewRedactionConfig(cfg)
+	require.NoError(t, err)
+	require.Len(t, redaction.Rules, 2)
+	assert.Equal(t, "customer_id", redaction.Rules[0].Name)
+	assert.Equal(t, "cust-XXX", redaction.Rules[0].Replacement)
+	assert.Equal(t, `internal\.example\.com`, redaction.Rules[1].Name)
+	assert.Equal(t, "********", redaction.Rules[1].Replacement)
+	assert.Equal(t, []string{"*.pem"}, redaction.ExcludeFiles)
+	assert.Equal(t, int64(1024), redaction.MaxDirSize)
+}
+
+func TestNewRedactionConfigDefault(t *testing.T) {
+	redaction, err := NewRedactionConfig(configmock.New(t))
+	require.NoError(t, err)
+	assert.Empty(t, redaction.Rules)
+	assert.Empty(t, redaction.ExcludeFiles)
+	assert.Zero(t, redaction.MaxDirSize)
+}
+
+func TestNewRedactionConfigInvalid(t *testing.T) {
+	for name, yaml := range map[string]string{
+		"invalid pattern":   "flare.redaction.custom_patterns: [{pattern: '[a-z'}]",
+		"missing pattern":   "flare.redaction.custom_patterns: [{name: empty}]",
+		"invalid exclusion": "flare.redaction.exclude_files: ['[a-z']",
+		"negative size":     "flare.redaction.max_dir_size: -1",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewRedactionConfig(configmock.NewFromYAML(t, yaml))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCustomRedaction(t *testing.T) {
+	redaction := RedactionConfig{
+		Rules: []RedactionRule{{Name: "customer_id", Pattern: `cust-[0-9]+`, Replacement: "cust-XXX"}},
+	}
+	fb := getNewBuilderWithRedaction(t, flarebuilder.FlareArgs{}, redaction)
+	defer fb.clean()
+
+	fb.AddFile("status.log", []byte("customer cust-1234 and cust-5678\napi_key: 123456789006789009"))
+	assertFileContent(t, fb, "customer cust-XXX and cust-XXX\napi_key: \"********\"", "status.log")
+
+	// the custom rules only apply to the scrubbed content
+	fb.AddFileWithoutScrubbing("raw.log", []byte("cust-1234"))
+	assertFileContent(t, fb, "cust-1234", "raw.log")
+
+	src := filepath.Join(t.TempDir(), "datadog.yaml")
+	require.NoError(t, os.WriteFile(src, []byte("tags:\n  - customer:cust-42\n"), os.ModePerm))
+	fb.CopyFileTo(src, "etc/datadog.yaml")
+	assertFileContent(t, fb, "tags:\n  - customer:cust-XXX", "etc/datadog.yaml")
+}
+
+func TestExcludeFiles(t *testing.T) {
+	redaction := RedactionConfig{ExcludeFiles: []string{"*.pem", "etc/secrets/*"}}
+	fb := getNewBuilderWithRedaction(t, flarebuilder.FlareArgs{}, redaction)
+	defer fb.clean()
+
+	fb.AddFile(FromSlash("etc/certs/agent.pem"), []byte("certificate"))
+	fb.AddFile(FromSlash("etc/secrets/token"), []byte("token"))
+	fb.AddFile(FromSlash("etc/datadog.yaml"), []byte("site: datadoghq.com"))
+
+	// files written directly by the providers are removed when saving the flare
+	path, err := fb.PrepareFilePath("server.pem")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("certificate"), os.ModePerm))
+
+	assert.NoFileExists(t, filepath.Join(fb.flareDir, "etc", "certs", "agent.pem"))
+	assert.NoFileExists(t, filepath.Join(fb.flareDir, "etc", "secrets", "token"))
+	assert.FileExists(t, filepath.Join(fb.flareDir, "etc", "datadog.yaml"))
+
+	fb.Lock()
+	fb.removeExcludedFiles()
+	fb.Unlock()
+	assert.NoFileExists(t, path)
+
+	assert.ElementsMatch(t, []PreviewSkippedFile{
+		{Path: "etc/certs/agent.pem", Reason: "excluded by flare.redaction.exclude_files"},
+		{Path: "etc/secrets/token", Reason: "excluded by flare.redaction.exclude_files"},
+		{Path: "server.pem", Reason: "excluded by flare.redaction.exclude_files"},
+	}, fb.skipped)
+}
+
+func TestMaxDirSize(t *testing.T) {
+	root := setupDirWithData(t)
+
+	fb := getNewBuilderWithRedaction(t, flarebuilder.FlareArgs{}, RedactionConfig{MaxDirSize: 20})
+	defer fb.clean()
+
+	// files are walked in lexical order: depth1/depth2/test4, depth1/test3, test1, test2
+	fb.CopyDirTo(root, "logs", func(string) bool { return true })
+	fb.AddFile("status.log", []byte("files at the root of the flare are not capped"))
+
+	assert.FileExists(t, filepath.Join(fb.flareDir, "logs", "depth1", "depth2", "test4"))
+	assert.FileExists(t, filepath.Join(fb.flareDir, "logs", "depth1", "test3"))
+	assert.NoFileExists(t, filepath.Join(fb.flareDir, "logs", "test1"))
+	assert.NoFileExists(t, filepath.Join(fb.flareDir, "logs", "test2"))
+	assert.FileExists(t, filepath.Join(fb.flareDir, "status.log"))
+
+	require.Len(t, fb.skipped, 2)
+	assert.Equal(t, "logs/test1", fb.skipped[0].Path)
+	assert.Equal(t, "the 'logs' directory exceeds flare.redaction.max_dir_size (20 bytes)", fb.skipped[0].Reason)
+
+	content, err := os.ReadFile(filepath.Join(fb.flareDir, "flare_creation.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Skipping 'logs/test1'")
+}
+
+func TestSavePreview(t *testing.T) {
+	redaction := RedactionConfig{
+		Rules:        []RedactionRule{{Name: "customer_id", Pattern: `cust-[0-9]+`}},
+		ExcludeFiles: []string{"*.pem"},
+	}
+	fb := getNewBuilderWithRedaction(t, flarebuilder.FlareArgs{Preview: true}, redaction)
+
+	fb.AddFile("status.log", []byte("cust-1 cust-2"))
+	fb.AddFileWithoutScrubbing(FromSlash("profiles/cpu.pprof"), []byte("profile"))
+	fb.AddFile("agent.pem", []byte("certificate"))
+
+	reportPath, err := fb.Save()
+	require.NoError(t, err)
+	defer os.Remove(reportPath)
+	assert.NoDirExists(t, fb.tmpDir)
+	assert.Contains(t, filepath.Base(reportPath), "datadog-agent-flare-preview-")
+
+	report, err := ReadPreviewReport(reportPath)
+	require.NoError(t, err)
+
+	files := map[string]PreviewFile{}
+	for _, file := range report.Files {
+		files[file.Path] = file
+	}
+	require.Contains(t, files, "status.log")
+	assert.True(t, files["status.log"].Scrubbed)
+	assert.Equal(t, map[string]int{"customer_id": 2}, files["status.log"].Redactions)
+	assert.Equal(t, int64(len("******** ********")), files["status.log"].Size)
+
+	require.Contains(t, files, "profiles/cpu.pprof")
+	assert.False(t, files["profiles/cpu.pprof"].Scrubbed)
+	assert.Contains(t, files, "flare_creation.log")
+	assert.Contains(t, files, "permissions.log")
+	assert.NotContains(t, files, "agent.pem")
+
+	assert.Equal(t, []PreviewSkippedFile{{Path: "agent.pem", Reason: "excluded by flare.redaction.exclude_files"}}, report.Skipped)
+
+	var total int64
+	for _, file := range report.Files {
+		total += file.Size
+	}
+	assert.Equal(t, total, report.TotalSize)
+}
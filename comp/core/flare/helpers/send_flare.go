@@ -241,7 +241,7 @@ func SendTo(cfg pkgconfigmodel.Reader, archivePath, caseID, email, apiKey, url s
 	apiKey = configUtils.SanitizeAPIKey(apiKey)
 	baseURL, _ := configUtils.AddAgentVersionToDomain(url, "flare")
 
-	transport := httputils.CreateHTTPTransport(cfg)
+	transport := httputils.NewTransport(cfg, httputils.FlareDestination)
 	client := &http.Client{
 		Transport: transport,
 		Timeout:   httpTimeout,
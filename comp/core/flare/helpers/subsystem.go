@@ -0,0 +1,106 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package helpers
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// commonSubsystemFiles are the files included in the flare of every subsystem. They describe the Agent itself and
+// how the flare was created.
+//
+// Patterns ending with a '/' match every file in the directory, the others are matched with path.Match against the
+// path of the file in the flare.
+var commonSubsystemFiles = []string{
+	"flare_creation.log",
+	"permissions.log",
+	"local",
+	"status.log",
+	"health.yaml",
+	"envvars.log",
+	"install_info",
+	"version-history.json",
+	"etc/datadog.yaml",
+	"runtime_config_dump.yaml",
+	"profiles/",
+}
+
+// subsystemFiles are the files relevant to each subsystem a flare can target, on top of commonSubsystemFiles
+var subsystemFiles = map[string][]string{
+	"apm": {
+		"logs/trace-agent.log*",
+		"expvar/trace-agent",
+	},
+	"logs": {
+		"logs/agent.log*",
+		"logs_file_permissions.log",
+		"registry.json",
+		"expvar/logs-agent",
+		"logs/streamlogs_info/",
+		"etc/confd/",
+	},
+	"security": {
+		"logs/security-agent.log*",
+		"logs/system-probe.log*",
+		"security-agent-status.log",
+		"etc/security-agent.yaml",
+		"etc/system-probe.yaml",
+		"system_probe_runtime_config_dump.yaml",
+		"expvar/security-agent",
+		"expvar/system-probe",
+		"system-probe/",
+		"compliance.d/",
+		"runtime-security.d/",
+	},
+}
+
+// FlareSubsystems returns the sorted list of the subsystems a flare can target
+func FlareSubsystems() []string {
+	subsystems := make([]string, 0, len(subsystemFiles))
+	for subsystem := range subsystemFiles {
+		subsystems = append(subsystems, subsystem)
+	}
+	sort.Strings(subsystems)
+	return subsystems
+}
+
+// ValidateFlareSubsystem returns an error if the flare can't target the given subsystem. An empty subsystem targets
+// the whole Agent.
+func ValidateFlareSubsystem(subsystem string) error {
+	if subsystem == "" {
+		return nil
+	}
+	if _, ok := subsystemFiles[subsystem]; !ok {
+		return fmt.Errorf("unknown flare subsystem %q, expected one of: %s", subsystem, strings.Join(FlareSubsystems(), ", "))
+	}
+	return nil
+}
+
+// isInSubsystem returns true if the file at the given path in the flare is relevant to the subsystem. Every file is
+// relevant when no subsystem is targeted.
+func isInSubsystem(subsystem string, destFile string) bool {
+	if subsystem == "" {
+		return true
+	}
+
+	destFile = filepath.ToSlash(filepath.Clean(destFile))
+	for _, patterns := range [][]string{commonSubsystemFiles, subsystemFiles[subsystem]} {
+		for _, pattern := range patterns {
+			if strings.HasSuffix(pattern, "/") {
+				if strings.HasPrefix(destFile, pattern) {
+					return true
+				}
+			} else if match, _ := path.Match(pattern, destFile); match {
+				return true
+			}
+		}
+	}
+	return false
+}
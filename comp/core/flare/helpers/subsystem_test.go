@@ -0,0 +1,91 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	flarebuilder "github.com/DataDog/datadog-agent/comp/core/flare/builder"
+)
+
+func TestValidateFlareSubsystem(t *testing.T) {
+	assert.Equal(t, []string{"apm", "logs", "security"}, FlareSubsystems())
+
+	assert.NoError(t, ValidateFlareSubsystem(""))
+	assert.NoError(t, ValidateFlareSubsystem("apm"))
+
+	err := ValidateFlareSubsystem("network")
+	require.Error(t, err)
+	assert.Equal(t, `unknown flare subsystem "network", expected one of: apm, logs, security`, err.Error())
+
+	_, err = NewFlareBuilder(false, flarebuilder.FlareArgs{Subsystem: "network"})
+	assert.Error(t, err)
+}
+
+func TestIsInSubsystem(t *testing.T) {
+	for _, tc := range []struct {
+		subsystem string
+		path      string
+		included  bool
+	}{
+		{"", "logs/agent.log", true},
+		{"apm", "status.log", true},
+		{"apm", "profiles/cpu.pprof", true},
+		{"apm", "logs/trace-agent.log", true},
+		{"apm", "logs/trace-agent.log.1", true},
+		{"apm", "expvar/trace-agent", true},
+		{"apm", "logs/agent.log", false},
+		{"apm", "etc/confd/disk.d/conf.yaml", false},
+		{"apm", "tagger-list.json", false},
+		{"logs", "logs/agent.log", true},
+		{"logs", "logs/streamlogs_info/streamlogs.log", true},
+		{"logs", "etc/confd/nginx.d/conf.yaml", true},
+		{"logs", "registry.json", true},
+		{"logs", "logs/trace-agent.log", false},
+		{"security", "runtime-security.d/default.policy", true},
+		{"security", "system-probe/dmesg.log", true},
+		{"security", "logs/security-agent.log", true},
+		{"security", "expvar/trace-agent", false},
+	} {
+		assert.Equal(t, tc.included, isInSubsystem(tc.subsystem, FromSlash(tc.path)), "%s in %q", tc.path, tc.subsystem)
+	}
+}
+
+func TestSubsystemFlare(t *testing.T) {
+	fb := getNewBuilderWithRedaction(t, flarebuilder.FlareArgs{Subsystem: "apm"}, RedactionConfig{})
+	defer fb.clean()
+
+	root := filepath.Join(t.TempDir(), "logs")
+	require.NoError(t, os.MkdirAll(root, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "agent.log"), []byte("core agent"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "trace-agent.log"), []byte("trace agent"), os.ModePerm))
+	fb.CopyDirToWithoutScrubbing(root, "logs", func(string) bool { return true })
+
+	fb.AddFile("status.log", []byte("status"))
+
+	// the content of the files outside of the subsystem isn't collected
+	called := false
+	fb.AddFileFromFunc("tagger-list.json", func() ([]byte, error) {
+		called = true
+		return []byte("{}"), nil
+	})
+	assert.False(t, called)
+
+	assertFileContent(t, fb, "trace agent", "logs/trace-agent.log")
+	assertFileContent(t, fb, "status", "status.log")
+	assert.NoFileExists(t, filepath.Join(fb.flareDir, "logs", "agent.log"))
+	assert.NoFileExists(t, filepath.Join(fb.flareDir, "tagger-list.json"))
+
+	assert.ElementsMatch(t, []PreviewSkippedFile{
+		{Path: "logs/agent.log", Reason: "not part of the 'apm' subsystem flare"},
+		{Path: "tagger-list.json", Reason: "not part of the 'apm' subsystem flare"},
+	}, fb.skipped)
+}
@@ -16,4 +16,6 @@ type Component interface {
 type Options struct {
 	Port           int
 	LogsGoroutines bool
+	// EnableRestarts allows the health supervisor to restart the unhealthy components which support it
+	EnableRestarts bool
 }
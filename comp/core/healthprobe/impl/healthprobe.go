@@ -17,6 +17,7 @@ import (
 
 	healthprobeComponent "github.com/DataDog/datadog-agent/comp/core/healthprobe/def"
 	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/comp/core/status"
 	compdef "github.com/DataDog/datadog-agent/comp/def"
 	"github.com/DataDog/datadog-agent/pkg/status/health"
 	"github.com/gorilla/mux"
@@ -33,7 +34,8 @@ type Requires struct {
 
 // Provides defines the output of the healthprobe component
 type Provides struct {
-	Comp healthprobeComponent.Component
+	Comp   healthprobeComponent.Component
+	Status status.InformationProvider
 }
 
 type healthprobe struct {
@@ -60,7 +62,11 @@ func (h *healthprobe) stop() error {
 
 // NewComponent creates a new healthprobe component
 func NewComponent(reqs Requires) (Provides, error) {
-	provides := Provides{}
+	health.SetRestartsEnabled(reqs.Options.EnableRestarts)
+
+	provides := Provides{
+		Status: status.NewInformationProvider(statusProvider{}),
+	}
 	healthPort := reqs.Options.Port
 	if healthPort <= 0 {
 		return provides, nil
@@ -0,0 +1,94 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package healthprobeimpl
+
+import (
+	"embed"
+	"io"
+
+	"github.com/DataDog/datadog-agent/comp/core/status"
+	"github.com/DataDog/datadog-agent/pkg/status/health"
+)
+
+//go:embed status_templates
+var templatesFS embed.FS
+
+// statusProvider renders the restarts of the components by the health supervisor
+type statusProvider struct{}
+
+func (p statusProvider) getStatusInfo() map[string]interface{} {
+	stats := make(map[string]interface{})
+
+	p.populateStatus(stats)
+
+	return stats
+}
+
+// Name returns the name
+func (p statusProvider) Name() string {
+	return "Health Supervisor"
+}
+
+// Section return the section
+func (p statusProvider) Section() string {
+	return "Health Supervisor"
+}
+
+// JSON populates the status map
+func (p statusProvider) JSON(_ bool, stats map[string]interface{}) error {
+	p.populateStatus(stats)
+
+	return nil
+}
+
+// Text renders the text output
+func (p statusProvider) Text(_ bool, buffer io.Writer) error {
+	return status.RenderText(templatesFS, "healthsupervisor.tmpl", buffer, p.getStatusInfo())
+}
+
+// HTML renders the html output
+func (p statusProvider) HTML(_ bool, buffer io.Writer) error {
+	return status.RenderHTML(templatesFS, "healthsupervisorHTML.tmpl", buffer, p.getStatusInfo())
+}
+
+// SchemaVersion is the version of the schema of the JSON output
+func (p statusProvider) SchemaVersion() int {
+	return 1
+}
+
+// Schema returns the JSON schemas of the properties added by the JSON output
+func (p statusProvider) Schema() map[string]interface{} {
+	str := map[string]interface{}{"type": "string"}
+
+	return map[string]interface{}{
+		"healthSupervisor": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"restartsEnabled": map[string]interface{}{"type": "boolean"},
+				"restartEvents": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"type":     "object",
+						"required": []string{"component", "time", "reason"},
+						"properties": map[string]interface{}{
+							"component": str,
+							"time":      map[string]interface{}{"type": "string", "format": "date-time"},
+							"reason":    str,
+							"error":     str,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (p statusProvider) populateStatus(stats map[string]interface{}) {
+	stats["healthSupervisor"] = map[string]interface{}{
+		"restartsEnabled": health.RestartsEnabled(),
+		"restartEvents":   health.GetRestartEvents(),
+	}
+}
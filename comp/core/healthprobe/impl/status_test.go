@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package healthprobeimpl
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	healthprobeComponent "github.com/DataDog/datadog-agent/comp/core/healthprobe/def"
+	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/comp/core/status"
+	compdef "github.com/DataDog/datadog-agent/comp/def"
+	"github.com/DataDog/datadog-agent/pkg/status/health"
+)
+
+func TestStatusProvider(t *testing.T) {
+	provides, err := NewComponent(Requires{
+		Lc:      compdef.NewTestLifecycle(t),
+		Log:     logmock.New(t),
+		Options: healthprobeComponent.Options{EnableRestarts: true},
+	})
+	require.NoError(t, err)
+	defer health.SetRestartsEnabled(false)
+	assert.True(t, health.RestartsEnabled())
+
+	health.RecordRestart("dogstatsd-udp", "liveness probe failed: the intake loop is not running", errors.New("address already in use"))
+
+	statusProvider := provides.Status.Provider
+
+	stats := make(map[string]interface{})
+	require.NoError(t, statusProvider.JSON(false, stats))
+	require.Contains(t, stats, "healthSupervisor")
+	supervisor := stats["healthSupervisor"].(map[string]interface{})
+	assert.Equal(t, true, supervisor["restartsEnabled"])
+	assert.Len(t, supervisor["restartEvents"], 1)
+
+	b := new(bytes.Buffer)
+	require.NoError(t, statusProvider.Text(false, b))
+	assert.Contains(t, b.String(), "Restarts: Enabled")
+	assert.Contains(t, b.String(), "dogstatsd-udp at ")
+	assert.Contains(t, b.String(), "Reason: liveness probe failed: the intake loop is not running")
+	assert.Contains(t, b.String(), "Restart failed: address already in use")
+
+	b = new(bytes.Buffer)
+	require.NoError(t, statusProvider.HTML(false, b))
+	assert.Contains(t, b.String(), "dogstatsd-udp restarted at ")
+
+	schemaProvider, ok := statusProvider.(status.SchemaProvider)
+	require.True(t, ok)
+	assert.Equal(t, 1, schemaProvider.SchemaVersion())
+	assert.Contains(t, schemaProvider.Schema(), "healthSupervisor")
+}
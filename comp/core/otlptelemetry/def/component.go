@@ -0,0 +1,16 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package otlptelemetry implements a component exporting the internal telemetry of the Agent as OTLP metrics
+package otlptelemetry
+
+// team: agent-runtimes
+
+// Component is the component type. It periodically exports the metrics of the internal telemetry registry, the ones
+// exposed by the /telemetry endpoint, to the OTLP/HTTP endpoint set by telemetry.otlp.endpoint.
+type Component interface {
+	// Export exports the current values of the internal telemetry
+	Export() error
+}
@@ -0,0 +1,144 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package otlptelemetryimpl
+
+import (
+	"math"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/DataDog/datadog-agent/comp/core/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/version"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// scopeName is the instrumentation scope of the exported metrics
+const scopeName = "github.com/DataDog/datadog-agent/comp/core/telemetry"
+
+// resourceAttributes identifies the Agent exporting its telemetry
+type resourceAttributes struct {
+	serviceName    string
+	serviceVersion string
+	hostName       string
+}
+
+// convertFamilies converts the Prometheus metric families of the telemetry registry to OTLP metrics. The counters,
+// histograms and summaries are cumulative since startTime, like their Prometheus counterparts.
+func convertFamilies(families []*telemetry.MetricFamily, resource resourceAttributes, startTime time.Time, now time.Time) pmetric.Metrics {
+	metrics := pmetric.NewMetrics()
+
+	rm := metrics.ResourceMetrics().AppendEmpty()
+	attributes := rm.Resource().Attributes()
+	attributes.PutStr("service.name", resource.serviceName)
+	attributes.PutStr("service.version", resource.serviceVersion)
+	if resource.hostName != "" {
+		attributes.PutStr("host.name", resource.hostName)
+	}
+
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName(scopeName)
+	sm.Scope().SetVersion(version.AgentVersion)
+
+	start := pcommon.NewTimestampFromTime(startTime)
+	for _, family := range families {
+		if len(family.GetMetric()) == 0 {
+			continue
+		}
+
+		m := sm.Metrics().AppendEmpty()
+		m.SetName(family.GetName())
+		m.SetDescription(family.GetHelp())
+
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			sum := m.SetEmptySum()
+			sum.SetIsMonotonic(true)
+			sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			for _, metric := range family.GetMetric() {
+				dp := sum.DataPoints().AppendEmpty()
+				setDataPointCommon(dp.Attributes(), dp.SetStartTimestamp, dp.SetTimestamp, metric, start, now)
+				dp.SetDoubleValue(metric.GetCounter().GetValue())
+			}
+		case dto.MetricType_HISTOGRAM:
+			histogram := m.SetEmptyHistogram()
+			histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			for _, metric := range family.GetMetric() {
+				dp := histogram.DataPoints().AppendEmpty()
+				setDataPointCommon(dp.Attributes(), dp.SetStartTimestamp, dp.SetTimestamp, metric, start, now)
+				convertHistogram(dp, metric.GetHistogram())
+			}
+		case dto.MetricType_SUMMARY:
+			summary := m.SetEmptySummary()
+			for _, metric := range family.GetMetric() {
+				dp := summary.DataPoints().AppendEmpty()
+				setDataPointCommon(dp.Attributes(), dp.SetStartTimestamp, dp.SetTimestamp, metric, start, now)
+				dp.SetCount(metric.GetSummary().GetSampleCount())
+				dp.SetSum(metric.GetSummary().GetSampleSum())
+				for _, q := range metric.GetSummary().GetQuantile() {
+					qv := dp.QuantileValues().AppendEmpty()
+					qv.SetQuantile(q.GetQuantile())
+					qv.SetValue(q.GetValue())
+				}
+			}
+		default:
+			// gauges and untyped metrics
+			gauge := m.SetEmptyGauge()
+			for _, metric := range family.GetMetric() {
+				dp := gauge.DataPoints().AppendEmpty()
+				setDataPointCommon(dp.Attributes(), nil, dp.SetTimestamp, metric, start, now)
+				if metric.GetGauge() != nil {
+					dp.SetDoubleValue(metric.GetGauge().GetValue())
+				} else {
+					dp.SetDoubleValue(metric.GetUntyped().GetValue())
+				}
+			}
+		}
+	}
+
+	return metrics
+}
+
+// setDataPointCommon sets the attributes and the timestamps of a data point, setStart is nil for the gauges
+func setDataPointCommon(attributes pcommon.Map, setStart func(pcommon.Timestamp), setTimestamp func(pcommon.Timestamp), metric *dto.Metric, start pcommon.Timestamp, now time.Time) {
+	for _, label := range metric.GetLabel() {
+		attributes.PutStr(label.GetName(), label.GetValue())
+	}
+	if setStart != nil {
+		setStart(start)
+	}
+	if metric.TimestampMs != nil {
+		setTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(metric.GetTimestampMs())))
+	} else {
+		setTimestamp(pcommon.NewTimestampFromTime(now))
+	}
+}
+
+// convertHistogram converts the cumulative buckets of a Prometheus histogram to the explicit buckets of an OTLP one
+func convertHistogram(dp pmetric.HistogramDataPoint, histogram *dto.Histogram) {
+	dp.SetCount(histogram.GetSampleCount())
+	dp.SetSum(histogram.GetSampleSum())
+
+	buckets := histogram.GetBucket()
+	bounds := make([]float64, 0, len(buckets))
+	counts := make([]uint64, 0, len(buckets)+1)
+	var previous uint64
+	for _, bucket := range buckets {
+		if math.IsInf(bucket.GetUpperBound(), 1) {
+			break
+		}
+		bounds = append(bounds, bucket.GetUpperBound())
+		counts = append(counts, bucket.GetCumulativeCount()-previous)
+		previous = bucket.GetCumulativeCount()
+	}
+	// the last OTLP bucket counts the values greater than the last bound
+	counts = append(counts, histogram.GetSampleCount()-previous)
+
+	dp.ExplicitBounds().FromRaw(bounds)
+	dp.BucketCounts().FromRaw(counts)
+}
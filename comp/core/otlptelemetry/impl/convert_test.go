@@ -0,0 +1,117 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package otlptelemetryimpl
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/DataDog/datadog-agent/comp/core/telemetry"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestConvertFamilies(t *testing.T) {
+	families := []*telemetry.MetricFamily{
+		{
+			Name: proto.String("requests"),
+			Help: proto.String("Number of requests."),
+			Type: dto.MetricType_COUNTER.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Label:   []*dto.LabelPair{{Name: proto.String("status"), Value: proto.String("ok")}},
+					Counter: &dto.Counter{Value: proto.Float64(12)},
+				},
+			},
+		},
+		{
+			Name: proto.String("latency"),
+			Type: dto.MetricType_HISTOGRAM.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Histogram: &dto.Histogram{
+						SampleCount: proto.Uint64(10),
+						SampleSum:   proto.Float64(4.5),
+						Bucket: []*dto.Bucket{
+							{UpperBound: proto.Float64(0.1), CumulativeCount: proto.Uint64(2)},
+							{UpperBound: proto.Float64(1), CumulativeCount: proto.Uint64(9)},
+							{UpperBound: proto.Float64(math.Inf(1)), CumulativeCount: proto.Uint64(10)},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: proto.String("durations"),
+			Type: dto.MetricType_SUMMARY.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Summary: &dto.Summary{
+						SampleCount: proto.Uint64(3),
+						SampleSum:   proto.Float64(6),
+						Quantile:    []*dto.Quantile{{Quantile: proto.Float64(0.5), Value: proto.Float64(2)}},
+					},
+				},
+			},
+		},
+		{
+			Name:   proto.String("queue_size"),
+			Type:   dto.MetricType_GAUGE.Enum(),
+			Metric: []*dto.Metric{{Gauge: &dto.Gauge{Value: proto.Float64(7)}, TimestampMs: proto.Int64(1700000000000)}},
+		},
+		{
+			Name: proto.String("empty"),
+			Type: dto.MetricType_GAUGE.Enum(),
+		},
+	}
+
+	start := time.Unix(1600000000, 0)
+	now := time.Unix(1700000010, 0)
+	metrics := convertFamilies(families, resourceAttributes{serviceName: "agent", serviceVersion: "7.0.0"}, start, now)
+
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	rm := metrics.ResourceMetrics().At(0)
+	serviceName, _ := rm.Resource().Attributes().Get("service.name")
+	assert.Equal(t, "agent", serviceName.Str())
+	_, ok := rm.Resource().Attributes().Get("host.name")
+	assert.False(t, ok)
+
+	ms := rm.ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 4, ms.Len())
+
+	requests := ms.At(0)
+	assert.Equal(t, "requests", requests.Name())
+	assert.Equal(t, "Number of requests.", requests.Description())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, requests.Sum().AggregationTemporality())
+	dp := requests.Sum().DataPoints().At(0)
+	assert.Equal(t, 12.0, dp.DoubleValue())
+	assert.Equal(t, start.UnixNano(), dp.StartTimestamp().AsTime().UnixNano())
+	assert.Equal(t, now.UnixNano(), dp.Timestamp().AsTime().UnixNano())
+	status, _ := dp.Attributes().Get("status")
+	assert.Equal(t, "ok", status.Str())
+
+	latency := ms.At(1).Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(10), latency.Count())
+	assert.Equal(t, 4.5, latency.Sum())
+	assert.Equal(t, []float64{0.1, 1}, latency.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{2, 7, 1}, latency.BucketCounts().AsRaw())
+
+	durations := ms.At(2).Summary().DataPoints().At(0)
+	assert.Equal(t, uint64(3), durations.Count())
+	assert.Equal(t, 0.5, durations.QuantileValues().At(0).Quantile())
+	assert.Equal(t, 2.0, durations.QuantileValues().At(0).Value())
+
+	queueSize := ms.At(3).Gauge().DataPoints().At(0)
+	assert.Equal(t, 7.0, queueSize.DoubleValue())
+	assert.Equal(t, int64(1700000000), queueSize.Timestamp().AsTime().Unix())
+	assert.Zero(t, queueSize.StartTimestamp())
+}
@@ -0,0 +1,198 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package otlptelemetryimpl provides the implementation of the otlptelemetry component.
+package otlptelemetryimpl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/comp/core/hostname/hostnameinterface"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	otlptelemetry "github.com/DataDog/datadog-agent/comp/core/otlptelemetry/def"
+	"github.com/DataDog/datadog-agent/comp/core/telemetry"
+	compdef "github.com/DataDog/datadog-agent/comp/def"
+	"github.com/DataDog/datadog-agent/pkg/util/flavor"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+	"github.com/DataDog/datadog-agent/pkg/version"
+)
+
+const (
+	// minExportInterval is the minimum interval between two exports
+	minExportInterval = 10 * time.Second
+	// maxErrorBodySize is the size of the response body reported when an export fails
+	maxErrorBodySize = 1024
+)
+
+// Requires defines the dependencies for the otlptelemetry component
+type Requires struct {
+	compdef.In
+
+	Config    config.Component
+	Log       log.Component
+	Telemetry telemetry.Component
+	Hostname  hostnameinterface.Component
+
+	Lc compdef.Lifecycle
+}
+
+// Provides defines the output of the otlptelemetry component
+type Provides struct {
+	compdef.Out
+
+	Comp otlptelemetry.Component
+}
+
+type exporter struct {
+	log       log.Component
+	telemetry telemetry.Component
+	hostname  hostnameinterface.Component
+
+	enabled  bool
+	endpoint string
+	headers  map[string]string
+	interval time.Duration
+	client   *http.Client
+
+	// startTime is the start of the cumulative metrics
+	startTime time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewComponent creates a new otlptelemetry component
+func NewComponent(deps Requires) Provides {
+	e := &exporter{
+		log:       deps.Log,
+		telemetry: deps.Telemetry,
+		hostname:  deps.Hostname,
+		enabled:   deps.Config.GetBool("telemetry.otlp.enabled"),
+		endpoint:  deps.Config.GetString("telemetry.otlp.endpoint"),
+		headers:   deps.Config.GetStringMapString("telemetry.otlp.headers"),
+		interval:  deps.Config.GetDuration("telemetry.otlp.export_interval"),
+		startTime: time.Now(),
+	}
+
+	if !e.enabled {
+		return Provides{Comp: e}
+	}
+
+	if u, err := url.Parse(e.endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		// the telemetry export mustn't prevent the Agent from starting
+		deps.Log.Errorf("Invalid telemetry.otlp.endpoint %q, expected an http or https URL, the OTLP export of the internal telemetry is disabled", e.endpoint)
+		e.enabled = false
+		return Provides{Comp: e}
+	}
+	if e.interval < minExportInterval {
+		deps.Log.Warnf("telemetry.otlp.export_interval is lower than %s, using %s", minExportInterval, minExportInterval)
+		e.interval = minExportInterval
+	}
+	e.client = &http.Client{
+		Timeout:   deps.Config.GetDuration("telemetry.otlp.timeout"),
+		Transport: httputils.NewTransport(deps.Config, httputils.AgentTelemetryDestination),
+	}
+
+	deps.Lc.Append(compdef.Hook{
+		OnStart: func(_ context.Context) error {
+			e.start()
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			e.stop()
+			return nil
+		},
+	})
+
+	return Provides{Comp: e}
+}
+
+func (e *exporter) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := e.export(ctx); err != nil {
+					e.log.Warnf("Failed to export the internal telemetry to %s: %s", e.endpoint, err)
+				}
+			}
+		}
+	}()
+	e.log.Infof("Exporting the internal telemetry with OTLP to %s every %s", e.endpoint, e.interval)
+}
+
+func (e *exporter) stop() {
+	e.cancel()
+	e.wg.Wait()
+}
+
+// Export exports the current values of the internal telemetry
+func (e *exporter) Export() error {
+	if !e.enabled {
+		return errors.New("the OTLP export of the internal telemetry is disabled")
+	}
+	return e.export(context.Background())
+}
+
+func (e *exporter) export(ctx context.Context) error {
+	families, err := e.telemetry.Gather(false)
+	if err != nil {
+		return fmt.Errorf("couldn't gather the telemetry: %w", err)
+	}
+
+	resource := resourceAttributes{
+		serviceName:    flavor.GetFlavor(),
+		serviceVersion: version.AgentVersion,
+		hostName:       e.hostname.GetSafe(ctx),
+	}
+	metrics := convertFamilies(families, resource, e.startTime, time.Now())
+	payload, err := pmetricotlp.NewExportRequestFromMetrics(metrics).MarshalProto()
+	if err != nil {
+		return fmt.Errorf("couldn't encode the metrics: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
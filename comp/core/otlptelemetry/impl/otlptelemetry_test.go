@@ -0,0 +1,152 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package otlptelemetryimpl
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/comp/core/hostname/hostnameinterface"
+	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/comp/core/telemetry"
+	"github.com/DataDog/datadog-agent/comp/core/telemetry/telemetryimpl"
+	compdef "github.com/DataDog/datadog-agent/comp/def"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func newTestComponent(t *testing.T, overrides map[string]interface{}) (Provides, telemetry.Component, *compdef.TestLifecycle) {
+	cfg := config.NewMock(t)
+	for key, value := range overrides {
+		cfg.SetWithoutSource(key, value)
+	}
+
+	// the telemetry component relies on a global registry, which is reset to not depend on the other tests
+	tel := fxutil.Test[telemetry.Mock](t, telemetryimpl.MockModule())
+	tel.Reset()
+
+	hostname, _ := hostnameinterface.NewMock("test-host")
+	lc := compdef.NewTestLifecycle(t)
+	provides := NewComponent(Requires{
+		Config:    cfg,
+		Log:       logmock.New(t),
+		Telemetry: tel,
+		Hostname:  hostname,
+		Lc:        lc,
+	})
+	return provides, tel, lc
+}
+
+func TestExport(t *testing.T) {
+	received := make(chan pmetric.Metrics, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/metrics", r.URL.Path)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		req := pmetricotlp.NewExportRequest()
+		require.NoError(t, req.UnmarshalProto(body))
+		received <- req.Metrics()
+	}))
+	defer srv.Close()
+
+	provides, tel, lc := newTestComponent(t, map[string]interface{}{
+		"telemetry.otlp.enabled":  true,
+		"telemetry.otlp.endpoint": srv.URL + "/v1/metrics",
+		"telemetry.otlp.headers":  map[string]string{"X-Api-Key": "secret"},
+	})
+	lc.AssertHooksNumber(1)
+
+	tel.NewCounter("otlp_test", "packets", []string{"listener"}, "Packets received.").Add(3, "udp")
+	tel.NewGauge("otlp_test", "queue_size", nil, "Size of the queue.").Set(7)
+
+	require.NoError(t, provides.Comp.Export())
+
+	var metrics pmetric.Metrics
+	select {
+	case metrics = <-received:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no export received")
+	}
+
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	rm := metrics.ResourceMetrics().At(0)
+	hostName, ok := rm.Resource().Attributes().Get("host.name")
+	require.True(t, ok)
+	assert.Equal(t, "test-host", hostName.Str())
+
+	byName := make(map[string]pmetric.Metric)
+	ms := rm.ScopeMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		byName[ms.At(i).Name()] = ms.At(i)
+	}
+
+	packets, ok := byName["otlp_test__packets"]
+	require.True(t, ok)
+	require.Equal(t, pmetric.MetricTypeSum, packets.Type())
+	assert.True(t, packets.Sum().IsMonotonic())
+	dp := packets.Sum().DataPoints().At(0)
+	assert.Equal(t, 3.0, dp.DoubleValue())
+	listener, _ := dp.Attributes().Get("listener")
+	assert.Equal(t, "udp", listener.Str())
+
+	queueSize, ok := byName["otlp_test__queue_size"]
+	require.True(t, ok)
+	require.Equal(t, pmetric.MetricTypeGauge, queueSize.Type())
+	assert.Equal(t, 7.0, queueSize.Gauge().DataPoints().At(0).DoubleValue())
+}
+
+func TestExportError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("invalid payload"))
+	}))
+	defer srv.Close()
+
+	provides, _, _ := newTestComponent(t, map[string]interface{}{
+		"telemetry.otlp.enabled":  true,
+		"telemetry.otlp.endpoint": srv.URL,
+	})
+	err := provides.Comp.Export()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400: invalid payload")
+}
+
+func TestDisabled(t *testing.T) {
+	provides, _, lc := newTestComponent(t, nil)
+	lc.AssertHooksNumber(0)
+	assert.Error(t, provides.Comp.Export())
+
+	// an invalid endpoint disables the export rather than failing the Agent startup
+	provides, _, lc = newTestComponent(t, map[string]interface{}{
+		"telemetry.otlp.enabled":  true,
+		"telemetry.otlp.endpoint": "localhost:4318",
+	})
+	lc.AssertHooksNumber(0)
+	assert.Error(t, provides.Comp.Export())
+}
+
+func TestStartStop(t *testing.T) {
+	_, _, lc := newTestComponent(t, map[string]interface{}{
+		"telemetry.otlp.enabled":  true,
+		"telemetry.otlp.endpoint": "http://localhost:4318/v1/metrics",
+	})
+	require.NoError(t, lc.Start(context.Background()))
+	require.NoError(t, lc.Stop(context.Background()))
+}
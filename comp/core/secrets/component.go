@@ -3,7 +3,7 @@
 // This product includes software developed at Datadog (https://www.datadoghq.com/).
 // Copyright 2016-present Datadog, Inc.
 
-// Package secrets decodes secret values by invoking the configured executable command
+// Package secrets decodes secret values by invoking the configured executable command or a native secret backend
 package secrets
 
 import (
@@ -23,6 +23,10 @@ type ConfigParams struct {
 	RemoveLinebreak  bool
 	RunPath          string
 	AuditFileMaxSize int
+	// BackendType selects a native secret backend used instead of the Command
+	BackendType string
+	// BackendConfig holds the settings of the native secret backend
+	BackendConfig map[string]interface{}
 }
 
 // Component is the component type.
@@ -35,6 +39,9 @@ type Component interface {
 	Resolve(data []byte, origin string) ([]byte, error)
 	// SubscribeToChanges registers a callback to be invoked whenever secrets are resolved or refreshed
 	SubscribeToChanges(callback SecretChangeCallback)
+	// SubscribeToRefresh registers a callback to be invoked whenever a refresh changes the value of a secret,
+	// whatever the setting using it. Subscribers reload the configurations embedding the secret.
+	SubscribeToRefresh(callback SecretChangeCallback)
 	// Refresh will resolve secret handles again, notifying any subscribers of changed values
 	Refresh() (string, error)
 }
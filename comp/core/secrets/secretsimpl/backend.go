@@ -0,0 +1,232 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package secretsimpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	backendTypeVault             = "vault"
+	backendTypeAWSSecretsManager = "aws.secrets_manager"
+	backendTypeGCPSecretManager  = "gcp.secret_manager"
+
+	// handleKeySeparator separates the secret ID from the key to extract from a secret holding several values,
+	// e.g. ENC[secret/data/datadog;api_key]
+	handleKeySeparator = ";"
+
+	// maxBackendResponseSize is the maximum size of the responses read from the secret managers
+	maxBackendResponseSize = 1024 * 1024
+
+	// leaseRetryInterval is how long to wait before fetching again the secrets whose lease expired after a failure
+	leaseRetryInterval = time.Minute
+)
+
+// secretBackend fetches the secrets natively from a secret manager instead of running the
+// secret_backend_command executable. The backends are only called with the resolver lock held.
+type secretBackend interface {
+	// fetchSecrets returns the value of each handle, or an error if any of them cannot be resolved
+	fetchSecrets(ctx context.Context, handles []string) (map[string]string, error)
+}
+
+// leaseRenewer is implemented by the backends whose secrets or credentials are leased and must be
+// renewed before they expire
+type leaseRenewer interface {
+	// nextRenewal returns when the next lease must be renewed, false if nothing is leased
+	nextRenewal() (time.Time, bool)
+	// renewLeases renews the leases due for renewal and returns the handles whose lease could not be
+	// renewed, their secret must be fetched again
+	renewLeases(ctx context.Context) []string
+}
+
+// Backend fetches the secrets from a secret manager whose client is too large to be a dependency of this
+// module, it's registered with RegisterBackend by the binaries supporting it
+type Backend interface {
+	// FetchSecret returns the value of the secret with the given ID
+	FetchSecret(ctx context.Context, id string) (string, error)
+}
+
+// BackendFactory creates a Backend from the secret_backend_config setting, the requests to the secret
+// manager must time out after the given timeout
+type BackendFactory func(config map[string]interface{}, timeout time.Duration) (Backend, error)
+
+var (
+	registeredBackendsMutex sync.Mutex
+	registeredBackends      = map[string]BackendFactory{}
+)
+
+// RegisterBackend registers the factory of the backend of the given secret_backend_type
+func RegisterBackend(backendType string, factory BackendFactory) {
+	registeredBackendsMutex.Lock()
+	defer registeredBackendsMutex.Unlock()
+	registeredBackends[backendType] = factory
+}
+
+func registeredBackend(backendType string) (BackendFactory, bool) {
+	registeredBackendsMutex.Lock()
+	defer registeredBackendsMutex.Unlock()
+	factory, ok := registeredBackends[backendType]
+	return factory, ok
+}
+
+// externalBackend fetches the secrets of the handles with a registered Backend
+type externalBackend struct {
+	backend Backend
+}
+
+func (b *externalBackend) fetchSecrets(ctx context.Context, handles []string) (map[string]string, error) {
+	ids, handlesByID := groupHandlesBySecret(handles)
+	secrets := make(map[string]string, len(handles))
+	for _, id := range ids {
+		secret, err := b.backend.FetchSecret(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, handle := range handlesByID[id] {
+			_, key := splitHandle(handle)
+			value, err := extractKey(handle, secret, key)
+			if err != nil {
+				return nil, err
+			}
+			secrets[handle] = value
+		}
+	}
+	return secrets, nil
+}
+
+// newSecretBackend returns the native backend of the given type, configured from the
+// secret_backend_config setting
+func newSecretBackend(backendType string, config map[string]interface{}, timeout time.Duration) (secretBackend, error) {
+	client := &http.Client{Timeout: timeout}
+	switch backendType {
+	case backendTypeVault:
+		return newVaultBackend(config, client)
+	case backendTypeAWSSecretsManager:
+		factory, ok := registeredBackend(backendType)
+		if !ok {
+			return nil, fmt.Errorf("the '%s' secret backend is not supported by this binary", backendType)
+		}
+		backend, err := factory(config, timeout)
+		if err != nil {
+			return nil, err
+		}
+		return &externalBackend{backend: backend}, nil
+	case backendTypeGCPSecretManager:
+		return newGCPSecretManagerBackend(config, client)
+	default:
+		return nil, fmt.Errorf("unknown secret_backend_type '%s', supported types are '%s', '%s' and '%s'",
+			backendType, backendTypeVault, backendTypeAWSSecretsManager, backendTypeGCPSecretManager)
+	}
+}
+
+// decodeBackendConfig decodes the secret_backend_config setting into the configuration of a backend
+func decodeBackendConfig(config map[string]interface{}, out interface{}) error {
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("invalid secret_backend_config: %s", err)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("invalid secret_backend_config: %s", err)
+	}
+	return nil
+}
+
+// splitHandle splits a handle into the ID of the secret and the optional key to extract from its value
+func splitHandle(handle string) (string, string) {
+	id, key, _ := strings.Cut(handle, handleKeySeparator)
+	return id, key
+}
+
+// groupHandlesBySecret returns the IDs of the secrets to fetch, sorted, and the handles using each of them
+func groupHandlesBySecret(handles []string) ([]string, map[string][]string) {
+	handlesByID := map[string][]string{}
+	for _, handle := range handles {
+		id, _ := splitHandle(handle)
+		handlesByID[id] = append(handlesByID[id], handle)
+	}
+
+	ids := make([]string, 0, len(handlesByID))
+	for id := range handlesByID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, handlesByID
+}
+
+// extractKey returns the value of the key in a secret holding a JSON object, or the whole secret when no key is set
+func extractKey(handle, secret, key string) (string, error) {
+	if key == "" {
+		return secret, nil
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &values); err != nil {
+		return "", fmt.Errorf("secret '%s' is not a JSON object, the key '%s' cannot be extracted", handle, key)
+	}
+	return valueOfKey(handle, values, key)
+}
+
+// valueOfKey returns the string value of the key
+func valueOfKey(handle string, values map[string]interface{}, key string) (string, error) {
+	value, found := values[key]
+	if !found {
+		return "", fmt.Errorf("secret '%s' has no key '%s'", handle, key)
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("the key '%s' of secret '%s' is not a string", key, handle)
+	}
+	return str, nil
+}
+
+// doJSONRequest sends the request and decodes its JSON response into out, the errors include the body of the
+// response returned by the secret manager
+func doJSONRequest(client *http.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBackendResponseSize))
+	if err != nil {
+		return fmt.Errorf("error reading the response of %s: %s", req.URL.Host, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &backendHTTPError{statusCode: resp.StatusCode, body: strings.TrimSpace(string(body))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("could not unmarshal the response of %s: %s", req.URL.Host, err)
+	}
+	return nil
+}
+
+// backendHTTPError is returned when a secret manager responds with an error status
+type backendHTTPError struct {
+	statusCode int
+	body       string
+}
+
+func (e *backendHTTPError) Error() string {
+	return fmt.Sprintf("unexpected status code %d: %s", e.statusCode, e.body)
+}
+
+// renewalTime returns when a lease of the given duration must be renewed, leaving a third of
+// the lease to retry the renewal or fetch the secret again
+func renewalTime(now time.Time, duration time.Duration) time.Time {
+	return now.Add(duration * 2 / 3)
+}
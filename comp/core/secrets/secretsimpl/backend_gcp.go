@@ -0,0 +1,182 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package secretsimpl
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	gcpDefaultSecretManagerEndpoint = "https://secretmanager.googleapis.com"
+	gcpDefaultMetadataEndpoint      = "http://metadata.google.internal"
+
+	// gcpTokenExpiryWindow is how long before its expiration the access token is retrieved again
+	gcpTokenExpiryWindow = time.Minute
+)
+
+// gcpConfig is the secret_backend_config of the gcp.secret_manager backend
+type gcpConfig struct {
+	ProjectID string `json:"project_id"`
+	Endpoint  string `json:"endpoint"`
+}
+
+// gcpSecretManagerBackend reads the secrets from Google Cloud Secret Manager. Handles are the name of the
+// secret, whose latest version is read, or the full resource name of a secret version, optionally followed
+// by the key to read when the secret is a JSON object, e.g. ENC[datadog;api_key].
+//
+// The access token of the service account of the instance, or of the Kubernetes service account with GKE
+// workload identity, is retrieved from the metadata server and retrieved again before it expires.
+type gcpSecretManagerBackend struct {
+	projectID        string
+	endpoint         string
+	metadataEndpoint string
+	client           *http.Client
+	now              func() time.Time
+
+	token           string
+	tokenExpiration time.Time
+}
+
+func newGCPSecretManagerBackend(config map[string]interface{}, client *http.Client) (*gcpSecretManagerBackend, error) {
+	var cfg gcpConfig
+	if err := decodeBackendConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+
+	b := &gcpSecretManagerBackend{
+		projectID:        cfg.ProjectID,
+		endpoint:         strings.TrimSuffix(cfg.Endpoint, "/"),
+		metadataEndpoint: gcpDefaultMetadataEndpoint,
+		client:           client,
+		now:              time.Now,
+	}
+	if b.endpoint == "" {
+		b.endpoint = gcpDefaultSecretManagerEndpoint
+	}
+	if host := os.Getenv("GCE_METADATA_HOST"); host != "" {
+		b.metadataEndpoint = "http://" + host
+	}
+	return b, nil
+}
+
+type gcpAccessSecretVersionResponse struct {
+	Payload struct {
+		Data string `json:"data"`
+	} `json:"payload"`
+}
+
+func (b *gcpSecretManagerBackend) fetchSecrets(ctx context.Context, handles []string) (map[string]string, error) {
+	names, handlesByName := groupHandlesBySecret(handles)
+	secrets := make(map[string]string, len(handles))
+	for _, name := range names {
+		secret, err := b.accessSecretVersion(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("could not read GCP secret '%s': %s", name, err)
+		}
+		for _, handle := range handlesByName[name] {
+			_, key := splitHandle(handle)
+			value, err := extractKey(handle, secret, key)
+			if err != nil {
+				return nil, err
+			}
+			secrets[handle] = value
+		}
+	}
+	return secrets, nil
+}
+
+func (b *gcpSecretManagerBackend) accessSecretVersion(ctx context.Context, name string) (string, error) {
+	resource := name
+	if !strings.HasPrefix(name, "projects/") {
+		if b.projectID == "" {
+			projectID, err := b.metadata(ctx, "/computeMetadata/v1/project/project-id")
+			if err != nil {
+				return "", fmt.Errorf("no project_id set and the project cannot be retrieved from the metadata server: %s", err)
+			}
+			b.projectID = strings.TrimSpace(projectID)
+		}
+		resource = fmt.Sprintf("projects/%s/secrets/%s/versions/latest", b.projectID, name)
+	}
+
+	token, err := b.accessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.endpoint+"/v1/"+resource+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp := &gcpAccessSecretVersionResponse{}
+	if err := doJSONRequest(b.client, req, resp); err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("invalid secret payload: %s", err)
+	}
+	return string(data), nil
+}
+
+// accessToken returns the cached access token, or retrieves it from the metadata server if it is about to expire
+func (b *gcpSecretManagerBackend) accessToken(ctx context.Context) (string, error) {
+	if b.token != "" && b.now().Add(gcpTokenExpiryWindow).Before(b.tokenExpiration) {
+		return b.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.metadataEndpoint+"/computeMetadata/v1/instance/service-accounts/default/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := doJSONRequest(b.client, req, &token); err != nil {
+		return "", fmt.Errorf("could not retrieve an access token from the metadata server: %s", err)
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("could not retrieve an access token from the metadata server: no token returned")
+	}
+
+	b.token = token.AccessToken
+	b.tokenExpiration = b.now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return b.token, nil
+}
+
+func (b *gcpSecretManagerBackend) metadata(ctx context.Context, path string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.metadataEndpoint+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBackendResponseSize))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", &backendHTTPError{statusCode: resp.StatusCode, body: strings.TrimSpace(string(body))}
+	}
+	return string(body), nil
+}
@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package secretsimpl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCPSecretManagerBackendFetchSecrets(t *testing.T) {
+	t.Setenv("GCE_METADATA_HOST", "")
+
+	tokenRequests := 0
+	secretRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/computeMetadata/v1/project/project-id":
+			assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+			fmt.Fprint(w, "my-project")
+			return
+		case "/computeMetadata/v1/instance/service-accounts/default/token":
+			assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+			tokenRequests++
+			fmt.Fprint(w, `{"access_token": "ya29.token", "expires_in": 3600, "token_type": "Bearer"}`)
+			return
+		}
+
+		secretRequests++
+		assert.Equal(t, "Bearer ya29.token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/v1/projects/my-project/secrets/datadog/versions/latest:access":
+			// {"api_key": "123456"}
+			fmt.Fprint(w, `{"payload": {"data": "eyJhcGlfa2V5IjogIjEyMzQ1NiJ9"}}`)
+		case "/v1/projects/other/secrets/password/versions/2:access":
+			fmt.Fprint(w, `{"payload": {"data": "cGFzc3dvcmQ="}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": {"code": 404}}`)
+		}
+	}))
+	defer server.Close()
+
+	b, err := newGCPSecretManagerBackend(map[string]interface{}{"endpoint": server.URL}, server.Client())
+	require.NoError(t, err)
+	b.metadataEndpoint = server.URL
+
+	secrets, err := b.fetchSecrets(context.Background(), []string{"datadog;api_key", "projects/other/secrets/password/versions/2"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"datadog;api_key": "123456",
+		"projects/other/secrets/password/versions/2": "password",
+	}, secrets)
+	assert.Equal(t, "my-project", b.projectID)
+	assert.Equal(t, 2, secretRequests)
+	assert.Equal(t, 1, tokenRequests)
+
+	_, err = b.fetchSecrets(context.Background(), []string{"missing"})
+	assert.EqualError(t, err, `could not read GCP secret 'missing': unexpected status code 404: {"error": {"code": 404}}`)
+	assert.Equal(t, 1, tokenRequests)
+
+	// the access token is retrieved again before it expires
+	b.now = func() time.Time { return time.Now().Add(time.Hour) }
+	_, err = b.fetchSecrets(context.Background(), []string{"datadog;api_key"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, tokenRequests)
+}
+
+func TestGCPSecretManagerBackendMetadataHost(t *testing.T) {
+	t.Setenv("GCE_METADATA_HOST", "169.254.169.254")
+
+	b, err := newGCPSecretManagerBackend(map[string]interface{}{"project_id": "my-project"}, http.DefaultClient)
+	require.NoError(t, err)
+	assert.Equal(t, "my-project", b.projectID)
+	assert.Equal(t, "https://secretmanager.googleapis.com", b.endpoint)
+	assert.Equal(t, "http://169.254.169.254", b.metadataEndpoint)
+}
@@ -0,0 +1,372 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package secretsimpl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	vaultAuthKubernetes = "kubernetes"
+	vaultAuthToken      = "token"
+
+	vaultDefaultKubernetesMountPath = "kubernetes"
+	vaultDefaultKubernetesTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// vaultConfig is the secret_backend_config of the vault backend
+type vaultConfig struct {
+	Address             string `json:"address"`
+	Namespace           string `json:"namespace"`
+	AuthMethod          string `json:"auth_method"`
+	Token               string `json:"token"`
+	KubernetesRole      string `json:"kubernetes_role"`
+	KubernetesMountPath string `json:"kubernetes_mount_path"`
+	KubernetesTokenPath string `json:"kubernetes_token_path"`
+}
+
+// vaultLease is the lease of a secret read from Vault, shared by all the handles of the secret
+type vaultLease struct {
+	id        string
+	handles   []string
+	duration  time.Duration
+	renewable bool
+	renewAt   time.Time
+}
+
+// vaultBackend reads the secrets from HashiCorp Vault. Handles are the path of the secret followed by
+// the key to read, e.g. ENC[secret/data/datadog;api_key], both KV version 1 and 2 engines are supported.
+//
+// The agent authenticates with the Kubernetes auth method using the token of its service account, or
+// with a static token. The Vault token and the leases of the dynamic secrets are renewed before they
+// expire.
+type vaultBackend struct {
+	config vaultConfig
+	client *http.Client
+	now    func() time.Time
+
+	token          string
+	tokenRenewable bool
+	tokenDuration  time.Duration
+	tokenRenewAt   time.Time
+
+	// leases by secret path
+	leases map[string]*vaultLease
+}
+
+var _ leaseRenewer = (*vaultBackend)(nil)
+
+func newVaultBackend(config map[string]interface{}, client *http.Client) (*vaultBackend, error) {
+	b := &vaultBackend{
+		client: client,
+		now:    time.Now,
+		leases: map[string]*vaultLease{},
+	}
+	if err := decodeBackendConfig(config, &b.config); err != nil {
+		return nil, err
+	}
+
+	if b.config.Address == "" {
+		b.config.Address = os.Getenv("VAULT_ADDR")
+	}
+	if b.config.Address == "" {
+		return nil, errors.New("the vault secret backend requires an address")
+	}
+	b.config.Address = strings.TrimSuffix(b.config.Address, "/")
+	if b.config.Namespace == "" {
+		b.config.Namespace = os.Getenv("VAULT_NAMESPACE")
+	}
+
+	switch b.config.AuthMethod {
+	case "", vaultAuthKubernetes:
+		b.config.AuthMethod = vaultAuthKubernetes
+		if b.config.KubernetesRole == "" {
+			return nil, errors.New("the kubernetes auth method of the vault secret backend requires a kubernetes_role")
+		}
+		if b.config.KubernetesMountPath == "" {
+			b.config.KubernetesMountPath = vaultDefaultKubernetesMountPath
+		}
+		if b.config.KubernetesTokenPath == "" {
+			b.config.KubernetesTokenPath = vaultDefaultKubernetesTokenPath
+		}
+	case vaultAuthToken:
+		if b.config.Token == "" {
+			b.config.Token = os.Getenv("VAULT_TOKEN")
+		}
+		if b.config.Token == "" {
+			return nil, errors.New("the token auth method of the vault secret backend requires a token")
+		}
+	default:
+		return nil, fmt.Errorf("unknown vault auth_method '%s', supported methods are '%s' and '%s'", b.config.AuthMethod, vaultAuthKubernetes, vaultAuthToken)
+	}
+	return b, nil
+}
+
+// vaultAuth is the auth section of the Vault responses, and the data of the token lookup
+type vaultAuth struct {
+	ClientToken   string `json:"client_token"`
+	LeaseDuration int    `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+type vaultResponse struct {
+	LeaseID       string                 `json:"lease_id"`
+	LeaseDuration int                    `json:"lease_duration"`
+	Renewable     bool                   `json:"renewable"`
+	Data          map[string]interface{} `json:"data"`
+	Auth          *vaultAuth             `json:"auth"`
+}
+
+func (b *vaultBackend) fetchSecrets(ctx context.Context, handles []string) (map[string]string, error) {
+	if b.token == "" {
+		if err := b.login(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	paths, handlesByPath := groupHandlesBySecret(handles)
+	secrets := make(map[string]string, len(handles))
+	for _, path := range paths {
+		resp, err := b.readSecret(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+
+		data := resp.Data
+		// the KV version 2 engine nests the secret and its metadata
+		if nested, ok := data["data"].(map[string]interface{}); ok {
+			if _, hasMetadata := data["metadata"]; hasMetadata {
+				data = nested
+			}
+		}
+
+		for _, handle := range handlesByPath[path] {
+			_, key := splitHandle(handle)
+			if key == "" {
+				return nil, fmt.Errorf("vault secret handle '%s' must be the path of the secret and the key to read separated by '%s'", handle, handleKeySeparator)
+			}
+			value, err := valueOfKey(handle, data, key)
+			if err != nil {
+				return nil, err
+			}
+			secrets[handle] = value
+		}
+
+		if resp.LeaseID != "" && resp.LeaseDuration > 0 {
+			b.trackLease(path, resp, handlesByPath[path])
+		}
+	}
+	return secrets, nil
+}
+
+// readSecret reads the secret at the path, logging in again once if the token was revoked or expired
+func (b *vaultBackend) readSecret(ctx context.Context, path string) (*vaultResponse, error) {
+	resp := &vaultResponse{}
+	err := b.request(ctx, http.MethodGet, "/v1/"+strings.TrimPrefix(path, "/"), nil, resp)
+
+	var httpErr *backendHTTPError
+	if errors.As(err, &httpErr) && httpErr.statusCode == http.StatusForbidden && b.config.AuthMethod == vaultAuthKubernetes {
+		log.Infof("Vault denied the access to '%s', logging in again", path)
+		if err := b.login(ctx); err != nil {
+			return nil, err
+		}
+		resp = &vaultResponse{}
+		err = b.request(ctx, http.MethodGet, "/v1/"+strings.TrimPrefix(path, "/"), nil, resp)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read vault secret '%s': %s", path, err)
+	}
+	return resp, nil
+}
+
+// trackLease records the lease of a dynamic secret so that it is renewed before it expires
+func (b *vaultBackend) trackLease(path string, resp *vaultResponse, handles []string) {
+	lease := &vaultLease{
+		id:        resp.LeaseID,
+		duration:  time.Duration(resp.LeaseDuration) * time.Second,
+		renewable: resp.Renewable,
+	}
+	lease.renewAt = renewalTime(b.now(), lease.duration)
+
+	// the handles already read from the secret share the new lease
+	lease.handles = append(lease.handles, handles...)
+	if previous, found := b.leases[path]; found {
+		for _, handle := range previous.handles {
+			if !slices.Contains(lease.handles, handle) {
+				lease.handles = append(lease.handles, handle)
+			}
+		}
+	}
+	b.leases[path] = lease
+}
+
+// login authenticates the agent and sets the token used by the next requests
+func (b *vaultBackend) login(ctx context.Context) error {
+	if b.config.AuthMethod == vaultAuthToken {
+		b.token = b.config.Token
+		// a static token may expire as well, look it up to renew it in time
+		resp := &vaultResponse{}
+		if err := b.request(ctx, http.MethodGet, "/v1/auth/token/lookup-self", nil, resp); err != nil {
+			b.token = ""
+			return fmt.Errorf("could not look up the vault token: %s", err)
+		}
+		ttl, _ := resp.Data["ttl"].(float64)
+		renewable, _ := resp.Data["renewable"].(bool)
+		b.setToken(b.config.Token, int(ttl), renewable)
+		return nil
+	}
+
+	jwt, err := os.ReadFile(b.config.KubernetesTokenPath)
+	if err != nil {
+		return fmt.Errorf("could not read the service account token to log into vault: %s", err)
+	}
+
+	b.token = ""
+	resp := &vaultResponse{}
+	body := map[string]string{
+		"role": b.config.KubernetesRole,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	}
+	if err := b.request(ctx, http.MethodPost, "/v1/auth/"+b.config.KubernetesMountPath+"/login", body, resp); err != nil {
+		return fmt.Errorf("could not log into vault with the kubernetes auth method: %s", err)
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return errors.New("could not log into vault with the kubernetes auth method: no token returned")
+	}
+	b.setToken(resp.Auth.ClientToken, resp.Auth.LeaseDuration, resp.Auth.Renewable)
+	return nil
+}
+
+func (b *vaultBackend) setToken(token string, leaseDuration int, renewable bool) {
+	b.token = token
+	b.tokenRenewable = renewable
+	b.tokenDuration = time.Duration(leaseDuration) * time.Second
+	b.tokenRenewAt = time.Time{}
+	if leaseDuration > 0 {
+		b.tokenRenewAt = renewalTime(b.now(), b.tokenDuration)
+	}
+}
+
+func (b *vaultBackend) nextRenewal() (time.Time, bool) {
+	next := b.tokenRenewAt
+	for _, lease := range b.leases {
+		if next.IsZero() || lease.renewAt.Before(next) {
+			next = lease.renewAt
+		}
+	}
+	return next, !next.IsZero()
+}
+
+func (b *vaultBackend) renewLeases(ctx context.Context) []string {
+	now := b.now()
+	if !b.tokenRenewAt.IsZero() && !now.Before(b.tokenRenewAt) {
+		b.renewToken(ctx)
+	}
+
+	var expired []string
+	for path, lease := range b.leases {
+		if now.Before(lease.renewAt) {
+			continue
+		}
+		if err := b.renewLease(ctx, lease); err != nil {
+			log.Infof("The lease of vault secret '%s' cannot be renewed, reading it again: %s", path, err)
+			expired = append(expired, lease.handles...)
+			delete(b.leases, path)
+		}
+	}
+	sort.Strings(expired)
+	return expired
+}
+
+// renewToken renews the token or logs in again, the token of the token auth method expires if it cannot be renewed
+func (b *vaultBackend) renewToken(ctx context.Context) {
+	if b.tokenRenewable {
+		resp := &vaultResponse{}
+		err := b.request(ctx, http.MethodPost, "/v1/auth/token/renew-self", nil, resp)
+		if err == nil && resp.Auth != nil && time.Duration(resp.Auth.LeaseDuration)*time.Second >= b.tokenDuration/2 {
+			b.setToken(b.token, resp.Auth.LeaseDuration, resp.Auth.Renewable)
+			return
+		}
+		if err != nil {
+			log.Warnf("Could not renew the vault token: %s", err)
+		}
+	}
+
+	if b.config.AuthMethod == vaultAuthToken {
+		log.Warnf("The vault token cannot be renewed anymore and will expire")
+		b.tokenRenewAt = time.Time{}
+		return
+	}
+	if err := b.login(ctx); err != nil {
+		log.Errorf("Could not renew the vault token: %s", err)
+		b.tokenRenewAt = renewalTime(b.now(), b.tokenDuration/3)
+	}
+}
+
+// renewLease extends the lease of a dynamic secret. The lease is considered expired when Vault does not
+// extend it for at least half of its duration, its maximum TTL being reached.
+func (b *vaultBackend) renewLease(ctx context.Context, lease *vaultLease) error {
+	if !lease.renewable {
+		return errors.New("the lease is not renewable")
+	}
+
+	resp := &vaultResponse{}
+	body := map[string]interface{}{
+		"lease_id":  lease.id,
+		"increment": int(lease.duration.Seconds()),
+	}
+	if err := b.request(ctx, http.MethodPut, "/v1/sys/leases/renew", body, resp); err != nil {
+		return err
+	}
+
+	renewed := time.Duration(resp.LeaseDuration) * time.Second
+	if renewed < lease.duration/2 {
+		return fmt.Errorf("the lease was only extended by %s", renewed)
+	}
+	lease.renewable = resp.Renewable
+	lease.renewAt = renewalTime(b.now(), renewed)
+	return nil
+}
+
+func (b *vaultBackend) request(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, b.config.Address+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if b.token != "" {
+		req.Header.Set("X-Vault-Token", b.token)
+	}
+	if b.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", b.config.Namespace)
+	}
+	return doJSONRequest(b.client, req, out)
+}
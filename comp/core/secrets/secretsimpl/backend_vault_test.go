@@ -0,0 +1,176 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package secretsimpl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVault serves the Vault endpoints used by the backend
+type fakeVault struct {
+	t            *testing.T
+	logins       int
+	renewals     int
+	leaseExtends int
+}
+
+func (f *fakeVault) handler(w http.ResponseWriter, r *http.Request) {
+	write := func(v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(f.t, json.NewEncoder(w).Encode(v))
+	}
+
+	if r.URL.Path != "/v1/auth/kubernetes/login" && r.Header.Get("X-Vault-Token") != "s.token" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v1/auth/kubernetes/login":
+		var body map[string]string
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(f.t, "datadog", body["role"])
+		assert.Equal(f.t, "service-account-jwt", body["jwt"])
+		f.logins++
+		write(map[string]interface{}{"auth": map[string]interface{}{"client_token": "s.token", "lease_duration": 3600, "renewable": true}})
+	case "/v1/secret/data/datadog":
+		write(map[string]interface{}{"data": map[string]interface{}{
+			"data":     map[string]interface{}{"api_key": "123456", "app_key": "abcdef"},
+			"metadata": map[string]interface{}{"version": 1},
+		}})
+	case "/v1/database/creds/agent":
+		write(map[string]interface{}{
+			"lease_id":       "database/creds/agent/lease",
+			"lease_duration": 600,
+			"renewable":      true,
+			"data":           map[string]interface{}{"username": "agent", "password": "secret"},
+		})
+	case "/v1/auth/token/renew-self":
+		f.renewals++
+		write(map[string]interface{}{"auth": map[string]interface{}{"client_token": "s.token", "lease_duration": 3600, "renewable": true}})
+	case "/v1/sys/leases/renew":
+		f.leaseExtends++
+		// the lease reaches its maximum TTL at the second renewal
+		duration := 600
+		if f.leaseExtends > 1 {
+			duration = 60
+		}
+		write(map[string]interface{}{"lease_id": "database/creds/agent/lease", "lease_duration": duration, "renewable": true})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestVaultBackend(t *testing.T) (*vaultBackend, *fakeVault) {
+	fake := &fakeVault{t: t}
+	server := httptest.NewServer(http.HandlerFunc(fake.handler))
+	t.Cleanup(server.Close)
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("service-account-jwt\n"), 0600))
+
+	b, err := newVaultBackend(map[string]interface{}{
+		"address":               server.URL,
+		"kubernetes_role":       "datadog",
+		"kubernetes_token_path": tokenPath,
+	}, server.Client())
+	require.NoError(t, err)
+	return b, fake
+}
+
+func TestNewVaultBackendErrors(t *testing.T) {
+	t.Setenv("VAULT_ADDR", "")
+	t.Setenv("VAULT_TOKEN", "")
+
+	_, err := newVaultBackend(map[string]interface{}{}, http.DefaultClient)
+	assert.EqualError(t, err, "the vault secret backend requires an address")
+
+	_, err = newVaultBackend(map[string]interface{}{"address": "http://vault"}, http.DefaultClient)
+	assert.EqualError(t, err, "the kubernetes auth method of the vault secret backend requires a kubernetes_role")
+
+	_, err = newVaultBackend(map[string]interface{}{"address": "http://vault", "auth_method": "token"}, http.DefaultClient)
+	assert.EqualError(t, err, "the token auth method of the vault secret backend requires a token")
+
+	_, err = newVaultBackend(map[string]interface{}{"address": "http://vault", "auth_method": "ldap"}, http.DefaultClient)
+	assert.EqualError(t, err, "unknown vault auth_method 'ldap', supported methods are 'kubernetes' and 'token'")
+}
+
+func TestVaultBackendFetchSecrets(t *testing.T) {
+	b, fake := newTestVaultBackend(t)
+
+	secrets, err := b.fetchSecrets(context.Background(), []string{"secret/data/datadog;api_key", "secret/data/datadog;app_key", "database/creds/agent;password"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"secret/data/datadog;api_key":   "123456",
+		"secret/data/datadog;app_key":   "abcdef",
+		"database/creds/agent;password": "secret",
+	}, secrets)
+	assert.Equal(t, 1, fake.logins)
+
+	_, err = b.fetchSecrets(context.Background(), []string{"secret/data/datadog"})
+	assert.EqualError(t, err, "vault secret handle 'secret/data/datadog' must be the path of the secret and the key to read separated by ';'")
+
+	_, err = b.fetchSecrets(context.Background(), []string{"secret/data/datadog;unknown"})
+	assert.EqualError(t, err, "secret 'secret/data/datadog;unknown' has no key 'unknown'")
+
+	_, err = b.fetchSecrets(context.Background(), []string{"secret/data/missing;key"})
+	assert.ErrorContains(t, err, "could not read vault secret 'secret/data/missing': unexpected status code 404")
+}
+
+func TestVaultBackendLoginAgainWhenTokenRevoked(t *testing.T) {
+	b, fake := newTestVaultBackend(t)
+	b.token = "s.revoked"
+
+	secrets, err := b.fetchSecrets(context.Background(), []string{"secret/data/datadog;api_key"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"secret/data/datadog;api_key": "123456"}, secrets)
+	assert.Equal(t, 1, fake.logins)
+}
+
+func TestVaultBackendRenewLeases(t *testing.T) {
+	b, fake := newTestVaultBackend(t)
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	_, err := b.fetchSecrets(context.Background(), []string{"database/creds/agent;username", "database/creds/agent;password"})
+	require.NoError(t, err)
+
+	// the lease of the secret is renewed first, after two thirds of its duration
+	next, ok := b.nextRenewal()
+	require.True(t, ok)
+	assert.Equal(t, now.Add(400*time.Second), next)
+
+	now = next
+	assert.Empty(t, b.renewLeases(context.Background()))
+	assert.Equal(t, 1, fake.leaseExtends)
+	assert.Equal(t, 0, fake.renewals)
+
+	// the lease is not extended enough anymore, the secret must be read again
+	now = now.Add(400 * time.Second)
+	assert.Equal(t, []string{"database/creds/agent;password", "database/creds/agent;username"}, b.renewLeases(context.Background()))
+	assert.Equal(t, 2, fake.leaseExtends)
+
+	// only the token remains to be renewed
+	next, ok = b.nextRenewal()
+	require.True(t, ok)
+	assert.Equal(t, b.tokenRenewAt, next)
+
+	now = next
+	assert.Empty(t, b.renewLeases(context.Background()))
+	assert.Equal(t, 1, fake.renewals)
+	assert.Equal(t, 1, fake.logins)
+	assert.Equal(t, now.Add(40*time.Minute), b.tokenRenewAt)
+}
@@ -150,3 +150,107 @@ func (r *secretResolver) fetchSecret(secretsHandle []string) (map[string]string,
 	}
 	return res, nil
 }
+
+// fetch returns the value of the handles from the native secret backend if one is configured, or from the
+// secret_backend_command
+func (r *secretResolver) fetch(handles []string) (map[string]string, error) {
+	if r.fetchHookFunc != nil {
+		// hook used only for tests
+		return r.fetchHookFunc(handles)
+	}
+	if r.backend != nil {
+		return r.fetchFromBackend(handles)
+	}
+	return r.fetchSecret(handles)
+}
+
+// fetchFromBackend fetches the secrets from the native secret backend
+func (r *secretResolver) fetchFromBackend(handles []string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(r.backendTimeout)*time.Second)
+	defer cancel()
+
+	log.Debugf("%s | fetching %d secrets from the '%s' secret backend", time.Now().String(), len(handles), r.backendType)
+	start := time.Now()
+	values, err := r.backend.fetchSecrets(ctx, handles)
+	elapsed := time.Since(start)
+	if err != nil {
+		r.tlmSecretBackendElapsed.Add(float64(elapsed.Milliseconds()), r.backendType, "error")
+		return nil, fmt.Errorf("error while fetching secrets from the '%s' secret backend: %s", r.backendType, err)
+	}
+	r.tlmSecretBackendElapsed.Add(float64(elapsed.Milliseconds()), r.backendType, "0")
+
+	res := map[string]string{}
+	for _, sec := range handles {
+		v, ok := values[sec]
+		if !ok {
+			r.tlmSecretResolveError.Inc("missing", sec)
+			return nil, fmt.Errorf("secret handle '%s' was not resolved by the '%s' secret backend", sec, r.backendType)
+		}
+
+		if r.removeTrailingLinebreak {
+			v = strings.TrimRight(v, "\r\n")
+		}
+
+		if v == "" {
+			r.tlmSecretResolveError.Inc("empty", sec)
+			return nil, fmt.Errorf("resolved secret for '%s' is empty", sec)
+		}
+		res[sec] = v
+	}
+
+	r.scheduleLeaseRenewal()
+	return res, nil
+}
+
+// scheduleLeaseRenewal schedules the renewal of the leases of the native secret backend, if it has any, or
+// the next attempt to fetch the secrets whose lease expired
+func (r *secretResolver) scheduleLeaseRenewal() {
+	renewer, ok := r.backend.(leaseRenewer)
+	if !ok {
+		return
+	}
+	next, ok := renewer.nextRenewal()
+	if len(r.expiredHandles) > 0 {
+		retry := time.Now().Add(leaseRetryInterval)
+		if !ok || retry.Before(next) {
+			next, ok = retry, true
+		}
+	}
+	if !ok {
+		return
+	}
+
+	if r.leaseTimer != nil {
+		r.leaseTimer.Stop()
+	}
+	r.leaseTimer = time.AfterFunc(time.Until(next), r.renewLeases)
+}
+
+// renewLeases renews the leases of the native secret backend and fetches again the secrets whose lease
+// could not be renewed, subscribers are notified of their new value
+func (r *secretResolver) renewLeases() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	renewer, ok := r.backend.(leaseRenewer)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(r.backendTimeout)*time.Second)
+	expired := append(r.expiredHandles, renewer.renewLeases(ctx)...)
+	cancel()
+
+	r.expiredHandles = nil
+	if len(expired) > 0 {
+		log.Infof("Fetching again %d secrets whose lease expired", len(expired))
+		secretResponse, err := r.fetchFromBackend(expired)
+		if err != nil {
+			log.Errorf("Could not fetch the secrets whose lease expired, retrying in %s: %s", leaseRetryInterval, err)
+			r.expiredHandles = expired
+		} else {
+			r.processSecretResponse(secretResponse, true)
+		}
+	}
+	r.scheduleLeaseRenewal()
+}
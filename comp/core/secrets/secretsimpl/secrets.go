@@ -87,6 +87,13 @@ type secretResolver struct {
 	removeTrailingLinebreak bool
 	// responseMaxSize defines max size of the JSON output from a secrets reader backend
 	responseMaxSize int
+	// native secret backend used instead of the backendCommand, backendErr is set if it could not be configured
+	backendType string
+	backend     secretBackend
+	backendErr  error
+	leaseTimer  *time.Timer
+	// handles whose lease expired and that could not be fetched again
+	expiredHandles []string
 	// refresh secrets at a regular interval
 	refreshInterval time.Duration
 	ticker          *time.Ticker
@@ -96,6 +103,8 @@ type secretResolver struct {
 	auditRotRecs     *rotatingNDRecords
 	// subscriptions want to be notified about changes to the secrets
 	subscriptions []secrets.SecretChangeCallback
+	// refreshSubscriptions want to be notified about refreshed secrets, whatever the setting using them
+	refreshSubscriptions []secrets.SecretChangeCallback
 
 	// can be overridden for testing purposes
 	commandHookFunc func(string) ([]byte, error)
@@ -221,6 +230,15 @@ func (r *secretResolver) Configure(params secrets.ConfigParams) {
 	if r.commandAllowGroupExec {
 		log.Warnf("Agent configuration relax permissions constraint on the secret backend cmd, Group can read and exec")
 	}
+	if params.BackendType != "" {
+		r.backendType = params.BackendType
+		r.backend, r.backendErr = newSecretBackend(params.BackendType, params.BackendConfig, time.Duration(r.backendTimeout)*time.Second)
+		if r.backendErr != nil {
+			log.Errorf("Could not configure the '%s' secret backend: %s", params.BackendType, r.backendErr)
+		} else if r.backendCommand != "" {
+			log.Warnf("Both secret_backend_command and secret_backend_type are set, the '%s' secret backend is used", params.BackendType)
+		}
+	}
 	r.auditFilename = filepath.Join(params.RunPath, auditFileBasename)
 	r.auditFileMaxSize = params.AuditFileMaxSize
 	if r.auditFileMaxSize == 0 {
@@ -261,8 +279,18 @@ func (r *secretResolver) SubscribeToChanges(cb secrets.SecretChangeCallback) {
 	r.subscriptions = append(r.subscriptions, cb)
 }
 
-// Resolve replaces all encoded secrets in data by executing "secret_backend_command" once if all secrets aren't
-// present in the cache.
+// SubscribeToRefresh adds this callback to the list that get notified when refreshed secrets change, the
+// allowlist does not apply to these subscriptions as they reload the configurations embedding the secrets
+func (r *secretResolver) SubscribeToRefresh(cb secrets.SecretChangeCallback) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.startRefreshRoutine()
+	r.refreshSubscriptions = append(r.refreshSubscriptions, cb)
+}
+
+// Resolve replaces all encoded secrets in data by executing "secret_backend_command", or querying the native
+// secret backend, once if all secrets aren't present in the cache.
 func (r *secretResolver) Resolve(data []byte, origin string) ([]byte, error) {
 	r.lock.Lock()
 	defer r.lock.Unlock()
@@ -271,9 +299,12 @@ func (r *secretResolver) Resolve(data []byte, origin string) ([]byte, error) {
 		log.Infof("Agent secrets is disabled by caller")
 		return nil, nil
 	}
-	if data == nil || r.backendCommand == "" {
+	if data == nil || (r.backendCommand == "" && r.backendType == "") {
 		return data, nil
 	}
+	if r.backendErr != nil {
+		return nil, r.backendErr
+	}
 
 	var config interface{}
 	err := yaml.Unmarshal(data, &config)
@@ -322,14 +353,7 @@ func (r *secretResolver) Resolve(data []byte, origin string) ([]byte, error) {
 
 	// check if any new secrets need to be fetch
 	if len(newHandles) != 0 {
-		var secretResponse map[string]string
-		var err error
-		if r.fetchHookFunc != nil {
-			// hook used only for tests
-			secretResponse, err = r.fetchHookFunc(newHandles)
-		} else {
-			secretResponse, err = r.fetchSecret(newHandles)
-		}
+		secretResponse, err := r.fetch(newHandles)
 		if err != nil {
 			return nil, err
 		}
@@ -416,7 +440,8 @@ func (r *secretResolver) matchesAllowlist(handle string) bool {
 
 // for all secrets returned by the backend command, notify subscribers (if allowlist lets them),
 // and return the handles that have received new values compared to what was in the cache,
-// and where those handles appear
+// and where those handles appear. The allowlist is only used when refreshing secrets, in which
+// case the refresh subscriptions are notified as well.
 func (r *secretResolver) processSecretResponse(secretResponse map[string]string, useAllowlist bool) secretRefreshInfo {
 	var handleInfoList []handleInfo
 
@@ -428,8 +453,9 @@ func (r *secretResolver) processSecretResponse(secretResponse map[string]string,
 			continue
 		}
 
-		// if allowlist is enabled and the config setting path is not contained in it, skip it
-		if useAllowlist && !r.matchesAllowlist(handle) {
+		// if allowlist is enabled and the config setting path is not contained in it, skip it unless
+		// the configurations embedding the secret are reloaded by a refresh subscription
+		if useAllowlist && !r.matchesAllowlist(handle) && len(r.refreshSubscriptions) == 0 {
 			continue
 		}
 
@@ -437,6 +463,7 @@ func (r *secretResolver) processSecretResponse(secretResponse map[string]string,
 
 		places := make([]handlePlace, 0, len(r.origin[handle]))
 		for _, secretCtx := range r.origin[handle] {
+			secretPath := strings.Join(secretCtx.path, "/")
 			for _, sub := range r.subscriptions {
 				if useAllowlist && !secretMatchesAllowlist(secretCtx) {
 					// only update setting paths that match the allowlist
@@ -444,7 +471,13 @@ func (r *secretResolver) processSecretResponse(secretResponse map[string]string,
 				}
 				// notify subscribers that secret has changed
 				sub(handle, secretCtx.origin, secretCtx.path, oldValue, secretValue)
-				secretPath := strings.Join(secretCtx.path, "/")
+				places = append(places, handlePlace{Context: secretCtx.origin, Path: secretPath})
+			}
+			if !useAllowlist {
+				continue
+			}
+			for _, sub := range r.refreshSubscriptions {
+				sub(handle, secretCtx.origin, secretCtx.path, oldValue, secretValue)
 				places = append(places, handlePlace{Context: secretCtx.origin, Path: secretPath})
 			}
 		}
@@ -466,9 +499,10 @@ func (r *secretResolver) Refresh() (string, error) {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
-	// get handles from the cache that match the allowlist
+	// get handles from the cache that match the allowlist, all of them are refreshed when the
+	// configurations embedding them are reloaded by a refresh subscription
 	newHandles := maps.Keys(r.cache)
-	if allowlistEnabled {
+	if allowlistEnabled && len(r.refreshSubscriptions) == 0 {
 		filteredHandles := make([]string, 0, len(newHandles))
 		for _, handle := range newHandles {
 			if r.matchesAllowlist(handle) {
@@ -483,14 +517,7 @@ func (r *secretResolver) Refresh() (string, error) {
 
 	log.Infof("Refreshing secrets for %d handles", len(newHandles))
 
-	var secretResponse map[string]string
-	var err error
-	if r.fetchHookFunc != nil {
-		// hook used only for tests
-		secretResponse, err = r.fetchHookFunc(newHandles)
-	} else {
-		secretResponse, err = r.fetchSecret(newHandles)
-	}
+	secretResponse, err := r.fetch(newHandles)
 	if err != nil {
 		return "", err
 	}
@@ -579,6 +606,12 @@ type secretInfo struct {
 	Handles                      map[string][][]string
 }
 
+type secretBackendInfo struct {
+	BackendType  string
+	BackendError string
+	Handles      map[string][][]string
+}
+
 type secretRefreshInfo struct {
 	Handles []handleInfo
 }
@@ -596,6 +629,9 @@ type handlePlace struct {
 //go:embed info.tmpl
 var secretInfoTmpl string
 
+//go:embed backend_info.tmpl
+var secretBackendInfoTmpl string
+
 //go:embed refresh.tmpl
 var secretRefreshTmpl string
 
@@ -605,10 +641,14 @@ func (r *secretResolver) GetDebugInfo(w io.Writer) {
 		fmt.Fprintf(w, "Agent secrets is disabled by caller")
 		return
 	}
-	if r.backendCommand == "" {
+	if r.backendCommand == "" && r.backendType == "" {
 		fmt.Fprintf(w, "No secret_backend_command set: secrets feature is not enabled")
 		return
 	}
+	if r.backend != nil || r.backendErr != nil {
+		r.getBackendDebugInfo(w)
+		return
+	}
 
 	t := template.New("secret_info")
 	t, err := t.Parse(secretInfoTmpl)
@@ -641,24 +681,46 @@ func (r *secretResolver) GetDebugInfo(w io.Writer) {
 		info.ExecutablePermissionsError = err.Error()
 	}
 
-	// we sort handles so the output is consistent and testable
-	orderedHandles := []string{}
-	for handle := range r.origin {
-		orderedHandles = append(orderedHandles, handle)
+	info.Handles = r.handlesOrigin()
+
+	err = t.Execute(w, info)
+	if err != nil {
+		fmt.Fprintf(w, "error rendering secret info: %s", err)
 	}
-	sort.Strings(orderedHandles)
+}
 
-	for _, handle := range orderedHandles {
-		contexts := r.origin[handle]
-		details := [][]string{}
-		for _, context := range contexts {
-			details = append(details, []string{context.origin, strings.Join(context.path, "/")})
-		}
-		info.Handles[handle] = details
+// getBackendDebugInfo exposes debug informations about the native secret backend
+func (r *secretResolver) getBackendDebugInfo(w io.Writer) {
+	t := template.New("secret_backend_info")
+	t, err := t.Parse(secretBackendInfoTmpl)
+	if err != nil {
+		fmt.Fprintf(w, "error parsing secret backend info template: %s", err)
+		return
+	}
+
+	info := secretBackendInfo{
+		BackendType: r.backendType,
+		Handles:     r.handlesOrigin(),
+	}
+	if r.backendErr != nil {
+		info.BackendError = r.backendErr.Error()
 	}
 
 	err = t.Execute(w, info)
 	if err != nil {
-		fmt.Fprintf(w, "error rendering secret info: %s", err)
+		fmt.Fprintf(w, "error rendering secret backend info: %s", err)
+	}
+}
+
+// handlesOrigin returns the places where each handle was found
+func (r *secretResolver) handlesOrigin() map[string][][]string {
+	handles := map[string][][]string{}
+	for handle, contexts := range r.origin {
+		details := [][]string{}
+		for _, context := range contexts {
+			details = append(details, []string{context.origin, strings.Join(context.path, "/")})
+		}
+		handles[handle] = details
 	}
+	return handles
 }
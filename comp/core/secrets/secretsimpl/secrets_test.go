@@ -6,16 +6,20 @@
 package secretsimpl
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"slices"
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
 	"github.com/DataDog/datadog-agent/comp/core/telemetry"
 	nooptelemetry "github.com/DataDog/datadog-agent/comp/core/telemetry/noopsimpl"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
@@ -748,6 +752,66 @@ func TestRefreshAllowlistAppliesToEachSettingPath(t *testing.T) {
 	assert.Equal(t, changedPaths, []string{"instances/0/password"})
 }
 
+// test that the refresh subscriptions are notified of every refreshed secret, whatever the allowlist
+func TestRefreshNotifiesRefreshSubscriptions(t *testing.T) {
+	tel := fxutil.Test[telemetry.Component](t, nooptelemetry.Module())
+	resolver := newEnabledSecretResolver(tel)
+	resolver.backendCommand = "some_command"
+	resolver.cache = map[string]string{"api_key": "key", "db_password": "password"}
+	resolver.origin = handleToContext{
+		"api_key": []secretContext{
+			{
+				origin: "datadog.yaml",
+				path:   []string{"api_key"},
+			},
+		},
+		"db_password": []secretContext{
+			{
+				origin: "postgres",
+				path:   []string{"password"},
+			},
+		},
+	}
+
+	fetched := []string{}
+	resolver.fetchHookFunc = func(handles []string) (map[string]string, error) {
+		fetched = append(fetched, handles...)
+		return map[string]string{
+			"api_key":     "rotated_key",
+			"db_password": "rotated_password",
+		}, nil
+	}
+
+	changes := []string{}
+	resolver.SubscribeToChanges(func(handle, _ string, _ []string, _, _ any) {
+		changes = append(changes, handle)
+	})
+	refreshed := []string{}
+	resolver.SubscribeToRefresh(func(handle, origin string, _ []string, oldValue, newValue any) {
+		refreshed = append(refreshed, fmt.Sprintf("%s/%s: %s -> %s", origin, handle, oldValue, newValue))
+	})
+
+	_, err := resolver.Refresh()
+	require.NoError(t, err)
+	sort.Strings(fetched)
+	sort.Strings(refreshed)
+	assert.Equal(t, []string{"api_key", "db_password"}, fetched)
+	// the subscriptions are still restricted by the allowlist
+	assert.Equal(t, []string{"api_key"}, changes)
+	assert.Equal(t, []string{
+		"datadog.yaml/api_key: key -> rotated_key",
+		"postgres/db_password: password -> rotated_password",
+	}, refreshed)
+
+	// the refresh subscriptions are not notified of resolved secrets
+	resolver.fetchHookFunc = func([]string) (map[string]string, error) {
+		return map[string]string{"pass1": "password1"}, nil
+	}
+	_, err = resolver.Resolve(testSimpleConf, "test")
+	require.NoError(t, err)
+	assert.Len(t, refreshed, 2)
+}
+
 // test that adding to the audit file stops working when the file gets too large
 func TestRefreshAddsToAuditFile(t *testing.T) {
 	tmpfile, err := os.CreateTemp("", "")
@@ -882,3 +946,159 @@ func TestIsLikelyAPIOrAppKey(t *testing.T) {
 		})
 	}
 }
+
+// fakeSecretBackend is a native secret backend whose secrets and leases are set by the tests
+type fakeSecretBackend struct {
+	secrets  map[string]string
+	fetched  [][]string
+	renewAt  time.Time
+	expiring []string
+}
+
+func (b *fakeSecretBackend) fetchSecrets(_ context.Context, handles []string) (map[string]string, error) {
+	b.fetched = append(b.fetched, handles)
+	res := map[string]string{}
+	for _, handle := range handles {
+		if value, ok := b.secrets[handle]; ok {
+			res[handle] = value
+		}
+	}
+	return res, nil
+}
+
+func (b *fakeSecretBackend) nextRenewal() (time.Time, bool) {
+	return b.renewAt, !b.renewAt.IsZero()
+}
+
+func (b *fakeSecretBackend) renewLeases(_ context.Context) []string {
+	expired := b.expiring
+	b.expiring = nil
+	b.renewAt = time.Time{}
+	return expired
+}
+
+func TestResolveWithNativeBackend(t *testing.T) {
+	tel := fxutil.Test[telemetry.Component](t, nooptelemetry.Module())
+	resolver := newEnabledSecretResolver(tel)
+	backend := &fakeSecretBackend{secrets: map[string]string{"pass1": "password1", "pass2": "password2", "pass3": "password3\n"}}
+	resolver.backendType = "fake"
+	resolver.backend = backend
+	resolver.backendTimeout = 5
+	resolver.removeTrailingLinebreak = true
+
+	resolved, err := resolver.Resolve(testConfNestedMultiple, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "some:\n  encoded:\n    third_level: password3\n  second_level: password2\ntop_level: password1\n", string(resolved))
+	require.Len(t, backend.fetched, 1)
+	assert.ElementsMatch(t, []string{"pass1", "pass2", "pass3"}, backend.fetched[0])
+
+	_, err = resolver.Resolve([]byte("key: ENC[unknown]"), "test")
+	assert.EqualError(t, err, "secret handle 'unknown' was not resolved by the 'fake' secret backend")
+}
+
+func TestResolveNativeBackendConfigError(t *testing.T) {
+	tel := fxutil.Test[telemetry.Component](t, nooptelemetry.Module())
+	resolver := newEnabledSecretResolver(tel)
+	resolver.Configure(secrets.ConfigParams{BackendType: "unknown"})
+
+	_, err := resolver.Resolve(testSimpleConf, "test")
+	assert.EqualError(t, err, "unknown secret_backend_type 'unknown', supported types are 'vault', 'aws.secrets_manager' and 'gcp.secret_manager'")
+
+	var buffer bytes.Buffer
+	resolver.GetDebugInfo(&buffer)
+	assert.Contains(t, buffer.String(), "Backend type: unknown\nBackend error: unknown secret_backend_type 'unknown'")
+}
+
+func TestRenewLeasesFetchesExpiredSecrets(t *testing.T) {
+	// disable the allowlist for the test, let any secret changes happen
+	allowlistEnabled = false
+	defer func() { allowlistEnabled = true }()
+
+	tel := fxutil.Test[telemetry.Component](t, nooptelemetry.Module())
+	resolver := newEnabledSecretResolver(tel)
+	backend := &fakeSecretBackend{
+		secrets: map[string]string{"pass1": "password1"},
+		renewAt: time.Now().Add(time.Hour),
+	}
+	resolver.backendType = "fake"
+	resolver.backend = backend
+	resolver.backendTimeout = 5
+
+	changes := map[string]any{}
+	resolver.SubscribeToChanges(func(handle, _ string, _ []string, _, newValue any) {
+		changes[handle] = newValue
+	})
+
+	_, err := resolver.Resolve(testSimpleConf, "test")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"pass1": "password1"}, changes)
+	require.NotNil(t, resolver.leaseTimer)
+	resolver.leaseTimer.Stop()
+
+	// the lease of the secret expired, it is fetched again and the subscribers are notified of its new value
+	backend.secrets["pass1"] = "password1_rotated"
+	backend.expiring = []string{"pass1"}
+	resolver.renewLeases()
+
+	assert.Equal(t, [][]string{{"pass1"}, {"pass1"}}, backend.fetched)
+	assert.Equal(t, map[string]any{"pass1": "password1_rotated"}, changes)
+	assert.Equal(t, "password1_rotated", resolver.cache["pass1"])
+	assert.Empty(t, resolver.expiredHandles)
+}
+
+// fakeRegisteredBackend is a registered backend returning the secrets by ID
+type fakeRegisteredBackend struct {
+	secrets map[string]string
+	fetched []string
+}
+
+func (b *fakeRegisteredBackend) FetchSecret(_ context.Context, id string) (string, error) {
+	b.fetched = append(b.fetched, id)
+	secret, ok := b.secrets[id]
+	if !ok {
+		return "", fmt.Errorf("secret '%s' not found", id)
+	}
+	return secret, nil
+}
+
+func TestResolveWithRegisteredBackend(t *testing.T) {
+	tel := fxutil.Test[telemetry.Component](t, nooptelemetry.Module())
+
+	resolver := newEnabledSecretResolver(tel)
+	resolver.Configure(secrets.ConfigParams{BackendType: backendTypeAWSSecretsManager})
+	_, err := resolver.Resolve(testSimpleConf, "test")
+	assert.EqualError(t, err, "the 'aws.secrets_manager' secret backend is not supported by this binary")
+
+	backend := &fakeRegisteredBackend{secrets: map[string]string{
+		"datadog": `{"api_key": "123456", "app_key": "abcdef"}`,
+		"plain":   "password",
+	}}
+	RegisterBackend(backendTypeAWSSecretsManager, func(config map[string]interface{}, _ time.Duration) (Backend, error) {
+		assert.Equal(t, map[string]interface{}{"region": "us-east-1"}, config)
+		return backend, nil
+	})
+	t.Cleanup(func() {
+		registeredBackendsMutex.Lock()
+		defer registeredBackendsMutex.Unlock()
+		delete(registeredBackends, backendTypeAWSSecretsManager)
+	})
+
+	b, err := newSecretBackend(backendTypeAWSSecretsManager, map[string]interface{}{"region": "us-east-1"}, time.Second)
+	require.NoError(t, err)
+
+	resolved, err := b.fetchSecrets(context.Background(), []string{"datadog;api_key", "datadog;app_key", "plain"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"datadog;api_key": "123456",
+		"datadog;app_key": "abcdef",
+		"plain":           "password",
+	}, resolved)
+	// the secret holding several keys is only read once
+	assert.Equal(t, []string{"datadog", "plain"}, backend.fetched)
+
+	_, err = b.fetchSecrets(context.Background(), []string{"plain;key"})
+	assert.EqualError(t, err, "secret 'plain;key' is not a JSON object, the key 'key' cannot be extracted")
+
+	_, err = b.fetchSecrets(context.Background(), []string{"missing"})
+	assert.EqualError(t, err, "secret 'missing' not found")
+}
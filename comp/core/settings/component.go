@@ -9,6 +9,7 @@ package settings
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/DataDog/datadog-agent/comp/core/config"
 	"github.com/DataDog/datadog-agent/pkg/config/model"
@@ -26,12 +27,46 @@ func (e *SettingNotFoundError) Error() string {
 	return fmt.Sprintf("setting %s not found", e.Name)
 }
 
+// SettingValidationError is used to report a value rejected by a runtime setting
+type SettingValidationError struct {
+	Name string
+	Err  error
+}
+
+func (e *SettingValidationError) Error() string {
+	return fmt.Sprintf("invalid value for setting %s: %s", e.Name, e.Err)
+}
+
+func (e *SettingValidationError) Unwrap() error {
+	return e.Err
+}
+
 // RuntimeSettingResponse is used to communicate settings config
 type RuntimeSettingResponse struct {
 	Description string
 	Hidden      bool
 }
 
+// AppliedOverride describes a runtime setting value applied at runtime
+type AppliedOverride struct {
+	Setting        string
+	PreviousValue  interface{}
+	PreviousSource model.Source
+	Value          interface{}
+	Source         model.Source
+	AppliedAt      time.Time
+}
+
+// SettingProvenance describes the effective value of a configuration setting and the source it comes from
+type SettingProvenance struct {
+	Setting string
+	Value   interface{}
+	Source  model.Source
+	Default interface{}
+	// Sources contains the value of the setting in each source setting it, from the lowest to the highest priority
+	Sources []model.ValueWithSource
+}
+
 // Params that the settings component need
 type Params struct {
 	// Settings define the runtime settings the component would understand
@@ -51,6 +86,22 @@ type Component interface {
 	// SetRuntimeSetting changes the value of a runtime configurable setting
 	SetRuntimeSetting(setting string, value interface{}, source model.Source) error
 
+	// StageRuntimeSettings validates and stages a set of runtime setting values, without applying them.
+	// Staged values are merged with the values staged previously and not committed yet.
+	StageRuntimeSettings(values map[string]interface{}) error
+	// StagedRuntimeSettings returns the staged values waiting to be committed
+	StagedRuntimeSettings() map[string]interface{}
+	// CommitRuntimeSettings atomically applies all the staged values: if one of them fails to be applied,
+	// the settings already changed are restored to their previous value.
+	CommitRuntimeSettings(source model.Source) ([]AppliedOverride, error)
+	// RollbackRuntimeSettings discards all the staged values
+	RollbackRuntimeSettings()
+	// AppliedOverrides returns the last value applied at runtime for each setting, sorted by setting name
+	AppliedOverrides() []AppliedOverride
+	// ConfigProvenance returns the effective value and the source of each configuration setting, sorted by setting
+	// name. When onlyChanged is true, the settings left to their default value are omitted.
+	ConfigProvenance(onlyChanged bool) []SettingProvenance
+
 	// API related functions
 	// Todo: (Components) Remove these functions once we can register routes using FX value groups
 
@@ -64,6 +115,18 @@ type Component interface {
 	SetValue(w http.ResponseWriter, r *http.Request)
 	// ListConfigurable returns the list of configurable setting at runtime
 	ListConfigurable(w http.ResponseWriter, r *http.Request)
+	// GetStagedValues returns the staged runtime setting values waiting to be committed
+	GetStagedValues(w http.ResponseWriter, r *http.Request)
+	// StageValues validates and stages runtime setting values
+	StageValues(w http.ResponseWriter, r *http.Request)
+	// CommitValues atomically applies the staged runtime setting values
+	CommitValues(w http.ResponseWriter, r *http.Request)
+	// RollbackValues discards the staged runtime setting values
+	RollbackValues(w http.ResponseWriter, r *http.Request)
+	// ListOverrides returns the runtime setting values applied at runtime
+	ListOverrides(w http.ResponseWriter, r *http.Request)
+	// GetConfigProvenance returns the effective value and the source of each configuration setting
+	GetConfigProvenance(w http.ResponseWriter, r *http.Request)
 }
 
 // RuntimeSetting represents a setting that can be changed and read at runtime.
@@ -74,6 +137,12 @@ type RuntimeSetting interface {
 	Hidden() bool
 }
 
+// RuntimeSettingValidator can be implemented by a RuntimeSetting to validate a value
+// before it is staged, so that invalid values are rejected before any setting is changed.
+type RuntimeSettingValidator interface {
+	Validate(v interface{}) error
+}
+
 // RuntimeSettingProvider stores the Provider instance
 type RuntimeSettingProvider struct {
 	fx.Out
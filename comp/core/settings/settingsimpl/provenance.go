@@ -0,0 +1,70 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package settingsimpl
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mohae/deepcopy"
+
+	"github.com/DataDog/datadog-agent/comp/core/settings"
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+	"github.com/DataDog/datadog-agent/pkg/util/scrubber"
+)
+
+// ConfigProvenance returns the effective value and the source of each configuration setting, sorted by setting name.
+// The values are scrubbed.
+func (s *settingsRegistry) ConfigProvenance(onlyChanged bool) []settings.SettingProvenance {
+	keys := s.config.AllKeysLowercased()
+	provenance := make([]settings.SettingProvenance, 0, len(keys))
+
+	for _, key := range keys {
+		source := s.config.GetSource(key)
+		if onlyChanged && isDefaultSource(source) {
+			continue
+		}
+
+		setting := settings.SettingProvenance{
+			Setting: key,
+			Value:   scrubSettingValue(key, s.config.Get(key)),
+			Source:  source,
+		}
+		for _, value := range s.config.GetAllSources(key) {
+			if value.Value == nil {
+				continue
+			}
+			value.Value = scrubSettingValue(key, value.Value)
+			if value.Source == model.SourceDefault {
+				setting.Default = value.Value
+			}
+			setting.Sources = append(setting.Sources, value)
+		}
+		provenance = append(provenance, setting)
+	}
+	return provenance
+}
+
+// GetConfigProvenance returns the effective value and the source of each configuration setting. The 'diff' query
+// parameter omits the settings left to their default value.
+func (s *settingsRegistry) GetConfigProvenance(w http.ResponseWriter, r *http.Request) {
+	onlyChanged := r.URL.Query().Get("diff") == "true"
+	s.writeJSON(w, map[string]interface{}{"settings": s.ConfigProvenance(onlyChanged)})
+}
+
+// isDefaultSource returns true if a setting with this source has not been set by the user, the agent or the backend
+func isDefaultSource(source model.Source) bool {
+	return source == "" || source == model.SourceDefault || source == model.SourceSchema
+}
+
+// scrubSettingValue scrubs the value of a setting. The scrubber matches the sensitive settings by their name, so the
+// value is scrubbed under the last part of the setting name.
+func scrubSettingValue(key string, value interface{}) interface{} {
+	name := key[strings.LastIndex(key, ".")+1:]
+	var data interface{} = map[string]interface{}{name: deepcopy.Copy(value)}
+	scrubber.ScrubDataObj(&data)
+	return data.(map[string]interface{})[name]
+}
@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package settingsimpl
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/comp/core/settings"
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func newProvenanceTestRegistry(t *testing.T) *settingsRegistry {
+	cfg := config.NewMock(t)
+	cfg.Set("api_key", "0123456789abcdef0123456789abcdef", model.SourceFile)
+	cfg.Set("log_level", "warn", model.SourceFile)
+	cfg.Set("log_level", "debug", model.SourceEnvVar)
+	cfg.Set("log_level", "trace", model.SourceFleetPolicies)
+
+	deps := fxutil.Test[dependencies](t, fx.Options(
+		fx.Provide(func() log.Component { return logmock.New(t) }),
+		fx.Supply(settings.Params{Config: cfg}),
+	))
+	return newSettings(deps).Comp.(*settingsRegistry)
+}
+
+func findProvenance(t *testing.T, provenance []settings.SettingProvenance, key string) settings.SettingProvenance {
+	for _, setting := range provenance {
+		if setting.Setting == key {
+			return setting
+		}
+	}
+	require.Failf(t, "setting not found", "%s is missing from the provenance", key)
+	return settings.SettingProvenance{}
+}
+
+func TestConfigProvenance(t *testing.T) {
+	s := newProvenanceTestRegistry(t)
+
+	provenance := s.ConfigProvenance(false)
+	assert.IsIncreasing(t, settingNames(provenance))
+
+	logLevel := findProvenance(t, provenance, "log_level")
+	assert.Equal(t, "trace", logLevel.Value)
+	assert.Equal(t, model.SourceFleetPolicies, logLevel.Source)
+	assert.Equal(t, "info", logLevel.Default)
+	assert.Equal(t, []model.ValueWithSource{
+		{Source: model.SourceDefault, Value: "info"},
+		{Source: model.SourceFile, Value: "warn"},
+		{Source: model.SourceEnvVar, Value: "debug"},
+		{Source: model.SourceFleetPolicies, Value: "trace"},
+	}, logLevel.Sources)
+
+	apiKey := findProvenance(t, provenance, "api_key")
+	assert.Equal(t, "***************************bcdef", apiKey.Value)
+	assert.Equal(t, model.SourceFile, apiKey.Source)
+	for _, value := range apiKey.Sources {
+		assert.NotContains(t, value.Value, "0123456789")
+	}
+
+	hostname := findProvenance(t, provenance, "hostname")
+	assert.Equal(t, model.SourceDefault, hostname.Source)
+}
+
+func TestConfigProvenanceOnlyChanged(t *testing.T) {
+	s := newProvenanceTestRegistry(t)
+
+	assert.Equal(t, []string{"api_key", "log_level"}, settingNames(s.ConfigProvenance(true)))
+}
+
+func TestGetConfigProvenance(t *testing.T) {
+	s := newProvenanceTestRegistry(t)
+
+	responseRecorder := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "http://agent.host/config/provenance/all?diff=true", nil)
+	s.GetConfigProvenance(responseRecorder, request)
+	require.Equal(t, 200, responseRecorder.Code)
+
+	var resp struct {
+		Settings []settings.SettingProvenance `json:"settings"`
+	}
+	require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &resp))
+	assert.Equal(t, []string{"api_key", "log_level"}, settingNames(resp.Settings))
+	assert.Equal(t, model.SourceFleetPolicies, findProvenance(t, resp.Settings, "log_level").Source)
+}
+
+func settingNames(provenance []settings.SettingProvenance) []string {
+	names := make([]string, 0, len(provenance))
+	for _, setting := range provenance {
+		names = append(names, setting.Setting)
+	}
+	return names
+}
@@ -64,6 +64,34 @@ func (m *mock) SetRuntimeSetting(key string, v interface{}, _ model.Source) erro
 	return nil
 }
 
+// StageRuntimeSettings stages a set of runtime setting values
+func (m mock) StageRuntimeSettings(map[string]interface{}) error {
+	return nil
+}
+
+// StagedRuntimeSettings returns the staged values waiting to be committed
+func (m mock) StagedRuntimeSettings() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+// CommitRuntimeSettings applies all the staged values
+func (m mock) CommitRuntimeSettings(model.Source) ([]settings.AppliedOverride, error) {
+	return nil, nil
+}
+
+// RollbackRuntimeSettings discards all the staged values
+func (m mock) RollbackRuntimeSettings() {}
+
+// AppliedOverrides returns the last value applied at runtime for each setting
+func (m mock) AppliedOverrides() []settings.AppliedOverride {
+	return nil
+}
+
+// ConfigProvenance returns the effective value and the source of each configuration setting
+func (m mock) ConfigProvenance(bool) []settings.SettingProvenance {
+	return nil
+}
+
 // GetFullConfig returns the full config
 func (m mock) GetFullConfig(...string) http.HandlerFunc {
 	return func(http.ResponseWriter, *http.Request) {}
@@ -82,3 +110,21 @@ func (m mock) SetValue(http.ResponseWriter, *http.Request) {}
 
 // ListConfigurable returns the list of configurable setting at runtime
 func (m mock) ListConfigurable(http.ResponseWriter, *http.Request) {}
+
+// GetStagedValues returns the staged runtime setting values
+func (m mock) GetStagedValues(http.ResponseWriter, *http.Request) {}
+
+// StageValues stages runtime setting values
+func (m mock) StageValues(http.ResponseWriter, *http.Request) {}
+
+// CommitValues applies the staged runtime setting values
+func (m mock) CommitValues(http.ResponseWriter, *http.Request) {}
+
+// RollbackValues discards the staged runtime setting values
+func (m mock) RollbackValues(http.ResponseWriter, *http.Request) {}
+
+// ListOverrides returns the runtime setting values applied at runtime
+func (m mock) ListOverrides(http.ResponseWriter, *http.Request) {}
+
+// GetConfigProvenance returns the effective value and the source of each configuration setting
+func (m mock) GetConfigProvenance(http.ResponseWriter, *http.Request) {}
@@ -37,11 +37,17 @@ func Module() fxutil.Module {
 type provides struct {
 	fx.Out
 
-	Comp         settings.Component
-	FullEndpoint api.AgentEndpointProvider
-	ListEndpoint api.AgentEndpointProvider
-	GetEndpoint  api.AgentEndpointProvider
-	SetEndpoint  api.AgentEndpointProvider
+	Comp               settings.Component
+	FullEndpoint       api.AgentEndpointProvider
+	ListEndpoint       api.AgentEndpointProvider
+	GetEndpoint        api.AgentEndpointProvider
+	SetEndpoint        api.AgentEndpointProvider
+	StagedEndpoint     api.AgentEndpointProvider
+	StageEndpoint      api.AgentEndpointProvider
+	CommitEndpoint     api.AgentEndpointProvider
+	RollbackEndpoint   api.AgentEndpointProvider
+	OverridesEndpoint  api.AgentEndpointProvider
+	ProvenanceEndpoint api.AgentEndpointProvider
 }
 
 type dependencies struct {
@@ -56,6 +62,10 @@ type settingsRegistry struct {
 	settings map[string]settings.RuntimeSetting
 	log      log.Component
 	config   config.Component
+	// staged contains the values staged and waiting to be committed
+	staged map[string]interface{}
+	// overrides contains the last value applied at runtime for each setting
+	overrides map[string]settings.AppliedOverride
 }
 
 // RuntimeSettings returns all runtime configurable settings
@@ -83,10 +93,12 @@ func (s *settingsRegistry) GetRuntimeSetting(setting string) (interface{}, error
 func (s *settingsRegistry) SetRuntimeSetting(setting string, value interface{}, source model.Source) error {
 	s.rwMutex.Lock()
 	defer s.rwMutex.Unlock()
-	if _, ok := s.settings[setting]; !ok {
-		return &settings.SettingNotFoundError{Name: setting}
+	override, err := s.applyLocked(setting, value, source)
+	if err != nil {
+		return err
 	}
-	return s.settings[setting].Set(s.config, value, source)
+	s.overrides[setting] = override
+	return nil
 }
 
 func (s *settingsRegistry) GetFullConfig(namespaces ...string) http.HandlerFunc {
@@ -243,9 +255,10 @@ func (s *settingsRegistry) SetValue(w http.ResponseWriter, r *http.Request) {
 
 func newSettings(deps dependencies) provides {
 	s := &settingsRegistry{
-		settings: deps.Params.Settings,
-		log:      deps.Log,
-		config:   deps.Params.Config,
+		settings:  deps.Params.Settings,
+		log:       deps.Log,
+		config:    deps.Params.Config,
+		overrides: map[string]settings.AppliedOverride{},
 	}
 	return provides{
 		Comp:         s,
@@ -253,5 +266,13 @@ func newSettings(deps dependencies) provides {
 		ListEndpoint: api.NewAgentEndpointProvider(s.ListConfigurable, "/config/list-runtime", "GET"),
 		GetEndpoint:  api.NewAgentEndpointProvider(s.GetValue, "/config/{setting}", "GET"),
 		SetEndpoint:  api.NewAgentEndpointProvider(s.SetValue, "/config/{setting}", "POST"),
+		// Two-phase apply of runtime settings
+		StagedEndpoint:    api.NewAgentEndpointProvider(s.GetStagedValues, "/config/transaction/staged", "GET"),
+		StageEndpoint:     api.NewAgentEndpointProvider(s.StageValues, "/config/transaction/stage", "POST"),
+		CommitEndpoint:    api.NewAgentEndpointProvider(s.CommitValues, "/config/transaction/commit", "POST"),
+		RollbackEndpoint:  api.NewAgentEndpointProvider(s.RollbackValues, "/config/transaction/rollback", "POST"),
+		OverridesEndpoint: api.NewAgentEndpointProvider(s.ListOverrides, "/config/transaction/overrides", "GET"),
+		// Provenance of the configuration settings
+		ProvenanceEndpoint: api.NewAgentEndpointProvider(s.GetConfigProvenance, "/config/provenance/all", "GET"),
 	}
 }
@@ -0,0 +1,252 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package settingsimpl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	json "github.com/json-iterator/go"
+
+	"github.com/DataDog/datadog-agent/comp/core/settings"
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+)
+
+// StageRuntimeSettings validates and stages a set of runtime setting values.
+// Nothing is staged if one of the values is invalid.
+func (s *settingsRegistry) StageRuntimeSettings(values map[string]interface{}) error {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	for name, value := range values {
+		setting, ok := s.settings[name]
+		if !ok {
+			return &settings.SettingNotFoundError{Name: name}
+		}
+		if validator, ok := setting.(settings.RuntimeSettingValidator); ok {
+			if err := validator.Validate(value); err != nil {
+				return &settings.SettingValidationError{Name: name, Err: err}
+			}
+		}
+	}
+
+	if s.staged == nil {
+		s.staged = make(map[string]interface{}, len(values))
+	}
+	for name, value := range values {
+		s.staged[name] = value
+	}
+	return nil
+}
+
+// StagedRuntimeSettings returns the staged values waiting to be committed
+func (s *settingsRegistry) StagedRuntimeSettings() map[string]interface{} {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	staged := make(map[string]interface{}, len(s.staged))
+	for name, value := range s.staged {
+		staged[name] = value
+	}
+	return staged
+}
+
+// CommitRuntimeSettings applies all the staged values, in the order of the
+// setting names. If a value fails to be applied, the settings already changed
+// are restored to their previous value and the staged values are kept so that
+// they can be fixed or rolled back.
+func (s *settingsRegistry) CommitRuntimeSettings(source model.Source) ([]settings.AppliedOverride, error) {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	names := make([]string, 0, len(s.staged))
+	for name := range s.staged {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	applied := make([]settings.AppliedOverride, 0, len(names))
+	for _, name := range names {
+		override, err := s.applyLocked(name, s.staged[name], source)
+		if err != nil {
+			s.revertLocked(applied, source)
+			return nil, fmt.Errorf("unable to apply setting %s, all the staged settings were rolled back: %w", name, err)
+		}
+		applied = append(applied, override)
+	}
+
+	for _, override := range applied {
+		s.overrides[override.Setting] = override
+	}
+	s.staged = nil
+
+	return applied, nil
+}
+
+// RollbackRuntimeSettings discards all the staged values
+func (s *settingsRegistry) RollbackRuntimeSettings() {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	s.staged = nil
+}
+
+// AppliedOverrides returns the last value applied at runtime for each setting
+func (s *settingsRegistry) AppliedOverrides() []settings.AppliedOverride {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	overrides := make([]settings.AppliedOverride, 0, len(s.overrides))
+	for _, override := range s.overrides {
+		overrides = append(overrides, override)
+	}
+	slices.SortFunc(overrides, func(a, b settings.AppliedOverride) int {
+		return strings.Compare(a.Setting, b.Setting)
+	})
+	return overrides
+}
+
+// applyLocked sets the value of a runtime setting and returns the corresponding
+// override. The caller must hold the write lock.
+func (s *settingsRegistry) applyLocked(name string, value interface{}, source model.Source) (settings.AppliedOverride, error) {
+	setting, ok := s.settings[name]
+	if !ok {
+		return settings.AppliedOverride{}, &settings.SettingNotFoundError{Name: name}
+	}
+
+	previous, err := setting.Get(s.config)
+	if err != nil {
+		s.log.Debugf("Unable to get the current value of setting %s: %s", name, err)
+	}
+
+	previousSource := s.sourceLocked(name)
+
+	if err := setting.Set(s.config, value, source); err != nil {
+		return settings.AppliedOverride{}, err
+	}
+
+	return settings.AppliedOverride{
+		Setting:        name,
+		PreviousValue:  previous,
+		PreviousSource: previousSource,
+		Value:          value,
+		Source:         source,
+		AppliedAt:      time.Now(),
+	}, nil
+}
+
+// sourceLocked returns the source of the current value of a runtime setting: the
+// source it was last applied with at runtime, or the source of the configuration
+// key with the same name. The caller must hold the lock.
+func (s *settingsRegistry) sourceLocked(name string) model.Source {
+	if override, ok := s.overrides[name]; ok {
+		return override.Source
+	}
+	if s.config.IsKnown(name) {
+		if source := s.config.GetSource(name); source != "" {
+			return source
+		}
+	}
+	return model.SourceDefault
+}
+
+// revertLocked restores the previous value and source of the given overrides, in
+// reverse order. The caller must hold the write lock.
+func (s *settingsRegistry) revertLocked(applied []settings.AppliedOverride, source model.Source) {
+	for i := len(applied) - 1; i >= 0; i-- {
+		override := applied[i]
+		if err := s.settings[override.Setting].Set(s.config, override.PreviousValue, override.PreviousSource); err != nil {
+			s.log.Errorf("Unable to restore the previous value of setting %s: %s", override.Setting, err)
+			continue
+		}
+		// the value applied by the commit would otherwise keep taking precedence
+		// over the restored one
+		if override.PreviousSource != source && s.config.IsKnown(override.Setting) {
+			s.config.UnsetForSource(override.Setting, source)
+		}
+	}
+}
+
+// GetStagedValues returns the staged values waiting to be committed
+func (s *settingsRegistry) GetStagedValues(w http.ResponseWriter, _ *http.Request) {
+	s.writeJSON(w, map[string]interface{}{"staged": s.StagedRuntimeSettings()})
+}
+
+// StageValues validates and stages the runtime setting values sent in the request body
+func (s *settingsRegistry) StageValues(w http.ResponseWriter, r *http.Request) {
+	// Values are read as strings, like the ones set through the SetValue endpoint,
+	// because runtime settings expect string values from the API.
+	var values map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+		body, _ := json.Marshal(map[string]string{"error": fmt.Sprintf("invalid request body: %s", err)})
+		http.Error(w, string(body), http.StatusBadRequest)
+		return
+	}
+
+	s.log.Infof("Got a request to stage %d setting(s)", len(values))
+
+	toStage := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		toStage[name] = value
+	}
+
+	if err := s.StageRuntimeSettings(toStage); err != nil {
+		body, _ := json.Marshal(map[string]string{"error": err.Error()})
+		var notFound *settings.SettingNotFoundError
+		var invalid *settings.SettingValidationError
+		if errors.As(err, &notFound) || errors.As(err, &invalid) {
+			http.Error(w, string(body), http.StatusBadRequest)
+		} else {
+			http.Error(w, string(body), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.writeJSON(w, map[string]interface{}{"staged": s.StagedRuntimeSettings()})
+}
+
+// CommitValues atomically applies all the staged values
+func (s *settingsRegistry) CommitValues(w http.ResponseWriter, _ *http.Request) {
+	s.log.Info("Got a request to commit the staged settings")
+
+	applied, err := s.CommitRuntimeSettings(model.SourceCLI)
+	if err != nil {
+		body, _ := json.Marshal(map[string]string{"error": err.Error()})
+		http.Error(w, string(body), http.StatusInternalServerError)
+		return
+	}
+
+	s.writeJSON(w, map[string]interface{}{"applied": applied})
+}
+
+// RollbackValues discards all the staged values
+func (s *settingsRegistry) RollbackValues(w http.ResponseWriter, _ *http.Request) {
+	s.log.Info("Got a request to roll back the staged settings")
+
+	s.RollbackRuntimeSettings()
+	w.WriteHeader(http.StatusOK)
+}
+
+// ListOverrides returns the last value applied at runtime for each setting
+func (s *settingsRegistry) ListOverrides(w http.ResponseWriter, _ *http.Request) {
+	s.writeJSON(w, map[string]interface{}{"overrides": s.AppliedOverrides()})
+}
+
+func (s *settingsRegistry) writeJSON(w http.ResponseWriter, resp interface{}) {
+	body, err := json.Marshal(resp)
+	if err != nil {
+		s.log.Errorf("Unable to marshal runtime settings response: %s", err)
+		body, _ := json.Marshal(map[string]string{"error": err.Error()})
+		http.Error(w, string(body), http.StatusInternalServerError)
+		return
+	}
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,251 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package settingsimpl
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/comp/core/settings"
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+type validatedTestSetting struct {
+	runtimeTestSetting
+	failSet bool
+}
+
+func (t *validatedTestSetting) Validate(v interface{}) error {
+	if v.(string) == "invalid" {
+		return errors.New("invalid value")
+	}
+	return nil
+}
+
+func (t *validatedTestSetting) Get(_ config.Component) (interface{}, error) {
+	return t.value, nil
+}
+
+func (t *validatedTestSetting) Set(c config.Component, v interface{}, source model.Source) error {
+	if t.failSet && v.(string) != "" {
+		return errors.New("set failed")
+	}
+	return t.runtimeTestSetting.Set(c, v, source)
+}
+
+func newStagingTestRegistry(t *testing.T) (*settingsRegistry, *runtimeTestSetting, *validatedTestSetting) {
+	foo := &runtimeTestSetting{description: "foo settings"}
+	bar := &validatedTestSetting{runtimeTestSetting: runtimeTestSetting{description: "bar settings"}}
+
+	deps := fxutil.Test[dependencies](t, fx.Options(
+		fx.Provide(func() log.Component { return logmock.New(t) }),
+		fx.Supply(
+			settings.Params{
+				Config: config.NewMock(t),
+				Settings: map[string]settings.RuntimeSetting{
+					"foo": foo,
+					"bar": bar,
+				},
+			},
+		),
+	))
+
+	return newSettings(deps).Comp.(*settingsRegistry), foo, bar
+}
+
+func TestStageAndCommitRuntimeSettings(t *testing.T) {
+	s, foo, bar := newStagingTestRegistry(t)
+
+	require.NoError(t, s.StageRuntimeSettings(map[string]interface{}{"foo": "a"}))
+	require.NoError(t, s.StageRuntimeSettings(map[string]interface{}{"bar": "b"}))
+	assert.Equal(t, map[string]interface{}{"foo": "a", "bar": "b"}, s.StagedRuntimeSettings())
+
+	// nothing is applied before the commit
+	assert.Equal(t, "", foo.value)
+	assert.Equal(t, "", bar.value)
+
+	applied, err := s.CommitRuntimeSettings(model.SourceCLI)
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	assert.Equal(t, "bar", applied[0].Setting)
+	assert.Equal(t, "foo", applied[1].Setting)
+
+	assert.Equal(t, "a", foo.value)
+	assert.Equal(t, "b", bar.value)
+	assert.Empty(t, s.StagedRuntimeSettings())
+
+	overrides := s.AppliedOverrides()
+	require.Len(t, overrides, 2)
+	assert.Equal(t, "bar", overrides[0].Setting)
+	assert.Equal(t, "b", overrides[0].Value)
+	assert.Equal(t, model.SourceCLI, overrides[0].Source)
+}
+
+func TestStageRuntimeSettingsValidation(t *testing.T) {
+	s, _, _ := newStagingTestRegistry(t)
+
+	err := s.StageRuntimeSettings(map[string]interface{}{"foo": "a", "non_existing": "b"})
+	var notFound *settings.SettingNotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	err = s.StageRuntimeSettings(map[string]interface{}{"foo": "a", "bar": "invalid"})
+	var invalid *settings.SettingValidationError
+	assert.ErrorAs(t, err, &invalid)
+
+	assert.Empty(t, s.StagedRuntimeSettings())
+}
+
+func TestCommitRuntimeSettingsRollsBackOnFailure(t *testing.T) {
+	s, foo, bar := newStagingTestRegistry(t)
+	bar.failSet = true
+
+	// "bar" is applied before "foo", make "foo" already set so that we can check it's untouched
+	require.NoError(t, s.SetRuntimeSetting("foo", "initial", model.SourceCLI))
+	require.NoError(t, s.StageRuntimeSettings(map[string]interface{}{"foo": "a", "bar": "b"}))
+
+	_, err := s.CommitRuntimeSettings(model.SourceCLI)
+	require.Error(t, err)
+
+	assert.Equal(t, "initial", foo.value)
+	assert.Equal(t, "", bar.value)
+
+	// staged values are kept until they are rolled back
+	assert.Len(t, s.StagedRuntimeSettings(), 2)
+	s.RollbackRuntimeSettings()
+	assert.Empty(t, s.StagedRuntimeSettings())
+
+	overrides := s.AppliedOverrides()
+	require.Len(t, overrides, 1)
+	assert.Equal(t, "foo", overrides[0].Setting)
+}
+
+func TestCommitRuntimeSettingsRevertsAppliedSettings(t *testing.T) {
+	s, foo, bar := newStagingTestRegistry(t)
+	foo.value = "initial"
+
+	// make "foo", applied after "bar", fail
+	s.settings["foo"] = &validatedTestSetting{runtimeTestSetting: *foo, failSet: true}
+	require.NoError(t, s.StageRuntimeSettings(map[string]interface{}{"foo": "a", "bar": "b"}))
+
+	_, err := s.CommitRuntimeSettings(model.SourceCLI)
+	require.Error(t, err)
+
+	// "bar" was applied then restored to its previous value
+	value, err := s.GetRuntimeSetting("bar")
+	require.NoError(t, err)
+	assert.Equal(t, "", value)
+	assert.Equal(t, model.SourceDefault, bar.source)
+	assert.Empty(t, s.AppliedOverrides())
+}
+
+// configTestSetting is a runtime setting backed by the configuration key with the same name
+type configTestSetting struct {
+	runtimeTestSetting
+	key string
+}
+
+func (t *configTestSetting) Get(c config.Component) (interface{}, error) {
+	return c.GetString(t.key), nil
+}
+
+func (t *configTestSetting) Set(c config.Component, v interface{}, source model.Source) error {
+	c.Set(t.key, v, source)
+	return nil
+}
+
+func TestCommitRuntimeSettingsRestoresSource(t *testing.T) {
+	s, foo, _ := newStagingTestRegistry(t)
+	s.config.Set("log_level", "warn", model.SourceFile)
+	s.settings["log_level"] = &configTestSetting{key: "log_level"}
+
+	// make "foo", applied after "log_level", fail
+	s.settings["foo"] = &validatedTestSetting{runtimeTestSetting: *foo, failSet: true}
+	require.NoError(t, s.StageRuntimeSettings(map[string]interface{}{"foo": "a", "log_level": "debug"}))
+
+	_, err := s.CommitRuntimeSettings(model.SourceCLI)
+	require.Error(t, err)
+
+	assert.Equal(t, "warn", s.config.GetString("log_level"))
+	assert.Equal(t, model.SourceFile, s.config.GetSource("log_level"))
+	assert.Nil(t, s.config.GetAllSources("log_level")[slices.Index(model.Sources, model.SourceCLI)].Value)
+}
+
+func TestCommitRuntimeSettingsRecordsPreviousSource(t *testing.T) {
+	s, _, _ := newStagingTestRegistry(t)
+	s.config.Set("log_level", "warn", model.SourceEnvVar)
+	s.settings["log_level"] = &configTestSetting{key: "log_level"}
+
+	require.NoError(t, s.StageRuntimeSettings(map[string]interface{}{"log_level": "debug"}))
+	applied, err := s.CommitRuntimeSettings(model.SourceCLI)
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, "warn", applied[0].PreviousValue)
+	assert.Equal(t, model.SourceEnvVar, applied[0].PreviousSource)
+	assert.Equal(t, model.SourceCLI, s.config.GetSource("log_level"))
+
+	// a later commit reverts to the value applied at runtime
+	require.NoError(t, s.StageRuntimeSettings(map[string]interface{}{"log_level": "info"}))
+	applied, err = s.CommitRuntimeSettings(model.SourceCLI)
+	require.NoError(t, err)
+	assert.Equal(t, model.SourceCLI, applied[0].PreviousSource)
+}
+
+func TestStagingEndpoints(t *testing.T) {
+	s, foo, _ := newStagingTestRegistry(t)
+
+	do := func(handler http.HandlerFunc, body string) (int, string) {
+		responseRecorder := httptest.NewRecorder()
+		request := httptest.NewRequest("POST", "http://agent.host/config/transaction", bytes.NewBufferString(body))
+		handler(responseRecorder, request)
+		resp := responseRecorder.Result()
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(respBody)
+	}
+
+	code, body := do(s.StageValues, `{"foo": "fancy"}`)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, `{"staged":{"foo":"fancy"}}`, body)
+
+	code, _ = do(s.StageValues, `{"bar": "invalid"}`)
+	assert.Equal(t, 400, code)
+
+	code, _ = do(s.StageValues, `not json`)
+	assert.Equal(t, 400, code)
+
+	code, body = do(s.GetStagedValues, "")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, `{"staged":{"foo":"fancy"}}`, body)
+
+	code, _ = do(s.CommitValues, "")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, "fancy", foo.value)
+	assert.Equal(t, model.SourceCLI, foo.source)
+
+	code, body = do(s.ListOverrides, "")
+	assert.Equal(t, 200, code)
+	assert.Contains(t, body, `"Setting":"foo"`)
+
+	code, _ = do(s.StageValues, `{"foo": "other"}`)
+	assert.Equal(t, 200, code)
+	code, _ = do(s.RollbackValues, "")
+	assert.Equal(t, 200, code)
+	assert.Empty(t, s.StagedRuntimeSettings())
+	assert.Equal(t, "fancy", foo.value)
+}
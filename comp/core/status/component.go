@@ -44,6 +44,16 @@ type Provider interface {
 	HTML(verbose bool, buffer io.Writer) error
 }
 
+// SchemaProvider is implemented by the status providers whose JSON output follows a versioned schema, it is used by
+// the versioned JSON status document. The providers of a same section share its schema: the version of a section is
+// the highest version of its providers.
+type SchemaProvider interface {
+	// SchemaVersion is the version of the schema of the JSON output, it must be bumped on every breaking change
+	SchemaVersion() int
+	// Schema returns the JSON schemas of the properties added by the JSON output, by property name
+	Schema() map[string]interface{}
+}
+
 // HeaderProvider interface
 type HeaderProvider interface {
 	// Index is used to choose the order in which the header information is displayed.
@@ -79,6 +79,35 @@ func (h *headerProvider) data() map[string]interface{} {
 	return data
 }
 
+// SchemaVersion is the version of the schema of the JSON output
+func (h *headerProvider) SchemaVersion() int {
+	return 1
+}
+
+// Schema returns the JSON schemas of the properties added by the JSON output
+func (h *headerProvider) Schema() map[string]interface{} {
+	str := map[string]interface{}{"type": "string"}
+	integer := map[string]interface{}{"type": "integer"}
+
+	return map[string]interface{}{
+		"version":          str,
+		"flavor":           str,
+		"conf_file":        str,
+		"extra_conf_file":  map[string]interface{}{"type": "array", "items": str},
+		"pid":              integer,
+		"go_version":       str,
+		"agent_start_nano": integer,
+		"python_version":   str,
+		"build_arch":       str,
+		"time_nano":        integer,
+		"config": map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": str,
+		},
+		"fips_status": str,
+	}
+}
+
 func newCommonHeaderProvider(params status.Params, config config.Component) status.HeaderProvider {
 
 	data := map[string]interface{}{}
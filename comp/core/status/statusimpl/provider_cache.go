@@ -0,0 +1,263 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package statusimpl
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/status"
+)
+
+// staleProvidersKey is the key of the JSON status listing the providers whose output comes from the cache
+const staleProvidersKey = "stale_providers"
+
+// outputKey identifies an output of a status provider
+type outputKey struct {
+	format  string
+	verbose bool
+}
+
+// providerOutput is an output of a status provider
+type providerOutput struct {
+	// stats is filled by the JSON output
+	stats map[string]interface{}
+	// buffer is filled by the Text and HTML outputs
+	buffer      []byte
+	err         error
+	collectedAt time.Time
+}
+
+// collection is an ongoing collection of an output
+type collection struct {
+	done   chan struct{}
+	output providerOutput
+}
+
+// providerCache caches the outputs of a status provider, so that a slow or hung
+// provider doesn't block the whole status.
+//
+// An output is collected again once it's older than the TTL. When the provider
+// doesn't answer within the timeout, its last successful output is used instead,
+// along with the time it was collected.
+type providerCache struct {
+	name    string
+	ttl     time.Duration
+	timeout time.Duration
+	now     func() time.Time
+
+	mu          sync.Mutex
+	outputs     map[outputKey]providerOutput
+	collections map[outputKey]*collection
+}
+
+func newProviderCache(name string, ttl, timeout time.Duration) *providerCache {
+	return &providerCache{
+		name:        name,
+		ttl:         ttl,
+		timeout:     timeout,
+		now:         time.Now,
+		outputs:     make(map[outputKey]providerOutput),
+		collections: make(map[outputKey]*collection),
+	}
+}
+
+// get returns the output of the provider for the given key, collecting it if
+// needed. stale is true when the output comes from the cache, and timedOut is
+// true when the provider didn't answer within the timeout.
+func (c *providerCache) get(key outputKey, collect func() providerOutput) (output providerOutput, stale bool, timedOut bool) {
+	c.mu.Lock()
+	cached, found := c.outputs[key]
+	if found && c.now().Sub(cached.collectedAt) < c.ttl {
+		c.mu.Unlock()
+		return cached, true, false
+	}
+
+	// a single collection runs at a time, so that a hung provider doesn't
+	// pile up goroutines
+	col, collecting := c.collections[key]
+	if !collecting {
+		col = &collection{done: make(chan struct{})}
+		c.collections[key] = col
+		go c.collect(key, col, collect)
+	}
+	c.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if c.timeout > 0 {
+		timer := time.NewTimer(c.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-col.done:
+		return col.output, false, false
+	case <-timeout:
+		if found {
+			return cached, true, true
+		}
+		return providerOutput{err: fmt.Errorf("status provider %q did not answer within %s", c.name, c.timeout)}, false, true
+	}
+}
+
+func (c *providerCache) collect(key outputKey, col *collection, collect func() providerOutput) {
+	defer close(col.done)
+	defer func() {
+		if r := recover(); r != nil {
+			col.output = providerOutput{err: fmt.Errorf("status provider %q panicked: %v", c.name, r)}
+		}
+		c.mu.Lock()
+		delete(c.collections, key)
+		c.mu.Unlock()
+	}()
+
+	col.output = collect()
+	col.output.collectedAt = c.now()
+
+	// only the successful outputs are kept, so that they can replace the
+	// output of a provider which doesn't answer anymore
+	if col.output.err == nil {
+		c.mu.Lock()
+		c.outputs[key] = col.output
+		c.mu.Unlock()
+	}
+}
+
+// json fills stats with the JSON output of the provider
+func (c *providerCache) json(verbose bool, stats map[string]interface{}, fn func(bool, map[string]interface{}) error) error {
+	output, stale, timedOut := c.get(outputKey{format: "json", verbose: verbose}, func() providerOutput {
+		output := providerOutput{stats: make(map[string]interface{})}
+		output.err = fn(verbose, output.stats)
+		return output
+	})
+
+	for k, v := range output.stats {
+		stats[k] = v
+	}
+
+	if stale {
+		staleProviders, ok := stats[staleProvidersKey].(map[string]interface{})
+		if !ok {
+			staleProviders = make(map[string]interface{})
+			stats[staleProvidersKey] = staleProviders
+		}
+		staleProviders[c.name] = map[string]interface{}{
+			"collected_at": output.collectedAt.Unix(),
+			"age":          c.age(output).String(),
+			"timed_out":    timedOut,
+		}
+	}
+
+	return output.err
+}
+
+// text writes the Text output of the provider to w
+func (c *providerCache) text(verbose bool, w io.Writer, fn func(bool, io.Writer) error) error {
+	output, stale, timedOut := c.get(outputKey{format: "text", verbose: verbose}, bufferedOutput(verbose, fn))
+	if len(output.buffer) == 0 {
+		return output.err
+	}
+
+	_, _ = w.Write(output.buffer)
+	if stale {
+		fmt.Fprintf(w, "  %s\n", c.stalenessMessage(output, timedOut))
+	}
+	return output.err
+}
+
+// html writes the HTML output of the provider to w
+func (c *providerCache) html(verbose bool, w io.Writer, fn func(bool, io.Writer) error) error {
+	output, stale, timedOut := c.get(outputKey{format: "html", verbose: verbose}, bufferedOutput(verbose, fn))
+	if len(output.buffer) == 0 {
+		return output.err
+	}
+
+	_, _ = w.Write(output.buffer)
+	if stale {
+		fmt.Fprintf(w, "<div class=\"stat\">\n  <span class=\"stat_data\">%s</span>\n</div>\n", html.EscapeString(c.stalenessMessage(output, timedOut)))
+	}
+	return output.err
+}
+
+func bufferedOutput(verbose bool, fn func(bool, io.Writer) error) func() providerOutput {
+	return func() providerOutput {
+		b := new(bytes.Buffer)
+		err := fn(verbose, b)
+		return providerOutput{buffer: b.Bytes(), err: err}
+	}
+}
+
+func (c *providerCache) age(output providerOutput) time.Duration {
+	return c.now().Sub(output.collectedAt).Round(time.Second)
+}
+
+func (c *providerCache) stalenessMessage(output providerOutput, timedOut bool) string {
+	if timedOut {
+		return fmt.Sprintf("%s status collected %s ago, the provider did not answer within %s", c.name, c.age(output), c.timeout)
+	}
+	return fmt.Sprintf("%s status collected %s ago", c.name, c.age(output))
+}
+
+// cachedProvider is a status provider whose outputs go through a providerCache
+type cachedProvider struct {
+	status.Provider
+	cache *providerCache
+}
+
+func newCachedProvider(provider status.Provider, ttl, timeout time.Duration) status.Provider {
+	return &cachedProvider{
+		Provider: provider,
+		cache:    newProviderCache(provider.Name(), ttl, timeout),
+	}
+}
+
+// JSON populates the status map
+func (p *cachedProvider) JSON(verbose bool, stats map[string]interface{}) error {
+	return p.cache.json(verbose, stats, p.Provider.JSON)
+}
+
+// Text renders the text output
+func (p *cachedProvider) Text(verbose bool, buffer io.Writer) error {
+	return p.cache.text(verbose, buffer, p.Provider.Text)
+}
+
+// HTML renders the html output
+func (p *cachedProvider) HTML(verbose bool, buffer io.Writer) error {
+	return p.cache.html(verbose, buffer, p.Provider.HTML)
+}
+
+// cachedHeaderProvider is a status header provider whose outputs go through a providerCache
+type cachedHeaderProvider struct {
+	status.HeaderProvider
+	cache *providerCache
+}
+
+func newCachedHeaderProvider(provider status.HeaderProvider, ttl, timeout time.Duration) status.HeaderProvider {
+	return &cachedHeaderProvider{
+		HeaderProvider: provider,
+		cache:          newProviderCache(provider.Name(), ttl, timeout),
+	}
+}
+
+// JSON populates the status map
+func (p *cachedHeaderProvider) JSON(verbose bool, stats map[string]interface{}) error {
+	return p.cache.json(verbose, stats, p.HeaderProvider.JSON)
+}
+
+// Text renders the text output
+func (p *cachedHeaderProvider) Text(verbose bool, buffer io.Writer) error {
+	return p.cache.text(verbose, buffer, p.HeaderProvider.Text)
+}
+
+// HTML renders the html output
+func (p *cachedHeaderProvider) HTML(verbose bool, buffer io.Writer) error {
+	return p.cache.html(verbose, buffer, p.HeaderProvider.HTML)
+}
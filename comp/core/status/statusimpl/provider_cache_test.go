@@ -0,0 +1,153 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package statusimpl
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowProvider counts its calls, and blocks while it's hung
+type slowProvider struct {
+	mu    sync.Mutex
+	calls int
+	hung  chan struct{}
+}
+
+func (p *slowProvider) Name() string {
+	return "Slow"
+}
+
+func (p *slowProvider) Section() string {
+	return "slow"
+}
+
+func (p *slowProvider) call() int {
+	p.mu.Lock()
+	p.calls++
+	calls, hung := p.calls, p.hung
+	p.mu.Unlock()
+
+	if hung != nil {
+		<-hung
+	}
+	return calls
+}
+
+func (p *slowProvider) hang() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.hung = make(chan struct{})
+}
+
+func (p *slowProvider) JSON(_ bool, stats map[string]interface{}) error {
+	stats["calls"] = p.call()
+	return nil
+}
+
+func (p *slowProvider) Text(_ bool, buffer io.Writer) error {
+	_, err := fmt.Fprintf(buffer, "calls: %d\n", p.call())
+	return err
+}
+
+func (p *slowProvider) HTML(_ bool, buffer io.Writer) error {
+	_, err := fmt.Fprintf(buffer, "<span>calls: %d</span>\n", p.call())
+	return err
+}
+
+func TestCachedProviderTTL(t *testing.T) {
+	provider := &slowProvider{}
+	cached := newCachedProvider(provider, time.Minute, time.Second).(*cachedProvider)
+	now := time.Now()
+	cached.cache.now = func() time.Time { return now }
+
+	b := new(bytes.Buffer)
+	require.NoError(t, cached.Text(false, b))
+	assert.Equal(t, "calls: 1\n", b.String())
+
+	// the output is served from the cache until it expires
+	now = now.Add(30 * time.Second)
+	b.Reset()
+	require.NoError(t, cached.Text(false, b))
+	assert.Equal(t, "calls: 1\n  Slow status collected 30s ago\n", b.String())
+
+	stats := make(map[string]interface{})
+	require.NoError(t, cached.JSON(false, stats))
+	assert.Equal(t, 2, stats["calls"])
+	assert.NotContains(t, stats, staleProvidersKey)
+
+	now = now.Add(time.Minute)
+	b.Reset()
+	require.NoError(t, cached.Text(false, b))
+	assert.Equal(t, "calls: 3\n", b.String())
+}
+
+func TestCachedProviderTimeout(t *testing.T) {
+	provider := &slowProvider{}
+	cached := newCachedProvider(provider, 0, 50*time.Millisecond).(*cachedProvider)
+
+	stats := make(map[string]interface{})
+	require.NoError(t, cached.JSON(false, stats))
+	assert.Equal(t, 1, stats["calls"])
+
+	b := new(bytes.Buffer)
+	require.NoError(t, cached.HTML(false, b))
+	assert.Equal(t, "<span>calls: 2</span>\n", b.String())
+
+	// the last output is used when the provider doesn't answer in time
+	provider.hang()
+	stats = make(map[string]interface{})
+	require.NoError(t, cached.JSON(false, stats))
+	assert.Equal(t, 1, stats["calls"])
+	require.Contains(t, stats, staleProvidersKey)
+	staleProvider := stats[staleProvidersKey].(map[string]interface{})["Slow"].(map[string]interface{})
+	assert.Equal(t, true, staleProvider["timed_out"])
+
+	b.Reset()
+	require.NoError(t, cached.HTML(false, b))
+	assert.Contains(t, b.String(), "<span>calls: 2</span>\n")
+	assert.Contains(t, b.String(), "Slow status collected 0s ago, the provider did not answer within 50ms")
+
+	// without any previous output, the timeout is reported as an error
+	b.Reset()
+	err := cached.Text(false, b)
+	assert.EqualError(t, err, `status provider "Slow" did not answer within 50ms`)
+	assert.Empty(t, b.String())
+
+	// a single collection runs while the provider is hung
+	provider.mu.Lock()
+	assert.Equal(t, 5, provider.calls)
+	close(provider.hung)
+	provider.hung = nil
+	provider.mu.Unlock()
+
+	assert.Eventually(t, func() bool {
+		b.Reset()
+		return cached.Text(false, b) == nil && b.String() == "calls: 6\n"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+type panickingProvider struct {
+	mockProvider
+}
+
+func (p panickingProvider) Text(_ bool, _ io.Writer) error {
+	panic("oops")
+}
+
+func TestCachedProviderPanic(t *testing.T) {
+	cached := newCachedProvider(panickingProvider{mockProvider{name: "Panic"}}, 0, time.Second)
+
+	err := cached.Text(false, new(bytes.Buffer))
+	assert.EqualError(t, err, `status provider "Panic" panicked: oops`)
+}
@@ -53,12 +53,14 @@ type provides struct {
 	APIGetStatus      api.AgentEndpointProvider
 	APIGetSection     api.AgentEndpointProvider
 	APIGetSectionList api.AgentEndpointProvider
+	APIGetSchema      api.AgentEndpointProvider
 }
 
 type statusImplementation struct {
 	sortedHeaderProviders    []status.HeaderProvider
 	sortedSectionNames       []string
 	sortedProvidersBySection map[string][]status.Provider
+	sectionSchemas           map[string]sectionSchema
 	log                      log.Component
 }
 
@@ -84,7 +86,22 @@ func newStatus(deps dependencies) provides {
 	sortedSectionNames := []string{}
 	collectorSectionPresent := false
 
+	// Providers outputs are cached, so that a hung provider doesn't block the whole status
+	cacheTTL := deps.Config.GetDuration("status.provider_cache_ttl")
+	providerTimeout := deps.Config.GetDuration("status.provider_timeout")
+
+	// The schemas are collected before the providers are cached since the cache hides the optional interfaces
+	sectionSchemas := map[string]sectionSchema{}
+
 	providers := fxutil.GetAndFilterGroup(deps.Providers)
+	for i, provider := range providers {
+		section := strings.ToLower(provider.Section())
+		schema := sectionSchemas[section]
+		schema.add(provider)
+		sectionSchemas[section] = schema
+
+		providers[i] = newCachedProvider(provider, cacheTTL, providerTimeout)
+	}
 
 	for _, provider := range providers {
 		if provider.Section() == status.CollectorSection && !collectorSectionPresent {
@@ -125,10 +142,18 @@ func newStatus(deps dependencies) provides {
 
 	sortedHeaderProviders = append([]status.HeaderProvider{newCommonHeaderProvider(deps.Params, deps.Config)}, sortedHeaderProviders...)
 
+	headerSchema := sectionSchema{}
+	for i, provider := range sortedHeaderProviders {
+		headerSchema.add(provider)
+		sortedHeaderProviders[i] = newCachedHeaderProvider(provider, cacheTTL, providerTimeout)
+	}
+	sectionSchemas[status.HeaderSection] = headerSchema
+
 	c := &statusImplementation{
 		sortedSectionNames:       sortedSectionNames,
 		sortedProvidersBySection: sortedProvidersBySection,
 		sortedHeaderProviders:    sortedHeaderProviders,
+		sectionSchemas:           sectionSchemas,
 		log:                      deps.Log,
 	}
 
@@ -150,6 +175,11 @@ func newStatus(deps dependencies) provides {
 			"/status/sections",
 			"GET",
 		),
+		APIGetSchema: api.NewAgentEndpointProvider(
+			c.getSchema,
+			"/status/schema",
+			"GET",
+		),
 	}
 }
 
@@ -157,6 +187,14 @@ func (s *statusImplementation) GetStatus(format string, verbose bool, excludeSec
 	var errs []error
 
 	switch format {
+	case status.VersionedJSONFormat:
+		sections := []string{}
+		for _, section := range s.GetSections() {
+			if !present(section, excludeSections) {
+				sections = append(sections, section)
+			}
+		}
+		return s.getVersionedStatus(sections, verbose)
 	case "json":
 		stats := make(map[string]interface{})
 		for _, sc := range s.sortedHeaderProviders {
@@ -283,6 +321,15 @@ func (s *statusImplementation) GetStatus(format string, verbose bool, excludeSec
 func (s *statusImplementation) GetStatusBySections(sections []string, format string, verbose bool) ([]byte, error) {
 	var errs []error
 
+	if format == status.VersionedJSONFormat {
+		for _, section := range sections {
+			if !present(section, s.GetSections()) {
+				return nil, s.unknownSectionError(section)
+			}
+		}
+		return s.getVersionedStatus(sections, verbose)
+	}
+
 	if len(sections) == 1 && sections[0] == "header" {
 		providers := s.sortedHeaderProviders
 		switch format {
@@ -350,9 +397,7 @@ func (s *statusImplementation) GetStatusBySections(sections []string, format str
 	for _, section := range sections {
 		providersForSection, ok := s.sortedProvidersBySection[strings.ToLower(section)]
 		if !ok {
-			res, _ := json.Marshal(s.GetSections())
-			errorMsg := fmt.Sprintf("unknown status section '%s', available sections are: %s", section, string(res))
-			return nil, errors.New(errorMsg)
+			return nil, s.unknownSectionError(section)
 		}
 		providers = append(providers, providersForSection...)
 	}
@@ -415,7 +460,13 @@ func (s *statusImplementation) GetStatusBySections(sections []string, format str
 }
 
 func (s *statusImplementation) GetSections() []string {
-	return append([]string{"header"}, s.sortedSectionNames...)
+	return append([]string{status.HeaderSection}, s.sortedSectionNames...)
+}
+
+func (s *statusImplementation) unknownSectionError(section string) error {
+	res, _ := json.Marshal(s.GetSections())
+	errorMsg := fmt.Sprintf("unknown status section '%s', available sections are: %s", section, string(res))
+	return errors.New(errorMsg)
 }
 
 // fillFlare add the status.log to flares.
@@ -11,11 +11,14 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+
+	"github.com/DataDog/datadog-agent/comp/core/status"
 )
 
 var mimeTypeMap = map[string]string{
-	"text": "text/plain",
-	"json": "application/json",
+	"text":                     "text/plain",
+	"json":                     "application/json",
+	status.VersionedJSONFormat: "application/json",
 }
 
 // SetJSONError writes a server error as JSON with the correct http error code
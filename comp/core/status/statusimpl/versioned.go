@@ -0,0 +1,146 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package statusimpl
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/comp/core/status"
+)
+
+// unstableSectionDescription documents the sections left out of the compatibility promise of the document
+const unstableSectionDescription = "Section without a versioned schema for all its providers: its data may change in any release."
+
+// sectionSchema is the versioned schema of the JSON output of a section
+type sectionSchema struct {
+	version    int
+	properties map[string]interface{}
+	// unversioned is set when a provider of the section has no versioned schema
+	unversioned bool
+}
+
+// add merges the schema of the provider, if it has one, in the schema of its section
+func (s *sectionSchema) add(provider interface{}) {
+	schemaProvider, ok := provider.(status.SchemaProvider)
+	if !ok {
+		s.unversioned = true
+		return
+	}
+
+	s.version = max(s.version, schemaProvider.SchemaVersion())
+	for name, schema := range schemaProvider.Schema() {
+		if s.properties == nil {
+			s.properties = make(map[string]interface{})
+		}
+		s.properties[name] = schema
+	}
+}
+
+// stable returns whether the whole output of the section follows its versioned schema, only the stable sections are
+// covered by the compatibility promise of the versioned JSON status document
+func (s sectionSchema) stable() bool {
+	return s.version > 0 && !s.unversioned
+}
+
+// jsonSchema returns the JSON schema of the section in the versioned JSON status document
+func (s sectionSchema) jsonSchema() map[string]interface{} {
+	data := map[string]interface{}{"type": "object"}
+	if len(s.properties) > 0 {
+		data["properties"] = s.properties
+	}
+
+	schema := map[string]interface{}{
+		"type":     "object",
+		"required": []string{"schema_version", "stable", "data"},
+		"properties": map[string]interface{}{
+			"schema_version": map[string]interface{}{"const": s.version},
+			"stable":         map[string]interface{}{"const": s.stable()},
+			"data":           data,
+			"errors": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+	if !s.stable() {
+		schema["description"] = unstableSectionDescription
+	}
+	return schema
+}
+
+// getVersionedStatus renders the versioned JSON status document of the given sections
+func (s *statusImplementation) getVersionedStatus(sections []string, verbose bool) ([]byte, error) {
+	doc := status.VersionedStatus{
+		SchemaVersion: status.DocumentSchemaVersion,
+		Sections:      make(map[string]status.VersionedSection, len(sections)),
+	}
+
+	for _, section := range sections {
+		section = strings.ToLower(section)
+
+		var jsonFuncs []func(bool, map[string]interface{}) error
+		if section == status.HeaderSection {
+			for _, provider := range s.sortedHeaderProviders {
+				jsonFuncs = append(jsonFuncs, provider.JSON)
+			}
+		} else {
+			for _, provider := range s.sortedProvidersBySection[section] {
+				jsonFuncs = append(jsonFuncs, provider.JSON)
+			}
+		}
+
+		versionedSection := status.VersionedSection{
+			SchemaVersion: s.sectionSchemas[section].version,
+			Stable:        s.sectionSchemas[section].stable(),
+			Data:          make(map[string]interface{}),
+		}
+		for _, jsonFunc := range jsonFuncs {
+			if err := jsonFunc(verbose, versionedSection.Data); err != nil {
+				versionedSection.Errors = append(versionedSection.Errors, err.Error())
+			}
+		}
+		doc.Sections[section] = versionedSection
+	}
+
+	return json.Marshal(doc)
+}
+
+// GetSchema returns the JSON schema of the versioned JSON status document
+func (s *statusImplementation) GetSchema() ([]byte, error) {
+	sections := make(map[string]interface{})
+	for _, section := range s.GetSections() {
+		sections[section] = s.sectionSchemas[section].jsonSchema()
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"$schema":  "https://json-schema.org/draft/2020-12/schema",
+		"title":    "Agent status",
+		"type":     "object",
+		"required": []string{"schema_version", "sections"},
+		"properties": map[string]interface{}{
+			"schema_version": map[string]interface{}{"const": status.DocumentSchemaVersion},
+			"sections": map[string]interface{}{
+				"type":       "object",
+				"properties": sections,
+				// the sections of the optional components
+				"additionalProperties": sectionSchema{unversioned: true}.jsonSchema(),
+			},
+		},
+	})
+}
+
+func (s *statusImplementation) getSchema(w http.ResponseWriter, _ *http.Request) {
+	schema, err := s.GetSchema()
+	if err != nil {
+		SetJSONError(w, s.log.Errorf("Error getting the status schema. Error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(schema)
+}
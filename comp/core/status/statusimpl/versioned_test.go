@@ -0,0 +1,182 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package statusimpl
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/comp/core/status"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+type mockSchemaProvider struct {
+	mockProvider
+	version int
+	schema  map[string]interface{}
+}
+
+func (m mockSchemaProvider) SchemaVersion() int {
+	return m.version
+}
+
+func (m mockSchemaProvider) Schema() map[string]interface{} {
+	return m.schema
+}
+
+func getVersionedTestComp(t *testing.T) provides {
+	deps := fxutil.Test[dependencies](t, fx.Options(
+		config.MockModule(),
+		fx.Provide(func() log.Component { return logmock.New(t) }),
+		fx.Supply(
+			agentParams,
+			status.NewInformationProvider(mockSchemaProvider{
+				mockProvider: mockProvider{
+					data:    map[string]interface{}{"foo": "bar"},
+					name:    "a",
+					section: "Versioned",
+				},
+				version: 2,
+				schema:  map[string]interface{}{"foo": map[string]interface{}{"type": "string"}},
+			}),
+			status.NewInformationProvider(mockSchemaProvider{
+				mockProvider: mockProvider{
+					data:    map[string]interface{}{"foo2": "bar2"},
+					name:    "b",
+					section: "Versioned",
+				},
+				version: 1,
+				schema:  map[string]interface{}{"foo2": map[string]interface{}{"type": "string"}},
+			}),
+			status.NewInformationProvider(mockProvider{
+				name:        "x",
+				section:     "unversioned",
+				returnError: true,
+			}),
+		),
+	))
+
+	return newStatus(deps)
+}
+
+func TestGetVersionedStatus(t *testing.T) {
+	provides := getVersionedTestComp(t)
+
+	res, err := provides.Comp.GetStatus(status.VersionedJSONFormat, false)
+	require.NoError(t, err)
+
+	var doc status.VersionedStatus
+	require.NoError(t, json.Unmarshal(res, &doc))
+	assert.Equal(t, status.DocumentSchemaVersion, doc.SchemaVersion)
+	assert.Len(t, doc.Sections, 3)
+
+	header := doc.Sections[status.HeaderSection]
+	assert.Equal(t, 1, header.SchemaVersion)
+	assert.True(t, header.Stable)
+	assert.Contains(t, header.Data, "version")
+	assert.Contains(t, header.Data, "pid")
+
+	versioned := doc.Sections["versioned"]
+	assert.Equal(t, 2, versioned.SchemaVersion)
+	assert.True(t, versioned.Stable)
+	assert.Equal(t, map[string]interface{}{"foo": "bar", "foo2": "bar2"}, versioned.Data)
+	assert.Empty(t, versioned.Errors)
+
+	unversioned := doc.Sections["unversioned"]
+	assert.Equal(t, 0, unversioned.SchemaVersion)
+	assert.False(t, unversioned.Stable)
+	assert.Empty(t, unversioned.Data)
+	assert.Equal(t, []string{"JSON error"}, unversioned.Errors)
+
+	// the excluded sections are left out of the document
+	res, err = provides.Comp.GetStatus(status.VersionedJSONFormat, false, "unversioned", "header")
+	require.NoError(t, err)
+	doc = status.VersionedStatus{}
+	require.NoError(t, json.Unmarshal(res, &doc))
+	assert.Len(t, doc.Sections, 1)
+	assert.Contains(t, doc.Sections, "versioned")
+}
+
+func TestGetVersionedStatusBySections(t *testing.T) {
+	provides := getVersionedTestComp(t)
+
+	res, err := provides.Comp.GetStatusBySections([]string{"Versioned", "header"}, status.VersionedJSONFormat, false)
+	require.NoError(t, err)
+
+	var doc status.VersionedStatus
+	require.NoError(t, json.Unmarshal(res, &doc))
+	assert.Len(t, doc.Sections, 2)
+	assert.Contains(t, doc.Sections, "versioned")
+	assert.Contains(t, doc.Sections, "header")
+
+	_, err = provides.Comp.GetStatusBySections([]string{"unknown"}, status.VersionedJSONFormat, false)
+	assert.EqualError(t, err, `unknown status section 'unknown', available sections are: ["header","unversioned","versioned"]`)
+}
+
+func TestGetSchema(t *testing.T) {
+	provides := getVersionedTestComp(t)
+
+	rr := httptest.NewRecorder()
+	provides.APIGetSchema.Provider.HandlerFunc()(rr, httptest.NewRequest(http.MethodGet, "/status/schema", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var schema struct {
+		Properties struct {
+			SchemaVersion map[string]interface{} `json:"schema_version"`
+			Sections      struct {
+				Properties map[string]struct {
+					Description string `json:"description"`
+					Properties  struct {
+						SchemaVersion map[string]interface{} `json:"schema_version"`
+						Stable        map[string]interface{} `json:"stable"`
+						Data          map[string]interface{} `json:"data"`
+					} `json:"properties"`
+				} `json:"properties"`
+			} `json:"sections"`
+		} `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &schema))
+	assert.Equal(t, float64(status.DocumentSchemaVersion), schema.Properties.SchemaVersion["const"])
+
+	sections := schema.Properties.Sections.Properties
+	require.Len(t, sections, 3)
+	assert.Equal(t, float64(1), sections["header"].Properties.SchemaVersion["const"])
+	assert.Contains(t, sections["header"].Properties.Data["properties"], "agent_start_nano")
+	assert.Equal(t, float64(2), sections["versioned"].Properties.SchemaVersion["const"])
+	assert.Equal(t, true, sections["versioned"].Properties.Stable["const"])
+	assert.Empty(t, sections["versioned"].Description)
+	assert.Equal(t, map[string]interface{}{
+		"foo":  map[string]interface{}{"type": "string"},
+		"foo2": map[string]interface{}{"type": "string"},
+	}, sections["versioned"].Properties.Data["properties"])
+	assert.Equal(t, float64(0), sections["unversioned"].Properties.SchemaVersion["const"])
+	assert.Equal(t, false, sections["unversioned"].Properties.Stable["const"])
+	assert.Equal(t, unstableSectionDescription, sections["unversioned"].Description)
+	assert.NotContains(t, sections["unversioned"].Properties.Data, "properties")
+}
+
+func TestSectionSchemaStable(t *testing.T) {
+	schema := sectionSchema{}
+	assert.False(t, schema.stable())
+
+	schema.add(mockSchemaProvider{version: 1})
+	assert.True(t, schema.stable())
+
+	// a provider without schema makes the whole section unstable, whatever the version of the other ones
+	schema.add(mockProvider{})
+	assert.False(t, schema.stable())
+	assert.Equal(t, 1, schema.version)
+}
@@ -0,0 +1,44 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package status
+
+const (
+	// VersionedJSONFormat is the format of the versioned JSON status document, see VersionedStatus
+	VersionedJSONFormat = "versioned-json"
+
+	// DocumentSchemaVersion is the version of the layout of the versioned JSON status document. It is only bumped
+	// when the layout of the document changes, the changes of the content of a section bump the version of its schema.
+	DocumentSchemaVersion = 1
+
+	// HeaderSection is the name of the section of the header providers
+	HeaderSection = "header"
+)
+
+// VersionedStatus is the versioned JSON status document. Unlike the 'json' format, which merges the outputs of every
+// provider in a single object, each section is rendered on its own along with the version of its schema, so that the
+// automation parsing the status can detect the breaking changes across upgrades. Only the data of the stable sections,
+// whose providers all implement SchemaProvider, is covered by this promise: most core sections, like collector,
+// forwarder, dogstatsd, logs, aggregator or apm, don't have a versioned schema yet and their data may change in any
+// release.
+type VersionedStatus struct {
+	// SchemaVersion is the version of the layout of the document, see DocumentSchemaVersion
+	SchemaVersion int `json:"schema_version"`
+	// Sections are the status sections, by lower case name
+	Sections map[string]VersionedSection `json:"sections"`
+}
+
+// VersionedSection is a section of the versioned JSON status document
+type VersionedSection struct {
+	// SchemaVersion is the version of the schema of Data, 0 when the section has no versioned schema
+	SchemaVersion int `json:"schema_version"`
+	// Stable is true when all the providers of the section have a versioned schema, the data of the other sections
+	// isn't covered by the schema versioning and may change in any release
+	Stable bool `json:"stable"`
+	// Data is the JSON output of the providers of the section
+	Data map[string]interface{} `json:"data"`
+	// Errors are the errors returned by the providers of the section
+	Errors []string `json:"errors,omitempty"`
+}
@@ -244,6 +244,8 @@ func (c *WorkloadMetaCollector) handleContainer(ev workloadmeta.Event) []*types.
 		tagList.AddLow(tags.KubeGPUVendor, gpuVendor)
 	}
 
+	addExtraMetadataTags(tagList, container.ExtraMetadata)
+
 	low, orch, high, standard := tagList.Compute()
 	return []*types.TagInfo{
 		{
@@ -336,6 +338,7 @@ func (c *WorkloadMetaCollector) extractTagsFromPodEntity(pod *workloadmeta.Kuber
 	tagList.AddLow(tags.KubePriorityClass, pod.PriorityClass)
 	tagList.AddLow(tags.KubeQOS, pod.QOSClass)
 	tagList.AddLow(tags.KubeRuntimeClass, pod.RuntimeClass)
+	addExtraMetadataTags(tagList, pod.ExtraMetadata)
 
 	c.extractTagsFromPodLabels(pod, tagList)
 
@@ -487,6 +490,8 @@ func (c *WorkloadMetaCollector) handleECSTask(ev workloadmeta.Event) []*types.Ta
 		taskTags.AddLow(tags.EcsServiceName, strings.ToLower(task.ServiceName))
 	}
 
+	addExtraMetadataTags(taskTags, task.ExtraMetadata)
+
 	tagInfos := make([]*types.TagInfo, 0, len(task.Containers))
 	for _, taskContainer := range task.Containers {
 		container, err := c.store.GetContainer(taskContainer.ID)
@@ -529,6 +534,14 @@ func (c *WorkloadMetaCollector) handleECSTask(ev workloadmeta.Event) []*types.Ta
 	return tagInfos
 }
 
+// addExtraMetadataTags adds the metadata attached to an entity by the
+// workloadmeta enrichers as low cardinality tags.
+func addExtraMetadataTags(tagList *taglist.TagList, metadata map[string]string) {
+	for k, v := range metadata {
+		tagList.AddLow(k, v)
+	}
+}
+
 func (c *WorkloadMetaCollector) handleGardenContainer(container *workloadmeta.Container) []*types.TagInfo {
 	return []*types.TagInfo{
 		{
@@ -1691,6 +1691,35 @@ func TestHandleContainer(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "tags from enrichers",
+			container: workloadmeta.Container{
+				EntityID: entityID,
+				EntityMeta: workloadmeta.EntityMeta{
+					Name: containerName,
+					ExtraMetadata: map[string]string{
+						"team":        "container-integrations",
+						"cost_center": "42",
+					},
+				},
+			},
+			expected: []*types.TagInfo{
+				{
+					Source:   containerSource,
+					EntityID: taggerEntityID,
+					HighCardTags: []string{
+						fmt.Sprintf("container_name:%s", containerName),
+						fmt.Sprintf("container_id:%s", entityID.ID),
+					},
+					OrchestratorCardTags: []string{},
+					LowCardTags: []string{
+						"team:container-integrations",
+						"cost_center:42",
+					},
+					StandardTags: []string{},
+				},
+			},
+		},
 		{
 			name: "tags from environment",
 			container: workloadmeta.Container{
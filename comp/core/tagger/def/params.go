@@ -20,6 +20,9 @@ type RemoteParams struct {
 	// RemoteTokenFetcher is the function to fetch the token for the remote tagger
 	// If it returns an error the remote tagger will continue to attempt to fetch the token
 	RemoteTokenFetcher func(config.Component) func() (string, error)
+	// RemoteFilterExpression is the optional function returning the filter expression
+	// the server applies to the streamed entities, see types.ParseFilterExpression
+	RemoteFilterExpression func(config.Component) string
 }
 
 // Params provides local tagger parameters
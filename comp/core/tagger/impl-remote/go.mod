@@ -34,6 +34,7 @@ require (
 )
 
 require (
+	github.com/DataDog/datadog-agent/pkg/telemetry v0.61.0 // indirect
 	github.com/klauspost/compress v1.18.0 // indirect
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
 )
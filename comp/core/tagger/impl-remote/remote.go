@@ -89,6 +89,8 @@ type remoteTagger struct {
 	streamCtx    context.Context
 	streamCancel context.CancelFunc
 	filter       *types.Filter
+	// filterExpression is nil when the server streams all the entities of the filter
+	filterExpression *types.FilterExpression
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -149,6 +151,13 @@ func newRemoteTagger(params tagger.RemoteParams, cfg config.Component, log log.C
 		log:            log,
 	}
 
+	if params.RemoteFilterExpression != nil {
+		remotetagger.filterExpression, err = types.ParseFilterExpression(params.RemoteFilterExpression(cfg))
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote tagger filter expression: %w", err)
+		}
+	}
+
 	checkCard := cfg.GetString("checks_tag_cardinality")
 	dsdCard := cfg.GetString("dogstatsd_tag_cardinality")
 	remotetagger.checksCardinality, err = types.StringToTagCardinality(checkCard)
@@ -183,8 +192,9 @@ func (t *remoteTagger) Start(ctx context.Context) error {
 	})
 
 	var err error
-	t.conn, err = grpc.DialContext( //nolint:staticcheck // TODO (ASC) fix grpc.DialContext is deprecated
+	t.conn, err = grpcutil.NewClientConn(
 		t.ctx,
+		"remote_tagger",
 		t.options.Target,
 		grpc.WithTransportCredentials(creds),
 		grpc.WithContextDialer(func(_ context.Context, url string) (net.Conn, error) {
@@ -587,6 +597,7 @@ func (t *remoteTagger) startTaggerStream(maxElapsed time.Duration) error {
 			Cardinality: pb.TagCardinality(t.filter.GetCardinality()),
 			StreamingID: uuid.New().String(),
 			Prefixes:    prefixes,
+			Filter:      t.filterExpression.String(),
 		})
 		if err != nil {
 			t.log.Infof("unable to establish stream, will possibly retry: %s", err)
@@ -7,10 +7,15 @@ package taggerimpl
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"path/filepath"
 	"sync"
 	"time"
 
+	"github.com/spf13/cast"
+
 	"github.com/DataDog/datadog-agent/comp/core/config"
 	log "github.com/DataDog/datadog-agent/comp/core/log/def"
 	"github.com/DataDog/datadog-agent/comp/core/tagger/collectors"
@@ -23,6 +28,7 @@ import (
 	taggertypes "github.com/DataDog/datadog-agent/pkg/tagger/types"
 	"github.com/DataDog/datadog-agent/pkg/tagset"
 	"github.com/DataDog/datadog-agent/pkg/util/containers/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/flavor"
 	"github.com/DataDog/datadog-agent/pkg/util/option"
 )
 
@@ -55,7 +61,7 @@ type localTagger struct {
 
 func newLocalTagger(cfg config.Component, wmeta workloadmeta.Component, log log.Component, telemetryStore *telemetry.Store) (tagger.Component, error) {
 	return &localTagger{
-		tagStore:       tagstore.NewTagStore(telemetryStore),
+		tagStore:       tagstore.NewTagStore(telemetryStore, cardinalityBudgetFromConfig(cfg, log)),
 		workloadStore:  wmeta,
 		log:            log,
 		telemetryStore: telemetryStore,
@@ -63,6 +69,23 @@ func newLocalTagger(cfg config.Component, wmeta workloadmeta.Component, log log.
 	}, nil
 }
 
+// cardinalityBudgetFromConfig returns the cardinality budget of the tag sources
+func cardinalityBudgetFromConfig(cfg config.Component, log log.Component) tagstore.CardinalityBudget {
+	budget := tagstore.CardinalityBudget{
+		MaxValues:         cfg.GetInt("tagger_cardinality_budget.max_values"),
+		MaxValuesBySource: make(map[string]int),
+	}
+	for source, maxValues := range cfg.GetStringMap("tagger_cardinality_budget.max_values_by_source") {
+		value, err := cast.ToIntE(maxValues)
+		if err != nil {
+			log.Warnf("Invalid cardinality budget %#v for the tag source %s: %v", maxValues, source, err)
+			continue
+		}
+		budget.MaxValuesBySource[source] = value
+	}
+	return budget
+}
+
 // Start starts the workloadmeta collector and then it is ready for requests.
 func (t *localTagger) Start(ctx context.Context) error {
 	t.ctx, t.cancel = context.WithCancel(ctx)
@@ -74,6 +97,10 @@ func (t *localTagger) Start(ctx context.Context) error {
 		t.tagStore,
 	)
 
+	if t.snapshotEnabled() {
+		t.importSnapshot()
+	}
+
 	go t.tagStore.Run(t.ctx)
 	go t.collector.Run(t.ctx, t.cfg)
 
@@ -83,9 +110,81 @@ func (t *localTagger) Start(ctx context.Context) error {
 // Stop queues a shutdown of Tagger
 func (t *localTagger) Stop() error {
 	t.cancel()
+
+	if t.snapshotEnabled() {
+		path := t.snapshotPath()
+		if n, err := t.tagStore.ExportSnapshot(path); err != nil {
+			t.log.Warnf("Could not export the tagger snapshot to %s: %v", path, err)
+		} else {
+			t.log.Debugf("Exported the tags of %d entities to the tagger snapshot %s", n, path)
+		}
+	}
+
 	return nil
 }
 
+// snapshotEnabled returns whether the tags are persisted across restarts. The
+// Cluster Agent stores the tags of a single source by entity, so it can't hold
+// the tags of the snapshot with the ones collected.
+func (t *localTagger) snapshotEnabled() bool {
+	return t.cfg.GetBool("tagger_snapshot.enabled") && flavor.GetFlavor() != flavor.ClusterAgent
+}
+
+func (t *localTagger) snapshotPath() string {
+	if path := t.cfg.GetString("tagger_snapshot.path"); path != "" {
+		return path
+	}
+	return filepath.Join(t.cfg.GetString("run_path"), "tagger_snapshot.json")
+}
+
+// importSnapshot imports the tags of the snapshot, they're used until the
+// collectors set the tags of the entities, or until the TTL expires. The
+// entities unknown to workloadmeta are pruned once it's initialized.
+func (t *localTagger) importSnapshot() {
+	path := t.snapshotPath()
+	ttl := t.cfg.GetDuration("tagger_snapshot.ttl")
+	n, err := t.tagStore.ImportSnapshot(path, t.cfg.GetDuration("tagger_snapshot.max_age"), ttl)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.log.Warnf("Could not import the tagger snapshot %s: %v", path, err)
+		}
+		return
+	}
+	t.log.Infof("Imported the tags of %d entities from the tagger snapshot %s", n, path)
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		timeout := time.After(ttl)
+		for !t.workloadStore.IsInitialized() {
+			select {
+			case <-ticker.C:
+			case <-timeout:
+				// the tags of the snapshot expired
+				return
+			case <-t.ctx.Done():
+				return
+			}
+		}
+		if pruned := t.tagStore.PruneSnapshot(t.isKnownEntity); pruned > 0 {
+			t.log.Debugf("Pruned the tags of %d entities of the tagger snapshot unknown to workloadmeta", pruned)
+		}
+	}()
+}
+
+// isKnownEntity returns whether an entity of the snapshot still exists
+func (t *localTagger) isKnownEntity(entityID types.EntityID) bool {
+	switch entityID.GetPrefix() {
+	case types.ContainerID:
+		container, err := t.workloadStore.GetContainer(entityID.GetID())
+		return err == nil && container.State.Running
+	case types.KubernetesPodUID:
+		_, err := t.workloadStore.GetKubernetesPod(entityID.GetID())
+		return err == nil
+	}
+	return false
+}
+
 // getTags returns a read only list of tags for a given entity.
 func (t *localTagger) getTags(entityID types.EntityID, cardinality types.TagCardinality) (tagset.HashedTags, error) {
 	if entityID.Empty() {
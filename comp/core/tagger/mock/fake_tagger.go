@@ -47,7 +47,7 @@ type Provides struct {
 func New() Provides {
 	return Provides{
 		Comp: &FakeTagger{
-			store: tagstore.NewTagStore(nil),
+			store: tagstore.NewTagStore(nil, tagstore.CardinalityBudget{}),
 		},
 	}
 }
@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package server
+
+import (
+	"github.com/DataDog/datadog-agent/comp/core/tagger/types"
+)
+
+// entityMatcher filters the events of a stream with the tag conditions of its
+// filter expression. The conditions on the kinds and prefixes of the entities
+// are already applied by the subscription.
+//
+// The entities sent to the client are tracked, so that an entity whose tags
+// stop matching the expression is deleted on the client side.
+type entityMatcher struct {
+	expression  *types.FilterExpression
+	cardinality types.TagCardinality
+	sent        map[types.EntityID]struct{}
+}
+
+func newEntityMatcher(expression *types.FilterExpression, cardinality types.TagCardinality) *entityMatcher {
+	return &entityMatcher{
+		expression:  expression,
+		cardinality: cardinality,
+		sent:        make(map[types.EntityID]struct{}),
+	}
+}
+
+// match returns the event to send to the client, if any
+func (m *entityMatcher) match(event types.EntityEvent) (types.EntityEvent, bool) {
+	if !m.expression.FiltersTags() {
+		return event, true
+	}
+
+	entityID := event.Entity.ID
+	_, sent := m.sent[entityID]
+
+	if event.EventType == types.EventTypeDeleted {
+		delete(m.sent, entityID)
+		return event, sent
+	}
+
+	if m.expression.MatchesTags(event.Entity.GetTags(m.cardinality)) {
+		m.sent[entityID] = struct{}{}
+		if !sent {
+			event.EventType = types.EventTypeAdded
+		}
+		return event, true
+	}
+
+	if sent {
+		delete(m.sent, entityID)
+		return types.EntityEvent{
+			EventType: types.EventTypeDeleted,
+			Entity:    types.Entity{ID: entityID},
+		}, true
+	}
+	return event, false
+}
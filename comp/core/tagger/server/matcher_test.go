@@ -0,0 +1,69 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/tagger/types"
+)
+
+func TestEntityMatcher(t *testing.T) {
+	expression, err := types.ParseFilterExpression("has:service")
+	require.NoError(t, err)
+	m := newEntityMatcher(expression, types.LowCardinality)
+
+	entityID := types.NewEntityID(types.ContainerID, "container")
+	event := func(eventType types.EventType, tags ...string) types.EntityEvent {
+		return types.EntityEvent{
+			EventType: eventType,
+			Entity:    types.Entity{ID: entityID, LowCardinalityTags: tags},
+		}
+	}
+
+	// an entity which doesn't match isn't sent
+	_, ok := m.match(event(types.EventTypeAdded, "env:prod"))
+	assert.False(t, ok)
+
+	// an entity which starts matching is added
+	e, ok := m.match(event(types.EventTypeModified, "env:prod", "service:redis"))
+	assert.True(t, ok)
+	assert.Equal(t, types.EventTypeAdded, e.EventType)
+
+	e, ok = m.match(event(types.EventTypeModified, "service:redis"))
+	assert.True(t, ok)
+	assert.Equal(t, types.EventTypeModified, e.EventType)
+
+	// an entity which stops matching is deleted
+	e, ok = m.match(event(types.EventTypeModified, "env:prod"))
+	assert.True(t, ok)
+	assert.Equal(t, types.EventTypeDeleted, e.EventType)
+	assert.Equal(t, entityID, e.Entity.ID)
+
+	// the deletion of an entity which wasn't sent isn't forwarded
+	_, ok = m.match(event(types.EventTypeDeleted))
+	assert.False(t, ok)
+}
+
+func TestEntityMatcherWithoutTagTerms(t *testing.T) {
+	expression, err := types.ParseFilterExpression("kind:container")
+	require.NoError(t, err)
+
+	for _, m := range []*entityMatcher{
+		newEntityMatcher(nil, types.LowCardinality),
+		newEntityMatcher(expression, types.LowCardinality),
+	} {
+		for _, eventType := range []types.EventType{types.EventTypeModified, types.EventTypeDeleted} {
+			in := types.EntityEvent{EventType: eventType, Entity: types.Entity{ID: types.NewEntityID(types.ContainerID, "container")}}
+			out, ok := m.match(in)
+			assert.True(t, ok)
+			assert.Equal(t, in, out)
+		}
+	}
+}
@@ -47,14 +47,19 @@ func NewServer(t tagger.Component, maxEventSize int, maxParallelSync int) *Serve
 }
 
 // TaggerStreamEntities subscribes to added, removed, or changed entities in the Tagger
-// and streams them to clients as pb.StreamTagsResponse events. Filtering is as
-// of yet not implemented.
+// and streams them to clients as pb.StreamTagsResponse events. The entities are
+// filtered by the prefixes and the filter expression of the request.
 func (s *Server) TaggerStreamEntities(in *pb.StreamTagsRequest, out pb.AgentSecure_TaggerStreamEntitiesServer) error {
 	cardinality, err := proto.Pb2TaggerCardinality(in.GetCardinality())
 	if err != nil {
 		return err
 	}
 
+	expression, err := types.ParseFilterExpression(in.GetFilter())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid filter expression: %s", err)
+	}
+
 	ticker := time.NewTicker(streamKeepAliveInterval)
 	defer ticker.Stop()
 
@@ -96,7 +101,8 @@ func (s *Server) TaggerStreamEntities(in *pb.StreamTagsRequest, out pb.AgentSecu
 		filterBuilder = filterBuilder.Include(types.EntityIDPrefix(prefix))
 	}
 
-	filter := filterBuilder.Build(cardinality)
+	filter := filterBuilder.WithExpression(expression).Build(cardinality)
+	matcher := newEntityMatcher(expression, cardinality)
 
 	streamingID := in.GetStreamingID()
 	if streamingID == "" {
@@ -141,6 +147,11 @@ func (s *Server) TaggerStreamEntities(in *pb.StreamTagsRequest, out pb.AgentSecu
 
 			responseEvents := make([]*pb.StreamTagsEvent, 0, len(events))
 			for _, event := range events {
+				event, ok := matcher.match(event)
+				if !ok {
+					continue
+				}
+
 				e, err := proto.Tagger2PbEntityEvent(event)
 				if err != nil {
 					log.Warnf("can't convert tagger entity to protobuf: %s", err)
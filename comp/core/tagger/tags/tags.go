@@ -190,4 +190,6 @@ const (
 	ContainerID = "container_id"
 	// RancherContainer is the tag for the Rancher container name
 	RancherContainer = "rancher_container"
+	// PodmanPod is the tag for the name of the Podman pod of a container
+	PodmanPod = "podman_pod"
 )
@@ -0,0 +1,154 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package tagstore
+
+import (
+	"strings"
+
+	"github.com/DataDog/datadog-agent/comp/core/tagger/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// CardinalityBudget limits the number of unique values of each tag a source
+// can emit, so that a single misbehaving label (e.g. pod-template-hash in the
+// high cardinality tags) can't explode the number of timeseries downstream.
+//
+// Once a tag of a source reached its budget, its values which weren't seen
+// before are dropped from the tags of the entities. The values are released
+// once no entity holds them anymore, e.g. when the pods of a previous
+// ReplicaSet are deleted, so that the budget bounds the number of values in
+// use rather than the number of values ever seen. A budget of 0 is unlimited.
+type CardinalityBudget struct {
+	// MaxValues is the budget of the sources without a budget of their own
+	MaxValues int
+	// MaxValuesBySource are the budgets of the sources, by source name
+	MaxValuesBySource map[string]int
+}
+
+func (b CardinalityBudget) forSource(source string) int {
+	if maxValues, ok := b.MaxValuesBySource[source]; ok {
+		return maxValues
+	}
+	return b.MaxValues
+}
+
+// budgetEnforcer tracks the unique values of the tags emitted by each source,
+// and drops the tags over the budget. The tracked values are rebuilt from the
+// entities of the store when it's pruned, so they are bounded by the tags of
+// the stored entities. It isn't thread safe, it's used with the lock of the
+// TagStore held.
+type budgetEnforcer struct {
+	budget CardinalityBudget
+	// values are the values seen by source, then by tag name
+	values map[string]map[string]map[string]struct{}
+	// exceeded are the tags which exceeded their budget, they are logged once
+	exceeded map[string]map[string]struct{}
+
+	telemetryStore *telemetry.Store
+}
+
+func newBudgetEnforcer(budget CardinalityBudget, telemetryStore *telemetry.Store) *budgetEnforcer {
+	return &budgetEnforcer{
+		budget:         budget,
+		values:         make(map[string]map[string]map[string]struct{}),
+		exceeded:       make(map[string]map[string]struct{}),
+		telemetryStore: telemetryStore,
+	}
+}
+
+// filter returns the tags of a source within its budget
+func (e *budgetEnforcer) filter(source string, tags []string) []string {
+	maxValues := e.budget.forSource(source)
+	if maxValues <= 0 || len(tags) == 0 {
+		return tags
+	}
+
+	sourceValues, ok := e.values[source]
+	if !ok {
+		sourceValues = make(map[string]map[string]struct{})
+		e.values[source] = sourceValues
+	}
+
+	filtered := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		name, value, _ := strings.Cut(tag, ":")
+		values, ok := sourceValues[name]
+		if !ok {
+			values = make(map[string]struct{})
+			sourceValues[name] = values
+		}
+
+		if _, seen := values[value]; !seen {
+			if len(values) >= maxValues {
+				e.drop(source, name, maxValues)
+				continue
+			}
+			values[value] = struct{}{}
+		}
+		filtered = append(filtered, tag)
+	}
+	return filtered
+}
+
+func (e *budgetEnforcer) drop(source, name string, maxValues int) {
+	if e.telemetryStore != nil {
+		e.telemetryStore.DroppedTags.Inc(source, name)
+	}
+
+	exceeded, ok := e.exceeded[source]
+	if !ok {
+		exceeded = make(map[string]struct{})
+		e.exceeded[source] = exceeded
+	}
+	if _, ok := exceeded[name]; !ok {
+		exceeded[name] = struct{}{}
+		log.Warnf("Tag %q from source %s exceeded its budget of %d unique values, its new values are dropped", name, source, maxValues)
+	}
+}
+
+// reset forgets the tracked values, before they are tracked again with track
+// for the tags still held by the entities
+func (e *budgetEnforcer) reset() {
+	e.values = make(map[string]map[string]map[string]struct{})
+}
+
+// track records the values of tags already admitted for a source, without
+// enforcing the budget
+func (e *budgetEnforcer) track(source string, tags []string) {
+	if e.budget.forSource(source) <= 0 || len(tags) == 0 {
+		return
+	}
+
+	sourceValues, ok := e.values[source]
+	if !ok {
+		sourceValues = make(map[string]map[string]struct{})
+		e.values[source] = sourceValues
+	}
+	for _, tag := range tags {
+		name, value, _ := strings.Cut(tag, ":")
+		values, ok := sourceValues[name]
+		if !ok {
+			values = make(map[string]struct{})
+			sourceValues[name] = values
+		}
+		values[value] = struct{}{}
+	}
+}
+
+// forgetExceeded forgets the exceeded tags which aren't tracked anymore, so
+// that they are logged again if they exceed their budget again
+func (e *budgetEnforcer) forgetExceeded() {
+	for source, names := range e.exceeded {
+		for name := range names {
+			if _, ok := e.values[source][name]; !ok {
+				delete(names, name)
+			}
+		}
+		if len(names) == 0 {
+			delete(e.exceeded, source)
+		}
+	}
+}
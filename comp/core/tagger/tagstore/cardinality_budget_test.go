@@ -0,0 +1,67 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package tagstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+
+	taggerTelemetry "github.com/DataDog/datadog-agent/comp/core/tagger/telemetry"
+	"github.com/DataDog/datadog-agent/comp/core/telemetry"
+	"github.com/DataDog/datadog-agent/comp/core/tagger/types"
+	"github.com/DataDog/datadog-agent/comp/core/telemetry/telemetryimpl"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func TestBudgetEnforcerFilter(t *testing.T) {
+	tel := fxutil.Test[telemetry.Component](t, telemetryimpl.MockModule())
+	telemetryStore := taggerTelemetry.NewStore(tel)
+
+	e := newBudgetEnforcer(CardinalityBudget{
+		MaxValues:         2,
+		MaxValuesBySource: map[string]int{"unlimited": 0, "strict": 1},
+	}, telemetryStore)
+
+	assert.Equal(t, []string{"pod_template_hash:a", "env:prod"}, e.filter("source", []string{"pod_template_hash:a", "env:prod"}))
+	assert.Equal(t, []string{"pod_template_hash:b", "env:prod"}, e.filter("source", []string{"pod_template_hash:b", "env:prod"}))
+	// the values over the budget are dropped, the values already seen are kept
+	assert.Equal(t, []string{"env:prod"}, e.filter("source", []string{"pod_template_hash:c", "env:prod"}))
+	assert.Equal(t, []string{"pod_template_hash:a", "env:staging"}, e.filter("source", []string{"pod_template_hash:a", "env:staging"}))
+	assert.Equal(t, []string{"pod_template_hash:d"}, e.filter("other", []string{"pod_template_hash:d"}))
+
+	// the budgets of the sources take precedence over the default one
+	assert.Equal(t, []string{"foo:a"}, e.filter("strict", []string{"foo:a", "foo:b"}))
+	assert.Equal(t, []string{"foo:a", "foo:b", "foo:c"}, e.filter("unlimited", []string{"foo:a", "foo:b", "foo:c"}))
+}
+
+func TestBudgetReleasedOnPrune(t *testing.T) {
+	clock := clock.NewMock()
+	store := newTagStoreWithClock(clock, nil)
+	store.setCardinalityBudget(CardinalityBudget{MaxValues: 1})
+
+	first := types.NewEntityID(types.ContainerID, "first")
+	second := types.NewEntityID(types.ContainerID, "second")
+	store.ProcessTagInfo([]*types.TagInfo{{Source: "source", EntityID: first, LowCardTags: []string{"pod_template_hash:a"}}})
+	store.ProcessTagInfo([]*types.TagInfo{{Source: "source", EntityID: second, LowCardTags: []string{"pod_template_hash:b"}}})
+	assert.Empty(t, store.Lookup(second, types.LowCardinality))
+
+	// the value is released once the entity holding it is pruned
+	store.ProcessTagInfo([]*types.TagInfo{{Source: "source", EntityID: first, DeleteEntity: true}})
+	clock.Add(deletedTTL + time.Second)
+	store.Prune()
+	assert.Empty(t, store.budget.values["source"]["pod_template_hash"])
+
+	store.ProcessTagInfo([]*types.TagInfo{{Source: "source", EntityID: second, LowCardTags: []string{"pod_template_hash:b"}}})
+	assert.Equal(t, []string{"pod_template_hash:b"}, store.Lookup(second, types.LowCardinality))
+
+	// the values held by the entities are still tracked after the prune
+	store.Prune()
+	store.ProcessTagInfo([]*types.TagInfo{{Source: "source", EntityID: first, LowCardTags: []string{"pod_template_hash:c"}}})
+	assert.Empty(t, store.Lookup(first, types.LowCardinality))
+}
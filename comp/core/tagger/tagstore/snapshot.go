@@ -0,0 +1,214 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package tagstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/tagger/types"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// The tags of the containers and pods are exported to a snapshot when the agent
+// stops, and imported when it starts, so that the metrics sent while the
+// collectors warm up aren't missing their tags.
+//
+// The imported tags are stored under their own source, which expires after a
+// TTL, and is removed as soon as a collector sets the tags of the entity.
+
+// SnapshotSource is the source of the tags imported from a snapshot
+const SnapshotSource = "snapshot"
+
+const snapshotVersion = 1
+
+var snapshotCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+// snapshotPrefixes are the kinds of entities exported to the snapshot, they
+// can be validated against workloadmeta
+var snapshotPrefixes = map[types.EntityIDPrefix]struct{}{
+	types.ContainerID:      {},
+	types.KubernetesPodUID: {},
+}
+
+// snapshot is the format of the snapshot file, the checksum is computed on the
+// entities as written on disk
+type snapshot struct {
+	Version   int
+	CreatedAt time.Time
+	Checksum  uint32
+	Entities  json.RawMessage
+}
+
+type snapshotEntity struct {
+	ID                   string   `json:"id"`
+	LowCardTags          []string `json:"low,omitempty"`
+	OrchestratorCardTags []string `json:"orchestrator,omitempty"`
+	HighCardTags         []string `json:"high,omitempty"`
+	StandardTags         []string `json:"standard,omitempty"`
+}
+
+// ExportSnapshot writes the tags of the containers and pods to a snapshot file
+func (s *TagStore) ExportSnapshot(path string) (int, error) {
+	var entities []snapshotEntity
+
+	s.RLock()
+	s.store.ForEach(nil, func(eid types.EntityID, et EntityTags) {
+		if _, ok := snapshotPrefixes[eid.GetPrefix()]; !ok {
+			return
+		}
+		entity := et.toEntity()
+		if len(entity.LowCardinalityTags)+len(entity.OrchestratorCardinalityTags)+len(entity.HighCardinalityTags) == 0 {
+			return
+		}
+		entities = append(entities, snapshotEntity{
+			ID:                   eid.String(),
+			LowCardTags:          entity.LowCardinalityTags,
+			OrchestratorCardTags: entity.OrchestratorCardinalityTags,
+			HighCardTags:         entity.HighCardinalityTags,
+			StandardTags:         entity.StandardTags,
+		})
+	})
+	s.RUnlock()
+
+	b, err := json.Marshal(entities)
+	if err != nil {
+		return 0, err
+	}
+	b, err = json.Marshal(snapshot{
+		Version:   snapshotVersion,
+		CreatedAt: s.clock.Now(),
+		Checksum:  crc32.Checksum(b, snapshotCRCTable),
+		Entities:  b,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// the snapshot is written to a temporary file first, so that it's never
+	// left half-written
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+	return len(entities), os.Rename(f.Name(), path)
+}
+
+// ImportSnapshot reads a snapshot file, and stores the tags of its entities
+// until the TTL expires. Snapshots older than maxAge are ignored.
+func (s *TagStore) ImportSnapshot(path string, maxAge, ttl time.Duration) (int, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(b, &snap); err != nil {
+		return 0, fmt.Errorf("malformed snapshot: %w", err)
+	}
+	if snap.Version != snapshotVersion {
+		return 0, fmt.Errorf("unsupported snapshot version %d", snap.Version)
+	}
+	if crc32.Checksum(snap.Entities, snapshotCRCTable) != snap.Checksum {
+		return 0, errors.New("snapshot checksum mismatch")
+	}
+	now := s.clock.Now()
+	if age := now.Sub(snap.CreatedAt); age > maxAge {
+		return 0, fmt.Errorf("snapshot is too old: %s", age.Truncate(time.Second))
+	}
+
+	var entities []snapshotEntity
+	if err := json.Unmarshal(snap.Entities, &entities); err != nil {
+		return 0, fmt.Errorf("malformed snapshot entities: %w", err)
+	}
+
+	tagInfos := make([]*types.TagInfo, 0, len(entities))
+	for _, entity := range entities {
+		prefix, id, err := types.ExtractPrefixAndID(entity.ID)
+		if err != nil {
+			log.Debugf("Skipping snapshot entity: %v", err)
+			continue
+		}
+		if _, ok := snapshotPrefixes[prefix]; !ok {
+			continue
+		}
+		tagInfos = append(tagInfos, &types.TagInfo{
+			Source:               SnapshotSource,
+			EntityID:             types.NewEntityID(prefix, id),
+			LowCardTags:          entity.LowCardTags,
+			OrchestratorCardTags: entity.OrchestratorCardTags,
+			HighCardTags:         entity.HighCardTags,
+			StandardTags:         entity.StandardTags,
+			ExpiryDate:           now.Add(ttl),
+		})
+	}
+
+	s.ProcessTagInfo(tagInfos)
+	return len(tagInfos), nil
+}
+
+// PruneSnapshot removes the tags imported from the snapshot of the entities
+// which aren't valid anymore, e.g. the containers which were deleted while the
+// agent was stopped.
+func (s *TagStore) PruneSnapshot(isValid func(types.EntityID) bool) int {
+	s.Lock()
+	defer s.Unlock()
+
+	var pruned []types.EntityID
+	s.store.ForEach(nil, func(eid types.EntityID, et EntityTags) {
+		if et.tagsForSource(SnapshotSource) != nil && !isValid(eid) {
+			pruned = append(pruned, eid)
+		}
+	})
+
+	events := make([]types.EntityEvent, 0, len(pruned))
+	for _, eid := range pruned {
+		if event, ok := s.removeSnapshotSource(eid); ok {
+			events = append(events, event)
+		}
+	}
+	if len(events) > 0 {
+		s.notifySubscribers(events)
+	}
+	return len(pruned)
+}
+
+// removeSnapshotSource removes the tags imported from the snapshot of an
+// entity, it must be called with the lock held
+func (s *TagStore) removeSnapshotSource(eid types.EntityID) (types.EntityEvent, bool) {
+	et, ok := s.store.Get(eid)
+	if !ok || et.tagsForSource(SnapshotSource) == nil {
+		return types.EntityEvent{}, false
+	}
+
+	s.deleteSource(et, SnapshotSource)
+	if et.shouldRemove() {
+		s.store.Unset(eid)
+		return types.EntityEvent{EventType: types.EventTypeDeleted, Entity: et.toEntity()}, true
+	}
+	return types.EntityEvent{EventType: types.EventTypeModified, Entity: et.toEntity()}, true
+}
+
+// deleteSource deletes the tags of a source of an entity right away, it must
+// be called with the lock held
+func (s *TagStore) deleteSource(et EntityTags, source string) {
+	now := s.clock.Now()
+	et.setSourceExpiration(source, now.Add(-time.Nanosecond))
+	et.deleteExpired(now)
+}
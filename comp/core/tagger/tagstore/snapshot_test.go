@@ -0,0 +1,139 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package tagstore
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/tagger/types"
+)
+
+func (s *StoreTestSuite) TestSnapshotExportImport() {
+	containerID := types.NewEntityID(types.ContainerID, "container")
+	podID := types.NewEntityID(types.KubernetesPodUID, "pod")
+	processID := types.NewEntityID(types.Process, "1234")
+
+	s.tagstore.ProcessTagInfo([]*types.TagInfo{
+		{
+			Source:       "source1",
+			EntityID:     containerID,
+			LowCardTags:  []string{"image_name:redis", "service:cache"},
+			HighCardTags: []string{"container_id:container"},
+			StandardTags: []string{"service:cache"},
+		},
+		{
+			Source:               "source1",
+			EntityID:             podID,
+			OrchestratorCardTags: []string{"pod_name:redis-0"},
+		},
+		{
+			Source:      "source1",
+			EntityID:    processID,
+			LowCardTags: []string{"tag"},
+		},
+	})
+
+	path := filepath.Join(s.T().TempDir(), "tagger_snapshot.json")
+	n, err := s.tagstore.ExportSnapshot(path)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, n)
+
+	s.clock.Add(time.Minute)
+	store := newTagStoreWithClock(s.clock, nil)
+	n, err = store.ImportSnapshot(path, 5*time.Minute, 2*time.Minute)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, n)
+
+	entity, err := store.GetEntity(containerID)
+	require.NoError(s.T(), err)
+	assert.ElementsMatch(s.T(), []string{"image_name:redis", "service:cache"}, entity.LowCardinalityTags)
+	assert.ElementsMatch(s.T(), []string{"container_id:container"}, entity.HighCardinalityTags)
+	assert.ElementsMatch(s.T(), []string{"service:cache"}, entity.StandardTags)
+	assert.ElementsMatch(s.T(), []string{"pod_name:redis-0"}, store.Lookup(podID, types.OrchestratorCardinality))
+	assert.Empty(s.T(), store.Lookup(processID, types.HighCardinality))
+
+	// the tags of the snapshot expire after the TTL
+	s.clock.Add(3 * time.Minute)
+	store.Prune()
+	assert.Empty(s.T(), store.Lookup(containerID, types.HighCardinality))
+	assert.Empty(s.T(), store.Lookup(podID, types.HighCardinality))
+}
+
+func (s *StoreTestSuite) TestSnapshotImportInvalid() {
+	s.tagstore.ProcessTagInfo([]*types.TagInfo{
+		{
+			Source:      "source1",
+			EntityID:    types.NewEntityID(types.ContainerID, "container"),
+			LowCardTags: []string{"image_name:redis"},
+		},
+	})
+
+	path := filepath.Join(s.T().TempDir(), "tagger_snapshot.json")
+	_, err := s.tagstore.ExportSnapshot(path)
+	require.NoError(s.T(), err)
+
+	store := newTagStoreWithClock(s.clock, nil)
+
+	// a snapshot older than the max age is ignored
+	s.clock.Add(10 * time.Minute)
+	_, err = store.ImportSnapshot(path, 5*time.Minute, 2*time.Minute)
+	assert.ErrorContains(s.T(), err, "too old")
+
+	// a corrupted snapshot is ignored
+	b, err := os.ReadFile(path)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), os.WriteFile(path, bytes.Replace(b, []byte("redis"), []byte("mysql"), 1), 0600))
+	_, err = store.ImportSnapshot(path, time.Hour, 2*time.Minute)
+	assert.ErrorContains(s.T(), err, "checksum mismatch")
+
+	_, err = store.ImportSnapshot(filepath.Join(s.T().TempDir(), "missing.json"), time.Hour, 2*time.Minute)
+	assert.ErrorIs(s.T(), err, os.ErrNotExist)
+
+	assert.Equal(s.T(), 0, store.store.Size())
+}
+
+func (s *StoreTestSuite) TestSnapshotSupersededByCollectors() {
+	containerID := types.NewEntityID(types.ContainerID, "container")
+	deletedID := types.NewEntityID(types.ContainerID, "deleted")
+
+	s.tagstore.ProcessTagInfo([]*types.TagInfo{
+		{
+			Source:      SnapshotSource,
+			EntityID:    containerID,
+			LowCardTags: []string{"image_tag:1.0"},
+			ExpiryDate:  s.clock.Now().Add(time.Minute),
+		},
+		{
+			Source:      SnapshotSource,
+			EntityID:    deletedID,
+			LowCardTags: []string{"image_tag:1.0"},
+			ExpiryDate:  s.clock.Now().Add(time.Minute),
+		},
+	})
+
+	// the tags collected replace the ones of the snapshot
+	s.tagstore.ProcessTagInfo([]*types.TagInfo{
+		{
+			Source:      "source1",
+			EntityID:    containerID,
+			LowCardTags: []string{"image_tag:2.0"},
+		},
+	})
+	assert.Equal(s.T(), []string{"image_tag:2.0"}, s.tagstore.Lookup(containerID, types.LowCardinality))
+
+	// the entities unknown to workloadmeta are pruned
+	pruned := s.tagstore.PruneSnapshot(func(types.EntityID) bool { return false })
+	assert.Equal(s.T(), 1, pruned)
+	assert.Equal(s.T(), []string{"image_tag:2.0"}, s.tagstore.Lookup(containerID, types.LowCardinality))
+	_, err := s.tagstore.GetEntity(deletedID)
+	assert.ErrorIs(s.T(), err, ErrNotFound)
+}
@@ -44,12 +44,17 @@ type TagStore struct {
 
 	clock clock.Clock
 
+	// budget is nil when the cardinality budget isn't enforced
+	budget *budgetEnforcer
+
 	telemetryStore *telemetry.Store
 }
 
 // NewTagStore creates new LocalTaggerTagStore.
-func NewTagStore(telemetryStore *telemetry.Store) *TagStore {
-	return newTagStoreWithClock(clock.New(), telemetryStore)
+func NewTagStore(telemetryStore *telemetry.Store, budget CardinalityBudget) *TagStore {
+	s := newTagStoreWithClock(clock.New(), telemetryStore)
+	s.setCardinalityBudget(budget)
+	return s
 }
 
 func newTagStoreWithClock(clock clock.Clock, telemetryStore *telemetry.Store) *TagStore {
@@ -64,6 +69,14 @@ func newTagStoreWithClock(clock clock.Clock, telemetryStore *telemetry.Store) *T
 	}
 }
 
+func (s *TagStore) setCardinalityBudget(budget CardinalityBudget) {
+	if budget.MaxValues <= 0 && len(budget.MaxValuesBySource) == 0 {
+		s.budget = nil
+		return
+	}
+	s.budget = newBudgetEnforcer(budget, s.telemetryStore)
+}
+
 // Run performs background maintenance for TagStore.
 func (s *TagStore) Run(ctx context.Context) {
 	pruneTicker := time.NewTicker(1 * time.Minute)
@@ -126,6 +139,12 @@ func (s *TagStore) ProcessTagInfo(tagInfos []*types.TagInfo) {
 			standardTags:         info.StandardTags,
 			expiryDate:           info.ExpiryDate,
 		}
+		if s.budget != nil {
+			newSt.lowCardTags = s.budget.filter(info.Source, newSt.lowCardTags)
+			newSt.orchestratorCardTags = s.budget.filter(info.Source, newSt.orchestratorCardTags)
+			newSt.highCardTags = s.budget.filter(info.Source, newSt.highCardTags)
+			newSt.standardTags = s.budget.filter(info.Source, newSt.standardTags)
+		}
 
 		eventType := types.EventTypeModified
 		if exist {
@@ -143,6 +162,10 @@ func (s *TagStore) ProcessTagInfo(tagInfos []*types.TagInfo) {
 			s.telemetryStore.UpdatedEntities.Inc()
 		}
 		storedTags.setTagsForSource(info.Source, newSt)
+		if info.Source != SnapshotSource && storedTags.tagsForSource(SnapshotSource) != nil {
+			// the tags collected supersede the ones imported from the snapshot
+			s.deleteSource(storedTags, SnapshotSource)
+		}
 
 		events = append(events, types.EntityEvent{
 			EventType: eventType,
@@ -242,11 +265,34 @@ func (s *TagStore) Prune() {
 		}
 	})
 
+	if s.budget != nil {
+		s.rebuildBudget()
+	}
+
 	if len(events) > 0 {
 		s.notifySubscribers(events)
 	}
 }
 
+// rebuildBudget tracks again the tag values held by the stored entities, to
+// release the budget of the values no entity holds anymore
+func (s *TagStore) rebuildBudget() {
+	s.budget.reset()
+	s.store.ForEach(nil, func(_ types.EntityID, et EntityTags) {
+		for _, source := range et.sources() {
+			tags := et.tagsForSource(source)
+			if tags == nil {
+				continue
+			}
+			s.budget.track(source, tags.lowCardTags)
+			s.budget.track(source, tags.orchestratorCardTags)
+			s.budget.track(source, tags.highCardTags)
+			s.budget.track(source, tags.standardTags)
+		}
+	})
+	s.budget.forgetExceeded()
+}
+
 // LookupHashed gets tags from the store and returns them as a HashedTags instance.
 func (s *TagStore) LookupHashed(entityID types.EntityID, cardinality types.TagCardinality) tagset.HashedTags {
 	s.RLock()
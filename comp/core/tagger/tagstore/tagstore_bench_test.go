@@ -47,7 +47,7 @@ func init() {
 func BenchmarkTagStoreThroughput(b *testing.B) {
 	tel := fxutil.Test[telemetry.Component](b, telemetryimpl.MockModule())
 	telemetryStore := taggerTelemetry.NewStore(tel)
-	store := NewTagStore(telemetryStore)
+	store := NewTagStore(telemetryStore, CardinalityBudget{})
 
 	doneCh := make(chan struct{})
 	pruneTicker := time.NewTicker(time.Second)
@@ -94,7 +94,7 @@ func BenchmarkTagStore_processTagInfo(b *testing.B) {
 	tel := fxutil.Test[telemetry.Component](b, telemetryimpl.MockModule())
 	telemetryStore := taggerTelemetry.NewStore(tel)
 
-	store := NewTagStore(telemetryStore)
+	store := NewTagStore(telemetryStore, CardinalityBudget{})
 
 	for i := 0; i < b.N; i++ {
 		processRandomTagInfoBatch(store)
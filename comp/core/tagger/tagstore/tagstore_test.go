@@ -96,6 +96,40 @@ func (s *StoreTestSuite) TestLookup() {
 	assert.Nil(s.T(), tagsNone)
 }
 
+func (s *StoreTestSuite) TestCardinalityBudget() {
+	s.tagstore.setCardinalityBudget(CardinalityBudget{MaxValues: 1})
+
+	entityID1 := types.NewEntityID(types.KubernetesPodUID, "pod1")
+	entityID2 := types.NewEntityID(types.KubernetesPodUID, "pod2")
+	s.tagstore.ProcessTagInfo([]*types.TagInfo{
+		{
+			Source:       "source1",
+			EntityID:     entityID1,
+			LowCardTags:  []string{"kube_deployment:app"},
+			HighCardTags: []string{"pod_template_hash:a"},
+		},
+		{
+			Source:       "source1",
+			EntityID:     entityID2,
+			LowCardTags:  []string{"kube_deployment:app"},
+			HighCardTags: []string{"pod_template_hash:b"},
+		},
+		{
+			Source:       "source2",
+			EntityID:     entityID2,
+			HighCardTags: []string{"pod_template_hash:b"},
+		},
+	})
+
+	assert.ElementsMatch(s.T(), []string{"kube_deployment:app", "pod_template_hash:a"}, s.tagstore.Lookup(entityID1, types.HighCardinality))
+	assert.ElementsMatch(s.T(), []string{"kube_deployment:app", "pod_template_hash:b"}, s.tagstore.Lookup(entityID2, types.HighCardinality))
+
+	storedTags, exists := s.tagstore.store.Get(entityID2)
+	require.True(s.T(), exists)
+	assert.Empty(s.T(), storedTags.tagsForSource("source1").highCardTags)
+	assert.Equal(s.T(), []string{"pod_template_hash:b"}, storedTags.tagsForSource("source2").highCardTags)
+}
+
 func (s *StoreTestSuite) TestLookupHashedWithEntityStr() {
 	entityID := types.NewEntityID(types.ContainerID, "test")
 	s.tagstore.ProcessTagInfo([]*types.TagInfo{
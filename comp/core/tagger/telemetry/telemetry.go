@@ -40,6 +40,10 @@ type Store struct {
 	// PrunedEntities tracks the number of pruned tagger entities.
 	PrunedEntities telemetry.Gauge
 
+	// DroppedTags tracks the number of tags dropped because their source
+	// exceeded its cardinality budget.
+	DroppedTags telemetry.Counter
+
 	// ClientStreamErrors tracks how many errors were received when streaming
 	// tagger events.
 	ClientStreamErrors telemetry.Counter
@@ -100,6 +104,12 @@ func NewStore(telemetryComp telemetry.Component) *Store {
 				[]string{}, "Number of pruned tagger entities.",
 				telemetry.Options{NoDoubleUnderscoreSep: true}),
 
+			// DroppedTags tracks the number of tags dropped because their source
+			// exceeded its cardinality budget.
+			DroppedTags: telemetryComp.NewCounterWithOpts(subsystem, "dropped_tags",
+				[]string{"source", "tag_name"}, "Number of tags dropped because their source exceeded its cardinality budget.",
+				telemetry.Options{NoDoubleUnderscoreSep: true}),
+
 			// ServerStreamErrors tracks how many errors happened when streaming
 			// out tagger events.
 			ServerStreamErrors: telemetryComp.NewCounterWithOpts(subsystem, "server_stream_errors",
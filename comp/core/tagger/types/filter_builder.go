@@ -14,6 +14,8 @@ type FilterBuilder struct {
 	prefixesToInclude map[EntityIDPrefix]struct{}
 
 	prefixesToExclude map[EntityIDPrefix]struct{}
+
+	expression *FilterExpression
 }
 
 // NewFilterBuilder returns a new empty filter builder
@@ -50,17 +52,29 @@ func (fb *FilterBuilder) Exclude(prefixes ...EntityIDPrefix) *FilterBuilder {
 	return fb
 }
 
+// WithExpression restricts the filter to the entities matching the expression
+func (fb *FilterBuilder) WithExpression(expression *FilterExpression) *FilterBuilder {
+	if fb == nil {
+		panic("filter builder should not be nil")
+	}
+
+	fb.expression = expression
+
+	return fb
+}
+
 // Build builds a new Filter object based on the calls to Include and Exclude
 // If the builder only excludes prefixes, the created filter will match any prefix except for the excluded ones.
 // If the builder only includes prefixes, the created filter will match only the prefixes included in the builder.
 // If the builder includes prefixes and excludes prefixes, the created filter will match only prefixes that are included but a not excluded in the builder
 // If the builder has neither included nor excluded prefixes, it will match by default all prefixes among `AllPrefixesSet` prefixes
+// If the builder has an expression, the created filter will only match the prefixes which can match the expression
 func (fb *FilterBuilder) Build(card TagCardinality) *Filter {
 	if fb == nil {
 		panic("filter builder should not be nil")
 	}
 
-	if len(fb.prefixesToInclude)+len(fb.prefixesToExclude) == 0 {
+	if len(fb.prefixesToInclude)+len(fb.prefixesToExclude) == 0 && fb.expression == nil {
 		return newFilter(AllPrefixesSet(), card)
 	}
 
@@ -78,5 +92,14 @@ func (fb *FilterBuilder) Build(card TagCardinality) *Filter {
 		delete(prefixSet, prefix)
 	}
 
-	return newFilter(prefixSet, card)
+	// exclude the prefixes which can't match the expression
+	for prefix := range prefixSet {
+		if !fb.expression.MatchesPrefix(prefix) {
+			delete(prefixSet, prefix)
+		}
+	}
+
+	filter := newFilter(prefixSet, card)
+	filter.expression = fb.expression
+	return filter
 }
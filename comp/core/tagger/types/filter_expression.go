@@ -0,0 +1,152 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// kindPrefixes maps the kinds of entities accepted in filter expressions to
+// their entity ID prefix
+var kindPrefixes = map[string]EntityIDPrefix{
+	"container":                ContainerID,
+	"container_image_metadata": ContainerImageMetadata,
+	"ecs_task":                 ECSTask,
+	"gpu":                      GPU,
+	"host":                     Host,
+	"kubernetes_deployment":    KubernetesDeployment,
+	"kubernetes_metadata":      KubernetesMetadata,
+	"kubernetes_pod":           KubernetesPodUID,
+	"process":                  Process,
+}
+
+// FilterExpression selects the entities streamed to a tagger subscriber, by
+// their kind, their prefix, and the keys of their tags.
+//
+// An expression is a list of terms separated by spaces, an entity matches the
+// expression if it matches all of them. A term is a key and a list of values
+// separated by commas, it matches if any of the values matches, and it's
+// negated when prefixed with `!`:
+//
+//   - `kind:<kind>` matches the entities of a kind, e.g. `kind:container,kubernetes_pod`
+//   - `prefix:<prefix>` matches the entities of an entity ID prefix, e.g. `prefix:container_id`
+//   - `has:<tag key>` matches the entities with a tag of this key, e.g. `!has:pod_template_hash`
+//
+// A nil FilterExpression matches all entities.
+type FilterExpression struct {
+	expr        string
+	prefixTerms []prefixTerm
+	tagTerms    []tagTerm
+}
+
+type prefixTerm struct {
+	prefixes map[EntityIDPrefix]struct{}
+	negated  bool
+}
+
+type tagTerm struct {
+	keys    map[string]struct{}
+	negated bool
+}
+
+// ParseFilterExpression parses a filter expression, it returns nil if the
+// expression is empty.
+func ParseFilterExpression(expr string) (*FilterExpression, error) {
+	fields := strings.Fields(expr)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	e := &FilterExpression{expr: strings.Join(fields, " ")}
+	for _, field := range fields {
+		term, negated := strings.CutPrefix(field, "!")
+		key, values, found := strings.Cut(term, ":")
+		if !found || values == "" {
+			return nil, fmt.Errorf("invalid term %q, expected `<key>:<values>`", field)
+		}
+
+		switch key {
+		case "kind", "prefix":
+			pt := prefixTerm{prefixes: make(map[EntityIDPrefix]struct{}), negated: negated}
+			for _, value := range strings.Split(values, ",") {
+				prefix := EntityIDPrefix(value)
+				if key == "kind" {
+					var ok bool
+					if prefix, ok = kindPrefixes[value]; !ok {
+						return nil, fmt.Errorf("unknown entity kind %q", value)
+					}
+				} else if _, ok := AllPrefixesSet()[prefix]; !ok {
+					return nil, fmt.Errorf("unknown entity ID prefix %q", value)
+				}
+				pt.prefixes[prefix] = struct{}{}
+			}
+			e.prefixTerms = append(e.prefixTerms, pt)
+		case "has":
+			tt := tagTerm{keys: make(map[string]struct{}), negated: negated}
+			for _, value := range strings.Split(values, ",") {
+				if value == "" {
+					return nil, fmt.Errorf("empty tag key in term %q", field)
+				}
+				tt.keys[value] = struct{}{}
+			}
+			e.tagTerms = append(e.tagTerms, tt)
+		default:
+			return nil, fmt.Errorf("unknown key %q in term %q, expected kind, prefix or has", key, field)
+		}
+	}
+
+	return e, nil
+}
+
+// String returns the expression, normalized
+func (e *FilterExpression) String() string {
+	if e == nil {
+		return ""
+	}
+	return e.expr
+}
+
+// MatchesPrefix returns whether the entities of a prefix can match the expression
+func (e *FilterExpression) MatchesPrefix(prefix EntityIDPrefix) bool {
+	if e == nil {
+		return true
+	}
+
+	for _, term := range e.prefixTerms {
+		if _, found := term.prefixes[prefix]; found == term.negated {
+			return false
+		}
+	}
+	return true
+}
+
+// FiltersTags returns whether the expression selects the entities by their tags,
+// in which case they have to be matched with MatchesTags.
+func (e *FilterExpression) FiltersTags() bool {
+	return e != nil && len(e.tagTerms) > 0
+}
+
+// MatchesTags returns whether an entity with these tags matches the expression
+func (e *FilterExpression) MatchesTags(tags []string) bool {
+	if !e.FiltersTags() {
+		return true
+	}
+
+	for _, term := range e.tagTerms {
+		found := false
+		for _, tag := range tags {
+			key, _, _ := strings.Cut(tag, ":")
+			if _, found = term.keys[key]; found {
+				break
+			}
+		}
+		if found == term.negated {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilterExpression(t *testing.T) {
+	tests := []struct {
+		name        string
+		expr        string
+		expectedErr string
+		expected    string
+	}{
+		{
+			name: "empty",
+			expr: "  ",
+		},
+		{
+			name:     "normalized",
+			expr:     "  kind:container   !has:pod_template_hash ",
+			expected: "kind:container !has:pod_template_hash",
+		},
+		{
+			name:        "missing values",
+			expr:        "kind:",
+			expectedErr: "invalid term",
+		},
+		{
+			name:        "missing key",
+			expr:        "container",
+			expectedErr: "invalid term",
+		},
+		{
+			name:        "unknown key",
+			expr:        "name:redis",
+			expectedErr: "unknown key",
+		},
+		{
+			name:        "unknown kind",
+			expr:        "kind:container,vm",
+			expectedErr: "unknown entity kind",
+		},
+		{
+			name:        "unknown prefix",
+			expr:        "prefix:vm_id",
+			expectedErr: "unknown entity ID prefix",
+		},
+		{
+			name:        "empty tag key",
+			expr:        "has:service,",
+			expectedErr: "empty tag key",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := ParseFilterExpression(test.expr)
+			if test.expectedErr != "" {
+				assert.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, e.String())
+		})
+	}
+}
+
+func TestFilterExpressionMatchesPrefix(t *testing.T) {
+	e, err := ParseFilterExpression("kind:container,kubernetes_pod !prefix:kubernetes_pod_uid")
+	require.NoError(t, err)
+
+	assert.True(t, e.MatchesPrefix(ContainerID))
+	assert.False(t, e.MatchesPrefix(KubernetesPodUID))
+	assert.False(t, e.MatchesPrefix(Host))
+	assert.False(t, e.FiltersTags())
+
+	var nilExpression *FilterExpression
+	assert.True(t, nilExpression.MatchesPrefix(Host))
+}
+
+func TestFilterExpressionMatchesTags(t *testing.T) {
+	e, err := ParseFilterExpression("has:service,app !has:pod_template_hash")
+	require.NoError(t, err)
+	require.True(t, e.FiltersTags())
+
+	assert.True(t, e.MatchesTags([]string{"service:redis", "env:prod"}))
+	assert.True(t, e.MatchesTags([]string{"app:redis"}))
+	assert.False(t, e.MatchesTags([]string{"env:prod"}))
+	assert.False(t, e.MatchesTags([]string{"service:redis", "pod_template_hash:abc"}))
+	assert.False(t, e.MatchesTags(nil))
+
+	var nilExpression *FilterExpression
+	assert.True(t, nilExpression.MatchesTags(nil))
+}
+
+func TestFilterBuilderWithExpression(t *testing.T) {
+	e, err := ParseFilterExpression("kind:container,host has:service")
+	require.NoError(t, err)
+
+	f := NewFilterBuilder().Exclude(Host).WithExpression(e).Build(LowCardinality)
+	assert.Equal(t, map[EntityIDPrefix]struct{}{ContainerID: {}}, f.GetPrefixes())
+	assert.Equal(t, LowCardinality, f.GetCardinality())
+	assert.Same(t, e, f.GetExpression())
+
+	// without an expression, the filter matches everything
+	f = NewFilterBuilder().WithExpression(nil).Build(HighCardinality)
+	assert.Equal(t, AllPrefixesSet(), f.GetPrefixes())
+	assert.Nil(t, f.GetExpression())
+}
@@ -18,6 +18,7 @@ func NewMatchAllFilter() *Filter {
 type Filter struct {
 	prefixes    map[EntityIDPrefix]struct{}
 	cardinality TagCardinality
+	expression  *FilterExpression
 }
 
 func newFilter(prefixes map[EntityIDPrefix]struct{}, cardinality TagCardinality) *Filter {
@@ -58,3 +59,12 @@ func (f *Filter) MatchesPrefix(prefix EntityIDPrefix) bool {
 
 	return found
 }
+
+// GetExpression returns the expression of the filter, or nil if it has none
+func (f *Filter) GetExpression() *FilterExpression {
+	if f == nil {
+		return nil
+	}
+
+	return f.expression
+}
@@ -13,6 +13,7 @@ import (
 	cfcontainer "github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/cloudfoundry/container"
 	cfvm "github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/cloudfoundry/vm"
 	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/containerd"
+	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/cri"
 	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/crio"
 	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/docker"
 	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/ecs"
@@ -22,6 +23,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/nvml"
 	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/podman"
 	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/process"
+	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/remote/enricher"
 	remoteprocesscollector "github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/remote/processcollector"
 )
 
@@ -30,6 +32,7 @@ func getCollectorOptions() []fx.Option {
 		cfcontainer.GetFxOptions(),
 		cfvm.GetFxOptions(),
 		containerd.GetFxOptions(),
+		cri.GetFxOptions(),
 		crio.GetFxOptions(),
 		docker.GetFxOptions(),
 		ecs.GetFxOptions(),
@@ -40,5 +43,6 @@ func getCollectorOptions() []fx.Option {
 		remoteprocesscollector.GetFxOptions(),
 		process.GetFxOptions(),
 		nvml.GetFxOptions(),
+		enricher.GetFxOptions(),
 	}
 }
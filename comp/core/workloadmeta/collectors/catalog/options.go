@@ -14,6 +14,7 @@ import (
 	cfcontainer "github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/cloudfoundry/container"
 	cfvm "github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/cloudfoundry/vm"
 	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/containerd"
+	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/cri"
 	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/crio"
 	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/docker"
 	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/internal/ecs"
@@ -32,6 +33,7 @@ func getCollectorOptions() []fx.Option {
 		cfcontainer.GetFxOptions(),
 		cfvm.GetFxOptions(),
 		containerd.GetFxOptions(),
+		cri.GetFxOptions(),
 		crio.GetFxOptions(),
 		docker.GetFxOptions(),
 		ecs.GetFxOptions(),
@@ -214,6 +214,14 @@ func (f *FakeDCAClient) PostLanguageMetadata(_ context.Context, _ *pbgo.ParentLa
 	panic("implement me")
 }
 
+func (f *FakeDCAClient) PostSecurityProfile(_ context.Context, _ []byte) error {
+	panic("implement me")
+}
+
+func (f *FakeDCAClient) GetSecurityProfile(_ context.Context, _ string) ([]byte, error) {
+	panic("implement me")
+}
+
 func (f *FakeDCAClient) SupportsNamespaceMetadataCollection() bool {
 	panic("implement me")
 }
@@ -0,0 +1,307 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+
+// Package cri implements the CRI Workloadmeta collector.
+// It populates the container catalog from the CRI socket, for environments
+// where the agent can't access the docker or containerd sockets.
+package cri
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/fx"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/util"
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/config/env"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	dderrors "github.com/DataDog/datadog-agent/pkg/errors"
+	"github.com/DataDog/datadog-agent/pkg/sbom/scanner"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/cri"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	collectorID   = "cri"
+	componentName = "workloadmeta-cri"
+)
+
+type collector struct {
+	id      string
+	client  cri.CRIClient
+	store   workloadmeta.Component
+	catalog workloadmeta.AgentType
+	seen    map[workloadmeta.EntityID]struct{}
+
+	// images are listed from the image service of the CRI socket
+	collectImages bool
+	seenImages    map[workloadmeta.EntityID]struct{}
+	sbomScanner   *scanner.Scanner //nolint: unused
+}
+
+// NewCollector returns a new cri collector provider and an error
+func NewCollector() (workloadmeta.CollectorProvider, error) {
+	return workloadmeta.CollectorProvider{
+		Collector: &collector{
+			id:         collectorID,
+			seen:       make(map[workloadmeta.EntityID]struct{}),
+			seenImages: make(map[workloadmeta.EntityID]struct{}),
+			catalog:    workloadmeta.NodeAgent | workloadmeta.ProcessAgent,
+		},
+	}, nil
+}
+
+// GetFxOptions returns the FX framework options for the collector
+func GetFxOptions() fx.Option {
+	return fx.Provide(NewCollector)
+}
+
+func (c *collector) Start(ctx context.Context, store workloadmeta.Component) error {
+	if !env.IsFeaturePresent(env.Cri) {
+		return dderrors.NewDisabled(componentName, "Agent is not running on a CRI runtime")
+	}
+
+	// The runtimes with a dedicated collector provide richer metadata, the
+	// CRI is only used as a fallback when none of their sockets is reachable.
+	for _, feature := range []env.Feature{env.Docker, env.Containerd, env.Crio, env.Podman} {
+		if env.IsFeaturePresent(feature) {
+			return dderrors.NewDisabled(componentName, fmt.Sprintf("containers are collected by the %s collector", feature))
+		}
+	}
+
+	client, err := cri.GetUtil()
+	if err != nil {
+		return err
+	}
+
+	c.client = client
+	c.store = store
+	c.collectImages = pkgconfigsetup.Datadog().GetBool("container_image.enabled")
+
+	go func() {
+		<-ctx.Done()
+		cri.StopStatsCaches()
+	}()
+
+	if err := c.startSBOMCollection(ctx); err != nil {
+		return fmt.Errorf("SBOM collection initialization failed: %v", err)
+	}
+
+	return nil
+}
+
+func (c *collector) Pull(_ context.Context) error {
+	containers, err := c.client.ListContainers()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[workloadmeta.EntityID]struct{}, len(containers))
+	events := make([]workloadmeta.CollectorEvent, 0, len(containers))
+
+	for _, container := range containers {
+		status, err := c.client.GetContainerStatus(container.GetId())
+		if err != nil {
+			// the container may have been removed since it was listed
+			log.Debugf("Could not get status of container %s: %v", container.GetId(), err)
+			continue
+		}
+
+		// several runtimes may be queried when multiple CRI sockets are configured
+		runtime := workloadmeta.ContainerRuntime(c.client.GetContainerRuntime(container.GetId()))
+		event := convertToEvent(container, status, runtime)
+		seen[event.Entity.GetID()] = struct{}{}
+		events = append(events, event)
+
+		if checkpoint := event.Entity.(*workloadmeta.Container).State.Checkpoint; checkpoint != nil {
+			if _, ok := c.seen[event.Entity.GetID()]; !ok {
+				log.Infof("Container %s was restored from a checkpoint of container %s/%s/%s", container.GetId(), checkpoint.PodNamespace, checkpoint.PodName, checkpoint.ContainerName)
+			}
+		}
+	}
+
+	for seenID := range c.seen {
+		if _, ok := seen[seenID]; ok {
+			continue
+		}
+
+		events = append(events, workloadmeta.CollectorEvent{
+			Type:   workloadmeta.EventTypeUnset,
+			Source: workloadmeta.SourceRuntime,
+			Entity: &workloadmeta.Container{
+				EntityID: seenID,
+			},
+		})
+	}
+
+	c.seen = seen
+
+	c.store.Notify(events)
+
+	if c.collectImages {
+		c.pullImages()
+	}
+
+	return nil
+}
+
+// pullImages populates the container image catalog. Failing to list the
+// images doesn't fail the collection of the containers.
+func (c *collector) pullImages() {
+	images, err := c.client.ListImages()
+	if err != nil {
+		log.Debugf("Could not list CRI images: %v", err)
+		return
+	}
+
+	seen := make(map[workloadmeta.EntityID]struct{}, len(images))
+	events := make([]workloadmeta.CollectorEvent, 0, len(images))
+
+	for _, image := range images {
+		event := convertImageToEvent(image)
+		seen[event.Entity.GetID()] = struct{}{}
+		events = append(events, event)
+	}
+
+	for seenID := range c.seenImages {
+		if _, ok := seen[seenID]; ok {
+			continue
+		}
+
+		events = append(events, workloadmeta.CollectorEvent{
+			Type:   workloadmeta.EventTypeUnset,
+			Source: workloadmeta.SourceRuntime,
+			Entity: &workloadmeta.ContainerImageMetadata{
+				EntityID: seenID,
+			},
+		})
+	}
+
+	c.seenImages = seen
+
+	c.store.Notify(events)
+}
+
+// sbomCollectionIsEnabled returns true if SBOM collection is enabled.
+func (c *collector) sbomCollectionIsEnabled() bool {
+	return c.collectImages && pkgconfigsetup.Datadog().GetBool("sbom.container_image.enabled")
+}
+
+func (c *collector) GetID() string {
+	return c.id
+}
+
+func (c *collector) GetTargetCatalog() workloadmeta.AgentType {
+	return c.catalog
+}
+
+func convertToEvent(container *criv1.Container, status *criv1.ContainerStatus, runtime workloadmeta.ContainerRuntime) workloadmeta.CollectorEvent {
+	labels := container.GetLabels()
+
+	image, err := workloadmeta.NewContainerImage(status.GetImageRef(), status.GetImage().GetImage())
+	if err != nil {
+		log.Debugf("Could not parse image of container %s: %v", container.GetId(), err)
+	}
+
+	return workloadmeta.CollectorEvent{
+		Type:   workloadmeta.EventTypeSet,
+		Source: workloadmeta.SourceRuntime,
+		Entity: &workloadmeta.Container{
+			EntityID: workloadmeta.EntityID{
+				Kind: workloadmeta.KindContainer,
+				ID:   container.GetId(),
+			},
+			EntityMeta: workloadmeta.EntityMeta{
+				Name:        container.GetMetadata().GetName(),
+				Namespace:   labels[kubernetes.CriContainerNamespaceLabel],
+				Labels:      labels,
+				Annotations: container.GetAnnotations(),
+			},
+			Image:   image,
+			Runtime: runtime,
+			State:   convertState(status),
+		},
+	}
+}
+
+// convertImageToEvent converts a CRI image. The image ID matches the image
+// reference of the containers using it.
+func convertImageToEvent(image *criv1.Image) workloadmeta.CollectorEvent {
+	var name string
+	if len(image.GetRepoTags()) > 0 {
+		name = image.GetRepoTags()[0]
+	}
+
+	return workloadmeta.CollectorEvent{
+		Type:   workloadmeta.EventTypeSet,
+		Source: workloadmeta.SourceRuntime,
+		Entity: &workloadmeta.ContainerImageMetadata{
+			EntityID: workloadmeta.EntityID{
+				Kind: workloadmeta.KindContainerImageMetadata,
+				ID:   image.GetId(),
+			},
+			EntityMeta: workloadmeta.EntityMeta{
+				Name:        name,
+				Annotations: image.GetSpec().GetAnnotations(),
+			},
+			RepoTags:    image.GetRepoTags(),
+			RepoDigests: image.GetRepoDigests(),
+			SizeBytes:   int64(image.GetSize_()),
+		},
+	}
+}
+
+func convertState(status *criv1.ContainerStatus) workloadmeta.ContainerState {
+	state := workloadmeta.ContainerState{
+		Running:    status.GetState() == criv1.ContainerState_CONTAINER_RUNNING,
+		Status:     convertStatus(status.GetState()),
+		CreatedAt:  convertTimestamp(status.GetCreatedAt()),
+		StartedAt:  convertTimestamp(status.GetStartedAt()),
+		Checkpoint: convertCheckpoint(status),
+	}
+
+	if status.GetState() == criv1.ContainerState_CONTAINER_EXITED {
+		exitCode := int64(status.GetExitCode())
+		state.FinishedAt = convertTimestamp(status.GetFinishedAt())
+		state.ExitCode = &exitCode
+	}
+
+	return state
+}
+
+// convertCheckpoint returns the checkpoint a container was restored from, or
+// nil if it wasn't restored. The checkpoint annotations are reported with the
+// checkpoint image or with the annotations of the restored container.
+func convertCheckpoint(status *criv1.ContainerStatus) *workloadmeta.ContainerCheckpoint {
+	return util.ParseContainerCheckpoint(status.GetId(), status.GetImage().GetAnnotations(), status.GetAnnotations())
+}
+
+func convertStatus(state criv1.ContainerState) workloadmeta.ContainerStatus {
+	switch state {
+	case criv1.ContainerState_CONTAINER_CREATED:
+		return workloadmeta.ContainerStatusCreated
+	case criv1.ContainerState_CONTAINER_RUNNING:
+		return workloadmeta.ContainerStatusRunning
+	case criv1.ContainerState_CONTAINER_EXITED:
+		return workloadmeta.ContainerStatusStopped
+	default:
+		return workloadmeta.ContainerStatusUnknown
+	}
+}
+
+// convertTimestamp converts a CRI timestamp, in nanoseconds, to a time.Time.
+// Unset timestamps are converted to the zero time.
+func convertTimestamp(ts int64) time.Time {
+	if ts == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ts).UTC()
+}
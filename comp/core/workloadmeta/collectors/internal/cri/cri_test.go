@@ -0,0 +1,264 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+
+package cri
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/cri/crimock"
+	"github.com/DataDog/datadog-agent/pkg/util/pointer"
+)
+
+type fakeWorkloadmetaStore struct {
+	workloadmeta.Component
+	notifiedEvents []workloadmeta.CollectorEvent
+}
+
+func (store *fakeWorkloadmetaStore) Notify(events []workloadmeta.CollectorEvent) {
+	store.notifiedEvents = append(store.notifiedEvents, events...)
+}
+
+func TestPull(t *testing.T) {
+	createdAt := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	startedAt := createdAt.Add(time.Second)
+	finishedAt := startedAt.Add(time.Minute)
+
+	containers := []*criv1.Container{
+		{
+			Id:          "running",
+			Metadata:    &criv1.ContainerMetadata{Name: "app"},
+			Labels:      map[string]string{"io.kubernetes.pod.namespace": "default"},
+			Annotations: map[string]string{"some-annotation": "some-value"},
+		},
+		{
+			Id:       "exited",
+			Metadata: &criv1.ContainerMetadata{Name: "init"},
+		},
+		{
+			Id:       "removed",
+			Metadata: &criv1.ContainerMetadata{Name: "gone"},
+		},
+	}
+
+	client := &crimock.MockCRIClient{}
+	client.On("ListContainers").Return(containers, nil)
+	client.On("GetContainerStatus", "running").Return(&criv1.ContainerStatus{
+		Id:        "running",
+		State:     criv1.ContainerState_CONTAINER_RUNNING,
+		CreatedAt: createdAt.UnixNano(),
+		StartedAt: startedAt.UnixNano(),
+		Image:     &criv1.ImageSpec{Image: "docker.io/library/nginx:1.25"},
+		ImageRef:  "sha256:0123456789",
+	}, nil)
+	client.On("GetContainerStatus", "exited").Return(&criv1.ContainerStatus{
+		Id:         "exited",
+		State:      criv1.ContainerState_CONTAINER_EXITED,
+		CreatedAt:  createdAt.UnixNano(),
+		StartedAt:  startedAt.UnixNano(),
+		FinishedAt: finishedAt.UnixNano(),
+		ExitCode:   1,
+		Image:      &criv1.ImageSpec{Image: "busybox"},
+		ImageRef:   "sha256:abcdef",
+	}, nil)
+	client.On("GetContainerStatus", "removed").Return((*criv1.ContainerStatus)(nil), errors.New("not found"))
+
+	store := &fakeWorkloadmetaStore{}
+	c := collector{
+		client: client,
+		store:  store,
+		seen: map[workloadmeta.EntityID]struct{}{
+			{Kind: workloadmeta.KindContainer, ID: "stale"}: {},
+		},
+	}
+
+	require.NoError(t, c.Pull(context.Background()))
+
+	nginx, err := workloadmeta.NewContainerImage("sha256:0123456789", "docker.io/library/nginx:1.25")
+	require.NoError(t, err)
+	busybox, err := workloadmeta.NewContainerImage("sha256:abcdef", "busybox")
+	require.NoError(t, err)
+
+	expectedEvents := []workloadmeta.CollectorEvent{
+		{
+			Type:   workloadmeta.EventTypeSet,
+			Source: workloadmeta.SourceRuntime,
+			Entity: &workloadmeta.Container{
+				EntityID: workloadmeta.EntityID{Kind: workloadmeta.KindContainer, ID: "running"},
+				EntityMeta: workloadmeta.EntityMeta{
+					Name:        "app",
+					Namespace:   "default",
+					Labels:      map[string]string{"io.kubernetes.pod.namespace": "default"},
+					Annotations: map[string]string{"some-annotation": "some-value"},
+				},
+				Image:   nginx,
+				Runtime: workloadmeta.ContainerRuntime("fakeruntime"),
+				State: workloadmeta.ContainerState{
+					Running:   true,
+					Status:    workloadmeta.ContainerStatusRunning,
+					CreatedAt: createdAt,
+					StartedAt: startedAt,
+				},
+			},
+		},
+		{
+			Type:   workloadmeta.EventTypeSet,
+			Source: workloadmeta.SourceRuntime,
+			Entity: &workloadmeta.Container{
+				EntityID:   workloadmeta.EntityID{Kind: workloadmeta.KindContainer, ID: "exited"},
+				EntityMeta: workloadmeta.EntityMeta{Name: "init"},
+				Image:      busybox,
+				Runtime:    workloadmeta.ContainerRuntime("fakeruntime"),
+				State: workloadmeta.ContainerState{
+					Running:    false,
+					Status:     workloadmeta.ContainerStatusStopped,
+					CreatedAt:  createdAt,
+					StartedAt:  startedAt,
+					FinishedAt: finishedAt,
+					ExitCode:   pointer.Ptr(int64(1)),
+				},
+			},
+		},
+		{
+			Type:   workloadmeta.EventTypeUnset,
+			Source: workloadmeta.SourceRuntime,
+			Entity: &workloadmeta.Container{
+				EntityID: workloadmeta.EntityID{Kind: workloadmeta.KindContainer, ID: "stale"},
+			},
+		},
+	}
+
+	assert.Equal(t, expectedEvents, store.notifiedEvents)
+	assert.Equal(t, map[workloadmeta.EntityID]struct{}{
+		{Kind: workloadmeta.KindContainer, ID: "running"}: {},
+		{Kind: workloadmeta.KindContainer, ID: "exited"}:  {},
+	}, c.seen)
+}
+
+func TestConvertCheckpoint(t *testing.T) {
+	checkpointAnnotations := map[string]string{
+		"io.kubernetes.cri-o.annotations.checkpoint.name":      "app",
+		"io.kubernetes.cri-o.annotations.checkpoint.pod":       "app-7d9c5",
+		"io.kubernetes.cri-o.annotations.checkpoint.namespace": "default",
+		"io.kubernetes.cri-o.annotations.checkpoint.created":   "2024-03-01T10:00:00.5Z",
+	}
+	expected := &workloadmeta.ContainerCheckpoint{
+		ContainerName: "app",
+		PodName:       "app-7d9c5",
+		PodNamespace:  "default",
+		CreatedAt:     time.Date(2024, time.March, 1, 10, 0, 0, 5e8, time.UTC),
+	}
+
+	tests := []struct {
+		name     string
+		status   *criv1.ContainerStatus
+		expected *workloadmeta.ContainerCheckpoint
+	}{
+		{
+			name:   "not restored",
+			status: &criv1.ContainerStatus{Image: &criv1.ImageSpec{Image: "busybox"}},
+		},
+		{
+			name:     "checkpoint image",
+			status:   &criv1.ContainerStatus{Image: &criv1.ImageSpec{Image: "checkpoint-app:latest", Annotations: checkpointAnnotations}},
+			expected: expected,
+		},
+		{
+			name:     "restored container annotations",
+			status:   &criv1.ContainerStatus{Annotations: checkpointAnnotations},
+			expected: expected,
+		},
+		{
+			name: "invalid creation time",
+			status: &criv1.ContainerStatus{Annotations: map[string]string{
+				"io.kubernetes.cri-o.annotations.checkpoint.name":    "app",
+				"io.kubernetes.cri-o.annotations.checkpoint.created": "yesterday",
+			}},
+			expected: &workloadmeta.ContainerCheckpoint{ContainerName: "app"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, convertCheckpoint(tt.status))
+		})
+	}
+}
+
+func TestPullImages(t *testing.T) {
+	client := &crimock.MockCRIClient{}
+	client.On("ListContainers").Return([]*criv1.Container{}, nil)
+	client.On("ListImages").Return([]*criv1.Image{
+		{
+			Id:          "sha256:0123456789",
+			RepoTags:    []string{"docker.io/library/nginx:1.25"},
+			RepoDigests: []string{"docker.io/library/nginx@sha256:fedcba"},
+			Size_:       1024,
+		},
+	}, nil)
+
+	store := &fakeWorkloadmetaStore{}
+	c := collector{
+		client:        client,
+		store:         store,
+		seen:          map[workloadmeta.EntityID]struct{}{},
+		collectImages: true,
+		seenImages: map[workloadmeta.EntityID]struct{}{
+			{Kind: workloadmeta.KindContainerImageMetadata, ID: "sha256:stale"}: {},
+		},
+	}
+
+	require.NoError(t, c.Pull(context.Background()))
+
+	expectedEvents := []workloadmeta.CollectorEvent{
+		{
+			Type:   workloadmeta.EventTypeSet,
+			Source: workloadmeta.SourceRuntime,
+			Entity: &workloadmeta.ContainerImageMetadata{
+				EntityID:    workloadmeta.EntityID{Kind: workloadmeta.KindContainerImageMetadata, ID: "sha256:0123456789"},
+				EntityMeta:  workloadmeta.EntityMeta{Name: "docker.io/library/nginx:1.25"},
+				RepoTags:    []string{"docker.io/library/nginx:1.25"},
+				RepoDigests: []string{"docker.io/library/nginx@sha256:fedcba"},
+				SizeBytes:   1024,
+			},
+		},
+		{
+			Type:   workloadmeta.EventTypeUnset,
+			Source: workloadmeta.SourceRuntime,
+			Entity: &workloadmeta.ContainerImageMetadata{
+				EntityID: workloadmeta.EntityID{Kind: workloadmeta.KindContainerImageMetadata, ID: "sha256:stale"},
+			},
+		},
+	}
+
+	assert.Equal(t, expectedEvents, store.notifiedEvents)
+	assert.Equal(t, map[workloadmeta.EntityID]struct{}{
+		{Kind: workloadmeta.KindContainerImageMetadata, ID: "sha256:0123456789"}: {},
+	}, c.seenImages)
+}
+
+func TestPullError(t *testing.T) {
+	client := &crimock.MockCRIClient{}
+	client.On("ListContainers").Return([]*criv1.Container(nil), errors.New("unavailable"))
+
+	store := &fakeWorkloadmetaStore{}
+	c := collector{
+		client: client,
+		store:  store,
+		seen:   map[workloadmeta.EntityID]struct{}{},
+	}
+
+	assert.Error(t, c.Pull(context.Background()))
+	assert.Empty(t, store.notifiedEvents)
+}
@@ -15,6 +15,7 @@ import (
 
 	v1 "k8s.io/cri-api/pkg/apis/runtime/v1"
 
+	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/util"
 	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
 	"github.com/DataDog/datadog-agent/pkg/util/crio"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -143,6 +144,7 @@ func getContainerState(containerStatus *v1.ContainerStatus) workloadmeta.Contain
 		StartedAt:  time.Unix(0, containerStatus.GetStartedAt()).UTC(),
 		FinishedAt: time.Unix(0, containerStatus.GetFinishedAt()).UTC(),
 		ExitCode:   &exitCode,
+		Checkpoint: util.ParseContainerCheckpoint(containerStatus.GetId(), containerStatus.GetImage().GetAnnotations(), containerStatus.GetAnnotations()),
 	}
 }
 
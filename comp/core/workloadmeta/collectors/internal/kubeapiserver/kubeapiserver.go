@@ -62,11 +62,11 @@ func shouldHaveDeploymentStore(cfg config.Reader) bool {
 func storeGenerators(cfg config.Reader) []storeGenerator {
 	var generators []storeGenerator
 
-	if shouldHavePodStore(cfg) {
+	if shouldHavePodStore(cfg) && !apiserver.IsInformerDisabled(cfg, "pods") {
 		generators = append(generators, newPodStore)
 	}
 
-	if shouldHaveDeploymentStore(cfg) {
+	if shouldHaveDeploymentStore(cfg) && !apiserver.IsInformerDisabled(cfg, "deployments.apps") {
 		generators = append(generators, newDeploymentStore)
 	}
 
@@ -74,7 +74,20 @@ func storeGenerators(cfg config.Reader) []storeGenerator {
 }
 
 func metadataCollectionGVRs(cfg config.Reader, discoveryClient discovery.DiscoveryInterface) ([]schema.GroupVersionResource, error) {
-	return getGVRsForRequestedResources(discoveryClient, resourcesWithMetadataCollectionEnabled(cfg))
+	gvrs, err := getGVRsForRequestedResources(discoveryClient, resourcesWithMetadataCollectionEnabled(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make([]schema.GroupVersionResource, 0, len(gvrs))
+	for _, gvr := range gvrs {
+		if apiserver.IsInformerDisabled(cfg, gvr.GroupResource().String()) {
+			log.Infof("The %s informer is disabled by cluster_agent.disabled_informers", gvr.GroupResource())
+			continue
+		}
+		enabled = append(enabled, gvr)
+	}
+	return enabled, nil
 }
 
 func resourcesWithMetadataCollectionEnabled(cfg config.Reader) []string {
@@ -83,6 +83,16 @@ func TestStoreGenerators(t *testing.T) {
 			},
 			expectedStoresGenerator: []storeGenerator{newPodStore, newDeploymentStore},
 		},
+		{
+			name: "Pods informer disabled",
+			cfg: map[string]interface{}{
+				"cluster_agent.collect_kubernetes_tags": true,
+				"language_detection.reporting.enabled":  true,
+				"language_detection.enabled":            true,
+				"cluster_agent.disabled_informers":      []string{"pods"},
+			},
+			expectedStoresGenerator: []storeGenerator{newDeploymentStore},
+		},
 	}
 
 	// Run test for each testcase
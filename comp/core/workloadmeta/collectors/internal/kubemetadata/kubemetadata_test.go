@@ -122,6 +122,14 @@ func (f *FakeDCAClient) PostLanguageMetadata(_ context.Context, _ *pbgo.ParentLa
 	panic("implement me")
 }
 
+func (f *FakeDCAClient) PostSecurityProfile(_ context.Context, _ []byte) error {
+	panic("implement me")
+}
+
+func (f *FakeDCAClient) GetSecurityProfile(_ context.Context, _ string) ([]byte, error) {
+	panic("implement me")
+}
+
 func (f *FakeDCAClient) SupportsNamespaceMetadataCollection() bool {
 	return f.LocalVersion.Major >= 7 && f.LocalVersion.Minor >= 55
 }
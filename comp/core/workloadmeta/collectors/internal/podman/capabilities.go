@@ -0,0 +1,38 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build podman
+
+package podman
+
+import (
+	"context"
+
+	"github.com/DataDog/datadog-agent/pkg/util/containers/capabilities"
+)
+
+func init() {
+	capabilities.RegisterProber("podman", probe)
+}
+
+// probe reads the podman containers DBs. The agent doesn't talk to podman
+// itself: the metadata of the containers is read from the DBs, and their stats
+// from the cgroups, so no capability of the runtime is used.
+func probe(_ context.Context) (string, []capabilities.Capability, error) {
+	dbPaths, _, err := getDBPaths()
+	if err != nil {
+		return "", nil, err
+	}
+	for _, dbPath := range dbPaths {
+		client, err := newDBClient(dbPath)
+		if err != nil {
+			return "", nil, err
+		}
+		if _, err := client.GetAllContainers(); err != nil {
+			return "", nil, err
+		}
+	}
+	return "", nil, nil
+}
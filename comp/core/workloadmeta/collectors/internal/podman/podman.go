@@ -11,12 +11,14 @@ package podman
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"sort"
 	"strings"
 
 	"go.uber.org/fx"
 
+	"github.com/DataDog/datadog-agent/comp/core/tagger/tags"
 	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
 	"github.com/DataDog/datadog-agent/pkg/config/env"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
@@ -35,14 +37,20 @@ const (
 
 type podmanClient interface {
 	GetAllContainers() ([]podman.Container, error)
+	GetAllPods() ([]podman.Pod, error)
 }
 
 type collector struct {
-	id      string
-	client  podmanClient
-	store   workloadmeta.Component
-	catalog workloadmeta.AgentType
-	seen    map[workloadmeta.EntityID]struct{}
+	id string
+	// clients are the clients of the containers DBs, by path
+	clients map[string]podmanClient
+	// discoverRootless is true when the DBs of the rootless users are
+	// discovered on each pull, as their sessions come and go
+	discoverRootless bool
+	store            workloadmeta.Component
+	catalog          workloadmeta.AgentType
+	// seen are the entities of each DB, by path
+	seen map[string]map[workloadmeta.EntityID]workloadmeta.Kind
 }
 
 // NewCollector returns a new podman collector provider and an error
@@ -50,7 +58,8 @@ func NewCollector() (workloadmeta.CollectorProvider, error) {
 	return workloadmeta.CollectorProvider{
 		Collector: &collector{
 			id:      collectorID,
-			seen:    make(map[workloadmeta.EntityID]struct{}),
+			clients: make(map[string]podmanClient),
+			seen:    make(map[string]map[workloadmeta.EntityID]workloadmeta.Kind),
 			catalog: workloadmeta.NodeAgent | workloadmeta.ProcessAgent,
 		},
 	}, nil
@@ -67,79 +76,192 @@ func (c *collector) Start(_ context.Context, store workloadmeta.Component) error
 		return dderrors.NewDisabled(componentName, "Podman not detected")
 	}
 
-	var dbPath string
-	dbPath = pkgconfigsetup.Datadog().GetString("podman_db_path")
+	dbPaths, discoverRootless, err := getDBPaths()
+	if err != nil {
+		return err
+	}
+	for _, dbPath := range dbPaths {
+		client, err := newDBClient(dbPath)
+		if err != nil {
+			return err
+		}
+		c.clients[dbPath] = client
+	}
+	c.discoverRootless = discoverRootless
+	c.store = store
+
+	return nil
+}
+
+// getDBPaths returns the paths of the containers DBs to read: the one set in
+// the configuration, or the default rootful DB and the DBs of the rootless users.
+// The DBs of the rootless users are expected to be discovered again later on
+// if discoverRootless is true.
+func getDBPaths() (dbPaths []string, discoverRootless bool, err error) {
+	dbPath := pkgconfigsetup.Datadog().GetString("podman_db_path")
 
 	// We verify the user-provided path exists to prevent the collector entering a failing loop.
-	if dbPath != "" && !dbIsAccessible(dbPath) {
-		return dderrors.NewDisabled(componentName, "podman_db_path is misconfigured/not accessible")
+	if dbPath != "" {
+		if !dbIsAccessible(dbPath) {
+			return nil, false, dderrors.NewDisabled(componentName, "podman_db_path is misconfigured/not accessible")
+		}
+		return []string{dbPath}, false, nil
 	}
 
 	// If dbPath is empty (default value of `podman_db_path`), attempts to use the default rootfull database (BoltDB first, then SQLite) as podman feature was detected (existence of /var/lib/containers/storage)
-	if dbPath == "" {
-		if dbIsAccessible(defaultBoltDBPath) {
-			log.Infof("Podman feature detected and podman_db_path not configured, defaulting to: %s", defaultBoltDBPath)
-			dbPath = defaultBoltDBPath
-		} else if dbIsAccessible(defaultSqlitePath) {
-			log.Infof("Podman feature detected and podman_db_path not configured, defaulting to: %s", defaultSqlitePath)
-			dbPath = defaultSqlitePath
-		} else {
-			// `/var/lib/containers/storage` exists but the Agent cannot list out its content.
-			return dderrors.NewDisabled(componentName, "Podman feature detected but the default location for the containers DB is not accessible")
-		}
+	if dbIsAccessible(defaultBoltDBPath) {
+		log.Infof("Podman feature detected and podman_db_path not configured, defaulting to: %s", defaultBoltDBPath)
+		dbPaths = append(dbPaths, defaultBoltDBPath)
+	} else if dbIsAccessible(defaultSqlitePath) {
+		log.Infof("Podman feature detected and podman_db_path not configured, defaulting to: %s", defaultSqlitePath)
+		dbPaths = append(dbPaths, defaultSqlitePath)
 	}
 
+	// the containers of the rootless users are collected as well
+	rootlessDBPaths := discoverRootlessDBs(hostPrefixes())
+	for _, rootlessDBPath := range rootlessDBPaths {
+		log.Infof("Found the DB of rootless Podman containers: %s", rootlessDBPath)
+	}
+	dbPaths = append(dbPaths, rootlessDBPaths...)
+
+	if len(dbPaths) == 0 {
+		// `/var/lib/containers/storage` exists but the Agent cannot list out its content.
+		return nil, false, dderrors.NewDisabled(componentName, "Podman feature detected but the default location for the containers DB is not accessible")
+	}
+	return dbPaths, true, nil
+}
+
+// newDBClient returns a client of the podman containers DB found at dbPath
+func newDBClient(dbPath string) (podmanClient, error) {
 	// As the containers database file is hard-coded in Podman (non-user customizable), the client to use is determined thanks to the file extension.
 	if strings.HasSuffix(dbPath, ".sql") {
 		log.Debugf("Using SQLite client for Podman DB as provided path ends with .sql")
-		c.client = podman.NewSQLDBClient(dbPath)
-	} else if strings.HasSuffix(dbPath, ".db") {
+		return podman.NewSQLDBClient(dbPath), nil
+	}
+	if strings.HasSuffix(dbPath, ".db") {
 		log.Debugf("Using BoltDB client for Podman DB as provided path ends with .db")
-		c.client = podman.NewDBClient(dbPath)
-	} else {
-		return dderrors.NewDisabled(componentName, "Podman detected but podman_db_path does not end in a known-format (.db or .sql)")
+		return podman.NewDBClient(dbPath), nil
 	}
-	c.store = store
+	return nil, dderrors.NewDisabled(componentName, "Podman detected but podman_db_path does not end in a known-format (.db or .sql)")
+}
 
-	return nil
+// refreshRootlessClients adds the clients of the DBs of the rootless users who
+// started a session since the last pull. The DBs of the users whose session
+// ended are still read, their containers being stopped.
+func (c *collector) refreshRootlessClients() {
+	for _, dbPath := range discoverRootlessDBs(hostPrefixes()) {
+		if _, ok := c.clients[dbPath]; ok {
+			continue
+		}
+		client, err := newDBClient(dbPath)
+		if err != nil {
+			log.Debugf("Could not read the DB of rootless Podman containers %s: %v", dbPath, err)
+			continue
+		}
+		log.Infof("Found the DB of rootless Podman containers: %s", dbPath)
+		c.clients[dbPath] = client
+	}
 }
 
 func (c *collector) Pull(_ context.Context) error {
-	containers, err := c.client.GetAllContainers()
-	if err != nil {
-		return err
+	if c.discoverRootless {
+		c.refreshRootlessClients()
+	}
+
+	if c.seen == nil {
+		c.seen = make(map[string]map[workloadmeta.EntityID]workloadmeta.Kind)
+	}
+
+	var events []workloadmeta.CollectorEvent
+	var errs []error
+	previous := c.allSeen()
+
+	// sorted for the events to be deterministic
+	dbPaths := make([]string, 0, len(c.clients))
+	for dbPath := range c.clients {
+		dbPaths = append(dbPaths, dbPath)
 	}
+	sort.Strings(dbPaths)
+
+	for _, dbPath := range dbPaths {
+		client := c.clients[dbPath]
+		containers, err := client.GetAllContainers()
+		if err != nil {
+			// the entities of the DB are kept until it can be read again
+			errs = append(errs, fmt.Errorf("could not read the Podman DB %s: %w", dbPath, err))
+			continue
+		}
 
-	seen := make(map[workloadmeta.EntityID]struct{})
-	events := make([]workloadmeta.CollectorEvent, 0, len(containers))
+		pods, err := client.GetAllPods()
+		if err != nil {
+			log.Debugf("Could not get the pods of the Podman DB %s: %v", dbPath, err)
+		}
+		podNames := make(map[string]string, len(pods))
+		for _, pod := range pods {
+			podNames[pod.Config.ID] = pod.Config.Name
+		}
 
-	for _, container := range containers {
-		event := convertToEvent(&container)
-		seen[event.Entity.GetID()] = struct{}{}
-		events = append(events, event)
+		seen := make(map[workloadmeta.EntityID]workloadmeta.Kind)
+		for _, container := range containers {
+			event := convertToEvent(&container, podNames)
+			seen[event.Entity.GetID()] = workloadmeta.KindContainer
+			events = append(events, event)
+
+			imageEvent, ok := convertToImageEvent(event.Entity.(*workloadmeta.Container))
+			if !ok {
+				continue
+			}
+			if _, found := seen[imageEvent.Entity.GetID()]; !found {
+				seen[imageEvent.Entity.GetID()] = workloadmeta.KindContainerImageMetadata
+				events = append(events, imageEvent)
+			}
+		}
+		c.seen[dbPath] = seen
 	}
 
-	for seenID := range c.seen {
-		if _, ok := seen[seenID]; ok {
+	current := c.allSeen()
+	for seenID, kind := range previous {
+		if _, ok := current[seenID]; ok {
 			continue
 		}
 
+		var entity workloadmeta.Entity
+		if kind == workloadmeta.KindContainerImageMetadata {
+			entity = &workloadmeta.ContainerImageMetadata{EntityID: seenID}
+		} else {
+			entity = &workloadmeta.Container{EntityID: seenID}
+		}
 		events = append(events, workloadmeta.CollectorEvent{
 			Type:   workloadmeta.EventTypeUnset,
 			Source: workloadmeta.SourceRuntime,
-			Entity: &workloadmeta.Container{
-				EntityID: seenID,
-			},
+			Entity: entity,
 		})
 	}
 
-	c.seen = seen
-
 	c.store.Notify(events)
 
+	// all the DBs failed to be read
+	if len(errs) == len(dbPaths) && len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	for _, err := range errs {
+		log.Warn(err)
+	}
+
 	return nil
 }
 
+// allSeen returns the entities of all the DBs
+func (c *collector) allSeen() map[workloadmeta.EntityID]workloadmeta.Kind {
+	all := make(map[workloadmeta.EntityID]workloadmeta.Kind)
+	for _, seen := range c.seen {
+		for id, kind := range seen {
+			all[id] = kind
+		}
+	}
+	return all
+}
+
 func (c *collector) GetID() string {
 	return c.id
 }
@@ -148,7 +270,7 @@ func (c *collector) GetTargetCatalog() workloadmeta.AgentType {
 	return c.catalog
 }
 
-func convertToEvent(container *podman.Container) workloadmeta.CollectorEvent {
+func convertToEvent(container *podman.Container, podNames map[string]string) workloadmeta.CollectorEvent {
 	containerID := container.Config.ID
 
 	var annotations map[string]string
@@ -178,6 +300,12 @@ func convertToEvent(container *podman.Container) workloadmeta.CollectorEvent {
 		})
 	}
 
+	// the containers of a pod are grouped by the name of the pod
+	var collectorTags []string
+	if podName, ok := podNames[container.Config.Pod]; ok && container.Config.Pod != "" {
+		collectorTags = []string{tags.PodmanPod + ":" + podName}
+	}
+
 	var eventType workloadmeta.EventType
 	if container.State.State == podman.ContainerStateRunning {
 		eventType = workloadmeta.EventTypeSet
@@ -213,11 +341,41 @@ func convertToEvent(container *podman.Container) workloadmeta.CollectorEvent {
 				CreatedAt:  container.State.StartedTime, // CreatedAt not available
 				FinishedAt: container.State.FinishedTime,
 			},
-			RestartCount: int(container.State.RestartCount),
+			CollectorTags: collectorTags,
+			RestartCount:  int(container.State.RestartCount),
 		},
 	}
 }
 
+// convertToImageEvent returns the event of the image of a container. The DB
+// only holds the names of the images the containers were created from.
+func convertToImageEvent(container *workloadmeta.Container) (workloadmeta.CollectorEvent, bool) {
+	image := container.Image
+	if image.ID == "" {
+		return workloadmeta.CollectorEvent{}, false
+	}
+
+	var repoTags []string
+	if image.Name != "" && image.Tag != "" {
+		repoTags = []string{image.Name + ":" + image.Tag}
+	}
+
+	return workloadmeta.CollectorEvent{
+		Type:   workloadmeta.EventTypeSet,
+		Source: workloadmeta.SourceRuntime,
+		Entity: &workloadmeta.ContainerImageMetadata{
+			EntityID: workloadmeta.EntityID{
+				Kind: workloadmeta.KindContainerImageMetadata,
+				ID:   image.ID,
+			},
+			EntityMeta: workloadmeta.EntityMeta{
+				Name: image.Name,
+			},
+			RepoTags: repoTags,
+		},
+	}, true
+}
+
 func getShortID(container *podman.Container) (containerID string) {
 	if len(container.Config.ID) >= 12 {
 		containerID = container.Config.ID[:12]
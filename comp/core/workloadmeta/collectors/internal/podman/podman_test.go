@@ -9,7 +9,10 @@ package podman
 
 import (
 	"context"
+	"errors"
 	"net"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -33,12 +36,17 @@ func (store *fakeWorkloadmetaStore) Notify(events []workloadmeta.CollectorEvent)
 
 type fakePodmanClient struct {
 	mockGetAllContainers func() ([]podman.Container, error)
+	pods                 []podman.Pod
 }
 
 func (client *fakePodmanClient) GetAllContainers() ([]podman.Container, error) {
 	return client.mockGetAllContainers()
 }
 
+func (client *fakePodmanClient) GetAllPods() ([]podman.Pod, error) {
+	return client.pods, nil
+}
+
 func TestPull(t *testing.T) {
 	startTime := time.Now()
 
@@ -110,6 +118,7 @@ func TestPull(t *testing.T) {
 				},
 				ID:           "124",
 				Name:         "dd-agent-dev",
+				Pod:          "pod-id",
 				Namespace:    "dev",
 				RawImageName: "docker.io/datadog/agent-dev:latest",
 				ContainerNetworkConfig: podman.ContainerNetworkConfig{
@@ -205,6 +214,20 @@ func TestPull(t *testing.T) {
 				RestartCount: 1,
 			},
 		},
+		{
+			Type:   workloadmeta.EventTypeSet,
+			Source: workloadmeta.SourceRuntime,
+			Entity: &workloadmeta.ContainerImageMetadata{
+				EntityID: workloadmeta.EntityID{
+					Kind: workloadmeta.KindContainerImageMetadata,
+					ID:   "my_image_id_1",
+				},
+				EntityMeta: workloadmeta.EntityMeta{
+					Name: "docker.io/datadog/agent",
+				},
+				RepoTags: []string{"docker.io/datadog/agent:latest"},
+			},
+		},
 		{
 			Type:   workloadmeta.EventTypeSet,
 			Source: workloadmeta.SourceRuntime,
@@ -252,7 +275,22 @@ func TestPull(t *testing.T) {
 					CreatedAt: startTime,
 					StartedAt: startTime,
 				},
-				RestartCount: 1,
+				CollectorTags: []string{"podman_pod:dd-pod"},
+				RestartCount:  1,
+			},
+		},
+		{
+			Type:   workloadmeta.EventTypeSet,
+			Source: workloadmeta.SourceRuntime,
+			Entity: &workloadmeta.ContainerImageMetadata{
+				EntityID: workloadmeta.EntityID{
+					Kind: workloadmeta.KindContainerImageMetadata,
+					ID:   "my_image_id_2",
+				},
+				EntityMeta: workloadmeta.EntityMeta{
+					Name: "docker.io/datadog/agent-dev",
+				},
+				RepoTags: []string{"docker.io/datadog/agent-dev:latest"},
 			},
 		},
 	}
@@ -261,6 +299,7 @@ func TestPull(t *testing.T) {
 		mockGetAllContainers: func() ([]podman.Container, error) {
 			return containers, nil
 		},
+		pods: []podman.Pod{{Config: &podman.PodConfig{ID: "pod-id", Name: "dd-pod"}}},
 	}
 
 	tests := []struct {
@@ -279,8 +318,8 @@ func TestPull(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			workloadmetaStore := fakeWorkloadmetaStore{}
 			podmanCollector := collector{
-				client: test.client,
-				store:  &workloadmetaStore,
+				clients: map[string]podmanClient{"/var/lib/containers/storage/db.sql": test.client},
+				store:   &workloadmetaStore,
 			}
 
 			err := podmanCollector.Pull(context.TODO())
@@ -291,6 +330,103 @@ func TestPull(t *testing.T) {
 	}
 }
 
+func TestPullSeveralDBs(t *testing.T) {
+	newContainer := func(id string, imageID string) podman.Container {
+		return podman.Container{
+			Config: &podman.ContainerConfig{
+				Spec: &specs.Spec{},
+				ID:   id,
+				ContainerRootFSConfig: podman.ContainerRootFSConfig{
+					RootfsImageID: imageID,
+				},
+			},
+			State: &podman.ContainerState{State: podman.ContainerStateRunning},
+		}
+	}
+
+	var rootlessErr error
+	rootfulContainers := []podman.Container{newContainer("rootful", "shared_image")}
+	rootlessContainers := []podman.Container{newContainer("rootless", "shared_image")}
+	workloadmetaStore := fakeWorkloadmetaStore{}
+	podmanCollector := collector{
+		clients: map[string]podmanClient{
+			"/var/lib/containers/storage/db.sql": &fakePodmanClient{
+				mockGetAllContainers: func() ([]podman.Container, error) { return rootfulContainers, nil },
+			},
+			"/home/user/.local/share/containers/storage/db.sql": &fakePodmanClient{
+				mockGetAllContainers: func() ([]podman.Container, error) { return rootlessContainers, rootlessErr },
+			},
+		},
+		store: &workloadmetaStore,
+	}
+
+	notifiedIDs := func() map[string]workloadmeta.EventType {
+		ids := make(map[string]workloadmeta.EventType)
+		for _, event := range workloadmetaStore.notifiedEvents {
+			ids[event.Entity.GetID().ID] = event.Type
+		}
+		workloadmetaStore.notifiedEvents = nil
+		return ids
+	}
+
+	assert.NoError(t, podmanCollector.Pull(context.TODO()))
+	assert.Equal(t, map[string]workloadmeta.EventType{
+		"rootful":      workloadmeta.EventTypeSet,
+		"rootless":     workloadmeta.EventTypeSet,
+		"shared_image": workloadmeta.EventTypeSet,
+	}, notifiedIDs())
+
+	// the containers of a DB which can't be read are kept
+	rootlessErr = errors.New("database is locked")
+	rootfulContainers = nil
+	assert.NoError(t, podmanCollector.Pull(context.TODO()))
+	assert.Equal(t, map[string]workloadmeta.EventType{
+		"rootful": workloadmeta.EventTypeUnset,
+	}, notifiedIDs())
+
+	rootlessErr = nil
+	rootlessContainers = nil
+	assert.NoError(t, podmanCollector.Pull(context.TODO()))
+	assert.Equal(t, map[string]workloadmeta.EventType{
+		"rootless":     workloadmeta.EventTypeUnset,
+		"shared_image": workloadmeta.EventTypeUnset,
+	}, notifiedIDs())
+}
+
+func TestDiscoverRootlessDBs(t *testing.T) {
+	hostDir := t.TempDir()
+	mkdir := func(path string) {
+		assert.NoError(t, os.MkdirAll(filepath.Join(hostDir, path), 0755))
+	}
+	touch := func(path string) {
+		mkdir(filepath.Dir(path))
+		assert.NoError(t, os.WriteFile(filepath.Join(hostDir, path), nil, 0644))
+	}
+
+	touch("/etc/passwd")
+	assert.NoError(t, os.WriteFile(filepath.Join(hostDir, "/etc/passwd"), []byte(`root:x:0:0:root:/root:/bin/bash
+# comment
+alice:x:1000:1000:Alice:/home/alice:/bin/bash
+bob:x:1001:1001::/home/bob:/bin/sh
+carol:x:1002:1002::/home/carol:/bin/sh
+`), 0644))
+
+	// alice uses a SQLite DB, bob a BoltDB, carol has no DB and dave isn't a known user
+	mkdir("/run/user/1000/containers")
+	touch("/home/alice/.local/share/containers/storage/db.sql")
+	mkdir("/run/user/1001/containers")
+	touch("/home/bob/.local/share/containers/storage/libpod/bolt_state.db")
+	mkdir("/run/user/1002/containers")
+	mkdir("/run/user/1003/containers")
+	// users without a session aren't looked for
+	touch("/root/.local/share/containers/storage/db.sql")
+
+	assert.Equal(t, []string{
+		filepath.Join(hostDir, "/home/alice/.local/share/containers/storage/db.sql"),
+		filepath.Join(hostDir, "/home/bob/.local/share/containers/storage/libpod/bolt_state.db"),
+	}, discoverRootlessDBs([]string{t.TempDir(), hostDir}))
+}
+
 func TestNetworkIPS(t *testing.T) {
 	tests := []struct {
 		name               string
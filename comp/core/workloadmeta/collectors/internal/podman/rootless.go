@@ -0,0 +1,99 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build podman
+
+package podman
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/config/env"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Rootless Podman keeps the containers DB of each user in their home directory.
+// The users running rootless containers are found from their runtime
+// directories, which hold the runtime state of their containers.
+const (
+	rootlessRunPattern    = "/run/user/*/containers"
+	rootlessStoragePath   = ".local/share/containers/storage"
+	rootlessBoltDBPath    = rootlessStoragePath + "/libpod/bolt_state.db"
+	rootlessSqliteDBPath  = rootlessStoragePath + "/db.sql"
+	passwdPath            = "/etc/passwd"
+	defaultHostMountPoint = "/host"
+)
+
+// hostPrefixes returns the prefixes the paths of the host are found at
+func hostPrefixes() []string {
+	if env.IsContainerized() {
+		return []string{"", defaultHostMountPoint}
+	}
+	return []string{""}
+}
+
+// discoverRootlessDBs returns the paths of the containers DBs of the users
+// running rootless Podman containers
+func discoverRootlessDBs(prefixes []string) []string {
+	var dbPaths []string
+	for _, prefix := range prefixes {
+		runDirs, _ := filepath.Glob(filepath.Join(prefix, rootlessRunPattern))
+		if len(runDirs) == 0 {
+			continue
+		}
+
+		homes, err := readHomeDirs(filepath.Join(prefix, passwdPath))
+		if err != nil {
+			log.Debugf("Could not read the home directories of the rootless Podman users: %v", err)
+			continue
+		}
+
+		for _, runDir := range runDirs {
+			uid := filepath.Base(filepath.Dir(runDir))
+			home, ok := homes[uid]
+			if !ok {
+				log.Debugf("Could not find the home directory of the rootless Podman user %s", uid)
+				continue
+			}
+
+			// Podman keeps using the BoltDB created by its previous versions, the SQLite DB is the default otherwise
+			if dbPath := filepath.Join(prefix, home, rootlessBoltDBPath); dbIsAccessible(dbPath) {
+				dbPaths = append(dbPaths, dbPath)
+			} else if dbPath := filepath.Join(prefix, home, rootlessSqliteDBPath); dbIsAccessible(dbPath) {
+				dbPaths = append(dbPaths, dbPath)
+			}
+		}
+
+		// the next prefixes are only looked at when no DB was found under this one
+		if len(dbPaths) > 0 {
+			break
+		}
+	}
+	return dbPaths
+}
+
+// readHomeDirs returns the home directories of the users of a passwd file, by UID
+func readHomeDirs(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	homes := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// name:password:UID:GID:GECOS:directory:shell
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) < 6 || strings.HasPrefix(fields[0], "#") || fields[5] == "" {
+			continue
+		}
+		homes[fields[2]] = fields[5]
+	}
+	return homes, scanner.Err()
+}
@@ -0,0 +1,155 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Package enricher implements the workloadmeta enrichers calling the
+// WorkloadmetaEnricher gRPC service of external processes.
+package enricher
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	"go.uber.org/fx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/proto"
+	"github.com/DataDog/datadog-agent/pkg/config/structure"
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/core"
+	grpcutil "github.com/DataDog/datadog-agent/pkg/util/grpc"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const defaultTimeout = 500 * time.Millisecond
+
+// These are the workloadmeta kinds sent to the remote enrichers, they're the
+// ones which can be converted to protobuf.
+var supportedKinds = []workloadmeta.Kind{
+	workloadmeta.KindContainer,
+	workloadmeta.KindKubernetesPod,
+	workloadmeta.KindECSTask,
+}
+
+// enricherConfig is the configuration of a remote enricher, in
+// `workloadmeta.remote_enrichers`.
+type enricherConfig struct {
+	ID        string `mapstructure:"id"`
+	Endpoint  string `mapstructure:"endpoint"`
+	AuthToken string `mapstructure:"auth_token"`
+	TimeoutMs int    `mapstructure:"timeout_ms"`
+}
+
+type dependencies struct {
+	fx.In
+
+	Config config.Component
+}
+
+type provides struct {
+	fx.Out
+
+	Enrichers []workloadmeta.Enricher `group:"workloadmeta_enrichers,flatten"`
+}
+
+type enricher struct {
+	id      string
+	client  pb.WorkloadmetaEnricherClient
+	timeout time.Duration
+}
+
+// NewEnrichers returns the remote enrichers configured in
+// `workloadmeta.remote_enrichers`. The invalid ones are skipped.
+func NewEnrichers(deps dependencies) provides {
+	var configs []enricherConfig
+	if err := structure.UnmarshalKey(deps.Config, "workloadmeta.remote_enrichers", &configs); err != nil {
+		log.Warnf("Invalid workloadmeta remote enrichers, ignoring them: %v", err)
+		return provides{}
+	}
+
+	var enrichers []workloadmeta.Enricher
+	for _, cfg := range configs {
+		if cfg.ID == "" || cfg.Endpoint == "" {
+			log.Warnf("Ignoring workloadmeta remote enricher %q: both id and endpoint are required", cfg.ID)
+			continue
+		}
+
+		client, err := newEnricherClient(cfg)
+		if err != nil {
+			log.Warnf("Ignoring workloadmeta remote enricher %q: %v", cfg.ID, err)
+			continue
+		}
+
+		timeout := defaultTimeout
+		if cfg.TimeoutMs > 0 {
+			timeout = time.Duration(cfg.TimeoutMs) * time.Millisecond
+		}
+
+		enrichers = append(enrichers, &enricher{
+			id:      cfg.ID,
+			client:  client,
+			timeout: timeout,
+		})
+	}
+
+	return provides{Enrichers: enrichers}
+}
+
+func newEnricherClient(cfg enricherConfig) (pb.WorkloadmetaEnricherClient, error) {
+	// NOTE: we're using InsecureSkipVerify because the enrichers are local
+	// processes, which usually don't have a certificate signed by a known
+	// authority. This is NOT equivalent to grpc.WithInsecure(), since that
+	// assumes a non-TLS connection.
+	tlsCreds := credentials.NewTLS(&tls.Config{
+		InsecureSkipVerify: true,
+	})
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(tlsCreds)}
+	if cfg.AuthToken != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(grpcutil.NewBearerTokenAuth(cfg.AuthToken)))
+	}
+
+	conn, err := grpc.NewClient(cfg.Endpoint, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pb.NewWorkloadmetaEnricherClient(conn), nil
+}
+
+// GetID implements workloadmeta.Enricher#GetID.
+func (e *enricher) GetID() string {
+	return e.id
+}
+
+// Kinds implements workloadmeta.Enricher#Kinds.
+func (e *enricher) Kinds() []workloadmeta.Kind {
+	return supportedKinds
+}
+
+// Enrich implements workloadmeta.Enricher#Enrich.
+func (e *enricher) Enrich(ctx context.Context, entity workloadmeta.Entity) (map[string]string, error) {
+	request, err := proto.ProtobufEnrichRequestFromWorkloadmetaEntity(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	response, err := e.client.Enrich(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetMetadata(), nil
+}
+
+// GetFxOptions returns the FX framework options for the remote enrichers
+func GetFxOptions() fx.Option {
+	return fx.Provide(NewEnrichers)
+}
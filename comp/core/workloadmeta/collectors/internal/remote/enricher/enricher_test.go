@@ -0,0 +1,74 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package enricher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/core"
+)
+
+type fakeEnricherClient struct {
+	request *pb.WorkloadmetaEnrichRequest
+}
+
+func (c *fakeEnricherClient) Enrich(_ context.Context, in *pb.WorkloadmetaEnrichRequest, _ ...grpc.CallOption) (*pb.WorkloadmetaEnrichResponse, error) {
+	c.request = in
+	return &pb.WorkloadmetaEnrichResponse{Metadata: map[string]string{"team": "containers"}}, nil
+}
+
+func TestNewEnrichers(t *testing.T) {
+	cfg := config.NewMock(t)
+	cfg.SetWithoutSource("workloadmeta.remote_enrichers", []map[string]interface{}{
+		{"id": "cmdb", "endpoint": "localhost:5101", "auth_token": "token", "timeout_ms": 100},
+		{"id": "no-endpoint"},
+		{"id": "default-timeout", "endpoint": "localhost:5102"},
+	})
+
+	enrichers := NewEnrichers(dependencies{Config: cfg}).Enrichers
+	require.Len(t, enrichers, 2)
+
+	assert.Equal(t, "cmdb", enrichers[0].GetID())
+	assert.Equal(t, 100*time.Millisecond, enrichers[0].(*enricher).timeout)
+	assert.Equal(t, supportedKinds, enrichers[0].Kinds())
+
+	assert.Equal(t, "default-timeout", enrichers[1].GetID())
+	assert.Equal(t, defaultTimeout, enrichers[1].(*enricher).timeout)
+}
+
+func TestEnrich(t *testing.T) {
+	client := &fakeEnricherClient{}
+	e := &enricher{id: "cmdb", client: client, timeout: time.Second}
+
+	container := &workloadmeta.Container{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindContainer,
+			ID:   "123",
+		},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name: "redis",
+		},
+	}
+
+	metadata, err := e.Enrich(context.Background(), container)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "containers"}, metadata)
+	assert.Equal(t, "123", client.request.GetEntityId().GetId())
+	assert.Equal(t, "redis", client.request.GetEntityMeta().GetName())
+
+	_, err = e.Enrich(context.Background(), &workloadmeta.GPU{
+		EntityID: workloadmeta.EntityID{Kind: workloadmeta.KindGPU, ID: "gpu"},
+	})
+	assert.Error(t, err)
+}
@@ -113,8 +113,9 @@ func (c *GenericCollector) Start(ctx context.Context, store workloadmeta.Compone
 		opts = append(opts, grpc.WithTransportCredentials(creds))
 	}
 
-	conn, err := grpc.DialContext( //nolint:staticcheck // TODO (ASC) fix grpc.DialContext is deprecated
+	conn, err := grpcutil.NewClientConn(
 		c.ctx,
+		c.CollectorID,
 		fmt.Sprintf(":%v", c.StreamHandler.Port()),
 		opts...,
 	)
@@ -0,0 +1,56 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package util
+
+import (
+	"time"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// The runtimes don't tell whether a container was restored from a checkpoint,
+// but the checkpoint archives created by the CRI CheckpointContainer call are
+// annotated with the checkpointed container by the checkpointctl library,
+// which is shared by CRI-O, containerd and Podman. The annotations are kept
+// on the checkpoint image and on the containers restored from it.
+const (
+	checkpointNameAnnotation      = "io.kubernetes.cri-o.annotations.checkpoint.name"
+	checkpointPodAnnotation       = "io.kubernetes.cri-o.annotations.checkpoint.pod"
+	checkpointNamespaceAnnotation = "io.kubernetes.cri-o.annotations.checkpoint.namespace"
+	checkpointCreatedAnnotation   = "io.kubernetes.cri-o.annotations.checkpoint.created"
+)
+
+// ParseContainerCheckpoint returns the checkpoint a container was restored
+// from, read from the first of the given annotations or labels which have
+// the checkpoint annotations, or nil if the container wasn't restored.
+func ParseContainerCheckpoint(containerID string, annotations ...map[string]string) *workloadmeta.ContainerCheckpoint {
+	for _, a := range annotations {
+		name, ok := a[checkpointNameAnnotation]
+		if !ok {
+			continue
+		}
+
+		checkpoint := &workloadmeta.ContainerCheckpoint{
+			ContainerName: name,
+			PodName:       a[checkpointPodAnnotation],
+			PodNamespace:  a[checkpointNamespaceAnnotation],
+		}
+
+		if created := a[checkpointCreatedAnnotation]; created != "" {
+			createdAt, err := time.Parse(time.RFC3339Nano, created)
+			if err != nil {
+				log.Debugf("Could not parse checkpoint creation time of container %s: %v", containerID, err)
+			} else {
+				checkpoint.CreatedAt = createdAt.UTC()
+			}
+		}
+
+		return checkpoint
+	}
+
+	return nil
+}
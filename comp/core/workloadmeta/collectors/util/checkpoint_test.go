@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+)
+
+func TestParseContainerCheckpoint(t *testing.T) {
+	checkpointAnnotations := map[string]string{
+		"io.kubernetes.cri-o.annotations.checkpoint.name":      "app",
+		"io.kubernetes.cri-o.annotations.checkpoint.pod":       "app-7d9c5",
+		"io.kubernetes.cri-o.annotations.checkpoint.namespace": "default",
+		"io.kubernetes.cri-o.annotations.checkpoint.created":   "2024-03-01T10:00:00.5Z",
+	}
+	expected := &workloadmeta.ContainerCheckpoint{
+		ContainerName: "app",
+		PodName:       "app-7d9c5",
+		PodNamespace:  "default",
+		CreatedAt:     time.Date(2024, time.March, 1, 10, 0, 0, 5e8, time.UTC),
+	}
+
+	tests := []struct {
+		name        string
+		annotations []map[string]string
+		expected    *workloadmeta.ContainerCheckpoint
+	}{
+		{
+			name:        "not restored",
+			annotations: []map[string]string{{"app": "web"}, nil},
+		},
+		{
+			name:        "first annotations",
+			annotations: []map[string]string{checkpointAnnotations, {"io.kubernetes.cri-o.annotations.checkpoint.name": "other"}},
+			expected:    expected,
+		},
+		{
+			name:        "fallback annotations",
+			annotations: []map[string]string{nil, {"app": "web"}, checkpointAnnotations},
+			expected:    expected,
+		},
+		{
+			name: "invalid creation time",
+			annotations: []map[string]string{{
+				"io.kubernetes.cri-o.annotations.checkpoint.name":    "app",
+				"io.kubernetes.cri-o.annotations.checkpoint.created": "yesterday",
+			}},
+			expected: &workloadmeta.ContainerCheckpoint{ContainerName: "app"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseContainerCheckpoint("id", tt.annotations...))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package workloadmeta
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/fx"
+)
+
+// enricherSourcePrefix is the prefix of the sources of the enrichers, followed
+// by their ID.
+const enricherSourcePrefix = "enricher_"
+
+// Enricher attaches extra metadata to the entities collected by the
+// collectors, e.g. the owner of a service from an internal CMDB. The metadata
+// is stored in the ExtraMetadata of the entities, under the source of the
+// enricher, and is reported as tags by the tagger.
+//
+// The metadata of the enrichers doesn't keep an entity in the store: it's
+// removed with the last source of the collectors.
+type Enricher interface {
+	// GetID returns the identifier of the enricher.
+	GetID() string
+
+	// Kinds returns the kinds of entities to enrich. Only the kinds with an
+	// EntityMeta can be enriched.
+	Kinds() []Kind
+
+	// Enrich returns the metadata of an entity, or nil if it has none. It's
+	// called whenever the entity changes, and shouldn't block for long.
+	Enrich(context.Context, Entity) (map[string]string, error)
+}
+
+// EnricherProvider is the enricher fx value group
+type EnricherProvider struct {
+	fx.Out
+
+	Enricher Enricher `group:"workloadmeta_enrichers"`
+}
+
+// EnricherList is an array of Enrichers
+type EnricherList []Enricher
+
+// EnricherSource returns the source of the metadata attached by an enricher.
+func EnricherSource(enricherID string) Source {
+	return Source(enricherSourcePrefix + enricherID)
+}
+
+// IsEnricher returns whether the source is the one of an enricher.
+func (s Source) IsEnricher() bool {
+	return strings.HasPrefix(string(s), enricherSourcePrefix)
+}
+
+// NewEnrichedEntity returns an entity holding only the extra metadata of an
+// enricher, to be set under the source of the enricher.
+func NewEnrichedEntity(id EntityID, metadata map[string]string) (Entity, error) {
+	meta := EntityMeta{ExtraMetadata: metadata}
+
+	switch id.Kind {
+	case KindContainer:
+		return &Container{EntityID: id, EntityMeta: meta}, nil
+	case KindKubernetesPod:
+		return &KubernetesPod{EntityID: id, EntityMeta: meta}, nil
+	case KindKubernetesMetadata:
+		return &KubernetesMetadata{EntityID: id, EntityMeta: meta}, nil
+	case KindKubernetesDeployment:
+		return &KubernetesDeployment{EntityID: id, EntityMeta: meta}, nil
+	case KindECSTask:
+		return &ECSTask{EntityID: id, EntityMeta: meta}, nil
+	case KindContainerImageMetadata:
+		return &ContainerImageMetadata{EntityID: id, EntityMeta: meta}, nil
+	case KindGPU:
+		return &GPU{EntityID: id, EntityMeta: meta}, nil
+	default:
+		return nil, fmt.Errorf("entities of kind %q can't be enriched", id.Kind)
+	}
+}
@@ -0,0 +1,31 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package workloadmeta
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnricherSource(t *testing.T) {
+	assert.Equal(t, Source("enricher_cmdb"), EnricherSource("cmdb"))
+	assert.True(t, EnricherSource("cmdb").IsEnricher())
+	assert.False(t, SourceRuntime.IsEnricher())
+}
+
+func TestNewEnrichedEntity(t *testing.T) {
+	metadata := map[string]string{"team": "containers"}
+
+	id := EntityID{Kind: KindKubernetesPod, ID: "pod"}
+	entity, err := NewEnrichedEntity(id, metadata)
+	require.NoError(t, err)
+	assert.Equal(t, &KubernetesPod{EntityID: id, EntityMeta: EntityMeta{ExtraMetadata: metadata}}, entity)
+
+	_, err = NewEnrichedEntity(EntityID{Kind: KindProcess, ID: "123"}, metadata)
+	assert.Error(t, err)
+}
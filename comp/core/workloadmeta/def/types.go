@@ -239,6 +239,8 @@ type EntityMeta struct {
 	Namespace   string
 	Annotations map[string]string
 	Labels      map[string]string
+	// ExtraMetadata is the metadata attached by the enrichers, see Enricher
+	ExtraMetadata map[string]string
 }
 
 // String returns a string representation of EntityMeta.
@@ -250,6 +252,9 @@ func (e EntityMeta) String(verbose bool) string {
 	if verbose {
 		_, _ = fmt.Fprintln(&sb, "Annotations:", mapToScrubbedJSONString(e.Annotations))
 		_, _ = fmt.Fprintln(&sb, "Labels:", mapToScrubbedJSONString(e.Labels))
+		if len(e.ExtraMetadata) > 0 {
+			_, _ = fmt.Fprintln(&sb, "Extra Metadata:", mapToScrubbedJSONString(e.ExtraMetadata))
+		}
 	}
 
 	return sb.String()
@@ -322,6 +327,8 @@ type ContainerState struct {
 	StartedAt  time.Time
 	FinishedAt time.Time
 	ExitCode   *int64
+	// Checkpoint is set when the container was restored from a checkpoint
+	Checkpoint *ContainerCheckpoint
 }
 
 // String returns a string representation of ContainerState.
@@ -338,6 +345,32 @@ func (c ContainerState) String(verbose bool) string {
 		if c.ExitCode != nil {
 			_, _ = fmt.Fprintln(&sb, "Exit Code:", *c.ExitCode)
 		}
+		if c.Checkpoint != nil {
+			_, _ = fmt.Fprint(&sb, c.Checkpoint.String(verbose))
+		}
+	}
+
+	return sb.String()
+}
+
+// ContainerCheckpoint is the checkpoint a container was restored from.
+type ContainerCheckpoint struct {
+	// ContainerName, PodName and PodNamespace identify the checkpointed container
+	ContainerName string
+	PodName       string
+	PodNamespace  string
+	CreatedAt     time.Time
+}
+
+// String returns a string representation of ContainerCheckpoint.
+func (c ContainerCheckpoint) String(verbose bool) string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintln(&sb, "Restored From Checkpoint Of:", c.ContainerName)
+
+	if verbose {
+		_, _ = fmt.Fprintln(&sb, "Checkpoint Pod Name:", c.PodName)
+		_, _ = fmt.Fprintln(&sb, "Checkpoint Pod Namespace:", c.PodNamespace)
+		_, _ = fmt.Fprintln(&sb, "Checkpoint Created At:", c.CreatedAt)
 	}
 
 	return sb.String()
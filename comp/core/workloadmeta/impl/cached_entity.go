@@ -39,6 +39,26 @@ func (e *cachedEntity) unset(source wmdef.Source) bool {
 	return false
 }
 
+// unsetEnrichments removes the sources of the enrichers if they are the only
+// ones left, and returns them.
+func (e *cachedEntity) unsetEnrichments() []wmdef.Source {
+	var enrichments []wmdef.Source
+	for source := range e.sources {
+		if !source.IsEnricher() {
+			return nil
+		}
+		enrichments = append(enrichments, source)
+	}
+
+	for _, source := range enrichments {
+		delete(e.sources, source)
+	}
+	if len(enrichments) > 0 {
+		e.computeCache()
+	}
+	return enrichments
+}
+
 func (e *cachedEntity) set(source wmdef.Source, entity wmdef.Entity) (found, changed bool) {
 	old, found := e.sources[source]
 
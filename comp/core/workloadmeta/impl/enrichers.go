@@ -0,0 +1,148 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package workloadmetaimpl
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	wmdef "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+)
+
+// enricherRunner feeds an enricher with the entities of the store, and pushes
+// the metadata it returns under its source.
+//
+// The events are queued and enriched in a separate goroutine, so that a slow
+// enricher doesn't hold the notifications of the store.
+type enricherRunner struct {
+	store    *workloadmeta
+	enricher wmdef.Enricher
+	source   wmdef.Source
+
+	mu      sync.Mutex
+	pending map[wmdef.EntityID]wmdef.Entity // nil when the entity was removed
+	wake    chan struct{}
+
+	// enriched is the metadata pushed for each entity, only accessed by the
+	// enriching goroutine
+	enriched map[wmdef.EntityID]map[string]string
+}
+
+func newEnricherRunner(store *workloadmeta, enricher wmdef.Enricher) *enricherRunner {
+	return &enricherRunner{
+		store:    store,
+		enricher: enricher,
+		source:   wmdef.EnricherSource(enricher.GetID()),
+		pending:  make(map[wmdef.EntityID]wmdef.Entity),
+		wake:     make(chan struct{}, 1),
+		enriched: make(map[wmdef.EntityID]map[string]string),
+	}
+}
+
+// startEnrichers starts the enrichers, they run until the context is done.
+func (w *workloadmeta) startEnrichers(ctx context.Context) {
+	for _, enricher := range w.enrichers {
+		r := newEnricherRunner(w, enricher)
+		go r.enrich(ctx)
+		go r.listen(ctx)
+	}
+}
+
+// listen queues the entities of the store to enrich. The subscription is named
+// after the source of the enricher, so that the store doesn't notify it of the
+// metadata of the enrichers.
+func (r *enricherRunner) listen(ctx context.Context) {
+	filterBuilder := wmdef.NewFilterBuilder()
+	for _, kind := range r.enricher.Kinds() {
+		filterBuilder = filterBuilder.AddKind(kind)
+	}
+
+	ch := r.store.Subscribe(string(r.source), wmdef.NormalPriority, filterBuilder.Build())
+	defer r.store.Unsubscribe(ch)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case bundle, ok := <-ch:
+			if !ok {
+				return
+			}
+			bundle.Acknowledge()
+
+			r.mu.Lock()
+			for _, event := range bundle.Events {
+				if event.Type == wmdef.EventTypeSet {
+					r.pending[event.Entity.GetID()] = event.Entity
+				} else {
+					r.pending[event.Entity.GetID()] = nil
+				}
+			}
+			r.mu.Unlock()
+
+			select {
+			case r.wake <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// enrich calls the enricher on the queued entities, and pushes the metadata
+// which changed.
+func (r *enricherRunner) enrich(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		}
+
+		r.mu.Lock()
+		pending := r.pending
+		r.pending = make(map[wmdef.EntityID]wmdef.Entity)
+		r.mu.Unlock()
+
+		var events []wmdef.Event
+		for id, entity := range pending {
+			if entity == nil {
+				// the store removed the metadata with the entity
+				delete(r.enriched, id)
+				continue
+			}
+
+			metadata, err := r.enricher.Enrich(ctx, entity)
+			if err != nil {
+				r.store.log.Debugf("enricher %q cannot enrich %s %q: %v", r.enricher.GetID(), id.Kind, id.ID, err)
+				continue
+			}
+
+			if maps.Equal(metadata, r.enriched[id]) {
+				continue
+			}
+
+			eventType := wmdef.EventTypeSet
+			if len(metadata) == 0 {
+				eventType = wmdef.EventTypeUnset
+				delete(r.enriched, id)
+			} else {
+				r.enriched[id] = metadata
+			}
+
+			enriched, err := wmdef.NewEnrichedEntity(id, metadata)
+			if err != nil {
+				r.store.log.Debugf("enricher %q cannot enrich %s %q: %v", r.enricher.GetID(), id.Kind, id.ID, err)
+				continue
+			}
+			events = append(events, wmdef.Event{Type: eventType, Entity: enriched})
+		}
+
+		if len(events) > 0 {
+			_ = r.store.Push(r.source, events...)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build test
+
+package workloadmetaimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wmdef "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/errors"
+)
+
+type fakeEnricher struct {
+	metadata map[string]string
+}
+
+func (e *fakeEnricher) GetID() string {
+	return "fake"
+}
+
+func (e *fakeEnricher) Kinds() []wmdef.Kind {
+	return []wmdef.Kind{wmdef.KindContainer}
+}
+
+func (e *fakeEnricher) Enrich(_ context.Context, entity wmdef.Entity) (map[string]string, error) {
+	if entity.(*wmdef.Container).Name == "ignored" {
+		return nil, nil
+	}
+	return e.metadata, nil
+}
+
+func TestHandleEnricherEvents(t *testing.T) {
+	s := newWorkloadmetaObject(t)
+	enricherSource := wmdef.EnricherSource("fake")
+
+	containerID := wmdef.EntityID{
+		Kind: wmdef.KindContainer,
+		ID:   "deadbeef",
+	}
+	container := &wmdef.Container{
+		EntityID: containerID,
+		EntityMeta: wmdef.EntityMeta{
+			Name: "redis",
+		},
+	}
+	enriched := &wmdef.Container{
+		EntityID: containerID,
+		EntityMeta: wmdef.EntityMeta{
+			ExtraMetadata: map[string]string{"team": "containers"},
+		},
+	}
+
+	// the metadata of an enricher doesn't create an entity
+	s.handleEvents([]wmdef.CollectorEvent{
+		{Type: wmdef.EventTypeSet, Source: enricherSource, Entity: enriched},
+	})
+	_, err := s.GetContainer(containerID.ID)
+	assert.True(t, errors.IsNotFound(err))
+
+	// the metadata of an enricher is merged with the collected entity
+	s.handleEvents([]wmdef.CollectorEvent{
+		{Type: wmdef.EventTypeSet, Source: fooSource, Entity: container},
+		{Type: wmdef.EventTypeSet, Source: enricherSource, Entity: enriched},
+	})
+	got, err := s.GetContainer(containerID.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "redis", got.Name)
+	assert.Equal(t, map[string]string{"team": "containers"}, got.ExtraMetadata)
+
+	// the metadata of an enricher is removed with the last collected source
+	s.handleEvents([]wmdef.CollectorEvent{
+		{Type: wmdef.EventTypeUnset, Source: fooSource, Entity: container},
+	})
+	_, err = s.GetContainer(containerID.ID)
+	assert.True(t, errors.IsNotFound(err))
+
+	s.handleEvents([]wmdef.CollectorEvent{
+		{Type: wmdef.EventTypeSet, Source: fooSource, Entity: container},
+	})
+	got, err = s.GetContainer(containerID.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.ExtraMetadata)
+}
+
+func TestEnricherRunner(t *testing.T) {
+	s := newWorkloadmetaObject(t)
+	s.enrichers = []wmdef.Enricher{&fakeEnricher{metadata: map[string]string{"team": "containers"}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		for {
+			select {
+			case evs := <-s.eventCh:
+				s.handleEvents(evs)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	s.startEnrichers(ctx)
+
+	container := &wmdef.Container{
+		EntityID: wmdef.EntityID{
+			Kind: wmdef.KindContainer,
+			ID:   "deadbeef",
+		},
+		EntityMeta: wmdef.EntityMeta{
+			Name: "redis",
+		},
+	}
+	s.Notify([]wmdef.CollectorEvent{
+		{Type: wmdef.EventTypeSet, Source: fooSource, Entity: container},
+	})
+
+	assert.Eventually(t, func() bool {
+		got, err := s.GetContainer(container.ID)
+		return err == nil && got.ExtraMetadata["team"] == "containers"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the metadata is removed when the enricher doesn't return it anymore
+	s.Notify([]wmdef.CollectorEvent{
+		{
+			Type:   wmdef.EventTypeSet,
+			Source: fooSource,
+			Entity: &wmdef.Container{
+				EntityID:   container.EntityID,
+				EntityMeta: wmdef.EntityMeta{Name: "ignored"},
+			},
+		},
+	})
+
+	assert.Eventually(t, func() bool {
+		got, err := s.GetContainer(container.ID)
+		return err == nil && got.Name == "ignored" && len(got.ExtraMetadata) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -59,6 +59,8 @@ func (w *workloadmeta) start(ctx context.Context) {
 		}
 	}()
 
+	w.startEnrichers(ctx)
+
 	go func() {
 		if err := w.startCandidatesWithRetry(ctx); err != nil {
 			w.log.Errorf("error starting collectors: %s", err)
@@ -674,9 +676,15 @@ func (w *workloadmeta) handleEvents(evs []wmdef.CollectorEvent) {
 		}
 
 		cachedEntity, ok := entitiesOfKind[entityID.ID]
+		removed := false
 
 		switch ev.Type {
 		case wmdef.EventTypeSet:
+			if !ok && ev.Source.IsEnricher() {
+				// the entity was removed while being enriched
+				continue
+			}
+
 			if !ok {
 				entitiesOfKind[entityID.ID] = newCachedEntity()
 				cachedEntity = entitiesOfKind[entityID.ID]
@@ -723,8 +731,28 @@ func (w *workloadmeta) handleEvents(evs []wmdef.CollectorEvent) {
 				string(ev.Source),
 			)
 
+			if ev.Source.IsEnricher() {
+				// the entity is notified without the metadata of the
+				// enricher, which is only kept for the subscribers of
+				// its source
+				enrichment := cachedEntity.get(ev.Source)
+				cachedEntity = c.copy()
+				cachedEntity.sources[ev.Source] = enrichment
+			} else {
+				// the metadata of the enrichers doesn't keep an entity
+				// in the store once all of its collected sources are
+				// removed
+				for _, source := range c.unsetEnrichments() {
+					telemetry.StoredEntities.Dec(
+						string(entityID.Kind),
+						string(source),
+					)
+				}
+			}
+
 			if len(c.sources) == 0 {
 				delete(entitiesOfKind, entityID.ID)
+				removed = true
 			}
 		default:
 			w.log.Errorf("cannot handle event of type %d. event dump: %+v", ev.Type, ev)
@@ -733,6 +761,12 @@ func (w *workloadmeta) handleEvents(evs []wmdef.CollectorEvent) {
 		for _, sub := range w.subscribers {
 			filter := sub.filter
 
+			// the enrichers subscribe under their source, and aren't
+			// notified of the metadata of the enrichers
+			if ev.Source.IsEnricher() && wmdef.Source(sub.name).IsEnricher() {
+				continue
+			}
+
 			// Notice that we cannot call filter.MatchEntity() here because
 			// the entity included in the event might be incomplete if it's
 			// an unset event. Some collectors only send the entity ID in
@@ -748,7 +782,7 @@ func (w *workloadmeta) handleEvents(evs []wmdef.CollectorEvent) {
 			if ev.Type == wmdef.EventTypeSet {
 				isEventTypeSet = true
 			} else if filter.Source() == wmdef.SourceAll {
-				isEventTypeSet = len(cachedEntity.sources) > 1
+				isEventTypeSet = !removed
 			} else {
 				isEventTypeSet = false
 			}
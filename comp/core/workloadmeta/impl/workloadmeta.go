@@ -42,6 +42,8 @@ type workloadmeta struct {
 	collectors            map[string]wmdef.Collector
 	collectorsInitialized wmdef.CollectorStatus
 
+	enrichers []wmdef.Enricher
+
 	eventCh chan []wmdef.CollectorEvent
 
 	ongoingPullsMut sync.Mutex
@@ -56,6 +58,8 @@ type Dependencies struct {
 	Config  config.Component
 	Catalog wmdef.CollectorList `group:"workloadmeta"`
 
+	Enrichers wmdef.EnricherList `group:"workloadmeta_enrichers"`
+
 	Params wmdef.Params
 }
 
@@ -82,6 +86,7 @@ func NewWorkloadMeta(deps Dependencies) Provider {
 		store:                 make(map[wmdef.Kind]map[string]*cachedEntity),
 		candidates:            candidates,
 		collectors:            make(map[string]wmdef.Collector),
+		enrichers:             fxutil.GetAndFilterGroup(deps.Enrichers),
 		eventCh:               make(chan []wmdef.CollectorEvent, eventChBufferSize),
 		ongoingPulls:          make(map[string]time.Time),
 		collectorsInitialized: wmdef.CollectorsNotStarted,
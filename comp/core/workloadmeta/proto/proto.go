@@ -113,6 +113,38 @@ func ProtobufFilterFromWorkloadmetaFilter(filter *workloadmeta.Filter) (*pb.Work
 	}, nil
 }
 
+// ProtobufEnrichRequestFromWorkloadmetaEntity converts the given
+// workloadmeta.Entity into the protobuf request sent to the remote enrichers
+func ProtobufEnrichRequestFromWorkloadmetaEntity(entity workloadmeta.Entity) (*pb.WorkloadmetaEnrichRequest, error) {
+	var (
+		protoEntityID   *pb.WorkloadmetaEntityId
+		protoEntityMeta *pb.EntityMeta
+		err             error
+	)
+
+	switch entity := entity.(type) {
+	case *workloadmeta.Container:
+		protoEntityID, err = toProtoEntityIDFromContainer(entity)
+		protoEntityMeta = toProtoEntityMetaFromContainer(entity)
+	case *workloadmeta.KubernetesPod:
+		protoEntityID, err = toProtoEntityIDFromKubernetesPod(entity)
+		protoEntityMeta = toProtoEntityMetaFromKubernetesPod(entity)
+	case *workloadmeta.ECSTask:
+		protoEntityID, err = toProtoEntityIDFromECSTask(entity)
+		protoEntityMeta = toProtoEntityMetaFromECSTask(entity)
+	default:
+		return nil, fmt.Errorf("unsupported kind: %s", entity.GetID().Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.WorkloadmetaEnrichRequest{
+		EntityId:   protoEntityID,
+		EntityMeta: protoEntityMeta,
+	}, nil
+}
+
 func protoContainerFromWorkloadmetaContainer(container *workloadmeta.Container) (*pb.Container, error) {
 	var pbContainerPorts []*pb.ContainerPort
 	for _, port := range container.Ports {
@@ -474,6 +474,40 @@ func TestProtobufFilterFromWorkloadmetaFilter(t *testing.T) {
 	assert.Equal(t, pb.WorkloadmetaEventType_EVENT_TYPE_SET, protoFilter.EventType)
 }
 
+func TestProtobufEnrichRequestFromWorkloadmetaEntity(t *testing.T) {
+	pod := &workloadmeta.KubernetesPod{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindKubernetesPod,
+			ID:   "123",
+		},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name:      "test-pod",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "redis"},
+		},
+		IP: "10.0.0.1",
+	}
+
+	request, err := ProtobufEnrichRequestFromWorkloadmetaEntity(pod)
+	require.NoError(t, err)
+	assert.Equal(t, &pb.WorkloadmetaEnrichRequest{
+		EntityId: &pb.WorkloadmetaEntityId{
+			Kind: pb.WorkloadmetaKind_KUBERNETES_POD,
+			Id:   "123",
+		},
+		EntityMeta: &pb.EntityMeta{
+			Name:      "test-pod",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "redis"},
+		},
+	}, request)
+
+	_, err = ProtobufEnrichRequestFromWorkloadmetaEntity(&workloadmeta.ContainerImageMetadata{
+		EntityID: workloadmeta.EntityID{Kind: workloadmeta.KindContainerImageMetadata, ID: "sha256:abc"},
+	})
+	assert.Error(t, err)
+}
+
 func TestWorkloadmetaFilterFromProtoFilter(t *testing.T) {
 	protoFilter := pb.WorkloadmetaFilter{
 		Kinds: []pb.WorkloadmetaKind{
@@ -6,6 +6,7 @@
 package listeners
 
 import (
+	"errors"
 	"expvar"
 	"fmt"
 	"net"
@@ -13,10 +14,13 @@ import (
 	"sync"
 	"time"
 
+	"go.uber.org/atomic"
+
 	"github.com/DataDog/datadog-agent/comp/dogstatsd/packets"
 	replay "github.com/DataDog/datadog-agent/comp/dogstatsd/replay/def"
 	"github.com/DataDog/datadog-agent/pkg/config/model"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/status/health"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
@@ -27,6 +31,10 @@ var (
 	udpBytes               = expvar.Int{}
 )
 
+// maxConsecutiveUDPReadErrors is the number of consecutive errors reading packets after which the listener is
+// considered unhealthy
+const maxConsecutiveUDPReadErrors = 100
+
 // RandomPortName is the value for dogstatsd_port setting that indicates that the server should allocate a random unique port.
 const RandomPortName = "__random__" // this would be zero if zero wasn't used already to disable udp support.
 
@@ -54,6 +62,14 @@ type UDPListener struct {
 	trafficCapture  replay.Component // Currently ignored
 	listenWg        sync.WaitGroup
 	telemetryStore  *TelemetryStore
+
+	rcvbuf     int
+	connLock   sync.RWMutex // protects conn, which is replaced when the listener is restarted
+	health     *health.Handle
+	listening  *atomic.Bool  // false once the intake loop returned
+	readErrors *atomic.Int64 // number of consecutive errors reading packets
+	stateLock  sync.Mutex    // prevents restarting the listener while it is stopped
+	stopped    bool
 }
 
 // NewUDPListener returns an idle UDP Statsd listener
@@ -77,15 +93,10 @@ func NewUDPListener(packetOut chan packets.Packets, sharedPacketPoolManager *pac
 	if err != nil {
 		return nil, fmt.Errorf("could not resolve udp addr: %s", err)
 	}
-	conn, err := net.ListenUDP("udp", addr)
+	rcvbuf := cfg.GetInt("dogstatsd_so_rcvbuf")
+	conn, err := listenUDP(addr, rcvbuf)
 	if err != nil {
-		return nil, fmt.Errorf("can't listen: %s", err)
-	}
-
-	if rcvbuf := cfg.GetInt("dogstatsd_so_rcvbuf"); rcvbuf != 0 {
-		if err := conn.SetReadBuffer(rcvbuf); err != nil {
-			return nil, fmt.Errorf("could not set socket rcvbuf: %s", err)
-		}
+		return nil, err
 	}
 
 	bufferSize := cfg.GetInt("dogstatsd_buffer_size")
@@ -103,22 +114,59 @@ func NewUDPListener(packetOut chan packets.Packets, sharedPacketPoolManager *pac
 		buffer:          buffer,
 		trafficCapture:  capture,
 		telemetryStore:  telemetryStore,
+		rcvbuf:          rcvbuf,
+		listening:       atomic.NewBool(false),
+		readErrors:      atomic.NewInt64(0),
 	}
 	log.Debugf("dogstatsd-udp: %s successfully initialized", conn.LocalAddr())
 	return listener, nil
 }
 
+func listenUDP(addr *net.UDPAddr, rcvbuf int) (*net.UDPConn, error) {
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("can't listen: %s", err)
+	}
+
+	if rcvbuf != 0 {
+		if err := conn.SetReadBuffer(rcvbuf); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("could not set socket rcvbuf: %s", err)
+		}
+	}
+	return conn, nil
+}
+
 // LocalAddr returns the local network address of the listener.
 func (l *UDPListener) LocalAddr() string {
+	l.connLock.RLock()
+	defer l.connLock.RUnlock()
+
 	return l.conn.LocalAddr().String()
 }
 
 // Listen runs the intake loop. Should be called in its own goroutine
 func (l *UDPListener) Listen() {
+	l.health = health.RegisterLiveness("dogstatsd-udp", health.WithProbe(l.probe), health.WithRestart(l.restart))
+
+	// The intake loop blocks on the socket while there is no traffic, so it can't read the health channel itself:
+	// its liveness is checked by the probe instead. The channel is closed when the listener deregisters.
+	go func(healthChan <-chan time.Time) {
+		for range healthChan {
+			// nothing
+		}
+	}(l.health.C)
+
+	l.start()
+}
+
+func (l *UDPListener) start() {
+	l.listening.Store(true)
 	l.listenWg.Add(1)
 
 	go func() {
 		defer l.listenWg.Done()
+		defer l.listening.Store(false)
 		l.listen()
 	}()
 }
@@ -140,8 +188,10 @@ func (l *UDPListener) listen() {
 			log.Errorf("dogstatsd-udp: error reading packet: %v", err)
 			udpPacketReadingErrors.Add(1)
 			l.telemetryStore.tlmUDPPackets.Inc("error")
+			l.readErrors.Inc()
 		} else {
 			l.telemetryStore.tlmUDPPackets.Inc("ok")
+			l.readErrors.Store(0)
 
 			udpBytes.Add(int64(n))
 			l.telemetryStore.tlmUDPPacketsBytes.Add(float64(n))
@@ -155,8 +205,59 @@ func (l *UDPListener) listen() {
 	}
 }
 
+// probe is the liveness probe of the listener, it fails when the intake loop stopped or can't read packets anymore
+func (l *UDPListener) probe() error {
+	if !l.listening.Load() {
+		return errors.New("the intake loop is not running")
+	}
+	if errs := l.readErrors.Load(); errs >= maxConsecutiveUDPReadErrors {
+		return fmt.Errorf("%d consecutive errors reading packets", errs)
+	}
+	return nil
+}
+
+// restart closes the UDP connection and listens again on the same address, keeping the packets buffers
+func (l *UDPListener) restart() error {
+	l.stateLock.Lock()
+	defer l.stateLock.Unlock()
+
+	if l.stopped {
+		return errors.New("the listener is stopped")
+	}
+
+	// listen again on the same port, even if it was allocated randomly
+	addr, err := net.ResolveUDPAddr("udp", l.LocalAddr())
+	if err != nil {
+		return fmt.Errorf("could not resolve udp addr: %s", err)
+	}
+
+	l.conn.Close()
+	l.listenWg.Wait()
+
+	conn, err := listenUDP(addr, l.rcvbuf)
+	if err != nil {
+		return err
+	}
+
+	l.connLock.Lock()
+	l.conn = conn
+	l.connLock.Unlock()
+
+	l.readErrors.Store(0)
+	l.start()
+	log.Infof("dogstatsd-udp: listener restarted on %s", conn.LocalAddr())
+	return nil
+}
+
 // Stop closes the UDP connection and stops listening
 func (l *UDPListener) Stop() {
+	l.stateLock.Lock()
+	defer l.stateLock.Unlock()
+
+	l.stopped = true
+	if l.health != nil {
+		l.health.Deregister() //nolint:errcheck
+	}
 	l.packetAssembler.Close()
 	l.packetsBuffer.Close()
 	l.conn.Close()
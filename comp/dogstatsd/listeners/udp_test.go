@@ -156,6 +156,59 @@ func TestUDPReceive(t *testing.T) {
 	}
 }
 
+func TestUDPListenerRestart(t *testing.T) {
+	packetChannel := make(chan packets.Packets)
+	deps := fulfillDepsWithConfig(t, map[string]interface{}{"dogstatsd_port": "__random__"})
+	telemetryStore := NewTelemetryStore(nil, deps.Telemetry)
+	packetsTelemetryStore := packets.NewTelemetryStore(nil, deps.Telemetry)
+	s, err := NewUDPListener(packetChannel, newPacketPoolManagerUDP(deps.Config, packetsTelemetryStore), deps.Config, nil, telemetryStore, packetsTelemetryStore)
+	require.NoError(t, err)
+	s.Listen()
+	defer s.Stop()
+
+	addr := s.LocalAddr()
+	assert.NoError(t, s.probe())
+
+	// the intake loop stops when the socket is closed under it
+	s.conn.Close()
+	require.Eventually(t, func() bool { return s.probe() != nil }, 2*time.Second, 10*time.Millisecond)
+	assert.EqualError(t, s.probe(), "the intake loop is not running")
+
+	// the restart listens again on the same port
+	require.NoError(t, s.restart())
+	assert.NoError(t, s.probe())
+	assert.Equal(t, addr, s.LocalAddr())
+
+	conn, err := net.Dial("udp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("daemon:666|g"))
+	require.NoError(t, err)
+
+	select {
+	case pkts := <-packetChannel:
+		assert.Equal(t, []byte("daemon:666|g"), pkts[0].Contents)
+	case <-time.After(2 * time.Second):
+		assert.FailNow(t, "Timeout on receive channel")
+	}
+}
+
+func TestUDPListenerProbeReadErrors(t *testing.T) {
+	deps := fulfillDepsWithConfig(t, map[string]interface{}{"dogstatsd_port": "__random__"})
+	telemetryStore := NewTelemetryStore(nil, deps.Telemetry)
+	packetsTelemetryStore := packets.NewTelemetryStore(nil, deps.Telemetry)
+	s, err := NewUDPListener(nil, newPacketPoolManagerUDP(deps.Config, packetsTelemetryStore), deps.Config, nil, telemetryStore, packetsTelemetryStore)
+	require.NoError(t, err)
+
+	s.listening.Store(true)
+	s.readErrors.Store(maxConsecutiveUDPReadErrors)
+	assert.EqualError(t, s.probe(), "100 consecutive errors reading packets")
+
+	// a stopped listener isn't restarted
+	s.Stop()
+	assert.Error(t, s.restart())
+}
+
 // Reproducer for https://github.com/DataDog/datadog-agent/issues/6803
 func TestNewUDPListenerWhenBusyWithSoRcvBufSet(t *testing.T) {
 	port, err := getAvailableUDPPort()
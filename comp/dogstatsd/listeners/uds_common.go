@@ -6,7 +6,6 @@
 package listeners
 
 import (
-	"encoding/binary"
 	"errors"
 	"expvar"
 	"fmt"
@@ -57,7 +56,10 @@ type UDSListener struct {
 	trafficCapture          replay.Component
 	pidMap                  pidmap.Component
 	OriginDetection         bool
-	config                  model.Reader
+	// peerOriginDetection enables the origin detection based on the
+	// credentials of the peer of the connected sockets
+	peerOriginDetection bool
+	config              model.Reader
 
 	wmeta option.Option[workloadmeta.Component]
 
@@ -69,6 +71,10 @@ type UDSListener struct {
 	packetBufferFlushTimeout time.Duration
 	telemetryWithListenerID  bool
 
+	// streamV2Enabled enables the version 2 of the stream protocol, with acknowledgements and backpressure
+	streamV2Enabled    bool
+	streamV2WindowSize uint32
+
 	listenWg *sync.WaitGroup
 
 	// telemetry
@@ -152,12 +158,15 @@ func NewUDSListener(packetOut chan packets.Packets, sharedPacketPoolManager *pac
 		sharedPacketPoolManager:      sharedPacketPoolManager,
 		trafficCapture:               capture,
 		pidMap:                       pidMap,
+		peerOriginDetection:          transport == "unix" && cfg.GetBool("dogstatsd_origin_detection"),
 		dogstatsdMemBasedRateLimiter: cfg.GetBool("dogstatsd_mem_based_rate_limiter.enabled"),
 		config:                       cfg,
 		transport:                    transport,
 		packetBufferSize:             uint(cfg.GetInt("dogstatsd_packet_buffer_size")),
 		packetBufferFlushTimeout:     cfg.GetDuration("dogstatsd_packet_buffer_flush_timeout"),
 		telemetryWithListenerID:      cfg.GetBool("dogstatsd_telemetry_enabled_listener_id"),
+		streamV2Enabled:              cfg.GetBool("dogstatsd_stream_v2_enabled"),
+		streamV2WindowSize:           uint32(cfg.GetInt("dogstatsd_stream_v2_window_size")),
 		listenWg:                     &sync.WaitGroup{},
 		wmeta:                        wmeta,
 		telemetryStore:               telemetryStore,
@@ -209,6 +218,17 @@ func (l *UDSListener) handleConnection(conn netUnixConn, closeFunc CloseFunction
 	var t2 time.Time
 	log.Debugf("dogstatsd-uds: starting to handle %s", conn.LocalAddr())
 
+	// The credentials of the peer of a connected socket identify the origin of
+	// its packets when they don't carry credentials in their ancillary data.
+	var peerPID int32
+	if l.peerOriginDetection {
+		var err error
+		peerPID, err = getUDSPeerPID(conn)
+		if err != nil {
+			log.Debugf("dogstatsd-uds: unable to read the credentials of the peer: %v", err)
+		}
+	}
+
 	var rateLimiter *ratelimit.MemBasedRateLimiter
 	if l.dogstatsdMemBasedRateLimiter {
 		var err error
@@ -227,6 +247,9 @@ func (l *UDSListener) handleConnection(conn netUnixConn, closeFunc CloseFunction
 		}
 	}
 
+	stream := &udsStreamState{v2Enabled: l.streamV2Enabled}
+	stream.window = func() uint32 { return l.streamWindow(stream.maxFrameSize) }
+
 	for {
 		var n int
 		var oobn int
@@ -240,7 +263,7 @@ func (l *UDSListener) handleConnection(conn netUnixConn, closeFunc CloseFunction
 		udsPackets.Add(1)
 
 		var capBuff *replay.CaptureBuffer
-		if l.trafficCapture != nil && l.trafficCapture.IsOngoing() {
+		if l.trafficCapture != nil && l.trafficCapture.IsRecording() {
 			capBuff = new(replay.CaptureBuffer)
 			capBuff.Pb.Ancillary = nil
 			capBuff.Pb.Payload = nil
@@ -269,14 +292,16 @@ func (l *UDSListener) handleConnection(conn netUnixConn, closeFunc CloseFunction
 		var maxPacketLength uint32
 		if l.transport == "unix" {
 			// Read the expected packet length (in stream mode)
-			b := []byte{0, 0, 0, 0}
-			_, err = io.ReadFull(conn, b)
-			expectedPacketLength := binary.LittleEndian.Uint32(b)
+			stream.maxFrameSize = uint32(len(packet.Buffer))
+			expectedPacketLength, err = stream.readFrameHeader(conn)
 
 			switch {
 			case err == io.EOF, errors.Is(err, io.ErrUnexpectedEOF):
 				log.Debugf("dogstatsd-uds: %s connection closed", l.transport)
 				return nil
+			case err != nil:
+				log.Debugf("dogstatsd-uds: error reading frame header, dropping connection: %v", err)
+				return nil
 			}
 			if expectedPacketLength > uint32(len(packet.Buffer)) {
 				log.Info("dogstatsd-uds: packet length too large, dropping connection")
@@ -288,12 +313,15 @@ func (l *UDSListener) handleConnection(conn netUnixConn, closeFunc CloseFunction
 		}
 
 		for err == nil {
+			var read, oobRead int
 			if oob != nil {
-				n, oobn, _, _, err = conn.ReadMsgUnix(packet.Buffer[n:maxPacketLength], oobS[oobn:])
+				read, oobRead, _, _, err = conn.ReadMsgUnix(packet.Buffer[n:maxPacketLength], oobS[oobn:])
 			} else {
-				n, _, err = conn.ReadFromUnix(packet.Buffer[n:maxPacketLength])
+				read, _, err = conn.ReadFromUnix(packet.Buffer[n:maxPacketLength])
 			}
-			if n == 0 && oobn == 0 && l.transport == "unix" {
+			n += read
+			oobn += oobRead
+			if read == 0 && oobRead == 0 && l.transport == "unix" {
 				log.Debugf("dogstatsd-uds: %s connection closed", l.transport)
 				return nil
 			}
@@ -313,9 +341,18 @@ func (l *UDSListener) handleConnection(conn netUnixConn, closeFunc CloseFunction
 
 		t1 = time.Now()
 
-		if oob != nil {
-			// Extract container id from credentials
-			pid, container, taggingErr := processUDSOrigin(oobS[:oobn], l.wmeta, l.pidMap)
+		if oob != nil || peerPID != 0 {
+			var pid int
+			var container string
+			var taggingErr error
+			if oob != nil {
+				// Extract container id from credentials
+				pid, container, taggingErr = processUDSOrigin(oobS[:oobn], l.wmeta, l.pidMap)
+			}
+			if (oob == nil || taggingErr != nil) && peerPID != 0 {
+				pid = int(peerPID)
+				container, taggingErr = processUDSPeerOrigin(peerPID, l.wmeta, l.pidMap)
+			}
 			if taggingErr != nil {
 				log.Warnf("dogstatsd-uds: error processing origin, data will not be tagged : %v", taggingErr)
 				udsOriginDetectionErrors.Add(1)
@@ -328,15 +365,14 @@ func (l *UDSListener) handleConnection(conn netUnixConn, closeFunc CloseFunction
 				}
 			}
 			if capBuff != nil {
-				capBuff.Oob = oob
 				capBuff.Pid = int32(pid)
 				capBuff.Pb.Pid = int32(pid)
-				capBuff.Pb.AncillarySize = int32(oobn)
-				capBuff.Pb.Ancillary = oobS[:oobn]
+				if oob != nil {
+					capBuff.Oob = oob
+					capBuff.Pb.AncillarySize = int32(oobn)
+					capBuff.Pb.Ancillary = oobS[:oobn]
+				}
 			}
-
-			// Return the buffer back to the pool for reuse
-			l.oobPoolManager.Put(oob)
 		}
 
 		if capBuff != nil {
@@ -348,6 +384,12 @@ func (l *UDSListener) handleConnection(conn netUnixConn, closeFunc CloseFunction
 			l.trafficCapture.Enqueue(capBuff)
 		}
 
+		if oob != nil {
+			// Return the buffer back to the pool for reuse, once the ancillary
+			// data was copied by the capture
+			l.oobPoolManager.Put(oob)
+		}
+
 		if err != nil {
 			// connection has been closed
 			if strings.HasSuffix(err.Error(), " use of closed network connection") {
@@ -369,6 +411,13 @@ func (l *UDSListener) handleConnection(conn netUnixConn, closeFunc CloseFunction
 
 		// packetsBuffer handles the forwarding of the packets to the dogstatsd server intake channel
 		packetsBuffer.Append(packet)
+
+		// the acknowledgement is only sent once the packet was handed over, so that a full intake queue slows down
+		// the clients of the version 2 of the stream protocol
+		if err = stream.ack(conn); err != nil {
+			log.Debugf("dogstatsd-uds: %v, dropping connection", err)
+			return nil
+		}
 	}
 }
 
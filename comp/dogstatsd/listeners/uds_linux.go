@@ -90,6 +90,36 @@ func processUDSOrigin(ancillary []byte, wmeta option.Option[workloadmeta.Compone
 	return int(pid), entity, nil
 }
 
+// getUDSPeerPID returns the PID of the process which connected the socket,
+// read from its SO_PEERCRED credentials. It's only available on connected
+// sockets, and is 0 when the process belongs to another PID namespace.
+func getUDSPeerPID(conn netUnixConn) (int32, error) {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	var cred *unix.Ucred
+	var credErr error
+	err = rawConn.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if credErr != nil {
+		return 0, credErr
+	}
+
+	return cred.Pid, nil
+}
+
+// processUDSPeerOrigin returns a string identifying the source of the packets
+// sent by the peer of a connected socket, see getUDSPeerPID.
+func processUDSPeerOrigin(pid int32, wmeta option.Option[workloadmeta.Component], state pidmap.Component) (string, error) {
+	return getEntityForPID(pid, false, wmeta, state)
+}
+
 // getEntityForPID returns the container entity name and caches the value for future lookups
 // As the result is cached and the lookup is really fast (parsing local files), it can be
 // called from the intake goroutine.
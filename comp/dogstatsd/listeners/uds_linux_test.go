@@ -13,6 +13,8 @@
 package listeners
 
 import (
+	"net"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -54,3 +56,24 @@ func TestUDSPassCred(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, enabled, 1)
 }
+
+func TestUDSPeerPID(t *testing.T) {
+	dir := t.TempDir()
+	socketPath := filepath.Join(dir, "dsd.socket")
+
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: socketPath, Net: "unix"})
+	require.NoError(t, err)
+	defer l.Close()
+
+	client, err := net.Dial("unix", socketPath)
+	require.NoError(t, err)
+	defer client.Close()
+
+	conn, err := l.AcceptUnix()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	pid, err := getUDSPeerPID(conn)
+	require.NoError(t, err)
+	assert.Equal(t, int32(os.Getpid()), pid)
+}
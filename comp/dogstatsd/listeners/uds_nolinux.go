@@ -30,6 +30,16 @@ func enableUDSPassCred(_ syscall.RawConn) error {
 	return ErrLinuxOnly
 }
 
+// getUDSPeerPID returns a "not implemented" error on non-linux hosts
+func getUDSPeerPID(_ netUnixConn) (int32, error) {
+	return 0, ErrLinuxOnly
+}
+
+// processUDSPeerOrigin returns a "not implemented" error on non-linux hosts
+func processUDSPeerOrigin(_ int32, _ option.Option[workloadmeta.Component], _ pidmap.Component) (string, error) {
+	return packets.NoOrigin, ErrLinuxOnly
+}
+
 // processUDSOrigin returns a "not implemented" error on non-linux hosts
 //
 //nolint:revive // TODO(AML) Fix revive linter
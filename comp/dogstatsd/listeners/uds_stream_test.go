@@ -11,10 +11,13 @@ package listeners
 
 import (
 	"encoding/binary"
+	"io"
+	"net"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/DataDog/datadog-agent/comp/core/config"
 	"github.com/DataDog/datadog-agent/comp/core/telemetry"
@@ -86,3 +89,90 @@ func TestUDSStreamReceive(t *testing.T) {
 		assert.FailNow(t, "Timeout on receive channel")
 	}
 }
+
+func TestUDSStreamV2(t *testing.T) {
+	socketPath := testSocketPath(t)
+
+	mockConfig := map[string]interface{}{}
+	mockConfig[socketPathConfKey("unix")] = socketPath
+	mockConfig["dogstatsd_origin_detection"] = false
+	mockConfig["dogstatsd_packet_buffer_size"] = 1
+	mockConfig["dogstatsd_stream_v2_window_size"] = 64 * 1024
+
+	var contents = []byte("daemon:666|g|#sometag1:somevalue1")
+
+	packetsChannel := make(chan packets.Packets, 4)
+
+	deps := fulfillDepsWithConfig(t, mockConfig)
+	telemetryStore := NewTelemetryStore(nil, deps.Telemetry)
+	packetsTelemetryStore := packets.NewTelemetryStore(nil, deps.Telemetry)
+	s, err := udsStreamListenerFactory(packetsChannel, newPacketPoolManagerUDS(deps.Config, packetsTelemetryStore), deps.Config, deps.PidMap, telemetryStore, packetsTelemetryStore, deps.Telemetry)
+	require.NoError(t, err)
+	s.Listen()
+	defer s.Stop()
+
+	conn, err := net.Dial("unix", socketPath)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetDeadline(time.Now().Add(2*time.Second)))
+
+	readUint32s := func(count int) []uint32 {
+		b := make([]byte, 4*count)
+		_, err := io.ReadFull(conn, b)
+		require.NoError(t, err)
+		values := make([]uint32, count)
+		for i := range values {
+			values[i] = binary.LittleEndian.Uint32(b[4*i:])
+		}
+		return values
+	}
+
+	// handshake
+	require.NoError(t, binary.Write(conn, binary.LittleEndian, udsStreamV2Magic))
+	assert.Equal(t, []uint32{udsStreamV2Magic, 8192, 64 * 1024}, readUint32s(3))
+
+	// the frame is acknowledged once handed over, the window shrinks as the queue fills up
+	require.NoError(t, binary.Write(conn, binary.LittleEndian, []uint32{uint32(len(contents)), 7}))
+	_, err = conn.Write(contents)
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{7, 48 * 1024}, readUint32s(2))
+
+	select {
+	case pkts := <-packetsChannel:
+		require.Len(t, pkts, 1)
+		assert.Equal(t, contents, pkts[0].Contents)
+	case <-time.After(2 * time.Second):
+		assert.FailNow(t, "Timeout on receive channel")
+	}
+
+	// an empty frame probes the window
+	require.NoError(t, binary.Write(conn, binary.LittleEndian, []uint32{0, 8}))
+	assert.Equal(t, []uint32{8, 64 * 1024}, readUint32s(2))
+}
+
+func TestUDSStreamV2Disabled(t *testing.T) {
+	socketPath := testSocketPath(t)
+
+	mockConfig := map[string]interface{}{}
+	mockConfig[socketPathConfKey("unix")] = socketPath
+	mockConfig["dogstatsd_origin_detection"] = false
+	mockConfig["dogstatsd_stream_v2_enabled"] = false
+
+	deps := fulfillDepsWithConfig(t, mockConfig)
+	telemetryStore := NewTelemetryStore(nil, deps.Telemetry)
+	packetsTelemetryStore := packets.NewTelemetryStore(nil, deps.Telemetry)
+	s, err := udsStreamListenerFactory(make(chan packets.Packets, 4), newPacketPoolManagerUDS(deps.Config, packetsTelemetryStore), deps.Config, deps.PidMap, telemetryStore, packetsTelemetryStore, deps.Telemetry)
+	require.NoError(t, err)
+	s.Listen()
+	defer s.Stop()
+
+	conn, err := net.Dial("unix", socketPath)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetDeadline(time.Now().Add(2*time.Second)))
+
+	// the handshake is handled as a too large version 1 frame, the connection is dropped
+	require.NoError(t, binary.Write(conn, binary.LittleEndian, udsStreamV2Magic))
+	_, err = conn.Read(make([]byte, 4))
+	assert.ErrorIs(t, err, io.EOF)
+}
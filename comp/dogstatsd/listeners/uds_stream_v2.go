@@ -0,0 +1,141 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package listeners
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Version 2 of the UDS stream protocol
+//
+// A client negotiates the version 2 by sending udsStreamV2Magic as the first 4 bytes of the connection. It can't be
+// mistaken for the length of a version 1 frame, as it exceeds any packet buffer size: agents which don't support the
+// version 2 drop the connection, and the client can then reconnect using the version 1.
+//
+// All the integers are unsigned 32 bits little endian integers.
+//
+//	handshake (client): magic
+//	handshake (server): magic | max frame size | window
+//	frame (client):     length | batch id | payload
+//	ack (server):       batch id | window
+//
+// The server acknowledges each frame once its payload has been handed over to the intake queue, and advertises the
+// window: the count of payload bytes the client may send past the acknowledged frame without waiting for another
+// acknowledgement. The window shrinks as the intake queue fills up, so that the clients hold their metrics instead of
+// filling the kernel buffer of the socket. A frame with an empty payload is acknowledged right away and can be used to
+// probe the window.
+const (
+	// udsStreamV2Magic is the value sent by the clients to negotiate the version 2 of the stream protocol
+	udsStreamV2Magic uint32 = 0xfffffe02
+	// udsStreamV2WriteTimeout is the timeout of the writes of handshakes and acknowledgements
+	udsStreamV2WriteTimeout = 5 * time.Second
+)
+
+// udsStreamState holds the protocol state of a stream connection
+type udsStreamState struct {
+	// v2Enabled is true if the listener accepts the version 2 of the protocol
+	v2Enabled bool
+	// v2 is true once the version 2 of the protocol has been negotiated
+	v2 bool
+	// negotiated is true once the first frame header has been read
+	negotiated bool
+	// maxFrameSize is the maximum payload size of a frame
+	maxFrameSize uint32
+	// window returns the window to advertise to the client
+	window func() uint32
+	// batchID is the id of the frame being read
+	batchID uint32
+	buf     [12]byte
+}
+
+// readFrameHeader reads the header of the next frame and returns the length of its payload. The version 2 handshake
+// and the empty frames are handled transparently.
+func (s *udsStreamState) readFrameHeader(conn netUnixConn) (uint32, error) {
+	for {
+		length, err := s.readUint32(conn)
+		if err != nil {
+			return 0, err
+		}
+
+		if !s.negotiated {
+			s.negotiated = true
+			if length == udsStreamV2Magic && s.v2Enabled {
+				if err := s.handshake(conn); err != nil {
+					return 0, err
+				}
+				continue
+			}
+		}
+
+		if !s.v2 {
+			return length, nil
+		}
+
+		if s.batchID, err = s.readUint32(conn); err != nil {
+			return 0, err
+		}
+		if length != 0 {
+			return length, nil
+		}
+
+		// empty frames are only used to probe the window
+		if err := s.ack(conn); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (s *udsStreamState) readUint32(conn netUnixConn) (uint32, error) {
+	if _, err := io.ReadFull(conn, s.buf[:4]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(s.buf[:4]), nil
+}
+
+// handshake acknowledges the negotiation of the version 2 of the protocol
+func (s *udsStreamState) handshake(conn netUnixConn) error {
+	s.v2 = true
+	binary.LittleEndian.PutUint32(s.buf[0:4], udsStreamV2Magic)
+	binary.LittleEndian.PutUint32(s.buf[4:8], s.maxFrameSize)
+	binary.LittleEndian.PutUint32(s.buf[8:12], s.window())
+	if err := s.write(conn, s.buf[:12]); err != nil {
+		return fmt.Errorf("can't write handshake: %w", err)
+	}
+	return nil
+}
+
+// ack acknowledges the last frame read, if the version 2 of the protocol was negotiated
+func (s *udsStreamState) ack(conn netUnixConn) error {
+	if !s.v2 {
+		return nil
+	}
+	binary.LittleEndian.PutUint32(s.buf[0:4], s.batchID)
+	binary.LittleEndian.PutUint32(s.buf[4:8], s.window())
+	if err := s.write(conn, s.buf[:8]); err != nil {
+		return fmt.Errorf("can't acknowledge batch %d: %w", s.batchID, err)
+	}
+	return nil
+}
+
+func (s *udsStreamState) write(conn netUnixConn, b []byte) error {
+	// a client that doesn't read its acknowledgements must not block the listener forever
+	_ = conn.SetWriteDeadline(time.Now().Add(udsStreamV2WriteTimeout))
+	_, err := conn.Write(b)
+	return err
+}
+
+// streamWindow returns the window advertised to the clients of the version 2 of the stream protocol. It shrinks as
+// the intake queue fills up, but always allows a frame of the maximum size so that the clients can make progress.
+func (l *UDSListener) streamWindow(maxFrameSize uint32) uint32 {
+	window := l.streamV2WindowSize
+	if queueSize := cap(l.packetOut); queueSize > 0 {
+		window = uint32(uint64(window) * uint64(queueSize-len(l.packetOut)) / uint64(queueSize))
+	}
+	return max(window, maxFrameSize)
+}
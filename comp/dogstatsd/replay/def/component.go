@@ -20,6 +20,9 @@ type Component interface {
 	// IsOngoing returns whether a capture is ongoing for this TrafficCapture instance.
 	IsOngoing() bool
 
+	// IsRecording returns whether the traffic must be enqueued, because a capture is ongoing or the ring buffer is enabled.
+	IsRecording() bool
+
 	// StartCapture starts a TrafficCapture and returns an error in the event of an issue.
 	StartCapture(p string, d time.Duration, compressed bool) (string, error)
 
@@ -34,9 +37,12 @@ type Component interface {
 	// RegisterOOBPoolManager registers the OOB shared pool manager with the TrafficCapture.f
 	RegisterOOBPoolManager(p *packets.PoolManager[[]byte]) error
 
-	// Enqueue enqueues a capture buffer so it's written to file.
+	// Enqueue enqueues a capture buffer so it's written to file, and copies it to the ring buffer if it's enabled.
 	Enqueue(msg *CaptureBuffer) bool
 
+	// DumpRingBuffer writes the traffic kept in the ring buffer to a capture file and returns its path.
+	DumpRingBuffer(p string, compressed bool) (string, error)
+
 	// GetStartUpError returns an error if TrafficCapture failed to start up
 	GetStartUpError() error
 }
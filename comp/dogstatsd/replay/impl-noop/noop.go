@@ -30,6 +30,11 @@ func (tc *noopTrafficCapture) IsOngoing() bool {
 	return tc.isRunning
 }
 
+// IsRecording returns whether isRunning is true
+func (tc *noopTrafficCapture) IsRecording() bool {
+	return tc.IsOngoing()
+}
+
 // StartCapture sets isRunning to true
 func (tc *noopTrafficCapture) StartCapture(_ string, _ time.Duration, _ bool) (string, error) {
 	tc.Lock()
@@ -61,6 +66,11 @@ func (tc *noopTrafficCapture) Enqueue(_ *replaydef.CaptureBuffer) bool {
 	return true
 }
 
+// DumpRingBuffer does nothing
+func (tc *noopTrafficCapture) DumpRingBuffer(_ string, _ bool) (string, error) {
+	return "", nil
+}
+
 // GetStartUpError returns nil
 func (tc *noopTrafficCapture) GetStartUpError() error {
 	return nil
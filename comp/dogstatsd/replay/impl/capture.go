@@ -8,19 +8,24 @@ package replayimpl
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"path"
 	"sync"
 	"time"
 
 	"github.com/spf13/afero"
 
+	api "github.com/DataDog/datadog-agent/comp/api/api/def"
 	configComponent "github.com/DataDog/datadog-agent/comp/core/config"
 	tagger "github.com/DataDog/datadog-agent/comp/core/tagger/def"
 	compdef "github.com/DataDog/datadog-agent/comp/def"
 	"github.com/DataDog/datadog-agent/comp/dogstatsd/packets"
 	replay "github.com/DataDog/datadog-agent/comp/dogstatsd/replay/def"
 	"github.com/DataDog/datadog-agent/pkg/config/model"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
 )
 
 //nolint:revive // TODO(AML) Fix revive linter
@@ -30,9 +35,16 @@ type Requires struct {
 	Tagger tagger.Component
 }
 
+// Provides defines the output of the replay component
+type Provides struct {
+	Comp               replay.Component
+	RingBufferEndpoint api.AgentEndpointProvider
+}
+
 // trafficCapture allows capturing traffic from our listeners and writing it to file
 type trafficCapture struct {
 	writer       *TrafficCaptureWriter
+	ring         *ringBuffer
 	config       model.Reader
 	tagger       tagger.Component
 	startUpError error
@@ -41,7 +53,7 @@ type trafficCapture struct {
 }
 
 //nolint:revive // TODO(AML) Fix revive linter
-func NewTrafficCapture(deps Requires) replay.Component {
+func NewTrafficCapture(deps Requires) Provides {
 	tc := &trafficCapture{
 		config: deps.Config,
 		tagger: deps.Tagger,
@@ -50,7 +62,10 @@ func NewTrafficCapture(deps Requires) replay.Component {
 		OnStart: tc.configure,
 	})
 
-	return tc
+	return Provides{
+		Comp:               tc,
+		RingBufferEndpoint: api.NewAgentEndpointProvider(tc.dumpRingBufferHandler, "/dogstatsd-capture/ring-buffer", "POST"),
+	}
 }
 
 func (tc *trafficCapture) configure(_ context.Context) error {
@@ -60,6 +75,10 @@ func (tc *trafficCapture) configure(_ context.Context) error {
 	}
 	tc.writer = writer
 
+	if d := tc.config.GetDuration("dogstatsd_capture_ring_buffer_duration"); d > 0 {
+		tc.ring = newRingBuffer(d, tc.config.GetInt("dogstatsd_capture_ring_buffer_max_size"))
+	}
+
 	return nil
 }
 
@@ -75,6 +94,12 @@ func (tc *trafficCapture) IsOngoing() bool {
 	return tc.writer.IsOngoing()
 }
 
+// IsRecording returns whether the traffic must be enqueued, because a capture is
+// ongoing or the ring buffer is enabled.
+func (tc *trafficCapture) IsRecording() bool {
+	return tc.ring != nil || tc.IsOngoing()
+}
+
 // StartCapture starts a TrafficCapture and returns an error in the event of an issue.
 func (tc *trafficCapture) StartCapture(p string, d time.Duration, compressed bool) (string, error) {
 	if tc.IsOngoing() {
@@ -116,13 +141,49 @@ func (tc *trafficCapture) RegisterOOBPoolManager(p *packets.PoolManager[[]byte])
 	return tc.writer.RegisterOOBPoolManager(p)
 }
 
-// Enqueue enqueues a capture buffer so it's written to file.
+// Enqueue enqueues a capture buffer so it's written to file, and copies it to
+// the ring buffer if it's enabled.
 func (tc *trafficCapture) Enqueue(msg *replay.CaptureBuffer) bool {
+	if tc.ring != nil {
+		tc.ring.add(msg)
+	}
+
 	tc.RLock()
 	defer tc.RUnlock()
 	return tc.writer.Enqueue(msg)
 }
 
+// DumpRingBuffer writes the traffic kept in the ring buffer to a capture file
+// and returns its path.
+func (tc *trafficCapture) DumpRingBuffer(p string, compressed bool) (string, error) {
+	if tc.ring == nil {
+		return "", errors.New("the dogstatsd capture ring buffer is disabled, set dogstatsd_capture_ring_buffer_duration to enable it")
+	}
+
+	target, path, err := OpenFile(afero.NewOsFs(), p, tc.defaultlocation())
+	if err != nil {
+		return "", err
+	}
+
+	if err := NewTrafficCaptureWriter(0, tc.tagger).Dump(target, tc.ring.snapshot(), compressed); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+func (tc *trafficCapture) dumpRingBufferHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	capturePath, err := tc.DumpRingBuffer(query.Get("path"), query.Get("compressed") == "true")
+	if err != nil {
+		httputils.SetJSONError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"path": capturePath})
+}
+
 func (tc *trafficCapture) defaultlocation() string {
 	location := tc.config.GetString("dogstatsd_capture_path")
 	if location == "" {
@@ -0,0 +1,91 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package replayimpl
+
+import (
+	"bytes"
+	"slices"
+	"sync"
+	"time"
+
+	replay "github.com/DataDog/datadog-agent/comp/dogstatsd/replay/def"
+)
+
+// ringBuffer keeps the dogstatsd traffic received during the last duration in
+// memory, so that it can be dumped to a capture file after the fact.
+//
+// The messages are copied when they are added, so that the packets can go back
+// to their pool as soon as they are processed.
+type ringBuffer struct {
+	duration time.Duration
+	maxSize  int
+	now      func() time.Time
+
+	mu   sync.Mutex
+	msgs []*replay.CaptureBuffer
+	size int
+}
+
+func newRingBuffer(duration time.Duration, maxSize int) *ringBuffer {
+	return &ringBuffer{
+		duration: duration,
+		maxSize:  maxSize,
+		now:      time.Now,
+	}
+}
+
+// add copies msg in the buffer, and evicts the oldest messages.
+func (rb *ringBuffer) add(msg *replay.CaptureBuffer) {
+	msgCopy := &replay.CaptureBuffer{
+		Pb: replay.UnixDogstatsdMsg{
+			Timestamp:     msg.Pb.Timestamp,
+			PayloadSize:   msg.Pb.PayloadSize,
+			Payload:       bytes.Clone(msg.Pb.Payload),
+			Pid:           msg.Pb.Pid,
+			AncillarySize: msg.Pb.AncillarySize,
+			Ancillary:     bytes.Clone(msg.Pb.Ancillary),
+		},
+		Pid:         msg.Pid,
+		ContainerID: msg.ContainerID,
+	}
+
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	rb.msgs = append(rb.msgs, msgCopy)
+	rb.size += msgSize(msgCopy)
+	rb.evict()
+}
+
+// evict drops the messages received before the duration, and the oldest
+// messages while the buffer exceeds its max size.
+func (rb *ringBuffer) evict() {
+	oldest := rb.now().Add(-rb.duration).UnixNano()
+
+	i := 0
+	for ; i < len(rb.msgs); i++ {
+		msg := rb.msgs[i]
+		if msg.Pb.Timestamp >= oldest && (rb.maxSize <= 0 || rb.size <= rb.maxSize) {
+			break
+		}
+		rb.size -= msgSize(msg)
+		rb.msgs[i] = nil
+	}
+	rb.msgs = rb.msgs[i:]
+}
+
+// snapshot returns the messages received during the last duration, from the oldest to the newest.
+func (rb *ringBuffer) snapshot() []*replay.CaptureBuffer {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	rb.evict()
+	return slices.Clone(rb.msgs)
+}
+
+func msgSize(msg *replay.CaptureBuffer) int {
+	return len(msg.Pb.Payload) + len(msg.Pb.Ancillary)
+}
@@ -0,0 +1,112 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package replayimpl
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/tagger/mock"
+	replay "github.com/DataDog/datadog-agent/comp/dogstatsd/replay/def"
+)
+
+func newCaptureBuffer(payload string, ts time.Time) *replay.CaptureBuffer {
+	msg := new(replay.CaptureBuffer)
+	msg.Pb.Timestamp = ts.UnixNano()
+	msg.Pb.PayloadSize = int32(len(payload))
+	msg.Pb.Payload = []byte(payload)
+	return msg
+}
+
+func payloads(msgs []*replay.CaptureBuffer) []string {
+	var res []string
+	for _, msg := range msgs {
+		res = append(res, string(msg.Pb.Payload))
+	}
+	return res
+}
+
+func TestRingBufferDuration(t *testing.T) {
+	rb := newRingBuffer(10*time.Second, 0)
+	now := time.Now()
+	rb.now = func() time.Time { return now }
+
+	rb.add(newCaptureBuffer("a:1|c", now))
+	now = now.Add(5 * time.Second)
+	rb.add(newCaptureBuffer("b:1|c", now))
+	assert.Equal(t, []string{"a:1|c", "b:1|c"}, payloads(rb.snapshot()))
+
+	now = now.Add(6 * time.Second)
+	assert.Equal(t, []string{"b:1|c"}, payloads(rb.snapshot()))
+
+	now = now.Add(time.Minute)
+	assert.Empty(t, rb.snapshot())
+	assert.Equal(t, 0, rb.size)
+}
+
+func TestRingBufferMaxSize(t *testing.T) {
+	rb := newRingBuffer(time.Minute, 10)
+	now := time.Now()
+
+	rb.add(newCaptureBuffer("a:1|c", now))
+	rb.add(newCaptureBuffer("b:1|c", now))
+	assert.Equal(t, []string{"a:1|c", "b:1|c"}, payloads(rb.snapshot()))
+
+	rb.add(newCaptureBuffer("c:1|c", now))
+	assert.Equal(t, []string{"b:1|c", "c:1|c"}, payloads(rb.snapshot()))
+	assert.Equal(t, 10, rb.size)
+}
+
+func TestRingBufferCopiesMessages(t *testing.T) {
+	rb := newRingBuffer(time.Minute, 0)
+
+	msg := newCaptureBuffer("a:1|c", time.Now())
+	rb.add(msg)
+	// the packet buffers are reused once they go back to their pool
+	copy(msg.Pb.Payload, "b:2|g")
+
+	assert.Equal(t, []string{"a:1|c"}, payloads(rb.snapshot()))
+}
+
+func TestRingBufferDump(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("foo/bar", 0777))
+	file, path, err := OpenFile(fs, "foo/bar", "")
+	require.NoError(t, err)
+
+	rb := newRingBuffer(time.Minute, 0)
+	for _, payload := range []string{"a:1|c", "b:1|c", "c:1|c"} {
+		rb.add(newCaptureBuffer(payload, time.Now()))
+	}
+
+	writer := NewTrafficCaptureWriter(0, mock.SetupFakeTagger(t))
+	require.NoError(t, writer.Dump(file, rb.snapshot(), false))
+
+	buf, err := afero.ReadFile(fs, path)
+	require.NoError(t, err)
+
+	reader := &TrafficCaptureReader{
+		Contents: buf,
+		Version:  int(datadogFileVersion),
+		offset:   uint32(len(datadogHeader)),
+	}
+
+	var read []string
+	for {
+		msg, err := reader.ReadNext()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		read = append(read, string(msg.Payload))
+	}
+	assert.Equal(t, []string{"a:1|c", "b:1|c", "c:1|c"}, read)
+}
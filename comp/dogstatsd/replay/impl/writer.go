@@ -159,13 +159,7 @@ func (tc *TrafficCaptureWriter) Capture(target io.WriteCloser, d time.Duration,
 	defer target.Close()
 	log.Debug("Starting capture...")
 
-	if compressed {
-		tc.zWriter = zstd.NewWriter(target)
-		tc.writer = bufio.NewWriter(tc.zWriter)
-	} else {
-		tc.zWriter = nil
-		tc.writer = bufio.NewWriter(target)
-	}
+	tc.openWriter(target, compressed)
 
 	tc.Lock()
 	if tc.ongoing {
@@ -234,6 +228,48 @@ func (tc *TrafficCaptureWriter) Capture(target io.WriteCloser, d time.Duration,
 	tc.ongoing = false
 }
 
+// Dump writes the given messages to the target, in the same format as a
+// capture. It must not be called on a TrafficCaptureWriter used for a capture.
+func (tc *TrafficCaptureWriter) Dump(target io.WriteCloser, msgs []*replay.CaptureBuffer, compressed bool) error {
+	defer target.Close()
+
+	tc.openWriter(target, compressed)
+
+	if err := tc.writeHeader(); err != nil {
+		return fmt.Errorf("unable to write the capture file header: %w", err)
+	}
+
+	for _, msg := range msgs {
+		if err := tc.processMessage(msg); err != nil {
+			return fmt.Errorf("unable to write a captured message: %w", err)
+		}
+	}
+
+	if _, err := tc.writeState(); err != nil {
+		return fmt.Errorf("unable to write the capture state: %w", err)
+	}
+
+	if err := tc.writer.Flush(); err != nil {
+		return err
+	}
+
+	if tc.zWriter != nil {
+		return tc.zWriter.Close()
+	}
+	return nil
+}
+
+// openWriter sets up the writers of the capture file.
+func (tc *TrafficCaptureWriter) openWriter(target io.Writer, compressed bool) {
+	if compressed {
+		tc.zWriter = zstd.NewWriter(target)
+		tc.writer = bufio.NewWriter(tc.zWriter)
+	} else {
+		tc.zWriter = nil
+		tc.writer = bufio.NewWriter(target)
+	}
+}
+
 // StopCapture stops the ongoing capture if in process.
 func (tc *TrafficCaptureWriter) StopCapture() {
 	tc.Lock()
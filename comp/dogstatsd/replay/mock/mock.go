@@ -44,6 +44,11 @@ func (tc *mockTrafficCapture) IsOngoing() bool {
 	return tc.isRunning
 }
 
+// IsRecording returns whether a capture is ongoing on the mock
+func (tc *mockTrafficCapture) IsRecording() bool {
+	return tc.IsOngoing()
+}
+
 // StartCapture does nothign on the mock
 func (tc *mockTrafficCapture) StartCapture(_ string, _ time.Duration, _ bool) (string, error) {
 	tc.Lock()
@@ -75,6 +80,11 @@ func (tc *mockTrafficCapture) Enqueue(msg *replay.CaptureBuffer) bool {
 	return true
 }
 
+// DumpRingBuffer does nothing on the mock
+func (tc *mockTrafficCapture) DumpRingBuffer(_ string, _ bool) (string, error) {
+	return "", nil
+}
+
 //nolint:revive // TODO(AML) Fix revive linter
 func (tc *mockTrafficCapture) GetStartUpError() error {
 	return nil
@@ -0,0 +1,57 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package server
+
+import (
+	"encoding/json"
+
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+)
+
+// metricControlConfig is the content of a METRIC_CONTROL remote configuration
+type metricControlConfig struct {
+	BlockedMetrics struct {
+		ByName struct {
+			Metrics []string `json:"metrics"`
+		} `json:"by_name"`
+	} `json:"blocked_metrics"`
+}
+
+// onMetricControlUpdate merges the metric names blocked through remote config
+// with the statsd_metric_blocklist ones. The new blocklist applies to the
+// messages parsed after the update, without restarting the server.
+func (s *server) onMetricControlUpdate(updates map[string]state.RawConfig, applyStateCallback func(string, state.ApplyStatus)) {
+	// an empty update means that no configuration applies to this agent anymore
+	if len(updates) == 0 {
+		s.log.Info("Dogstatsd: no metric blocked through remote config, restoring the configured blocklist")
+		s.remoteMetricBlocklist.Store(nil)
+		return
+	}
+
+	metricNames := append([]string{}, s.metricBlocklistConfig...)
+	remoteCount := 0
+	for configPath, rawConfig := range updates {
+		var config metricControlConfig
+		if err := json.Unmarshal(rawConfig.Config, &config); err != nil {
+			s.log.Warnf("Dogstatsd: skipping invalid METRIC_CONTROL update %s: %v", configPath, err)
+			applyStateCallback(configPath, state.ApplyStatus{
+				State: state.ApplyStateError,
+				Error: "error unmarshalling payload",
+			})
+			continue
+		}
+
+		metricNames = append(metricNames, config.BlockedMetrics.ByName.Metrics...)
+		remoteCount += len(config.BlockedMetrics.ByName.Metrics)
+		applyStateCallback(configPath, state.ApplyStatus{
+			State: state.ApplyStateAcknowledged,
+		})
+	}
+
+	metricBlocklist := newBlocklist(metricNames, s.metricBlocklistMatchPrefix)
+	s.remoteMetricBlocklist.Store(&metricBlocklist)
+	s.log.Infof("Dogstatsd: %d metric names blocked through remote config", remoteCount)
+}
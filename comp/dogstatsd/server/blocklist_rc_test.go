@@ -0,0 +1,57 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build test
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/dogstatsd/listeners"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+)
+
+func TestMetricControlUpdate(t *testing.T) {
+	cfg := make(map[string]interface{})
+	cfg["dogstatsd_port"] = listeners.RandomPortName
+	cfg["statsd_metric_blocklist"] = []string{"local.blocked"}
+
+	deps := fulfillDepsWithConfigOverride(t, cfg)
+	s := deps.Server.(*server)
+	parser := newParser(deps.Config, s.sharedFloat64List, 1, deps.WMeta, s.stringInternerTelemetry)
+
+	parse := func(message string) []metrics.MetricSample {
+		samples, err := s.parseMetricMessage(nil, parser, []byte(message), "", 0, "", false)
+		require.NoError(t, err)
+		return samples
+	}
+
+	assert.Len(t, parse("local.blocked:1|g"), 0)
+	assert.Len(t, parse("remote.blocked:1|g"), 1)
+
+	applied := map[string]state.ApplyStatus{}
+	applyStateCallback := func(path string, status state.ApplyStatus) { applied[path] = status }
+
+	s.onMetricControlUpdate(map[string]state.RawConfig{
+		"datadog/2/METRIC_CONTROL/valid/config":   {Config: []byte(`{"blocked_metrics":{"by_name":{"metrics":["remote.blocked"]}}}`)},
+		"datadog/2/METRIC_CONTROL/invalid/config": {Config: []byte(`{`)},
+	}, applyStateCallback)
+
+	assert.Equal(t, state.ApplyStateAcknowledged, applied["datadog/2/METRIC_CONTROL/valid/config"].State)
+	assert.Equal(t, state.ApplyStateError, applied["datadog/2/METRIC_CONTROL/invalid/config"].State)
+	assert.Len(t, parse("local.blocked:1|g"), 0)
+	assert.Len(t, parse("remote.blocked:1|g"), 0)
+	assert.Len(t, parse("other:1|g"), 1)
+
+	// the configured blocklist is restored when no configuration applies anymore
+	s.onMetricControlUpdate(map[string]state.RawConfig{}, applyStateCallback)
+	assert.Len(t, parse("local.blocked:1|g"), 0)
+	assert.Len(t, parse("remote.blocked:1|g"), 1)
+}
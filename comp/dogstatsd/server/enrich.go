@@ -34,6 +34,9 @@ type enrichConfig struct {
 	defaultHostname           string
 	entityIDPrecedenceEnabled bool
 	serverlessMode            bool
+	// histogramToSketch matches the names of the histograms and timers aggregated into sketches, like
+	// distributions, instead of being aggregated into fixed percentiles
+	histogramToSketch nameMatcher
 }
 
 // extractTagsMetadata returns tags (client tags + host tag) and information needed to query tagger (origins, cardinality).
@@ -90,6 +93,8 @@ func enrichMetricType(dogstatsdMetricType metricType) metrics.MetricType {
 		return metrics.SetType
 	case timingType:
 		return metrics.HistogramType
+	case sketchType:
+		return metrics.DistributionType
 	}
 	return metrics.GaugeType
 }
@@ -131,6 +136,9 @@ func enrichMetricSample(dest []metrics.MetricSample, ddSample dogstatsdMetricSam
 	}
 
 	mtype := enrichMetricType(ddSample.metricType)
+	if mtype == metrics.HistogramType && conf.histogramToSketch.test(metricName) {
+		mtype = metrics.DistributionType
+	}
 
 	// if 'ddSample.values' contains values we're enriching a multi-value
 	// dogstatsd message and will create a MetricSample per value. If not
@@ -164,6 +172,7 @@ func enrichMetricSample(dest []metrics.MetricSample, ddSample dogstatsdMetricSam
 		Value:      ddSample.value,
 		SampleRate: ddSample.sampleRate,
 		RawValue:   ddSample.setValue,
+		Sketch:     ddSample.sketch,
 		Timestamp:  tsToFloatForSamples(ddSample.ts),
 		OriginInfo: extractedOrigin,
 		ListenerID: listenerID,
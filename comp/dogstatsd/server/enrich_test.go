@@ -262,6 +262,48 @@ func TestConvertParseSet(t *testing.T) {
 	assert.InEpsilon(t, 1.0, parsed.SampleRate, epsilon)
 }
 
+func TestConvertParseSketch(t *testing.T) {
+	conf := enrichConfig{
+		defaultHostname: "default-hostname",
+	}
+
+	parsed, err := parseAndEnrichSingleMetricMessage(t, []byte("daemon:"+encodeSketch(t, 1, 2, 3)+"|sk"), conf)
+
+	assert.NoError(t, err)
+
+	assert.Equal(t, "daemon", parsed.Name)
+	assert.Equal(t, metrics.DistributionType, parsed.Mtype)
+	require.NotNil(t, parsed.Sketch)
+	assert.Equal(t, int64(3), parsed.Sketch.Basic.Cnt)
+	assert.Equal(t, 0, len(parsed.Tags))
+	assert.Equal(t, "default-hostname", parsed.Host)
+	assert.InEpsilon(t, 1.0, parsed.SampleRate, epsilon)
+}
+
+func TestConvertParseHistogramToSketch(t *testing.T) {
+	conf := enrichConfig{
+		defaultHostname:   "default-hostname",
+		metricPrefix:      "app.",
+		histogramToSketch: newNameMatcher([]string{"app.request.", "app.db.query"}, true),
+	}
+
+	for _, tc := range []struct {
+		message  string
+		expected metrics.MetricType
+	}{
+		{"request.latency:12|ms", metrics.DistributionType},
+		{"request.size:1024|h", metrics.DistributionType},
+		{"db.query.duration:3|ms", metrics.DistributionType},
+		{"db.connections:3|h", metrics.HistogramType},
+		{"request.count:1|c", metrics.CounterType},
+		{"request.latency:12|d", metrics.DistributionType},
+	} {
+		parsed, err := parseAndEnrichSingleMetricMessage(t, []byte(tc.message), conf)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, parsed.Mtype, tc.message)
+	}
+}
+
 func TestConvertParseSetUnicode(t *testing.T) {
 	conf := enrichConfig{
 		defaultHostname: "default-hostname",
@@ -18,6 +18,9 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/containers/metrics/provider"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/option"
+
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/quantile"
+	"github.com/DataDog/sketches-go/ddsketch"
 )
 
 type messageType int
@@ -153,8 +156,15 @@ func (p *parser) parseMetricSample(message []byte) (dogstatsdMetricSample, error
 	var setValue []byte
 	var values []float64
 	var value float64
+	var rawSketch *ddsketch.DDSketch
 	if metricType == setType {
 		setValue = rawValue // special case for the set type, we obviously don't support multiple values for this type
+	} else if metricType == sketchType {
+		// the sketch is decoded here, but only converted once the sample rate is known
+		rawSketch, err = parseMetricSampleSketch(rawValue)
+		if err != nil {
+			return dogstatsdMetricSample{}, fmt.Errorf("could not parse dogstatsd sketch: %v", err)
+		}
 	} else {
 		// In case the list contains only one value, dogstatsd 1.0
 		// protocol, we directly parse it as a float64. This avoids
@@ -217,11 +227,20 @@ func (p *parser) parseMetricSample(message []byte) (dogstatsdMetricSample, error
 		}
 	}
 
+	var sketch *quantile.Sketch
+	if rawSketch != nil {
+		sketch, err = convertMetricSampleSketch(rawSketch, sampleRate)
+		if err != nil {
+			return dogstatsdMetricSample{}, fmt.Errorf("could not convert dogstatsd sketch: %v", err)
+		}
+	}
+
 	return dogstatsdMetricSample{
 		name:         p.interner.LoadOrStore(name),
 		value:        value,
 		values:       values,
 		setValue:     string(setValue),
+		sketch:       sketch,
 		metricType:   metricType,
 		sampleRate:   sampleRate,
 		tags:         tags,
@@ -7,9 +7,14 @@ package server
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
 	"github.com/DataDog/datadog-agent/comp/core/tagger/origindetection"
 	"time"
+
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/quantile"
+	"github.com/DataDog/sketches-go/ddsketch"
+	"github.com/DataDog/sketches-go/ddsketch/store"
 )
 
 type metricType int
@@ -21,6 +26,7 @@ const (
 	histogramType
 	setType
 	timingType
+	sketchType
 )
 
 var (
@@ -30,6 +36,7 @@ var (
 	distributionSymbol = []byte("d")
 	setSymbol          = []byte("s")
 	timingSymbol       = []byte("ms")
+	sketchSymbol       = []byte("sk")
 
 	tagsFieldPrefix       = []byte("#")
 	sampleRateFieldPrefix = []byte("@")
@@ -43,7 +50,9 @@ type dogstatsdMetricSample struct {
 	// use for multiple value messages
 	values []float64
 	// use to store set's values
-	setValue   string
+	setValue string
+	// use to store the values of pre-aggregated sketch messages
+	sketch     *quantile.Sketch
 	metricType metricType
 	sampleRate float64
 	tags       []string
@@ -93,6 +102,8 @@ func parseMetricSampleMetricType(rawMetricType []byte) (metricType, error) {
 		return setType, nil
 	case bytes.Equal(rawMetricType, timingSymbol):
 		return timingType, nil
+	case bytes.Equal(rawMetricType, sketchSymbol):
+		return sketchType, nil
 	}
 	return 0, fmt.Errorf("invalid metric type: %q", rawMetricType)
 }
@@ -100,3 +111,36 @@ func parseMetricSampleMetricType(rawMetricType []byte) (metricType, error) {
 func parseMetricSampleSampleRate(rawSampleRate []byte) (float64, error) {
 	return parseFloat64(rawSampleRate)
 }
+
+// parseMetricSampleSketch decodes the value of a sketch message: a DDSketch
+// serialized with its index mapping, in base64.
+func parseMetricSampleSketch(rawValue []byte) (*ddsketch.DDSketch, error) {
+	buf := make([]byte, base64.StdEncoding.DecodedLen(len(rawValue)))
+	n, err := base64.StdEncoding.Decode(buf, rawValue)
+	if err != nil {
+		return nil, err
+	}
+
+	sketch, err := ddsketch.DecodeDDSketch(buf[:n], store.DefaultProvider, nil)
+	if err != nil {
+		return nil, err
+	}
+	if sketch.IndexMapping == nil {
+		return nil, fmt.Errorf("missing index mapping")
+	}
+	if sketch.IsEmpty() {
+		return nil, fmt.Errorf("empty sketch")
+	}
+	return sketch, nil
+}
+
+// convertMetricSampleSketch converts the sketch of a message to the sketch
+// used by the aggregator, scaling its counts according to the sample rate.
+func convertMetricSampleSketch(sketch *ddsketch.DDSketch, sampleRate float64) (*quantile.Sketch, error) {
+	if sampleRate > 0 && sampleRate < 1 {
+		if err := sketch.Reweight(1 / sampleRate); err != nil {
+			return nil, err
+		}
+	}
+	return quantile.ConvertDDSketchIntoSketch(sketch)
+}
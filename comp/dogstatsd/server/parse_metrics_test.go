@@ -6,9 +6,11 @@
 package server
 
 import (
+	"encoding/base64"
 	"testing"
 	"time"
 
+	"github.com/DataDog/sketches-go/ddsketch"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/fx"
@@ -218,6 +220,69 @@ func TestParseDistributionMultiple(t *testing.T) {
 	assert.Zero(t, sample.ts)
 }
 
+func encodeSketch(t *testing.T, values ...float64) string {
+	sketch, err := ddsketch.NewDefaultDDSketch(0.01)
+	require.NoError(t, err)
+	for _, v := range values {
+		require.NoError(t, sketch.Add(v))
+	}
+
+	var buf []byte
+	sketch.Encode(&buf, false)
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+func TestParseSketch(t *testing.T) {
+	sample, err := parseMetricSample(t, make(map[string]any), []byte("daemon:"+encodeSketch(t, 1, 2, 3, 4)+"|sk|#sometag:value"))
+
+	assert.NoError(t, err)
+
+	assert.Equal(t, "daemon", sample.name)
+	assert.Equal(t, sketchType, sample.metricType)
+	require.NotNil(t, sample.sketch)
+	assert.Equal(t, int64(4), sample.sketch.Basic.Cnt)
+	assert.InEpsilon(t, 10.0, sample.sketch.Basic.Sum, 0.01)
+	assert.InEpsilon(t, 1.0, sample.sketch.Basic.Min, 0.01)
+	assert.InEpsilon(t, 4.0, sample.sketch.Basic.Max, 0.01)
+	require.Nil(t, sample.values)
+	assert.Equal(t, []string{"sometag:value"}, sample.tags)
+	assert.Zero(t, sample.ts)
+}
+
+func TestParseSketchWithSampleRate(t *testing.T) {
+	sample, err := parseMetricSample(t, make(map[string]any), []byte("daemon:"+encodeSketch(t, 1, 2)+"|sk|@0.5"))
+
+	assert.NoError(t, err)
+
+	require.NotNil(t, sample.sketch)
+	assert.Equal(t, int64(4), sample.sketch.Basic.Cnt)
+	assert.InEpsilon(t, 6.0, sample.sketch.Basic.Sum, 0.01)
+	assert.InEpsilon(t, 0.5, sample.sampleRate, epsilon)
+}
+
+func TestParseSketchError(t *testing.T) {
+	// invalid base64
+	_, err := parseMetricSample(t, make(map[string]any), []byte("daemon:not_a_sketch|sk"))
+	assert.Error(t, err)
+
+	// invalid sketch
+	_, err = parseMetricSample(t, make(map[string]any), []byte("daemon:"+base64.StdEncoding.EncodeToString([]byte("sketch"))+"|sk"))
+	assert.Error(t, err)
+
+	// empty sketch
+	_, err = parseMetricSample(t, make(map[string]any), []byte("daemon:"+encodeSketch(t)+"|sk"))
+	assert.Error(t, err)
+
+	// missing index mapping
+	sketch, err := ddsketch.NewDefaultDDSketch(0.01)
+	require.NoError(t, err)
+	require.NoError(t, sketch.Add(1))
+	var buf []byte
+	sketch.Encode(&buf, true)
+	_, err = parseMetricSample(t, make(map[string]any), []byte("daemon:"+base64.StdEncoding.EncodeToString(buf)+"|sk"))
+	assert.Error(t, err)
+}
+
 func TestParseSetUnicode(t *testing.T) {
 	sample, err := parseMetricSample(t, make(map[string]any), []byte("daemon:♬†øU†øU¥ºuT0♪|s"))
 
@@ -0,0 +1,207 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package server
+
+import (
+	"hash/maphash"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/telemetry"
+	"github.com/DataDog/datadog-agent/comp/dogstatsd/packets"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+// originQuotaContextsWindow is the window over which the contexts of an origin
+// are counted. The origins not seen during a whole window are forgotten.
+const originQuotaContextsWindow = time.Minute
+
+// originQuotas limits the packets and the contexts ingested from each origin, a
+// container or a process, so that a single runaway client can't flood the
+// aggregator.
+//
+// The packets are limited per second. The contexts, a metric name along with
+// its tags, are limited per originQuotaContextsWindow: once an origin reached its
+// limit, the samples of its known contexts are still accepted but the new ones
+// are dropped until the end of the window.
+type originQuotas struct {
+	packetsPerSecond int
+	maxContexts      int
+	now              func() time.Time
+	seed             maphash.Seed
+
+	mu          sync.Mutex
+	origins     map[string]*originQuota
+	windowStart time.Time
+
+	tlmDroppedPackets  telemetry.SimpleCounter
+	tlmDroppedContexts telemetry.SimpleCounter
+	tlmLimitedOrigins  telemetry.SimpleGauge
+}
+
+type originQuota struct {
+	mu sync.Mutex
+
+	second  int64
+	packets int
+
+	windowStart time.Time
+	contexts    map[uint64]struct{}
+	limited     bool
+}
+
+// newOriginQuotas returns the quotas of the origins, or nil when they are disabled.
+func newOriginQuotas(packetsPerSecond, maxContexts int, telemetrycomp telemetry.Component) *originQuotas {
+	if packetsPerSecond <= 0 && maxContexts <= 0 {
+		return nil
+	}
+
+	dropped := telemetrycomp.NewCounter("dogstatsd", "origin_quota_dropped",
+		[]string{"reason"}, "Count of packets and samples dropped because their origin exceeded its quota")
+
+	return &originQuotas{
+		packetsPerSecond:   packetsPerSecond,
+		maxContexts:        maxContexts,
+		now:                time.Now,
+		seed:               maphash.MakeSeed(),
+		origins:            make(map[string]*originQuota),
+		tlmDroppedPackets:  dropped.WithValues("packets"),
+		tlmDroppedContexts: dropped.WithValues("contexts"),
+		tlmLimitedOrigins: telemetrycomp.NewSimpleGauge("dogstatsd", "origin_quota_limited_origins",
+			"Number of origins which exceeded their quota during the last window"),
+	}
+}
+
+// originKey returns the key identifying the origin of a packet, or an empty
+// string when the origin is unknown.
+func originKey(packet *packets.Packet) string {
+	if packet.Origin != packets.NoOrigin {
+		return packet.Origin
+	}
+	if packet.ProcessID != 0 {
+		return "pid:" + strconv.FormatUint(uint64(packet.ProcessID), 10)
+	}
+	return ""
+}
+
+// get returns the quota of the given origin, creating it if needed.
+func (q *originQuotas) get(origin string, now time.Time) *originQuota {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if now.Sub(q.windowStart) >= originQuotaContextsWindow {
+		q.expire(now)
+	}
+
+	quota, ok := q.origins[origin]
+	if !ok {
+		quota = &originQuota{
+			windowStart: now,
+			contexts:    make(map[uint64]struct{}),
+		}
+		q.origins[origin] = quota
+	}
+	return quota
+}
+
+// expire forgets the origins not seen during the last window, and starts a new one.
+func (q *originQuotas) expire(now time.Time) {
+	limited := 0
+	for origin, quota := range q.origins {
+		quota.mu.Lock()
+		if now.Sub(quota.windowStart) >= 2*originQuotaContextsWindow {
+			delete(q.origins, origin)
+		} else if quota.limited {
+			limited++
+		}
+		quota.mu.Unlock()
+	}
+	q.windowStart = now
+	q.tlmLimitedOrigins.Set(float64(limited))
+}
+
+// allowPacket returns false if the origin exceeded its packets quota. The second
+// value is true when it's the first time the origin exceeds a quota in the window.
+func (q *originQuotas) allowPacket(origin string) (bool, bool) {
+	if q.packetsPerSecond <= 0 {
+		return true, false
+	}
+
+	now := q.now()
+	quota := q.get(origin, now)
+
+	quota.mu.Lock()
+	defer quota.mu.Unlock()
+
+	quota.rotate(now)
+	if second := now.Unix(); second != quota.second {
+		quota.second = second
+		quota.packets = 0
+	}
+
+	if quota.packets >= q.packetsPerSecond {
+		q.tlmDroppedPackets.Inc()
+		return false, quota.limit()
+	}
+	quota.packets++
+	return true, false
+}
+
+// allowSample returns false if the context of the sample is new, and the
+// origin exceeded its contexts quota. The second value is true when it's the
+// first time the origin exceeds a quota in the window.
+func (q *originQuotas) allowSample(origin string, sample *metrics.MetricSample) (bool, bool) {
+	if q.maxContexts <= 0 {
+		return true, false
+	}
+
+	var h maphash.Hash
+	h.SetSeed(q.seed)
+	h.WriteString(sample.Name)
+	for _, tag := range sample.Tags {
+		h.WriteByte(',')
+		h.WriteString(tag)
+	}
+	key := h.Sum64()
+
+	now := q.now()
+	quota := q.get(origin, now)
+
+	quota.mu.Lock()
+	defer quota.mu.Unlock()
+
+	quota.rotate(now)
+	if _, ok := quota.contexts[key]; ok {
+		return true, false
+	}
+
+	if len(quota.contexts) >= q.maxContexts {
+		q.tlmDroppedContexts.Inc()
+		return false, quota.limit()
+	}
+	quota.contexts[key] = struct{}{}
+	return true, false
+}
+
+// rotate starts a new contexts window if the current one is over.
+func (o *originQuota) rotate(now time.Time) {
+	if now.Sub(o.windowStart) < originQuotaContextsWindow {
+		return
+	}
+	o.windowStart = now
+	o.contexts = make(map[uint64]struct{})
+	o.limited = false
+}
+
+// limit marks the origin as limited, and returns true if it wasn't already in the window.
+func (o *originQuota) limit() bool {
+	if o.limited {
+		return false
+	}
+	o.limited = true
+	return true
+}
@@ -0,0 +1,107 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build test
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/telemetry"
+	"github.com/DataDog/datadog-agent/comp/core/telemetry/telemetryimpl"
+	"github.com/DataDog/datadog-agent/comp/dogstatsd/listeners"
+	"github.com/DataDog/datadog-agent/comp/dogstatsd/packets"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func TestOriginKey(t *testing.T) {
+	assert.Equal(t, "container_id://abc", originKey(&packets.Packet{Origin: "container_id://abc", ProcessID: 42}))
+	assert.Equal(t, "pid:42", originKey(&packets.Packet{ProcessID: 42}))
+	assert.Equal(t, "", originKey(&packets.Packet{}))
+}
+
+func TestOriginQuotasDisabled(t *testing.T) {
+	telemetryComp := fxutil.Test[telemetry.Component](t, telemetryimpl.MockModule())
+	assert.Nil(t, newOriginQuotas(0, 0, telemetryComp))
+}
+
+func TestOriginQuotas(t *testing.T) {
+	telemetryComp := fxutil.Test[telemetry.Component](t, telemetryimpl.MockModule())
+	q := newOriginQuotas(2, 2, telemetryComp)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+
+	allowPacket := func(origin string) bool {
+		allowed, _ := q.allowPacket(origin)
+		return allowed
+	}
+	allowSample := func(origin, name string) bool {
+		allowed, _ := q.allowSample(origin, &metrics.MetricSample{Name: name, Tags: []string{"env:prod"}})
+		return allowed
+	}
+
+	// packets
+	assert.True(t, allowPacket("a"))
+	assert.True(t, allowPacket("a"))
+	allowed, limited := q.allowPacket("a")
+	assert.False(t, allowed)
+	assert.True(t, limited)
+	allowed, limited = q.allowPacket("a")
+	assert.False(t, allowed)
+	assert.False(t, limited, "the origin is only reported once per window")
+	assert.True(t, allowPacket("b"))
+
+	now = now.Add(time.Second)
+	assert.True(t, allowPacket("a"))
+
+	// contexts
+	assert.True(t, allowSample("a", "metric.1"))
+	assert.True(t, allowSample("a", "metric.2"))
+	assert.False(t, allowSample("a", "metric.3"))
+	assert.True(t, allowSample("a", "metric.1"), "the known contexts are still accepted")
+	assert.True(t, allowSample("b", "metric.3"))
+
+	now = now.Add(originQuotaContextsWindow)
+	assert.True(t, allowSample("a", "metric.3"))
+
+	// the origins not seen anymore are forgotten
+	now = now.Add(2 * originQuotaContextsWindow)
+	q.get("c", now)
+	assert.Len(t, q.origins, 1)
+}
+
+func TestParsePacketsOriginQuotas(t *testing.T) {
+	cfg := make(map[string]interface{})
+	cfg["dogstatsd_port"] = listeners.RandomPortName
+	cfg["dogstatsd_origin_quota.packets_per_second"] = 2
+	cfg["dogstatsd_origin_quota.max_contexts"] = 2
+
+	deps := fulfillDepsWithConfigOverride(t, cfg)
+	s := deps.Server.(*server)
+	require.NotNil(t, s.originQuotas)
+	now := time.Now()
+	s.originQuotas.now = func() time.Time { return now }
+
+	parser := newParser(deps.Config, s.sharedFloat64List, 1, deps.WMeta, s.stringInternerTelemetry)
+	var b batcherMock
+
+	// the third context of the origin is dropped
+	s.parsePackets(&b, parser, genTestPackets([]byte("metric.1:1|g\nmetric.2:1|g\nmetric.3:1|g")), metrics.MetricSampleBatch{})
+	require.Len(t, b.samples, 2)
+	assert.Equal(t, "metric.1", b.samples[0].Name)
+	assert.Equal(t, "metric.2", b.samples[1].Name)
+
+	// the third packet of the origin is dropped
+	b.clear()
+	s.parsePackets(&b, parser, genTestPackets([]byte("metric.1:2|g"), []byte("metric.1:3|g")), metrics.MetricSampleBatch{})
+	require.Len(t, b.samples, 1)
+	assert.Equal(t, 2.0, b.samples[0].Value)
+}
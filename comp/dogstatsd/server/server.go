@@ -13,6 +13,7 @@ import (
 	"net"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/fx"
@@ -28,6 +29,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/dogstatsd/pidmap"
 	replay "github.com/DataDog/datadog-agent/comp/dogstatsd/replay/def"
 	serverdebug "github.com/DataDog/datadog-agent/comp/dogstatsd/serverDebug"
+	rctypes "github.com/DataDog/datadog-agent/comp/remote-config/rcclient/types"
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
 	"github.com/DataDog/datadog-agent/pkg/config/model"
 	"github.com/DataDog/datadog-agent/pkg/config/structure"
@@ -35,6 +37,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/metrics"
 	"github.com/DataDog/datadog-agent/pkg/metrics/event"
 	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
 	"github.com/DataDog/datadog-agent/pkg/status/health"
 	"github.com/DataDog/datadog-agent/pkg/util/hostname"
 	"github.com/DataDog/datadog-agent/pkg/util/option"
@@ -86,6 +89,7 @@ type provides struct {
 
 	Comp          Component
 	StatsEndpoint api.AgentEndpointProvider
+	RCListener    rctypes.ListenerProvider
 }
 
 // When the internal telemetry is enabled, used to tag the origin
@@ -158,6 +162,17 @@ type server struct {
 
 	enrichConfig enrichConfig
 
+	// metricBlocklistConfig and metricBlocklistMatchPrefix are the configured
+	// blocklist of metric names, merged with the one received through remote config
+	metricBlocklistConfig      []string
+	metricBlocklistMatchPrefix bool
+	// remoteMetricBlocklist replaces the blocklist of enrichConfig once an
+	// update is received through remote config
+	remoteMetricBlocklist atomic.Pointer[blocklist]
+
+	// originQuotas limits the ingestion of each origin, nil when disabled
+	originQuotas *originQuotas
+
 	wmeta option.Option[workloadmeta.Component]
 
 	// telemetry
@@ -185,16 +200,21 @@ func initTelemetry() {
 func newServer(deps dependencies) provides {
 	s := newServerCompat(deps.Config, deps.Log, deps.Replay, deps.Debug, deps.Params.Serverless, deps.Demultiplexer, deps.WMeta, deps.PidMap, deps.Telemetry)
 
+	var rcListener rctypes.ListenerProvider
 	if deps.Config.GetBool("use_dogstatsd") {
 		deps.Lc.Append(fx.Hook{
 			OnStart: s.startHook,
 			OnStop:  s.stop,
 		})
+		rcListener.ListenerProvider = rctypes.RCListener{
+			state.ProductMetricControl: s.onMetricControlUpdate,
+		}
 	}
 
 	return provides{
 		Comp:          s,
 		StatsEndpoint: api.NewAgentEndpointProvider(s.writeStats, "/dogstatsd-stats", "GET"),
+		RCListener:    rcListener,
 	}
 }
 
@@ -219,10 +239,10 @@ func newServerCompat(cfg model.Reader, log log.Component, capture replay.Compone
 	}
 
 	metricPrefixBlacklist := cfg.GetStringSlice("statsd_metric_namespace_blacklist")
-	metricBlocklist := newBlocklist(
-		cfg.GetStringSlice("statsd_metric_blocklist"),
-		cfg.GetBool("statsd_metric_blocklist_match_prefix"),
-	)
+	metricBlocklistConfig := cfg.GetStringSlice("statsd_metric_blocklist")
+	metricBlocklistMatchPrefix := cfg.GetBool("statsd_metric_blocklist_match_prefix")
+	metricBlocklist := newBlocklist(metricBlocklistConfig, metricBlocklistMatchPrefix)
+	histogramToSketch := newNameMatcher(cfg.GetStringSlice("dogstatsd_histogram_to_sketch_prefixes"), true)
 
 	defaultHostname, err := hostname.Get(context.TODO())
 	if err != nil {
@@ -296,10 +316,18 @@ func newServerCompat(cfg model.Reader, log log.Component, capture replay.Compone
 			metricPrefix:              metricPrefix,
 			metricPrefixBlacklist:     metricPrefixBlacklist,
 			metricBlocklist:           metricBlocklist,
+			histogramToSketch:         histogramToSketch,
 			entityIDPrecedenceEnabled: entityIDPrecedenceEnabled,
 			defaultHostname:           defaultHostname,
 			serverlessMode:            serverless,
 		},
+		metricBlocklistConfig:      metricBlocklistConfig,
+		metricBlocklistMatchPrefix: metricBlocklistMatchPrefix,
+		originQuotas: newOriginQuotas(
+			cfg.GetInt("dogstatsd_origin_quota.packets_per_second"),
+			cfg.GetInt("dogstatsd_origin_quota.max_contexts"),
+			telemetrycomp,
+		),
 		wmeta:                   wmeta,
 		telemetry:               telemetrycomp,
 		tlmProcessed:            dogstatsdTelemetryCount,
@@ -628,6 +656,22 @@ func (s *server) errLog(format string, params ...interface{}) {
 func (s *server) parsePackets(batcher dogstatsdBatcher, parser *parser, packets []*packets.Packet, samples metrics.MetricSampleBatch) metrics.MetricSampleBatch {
 	for _, packet := range packets {
 		s.log.Tracef("Dogstatsd receive: %q", packet.Contents)
+
+		var origin string
+		if s.originQuotas != nil {
+			origin = originKey(packet)
+		}
+		if origin != "" {
+			allowed, limited := s.originQuotas.allowPacket(origin)
+			if limited {
+				s.log.Warnf("Dogstatsd: origin %q exceeded its quota of %d packets per second, its packets are dropped", origin, s.originQuotas.packetsPerSecond)
+			}
+			if !allowed {
+				s.sharedPacketPoolManager.Put(packet)
+				continue
+			}
+		}
+
 		for {
 			message := nextMessage(&packet.Contents, s.eolEnabled(packet.Source))
 			if message == nil {
@@ -668,6 +712,16 @@ func (s *server) parsePackets(batcher dogstatsdBatcher, parser *parser, packets
 				}
 
 				for idx := range samples {
+					if origin != "" {
+						allowed, limited := s.originQuotas.allowSample(origin, &samples[idx])
+						if limited {
+							s.log.Warnf("Dogstatsd: origin %q exceeded its quota of %d contexts, its new contexts are dropped", origin, s.originQuotas.maxContexts)
+						}
+						if !allowed {
+							continue
+						}
+					}
+
 					s.Debug.StoreMetricStats(samples[idx])
 
 					if samples[idx].Timestamp > 0.0 {
@@ -759,7 +813,11 @@ func (s *server) parseMetricMessage(metricSamples []metrics.MetricSample, parser
 		}
 	}
 
-	metricSamples = enrichMetricSample(metricSamples, sample, origin, processID, listenerID, s.enrichConfig)
+	conf := s.enrichConfig
+	if remoteMetricBlocklist := s.remoteMetricBlocklist.Load(); remoteMetricBlocklist != nil {
+		conf.metricBlocklist = *remoteMetricBlocklist
+	}
+	metricSamples = enrichMetricSample(metricSamples, sample, origin, processID, listenerID, conf)
 
 	if len(sample.values) > 0 {
 		s.sharedFloat64List.put(sample.values)
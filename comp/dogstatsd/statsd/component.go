@@ -26,4 +26,22 @@ type Component interface {
 
 	// CreateForHostPort returns a pre-configured statsd client that defaults to `host:port` if no env var is set
 	CreateForHostPort(host string, port int, options ...ddgostatsd.Option) (ddgostatsd.ClientInterface, error)
+
+	// CreateForComponent returns the client used by the component `name` to send its internal metrics.
+	// Its mode and sample rate are configured centrally in the `internal_statsd` section, buffered
+	// clients default to `addr` if no env var is set.
+	CreateForComponent(name string, addr string, options ...ddgostatsd.Option) (ddgostatsd.ClientInterface, error)
 }
+
+// Mode defines how the client returned by CreateForComponent sends the metrics
+type Mode string
+
+const (
+	// ModeBuffered sends the metrics to the dogstatsd server through a buffered client
+	ModeBuffered Mode = "buffered"
+	// ModeDirect sends the metrics to the aggregator of the agent, bypassing the dogstatsd server.
+	// It falls back to ModeBuffered in processes not running an aggregator.
+	ModeDirect Mode = "direct"
+	// ModeNoop drops the metrics
+	ModeNoop Mode = "noop"
+)
@@ -0,0 +1,170 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package statsd
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	ddgostatsd "github.com/DataDog/datadog-go/v5/statsd"
+
+	"github.com/DataDog/datadog-agent/pkg/config/structure"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// componentConfig is the configuration of the internal statsd client of a component
+type componentConfig struct {
+	Mode Mode `mapstructure:"mode"`
+	// SampleRate is applied on top of the sample rate of each metric, values
+	// outside of ]0, 1] are ignored.
+	SampleRate float64 `mapstructure:"sample_rate"`
+}
+
+// CreateForComponent returns the client used by the component `name` to send its internal metrics
+func (hs *service) CreateForComponent(name string, addr string, options ...ddgostatsd.Option) (ddgostatsd.ClientInterface, error) {
+	cfg := hs.componentConfig(name)
+
+	var client ddgostatsd.ClientInterface
+	// the direct client doesn't sample the metrics according to their rate
+	var sample bool
+	switch cfg.Mode {
+	case ModeNoop:
+		return &ddgostatsd.NoOpClient{}, nil
+	case ModeDirect:
+		if hs.direct != nil {
+			client, sample = hs.direct, true
+			break
+		}
+		log.Debugf("No aggregator to send the internal metrics of %s to, falling back to the %s mode", name, ModeBuffered)
+		fallthrough
+	case ModeBuffered:
+		var err error
+		client, err = createClient(addr, options...)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown internal statsd mode %q for %s", cfg.Mode, name)
+	}
+
+	if cfg.SampleRate < 1 {
+		return &sampledClient{
+			ClientInterface: client,
+			rate:            cfg.SampleRate,
+			sample:          sample,
+		}, nil
+	}
+	return client, nil
+}
+
+// componentConfig returns the configuration of the internal statsd client of
+// the component `name`: the global one, overridden by the one of the component.
+func (hs *service) componentConfig(name string) componentConfig {
+	cfg := componentConfig{
+		Mode:       ModeBuffered,
+		SampleRate: 1,
+	}
+	if hs.config == nil {
+		return cfg
+	}
+
+	if mode := hs.config.GetString("internal_statsd.mode"); mode != "" {
+		cfg.Mode = Mode(mode)
+	}
+	if rate := hs.config.GetFloat64("internal_statsd.sample_rate"); rate > 0 && rate <= 1 {
+		cfg.SampleRate = rate
+	}
+
+	overrides := map[string]componentConfig{}
+	if err := structure.UnmarshalKey(hs.config, "internal_statsd.components", &overrides); err != nil {
+		log.Warnf("Invalid internal statsd component overrides, ignoring them: %v", err)
+		return cfg
+	}
+	if override, found := overrides[name]; found {
+		if override.Mode != "" {
+			cfg.Mode = override.Mode
+		}
+		if override.SampleRate > 0 && override.SampleRate <= 1 {
+			cfg.SampleRate = override.SampleRate
+		}
+	}
+	return cfg
+}
+
+// sampledClient applies the sample rate of a component to the metrics it sends
+type sampledClient struct {
+	ddgostatsd.ClientInterface
+	rate float64
+	// sample is set when the underlying client doesn't sample the metrics
+	// according to their rate, and the metrics must be dropped here instead.
+	sample bool
+}
+
+// keep returns whether a metric sent at `rate` must be kept, and the rate to forward it with
+func (c *sampledClient) keep(rate float64) (bool, float64) {
+	rate *= c.rate
+	if c.sample && rand.Float64() > rate {
+		return false, rate
+	}
+	return true, rate
+}
+
+func (c *sampledClient) Gauge(name string, value float64, tags []string, rate float64) error {
+	if keep, rate := c.keep(rate); keep {
+		return c.ClientInterface.Gauge(name, value, tags, rate)
+	}
+	return nil
+}
+
+func (c *sampledClient) Count(name string, value int64, tags []string, rate float64) error {
+	if keep, rate := c.keep(rate); keep {
+		return c.ClientInterface.Count(name, value, tags, rate)
+	}
+	return nil
+}
+
+func (c *sampledClient) Histogram(name string, value float64, tags []string, rate float64) error {
+	if keep, rate := c.keep(rate); keep {
+		return c.ClientInterface.Histogram(name, value, tags, rate)
+	}
+	return nil
+}
+
+func (c *sampledClient) Distribution(name string, value float64, tags []string, rate float64) error {
+	if keep, rate := c.keep(rate); keep {
+		return c.ClientInterface.Distribution(name, value, tags, rate)
+	}
+	return nil
+}
+
+func (c *sampledClient) Decr(name string, tags []string, rate float64) error {
+	return c.Count(name, -1, tags, rate)
+}
+
+func (c *sampledClient) Incr(name string, tags []string, rate float64) error {
+	return c.Count(name, 1, tags, rate)
+}
+
+func (c *sampledClient) Set(name string, value string, tags []string, rate float64) error {
+	if keep, rate := c.keep(rate); keep {
+		return c.ClientInterface.Set(name, value, tags, rate)
+	}
+	return nil
+}
+
+func (c *sampledClient) Timing(name string, value time.Duration, tags []string, rate float64) error {
+	return c.TimeInMilliseconds(name, value.Seconds()*1000, tags, rate)
+}
+
+func (c *sampledClient) TimeInMilliseconds(name string, value float64, tags []string, rate float64) error {
+	if keep, rate := c.keep(rate); keep {
+		return c.ClientInterface.TimeInMilliseconds(name, value, tags, rate)
+	}
+	return nil
+}
+
+var _ ddgostatsd.ClientInterface = (*sampledClient)(nil)
@@ -16,6 +16,7 @@ import (
 
 	ddgostatsd "github.com/DataDog/datadog-go/v5/statsd"
 
+	"github.com/DataDog/datadog-agent/comp/core/config"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
 )
 
@@ -25,10 +26,22 @@ func Module() fxutil.Module {
 		fx.Provide(newStatsdService))
 }
 
+type dependencies struct {
+	fx.In
+
+	Config config.Component `optional:"true"`
+	// Direct is a client sending the metrics straight to the aggregator, only
+	// provided by the processes running one.
+	Direct ddgostatsd.ClientInterface `name:"statsd_direct" optional:"true"`
+}
+
 type service struct {
 	sync.Mutex
 	// The default shared client.
 	client ddgostatsd.ClientInterface
+
+	config config.Component
+	direct ddgostatsd.ClientInterface
 }
 
 // Get returns a pre-configured and shared statsd client (requires STATSD_URL env var to be set)
@@ -95,6 +108,9 @@ func createClient(addr string, options ...ddgostatsd.Option) (ddgostatsd.ClientI
 	return ddgostatsd.New(addr, options...)
 }
 
-func newStatsdService() Component {
-	return &service{}
+func newStatsdService(deps dependencies) Component {
+	return &service{
+		config: deps.Config,
+		direct: deps.Direct,
+	}
 }
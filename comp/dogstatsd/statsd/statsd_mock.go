@@ -50,6 +50,11 @@ func (m *mockService) CreateForHostPort(_ string, _ int, _ ...ddgostatsd.Option)
 	return m.client, nil
 }
 
+// CreateForComponent returns the client used by the component `name` to send its internal metrics
+func (m *mockService) CreateForComponent(_ string, _ string, _ ...ddgostatsd.Option) (ddgostatsd.ClientInterface, error) {
+	return m.client, nil
+}
+
 var _ Mock = (*mockService)(nil)
 
 // MockClient is an alias for injecting a mock client.
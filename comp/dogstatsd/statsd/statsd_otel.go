@@ -42,4 +42,9 @@ func (m *otelcomponent) CreateForHostPort(_ string, _ int, _ ...ddgostatsd.Optio
 	return m.client, nil
 }
 
+// CreateForComponent returns the client used by the component `name` to send its internal metrics
+func (m *otelcomponent) CreateForComponent(_ string, _ string, _ ...ddgostatsd.Option) (ddgostatsd.ClientInterface, error) {
+	return m.client, nil
+}
+
 var _ Component = (*otelcomponent)(nil)
@@ -8,9 +8,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	ddgostatsd "github.com/DataDog/datadog-go/v5/statsd"
 
+	"github.com/DataDog/datadog-agent/comp/core/config"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
 )
 
@@ -31,3 +33,81 @@ func TestGet(t *testing.T) {
 	err = c.Close()
 	assert.NoError(t, err)
 }
+
+type recordingClient struct {
+	ddgostatsd.NoOpClient
+	rates []float64
+}
+
+func (c *recordingClient) Count(_ string, _ int64, _ []string, rate float64) error {
+	c.rates = append(c.rates, rate)
+	return nil
+}
+
+func TestCreateForComponent(t *testing.T) {
+	cfg := config.NewMockFromYAML(t, `
+internal_statsd:
+  sample_rate: 0.5
+  components:
+    disabled:
+      mode: noop
+    aggregated:
+      mode: direct
+      sample_rate: 0.1
+    invalid:
+      mode: unknown
+`)
+	direct := &recordingClient{}
+	s := newStatsdService(dependencies{Config: cfg, Direct: direct})
+
+	c, err := s.CreateForComponent("disabled", "", ddgostatsd.WithoutTelemetry())
+	require.NoError(t, err)
+	assert.IsType(t, &ddgostatsd.NoOpClient{}, c)
+
+	c, err = s.CreateForComponent("aggregated", "", ddgostatsd.WithoutTelemetry())
+	require.NoError(t, err)
+	require.IsType(t, &sampledClient{}, c)
+	assert.Same(t, direct, c.(*sampledClient).ClientInterface)
+	assert.Equal(t, 0.1, c.(*sampledClient).rate)
+	assert.True(t, c.(*sampledClient).sample)
+
+	c, err = s.CreateForComponent("default", "", ddgostatsd.WithoutTelemetry())
+	require.NoError(t, err)
+	require.IsType(t, &sampledClient{}, c)
+	assert.Equal(t, 0.5, c.(*sampledClient).rate)
+	assert.False(t, c.(*sampledClient).sample)
+	assert.NoError(t, c.Close())
+
+	_, err = s.CreateForComponent("invalid", "", ddgostatsd.WithoutTelemetry())
+	assert.Error(t, err)
+}
+
+func TestCreateForComponentDirectFallback(t *testing.T) {
+	cfg := config.NewMockFromYAML(t, `
+internal_statsd:
+  mode: direct
+`)
+	s := newStatsdService(dependencies{Config: cfg})
+
+	c, err := s.CreateForComponent("component", "", ddgostatsd.WithoutTelemetry())
+	require.NoError(t, err)
+	assert.IsType(t, &ddgostatsd.Client{}, c)
+	assert.NoError(t, c.Close())
+}
+
+func TestSampledClient(t *testing.T) {
+	recorder := &recordingClient{}
+	c := &sampledClient{ClientInterface: recorder, rate: 0.5}
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, c.Incr("metric", nil, 0.5))
+	}
+	assert.Len(t, recorder.rates, 10)
+	assert.Equal(t, 0.25, recorder.rates[0])
+
+	recorder = &recordingClient{}
+	c = &sampledClient{ClientInterface: recorder, rate: 0.000001, sample: true}
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, c.Incr("metric", nil, 1))
+	}
+	assert.Empty(t, recorder.rates)
+}
@@ -231,7 +231,7 @@ func (fh *forwarderHealth) validateAPIKey(apiKey, domain string) (bool, error) {
 
 	url := fmt.Sprintf("%s%s?api_key=%s", domain, endpoints.V1ValidateEndpoint, apiKey)
 
-	transport := httputils.CreateHTTPTransport(fh.config)
+	transport := httputils.NewTransport(fh.config, httputils.ForwarderDestination)
 
 	client := &http.Client{
 		Transport: transport,
@@ -84,7 +84,7 @@ func NewWorker(
 
 // NewHTTPClient creates a new http.Client
 func NewHTTPClient(config config.Component) *http.Client {
-	transport := httputils.CreateHTTPTransport(config)
+	transport := httputils.NewTransport(config, httputils.ForwarderDestination)
 
 	return &http.Client{
 		Timeout:   config.GetDuration("forwarder_timeout") * time.Second,
@@ -8,6 +8,7 @@ package agentimpl
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -52,6 +53,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/status/health"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
 	"github.com/DataDog/datadog-agent/pkg/util/goroutinesdump"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
 	"github.com/DataDog/datadog-agent/pkg/util/option"
 	"github.com/DataDog/datadog-agent/pkg/util/startstop"
 )
@@ -98,6 +100,7 @@ type provides struct {
 	RCListener     rctypes.ListenerProvider
 	LogsReciever   option.Option[integrations.Component]
 	APIStreamLogs  api.AgentEndpointProvider
+	APIRegistry    api.AgentEndpointProvider
 }
 
 // logAgent represents the data pipeline that collects, decodes,
@@ -182,6 +185,10 @@ func newLogsAgent(deps dependencies) provides {
 				"/stream-logs",
 				"POST",
 			),
+			APIRegistry: api.NewAgentEndpointProvider(logsAgent.exportRegistry,
+				"/logs/registry",
+				"GET",
+			),
 		}
 	}
 
@@ -417,6 +424,22 @@ func (a *logAgent) onUpdateSDS(reconfigType sds.ReconfigureOrderType, updates ma
 	}
 }
 
+// exportRegistry writes the registry of the auditor, holding the file offsets
+// and the journald cursors, to be imported by another agent.
+func (a *logAgent) exportRegistry(w http.ResponseWriter, _ *http.Request) {
+	if a.started.Load() == status.StatusNotStarted {
+		httputils.SetJSONError(w, errors.New("the logs agent is not started"), http.StatusServiceUnavailable)
+		return
+	}
+	exporter, ok := a.auditor.(auditor.RegistryExporter)
+	if !ok {
+		httputils.SetJSONError(w, errors.New("the registry of the logs agent can't be exported"), http.StatusNotImplemented)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(exporter.ExportRegistry())
+}
+
 func streamLogsEvents(logsAgent agent.Component) func(w http.ResponseWriter, r *http.Request) {
 	return apiutils.GetStreamFunc(func() apiutils.MessageReceiver {
 		return logsAgent.GetMessageReceiver()
@@ -10,7 +10,9 @@ package agentimpl
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -39,6 +41,7 @@ import (
 	compressionfx "github.com/DataDog/datadog-agent/comp/serializer/logscompression/fx-mock"
 	"github.com/DataDog/datadog-agent/pkg/config/env"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/logs/auditor"
 	"github.com/DataDog/datadog-agent/pkg/logs/client/http"
 	"github.com/DataDog/datadog-agent/pkg/logs/client/mock"
 	"github.com/DataDog/datadog-agent/pkg/logs/client/tcp"
@@ -234,6 +237,30 @@ func (suite *AgentTestSuite) TestGetPipelineProvider() {
 	assert.NotNil(suite.T(), agent.GetPipelineProvider())
 }
 
+func (suite *AgentTestSuite) TestExportRegistry() {
+	l := mock.NewMockLogsIntake(suite.T())
+	defer l.Close()
+
+	endpoint := tcp.AddrToEndPoint(l.Addr())
+	endpoints := config.NewEndpoints(endpoint, nil, true, false)
+
+	agent, _, _ := createAgent(suite, endpoints)
+
+	recorder := httptest.NewRecorder()
+	agent.exportRegistry(recorder, httptest.NewRequest("GET", "/logs/registry", nil))
+	assert.Equal(suite.T(), 503, recorder.Code)
+
+	agent.startPipeline()
+	defer agent.stop(context.TODO())
+
+	recorder = httptest.NewRecorder()
+	agent.exportRegistry(recorder, httptest.NewRequest("GET", "/logs/registry", nil))
+	assert.Equal(suite.T(), 200, recorder.Code)
+	var registry auditor.JSONRegistry
+	assert.NoError(suite.T(), json.Unmarshal(recorder.Body.Bytes(), &registry))
+	assert.Equal(suite.T(), 2, registry.Version)
+}
+
 func (suite *AgentTestSuite) TestStatusProvider() {
 	tests := []struct {
 		name     string
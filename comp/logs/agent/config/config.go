@@ -62,16 +62,22 @@ var (
 
 // GlobalProcessingRules returns the global processing rules to apply to all logs.
 func GlobalProcessingRules(coreConfig pkgconfigmodel.Reader) ([]*ProcessingRule, error) {
+	return ProcessingRulesFromKey(coreConfig, "logs_config.processing_rules")
+}
+
+// ProcessingRulesFromKey returns the validated and compiled processing rules
+// set under the given configuration key.
+func ProcessingRulesFromKey(coreConfig pkgconfigmodel.Reader, key string) ([]*ProcessingRule, error) {
 	var rules []*ProcessingRule
 	var err error
-	raw := coreConfig.Get("logs_config.processing_rules")
+	raw := coreConfig.Get(key)
 	if raw == nil {
 		return rules, nil
 	}
 	if s, ok := raw.(string); ok && s != "" {
 		err = json.Unmarshal([]byte(s), &rules)
 	} else {
-		err = structure.UnmarshalKey(coreConfig, "logs_config.processing_rules", &rules, structure.ConvertEmptyStringToNil)
+		err = structure.UnmarshalKey(coreConfig, key, &rules, structure.ConvertEmptyStringToNil)
 	}
 	if err != nil {
 		return nil, err
@@ -16,15 +16,17 @@ import (
 
 // Logs source types
 const (
-	TCPType           = "tcp"
-	UDPType           = "udp"
-	FileType          = "file"
-	DockerType        = "docker"
-	ContainerdType    = "containerd"
-	JournaldType      = "journald"
-	IntegrationType   = "integration"
-	WindowsEventType  = "windows_event"
-	StringChannelType = "string_channel"
+	TCPType              = "tcp"
+	UDPType              = "udp"
+	FileType             = "file"
+	DockerType           = "docker"
+	ContainerdType       = "containerd"
+	JournaldType         = "journald"
+	IntegrationType      = "integration"
+	WindowsEventType     = "windows_event"
+	StringChannelType    = "string_channel"
+	SyslogType           = "syslog"
+	KubernetesEventsType = "kubernetes_events"
 
 	// UTF16BE for UTF-16 Big endian encoding
 	UTF16BE string = "utf-16-be"
@@ -45,6 +47,10 @@ type LogsConfig struct {
 	IdleTimeout string `mapstructure:"idle_timeout" json:"idle_timeout" yaml:"idle_timeout"` // Network
 	Path        string // File, Journald
 
+	Protocol    string `mapstructure:"protocol" json:"protocol" yaml:"protocol"`                // Syslog
+	TLSCertFile string `mapstructure:"tls_cert_file" json:"tls_cert_file" yaml:"tls_cert_file"` // Syslog
+	TLSKeyFile  string `mapstructure:"tls_key_file" json:"tls_key_file" yaml:"tls_key_file"`    // Syslog
+
 	Encoding     string           `mapstructure:"encoding" json:"encoding" yaml:"encoding"`                   // File
 	ExcludePaths StringSliceField `mapstructure:"exclude_paths" json:"exclude_paths" yaml:"exclude_paths"`    // File
 	TailingMode  string           `mapstructure:"start_position" json:"start_position" yaml:"start_position"` // File
@@ -70,6 +76,10 @@ type LogsConfig struct {
 	ChannelPath string `mapstructure:"channel_path" json:"channel_path" yaml:"channel_path"` // Windows Event
 	Query       string // Windows Event
 
+	IncludeNamespaces StringSliceField  `mapstructure:"include_namespaces" json:"include_namespaces" yaml:"include_namespaces"` // Kubernetes Events
+	ExcludeNamespaces StringSliceField  `mapstructure:"exclude_namespaces" json:"exclude_namespaces" yaml:"exclude_namespaces"` // Kubernetes Events
+	FieldMapping      map[string]string `mapstructure:"field_mapping" json:"field_mapping" yaml:"field_mapping"`                // Kubernetes Events
+
 	// used as input only by the Channel tailer.
 	// could have been unidirectional but the tailer could not close it in this case.
 	Channel chan *ChannelMessage
@@ -144,6 +154,12 @@ func (c *LogsConfig) Dump(multiline bool) string {
 	case UDPType:
 		fmt.Fprintf(&b, ws("Port: %d,"), c.Port)
 		fmt.Fprintf(&b, ws("IdleTimeout: %#v,"), c.IdleTimeout)
+	case SyslogType:
+		fmt.Fprintf(&b, ws("Port: %d,"), c.Port)
+		fmt.Fprintf(&b, ws("Protocol: %#v,"), c.Protocol)
+		fmt.Fprintf(&b, ws("IdleTimeout: %#v,"), c.IdleTimeout)
+		fmt.Fprintf(&b, ws("TLSCertFile: %#v,"), c.TLSCertFile)
+		fmt.Fprintf(&b, ws("TLSKeyFile: %#v,"), c.TLSKeyFile)
 	case FileType:
 		fmt.Fprintf(&b, ws("Path: %#v,"), c.Path)
 		fmt.Fprintf(&b, ws("Encoding: %#v,"), c.Encoding)
@@ -166,6 +182,10 @@ func (c *LogsConfig) Dump(multiline bool) string {
 	case WindowsEventType:
 		fmt.Fprintf(&b, ws("ChannelPath: %#v,"), c.ChannelPath)
 		fmt.Fprintf(&b, ws("Query: %#v,"), c.Query)
+	case KubernetesEventsType:
+		fmt.Fprintf(&b, ws("IncludeNamespaces: %#v,"), c.IncludeNamespaces)
+		fmt.Fprintf(&b, ws("ExcludeNamespaces: %#v,"), c.ExcludeNamespaces)
+		fmt.Fprintf(&b, ws("FieldMapping: %#v,"), c.FieldMapping)
 	case StringChannelType:
 		fmt.Fprintf(&b, ws("Channel: %p,"), c.Channel)
 		c.ChannelTagsMutex.Lock()
@@ -200,6 +220,7 @@ func (c *LogsConfig) PublicJSON() ([]byte, error) {
 	return json.Marshal(&struct {
 		Type            string            `json:"type,omitempty"`
 		Port            int               `json:"port,omitempty"`           // Network
+		Protocol        string            `json:"protocol,omitempty"`       // Syslog
 		Path            string            `json:"path,omitempty"`           // File, Journald
 		Encoding        string            `json:"encoding,omitempty"`       // File
 		ExcludePaths    []string          `json:"exclude_paths,omitempty"`  // File
@@ -213,6 +234,7 @@ func (c *LogsConfig) PublicJSON() ([]byte, error) {
 	}{
 		Type:            c.Type,
 		Port:            c.Port,
+		Protocol:        c.Protocol,
 		Path:            c.Path,
 		Encoding:        c.Encoding,
 		ExcludePaths:    c.ExcludePaths,
@@ -287,6 +309,10 @@ func (c *LogsConfig) Validate() error {
 		return fmt.Errorf("tcp source must have a port")
 	case c.Type == UDPType && c.Port == 0:
 		return fmt.Errorf("udp source must have a port")
+	case c.Type == SyslogType:
+		if err := c.validateSyslog(); err != nil {
+			return err
+		}
 	}
 	err := ValidateProcessingRules(c.ProcessingRules)
 	if err != nil {
@@ -295,6 +321,25 @@ func (c *LogsConfig) Validate() error {
 	return CompileProcessingRules(c.ProcessingRules)
 }
 
+func (c *LogsConfig) validateSyslog() error {
+	if c.Port == 0 {
+		return fmt.Errorf("syslog source must have a port")
+	}
+	switch c.Protocol {
+	case UDPType:
+		if c.TLSCertFile != "" || c.TLSKeyFile != "" {
+			return fmt.Errorf("syslog source can only use TLS with the tcp protocol")
+		}
+	case TCPType:
+		if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+			return fmt.Errorf("syslog source must have both a tls_cert_file and a tls_key_file to use TLS")
+		}
+	default:
+		return fmt.Errorf("syslog source protocol must be %q or %q, got %q", TCPType, UDPType, c.Protocol)
+	}
+	return nil
+}
+
 func (c *LogsConfig) validateTailingMode() error {
 	mode, found := TailingModeFromString(c.TailingMode)
 	if !found && c.TailingMode != "" {
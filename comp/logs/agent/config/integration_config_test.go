@@ -21,6 +21,9 @@ func TestValidateShouldSucceedWithValidConfigs(t *testing.T) {
 		{Type: FileType, Path: "/var/log/foo.log"},
 		{Type: TCPType, Port: 1234},
 		{Type: UDPType, Port: 5678},
+		{Type: SyslogType, Port: 514, Protocol: UDPType},
+		{Type: SyslogType, Port: 514, Protocol: TCPType},
+		{Type: SyslogType, Port: 6514, Protocol: TCPType, TLSCertFile: "/etc/ssl/cert.pem", TLSKeyFile: "/etc/ssl/key.pem"},
 		{Type: DockerType},
 		{Type: JournaldType, ProcessingRules: []*ProcessingRule{{Name: "foo", Type: ExcludeAtMatch, Pattern: ".*"}}},
 	}
@@ -37,6 +40,11 @@ func TestValidateShouldFailWithInvalidConfigs(t *testing.T) {
 		{Type: FileType},
 		{Type: TCPType},
 		{Type: UDPType},
+		{Type: SyslogType, Protocol: UDPType},
+		{Type: SyslogType, Port: 514},
+		{Type: SyslogType, Port: 514, Protocol: "http"},
+		{Type: SyslogType, Port: 514, Protocol: UDPType, TLSCertFile: "/etc/ssl/cert.pem", TLSKeyFile: "/etc/ssl/key.pem"},
+		{Type: SyslogType, Port: 6514, Protocol: TCPType, TLSCertFile: "/etc/ssl/cert.pem"},
 		{Type: DockerType, ProcessingRules: []*ProcessingRule{{Name: "foo"}}},
 		{Type: DockerType, ProcessingRules: []*ProcessingRule{{Name: "foo", Type: "bar"}}},
 		{Type: DockerType, ProcessingRules: []*ProcessingRule{{Name: "foo", Type: ExcludeAtMatch}}},
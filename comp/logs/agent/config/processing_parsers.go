@@ -0,0 +1,224 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Types of the values extracted by a grok parser.
+const (
+	ParsedFieldString = ""
+	ParsedFieldInt    = "int"
+	ParsedFieldFloat  = "float"
+)
+
+// ParsedField is an attribute extracted by a parsing rule.
+type ParsedField struct {
+	// Name is the name of the attribute, nested attributes are separated by dots.
+	Name string
+	// Type is the type the extracted value is converted to.
+	Type string
+}
+
+// grokPatterns are the patterns a grok parser can refer to with %{NAME}.
+var grokPatterns = map[string]string{
+	"USERNAME":          `[a-zA-Z0-9._-]+`,
+	"USER":              `%{USERNAME}`,
+	"INT":               `[+-]?[0-9]+`,
+	"BASE10NUM":         `[+-]?(?:[0-9]+(?:\.[0-9]+)?|\.[0-9]+)`,
+	"NUMBER":            `%{BASE10NUM}`,
+	"POSINT":            `\b[1-9][0-9]*\b`,
+	"NONNEGINT":         `\b[0-9]+\b`,
+	"WORD":              `\b\w+\b`,
+	"NOTSPACE":          `\S+`,
+	"SPACE":             `\s*`,
+	"DATA":              `.*?`,
+	"GREEDYDATA":        `.*`,
+	"QUOTEDSTRING":      `"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`,
+	"UUID":              `[A-Fa-f0-9]{8}-(?:[A-Fa-f0-9]{4}-){3}[A-Fa-f0-9]{12}`,
+	"IPV4":              `(?:(?:25[0-5]|2[0-4][0-9]|1?[0-9]{1,2})\.){3}(?:25[0-5]|2[0-4][0-9]|1?[0-9]{1,2})`,
+	"IPV6":              `[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}(?:%[0-9A-Za-z]+)?`,
+	"IP":                `%{IPV6}|%{IPV4}`,
+	"HOSTNAME":          `\b[0-9A-Za-z][0-9A-Za-z-]{0,62}(?:\.[0-9A-Za-z][0-9A-Za-z-]{0,62})*\.?\b`,
+	"IPORHOST":          `%{IP}|%{HOSTNAME}`,
+	"HOSTPORT":          `%{IPORHOST}:%{POSINT}`,
+	"PATH":              `(?:/[^\s]*)+`,
+	"LOGLEVEL":          `[Aa]lert|ALERT|[Tt]race|TRACE|[Dd]ebug|DEBUG|[Nn]otice|NOTICE|[Ii]nfo(?:rmation)?|INFO(?:RMATION)?|[Ww]arn(?:ing)?|WARN(?:ING)?|[Ee]rr(?:or)?|ERR(?:OR)?|[Cc]rit(?:ical)?|CRIT(?:ICAL)?|[Ff]atal|FATAL|[Ss]evere|SEVERE|[Ee]merg(?:ency)?|EMERG(?:ENCY)?`,
+	"TIMESTAMP_ISO8601": `\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}(?::\d{2}(?:[.,]\d+)?)?(?:Z|[+-]\d{2}:?\d{2})?`,
+	"HTTPDATE":          `\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}`,
+	"SYSLOGTIMESTAMP":   `\w{3} +\d{1,2} \d{2}:\d{2}:\d{2}`,
+}
+
+// grokReference matches the %{NAME}, %{NAME:field} and %{NAME:field:type}
+// references of a grok parser.
+var grokReference = regexp.MustCompile(`%\{(\w+)(?::([^:}]+))?(?::(\w+))?\}`)
+
+// maxGrokDepth is the maximum nesting of the grok patterns.
+const maxGrokDepth = 8
+
+// compileParser compiles the pattern of a parsing rule into a regex matching
+// the whole content, and returns the fields extracted from its submatches,
+// indexed like the submatches.
+func compileParser(ruleType string, pattern string) (*regexp.Regexp, []ParsedField, error) {
+	var expr string
+	groups := make(map[string]ParsedField)
+	var err error
+	switch ruleType {
+	case GrokParser:
+		expr, err = expandGrok(pattern, groups)
+	case DissectParser:
+		expr, err = expandDissect(pattern, groups)
+	default:
+		err = fmt.Errorf("%s is not a parsing rule", ruleType)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, nil, err
+	}
+	fields := make([]ParsedField, re.NumSubexp()+1)
+	for i, name := range re.SubexpNames() {
+		if field, ok := groups[name]; ok {
+			fields[i] = field
+		}
+	}
+	return re, fields, nil
+}
+
+// expandGrok replaces the references of a grok pattern by their regex, the
+// references with a field name being replaced by a named group added to groups.
+func expandGrok(pattern string, groups map[string]ParsedField) (string, error) {
+	var expanded strings.Builder
+	last := 0
+	for _, loc := range grokReference.FindAllStringSubmatchIndex(pattern, -1) {
+		expanded.WriteString(pattern[last:loc[0]])
+		last = loc[1]
+
+		name := pattern[loc[2]:loc[3]]
+		expr, err := expandGrokPattern(name, 0)
+		if err != nil {
+			return "", err
+		}
+		if loc[4] < 0 {
+			expanded.WriteString("(?:" + expr + ")")
+			continue
+		}
+
+		field := ParsedField{Name: pattern[loc[4]:loc[5]]}
+		if loc[6] >= 0 {
+			field.Type = pattern[loc[6]:loc[7]]
+		}
+		if err := validateParsedField(field); err != nil {
+			return "", err
+		}
+		group := "field" + strconv.Itoa(len(groups))
+		groups[group] = field
+		expanded.WriteString("(?P<" + group + ">" + expr + ")")
+	}
+	expanded.WriteString(pattern[last:])
+	return expanded.String(), nil
+}
+
+// expandGrokPattern returns the regex of a grok pattern of the library.
+func expandGrokPattern(name string, depth int) (string, error) {
+	if depth > maxGrokDepth {
+		return "", fmt.Errorf("grok pattern %s is nested too deeply", name)
+	}
+	expr, ok := grokPatterns[name]
+	if !ok {
+		return "", fmt.Errorf("unknown grok pattern %s", name)
+	}
+	var err error
+	expr = grokReference.ReplaceAllStringFunc(expr, func(reference string) string {
+		inner, innerErr := expandGrokPattern(grokReference.FindStringSubmatch(reference)[1], depth+1)
+		if innerErr != nil {
+			err = innerErr
+		}
+		return "(?:" + inner + ")"
+	})
+	return expr, err
+}
+
+// dissectKey matches the %{field}, %{?skipped}, %{} and %{field->} keys of a
+// dissect parser.
+var dissectKey = regexp.MustCompile(`%\{([^}]*)\}`)
+
+// expandDissect turns a dissect pattern into a regex, the fields of the
+// pattern being replaced by a named group added to groups. Each field ends at
+// the first occurrence of the delimiter following it, the last field taking
+// the rest of the content.
+func expandDissect(pattern string, groups map[string]ParsedField) (string, error) {
+	locs := dissectKey.FindAllStringSubmatchIndex(pattern, -1)
+	if len(locs) == 0 {
+		return "", fmt.Errorf("dissect pattern has no field")
+	}
+
+	var expanded strings.Builder
+	expanded.WriteString(regexp.QuoteMeta(pattern[:locs[0][0]]))
+	for i, loc := range locs {
+		key := pattern[loc[2]:loc[3]]
+		end := len(pattern)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		delimiter := pattern[loc[1]:end]
+		if delimiter == "" && end != len(pattern) {
+			return "", fmt.Errorf("dissect fields %s and %s must be separated by a delimiter", pattern[loc[0]:loc[1]], pattern[end:locs[i+1][1]])
+		}
+
+		padded := strings.HasSuffix(key, "->")
+		key = strings.TrimSuffix(key, "->")
+		expr := ".*?"
+		if delimiter == "" {
+			expr = ".*"
+		}
+		if key == "" || strings.HasPrefix(key, "?") {
+			expanded.WriteString("(?:" + expr + ")")
+		} else {
+			field := ParsedField{Name: key}
+			if err := validateParsedField(field); err != nil {
+				return "", err
+			}
+			group := "field" + strconv.Itoa(len(groups))
+			groups[group] = field
+			expanded.WriteString("(?P<" + group + ">" + expr + ")")
+		}
+
+		if padded {
+			// the delimiter following a padded field may be repeated
+			expanded.WriteString("(?:" + regexp.QuoteMeta(delimiter) + ")+")
+		} else {
+			expanded.WriteString(regexp.QuoteMeta(delimiter))
+		}
+	}
+	return expanded.String(), nil
+}
+
+// validateParsedField checks the name and the type of a parsed field.
+func validateParsedField(field ParsedField) error {
+	for _, part := range strings.Split(field.Name, ".") {
+		if part == "" {
+			return fmt.Errorf("invalid field name %q", field.Name)
+		}
+	}
+	switch field.Type {
+	case ParsedFieldString:
+	case ParsedFieldInt, ParsedFieldFloat:
+		if field.Name == "message" {
+			return fmt.Errorf("the message field can only be a string")
+		}
+	default:
+		return fmt.Errorf("unknown type %s for field %s", field.Type, field.Name)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// extract returns the fields extracted by a compiled parsing rule.
+func extract(rule *ProcessingRule, content string) map[string]string {
+	match := rule.Regex.FindStringSubmatch(content)
+	if match == nil {
+		return nil
+	}
+	values := make(map[string]string)
+	for i, field := range rule.Fields {
+		if field.Name != "" {
+			values[field.Name] = match[i]
+		}
+	}
+	return values
+}
+
+func TestCompileGrokParser(t *testing.T) {
+	rules := []*ProcessingRule{{
+		Name:    "access",
+		Type:    GrokParser,
+		Pattern: `%{IPORHOST:network.client.ip} - %{NOTSPACE} \[%{HTTPDATE:date}\] "%{WORD:http.method} %{NOTSPACE:http.url}" %{INT:http.status_code:int} %{NUMBER:duration:float}`,
+	}}
+	require.NoError(t, ValidateProcessingRules(rules))
+	require.NoError(t, CompileProcessingRules(rules))
+
+	values := extract(rules[0], `192.168.1.10 - - [10/Oct/2025:13:55:36 +0000] "GET /index.html" 200 0.25`)
+	assert.Equal(t, map[string]string{
+		"network.client.ip": "192.168.1.10",
+		"date":              "10/Oct/2025:13:55:36 +0000",
+		"http.method":       "GET",
+		"http.url":          "/index.html",
+		"http.status_code":  "200",
+		"duration":          "0.25",
+	}, values)
+
+	var types []string
+	for _, field := range rules[0].Fields {
+		if field.Name != "" {
+			types = append(types, field.Type)
+		}
+	}
+	assert.Equal(t, []string{"", "", "", "", "int", "float"}, types)
+
+	// the pattern must match the whole content
+	assert.Nil(t, extract(rules[0], `not an access log`))
+}
+
+func TestCompileGrokParserWithRegex(t *testing.T) {
+	rules := []*ProcessingRule{{
+		Name:    "app",
+		Type:    GrokParser,
+		Pattern: `(\w+) %{LOGLEVEL:level}: %{GREEDYDATA:message}`,
+	}}
+	require.NoError(t, CompileProcessingRules(rules))
+
+	assert.Equal(t, map[string]string{"level": "WARN", "message": "disk almost full"}, extract(rules[0], "app WARN: disk almost full"))
+}
+
+func TestCompileDissectParser(t *testing.T) {
+	rules := []*ProcessingRule{{
+		Name:    "app",
+		Type:    DissectParser,
+		Pattern: `[%{timestamp}] %{level->} %{?thread} %{logger}: %{message}`,
+	}}
+	require.NoError(t, ValidateProcessingRules(rules))
+	require.NoError(t, CompileProcessingRules(rules))
+
+	assert.Equal(t, map[string]string{
+		"timestamp": "2025-10-10 13:55:36",
+		"level":     "INFO",
+		"logger":    "com.example.App",
+		"message":   "started: listening on 8080",
+	}, extract(rules[0], "[2025-10-10 13:55:36] INFO    main com.example.App: started: listening on 8080"))
+	assert.Nil(t, extract(rules[0], "started"))
+}
+
+func TestValidateParsingRules(t *testing.T) {
+	for _, rule := range []*ProcessingRule{
+		{Name: "unknown pattern", Type: GrokParser, Pattern: `%{UNKNOWN:field}`},
+		{Name: "unknown type", Type: GrokParser, Pattern: `%{INT:field:bool}`},
+		{Name: "typed message", Type: GrokParser, Pattern: `%{INT:message:int}`},
+		{Name: "empty field name", Type: GrokParser, Pattern: `%{INT:field.}`},
+		{Name: "invalid regex", Type: GrokParser, Pattern: `%{INT:field}(?=a)`},
+		{Name: "no field", Type: DissectParser, Pattern: `some text`},
+		{Name: "no delimiter", Type: DissectParser, Pattern: `%{first}%{second}`},
+	} {
+		t.Run(rule.Name, func(t *testing.T) {
+			assert.Error(t, ValidateProcessingRules([]*ProcessingRule{rule}))
+			assert.Error(t, CompileProcessingRules([]*ProcessingRule{rule}))
+		})
+	}
+}
@@ -16,9 +16,11 @@ const (
 	IncludeAtMatch = "include_at_match"
 	MaskSequences  = "mask_sequences"
 	MultiLine      = "multi_line"
+	GrokParser     = "grok_parser"
+	DissectParser  = "dissect_parser"
 )
 
-// ProcessingRule defines an exclusion or a masking rule to
+// ProcessingRule defines an exclusion, a masking or a parsing rule to
 // be applied on log lines
 type ProcessingRule struct {
 	Type               string
@@ -28,6 +30,9 @@ type ProcessingRule struct {
 	// TODO: should be moved out
 	Regex       *regexp.Regexp
 	Placeholder []byte
+	// Fields are the attributes extracted by a parsing rule, indexed like the
+	// submatches of Regex.
+	Fields []ParsedField
 }
 
 // ValidateProcessingRules validates the rules and raises an error if one is misconfigured.
@@ -42,7 +47,7 @@ func ValidateProcessingRules(rules []*ProcessingRule) error {
 		}
 
 		switch rule.Type {
-		case ExcludeAtMatch, IncludeAtMatch, MaskSequences, MultiLine:
+		case ExcludeAtMatch, IncludeAtMatch, MaskSequences, MultiLine, GrokParser, DissectParser:
 			break
 		case "":
 			return fmt.Errorf("type must be set for processing rule `%s`", rule.Name)
@@ -53,6 +58,12 @@ func ValidateProcessingRules(rules []*ProcessingRule) error {
 		if rule.Pattern == "" {
 			return fmt.Errorf("no pattern provided for processing rule: %s", rule.Name)
 		}
+		if IsParsingRule(rule) {
+			if _, _, err := compileParser(rule.Type, rule.Pattern); err != nil {
+				return fmt.Errorf("invalid pattern %s for processing rule: %s: %v", rule.Pattern, rule.Name, err)
+			}
+			continue
+		}
 		_, err := regexp.Compile(rule.Pattern)
 		if err != nil {
 			return fmt.Errorf("invalid pattern %s for processing rule: %s", rule.Pattern, rule.Name)
@@ -64,6 +75,15 @@ func ValidateProcessingRules(rules []*ProcessingRule) error {
 // CompileProcessingRules compiles all processing rule regular expressions.
 func CompileProcessingRules(rules []*ProcessingRule) error {
 	for _, rule := range rules {
+		if IsParsingRule(rule) {
+			re, fields, err := compileParser(rule.Type, rule.Pattern)
+			if err != nil {
+				return err
+			}
+			rule.Regex = re
+			rule.Fields = fields
+			continue
+		}
 		re, err := regexp.Compile(rule.Pattern)
 		if err != nil {
 			return err
@@ -83,3 +103,8 @@ func CompileProcessingRules(rules []*ProcessingRule) error {
 	}
 	return nil
 }
+
+// IsParsingRule returns whether the rule extracts attributes from the log lines.
+func IsParsingRule(rule *ProcessingRule) bool {
+	return rule.Type == GrokParser || rule.Type == DissectParser
+}
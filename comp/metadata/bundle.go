@@ -13,6 +13,7 @@ import (
 	hostgpu "github.com/DataDog/datadog-agent/comp/metadata/hostgpu/fx"
 	"github.com/DataDog/datadog-agent/comp/metadata/inventoryagent/inventoryagentimpl"
 	"github.com/DataDog/datadog-agent/comp/metadata/inventorychecks/inventorychecksimpl"
+	inventoryfeatures "github.com/DataDog/datadog-agent/comp/metadata/inventoryfeatures/fx"
 	"github.com/DataDog/datadog-agent/comp/metadata/inventoryhost/inventoryhostimpl"
 	"github.com/DataDog/datadog-agent/comp/metadata/inventoryotel/inventoryotelimpl"
 	"github.com/DataDog/datadog-agent/comp/metadata/packagesigning/packagesigningimpl"
@@ -35,6 +36,7 @@ func Bundle() fxutil.BundleOptions {
 		inventoryhostimpl.Module(),
 		hostgpu.Module(),
 		inventorychecksimpl.Module(),
+		inventoryfeatures.Module(),
 		inventoryotelimpl.Module(),
 		packagesigningimpl.Module(),
 		systemprobe.Module(),
@@ -0,0 +1,12 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Package inventoryfeatures implements a component to generate the 'features_metadata' metadata payload for inventory.
+package inventoryfeatures
+
+// team: agent-configuration
+
+// Component is the component type.
+type Component interface{}
@@ -0,0 +1,143 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package inventoryfeaturesimpl
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	sysconfigtypes "github.com/DataDog/datadog-agent/cmd/system-probe/config/types"
+	"github.com/DataDog/datadog-agent/comp/otelcol/otlp/configcheck"
+	configFetcher "github.com/DataDog/datadog-agent/pkg/config/fetcher"
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+	"github.com/DataDog/datadog-agent/pkg/status/systemprobe"
+	"github.com/DataDog/datadog-agent/pkg/util/scrubber"
+)
+
+// listenerDialTimeout bounds the time spent checking that a port is bound
+const listenerDialTimeout = time.Second
+
+var (
+	// for testing
+	fetchTraceConfig      = configFetcher.TraceAgentConfig
+	fetchProcessConfig    = func(cfg model.Reader) (string, error) { return configFetcher.ProcessAgentConfig(cfg, false) }
+	fetchSecurityConfig   = configFetcher.SecurityAgentConfig
+	fetchSystemProbeStats = systemprobe.GetStats
+)
+
+// featureStatus is the effective status of an Agent subsystem
+type featureStatus struct {
+	// Enabled reports whether the subsystem is enabled in the configuration
+	Enabled bool `json:"enabled"`
+	// Active reports whether the subsystem is actually running
+	Active bool `json:"active"`
+	// Reason explains why an enabled subsystem isn't active
+	Reason string `json:"reason,omitempty"`
+}
+
+func inactive(err error) featureStatus {
+	return featureStatus{
+		Enabled: true,
+		Reason:  scrubber.ScrubLine(err.Error()),
+	}
+}
+
+func (i *inventoryfeaturesimpl) getFeatures() map[string]featureStatus {
+	features := make(map[string]featureStatus)
+
+	// The other agents are active when they answer on their API
+	features["apm"] = agentStatus(i.conf.GetBool("apm_config.enabled"), "trace-agent", i.conf, fetchTraceConfig)
+	features["process_agent"] = agentStatus(
+		i.conf.GetBool("process_config.process_collection.enabled") || i.conf.GetBool("process_config.container_collection.enabled"),
+		"process-agent", i.conf, fetchProcessConfig)
+	features["security_agent"] = agentStatus(
+		i.conf.GetBool("runtime_security_config.enabled") || i.conf.GetBool("compliance_config.enabled"),
+		"security-agent", i.conf, fetchSecurityConfig)
+
+	otlpEnabled := configcheck.IsEnabled(i.conf)
+	for _, protocol := range []string{"grpc", "http"} {
+		endpoint := i.conf.GetString(fmt.Sprintf("otlp_config.receiver.protocols.%s.endpoint", protocol))
+		features["otlp_"+protocol] = listenerStatus(otlpEnabled && endpoint != "", endpoint)
+	}
+
+	var sysprobeObject *sysconfigtypes.Config
+	if sysprobeConf, ok := i.sysprobeConf.Get(); ok {
+		sysprobeObject = sysprobeConf.SysProbeObject()
+	}
+	systemProbeFeatures(sysprobeObject, features)
+
+	return features
+}
+
+// agentStatus returns the status of an agent running in its own process
+func agentStatus(enabled bool, name string, conf model.Reader, fetch func(model.Reader) (string, error)) featureStatus {
+	if !enabled {
+		return featureStatus{}
+	}
+	if _, err := fetch(conf); err != nil {
+		return inactive(fmt.Errorf("%s is not reachable: %w", name, err))
+	}
+	return featureStatus{Enabled: true, Active: true}
+}
+
+// listenerStatus returns the status of a server, active when its port is bound
+func listenerStatus(enabled bool, endpoint string) featureStatus {
+	if !enabled {
+		return featureStatus{}
+	}
+
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return inactive(fmt.Errorf("invalid endpoint %q: %w", endpoint, err))
+	}
+	// a server listening on all interfaces is reachable on the loopback
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), listenerDialTimeout)
+	if err != nil {
+		return inactive(fmt.Errorf("nothing is listening on %s: %w", endpoint, err))
+	}
+	conn.Close()
+	return featureStatus{Enabled: true, Active: true}
+}
+
+// systemProbeFeatures adds the status of system-probe and of each of its
+// enabled modules, whose failure to start is reported by system-probe, e.g.
+// when their eBPF programs can't be loaded.
+func systemProbeFeatures(sysprobeObject *sysconfigtypes.Config, features map[string]featureStatus) {
+	if sysprobeObject == nil || !sysprobeObject.Enabled {
+		features["system_probe"] = featureStatus{}
+		return
+	}
+
+	stats, err := fetchSystemProbeStats(sysprobeObject.SocketAddress)
+	if err != nil {
+		err = fmt.Errorf("system-probe is not reachable: %w", err)
+		features["system_probe"] = inactive(err)
+		for module := range sysprobeObject.EnabledModules {
+			features["system_probe_"+string(module)] = inactive(err)
+		}
+		return
+	}
+	features["system_probe"] = featureStatus{Enabled: true, Active: true}
+
+	for module := range sysprobeObject.EnabledModules {
+		moduleStats, ok := stats[string(module)]
+		if !ok {
+			features["system_probe_"+string(module)] = inactive(fmt.Errorf("module %s is not loaded", module))
+			continue
+		}
+		if statsMap, ok := moduleStats.(map[string]interface{}); ok && statsMap["Error"] != nil {
+			moduleErr := fmt.Sprint(statsMap["Error"])
+			features["system_probe_"+string(module)] = inactive(fmt.Errorf("module %s failed to start: %s", module, moduleErr))
+			continue
+		}
+		features["system_probe_"+string(module)] = featureStatus{Enabled: true, Active: true}
+	}
+}
@@ -0,0 +1,117 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Package inventoryfeaturesimpl implements a component to generate the 'features_metadata' metadata payload for inventory.
+package inventoryfeaturesimpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	api "github.com/DataDog/datadog-agent/comp/api/api/def"
+	"github.com/DataDog/datadog-agent/comp/api/authtoken"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	flaretypes "github.com/DataDog/datadog-agent/comp/core/flare/types"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/comp/core/sysprobeconfig"
+	"github.com/DataDog/datadog-agent/comp/metadata/internal/util"
+	inventoryfeatures "github.com/DataDog/datadog-agent/comp/metadata/inventoryfeatures/def"
+	"github.com/DataDog/datadog-agent/comp/metadata/runner/runnerimpl"
+	"github.com/DataDog/datadog-agent/pkg/serializer"
+	"github.com/DataDog/datadog-agent/pkg/serializer/marshaler"
+	"github.com/DataDog/datadog-agent/pkg/util/hostname"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+	"github.com/DataDog/datadog-agent/pkg/util/option"
+	"github.com/DataDog/datadog-agent/pkg/util/uuid"
+)
+
+// Payload handles the JSON unmarshalling of the metadata payload
+type Payload struct {
+	Hostname  string                   `json:"hostname"`
+	Timestamp int64                    `json:"timestamp"`
+	Metadata  map[string]featureStatus `json:"features_metadata"`
+	UUID      string                   `json:"uuid"`
+}
+
+// MarshalJSON serialization a Payload to JSON
+func (p *Payload) MarshalJSON() ([]byte, error) {
+	type PayloadAlias Payload
+	return json.Marshal((*PayloadAlias)(p))
+}
+
+// SplitPayload implements marshaler.AbstractMarshaler#SplitPayload.
+//
+// In this case, the payload can't be split any further.
+func (p *Payload) SplitPayload(_ int) ([]marshaler.AbstractMarshaler, error) {
+	return nil, fmt.Errorf("could not split inventories features payload any more, payload is too big for intake")
+}
+
+type inventoryfeaturesimpl struct {
+	util.InventoryPayload
+
+	log          log.Component
+	conf         config.Component
+	sysprobeConf option.Option[sysprobeconfig.Component]
+	hostname     string
+}
+
+// Requires defines the dependencies for the inventoryfeatures component
+type Requires struct {
+	Log        log.Component
+	Config     config.Component
+	Serializer serializer.MetricSerializer
+	// We need the authtoken to be created so we requires the comp. It will be used by configFetcher.
+	AuthToken      authtoken.Component
+	SysProbeConfig option.Option[sysprobeconfig.Component]
+}
+
+// Provides defines the output of the inventoryfeatures component
+type Provides struct {
+	Comp             inventoryfeatures.Component
+	MetadataProvider runnerimpl.Provider
+	FlareProvider    flaretypes.Provider
+	Endpoint         api.AgentEndpointProvider
+}
+
+// NewComponent creates a new inventoryfeatures component
+func NewComponent(reqs Requires) Provides {
+	hname, _ := hostname.Get(context.Background())
+	i := &inventoryfeaturesimpl{
+		log:          reqs.Log,
+		conf:         reqs.Config,
+		sysprobeConf: reqs.SysProbeConfig,
+		hostname:     hname,
+	}
+	i.InventoryPayload = util.CreateInventoryPayload(reqs.Config, reqs.Log, reqs.Serializer, i.getPayload, "features.json")
+
+	return Provides{
+		Comp:             i,
+		MetadataProvider: i.MetadataProvider(),
+		FlareProvider:    i.FlareProvider(),
+		Endpoint:         api.NewAgentEndpointProvider(i.writePayloadAsJSON, "/metadata/inventory-features", "GET"),
+	}
+}
+
+func (i *inventoryfeaturesimpl) writePayloadAsJSON(w http.ResponseWriter, _ *http.Request) {
+	// GetAsJSON already return scrubbed data
+	scrubbed, err := i.GetAsJSON()
+	if err != nil {
+		httputils.SetJSONError(w, err, 500)
+		return
+	}
+	w.Write(scrubbed)
+}
+
+func (i *inventoryfeaturesimpl) getPayload() marshaler.JSONMarshaler {
+	return &Payload{
+		Hostname:  i.hostname,
+		Timestamp: time.Now().UnixNano(),
+		Metadata:  i.getFeatures(),
+		UUID:      uuid.GetUUID(),
+	}
+}
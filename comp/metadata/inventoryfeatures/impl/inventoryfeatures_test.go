@@ -0,0 +1,152 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package inventoryfeaturesimpl
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+
+	sysconfigtypes "github.com/DataDog/datadog-agent/cmd/system-probe/config/types"
+	"github.com/DataDog/datadog-agent/comp/api/authtoken"
+	authtokenimpl "github.com/DataDog/datadog-agent/comp/api/authtoken/fetchonlyimpl"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/comp/core/sysprobeconfig"
+	configFetcher "github.com/DataDog/datadog-agent/pkg/config/fetcher"
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+	serializermock "github.com/DataDog/datadog-agent/pkg/serializer/mocks"
+	"github.com/DataDog/datadog-agent/pkg/status/systemprobe"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+	"github.com/DataDog/datadog-agent/pkg/util/option"
+)
+
+func setupFetchers(t *testing.T) {
+	t.Cleanup(func() {
+		fetchTraceConfig = configFetcher.TraceAgentConfig
+		fetchProcessConfig = func(cfg model.Reader) (string, error) { return configFetcher.ProcessAgentConfig(cfg, false) }
+		fetchSecurityConfig = configFetcher.SecurityAgentConfig
+		fetchSystemProbeStats = systemprobe.GetStats
+	})
+
+	fetchTraceConfig = func(_ model.Reader) (string, error) { return "", nil }
+	fetchProcessConfig = func(_ model.Reader) (string, error) { return "", errors.New("connection refused") }
+	fetchSecurityConfig = func(_ model.Reader) (string, error) { return "", nil }
+	fetchSystemProbeStats = func(_ string) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			"network_tracer": map[string]interface{}{"state": map[string]interface{}{}},
+			"event_monitor":  map[string]interface{}{"Error": "failed to load eBPF programs"},
+			"updated_at":     time.Now().Unix(),
+		}, nil
+	}
+}
+
+func getInventoryFeaturesComp(t *testing.T, overrides map[string]interface{}) *inventoryfeaturesimpl {
+	l := logmock.New(t)
+
+	cfg := config.NewMock(t)
+	for k, v := range overrides {
+		cfg.Set(k, v, model.SourceUnknown)
+	}
+
+	r := Requires{
+		Log:        l,
+		Config:     cfg,
+		Serializer: serializermock.NewMetricSerializer(t),
+		AuthToken: fxutil.Test[authtoken.Component](t,
+			authtokenimpl.Module(),
+			fx.Provide(func() log.Component { return l }),
+			fx.Provide(func() config.Component { return cfg }),
+		),
+		SysProbeConfig: option.None[sysprobeconfig.Component](),
+	}
+
+	comp := NewComponent(r).Comp
+	return comp.(*inventoryfeaturesimpl)
+}
+
+func TestGetPayload(t *testing.T) {
+	setupFetchers(t)
+
+	i := getInventoryFeaturesComp(t, map[string]interface{}{
+		"apm_config.enabled":                        true,
+		"process_config.process_collection.enabled": true,
+		"runtime_security_config.enabled":           false,
+		"compliance_config.enabled":                 false,
+	})
+	i.hostname = "test-hostname"
+
+	p := i.getPayload().(*Payload)
+	assert.Equal(t, "test-hostname", p.Hostname)
+	assert.True(t, p.Timestamp <= time.Now().UnixNano())
+
+	assert.Equal(t, featureStatus{Enabled: true, Active: true}, p.Metadata["apm"])
+	assert.Equal(t, featureStatus{Enabled: true, Reason: "process-agent is not reachable: connection refused"}, p.Metadata["process_agent"])
+	assert.Equal(t, featureStatus{}, p.Metadata["security_agent"])
+	assert.Equal(t, featureStatus{}, p.Metadata["system_probe"])
+}
+
+func TestListenerStatus(t *testing.T) {
+	assert.Equal(t, featureStatus{}, listenerStatus(false, "0.0.0.0:4317"))
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	_, port, err := net.SplitHostPort(l.Addr().String())
+	require.NoError(t, err)
+
+	assert.Equal(t, featureStatus{Enabled: true, Active: true}, listenerStatus(true, "0.0.0.0:"+port))
+	assert.Equal(t, featureStatus{Enabled: true, Active: true}, listenerStatus(true, l.Addr().String()))
+
+	l.Close()
+	status := listenerStatus(true, l.Addr().String())
+	assert.True(t, status.Enabled)
+	assert.False(t, status.Active)
+	assert.Contains(t, status.Reason, "nothing is listening on "+l.Addr().String())
+
+	status = listenerStatus(true, "localhost")
+	assert.False(t, status.Active)
+	assert.Contains(t, status.Reason, "invalid endpoint")
+}
+
+func TestSystemProbeFeatures(t *testing.T) {
+	setupFetchers(t)
+
+	sysprobeObject := &sysconfigtypes.Config{
+		Enabled: true,
+		EnabledModules: map[sysconfigtypes.ModuleName]struct{}{
+			"network_tracer": {},
+			"event_monitor":  {},
+			"oom_kill_probe": {},
+		},
+	}
+
+	features := make(map[string]featureStatus)
+	systemProbeFeatures(sysprobeObject, features)
+	assert.Equal(t, map[string]featureStatus{
+		"system_probe":                {Enabled: true, Active: true},
+		"system_probe_network_tracer": {Enabled: true, Active: true},
+		"system_probe_event_monitor":  {Enabled: true, Reason: "module event_monitor failed to start: failed to load eBPF programs"},
+		"system_probe_oom_kill_probe": {Enabled: true, Reason: "module oom_kill_probe is not loaded"},
+	}, features)
+
+	fetchSystemProbeStats = func(_ string) (map[string]interface{}, error) {
+		return nil, errors.New("connection refused")
+	}
+	features = make(map[string]featureStatus)
+	systemProbeFeatures(sysprobeObject, features)
+	assert.Equal(t, featureStatus{Enabled: true, Reason: "system-probe is not reachable: connection refused"}, features["system_probe"])
+	assert.Equal(t, featureStatus{Enabled: true, Reason: "system-probe is not reachable: connection refused"}, features["system_probe_network_tracer"])
+
+	features = make(map[string]featureStatus)
+	systemProbeFeatures(nil, features)
+	assert.Equal(t, map[string]featureStatus{"system_probe": {}}, features)
+}
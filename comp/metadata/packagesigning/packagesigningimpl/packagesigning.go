@@ -163,7 +163,7 @@ func isAllowedInstallationTool(installTool string) bool {
 
 func (is *pkgSigning) getData() []signingKey {
 
-	transport := httputils.CreateHTTPTransport(is.conf)
+	transport := httputils.NewTransport(is.conf, httputils.DefaultDestination)
 	client := &http.Client{Transport: transport}
 
 	switch is.pkgManager {
@@ -15,6 +15,7 @@ import (
 
 	"gopkg.in/yaml.v2"
 
+	sysprobeclient "github.com/DataDog/datadog-agent/cmd/system-probe/api/client"
 	api "github.com/DataDog/datadog-agent/comp/api/api/def"
 	"github.com/DataDog/datadog-agent/comp/api/authtoken"
 	"github.com/DataDog/datadog-agent/comp/core/config"
@@ -38,6 +39,7 @@ var (
 	// for testing
 	fetchSystemProbeConfig         = configFetcher.SystemProbeConfig
 	fetchSystemProbeConfigBySource = configFetcher.SystemProbeConfigBySource
+	fetchEBPFLoadOutcomes          = fetchEBPFLoadOutcomesFromSystemProbe
 )
 
 // Payload handles the JSON unmarshalling of the metadata payload
@@ -174,10 +176,48 @@ func (sb *systemprobe) getConfigLayers() map[string]interface{} {
 	return metadata
 }
 
+// fetchEBPFLoadOutcomesFromSystemProbe queries system-probe for the method used to load each eBPF asset
+func fetchEBPFLoadOutcomesFromSystemProbe(config model.Reader) (map[string]interface{}, error) {
+	hc := sysprobeclient.Get(config.GetString("system_probe_config.sysprobe_socket"))
+	resp, err := hc.Get(sysprobeclient.DebugURL("/ebpf_load_outcomes"))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	outcomes := map[string]interface{}{}
+	if err := json.NewDecoder(resp.Body).Decode(&outcomes); err != nil {
+		return nil, err
+	}
+	return outcomes, nil
+}
+
+func (sb *systemprobe) getEBPFLoadOutcomes(metadata map[string]interface{}) {
+	sysprobeConf, isSet := sb.sysprobeConf.Get()
+	if !isSet {
+		return
+	}
+
+	outcomes, err := fetchEBPFLoadOutcomes(sysprobeConf)
+	if err != nil {
+		sb.log.Debugf("error fetching eBPF load outcomes from system-probe: %s", err)
+		return
+	}
+	if len(outcomes) > 0 {
+		metadata["ebpf_load_outcomes"] = outcomes
+	}
+}
+
 func (sb *systemprobe) getPayload() marshaler.JSONMarshaler {
+	metadata := sb.getConfigLayers()
+	sb.getEBPFLoadOutcomes(metadata)
+
 	return &Payload{
 		Hostname:  sb.hostname,
 		Timestamp: time.Now().UnixNano(),
-		Metadata:  sb.getConfigLayers(),
+		Metadata:  metadata,
 	}
 }
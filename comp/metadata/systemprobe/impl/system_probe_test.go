@@ -36,8 +36,13 @@ func setupFetcher(t *testing.T) {
 	t.Cleanup(func() {
 		fetchSystemProbeConfig = configFetcher.SystemProbeConfig
 		fetchSystemProbeConfigBySource = configFetcher.SystemProbeConfigBySource
+		fetchEBPFLoadOutcomes = fetchEBPFLoadOutcomesFromSystemProbe
 	})
 
+	fetchEBPFLoadOutcomes = func(_ model.Reader) (map[string]interface{}, error) {
+		return map[string]interface{}{"oom-kill": map[string]interface{}{"method": "co-re"}}, nil
+	}
+
 	fetchSystemProbeConfig = func(_ model.Reader) (string, error) { return "full config", nil }
 	fetchSystemProbeConfigBySource = func(_ model.Reader) (string, error) {
 		data, err := json.Marshal(map[string]interface{}{
@@ -89,6 +94,7 @@ func assertPayload(t *testing.T, p *Payload) {
 			"remote_configuration":               "rc: true\n",
 			"source_local_configuration":         "local: true\n",
 			"agent_version":                      version.AgentVersion,
+			"ebpf_load_outcomes":                 map[string]interface{}{"oom-kill": map[string]interface{}{"method": "co-re"}},
 		},
 		p.Metadata)
 }
@@ -114,7 +120,8 @@ func TestGetPayloadNoConfig(t *testing.T) {
 	assert.True(t, p.Timestamp <= time.Now().UnixNano())
 	assert.Equal(t,
 		map[string]interface{}{
-			"agent_version": version.AgentVersion,
+			"agent_version":      version.AgentVersion,
+			"ebpf_load_outcomes": map[string]interface{}{"oom-kill": map[string]interface{}{"method": "co-re"}},
 		},
 		p.Metadata)
 }
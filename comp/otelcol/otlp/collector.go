@@ -38,6 +38,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/otelcol/otlp/components/processor/infraattributesprocessor"
 	"github.com/DataDog/datadog-agent/comp/otelcol/otlp/datatype"
 	"github.com/DataDog/datadog-agent/comp/otelcol/otlp/internal/configutils"
+	"github.com/DataDog/datadog-agent/comp/otelcol/otlp/internal/resourcemapping"
 	"github.com/DataDog/datadog-agent/pkg/logs/message"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
 	"github.com/DataDog/datadog-agent/pkg/util/flavor"
@@ -125,7 +126,7 @@ func getComponents(s serializer.MetricSerializer, logsAgentChannel chan *message
 		errs = append(errs, err)
 	}
 
-	processorFactories := []processor.Factory{batchprocessor.NewFactory()}
+	processorFactories := []processor.Factory{batchprocessor.NewFactory(), resourcemapping.NewFactory()}
 	if tagger != nil {
 		processorFactories = append(processorFactories, infraattributesprocessor.NewFactoryForAgent(tagger))
 	}
@@ -168,6 +169,11 @@ type PipelineConfig struct {
 	Debug map[string]interface{}
 	// Metrics contains configuration options for the serializer metrics exporter
 	Metrics map[string]interface{}
+	// Logs contains configuration options for the logsagent exporter
+	Logs map[string]interface{}
+	// ResourceMapping contains configuration options for the resourcemapping processor,
+	// it is left empty when the resource attributes are not remapped.
+	ResourceMapping map[string]interface{}
 }
 
 // shouldSetLoggingSection returns whether debug logging is enabled.
@@ -36,6 +36,15 @@ type Config struct {
 	OtelSource    string
 	LogSourceName string
 	QueueSettings exporterhelper.QueueConfig
+	// ResourceAttributesAsTags maps the resource attributes to the tags set on their logs.
+	ResourceAttributesAsTags map[string]string `mapstructure:"resource_attributes_as_tags"`
+	// ProcessingRules are applied to the logs, after the global processing rules.
+	ProcessingRules []*config.ProcessingRule `mapstructure:"processing_rules"`
+}
+
+// Validate checks the processing rules of the configuration.
+func (c *Config) Validate() error {
+	return config.ValidateProcessingRules(c.ProcessingRules)
 }
 
 type factory struct {
@@ -70,7 +79,10 @@ func (f *factory) createLogsExporter(
 	c component.Config,
 ) (exp.Logs, error) {
 	cfg := checkAndCastConfig(c)
-	logSource := sources.NewLogSource(cfg.LogSourceName, &config.LogsConfig{})
+	if err := config.CompileProcessingRules(cfg.ProcessingRules); err != nil {
+		return nil, err
+	}
+	logSource := sources.NewLogSource(cfg.LogSourceName, &config.LogsConfig{ProcessingRules: cfg.ProcessingRules})
 
 	// TODO: Ideally the attributes translator would be created once and reused
 	// across all signals. This would need unifying the logsagent and serializer
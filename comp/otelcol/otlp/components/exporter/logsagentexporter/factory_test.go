@@ -11,9 +11,12 @@ import (
 	"context"
 	"testing"
 
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/comp/otelcol/otlp/testutil"
 	"github.com/DataDog/datadog-agent/pkg/logs/message"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 )
@@ -28,3 +31,27 @@ func TestNewLogsExporter(t *testing.T) {
 	_, err := factory.CreateLogs(context.Background(), set, cfg)
 	assert.NoError(t, err)
 }
+
+func TestNewLogsExporterProcessingRules(t *testing.T) {
+	channel := make(chan *message.Message, 1)
+
+	factory := NewFactory(channel)
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.ProcessingRules = []*config.ProcessingRule{{Type: config.ExcludeAtMatch, Name: "exclude_healthchecks", Pattern: "healthcheck"}}
+	require.NoError(t, cfg.Validate())
+
+	set := exportertest.NewNopSettings(component.MustNewType(TypeStr))
+	exp, err := factory.CreateLogs(context.Background(), set, cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.ConsumeLogs(context.Background(), testutil.GenerateLogsOneLogRecord()))
+
+	msg := <-channel
+	rules := msg.Origin.LogSource.Config.ProcessingRules
+	require.Len(t, rules, 1)
+	assert.True(t, rules[0].Regex.MatchString("GET /healthcheck"))
+}
+
+func TestConfigValidateProcessingRules(t *testing.T) {
+	cfg := &Config{ProcessingRules: []*config.ProcessingRule{{Type: config.ExcludeAtMatch, Name: "no_pattern"}}}
+	assert.Error(t, cfg.Validate())
+}
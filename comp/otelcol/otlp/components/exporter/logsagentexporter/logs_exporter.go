@@ -8,6 +8,7 @@ package logsagentexporter
 import (
 	"context"
 	"errors"
+	"slices"
 	"strings"
 	"time"
 
@@ -15,10 +16,12 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/logs/sources"
 	"github.com/DataDog/datadog-agent/pkg/util/scrubber"
 
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
 	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes"
 	logsmapping "github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/logs"
 	"github.com/stormcat24/protodep/pkg/logger"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 )
 
@@ -28,6 +31,8 @@ type Exporter struct {
 	logsAgentChannel chan *message.Message
 	logSource        *sources.LogSource
 	translator       *logsmapping.Translator
+	// resourceAttributesAsTags maps the resource attributes to tag names
+	resourceAttributesAsTags map[string]string
 }
 
 // NewExporter initializes a new logs agent exporter with the given parameters
@@ -44,10 +49,11 @@ func NewExporter(
 	}
 
 	return &Exporter{
-		set:              set,
-		logsAgentChannel: logsAgentChannel,
-		logSource:        logSource,
-		translator:       translator,
+		set:                      set,
+		logsAgentChannel:         logsAgentChannel,
+		logSource:                logSource,
+		translator:               translator,
+		resourceAttributesAsTags: cfg.ResourceAttributesAsTags,
 	}, nil
 }
 
@@ -64,9 +70,40 @@ func (e *Exporter) ConsumeLogs(ctx context.Context, ld plog.Logs) (err error) {
 		}
 	}()
 
-	payloads := e.translator.MapLogs(ctx, ld, nil)
+	if len(e.resourceAttributesAsTags) == 0 {
+		e.sendPayloads(e.translator.MapLogs(ctx, ld, nil), nil)
+		return nil
+	}
+
+	// the logs of each resource are mapped separately to get the tags of their resource
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resourceLogs := plog.NewLogs()
+		rl.CopyTo(resourceLogs.ResourceLogs().AppendEmpty())
+		e.sendPayloads(e.translator.MapLogs(ctx, resourceLogs, nil), e.resourceTags(rl.Resource()))
+	}
+	return nil
+}
+
+// resourceTags returns the tags mapped from the attributes of a resource.
+func (e *Exporter) resourceTags(resource pcommon.Resource) []string {
+	var tags []string
+	for attribute, tagName := range e.resourceAttributesAsTags {
+		value, ok := resource.Attributes().Get(attribute)
+		if !ok || value.AsString() == "" {
+			continue
+		}
+		tags = append(tags, tagName+":"+value.AsString())
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// sendPayloads sends the mapped logs to the logs agent, with the extra tags.
+func (e *Exporter) sendPayloads(payloads []datadogV2.HTTPLogItem, extraTags []string) {
 	for _, ddLog := range payloads {
-		tags := strings.Split(ddLog.GetDdtags(), ",")
+		tags := append(strings.Split(ddLog.GetDdtags(), ","), extraTags...)
 		// Tags are set in the message origin instead
 		ddLog.Ddtags = nil
 		service := ""
@@ -104,6 +141,4 @@ func (e *Exporter) ConsumeLogs(ctx context.Context, ld plog.Logs) (err error) {
 
 		e.logsAgentChannel <- message
 	}
-
-	return nil
 }
@@ -29,9 +29,10 @@ func TestLogsExporter(t *testing.T) {
 	ld := lr.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
 
 	type args struct {
-		ld            plog.Logs
-		otelSource    string
-		logSourceName string
+		ld                       plog.Logs
+		otelSource               string
+		logSourceName            string
+		resourceAttributesAsTags map[string]string
 	}
 	tests := []struct {
 		name         string
@@ -66,6 +67,34 @@ func TestLogsExporter(t *testing.T) {
 			},
 			expectedTags: [][]string{{"otel_source:datadog_agent"}},
 		},
+		{
+			name: "resource-attributes-as-tags",
+			args: args{
+				ld:                       lr,
+				otelSource:               otelSource,
+				logSourceName:            LogSourceName,
+				resourceAttributesAsTags: map[string]string{"resource-attr": "team", "missing-attr": "missing"},
+			},
+
+			want: testutil.JSONLogs{
+				{
+					"message":              ld.Body().AsString(),
+					"app":                  "server",
+					"instance_num":         "1",
+					"@timestamp":           testutil.TestLogTime.Format("2006-01-02T15:04:05.000Z07:00"),
+					"status":               "Info",
+					"dd.span_id":           fmt.Sprintf("%d", spanIDToUint64(ld.SpanID())),
+					"dd.trace_id":          fmt.Sprintf("%d", traceIDToUint64(ld.TraceID())),
+					"otel.severity_text":   "Info",
+					"otel.severity_number": "9",
+					"otel.span_id":         spanIDToHexOrEmptyString(ld.SpanID()),
+					"otel.trace_id":        traceIDToHexOrEmptyString(ld.TraceID()),
+					"otel.timestamp":       fmt.Sprintf("%d", testutil.TestLogTime.UnixNano()),
+					"resource-attr":        "resource-attr-val-1",
+				},
+			},
+			expectedTags: [][]string{{"otel_source:datadog_agent", "team:resource-attr-val-1"}},
+		},
 		{
 			name: "message-attribute",
 			args: args{
@@ -299,8 +328,9 @@ func TestLogsExporter(t *testing.T) {
 			params := exportertest.NewNopSettings(component.MustNewType(TypeStr))
 			f := NewFactory(testChannel)
 			cfg := &Config{
-				OtelSource:    tt.args.otelSource,
-				LogSourceName: tt.args.logSourceName,
+				OtelSource:               tt.args.otelSource,
+				LogSourceName:            tt.args.logSourceName,
+				ResourceAttributesAsTags: tt.args.resourceAttributesAsTags,
 			}
 			ctx := context.Background()
 			exp, err := f.CreateLogs(ctx, params, cfg)
@@ -33,6 +33,7 @@ require (
 require go.opentelemetry.io/collector/confmap/xconfmap v0.121.0
 
 require (
+	github.com/DataDog/datadog-agent/pkg/telemetry v0.61.0 // indirect
 	go.opentelemetry.io/collector/consumer/xconsumer v0.121.0 // indirect
 	go.opentelemetry.io/collector/processor/xprocessor v0.121.0 // indirect
 )
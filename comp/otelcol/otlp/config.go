@@ -17,6 +17,7 @@ import (
 	"github.com/go-viper/mapstructure/v2"
 
 	"github.com/DataDog/datadog-agent/comp/core/config"
+	logsconfig "github.com/DataDog/datadog-agent/comp/logs/agent/config"
 	"github.com/DataDog/datadog-agent/comp/otelcol/otlp/components/exporter/serializerexporter"
 	"github.com/DataDog/datadog-agent/comp/otelcol/otlp/configcheck"
 	coreconfig "github.com/DataDog/datadog-agent/pkg/config/setup"
@@ -63,6 +64,14 @@ func FromAgentConfig(cfg config.Reader) (PipelineConfig, error) {
 
 	debugConfig := configcheck.ReadConfigSection(cfg, coreconfig.OTLPDebug)
 
+	var lc map[string]interface{}
+	if logsEnabled {
+		lc, err = logsExporterConfig(cfg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid logs config: %w", err))
+		}
+	}
+
 	return PipelineConfig{
 		OTLPReceiverConfig: otlpConfig.ToStringMap(),
 		TracePort:          tracePort,
@@ -70,10 +79,62 @@ func FromAgentConfig(cfg config.Reader) (PipelineConfig, error) {
 		TracesEnabled:      tracesEnabled,
 		LogsEnabled:        logsEnabled,
 		Metrics:            mc,
+		Logs:               lc,
+		ResourceMapping:    resourceMappingConfig(cfg),
 		Debug:              debugConfig.ToStringMap(),
 	}, multierr.Combine(errs...)
 }
 
+// logsExporterConfig returns the configuration of the logsagent exporter: the
+// resource attributes mapped to tags, and the processing rules applied to the
+// OTLP logs in addition to the global ones.
+func logsExporterConfig(cfg config.Reader) (map[string]interface{}, error) {
+	rules, err := logsconfig.ProcessingRulesFromKey(cfg, coreconfig.OTLPLogsProcessingRules)
+	if err != nil {
+		return nil, err
+	}
+	rawRules := make([]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		rawRules = append(rawRules, map[string]interface{}{
+			"type":                rule.Type,
+			"name":                rule.Name,
+			"pattern":             rule.Pattern,
+			"replace_placeholder": rule.ReplacePlaceholder,
+		})
+	}
+
+	attributesAsTags := make(map[string]interface{})
+	for attribute, tag := range cfg.GetStringMapString(coreconfig.OTLPLogsResourceAttrsTags) {
+		attributesAsTags[attribute] = tag
+	}
+	if len(rawRules) == 0 && len(attributesAsTags) == 0 {
+		return nil, nil
+	}
+	return map[string]interface{}{
+		"resource_attributes_as_tags": attributesAsTags,
+		"processing_rules":            rawRules,
+	}, nil
+}
+
+// resourceMappingConfig returns the configuration of the resourcemapping
+// processor, or nil if the resource attributes are not remapped.
+func resourceMappingConfig(cfg config.Reader) map[string]interface{} {
+	rename := make(map[string]interface{})
+	for from, to := range cfg.GetStringMapString(coreconfig.OTLPResourceAttrsRename) {
+		rename[from] = to
+	}
+	drop := cfg.GetStringSlice(coreconfig.OTLPResourceAttrsDrop)
+	hostnameAttributes := cfg.GetStringSlice(coreconfig.OTLPHostnameAttributes)
+	if len(rename) == 0 && len(drop) == 0 && len(hostnameAttributes) == 0 {
+		return nil
+	}
+	return map[string]interface{}{
+		"rename":              rename,
+		"drop":                drop,
+		"hostname_attributes": hostnameAttributes,
+	}
+}
+
 func normalizeMetricsConfig(metricsConfigMap map[string]interface{}, strict bool) (map[string]interface{}, error) {
 	// metricsConfigMap doesn't strictly match the types present in MetricsConfig struct
 	// so to get properly type map we need to decode it twice
@@ -166,6 +166,36 @@ func TestFromAgentConfigReceiver(t *testing.T) {
 				Debug: map[string]interface{}{},
 			},
 		},
+		{
+			path: "logs_resource_attributes.yaml",
+			cfg: PipelineConfig{
+				OTLPReceiverConfig: map[string]interface{}{},
+
+				TracePort:      5003,
+				MetricsEnabled: true,
+				TracesEnabled:  true,
+				LogsEnabled:    true,
+				Metrics: map[string]interface{}{
+					"enabled":                 true,
+					"tag_cardinality":         "low",
+					"apm_stats_receiver_addr": "http://localhost:8126/v0.6/stats",
+				},
+				Logs: map[string]interface{}{
+					"resource_attributes_as_tags": map[string]interface{}{
+						"service.namespace": "team",
+					},
+					"processing_rules": []interface{}{
+						map[string]interface{}{
+							"type":                "exclude_at_match",
+							"name":                "exclude_healthchecks",
+							"pattern":             "healthcheck",
+							"replace_placeholder": "",
+						},
+					},
+				},
+				Debug: map[string]interface{}{},
+			},
+		},
 		{
 			path: "logs_disabled.yaml",
 			cfg: PipelineConfig{
@@ -183,6 +213,28 @@ func TestFromAgentConfigReceiver(t *testing.T) {
 				Debug: map[string]interface{}{},
 			},
 		},
+		{
+			path: "resource_attributes.yaml",
+			cfg: PipelineConfig{
+				OTLPReceiverConfig: map[string]interface{}{},
+
+				TracePort:      5003,
+				MetricsEnabled: true,
+				TracesEnabled:  true,
+				LogsEnabled:    false,
+				Metrics: map[string]interface{}{
+					"enabled":                 true,
+					"tag_cardinality":         "low",
+					"apm_stats_receiver_addr": "http://localhost:8126/v0.6/stats",
+				},
+				ResourceMapping: map[string]interface{}{
+					"rename":              map[string]interface{}{"app": "service.name"},
+					"drop":                []string{"process.command_line"},
+					"hostname_attributes": []string{"k8s.node.name", "host.name"},
+				},
+				Debug: map[string]interface{}{},
+			},
+		},
 	}
 
 	for _, testInstance := range tests {
@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package resourcemapping
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// Config defines the configuration of the resource mapping processor.
+type Config struct {
+	// Rename maps resource attributes to their new name.
+	Rename map[string]string `mapstructure:"rename"`
+	// Drop lists the resource attributes to remove.
+	Drop []string `mapstructure:"drop"`
+	// HostnameAttributes lists the resource attributes used as hostname, by
+	// order of precedence. They are looked up after the renaming.
+	HostnameAttributes []string `mapstructure:"hostname_attributes"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks that the attributes are neither empty nor both renamed and dropped.
+func (cfg *Config) Validate() error {
+	targets := make(map[string]string, len(cfg.Rename))
+	for from, to := range cfg.Rename {
+		if from == "" || to == "" {
+			return fmt.Errorf("invalid rename %q -> %q: attribute names can't be empty", from, to)
+		}
+		if other, ok := targets[to]; ok {
+			return fmt.Errorf("attributes %q and %q can't both be renamed to %q", min(from, other), max(from, other), to)
+		}
+		targets[to] = from
+	}
+	for _, attr := range cfg.Drop {
+		if attr == "" {
+			return errors.New("dropped attribute names can't be empty")
+		}
+		if _, ok := cfg.Rename[attr]; ok {
+			return fmt.Errorf("attribute %q can't be both renamed and dropped", attr)
+		}
+	}
+	for _, attr := range cfg.HostnameAttributes {
+		if attr == "" {
+			return errors.New("hostname attribute names can't be empty")
+		}
+	}
+	return nil
+}
+
+// isEmpty returns whether the configuration leaves the resources unchanged.
+func (cfg *Config) isEmpty() bool {
+	return len(cfg.Rename) == 0 && len(cfg.Drop) == 0 && len(cfg.HostnameAttributes) == 0
+}
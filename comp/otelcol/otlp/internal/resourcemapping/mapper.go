@@ -0,0 +1,85 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package resourcemapping
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// hostnameAttribute is the resource attribute taking precedence over all the
+// other attributes when resolving the hostname of a payload.
+const hostnameAttribute = "datadog.host.name"
+
+type mapper struct {
+	cfg *Config
+}
+
+func newMapper(cfg *Config) *mapper {
+	return &mapper{cfg: cfg}
+}
+
+func (m *mapper) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		m.mapResource(rms.At(i).Resource())
+	}
+	return md, nil
+}
+
+func (m *mapper) processLogs(_ context.Context, ld plog.Logs) (plog.Logs, error) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		m.mapResource(rls.At(i).Resource())
+	}
+	return ld, nil
+}
+
+func (m *mapper) processTraces(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		m.mapResource(rss.At(i).Resource())
+	}
+	return td, nil
+}
+
+// mapResource renames and drops the attributes of the resource, then sets its
+// hostname from the first hostname attribute found.
+func (m *mapper) mapResource(resource pcommon.Resource) {
+	if m.cfg.isEmpty() {
+		return
+	}
+	attrs := resource.Attributes()
+
+	// the values are collected first so that attributes can be swapped
+	renamed := make(map[string]pcommon.Value, len(m.cfg.Rename))
+	for from, to := range m.cfg.Rename {
+		if value, ok := attrs.Get(from); ok {
+			v := pcommon.NewValueEmpty()
+			value.CopyTo(v)
+			renamed[to] = v
+			attrs.Remove(from)
+		}
+	}
+	for to, value := range renamed {
+		value.CopyTo(attrs.PutEmpty(to))
+	}
+
+	for _, attr := range m.cfg.Drop {
+		attrs.Remove(attr)
+	}
+
+	for _, attr := range m.cfg.HostnameAttributes {
+		if value, ok := attrs.Get(attr); ok && value.AsString() != "" {
+			attrs.PutStr(hostnameAttribute, value.AsString())
+			break
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package resourcemapping
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		err  string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			cfg: Config{
+				Rename:             map[string]string{"app": "service.name"},
+				Drop:               []string{"process.command_line"},
+				HostnameAttributes: []string{"k8s.node.name", "host.name"},
+			},
+		},
+		{
+			name: "empty rename target",
+			cfg:  Config{Rename: map[string]string{"app": ""}},
+			err:  `invalid rename "app" -> "": attribute names can't be empty`,
+		},
+		{
+			name: "same rename target",
+			cfg:  Config{Rename: map[string]string{"app": "service.name", "application": "service.name"}},
+			err:  `attributes "app" and "application" can't both be renamed to "service.name"`,
+		},
+		{
+			name: "renamed and dropped",
+			cfg: Config{
+				Rename: map[string]string{"app": "service.name"},
+				Drop:   []string{"app"},
+			},
+			err: `attribute "app" can't be both renamed and dropped`,
+		},
+		{
+			name: "empty hostname attribute",
+			cfg:  Config{HostnameAttributes: []string{""}},
+			err:  "hostname attribute names can't be empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestMapResource(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		attrs    map[string]any
+		expected map[string]any
+	}{
+		{
+			name:     "no mapping",
+			attrs:    map[string]any{"host.name": "host", "app": "web"},
+			expected: map[string]any{"host.name": "host", "app": "web"},
+		},
+		{
+			name: "rename",
+			cfg:  Config{Rename: map[string]string{"app": "service.name", "missing": "other"}},
+			attrs: map[string]any{
+				"app":          "web",
+				"service.name": "unknown_service",
+				"port":         int64(8080),
+			},
+			expected: map[string]any{"service.name": "web", "port": int64(8080)},
+		},
+		{
+			name:     "swap",
+			cfg:      Config{Rename: map[string]string{"a": "b", "b": "a"}},
+			attrs:    map[string]any{"a": "1", "b": "2"},
+			expected: map[string]any{"a": "2", "b": "1"},
+		},
+		{
+			name:     "drop",
+			cfg:      Config{Drop: []string{"process.command_line", "missing"}},
+			attrs:    map[string]any{"process.command_line": "./server --token=secret", "host.name": "host"},
+			expected: map[string]any{"host.name": "host"},
+		},
+		{
+			name: "hostname precedence",
+			cfg:  Config{HostnameAttributes: []string{"k8s.node.name", "cloud.instance.id", "host.name"}},
+			attrs: map[string]any{
+				"k8s.node.name":     "",
+				"cloud.instance.id": "i-1234",
+				"host.name":         "host",
+			},
+			expected: map[string]any{
+				"k8s.node.name":     "",
+				"cloud.instance.id": "i-1234",
+				"host.name":         "host",
+				"datadog.host.name": "i-1234",
+			},
+		},
+		{
+			name:     "hostname from renamed attribute",
+			cfg:      Config{Rename: map[string]string{"node": "k8s.node.name"}, HostnameAttributes: []string{"k8s.node.name"}},
+			attrs:    map[string]any{"node": "node-1", "host.name": "host"},
+			expected: map[string]any{"k8s.node.name": "node-1", "host.name": "host", "datadog.host.name": "node-1"},
+		},
+		{
+			name:     "no hostname attribute",
+			cfg:      Config{HostnameAttributes: []string{"k8s.node.name"}},
+			attrs:    map[string]any{"host.name": "host"},
+			expected: map[string]any{"host.name": "host"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ld := plog.NewLogs()
+			resource := ld.ResourceLogs().AppendEmpty().Resource()
+			require.NoError(t, resource.Attributes().FromRaw(tt.attrs))
+
+			_, err := newMapper(&tt.cfg).processLogs(context.Background(), ld)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resource.Attributes().AsRaw())
+		})
+	}
+}
+
+func TestProcessSignals(t *testing.T) {
+	m := newMapper(&Config{Rename: map[string]string{"app": "service.name"}})
+
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().Resource().Attributes().PutStr("app", "web")
+	md, err := m.processMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"service.name": "web"}, md.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().Resource().Attributes().PutStr("app", "web")
+	td, err = m.processTraces(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"service.name": "web"}, td.ResourceSpans().At(0).Resource().Attributes().AsRaw())
+}
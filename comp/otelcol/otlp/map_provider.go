@@ -22,11 +22,31 @@ func buildKey(keys ...string) string {
 	return strings.Join(keys, confmap.KeyDelimiter)
 }
 
+// addResourceMapping prepends the resourcemapping processor to the processors of
+// the given pipeline when the resource attributes are remapped.
+func addResourceMapping(baseMap *confmap.Conf, cfg PipelineConfig, pipeline string) error {
+	if len(cfg.ResourceMapping) == 0 {
+		return nil
+	}
+	key := buildKey("service", "pipelines", pipeline, "processors")
+	processors := []interface{}{"resourcemapping"}
+	if v, ok := baseMap.Get(key).([]interface{}); ok {
+		processors = append(processors, v...)
+	}
+	return baseMap.Merge(confmap.NewFromStringMap(map[string]interface{}{
+		buildKey("processors", "resourcemapping"): cfg.ResourceMapping,
+		key: processors,
+	}))
+}
+
 func buildTracesMap(cfg PipelineConfig) (*confmap.Conf, error) {
 	baseMap, err := configutils.NewMapFromYAMLString(defaultTracesConfig)
 	if err != nil {
 		return nil, err
 	}
+	if err = addResourceMapping(baseMap, cfg, "traces"); err != nil {
+		return nil, err
+	}
 	smap := map[string]interface{}{
 		buildKey("exporters", "otlp", "endpoint"): fmt.Sprintf("%s:%d", "localhost", cfg.TracePort),
 	}
@@ -42,6 +62,9 @@ func buildMetricsMap(cfg PipelineConfig) (*confmap.Conf, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err = addResourceMapping(baseMap, cfg, "metrics"); err != nil {
+		return nil, err
+	}
 	smap := map[string]interface{}{
 		buildKey("exporters", "serializer", "metrics"): cfg.Metrics,
 	}
@@ -52,11 +75,21 @@ func buildMetricsMap(cfg PipelineConfig) (*confmap.Conf, error) {
 	return baseMap, err
 }
 
-func buildLogsMap(_ PipelineConfig) (*confmap.Conf, error) {
+func buildLogsMap(cfg PipelineConfig) (*confmap.Conf, error) {
 	baseMap, err := configutils.NewMapFromYAMLString(defaultLogsConfig)
 	if err != nil {
 		return nil, err
 	}
+	if err = addResourceMapping(baseMap, cfg, "logs"); err != nil {
+		return nil, err
+	}
+	if len(cfg.Logs) > 0 {
+		smap := map[string]interface{}{
+			buildKey("exporters", "logsagent"): cfg.Logs,
+		}
+		configMap := confmap.NewFromStringMap(smap)
+		err = baseMap.Merge(configMap)
+	}
 	return baseMap, err
 }
 
@@ -563,6 +563,73 @@ func TestNewMap(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "only gRPC, traces and logs, resource mapping",
+			pcfg: PipelineConfig{
+				OTLPReceiverConfig: testutil.OTLPConfigFromPorts("bindhost", 1234, 0),
+				TracePort:          5003,
+				TracesEnabled:      true,
+				LogsEnabled:        true,
+				ResourceMapping: map[string]interface{}{
+					"rename":              map[string]interface{}{"app": "service.name"},
+					"drop":                []string{"process.command_line"},
+					"hostname_attributes": []string{"k8s.node.name", "host.name"},
+				},
+				Debug: map[string]interface{}{
+					"verbosity": "none",
+				},
+			},
+			ocfg: map[string]interface{}{
+				"receivers": map[string]interface{}{
+					"otlp": map[string]interface{}{
+						"protocols": map[string]interface{}{
+							"grpc": map[string]interface{}{
+								"endpoint": "bindhost:1234",
+							},
+						},
+					},
+				},
+				"exporters": map[string]interface{}{
+					"otlp": map[string]interface{}{
+						"tls": map[string]interface{}{
+							"insecure": true,
+						},
+						"compression": "none",
+						"endpoint":    "localhost:5003",
+						"sending_queue": map[string]interface{}{
+							"enabled": false,
+						},
+					},
+					"logsagent": interface{}(nil),
+				},
+				"processors": map[string]interface{}{
+					"infraattributes": interface{}(nil),
+					"batch": map[string]interface{}{
+						"timeout": "10s",
+					},
+					"resourcemapping": map[string]interface{}{
+						"rename":              map[string]interface{}{"app": "service.name"},
+						"drop":                []string{"process.command_line"},
+						"hostname_attributes": []string{"k8s.node.name", "host.name"},
+					},
+				},
+				"service": map[string]interface{}{
+					"telemetry": map[string]interface{}{"metrics": map[string]interface{}{"level": "none"}},
+					"pipelines": map[string]interface{}{
+						"traces": map[string]interface{}{
+							"receivers":  []interface{}{"otlp"},
+							"processors": []interface{}{"resourcemapping"},
+							"exporters":  []interface{}{"otlp"},
+						},
+						"logs": map[string]interface{}{
+							"receivers":  []interface{}{"otlp"},
+							"processors": []interface{}{"resourcemapping", "infraattributes", "batch"},
+							"exporters":  []interface{}{"logsagent"},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "only HTTP; metrics, logs and traces",
 			pcfg: PipelineConfig{
@@ -16,7 +16,9 @@ import (
 	gpusubscriber "github.com/DataDog/datadog-agent/comp/process/gpusubscriber/def"
 	"github.com/DataDog/datadog-agent/comp/process/processcheck"
 	"github.com/DataDog/datadog-agent/comp/process/types"
+	rctypes "github.com/DataDog/datadog-agent/comp/remote-config/rcclient/types"
 	"github.com/DataDog/datadog-agent/pkg/process/checks"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
 )
 
@@ -45,8 +47,9 @@ type dependencies struct {
 type result struct {
 	fx.Out
 
-	Check     types.ProvidesCheck
-	Component processcheck.Component
+	Check      types.ProvidesCheck
+	Component  processcheck.Component
+	RCListener rctypes.ListenerProvider
 }
 
 func newCheck(deps dependencies) result {
@@ -58,6 +61,11 @@ func newCheck(deps dependencies) result {
 			CheckComponent: c,
 		},
 		Component: c,
+		RCListener: rctypes.ListenerProvider{
+			ListenerProvider: rctypes.RCListener{
+				state.ProductProcessScrubbingRules: c.processCheck.OnScrubbingRulesUpdate,
+			},
+		},
 	}
 }
 
@@ -12,7 +12,9 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/config"
 	"github.com/DataDog/datadog-agent/comp/process/processdiscoverycheck"
 	"github.com/DataDog/datadog-agent/comp/process/types"
+	rctypes "github.com/DataDog/datadog-agent/comp/remote-config/rcclient/types"
 	"github.com/DataDog/datadog-agent/pkg/process/checks"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
 )
 
@@ -37,8 +39,9 @@ type dependencies struct {
 type result struct {
 	fx.Out
 
-	Check     types.ProvidesCheck
-	Component processdiscoverycheck.Component
+	Check      types.ProvidesCheck
+	Component  processdiscoverycheck.Component
+	RCListener rctypes.ListenerProvider
 }
 
 func newCheck(deps dependencies) result {
@@ -50,6 +53,11 @@ func newCheck(deps dependencies) result {
 			CheckComponent: c,
 		},
 		Component: c,
+		RCListener: rctypes.ListenerProvider{
+			ListenerProvider: rctypes.RCListener{
+				state.ProductProcessScrubbingRules: c.processDiscoveryCheck.OnScrubbingRulesUpdate,
+			},
+		},
 	}
 }
 
@@ -8,7 +8,6 @@ package profilerimpl
 
 import (
 	"context"
-	"fmt"
 
 	"go.uber.org/fx"
 
@@ -66,18 +65,19 @@ func newProfiler(deps dependencies) profilecomp.Component {
 }
 
 func getProfilingSettings(cfg config.Component) profiling.Settings {
-	// allow full url override for development use
-	site := cfg.GetString("internal_profiling.profile_dd_url")
-	if site == "" {
-		s := cfg.GetString("site")
-		if s == "" {
-			s = pkgconfigsetup.DefaultSite
-		}
-		site = fmt.Sprintf(profiling.ProfilingURLTemplate, s)
+	s := cfg.GetString("site")
+	if s == "" {
+		s = pkgconfigsetup.DefaultSite
 	}
+	// allow full url override for development use
+	site := profiling.IntakeURL(
+		cfg.GetString("internal_profiling.profile_dd_url"),
+		cfg.GetBool("internal_profiling.use_trace_agent_proxy"),
+		cfg.GetInt("apm_config.receiver_port"),
+		s,
+	)
 
-	tags := cfg.GetStringSlice("internal_profiling.extra_tags")
-	tags = append(tags, fmt.Sprintf("version:%v", version.AgentVersion))
+	tags := profiling.Tags(cfg.GetStringSlice("internal_profiling.extra_tags"), "process-agent", version.AgentVersion)
 
 	return profiling.Settings{
 		ProfilingURL:         site,
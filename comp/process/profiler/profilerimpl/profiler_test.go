@@ -0,0 +1,44 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package profilerimpl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/pkg/version"
+)
+
+func TestGetProfilingSettings(t *testing.T) {
+	cfg := config.NewMock(t)
+	cfg.SetWithoutSource("site", "datadoghq.eu")
+	cfg.SetWithoutSource("internal_profiling.extra_tags", []string{"team:processes"})
+
+	settings := getProfilingSettings(cfg)
+	assert.Equal(t, "https://intake.profile.datadoghq.eu/v1/input", settings.ProfilingURL)
+	assert.Equal(t, "process-agent", settings.Service)
+	assert.Equal(t, []string{"team:processes", "version:" + version.AgentVersion, "subsystem:process-agent"}, settings.Tags)
+}
+
+func TestGetProfilingSettingsTraceAgentProxy(t *testing.T) {
+	cfg := config.NewMock(t)
+	cfg.SetWithoutSource("internal_profiling.use_trace_agent_proxy", true)
+	cfg.SetWithoutSource("apm_config.receiver_port", 8127)
+
+	assert.Equal(t, "http://localhost:8127/profiling/v1/input", getProfilingSettings(cfg).ProfilingURL)
+
+	// the trace-agent receiver is disabled, the profiles are sent to the intake
+	cfg.SetWithoutSource("apm_config.receiver_port", 0)
+	assert.Equal(t, "https://intake.profile.datadoghq.com/v1/input", getProfilingSettings(cfg).ProfilingURL)
+
+	// an explicit URL takes precedence over the trace-agent proxy
+	cfg.SetWithoutSource("internal_profiling.profile_dd_url", "https://profiling.example.com/v1/input")
+	assert.Equal(t, "https://profiling.example.com/v1/input", getProfilingSettings(cfg).ProfilingURL)
+}
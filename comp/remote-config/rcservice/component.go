@@ -8,6 +8,7 @@ package rcservice
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/DataDog/datadog-agent/pkg/config/remote/service"
 	pbgo "github.com/DataDog/datadog-agent/pkg/proto/pbgo/core"
@@ -27,4 +28,6 @@ type Component interface {
 	ClientGetConfigs(_ context.Context, request *pbgo.ClientGetConfigsRequest) (*pbgo.ClientGetConfigsResponse, error)
 	// ConfigGetState returns the state of the configuration and the director repos in the local store
 	ConfigGetState() (*pbgo.GetStateConfigResponse, error)
+	// ServeMirror serves the Remote Configuration backend API to downstream agents using this agent as their mirror
+	ServeMirror(w http.ResponseWriter, r *http.Request)
 }
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"time"
 
+	api "github.com/DataDog/datadog-agent/comp/api/api/def"
 	cfgcomp "github.com/DataDog/datadog-agent/comp/core/config"
 	"github.com/DataDog/datadog-agent/comp/core/hostname"
 	log "github.com/DataDog/datadog-agent/comp/core/log/def"
@@ -46,9 +47,16 @@ type dependencies struct {
 	Logger                log.Component
 }
 
+type provides struct {
+	fx.Out
+
+	Comp     option.Option[rcservice.Component]
+	Endpoint api.AgentEndpointProvider
+}
+
 // newRemoteConfigServiceOptional conditionally creates and configures a new remote config service, based on whether RC is enabled.
-func newRemoteConfigServiceOptional(deps dependencies) option.Option[rcservice.Component] {
-	none := option.None[rcservice.Component]()
+func newRemoteConfigServiceOptional(deps dependencies) provides {
+	none := provides{Comp: option.None[rcservice.Component]()}
 	if !pkgconfigsetup.IsRemoteConfigEnabled(deps.Cfg) {
 		return none
 	}
@@ -59,7 +67,11 @@ func newRemoteConfigServiceOptional(deps dependencies) option.Option[rcservice.C
 		return none
 	}
 
-	return option.New[rcservice.Component](configService)
+	p := provides{Comp: option.New[rcservice.Component](configService)}
+	if deps.Cfg.GetBool("remote_configuration.mirror.enabled") {
+		p.Endpoint = api.NewAgentEndpointProvider(configService.ServeMirror, "/remote-config/mirror/api/v0.1/{endpoint}", "GET", "POST")
+	}
+	return p
 }
 
 // newRemoteConfigServiceOptional creates and configures a new remote config service
@@ -94,6 +106,14 @@ func newRemoteConfigService(deps dependencies) (rcservice.Component, error) {
 	if deps.Cfg.IsSet("remote_configuration.clients.cache_bypass_limit") {
 		options = append(options, remoteconfig.WithClientCacheBypassLimit(deps.Cfg.GetInt("remote_configuration.clients.cache_bypass_limit"), "remote_configuration.clients.cache_bypass_limit"))
 	}
+	if mirrorURL := deps.Cfg.GetString("remote_configuration.mirror.url"); mirrorURL != "" {
+		authToken := deps.Cfg.GetString("remote_configuration.mirror.auth_token")
+		if authToken == "" {
+			// agents running next to a cluster-agent mirror already share its token
+			authToken = deps.Cfg.GetString("cluster_agent.auth_token")
+		}
+		options = append(options, remoteconfig.WithMirror(mirrorURL, authToken))
+	}
 
 	configService, err := remoteconfig.NewService(
 		deps.Cfg,
@@ -129,7 +129,7 @@ func NewAgent(deps dependencies) (traceagent.Component, error) {
 		at:                 deps.At,
 		wg:                 &sync.WaitGroup{},
 	}
-	statsdCl, err := setupMetrics(deps.Statsd, c.config, c.telemetryCollector)
+	statsdCl, listenerStatsdCl, err := setupMetrics(deps.Statsd, c.config, c.telemetryCollector)
 	if err != nil {
 		return nil, err
 	}
@@ -144,6 +144,7 @@ func NewAgent(deps dependencies) (traceagent.Component, error) {
 		statsdCl,
 		deps.Compressor,
 	)
+	c.Agent.Receiver.ListenerStatsd = listenerStatsdCl
 
 	c.config.OnUpdateAPIKey(c.UpdateAPIKey)
 
@@ -230,24 +231,31 @@ func start(ag component) error {
 	return nil
 }
 
-func setupMetrics(statsd statsd.Component, cfg config.Component, telemetryCollector telemetry.TelemetryCollector) (ddgostatsd.ClientInterface, error) {
+func setupMetrics(statsd statsd.Component, cfg config.Component, telemetryCollector telemetry.TelemetryCollector) (ddgostatsd.ClientInterface, ddgostatsd.ClientInterface, error) {
 	addr, err := findAddr(cfg.Object())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	tags := ddgostatsd.WithTags([]string{"version:" + version.AgentVersion})
+	client, err := statsd.CreateForComponent("trace-agent", addr, tags)
+	if err != nil {
+		telemetryCollector.SendStartupError(telemetry.CantConfigureDogstatsd, err)
+		return nil, nil, fmt.Errorf("cannot configure dogstatsd: %v", err)
 	}
 
-	// TODO: Try to use statsd.Get() everywhere instead in the long run.
-	client, err := statsd.CreateForAddr(addr, ddgostatsd.WithTags([]string{"version:" + version.AgentVersion}))
+	// The connection metrics of the API listeners are emitted by their own client.
+	listenerClient, err := statsd.CreateForComponent("trace-agent-listeners", addr, tags)
 	if err != nil {
 		telemetryCollector.SendStartupError(telemetry.CantConfigureDogstatsd, err)
-		return nil, fmt.Errorf("cannot configure dogstatsd: %v", err)
+		return nil, nil, fmt.Errorf("cannot configure dogstatsd: %v", err)
 	}
 
 	err = client.Count("datadog.trace_agent.started", 1, nil, 1)
 	if err != nil {
 		log.Error("Failed to emit datadog.trace_agent.started metric: ", err)
 	}
-	return client, nil
+	return client, listenerClient, nil
 }
 
 func stop(ag component) error {
@@ -256,6 +264,9 @@ func stop(ag component) error {
 	if err := ag.Statsd.Flush(); err != nil {
 		log.Error("Could not flush statsd: ", err)
 	}
+	if err := ag.Agent.Receiver.ListenerStatsd.Flush(); err != nil {
+		log.Error("Could not flush the statsd client of the listeners: ", err)
+	}
 	stopAgentSidekicks(ag.config, ag.Statsd)
 	if ag.params.CPUProfile != "" {
 		pprof.StopCPUProfile()
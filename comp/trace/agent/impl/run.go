@@ -18,6 +18,7 @@ import (
 	apiutil "github.com/DataDog/datadog-agent/pkg/api/util"
 	rc "github.com/DataDog/datadog-agent/pkg/config/remote/client"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/fips"
 	"github.com/DataDog/datadog-agent/pkg/trace/api"
 	tracecfg "github.com/DataDog/datadog-agent/pkg/trace/config"
 	"github.com/DataDog/datadog-agent/pkg/trace/info"
@@ -42,6 +43,13 @@ func runAgentSidekicks(ag component) error {
 		return err
 	}
 
+	// In FIPS mode, refuse to run if the trace proxies or the debug server would use non FIPS-approved crypto
+	fips.RegisterTLSConfig("trace_proxies", tracecfg.NewHTTPTransport().TLSClientConfig)
+	fips.RegisterTLSConfig("trace_debug_server", ag.at.GetTLSServerConfig())
+	if err := fips.Verify(); err != nil {
+		return err
+	}
+
 	defer watchdog.LogOnPanic(ag.Statsd)
 
 	if err := coredump.Setup(pkgconfigsetup.Datadog()); err != nil {
@@ -140,8 +148,7 @@ func profilingConfig(tracecfg *tracecfg.AgentConfig) *profiling.Settings {
 	if endpoint == "" {
 		endpoint = fmt.Sprintf(profiling.ProfilingURLTemplate, tracecfg.Site)
 	}
-	tags := pkgconfigsetup.Datadog().GetStringSlice("internal_profiling.extra_tags")
-	tags = append(tags, fmt.Sprintf("version:%s", version.AgentVersion))
+	tags := profiling.Tags(pkgconfigsetup.Datadog().GetStringSlice("internal_profiling.extra_tags"), "trace-agent", version.AgentVersion)
 	return &profiling.Settings{
 		ProfilingURL: endpoint,
 
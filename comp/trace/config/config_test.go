@@ -62,6 +62,35 @@ func TestParseRepaceRules(t *testing.T) {
 	}
 }
 
+// TestValidateIngestionQuotas tests the validateIngestionQuotas helper function.
+func TestValidateIngestionQuotas(t *testing.T) {
+	quotas := []*traceconfig.IngestionQuota{
+		{Env: "Prod", MaxSpans: 1000},
+		{Env: "prod", Service: "web", Period: "monthly", MaxBytes: 1 << 30, Spillover: "tag"},
+	}
+	require.NoError(t, validateIngestionQuotas(quotas))
+	assert.Equal(t, &traceconfig.IngestionQuota{Env: "prod", Period: "daily", MaxSpans: 1000, Spillover: "downsample"}, quotas[0])
+	assert.Equal(t, "monthly", quotas[1].Period)
+
+	for name, quota := range map[string]*traceconfig.IngestionQuota{
+		"no-env":      {MaxSpans: 1},
+		"no-limit":    {Env: "prod"},
+		"bad-period":  {Env: "prod", MaxSpans: 1, Period: "weekly"},
+		"bad-action":  {Env: "prod", MaxSpans: 1, Spillover: "drop"},
+		"bad-rate":    {Env: "prod", MaxSpans: 1, SpilloverSampleRate: 2},
+		"duplicate":   {Env: "prod", MaxSpans: 1},
+		"normal-case": {Env: "PROD", MaxSpans: 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			quotas := []*traceconfig.IngestionQuota{quota}
+			if name == "duplicate" || name == "normal-case" {
+				quotas = append(quotas, &traceconfig.IngestionQuota{Env: "prod", MaxSpans: 1})
+			}
+			assert.Error(t, validateIngestionQuotas(quotas))
+		})
+	}
+}
+
 // TestSplitTag tests various split-tagging scenarios
 func TestSplitTag(t *testing.T) {
 	for _, tt := range []struct {
@@ -574,6 +603,7 @@ func TestFullYamlConfig(t *testing.T) {
 	assert.Equal(t, "0.0.0.0", cfg.ReceiverHost)
 	assert.True(t, cfg.OTLPReceiver.SpanNameAsResourceName)
 	assert.False(t, cfg.OTLPReceiver.IgnoreMissingDatadogFields)
+	assert.True(t, cfg.OTLPReceiver.SamplingParity)
 	assert.Equal(t, map[string]string{"a": "b", "and:colons": "in:values", "c": "d", "with.dots": "in.side"}, cfg.OTLPReceiver.SpanNameRemappings)
 
 	noProxy := true
@@ -1038,6 +1068,57 @@ func TestLoadEnv(t *testing.T) {
 		assert.Contains(t, cfg.ReplaceTags, rule2)
 	})
 
+	env = "DD_APM_INGESTION_QUOTAS"
+	t.Run(env, func(t *testing.T) {
+		t.Setenv(env, `[{"env":"prod","max_spans":1000000},{"env":"prod","service":"web","period":"monthly","max_bytes":1000,"spillover":"tag"}]`)
+
+		c := buildConfigComponent(t, true, fx.Replace(corecomp.MockParams{
+			Params: corecomp.Params{ConfFilePath: "./testdata/full.yaml"},
+		}))
+
+		cfg := c.Object()
+
+		assert.NotNil(t, cfg)
+		assert.Equal(t, []*traceconfig.IngestionQuota{
+			{Env: "prod", Period: "daily", MaxSpans: 1000000, Spillover: "downsample"},
+			{Env: "prod", Service: "web", Period: "monthly", MaxBytes: 1000, Spillover: "tag"},
+		}, cfg.IngestionQuotas)
+	})
+
+	env = "DD_APM_SAMPLER_STATE_ENABLED"
+	t.Run(env, func(t *testing.T) {
+		t.Setenv(env, "true")
+		t.Setenv("DD_APM_SAMPLER_STATE_INTERVAL", "30s")
+
+		c := buildConfigComponent(t, true, fx.Replace(corecomp.MockParams{
+			Params: corecomp.Params{ConfFilePath: "./testdata/full.yaml"},
+		}))
+
+		cfg := c.Object()
+
+		assert.NotNil(t, cfg)
+		assert.True(t, cfg.SamplerStateEnabled)
+		assert.Equal(t, "trace_sampler_state.json", filepath.Base(cfg.SamplerStatePath))
+		assert.Equal(t, 30*time.Second, cfg.SamplerStateInterval)
+		assert.Equal(t, 10*time.Minute, cfg.SamplerStateMaxAge)
+	})
+
+	env = "DD_APM_ERROR_FINGERPRINTS_ENABLED"
+	t.Run(env, func(t *testing.T) {
+		t.Setenv(env, "true")
+		t.Setenv("DD_APM_ERROR_FINGERPRINTS_STACK_FRAMES", "3")
+
+		c := buildConfigComponent(t, true, fx.Replace(corecomp.MockParams{
+			Params: corecomp.Params{ConfFilePath: "./testdata/full.yaml"},
+		}))
+
+		cfg := c.Object()
+
+		assert.NotNil(t, cfg)
+		assert.True(t, cfg.ErrorFingerprintsEnabled)
+		assert.Equal(t, 3, cfg.ErrorFingerprintStackFrames)
+	})
+
 	env = "DD_APM_FILTER_TAGS_REQUIRE"
 	t.Run(env, func(t *testing.T) {
 		t.Setenv(env, `important1 important2:value1`)
@@ -1338,6 +1419,22 @@ func TestLoadEnv(t *testing.T) {
 		assert.Equal(t, "my-key", pkgconfigsetup.Datadog().GetString("apm_config.debugger_api_key"))
 	})
 
+	env = "DD_APM_DEBUGGER_DEDUP_WINDOW"
+	t.Run(env, func(t *testing.T) {
+		t.Setenv(env, "5s")
+		t.Setenv("DD_APM_DEBUGGER_PROBE_RATE_LIMIT", "10")
+
+		c := buildConfigComponent(t, true, fx.Replace(corecomp.MockParams{
+			Params: corecomp.Params{ConfFilePath: "./testdata/full.yaml"},
+		}))
+		cfg := c.Object()
+
+		assert.NotNil(t, cfg)
+
+		assert.Equal(t, 5*time.Second, cfg.DebuggerProxy.DedupWindow)
+		assert.Equal(t, 10.0, cfg.DebuggerProxy.ProbeRateLimit)
+	})
+
 	env = "DD_APM_DEBUGGER_ADDITIONAL_ENDPOINTS"
 	t.Run(env, func(t *testing.T) {
 		t.Setenv(env, `{"url1": ["key1", "key2"], "url2": ["key3"]}`)
@@ -2038,6 +2135,19 @@ func TestPeerTagsAggregation(t *testing.T) {
 		assert.Nil(t, cfg.ConfiguredPeerTags())
 	})
 
+	t.Run("default-disabled", func(t *testing.T) {
+		overrides := map[string]interface{}{
+			"apm_config.default_peer_tags_aggregation": false,
+			"apm_config.peer_tags":                     []string{"messaging.destination"},
+		}
+		config := buildConfigComponent(t, true, fx.Replace(corecomp.MockParams{Overrides: overrides}))
+		cfg := config.Object()
+		require.NotNil(t, cfg)
+		assert.True(t, cfg.PeerTagsAggregation)
+		assert.False(t, cfg.DefaultPeerTagsAggregation)
+		assert.Equal(t, []string{"_dd.base_service", "messaging.destination"}, cfg.ConfiguredPeerTags())
+	})
+
 	t.Run("deprecated-enabled", func(t *testing.T) {
 		overrides := map[string]interface{}{
 			"apm_config.peer_service_aggregation": true,
@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -33,6 +34,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/config/structure"
 	"github.com/DataDog/datadog-agent/pkg/config/utils"
 	"github.com/DataDog/datadog-agent/pkg/trace/config"
+	"github.com/DataDog/datadog-agent/pkg/trace/sampler"
 	"github.com/DataDog/datadog-agent/pkg/trace/traceutil"
 	"github.com/DataDog/datadog-agent/pkg/util/fargate"
 	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
@@ -110,7 +112,7 @@ func prepareConfig(c corecompcfg.Component, tagger tagger.Component) (*config.Ag
 
 	orch := fargate.GetOrchestrator() // Needs to be after loading config, because it relies on feature auto-detection
 	cfg.FargateOrchestrator = config.FargateOrchestratorName(orch)
-	if p := pkgconfigsetup.Datadog().GetProxies(); p != nil {
+	if p := httputils.GetDestinationConfig(coreConfigObject, httputils.APMDestination).Proxies; p != nil {
 		cfg.Proxy = httputils.GetProxyTransportFunc(p, c)
 	}
 	if pkgconfigsetup.IsRemoteConfigEnabled(coreConfigObject) && coreConfigObject.GetBool("remote_configuration.apm_sampling.enabled") {
@@ -130,7 +132,7 @@ func prepareConfig(c corecompcfg.Component, tagger tagger.Component) (*config.Ag
 	cfg.ContainerProcRoot = coreConfigObject.GetString("container_proc_root")
 	cfg.GetAgentAuthToken = apiutil.GetAuthToken
 	cfg.HTTPTransportFunc = func() *http.Transport {
-		return httputils.CreateHTTPTransport(coreConfigObject)
+		return httputils.NewTransport(coreConfigObject, httputils.APMDestination)
 	}
 
 	cfg.IsMRFEnabled = func() bool {
@@ -272,6 +274,10 @@ func applyDatadogConfig(c *config.AgentConfig, core corecompcfg.Component) error
 	if core.IsSet("apm_config.peer_tags") {
 		c.PeerTags = core.GetStringSlice("apm_config.peer_tags")
 	}
+	c.DefaultPeerTagsAggregation = core.GetBool("apm_config.default_peer_tags_aggregation")
+	if c.PeerTagsAggregation && !c.DefaultPeerTagsAggregation {
+		log.Infof("default peer tags aggregation is disabled, stats are only aggregated on the peer tags of `apm_config.peer_tags`: %v", c.PeerTags)
+	}
 
 	if core.IsSet("apm_config.extra_sample_rate") {
 		c.ExtraSampleRate = core.GetFloat64("apm_config.extra_sample_rate")
@@ -301,6 +307,19 @@ func applyDatadogConfig(c *config.AgentConfig, core corecompcfg.Component) error
 	if core.IsSet("apm_config.rare_sampler.cardinality") {
 		c.RareSamplerCardinality = core.GetInt("apm_config.rare_sampler.cardinality")
 	}
+	if core.GetBool("apm_config.sampler_state.enabled") {
+		c.SamplerStateEnabled = true
+		c.SamplerStatePath = core.GetString("apm_config.sampler_state.path")
+		if c.SamplerStatePath == "" {
+			c.SamplerStatePath = filepath.Join(core.GetString("run_path"), "trace_sampler_state.json")
+		}
+		if d := core.GetDuration("apm_config.sampler_state.interval"); d > 0 {
+			c.SamplerStateInterval = d
+		}
+		if d := core.GetDuration("apm_config.sampler_state.max_age"); d > 0 {
+			c.SamplerStateMaxAge = d
+		}
+	}
 
 	if core.IsSet("apm_config.probabilistic_sampler.enabled") {
 		c.ProbabilisticSamplerEnabled = core.GetBool("apm_config.probabilistic_sampler.enabled")
@@ -315,6 +334,24 @@ func applyDatadogConfig(c *config.AgentConfig, core corecompcfg.Component) error
 	if core.IsSet("apm_config.error_tracking_standalone.enabled") {
 		c.ErrorTrackingStandalone = core.GetBool("apm_config.error_tracking_standalone.enabled")
 	}
+	if core.GetBool("apm_config.error_fingerprints.enabled") {
+		c.ErrorFingerprintsEnabled = true
+		if frames := core.GetInt("apm_config.error_fingerprints.stack_frames"); frames > 0 {
+			c.ErrorFingerprintStackFrames = frames
+		}
+	}
+
+	if k := "apm_config.ingestion_quotas"; core.IsSet(k) {
+		quotas := make([]*config.IngestionQuota, 0)
+		if err := structure.UnmarshalKey(core, k, &quotas); err != nil {
+			log.Errorf("Bad format for %q it should be of the form '[{\"env\": \"env_name\",\"period\":\"daily\",\"max_spans\":1000000}]', error: %v", k, err)
+		} else {
+			if err := validateIngestionQuotas(quotas); err != nil {
+				return fmt.Errorf("ingestion_quotas: %s", err)
+			}
+			c.IngestionQuotas = quotas
+		}
+	}
 
 	if core.IsSet("apm_config.max_remote_traces_per_second") {
 		c.MaxRemoteTPS = core.GetFloat64("apm_config.max_remote_traces_per_second")
@@ -408,6 +445,7 @@ func applyDatadogConfig(c *config.AgentConfig, core corecompcfg.Component) error
 		SpanNameAsResourceName:     core.GetBool("otlp_config.traces.span_name_as_resource_name"),
 		IgnoreMissingDatadogFields: core.GetBool("otlp_config.traces.ignore_missing_datadog_fields"),
 		ProbabilisticSampling:      core.GetFloat64("otlp_config.traces.probabilistic_sampler.sampling_percentage"),
+		SamplingParity:             core.GetBool("otlp_config.traces.sampling_parity"),
 		AttributesTranslator:       attributesTranslator,
 	}
 
@@ -595,6 +633,8 @@ func applyDatadogConfig(c *config.AgentConfig, core corecompcfg.Component) error
 	if k := "apm_config.debugger_additional_endpoints"; core.IsSet(k) {
 		c.DebuggerProxy.AdditionalEndpoints = core.GetStringMapStringSlice(k)
 	}
+	c.DebuggerProxy.DedupWindow = core.GetDuration("apm_config.debugger_dedup_window")
+	c.DebuggerProxy.ProbeRateLimit = core.GetFloat64("apm_config.debugger_probe_rate_limit")
 	if k := "apm_config.debugger_diagnostics_dd_url"; core.IsSet(k) {
 		c.DebuggerDiagnosticsProxy.DDURL = core.GetString(k)
 	}
@@ -669,6 +709,9 @@ func loadDeprecatedValues(c *config.AgentConfig) error {
 	if cfg.IsSet("apm_config.receiver_timeout") {
 		c.ReceiverTimeout = cfg.GetInt("apm_config.receiver_timeout")
 	}
+	if cfg.IsSet("apm_config.drain_timeout") {
+		c.DrainTimeout = cfg.GetInt("apm_config.drain_timeout")
+	}
 	if cfg.IsSet("apm_config.watchdog_check_delay") {
 		d := time.Duration(cfg.GetInt("apm_config.watchdog_check_delay"))
 		c.WatchdogInterval = d * time.Second
@@ -718,6 +761,44 @@ func compileReplaceRules(rules []*config.ReplaceRule) error {
 	return nil
 }
 
+// validateIngestionQuotas checks the ingestion quotas and sets their defaults.
+// If it fails it returns the first error.
+func validateIngestionQuotas(quotas []*config.IngestionQuota) error {
+	seen := make(map[[2]string]struct{}, len(quotas))
+	for _, q := range quotas {
+		if q.Env == "" {
+			return errors.New(`all quotas must have an "env" property`)
+		}
+		q.Env = traceutil.NormalizeTagValue(q.Env)
+		key := [2]string{q.Env, q.Service}
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("duplicate quota for env %q and service %q", q.Env, q.Service)
+		}
+		seen[key] = struct{}{}
+		switch q.Period {
+		case "":
+			q.Period = sampler.QuotaPeriodDaily
+		case sampler.QuotaPeriodDaily, sampler.QuotaPeriodMonthly:
+		default:
+			return fmt.Errorf("env %q: unknown period %q, expected %q or %q", q.Env, q.Period, sampler.QuotaPeriodDaily, sampler.QuotaPeriodMonthly)
+		}
+		if q.MaxSpans <= 0 && q.MaxBytes <= 0 {
+			return fmt.Errorf(`env %q: at least one of "max_spans" or "max_bytes" must be set`, q.Env)
+		}
+		switch q.Spillover {
+		case "":
+			q.Spillover = sampler.QuotaSpilloverDownsample
+		case sampler.QuotaSpilloverDownsample, sampler.QuotaSpilloverTag:
+		default:
+			return fmt.Errorf("env %q: unknown spillover %q, expected %q or %q", q.Env, q.Spillover, sampler.QuotaSpilloverDownsample, sampler.QuotaSpilloverTag)
+		}
+		if q.SpilloverSampleRate < 0 || q.SpilloverSampleRate > 1 {
+			return fmt.Errorf(`env %q: "spillover_sample_rate" must be between 0 and 1`, q.Env)
+		}
+	}
+	return nil
+}
+
 // getDuration returns the duration of the provided value in seconds
 func getDuration(seconds int) time.Duration {
 	return time.Duration(seconds) * time.Second
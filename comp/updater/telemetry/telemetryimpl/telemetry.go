@@ -36,7 +36,7 @@ func Module() fxutil.Module {
 
 func newTelemetry(deps dependencies) (telemetry.Component, error) {
 	client := &http.Client{
-		Transport: httputils.CreateHTTPTransport(deps.Config),
+		Transport: httputils.NewTransport(deps.Config, httputils.AgentTelemetryDestination),
 	}
 	telemetry := fleettelemetry.NewTelemetry(client, utils.SanitizeAPIKey(deps.Config.GetString("api_key")), deps.Config.GetString("site"), "datadog-installer-daemon")
 	deps.Lc.Append(fx.Hook{OnStop: func(context.Context) error { telemetry.Stop(); return nil }})
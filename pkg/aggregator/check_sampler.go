@@ -48,6 +48,10 @@ func newCheckSampler(expirationCount int, expireMetrics bool, contextResolverMet
 }
 
 func (cs *CheckSampler) addSample(metricSample *metrics.MetricSample) {
+	if currentMetricTransformer().dropped(metricSample.Name) {
+		return
+	}
+
 	contextKey := cs.contextResolver.trackContext(metricSample)
 
 	if metricSample.Mtype == metrics.DistributionType {
@@ -75,6 +79,10 @@ func (cs *CheckSampler) newSketchSeries(ck ckey.ContextKey, points []metrics.Ske
 }
 
 func (cs *CheckSampler) addBucket(bucket *metrics.HistogramBucket) {
+	if currentMetricTransformer().dropped(bucket.Name) {
+		return
+	}
+
 	if bucket.Value < 0 {
 		log.Warnf("Negative bucket value %d for metric %s discarding", bucket.Value, bucket.Name)
 		return
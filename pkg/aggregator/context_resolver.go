@@ -78,9 +78,9 @@ type contextResolver struct {
 	metricBuffer     *tagset.HashingTagsAccumulator
 }
 
-// generateContextKey generates the contextKey associated with the context of the metricSample
-func (cr *contextResolver) generateContextKey(metricSampleContext metrics.MetricSampleContext) (ckey.ContextKey, ckey.TagsKey, ckey.TagsKey) {
-	return cr.keyGenerator.GenerateWithTags2(metricSampleContext.GetName(), metricSampleContext.GetHost(), cr.taggerBuffer, cr.metricBuffer)
+// generateContextKey generates the contextKey associated with the name, the host and the tags in the buffers
+func (cr *contextResolver) generateContextKey(name, host string) (ckey.ContextKey, ckey.TagsKey, ckey.TagsKey) {
+	return cr.keyGenerator.GenerateWithTags2(name, host, cr.taggerBuffer, cr.metricBuffer)
 }
 
 func newContextResolver(tagger tagger.Component, cache *tags.Store, id string) *contextResolver {
@@ -99,18 +99,25 @@ func newContextResolver(tagger tagger.Component, cache *tags.Store, id string) *
 	}
 }
 
-// trackContext returns the contextKey associated with the context of the metricSample and tracks that context
+// trackContext returns the contextKey associated with the context of the metricSample and tracks that context.
+// The metric transformation rules renaming the metric or removing tags are applied to the context.
 func (cr *contextResolver) trackContext(metricSampleContext metrics.MetricSampleContext, timestamp int64) ckey.ContextKey {
 	metricSampleContext.GetTags(cr.taggerBuffer, cr.metricBuffer, cr.tagger.EnrichTags) // tags here are not sorted and can contain duplicates
 	defer cr.taggerBuffer.Reset()
 	defer cr.metricBuffer.Reset()
 
-	contextKey, taggerKey, metricKey := cr.generateContextKey(metricSampleContext) // the generator will remove duplicates (and doesn't mind the order)
+	name, strippedTagKeys := currentMetricTransformer().transform(metricSampleContext.GetName())
+	if strippedTagKeys != nil {
+		stripTags(cr.taggerBuffer, strippedTagKeys)
+		stripTags(cr.metricBuffer, strippedTagKeys)
+	}
+
+	contextKey, taggerKey, metricKey := cr.generateContextKey(name, metricSampleContext.GetHost()) // the generator will remove duplicates (and doesn't mind the order)
 
 	if entry, ok := cr.contextsByKey[contextKey]; !ok {
 		mtype := metricSampleContext.GetMetricType()
 		context := &Context{
-			Name:       metricSampleContext.GetName(),
+			Name:       name,
 			taggerTags: cr.tagsCache.Insert(taggerKey, cr.taggerBuffer),
 			metricTags: cr.tagsCache.Insert(metricKey, cr.metricBuffer),
 			Host:       metricSampleContext.GetHost(),
@@ -151,6 +151,10 @@ func initAgentDemultiplexer(log log.Component,
 		pkgconfigsetup.Datadog().Set("telemetry.dogstatsd_origin", false, model.SourceAgentRuntime)
 	}
 
+	if err := loadMetricTransformations(pkgconfigsetup.Datadog()); err != nil {
+		log.Warnf("Error loading the metric transformations: %v", err)
+	}
+
 	// prepare the serializer
 	// ----------------------
 
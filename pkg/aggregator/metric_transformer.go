@@ -0,0 +1,262 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package aggregator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+	"github.com/DataDog/datadog-agent/pkg/config/structure"
+	"github.com/DataDog/datadog-agent/pkg/tagset"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+)
+
+const (
+	// MetricTransformationRename renames the matching metrics
+	MetricTransformationRename = "rename"
+	// MetricTransformationDrop drops the matching metrics
+	MetricTransformationDrop = "drop"
+	// MetricTransformationStripTags removes tags from the matching metrics, aggregating their contexts together
+	MetricTransformationStripTags = "strip_tags"
+)
+
+var (
+	tlmMetricTransformations = telemetry.NewCounter("aggregator", "metric_transformations",
+		[]string{"action"}, "Count the number of samples transformed by the metric transformation rules")
+	tlmMetricTransformationsRenamed  = tlmMetricTransformations.WithValues(MetricTransformationRename)
+	tlmMetricTransformationsDropped  = tlmMetricTransformations.WithValues(MetricTransformationDrop)
+	tlmMetricTransformationsStripped = tlmMetricTransformations.WithValues(MetricTransformationStripTags)
+)
+
+// MetricTransformation is a rule transforming the metrics before they are aggregated.
+//
+// Metrics are matched by their original name, a name ending with `*` matches all the
+// metrics starting with the rest of the name. Tags are matched by their key, `env`
+// matches both `env` and `env:prod`.
+type MetricTransformation struct {
+	Metrics []string `json:"metrics" yaml:"metrics" mapstructure:"metrics"`
+	Action  string   `json:"action" yaml:"action" mapstructure:"action"`
+	NewName string   `json:"new_name" yaml:"new_name" mapstructure:"new_name"`
+	Tags    []string `json:"tags" yaml:"tags" mapstructure:"tags"`
+}
+
+// Validate returns an error if the transformation cannot be applied
+func (t MetricTransformation) Validate() error {
+	if len(t.Metrics) == 0 {
+		return errors.New("no metric to transform")
+	}
+	for _, metric := range t.Metrics {
+		if metric == "" || metric == "*" {
+			return fmt.Errorf("invalid metric name %q", metric)
+		}
+	}
+
+	switch t.Action {
+	case MetricTransformationRename:
+		if t.NewName == "" {
+			return errors.New("the rename action requires a new_name")
+		}
+	case MetricTransformationDrop:
+	case MetricTransformationStripTags:
+		if len(t.Tags) == 0 {
+			return errors.New("the strip_tags action requires tags")
+		}
+	default:
+		return fmt.Errorf("unknown action %q", t.Action)
+	}
+	return nil
+}
+
+// compiledTransformation is a MetricTransformation indexed for the lookups done on each sample
+type compiledTransformation struct {
+	MetricTransformation
+	names    map[string]struct{}
+	prefixes []string
+}
+
+func (t *compiledTransformation) matches(name string) bool {
+	if _, found := t.names[name]; found {
+		return true
+	}
+	for _, prefix := range t.prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// metricTransformer applies the transformation rules, in order, to the metrics
+type metricTransformer struct {
+	transformations []compiledTransformation
+}
+
+func newMetricTransformer(transformations []MetricTransformation) *metricTransformer {
+	if len(transformations) == 0 {
+		return nil
+	}
+
+	t := &metricTransformer{}
+	for _, transformation := range transformations {
+		compiled := compiledTransformation{
+			MetricTransformation: transformation,
+			names:                map[string]struct{}{},
+		}
+		for _, metric := range transformation.Metrics {
+			if prefix, isPrefix := strings.CutSuffix(metric, "*"); isPrefix {
+				compiled.prefixes = append(compiled.prefixes, prefix)
+			} else {
+				compiled.names[metric] = struct{}{}
+			}
+		}
+		t.transformations = append(t.transformations, compiled)
+	}
+	return t
+}
+
+// dropped returns whether the metric must be dropped
+func (t *metricTransformer) dropped(name string) bool {
+	if t == nil {
+		return false
+	}
+	for i := range t.transformations {
+		if t.transformations[i].Action == MetricTransformationDrop && t.transformations[i].matches(name) {
+			tlmMetricTransformationsDropped.Inc()
+			return true
+		}
+	}
+	return false
+}
+
+// transform returns the name of the metric after the rename rules, the last matching rule
+// wins, and the keys of the tags to remove from it.
+func (t *metricTransformer) transform(name string) (string, []string) {
+	if t == nil {
+		return name, nil
+	}
+
+	newName := name
+	var stripped []string
+	for i := range t.transformations {
+		transformation := &t.transformations[i]
+		if !transformation.matches(name) {
+			continue
+		}
+		switch transformation.Action {
+		case MetricTransformationRename:
+			newName = transformation.NewName
+		case MetricTransformationStripTags:
+			if stripped == nil {
+				stripped = transformation.Tags
+			} else {
+				stripped = append(append([]string{}, stripped...), transformation.Tags...)
+			}
+		}
+	}
+
+	if newName != name {
+		tlmMetricTransformationsRenamed.Inc()
+	}
+	if stripped != nil {
+		tlmMetricTransformationsStripped.Inc()
+	}
+	return newName, stripped
+}
+
+// hasTagKey returns whether the tag has one of the keys
+func hasTagKey(tag string, keys []string) bool {
+	key, _, _ := strings.Cut(tag, ":")
+	for _, k := range keys {
+		if key == k {
+			return true
+		}
+	}
+	return false
+}
+
+// stripTags removes in place the tags having one of the keys from the accumulator
+func stripTags(tb *tagset.HashingTagsAccumulator, keys []string) {
+	tags, hashes := tb.Get(), tb.Hashes()
+	n := 0
+	for i := range tags {
+		if hasTagKey(tags[i], keys) {
+			continue
+		}
+		tags[n] = tags[i]
+		hashes[n] = hashes[i]
+		n++
+	}
+	tb.Truncate(n)
+}
+
+// strippedTags returns the tags not having one of the keys, reusing the slice
+func strippedTags(tags []string, keys []string) []string {
+	kept := tags[:0]
+	for _, tag := range tags {
+		if !hasTagKey(tag, keys) {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
+
+// metricTransformations holds the rules of the metric_transformations setting and the
+// ones received through remote config. The samplers only load the compiled transformer,
+// which is replaced when the rules change.
+var metricTransformations = struct {
+	sync.Mutex
+	local       []MetricTransformation
+	remote      []MetricTransformation
+	transformer atomic.Pointer[metricTransformer]
+}{}
+
+// currentMetricTransformer returns the transformer to apply to the samples, nil when no rule is set
+func currentMetricTransformer() *metricTransformer {
+	return metricTransformations.transformer.Load()
+}
+
+// storeMetricTransformer compiles the local rules followed by the remote ones, metricTransformations must be locked
+func storeMetricTransformer() {
+	rules := append(append([]MetricTransformation{}, metricTransformations.local...), metricTransformations.remote...)
+	metricTransformations.transformer.Store(newMetricTransformer(rules))
+}
+
+// loadMetricTransformations loads the rules of the metric_transformations setting, skipping the invalid ones
+func loadMetricTransformations(config model.Reader) error {
+	var configured []MetricTransformation
+	if err := structure.UnmarshalKey(config, "metric_transformations", &configured); err != nil {
+		return fmt.Errorf("invalid metric_transformations: %w", err)
+	}
+
+	var local []MetricTransformation
+	var errs []error
+	for i, transformation := range configured {
+		if err := transformation.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("skipping metric_transformations[%d]: %w", i, err))
+			continue
+		}
+		local = append(local, transformation)
+	}
+
+	metricTransformations.Lock()
+	defer metricTransformations.Unlock()
+	metricTransformations.local = local
+	storeMetricTransformer()
+	return errors.Join(errs...)
+}
+
+// SetRemoteMetricTransformations replaces the rules received through remote config. They are applied
+// after the rules of the metric_transformations setting, to the samples processed after the update.
+func SetRemoteMetricTransformations(transformations []MetricTransformation) {
+	metricTransformations.Lock()
+	defer metricTransformations.Unlock()
+	metricTransformations.remote = transformations
+	storeMetricTransformer()
+}
@@ -0,0 +1,147 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build test
+
+package aggregator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nooptagger "github.com/DataDog/datadog-agent/comp/core/tagger/impl-noop"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/internal/tags"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	"github.com/DataDog/datadog-agent/pkg/tagset"
+)
+
+func resetMetricTransformations() {
+	metricTransformations.Lock()
+	defer metricTransformations.Unlock()
+	metricTransformations.local = nil
+	metricTransformations.remote = nil
+	storeMetricTransformer()
+}
+
+func TestMetricTransformationValidate(t *testing.T) {
+	for name, transformation := range map[string]MetricTransformation{
+		"no metric":      {Action: MetricTransformationDrop},
+		"match all":      {Metrics: []string{"*"}, Action: MetricTransformationDrop},
+		"unknown action": {Metrics: []string{"foo"}, Action: "aggregate"},
+		"no new name":    {Metrics: []string{"foo"}, Action: MetricTransformationRename},
+		"no tags":        {Metrics: []string{"foo"}, Action: MetricTransformationStripTags},
+	} {
+		assert.Error(t, transformation.Validate(), name)
+	}
+
+	assert.NoError(t, MetricTransformation{Metrics: []string{"foo.*"}, Action: MetricTransformationDrop}.Validate())
+	assert.NoError(t, MetricTransformation{Metrics: []string{"foo"}, Action: MetricTransformationRename, NewName: "bar"}.Validate())
+	assert.NoError(t, MetricTransformation{Metrics: []string{"foo"}, Action: MetricTransformationStripTags, Tags: []string{"pod_name"}}.Validate())
+}
+
+func TestMetricTransformer(t *testing.T) {
+	var transformer *metricTransformer
+	assert.False(t, transformer.dropped("foo"))
+	name, stripped := transformer.transform("foo")
+	assert.Equal(t, "foo", name)
+	assert.Nil(t, stripped)
+
+	transformer = newMetricTransformer([]MetricTransformation{
+		{Metrics: []string{"debug.*", "noisy"}, Action: MetricTransformationDrop},
+		{Metrics: []string{"app.latency"}, Action: MetricTransformationRename, NewName: "app.request.latency"},
+		{Metrics: []string{"app.*"}, Action: MetricTransformationStripTags, Tags: []string{"pod_name"}},
+		{Metrics: []string{"app.latency"}, Action: MetricTransformationStripTags, Tags: []string{"request_id"}},
+		// rules match the original name of the metric
+		{Metrics: []string{"app.request.latency"}, Action: MetricTransformationDrop},
+	})
+
+	assert.True(t, transformer.dropped("debug.alloc"))
+	assert.True(t, transformer.dropped("noisy"))
+	assert.False(t, transformer.dropped("noisy.not"))
+	assert.False(t, transformer.dropped("app.latency"))
+
+	name, stripped = transformer.transform("app.latency")
+	assert.Equal(t, "app.request.latency", name)
+	assert.Equal(t, []string{"pod_name", "request_id"}, stripped)
+
+	name, stripped = transformer.transform("app.errors")
+	assert.Equal(t, "app.errors", name)
+	assert.Equal(t, []string{"pod_name"}, stripped)
+
+	name, stripped = transformer.transform("other")
+	assert.Equal(t, "other", name)
+	assert.Nil(t, stripped)
+}
+
+func TestStripTags(t *testing.T) {
+	tb := tagset.NewHashingTagsAccumulatorWithTags([]string{"env:prod", "pod_name:web-1", "pod_name", "service:web", "pod_name_suffix:1"})
+	stripTags(tb, []string{"pod_name"})
+	assert.Equal(t, []string{"env:prod", "service:web", "pod_name_suffix:1"}, tb.Get())
+	assert.Equal(t, tagset.NewHashingTagsAccumulatorWithTags(tb.Get()).Hashes(), tb.Hashes())
+
+	assert.Equal(t, []string{"env:prod"}, strippedTags([]string{"pod_name:web-1", "env:prod"}, []string{"pod_name"}))
+}
+
+func TestLoadMetricTransformations(t *testing.T) {
+	t.Cleanup(resetMetricTransformations)
+
+	cfg := configmock.NewFromYAML(t, `
+metric_transformations:
+  - metrics: ["app.latency"]
+    action: rename
+    new_name: app.request.latency
+  - metrics: ["app.errors"]
+    action: rename
+`)
+	err := loadMetricTransformations(cfg)
+	assert.ErrorContains(t, err, "metric_transformations[1]")
+
+	name, _ := currentMetricTransformer().transform("app.latency")
+	assert.Equal(t, "app.request.latency", name)
+	assert.False(t, currentMetricTransformer().dropped("app.errors"))
+
+	// remote rules are applied after the local ones
+	SetRemoteMetricTransformations([]MetricTransformation{
+		{Metrics: []string{"app.latency"}, Action: MetricTransformationRename, NewName: "app.remote.latency"},
+		{Metrics: []string{"app.errors"}, Action: MetricTransformationDrop},
+	})
+	name, _ = currentMetricTransformer().transform("app.latency")
+	assert.Equal(t, "app.remote.latency", name)
+	assert.True(t, currentMetricTransformer().dropped("app.errors"))
+
+	// the local rules are kept when the remote ones are removed
+	SetRemoteMetricTransformations(nil)
+	name, _ = currentMetricTransformer().transform("app.latency")
+	assert.Equal(t, "app.request.latency", name)
+	assert.False(t, currentMetricTransformer().dropped("app.errors"))
+}
+
+func TestTrackContextMetricTransformations(t *testing.T) {
+	SetRemoteMetricTransformations([]MetricTransformation{
+		{Metrics: []string{"app.latency"}, Action: MetricTransformationRename, NewName: "app.request.latency"},
+		{Metrics: []string{"app.*"}, Action: MetricTransformationStripTags, Tags: []string{"pod_name"}},
+	})
+	t.Cleanup(resetMetricTransformations)
+
+	r := newContextResolver(nooptagger.NewComponent(), tags.NewStore(true, "test"), "test")
+	key1 := r.trackContext(&mockSample{"app.latency", []string{"pod_name:web-1", "kube_deployment:web"}, []string{"env:prod"}}, 0)
+	key2 := r.trackContext(&mockSample{"app.latency", []string{"pod_name:web-2", "kube_deployment:web"}, []string{"env:prod"}}, 0)
+	key3 := r.trackContext(&mockSample{"other", []string{"pod_name:web-1"}, []string{"env:prod"}}, 0)
+
+	// the contexts of the pods are aggregated together
+	assert.Equal(t, key1, key2)
+	assert.NotEqual(t, key1, key3)
+	assert.Equal(t, 2, r.length())
+
+	cx, found := r.get(key1)
+	require.True(t, found)
+	assertContext(t, cx, "app.request.latency", []string{"kube_deployment:web", "env:prod"}, "noop")
+
+	cx, found = r.get(key3)
+	require.True(t, found)
+	assertContext(t, cx, "other", []string{"pod_name:web-1", "env:prod"}, "noop")
+}
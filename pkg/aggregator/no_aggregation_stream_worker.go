@@ -65,11 +65,13 @@ var (
 	noaggExpvars                               = expvar.NewMap("no_aggregation")
 	expvarNoAggSamplesProcessedOk              = expvar.Int{}
 	expvarNoAggSamplesProcessedUnsupportedType = expvar.Int{}
+	expvarNoAggSamplesProcessedDropped         = expvar.Int{}
 	expvarNoAggFlush                           = expvar.Int{}
 
 	tlmNoAggSamplesProcessed                = telemetry.NewCounter("no_aggregation", "processed", []string{"state"}, "Count the number of samples processed by the no-aggregation pipeline worker")
 	tlmNoAggSamplesProcessedOk              = tlmNoAggSamplesProcessed.WithValues("ok")
 	tlmNoAggSamplesProcessedUnsupportedType = tlmNoAggSamplesProcessed.WithValues("unsupported_type")
+	tlmNoAggSamplesProcessedDropped         = tlmNoAggSamplesProcessed.WithValues("dropped")
 
 	tlmNoAggFlush = telemetry.NewSimpleCounter("no_aggregation", "flush", "Count the number of flushes done by the no-aggregation pipeline worker")
 )
@@ -77,6 +79,7 @@ var (
 func init() {
 	noaggExpvars.Set("ProcessedOk", &expvarNoAggSamplesProcessedOk)
 	noaggExpvars.Set("ProcessedUnsupportedType", &expvarNoAggSamplesProcessedUnsupportedType)
+	noaggExpvars.Set("ProcessedDropped", &expvarNoAggSamplesProcessedDropped)
 	noaggExpvars.Set("Flush", &expvarNoAggFlush)
 }
 
@@ -190,8 +193,15 @@ func (w *noAggregationStreamWorker) run() {
 						log.Tracef("Streaming %d metrics from the no-aggregation pipeline", len(samples))
 						countProcessed := 0
 						countUnsupportedType := 0
+						countDropped := 0
 
+						transformer := currentMetricTransformer()
 						for _, sample := range samples {
+							if transformer.dropped(sample.Name) {
+								countDropped++
+								continue
+							}
+
 							mtype, supported := metricSampleAPIType(sample)
 
 							if !supported {
@@ -211,11 +221,17 @@ func (w *noAggregationStreamWorker) run() {
 								sample.Value /= bucketSize
 							}
 
+							name, strippedTagKeys := transformer.transform(sample.Name)
+							tags := w.metricBuffer.Copy()
+							if strippedTagKeys != nil {
+								tags = strippedTags(tags, strippedTagKeys)
+							}
+
 							// turns this metric sample into a serie
 							var serie metrics.Serie
-							serie.Name = sample.Name
+							serie.Name = name
 							serie.Points = []metrics.Point{{Ts: sample.Timestamp, Value: sample.Value}}
-							serie.Tags = tagset.CompositeTagsFromSlice(w.metricBuffer.Copy())
+							serie.Tags = tagset.CompositeTagsFromSlice(tags)
 							serie.Host = sample.Host
 							serie.MType = mtype
 							serie.Interval = bucketSize
@@ -234,6 +250,8 @@ func (w *noAggregationStreamWorker) run() {
 						expvarNoAggSamplesProcessedOk.Add(int64(countProcessed))
 						tlmNoAggSamplesProcessedUnsupportedType.Add(float64(countUnsupportedType))
 						expvarNoAggSamplesProcessedUnsupportedType.Add(int64(countUnsupportedType))
+						tlmNoAggSamplesProcessedDropped.Add(float64(countDropped))
+						expvarNoAggSamplesProcessedDropped.Add(int64(countDropped))
 
 						w.metricSamplePool.PutBatch(samples) // return the sample batch back to the pool for reuse
 
@@ -13,6 +13,9 @@ import (
 	"github.com/DataDog/opentelemetry-mapping-go/pkg/quantile"
 )
 
+// sketchConfig is the configuration of the sketches of the agent
+var sketchConfig = quantile.Default()
+
 type sketchMap map[int64]map[ckey.ContextKey]*quantile.Agent
 
 // Len returns the number of sketches stored
@@ -48,6 +51,17 @@ func (m sketchMap) insertInterp(ts int64, ck ckey.ContextKey, lower float64, upp
 	return true
 }
 
+// insertSketch merges the pre-aggregated sketch sk into a sketch for the given (ts, contextKey)
+// NOTE: ts is truncated to bucketSize
+func (m sketchMap) insertSketch(ts int64, ck ckey.ContextKey, sk *quantile.Sketch) bool {
+	if sk.Basic.Cnt == 0 {
+		return false
+	}
+
+	m.getOrCreate(ts, ck).Sketch.Merge(sketchConfig, sk)
+	return true
+}
+
 func (m sketchMap) getOrCreate(ts int64, ck ckey.ContextKey) *quantile.Agent {
 	// level 1: ts -> ctx
 	byCtx, ok := m[ts]
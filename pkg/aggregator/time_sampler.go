@@ -78,6 +78,10 @@ func (s *TimeSampler) isBucketStillOpen(bucketStartTimestamp, timestamp int64) b
 }
 
 func (s *TimeSampler) sample(metricSample *metrics.MetricSample, timestamp float64) {
+	if currentMetricTransformer().dropped(metricSample.Name) {
+		return
+	}
+
 	// use the timestamp provided in the sample if any
 	if metricSample.Timestamp > 0 {
 		timestamp = metricSample.Timestamp
@@ -89,7 +93,11 @@ func (s *TimeSampler) sample(metricSample *metrics.MetricSample, timestamp float
 
 	switch metricSample.Mtype {
 	case metrics.DistributionType:
-		s.sketchMap.insert(bucketStart, contextKey, metricSample.Value, metricSample.SampleRate)
+		if metricSample.Sketch != nil {
+			s.sketchMap.insertSketch(bucketStart, contextKey, metricSample.Sketch)
+		} else {
+			s.sketchMap.insert(bucketStart, contextKey, metricSample.Value, metricSample.SampleRate)
+		}
 	default:
 		// If it's a new bucket, initialize it
 		bucketMetrics, ok := s.metricsByTimestamp[bucketStart]
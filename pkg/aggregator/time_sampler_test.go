@@ -393,6 +393,55 @@ func TestSketchBucketSampling(t *testing.T) {
 	testWithTagsStore(t, testSketchBucketSampling)
 }
 
+func testPreAggregatedSketchSampling(t *testing.T, store *tags.Store) {
+	sampler := testTimeSampler(store)
+
+	preAggregated := &quantile.Sketch{}
+	preAggregated.Insert(quantile.Default(), 1, 2)
+
+	mSample1 := metrics.MetricSample{
+		Name:       "test.metric.name",
+		Mtype:      metrics.DistributionType,
+		Tags:       []string{"a", "b"},
+		SampleRate: 1,
+		Sketch:     preAggregated,
+	}
+	mSample2 := metrics.MetricSample{
+		Name:       "test.metric.name",
+		Value:      3,
+		Mtype:      metrics.DistributionType,
+		Tags:       []string{"a", "b"},
+		SampleRate: 1,
+	}
+	sampler.sample(&mSample1, 10001)
+	sampler.sample(&mSample2, 10002)
+	sampler.sample(&mSample1, 10011)
+
+	_, flushed := flushSerie(sampler, 10020.0)
+	expSketch1 := &quantile.Sketch{}
+	expSketch1.Insert(quantile.Default(), 1, 2, 3)
+	expSketch2 := &quantile.Sketch{}
+	expSketch2.Insert(quantile.Default(), 1, 2)
+
+	assert.Equal(t, 1, len(flushed))
+	metrics.AssertSketchSeriesApproxEqual(t, &metrics.SketchSeries{
+		Name:     "test.metric.name",
+		Tags:     tagset.CompositeTagsFromSlice([]string{"a", "b"}),
+		Interval: 10,
+		Points: []metrics.SketchPoint{
+			{Ts: 10000, Sketch: expSketch1},
+			{Ts: 10010, Sketch: expSketch2},
+		},
+		ContextKey: generateContextKey(&mSample1),
+	}, flushed[0], 0.01)
+
+	// the pre-aggregated sketch isn't modified by the sampler
+	assert.Equal(t, int64(2), preAggregated.Basic.Cnt)
+}
+func TestPreAggregatedSketchSampling(t *testing.T) {
+	testWithTagsStore(t, testPreAggregatedSketchSampling)
+}
+
 func testSketchContextSampling(t *testing.T, store *tags.Store) {
 	sampler := testTimeSampler(store)
 
@@ -10,12 +10,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"go.uber.org/fx"
 
 	"github.com/DataDog/datadog-agent/comp/core"
 	"github.com/DataDog/datadog-agent/comp/core/config"
 	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	settingsComponent "github.com/DataDog/datadog-agent/comp/core/settings"
 	ddflareextensiontypes "github.com/DataDog/datadog-agent/comp/otelcol/ddflareextension/types"
 	"github.com/DataDog/datadog-agent/pkg/api/util"
 	"github.com/DataDog/datadog-agent/pkg/config/settings"
@@ -31,6 +35,9 @@ type cliParams struct {
 	// source enables detailed information about each source and its value
 	source bool
 
+	// all makes the diff command print every setting, including the ones left to their default value
+	all bool
+
 	// args are the positional command line args
 	args []string
 }
@@ -101,6 +108,58 @@ func MakeCommand(globalParamsGetter func() GlobalParams) *cobra.Command {
 	cmd.AddCommand(getCmd)
 	getCmd.Flags().BoolVarP(&cliParams.source, "source", "s", false, "print every source and its value")
 
+	stageCmd := &cobra.Command{
+		Use:   "stage [setting] [value] [[setting] [value]...]",
+		Short: "Validate and stage runtime setting values, to be applied together by the commit command",
+		Long:  ``,
+		RunE:  oneShotRunE(stageConfigValues),
+	}
+	cmd.AddCommand(stageCmd)
+
+	stagedCmd := &cobra.Command{
+		Use:   "staged",
+		Short: "List the staged runtime setting values waiting to be committed",
+		Long:  ``,
+		RunE:  oneShotRunE(listStagedConfigValues),
+	}
+	cmd.AddCommand(stagedCmd)
+
+	commitCmd := &cobra.Command{
+		Use:   "commit",
+		Short: "Apply all the staged runtime setting values, or none of them if one fails to be applied",
+		Long:  ``,
+		RunE:  oneShotRunE(commitConfigValues),
+	}
+	cmd.AddCommand(commitCmd)
+
+	rollbackCmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Discard all the staged runtime setting values",
+		Long:  ``,
+		RunE:  oneShotRunE(rollbackConfigValues),
+	}
+	cmd.AddCommand(rollbackCmd)
+
+	overridesCmd := &cobra.Command{
+		Use:   "overrides",
+		Short: "List the setting values applied at runtime",
+		Long:  ``,
+		RunE:  oneShotRunE(listConfigOverrides),
+	}
+	cmd.AddCommand(overridesCmd)
+
+	diffCmd := &cobra.Command{
+		Use:   "diff [setting prefix...]",
+		Short: "Print the settings which differ from their default value, and the source setting them",
+		Long: `Print the settings of the running agent which differ from their default value, with the source their
+effective value comes from (file, environment variable, fleet policies, remote configuration, CLI...).
+Only the settings starting with one of the given prefixes are printed, if any.`,
+		RunE: oneShotRunE(diffConfiguration),
+	}
+	cmd.AddCommand(diffCmd)
+	diffCmd.Flags().BoolVarP(&cliParams.all, "all", "a", false, "print every setting of the resolved configuration, including the ones left to their default value")
+	diffCmd.Flags().BoolVarP(&cliParams.source, "source", "s", false, "print every source and its value")
+
 	otelCmd := &cobra.Command{
 		Use:   "otel-agent",
 		Short: "Otel-agent, prints out the read-only runtime configs of otel-agent if otel-agent is present and converter is enabled",
@@ -229,6 +288,194 @@ func getConfigValue(_ log.Component, config config.Component, cliParams *cliPara
 	return nil
 }
 
+func stageConfigValues(_ log.Component, config config.Component, cliParams *cliParams) error {
+	if len(cliParams.args) == 0 || len(cliParams.args)%2 != 0 {
+		return fmt.Errorf("pairs of setting name and value are required")
+	}
+
+	err := util.SetAuthToken(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := cliParams.GlobalParams.SettingsClient()
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]string, len(cliParams.args)/2)
+	for i := 0; i < len(cliParams.args); i += 2 {
+		values[cliParams.args[i]] = cliParams.args[i+1]
+	}
+
+	staged, err := c.Stage(values)
+	if err != nil {
+		return err
+	}
+
+	printStagedValues(staged)
+	fmt.Println("Run the commit command to apply them, or the rollback command to discard them.")
+
+	return nil
+}
+
+func listStagedConfigValues(_ log.Component, config config.Component, cliParams *cliParams) error {
+	err := util.SetAuthToken(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := cliParams.GlobalParams.SettingsClient()
+	if err != nil {
+		return err
+	}
+
+	staged, err := c.Staged()
+	if err != nil {
+		return err
+	}
+
+	printStagedValues(staged)
+
+	return nil
+}
+
+func printStagedValues(staged map[string]interface{}) {
+	names := make([]string, 0, len(staged))
+	for name := range staged {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("=== Staged settings ===")
+	for _, name := range names {
+		fmt.Printf("%-30s %v\n", name, staged[name])
+	}
+}
+
+func commitConfigValues(_ log.Component, config config.Component, cliParams *cliParams) error {
+	err := util.SetAuthToken(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := cliParams.GlobalParams.SettingsClient()
+	if err != nil {
+		return err
+	}
+
+	applied, err := c.Commit()
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		fmt.Println("No staged settings to apply")
+		return nil
+	}
+
+	for _, override := range applied {
+		fmt.Printf("Configuration setting %s is now set to: %v (was: %v)\n", override.Setting, override.Value, override.PreviousValue)
+	}
+
+	return nil
+}
+
+func rollbackConfigValues(_ log.Component, config config.Component, cliParams *cliParams) error {
+	err := util.SetAuthToken(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := cliParams.GlobalParams.SettingsClient()
+	if err != nil {
+		return err
+	}
+
+	if err := c.Rollback(); err != nil {
+		return err
+	}
+
+	fmt.Println("Staged settings discarded")
+
+	return nil
+}
+
+func listConfigOverrides(_ log.Component, config config.Component, cliParams *cliParams) error {
+	err := util.SetAuthToken(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := cliParams.GlobalParams.SettingsClient()
+	if err != nil {
+		return err
+	}
+
+	overrides, err := c.Overrides()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("=== Settings changed at runtime ===")
+	for _, override := range overrides {
+		fmt.Printf("%-30s %v (source: %s, applied at: %s)\n", override.Setting, override.Value, override.Source, override.AppliedAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+func diffConfiguration(_ log.Component, config config.Component, cliParams *cliParams) error {
+	err := util.SetAuthToken(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := cliParams.GlobalParams.SettingsClient()
+	if err != nil {
+		return err
+	}
+
+	provenance, err := c.Provenance(!cliParams.all)
+	if err != nil {
+		return err
+	}
+
+	if cliParams.all {
+		fmt.Println("=== Resolved configuration ===")
+	} else {
+		fmt.Println("=== Settings differing from their default value ===")
+	}
+	for _, setting := range filterProvenance(provenance, cliParams.args) {
+		fmt.Printf("%-30s %v (source: %s, default: %v)\n", setting.Setting, setting.Value, setting.Source, setting.Default)
+		if cliParams.source {
+			for _, sourceVal := range setting.Sources {
+				fmt.Printf("  %s: %v\n", sourceVal.Source, sourceVal.Value)
+			}
+		}
+	}
+
+	return nil
+}
+
+// filterProvenance returns the settings starting with one of the given prefixes, or all of them if there is none
+func filterProvenance(provenance []settingsComponent.SettingProvenance, prefixes []string) []settingsComponent.SettingProvenance {
+	if len(prefixes) == 0 {
+		return provenance
+	}
+
+	filtered := make([]settingsComponent.SettingProvenance, 0, len(provenance))
+	for _, setting := range provenance {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(setting.Setting, strings.ToLower(prefix)) {
+				filtered = append(filtered, setting)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
 func otelAgentCfg(_ log.Component, config config.Component, cliParams *cliParams) error {
 	if !config.GetBool("otelcollector.enabled") {
 		return errors.New("otel-agent is not enabled")
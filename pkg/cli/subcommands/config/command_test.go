@@ -13,6 +13,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/comp/core"
 	"github.com/DataDog/datadog-agent/comp/core/secrets"
+	"github.com/DataDog/datadog-agent/comp/core/settings"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
 )
 
@@ -83,3 +84,85 @@ func TestConfigGetCommand(t *testing.T) {
 			require.Equal(t, false, secretParams.Enabled)
 		})
 }
+
+func TestConfigStageCommand(t *testing.T) {
+	commands := []*cobra.Command{
+		MakeCommand(func() GlobalParams {
+			return GlobalParams{}
+		}),
+	}
+
+	fxutil.TestOneShotSubcommand(t,
+		commands,
+		[]string{"config", "stage", "foo", "bar", "baz", "qux"},
+		stageConfigValues,
+		func(cliParams *cliParams, _ core.BundleParams, secretParams secrets.Params) {
+			require.Equal(t, []string{"foo", "bar", "baz", "qux"}, cliParams.args)
+			require.Equal(t, false, secretParams.Enabled)
+		})
+}
+
+func TestConfigCommitCommand(t *testing.T) {
+	commands := []*cobra.Command{
+		MakeCommand(func() GlobalParams {
+			return GlobalParams{}
+		}),
+	}
+
+	fxutil.TestOneShotSubcommand(t,
+		commands,
+		[]string{"config", "commit"},
+		commitConfigValues,
+		func(cliParams *cliParams, _ core.BundleParams, secretParams secrets.Params) {
+			require.Equal(t, []string{}, cliParams.args)
+			require.Equal(t, false, secretParams.Enabled)
+		})
+}
+
+func TestConfigRollbackCommand(t *testing.T) {
+	commands := []*cobra.Command{
+		MakeCommand(func() GlobalParams {
+			return GlobalParams{}
+		}),
+	}
+
+	fxutil.TestOneShotSubcommand(t,
+		commands,
+		[]string{"config", "rollback"},
+		rollbackConfigValues,
+		func(cliParams *cliParams, _ core.BundleParams, secretParams secrets.Params) {
+			require.Equal(t, []string{}, cliParams.args)
+			require.Equal(t, false, secretParams.Enabled)
+		})
+}
+
+func TestConfigDiffCommand(t *testing.T) {
+	commands := []*cobra.Command{
+		MakeCommand(func() GlobalParams {
+			return GlobalParams{}
+		}),
+	}
+
+	fxutil.TestOneShotSubcommand(t,
+		commands,
+		[]string{"config", "diff", "--all", "logs_config"},
+		diffConfiguration,
+		func(cliParams *cliParams, _ core.BundleParams, secretParams secrets.Params) {
+			require.Equal(t, []string{"logs_config"}, cliParams.args)
+			require.Equal(t, true, cliParams.all)
+			require.Equal(t, false, cliParams.source)
+			require.Equal(t, false, secretParams.Enabled)
+		})
+}
+
+func TestFilterProvenance(t *testing.T) {
+	provenance := []settings.SettingProvenance{
+		{Setting: "log_level"},
+		{Setting: "logs_config.container_collect_all"},
+		{Setting: "logs_enabled"},
+	}
+
+	require.Equal(t, provenance, filterProvenance(provenance, nil))
+	require.Equal(t, provenance[1:2], filterProvenance(provenance, []string{"LOGS_CONFIG."}))
+	require.Equal(t, provenance[:1], filterProvenance(provenance, []string{"log_level", "unknown"}))
+}
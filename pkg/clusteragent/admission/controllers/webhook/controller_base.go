@@ -33,9 +33,11 @@ import (
 	mutatecommon "github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/common"
 	configWebhook "github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/config"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/cwsinstrumentation"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/resourcedefaults"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/tagsfromlabels"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/validate/kubernetesadmissionevents"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/workload"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/autoscalers"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
@@ -58,11 +60,12 @@ func NewController(
 	pa workload.PodPatcher,
 	datadogConfig config.Component,
 	demultiplexer demultiplexer.Component,
+	usageProcessor autoscalers.QueryProcessor,
 ) Controller {
 	if config.useAdmissionV1() {
-		return NewControllerV1(client, secretInformer, validatingInformers.V1().ValidatingWebhookConfigurations(), mutatingInformers.V1().MutatingWebhookConfigurations(), isLeaderFunc, leadershipStateNotif, config, wmeta, pa, datadogConfig, demultiplexer)
+		return NewControllerV1(client, secretInformer, validatingInformers.V1().ValidatingWebhookConfigurations(), mutatingInformers.V1().MutatingWebhookConfigurations(), isLeaderFunc, leadershipStateNotif, config, wmeta, pa, datadogConfig, demultiplexer, usageProcessor)
 	}
-	return NewControllerV1beta1(client, secretInformer, validatingInformers.V1beta1().ValidatingWebhookConfigurations(), mutatingInformers.V1beta1().MutatingWebhookConfigurations(), isLeaderFunc, leadershipStateNotif, config, wmeta, pa, datadogConfig, demultiplexer)
+	return NewControllerV1beta1(client, secretInformer, validatingInformers.V1beta1().ValidatingWebhookConfigurations(), mutatingInformers.V1beta1().MutatingWebhookConfigurations(), isLeaderFunc, leadershipStateNotif, config, wmeta, pa, datadogConfig, demultiplexer, usageProcessor)
 }
 
 // Webhook represents an admission webhook
@@ -98,7 +101,7 @@ type Webhook interface {
 // The reason is that the volume mount for the APM socket added by the configWebhook webhook
 // doesn't always work on Fargate (one of the envs where we use an agent sidecar), and
 // the agent sidecar webhook needs to remove it.
-func (c *controllerBase) generateWebhooks(wmeta workloadmeta.Component, pa workload.PodPatcher, datadogConfig config.Component, demultiplexer demultiplexer.Component) []Webhook {
+func (c *controllerBase) generateWebhooks(wmeta workloadmeta.Component, pa workload.PodPatcher, datadogConfig config.Component, demultiplexer demultiplexer.Component, usageProcessor autoscalers.QueryProcessor) []Webhook {
 	var webhooks []Webhook
 	var validatingWebhooks []Webhook
 
@@ -140,6 +143,14 @@ func (c *controllerBase) generateWebhooks(wmeta workloadmeta.Component, pa workl
 	autoscalingWebhook := autoscaling.NewWebhook(pa, datadogConfig)
 	webhooks = append(webhooks, autoscalingWebhook)
 
+	// Setup resource defaults webhook.
+	resourceDefaultsWebhook, err := resourcedefaults.NewWebhook(usageProcessor, datadogConfig)
+	if err != nil {
+		log.Errorf("failed to register resource defaults webhook: %v", err)
+	} else {
+		webhooks = append(webhooks, resourceDefaultsWebhook)
+	}
+
 	// Setup APM Instrumentation webhook. APM Instrumentation webhook needs to be registered after the config webhook.
 	apmWebhook, err := generateAutoInstrumentationWebhook(wmeta, datadogConfig)
 	if err != nil {
@@ -199,7 +210,9 @@ func generateAutoInstrumentationWebhook(wmeta workloadmeta.Component, datadogCon
 		configWebhook.NewMutator(configWebhook.NewMutatorConfig(datadogConfig), apm),
 		apm,
 	)
-	return autoinstrumentation.NewWebhook(config, wmeta, mutator)
+	// Only the namespace mutator supports overriding its configuration through remote config
+	remote, _ := apm.(autoinstrumentation.RemoteConfigurable)
+	return autoinstrumentation.NewWebhook(config, wmeta, mutator, remote)
 }
 
 // controllerBase acts as a base class for ControllerV1 and ControllerV1beta1.
@@ -50,6 +50,7 @@ func TestNewController(t *testing.T) {
 		nil,
 		datadogConfig,
 		nil,
+		nil,
 	)
 
 	assert.IsType(t, &ControllerV1{}, controller)
@@ -67,6 +68,7 @@ func TestNewController(t *testing.T) {
 		nil,
 		datadogConfig,
 		nil,
+		nil,
 	)
 
 	assert.IsType(t, &ControllerV1beta1{}, controller)
@@ -951,7 +951,7 @@ func TestGenerateTemplatesV1(t *testing.T) {
 
 			c := &ControllerV1{}
 			c.config = tt.configFunc(mockConfig)
-			c.webhooks = c.generateWebhooks(wmeta, nil, mockConfig, nil)
+			c.webhooks = c.generateWebhooks(wmeta, nil, mockConfig, nil, nil)
 			c.generateTemplates()
 
 			assert.EqualValues(t, tt.want(), c.mutatingWebhookTemplates)
@@ -1196,6 +1196,7 @@ func (f *fixtureV1) createController() (*ControllerV1, informers.SharedInformerF
 		nil,
 		datadogConfig,
 		nil,
+		nil,
 	), factory
 }
 
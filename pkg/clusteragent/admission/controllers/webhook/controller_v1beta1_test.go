@@ -945,7 +945,7 @@ func TestGenerateTemplatesV1beta1(t *testing.T) {
 
 			c := &ControllerV1beta1{}
 			c.config = tt.configFunc(mockConfig)
-			c.webhooks = c.generateWebhooks(wmeta, nil, mockConfig, nil)
+			c.webhooks = c.generateWebhooks(wmeta, nil, mockConfig, nil, nil)
 			c.generateTemplates()
 
 			assert.EqualValues(t, tt.want(), c.mutatingWebhookTemplates)
@@ -1190,6 +1190,7 @@ func (f *fixtureV1beta1) createController() (*ControllerV1beta1, informers.Share
 		nil,
 		datadogConfig,
 		nil,
+		nil,
 	), factory
 }
 
@@ -106,4 +106,13 @@ var (
 	PatchErrors = telemetry.NewCounterWithOpts("admission_webhooks", "patcher_errors",
 		[]string{}, "Number of patch errors.",
 		telemetry.Options{NoDoubleUnderscoreSep: true})
+	InstrumentationRemoteConfigs = telemetry.NewGaugeWithOpts("admission_webhooks", "instrumentation_rc_configs",
+		[]string{"dry_run"}, "Number of auto instrumentation configurations applied from remote config.",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
+	ResourceDefaultsInjections = telemetry.NewCounterWithOpts("admission_webhooks", "resource_defaults_injections",
+		[]string{"status"}, "Number of containers missing resources evaluated by the resource defaults webhook by status.",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
+	InstrumentationDryRunDecisions = telemetry.NewCounterWithOpts("admission_webhooks", "instrumentation_rc_dry_run_decisions",
+		[]string{"would_mutate", "mutated"}, "Number of pods evaluated against a dry-run auto instrumentation remote configuration.",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
 )
@@ -26,6 +26,7 @@ import (
 	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/common"
 	mutatecommon "github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/common"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -49,19 +50,23 @@ type Webhook struct {
 
 	wmeta   workloadmeta.Component
 	mutator mutatecommon.Mutator
+	// remote is the mutator whose configuration can be overridden through remote config, nil if not supported
+	remote RemoteConfigurable
 
 	// use to store all the config option from the config component to avoid costly lookups in the admission webhook hot path.
 	config *WebhookConfig
 }
 
-// NewWebhook returns a new Webhook dependent on the injection filter.
-func NewWebhook(config *Config, wmeta workloadmeta.Component, mutator mutatecommon.Mutator) (*Webhook, error) {
+// NewWebhook returns a new Webhook dependent on the injection filter. The remote mutator is optional, it receives the
+// configurations sent through remote config.
+func NewWebhook(config *Config, wmeta workloadmeta.Component, mutator mutatecommon.Mutator, remote RemoteConfigurable) (*Webhook, error) {
 	webhook := &Webhook{
 		name:            webhookName,
 		resources:       map[string][]string{"": {"pods"}},
 		operations:      []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
 		matchConditions: []admissionregistrationv1.MatchCondition{},
 		mutator:         mutator,
+		remote:          remote,
 		wmeta:           wmeta,
 		config:          config.Webhook,
 	}
@@ -121,6 +126,21 @@ func (w *Webhook) WebhookFunc() admission.WebhookFunc {
 	}
 }
 
+// UpdateRemoteConfig forwards the auto instrumentation configurations received through remote config to the mutator
+func (w *Webhook) UpdateRemoteConfig(updates map[string]state.RawConfig, applyStateCallback func(string, state.ApplyStatus)) {
+	if w.remote == nil {
+		for path := range updates {
+			log.Errorf("Ignoring auto instrumentation remote configuration %s, it is not supported when targets are configured", path)
+			applyStateCallback(path, state.ApplyStatus{
+				State: state.ApplyStateError,
+				Error: "remote configuration is not supported when instrumentation targets are configured",
+			})
+		}
+		return
+	}
+	w.remote.UpdateRemoteConfig(updates, applyStateCallback)
+}
+
 func (w *Webhook) inject(pod *corev1.Pod, ns string, cl dynamic.Interface) (bool, error) {
 	log.Debugf("Mutating pod with SSI %q", mutatecommon.PodString(pod))
 	return w.mutator.MutatePod(pod, ns, cl)
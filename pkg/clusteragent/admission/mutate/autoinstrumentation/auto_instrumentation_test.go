@@ -3442,7 +3442,7 @@ func maybeWebhook(wmeta workloadmeta.Component, ddConfig config.Component, versi
 	if err != nil {
 		return nil, err
 	}
-	webhook, err := NewWebhook(config, wmeta, mutator)
+	webhook, err := NewWebhook(config, wmeta, mutator, mutator)
 	if err != nil {
 		return nil, err
 	}
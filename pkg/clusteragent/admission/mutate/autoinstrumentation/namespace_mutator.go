@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -31,6 +32,10 @@ type NamespaceMutator struct {
 	wmeta           workloadmeta.Component
 	pinnedLibraries pinnedLibraries
 	core            *mutatorCore
+
+	// remote is the configuration received through remote config, it overrides
+	// the library versions and the namespaces of the local configuration
+	remote atomic.Pointer[remoteInstrumentation]
 }
 
 // NewNamespaceMutator creates a new injector interface for the auto-instrumentation injector.
@@ -41,13 +46,15 @@ func NewNamespaceMutator(config *Config, wmeta workloadmeta.Component) (*Namespa
 	}
 
 	pinnedLibraries := getPinnedLibraries(config.Instrumentation.LibVersions, config.containerRegistry, true)
-	return &NamespaceMutator{
+	m := &NamespaceMutator{
 		config:          config,
 		filter:          filter,
 		wmeta:           wmeta,
 		pinnedLibraries: pinnedLibraries,
-		core:            newMutatorCore(config, wmeta, filter),
-	}, nil
+	}
+	// The core evaluates the namespaces through the mutator to take the remote configuration into account
+	m.core = newMutatorCore(config, wmeta, m)
+	return m, nil
 }
 
 // MutatePod implements the common.Mutator interface for the auto-instrumentation injector. It injects all of the
@@ -62,7 +69,9 @@ func (m *NamespaceMutator) MutatePod(pod *corev1.Pod, ns string, _ dynamic.Inter
 		pod.Namespace = ns
 	}
 
-	if !m.isPodEligible(pod) {
+	eligible := m.isPodEligible(pod)
+	m.reportDryRun(pod, eligible)
+	if !eligible {
 		return false, nil
 	}
 
@@ -105,7 +114,7 @@ func (m *NamespaceMutator) ShouldMutatePod(pod *corev1.Pod) bool {
 	if !m.config.Instrumentation.Enabled {
 		return false
 	}
-	return m.filter.ShouldMutatePod(pod)
+	return m.currentFilter().ShouldMutatePod(pod)
 }
 
 // IsNamespaceEligible implements the common.MutationFilter interface for the auto-instrumentation injector.
@@ -113,7 +122,23 @@ func (m *NamespaceMutator) IsNamespaceEligible(ns string) bool {
 	if !m.config.Instrumentation.Enabled {
 		return false
 	}
-	return m.filter.IsNamespaceEligible(ns)
+	return m.currentFilter().IsNamespaceEligible(ns)
+}
+
+// currentFilter returns the filter of the remote configuration if one is applied, the local filter otherwise.
+func (m *NamespaceMutator) currentFilter() mutatecommon.MutationFilter {
+	if remote := m.activeRemote(); remote != nil {
+		return remote.filter
+	}
+	return m.filter
+}
+
+// currentPinnedLibraries returns the libraries of the remote configuration if one is applied, the local ones otherwise.
+func (m *NamespaceMutator) currentPinnedLibraries() pinnedLibraries {
+	if remote := m.activeRemote(); remote != nil {
+		return remote.pinnedLibraries
+	}
+	return m.pinnedLibraries
 }
 
 type mutatorCore struct {
@@ -233,13 +258,14 @@ func (m *mutatorCore) newInjector(startTime time.Time, pod *corev1.Pod, opts ...
 
 // isPodEligible checks whether we are allowed to inject in this pod.
 func (m *NamespaceMutator) isPodEligible(pod *corev1.Pod) bool {
-	return m.filter.ShouldMutatePod(pod)
+	return m.currentFilter().ShouldMutatePod(pod)
 }
 
 // extractLibInfo metadata about what library information we should be
 // injecting into the pod and where it came from.
 func (m *NamespaceMutator) extractLibInfo(pod *corev1.Pod) extractedPodLibInfo {
 	extracted := m.core.initExtractedLibInfo(pod)
+	pinnedLibraries := m.currentPinnedLibraries()
 
 	libs := extractLibrariesFromAnnotations(pod, m.config.containerRegistry)
 	if len(libs) > 0 {
@@ -250,8 +276,8 @@ func (m *NamespaceMutator) extractLibInfo(pod *corev1.Pod) extractedPodLibInfo {
 	// we prefer to use these and not override their behavior.
 	//
 	// N.B. this is empty if auto-instrumentation is disabled.
-	if !m.pinnedLibraries.areSetToDefaults && len(m.pinnedLibraries.libs) > 0 {
-		return extracted.withLibs(m.pinnedLibraries.libs)
+	if !pinnedLibraries.areSetToDefaults && len(pinnedLibraries.libs) > 0 {
+		return extracted.withLibs(pinnedLibraries.libs)
 	}
 
 	// if the language_detection injection is enabled
@@ -260,8 +286,8 @@ func (m *NamespaceMutator) extractLibInfo(pod *corev1.Pod) extractedPodLibInfo {
 		return e
 	}
 
-	if len(m.pinnedLibraries.libs) > 0 {
-		return extracted.withLibs(m.pinnedLibraries.libs)
+	if len(pinnedLibraries.libs) > 0 {
+		return extracted.withLibs(pinnedLibraries.libs)
 	}
 
 	if extracted.source.isSingleStep() {
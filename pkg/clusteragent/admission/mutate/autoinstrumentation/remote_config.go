@@ -0,0 +1,156 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package autoinstrumentation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/metrics"
+	mutatecommon "github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/common"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// RemoteConfigurable is implemented by the mutators whose configuration can be overridden through remote config
+type RemoteConfigurable interface {
+	UpdateRemoteConfig(updates map[string]state.RawConfig, applyStateCallback func(string, state.ApplyStatus))
+}
+
+// RemoteInstrumentationConfig is the auto instrumentation configuration received through remote config. It overrides
+// the library versions and the namespaces of the local configuration, at least one of the namespace lists must be set.
+type RemoteInstrumentationConfig struct {
+	LibVersions        map[string]string `json:"lib_versions"`
+	EnabledNamespaces  []string          `json:"enabled_namespaces"`
+	DisabledNamespaces []string          `json:"disabled_namespaces"`
+	// DryRun reports which pods would be mutated with this configuration without applying it
+	DryRun bool `json:"dry_run"`
+}
+
+// remoteInstrumentation is a remote configuration ready to be evaluated by the mutator
+type remoteInstrumentation struct {
+	configPath      string
+	filter          mutatecommon.MutationFilter
+	pinnedLibraries pinnedLibraries
+	dryRun          bool
+}
+
+func newRemoteInstrumentation(configPath string, raw []byte, config *Config) (*remoteInstrumentation, error) {
+	var remoteConfig RemoteInstrumentationConfig
+	if err := json.Unmarshal(raw, &remoteConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal configuration: %w", err)
+	}
+
+	for lang := range remoteConfig.LibVersions {
+		if !language(lang).isSupported() {
+			return nil, fmt.Errorf("unsupported language %q", lang)
+		}
+	}
+
+	// without namespaces the filter would target every namespace, which must be requested explicitly with
+	// disabled_namespaces rather than be the result of a configuration missing its targets
+	if len(remoteConfig.EnabledNamespaces) == 0 && len(remoteConfig.DisabledNamespaces) == 0 {
+		return nil, errors.New("either enabled_namespaces or disabled_namespaces must be set")
+	}
+
+	filter, err := mutatecommon.NewDefaultFilter(config.Instrumentation.Enabled, remoteConfig.EnabledNamespaces, remoteConfig.DisabledNamespaces)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespaces: %w", err)
+	}
+
+	return &remoteInstrumentation{
+		configPath:      configPath,
+		filter:          filter,
+		pinnedLibraries: getPinnedLibraries(remoteConfig.LibVersions, config.containerRegistry, true),
+		dryRun:          remoteConfig.DryRun,
+	}, nil
+}
+
+// UpdateRemoteConfig applies the auto instrumentation configuration received through remote config. A single
+// configuration is supported, the configuration with the smallest path is applied and the other ones are rejected.
+// The local configuration is restored when the remote configuration is removed.
+func (m *NamespaceMutator) UpdateRemoteConfig(updates map[string]state.RawConfig, applyStateCallback func(string, state.ApplyStatus)) {
+	paths := make([]string, 0, len(updates))
+	for path := range updates {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var applied *remoteInstrumentation
+	for _, path := range paths {
+		if applied != nil {
+			log.Errorf("Ignoring auto instrumentation remote configuration %s, configuration %s is already applied", path, applied.configPath)
+			applyStateCallback(path, state.ApplyStatus{
+				State: state.ApplyStateError,
+				Error: "only one auto instrumentation configuration is supported, configuration " + applied.configPath + " is already applied",
+			})
+			continue
+		}
+
+		remote, err := newRemoteInstrumentation(path, updates[path].Config, m.config)
+		if err != nil {
+			log.Errorf("Invalid auto instrumentation remote configuration %s: %v", path, err)
+			applyStateCallback(path, state.ApplyStatus{State: state.ApplyStateError, Error: err.Error()})
+			continue
+		}
+
+		applied = remote
+		applyStateCallback(path, state.ApplyStatus{State: state.ApplyStateAcknowledged})
+	}
+
+	metrics.InstrumentationRemoteConfigs.Set(0, "true")
+	metrics.InstrumentationRemoteConfigs.Set(0, "false")
+	if applied == nil {
+		if m.remote.Swap(nil) != nil {
+			log.Info("Auto instrumentation remote configuration removed, using the local configuration")
+		}
+		return
+	}
+
+	metrics.InstrumentationRemoteConfigs.Set(1, strconv.FormatBool(applied.dryRun))
+	log.Infof("Applying auto instrumentation remote configuration %s (dry run: %t)", applied.configPath, applied.dryRun)
+	m.remote.Store(applied)
+}
+
+// activeRemote returns the remote configuration overriding the local one, nil if there is none or if it is a dry run
+func (m *NamespaceMutator) activeRemote() *remoteInstrumentation {
+	remote := m.remote.Load()
+	if remote == nil || remote.dryRun {
+		return nil
+	}
+	return remote
+}
+
+// reportDryRun reports whether the pod would be mutated with the dry-run remote configuration
+func (m *NamespaceMutator) reportDryRun(pod *corev1.Pod, mutated bool) {
+	remote := m.remote.Load()
+	if remote == nil || !remote.dryRun {
+		return
+	}
+
+	wouldMutate := remote.filter.ShouldMutatePod(pod)
+	metrics.InstrumentationDryRunDecisions.Inc(strconv.FormatBool(wouldMutate), strconv.FormatBool(mutated))
+	if wouldMutate == mutated {
+		return
+	}
+
+	if wouldMutate {
+		var images []string
+		for _, lib := range remote.pinnedLibraries.libs {
+			images = append(images, lib.image)
+		}
+		log.Infof("Dry run: pod %s would be mutated with the remote configuration %s, libraries: %v", mutatecommon.PodString(pod), remote.configPath, images)
+	} else {
+		log.Infof("Dry run: pod %s would not be mutated with the remote configuration %s", mutatecommon.PodString(pod), remote.configPath)
+	}
+}
@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package autoinstrumentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core"
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	workloadmetafxmock "github.com/DataDog/datadog-agent/comp/core/workloadmeta/fx-mock"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/common"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func TestNamespaceMutatorUpdateRemoteConfig(t *testing.T) {
+	wmeta := fxutil.Test[workloadmeta.Component](t,
+		core.MockBundle(),
+		workloadmetafxmock.MockModule(workloadmeta.NewParams()),
+	)
+	mockConfig := configmock.New(t)
+	mockConfig.SetWithoutSource("apm_config.instrumentation.enabled", true)
+	mockConfig.SetWithoutSource("apm_config.instrumentation.enabled_namespaces", []string{"local"})
+	mockConfig.SetWithoutSource("apm_config.instrumentation.lib_versions", map[string]string{"java": "v1"})
+
+	config, err := NewConfig(mockConfig)
+	require.NoError(t, err)
+	mutator, err := NewNamespaceMutator(config, wmeta)
+	require.NoError(t, err)
+
+	applied := map[string]state.ApplyStatus{}
+	applyStateCallback := func(configPath string, status state.ApplyStatus) {
+		applied[configPath] = status
+	}
+
+	localPod := common.FakePodWithNamespaceAndLabel("local", "", "")
+	remotePod := common.FakePodWithNamespaceAndLabel("remote", "", "")
+	libImages := func() []string {
+		var images []string
+		for _, lib := range mutator.extractLibInfo(remotePod).libs {
+			images = append(images, lib.image)
+		}
+		return images
+	}
+
+	mutator.UpdateRemoteConfig(map[string]state.RawConfig{
+		"a": {Config: []byte(`{"lib_versions":{"java":"v1.2.3","dotnet":"v3.4.5"},"enabled_namespaces":["remote"]}`)},
+		"b": {Config: []byte(`{"enabled_namespaces":["local"]}`)},
+	}, applyStateCallback)
+	assert.Equal(t, state.ApplyStateAcknowledged, applied["a"].State)
+	assert.Equal(t, state.ApplyStateError, applied["b"].State)
+	assert.False(t, mutator.isPodEligible(localPod))
+	assert.True(t, mutator.isPodEligible(remotePod))
+	assert.True(t, mutator.IsNamespaceEligible("remote"))
+	assert.ElementsMatch(t, []string{
+		language("java").libImageName(config.containerRegistry, "v1.2.3"),
+		language("dotnet").libImageName(config.containerRegistry, "v3.4.5"),
+	}, libImages())
+
+	// the local configuration is kept in dry run mode
+	mutator.UpdateRemoteConfig(map[string]state.RawConfig{
+		"a": {Config: []byte(`{"lib_versions":{"java":"v1.2.3"},"enabled_namespaces":["remote"],"dry_run":true}`)},
+	}, applyStateCallback)
+	assert.Equal(t, state.ApplyStateAcknowledged, applied["a"].State)
+	assert.True(t, mutator.isPodEligible(localPod))
+	assert.False(t, mutator.isPodEligible(remotePod))
+	_, err = mutator.MutatePod(remotePod.DeepCopy(), "remote", nil)
+	require.NoError(t, err)
+
+	// invalid configurations are rejected and the local configuration is restored
+	for name, raw := range map[string]string{
+		"corrupt":    `{"lib_versions":`,
+		"language":   `{"lib_versions":{"cobol":"v1"}}`,
+		"namespaces": `{"enabled_namespaces":["remote"],"disabled_namespaces":["local"]}`,
+		"no targets": `{"lib_versions":{"java":"v1.2.3"}}`,
+	} {
+		mutator.UpdateRemoteConfig(map[string]state.RawConfig{name: {Config: []byte(raw)}}, applyStateCallback)
+		assert.Equal(t, state.ApplyStateError, applied[name].State, name)
+		assert.NotEmpty(t, applied[name].Error, name)
+	}
+	assert.True(t, mutator.isPodEligible(localPod))
+	assert.False(t, mutator.isPodEligible(remotePod))
+	assert.Equal(t, []string{language("java").libImageName(config.containerRegistry, "v1")}, libImages())
+}
+
+func TestWebhookUpdateRemoteConfigNotSupported(t *testing.T) {
+	webhook := &Webhook{}
+
+	applied := map[string]state.ApplyStatus{}
+	webhook.UpdateRemoteConfig(map[string]state.RawConfig{
+		"a": {Config: []byte(`{"enabled_namespaces":["remote"]}`)},
+	}, func(configPath string, status state.ApplyStatus) {
+		applied[configPath] = status
+	})
+	assert.Equal(t, state.ApplyStateError, applied["a"].State)
+}
@@ -0,0 +1,271 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+// Package resourcedefaults implements the webhook that sets default resource requests
+// and limits on containers based on their historical usage
+package resourcedefaults
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	admiv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/admission"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/common"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/metrics"
+	mutatecommon "github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/common"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/autoscalers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	webhookName = "resource_defaults"
+
+	statusInjected = "injected"
+	statusAudited  = "audited"
+	statusNoData   = "no_data"
+	statusPending  = "pending"
+	statusError    = "error"
+)
+
+// Webhook implements the MutatingWebhook interface
+type Webhook struct {
+	name            string
+	isEnabled       bool
+	endpoint        string
+	resources       map[string][]string
+	operations      []admissionregistrationv1.OperationType
+	matchConditions []admissionregistrationv1.MatchCondition
+
+	filter        mutatecommon.MutationFilter
+	usageProvider usageProvider
+	auditOnly     bool
+	requestMargin float64
+	limitMargin   float64
+}
+
+// NewWebhook returns a new Webhook. The historical usage of the containers is queried with the processor.
+func NewWebhook(processor autoscalers.QueryProcessor, datadogConfig config.Component) (*Webhook, error) {
+	isEnabled := datadogConfig.GetBool("admission_controller.resource_defaults.enabled")
+	filter, err := mutatecommon.NewDefaultFilter(
+		isEnabled,
+		datadogConfig.GetStringSlice("admission_controller.resource_defaults.enabled_namespaces"),
+		datadogConfig.GetStringSlice("admission_controller.resource_defaults.disabled_namespaces"))
+	if err != nil {
+		return nil, err
+	}
+	if isEnabled && processor == nil {
+		return nil, errors.New("the resource defaults webhook requires a Datadog API client, external_metrics_provider.enabled must be set")
+	}
+
+	usageProvider := newDatadogUsageProvider(
+		processor,
+		time.Duration(datadogConfig.GetInt("admission_controller.resource_defaults.usage_window"))*time.Second,
+		time.Duration(datadogConfig.GetInt("admission_controller.resource_defaults.cache_validity"))*time.Minute,
+	)
+	if isEnabled {
+		go usageProvider.run()
+	}
+
+	return &Webhook{
+		name:            webhookName,
+		isEnabled:       isEnabled,
+		endpoint:        datadogConfig.GetString("admission_controller.resource_defaults.endpoint"),
+		resources:       map[string][]string{"": {"pods"}},
+		operations:      []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+		matchConditions: []admissionregistrationv1.MatchCondition{},
+		filter:          filter,
+		usageProvider:   usageProvider,
+		auditOnly:       datadogConfig.GetBool("admission_controller.resource_defaults.audit_only"),
+		requestMargin:   datadogConfig.GetFloat64("admission_controller.resource_defaults.request_margin"),
+		limitMargin:     datadogConfig.GetFloat64("admission_controller.resource_defaults.limit_margin"),
+	}, nil
+}
+
+// Name returns the name of the webhook
+func (w *Webhook) Name() string {
+	return w.name
+}
+
+// WebhookType returns the type of the webhook
+func (w *Webhook) WebhookType() common.WebhookType {
+	return common.MutatingWebhook
+}
+
+// IsEnabled returns whether the webhook is enabled
+func (w *Webhook) IsEnabled() bool {
+	return w.isEnabled
+}
+
+// Endpoint returns the endpoint of the webhook
+func (w *Webhook) Endpoint() string {
+	return w.endpoint
+}
+
+// Resources returns the kubernetes resources for which the webhook should
+// be invoked
+func (w *Webhook) Resources() map[string][]string {
+	return w.resources
+}
+
+// Operations returns the operations on the resources specified for which
+// the webhook should be invoked
+func (w *Webhook) Operations() []admissionregistrationv1.OperationType {
+	return w.operations
+}
+
+// LabelSelectors returns the label selectors that specify when the webhook
+// should be invoked
+func (w *Webhook) LabelSelectors(_ bool) (namespaceSelector *metav1.LabelSelector, objectSelector *metav1.LabelSelector) {
+	// Resource defaults are enabled per namespace, the namespaces are filtered by the webhook
+	// instead of requiring the pods to be labelled.
+	return nil, nil
+}
+
+// MatchConditions returns the Match Conditions used for fine-grained
+// request filtering
+func (w *Webhook) MatchConditions() []admissionregistrationv1.MatchCondition {
+	return w.matchConditions
+}
+
+// WebhookFunc returns the function that mutates the resources
+func (w *Webhook) WebhookFunc() admission.WebhookFunc {
+	return func(request *admission.Request) *admiv1.AdmissionResponse {
+		return common.MutationResponse(mutatecommon.Mutate(request.Object, request.Namespace, w.Name(), w.setResourceDefaults, request.DynamicClient))
+	}
+}
+
+// setResourceDefaults sets the requests and the limits missing on the containers of the pod from the historical
+// usage of the containers of its workload. In audit only mode, the defaults are logged and the pod is not mutated.
+func (w *Webhook) setResourceDefaults(pod *corev1.Pod, ns string, _ dynamic.Interface) (bool, error) {
+	if pod.Namespace == "" {
+		pod.Namespace = ns
+	}
+	if !w.filter.IsNamespaceEligible(pod.Namespace) {
+		return false, nil
+	}
+
+	owner, found := getWorkloadOwner(pod)
+	if !found {
+		log.Debugf("Skipping resource defaults for pod %s, it is not owned by a supported workload", mutatecommon.PodString(pod))
+		return false, nil
+	}
+
+	mutated := false
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		if !missesResources(container) {
+			continue
+		}
+
+		usage, err := w.usageProvider.getContainerUsage(pod.Namespace, owner, container.Name)
+		if errors.Is(err, errUsagePending) {
+			metrics.ResourceDefaultsInjections.Inc(statusPending)
+			log.Debugf("Usage of container %s of %s not queried yet, skipping resource defaults", container.Name, owner)
+			continue
+		}
+		if err != nil {
+			metrics.ResourceDefaultsInjections.Inc(statusError)
+			log.Warnf("Cannot set resource defaults for container %s in pod %s: %v", container.Name, mutatecommon.PodString(pod), err)
+			continue
+		}
+		if usage == nil {
+			metrics.ResourceDefaultsInjections.Inc(statusNoData)
+			log.Debugf("No usage data for container %s of %s, skipping resource defaults", container.Name, owner)
+			continue
+		}
+
+		defaults := w.applyDefaults(container.Resources, usage)
+		if w.auditOnly {
+			metrics.ResourceDefaultsInjections.Inc(statusAudited)
+			log.Infof("Audit only: resources of container %s in pod %s would be set to requests %v and limits %v",
+				container.Name, mutatecommon.PodString(pod), defaults.Requests, defaults.Limits)
+			continue
+		}
+
+		metrics.ResourceDefaultsInjections.Inc(statusInjected)
+		container.Resources = defaults
+		mutated = true
+	}
+
+	return mutated, nil
+}
+
+// missesResources returns whether a CPU or memory request or limit is not set on the container
+func missesResources(container *corev1.Container) bool {
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if _, found := container.Resources.Requests[name]; !found {
+			return true
+		}
+		if _, found := container.Resources.Limits[name]; !found {
+			return true
+		}
+	}
+	return false
+}
+
+// applyDefaults returns the resource requirements with the missing values set from the usage. The request is set
+// when neither the request nor the limit is set, as Kubernetes defaults the request to the limit. The limit is set
+// when it is missing, and never below the request.
+func (w *Webhook) applyDefaults(current corev1.ResourceRequirements, usage *containerUsage) corev1.ResourceRequirements {
+	defaults := *current.DeepCopy()
+	if defaults.Requests == nil {
+		defaults.Requests = corev1.ResourceList{}
+	}
+	if defaults.Limits == nil {
+		defaults.Limits = corev1.ResourceList{}
+	}
+
+	for _, r := range []struct {
+		name             corev1.ResourceName
+		average, maximum float64
+		quantity         func(float64) *resource.Quantity
+	}{
+		{corev1.ResourceCPU, usage.cpuAverage, usage.cpuMax, cpuQuantity},
+		{corev1.ResourceMemory, usage.memoryAverage, usage.memoryMax, memoryQuantity},
+	} {
+		if r.average <= 0 || r.maximum <= 0 {
+			continue
+		}
+
+		request, hasRequest := defaults.Requests[r.name]
+		_, hasLimit := defaults.Limits[r.name]
+		if !hasRequest && !hasLimit {
+			request = *r.quantity(r.average * (1 + w.requestMargin))
+			defaults.Requests[r.name] = request
+			hasRequest = true
+		}
+
+		if !hasLimit {
+			limit := *r.quantity(r.maximum * (1 + w.limitMargin))
+			if hasRequest && limit.Cmp(request) < 0 {
+				limit = request
+			}
+			defaults.Limits[r.name] = limit
+		}
+	}
+
+	return defaults
+}
+
+// cpuQuantity converts nanocores to a quantity of millicores
+func cpuQuantity(nanocores float64) *resource.Quantity {
+	return resource.NewMilliQuantity(int64(math.Ceil(nanocores/1e6)), resource.DecimalSI)
+}
+
+// memoryQuantity converts bytes to a quantity
+func memoryQuantity(bytes float64) *resource.Quantity {
+	return resource.NewQuantity(int64(math.Ceil(bytes)), resource.BinarySI)
+}
@@ -0,0 +1,261 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package resourcedefaults
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/autoscalers"
+	"github.com/DataDog/datadog-agent/pkg/util/pointer"
+)
+
+// fakeProcessor returns the points of the queries starting with their prefix, the other queries have no data
+type fakeProcessor struct {
+	points  map[string]autoscalers.Point
+	queries int
+}
+
+func (p *fakeProcessor) QueryExternalMetric(queries []string, _ time.Duration) map[string]autoscalers.Point {
+	p.queries += len(queries)
+	points := make(map[string]autoscalers.Point, len(queries))
+	for _, query := range queries {
+		point := autoscalers.Point{Error: autoscalers.NewProcessingError("no serie was found")}
+		for prefix, prefixPoint := range p.points {
+			if strings.HasPrefix(query, prefix) {
+				point = prefixPoint
+			}
+		}
+		points[query] = point
+	}
+	return points
+}
+
+func newPod(namespace string, containers ...corev1.Container) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-6d4cf56db6-abcde",
+			Namespace: namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "web-6d4cf56db6", Controller: pointer.Ptr(true)},
+			},
+		},
+		Spec: corev1.PodSpec{Containers: containers},
+	}
+}
+
+func TestSetResourceDefaults(t *testing.T) {
+	processor := &fakeProcessor{points: map[string]autoscalers.Point{
+		"avg:kubernetes.cpu.usage.total":    {Value: 100e6, Valid: true},
+		"max:kubernetes.cpu.usage.total":    {Value: 400e6, Valid: true},
+		"avg:kubernetes.memory.working_set": {Value: 100 * 1024 * 1024, Valid: true},
+		"max:kubernetes.memory.working_set": {Value: 200 * 1024 * 1024, Valid: true},
+	}}
+
+	mockConfig := configmock.New(t)
+	mockConfig.SetWithoutSource("admission_controller.resource_defaults.enabled", true)
+	mockConfig.SetWithoutSource("admission_controller.resource_defaults.enabled_namespaces", []string{"defaults", "audit"})
+	mockConfig.SetWithoutSource("admission_controller.resource_defaults.request_margin", 0)
+	mockConfig.SetWithoutSource("admission_controller.resource_defaults.limit_margin", 0.5)
+	webhook, err := NewWebhook(processor, mockConfig)
+	require.NoError(t, err)
+	// the usage is refreshed by the test instead of in the background
+	provider := newDatadogUsageProvider(processor, time.Hour, time.Hour)
+	webhook.usageProvider = provider
+
+	pod := newPod("defaults",
+		corev1.Container{Name: "app"},
+		corev1.Container{Name: "sidecar", Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+		}},
+	)
+	// the admissions don't wait for the usage to be queried
+	mutated, err := webhook.setResourceDefaults(pod, "defaults", nil)
+	require.NoError(t, err)
+	assert.False(t, mutated)
+	assert.Zero(t, processor.queries)
+
+	provider.refreshAll()
+	mutated, err = webhook.setResourceDefaults(pod, "defaults", nil)
+	require.NoError(t, err)
+	assert.True(t, mutated)
+
+	app := pod.Spec.Containers[0].Resources
+	assert.True(t, resource.MustParse("100m").Equal(app.Requests[corev1.ResourceCPU]))
+	assert.True(t, resource.MustParse("600m").Equal(app.Limits[corev1.ResourceCPU]))
+	assert.True(t, resource.MustParse("100Mi").Equal(app.Requests[corev1.ResourceMemory]))
+	assert.True(t, resource.MustParse("300Mi").Equal(app.Limits[corev1.ResourceMemory]))
+
+	// the resources set on the container are kept, the limit is never set below the request
+	// and the memory request defaults to the memory limit
+	sidecar := pod.Spec.Containers[1].Resources
+	assert.True(t, resource.MustParse("1").Equal(sidecar.Requests[corev1.ResourceCPU]))
+	assert.True(t, resource.MustParse("1").Equal(sidecar.Limits[corev1.ResourceCPU]))
+	assert.NotContains(t, sidecar.Requests, corev1.ResourceMemory)
+	assert.True(t, resource.MustParse("64Mi").Equal(sidecar.Limits[corev1.ResourceMemory]))
+
+	// the usage is cached
+	queries := processor.queries
+	_, err = webhook.setResourceDefaults(newPod("defaults", corev1.Container{Name: "app"}), "defaults", nil)
+	require.NoError(t, err)
+	assert.Equal(t, queries, processor.queries)
+
+	// audit only mode does not mutate the pod
+	webhook.auditOnly = true
+	pod = newPod("audit", corev1.Container{Name: "app"})
+	_, err = webhook.setResourceDefaults(pod, "audit", nil)
+	require.NoError(t, err)
+	provider.refreshAll()
+	mutated, err = webhook.setResourceDefaults(pod, "audit", nil)
+	require.NoError(t, err)
+	assert.False(t, mutated)
+	assert.Empty(t, pod.Spec.Containers[0].Resources)
+	webhook.auditOnly = false
+
+	// namespaces that are not enabled are skipped
+	queries = processor.queries
+	pod = newPod("other", corev1.Container{Name: "app"})
+	mutated, err = webhook.setResourceDefaults(pod, "other", nil)
+	require.NoError(t, err)
+	assert.False(t, mutated)
+	assert.Equal(t, queries, processor.queries)
+
+	// pods without owner are skipped
+	pod = newPod("defaults", corev1.Container{Name: "standalone"})
+	pod.OwnerReferences = nil
+	mutated, err = webhook.setResourceDefaults(pod, "defaults", nil)
+	require.NoError(t, err)
+	assert.False(t, mutated)
+}
+
+func TestSetResourceDefaultsNoData(t *testing.T) {
+	mockConfig := configmock.New(t)
+	mockConfig.SetWithoutSource("admission_controller.resource_defaults.enabled", true)
+	webhook, err := NewWebhook(&fakeProcessor{}, mockConfig)
+	require.NoError(t, err)
+	provider := newDatadogUsageProvider(&fakeProcessor{}, time.Hour, time.Hour)
+	webhook.usageProvider = provider
+
+	pod := newPod("nodata", corev1.Container{Name: "app"})
+	_, err = webhook.setResourceDefaults(pod, "nodata", nil)
+	require.NoError(t, err)
+	provider.refreshAll()
+	mutated, err := webhook.setResourceDefaults(pod, "nodata", nil)
+	require.NoError(t, err)
+	assert.False(t, mutated)
+	assert.Empty(t, pod.Spec.Containers[0].Resources)
+}
+
+// errorProcessor fails the queries while err is set, and returns the points of its fakeProcessor otherwise
+type errorProcessor struct {
+	fakeProcessor
+	err error
+}
+
+func (p *errorProcessor) QueryExternalMetric(queries []string, window time.Duration) map[string]autoscalers.Point {
+	if p.err == nil {
+		return p.fakeProcessor.QueryExternalMetric(queries, window)
+	}
+	p.queries += len(queries)
+	points := make(map[string]autoscalers.Point, len(queries))
+	for _, query := range queries {
+		points[query] = autoscalers.Point{Error: p.err}
+	}
+	return points
+}
+
+func TestGetContainerUsageError(t *testing.T) {
+	processor := &errorProcessor{err: errors.New("rate limited")}
+	provider := newDatadogUsageProvider(processor, time.Hour, time.Hour)
+	owner := workloadOwner{tagName: "kube_deployment", name: "web"}
+
+	_, err := provider.getContainerUsage("error", owner, "app")
+	assert.ErrorIs(t, err, errUsagePending)
+
+	// the errors are cached
+	provider.refreshAll()
+	queries := processor.queries
+	_, err = provider.getContainerUsage("error", owner, "app")
+	assert.ErrorContains(t, err, "rate limited")
+	_, err = provider.getContainerUsage("error", owner, "app")
+	assert.ErrorContains(t, err, "rate limited")
+	assert.Equal(t, queries, processor.queries)
+
+	// the usage is served once the query succeeds, and kept on a transient error
+	processor.err = nil
+	processor.points = map[string]autoscalers.Point{"avg:kubernetes.cpu.usage.total": {Value: 100e6, Valid: true}}
+	provider.refreshAll()
+	usage, err := provider.getContainerUsage("error", owner, "app")
+	require.NoError(t, err)
+	assert.Equal(t, 100e6, usage.cpuAverage)
+
+	processor.err = errors.New("rate limited")
+	provider.refreshAll()
+	usage, err = provider.getContainerUsage("error", owner, "app")
+	require.NoError(t, err)
+	assert.Equal(t, 100e6, usage.cpuAverage)
+
+	// the containers of the workloads that aren't admitted anymore are dropped
+	provider.entries[usageKey{namespace: "error", owner: owner, container: "app"}].requested = time.Now().Add(-usageIdleRefreshes*time.Hour - time.Minute)
+	provider.refreshAll()
+	assert.Empty(t, provider.entries)
+}
+
+func TestGetWorkloadOwner(t *testing.T) {
+	tests := []struct {
+		name          string
+		ownerRef      metav1.OwnerReference
+		expected      workloadOwner
+		expectedFound bool
+	}{
+		{
+			name:          "deployment",
+			ownerRef:      metav1.OwnerReference{Kind: "ReplicaSet", Name: "web-6d4cf56db6", Controller: pointer.Ptr(true)},
+			expected:      workloadOwner{tagName: "kube_deployment", name: "web"},
+			expectedFound: true,
+		},
+		{
+			name:          "statefulset",
+			ownerRef:      metav1.OwnerReference{Kind: "StatefulSet", Name: "db", Controller: pointer.Ptr(true)},
+			expected:      workloadOwner{tagName: "kube_stateful_set", name: "db"},
+			expectedFound: true,
+		},
+		{
+			name:          "cronjob",
+			ownerRef:      metav1.OwnerReference{Kind: "Job", Name: "backup-28977645", Controller: pointer.Ptr(true)},
+			expected:      workloadOwner{tagName: "kube_cronjob", name: "backup"},
+			expectedFound: true,
+		},
+		{
+			name:     "not a controller",
+			ownerRef: metav1.OwnerReference{Kind: "StatefulSet", Name: "db"},
+		},
+		{
+			name:     "unsupported kind",
+			ownerRef: metav1.OwnerReference{Kind: "Node", Name: "node", Controller: pointer.Ptr(true)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{tt.ownerRef}}}
+			owner, found := getWorkloadOwner(pod)
+			assert.Equal(t, tt.expectedFound, found)
+			assert.Equal(t, tt.expected, owner)
+		})
+	}
+}
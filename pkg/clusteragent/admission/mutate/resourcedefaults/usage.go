@@ -0,0 +1,260 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package resourcedefaults
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/DataDog/datadog-agent/comp/core/tagger/tags"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/autoscalers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// usageQueueSize bounds the containers waiting for their first usage query,
+	// the other ones are queried by the next refresh
+	usageQueueSize = 1000
+	// usageIdleRefreshes is the number of refreshes after which the usage of the
+	// containers whose workload wasn't admitted is dropped
+	usageIdleRefreshes = 3
+	// minUsageCacheTTL bounds the rate of the refreshes
+	minUsageCacheTTL = time.Minute
+
+	cpuUsageMetric    = "kubernetes.cpu.usage.total"
+	memoryUsageMetric = "kubernetes.memory.working_set"
+)
+
+// containerUsage is the historical usage of a container, CPU is in nanocores and memory in bytes.
+// A zero value means that no usage is known for the resource.
+type containerUsage struct {
+	cpuAverage    float64
+	cpuMax        float64
+	memoryAverage float64
+	memoryMax     float64
+}
+
+// usageProvider returns the historical usage of the containers of a workload
+type usageProvider interface {
+	getContainerUsage(namespace string, owner workloadOwner, container string) (*containerUsage, error)
+}
+
+// workloadOwner identifies the workload of a pod with the tag of its metrics
+type workloadOwner struct {
+	tagName string
+	name    string
+}
+
+func (o workloadOwner) String() string {
+	return o.tagName + ":" + o.name
+}
+
+// getWorkloadOwner returns the workload owning the pod, false if the pod is not owned by a supported workload
+func getWorkloadOwner(pod *corev1.Pod) (workloadOwner, bool) {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller == nil || !*ref.Controller {
+			continue
+		}
+
+		switch ref.Kind {
+		case kubernetes.ReplicaSetKind:
+			if deployment := kubernetes.ParseDeploymentForReplicaSet(ref.Name); deployment != "" {
+				return workloadOwner{tagName: tags.KubeDeployment, name: deployment}, true
+			}
+			return workloadOwner{tagName: tags.KubeReplicaSet, name: ref.Name}, true
+		case kubernetes.StatefulSetKind:
+			return workloadOwner{tagName: tags.KubeStatefulSet, name: ref.Name}, true
+		case kubernetes.DaemonSetKind:
+			return workloadOwner{tagName: tags.KubeDaemonSet, name: ref.Name}, true
+		case kubernetes.JobKind:
+			if cronjob, _ := kubernetes.ParseCronJobForJob(ref.Name); cronjob != "" {
+				return workloadOwner{tagName: tags.KubeCronjob, name: cronjob}, true
+			}
+			return workloadOwner{tagName: tags.KubeJob, name: ref.Name}, true
+		}
+	}
+	return workloadOwner{}, false
+}
+
+// errUsagePending is returned for the containers whose usage wasn't queried yet
+var errUsagePending = errors.New("the usage of the workload is being queried")
+
+// usageKey identifies a container of a workload
+type usageKey struct {
+	namespace string
+	owner     workloadOwner
+	container string
+}
+
+// usageEntry is the cached usage of a container. The error of the last query is
+// cached as well, so that the admissions don't retry it.
+type usageEntry struct {
+	usage     *containerUsage
+	err       error
+	fetched   time.Time
+	requested time.Time
+}
+
+// datadogUsageProvider serves the historical usage of the containers from a cache,
+// refreshed in the background from the Datadog metrics so that an admission never
+// waits for a query. The usage of a container is queried the first time it is
+// requested, refreshed every cacheTTL, and dropped once its workload wasn't
+// admitted for usageIdleRefreshes refreshes.
+type datadogUsageProvider struct {
+	processor autoscalers.QueryProcessor
+	window    time.Duration
+	cacheTTL  time.Duration
+
+	mu      sync.Mutex
+	entries map[usageKey]*usageEntry
+	// queue holds the containers requested for the first time
+	queue chan usageKey
+}
+
+func newDatadogUsageProvider(processor autoscalers.QueryProcessor, window, cacheTTL time.Duration) *datadogUsageProvider {
+	if cacheTTL < minUsageCacheTTL {
+		cacheTTL = minUsageCacheTTL
+	}
+	return &datadogUsageProvider{
+		processor: processor,
+		window:    window,
+		cacheTTL:  cacheTTL,
+		entries:   make(map[usageKey]*usageEntry),
+		queue:     make(chan usageKey, usageQueueSize),
+	}
+}
+
+// run queries the usage of the new containers and refreshes the cached ones, for
+// the lifetime of the cluster agent
+func (p *datadogUsageProvider) run() {
+	ticker := time.NewTicker(p.cacheTTL)
+	defer ticker.Stop()
+	for {
+		select {
+		case key := <-p.queue:
+			p.refresh(key)
+		case <-ticker.C:
+			p.refreshAll()
+		}
+	}
+}
+
+// getContainerUsage returns the cached usage of the container, nil if there is no usage
+// data, errUsagePending if it wasn't queried yet or the error of its last query.
+func (p *datadogUsageProvider) getContainerUsage(namespace string, owner workloadOwner, container string) (*containerUsage, error) {
+	key := usageKey{namespace: namespace, owner: owner, container: container}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, found := p.entries[key]
+	if !found {
+		entry = &usageEntry{}
+		p.entries[key] = entry
+		select {
+		case p.queue <- key:
+		default:
+			// the container is queried by the next refresh
+		}
+	}
+	entry.requested = time.Now()
+
+	if entry.fetched.IsZero() {
+		return nil, errUsagePending
+	}
+	return entry.usage, entry.err
+}
+
+// refreshAll refreshes the usage of the cached containers, and drops the ones whose
+// workload wasn't admitted recently
+func (p *datadogUsageProvider) refreshAll() {
+	p.mu.Lock()
+	keys := make([]usageKey, 0, len(p.entries))
+	for key, entry := range p.entries {
+		if time.Since(entry.requested) > usageIdleRefreshes*p.cacheTTL {
+			delete(p.entries, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	p.mu.Unlock()
+
+	for _, key := range keys {
+		p.refresh(key)
+	}
+}
+
+// refresh queries the usage of the container and caches it. On a transient error the
+// previous usage is kept if any, the error is cached otherwise.
+func (p *datadogUsageProvider) refresh(key usageKey) {
+	usage, err := p.queryContainerUsage(key)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, found := p.entries[key]
+	if !found {
+		return
+	}
+	if err != nil && !entry.fetched.IsZero() && entry.err == nil {
+		log.Debugf("Keeping the previous usage of %s/%s: %v", key.namespace, key.owner, err)
+		return
+	}
+	entry.usage = usage
+	entry.err = err
+	entry.fetched = time.Now()
+}
+
+// queryContainerUsage returns the usage of the container over the window, nil if there is no usage data
+func (p *datadogUsageProvider) queryContainerUsage(key usageKey) (*containerUsage, error) {
+	scope := fmt.Sprintf("%s:%s,%s,%s:%s", tags.KubeNamespace, key.namespace, key.owner, tags.KubeContainerName, key.container)
+	rollup := int64(p.window.Seconds())
+	usage := &containerUsage{}
+	queries := map[string]*float64{
+		usageQuery("avg", cpuUsageMetric, scope, rollup):    &usage.cpuAverage,
+		usageQuery("max", cpuUsageMetric, scope, rollup):    &usage.cpuMax,
+		usageQuery("avg", memoryUsageMetric, scope, rollup): &usage.memoryAverage,
+		usageQuery("max", memoryUsageMetric, scope, rollup): &usage.memoryMax,
+	}
+
+	batch := make([]string, 0, len(queries))
+	for query := range queries {
+		batch = append(batch, query)
+	}
+
+	found := false
+	points := p.processor.QueryExternalMetric(batch, p.window)
+	for query, value := range queries {
+		point := points[query]
+		if point.Error != nil {
+			// Processing errors mean that the workload has no data, the other ones are transient
+			var processingErr *autoscalers.ProcessingError
+			if !errors.As(point.Error, &processingErr) {
+				return nil, fmt.Errorf("unable to query the usage of %s/%s: %w", key.namespace, key.owner, point.Error)
+			}
+			continue
+		}
+		if point.Valid && point.Value > 0 {
+			*value = point.Value
+			found = true
+		}
+	}
+
+	if !found {
+		return nil, nil
+	}
+	return usage, nil
+}
+
+// usageQuery returns the query aggregating the metric over the whole window
+func usageQuery(aggregator, metric, scope string, rollup int64) string {
+	return fmt.Sprintf("%s:%s{%s}.rollup(%s, %d)", aggregator, metric, scope, aggregator, rollup)
+}
@@ -12,14 +12,19 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/comp/aggregator/demultiplexer"
+	datadogclient "github.com/DataDog/datadog-agent/comp/autoscaling/datadogclient/def"
 	"github.com/DataDog/datadog-agent/comp/core/config"
 	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/controllers/secret"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/controllers/webhook"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/workload"
+	rcclient "github.com/DataDog/datadog-agent/pkg/config/remote/client"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/common"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/autoscalers"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/option"
 
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
@@ -36,6 +41,13 @@ type ControllerContext struct {
 	StopCh                       chan struct{}
 	ValidatingStopCh             chan struct{}
 	Demultiplexer                demultiplexer.Component
+	RcClient                     *rcclient.Client
+	DatadogClient                option.Option[datadogclient.Component]
+}
+
+// remoteConfigWebhook is implemented by the webhooks whose configuration can be updated through remote config
+type remoteConfigWebhook interface {
+	UpdateRemoteConfig(updates map[string]state.RawConfig, applyStateCallback func(string, state.ApplyStatus))
 }
 
 // StartControllers starts the secret and webhook controllers
@@ -80,6 +92,12 @@ func StartControllers(ctx ControllerContext, wmeta workloadmeta.Component, pa wo
 		return webhooks, err
 	}
 
+	// The historical usage used by the resource defaults webhook is queried from the Datadog API
+	var usageProcessor autoscalers.QueryProcessor
+	if dc, ok := ctx.DatadogClient.Get(); ok {
+		usageProcessor = autoscalers.NewProcessor(dc)
+	}
+
 	webhookConfig := webhook.NewConfig(v1Enabled, nsSelectorEnabled, matchConditionsSupported, datadogConfig)
 	webhookController := webhook.NewController(
 		ctx.Client,
@@ -93,6 +111,7 @@ func StartControllers(ctx ControllerContext, wmeta workloadmeta.Component, pa wo
 		pa,
 		datadogConfig,
 		ctx.Demultiplexer,
+		usageProcessor,
 	)
 
 	go secretController.Run(ctx.StopCh)
@@ -120,5 +139,14 @@ func StartControllers(ctx ControllerContext, wmeta workloadmeta.Component, pa wo
 
 	webhooks = append(webhooks, webhookController.EnabledWebhooks()...)
 
+	if ctx.RcClient != nil && datadogConfig.GetBool("admission_controller.auto_instrumentation.remote_config.enabled") {
+		for _, w := range webhooks {
+			if rcWebhook, ok := w.(remoteConfigWebhook); ok {
+				log.Infof("Subscribing webhook %s to remote config", w.Name())
+				ctx.RcClient.Subscribe(state.ProductAPMInstrumentation, rcWebhook.UpdateRemoteConfig)
+			}
+		}
+	}
+
 	return webhooks, apiserver.SyncInformers(informers, 0)
 }
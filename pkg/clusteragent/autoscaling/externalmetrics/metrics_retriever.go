@@ -33,16 +33,19 @@ type MetricsRetriever struct {
 	metricsMaxAge             int64
 	splitBatchBackoffOnErrors bool
 	processor                 autoscalers.ProcessorInterface
+	prometheusProcessor       autoscalers.QueryProcessor
 	store                     *DatadogMetricsInternalStore
 	isLeader                  func() bool
 }
 
 // NewMetricsRetriever returns a new MetricsRetriever
-func NewMetricsRetriever(refreshPeriod, metricsMaxAge int64, processor autoscalers.ProcessorInterface, isLeader func() bool, store *DatadogMetricsInternalStore, splitBatchBackoffOnErrors bool) (*MetricsRetriever, error) {
+// Queries of the DatadogMetrics using the prometheus query backend are evaluated by prometheusProcessor.
+func NewMetricsRetriever(refreshPeriod, metricsMaxAge int64, processor autoscalers.ProcessorInterface, prometheusProcessor autoscalers.QueryProcessor, isLeader func() bool, store *DatadogMetricsInternalStore, splitBatchBackoffOnErrors bool) (*MetricsRetriever, error) {
 	return &MetricsRetriever{
 		refreshPeriod:             refreshPeriod,
 		metricsMaxAge:             metricsMaxAge,
 		processor:                 processor,
+		prometheusProcessor:       prometheusProcessor,
 		store:                     store,
 		isLeader:                  isLeader,
 		splitBatchBackoffOnErrors: splitBatchBackoffOnErrors,
@@ -115,16 +118,18 @@ func (mr *MetricsRetriever) retrieveMetricsValuesSlice(datadogMetrics []model.Da
 		return
 	}
 
-	queriesByTimeWindow := getBatchedQueriesByTimeWindow(datadogMetrics)
-	resultsByTimeWindow := make(map[time.Duration]map[string]autoscalers.Point)
-
-	for timeWindow, queries := range queriesByTimeWindow {
-		log.Debugf("Starting refreshing external metrics with: %d queries (window: %d)", len(queries), timeWindow)
-
-		results := mr.processor.QueryExternalMetric(queries, timeWindow)
-		resultsByTimeWindow[timeWindow] = results
+	var datadogQueryMetrics, prometheusQueryMetrics []model.DatadogMetricInternal
+	for _, datadogMetric := range datadogMetrics {
+		if datadogMetric.PrometheusQuery {
+			prometheusQueryMetrics = append(prometheusQueryMetrics, datadogMetric)
+		} else {
+			datadogQueryMetrics = append(datadogQueryMetrics, datadogMetric)
+		}
 	}
 
+	resultsByTimeWindow := queryMetricsValues(mr.processor, datadogQueryMetrics)
+	prometheusResultsByTimeWindow := queryMetricsValues(mr.prometheusProcessor, prometheusQueryMetrics)
+
 	// Update store with current results
 	currentTime := time.Now().UTC()
 	for _, datadogMetric := range datadogMetrics {
@@ -138,9 +143,12 @@ func (mr *MetricsRetriever) retrieveMetricsValuesSlice(datadogMetrics []model.Da
 		query := datadogMetric.Query()
 		timeWindow := maybeAdjustTimeWindowForQuery(datadogMetric.GetTimeWindow())
 		results := resultsByTimeWindow[timeWindow]
+		if datadogMetric.PrometheusQuery {
+			results = prometheusResultsByTimeWindow[timeWindow]
+		}
 
 		if queryResult, found := results[query]; found {
-			log.Debugf("QueryResult from DD for %q: %v", query, queryResult)
+			log.Debugf("QueryResult for %q: %v", query, queryResult)
 
 			if queryResult.Valid {
 				if mr.splitBatchBackoffOnErrors {
@@ -179,6 +187,21 @@ func (mr *MetricsRetriever) retrieveMetricsValuesSlice(datadogMetrics []model.Da
 	}
 }
 
+// queryMetricsValues queries the values of the DatadogMetrics, batching the queries by time window
+func queryMetricsValues(processor autoscalers.QueryProcessor, datadogMetrics []model.DatadogMetricInternal) map[time.Duration]map[string]autoscalers.Point {
+	queriesByTimeWindow := getBatchedQueriesByTimeWindow(datadogMetrics)
+	resultsByTimeWindow := make(map[time.Duration]map[string]autoscalers.Point, len(queriesByTimeWindow))
+
+	for timeWindow, queries := range queriesByTimeWindow {
+		log.Debugf("Starting refreshing external metrics with: %d queries (window: %d)", len(queries), timeWindow)
+
+		results := processor.QueryExternalMetric(queries, timeWindow)
+		resultsByTimeWindow[timeWindow] = results
+	}
+
+	return resultsByTimeWindow
+}
+
 func incrementRetries(metricsInternal *model.DatadogMetricInternal) {
 	metricsInternal.Retries++
 	timeNow := time.Now().UTC()
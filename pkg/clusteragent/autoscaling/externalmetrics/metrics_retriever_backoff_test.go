@@ -72,7 +72,7 @@ func (f *metricsFixtureWithBackoff) runWithBackoff(t *testing.T, _ time.Time) {
 		points:          f.queryResults,
 		extQueryCounter: 0,
 	}
-	metricsRetriever, err := NewMetricsRetriever(0, f.maxAge, &mockedProcessor, getIsLeaderFunction(true), &store, true)
+	metricsRetriever, err := NewMetricsRetriever(0, f.maxAge, &mockedProcessor, &mockedProcessor, getIsLeaderFunction(true), &store, true)
 	assert.Nil(t, err)
 	metricsRetriever.retrieveMetricsValues()
 
@@ -121,7 +121,7 @@ func (f *metricsFixtureWithBackoff) runQueryOnly(t *testing.T) {
 		points:          f.queryResults,
 		extQueryCounter: 0,
 	}
-	metricsRetriever, err := NewMetricsRetriever(0, f.maxAge, &mockedProcessor, getIsLeaderFunction(true), &store, true)
+	metricsRetriever, err := NewMetricsRetriever(0, f.maxAge, &mockedProcessor, &mockedProcessor, getIsLeaderFunction(true), &store, true)
 	assert.Nil(t, err)
 	metricsRetriever.retrieveMetricsValues()
 	assert.Equal(t, f.extQueryCount, mockedProcessor.extQueryCounter)
@@ -55,7 +55,9 @@ type metricsFixture struct {
 	maxAge       int64
 	storeContent []ddmWithQuery
 	queryResults map[string]autoscalers.Point
-	expected     []ddmWithQuery
+	// prometheusQueryResults are the results of the queries using the prometheus query backend
+	prometheusQueryResults map[string]autoscalers.Point
+	expected               []ddmWithQuery
 }
 
 func (f *metricsFixture) run(t *testing.T) {
@@ -72,7 +74,10 @@ func (f *metricsFixture) run(t *testing.T) {
 	mockedProcessor := mockedProcessor{
 		points: f.queryResults,
 	}
-	metricsRetriever, err := NewMetricsRetriever(0, f.maxAge, &mockedProcessor, getIsLeaderFunction(true), &store, false)
+	prometheusProcessor := mockedProcessor{
+		points: f.prometheusQueryResults,
+	}
+	metricsRetriever, err := NewMetricsRetriever(0, f.maxAge, &mockedProcessor, &prometheusProcessor, getIsLeaderFunction(true), &store, false)
 	assert.Nil(t, err)
 	metricsRetriever.retrieveMetricsValues()
 
@@ -166,6 +171,72 @@ func TestRetrieveMetricsBasic(t *testing.T) {
 				},
 			},
 		},
+		{
+			maxAge: 30,
+			desc:   "Test queries using the prometheus query backend",
+			storeContent: []ddmWithQuery{
+				{
+					ddm: model.DatadogMetricInternal{
+						ID:       "metric0",
+						Active:   true,
+						DataTime: defaultPreviousUpdateTime,
+						Valid:    true,
+						Error:    nil,
+					},
+					query: "query-metric0",
+				},
+				{
+					ddm: model.DatadogMetricInternal{
+						ID:              "metric1",
+						Active:          true,
+						PrometheusQuery: true,
+						DataTime:        defaultPreviousUpdateTime,
+						Valid:           false,
+						Error:           nil,
+					},
+					query: "query-metric0",
+				},
+			},
+			queryResults: map[string]autoscalers.Point{
+				"query-metric0": {
+					Value:     10.0,
+					Timestamp: defaultTestTime.Unix(),
+					Valid:     true,
+				},
+			},
+			prometheusQueryResults: map[string]autoscalers.Point{
+				"query-metric0": {
+					Value:     20.0,
+					Timestamp: defaultTestTime.Unix(),
+					Valid:     true,
+				},
+			},
+			expected: []ddmWithQuery{
+				{
+					ddm: model.DatadogMetricInternal{
+						ID:       "metric0",
+						Active:   true,
+						Value:    10.0,
+						DataTime: defaultTestTime,
+						Valid:    true,
+						Error:    nil,
+					},
+					query: "query-metric0",
+				},
+				{
+					ddm: model.DatadogMetricInternal{
+						ID:              "metric1",
+						Active:          true,
+						PrometheusQuery: true,
+						Value:           20.0,
+						DataTime:        defaultTestTime,
+						Valid:           true,
+						Error:           nil,
+					},
+					query: "query-metric0",
+				},
+			},
+		},
 	}
 
 	for i, fixture := range fixtures {
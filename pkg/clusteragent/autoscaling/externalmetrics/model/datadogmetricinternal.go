@@ -28,6 +28,8 @@ import (
 const (
 	DatadogMetricErrorConditionReason string = "Unable to fetch data from Datadog"
 	alwaysActiveAnnotation            string = "external-metrics.datadoghq.com/always-active"
+	queryBackendAnnotation            string = "external-metrics.datadoghq.com/query-backend"
+	prometheusQueryBackend            string = "prometheus"
 )
 
 // DatadogMetricInternal is a flatten, easier to use, representation of `DatadogMetric` CRD
@@ -38,6 +40,7 @@ type DatadogMetricInternal struct {
 	Valid                bool
 	Active               bool
 	AlwaysActive         bool
+	PrometheusQuery      bool
 	Deleted              bool
 	Autogen              bool
 	ExternalMetricName   string
@@ -61,6 +64,7 @@ func NewDatadogMetricInternal(id string, datadogMetric datadoghq.DatadogMetric)
 		Valid:                false,
 		Active:               false,
 		AlwaysActive:         hasForceActiveAnnotation(datadogMetric),
+		PrometheusQuery:      hasPrometheusQueryBackendAnnotation(datadogMetric),
 		Deleted:              false,
 		Autogen:              false,
 		AutoscalerReferences: datadogMetric.Status.AutoscalerReferences,
@@ -121,6 +125,20 @@ func hasForceActiveAnnotation(metric datadoghq.DatadogMetric) bool {
 	return false
 }
 
+// hasPrometheusQueryBackendAnnotation returns whether the query should be evaluated
+// by the Prometheus endpoint instead of the Datadog API
+func hasPrometheusQueryBackendAnnotation(metric datadoghq.DatadogMetric) bool {
+	value, found := metric.Annotations[queryBackendAnnotation]
+	if !found {
+		return false
+	}
+	if value != prometheusQueryBackend {
+		log.Debugf("Unknown value from %s annotation: '%s', using the Datadog API", queryBackendAnnotation, value)
+		return false
+	}
+	return true
+}
+
 // NewDatadogMetricInternalFromExternalMetric returns a `DatadogMetricInternal` object
 // that is auto-generated from a standard ExternalMetric query (non-DatadogMetric reference)
 func NewDatadogMetricInternalFromExternalMetric(id, query, metricName, autoscalerReference string) DatadogMetricInternal {
@@ -154,6 +172,7 @@ func (d *DatadogMetricInternal) RawQuery() string {
 // UpdateFrom updates the `DatadogMetricInternal` from `DatadogMetric`
 func (d *DatadogMetricInternal) UpdateFrom(current datadoghq.DatadogMetric) {
 	currentSpec := current.Spec
+	prometheusQuery := hasPrometheusQueryBackendAnnotation(current)
 
 	if d.shouldResolveQuery(currentSpec) {
 		d.resolveQuery(currentSpec.Query)
@@ -163,7 +182,8 @@ func (d *DatadogMetricInternal) UpdateFrom(current datadoghq.DatadogMetric) {
 	// right away we reset retry count and backoff.
 	if d.query != currentSpec.Query ||
 		d.MaxAge != currentSpec.MaxAge.Duration ||
-		d.TimeWindow != currentSpec.TimeWindow.Duration {
+		d.TimeWindow != currentSpec.TimeWindow.Duration ||
+		d.PrometheusQuery != prometheusQuery {
 		d.Retries = 0
 		d.RetryAfter = time.Time{}
 	}
@@ -172,6 +192,7 @@ func (d *DatadogMetricInternal) UpdateFrom(current datadoghq.DatadogMetric) {
 	d.MaxAge = currentSpec.MaxAge.Duration
 	d.TimeWindow = currentSpec.TimeWindow.Duration
 	d.AlwaysActive = hasForceActiveAnnotation(current)
+	d.PrometheusQuery = prometheusQuery
 }
 
 // GetTimeWindow gets the time window for the metric, if unset defaults to max age.
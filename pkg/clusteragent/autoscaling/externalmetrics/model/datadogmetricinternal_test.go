@@ -39,6 +39,7 @@ func TestDatadogMetricInternal_UpdateFrom(t *testing.T) {
 		expectedTimewindow    time.Duration
 		expectedMaxAge        time.Duration
 		expectedAlwaysActive  bool
+		expectedPrometheus    bool
 		expectedRetries       int
 		expectedRetryAfter    time.Time
 	}{
@@ -314,6 +315,54 @@ func TestDatadogMetricInternal_UpdateFrom(t *testing.T) {
 			expectedRetries:       0,
 			expectedRetryAfter:    time.Time{},
 		},
+		{
+			name: "same query - prometheus query backend",
+			ddmInternal: &DatadogMetricInternal{
+				query:         simpleQuery,
+				resolvedQuery: &simpleQuery,
+				Retries:       1,
+				RetryAfter:    currentTime,
+			},
+			new: datadoghq.DatadogMetric{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{
+						queryBackendAnnotation: "prometheus",
+					},
+				},
+				Spec: datadoghq.DatadogMetricSpec{
+					Query: simpleQuery,
+				},
+			},
+			expectedQuery:         simpleQuery,
+			expectedResolvedQuery: &simpleQuery,
+			expectedPrometheus:    true,
+			expectedRetries:       0,
+			expectedRetryAfter:    time.Time{},
+		},
+		{
+			name: "same query - unknown query backend",
+			ddmInternal: &DatadogMetricInternal{
+				query:         simpleQuery,
+				resolvedQuery: &simpleQuery,
+				Retries:       1,
+				RetryAfter:    currentTime,
+			},
+			new: datadoghq.DatadogMetric{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{
+						queryBackendAnnotation: "graphite",
+					},
+				},
+				Spec: datadoghq.DatadogMetricSpec{
+					Query: simpleQuery,
+				},
+			},
+			expectedQuery:         simpleQuery,
+			expectedResolvedQuery: &simpleQuery,
+			expectedPrometheus:    false,
+			expectedRetries:       1,
+			expectedRetryAfter:    currentTime,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -327,6 +376,7 @@ func TestDatadogMetricInternal_UpdateFrom(t *testing.T) {
 
 			assert.Equal(t, tt.expectedMaxAge, tt.ddmInternal.MaxAge)
 			assert.Equal(t, tt.expectedAlwaysActive, tt.ddmInternal.AlwaysActive)
+			assert.Equal(t, tt.expectedPrometheus, tt.ddmInternal.PrometheusQuery)
 		})
 	}
 }
@@ -169,6 +169,8 @@ func (d *dispatcher) expireNodes() {
 			dispatchedConfigs.Delete(name, le.JoinLeaderValue)
 			statsCollectionFails.Delete(name, le.JoinLeaderValue)
 			busyness.Delete(name, le.JoinLeaderValue)
+			runnerCPUUsage.Delete(name, le.JoinLeaderValue)
+			runnerMemoryUsage.Delete(name, le.JoinLeaderValue)
 		}
 		node.RUnlock()
 	}
@@ -209,6 +211,20 @@ func (d *dispatcher) updateRunnersStats() {
 			}
 		}
 
+		if pkgconfigsetup.Datadog().GetBool("cluster_checks.rebalance_with_resource_usage") {
+			resources, err := d.clcRunnersClient.GetRunnerResources(ip)
+			if err != nil {
+				// This can happen in old versions of the runners that do not expose this information.
+				// The runner is then ignored when rebalancing on the resource usage.
+				log.Debugf("Cannot get resource usage for node %s with IP %s. Error: %v", name, node.clientIP, err)
+				node.resources = nil
+			} else {
+				node.resources = &resources
+				runnerCPUUsage.Set(resources.CPUUsage, node.name, le.JoinLeaderValue)
+				runnerMemoryUsage.Set(float64(resources.MemoryUsage), node.name, le.JoinLeaderValue)
+			}
+		}
+
 		stats, err := d.clcRunnersClient.GetRunnerStats(ip)
 		if err != nil {
 			log.Debugf("Cannot get CLC Runner stats with IP %s on node %s: %v", node.clientIP, name, err)
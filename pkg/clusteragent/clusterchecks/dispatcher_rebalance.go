@@ -172,6 +172,10 @@ func (d *dispatcher) moveCheck(src, dest, checkID string) error {
 }
 
 func (d *dispatcher) rebalance(force bool) []types.RebalanceResponse {
+	if pkgconfigsetup.Datadog().GetBool("cluster_checks.rebalance_with_resource_usage") {
+		return d.rebalanceUsingResourceUsage(force)
+	}
+
 	if pkgconfigsetup.Datadog().GetBool("cluster_checks.rebalance_with_utilization") {
 		return d.rebalanceUsingUtilization(force)
 	}
@@ -0,0 +1,267 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build clusterchecks
+
+package clusterchecks
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	le "github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/leaderelection/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// checkLoad is the share of the resource usage of a runner attributed to a cluster check
+type checkLoad struct {
+	cpu    float64
+	memory float64
+}
+
+// runnerLoad is the resource usage of a runner and the estimated usage of the
+// cluster checks that can be moved away from it
+type runnerLoad struct {
+	cpu    float64
+	memory float64
+	checks map[string]checkLoad
+}
+
+// resourceMove is a check move proposed by proposeResourceMoves
+type resourceMove struct {
+	checkID string
+	src     string
+	dest    string
+}
+
+// resourceAverages is the average resource usage of the runners, used to
+// compare the load of runners with different CPU and memory profiles
+type resourceAverages struct {
+	cpu    float64
+	memory float64
+}
+
+// pressure returns how loaded the runner is compared to the average, 1
+// meaning that its most used resource is at the average
+func (a resourceAverages) pressure(cpu, memory float64) float64 {
+	return math.Max(ratio(cpu, a.cpu), ratio(memory, a.memory))
+}
+
+func ratio(value, average float64) float64 {
+	if average <= 0 {
+		return 0
+	}
+	return value / average
+}
+
+// rebalanceUsingResourceUsage rebalances the cluster checks by taking into
+// account the CPU and memory usage reported by the runners instead of only
+// relying on the check stats.
+//
+// Some checks (e.g. SNMP checks monitoring large devices) use a lot more
+// resources than what their execution time and number of metric samples
+// suggest, the runners running them get hot while the busyness of all the
+// runners looks similar. The usage of a runner is attributed to its checks
+// proportionally to their busyness, then the most expensive cluster checks
+// are moved from the runners using more than the average (plus the
+// cluster_checks.rebalance_resource_usage_tolerance ratio) to the least loaded
+// runners.
+//
+// The runners that don't report their resource usage (e.g. older versions)
+// are ignored. When forced, the checks are moved from all the runners above
+// the average.
+func (d *dispatcher) rebalanceUsingResourceUsage(force bool) []types.RebalanceResponse {
+	// Collect CLC runners stats and update cache before rebalancing
+	d.updateRunnersStats()
+
+	start := time.Now()
+	defer func() {
+		rebalancingDuration.Set(time.Since(start).Seconds(), le.JoinLeaderValue)
+	}()
+
+	tolerance := pkgconfigsetup.Datadog().GetFloat64("cluster_checks.rebalance_resource_usage_tolerance")
+	if force {
+		tolerance = 0
+	}
+
+	var checksMoved []types.RebalanceResponse
+	for _, move := range proposeResourceMoves(d.currentResourceLoads(), tolerance) {
+		rebalancingDecisions.Inc(le.JoinLeaderValue)
+
+		err := d.moveCheck(move.src, move.dest, move.checkID)
+		if err != nil {
+			log.Warnf("Cannot move check %s: %v", move.checkID, err)
+			continue
+		}
+
+		successfulRebalancing.Inc(le.JoinLeaderValue)
+		log.Infof("Moved check %s from runner %s to runner %s to balance the resource usage", move.checkID, move.src, move.dest)
+
+		checksMoved = append(checksMoved, types.RebalanceResponse{
+			CheckID:        move.checkID,
+			SourceNodeName: move.src,
+			DestNodeName:   move.dest,
+		})
+	}
+
+	return checksMoved
+}
+
+// currentResourceLoads returns the load of the runners reporting their resource usage
+func (d *dispatcher) currentResourceLoads() map[string]*runnerLoad {
+	d.store.RLock()
+	defer d.store.RUnlock()
+
+	loads := make(map[string]*runnerLoad, len(d.store.nodes))
+	for nodeName, node := range d.store.nodes {
+		node.RLock()
+		if node.resources == nil {
+			node.RUnlock()
+			continue
+		}
+
+		load := &runnerLoad{
+			cpu:    node.resources.CPUUsage,
+			memory: float64(node.resources.MemoryUsage),
+			checks: map[string]checkLoad{},
+		}
+
+		// All the checks of the runner, including the node checks, are used to
+		// attribute the usage but only the cluster checks can be moved
+		totalBusyness := calculateBusyness(node.clcRunnerStats)
+		for checkID, stats := range node.clcRunnerStats {
+			checkBusyness := busynessFunc(stats)
+			if !stats.IsClusterCheck || totalBusyness <= 0 || checkBusyness <= 0 {
+				continue
+			}
+			if _, excluded := d.excludedChecksFromDispatching[checkid.IDToCheckName(checkid.ID(checkID))]; excluded {
+				continue
+			}
+
+			share := float64(checkBusyness) / float64(totalBusyness)
+			load.checks[checkID] = checkLoad{
+				cpu:    share * load.cpu,
+				memory: share * load.memory,
+			}
+		}
+
+		loads[nodeName] = load
+		node.RUnlock()
+	}
+
+	return loads
+}
+
+// proposeResourceMoves returns the checks to move so that no runner uses more
+// than the average resource usage multiplied by 1+tolerance. The most
+// expensive checks of the most loaded runners are moved first, to the runner
+// that would be the least loaded after receiving them, and only if the
+// destination stays less loaded than the source after the move. A check is
+// moved at most once.
+func proposeResourceMoves(loads map[string]*runnerLoad, tolerance float64) []resourceMove {
+	if len(loads) < 2 {
+		return nil
+	}
+
+	var averages resourceAverages
+	for _, load := range loads {
+		averages.cpu += load.cpu
+		averages.memory += load.memory
+	}
+	averages.cpu /= float64(len(loads))
+	averages.memory /= float64(len(loads))
+
+	pressure := func(runner string) float64 {
+		return averages.pressure(loads[runner].cpu, loads[runner].memory)
+	}
+
+	runners := make([]string, 0, len(loads))
+	for runner := range loads {
+		runners = append(runners, runner)
+	}
+	sort.Slice(runners, func(i, j int) bool {
+		if pressure(runners[i]) == pressure(runners[j]) {
+			return runners[i] < runners[j]
+		}
+		return pressure(runners[i]) > pressure(runners[j])
+	})
+
+	var moves []resourceMove
+	for _, src := range runners {
+		source := loads[src]
+		if pressure(src) <= 1+tolerance {
+			// The runners are sorted, the next ones are not hot either
+			break
+		}
+
+		for _, checkID := range source.checksSortedByCost(averages) {
+			if pressure(src) <= 1+tolerance {
+				break
+			}
+
+			check := source.checks[checkID]
+			dest, destPressure := bestDestination(loads, runners, src, check, averages)
+			destination := loads[dest]
+
+			srcPressure := averages.pressure(source.cpu-check.cpu, source.memory-check.memory)
+			if destPressure >= srcPressure {
+				// Moving this check would only move the problem, try a smaller one
+				continue
+			}
+
+			source.cpu -= check.cpu
+			source.memory -= check.memory
+			delete(source.checks, checkID)
+			destination.cpu += check.cpu
+			destination.memory += check.memory
+
+			moves = append(moves, resourceMove{checkID: checkID, src: src, dest: dest})
+		}
+	}
+
+	return moves
+}
+
+// checksSortedByCost returns the checks of the runner, the most expensive first
+func (l *runnerLoad) checksSortedByCost(averages resourceAverages) []string {
+	checkIDs := make([]string, 0, len(l.checks))
+	for checkID := range l.checks {
+		checkIDs = append(checkIDs, checkID)
+	}
+
+	cost := func(checkID string) float64 {
+		return averages.pressure(l.checks[checkID].cpu, l.checks[checkID].memory)
+	}
+	sort.Slice(checkIDs, func(i, j int) bool {
+		if cost(checkIDs[i]) == cost(checkIDs[j]) {
+			return checkIDs[i] < checkIDs[j]
+		}
+		return cost(checkIDs[i]) > cost(checkIDs[j])
+	})
+
+	return checkIDs
+}
+
+// bestDestination returns the runner, other than the source, that would be the
+// least loaded after receiving the check, and its pressure after the move
+func bestDestination(loads map[string]*runnerLoad, runners []string, src string, check checkLoad, averages resourceAverages) (string, float64) {
+	best := ""
+	bestPressure := 0.0
+	for _, runner := range runners {
+		if runner == src {
+			continue
+		}
+		pressure := averages.pressure(loads[runner].cpu+check.cpu, loads[runner].memory+check.memory)
+		if best == "" || pressure < bestPressure {
+			best = runner
+			bestPressure = pressure
+		}
+	}
+	return best, bestPressure
+}
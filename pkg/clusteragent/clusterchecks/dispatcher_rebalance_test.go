@@ -1633,3 +1633,171 @@ func TestRebalanceIsWorthIt(t *testing.T) {
 
 	assert.True(t, rebalanceIsWorthIt(currentDistribution, proposedDistribution, 10))
 }
+
+func TestRebalanceUsingResourceUsage(t *testing.T) {
+	fakeTagger := mock.SetupFakeTagger(t)
+	testDispatcher := newDispatcher(fakeTagger)
+
+	testDispatcher.store.active = true
+	testDispatcher.store.nodes["node1"] = newNodeStore("node1", "")
+	testDispatcher.store.nodes["node1"].resources = &types.RunnerResources{CPUUsage: 2, MemoryUsage: 400}
+	testDispatcher.store.nodes["node2"] = newNodeStore("node2", "")
+	testDispatcher.store.nodes["node2"].resources = &types.RunnerResources{CPUUsage: 0.4, MemoryUsage: 400}
+	// This runner does not report its resource usage, it's ignored.
+	testDispatcher.store.nodes["node3"] = newNodeStore("node3", "")
+
+	testDispatcher.store.nodes["node1"].clcRunnerStats = map[string]types.CLCRunnerStats{
+		// This is the most expensive check, moving it would make node2 hotter
+		// than node1, so the check won't move.
+		"check1": {
+			AverageExecutionTime: 3000,
+			IsClusterCheck:       true,
+		},
+		// This check should be moved.
+		"check2": {
+			AverageExecutionTime: 1000,
+			IsClusterCheck:       true,
+		},
+		// This check is not a cluster check, so it won't be moved.
+		"check3": {
+			AverageExecutionTime: 1000,
+			IsClusterCheck:       false,
+		},
+	}
+
+	testDispatcher.store.idToDigest = map[checkid.ID]string{
+		"check1": "digest1",
+		"check2": "digest2",
+	}
+	testDispatcher.store.digestToConfig = map[string]integration.Config{
+		"digest1": {},
+		"digest2": {},
+	}
+	testDispatcher.store.digestToNode = map[string]string{
+		"digest1": "node1",
+		"digest2": "node1",
+	}
+
+	checksMoved := testDispatcher.rebalanceUsingResourceUsage(false)
+
+	requireNotLocked(t, testDispatcher.store)
+
+	// Check that the internal state has been updated
+	expectedStatsNode1 := types.CLCRunnersStats{
+		"check1": {
+			AverageExecutionTime: 3000,
+			IsClusterCheck:       true,
+		},
+		"check3": {
+			AverageExecutionTime: 1000,
+			IsClusterCheck:       false,
+		},
+	}
+	expectedStatsNode2 := types.CLCRunnersStats{
+		"check2": {
+			AverageExecutionTime: 1000,
+			IsClusterCheck:       true,
+		},
+	}
+	assert.Equal(t, expectedStatsNode1, testDispatcher.store.nodes["node1"].clcRunnerStats)
+	assert.Equal(t, expectedStatsNode2, testDispatcher.store.nodes["node2"].clcRunnerStats)
+	assert.Empty(t, testDispatcher.store.nodes["node3"].clcRunnerStats)
+
+	// Check response
+	require.Len(t, checksMoved, 1)
+	assert.Equal(t, "check2", checksMoved[0].CheckID)
+	assert.Equal(t, "node1", checksMoved[0].SourceNodeName)
+	assert.Equal(t, "node2", checksMoved[0].DestNodeName)
+
+	// The next rebalance should not move anything because check1 is still too
+	// expensive to be moved.
+	checksMoved = testDispatcher.rebalanceUsingResourceUsage(false)
+	assert.Empty(t, checksMoved)
+}
+
+func TestProposeResourceMoves(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		loads     map[string]*runnerLoad
+		tolerance float64
+		expected  []resourceMove
+	}{
+		{
+			name: "balanced runners",
+			loads: map[string]*runnerLoad{
+				"runner1": {cpu: 1, memory: 100, checks: map[string]checkLoad{"check1": {cpu: 0.5, memory: 50}}},
+				"runner2": {cpu: 1.1, memory: 110, checks: map[string]checkLoad{"check2": {cpu: 0.5, memory: 50}}},
+			},
+			tolerance: 0.2,
+		},
+		{
+			name: "single runner",
+			loads: map[string]*runnerLoad{
+				"runner1": {cpu: 4, memory: 100, checks: map[string]checkLoad{"check1": {cpu: 2, memory: 50}}},
+			},
+			tolerance: 0.2,
+		},
+		{
+			name: "hot CPU runner",
+			loads: map[string]*runnerLoad{
+				"runner1": {cpu: 3, memory: 100, checks: map[string]checkLoad{
+					"snmp1": {cpu: 1, memory: 10},
+					"snmp2": {cpu: 1, memory: 10},
+					"http":  {cpu: 0.1, memory: 10},
+				}},
+				"runner2": {cpu: 1, memory: 100, checks: map[string]checkLoad{}},
+				"runner3": {cpu: 0.5, memory: 100, checks: map[string]checkLoad{}},
+			},
+			tolerance: 0.2,
+			expected: []resourceMove{
+				{checkID: "snmp1", src: "runner1", dest: "runner3"},
+				{checkID: "http", src: "runner1", dest: "runner2"},
+			},
+		},
+		{
+			name: "hot memory runner",
+			loads: map[string]*runnerLoad{
+				"runner1": {cpu: 1, memory: 300, checks: map[string]checkLoad{
+					"check1": {cpu: 0.01, memory: 80},
+					"check2": {cpu: 0.01, memory: 10},
+				}},
+				"runner2": {cpu: 1, memory: 100, checks: map[string]checkLoad{}},
+			},
+			tolerance: 0.2,
+			expected: []resourceMove{
+				{checkID: "check1", src: "runner1", dest: "runner2"},
+			},
+		},
+		{
+			name: "moving the only check moves the problem",
+			loads: map[string]*runnerLoad{
+				"runner1": {cpu: 2, memory: 100, checks: map[string]checkLoad{"check1": {cpu: 2, memory: 100}}},
+				"runner2": {cpu: 0, memory: 0, checks: map[string]checkLoad{}},
+			},
+			tolerance: 0.2,
+		},
+		{
+			name: "within the tolerance",
+			loads: map[string]*runnerLoad{
+				"runner1": {cpu: 1.15, memory: 100, checks: map[string]checkLoad{"check1": {cpu: 0.1, memory: 1}}},
+				"runner2": {cpu: 0.85, memory: 100, checks: map[string]checkLoad{}},
+			},
+			tolerance: 0.2,
+		},
+		{
+			name: "forced without tolerance",
+			loads: map[string]*runnerLoad{
+				"runner1": {cpu: 1.15, memory: 100, checks: map[string]checkLoad{"check1": {cpu: 0.1, memory: 1}}},
+				"runner2": {cpu: 0.85, memory: 100, checks: map[string]checkLoad{}},
+			},
+			tolerance: 0,
+			expected: []resourceMove{
+				{checkID: "check1", src: "runner1", dest: "runner2"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, proposeResourceMoves(tc.loads, tc.tolerance))
+		})
+	}
+}
@@ -673,10 +673,26 @@ func (d *dummyClientStruct) GetRunnerWorkers(IP string) (types.Workers, error) {
 	return workers[IP], nil
 }
 
+func (d *dummyClientStruct) GetRunnerResources(IP string) (types.RunnerResources, error) {
+	resources := map[string]types.RunnerResources{
+		"10.0.0.1": {
+			CPUUsage:    0.1,
+			MemoryUsage: 100 * 1024 * 1024,
+		},
+		"10.0.0.2": {
+			CPUUsage:    0.5,
+			MemoryUsage: 300 * 1024 * 1024,
+		},
+	}
+
+	return resources[IP], nil
+}
+
 func TestUpdateRunnersStats(t *testing.T) {
 	fakeTagger := mock.SetupFakeTagger(t)
 	mockConfig := configmock.New(t)
 	mockConfig.SetWithoutSource("cluster_checks.rebalance_with_utilization", true)
+	mockConfig.SetWithoutSource("cluster_checks.rebalance_with_resource_usage", true)
 
 	dispatcher := newDispatcher(fakeTagger)
 	status := types.NodeStatus{LastChange: 10}
@@ -706,6 +722,7 @@ func TestUpdateRunnersStats(t *testing.T) {
 	assert.EqualValues(t, "10.0.0.1", node1.clientIP)
 	assert.EqualValues(t, types.CLCRunnersStats{}, node1.clcRunnerStats)
 	assert.Zero(t, node1.workers)
+	assert.Nil(t, node1.resources)
 
 	node2, found := dispatcher.store.getNodeStore("node2")
 	assert.True(t, found)
@@ -720,12 +737,14 @@ func TestUpdateRunnersStats(t *testing.T) {
 	assert.EqualValues(t, "10.0.0.1", node1.clientIP)
 	assert.EqualValues(t, stats1, node1.clcRunnerStats)
 	assert.Equal(t, 1, node1.workers)
+	assert.Equal(t, &types.RunnerResources{CPUUsage: 0.1, MemoryUsage: 100 * 1024 * 1024}, node1.resources)
 
 	node2, found = dispatcher.store.getNodeStore("node2")
 	assert.True(t, found)
 	assert.EqualValues(t, "10.0.0.2", node2.clientIP)
 	assert.EqualValues(t, stats2, node2.clcRunnerStats)
 	assert.Equal(t, 2, node2.workers)
+	assert.Equal(t, &types.RunnerResources{CPUUsage: 0.5, MemoryUsage: 300 * 1024 * 1024}, node2.resources)
 
 	// Switch node1 and node2 stats
 	_ = dispatcher.processNodeStatus("node2", "10.0.0.1", status)
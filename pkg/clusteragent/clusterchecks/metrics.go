@@ -52,4 +52,10 @@ var (
 	predictedUtilization = telemetry.NewGaugeWithOpts("cluster_checks", "predicted_utilization",
 		[]string{"node", le.JoinLeaderLabel}, "Utilization predicted by the rebalance algorithm",
 		telemetry.Options{NoDoubleUnderscoreSep: true})
+	runnerCPUUsage = telemetry.NewGaugeWithOpts("cluster_checks", "runner_cpu_usage",
+		[]string{"node", le.JoinLeaderLabel}, "Number of cores used by a check runner, as reported by the runner",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
+	runnerMemoryUsage = telemetry.NewGaugeWithOpts("cluster_checks", "runner_memory_usage_bytes",
+		[]string{"node", le.JoinLeaderLabel}, "Resident memory of a check runner, as reported by the runner",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
 )
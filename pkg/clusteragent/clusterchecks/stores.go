@@ -93,6 +93,7 @@ type nodeStore struct {
 	clcRunnerStats   types.CLCRunnersStats
 	busyness         int
 	workers          int
+	resources        *types.RunnerResources // nil when the resource usage of the runner is unknown
 }
 
 func newNodeStore(name, clientIP string) *nodeStore {
@@ -111,3 +111,11 @@ type Workers struct {
 type WorkerInfo struct {
 	Utilization float64 `json:"Utilization"`
 }
+
+// RunnerResources is used to unmarshal the resource usage of each CLC Runner
+type RunnerResources struct {
+	// CPUUsage is the average number of cores used since the previous request
+	CPUUsage float64 `json:"CPUUsage"`
+	// MemoryUsage is the resident memory in bytes
+	MemoryUsage uint64 `json:"MemoryUsage"`
+}
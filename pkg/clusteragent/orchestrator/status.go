@@ -81,7 +81,7 @@ func GetStatus(ctx context.Context, apiCl kubernetes.Interface) map[string]inter
 	setSkippedResourcesInformationDCAMode(status)
 
 	// rewriting DCA Mode in case we are running in cluster check mode.
-	if orchestrator.KubernetesResourceCache.ItemCount() == 0 && pkgconfigsetup.Datadog().GetBool("cluster_checks.enabled") {
+	if orchestrator.CacheSize() == 0 && pkgconfigsetup.Datadog().GetBool("cluster_checks.enabled") {
 		// we need to check first whether we have dispatched checks to CLC
 		stats, err := clusterchecks.GetStats()
 		if err != nil {
@@ -116,7 +116,7 @@ func GetStatus(ctx context.Context, apiCl kubernetes.Interface) map[string]inter
 func setCacheInformationDCAMode(status map[string]interface{}) {
 
 	// get cache size
-	status["CacheNumber"] = orchestrator.KubernetesResourceCache.ItemCount()
+	status["CacheNumber"] = orchestrator.CacheSize()
 
 	// get cache hits
 	cacheHitsJSON := []byte(expvar.Get("orchestrator-cache").String())
@@ -175,8 +175,7 @@ func setCollectionIsWorkingDCAMode(status map[string]interface{}) {
 	status["Leader"] = engine.IsLeader()
 	status["LeaderName"] = engine.GetLeader()
 	if engine.IsLeader() {
-		c := orchestrator.KubernetesResourceCache.ItemCount()
-		if c > 0 {
+		if orchestrator.CacheSize() > 0 {
 			status["CollectionWorking"] = "The collection is at least partially running since the cache has been populated."
 		} else {
 			status["CollectionWorking"] = "The collection has not run successfully yet since the cache is empty."
@@ -101,7 +101,7 @@ func httpClientFactory(timeout time.Duration) func() *http.Client {
 		return &http.Client{
 			Timeout: timeout,
 			// reusing core agent HTTP transport to benefit from proxy settings.
-			Transport: httputils.CreateHTTPTransport(pkgconfigsetup.Datadog()),
+			Transport: httputils.NewTransport(pkgconfigsetup.Datadog(), httputils.AgentTelemetryDestination),
 		}
 	}
 }
@@ -0,0 +1,91 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package stats
+
+import (
+	"runtime"
+	"runtime/metrics"
+	"time"
+)
+
+const (
+	allocBytesMetric   = "/gc/heap/allocs:bytes"
+	allocObjectsMetric = "/gc/heap/allocs:objects"
+)
+
+// ResourceUsage is the CPU time used, and the memory allocated, while a check
+// instance ran.
+//
+// The CPU time is the one of the OS thread the check runs on, which is locked
+// to the goroutine of the worker for the duration of the run: it's accounted
+// to the check instance only, but the work the check hands off to other
+// goroutines isn't accounted. The Go runtime doesn't track the allocations per
+// goroutine, so the allocated memory is approximate: it's measured for the
+// whole process around the run, so the allocations of the checks running at
+// the same time on other workers are accounted to each of them, and the memory
+// allocated outside of the Go heap (e.g. by Python checks) isn't accounted.
+type ResourceUsage struct {
+	CPUTime          time.Duration
+	AllocatedBytes   uint64
+	AllocatedObjects uint64
+}
+
+// MeasureResources runs fn with the current goroutine locked to its OS thread,
+// and returns the resources it used.
+func MeasureResources(fn func()) ResourceUsage {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	start := SampleResources()
+	fn()
+	return SampleResources().UsageSince(start)
+}
+
+// ResourceSample is a reading of the CPU time of the current OS thread and of
+// the runtime metrics the resource usage of a check run is computed from.
+type ResourceSample struct {
+	cpuTime      time.Duration
+	hasCPUTime   bool
+	allocBytes   uint64
+	allocObjects uint64
+}
+
+// SampleResources reads the CPU time of the current OS thread, and the runtime
+// metrics of the process. The caller must be locked to its OS thread for the
+// CPU time of two samples to be comparable.
+func SampleResources() ResourceSample {
+	samples := []metrics.Sample{
+		{Name: allocBytesMetric},
+		{Name: allocObjectsMetric},
+	}
+	metrics.Read(samples)
+
+	var s ResourceSample
+	s.cpuTime, s.hasCPUTime = threadCPUTime()
+	// the metrics are of kind KindBad if they aren't supported by the runtime
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		s.allocBytes = samples[0].Value.Uint64()
+	}
+	if samples[1].Value.Kind() == metrics.KindUint64 {
+		s.allocObjects = samples[1].Value.Uint64()
+	}
+	return s
+}
+
+// UsageSince returns the resources used between the start sample and this one
+func (s ResourceSample) UsageSince(start ResourceSample) ResourceUsage {
+	var usage ResourceUsage
+	if s.hasCPUTime && start.hasCPUTime && s.cpuTime > start.cpuTime {
+		usage.CPUTime = s.cpuTime - start.cpuTime
+	}
+	if s.allocBytes > start.allocBytes {
+		usage.AllocatedBytes = s.allocBytes - start.allocBytes
+	}
+	if s.allocObjects > start.allocObjects {
+		usage.AllocatedObjects = s.allocObjects - start.allocObjects
+	}
+	return usage
+}
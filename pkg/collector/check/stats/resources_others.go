@@ -0,0 +1,15 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build !linux && !darwin && !windows
+
+package stats
+
+import "time"
+
+// threadCPUTime isn't supported on this platform
+func threadCPUTime() (time.Duration, bool) {
+	return 0, false
+}
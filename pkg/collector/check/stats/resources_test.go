@@ -0,0 +1,67 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var allocSink [][]byte
+
+func TestResourceUsage(t *testing.T) {
+	start := SampleResources()
+	// the large objects are accounted as soon as they're allocated, unlike the
+	// small ones which are accounted when their span is flushed
+	for i := 0; i < 100; i++ {
+		allocSink = append(allocSink, make([]byte, 64<<10))
+	}
+	usage := SampleResources().UsageSince(start)
+	allocSink = nil
+
+	assert.GreaterOrEqual(t, usage.AllocatedBytes, uint64(100*64<<10))
+	assert.GreaterOrEqual(t, usage.AllocatedObjects, uint64(100))
+
+	// the usage is never negative
+	assert.Equal(t, ResourceUsage{}, start.UsageSince(SampleResources()))
+}
+
+func TestMeasureResourcesCPUTime(t *testing.T) {
+	if _, ok := threadCPUTime(); !ok {
+		t.Skip("the CPU time of the threads isn't supported")
+	}
+
+	spin := func(d time.Duration) {
+		for deadline := time.Now().Add(d); time.Now().Before(deadline); {
+		}
+	}
+
+	usage := MeasureResources(func() { spin(100 * time.Millisecond) })
+	assert.Greater(t, usage.CPUTime, 20*time.Millisecond)
+
+	// the CPU time used by the other goroutines isn't accounted
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		spin(100 * time.Millisecond)
+	}()
+	usage = MeasureResources(func() { <-done })
+	assert.Less(t, usage.CPUTime, 20*time.Millisecond)
+}
+
+func TestAddResourceUsage(t *testing.T) {
+	stats := NewStats(newMockCheck())
+
+	stats.AddResourceUsage(ResourceUsage{CPUTime: 1500 * time.Microsecond, AllocatedBytes: 2048})
+	stats.AddResourceUsage(ResourceUsage{CPUTime: 3 * time.Millisecond, AllocatedBytes: 1024})
+
+	assert.Equal(t, int64(3), stats.LastCPUTime)
+	assert.Equal(t, int64(4), stats.TotalCPUTime)
+	assert.Equal(t, uint64(1024), stats.LastAllocatedBytes)
+	assert.Equal(t, uint64(3072), stats.TotalAllocatedBytes)
+}
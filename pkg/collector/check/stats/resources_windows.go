@@ -0,0 +1,36 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package stats
+
+import (
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procGetThreadTimes = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetThreadTimes")
+
+// threadCPUTime returns the CPU time used by the current OS thread
+func threadCPUTime() (time.Duration, bool) {
+	var creation, exit, kernel, user windows.Filetime
+	// the pseudo handle of the current thread doesn't need to be closed
+	thread, _ := windows.GetCurrentThread()
+	ret, _, _ := procGetThreadTimes.Call(
+		uintptr(thread),
+		uintptr(unsafe.Pointer(&creation)),
+		uintptr(unsafe.Pointer(&exit)),
+		uintptr(unsafe.Pointer(&kernel)),
+		uintptr(unsafe.Pointer(&user)),
+	)
+	if ret == 0 {
+		return 0, false
+	}
+	// the FILETIME durations are in 100-nanosecond intervals
+	ticks := uint64(kernel.HighDateTime)<<32 | uint64(kernel.LowDateTime)
+	ticks += uint64(user.HighDateTime)<<32 | uint64(user.LowDateTime)
+	return time.Duration(ticks * 100), true
+}
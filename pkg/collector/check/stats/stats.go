@@ -52,6 +52,10 @@ var (
 		[]string{"check_name"}, "Histogram buckets count")
 	tlmExecutionTime = telemetry.NewGauge("checks", "execution_time",
 		[]string{"check_name", "check_loader"}, "Check execution time")
+	tlmCPUTime = telemetry.NewCounter("checks", "cpu_time",
+		[]string{"check_name"}, "CPU time used by the check runs, in seconds")
+	tlmAllocatedBytes = telemetry.NewCounter("checks", "allocated_bytes",
+		[]string{"check_name"}, "Memory allocated by the process while the check ran, in bytes (approximate)")
 	tlmCheckDelay = telemetry.NewGauge("checks",
 		"delay",
 		[]string{"check_name"},
@@ -128,6 +132,10 @@ type Stats struct {
 	LastDelay                int64     // most recent check start time delay relative to the previous check run, in seconds
 	LastWarnings             []string  // warnings that occurred in the last run, if any
 	UpdateTimestamp          int64     // latest update to this instance, unix timestamp in seconds
+	LastCPUTime              int64     // CPU time used by the most recent run, in milliseconds
+	TotalCPUTime             int64     // CPU time used by all the runs, in milliseconds
+	LastAllocatedBytes       uint64    // memory allocated by the process during the most recent run, approximate
+	TotalAllocatedBytes      uint64    // memory allocated by the process during all the runs, approximate
 	m                        sync.Mutex
 	Telemetry                bool // do we want telemetry on this Check
 	HASupported              bool
@@ -270,6 +278,21 @@ func (cs *Stats) Add(t time.Duration, err error, warnings []error, metricStats S
 	}
 }
 
+// AddResourceUsage tracks the resources used by a new execution
+func (cs *Stats) AddResourceUsage(usage ResourceUsage) {
+	cs.m.Lock()
+	defer cs.m.Unlock()
+
+	cs.LastCPUTime = usage.CPUTime.Milliseconds()
+	cs.TotalCPUTime += cs.LastCPUTime
+	cs.LastAllocatedBytes = usage.AllocatedBytes
+	cs.TotalAllocatedBytes += usage.AllocatedBytes
+	if cs.Telemetry {
+		tlmCPUTime.Add(usage.CPUTime.Seconds(), cs.CheckName)
+		tlmAllocatedBytes.Add(float64(usage.AllocatedBytes), cs.CheckName)
+	}
+}
+
 // SetStateCancelling sets the check stats to be in a cancelling state
 func (cs *Stats) SetStateCancelling() {
 	cs.m.Lock()
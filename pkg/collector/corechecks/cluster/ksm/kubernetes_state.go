@@ -218,6 +218,11 @@ type KSMConfig struct {
 	// PodCollectionMode defines how pods are collected.
 	// Accepted values are: "default", "node_kubelet", and "cluster_unassigned".
 	PodCollectionMode podCollectionMode `yaml:"pod_collection_mode"`
+
+	// DisableLabelsPushDown disables the filtering of the resource labels and annotations kept in memory, default false.
+	// By default, only the labels and annotations used by label joins, labels_as_tags and annotations_as_tags
+	// are stored, the other ones are dropped when the objects are ingested.
+	DisableLabelsPushDown bool `yaml:"disable_labels_push_down"`
 }
 
 // KSMCheck wraps the config and the metric stores needed to run the check
@@ -442,6 +447,11 @@ func (k *KSMCheck) Configure(senderManager sender.SenderManager, integrationConf
 				return err
 			}
 
+			// Only keep in memory the labels and annotations that are used by the check.
+			if !k.instance.DisableLabelsPushDown {
+				builder.WithLabelsAllowList(k.buildLabelsAllowList())
+			}
+
 			// Start the collection process
 			k.allStores = builder.BuildStores()
 
@@ -862,6 +872,29 @@ func (k *KSMCheck) processLabelJoins() {
 	}
 }
 
+// buildLabelsAllowList returns, for each metadata metric used by label joins,
+// the label and annotation keys that need to be kept in the metrics stores.
+func (k *KSMCheck) buildLabelsAllowList() ksmstore.LabelsAllowList {
+	allowList := make(ksmstore.LabelsAllowList, len(k.instance.labelJoins))
+	for metric, joinConf := range k.instance.labelJoins {
+		if joinConf.getAllLabels {
+			allowList[metric] = nil
+			continue
+		}
+
+		keys := make(map[string]struct{}, len(joinConf.labelsToMatch)+len(joinConf.labelsToGet))
+		for _, label := range joinConf.labelsToMatch {
+			keys[label] = struct{}{}
+		}
+		for label := range joinConf.labelsToGet {
+			keys[label] = struct{}{}
+		}
+		allowList[metric] = keys
+	}
+
+	return allowList
+}
+
 func (k *KSMCheck) processLabelsAsTags() {
 	k.processLabelsOrAnnotationsAsTags("label", k.instance.LabelsAsTags)
 }
@@ -995,6 +1028,22 @@ func (k *KSMCheck) sendTelemetry(s sender.Sender) {
 	for resource, count := range k.telemetry.getResourcesCount() {
 		s.Gauge(ksmMetricPrefix+"telemetry.metrics.count", float64(count), "", []string{"resource_name:" + resource})
 	}
+
+	if k.instance.DisableLabelsPushDown {
+		return
+	}
+
+	// estimated memory used by the labels before and after the labels allowlist push down
+	generated, stored := 0, 0
+	for _, stores := range k.allStores {
+		for _, store := range stores {
+			footprint := store.(*ksmstore.MetricsStore).LabelsFootprint()
+			generated += footprint.Generated
+			stored += footprint.Stored
+		}
+	}
+	s.Gauge(ksmMetricPrefix+"telemetry.store.labels_bytes", float64(generated), "", []string{"state:generated"})
+	s.Gauge(ksmMetricPrefix+"telemetry.store.labels_bytes", float64(stored), "", []string{"state:stored"})
 }
 
 // Factory creates a new check factory
@@ -1354,6 +1354,31 @@ func TestKSMCheck_processLabelsAsTags(t *testing.T) {
 	}
 }
 
+func TestKSMCheck_buildLabelsAllowList(t *testing.T) {
+	k := &KSMCheck{instance: &KSMConfig{
+		labelJoins: map[string]*joinsConfig{
+			"kube_pod_labels": {
+				labelsToMatch: []string{"pod", "namespace"},
+				labelsToGet:   map[string]string{"label_app": "app"},
+			},
+			"kube_node_labels": {
+				labelsToMatch: []string{"node"},
+				getAllLabels:  true,
+			},
+		},
+	}}
+
+	expected := ksmstore.LabelsAllowList{
+		"kube_pod_labels": {
+			"pod":       {},
+			"namespace": {},
+			"label_app": {},
+		},
+		"kube_node_labels": nil,
+	}
+	assert.Equal(t, expected, k.buildLabelsAllowList())
+}
+
 func TestKSMCheck_processAnnotationsAsTags(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -18,12 +18,14 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/collectors"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/collectors/inventory"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/discovery"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
 	"github.com/DataDog/datadog-agent/pkg/orchestrator"
 	"github.com/DataDog/datadog-agent/pkg/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -298,6 +300,13 @@ func (cb *CollectorBundle) initialize() {
 			informersToSync[apiserver.InformerName(collectorFullName)] = informer
 			informerSynced[informer] = struct{}{}
 
+			// evict the deleted resources from the cache without waiting for their entry to expire
+			if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+				DeleteFunc: deletedResourceHandler,
+			}); err != nil {
+				log.Warnf("Failed to add delete event handler for %s: %s", collectorFullName, err)
+			}
+
 			// add event handlers for terminated resources
 			if terminatedResourceCollectionEnabled && collector.Metadata().SupportsTerminatedResourceCollection {
 				if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -397,6 +406,16 @@ func (cb *CollectorBundle) terminatedResourceHandler(collector collectors.K8sCol
 	}
 }
 
+// deletedResourceHandler removes the deleted resources from the processors cache
+func deletedResourceHandler(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if object, err := meta.Accessor(obj); err == nil {
+		processors.ResourceCache.Delete(object.GetUID())
+	}
+}
+
 // GetTerminatedResourceBundle returns the terminated resource bundle.
 func (cb *CollectorBundle) GetTerminatedResourceBundle() *TerminatedResourceBundle {
 	return cb.terminatedResourceBundle
@@ -8,6 +8,8 @@
 package k8s
 
 import (
+	"time"
+
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/collectors"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors"
 	k8sProcessors "github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors/k8s"
@@ -34,6 +36,7 @@ type ReplicaSetCollector struct {
 	lister    appsv1Listers.ReplicaSetLister
 	metadata  *collectors.CollectorMetadata
 	processor *processors.Processor
+	pruner    *replicaSetPruner
 }
 
 // NewReplicaSetCollector creates a new collector for the Kubernetes ReplicaSet
@@ -59,6 +62,7 @@ func NewReplicaSetCollector(metadataAsTags utils.MetadataAsTags) *ReplicaSetColl
 			SupportsTerminatedResourceCollection: true,
 		},
 		processor: processors.NewProcessor(new(k8sProcessors.ReplicaSetHandlers)),
+		pruner:    newReplicaSetPruner(),
 	}
 }
 
@@ -85,6 +89,10 @@ func (c *ReplicaSetCollector) Run(rcfg *collectors.CollectorRunConfig) (*collect
 		return nil, collectors.NewListingError(err)
 	}
 
+	if rcfg.Config.ReplicaSetPruningEnabled {
+		list = c.pruner.prune(list, rcfg.Config.ReplicaSetPruningMinAge, time.Now())
+	}
+
 	return c.Process(rcfg, list)
 }
 
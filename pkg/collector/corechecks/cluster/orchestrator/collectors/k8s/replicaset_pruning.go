@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver && orchestrator
+
+package k8s
+
+import (
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors"
+)
+
+// replicaSetPruner skips the ReplicaSets kept by the Deployments for their rollout history, which
+// are scaled to zero, to reduce the volume of the payloads. A pruned ReplicaSet is reported once
+// as deleted, and is reported again as soon as it's scaled up.
+type replicaSetPruner struct {
+	// pruned holds the UIDs of the ReplicaSets which were reported as deleted
+	pruned map[types.UID]struct{}
+}
+
+func newReplicaSetPruner() *replicaSetPruner {
+	return &replicaSetPruner{pruned: make(map[types.UID]struct{})}
+}
+
+// isPrunable returns whether the ReplicaSet has no desired nor actual replica and is older than minAge
+func isPrunable(rs *appsv1.ReplicaSet, minAge time.Duration, now time.Time) bool {
+	desired := rs.Spec.Replicas != nil && *rs.Spec.Replicas > 0
+	return !desired && rs.Status.Replicas == 0 && now.Sub(rs.CreationTimestamp.Time) > minAge
+}
+
+// prune returns the ReplicaSets to report. The ReplicaSets which just became prunable are replaced
+// by a copy holding a deletion timestamp, so that they're reported as deleted.
+func (p *replicaSetPruner) prune(list []*appsv1.ReplicaSet, minAge time.Duration, now time.Time) []*appsv1.ReplicaSet {
+	kept := make([]*appsv1.ReplicaSet, 0, len(list))
+	listed := make(map[types.UID]struct{}, len(list))
+
+	for _, rs := range list {
+		listed[rs.UID] = struct{}{}
+
+		if !isPrunable(rs, minAge, now) {
+			delete(p.pruned, rs.UID)
+			kept = append(kept, rs)
+			continue
+		}
+		if _, found := p.pruned[rs.UID]; found {
+			continue
+		}
+
+		// the objects of the lister are shared and must not be modified
+		deleted := rs.DeepCopy()
+		deletionTimestamp := metav1.NewTime(now)
+		deleted.DeletionTimestamp = &deletionTimestamp
+		// the resource version didn't change, make sure the deletion isn't skipped by the cache
+		processors.ResourceCache.Delete(rs.UID)
+
+		p.pruned[rs.UID] = struct{}{}
+		kept = append(kept, deleted)
+	}
+
+	// forget the ReplicaSets which don't exist anymore
+	for uid := range p.pruned {
+		if _, found := listed[uid]; !found {
+			delete(p.pruned, uid)
+		}
+	}
+
+	return kept
+}
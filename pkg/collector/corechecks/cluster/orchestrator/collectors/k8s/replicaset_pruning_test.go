@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver && orchestrator
+
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator"
+)
+
+func newTestReplicaSet(uid string, created time.Time, desired, actual int32) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:               types.UID(uid),
+			Name:              uid,
+			ResourceVersion:   "1",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec:   appsv1.ReplicaSetSpec{Replicas: ptr.To(desired)},
+		Status: appsv1.ReplicaSetStatus{Replicas: actual},
+	}
+}
+
+func names(list []*appsv1.ReplicaSet) []string {
+	var n []string
+	for _, rs := range list {
+		n = append(n, rs.Name)
+	}
+	return n
+}
+
+func TestReplicaSetPruning(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-48 * time.Hour)
+
+	running := newTestReplicaSet("running", old, 2, 2)
+	scalingDown := newTestReplicaSet("scaling-down", old, 0, 1)
+	recent := newTestReplicaSet("recent", now.Add(-time.Hour), 0, 0)
+	history := newTestReplicaSet("history", old, 0, 0)
+
+	processors.ResourceCache.Skip(history.UID, history.ResourceVersion, orchestrator.K8sReplicaSet)
+	defer processors.ResourceCache.Delete(history.UID)
+
+	pruner := newReplicaSetPruner()
+
+	// the first time a ReplicaSet is pruned, it is reported as deleted
+	kept := pruner.prune([]*appsv1.ReplicaSet{running, scalingDown, recent, history}, 24*time.Hour, now)
+	assert.Equal(t, []string{"running", "scaling-down", "recent", "history"}, names(kept))
+	require.NotNil(t, kept[3].DeletionTimestamp)
+	assert.Equal(t, now, kept[3].DeletionTimestamp.Time)
+	assert.Nil(t, history.DeletionTimestamp, "the listed object must not be modified")
+	assert.False(t, processors.ResourceCache.Skip(history.UID, history.ResourceVersion, orchestrator.K8sReplicaSet))
+
+	// then it is skipped
+	kept = pruner.prune([]*appsv1.ReplicaSet{running, scalingDown, recent, history}, 24*time.Hour, now)
+	assert.Equal(t, []string{"running", "scaling-down", "recent"}, names(kept))
+
+	// and reported again once scaled up
+	scaledUp := newTestReplicaSet("history", old, 1, 0)
+	kept = pruner.prune([]*appsv1.ReplicaSet{scaledUp}, 24*time.Hour, now)
+	assert.Equal(t, []string{"history"}, names(kept))
+	assert.Nil(t, kept[0].DeletionTimestamp)
+	assert.Empty(t, pruner.pruned)
+}
+
+func TestReplicaSetPruningForgetsDeletedReplicaSets(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := newTestReplicaSet("history", now.Add(-48*time.Hour), 0, 0)
+
+	pruner := newReplicaSetPruner()
+	pruner.prune([]*appsv1.ReplicaSet{history}, 24*time.Hour, now)
+	assert.Len(t, pruner.pruned, 1)
+
+	pruner.prune(nil, 24*time.Hour, now)
+	assert.Empty(t, pruner.pruned)
+}
@@ -32,6 +32,7 @@ import (
 	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/collectors"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
 	"github.com/DataDog/datadog-agent/pkg/orchestrator"
 	orchcfg "github.com/DataDog/datadog-agent/pkg/orchestrator/config"
@@ -142,6 +143,7 @@ func (o *OrchestratorCheck) Configure(senderManager sender.SenderManager, integr
 	if o.orchestratorConfig.KubeClusterName == "" {
 		return errors.New("orchestrator check is configured but the cluster name is empty")
 	}
+	processors.ResourceCache.SetLimits(o.orchestratorConfig.ResourceCacheTTL, o.orchestratorConfig.ResourceCacheMaxEntries)
 
 	// load instance level config
 	err = o.instance.parse(config)
@@ -0,0 +1,166 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build orchestrator
+
+package processors
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/DataDog/datadog-agent/pkg/orchestrator"
+	pkgorchestratormodel "github.com/DataDog/datadog-agent/pkg/orchestrator/model"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+)
+
+const (
+	// DefaultResourceCacheTTL is the time after which a resource is reported again even if it didn't change
+	DefaultResourceCacheTTL = 3 * time.Minute
+	// DefaultResourceCacheMaxEntries is the maximum number of resources tracked by the cache
+	DefaultResourceCacheMaxEntries = 100000
+
+	evictionReasonExpired = "expired"
+	evictionReasonSize    = "size"
+	evictionReasonDeleted = "deleted"
+)
+
+var (
+	tlmCacheEvictions = telemetry.NewCounter("orchestrator", "cache_evictions", []string{"orchestrator", "resource", "reason"}, "Number of resources evicted from the cache")
+
+	// ResourceCache is the cache shared by the processors to skip the resources which were
+	// already reported with the same resource version.
+	ResourceCache = NewResourceVersionCache(DefaultResourceCacheTTL, DefaultResourceCacheMaxEntries)
+)
+
+// resourceVersionEntry is the last reported resource version of a resource
+type resourceVersionEntry struct {
+	uid             types.UID
+	resourceVersion string
+	nodeType        pkgorchestratormodel.NodeType
+	expiresAt       time.Time
+}
+
+// ResourceVersionCache tracks the last reported resource version of the resources. Entries expire
+// after a TTL so that unchanged resources are regularly reported again, and the number of entries
+// is bounded so that the memory doesn't grow with the churn of the cluster: when the cache is
+// full, the entries written the longest time ago are evicted first.
+type ResourceVersionCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	// entries is ordered by write time, the most recent first. As all the entries share the same
+	// TTL, it's also ordered by expiration time.
+	entries *list.List
+	index   map[types.UID]*list.Element
+	now     func() time.Time
+}
+
+// NewResourceVersionCache returns a cache with the given TTL and maximum number of entries
+func NewResourceVersionCache(ttl time.Duration, maxEntries int) *ResourceVersionCache {
+	return &ResourceVersionCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    list.New(),
+		index:      make(map[types.UID]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// SetLimits updates the TTL and the maximum number of entries of the cache. Non-positive values
+// are ignored.
+func (c *ResourceVersionCache) SetLimits(ttl time.Duration, maxEntries int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ttl > 0 {
+		c.ttl = ttl
+	}
+	if maxEntries > 0 {
+		c.maxEntries = maxEntries
+	}
+	c.evictOverflow()
+	orchestrator.SetCacheSize(c.entries.Len())
+}
+
+// Skip returns true if the resource was already reported with the same resource version, and
+// records the resource version otherwise.
+func (c *ResourceVersionCache) Skip(uid types.UID, resourceVersion string, nodeType pkgorchestratormodel.NodeType) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.evictExpired(now)
+
+	if elem, found := c.index[uid]; found {
+		entry := elem.Value.(*resourceVersionEntry)
+		if entry.resourceVersion == resourceVersion {
+			orchestrator.IncCacheHit(nodeType)
+			return true
+		}
+		entry.resourceVersion = resourceVersion
+		entry.nodeType = nodeType
+		entry.expiresAt = now.Add(c.ttl)
+		c.entries.MoveToFront(elem)
+	} else {
+		c.index[uid] = c.entries.PushFront(&resourceVersionEntry{
+			uid:             uid,
+			resourceVersion: resourceVersion,
+			nodeType:        nodeType,
+			expiresAt:       now.Add(c.ttl),
+		})
+		c.evictOverflow()
+	}
+
+	orchestrator.SetCacheSize(c.entries.Len())
+	orchestrator.IncCacheMiss(nodeType)
+	return false
+}
+
+// Delete removes a resource from the cache, so that it's reported by the next collection. It's
+// called when the resource is deleted from the cluster so that its entry doesn't wait for the TTL.
+func (c *ResourceVersionCache) Delete(uid types.UID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, found := c.index[uid]; found {
+		c.remove(elem, evictionReasonDeleted)
+		orchestrator.SetCacheSize(c.entries.Len())
+	}
+}
+
+// Len returns the number of resources held by the cache
+func (c *ResourceVersionCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entries.Len()
+}
+
+// evictExpired removes the expired entries, starting from the oldest one
+func (c *ResourceVersionCache) evictExpired(now time.Time) {
+	for elem := c.entries.Back(); elem != nil; elem = c.entries.Back() {
+		if elem.Value.(*resourceVersionEntry).expiresAt.After(now) {
+			return
+		}
+		c.remove(elem, evictionReasonExpired)
+	}
+}
+
+// evictOverflow removes the oldest entries until the cache fits its maximum number of entries
+func (c *ResourceVersionCache) evictOverflow() {
+	for c.entries.Len() > c.maxEntries {
+		c.remove(c.entries.Back(), evictionReasonSize)
+	}
+}
+
+func (c *ResourceVersionCache) remove(elem *list.Element, reason string) {
+	entry := c.entries.Remove(elem).(*resourceVersionEntry)
+	delete(c.index, entry.uid)
+	tlmCacheEvictions.Inc(append(entry.nodeType.TelemetryTags(), reason)...)
+}
@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build orchestrator
+
+package processors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+
+	pkgorchestratormodel "github.com/DataDog/datadog-agent/pkg/orchestrator/model"
+)
+
+func newTestResourceVersionCache(ttl time.Duration, maxEntries int) (*ResourceVersionCache, *time.Time) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewResourceVersionCache(ttl, maxEntries)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestResourceVersionCacheSkip(t *testing.T) {
+	c, _ := newTestResourceVersionCache(time.Minute, 10)
+
+	// the resource is unknown, therefore it is not skipped
+	assert.False(t, c.Skip("123", "1", pkgorchestratormodel.K8sPod))
+	// the resource was reported with the same version, therefore it is skipped
+	assert.True(t, c.Skip("123", "1", pkgorchestratormodel.K8sPod))
+	// the version has changed, therefore it is not skipped
+	assert.False(t, c.Skip("123", "2", pkgorchestratormodel.K8sPod))
+	assert.True(t, c.Skip("123", "2", pkgorchestratormodel.K8sPod))
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestResourceVersionCacheExpiration(t *testing.T) {
+	c, now := newTestResourceVersionCache(time.Minute, 10)
+
+	assert.False(t, c.Skip("a", "1", pkgorchestratormodel.K8sPod))
+	*now = now.Add(30 * time.Second)
+	assert.False(t, c.Skip("b", "1", pkgorchestratormodel.K8sPod))
+
+	// hits don't extend the TTL so that unchanged resources are regularly reported
+	*now = now.Add(29 * time.Second)
+	assert.True(t, c.Skip("a", "1", pkgorchestratormodel.K8sPod))
+	*now = now.Add(time.Second)
+	assert.False(t, c.Skip("a", "1", pkgorchestratormodel.K8sPod))
+
+	// the expired entries are evicted even if their resource isn't seen anymore
+	*now = now.Add(30 * time.Second)
+	assert.True(t, c.Skip("a", "1", pkgorchestratormodel.K8sPod))
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestResourceVersionCacheMaxEntries(t *testing.T) {
+	c, now := newTestResourceVersionCache(time.Hour, 2)
+
+	for _, uid := range []types.UID{"a", "b", "c"} {
+		assert.False(t, c.Skip(uid, "1", pkgorchestratormodel.K8sPod))
+		*now = now.Add(time.Second)
+	}
+	assert.Equal(t, 2, c.Len())
+
+	// the oldest entry was evicted
+	assert.True(t, c.Skip("b", "1", pkgorchestratormodel.K8sPod))
+	assert.True(t, c.Skip("c", "1", pkgorchestratormodel.K8sPod))
+	assert.False(t, c.Skip("a", "1", pkgorchestratormodel.K8sPod))
+	assert.False(t, c.Skip("b", "1", pkgorchestratormodel.K8sPod))
+
+	// lowering the limit evicts the oldest entries right away
+	c.SetLimits(0, 1)
+	assert.Equal(t, 1, c.Len())
+	assert.True(t, c.Skip("b", "1", pkgorchestratormodel.K8sPod))
+}
+
+func TestResourceVersionCacheDelete(t *testing.T) {
+	c, _ := newTestResourceVersionCache(time.Hour, 10)
+
+	assert.False(t, c.Skip("a", "1", pkgorchestratormodel.K8sPod))
+	c.Delete("a")
+	c.Delete("unknown")
+	assert.Equal(t, 0, c.Len())
+	assert.False(t, c.Skip("a", "1", pkgorchestratormodel.K8sPod))
+}
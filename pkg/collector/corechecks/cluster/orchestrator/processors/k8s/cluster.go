@@ -132,7 +132,7 @@ func (p *ClusterProcessor) Process(ctx processors.ProcessorContext, list interfa
 		return processResult, 0, fmt.Errorf("failed to compute resource version: %s", err.Error())
 	}
 
-	if orchestrator.SkipKubernetesResource(types.UID(pctx.ClusterID), clusterModel.ResourceVersion, orchestrator.K8sCluster) {
+	if processors.ResourceCache.Skip(types.UID(pctx.ClusterID), clusterModel.ResourceVersion, orchestrator.K8sCluster) {
 		stats := orchestrator.CheckStats{
 			CacheHits: 1,
 			CacheMiss: 0,
@@ -214,7 +214,7 @@ func (p *Processor) Process(ctx ProcessorContext, list interface{}) (processResu
 		resourceUID := p.h.ResourceUID(ctx, resource)
 		resourceVersion := p.h.ResourceVersion(ctx, resource, resourceMetadataModel)
 
-		if orchestrator.SkipKubernetesResource(resourceUID, resourceVersion, ctx.GetNodeType()) {
+		if ResourceCache.Skip(resourceUID, resourceVersion, ctx.GetNodeType()) {
 			continue
 		}
 
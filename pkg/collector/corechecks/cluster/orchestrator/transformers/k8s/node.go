@@ -166,6 +166,45 @@ func extractCapacitiesAndAllocatables(n *corev1.Node, mn *model.Node) {
 	}
 
 	setSupportedResources(n, mn, resourcesMilli)
+	setExtendedResources(n, mn)
+}
+
+// setExtendedResources reports the extended resources (e.g. nvidia.com/gpu) and hugepages advertised by the node,
+// including those with a zero value which are skipped by setSupportedResources. Device plugins advertise the
+// healthy devices of a node as allocatable, so an allocatable value lower than the capacity means some devices are
+// unhealthy; such resources are also reported as tags so that capacity issues can be searched for.
+func setExtendedResources(n *corev1.Node, mn *model.Node) {
+	for resource, capacity := range n.Status.Capacity {
+		if !isExtendedResourceName(resource) && !isHugePageResourceName(resource) {
+			continue
+		}
+
+		allocatable := n.Status.Allocatable[resource]
+		mn.Status.Capacity[resource.String()] = capacity.Value()
+		mn.Status.Allocatable[resource.String()] = allocatable.Value()
+
+		if !isExtendedResourceName(resource) {
+			continue
+		}
+		mn.Tags = append(mn.Tags, fmt.Sprintf("node_extended_resource:%s", resource))
+		if allocatable.Cmp(capacity) < 0 {
+			mn.Tags = append(mn.Tags, fmt.Sprintf("node_extended_resource_unhealthy:%s", resource))
+		}
+	}
+}
+
+// isExtendedResourceName is mostly copied from the IsExtendedResourceName helper of kubernetes, in pkg/apis/core/v1/helper
+func isExtendedResourceName(name corev1.ResourceName) bool {
+	// extended resources are fully qualified names outside of the kubernetes.io domain
+	if !strings.Contains(string(name), "/") || strings.Contains(string(name), corev1.ResourceDefaultNamespacePrefix) {
+		return false
+	}
+	// resource quota requests are not extended resources
+	return !strings.HasPrefix(string(name), corev1.DefaultResourceRequestsPrefix)
+}
+
+func isHugePageResourceName(name corev1.ResourceName) bool {
+	return strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix)
 }
 
 // The function iterates over the capacity and allocatable resources of the node and sets the corresponding values in the model.Node object.
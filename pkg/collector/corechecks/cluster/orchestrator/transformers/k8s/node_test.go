@@ -192,6 +192,53 @@ func TestExtractNode(t *testing.T) {
 				},
 				Tags: []string{"node_status:unknown", "node_schedulable:true"},
 			}},
+		"node with extended resources": {
+			input: corev1.Node{
+				Status: corev1.NodeStatus{
+					Capacity: map[corev1.ResourceName]resource.Quantity{
+						corev1.ResourceCPU:   resource.MustParse("8"),
+						"nvidia.com/gpu":     resource.MustParse("4"),
+						"example.com/fpga":   resource.MustParse("1"),
+						"hugepages-2Mi":      resource.MustParse("0"),
+						"hugepages-1Gi":      resource.MustParse("2Gi"),
+						"requests.cpu":       resource.MustParse("1"),
+						"kubernetes.io/test": resource.MustParse("1"),
+					},
+					Allocatable: map[corev1.ResourceName]resource.Quantity{
+						corev1.ResourceCPU: resource.MustParse("8"),
+						"nvidia.com/gpu":   resource.MustParse("3"),
+						"example.com/fpga": resource.MustParse("1"),
+						"hugepages-1Gi":    resource.MustParse("2Gi"),
+					}},
+			}, expected: model.Node{
+				Metadata: &model.Metadata{},
+				Status: &model.NodeStatus{
+					Status: "Unknown",
+					Capacity: map[string]int64{
+						"cpu":                8000,
+						"nvidia.com/gpu":     4,
+						"example.com/fpga":   1,
+						"hugepages-2Mi":      0,
+						"hugepages-1Gi":      2147483648,
+						"requests.cpu":       1,
+						"kubernetes.io/test": 1,
+					},
+					Allocatable: map[string]int64{
+						"cpu":              8000,
+						"nvidia.com/gpu":   3,
+						"example.com/fpga": 1,
+						"hugepages-2Mi":    0,
+						"hugepages-1Gi":    2147483648,
+					},
+				},
+				Tags: []string{
+					"node_status:unknown",
+					"node_schedulable:true",
+					"node_extended_resource:nvidia.com/gpu",
+					"node_extended_resource:example.com/fpga",
+					"node_extended_resource_unhealthy:nvidia.com/gpu",
+				},
+			}},
 		"node with only a condition": {
 			input: corev1.Node{
 				ObjectMeta: metav1.ObjectMeta{
@@ -90,6 +90,52 @@ func ExtractPod(ctx processors.ProcessorContext, p *corev1.Pod) *model.Pod {
 	return &podModel
 }
 
+// PodStartupLatency holds the durations of the startup phases of a pod,
+// derived from the transition times of its conditions.
+type PodStartupLatency struct {
+	ScheduledTime time.Time
+	// Initialization is the time between the PodScheduled and Initialized conditions,
+	// which includes pulling the images and running the init containers
+	Initialization time.Duration
+	// Readiness is the time between the Initialized and Ready conditions
+	Readiness time.Duration
+	// Total is the time between the PodScheduled and Ready conditions
+	Total time.Duration
+}
+
+// ExtractPodStartupLatency returns the startup latency of a pod. It returns false
+// if the pod isn't ready, or if its conditions aren't ordered as expected.
+func ExtractPodStartupLatency(p *corev1.Pod) (PodStartupLatency, bool) {
+	var scheduled, initialized, ready time.Time
+	for _, c := range p.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case corev1.PodScheduled:
+			scheduled = c.LastTransitionTime.Time
+		case corev1.PodInitialized:
+			initialized = c.LastTransitionTime.Time
+		case corev1.PodReady:
+			ready = c.LastTransitionTime.Time
+		}
+	}
+
+	if scheduled.IsZero() || initialized.IsZero() || ready.IsZero() {
+		return PodStartupLatency{}, false
+	}
+	if initialized.Before(scheduled) || ready.Before(initialized) {
+		return PodStartupLatency{}, false
+	}
+
+	return PodStartupLatency{
+		ScheduledTime:  scheduled,
+		Initialization: initialized.Sub(scheduled),
+		Readiness:      ready.Sub(initialized),
+		Total:          ready.Sub(scheduled),
+	}, true
+}
+
 func convertNodeSelector(ns *corev1.NodeSelector) *model.NodeSelector {
 	if ns == nil {
 		return nil
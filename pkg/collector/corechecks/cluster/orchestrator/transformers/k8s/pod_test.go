@@ -874,6 +874,60 @@ func TestExtractPodConditions(t *testing.T) {
 	assert.Equal(t, expectedTags, conditionTags)
 }
 
+func TestExtractPodStartupLatency(t *testing.T) {
+	scheduled := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	condition := func(conditionType v1.PodConditionType, status v1.ConditionStatus, after time.Duration) v1.PodCondition {
+		return v1.PodCondition{Type: conditionType, Status: status, LastTransitionTime: metav1.NewTime(scheduled.Add(after))}
+	}
+
+	for name, tc := range map[string]struct {
+		conditions []v1.PodCondition
+		expected   PodStartupLatency
+		ok         bool
+	}{
+		"ready": {
+			conditions: []v1.PodCondition{
+				condition(v1.PodScheduled, v1.ConditionTrue, 0),
+				condition(v1.PodInitialized, v1.ConditionTrue, 12*time.Second),
+				condition(v1.ContainersReady, v1.ConditionTrue, 20*time.Second),
+				condition(v1.PodReady, v1.ConditionTrue, 20*time.Second),
+			},
+			expected: PodStartupLatency{
+				ScheduledTime:  scheduled,
+				Initialization: 12 * time.Second,
+				Readiness:      8 * time.Second,
+				Total:          20 * time.Second,
+			},
+			ok: true,
+		},
+		"not ready": {
+			conditions: []v1.PodCondition{
+				condition(v1.PodScheduled, v1.ConditionTrue, 0),
+				condition(v1.PodInitialized, v1.ConditionTrue, 12*time.Second),
+				condition(v1.PodReady, v1.ConditionFalse, 12*time.Second),
+			},
+		},
+		"not scheduled": {
+			conditions: []v1.PodCondition{
+				condition(v1.PodScheduled, v1.ConditionFalse, 0),
+			},
+		},
+		"unordered conditions": {
+			conditions: []v1.PodCondition{
+				condition(v1.PodScheduled, v1.ConditionTrue, time.Minute),
+				condition(v1.PodInitialized, v1.ConditionTrue, 0),
+				condition(v1.PodReady, v1.ConditionTrue, 2*time.Minute),
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			latency, ok := ExtractPodStartupLatency(&v1.Pod{Status: v1.PodStatus{Conditions: tc.conditions}})
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, latency)
+		})
+	}
+}
+
 func TestFillPodResourceVersion(t *testing.T) {
 	for _, tc := range []struct {
 		name  string
@@ -104,6 +104,18 @@ func (c *Check) Run() error {
 		filter,
 	)
 
+	restoredFilter := workloadmeta.NewFilterBuilder().
+		SetSource(workloadmeta.SourceRuntime).
+		SetEventType(workloadmeta.EventTypeSet).
+		AddKind(workloadmeta.KindContainer).
+		Build()
+
+	restoredEventsCh := c.workloadmetaStore.Subscribe(
+		CheckName+"-restored",
+		workloadmeta.NormalPriority,
+		restoredFilter,
+	)
+
 	podFilter := workloadmeta.NewFilterBuilder().
 		SetSource(workloadmeta.SourceNodeOrchestrator).
 		SetEventType(workloadmeta.EventTypeUnset).
@@ -148,6 +160,11 @@ func (c *Check) Run() error {
 				return nil
 			}
 			c.processor.processEvents(eventBundle)
+		case eventBundle, ok := <-restoredEventsCh:
+			if !ok {
+				return nil
+			}
+			c.processor.processRestoredContainers(eventBundle)
 		case eventBundle, ok := <-podEventsCh:
 			if !ok {
 				stopProcessor()
@@ -7,6 +7,7 @@ package containerlifecycle
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/forwarder/eventplatform"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
 	types "github.com/DataDog/datadog-agent/pkg/containerlifecycle"
+	metricsevent "github.com/DataDog/datadog-agent/pkg/metrics/event"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
 	"google.golang.org/protobuf/proto"
@@ -27,6 +29,10 @@ type processor struct {
 	containersQueue *queue
 	tasksQueue      *queue
 	store           workloadmeta.Component
+
+	// restoredContainers are the containers restored from a checkpoint an
+	// event was sent for, until they're deleted
+	restoredContainers map[string]struct{}
 }
 
 func newProcessor(sender sender.Sender, chunkSize int, store workloadmeta.Component) *processor {
@@ -36,6 +42,8 @@ func newProcessor(sender sender.Sender, chunkSize int, store workloadmeta.Compon
 		containersQueue: newQueue(chunkSize),
 		tasksQueue:      newQueue(chunkSize),
 		store:           store,
+
+		restoredContainers: make(map[string]struct{}),
 	}
 }
 
@@ -94,8 +102,67 @@ func (p *processor) processEvents(evBundle workloadmeta.EventBundle) {
 	}
 }
 
+// processRestoredContainers handles workloadmeta container set events, it
+// sends an event the first time a container restored from a checkpoint is
+// reported. The deletion payloads don't support other lifecycle events.
+func (p *processor) processRestoredContainers(evBundle workloadmeta.EventBundle) {
+	evBundle.Acknowledge()
+
+	for _, ev := range evBundle.Events {
+		container, ok := ev.Entity.(*workloadmeta.Container)
+		if !ok || container.State.Checkpoint == nil {
+			continue
+		}
+		if _, found := p.restoredContainers[container.ID]; found {
+			continue
+		}
+
+		log.Debugf("Container %q was restored from a checkpoint", container.ID)
+		p.restoredContainers[container.ID] = struct{}{}
+		p.sender.Event(restoredContainerEvent(container))
+	}
+}
+
+// restoredContainerEvent returns the event reporting that a container was
+// restored from a checkpoint
+func restoredContainerEvent(container *workloadmeta.Container) metricsevent.Event {
+	checkpoint := container.State.Checkpoint
+
+	name := container.Name
+	if name == "" {
+		name = container.ID
+	}
+	checkpointed := checkpoint.ContainerName
+	if checkpoint.PodName != "" {
+		checkpointed = fmt.Sprintf("%s/%s/%s", checkpoint.PodNamespace, checkpoint.PodName, checkpoint.ContainerName)
+	}
+	text := fmt.Sprintf("Container %s was restored from a checkpoint of container %s", container.ID, checkpointed)
+	if !checkpoint.CreatedAt.IsZero() {
+		text += fmt.Sprintf(" created at %s", checkpoint.CreatedAt.Format(time.RFC3339))
+	}
+
+	ts := container.State.CreatedAt
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	return metricsevent.Event{
+		Title:          fmt.Sprintf("Container %s restored from a checkpoint", name),
+		Text:           text,
+		Ts:             ts.Unix(),
+		Priority:       metricsevent.PriorityNormal,
+		AlertType:      metricsevent.AlertTypeInfo,
+		Tags:           []string{"container_id:" + container.ID},
+		AggregationKey: fmt.Sprintf("%s:%s", CheckName, container.ID),
+		SourceTypeName: CheckName,
+		EventType:      CheckName,
+	}
+}
+
 // processContainer enqueue container events
 func (p *processor) processContainer(container *workloadmeta.Container, sources []workloadmeta.Source) error {
+	delete(p.restoredContainers, container.ID)
+
 	event := newEvent()
 	event.withObjectKind(types.ObjectKindContainer)
 	event.withEventType(types.EventNameDelete)
@@ -17,6 +17,7 @@ import (
 	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
 	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
+	metricsevent "github.com/DataDog/datadog-agent/pkg/metrics/event"
 	"github.com/DataDog/datadog-agent/pkg/util/hostname"
 )
 
@@ -193,3 +194,59 @@ func TestProcessContainer(t *testing.T) {
 			}},
 	}, p.containersQueue.data)
 }
+
+func TestProcessRestoredContainers(t *testing.T) {
+	sender := mocksender.NewMockSender("test")
+	sender.On("Event", mock.Anything).Return()
+	p := &processor{
+		sender:             sender,
+		containersQueue:    &queue{},
+		store:              &fakeStore{},
+		restoredContainers: make(map[string]struct{}),
+	}
+
+	createdAt := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	restored := &workloadmeta.Container{
+		EntityID:   workloadmeta.EntityID{Kind: workloadmeta.KindContainer, ID: "cont1"},
+		EntityMeta: workloadmeta.EntityMeta{Name: "app"},
+		State: workloadmeta.ContainerState{
+			CreatedAt: createdAt,
+			Checkpoint: &workloadmeta.ContainerCheckpoint{
+				ContainerName: "app",
+				PodName:       "app-7d9c5",
+				PodNamespace:  "default",
+			},
+		},
+	}
+	other := &workloadmeta.Container{
+		EntityID: workloadmeta.EntityID{Kind: workloadmeta.KindContainer, ID: "cont2"},
+	}
+	bundle := func(containers ...*workloadmeta.Container) workloadmeta.EventBundle {
+		bundle := workloadmeta.EventBundle{Ch: make(chan struct{})}
+		for _, container := range containers {
+			bundle.Events = append(bundle.Events, workloadmeta.Event{Type: workloadmeta.EventTypeSet, Entity: container})
+		}
+		return bundle
+	}
+
+	// the event is sent once for the restored container
+	p.processRestoredContainers(bundle(restored, other))
+	p.processRestoredContainers(bundle(restored))
+	sender.AssertNumberOfCalls(t, "Event", 1)
+	sender.AssertEvent(t, metricsevent.Event{
+		Title:          "Container app restored from a checkpoint",
+		Text:           "Container cont1 was restored from a checkpoint of container default/app-7d9c5/app",
+		Ts:             createdAt.Unix(),
+		Priority:       metricsevent.PriorityNormal,
+		AlertType:      metricsevent.AlertTypeInfo,
+		Tags:           []string{"container_id:cont1"},
+		AggregationKey: "container_lifecycle:cont1",
+		SourceTypeName: CheckName,
+		EventType:      CheckName,
+	}, time.Second)
+
+	// it's sent again if the container is deleted and reported again
+	assert.NoError(t, p.processContainer(restored, []workloadmeta.Source{workloadmeta.SourceRuntime}))
+	p.processRestoredContainers(bundle(restored))
+	sender.AssertNumberOfCalls(t, "Event", 2)
+}
@@ -7,22 +7,50 @@
 
 package cri
 
-import workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+import (
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/cri"
+)
 
 var metricsNameMapping = map[string]string{
 	"container.uptime":       "cri.uptime",
 	"container.cpu.usage":    "cri.cpu.usage",
 	"container.memory.usage": "cri.mem.rss",
+	"container.net.sent":     "cri.net.bytes_sent",
+	"container.net.rcvd":     "cri.net.bytes_rcvd",
 	"cri.disk.used":          "cri.disk.used",   // Passthrough for custom metrics extension
 	"cri.disk.inodes":        "cri.disk.inodes", // Passthrough for custom metrics extension
 }
 
 // metricsAdapter implements the generic.MetricsAdapter interface
-type metricsAdapter struct{}
+type metricsAdapter struct {
+	criGetter func() (cri.CRIClient, error)
+}
 
 // AdaptTags can be used to change Tagger tags before submitting the metrics
 func (a metricsAdapter) AdaptTags(tags []string, c *workloadmeta.Container) []string {
-	return append(tags, "runtime:"+string(c.Runtime))
+	return append(tags, "runtime:"+a.containerRuntime(c))
+}
+
+// containerRuntime returns the runtime of the container. When several CRI
+// sockets are queried, the runtime of the socket reporting the container is
+// used, as the metadata of the container may come from another collector.
+func (a metricsAdapter) containerRuntime(c *workloadmeta.Container) string {
+	if a.criGetter == nil {
+		return string(c.Runtime)
+	}
+
+	client, err := a.criGetter()
+	if err != nil {
+		return string(c.Runtime)
+	}
+
+	if lookup, ok := client.(cri.ContainerRuntimeLookup); ok {
+		if runtime, found := lookup.LookupContainerRuntime(c.ID); found && runtime != "" {
+			return runtime
+		}
+	}
+	return string(c.Runtime)
 }
 
 // AdaptMetrics can be used to change metrics (change name or value) before submitting the metric.
@@ -36,7 +36,8 @@ const (
 
 // CRIConfig holds the config of the check
 type CRIConfig struct {
-	CollectDisk bool `yaml:"collect_disk"`
+	CollectDisk    bool `yaml:"collect_disk"`
+	CollectImageFs bool `yaml:"collect_image_fs"`
 }
 
 // CRICheck grabs CRI metrics
@@ -46,6 +47,7 @@ type CRICheck struct {
 	processor generic.Processor
 	store     workloadmeta.Component
 	tagger    tagger.Component
+	criGetter func() (cri.CRIClient, error)
 }
 
 // Factory is exported for integration testing
@@ -56,6 +58,9 @@ func Factory(store workloadmeta.Component, tagger tagger.Component) option.Optio
 			instance:  &CRIConfig{},
 			store:     store,
 			tagger:    tagger,
+			criGetter: func() (cri.CRIClient, error) {
+				return cri.GetUtil()
+			},
 		}
 	})
 }
@@ -64,6 +69,7 @@ func Factory(store workloadmeta.Component, tagger tagger.Component) option.Optio
 func (c *CRIConfig) Parse(data []byte) error {
 	// default values
 	c.CollectDisk = false
+	c.CollectImageFs = false
 
 	return yaml.Unmarshal(data, c)
 }
@@ -84,11 +90,9 @@ func (c *CRICheck) Configure(senderManager sender.SenderManager, _ uint64, confi
 		log.Warnf("Can't get container include/exclude filter, no filtering will be applied: %v", err)
 	}
 
-	c.processor = generic.NewProcessor(metrics.GetProvider(option.New(c.store)), generic.NewMetadataContainerAccessor(c.store), metricsAdapter{}, getProcessorFilter(containerFilter, c.store), c.tagger)
+	c.processor = generic.NewProcessor(metrics.GetProvider(option.New(c.store)), generic.NewMetadataContainerAccessor(c.store), metricsAdapter{criGetter: c.criGetter}, getProcessorFilter(containerFilter, c.store), c.tagger)
 	if c.instance.CollectDisk {
-		c.processor.RegisterExtension("cri-custom-metrics", &criCustomMetricsExtension{criGetter: func() (cri.CRIClient, error) {
-			return cri.GetUtil()
-		}})
+		c.processor.RegisterExtension("cri-custom-metrics", &criCustomMetricsExtension{criGetter: c.criGetter})
 	}
 
 	return nil
@@ -102,6 +106,10 @@ func (c *CRICheck) Run() error {
 	}
 	defer sender.Commit()
 
+	if c.instance.CollectImageFs {
+		c.collectImageMetrics(sender)
+	}
+
 	return c.runProcessor(sender)
 }
 
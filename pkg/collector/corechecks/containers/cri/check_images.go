@@ -0,0 +1,49 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+
+package cri
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// collectImageMetrics reports the node-level usage of the image filesystems
+// and the size of the images known by the runtime. They are only available
+// through the image service of the CRI socket, which makes them the only
+// source of image metrics when the containerd socket isn't mounted.
+func (c *CRICheck) collectImageMetrics(sender sender.Sender) {
+	client, err := c.criGetter()
+	if err != nil {
+		log.Infof("Unable to reach CRI socket, err: %v", err)
+		return
+	}
+
+	fsInfo, err := client.ImageFsInfo()
+	if err != nil {
+		log.Infof("Unable to get CRI image filesystems, err: %v", err)
+	} else {
+		for _, fs := range fsInfo.GetImageFilesystems() {
+			tags := []string{"mountpoint:" + fs.GetFsId().GetMountpoint()}
+			sender.Gauge("cri.image_fs.used", float64(fs.GetUsedBytes().GetValue()), "", tags)
+			sender.Gauge("cri.image_fs.inodes", float64(fs.GetInodesUsed().GetValue()), "", tags)
+		}
+	}
+
+	images, err := client.ListImages()
+	if err != nil {
+		log.Infof("Unable to list CRI images, err: %v", err)
+		return
+	}
+
+	var totalSize uint64
+	for _, image := range images {
+		totalSize += image.GetSize_()
+	}
+	sender.Gauge("cri.images.count", float64(len(images)), "", nil)
+	sender.Gauge("cri.images.size", float64(totalSize), "", nil)
+}
@@ -43,6 +43,29 @@ func (cext *criCustomMetricsExtension) PreProcess(sender generic.SenderFunc, agg
 	if err != nil {
 		log.Infof("Unable to get CRI stats, err: %v", err)
 	}
+
+	// Some runtimes (cri-o) only report the writable layer usage as part of the pod sandbox stats
+	podStats, err := client.ListPodSandboxStats()
+	if err != nil {
+		log.Debugf("Unable to get CRI pod sandbox stats, err: %v", err)
+		return
+	}
+
+	for _, pod := range podStats {
+		for _, containerStats := range pod.GetLinux().GetContainers() {
+			containerID := containerStats.GetAttributes().GetId()
+			if containerID == "" || containerStats.GetWritableLayer() == nil {
+				continue
+			}
+
+			if cext.criContainerStats == nil {
+				cext.criContainerStats = make(map[string]*criTypes.ContainerStats)
+			}
+			if existing, found := cext.criContainerStats[containerID]; !found || existing.GetWritableLayer() == nil {
+				cext.criContainerStats[containerID] = containerStats
+			}
+		}
+	}
 }
 
 //nolint:revive // TODO(CINT) Fix revive linter
@@ -12,6 +12,7 @@ import (
 
 	taggerMock "github.com/DataDog/datadog-agent/comp/core/tagger/mock"
 	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/containers/generic"
 	"github.com/DataDog/datadog-agent/pkg/util/containers/cri"
 	"github.com/DataDog/datadog-agent/pkg/util/containers/cri/crimock"
@@ -66,6 +67,7 @@ func TestCriCheck(t *testing.T) {
 			},
 		},
 	}, nil)
+	mockCri.On("ListPodSandboxStats").Return(map[string]*criTypes.PodSandboxStats{}, nil)
 
 	// Create CRI check
 	check := CRICheck{
@@ -79,12 +81,76 @@ func TestCriCheck(t *testing.T) {
 	assert.NoError(t, err)
 
 	expectedTags := []string{"runtime:containerd"}
-	mockSender.AssertNumberOfCalls(t, "Rate", 1)
+	mockSender.AssertNumberOfCalls(t, "Rate", 3)
 	mockSender.AssertNumberOfCalls(t, "Gauge", 4)
 
 	mockSender.AssertMetricInRange(t, "Gauge", "cri.uptime", 0, 600, "", expectedTags)
 	mockSender.AssertMetric(t, "Rate", "cri.cpu.usage", 100, "", expectedTags)
 	mockSender.AssertMetric(t, "Gauge", "cri.mem.rss", 42000, "", expectedTags)
+	mockSender.AssertMetric(t, "Rate", "cri.net.bytes_sent", 42, "", append(expectedTags, "interface:eth42"))
+	mockSender.AssertMetric(t, "Rate", "cri.net.bytes_rcvd", 43, "", append(expectedTags, "interface:eth42"))
 	mockSender.AssertMetric(t, "Gauge", "cri.disk.used", 10, "", expectedTags)
 	mockSender.AssertMetric(t, "Gauge", "cri.disk.inodes", 20, "", expectedTags)
 }
+
+func TestCriCheckImageMetrics(t *testing.T) {
+	mockCri := &crimock.MockCRIClient{}
+	mockCri.On("ImageFsInfo").Return(&criTypes.ImageFsInfoResponse{
+		ImageFilesystems: []*criTypes.FilesystemUsage{
+			{
+				FsId:       &criTypes.FilesystemIdentifier{Mountpoint: "/var/lib/containerd"},
+				UsedBytes:  &criTypes.UInt64Value{Value: 1000},
+				InodesUsed: &criTypes.UInt64Value{Value: 50},
+			},
+		},
+	}, nil)
+	mockCri.On("ListImages").Return([]*criTypes.Image{
+		{Id: "sha256:nginx", Size_: 300},
+		{Id: "sha256:busybox", Size_: 200},
+	}, nil)
+
+	mockSender := mocksender.NewMockSender("cri")
+	mockSender.SetupAcceptAll()
+
+	check := CRICheck{
+		instance:  &CRIConfig{CollectImageFs: true},
+		criGetter: func() (cri.CRIClient, error) { return mockCri, nil },
+	}
+	check.collectImageMetrics(mockSender)
+
+	fsTags := []string{"mountpoint:/var/lib/containerd"}
+	mockSender.AssertMetric(t, "Gauge", "cri.image_fs.used", 1000, "", fsTags)
+	mockSender.AssertMetric(t, "Gauge", "cri.image_fs.inodes", 50, "", fsTags)
+	mockSender.AssertMetric(t, "Gauge", "cri.images.count", 2, "", nil)
+	mockSender.AssertMetric(t, "Gauge", "cri.images.size", 500, "", nil)
+}
+
+// multiSocketCRIClient mocks a CRI client querying several sockets
+type multiSocketCRIClient struct {
+	crimock.MockCRIClient
+	containerRuntimes map[string]string
+}
+
+func (m *multiSocketCRIClient) LookupContainerRuntime(containerID string) (string, bool) {
+	runtime, found := m.containerRuntimes[containerID]
+	return runtime, found
+}
+
+func TestMetricsAdapterRuntimeTag(t *testing.T) {
+	sandboxed := generic.CreateContainerMeta("containerd", "cID100")
+	regular := generic.CreateContainerMeta("containerd", "cID101")
+
+	// single socket: the runtime of the container metadata is used
+	adapter := metricsAdapter{criGetter: func() (cri.CRIClient, error) { return &crimock.MockCRIClient{}, nil }}
+	assert.Equal(t, []string{"runtime:containerd"}, adapter.AdaptTags(nil, sandboxed))
+
+	// several sockets: the runtime of the socket reporting the container is used
+	client := &multiSocketCRIClient{containerRuntimes: map[string]string{"cID100": "kata"}}
+	adapter = metricsAdapter{criGetter: func() (cri.CRIClient, error) { return client, nil }}
+	assert.Equal(t, []string{"runtime:kata"}, adapter.AdaptTags(nil, sandboxed))
+	assert.Equal(t, []string{"runtime:containerd"}, adapter.AdaptTags(nil, regular))
+
+	// unreachable CRI
+	adapter = metricsAdapter{criGetter: func() (cri.CRIClient, error) { return nil, assert.AnError }}
+	assert.Equal(t, []string{"runtime:containerd"}, adapter.AdaptTags(nil, sandboxed))
+}
@@ -43,13 +43,22 @@ func NewProbe(cfg *ebpf.Config) (*Probe, error) {
 			return probe, nil
 		}
 
+		if cfg.EnableRemotePrebuiltArtifacts {
+			log.Warnf("error loading CO-RE oom-kill probe: %s. falling back to remote prebuilt probe", err)
+			if probe, err = loadOOMKillRemotePrebuiltProbe(cfg); err == nil {
+				return probe, nil
+			}
+		}
+
 		if !cfg.AllowRuntimeCompiledFallback {
 			return nil, fmt.Errorf("error loading CO-RE oom-kill probe: %s. set system_probe_config.allow_runtime_compiled_fallback to true to allow fallback to runtime compilation", err)
 		}
 		log.Warnf("error loading CO-RE oom-kill probe: %s. falling back to runtime compiled probe", err)
 	}
 
-	return loadOOMKillRuntimeCompiledProbe(cfg)
+	probe, err := loadOOMKillRuntimeCompiledProbe(cfg)
+	ebpf.StoreLoadOutcome("oom-kill", ebpf.LoadMethodRuntimeCompiled, err)
+	return probe, err
 }
 
 func loadOOMKillCOREProbe() (*Probe, error) {
@@ -74,6 +83,20 @@ func loadOOMKillCOREProbe() (*Probe, error) {
 	return probe, nil
 }
 
+func loadOOMKillRemotePrebuiltProbe(cfg *ebpf.Config) (*Probe, error) {
+	var probe *Probe
+	err := ebpf.LoadRemotePrebuiltAsset(cfg, "oom-kill.o", func(buf bytecode.AssetReader, opts manager.Options) (err error) {
+		probe, err = startOOMKillProbe(buf, opts)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("successfully loaded remote prebuilt version of oom-kill probe")
+	return probe, nil
+}
+
 func loadOOMKillRuntimeCompiledProbe(cfg *ebpf.Config) (*Probe, error) {
 	buf, err := runtime.OomKill.Compile(cfg, getCFlags(cfg))
 	if err != nil {
@@ -41,6 +41,10 @@ type Tracer struct {
 func NewTracer(cfg *ebpf.Config) (*Tracer, error) {
 	if cfg.EnableCORE {
 		probe, err := loadTCPQueueLengthCOREProbe(cfg)
+		if err != nil && cfg.EnableRemotePrebuiltArtifacts {
+			log.Warnf("error loading CO-RE tcp-queue-length probe: %s. falling back to remote prebuilt probe", err)
+			probe, err = loadTCPQueueLengthRemotePrebuiltProbe(cfg)
+		}
 		if err != nil {
 			if !cfg.AllowRuntimeCompiledFallback {
 				return nil, fmt.Errorf("error loading CO-RE tcp-queue-length probe: %s. set system_probe_config.allow_runtime_compiled_fallback to true to allow fallback to runtime compilation", err)
@@ -51,7 +55,9 @@ func NewTracer(cfg *ebpf.Config) (*Tracer, error) {
 		}
 	}
 
-	return loadTCPQueueLengthRuntimeCompiledProbe(cfg)
+	probe, err := loadTCPQueueLengthRuntimeCompiledProbe(cfg)
+	ebpf.StoreLoadOutcome("tcp-queue-length", ebpf.LoadMethodRuntimeCompiled, err)
+	return probe, err
 }
 
 func startTCPQueueLengthProbe(buf bytecode.AssetReader, managerOptions manager.Options) (*Tracer, error) {
@@ -170,6 +176,25 @@ func loadTCPQueueLengthCOREProbe(cfg *ebpf.Config) (*Tracer, error) {
 	return probe, nil
 }
 
+func loadTCPQueueLengthRemotePrebuiltProbe(cfg *ebpf.Config) (*Tracer, error) {
+	filename := "tcp-queue-length.o"
+	if cfg.BPFDebug {
+		filename = "tcp-queue-length-debug.o"
+	}
+
+	var probe *Tracer
+	err := ebpf.LoadRemotePrebuiltAsset(cfg, filename, func(buf bytecode.AssetReader, opts manager.Options) (err error) {
+		probe, err = startTCPQueueLengthProbe(buf, opts)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("successfully loaded remote prebuilt version of tcp-queue-length probe")
+	return probe, nil
+}
+
 func loadTCPQueueLengthRuntimeCompiledProbe(cfg *ebpf.Config) (*Tracer, error) {
 	compiledOutput, err := runtime.TcpQueueLength.Compile(cfg, []string{"-g"})
 	if err != nil {
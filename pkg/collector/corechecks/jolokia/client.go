@@ -0,0 +1,149 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package jolokia
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxResponseSize is the maximum size of a response of the Jolokia agent
+const maxResponseSize = 64 * 1024 * 1024
+
+// request is a Jolokia request, see https://jolokia.org/reference/html/manual/jolokia_protocol.html
+type request struct {
+	Type   string            `json:"type"`
+	MBean  string            `json:"mbean"`
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// response is a Jolokia response
+type response struct {
+	Status  int             `json:"status"`
+	Value   json.RawMessage `json:"value"`
+	Error   string          `json:"error"`
+	Request request         `json:"request"`
+}
+
+// client queries a Jolokia agent, or any bridge implementing the Jolokia protocol over HTTP
+type client struct {
+	url        string
+	user       string
+	password   string
+	httpClient *http.Client
+}
+
+func newClient(cfg *instanceConfig) *client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: !cfg.tlsVerify, //nolint:gosec // verification is disabled on demand only
+	}
+
+	return &client{
+		url:      cfg.url,
+		user:     cfg.user,
+		password: cfg.password,
+		httpClient: &http.Client{
+			Timeout:   cfg.timeout,
+			Transport: transport,
+		},
+	}
+}
+
+// search returns the names of the beans registered in the JVM
+func (c *client) search(ctx context.Context) ([]string, error) {
+	responses, err := c.do(ctx, []request{{Type: "search", MBean: "*:*"}})
+	if err != nil {
+		return nil, err
+	}
+	if responses[0].Status != http.StatusOK {
+		return nil, fmt.Errorf("search failed with status %d: %s", responses[0].Status, responses[0].Error)
+	}
+
+	var names []string
+	if err := json.Unmarshal(responses[0].Value, &names); err != nil {
+		return nil, fmt.Errorf("invalid search response: %s", err)
+	}
+	return names, nil
+}
+
+// read returns the attributes of the given beans, the beans that couldn't be read are omitted
+func (c *client) read(ctx context.Context, beans []string) (map[string]map[string]interface{}, error) {
+	requests := make([]request, 0, len(beans))
+	for _, name := range beans {
+		requests = append(requests, request{
+			Type:  "read",
+			MBean: name,
+			// don't fail the whole bean if some of its attributes can't be read
+			Config: map[string]string{"ignoreErrors": "true"},
+		})
+	}
+
+	responses, err := c.do(ctx, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := make(map[string]map[string]interface{}, len(responses))
+	for _, resp := range responses {
+		if resp.Status != http.StatusOK {
+			continue
+		}
+		values := make(map[string]interface{})
+		if err := json.Unmarshal(resp.Value, &values); err != nil {
+			continue
+		}
+		attributes[resp.Request.MBean] = values
+	}
+	return attributes, nil
+}
+
+// do sends a bulk request to the Jolokia agent
+func (c *client) do(ctx context.Context, requests []request) ([]response, error) {
+	body, err := json.Marshal(requests)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []response
+	if err := json.Unmarshal(data, &responses); err != nil {
+		return nil, fmt.Errorf("invalid response: %s", err)
+	}
+	if len(responses) != len(requests) {
+		return nil, errors.New("the count of responses doesn't match the count of requests")
+	}
+	return responses, nil
+}
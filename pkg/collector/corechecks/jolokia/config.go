@@ -0,0 +1,167 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package jolokia
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+)
+
+const (
+	defaultTimeout            = 10 * time.Second
+	defaultMaxReturnedMetrics = 350
+	defaultRefreshBeansPeriod = 10 * time.Minute
+	defaultJolokiaPath        = "/jolokia"
+)
+
+// InitConfig is used to deserialize the check init config
+type InitConfig struct {
+	Conf                     []FilterConfig `yaml:"conf"`
+	TimeoutSeconds           int            `yaml:"timeout"`
+	RefreshBeansPeriod       int            `yaml:"refresh_beans"`
+	CollectDefaultJVMMetrics *bool          `yaml:"collect_default_jvm_metrics"`
+	IsJMX                    bool           `yaml:"is_jmx"`
+}
+
+// InstanceConfig is used to deserialize the config of an instance. The host, port, user, password, name, tags,
+// max_returned_metrics and conf settings are compatible with the JMXFetch instances.
+type InstanceConfig struct {
+	JolokiaURL         string         `yaml:"jolokia_url"`
+	Host               string         `yaml:"host"`
+	Port               int            `yaml:"port"`
+	User               string         `yaml:"user"`
+	Password           string         `yaml:"password"`
+	TLSVerify          *bool          `yaml:"tls_verify"`
+	Name               string         `yaml:"name"`
+	Tags               []string       `yaml:"tags"`
+	TimeoutSeconds     int            `yaml:"timeout"`
+	MaxReturnedMetrics int            `yaml:"max_returned_metrics"`
+	RefreshBeansPeriod int            `yaml:"refresh_beans"`
+	Conf               []FilterConfig `yaml:"conf"`
+	// CollectDefaultJVMMetrics overrides the init_config setting for the instance
+	CollectDefaultJVMMetrics *bool `yaml:"collect_default_jvm_metrics"`
+	IsJMX                    bool  `yaml:"is_jmx"`
+}
+
+// FilterConfig is used to deserialize an item of the conf list of JMXFetch
+type FilterConfig struct {
+	Include map[string]interface{} `yaml:"include"`
+	Exclude map[string]interface{} `yaml:"exclude"`
+}
+
+// instanceConfig is the validated configuration of an instance
+type instanceConfig struct {
+	url                string
+	user               string
+	password           string
+	tlsVerify          bool
+	name               string
+	tags               []string
+	timeout            time.Duration
+	maxReturnedMetrics int
+	refreshBeansPeriod time.Duration
+	filters            []*beanFilter
+}
+
+func newInstanceConfig(rawInstance integration.Data, rawInitConfig integration.Data) (*instanceConfig, error) {
+	instance := InstanceConfig{}
+	initConfig := InitConfig{}
+
+	if err := yaml.Unmarshal(rawInitConfig, &initConfig); err != nil {
+		return nil, fmt.Errorf("invalid init_config: %s", err)
+	}
+	if err := yaml.Unmarshal(rawInstance, &instance); err != nil {
+		return nil, fmt.Errorf("invalid instance config: %s", err)
+	}
+	// the instances flagged with is_jmx are scheduled on JMXFetch, so they only reach this check when it's run
+	// explicitly, e.g. by the check command, and would then be collected twice
+	if instance.IsJMX || initConfig.IsJMX {
+		return nil, errors.New("is_jmx must not be set, the configurations with is_jmx are run by JMXFetch")
+	}
+
+	c := &instanceConfig{
+		url:                instance.JolokiaURL,
+		user:               instance.User,
+		password:           instance.Password,
+		tlsVerify:          instance.TLSVerify == nil || *instance.TLSVerify,
+		name:               instance.Name,
+		tags:               instance.Tags,
+		maxReturnedMetrics: instance.MaxReturnedMetrics,
+	}
+
+	if c.url == "" {
+		if instance.Host == "" || instance.Port == 0 {
+			return nil, errors.New("either jolokia_url or host and port are required")
+		}
+		c.url = (&url.URL{
+			Scheme: "http",
+			Host:   instance.Host + ":" + strconv.Itoa(instance.Port),
+			Path:   defaultJolokiaPath,
+		}).String()
+	} else if u, err := url.Parse(c.url); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid jolokia_url %q, expected an http or https URL", c.url)
+	}
+
+	if c.name == "" {
+		c.name = c.url
+	}
+	if c.maxReturnedMetrics <= 0 {
+		c.maxReturnedMetrics = defaultMaxReturnedMetrics
+	}
+
+	switch {
+	case instance.TimeoutSeconds > 0:
+		c.timeout = time.Duration(instance.TimeoutSeconds) * time.Second
+	case initConfig.TimeoutSeconds > 0:
+		c.timeout = time.Duration(initConfig.TimeoutSeconds) * time.Second
+	default:
+		c.timeout = defaultTimeout
+	}
+
+	switch {
+	case instance.RefreshBeansPeriod > 0:
+		c.refreshBeansPeriod = time.Duration(instance.RefreshBeansPeriod) * time.Second
+	case initConfig.RefreshBeansPeriod > 0:
+		c.refreshBeansPeriod = time.Duration(initConfig.RefreshBeansPeriod) * time.Second
+	default:
+		c.refreshBeansPeriod = defaultRefreshBeansPeriod
+	}
+
+	// the instance filters are evaluated before the ones of the init config, like in JMXFetch
+	for i, conf := range append(instance.Conf, initConfig.Conf...) {
+		filter, err := newBeanFilter(conf)
+		if err != nil {
+			return nil, fmt.Errorf("invalid conf item %d: %s", i, err)
+		}
+		c.filters = append(c.filters, filter)
+	}
+
+	// the default JVM metrics are collected unless disabled, like with JMXFetch, after the configured filters so
+	// that these take precedence
+	collectDefaultJVMMetrics := true
+	switch {
+	case instance.CollectDefaultJVMMetrics != nil:
+		collectDefaultJVMMetrics = *instance.CollectDefaultJVMMetrics
+	case initConfig.CollectDefaultJVMMetrics != nil:
+		collectDefaultJVMMetrics = *initConfig.CollectDefaultJVMMetrics
+	}
+	if collectDefaultJVMMetrics {
+		c.filters = append(c.filters, defaultJVMFilters...)
+	}
+
+	if len(c.filters) == 0 {
+		return nil, errors.New("at least one conf item is required when collect_default_jvm_metrics is disabled")
+	}
+
+	return c, nil
+}
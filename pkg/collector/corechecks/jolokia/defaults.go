@@ -0,0 +1,131 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package jolokia
+
+import (
+	"gopkg.in/yaml.v2"
+)
+
+// defaultJVMConf is the conf list collecting the default JVM metrics of JMXFetch, with the same aliases, so that the
+// dashboards and monitors built on the JMXFetch metrics keep working
+const defaultJVMConf = `
+- include:
+    domain: java.lang
+    type: Memory
+    attribute:
+      HeapMemoryUsage.used:
+        alias: jvm.heap_memory
+      HeapMemoryUsage.committed:
+        alias: jvm.heap_memory_committed
+      HeapMemoryUsage.init:
+        alias: jvm.heap_memory_init
+      HeapMemoryUsage.max:
+        alias: jvm.heap_memory_max
+      NonHeapMemoryUsage.used:
+        alias: jvm.non_heap_memory
+      NonHeapMemoryUsage.committed:
+        alias: jvm.non_heap_memory_committed
+      NonHeapMemoryUsage.init:
+        alias: jvm.non_heap_memory_init
+      NonHeapMemoryUsage.max:
+        alias: jvm.non_heap_memory_max
+- include:
+    domain: java.lang
+    type: Threading
+    attribute:
+      ThreadCount:
+        alias: jvm.thread_count
+- include:
+    domain: java.lang
+    type: ClassLoading
+    attribute:
+      LoadedClassCount:
+        alias: jvm.loaded_classes
+      UnloadedClassCount:
+        alias: jvm.unloaded_classes
+- include:
+    domain: java.lang
+    type: OperatingSystem
+    attribute:
+      OpenFileDescriptorCount:
+        alias: jvm.os.open_file_descriptors
+      ProcessCpuLoad:
+        alias: jvm.cpu_load.process
+      SystemCpuLoad:
+        alias: jvm.cpu_load.system
+- include:
+    domain: java.nio
+    type: BufferPool
+    name:
+      - direct
+      - mapped
+    attribute:
+      Count:
+        alias: jvm.buffer_pool.$name.count
+      MemoryUsed:
+        alias: jvm.buffer_pool.$name.used
+      TotalCapacity:
+        alias: jvm.buffer_pool.$name.capacity
+- include:
+    domain: java.lang
+    type: GarbageCollector
+    name:
+      - Copy
+      - PS Scavenge
+      - ParNew
+      - G1 Young Generation
+    attribute:
+      CollectionCount:
+        alias: jvm.gc.minor_collection_count
+        metric_type: counter
+      CollectionTime:
+        alias: jvm.gc.minor_collection_time
+        metric_type: counter
+- include:
+    domain: java.lang
+    type: GarbageCollector
+    name:
+      - MarkSweepCompact
+      - PS MarkSweep
+      - ConcurrentMarkSweep
+      - G1 Old Generation
+    attribute:
+      CollectionCount:
+        alias: jvm.gc.major_collection_count
+        metric_type: counter
+      CollectionTime:
+        alias: jvm.gc.major_collection_time
+        metric_type: counter
+- include:
+    domain: java.lang
+    type: GarbageCollector
+    attribute:
+      CollectionCount:
+        alias: jvm.gc.cms.count
+        metric_type: counter
+      CollectionTime:
+        alias: jvm.gc.parnew.time
+        metric_type: counter
+`
+
+// defaultJVMFilters is parsed once, as the filters are only read by the checks
+var defaultJVMFilters = mustParseFilters(defaultJVMConf)
+
+func mustParseFilters(conf string) []*beanFilter {
+	var confs []FilterConfig
+	if err := yaml.Unmarshal([]byte(conf), &confs); err != nil {
+		panic(err)
+	}
+	filters := make([]*beanFilter, 0, len(confs))
+	for _, c := range confs {
+		filter, err := newBeanFilter(c)
+		if err != nil {
+			panic(err)
+		}
+		filters = append(filters, filter)
+	}
+	return filters
+}
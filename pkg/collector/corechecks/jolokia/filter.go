@@ -0,0 +1,336 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package jolokia
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// metricType is the type of the metric reported for an attribute, as named in the JMXFetch configurations
+type metricType string
+
+const (
+	metricTypeGauge          metricType = "gauge"
+	metricTypeCounter        metricType = "counter"
+	metricTypeRate           metricType = "rate"
+	metricTypeMonotonicCount metricType = "monotonic_count"
+	metricTypeHistogram      metricType = "histogram"
+)
+
+// bean is a parsed MBean name, like domain:key1=value1,key2=value2
+type bean struct {
+	name       string
+	domain     string
+	properties map[string]string
+}
+
+// parseBeanName parses a canonical MBean name
+func parseBeanName(name string) (*bean, error) {
+	domain, rawProperties, ok := strings.Cut(name, ":")
+	if !ok || domain == "" {
+		return nil, fmt.Errorf("invalid bean name %q", name)
+	}
+
+	b := &bean{
+		name:       name,
+		domain:     domain,
+		properties: make(map[string]string),
+	}
+	for _, property := range splitBeanProperties(rawProperties) {
+		key, value, ok := strings.Cut(property, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid bean name %q", name)
+		}
+		b.properties[key] = value
+	}
+	return b, nil
+}
+
+// splitBeanProperties splits the key properties of a bean name on the commas that aren't quoted
+func splitBeanProperties(s string) []string {
+	var (
+		properties []string
+		quoted     bool
+		start      int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				properties = append(properties, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if start < len(s) {
+		properties = append(properties, s[start:])
+	}
+	return properties
+}
+
+// beanMatcher matches beans on the criteria of an include or exclude item
+type beanMatcher struct {
+	domains      []string
+	domainRegex  []*regexp.Regexp
+	beans        []string
+	beanRegex    []*regexp.Regexp
+	properties   map[string][]string
+	criteriaSize int
+}
+
+// attributeConfig is the configuration of the metric reported for an attribute
+type attributeConfig struct {
+	metricType metricType
+	alias      string
+}
+
+// beanFilter is an item of the JMXFetch conf list, selecting the attributes of the beans to collect
+type beanFilter struct {
+	include *beanMatcher
+	exclude *beanMatcher
+	// attributes holds the attributes to collect, all the numeric attributes are collected when it's nil
+	attributes map[string]attributeConfig
+	// excludedAttributes holds the attributes that aren't collected
+	excludedAttributes []string
+	tags               map[string]string
+}
+
+func newBeanFilter(conf FilterConfig) (*beanFilter, error) {
+	if len(conf.Include) == 0 {
+		return nil, errors.New("the include section is required")
+	}
+
+	f := &beanFilter{}
+
+	var err error
+	if f.include, err = newBeanMatcher(conf.Include); err != nil {
+		return nil, fmt.Errorf("invalid include section: %s", err)
+	}
+	if len(conf.Exclude) > 0 {
+		if f.exclude, err = newBeanMatcher(conf.Exclude); err != nil {
+			return nil, fmt.Errorf("invalid exclude section: %s", err)
+		}
+	}
+
+	if rawAttributes, ok := conf.Include["attribute"]; ok {
+		if f.attributes, err = parseAttributes(rawAttributes); err != nil {
+			return nil, err
+		}
+	}
+	if rawAttributes, ok := conf.Exclude["attribute"]; ok {
+		excluded, err := parseAttributes(rawAttributes)
+		if err != nil {
+			return nil, err
+		}
+		for name := range excluded {
+			f.excludedAttributes = append(f.excludedAttributes, name)
+		}
+	}
+
+	if rawTags, ok := conf.Include["tags"]; ok {
+		tags, ok := rawTags.(map[interface{}]interface{})
+		if !ok {
+			return nil, errors.New("tags must be a mapping")
+		}
+		f.tags = make(map[string]string, len(tags))
+		for key, value := range tags {
+			f.tags[fmt.Sprint(key)] = fmt.Sprint(value)
+		}
+	}
+
+	return f, nil
+}
+
+func newBeanMatcher(criteria map[string]interface{}) (*beanMatcher, error) {
+	m := &beanMatcher{properties: make(map[string][]string)}
+	for key, value := range criteria {
+		if key == "attribute" || key == "tags" {
+			continue
+		}
+
+		values, err := toStringList(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", key, err)
+		}
+
+		switch key {
+		case "domain":
+			m.domains = values
+		case "domain_regex":
+			if m.domainRegex, err = compileRegexes(values); err != nil {
+				return nil, err
+			}
+		case "bean", "bean_name":
+			m.beans = append(m.beans, values...)
+		case "bean_regex":
+			if m.beanRegex, err = compileRegexes(values); err != nil {
+				return nil, err
+			}
+		default:
+			m.properties[key] = values
+		}
+		m.criteriaSize++
+	}
+	return m, nil
+}
+
+// matchAll returns true if the bean matches all the criteria, which is how the include items are evaluated
+func (m *beanMatcher) matchAll(b *bean) bool {
+	if m.domains != nil && !slices.Contains(m.domains, b.domain) {
+		return false
+	}
+	if m.domainRegex != nil && !matchAnyRegex(m.domainRegex, b.domain) {
+		return false
+	}
+	if m.beans != nil && !slices.Contains(m.beans, b.name) {
+		return false
+	}
+	if m.beanRegex != nil && !matchAnyRegex(m.beanRegex, b.name) {
+		return false
+	}
+	for key, values := range m.properties {
+		value, ok := b.properties[key]
+		if !ok || !slices.Contains(values, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAny returns true if the bean matches any of the criteria, which is how the exclude items are evaluated
+func (m *beanMatcher) matchAny(b *bean) bool {
+	if slices.Contains(m.domains, b.domain) || matchAnyRegex(m.domainRegex, b.domain) {
+		return true
+	}
+	if slices.Contains(m.beans, b.name) || matchAnyRegex(m.beanRegex, b.name) {
+		return true
+	}
+	for key, values := range m.properties {
+		if value, ok := b.properties[key]; ok && slices.Contains(values, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchBean returns true if some attributes of the bean are selected by the filter
+func (f *beanFilter) matchBean(b *bean) bool {
+	if !f.include.matchAll(b) {
+		return false
+	}
+	return f.exclude == nil || f.exclude.criteriaSize == 0 || !f.exclude.matchAny(b)
+}
+
+// matchAttribute returns the configuration of the metric of an attribute of a matched bean, and whether the attribute
+// is collected. Composite attributes are named after their attribute and key, like HeapMemoryUsage.used.
+func (f *beanFilter) matchAttribute(name string) (attributeConfig, bool) {
+	if slices.Contains(f.excludedAttributes, name) {
+		return attributeConfig{}, false
+	}
+	if f.attributes == nil {
+		return attributeConfig{metricType: metricTypeGauge}, true
+	}
+	if conf, ok := f.attributes[name]; ok {
+		return conf, true
+	}
+	// the keys of a composite attribute are collected when the attribute itself is selected
+	if attribute, _, ok := strings.Cut(name, "."); ok {
+		if conf, ok := f.attributes[attribute]; ok && conf.alias == "" {
+			return conf, true
+		}
+	}
+	return attributeConfig{}, false
+}
+
+// parseAttributes parses the attribute section of a filter, either a list of attribute names or a mapping of the
+// attribute names to their metric_type and alias
+func parseAttributes(raw interface{}) (map[string]attributeConfig, error) {
+	attributes := make(map[string]attributeConfig)
+	switch raw := raw.(type) {
+	case []interface{}, string:
+		names, err := toStringList(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute: %s", err)
+		}
+		for _, name := range names {
+			attributes[name] = attributeConfig{metricType: metricTypeGauge}
+		}
+	case map[interface{}]interface{}:
+		for rawName, rawConf := range raw {
+			name := fmt.Sprint(rawName)
+			conf := attributeConfig{metricType: metricTypeGauge}
+			if settings, ok := rawConf.(map[interface{}]interface{}); ok {
+				if value, ok := settings["metric_type"]; ok {
+					conf.metricType = metricType(strings.ToLower(fmt.Sprint(value)))
+				}
+				if value, ok := settings["alias"]; ok {
+					conf.alias = fmt.Sprint(value)
+				}
+			}
+			switch conf.metricType {
+			case metricTypeGauge, metricTypeCounter, metricTypeRate, metricTypeMonotonicCount, metricTypeHistogram:
+			default:
+				return nil, fmt.Errorf("unsupported metric_type %q of attribute %s", conf.metricType, name)
+			}
+			attributes[name] = conf
+		}
+	default:
+		return nil, errors.New("attribute must be a list or a mapping")
+	}
+	return attributes, nil
+}
+
+func toStringList(value interface{}) ([]string, error) {
+	switch value := value.(type) {
+	case string:
+		return []string{value}, nil
+	case int, float64, bool:
+		return []string{fmt.Sprint(value)}, nil
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			switch item.(type) {
+			case string, int, float64, bool:
+				values = append(values, fmt.Sprint(item))
+			default:
+				return nil, errors.New("expected a list of strings")
+			}
+		}
+		return values, nil
+	}
+	return nil, errors.New("expected a string or a list of strings")
+}
+
+// compileRegexes compiles the domain_regex and bean_regex patterns. They are anchored, as these must match the whole
+// domain or bean name like with JMXFetch, which uses Matcher.matches.
+func compileRegexes(patterns []string) ([]*regexp.Regexp, error) {
+	regexes := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %s", pattern, err)
+		}
+		regexes = append(regexes, re)
+	}
+	return regexes, nil
+}
+
+func matchAnyRegex(regexes []*regexp.Regexp, s string) bool {
+	for _, re := range regexes {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package jolokia
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestParseBeanName(t *testing.T) {
+	b, err := parseBeanName(`kafka.server:type=BrokerTopicMetrics,name=BytesInPerSec,topic="a,b"`)
+	require.NoError(t, err)
+	assert.Equal(t, "kafka.server", b.domain)
+	assert.Equal(t, map[string]string{"type": "BrokerTopicMetrics", "name": "BytesInPerSec", "topic": `"a,b"`}, b.properties)
+
+	_, err = parseBeanName("no-domain")
+	assert.Error(t, err)
+	_, err = parseBeanName("java.lang:type")
+	assert.Error(t, err)
+}
+
+func newTestFilter(t *testing.T, conf string) *beanFilter {
+	var filterConf FilterConfig
+	require.NoError(t, yaml.Unmarshal([]byte(conf), &filterConf))
+	filter, err := newBeanFilter(filterConf)
+	require.NoError(t, err)
+	return filter
+}
+
+func TestBeanFilter(t *testing.T) {
+	mustParse := func(name string) *bean {
+		b, err := parseBeanName(name)
+		require.NoError(t, err)
+		return b
+	}
+	memory := mustParse("java.lang:type=Memory")
+	g1Young := mustParse("java.lang:type=GarbageCollector,name=G1 Young Generation")
+	g1Old := mustParse("java.lang:type=GarbageCollector,name=G1 Old Generation")
+	bytesIn := mustParse("kafka.server:type=BrokerTopicMetrics,name=BytesInPerSec")
+
+	filter := newTestFilter(t, `
+include:
+  domain: java.lang
+  type:
+    - Memory
+    - GarbageCollector
+  attribute:
+    HeapMemoryUsage.used:
+      alias: jvm.heap_memory
+    CollectionCount:
+      metric_type: counter
+exclude:
+  name: G1 Old Generation
+`)
+	assert.True(t, filter.matchBean(memory))
+	assert.True(t, filter.matchBean(g1Young))
+	assert.False(t, filter.matchBean(g1Old))
+	assert.False(t, filter.matchBean(bytesIn))
+
+	conf, ok := filter.matchAttribute("HeapMemoryUsage.used")
+	assert.True(t, ok)
+	assert.Equal(t, attributeConfig{metricType: metricTypeGauge, alias: "jvm.heap_memory"}, conf)
+	_, ok = filter.matchAttribute("HeapMemoryUsage.max")
+	assert.False(t, ok)
+	conf, ok = filter.matchAttribute("CollectionCount")
+	assert.True(t, ok)
+	assert.Equal(t, metricTypeCounter, conf.metricType)
+
+	// all the attributes are collected when none is listed
+	filter = newTestFilter(t, `
+include:
+  bean_regex: kafka\.server:type=BrokerTopicMetrics,.*
+exclude:
+  attribute:
+    - RateUnit
+`)
+	assert.True(t, filter.matchBean(bytesIn))
+	assert.False(t, filter.matchBean(memory))
+	_, ok = filter.matchAttribute("Count")
+	assert.True(t, ok)
+	_, ok = filter.matchAttribute("RateUnit")
+	assert.False(t, ok)
+
+	// a composite attribute selects all its keys
+	filter = newTestFilter(t, `
+include:
+  bean: java.lang:type=Memory
+  attribute:
+    - HeapMemoryUsage
+`)
+	assert.True(t, filter.matchBean(memory))
+	_, ok = filter.matchAttribute("HeapMemoryUsage.committed")
+	assert.True(t, ok)
+
+	// the regexes must match the whole domain or bean name
+	filter = newTestFilter(t, `
+include:
+  domain_regex: java
+`)
+	assert.False(t, filter.matchBean(memory))
+	filter = newTestFilter(t, `
+include:
+  domain_regex: java\..*|kafka\..*
+  bean_regex: .*Memory|.*PerSec
+`)
+	assert.True(t, filter.matchBean(memory))
+	assert.True(t, filter.matchBean(bytesIn))
+	assert.False(t, filter.matchBean(g1Young))
+}
+
+func TestBeanFilterErrors(t *testing.T) {
+	for _, conf := range []string{
+		`exclude: {domain: java.lang}`,
+		`include: {domain_regex: "("}`,
+		`include: {domain: {a: b}}`,
+		`include: {attribute: {Count: {metric_type: summary}}}`,
+		`include: {attribute: 3}`,
+		`include: {tags: [a]}`,
+	} {
+		var filterConf FilterConfig
+		require.NoError(t, yaml.Unmarshal([]byte(conf), &filterConf))
+		_, err := newBeanFilter(filterConf)
+		assert.Error(t, err, conf)
+	}
+}
+
+func TestConvertMetricName(t *testing.T) {
+	assert.Equal(t, "heap_memory_usage.used", convertMetricName("HeapMemoryUsage.used"))
+	assert.Equal(t, "collection_count", convertMetricName("CollectionCount"))
+	assert.Equal(t, "one_minute_rate", convertMetricName("OneMinuteRate"))
+	assert.Equal(t, "count_per_sec", convertMetricName("count-per-sec"))
+}
@@ -0,0 +1,306 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package jolokia implements a check collecting JMX metrics through a Jolokia agent or any JMX over HTTP bridge
+// implementing the Jolokia protocol. Its configuration is compatible with the JMXFetch one, which allows collecting
+// JMX metrics without running JMXFetch, and thus Java, alongside the agent.
+package jolokia
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/option"
+)
+
+const (
+	// CheckName is the name of the check
+	CheckName = "jolokia"
+
+	canConnectServiceCheck = "jolokia.can_connect"
+)
+
+var (
+	firstCapPattern   = regexp.MustCompile(`(.)([A-Z][a-z]+)`)
+	allCapPattern     = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	metricReplacement = regexp.MustCompile(`([^a-zA-Z0-9_.]+)|(^[^a-zA-Z]+)`)
+	dotUnderscore     = regexp.MustCompile(`_*\._*`)
+)
+
+// matchedBean is a bean with some attributes selected by the filters
+type matchedBean struct {
+	bean    *bean
+	filters []*beanFilter
+}
+
+// Check collects the JMX metrics of a single JVM per instance
+type Check struct {
+	core.CheckBase
+	cfg    *instanceConfig
+	client *client
+	now    func() time.Time
+
+	beans          []matchedBean
+	beansRefreshed time.Time
+}
+
+// Configure parses the instance configuration
+func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigDigest uint64, rawInstance integration.Data, rawInitConfig integration.Data, source string) error {
+	cfg, err := newInstanceConfig(rawInstance, rawInitConfig)
+	if err != nil {
+		return err
+	}
+
+	c.BuildID(integrationConfigDigest, rawInstance, rawInitConfig)
+	c.cfg = cfg
+	c.client = newClient(cfg)
+
+	return c.CommonConfigure(senderManager, rawInitConfig, rawInstance, source)
+}
+
+// Run collects the attributes of the beans matching the filters
+func (c *Check) Run() error {
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+
+	instanceTags := append([]string{"instance:" + c.cfg.name}, c.cfg.tags...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.timeout)
+	defer cancel()
+
+	if err := c.collect(ctx, sender, instanceTags); err != nil {
+		sender.ServiceCheck(canConnectServiceCheck, servicecheck.ServiceCheckCritical, "", instanceTags, err.Error())
+		sender.Commit()
+		return err
+	}
+
+	sender.ServiceCheck(canConnectServiceCheck, servicecheck.ServiceCheckOK, "", instanceTags, "")
+	sender.Commit()
+	return nil
+}
+
+func (c *Check) collect(ctx context.Context, sender sender.Sender, instanceTags []string) error {
+	if c.beans == nil || c.now().Sub(c.beansRefreshed) >= c.cfg.refreshBeansPeriod {
+		if err := c.refreshBeans(ctx); err != nil {
+			return fmt.Errorf("couldn't list the beans: %w", err)
+		}
+	}
+	if len(c.beans) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(c.beans))
+	for _, matched := range c.beans {
+		names = append(names, matched.bean.name)
+	}
+	attributes, err := c.client.read(ctx, names)
+	if err != nil {
+		return fmt.Errorf("couldn't read the beans: %w", err)
+	}
+
+	count := 0
+	for _, matched := range c.beans {
+		values, ok := attributes[matched.bean.name]
+		if !ok {
+			continue
+		}
+
+		tags := beanTags(matched.bean, instanceTags)
+		for _, attribute := range flattenAttributes(values) {
+			filter, conf, ok := matchAttribute(matched.filters, attribute.name)
+			if !ok {
+				continue
+			}
+
+			if count >= c.cfg.maxReturnedMetrics {
+				log.Warnf("jolokia instance %s: the number of metrics exceeds max_returned_metrics (%d), the remaining metrics are dropped", c.cfg.name, c.cfg.maxReturnedMetrics)
+				return nil
+			}
+			count++
+
+			metricTags := tags
+			if len(filter.tags) > 0 {
+				metricTags = append(slices.Clip(tags), filterTags(filter, matched.bean)...)
+			}
+			submit(sender, conf.metricType, metricName(conf, matched.bean, attribute.name), attribute.value, metricTags)
+		}
+	}
+	return nil
+}
+
+// refreshBeans lists the beans of the JVM and keeps the ones selected by the filters
+func (c *Check) refreshBeans(ctx context.Context) error {
+	names, err := c.client.search(ctx)
+	if err != nil {
+		return err
+	}
+	slices.Sort(names)
+
+	beans := make([]matchedBean, 0)
+	for _, name := range names {
+		b, err := parseBeanName(name)
+		if err != nil {
+			log.Debugf("jolokia instance %s: %s", c.cfg.name, err)
+			continue
+		}
+
+		var filters []*beanFilter
+		for _, filter := range c.cfg.filters {
+			if filter.matchBean(b) {
+				filters = append(filters, filter)
+			}
+		}
+		if len(filters) > 0 {
+			beans = append(beans, matchedBean{bean: b, filters: filters})
+		}
+	}
+
+	c.beans = beans
+	c.beansRefreshed = c.now()
+	log.Debugf("jolokia instance %s: %d beans out of %d match the filters", c.cfg.name, len(beans), len(names))
+	return nil
+}
+
+// matchAttribute returns the first filter collecting the attribute, like JMXFetch each attribute is reported once
+func matchAttribute(filters []*beanFilter, name string) (*beanFilter, attributeConfig, bool) {
+	for _, filter := range filters {
+		if conf, ok := filter.matchAttribute(name); ok {
+			return filter, conf, true
+		}
+	}
+	return nil, attributeConfig{}, false
+}
+
+type attributeValue struct {
+	name  string
+	value float64
+}
+
+// flattenAttributes returns the numeric values of the attributes of a bean, sorted by name. The keys of composite
+// attributes are reported as attribute.key.
+func flattenAttributes(values map[string]interface{}) []attributeValue {
+	var attributes []attributeValue
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		switch value := values[name].(type) {
+		case map[string]interface{}:
+			for _, key := range slices.Sorted(maps.Keys(value)) {
+				if v, ok := toFloat(value[key]); ok {
+					attributes = append(attributes, attributeValue{name: name + "." + key, value: v})
+				}
+			}
+		default:
+			if v, ok := toFloat(value); ok {
+				attributes = append(attributes, attributeValue{name: name, value: v})
+			}
+		}
+	}
+	return attributes
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case float64:
+		return value, true
+	case bool:
+		if value {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// beanTags returns the tags of the metrics of a bean: the instance tags, the domain and the bean properties
+func beanTags(b *bean, instanceTags []string) []string {
+	tags := make([]string, 0, len(instanceTags)+len(b.properties)+1)
+	tags = append(tags, instanceTags...)
+	tags = append(tags, "jmx_domain:"+b.domain)
+	for _, key := range slices.Sorted(maps.Keys(b.properties)) {
+		tags = append(tags, key+":"+strings.Trim(b.properties[key], `"`))
+	}
+	return tags
+}
+
+// filterTags returns the tags added by a filter, their values may refer to the bean properties as $property
+func filterTags(filter *beanFilter, b *bean) []string {
+	tags := make([]string, 0, len(filter.tags))
+	for _, key := range slices.Sorted(maps.Keys(filter.tags)) {
+		tags = append(tags, key+":"+replaceBeanPlaceholders(filter.tags[key], b, ""))
+	}
+	return tags
+}
+
+// metricName returns the alias of the attribute, or a name derived from the domain and the attribute name like JMXFetch
+func metricName(conf attributeConfig, b *bean, attribute string) string {
+	if conf.alias != "" {
+		return replaceBeanPlaceholders(conf.alias, b, attribute)
+	}
+	return "jmx." + b.domain + "." + convertMetricName(attribute)
+}
+
+// replaceBeanPlaceholders replaces $domain, $attribute and $property by their values
+func replaceBeanPlaceholders(s string, b *bean, attribute string) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+	s = strings.ReplaceAll(s, "$domain", b.domain)
+	if attribute != "" {
+		s = strings.ReplaceAll(s, "$attribute", attribute)
+	}
+	// replace the longest property names first, so that a property name being a prefix of another one doesn't
+	// break the replacement of the latter
+	keys := slices.SortedFunc(maps.Keys(b.properties), func(a, b string) int { return len(b) - len(a) })
+	for _, key := range keys {
+		s = strings.ReplaceAll(s, "$"+key, strings.Trim(b.properties[key], `"`))
+	}
+	return s
+}
+
+// convertMetricName converts an attribute name to a metric name, like JMXFetch does
+func convertMetricName(name string) string {
+	name = firstCapPattern.ReplaceAllString(name, "${1}_${2}")
+	name = allCapPattern.ReplaceAllString(name, "${1}_${2}")
+	name = metricReplacement.ReplaceAllString(name, "_")
+	name = dotUnderscore.ReplaceAllString(name, ".")
+	return strings.ToLower(name)
+}
+
+func submit(sender sender.Sender, mtype metricType, name string, value float64, tags []string) {
+	switch mtype {
+	case metricTypeCounter, metricTypeRate:
+		sender.Rate(name, value, "", tags)
+	case metricTypeMonotonicCount:
+		sender.MonotonicCount(name, value, "", tags)
+	case metricTypeHistogram:
+		sender.Histogram(name, value, "", tags)
+	default:
+		sender.Gauge(name, value, "", tags)
+	}
+}
+
+// Factory creates a new check factory
+func Factory() option.Option[func() check.Check] {
+	return option.New(newCheck)
+}
+
+func newCheck() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(CheckName),
+		now:       time.Now,
+	}
+}
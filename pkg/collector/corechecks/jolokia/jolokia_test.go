@@ -0,0 +1,235 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package jolokia
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+var testBeans = map[string]map[string]interface{}{
+	"java.lang:type=Memory": {
+		"HeapMemoryUsage": map[string]interface{}{"used": 1024, "max": 4096},
+		"Verbose":         false,
+		"ObjectName":      map[string]interface{}{"objectName": "java.lang:type=Memory"},
+	},
+	"java.lang:type=GarbageCollector,name=G1 Young Generation": {
+		"CollectionCount": 12,
+		"CollectionTime":  345,
+		"Name":            "G1 Young Generation",
+	},
+	"kafka.server:type=BrokerTopicMetrics,name=BytesInPerSec": {
+		"Count":         42,
+		"OneMinuteRate": 1.5,
+		"RateUnit":      "SECONDS",
+	},
+}
+
+func newJolokiaServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		if user != "jmx" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var requests []request
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&requests))
+
+		responses := make([]map[string]interface{}, 0, len(requests))
+		for _, req := range requests {
+			resp := map[string]interface{}{"request": req, "status": 200}
+			switch req.Type {
+			case "search":
+				var names []string
+				for name := range testBeans {
+					names = append(names, name)
+				}
+				resp["value"] = names
+			case "read":
+				if value, ok := testBeans[req.MBean]; ok {
+					resp["value"] = value
+				} else {
+					resp["status"] = 404
+					resp["error"] = "javax.management.InstanceNotFoundException"
+				}
+			}
+			responses = append(responses, resp)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(responses))
+	}))
+}
+
+func runCheck(t *testing.T, instance string, initConfig string) (*mocksender.MockSender, error) {
+	c := newCheck().(*Check)
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	require.NoError(t, c.Configure(senderManager, integration.FakeConfigHash, integration.Data(instance), integration.Data(initConfig), "test"))
+
+	s := mocksender.NewMockSenderWithSenderManager(c.ID(), senderManager)
+	s.SetupAcceptAll()
+	return s, c.Run()
+}
+
+func TestJolokiaCheck(t *testing.T) {
+	srv := newJolokiaServer(t)
+	defer srv.Close()
+
+	s, err := runCheck(t, fmt.Sprintf(`
+jolokia_url: %s/jolokia
+name: broker
+user: jmx
+password: secret
+tags:
+  - env:test
+conf:
+  - include:
+      domain: kafka.server
+      bean_regex: .*BrokerTopicMetrics.*
+      attribute:
+        Count:
+          metric_type: monotonic_count
+          alias: kafka.net.$name.count
+      tags:
+        metric_name: $name
+`, srv.URL), `
+conf:
+  - include:
+      domain: java.lang
+      type: Memory
+      attribute:
+        - HeapMemoryUsage
+        - Verbose
+  - include:
+      domain: java.lang
+      type: GarbageCollector
+    exclude:
+      attribute:
+        - CollectionTime
+`)
+	require.NoError(t, err)
+
+	instanceTags := []string{"instance:broker", "env:test"}
+	memoryTags := append(instanceTags, "jmx_domain:java.lang", "type:Memory")
+	s.AssertMetric(t, "Gauge", "jmx.java.lang.heap_memory_usage.used", 1024, "", memoryTags)
+	s.AssertMetric(t, "Gauge", "jmx.java.lang.heap_memory_usage.max", 4096, "", memoryTags)
+	s.AssertMetric(t, "Gauge", "jmx.java.lang.verbose", 0, "", memoryTags)
+
+	gcTags := append(instanceTags, "jmx_domain:java.lang", "name:G1 Young Generation", "type:GarbageCollector")
+	s.AssertMetric(t, "Gauge", "jmx.java.lang.collection_count", 12, "", gcTags)
+	s.AssertNotCalled(t, "Gauge", "jmx.java.lang.collection_time", mock.Anything, mock.Anything, mock.Anything)
+	// the attributes not collected by the configured filters are collected by the default JVM ones
+	s.AssertMetric(t, "Rate", "jvm.gc.minor_collection_time", 345, "", gcTags)
+	s.AssertNotCalled(t, "Rate", "jvm.gc.minor_collection_count", mock.Anything, mock.Anything, mock.Anything)
+	s.AssertNotCalled(t, "Gauge", "jvm.heap_memory", mock.Anything, mock.Anything, mock.Anything)
+
+	kafkaTags := append(instanceTags, "jmx_domain:kafka.server", "name:BytesInPerSec", "type:BrokerTopicMetrics", "metric_name:BytesInPerSec")
+	s.AssertMetric(t, "MonotonicCount", "kafka.net.BytesInPerSec.count", 42, "", kafkaTags)
+	s.AssertNotCalled(t, "Gauge", "jmx.kafka.server.one_minute_rate", mock.Anything, mock.Anything, mock.Anything)
+
+	s.AssertServiceCheck(t, canConnectServiceCheck, servicecheck.ServiceCheckOK, "", instanceTags, "")
+}
+
+func TestJolokiaCheckMaxReturnedMetrics(t *testing.T) {
+	srv := newJolokiaServer(t)
+	defer srv.Close()
+
+	s, err := runCheck(t, fmt.Sprintf(`
+jolokia_url: %s/jolokia
+user: jmx
+password: secret
+max_returned_metrics: 2
+collect_default_jvm_metrics: false
+conf:
+  - include:
+      domain: java.lang
+`, srv.URL), "")
+	require.NoError(t, err)
+	s.AssertNumberOfCalls(t, "Gauge", 2)
+}
+
+func TestJolokiaCheckDefaultJVMMetrics(t *testing.T) {
+	srv := newJolokiaServer(t)
+	defer srv.Close()
+
+	s, err := runCheck(t, fmt.Sprintf(`
+jolokia_url: %s/jolokia
+name: jvm
+user: jmx
+password: secret
+`, srv.URL), "")
+	require.NoError(t, err)
+
+	memoryTags := []string{"instance:jvm", "jmx_domain:java.lang", "type:Memory"}
+	s.AssertMetric(t, "Gauge", "jvm.heap_memory", 1024, "", memoryTags)
+	s.AssertMetric(t, "Gauge", "jvm.heap_memory_max", 4096, "", memoryTags)
+	gcTags := []string{"instance:jvm", "jmx_domain:java.lang", "name:G1 Young Generation", "type:GarbageCollector"}
+	s.AssertMetric(t, "Rate", "jvm.gc.minor_collection_count", 12, "", gcTags)
+	s.AssertMetric(t, "Rate", "jvm.gc.minor_collection_time", 345, "", gcTags)
+	s.AssertNotCalled(t, "Rate", "jvm.gc.cms.count", mock.Anything, mock.Anything, mock.Anything)
+	s.AssertNumberOfCalls(t, "Gauge", 2)
+	s.AssertNumberOfCalls(t, "Rate", 2)
+}
+
+func TestJolokiaCheckConnectionError(t *testing.T) {
+	srv := newJolokiaServer(t)
+	defer srv.Close()
+
+	s, err := runCheck(t, fmt.Sprintf(`
+jolokia_url: %s/jolokia
+name: broker
+user: jmx
+password: wrong
+conf:
+  - include:
+      domain: java.lang
+`, srv.URL), "")
+	assert.Error(t, err)
+	s.AssertServiceCheck(t, canConnectServiceCheck, servicecheck.ServiceCheckCritical, "", []string{"instance:broker"}, err.Error())
+}
+
+func TestInstanceConfig(t *testing.T) {
+	cfg, err := newInstanceConfig([]byte(`
+host: localhost
+port: 8778
+conf:
+  - include:
+      domain: java.lang
+`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8778/jolokia", cfg.url)
+	assert.Equal(t, defaultMaxReturnedMetrics, cfg.maxReturnedMetrics)
+	assert.Equal(t, defaultTimeout, cfg.timeout)
+	assert.Len(t, cfg.filters, 1+len(defaultJVMFilters))
+
+	cfg, err = newInstanceConfig([]byte(`jolokia_url: http://localhost:8778/jolokia`), []byte(`collect_default_jvm_metrics: false`))
+	assert.Error(t, err)
+	cfg, err = newInstanceConfig([]byte(`{jolokia_url: "http://localhost:8778/jolokia", collect_default_jvm_metrics: true}`), []byte(`collect_default_jvm_metrics: false`))
+	require.NoError(t, err)
+	assert.Len(t, cfg.filters, len(defaultJVMFilters))
+
+	for _, instance := range []string{
+		`conf: [{include: {domain: java.lang}}]`,
+		`{jolokia_url: "ftp://localhost", conf: [{include: {domain: java.lang}}]}`,
+		`{jolokia_url: "http://localhost:8778/jolokia", collect_default_jvm_metrics: false}`,
+		`{jolokia_url: "http://localhost:8778/jolokia", is_jmx: true}`,
+	} {
+		_, err := newInstanceConfig([]byte(instance), nil)
+		assert.Error(t, err, instance)
+	}
+}
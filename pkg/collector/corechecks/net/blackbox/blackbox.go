@@ -0,0 +1,100 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package blackbox implements a check probing HTTP(S), TCP and gRPC endpoints from the agent,
+// with assertions on the responses, as a local alternative to synthetic tests.
+package blackbox
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/option"
+)
+
+const (
+	// CheckName is the name of the check
+	CheckName = "blackbox"
+
+	defaultMinCollectionInterval = 60 * time.Second
+)
+
+// Check runs a single probe per instance
+type Check struct {
+	core.CheckBase
+	cfg    *probeConfig
+	prober *prober
+}
+
+// Configure parses the probe configuration
+func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigDigest uint64, rawInstance integration.Data, rawInitConfig integration.Data, source string) error {
+	cfg, err := newProbeConfig(rawInstance, rawInitConfig)
+	if err != nil {
+		return err
+	}
+
+	c.BuildID(integrationConfigDigest, rawInstance, rawInitConfig)
+	c.cfg = cfg
+
+	return c.CommonConfigure(senderManager, rawInitConfig, rawInstance, source)
+}
+
+// Run runs the probe and reports its outcome
+func (c *Check) Run() error {
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+
+	tags := []string{
+		"probe_name:" + c.cfg.name,
+		"probe_type:" + string(c.cfg.probeType),
+		"target:" + c.cfg.target,
+	}
+
+	result, err := c.prober.run(context.Background(), c.cfg)
+	if err != nil {
+		log.Debugf("blackbox probe %s failed: %s", c.cfg.name, err)
+		sender.Gauge("blackbox.success", 0, "", tags)
+		sender.ServiceCheck("blackbox.up", servicecheck.ServiceCheckCritical, "", tags, err.Error())
+		sender.Commit()
+		return nil
+	}
+
+	sender.Gauge("blackbox.duration", result.latency.Seconds(), "", tags)
+	if result.leafCert != nil {
+		sender.Gauge("blackbox.tls.days_left", result.leafCert.NotAfter.Sub(c.prober.now()).Hours()/24, "", tags)
+	}
+
+	if failures := c.prober.assert(c.cfg, result); len(failures) > 0 {
+		sender.Gauge("blackbox.success", 0, "", tags)
+		sender.ServiceCheck("blackbox.up", servicecheck.ServiceCheckCritical, "", tags, strings.Join(failures, "; "))
+	} else {
+		sender.Gauge("blackbox.success", 1, "", tags)
+		sender.ServiceCheck("blackbox.up", servicecheck.ServiceCheckOK, "", tags, "")
+	}
+
+	sender.Commit()
+	return nil
+}
+
+// Factory creates a new check factory
+func Factory() option.Option[func() check.Check] {
+	return option.New(newCheck)
+}
+
+func newCheck() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBaseWithInterval(CheckName, defaultMinCollectionInterval),
+		prober:    &prober{now: time.Now},
+	}
+}
@@ -0,0 +1,132 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package blackbox
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+func runCheck(t *testing.T, instance string) *mocksender.MockSender {
+	c := newCheck().(*Check)
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	require.NoError(t, c.Configure(senderManager, integration.FakeConfigHash, integration.Data(instance), nil, "test"))
+
+	s := mocksender.NewMockSenderWithSenderManager(c.ID(), senderManager)
+	s.SetupAcceptAll()
+	require.NoError(t, c.Run())
+	return s
+}
+
+func TestHTTPProbe(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		fmt.Fprint(w, `{"status":"healthy"}`)
+	}))
+	defer srv.Close()
+
+	tags := []string{"probe_name:api", "probe_type:http", "target:" + srv.URL}
+	s := runCheck(t, fmt.Sprintf(`
+name: api
+target: %s
+tls_verify: false
+assertions:
+  body_regex: healthy
+  min_tls_validity_days: 1
+`, srv.URL))
+	s.AssertMetric(t, "Gauge", "blackbox.success", 1, "", tags)
+	s.AssertMetricTaggedWith(t, "Gauge", "blackbox.duration", tags)
+	s.AssertMetricTaggedWith(t, "Gauge", "blackbox.tls.days_left", tags)
+	s.AssertServiceCheck(t, "blackbox.up", servicecheck.ServiceCheckOK, "", tags, "")
+
+	tags = []string{"probe_name:api", "probe_type:http", "target:" + srv.URL + "/broken"}
+	s = runCheck(t, fmt.Sprintf(`
+name: api
+target: %s/broken
+tls_verify: false
+assertions:
+  body_regex: unhealthy
+`, srv.URL))
+	s.AssertMetric(t, "Gauge", "blackbox.success", 0, "", tags)
+	s.AssertServiceCheck(t, "blackbox.up", servicecheck.ServiceCheckCritical, "", tags,
+		`status code 503 is an error; body does not match "unhealthy"`)
+}
+
+func TestHTTPProbeCertificateVerification(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer srv.Close()
+
+	// the test server certificate isn't trusted
+	tags := []string{"probe_name:" + srv.URL, "probe_type:http", "target:" + srv.URL}
+	s := runCheck(t, "target: "+srv.URL)
+	s.AssertMetric(t, "Gauge", "blackbox.success", 0, "", tags)
+	s.AssertServiceCheck(t, "blackbox.up", servicecheck.ServiceCheckCritical, "", tags, mock.Anything)
+	s.AssertNotCalled(t, "Gauge", "blackbox.duration", mock.Anything, "", tags)
+}
+
+func TestTCPProbe(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+
+	tags := []string{"probe_name:db", "probe_type:tcp", "target:" + addr}
+	s := runCheck(t, fmt.Sprintf("name: db\ntype: tcp\ntarget: %s", addr))
+	s.AssertMetric(t, "Gauge", "blackbox.success", 1, "", tags)
+	s.AssertServiceCheck(t, "blackbox.up", servicecheck.ServiceCheckOK, "", tags, "")
+
+	l.Close()
+	s = runCheck(t, fmt.Sprintf("name: db\ntype: tcp\ntarget: %s", addr))
+	s.AssertMetric(t, "Gauge", "blackbox.success", 0, "", tags)
+	s.AssertServiceCheck(t, "blackbox.up", servicecheck.ServiceCheckCritical, "", tags, mock.Anything)
+}
+
+func TestGRPCProbe(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("payments", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(srv, healthSrv)
+	go srv.Serve(l) //nolint:errcheck
+	defer srv.Stop()
+	addr := l.Addr().String()
+
+	tags := []string{"probe_name:grpc", "probe_type:grpc", "target:" + addr}
+	s := runCheck(t, fmt.Sprintf("name: grpc\ntype: grpc\ntarget: %s", addr))
+	s.AssertMetric(t, "Gauge", "blackbox.success", 1, "", tags)
+	s.AssertServiceCheck(t, "blackbox.up", servicecheck.ServiceCheckOK, "", tags, "")
+
+	s = runCheck(t, fmt.Sprintf("name: grpc\ntype: grpc\ntarget: %s\ngrpc_service: payments", addr))
+	s.AssertMetric(t, "Gauge", "blackbox.success", 0, "", tags)
+	s.AssertServiceCheck(t, "blackbox.up", servicecheck.ServiceCheckCritical, "", tags, "serving status is NOT_SERVING")
+}
+
+func TestAssertLatency(t *testing.T) {
+	p := &prober{now: time.Now}
+	cfg := &probeConfig{probeType: probeTypeTCP, maxLatency: 100 * time.Millisecond}
+
+	assert.Empty(t, p.assert(cfg, &probeResult{latency: 50 * time.Millisecond}))
+	assert.Equal(t, []string{"latency 250ms is over 100ms"}, p.assert(cfg, &probeResult{latency: 250 * time.Millisecond}))
+}
@@ -0,0 +1,174 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package blackbox
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+)
+
+// probeType is the protocol used by a probe
+type probeType string
+
+const (
+	probeTypeHTTP probeType = "http"
+	probeTypeTCP  probeType = "tcp"
+	probeTypeGRPC probeType = "grpc"
+)
+
+// InitConfig is used to deserialize the check init config
+type InitConfig struct {
+	TimeoutSeconds int `yaml:"timeout"`
+}
+
+// InstanceConfig is used to deserialize the config of a probe
+type InstanceConfig struct {
+	Name           string            `yaml:"name"`
+	Type           string            `yaml:"type"`
+	Target         string            `yaml:"target"`
+	TimeoutSeconds int               `yaml:"timeout"`
+	TLS            bool              `yaml:"tls"`
+	TLSVerify      *bool             `yaml:"tls_verify"`
+	TLSServerName  string            `yaml:"tls_server_name"`
+	Method         string            `yaml:"method"`
+	Headers        map[string]string `yaml:"headers"`
+	Body           string            `yaml:"body"`
+	GRPCService    string            `yaml:"grpc_service"`
+	Assertions     AssertionsConfig  `yaml:"assertions"`
+}
+
+// AssertionsConfig is used to deserialize the assertions of a probe
+type AssertionsConfig struct {
+	StatusCodes        []int  `yaml:"status_codes"`
+	MaxLatencyMs       int    `yaml:"max_latency_ms"`
+	BodyRegex          string `yaml:"body_regex"`
+	MinTLSValidityDays int    `yaml:"min_tls_validity_days"`
+}
+
+// probeConfig is the validated configuration of a probe
+type probeConfig struct {
+	name          string
+	probeType     probeType
+	target        string
+	timeout       time.Duration
+	tls           bool
+	tlsVerify     bool
+	tlsServerName string
+
+	// HTTP specific
+	method  string
+	headers map[string]string
+	body    string
+
+	// gRPC specific
+	grpcService string
+
+	statusCodes    []int
+	maxLatency     time.Duration
+	bodyRegex      *regexp.Regexp
+	minTLSValidity time.Duration
+}
+
+func newProbeConfig(rawInstance integration.Data, rawInitConfig integration.Data) (*probeConfig, error) {
+	instance := InstanceConfig{}
+	initConfig := InitConfig{}
+
+	if err := yaml.Unmarshal(rawInitConfig, &initConfig); err != nil {
+		return nil, fmt.Errorf("invalid init_config: %s", err)
+	}
+	if err := yaml.Unmarshal(rawInstance, &instance); err != nil {
+		return nil, fmt.Errorf("invalid instance config: %s", err)
+	}
+
+	if instance.Target == "" {
+		return nil, errors.New("the target of the probe is required")
+	}
+
+	c := &probeConfig{
+		name:           instance.Name,
+		probeType:      probeType(strings.ToLower(instance.Type)),
+		target:         instance.Target,
+		tls:            instance.TLS,
+		tlsVerify:      instance.TLSVerify == nil || *instance.TLSVerify,
+		tlsServerName:  instance.TLSServerName,
+		method:         strings.ToUpper(instance.Method),
+		headers:        instance.Headers,
+		body:           instance.Body,
+		grpcService:    instance.GRPCService,
+		statusCodes:    instance.Assertions.StatusCodes,
+		maxLatency:     time.Duration(instance.Assertions.MaxLatencyMs) * time.Millisecond,
+		minTLSValidity: time.Duration(instance.Assertions.MinTLSValidityDays) * 24 * time.Hour,
+	}
+	if c.name == "" {
+		c.name = c.target
+	}
+
+	switch {
+	case instance.TimeoutSeconds > 0:
+		c.timeout = time.Duration(instance.TimeoutSeconds) * time.Second
+	case initConfig.TimeoutSeconds > 0:
+		c.timeout = time.Duration(initConfig.TimeoutSeconds) * time.Second
+	default:
+		c.timeout = defaultTimeout
+	}
+
+	switch c.probeType {
+	case "":
+		c.probeType = probeTypeHTTP
+		fallthrough
+	case probeTypeHTTP:
+		u, err := url.Parse(c.target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target URL: %s", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("unsupported target URL scheme %q, expected http or https", u.Scheme)
+		}
+		c.tls = u.Scheme == "https"
+		if c.method == "" {
+			c.method = http.MethodGet
+		}
+	case probeTypeTCP, probeTypeGRPC:
+		if _, _, err := net.SplitHostPort(c.target); err != nil {
+			return nil, fmt.Errorf("invalid target address, expected host:port: %s", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported probe type %q, expected one of http, tcp or grpc", instance.Type)
+	}
+
+	if instance.Assertions.BodyRegex != "" {
+		if c.probeType != probeTypeHTTP {
+			return nil, errors.New("the body_regex assertion is only supported by http probes")
+		}
+		re, err := regexp.Compile(instance.Assertions.BodyRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid body_regex: %s", err)
+		}
+		c.bodyRegex = re
+	}
+	if len(c.statusCodes) > 0 && c.probeType != probeTypeHTTP {
+		return nil, errors.New("the status_codes assertion is only supported by http probes")
+	}
+	if c.minTLSValidity > 0 && !c.tls {
+		return nil, errors.New("the min_tls_validity_days assertion requires a TLS connection")
+	}
+
+	return c, nil
+}
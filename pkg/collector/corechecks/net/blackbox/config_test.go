@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package blackbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+)
+
+func TestNewProbeConfig(t *testing.T) {
+	cfg, err := newProbeConfig(integration.Data(`
+name: api
+target: https://api.example.com/health
+headers:
+  Authorization: Bearer token
+assertions:
+  status_codes: [200, 204]
+  max_latency_ms: 500
+  body_regex: "ok|healthy"
+  min_tls_validity_days: 14
+`), integration.Data(`timeout: 3`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "api", cfg.name)
+	assert.Equal(t, probeTypeHTTP, cfg.probeType)
+	assert.Equal(t, "GET", cfg.method)
+	assert.True(t, cfg.tls)
+	assert.True(t, cfg.tlsVerify)
+	assert.Equal(t, 3*time.Second, cfg.timeout)
+	assert.Equal(t, []int{200, 204}, cfg.statusCodes)
+	assert.Equal(t, 500*time.Millisecond, cfg.maxLatency)
+	assert.Equal(t, 14*24*time.Hour, cfg.minTLSValidity)
+	assert.True(t, cfg.bodyRegex.MatchString("healthy"))
+}
+
+func TestNewProbeConfigDefaults(t *testing.T) {
+	cfg, err := newProbeConfig(integration.Data(`
+type: TCP
+target: db.example.com:5432
+tls_verify: false
+`), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "db.example.com:5432", cfg.name)
+	assert.Equal(t, probeTypeTCP, cfg.probeType)
+	assert.Equal(t, defaultTimeout, cfg.timeout)
+	assert.False(t, cfg.tls)
+	assert.False(t, cfg.tlsVerify)
+}
+
+func TestNewProbeConfigErrors(t *testing.T) {
+	for name, instance := range map[string]string{
+		"missing target":           `type: http`,
+		"unknown type":             "type: icmp\ntarget: example.com",
+		"bad scheme":               `target: ftp://example.com`,
+		"missing port":             "type: tcp\ntarget: example.com",
+		"bad regex":                "target: http://example.com\nassertions:\n  body_regex: '('",
+		"body regex on tcp":        "type: tcp\ntarget: example.com:80\nassertions:\n  body_regex: ok",
+		"status codes on grpc":     "type: grpc\ntarget: example.com:80\nassertions:\n  status_codes: [200]",
+		"tls validity without tls": "type: tcp\ntarget: example.com:80\nassertions:\n  min_tls_validity_days: 3",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := newProbeConfig(integration.Data(instance), nil)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,214 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package blackbox
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+)
+
+// maxBodySize bounds the size of the HTTP response body matched against body_regex
+const maxBodySize = 1 << 20
+
+// probeResult is the outcome of a successful probe, assertions aside
+type probeResult struct {
+	latency time.Duration
+	// leafCert is the certificate presented by the target, nil without TLS
+	leafCert *x509.Certificate
+
+	// HTTP specific
+	statusCode int
+	body       []byte
+
+	// gRPC specific
+	servingStatus healthpb.HealthCheckResponse_ServingStatus
+}
+
+// prober runs the probes, its fields are overridden in tests
+type prober struct {
+	now func() time.Time
+}
+
+func (p *prober) run(ctx context.Context, cfg *probeConfig) (*probeResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.timeout)
+	defer cancel()
+
+	switch cfg.probeType {
+	case probeTypeHTTP:
+		return p.probeHTTP(ctx, cfg)
+	case probeTypeTCP:
+		return p.probeTCP(ctx, cfg)
+	case probeTypeGRPC:
+		return p.probeGRPC(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("unsupported probe type %q", cfg.probeType)
+	}
+}
+
+func (p *prober) tlsConfig(cfg *probeConfig) *tls.Config {
+	return &tls.Config{
+		ServerName:         cfg.tlsServerName,
+		InsecureSkipVerify: !cfg.tlsVerify, //nolint:gosec // explicitly disabled by the user
+	}
+}
+
+func (p *prober) probeHTTP(ctx context.Context, cfg *probeConfig) (*probeResult, error) {
+	var body io.Reader
+	if cfg.body != "" {
+		body = strings.NewReader(cfg.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, cfg.method, cfg.target, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range cfg.headers {
+		if strings.EqualFold(k, "host") {
+			req.Host = v
+			continue
+		}
+		req.Header.Set(k, v)
+	}
+
+	// probes are run against the target directly, without proxy nor connection reuse,
+	// so that every run measures a full connection
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig:   p.tlsConfig(cfg),
+			DisableKeepAlives: true,
+		},
+	}
+
+	start := p.now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := &probeResult{statusCode: resp.StatusCode}
+	if cfg.bodyRegex != nil {
+		result.body, err = io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	} else {
+		_, err = io.Copy(io.Discard, resp.Body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading the response body: %w", err)
+	}
+	result.latency = p.now().Sub(start)
+
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		result.leafCert = resp.TLS.PeerCertificates[0]
+	}
+	return result, nil
+}
+
+func (p *prober) probeTCP(ctx context.Context, cfg *probeConfig) (*probeResult, error) {
+	var dialer net.Dialer
+
+	start := p.now()
+	conn, err := dialer.DialContext(ctx, "tcp", cfg.target)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	result := &probeResult{}
+	if cfg.tls {
+		tlsCfg := p.tlsConfig(cfg)
+		if tlsCfg.ServerName == "" {
+			tlsCfg.ServerName, _, _ = net.SplitHostPort(cfg.target)
+		}
+		tlsConn := tls.Client(conn, tlsCfg)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return nil, fmt.Errorf("TLS handshake failed: %w", err)
+		}
+		if certs := tlsConn.ConnectionState().PeerCertificates; len(certs) > 0 {
+			result.leafCert = certs[0]
+		}
+	}
+	result.latency = p.now().Sub(start)
+
+	return result, nil
+}
+
+func (p *prober) probeGRPC(ctx context.Context, cfg *probeConfig) (*probeResult, error) {
+	creds := insecure.NewCredentials()
+	if cfg.tls {
+		creds = credentials.NewTLS(p.tlsConfig(cfg))
+	}
+	conn, err := grpc.NewClient(cfg.target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var pr peer.Peer
+	start := p.now()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: cfg.grpcService}, grpc.Peer(&pr))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &probeResult{
+		latency:       p.now().Sub(start),
+		servingStatus: resp.GetStatus(),
+	}
+	if tlsInfo, ok := pr.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+		result.leafCert = tlsInfo.State.PeerCertificates[0]
+	}
+	return result, nil
+}
+
+// assert returns the failed assertions of the probe
+func (p *prober) assert(cfg *probeConfig, result *probeResult) []string {
+	var failures []string
+
+	if cfg.probeType == probeTypeHTTP {
+		if len(cfg.statusCodes) > 0 {
+			if !slices.Contains(cfg.statusCodes, result.statusCode) {
+				failures = append(failures, fmt.Sprintf("status code %d is not one of %v", result.statusCode, cfg.statusCodes))
+			}
+		} else if result.statusCode >= 400 {
+			failures = append(failures, fmt.Sprintf("status code %d is an error", result.statusCode))
+		}
+		if cfg.bodyRegex != nil && !cfg.bodyRegex.Match(result.body) {
+			failures = append(failures, fmt.Sprintf("body does not match %q", cfg.bodyRegex.String()))
+		}
+	}
+
+	if cfg.probeType == probeTypeGRPC && result.servingStatus != healthpb.HealthCheckResponse_SERVING {
+		failures = append(failures, fmt.Sprintf("serving status is %s", result.servingStatus))
+	}
+
+	if cfg.maxLatency > 0 && result.latency > cfg.maxLatency {
+		failures = append(failures, fmt.Sprintf("latency %s is over %s", result.latency.Round(time.Millisecond), cfg.maxLatency))
+	}
+
+	if cfg.minTLSValidity > 0 {
+		if result.leafCert == nil {
+			failures = append(failures, "no certificate was presented")
+		} else if validity := result.leafCert.NotAfter.Sub(p.now()); validity < cfg.minTLSValidity {
+			failures = append(failures, fmt.Sprintf("certificate expires in %.1f days, less than %d days",
+				validity.Hours()/24, int(cfg.minTLSValidity.Hours()/24)))
+		}
+	}
+
+	return failures
+}
@@ -0,0 +1,110 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package tlscert
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+)
+
+const (
+	defaultTimeout      = 5 * time.Second
+	defaultDaysWarning  = 14
+	defaultDaysCritical = 7
+)
+
+// InstanceConfig is used to deserialize the config of an instance
+type InstanceConfig struct {
+	Paths             []string                `yaml:"paths"`
+	Endpoints         []string                `yaml:"endpoints"`
+	ListeningPorts    ListeningPortsConfig    `yaml:"listening_ports"`
+	KubernetesSecrets KubernetesSecretsConfig `yaml:"kubernetes_secrets"`
+	IncludeChain      bool                    `yaml:"include_chain"`
+	DaysWarning       int                     `yaml:"days_warning"`
+	DaysCritical      int                     `yaml:"days_critical"`
+	TimeoutSeconds    int                     `yaml:"timeout"`
+}
+
+// ListeningPortsConfig is used to deserialize the discovery of the certificates served on the local listening ports
+type ListeningPortsConfig struct {
+	Enabled      bool  `yaml:"enabled"`
+	ExcludePorts []int `yaml:"exclude_ports"`
+}
+
+// KubernetesSecretsConfig is used to deserialize the discovery of the certificates stored in Kubernetes secrets
+type KubernetesSecretsConfig struct {
+	Enabled    bool     `yaml:"enabled"`
+	Namespaces []string `yaml:"namespaces"`
+}
+
+// checkConfig is the validated configuration of an instance
+type checkConfig struct {
+	paths             []string
+	endpoints         []string
+	listeningPorts    bool
+	excludedPorts     map[uint32]struct{}
+	kubernetesSecrets bool
+	namespaces        []string
+	includeChain      bool
+	warningThreshold  time.Duration
+	criticalThreshold time.Duration
+	timeout           time.Duration
+}
+
+func newCheckConfig(rawInstance integration.Data) (*checkConfig, error) {
+	instance := InstanceConfig{
+		DaysWarning:  defaultDaysWarning,
+		DaysCritical: defaultDaysCritical,
+	}
+	if err := yaml.Unmarshal(rawInstance, &instance); err != nil {
+		return nil, fmt.Errorf("invalid instance config: %s", err)
+	}
+
+	if len(instance.Paths) == 0 && len(instance.Endpoints) == 0 && !instance.ListeningPorts.Enabled && !instance.KubernetesSecrets.Enabled {
+		return nil, errors.New("at least one of paths, endpoints, listening_ports or kubernetes_secrets must be configured")
+	}
+	for _, pattern := range instance.Paths {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid path pattern %q: %s", pattern, err)
+		}
+	}
+	for _, endpoint := range instance.Endpoints {
+		if _, _, err := net.SplitHostPort(endpoint); err != nil {
+			return nil, fmt.Errorf("invalid endpoint %q, expected host:port: %s", endpoint, err)
+		}
+	}
+	if instance.DaysCritical < 0 || instance.DaysWarning < instance.DaysCritical {
+		return nil, fmt.Errorf("days_critical (%d) must be positive and lower than days_warning (%d)", instance.DaysCritical, instance.DaysWarning)
+	}
+
+	c := &checkConfig{
+		paths:             instance.Paths,
+		endpoints:         instance.Endpoints,
+		listeningPorts:    instance.ListeningPorts.Enabled,
+		excludedPorts:     make(map[uint32]struct{}, len(instance.ListeningPorts.ExcludePorts)),
+		kubernetesSecrets: instance.KubernetesSecrets.Enabled,
+		namespaces:        instance.KubernetesSecrets.Namespaces,
+		includeChain:      instance.IncludeChain,
+		warningThreshold:  time.Duration(instance.DaysWarning) * 24 * time.Hour,
+		criticalThreshold: time.Duration(instance.DaysCritical) * 24 * time.Hour,
+		timeout:           defaultTimeout,
+	}
+	for _, port := range instance.ListeningPorts.ExcludePorts {
+		c.excludedPorts[uint32(port)] = struct{}{}
+	}
+	if instance.TimeoutSeconds > 0 {
+		c.timeout = time.Duration(instance.TimeoutSeconds) * time.Second
+	}
+
+	return c, nil
+}
@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package tlscert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCheckConfig(t *testing.T) {
+	cfg, err := newCheckConfig([]byte(`
+paths:
+  - /etc/ssl/private/*.pem
+endpoints:
+  - localhost:443
+listening_ports:
+  enabled: true
+  exclude_ports: [22]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/etc/ssl/private/*.pem"}, cfg.paths)
+	assert.Equal(t, []string{"localhost:443"}, cfg.endpoints)
+	assert.True(t, cfg.listeningPorts)
+	assert.Contains(t, cfg.excludedPorts, uint32(22))
+	assert.False(t, cfg.kubernetesSecrets)
+	assert.Equal(t, defaultDaysWarning*24*time.Hour, cfg.warningThreshold)
+	assert.Equal(t, defaultDaysCritical*24*time.Hour, cfg.criticalThreshold)
+	assert.Equal(t, defaultTimeout, cfg.timeout)
+}
+
+func TestNewCheckConfigErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		instance string
+		err      string
+	}{
+		"no source": {
+			instance: `include_chain: true`,
+			err:      "at least one of paths",
+		},
+		"invalid pattern": {
+			instance: `paths: ["/etc/[ssl"]`,
+			err:      "invalid path pattern",
+		},
+		"invalid endpoint": {
+			instance: `endpoints: ["localhost"]`,
+			err:      "invalid endpoint",
+		},
+		"inverted thresholds": {
+			instance: "kubernetes_secrets:\n  enabled: true\ndays_warning: 3\ndays_critical: 7",
+			err:      "days_critical (7) must be positive and lower than days_warning (3)",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := newCheckConfig([]byte(tc.instance))
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
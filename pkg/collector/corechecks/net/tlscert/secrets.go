@@ -0,0 +1,167 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package tlscert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// secretsPageSize is the number of secrets listed per request
+const secretsPageSize = 500
+
+var secretsResource = v1.SchemeGroupVersion.WithResource("secrets")
+
+// cachedSecret is the certificate read from a version of a secret
+type cachedSecret struct {
+	resourceVersion string
+	bundle          pemBundle
+	found           bool
+}
+
+// secretsLister lists the certificates of the secrets of type kubernetes.io/tls.
+//
+// The API can't return the certificate of a secret without its private key, so
+// the secrets are listed by their metadata, and only the ones created or updated
+// since the previous run are read. Their certificates are kept, their private
+// keys are dropped.
+type secretsLister struct {
+	client         kubernetes.Interface
+	metadataClient metadata.Interface
+	// secrets are the certificates of the listed secrets, by UID
+	secrets map[types.UID]cachedSecret
+}
+
+func newSecretsLister() *secretsLister {
+	return &secretsLister{secrets: make(map[types.UID]cachedSecret)}
+}
+
+// newTLSSecretsLister returns the function listing the certificates of the TLS
+// secrets of the given namespaces, or of all namespaces if none is given
+func newTLSSecretsLister() func(ctx context.Context, namespaces []string) ([]pemBundle, error) {
+	return newSecretsLister().listOnLeader
+}
+
+// listOnLeader lists the certificates of the secrets if the agent is the one
+// collecting them. The secrets being cluster wide, they are listed by the cluster
+// check runner the check is dispatched to, or by the leader agent.
+func (l *secretsLister) listOnLeader(ctx context.Context, namespaces []string) ([]pemBundle, error) {
+	if !pkgconfigsetup.IsCLCRunner(pkgconfigsetup.Datadog()) {
+		if !pkgconfigsetup.Datadog().GetBool("leader_election") {
+			return nil, errors.New("leader election must be enabled to list the secrets outside of a cluster check")
+		}
+		leader, err := cluster.RunLeaderElection()
+		if errors.Is(err, apiserver.ErrNotLeader) {
+			log.Debugf("Not leader (leader is %q), skipping the kubernetes secrets", leader)
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("leader election error: %w", err)
+		}
+	}
+
+	if l.client == nil {
+		client, err := apiserver.GetAPIClient()
+		if err != nil {
+			return nil, err
+		}
+		metadataClient, err := client.MetadataClient()
+		if err != nil {
+			return nil, err
+		}
+		l.client, l.metadataClient = client.Cl, metadataClient
+	}
+	return l.list(ctx, namespaces)
+}
+
+func (l *secretsLister) list(ctx context.Context, namespaces []string) ([]pemBundle, error) {
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	var bundles []pemBundle
+	var errs []error
+	listed := make(map[types.UID]struct{}, len(l.secrets))
+	for _, namespace := range namespaces {
+		opts := metav1.ListOptions{
+			FieldSelector: "type=" + string(v1.SecretTypeTLS),
+			Limit:         secretsPageSize,
+		}
+		for {
+			page, err := l.metadataClient.Resource(secretsResource).Namespace(namespace).List(ctx, opts)
+			if err != nil {
+				return bundles, errors.Join(append(errs, err)...)
+			}
+			for i := range page.Items {
+				meta := &page.Items[i]
+				listed[meta.UID] = struct{}{}
+				bundle, found, err := l.certificate(ctx, meta)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				if found {
+					bundles = append(bundles, bundle)
+				}
+			}
+			if page.Continue == "" {
+				break
+			}
+			opts.Continue = page.Continue
+		}
+	}
+
+	for uid := range l.secrets {
+		if _, found := listed[uid]; !found {
+			delete(l.secrets, uid)
+		}
+	}
+	return bundles, errors.Join(errs...)
+}
+
+// certificate returns the certificate of the secret, read if the secret changed
+// since the previous run. It returns false if the secret holds no certificate.
+func (l *secretsLister) certificate(ctx context.Context, meta *metav1.PartialObjectMetadata) (pemBundle, bool, error) {
+	if cached, found := l.secrets[meta.UID]; found && cached.resourceVersion == meta.ResourceVersion {
+		return cached.bundle, cached.found, nil
+	}
+
+	secret, err := l.client.CoreV1().Secrets(meta.Namespace).Get(ctx, meta.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return pemBundle{}, false, nil
+	}
+	if err != nil {
+		return pemBundle{}, false, err
+	}
+
+	cached := cachedSecret{resourceVersion: secret.ResourceVersion}
+	if data, found := secret.Data[v1.TLSCertKey]; found {
+		cached.found = true
+		cached.bundle = pemBundle{
+			source:   sourceKubernetesSecret,
+			location: secret.Namespace + "/" + secret.Name,
+			tags:     []string{"kube_namespace:" + secret.Namespace, "secret_name:" + secret.Name},
+			data:     data,
+		}
+	}
+	l.secrets[secret.UID] = cached
+	return cached.bundle, cached.found, nil
+}
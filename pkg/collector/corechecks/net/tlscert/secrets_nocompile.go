@@ -0,0 +1,19 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !kubeapiserver
+
+package tlscert
+
+import (
+	"context"
+	"errors"
+)
+
+func newTLSSecretsLister() func(context.Context, []string) ([]pemBundle, error) {
+	return func(context.Context, []string) ([]pemBundle, error) {
+		return nil, errors.New("kubernetes secrets are not supported by this build of the agent")
+	}
+}
@@ -0,0 +1,72 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package tlscert
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	metadatafake "k8s.io/client-go/metadata/fake"
+)
+
+func secretMetadata(secret *v1.Secret) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: secret.ObjectMeta,
+	}
+}
+
+func TestListTLSSecrets(t *testing.T) {
+	cert, key := newTestCertificate(t, "ingress.example.com", time.Now().Add(time.Hour))
+	secrets := []*v1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "ingress-tls", UID: "ingress-uid", ResourceVersion: "1"},
+			Type:       v1.SecretTypeTLS,
+			Data:       map[string][]byte{v1.TLSCertKey: cert, v1.TLSPrivateKeyKey: key},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "db", Name: "db-tls", UID: "db-uid", ResourceVersion: "1"},
+			Type:       v1.SecretTypeTLS,
+			Data:       map[string][]byte{v1.TLSCertKey: cert, v1.TLSPrivateKeyKey: key},
+		},
+	}
+	client := fake.NewSimpleClientset(secrets[0], secrets[1])
+	scheme := metadatafake.NewTestScheme()
+	require.NoError(t, metav1.AddMetaToScheme(scheme))
+	metadataClient := metadatafake.NewSimpleMetadataClient(scheme, secretMetadata(secrets[0]), secretMetadata(secrets[1]))
+
+	lister := newSecretsLister()
+	lister.client, lister.metadataClient = client, metadataClient
+
+	bundles, err := lister.list(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Len(t, bundles, 2)
+
+	// the secrets which didn't change aren't read again
+	client.ClearActions()
+	bundles, err = lister.list(context.Background(), []string{"web"})
+	require.NoError(t, err)
+	require.Len(t, bundles, 1)
+	assert.Empty(t, client.Actions())
+	assert.Equal(t, sourceKubernetesSecret, bundles[0].source)
+	assert.Equal(t, "web/ingress-tls", bundles[0].location)
+	assert.Equal(t, []string{"kube_namespace:web", "secret_name:ingress-tls"}, bundles[0].tags)
+	assert.NotContains(t, lister.secrets, types.UID("db-uid"), "the secrets which weren't listed are dropped")
+
+	certs, err := parsePEMBundle(bundles[0], false)
+	require.NoError(t, err)
+	require.Len(t, certs, 1)
+	assert.Equal(t, "ingress.example.com", certs[0].cert.Subject.CommonName)
+}
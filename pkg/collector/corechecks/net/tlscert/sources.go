@@ -0,0 +1,294 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package tlscert
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	gopsutilnet "github.com/shirou/gopsutil/v4/net"
+)
+
+// maxConcurrentHandshakes bounds the number of TLS handshakes run in parallel
+const maxConcurrentHandshakes = 8
+
+// certificateSource is the kind of location certificates are found at
+type certificateSource string
+
+const (
+	sourceFile             certificateSource = "file"
+	sourceEndpoint         certificateSource = "endpoint"
+	sourceListeningPort    certificateSource = "listening_port"
+	sourceKubernetesSecret certificateSource = "kubernetes_secret"
+)
+
+// foundCertificate is a certificate found by the check, along with its location
+type foundCertificate struct {
+	source certificateSource
+	// location is the path, the address or the namespace/name of the secret holding the certificate
+	location string
+	tags     []string
+	cert     *x509.Certificate
+}
+
+// pemBundle is a list of PEM encoded certificates read from a location
+type pemBundle struct {
+	source   certificateSource
+	location string
+	tags     []string
+	data     []byte
+}
+
+// finder discovers the certificates, its fields are overridden in tests
+type finder struct {
+	listeningAddresses func(ctx context.Context) ([]string, error)
+	listSecrets        func(ctx context.Context, namespaces []string) ([]pemBundle, error)
+}
+
+// find returns the certificates found at the configured locations, along with the errors
+// met while looking for them
+func (f *finder) find(ctx context.Context, cfg *checkConfig) ([]foundCertificate, []error) {
+	var found []foundCertificate
+	var errs []error
+
+	for _, pattern := range cfg.paths {
+		certs, err := findFiles(pattern, cfg.includeChain)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		found = append(found, certs...)
+	}
+
+	targets := make([]endpointTarget, 0, len(cfg.endpoints))
+	for _, endpoint := range cfg.endpoints {
+		targets = append(targets, endpointTarget{address: endpoint, source: sourceEndpoint})
+	}
+	if cfg.listeningPorts {
+		listening, err := f.listeningAddresses(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not list the listening ports: %w", err))
+		}
+		for _, address := range listening {
+			_, port, _ := net.SplitHostPort(address)
+			p, _ := strconv.ParseUint(port, 10, 32)
+			if _, excluded := cfg.excludedPorts[uint32(p)]; excluded {
+				continue
+			}
+			targets = append(targets, endpointTarget{address: address, source: sourceListeningPort})
+		}
+	}
+	certs, handshakeErrs := fetchEndpoints(ctx, targets, cfg)
+	found = append(found, certs...)
+	errs = append(errs, handshakeErrs...)
+
+	if cfg.kubernetesSecrets {
+		bundles, err := f.listSecrets(ctx, cfg.namespaces)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not list the kubernetes secrets: %w", err))
+		}
+		for _, bundle := range bundles {
+			certs, err := parsePEMBundle(bundle, cfg.includeChain)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			found = append(found, certs...)
+		}
+	}
+
+	return found, errs
+}
+
+// findFiles reads the certificates of the files matching the pattern
+func findFiles(pattern string, includeChain bool) ([]foundCertificate, error) {
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path pattern %q: %w", pattern, err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no file matches %q", pattern)
+	}
+
+	var found []foundCertificate
+	var errs []error
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not read %s: %w", path, err))
+			continue
+		}
+		certs, err := parsePEMBundle(pemBundle{
+			source:   sourceFile,
+			location: path,
+			tags:     []string{"cert_path:" + path},
+			data:     data,
+		}, includeChain)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		found = append(found, certs...)
+	}
+	return found, errors.Join(errs...)
+}
+
+// parsePEMBundle parses the certificates of a bundle. Other PEM blocks, like private keys,
+// are ignored, so that patterns can match whole directories.
+func parsePEMBundle(bundle pemBundle, includeChain bool) ([]foundCertificate, error) {
+	var found []foundCertificate
+
+	rest := bundle.data
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return found, fmt.Errorf("invalid certificate in %s: %w", bundle.location, err)
+		}
+		found = append(found, foundCertificate{
+			source:   bundle.source,
+			location: bundle.location,
+			tags:     bundle.tags,
+			cert:     cert,
+		})
+		if !includeChain {
+			break
+		}
+	}
+	return found, nil
+}
+
+// endpointTarget is an address the certificates are fetched from
+type endpointTarget struct {
+	address string
+	source  certificateSource
+}
+
+// fetchEndpoints runs a TLS handshake with each target to get the certificates it serves
+func fetchEndpoints(ctx context.Context, targets []endpointTarget, cfg *checkConfig) ([]foundCertificate, []error) {
+	var (
+		mu    sync.Mutex
+		found []foundCertificate
+		errs  []error
+		wg    sync.WaitGroup
+	)
+	sem := make(chan struct{}, maxConcurrentHandshakes)
+
+	for _, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(target endpointTarget) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			certs, err := fetchEndpoint(ctx, target.address, cfg.timeout)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				// listening ports are not expected to all serve TLS
+				if target.source != sourceListeningPort {
+					errs = append(errs, err)
+				}
+				return
+			}
+			if !cfg.includeChain {
+				certs = certs[:1]
+			}
+			for _, cert := range certs {
+				found = append(found, foundCertificate{
+					source:   target.source,
+					location: target.address,
+					tags:     []string{"endpoint:" + target.address},
+					cert:     cert,
+				})
+			}
+		}(target)
+	}
+	wg.Wait()
+
+	// keep a stable order to ease troubleshooting
+	sort.SliceStable(found, func(i, j int) bool { return found[i].location < found[j].location })
+	return found, errs
+}
+
+func fetchEndpoint(ctx context.Context, address string, timeout time.Duration) ([]*x509.Certificate, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	host, _, _ := net.SplitHostPort(address)
+	dialer := tls.Dialer{
+		Config: &tls.Config{
+			// the certificates are inventoried, not verified
+			InsecureSkipVerify: true, //nolint:gosec
+		},
+	}
+	if net.ParseIP(host) == nil {
+		dialer.Config.ServerName = host
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("TLS handshake with %s failed: %w", address, err)
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("%s presented no certificate", address)
+	}
+	return certs, nil
+}
+
+// localListeningAddresses returns the loopback addresses of the TCP ports the host listens on
+func localListeningAddresses(ctx context.Context) ([]string, error) {
+	conns, err := gopsutilnet.ConnectionsWithContext(ctx, "tcp")
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{})
+	var addresses []string
+	for _, conn := range conns {
+		if conn.Status != "LISTEN" {
+			continue
+		}
+		ip := net.ParseIP(conn.Laddr.IP)
+		switch {
+		case ip == nil:
+			continue
+		case ip.IsUnspecified() && ip.To4() != nil:
+			ip = net.IPv4(127, 0, 0, 1)
+		case ip.IsUnspecified():
+			ip = net.IPv6loopback
+		}
+		address := net.JoinHostPort(ip.String(), strconv.FormatUint(uint64(conn.Laddr.Port), 10))
+		if _, found := seen[address]; found {
+			continue
+		}
+		seen[address] = struct{}{}
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	return addresses, nil
+}
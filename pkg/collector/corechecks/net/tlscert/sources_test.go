@@ -0,0 +1,152 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package tlscert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCertificate returns a PEM encoded self-signed certificate and its private key
+func newTestCertificate(t *testing.T, commonName string, notAfter time.Time) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: commonName},
+		Issuer:       pkix.Name{CommonName: commonName},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+		DNSNames:     []string{commonName},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// newTestTLSListener serves the certificate on a local port and returns its address
+func newTestTLSListener(t *testing.T, certPEM, keyPEM []byte) string {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestParsePEMBundle(t *testing.T) {
+	first, key := newTestCertificate(t, "first.example.com", time.Now().Add(time.Hour))
+	second, _ := newTestCertificate(t, "second.example.com", time.Now().Add(time.Hour))
+	bundle := pemBundle{
+		source:   sourceFile,
+		location: "bundle.pem",
+		data:     append(append(append([]byte{}, key...), first...), second...),
+	}
+
+	certs, err := parsePEMBundle(bundle, false)
+	require.NoError(t, err)
+	require.Len(t, certs, 1)
+	assert.Equal(t, "first.example.com", certs[0].cert.Subject.CommonName)
+
+	certs, err = parsePEMBundle(bundle, true)
+	require.NoError(t, err)
+	require.Len(t, certs, 2)
+	assert.Equal(t, "second.example.com", certs[1].cert.Subject.CommonName)
+
+	bundle.data = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")})
+	_, err = parsePEMBundle(bundle, false)
+	assert.ErrorContains(t, err, "invalid certificate in bundle.pem")
+}
+
+func TestFindFiles(t *testing.T) {
+	dir := t.TempDir()
+	cert, key := newTestCertificate(t, "file.example.com", time.Now().Add(time.Hour))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tls.crt"), cert, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tls.key"), key, 0o600))
+
+	certs, err := findFiles(filepath.Join(dir, "*"), false)
+	require.NoError(t, err)
+	require.Len(t, certs, 1)
+	assert.Equal(t, sourceFile, certs[0].source)
+	assert.Equal(t, []string{"cert_path:" + filepath.Join(dir, "tls.crt")}, certs[0].tags)
+
+	_, err = findFiles(filepath.Join(dir, "missing.crt"), false)
+	assert.ErrorContains(t, err, "no file matches")
+}
+
+func TestFindEndpoints(t *testing.T) {
+	cert, key := newTestCertificate(t, "endpoint.example.com", time.Now().Add(time.Hour))
+	endpoint := newTestTLSListener(t, cert, key)
+	listening := newTestTLSListener(t, cert, key)
+
+	// a listening port which doesn't serve TLS
+	plain, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer plain.Close()
+
+	f := &finder{
+		listeningAddresses: func(context.Context) ([]string, error) {
+			return []string{listening, plain.Addr().String()}, nil
+		},
+	}
+	cfg := &checkConfig{
+		endpoints:      []string{endpoint},
+		listeningPorts: true,
+		excludedPorts:  map[uint32]struct{}{},
+		timeout:        time.Second,
+	}
+
+	certs, errs := f.find(context.Background(), cfg)
+	assert.Empty(t, errs)
+	require.Len(t, certs, 2)
+	sources := map[certificateSource]string{}
+	for _, c := range certs {
+		sources[c.source] = c.location
+		assert.Equal(t, "endpoint.example.com", c.cert.Subject.CommonName)
+	}
+	assert.Equal(t, map[certificateSource]string{sourceEndpoint: endpoint, sourceListeningPort: listening}, sources)
+
+	// unreachable configured endpoints are reported
+	cfg.endpoints = []string{plain.Addr().String()}
+	cfg.listeningPorts = false
+	certs, errs = f.find(context.Background(), cfg)
+	assert.Empty(t, certs)
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "TLS handshake with "+plain.Addr().String()+" failed")
+}
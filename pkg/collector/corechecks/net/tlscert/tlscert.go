@@ -0,0 +1,158 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package tlscert implements a check inventorying the TLS certificates stored in files and
+// Kubernetes secrets or served on network endpoints, and reporting how long they remain valid.
+package tlscert
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/option"
+)
+
+const (
+	// CheckName is the name of the check
+	CheckName = "tls_certificates"
+
+	defaultMinCollectionInterval = 10 * time.Minute
+
+	// inventoryKey is the key of the certificates in the check metadata
+	inventoryKey = "certificates"
+)
+
+// certificateMetadata describes a certificate in the inventory of the check
+type certificateMetadata struct {
+	Source       string    `json:"source"`
+	Location     string    `json:"location"`
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SerialNumber string    `json:"serial_number"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+	DNSNames     []string  `json:"dns_names,omitempty"`
+	IPAddresses  []string  `json:"ip_addresses,omitempty"`
+	Fingerprint  string    `json:"sha256_fingerprint"`
+}
+
+// Check reports the expiry of the certificates found at the configured locations
+type Check struct {
+	core.CheckBase
+	cfg    *checkConfig
+	finder *finder
+	now    func() time.Time
+}
+
+// Configure parses the check configuration
+func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigDigest uint64, rawInstance integration.Data, rawInitConfig integration.Data, source string) error {
+	cfg, err := newCheckConfig(rawInstance)
+	if err != nil {
+		return err
+	}
+
+	c.BuildID(integrationConfigDigest, rawInstance, rawInitConfig)
+	c.cfg = cfg
+
+	return c.CommonConfigure(senderManager, rawInitConfig, rawInstance, source)
+}
+
+// Run looks for the certificates and reports their expiry
+func (c *Check) Run() error {
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+
+	found, errs := c.finder.find(context.Background(), c.cfg)
+	for _, err := range errs {
+		log.Debugf("tls_certificates check %s: %s", c.ID(), err)
+	}
+
+	now := c.now()
+	inventory := make([]certificateMetadata, 0, len(found))
+	for _, f := range found {
+		tags := append([]string{"cert_source:" + string(f.source)}, f.tags...)
+		if cn := f.cert.Subject.CommonName; cn != "" {
+			tags = append(tags, "subject_cn:"+cn)
+		}
+		if cn := f.cert.Issuer.CommonName; cn != "" {
+			tags = append(tags, "issuer_cn:"+cn)
+		}
+
+		validity := f.cert.NotAfter.Sub(now)
+		sender.Gauge("tls_certificates.days_left", validity.Hours()/24, "", tags)
+
+		status, message := servicecheck.ServiceCheckOK, ""
+		switch {
+		case validity <= 0:
+			status, message = servicecheck.ServiceCheckCritical, fmt.Sprintf("certificate of %s expired on %s", f.location, f.cert.NotAfter.Format(time.RFC3339))
+		case validity < c.cfg.criticalThreshold:
+			status, message = servicecheck.ServiceCheckCritical, expiresMessage(f, validity)
+		case validity < c.cfg.warningThreshold:
+			status, message = servicecheck.ServiceCheckWarning, expiresMessage(f, validity)
+		}
+		sender.ServiceCheck("tls_certificates.expiry", status, "", tags, message)
+
+		inventory = append(inventory, newCertificateMetadata(f))
+	}
+
+	sender.Commit()
+
+	if inv, err := check.GetInventoryChecksContext(); err == nil {
+		inv.Set(string(c.ID()), inventoryKey, inventory)
+	}
+
+	return errors.Join(errs...)
+}
+
+func expiresMessage(f foundCertificate, validity time.Duration) string {
+	return fmt.Sprintf("certificate of %s expires in %.1f days, on %s", f.location, validity.Hours()/24, f.cert.NotAfter.Format(time.RFC3339))
+}
+
+func newCertificateMetadata(f foundCertificate) certificateMetadata {
+	fingerprint := sha256.Sum256(f.cert.Raw)
+	metadata := certificateMetadata{
+		Source:       string(f.source),
+		Location:     f.location,
+		Subject:      f.cert.Subject.String(),
+		Issuer:       f.cert.Issuer.String(),
+		SerialNumber: f.cert.SerialNumber.Text(16),
+		NotBefore:    f.cert.NotBefore,
+		NotAfter:     f.cert.NotAfter,
+		DNSNames:     f.cert.DNSNames,
+		Fingerprint:  hex.EncodeToString(fingerprint[:]),
+	}
+	for _, ip := range f.cert.IPAddresses {
+		metadata.IPAddresses = append(metadata.IPAddresses, ip.String())
+	}
+	return metadata
+}
+
+// Factory creates a new check factory
+func Factory() option.Option[func() check.Check] {
+	return option.New(newCheck)
+}
+
+func newCheck() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBaseWithInterval(CheckName, defaultMinCollectionInterval),
+		finder: &finder{
+			listeningAddresses: localListeningAddresses,
+			listSecrets:        newTLSSecretsLister(),
+		},
+		now: time.Now,
+	}
+}
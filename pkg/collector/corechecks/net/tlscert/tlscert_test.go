@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package tlscert
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+func TestRun(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	for name, notAfter := range map[string]time.Time{
+		"valid":    now.Add(30 * 24 * time.Hour),
+		"expiring": now.Add(10 * 24 * time.Hour),
+		"expired":  now.Add(-24 * time.Hour),
+	} {
+		cert, _ := newTestCertificate(t, name+".example.com", notAfter)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name+".crt"), cert, 0o600))
+	}
+	secretCert, _ := newTestCertificate(t, "ingress.example.com", now.Add(3*24*time.Hour))
+
+	c := newCheck().(*Check)
+	c.now = func() time.Time { return now }
+	c.finder.listSecrets = func(_ context.Context, namespaces []string) ([]pemBundle, error) {
+		assert.Equal(t, []string{"web"}, namespaces)
+		return []pemBundle{{
+			source:   sourceKubernetesSecret,
+			location: "web/ingress-tls",
+			tags:     []string{"kube_namespace:web", "secret_name:ingress-tls"},
+			data:     secretCert,
+		}}, nil
+	}
+
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	instance := fmt.Sprintf(`
+paths:
+  - %s/*.crt
+  - %s/missing.crt
+kubernetes_secrets:
+  enabled: true
+  namespaces: [web]
+`, dir, dir)
+	require.NoError(t, c.Configure(senderManager, integration.FakeConfigHash, integration.Data(instance), nil, "test"))
+
+	s := mocksender.NewMockSenderWithSenderManager(c.ID(), senderManager)
+	s.SetupAcceptAll()
+	err := c.Run()
+	assert.ErrorContains(t, err, "no file matches")
+
+	fileTags := func(name string) []string {
+		return []string{
+			"cert_source:file",
+			"cert_path:" + filepath.Join(dir, name+".crt"),
+			"subject_cn:" + name + ".example.com",
+			"issuer_cn:" + name + ".example.com",
+		}
+	}
+	s.AssertMetric(t, "Gauge", "tls_certificates.days_left", 30, "", fileTags("valid"))
+	s.AssertServiceCheck(t, "tls_certificates.expiry", servicecheck.ServiceCheckOK, "", fileTags("valid"), "")
+	s.AssertMetric(t, "Gauge", "tls_certificates.days_left", 10, "", fileTags("expiring"))
+	s.AssertServiceCheck(t, "tls_certificates.expiry", servicecheck.ServiceCheckWarning, "", fileTags("expiring"),
+		fmt.Sprintf("certificate of %s expires in 10.0 days, on 2026-01-11T00:00:00Z", filepath.Join(dir, "expiring.crt")))
+	s.AssertMetric(t, "Gauge", "tls_certificates.days_left", -1, "", fileTags("expired"))
+	s.AssertServiceCheck(t, "tls_certificates.expiry", servicecheck.ServiceCheckCritical, "", fileTags("expired"),
+		fmt.Sprintf("certificate of %s expired on 2025-12-31T00:00:00Z", filepath.Join(dir, "expired.crt")))
+
+	secretTags := []string{
+		"cert_source:kubernetes_secret",
+		"kube_namespace:web",
+		"secret_name:ingress-tls",
+		"subject_cn:ingress.example.com",
+		"issuer_cn:ingress.example.com",
+	}
+	s.AssertMetric(t, "Gauge", "tls_certificates.days_left", 3, "", secretTags)
+	s.AssertServiceCheck(t, "tls_certificates.expiry", servicecheck.ServiceCheckCritical, "", secretTags,
+		"certificate of web/ingress-tls expires in 3.0 days, on 2026-01-04T00:00:00Z")
+	s.AssertNumberOfCalls(t, "Gauge", 4)
+	s.AssertCalled(t, "Commit")
+}
+
+func TestNewCertificateMetadata(t *testing.T) {
+	notAfter := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cert, _ := newTestCertificate(t, "api.example.com", notAfter)
+	certs, err := parsePEMBundle(pemBundle{source: sourceEndpoint, location: "api.example.com:443", data: cert}, false)
+	require.NoError(t, err)
+
+	metadata := newCertificateMetadata(certs[0])
+	assert.Equal(t, "endpoint", metadata.Source)
+	assert.Equal(t, "api.example.com:443", metadata.Location)
+	assert.Equal(t, "CN=api.example.com", metadata.Subject)
+	assert.Equal(t, "CN=api.example.com", metadata.Issuer)
+	assert.Equal(t, "2a", metadata.SerialNumber)
+	assert.Equal(t, notAfter, metadata.NotAfter)
+	assert.Equal(t, []string{"api.example.com"}, metadata.DNSNames)
+	assert.Equal(t, []string{"127.0.0.1"}, metadata.IPAddresses)
+	assert.Len(t, metadata.Fingerprint, 64)
+}
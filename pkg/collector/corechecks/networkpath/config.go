@@ -32,6 +32,7 @@ type InitConfig struct {
 	MinCollectionInterval int64 `yaml:"min_collection_interval"`
 	TimeoutMs             int64 `yaml:"timeout"`
 	MaxTTL                uint8 `yaml:"max_ttl"`
+	ProbesPerHop          uint8 `yaml:"probes_per_hop"`
 }
 
 // InstanceConfig is used to deserialize integration instance config
@@ -40,6 +41,8 @@ type InstanceConfig struct {
 
 	DestPort uint16 `yaml:"port"`
 
+	SourcePort uint16 `yaml:"source_port"`
+
 	Protocol string `yaml:"protocol"`
 
 	SourceService      string `yaml:"source_service"`
@@ -47,6 +50,8 @@ type InstanceConfig struct {
 
 	MaxTTL uint8 `yaml:"max_ttl"`
 
+	ProbesPerHop uint8 `yaml:"probes_per_hop"`
+
 	TimeoutMs int64 `yaml:"timeout"`
 
 	MinCollectionInterval int `yaml:"min_collection_interval"`
@@ -59,9 +64,11 @@ type InstanceConfig struct {
 type CheckConfig struct {
 	DestHostname          string
 	DestPort              uint16
+	SourcePort            uint16
 	SourceService         string
 	DestinationService    string
 	MaxTTL                uint8
+	ProbesPerHop          uint8
 	Protocol              payload.Protocol
 	Timeout               time.Duration
 	MinCollectionInterval time.Duration
@@ -88,6 +95,7 @@ func NewCheckConfig(rawInstance integration.Data, rawInitConfig integration.Data
 
 	c.DestHostname = instance.DestHostname
 	c.DestPort = instance.DestPort
+	c.SourcePort = instance.SourcePort
 	c.SourceService = instance.SourceService
 	c.DestinationService = instance.DestinationService
 	c.Protocol = payload.Protocol(strings.ToUpper(instance.Protocol))
@@ -116,6 +124,12 @@ func NewCheckConfig(rawInstance integration.Data, rawInitConfig integration.Data
 		setup.DefaultNetworkPathMaxTTL,
 	)
 
+	c.ProbesPerHop = firstNonZero(
+		instance.ProbesPerHop,
+		initConfig.ProbesPerHop,
+		setup.DefaultNetworkPathProbesPerHop,
+	)
+
 	c.Tags = instance.Tags
 	c.Namespace = setup.Datadog().GetString("network_devices.namespace")
 
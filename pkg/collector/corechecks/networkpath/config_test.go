@@ -37,6 +37,7 @@ hostname: 1.2.3.4
 				Namespace:             "my-namespace",
 				Timeout:               setup.DefaultNetworkPathTimeout * time.Millisecond,
 				MaxTTL:                setup.DefaultNetworkPathMaxTTL,
+				ProbesPerHop:          setup.DefaultNetworkPathProbesPerHop,
 			},
 		},
 		{
@@ -73,6 +74,7 @@ min_collection_interval: 10
 				Namespace:             "my-namespace",
 				Timeout:               setup.DefaultNetworkPathTimeout * time.Millisecond,
 				MaxTTL:                setup.DefaultNetworkPathMaxTTL,
+				ProbesPerHop:          setup.DefaultNetworkPathProbesPerHop,
 			},
 		},
 		{
@@ -89,6 +91,7 @@ min_collection_interval: 10
 				Namespace:             "my-namespace",
 				Timeout:               setup.DefaultNetworkPathTimeout * time.Millisecond,
 				MaxTTL:                setup.DefaultNetworkPathMaxTTL,
+				ProbesPerHop:          setup.DefaultNetworkPathProbesPerHop,
 			},
 		},
 		{
@@ -102,6 +105,7 @@ hostname: 1.2.3.4
 				Namespace:             "my-namespace",
 				Timeout:               setup.DefaultNetworkPathTimeout * time.Millisecond,
 				MaxTTL:                setup.DefaultNetworkPathMaxTTL,
+				ProbesPerHop:          setup.DefaultNetworkPathProbesPerHop,
 			},
 		},
 		{
@@ -120,6 +124,7 @@ destination_service: service-b
 				Namespace:             "my-namespace",
 				Timeout:               setup.DefaultNetworkPathTimeout * time.Millisecond,
 				MaxTTL:                setup.DefaultNetworkPathMaxTTL,
+				ProbesPerHop:          setup.DefaultNetworkPathProbesPerHop,
 			},
 		},
 		{
@@ -136,6 +141,7 @@ protocol: udp
 				Protocol:              payload.ProtocolUDP,
 				Timeout:               setup.DefaultNetworkPathTimeout * time.Millisecond,
 				MaxTTL:                setup.DefaultNetworkPathMaxTTL,
+				ProbesPerHop:          setup.DefaultNetworkPathProbesPerHop,
 			},
 		},
 		{
@@ -152,6 +158,7 @@ protocol: UDP
 				Protocol:              payload.ProtocolUDP,
 				Timeout:               setup.DefaultNetworkPathTimeout * time.Millisecond,
 				MaxTTL:                setup.DefaultNetworkPathMaxTTL,
+				ProbesPerHop:          setup.DefaultNetworkPathProbesPerHop,
 			},
 		},
 		{
@@ -168,6 +175,7 @@ protocol: TCP
 				Protocol:              payload.ProtocolTCP,
 				Timeout:               setup.DefaultNetworkPathTimeout * time.Millisecond,
 				MaxTTL:                setup.DefaultNetworkPathMaxTTL,
+				ProbesPerHop:          setup.DefaultNetworkPathProbesPerHop,
 			},
 		},
 		{
@@ -186,6 +194,7 @@ min_collection_interval: 10
 				Namespace:             "my-namespace",
 				Timeout:               50000 * time.Millisecond,
 				MaxTTL:                setup.DefaultNetworkPathMaxTTL,
+				ProbesPerHop:          setup.DefaultNetworkPathProbesPerHop,
 			},
 		},
 		{
@@ -205,6 +214,7 @@ timeout: 70000
 				Namespace:             "my-namespace",
 				Timeout:               50000 * time.Millisecond,
 				MaxTTL:                setup.DefaultNetworkPathMaxTTL,
+				ProbesPerHop:          setup.DefaultNetworkPathProbesPerHop,
 			},
 		},
 		{
@@ -223,6 +233,7 @@ timeout: 70000
 				Namespace:             "my-namespace",
 				Timeout:               70000 * time.Millisecond,
 				MaxTTL:                setup.DefaultNetworkPathMaxTTL,
+				ProbesPerHop:          setup.DefaultNetworkPathProbesPerHop,
 			},
 		},
 		{
@@ -240,6 +251,7 @@ min_collection_interval: 10
 				Namespace:             "my-namespace",
 				Timeout:               setup.DefaultNetworkPathTimeout * time.Millisecond,
 				MaxTTL:                setup.DefaultNetworkPathMaxTTL,
+				ProbesPerHop:          setup.DefaultNetworkPathProbesPerHop,
 			},
 		},
 		{
@@ -270,6 +282,7 @@ min_collection_interval: 10
 				Namespace:             "my-namespace",
 				Timeout:               setup.DefaultNetworkPathTimeout * time.Millisecond,
 				MaxTTL:                50,
+				ProbesPerHop:          setup.DefaultNetworkPathProbesPerHop,
 			},
 		},
 		{
@@ -289,6 +302,7 @@ max_ttl: 64
 				Namespace:             "my-namespace",
 				Timeout:               setup.DefaultNetworkPathTimeout * time.Millisecond,
 				MaxTTL:                50,
+				ProbesPerHop:          setup.DefaultNetworkPathProbesPerHop,
 			},
 		},
 		{
@@ -307,6 +321,46 @@ max_ttl: 64
 				Namespace:             "my-namespace",
 				Timeout:               setup.DefaultNetworkPathTimeout * time.Millisecond,
 				MaxTTL:                64,
+				ProbesPerHop:          setup.DefaultNetworkPathProbesPerHop,
+			},
+		},
+		{
+			name: "source port and probes per hop from instance config",
+			rawInstance: []byte(`
+hostname: 1.2.3.4
+port: 33434
+source_port: 4242
+probes_per_hop: 3
+`),
+			rawInitConfig: []byte(`
+probes_per_hop: 5
+`),
+			expectedConfig: &CheckConfig{
+				DestHostname:          "1.2.3.4",
+				DestPort:              33434,
+				SourcePort:            4242,
+				MinCollectionInterval: time.Duration(60) * time.Second,
+				Namespace:             "my-namespace",
+				Timeout:               setup.DefaultNetworkPathTimeout * time.Millisecond,
+				MaxTTL:                setup.DefaultNetworkPathMaxTTL,
+				ProbesPerHop:          3,
+			},
+		},
+		{
+			name: "probes per hop from init config",
+			rawInstance: []byte(`
+hostname: 1.2.3.4
+`),
+			rawInitConfig: []byte(`
+probes_per_hop: 5
+`),
+			expectedConfig: &CheckConfig{
+				DestHostname:          "1.2.3.4",
+				MinCollectionInterval: time.Duration(60) * time.Second,
+				Namespace:             "my-namespace",
+				Timeout:               setup.DefaultNetworkPathTimeout * time.Millisecond,
+				MaxTTL:                setup.DefaultNetworkPathMaxTTL,
+				ProbesPerHop:          5,
 			},
 		},
 	}
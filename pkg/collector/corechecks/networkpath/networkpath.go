@@ -53,7 +53,9 @@ func (c *Check) Run() error {
 	cfg := config.Config{
 		DestHostname: c.config.DestHostname,
 		DestPort:     c.config.DestPort,
+		SourcePort:   c.config.SourcePort,
 		MaxTTL:       c.config.MaxTTL,
+		ProbesPerHop: c.config.ProbesPerHop,
 		Timeout:      c.config.Timeout,
 		Protocol:     c.config.Protocol,
 	}
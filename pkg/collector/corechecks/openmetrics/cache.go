@@ -0,0 +1,101 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"math"
+)
+
+// staleMarker is the NaN value Prometheus uses to mark a series as stale
+const staleMarker = 0x7ff0000000000002
+
+// isStaleMarker returns true if the value is the Prometheus staleness marker
+func isStaleMarker(value float64) bool {
+	return math.Float64bits(value) == staleMarker
+}
+
+// seriesState is the last observation of a cumulative series
+type seriesState struct {
+	value   float64
+	created float64
+	// scrape is the number of the last scrape the series was part of
+	scrape uint64
+}
+
+// seriesCache holds the state of the cumulative series, that is the counters and the buckets, sums and counts of the
+// histograms and summaries, to submit their increase between two scrapes. Resets are detected per series, from the
+// value decreasing or the creation timestamp changing, and the series missing from a scrape or marked as stale are
+// evicted, which bounds the cache to the series of the latest scrape.
+type seriesCache struct {
+	series    map[string]*seriesState
+	maxSeries int
+
+	scrape uint64
+	// lastScrape is the timestamp, in seconds, of the previous successful scrape
+	lastScrape float64
+	// dropped is the number of new series that couldn't be tracked during the current scrape
+	dropped int
+}
+
+func newSeriesCache(maxSeries int) *seriesCache {
+	return &seriesCache{
+		series:    make(map[string]*seriesState),
+		maxSeries: maxSeries,
+	}
+}
+
+// startScrape starts tracking the series of a new scrape
+func (c *seriesCache) startScrape() {
+	c.scrape++
+	c.dropped = 0
+}
+
+// endScrape evicts the series that weren't part of the scrape, and returns their number
+func (c *seriesCache) endScrape(timestamp float64) int {
+	evicted := 0
+	for key, state := range c.series {
+		if state.scrape != c.scrape {
+			delete(c.series, key)
+			evicted++
+		}
+	}
+	c.lastScrape = timestamp
+	return evicted
+}
+
+// increase records the value of a cumulative series, and returns its increase since the previous scrape. created is
+// the creation timestamp of the series in seconds, or 0 when the target doesn't expose it.
+func (c *seriesCache) increase(key string, value float64, created float64) (float64, bool) {
+	if math.IsNaN(value) {
+		// staleness marker, or a value that can't be accumulated
+		delete(c.series, key)
+		return 0, false
+	}
+
+	state, ok := c.series[key]
+	if !ok {
+		if len(c.series) >= c.maxSeries {
+			c.dropped++
+			return 0, false
+		}
+		c.series[key] = &seriesState{value: value, created: created, scrape: c.scrape}
+		// the whole value of a series created since the previous scrape is new, the increase of the other ones is
+		// unknown until their next scrape
+		if c.lastScrape > 0 && created > 0 && created >= c.lastScrape {
+			return value, true
+		}
+		return 0, false
+	}
+
+	previous := *state
+	state.value, state.created, state.scrape = value, created, c.scrape
+
+	if (created > 0 && previous.created > 0 && created != previous.created) || value < previous.value {
+		// the counter was reset, typically because the target restarted, and it counts from 0 again
+		return value, true
+	}
+	return value - previous.value, true
+}
@@ -0,0 +1,93 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeriesCacheIncrease(t *testing.T) {
+	cache := newSeriesCache(10)
+
+	scrape := func(timestamp float64, observe func()) int {
+		cache.startScrape()
+		observe()
+		return cache.endScrape(timestamp)
+	}
+	assertIncrease := func(key string, value float64, created float64, expected float64, expectedOk bool) {
+		t.Helper()
+		increase, ok := cache.increase(key, value, created)
+		assert.Equal(t, expectedOk, ok)
+		assert.Equal(t, expected, increase)
+	}
+
+	// the increase is unknown on the first scrape
+	scrape(100, func() {
+		assertIncrease("a", 10, 0, 0, false)
+		assertIncrease("b", 5, 50, 0, false)
+		assertIncrease("c", 7, 0, 0, false)
+	})
+
+	evicted := scrape(110, func() {
+		assertIncrease("a", 15, 0, 5, true)
+		// the target restarted, the creation timestamp changed while the value didn't decrease
+		assertIncrease("b", 8, 105, 8, true)
+		// a series created since the previous scrape is entirely new
+		assertIncrease("d", 3, 104, 3, true)
+		// a series created before the previous scrape isn't
+		assertIncrease("e", 3, 90, 0, false)
+	})
+	// c is missing from the scrape
+	assert.Equal(t, 1, evicted)
+
+	scrape(120, func() {
+		// the counter was reset
+		assertIncrease("a", 2, 0, 2, true)
+		assertIncrease("b", 9, 105, 1, true)
+		// the series was evicted, it doesn't spike when it reappears
+		assertIncrease("c", 20, 0, 0, false)
+		// staleness marker
+		assertIncrease("d", math.Float64frombits(staleMarker), 0, 0, false)
+		assertIncrease("e", 4, 90, 1, true)
+	})
+	assert.NotContains(t, cache.series, "d")
+
+	scrape(130, func() {
+		assertIncrease("d", 5, 104, 0, false)
+	})
+}
+
+func TestSeriesCacheMaxSeries(t *testing.T) {
+	cache := newSeriesCache(2)
+	cache.startScrape()
+	cache.increase("a", 1, 0)
+	cache.increase("b", 1, 0)
+	cache.increase("c", 1, 0)
+	assert.Equal(t, 1, cache.dropped)
+	cache.endScrape(100)
+	assert.Len(t, cache.series, 2)
+
+	cache.startScrape()
+	assert.Zero(t, cache.dropped)
+	increase, ok := cache.increase("a", 3, 0)
+	assert.True(t, ok)
+	assert.Equal(t, 2.0, increase)
+	cache.endScrape(110)
+
+	// the series missing from the scrape freed their space
+	cache.startScrape()
+	cache.increase("c", 1, 0)
+	assert.Zero(t, cache.dropped)
+}
+
+func TestIsStaleMarker(t *testing.T) {
+	assert.True(t, isStaleMarker(math.Float64frombits(staleMarker)))
+	assert.False(t, isStaleMarker(math.NaN()))
+	assert.False(t, isStaleMarker(0))
+}
@@ -0,0 +1,225 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+)
+
+const (
+	defaultTimeout      = 10 * time.Second
+	defaultMaxExemplars = 100
+	defaultMaxSeries    = 50000
+)
+
+// InitConfig is used to deserialize the check init config
+type InitConfig struct {
+	TimeoutSeconds int `yaml:"timeout"`
+}
+
+// InstanceConfig is used to deserialize the config of an instance. The settings are a subset of the ones of the
+// OpenMetrics V2 Python check, so that instances can switch between both implementations with the loader setting.
+type InstanceConfig struct {
+	OpenMetricsEndpoint string            `yaml:"openmetrics_endpoint"`
+	Namespace           string            `yaml:"namespace"`
+	RawMetricPrefix     string            `yaml:"raw_metric_prefix"`
+	Metrics             []interface{}     `yaml:"metrics"`
+	ExcludeMetrics      []string          `yaml:"exclude_metrics"`
+	RenameLabels        map[string]string `yaml:"rename_labels"`
+	ExcludeLabels       []string          `yaml:"exclude_labels"`
+	Tags                []string          `yaml:"tags"`
+	Headers             map[string]string `yaml:"headers"`
+	Username            string            `yaml:"username"`
+	Password            string            `yaml:"password"`
+	TLSVerify           *bool             `yaml:"tls_verify"`
+	TimeoutSeconds      int               `yaml:"timeout"`
+	CollectExemplars    *bool             `yaml:"collect_exemplars"`
+	MaxExemplars        int               `yaml:"max_exemplars"`
+	MaxSeries           int               `yaml:"max_series"`
+}
+
+// metricMatcher selects the metrics to collect and their name
+type metricMatcher struct {
+	name   string
+	regex  *regexp.Regexp
+	rename string
+}
+
+// instanceConfig is the validated configuration of an instance
+type instanceConfig struct {
+	endpoint         string
+	namespace        string
+	rawMetricPrefix  string
+	metrics          []metricMatcher
+	excludeMetrics   []*regexp.Regexp
+	renameLabels     map[string]string
+	excludeLabels    map[string]bool
+	tags             []string
+	headers          map[string]string
+	username         string
+	password         string
+	tlsVerify        bool
+	timeout          time.Duration
+	collectExemplars bool
+	maxExemplars     int
+	maxSeries        int
+}
+
+func newInstanceConfig(rawInstance integration.Data, rawInitConfig integration.Data) (*instanceConfig, error) {
+	instance := InstanceConfig{}
+	initConfig := InitConfig{}
+
+	if err := yaml.Unmarshal(rawInitConfig, &initConfig); err != nil {
+		return nil, fmt.Errorf("invalid init_config: %s", err)
+	}
+	if err := yaml.Unmarshal(rawInstance, &instance); err != nil {
+		return nil, fmt.Errorf("invalid instance config: %s", err)
+	}
+
+	if instance.OpenMetricsEndpoint == "" {
+		return nil, errors.New("the openmetrics_endpoint setting is required")
+	}
+	if u, err := url.Parse(instance.OpenMetricsEndpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid openmetrics_endpoint %q, expected an http or https URL", instance.OpenMetricsEndpoint)
+	}
+	if instance.Namespace == "" {
+		return nil, errors.New("the namespace setting is required")
+	}
+
+	c := &instanceConfig{
+		endpoint:         instance.OpenMetricsEndpoint,
+		namespace:        instance.Namespace,
+		rawMetricPrefix:  instance.RawMetricPrefix,
+		renameLabels:     instance.RenameLabels,
+		excludeLabels:    make(map[string]bool, len(instance.ExcludeLabels)),
+		tags:             append([]string{"endpoint:" + instance.OpenMetricsEndpoint}, instance.Tags...),
+		headers:          instance.Headers,
+		username:         instance.Username,
+		password:         instance.Password,
+		tlsVerify:        instance.TLSVerify == nil || *instance.TLSVerify,
+		collectExemplars: instance.CollectExemplars == nil || *instance.CollectExemplars,
+		maxExemplars:     instance.MaxExemplars,
+		maxSeries:        instance.MaxSeries,
+	}
+	for _, label := range instance.ExcludeLabels {
+		c.excludeLabels[label] = true
+	}
+	if c.maxExemplars <= 0 {
+		c.maxExemplars = defaultMaxExemplars
+	}
+	if c.maxSeries <= 0 {
+		c.maxSeries = defaultMaxSeries
+	}
+
+	switch {
+	case instance.TimeoutSeconds > 0:
+		c.timeout = time.Duration(instance.TimeoutSeconds) * time.Second
+	case initConfig.TimeoutSeconds > 0:
+		c.timeout = time.Duration(initConfig.TimeoutSeconds) * time.Second
+	default:
+		c.timeout = defaultTimeout
+	}
+
+	if len(instance.Metrics) == 0 {
+		return nil, errors.New("the metrics setting is required")
+	}
+	for _, item := range instance.Metrics {
+		matchers, err := parseMetricsItem(item)
+		if err != nil {
+			return nil, err
+		}
+		c.metrics = append(c.metrics, matchers...)
+	}
+
+	for _, pattern := range instance.ExcludeMetrics {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude_metrics regex %q: %s", pattern, err)
+		}
+		c.excludeMetrics = append(c.excludeMetrics, re)
+	}
+
+	return c, nil
+}
+
+// parseMetricsItem parses an item of the metrics list, either a metric name or regex, or a mapping of the metric
+// names to their new name
+func parseMetricsItem(item interface{}) ([]metricMatcher, error) {
+	switch item := item.(type) {
+	case string:
+		m, err := newMetricMatcher(item, "")
+		if err != nil {
+			return nil, err
+		}
+		return []metricMatcher{m}, nil
+	case map[interface{}]interface{}:
+		var matchers []metricMatcher
+		for rawName, rawRename := range item {
+			name, ok := rawName.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid metrics item %v", item)
+			}
+			rename, ok := rawRename.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid new name of metric %s, expected a string", name)
+			}
+			m, err := newMetricMatcher(name, rename)
+			if err != nil {
+				return nil, err
+			}
+			matchers = append(matchers, m)
+		}
+		return matchers, nil
+	}
+	return nil, fmt.Errorf("invalid metrics item %v, expected a string or a mapping", item)
+}
+
+func newMetricMatcher(name string, rename string) (metricMatcher, error) {
+	m := metricMatcher{name: name, rename: rename}
+	// like the Python check, the names that aren't valid metric names are regular expressions
+	if strings.ContainsFunc(name, func(r rune) bool {
+		return !(r == '_' || r == ':' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'))
+	}) {
+		re, err := regexp.Compile("^(?:" + name + ")$")
+		if err != nil {
+			return m, fmt.Errorf("invalid metrics regex %q: %s", name, err)
+		}
+		m.regex = re
+	}
+	return m, nil
+}
+
+// metricName returns the name of the metric reported for a family, and whether the family is collected
+func (c *instanceConfig) metricName(familyName string) (string, bool) {
+	name := strings.TrimPrefix(familyName, c.rawMetricPrefix)
+	for _, re := range c.excludeMetrics {
+		if re.MatchString(name) {
+			return "", false
+		}
+	}
+	for _, m := range c.metrics {
+		switch {
+		case m.regex == nil && m.name == name:
+		case m.regex != nil && m.regex.MatchString(name):
+		default:
+			continue
+		}
+		if m.rename != "" {
+			return m.rename, true
+		}
+		return name, true
+	}
+	return "", false
+}
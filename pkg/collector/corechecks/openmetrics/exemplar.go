@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"strconv"
+)
+
+// traceIDLabels and spanIDLabels are the exemplar labels holding the trace and span IDs, as set by the OpenTelemetry
+// and Prometheus client libraries
+var (
+	traceIDLabels = []string{"trace_id", "traceID", "traceId"}
+	spanIDLabels  = []string{"span_id", "spanID", "spanId"}
+)
+
+// exemplarMetadata associates a metric series to a trace in the inventory of the check. It isn't attached to the
+// submitted samples.
+type exemplarMetadata struct {
+	Metric    string   `json:"metric"`
+	Tags      []string `json:"tags"`
+	Value     float64  `json:"value"`
+	Timestamp float64  `json:"timestamp,omitempty"`
+	TraceID   string   `json:"trace_id"`
+	SpanID    string   `json:"span_id,omitempty"`
+}
+
+// newExemplarMetadata returns the metadata of an exemplar, if it refers to a trace
+func newExemplarMetadata(metric string, tags []string, e *exemplar) (exemplarMetadata, bool) {
+	traceID, ok := convertID(lookupLabel(e.labels, traceIDLabels))
+	if !ok {
+		return exemplarMetadata{}, false
+	}
+	spanID, _ := convertID(lookupLabel(e.labels, spanIDLabels))
+
+	return exemplarMetadata{
+		Metric:    metric,
+		Tags:      tags,
+		Value:     e.value,
+		Timestamp: e.timestamp,
+		TraceID:   traceID,
+		SpanID:    spanID,
+	}, true
+}
+
+func lookupLabel(labels map[string]string, names []string) string {
+	for _, name := range names {
+		if value, ok := labels[name]; ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// convertID converts a W3C trace or span ID, encoded in hexadecimal, to a Datadog ID. Like the OTLP ingestion, the
+// Datadog ID of a 128-bit trace ID is made of its lower 64 bits. IDs that are already decimal are kept as is.
+func convertID(id string) (string, bool) {
+	switch len(id) {
+	case 32:
+		id = id[16:]
+	case 16:
+	default:
+		if value, err := strconv.ParseUint(id, 10, 64); err == nil && value != 0 {
+			return id, true
+		}
+		return "", false
+	}
+
+	value, err := strconv.ParseUint(id, 16, 64)
+	if err != nil || value == 0 {
+		return "", false
+	}
+	return strconv.FormatUint(value, 10), true
+}
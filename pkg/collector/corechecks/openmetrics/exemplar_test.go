@@ -0,0 +1,49 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertID(t *testing.T) {
+	for _, tc := range []struct {
+		id       string
+		expected string
+		ok       bool
+	}{
+		{id: "4bf92f3577b34da6a3ce929d0e0e4736", expected: "11803532876627986230", ok: true},
+		{id: "00f067aa0ba902b7", expected: "67667974448284343", ok: true},
+		{id: "1234", expected: "1234", ok: true},
+		{id: "00000000000000000000000000000000"},
+		{id: "0"},
+		{id: "not-an-id"},
+		{id: ""},
+	} {
+		id, ok := convertID(tc.id)
+		assert.Equal(t, tc.ok, ok, tc.id)
+		assert.Equal(t, tc.expected, id, tc.id)
+	}
+}
+
+func TestNewExemplarMetadata(t *testing.T) {
+	_, ok := newExemplarMetadata("app.requests.count", nil, &exemplar{labels: map[string]string{"user": "a"}, value: 1})
+	assert.False(t, ok)
+
+	metadata, ok := newExemplarMetadata("app.requests.count", []string{"env:test"}, &exemplar{
+		labels: map[string]string{"traceId": "a3ce929d0e0e4736", "spanId": "invalid"},
+		value:  2,
+	})
+	assert.True(t, ok)
+	assert.Equal(t, exemplarMetadata{
+		Metric:  "app.requests.count",
+		Tags:    []string{"env:test"},
+		Value:   2,
+		TraceID: "11803532876627986230",
+	}, metadata)
+}
@@ -0,0 +1,333 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package openmetrics implements a check collecting the metrics exposed by an OpenMetrics or Prometheus endpoint.
+// Unlike the Python implementation, it parses the exemplars of the samples and reports the trace they refer to in the
+// inventory metadata of the check.
+//
+// The metric samples submitted to the aggregator carry no metadata, so the association between a series and a trace
+// is check-level only: the inventory holds the exemplars of the latest run, up to max_exemplars, and is sent with the
+// other check metadata at the interval of the inventories (1 to 10 minutes by default) rather than with the metrics.
+// Tagging the samples with the trace IDs instead would create a context per trace.
+package openmetrics
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/option"
+)
+
+const (
+	// CheckName is the name of the check, the check is selected over the Python one with the core loader
+	CheckName = "openmetrics"
+
+	// acceptHeader prefers the OpenMetrics format, which is the only one exposing exemplars
+	acceptHeader = "application/openmetrics-text;version=1.0.0,application/openmetrics-text;version=0.0.1;q=0.75,text/plain;version=0.0.4;q=0.5,*/*;q=0.1"
+
+	// maxResponseSize is the maximum size of a scraped payload
+	maxResponseSize = 64 * 1024 * 1024
+
+	// inventoryKey is the key of the exemplars in the inventory metadata of the check
+	inventoryKey = "exemplars"
+)
+
+// Check collects the metrics exposed by a single endpoint per instance. The increase of the cumulative series is
+// computed by the check rather than by the aggregator, so that counter resets and target restarts are detected per
+// series from the exposed values and creation timestamps.
+type Check struct {
+	core.CheckBase
+	cfg        *instanceConfig
+	httpClient *http.Client
+	cache      *seriesCache
+	now        func() time.Time
+
+	// exemplars holds the exemplars of the latest run, they replace the ones of the previous run in the inventory
+	exemplars []exemplarMetadata
+}
+
+// Configure parses the instance configuration
+func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigDigest uint64, rawInstance integration.Data, rawInitConfig integration.Data, source string) error {
+	cfg, err := newInstanceConfig(rawInstance, rawInitConfig)
+	if err != nil {
+		return err
+	}
+
+	c.BuildID(integrationConfigDigest, rawInstance, rawInitConfig)
+	c.cfg = cfg
+	c.cache = newSeriesCache(cfg.maxSeries)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: !cfg.tlsVerify, //nolint:gosec // verification is disabled on demand only
+	}
+	c.httpClient = &http.Client{
+		Timeout:   cfg.timeout,
+		Transport: transport,
+	}
+
+	return c.CommonConfigure(senderManager, rawInitConfig, rawInstance, source)
+}
+
+// Run scrapes the endpoint and submits the selected metrics
+func (c *Check) Run() error {
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+
+	healthServiceCheck := c.cfg.namespace + ".openmetrics.health"
+
+	scrapeTime := c.now()
+	families, err := c.scrape()
+	if err != nil {
+		sender.ServiceCheck(healthServiceCheck, servicecheck.ServiceCheckCritical, "", c.cfg.tags, err.Error())
+		sender.Commit()
+		return err
+	}
+
+	c.exemplars = c.exemplars[:0]
+	c.cache.startScrape()
+	for _, family := range families {
+		c.submitFamily(sender, family)
+	}
+	if c.cache.dropped > 0 {
+		log.Warnf("openmetrics instance %s: the number of series exceeds max_series (%d), %d new series are not collected", c.cfg.endpoint, c.cfg.maxSeries, c.cache.dropped)
+	}
+	if evicted := c.cache.endScrape(float64(scrapeTime.UnixNano()) / 1e9); evicted > 0 {
+		log.Debugf("openmetrics instance %s: %d stale series evicted", c.cfg.endpoint, evicted)
+	}
+
+	sender.ServiceCheck(healthServiceCheck, servicecheck.ServiceCheckOK, "", c.cfg.tags, "")
+	sender.Commit()
+
+	// the exemplars are reported in the check inventory rather than with the samples, see the package documentation
+	if c.cfg.collectExemplars {
+		if inv, err := check.GetInventoryChecksContext(); err == nil {
+			inv.Set(string(c.ID()), inventoryKey, slices.Clone(c.exemplars))
+		}
+	}
+	return nil
+}
+
+// scrape fetches and parses the metrics exposed by the endpoint
+func (c *Check) scrape() ([]*metricFamily, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", acceptHeader)
+	for key, value := range c.cfg.headers {
+		req.Header.Set(key, value)
+	}
+	if c.cfg.username != "" {
+		req.SetBasicAuth(c.cfg.username, c.cfg.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	families, err := parseText(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	return families, nil
+}
+
+// submitFamily submits the samples of a metric family, following the naming of the Python check
+func (c *Check) submitFamily(sender sender.Sender, family *metricFamily) {
+	baseName := family.name
+	if family.mtype == typeCounter {
+		baseName = strings.TrimSuffix(baseName, "_total")
+	}
+	name, ok := c.cfg.metricName(baseName)
+	if !ok {
+		return
+	}
+	name = c.cfg.namespace + "." + name
+
+	created := createdTimestamps(family)
+
+	for _, s := range family.samples {
+		suffix := strings.TrimPrefix(s.name, family.name)
+
+		switch family.mtype {
+		case typeCounter:
+			if suffix == "_created" {
+				continue
+			}
+			c.submitIncrease(sender, name+".count", s, c.sampleTags(s, ""), created)
+		case typeHistogram:
+			switch suffix {
+			case "_bucket":
+				tags := c.sampleTags(s, "le")
+				tags = append(tags, "upper_bound:"+formatUpperBound(s.labels["le"]))
+				c.submitIncrease(sender, name+".bucket", s, tags, created)
+			case "_sum":
+				c.submitIncrease(sender, name+".sum", s, c.sampleTags(s, ""), created)
+			case "_count":
+				c.submitIncrease(sender, name+".count", s, c.sampleTags(s, ""), created)
+			}
+		case typeGaugeHistogram:
+			switch suffix {
+			case "_bucket":
+				tags := c.sampleTags(s, "le")
+				tags = append(tags, "upper_bound:"+formatUpperBound(s.labels["le"]))
+				c.submitGauge(sender, name+".bucket", s, tags)
+			case "_gsum":
+				c.submitGauge(sender, name+".sum", s, c.sampleTags(s, ""))
+			case "_gcount":
+				c.submitGauge(sender, name+".count", s, c.sampleTags(s, ""))
+			}
+		case typeSummary:
+			switch suffix {
+			case "":
+				c.submitGauge(sender, name+".quantile", s, c.sampleTags(s, ""))
+			case "_sum":
+				c.submitIncrease(sender, name+".sum", s, c.sampleTags(s, ""), created)
+			case "_count":
+				c.submitIncrease(sender, name+".count", s, c.sampleTags(s, ""), created)
+			}
+		case typeInfo:
+			c.submitGauge(sender, name+".info", s, c.sampleTags(s, ""))
+		default:
+			c.submitGauge(sender, name, s, c.sampleTags(s, ""))
+		}
+	}
+}
+
+// submitGauge submits the value of a sample, unless it is a staleness marker
+func (c *Check) submitGauge(sender sender.Sender, name string, s *sample, tags []string) {
+	if isStaleMarker(s.value) {
+		return
+	}
+	sender.Gauge(name, s.value, "", tags)
+	c.recordExemplar(name, s, tags)
+}
+
+// submitIncrease submits the increase of a cumulative sample since the previous scrape. The previous value is
+// looked up by series rather than by tags, as the series only differing by excluded labels share their tags.
+func (c *Check) submitIncrease(sender sender.Sender, name string, s *sample, tags []string, created map[string]float64) {
+	key := s.name + "|" + formatLabels(s.labels)
+	if increase, ok := c.cache.increase(key, s.value, created[labelsKey(s.labels)]); ok {
+		sender.Count(name, increase, "", tags)
+	}
+	// the exemplar refers to a trace even when the increase of the series isn't known yet
+	c.recordExemplar(name, s, tags)
+}
+
+// recordExemplar records the exemplar of a sample, if any
+func (c *Check) recordExemplar(name string, s *sample, tags []string) {
+	if s.exemplar == nil || !c.cfg.collectExemplars {
+		return
+	}
+	if len(c.exemplars) >= c.cfg.maxExemplars {
+		log.Debugf("openmetrics instance %s: the number of exemplars exceeds max_exemplars (%d), dropping the exemplar of %s", c.cfg.endpoint, c.cfg.maxExemplars, name)
+		return
+	}
+	if metadata, ok := newExemplarMetadata(name, tags, s.exemplar); ok {
+		c.exemplars = append(c.exemplars, metadata)
+	}
+}
+
+// createdTimestamps returns the creation timestamps exposed by the _created samples of a family, by label set
+func createdTimestamps(family *metricFamily) map[string]float64 {
+	var created map[string]float64
+	for _, s := range family.samples {
+		if s.name != family.name+"_created" {
+			continue
+		}
+		if created == nil {
+			created = make(map[string]float64)
+		}
+		created[labelsKey(s.labels)] = s.value
+	}
+	return created
+}
+
+// labelsKey returns a key identifying a label set, the labels identifying the buckets and quantiles of a series are
+// ignored as they share the creation timestamp of the series
+func labelsKey(labels map[string]string) string {
+	return formatLabels(labels, "le", "quantile")
+}
+
+// formatLabels returns the sorted labels of a label set, except the ignored ones
+func formatLabels(labels map[string]string, ignored ...string) string {
+	var b strings.Builder
+	for _, label := range slices.Sorted(maps.Keys(labels)) {
+		if slices.Contains(ignored, label) {
+			continue
+		}
+		b.WriteString(label)
+		b.WriteByte('=')
+		b.WriteString(labels[label])
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// sampleTags returns the tags of a sample: the instance tags and its labels, except the skipped one
+func (c *Check) sampleTags(s *sample, skipLabel string) []string {
+	tags := make([]string, 0, len(c.cfg.tags)+len(s.labels)+1)
+	tags = append(tags, c.cfg.tags...)
+	for _, label := range slices.Sorted(maps.Keys(s.labels)) {
+		if label == skipLabel || c.cfg.excludeLabels[label] {
+			continue
+		}
+		key := label
+		if renamed, ok := c.cfg.renameLabels[label]; ok {
+			key = renamed
+		}
+		tags = append(tags, key+":"+s.labels[label])
+	}
+	return tags
+}
+
+// formatUpperBound formats the upper bound of a histogram bucket like the Python check
+func formatUpperBound(le string) string {
+	if le == "+Inf" {
+		return "inf"
+	}
+	return le
+}
+
+// Factory creates a new check factory
+func Factory() option.Option[func() check.Check] {
+	return option.New(newCheck)
+}
+
+func newCheck() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(CheckName),
+		now:       time.Now,
+	}
+}
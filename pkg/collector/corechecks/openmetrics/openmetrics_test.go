@@ -0,0 +1,253 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package openmetrics
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+// testPayload is the payload exposed by the test server, the values of the cumulative series are multiplied by the
+// number of scrapes
+const testPayload = `# TYPE http_requests counter
+http_requests_total{method="get",pod="web-1"} %[1]d # {trace_id="4bf92f3577b34da6a3ce929d0e0e4736",span_id="00f067aa0ba902b7"} 1 1700000000.5
+http_requests_created{method="get",pod="web-1"} 1700000000
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{le="0.1"} %[2]d # {traceID="1234"} 0.05
+request_duration_seconds_bucket{le="+Inf"} %[3]d
+request_duration_seconds_count %[3]d
+request_duration_seconds_sum %[4]g
+# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{quantile="0.5"} 0.01
+rpc_duration_seconds_sum %[5]d
+rpc_duration_seconds_count %[6]d
+# TYPE build info
+build_info{version="1.2.3"} 1
+# TYPE temperature gauge
+temperature 21.5
+# TYPE ignored gauge
+ignored 1
+# EOF
+`
+
+func newTestServer(t *testing.T) *httptest.Server {
+	scrapes := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Accept"), "application/openmetrics-text")
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		scrapes++
+		w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+		fmt.Fprintf(w, testPayload, 12*scrapes, 3*scrapes, 5*scrapes, 1.5*float64(scrapes), 3*scrapes, 200*scrapes)
+	}))
+}
+
+// runCheck runs the check twice, so that the increase of the cumulative series since the first run is submitted
+func runCheck(t *testing.T, instance string) (*Check, *mocksender.MockSender, error) {
+	c := newCheck().(*Check)
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	require.NoError(t, c.Configure(senderManager, integration.FakeConfigHash, integration.Data(instance), nil, "test"))
+
+	s := mocksender.NewMockSenderWithSenderManager(c.ID(), senderManager)
+	s.SetupAcceptAll()
+	if err := c.Run(); err != nil {
+		return c, s, err
+	}
+	s.ResetCalls()
+	return c, s, c.Run()
+}
+
+func TestOpenMetricsCheck(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	c, s, err := runCheck(t, fmt.Sprintf(`
+openmetrics_endpoint: %s/metrics
+namespace: app
+headers:
+  Authorization: Bearer token
+tags:
+  - env:test
+metrics:
+  - http_requests
+  - request_duration_seconds
+  - rpc_.*
+  - build
+  - temperature: room.temperature
+rename_labels:
+  pod: kube_pod
+exclude_labels:
+  - method
+`, srv.URL))
+	require.NoError(t, err)
+
+	instanceTags := []string{"endpoint:" + srv.URL + "/metrics", "env:test"}
+	counterTags := append(instanceTags, "kube_pod:web-1")
+	s.AssertMetric(t, "Count", "app.http_requests.count", 12, "", counterTags)
+	s.AssertNumberOfCalls(t, "Count", 7)
+
+	s.AssertMetric(t, "Count", "app.request_duration_seconds.bucket", 3, "", append(instanceTags, "upper_bound:0.1"))
+	s.AssertMetric(t, "Count", "app.request_duration_seconds.bucket", 5, "", append(instanceTags, "upper_bound:inf"))
+	s.AssertMetric(t, "Count", "app.request_duration_seconds.count", 5, "", instanceTags)
+	s.AssertMetric(t, "Count", "app.request_duration_seconds.sum", 1.5, "", instanceTags)
+
+	s.AssertMetric(t, "Gauge", "app.rpc_duration_seconds.quantile", 0.01, "", append(instanceTags, "quantile:0.5"))
+	s.AssertMetric(t, "Count", "app.rpc_duration_seconds.sum", 3, "", instanceTags)
+	s.AssertMetric(t, "Count", "app.rpc_duration_seconds.count", 200, "", instanceTags)
+
+	s.AssertMetric(t, "Gauge", "app.build.info", 1, "", append(instanceTags, "version:1.2.3"))
+	s.AssertMetric(t, "Gauge", "app.room.temperature", 21.5, "", instanceTags)
+	s.AssertNotCalled(t, "Gauge", "app.ignored", mock.Anything, mock.Anything, mock.Anything)
+
+	s.AssertServiceCheck(t, "app.openmetrics.health", servicecheck.ServiceCheckOK, "", instanceTags, "")
+
+	assert.Equal(t, []exemplarMetadata{
+		{
+			Metric:    "app.http_requests.count",
+			Tags:      counterTags,
+			Value:     1,
+			Timestamp: 1700000000.5,
+			TraceID:   "11803532876627986230",
+			SpanID:    "67667974448284343",
+		},
+		{
+			Metric:  "app.request_duration_seconds.bucket",
+			Tags:    append(instanceTags, "upper_bound:0.1"),
+			Value:   0.05,
+			TraceID: "1234",
+		},
+	}, c.exemplars)
+}
+
+func TestOpenMetricsCheckExcludedLabelSeries(t *testing.T) {
+	scrapes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		scrapes++
+		w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+		fmt.Fprintf(w, `# TYPE http_requests counter
+http_requests_total{method="get"} %d
+http_requests_total{method="post"} %d
+# EOF
+`, 100*scrapes, 10*scrapes)
+	}))
+	defer srv.Close()
+
+	_, s, err := runCheck(t, fmt.Sprintf(`
+openmetrics_endpoint: %s/metrics
+namespace: app
+metrics:
+  - http_requests
+exclude_labels:
+  - method
+`, srv.URL))
+	require.NoError(t, err)
+
+	// the series only differing by the excluded label keep their own previous value
+	tags := []string{"endpoint:" + srv.URL + "/metrics"}
+	s.AssertMetric(t, "Count", "app.http_requests.count", 100, "", tags)
+	s.AssertMetric(t, "Count", "app.http_requests.count", 10, "", tags)
+	s.AssertNumberOfCalls(t, "Count", 2)
+}
+
+func TestOpenMetricsCheckExemplarsLimit(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	c, _, err := runCheck(t, fmt.Sprintf(`
+openmetrics_endpoint: %s/metrics
+namespace: app
+headers:
+  Authorization: Bearer token
+metrics:
+  - .*
+max_exemplars: 1
+`, srv.URL))
+	require.NoError(t, err)
+	require.Len(t, c.exemplars, 1)
+	assert.Equal(t, "app.http_requests.count", c.exemplars[0].Metric)
+
+	c, _, err = runCheck(t, fmt.Sprintf(`
+openmetrics_endpoint: %s/metrics
+namespace: app
+headers:
+  Authorization: Bearer token
+metrics:
+  - .*
+collect_exemplars: false
+`, srv.URL))
+	require.NoError(t, err)
+	assert.Empty(t, c.exemplars)
+}
+
+func TestOpenMetricsCheckScrapeError(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	_, s, err := runCheck(t, fmt.Sprintf(`
+openmetrics_endpoint: %s/metrics
+namespace: app
+metrics:
+  - .*
+`, srv.URL))
+	assert.Error(t, err)
+	s.AssertServiceCheck(t, "app.openmetrics.health", servicecheck.ServiceCheckCritical, "", []string{"endpoint:" + srv.URL + "/metrics"}, err.Error())
+	s.AssertNotCalled(t, "Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInstanceConfig(t *testing.T) {
+	cfg, err := newInstanceConfig([]byte(`
+openmetrics_endpoint: http://localhost:9090/metrics
+namespace: app
+raw_metric_prefix: myapp_
+metrics:
+  - requests
+  - go_.*: runtime
+exclude_metrics:
+  - go_gc_.*
+`), []byte(`timeout: 3`))
+	require.NoError(t, err)
+	assert.Equal(t, defaultMaxExemplars, cfg.maxExemplars)
+	assert.True(t, cfg.collectExemplars)
+	assert.Equal(t, defaultMaxSeries, cfg.maxSeries)
+	assert.Equal(t, "3s", cfg.timeout.String())
+
+	name, ok := cfg.metricName("myapp_requests")
+	assert.True(t, ok)
+	assert.Equal(t, "requests", name)
+	name, ok = cfg.metricName("go_goroutines")
+	assert.True(t, ok)
+	assert.Equal(t, "runtime", name)
+	_, ok = cfg.metricName("go_gc_duration_seconds")
+	assert.False(t, ok)
+	_, ok = cfg.metricName("myapp_requests_total")
+	assert.False(t, ok)
+
+	for _, instance := range []string{
+		`{namespace: app, metrics: [a]}`,
+		`{openmetrics_endpoint: "ftp://localhost", namespace: app, metrics: [a]}`,
+		`{openmetrics_endpoint: "http://localhost", metrics: [a]}`,
+		`{openmetrics_endpoint: "http://localhost", namespace: app}`,
+		`{openmetrics_endpoint: "http://localhost", namespace: app, metrics: ["("]}`,
+		`{openmetrics_endpoint: "http://localhost", namespace: app, metrics: [{a: 1}]}`,
+	} {
+		_, err := newInstanceConfig([]byte(instance), nil)
+		assert.Error(t, err, instance)
+	}
+}
@@ -0,0 +1,278 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// metric family types, as declared by the TYPE metadata
+const (
+	typeCounter        = "counter"
+	typeGauge          = "gauge"
+	typeHistogram      = "histogram"
+	typeGaugeHistogram = "gaugehistogram"
+	typeSummary        = "summary"
+	typeInfo           = "info"
+	typeStateSet       = "stateset"
+	typeUnknown        = "unknown"
+	// typeUntyped is the Prometheus name of the unknown type
+	typeUntyped = "untyped"
+)
+
+// familySuffixes holds the suffixes of the sample names of each family type
+var familySuffixes = map[string][]string{
+	typeCounter:        {"_total", "_created"},
+	typeHistogram:      {"_bucket", "_count", "_sum", "_created"},
+	typeGaugeHistogram: {"_bucket", "_gcount", "_gsum"},
+	typeSummary:        {"_count", "_sum", "_created"},
+	typeInfo:           {"_info"},
+}
+
+// metricFamily is a set of samples sharing a name and a type
+type metricFamily struct {
+	name    string
+	mtype   string
+	samples []*sample
+}
+
+// sample is a single sample of a metric family
+type sample struct {
+	name     string
+	labels   map[string]string
+	value    float64
+	exemplar *exemplar
+}
+
+// exemplar is a reference to data outside of the metric set, typically a trace
+type exemplar struct {
+	labels    map[string]string
+	value     float64
+	timestamp float64
+}
+
+// parseText parses metrics exposed in the OpenMetrics or in the Prometheus text formats. The OpenMetrics format is a
+// superset of the Prometheus one, which additionally supports exemplars.
+func parseText(data []byte) ([]*metricFamily, error) {
+	var (
+		families []*metricFamily
+		byName   = make(map[string]*metricFamily)
+		current  *metricFamily
+	)
+
+	getFamily := func(name string) *metricFamily {
+		if f, ok := byName[name]; ok {
+			return f
+		}
+		f := &metricFamily{name: name, mtype: typeUnknown}
+		byName[name] = f
+		families = append(families, f)
+		return f
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if line[0] == '#' {
+			fields := strings.SplitN(line, " ", 4)
+			if len(fields) == 2 && fields[1] == "EOF" {
+				break
+			}
+			if len(fields) < 4 {
+				continue
+			}
+			switch fields[1] {
+			case "TYPE":
+				current = getFamily(fields[2])
+				current.mtype = strings.ToLower(fields[3])
+				if current.mtype == typeUntyped {
+					current.mtype = typeUnknown
+				}
+			case "HELP", "UNIT":
+				current = getFamily(fields[2])
+			}
+			continue
+		}
+
+		s, err := parseSample(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		if current == nil || !current.owns(s.name) {
+			current = nil
+			for _, name := range candidateFamilyNames(s.name) {
+				if f, ok := byName[name]; ok && f.owns(s.name) {
+					current = f
+					break
+				}
+			}
+			if current == nil {
+				current = getFamily(s.name)
+			}
+		}
+		current.samples = append(current.samples, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return families, nil
+}
+
+// owns returns true if the sample name belongs to the family
+func (f *metricFamily) owns(sampleName string) bool {
+	if sampleName == f.name {
+		return f.mtype != typeHistogram && f.mtype != typeSummary && f.mtype != typeGaugeHistogram
+	}
+	suffix, ok := strings.CutPrefix(sampleName, f.name)
+	if !ok {
+		return false
+	}
+	for _, s := range familySuffixes[f.mtype] {
+		if suffix == s {
+			return true
+		}
+	}
+	return false
+}
+
+// candidateFamilyNames returns the possible family names of a sample name
+func candidateFamilyNames(sampleName string) []string {
+	names := []string{sampleName}
+	for _, suffix := range []string{"_total", "_created", "_bucket", "_count", "_sum", "_gcount", "_gsum", "_info"} {
+		if name, ok := strings.CutSuffix(sampleName, suffix); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// parseSample parses a sample line: name{labels} value [timestamp] [# {labels} value [timestamp]]
+func parseSample(line string) (*sample, error) {
+	s := &sample{}
+
+	end := strings.IndexAny(line, "{ ")
+	if end <= 0 {
+		return nil, errors.New("invalid sample")
+	}
+	s.name = line[:end]
+	rest := line[end:]
+
+	if rest[0] == '{' {
+		var err error
+		if s.labels, rest, err = parseLabels(rest); err != nil {
+			return nil, err
+		}
+	}
+
+	rest, rawExemplar, hasExemplar := strings.Cut(rest, " # ")
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, errors.New("invalid sample value")
+	}
+	value, err := parseFloat(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid sample value: %w", err)
+	}
+	s.value = value
+
+	if hasExemplar {
+		if s.exemplar, err = parseExemplar(strings.TrimSpace(rawExemplar)); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// parseExemplar parses an exemplar: {labels} value [timestamp]
+func parseExemplar(raw string) (*exemplar, error) {
+	if raw == "" || raw[0] != '{' {
+		return nil, errors.New("invalid exemplar")
+	}
+	labels, rest, err := parseLabels(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exemplar: %w", err)
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, errors.New("invalid exemplar value")
+	}
+	e := &exemplar{labels: labels}
+	if e.value, err = parseFloat(fields[0]); err != nil {
+		return nil, fmt.Errorf("invalid exemplar value: %w", err)
+	}
+	if len(fields) == 2 {
+		if e.timestamp, err = parseFloat(fields[1]); err != nil {
+			return nil, fmt.Errorf("invalid exemplar timestamp: %w", err)
+		}
+	}
+	return e, nil
+}
+
+// parseLabels parses a label set starting with '{', and returns the rest of the input
+func parseLabels(s string) (map[string]string, string, error) {
+	labels := make(map[string]string)
+	i := 1
+	for {
+		for i < len(s) && (s[i] == ' ' || s[i] == ',') {
+			i++
+		}
+		if i >= len(s) {
+			return nil, "", errors.New("unterminated label set")
+		}
+		if s[i] == '}' {
+			return labels, s[i+1:], nil
+		}
+
+		eq := strings.IndexByte(s[i:], '=')
+		if eq <= 0 {
+			return nil, "", errors.New("invalid label")
+		}
+		name := strings.TrimSpace(s[i : i+eq])
+		i += eq + 1
+		if i >= len(s) || s[i] != '"' {
+			return nil, "", fmt.Errorf("invalid value of label %s", name)
+		}
+		i++
+
+		var value strings.Builder
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				switch s[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(s[i])
+				}
+				continue
+			}
+			value.WriteByte(s[i])
+		}
+		if i >= len(s) {
+			return nil, "", fmt.Errorf("unterminated value of label %s", name)
+		}
+		i++
+		labels[name] = value.String()
+	}
+}
+
+// parseFloat parses a number, including the NaN, +Inf and -Inf values
+func parseFloat(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
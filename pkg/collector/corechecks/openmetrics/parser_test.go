@@ -0,0 +1,103 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOpenMetrics(t *testing.T) {
+	families, err := parseText([]byte(`# TYPE http_requests counter
+# HELP http_requests Number of requests.
+http_requests_total{method="get",path="/a \"b\""} 12 # {trace_id="4bf92f3577b34da6a3ce929d0e0e4736",span_id="00f067aa0ba902b7"} 1 1700000000.5
+http_requests_created{method="get",path="/a \"b\""} 1700000000
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{le="0.1"} 3
+request_duration_seconds_bucket{le="+Inf"} 5 # {trace_id="abc"} 0.7
+request_duration_seconds_count 5
+request_duration_seconds_sum 1.5
+# TYPE build info
+build_info{version="1.2.3"} 1
+# TYPE temperature gauge
+temperature NaN
+# EOF
+ignored 1
+`))
+	require.NoError(t, err)
+	require.Len(t, families, 4)
+
+	counter := families[0]
+	assert.Equal(t, "http_requests", counter.name)
+	assert.Equal(t, typeCounter, counter.mtype)
+	require.Len(t, counter.samples, 2)
+	assert.Equal(t, "http_requests_total", counter.samples[0].name)
+	assert.Equal(t, map[string]string{"method": "get", "path": `/a "b"`}, counter.samples[0].labels)
+	assert.Equal(t, 12.0, counter.samples[0].value)
+	assert.Equal(t, &exemplar{
+		labels:    map[string]string{"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "span_id": "00f067aa0ba902b7"},
+		value:     1,
+		timestamp: 1700000000.5,
+	}, counter.samples[0].exemplar)
+	assert.Nil(t, counter.samples[1].exemplar)
+
+	histogram := families[1]
+	assert.Equal(t, typeHistogram, histogram.mtype)
+	require.Len(t, histogram.samples, 4)
+	assert.Equal(t, "+Inf", histogram.samples[1].labels["le"])
+	assert.Equal(t, 0.7, histogram.samples[1].exemplar.value)
+
+	assert.Equal(t, typeInfo, families[2].mtype)
+	assert.Equal(t, "build_info", families[2].samples[0].name)
+
+	assert.True(t, math.IsNaN(families[3].samples[0].value))
+}
+
+func TestParsePrometheus(t *testing.T) {
+	families, err := parseText([]byte(`# HELP go_goroutines Number of goroutines.
+# TYPE go_goroutines gauge
+go_goroutines 8
+# TYPE process_cpu_seconds_total counter
+process_cpu_seconds_total 1.25 1700000000000
+# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{quantile="0.5"} 0.01
+rpc_duration_seconds_sum 3
+rpc_duration_seconds_count 200
+# TYPE legacy untyped
+legacy 1
+no_metadata{a="b"} 2
+`))
+	require.NoError(t, err)
+	require.Len(t, families, 5)
+
+	assert.Equal(t, "process_cpu_seconds_total", families[1].name)
+	assert.Equal(t, typeCounter, families[1].mtype)
+	assert.Len(t, families[1].samples, 1)
+
+	assert.Equal(t, typeSummary, families[2].mtype)
+	assert.Len(t, families[2].samples, 3)
+
+	assert.Equal(t, typeUnknown, families[3].mtype)
+	assert.Equal(t, "no_metadata", families[4].name)
+	assert.Equal(t, typeUnknown, families[4].mtype)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, payload := range []string{
+		`metric{a="b" 1`,
+		`metric{a=b} 1`,
+		`metric abc`,
+		`metric 1 2 3`,
+		`metric 1 # trace_id="abc" 1`,
+		`metric 1 # {trace_id="abc"}`,
+	} {
+		_, err := parseText([]byte(payload))
+		assert.Error(t, err, payload)
+	}
+}
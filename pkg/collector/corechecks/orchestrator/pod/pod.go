@@ -11,6 +11,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"go.uber.org/atomic"
 
@@ -58,6 +59,7 @@ type Check struct {
 	store      workloadmeta.Component
 	cfg        config.Component
 	tagger     tagger.Component
+	startups   *podStartupTracker
 }
 
 // Factory creates a new check factory
@@ -106,6 +108,7 @@ func (c *Check) Configure(
 	if c.config.KubeClusterName == "" {
 		return errors.New("orchestrator check is configured but the cluster name is empty")
 	}
+	processors.ResourceCache.SetLimits(c.config.ResourceCacheTTL, c.config.ResourceCacheMaxEntries)
 
 	if c.processor == nil {
 		c.processor = processors.NewProcessor(k8sProcessors.NewPodHandlers(c.cfg, c.store, c.tagger))
@@ -119,6 +122,10 @@ func (c *Check) Configure(
 		c.sender = sender
 	}
 
+	if c.config.PodStartupMetricsEnabled && c.startups == nil {
+		c.startups = newPodStartupTracker(time.Now())
+	}
+
 	if c.hostName == "" {
 		hname, _ := hostname.Get(context.TODO())
 		c.hostName = hname
@@ -178,5 +185,10 @@ func (c *Check) Run() error {
 	c.sender.OrchestratorMetadata(processResult.MetadataMessages, c.clusterID, int(orchestrator.K8sPod))
 	c.sender.OrchestratorManifest(processResult.ManifestMessages, c.clusterID)
 
+	if c.startups != nil {
+		c.startups.report(c.sender, podList)
+		c.sender.Commit()
+	}
+
 	return nil
 }
@@ -0,0 +1,92 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubelet && orchestrator
+
+package pod
+
+import (
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/DataDog/datadog-agent/comp/core/tagger/tags"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	k8sTransformers "github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/transformers/k8s"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes"
+)
+
+const podStartupMetricPrefix = "kubernetes.pod.startup."
+
+// podStartupTracker reports the startup latency of the pods once, when they become ready
+type podStartupTracker struct {
+	// pods scheduled before the tracker started are ignored, as their Ready condition
+	// may have transitioned several times since they started
+	startTime time.Time
+	reported  map[types.UID]struct{}
+}
+
+func newPodStartupTracker(startTime time.Time) *podStartupTracker {
+	return &podStartupTracker{
+		startTime: startTime,
+		reported:  make(map[types.UID]struct{}),
+	}
+}
+
+// report sends the startup latency of the pods which became ready since the last call
+func (t *podStartupTracker) report(s sender.Sender, pods []*corev1.Pod) {
+	seen := make(map[types.UID]struct{}, len(pods))
+	for _, pod := range pods {
+		seen[pod.UID] = struct{}{}
+		if _, found := t.reported[pod.UID]; found {
+			continue
+		}
+
+		latency, ok := k8sTransformers.ExtractPodStartupLatency(pod)
+		if !ok || latency.ScheduledTime.Before(t.startTime) {
+			continue
+		}
+		t.reported[pod.UID] = struct{}{}
+
+		podTags := podStartupTags(pod)
+		s.Distribution(podStartupMetricPrefix+"initialization_duration", latency.Initialization.Seconds(), "", podTags)
+		s.Distribution(podStartupMetricPrefix+"readiness_duration", latency.Readiness.Seconds(), "", podTags)
+		s.Distribution(podStartupMetricPrefix+"total_duration", latency.Total.Seconds(), "", podTags)
+	}
+
+	// forget the pods which are gone
+	for uid := range t.reported {
+		if _, found := seen[uid]; !found {
+			delete(t.reported, uid)
+		}
+	}
+}
+
+// podStartupTags returns the low cardinality tags of a pod: its namespace and owner
+func podStartupTags(pod *corev1.Pod) []string {
+	podTags := []string{tags.KubeNamespace + ":" + pod.Namespace}
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller == nil || !*owner.Controller {
+			continue
+		}
+		podTags = append(podTags,
+			tags.KubeOwnerRefKind+":"+strings.ToLower(owner.Kind),
+			tags.KubeOwnerRefName+":"+owner.Name,
+		)
+		switch owner.Kind {
+		case kubernetes.ReplicaSetKind:
+			if deployment := kubernetes.ParseDeploymentForReplicaSet(owner.Name); deployment != "" {
+				podTags = append(podTags, tags.KubeDeployment+":"+deployment)
+			}
+		case kubernetes.JobKind:
+			if cronjob, _ := kubernetes.ParseCronJobForJob(owner.Name); cronjob != "" {
+				podTags = append(podTags, tags.KubeCronjob+":"+cronjob)
+			}
+		}
+	}
+	return podTags
+}
@@ -0,0 +1,85 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubelet && orchestrator && test
+
+package pod
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/util/pointer"
+)
+
+func newStartedPod(uid string, scheduled time.Time, ready bool) *corev1.Pod {
+	readyStatus := corev1.ConditionFalse
+	if ready {
+		readyStatus = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:       types.UID(uid),
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "web-5d4f8c7b9", Controller: pointer.Ptr(true)},
+			},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(scheduled)},
+				{Type: corev1.PodInitialized, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(scheduled.Add(30 * time.Second))},
+				{Type: corev1.PodReady, Status: readyStatus, LastTransitionTime: metav1.NewTime(scheduled.Add(45 * time.Second))},
+			},
+		},
+	}
+}
+
+func TestPodStartupTracker(t *testing.T) {
+	start := time.Now().Truncate(time.Second)
+	tracker := newPodStartupTracker(start)
+	expectedTags := []string{
+		"kube_namespace:default",
+		"kube_ownerref_kind:replicaset",
+		"kube_ownerref_name:web-5d4f8c7b9",
+		"kube_deployment:web",
+	}
+
+	s := mocksender.NewMockSender("orchestrator_pod")
+	s.SetupAcceptAll()
+
+	pods := []*corev1.Pod{
+		newStartedPod("before-start", start.Add(-time.Minute), true),
+		newStartedPod("starting", start.Add(time.Second), false),
+		newStartedPod("started", start.Add(time.Second), true),
+	}
+	tracker.report(s, pods)
+
+	s.AssertNumberOfCalls(t, "Distribution", 3)
+	s.AssertMetric(t, "Distribution", "kubernetes.pod.startup.initialization_duration", 30, "", expectedTags)
+	s.AssertMetric(t, "Distribution", "kubernetes.pod.startup.readiness_duration", 15, "", expectedTags)
+	s.AssertMetric(t, "Distribution", "kubernetes.pod.startup.total_duration", 45, "", expectedTags)
+
+	// the latency of a pod is only reported once
+	s.ResetCalls()
+	s.SetupAcceptAll()
+	pods[1] = newStartedPod("starting", start.Add(time.Second), true)
+	tracker.report(s, pods)
+	s.AssertNumberOfCalls(t, "Distribution", 3)
+
+	// pods which are gone are forgotten
+	s.ResetCalls()
+	tracker.report(s, pods[:1])
+	s.AssertNotCalled(t, "Distribution", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	if len(tracker.reported) != 0 {
+		t.Errorf("expected no tracked pod, got %d", len(tracker.reported))
+	}
+}
@@ -75,6 +75,15 @@ func (pcm ProfileConfigMap) withNames() ProfileConfigMap {
 	return pcm
 }
 
+// definitions returns the definitions of the profiles, by name
+func (pcm ProfileConfigMap) definitions() profiledefinition.ProfileDefinitionMap {
+	definitions := make(profiledefinition.ProfileDefinitionMap, len(pcm))
+	for name, profile := range pcm {
+		definitions[name] = &profile.Definition
+	}
+	return definitions
+}
+
 // Clone duplicates a ProfileConfigMap
 func (pcm ProfileConfigMap) Clone() ProfileConfigMap {
 	return profiledefinition.CloneMap(pcm)
@@ -439,7 +439,7 @@ func Test_mergeProfileDefinition(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mergeProfileDefinition(&tt.targetDefinition, &tt.baseDefinition)
+			profiledefinition.MergeProfileDefinition(&tt.targetDefinition, &tt.baseDefinition)
 			assert.Equal(t, tt.expectedDefinition.Metrics, tt.targetDefinition.Metrics)
 			assert.Equal(t, tt.expectedDefinition.MetricTags, tt.targetDefinition.MetricTags)
 			assert.Equal(t, tt.expectedDefinition.Metadata, tt.targetDefinition.Metadata)
@@ -31,8 +31,9 @@ func ResetRCProvider() {
 }
 
 // NewRCProvider returns a profile provider that subscribes to remote
-// configuration and receives profile updates from the backend. Multiple calls
-// will return the same singleton object.
+// configuration and receives profile updates from the backend, both the user
+// profiles and the profiles maintained by Datadog. Multiple calls will return
+// the same singleton object.
 func NewRCProvider(client rcclient.Component) (Provider, error) {
 	rcOnce.Do(func() {
 		rcSingleton, rcError = buildAndSubscribeRCProvider(client)
@@ -56,13 +57,14 @@ func buildAndSubscribeRCProvider(rcClient rcclient.Component) (*UpdatableProvide
 	// Subscribe to the RC client
 	log.Info("Subscribing to remote config for device profiles")
 	rcClient.Subscribe(state.ProductNDMDeviceProfilesCustom, makeOnUpdate(provider))
+	rcClient.Subscribe(state.ProductNDMDeviceProfilesDD, makeOnDefaultUpdate(provider, defaultProfiles))
 
 	return provider, nil
 }
 
 // unpackRawConfigs converts a map of raw remote config data to a map of parsed
-// profiles.
-func unpackRawConfigs(update map[string]state.RawConfig) (ProfileConfigMap, map[string]error) {
+// profiles, flagged as user profiles or not.
+func unpackRawConfigs(update map[string]state.RawConfig, isUserProfile bool) (ProfileConfigMap, map[string]error) {
 	errors := make(map[string]error)
 	profiles := make(ProfileConfigMap)
 	// iterate over keys in sorted order for determinism
@@ -85,7 +87,7 @@ func unpackRawConfigs(update map[string]state.RawConfig) (ProfileConfigMap, map[
 		profiles[def.Profile.Name] = ProfileConfig{
 			DefinitionFile: "",
 			Definition:     def.Profile,
-			IsUserProfile:  true,
+			IsUserProfile:  isUserProfile,
 		}
 	}
 	return profiles, errors
@@ -97,22 +99,83 @@ func unpackRawConfigs(update map[string]state.RawConfig) (ProfileConfigMap, map[
 func makeOnUpdate(up *UpdatableProvider) func(map[string]state.RawConfig, func(string, state.ApplyStatus)) {
 	onUpdate := func(update map[string]state.RawConfig, applyStateCallback func(string, state.ApplyStatus)) {
 		log.Infof("Received %d device profiles via remote configuration", len(update))
-		userProfiles, errors := unpackRawConfigs(update)
+		userProfiles, errors := unpackRawConfigs(update, true)
 		// update is a dict of ALL current custom profiles, so we replace the existing set entirely.
-		up.Update(userProfiles, up.defaultProfiles, time.Now())
-		// Report successes/errors
-		for k := range update {
-			if errors[k] != nil {
-				applyStateCallback(k, state.ApplyStatus{
-					State: state.ApplyStateError,
-					Error: errors[k].Error(),
-				})
-			} else {
-				applyStateCallback(k, state.ApplyStatus{
-					State: state.ApplyStateAcknowledged,
-				})
+		up.UpdateUserProfiles(userProfiles, time.Now())
+		reportApplyStatus(update, errors, applyStateCallback)
+	}
+	return onUpdate
+}
+
+// makeOnDefaultUpdate generates an onUpdate function suitable for
+// rcclient.Component.Subscribe that will update the default profiles of the
+// given UpdatableProvider whenever the RC client receives new profiles
+// maintained by Datadog. The received profiles are merged with the profiles
+// bundled with the agent, so that new device models are supported without
+// upgrading the agent.
+func makeOnDefaultUpdate(up *UpdatableProvider, bundledProfiles ProfileConfigMap) func(map[string]state.RawConfig, func(string, state.ApplyStatus)) {
+	onUpdate := func(update map[string]state.RawConfig, applyStateCallback func(string, state.ApplyStatus)) {
+		log.Infof("Received %d Datadog device profiles via remote configuration", len(update))
+		remoteProfiles, errors := unpackRawConfigs(update, false)
+		// update is a dict of ALL current Datadog profiles, an empty update restores the bundled profiles.
+		up.UpdateDefaultProfiles(mergeDefaultProfiles(bundledProfiles, remoteProfiles), time.Now())
+		reportApplyStatus(update, errors, applyStateCallback)
+	}
+	return onUpdate
+}
+
+// mergeDefaultProfiles returns the bundled profiles updated with the remote
+// profiles. A remote profile replaces the bundled profile with the same name,
+// and takes over the sysObjectIDs it declares: they are removed from the other
+// bundled profiles so that the auto-detection picks the remote profile instead
+// of failing on duplicate sysObjectIDs.
+func mergeDefaultProfiles(bundledProfiles, remoteProfiles ProfileConfigMap) ProfileConfigMap {
+	remoteSysObjectIDs := make(map[string]string)
+	for name, profile := range remoteProfiles {
+		for _, sysObjectID := range profile.Definition.SysObjectIDs {
+			remoteSysObjectIDs[sysObjectID] = name
+		}
+	}
+
+	merged := make(ProfileConfigMap, len(bundledProfiles)+len(remoteProfiles))
+	for name, profile := range bundledProfiles {
+		if _, ok := remoteProfiles[name]; ok {
+			continue
+		}
+		if !slices.ContainsFunc(profile.Definition.SysObjectIDs, func(sysObjectID string) bool {
+			_, ok := remoteSysObjectIDs[sysObjectID]
+			return ok
+		}) {
+			merged[name] = profile
+			continue
+		}
+
+		profile = profile.Clone()
+		profile.Definition.SysObjectIDs = slices.DeleteFunc(profile.Definition.SysObjectIDs, func(sysObjectID string) bool {
+			if remoteName, ok := remoteSysObjectIDs[sysObjectID]; ok {
+				log.Debugf("sysObjectID %s of bundled profile %q is now handled by the remote profile %q", sysObjectID, name, remoteName)
+				return true
 			}
+			return false
+		})
+		merged[name] = profile
+	}
+	maps.Copy(merged, remoteProfiles)
+	return merged
+}
+
+// reportApplyStatus acknowledges the configs of the update, or reports their error
+func reportApplyStatus(update map[string]state.RawConfig, errors map[string]error, applyStateCallback func(string, state.ApplyStatus)) {
+	for k := range update {
+		if errors[k] != nil {
+			applyStateCallback(k, state.ApplyStatus{
+				State: state.ApplyStateError,
+				Error: errors[k].Error(),
+			})
+		} else {
+			applyStateCallback(k, state.ApplyStatus{
+				State: state.ApplyStateAcknowledged,
+			})
 		}
 	}
-	return onUpdate
 }
@@ -9,7 +9,9 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/networkdevice/profile/profiledefinition"
 	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func TestUnpackRawConfigs(t *testing.T) {
@@ -98,7 +100,7 @@ func TestUnpackRawConfigs(t *testing.T) {
 		},
 	}} {
 		t.Run(tc.name, func(t *testing.T) {
-			profiles, errors := unpackRawConfigs(tc.configs)
+			profiles, errors := unpackRawConfigs(tc.configs, true)
 			assert.Equal(t, tc.expectedProfiles, profiles)
 			for k, v := range tc.expectedErrors {
 				err := errors[k]
@@ -112,3 +114,88 @@ func TestUnpackRawConfigs(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeDefaultProfiles(t *testing.T) {
+	bundledProfiles := ProfileConfigMap{
+		"_base": ProfileConfig{},
+		"device-a": ProfileConfig{Definition: profiledefinition.ProfileDefinition{
+			SysObjectIDs: profiledefinition.StringArray{"1.1.*", "1.2.*"},
+		}},
+		"device-b": ProfileConfig{Definition: profiledefinition.ProfileDefinition{
+			SysObjectIDs: profiledefinition.StringArray{"2.*"},
+		}},
+	}.withNames()
+	remoteProfiles := ProfileConfigMap{
+		"device-b": ProfileConfig{Definition: profiledefinition.ProfileDefinition{
+			SysObjectIDs: profiledefinition.StringArray{"2.1.*"},
+		}},
+		"device-c": ProfileConfig{Definition: profiledefinition.ProfileDefinition{
+			SysObjectIDs: profiledefinition.StringArray{"1.2.*"},
+		}},
+	}.withNames()
+
+	merged := mergeDefaultProfiles(bundledProfiles, remoteProfiles)
+
+	sysObjectIDs := make(map[string]profiledefinition.StringArray)
+	for name, profile := range merged {
+		sysObjectIDs[name] = profile.Definition.SysObjectIDs
+	}
+	assert.Equal(t, map[string]profiledefinition.StringArray{
+		"_base":    nil,
+		"device-a": {"1.1.*"},
+		"device-b": {"2.1.*"},
+		"device-c": {"1.2.*"},
+	}, sysObjectIDs)
+	// the bundled profiles are not modified
+	assert.Equal(t, profiledefinition.StringArray{"1.1.*", "1.2.*"}, bundledProfiles["device-a"].Definition.SysObjectIDs)
+}
+
+func TestMakeOnDefaultUpdate(t *testing.T) {
+	bundledProfiles := ProfileConfigMap{
+		"old-device": ProfileConfig{Definition: profiledefinition.ProfileDefinition{
+			SysObjectIDs: profiledefinition.StringArray{"1.3.6.1.4.1.32473.1.*"},
+			Metrics:      makeMetrics("1.2.1.0"),
+		}},
+	}.withNames()
+	up := &UpdatableProvider{}
+	up.Update(ProfileConfigMap{}, bundledProfiles, time.Now().Add(-time.Minute))
+	onUpdate := makeOnDefaultUpdate(up, bundledProfiles)
+
+	applied := make(map[string]state.ApplyStatus)
+	applyStateCallback := func(k string, status state.ApplyStatus) {
+		applied[k] = status
+	}
+
+	_, err := up.GetProfileForSysObjectID("1.3.6.1.4.1.32473.2.1")
+	assert.Error(t, err)
+
+	lastUpdated := up.LastUpdated()
+	onUpdate(map[string]state.RawConfig{
+		"new-device": {Config: []byte(`{
+			"profile_definition": {
+				"name": "new-device",
+				"sysobjectid": ["1.3.6.1.4.1.32473.2.*"],
+				"metrics": [{"symbol": {"OID": "1.2.3.0", "name": "someMetric"}}]
+			}
+		}`)},
+		"broken": {Config: []byte(`{"profile_definition": [not valid json]}`)},
+	}, applyStateCallback)
+	assert.Equal(t, state.ApplyStateAcknowledged, applied["new-device"].State)
+	assert.Equal(t, state.ApplyStateError, applied["broken"].State)
+	assert.True(t, up.LastUpdated().After(lastUpdated))
+
+	// the new device model is auto-detected, the bundled profiles are kept
+	profile, err := up.GetProfileForSysObjectID("1.3.6.1.4.1.32473.2.1")
+	require.NoError(t, err)
+	assert.Equal(t, "new-device", profile.Definition.Name)
+	assert.False(t, profile.IsUserProfile)
+	profile, err = up.GetProfileForSysObjectID("1.3.6.1.4.1.32473.1.1")
+	require.NoError(t, err)
+	assert.Equal(t, "old-device", profile.Definition.Name)
+
+	// removing the remote profiles restores the bundled profiles
+	onUpdate(map[string]state.RawConfig{}, applyStateCallback)
+	_, err = up.GetProfileForSysObjectID("1.3.6.1.4.1.32473.2.1")
+	assert.Error(t, err)
+	assert.True(t, up.HasProfile("old-device"))
+}
@@ -32,6 +32,24 @@ func (up *UpdatableProvider) Update(userProfiles, defaultProfiles ProfileConfigM
 	up.lastUpdated = now
 }
 
+// UpdateUserProfiles installs new user profiles, keeping the current default profiles.
+func (up *UpdatableProvider) UpdateUserProfiles(userProfiles ProfileConfigMap, now time.Time) {
+	up.lock.Lock()
+	defer up.lock.Unlock()
+	up.userProfiles = userProfiles
+	up.resolvedProfiles = resolveProfiles(up.userProfiles, up.defaultProfiles)
+	up.lastUpdated = now
+}
+
+// UpdateDefaultProfiles installs new default profiles, keeping the current user profiles.
+func (up *UpdatableProvider) UpdateDefaultProfiles(defaultProfiles ProfileConfigMap, now time.Time) {
+	up.lock.Lock()
+	defer up.lock.Unlock()
+	up.defaultProfiles = defaultProfiles
+	up.resolvedProfiles = resolveProfiles(up.userProfiles, up.defaultProfiles)
+	up.lastUpdated = now
+}
+
 // HasProfile implements Provider.HasProfile
 func (up *UpdatableProvider) HasProfile(profileName string) bool {
 	up.lock.RLock()
@@ -0,0 +1,176 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package python
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// checkBudget holds the resources a Python check instance is allowed to use
+type checkBudget struct {
+	// maxMemoryGrowth is the maximum growth of the memory allocated by the interpreter
+	// during the runs of a check instance, in bytes. 0 disables the memory budget.
+	maxMemoryGrowth int64
+	// maxRunTime is the maximum wall time of a single run. 0 disables the run time budget.
+	maxRunTime time.Duration
+	// maxRunTimeViolations is the number of consecutive runs over maxRunTime after which
+	// the check instance is quarantined
+	maxRunTimeViolations int
+}
+
+func newCheckBudget(config model.Reader) checkBudget {
+	return checkBudget{
+		maxMemoryGrowth:      config.GetInt64("python_check_budget.max_memory_growth"),
+		maxRunTime:           config.GetDuration("python_check_budget.max_run_time"),
+		maxRunTimeViolations: config.GetInt("python_check_budget.max_run_time_violations"),
+	}
+}
+
+// QuarantinedCheck describes a check instance which is not run anymore because it exceeded its budget
+type QuarantinedCheck struct {
+	CheckID string    `json:"check_id"`
+	Reason  string    `json:"reason"`
+	Since   time.Time `json:"since"`
+}
+
+type checkUsage struct {
+	runs              int
+	memoryGrowth      int64
+	runTimeViolations int
+	quarantine        *QuarantinedCheck
+}
+
+// resourceGovernor tracks the memory growth and the run time of the Python check instances,
+// and quarantines the ones exceeding their budget so that a single leaking or stuck check
+// can't take the whole agent down. The runs lasting longer than the run time budget are
+// interrupted by a watchdog.
+//
+// The memory is accounted per check: the allocations of the interpreter are counted per thread,
+// and a check runs on a thread of its own during a run, so the growth of the memory allocated by
+// the thread during a run is attributed to the check. The memory allocated by the threads the
+// check starts isn't accounted for. The first run of an instance isn't accounted for either, as
+// it usually allocates caches and imports modules.
+type resourceGovernor struct {
+	budget checkBudget
+
+	mu     sync.Mutex
+	checks map[checkid.ID]*checkUsage
+}
+
+func newResourceGovernor(budget checkBudget) *resourceGovernor {
+	return &resourceGovernor{
+		budget: budget,
+		checks: make(map[checkid.ID]*checkUsage),
+	}
+}
+
+// recordRun accounts for a run of the check instance, which took runTime and during which the
+// interpreter memory grew by memoryGrowth bytes, and quarantines the check if needed
+func (g *resourceGovernor) recordRun(id checkid.ID, runTime time.Duration, memoryGrowth int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	usage, found := g.checks[id]
+	if !found {
+		usage = &checkUsage{}
+		g.checks[id] = usage
+	}
+	if usage.quarantine != nil {
+		return
+	}
+
+	usage.runs++
+	if usage.runs > 1 {
+		// memory freed by the check is deducted, but the check doesn't get credit for
+		// memory it didn't allocate
+		usage.memoryGrowth = max(usage.memoryGrowth+memoryGrowth, 0)
+	}
+
+	if g.budget.maxRunTime > 0 && runTime > g.budget.maxRunTime {
+		usage.runTimeViolations++
+	} else {
+		usage.runTimeViolations = 0
+	}
+
+	var reason string
+	switch {
+	case g.budget.maxMemoryGrowth > 0 && usage.memoryGrowth > g.budget.maxMemoryGrowth:
+		reason = fmt.Sprintf("the interpreter memory grew by %d bytes during its runs, over the budget of %d bytes",
+			usage.memoryGrowth, g.budget.maxMemoryGrowth)
+	case g.budget.maxRunTime > 0 && usage.runTimeViolations >= max(g.budget.maxRunTimeViolations, 1):
+		reason = fmt.Sprintf("its last %d runs took longer than the budget of %s",
+			usage.runTimeViolations, g.budget.maxRunTime)
+	default:
+		return
+	}
+
+	log.Warnf("Quarantining python check %s: %s", id, reason)
+	usage.quarantine = &QuarantinedCheck{
+		CheckID: string(id),
+		Reason:  reason,
+		Since:   time.Now(),
+	}
+}
+
+// quarantined returns the quarantine of the check instance, if any
+func (g *resourceGovernor) quarantined(id checkid.ID) (QuarantinedCheck, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if usage, found := g.checks[id]; found && usage.quarantine != nil {
+		return *usage.quarantine, true
+	}
+	return QuarantinedCheck{}, false
+}
+
+// forget drops the usage and the quarantine of an unscheduled check instance
+func (g *resourceGovernor) forget(id checkid.ID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.checks, id)
+}
+
+// quarantinedChecks returns the quarantined check instances, sorted by ID
+func (g *resourceGovernor) quarantinedChecks() []QuarantinedCheck {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	quarantined := []QuarantinedCheck{}
+	for _, usage := range g.checks {
+		if usage.quarantine != nil {
+			quarantined = append(quarantined, *usage.quarantine)
+		}
+	}
+	sort.Slice(quarantined, func(i, j int) bool { return quarantined[i].CheckID < quarantined[j].CheckID })
+	return quarantined
+}
+
+// errQuarantined is returned when running a quarantined check instance
+type errQuarantined struct {
+	quarantine QuarantinedCheck
+}
+
+func (e errQuarantined) Error() string {
+	return fmt.Sprintf("check quarantined since %s because %s", e.quarantine.Since.Format(time.RFC3339), e.quarantine.Reason)
+}
+
+// checkGovernor enforces the resource budgets of the Python checks, nil if budgets are disabled
+var checkGovernor *resourceGovernor
+
+func expvarQuarantinedChecks() interface{} {
+	if checkGovernor == nil {
+		return []QuarantinedCheck{}
+	}
+	return checkGovernor.quarantinedChecks()
+}
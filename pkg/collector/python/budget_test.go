@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package python
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGovernorMemoryGrowth(t *testing.T) {
+	g := newResourceGovernor(checkBudget{maxMemoryGrowth: 100})
+
+	// the first run isn't accounted for
+	g.recordRun("leaky", time.Second, 1000)
+	_, quarantined := g.quarantined("leaky")
+	assert.False(t, quarantined)
+
+	g.recordRun("leaky", time.Second, 60)
+	g.recordRun("leaky", time.Second, -20)
+	_, quarantined = g.quarantined("leaky")
+	assert.False(t, quarantined)
+
+	g.recordRun("leaky", time.Second, 70)
+	quarantine, quarantined := g.quarantined("leaky")
+	require.True(t, quarantined)
+	assert.Equal(t, "leaky", quarantine.CheckID)
+	assert.Contains(t, quarantine.Reason, "grew by 110 bytes")
+	assert.ErrorContains(t, errQuarantined{quarantine: quarantine}, "check quarantined since")
+}
+
+func TestGovernorMemoryGrowthNoCredit(t *testing.T) {
+	g := newResourceGovernor(checkBudget{maxMemoryGrowth: 100})
+
+	g.recordRun("check", time.Second, 0)
+	// memory freed during a run, e.g. by a garbage collection, doesn't offset later growth
+	g.recordRun("check", time.Second, -500)
+	g.recordRun("check", time.Second, 150)
+	_, quarantined := g.quarantined("check")
+	assert.True(t, quarantined)
+}
+
+func TestGovernorRunTime(t *testing.T) {
+	g := newResourceGovernor(checkBudget{maxRunTime: time.Minute, maxRunTimeViolations: 2})
+
+	g.recordRun("slow", 2*time.Minute, 0)
+	// a run within budget resets the violations
+	g.recordRun("slow", time.Second, 0)
+	g.recordRun("slow", 2*time.Minute, 0)
+	_, quarantined := g.quarantined("slow")
+	assert.False(t, quarantined)
+
+	g.recordRun("slow", 2*time.Minute, 0)
+	quarantine, quarantined := g.quarantined("slow")
+	require.True(t, quarantined)
+	assert.Contains(t, quarantine.Reason, "last 2 runs took longer than the budget of 1m0s")
+}
+
+func TestGovernorDisabledBudgets(t *testing.T) {
+	g := newResourceGovernor(checkBudget{})
+
+	for i := 0; i < 10; i++ {
+		g.recordRun("check", time.Hour, 1<<30)
+	}
+	_, quarantined := g.quarantined("check")
+	assert.False(t, quarantined)
+}
+
+func TestGovernorForget(t *testing.T) {
+	g := newResourceGovernor(checkBudget{maxRunTime: time.Minute, maxRunTimeViolations: 1})
+
+	g.recordRun("b", 2*time.Minute, 0)
+	g.recordRun("a", 2*time.Minute, 0)
+	g.recordRun("c", time.Second, 0)
+
+	quarantined := g.quarantinedChecks()
+	require.Len(t, quarantined, 2)
+	assert.Equal(t, "a", quarantined[0].CheckID)
+	assert.Equal(t, "b", quarantined[1].CheckID)
+
+	g.forget("a")
+	_, found := g.quarantined("a")
+	assert.False(t, found)
+	assert.Len(t, g.quarantinedChecks(), 1)
+}
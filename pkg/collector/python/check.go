@@ -15,6 +15,7 @@ import (
 	"runtime"
 	"runtime/pprof"
 	"strings"
+	"sync"
 	"time"
 	"unsafe"
 
@@ -91,6 +92,17 @@ func NewPythonCheck(senderManager sender.SenderManager, name string, class *C.rt
 }
 
 func (c *PythonCheck) runCheckImpl(commitMetrics bool) error {
+	// long running checks never return from their run, so they can't be budgeted
+	governor := checkGovernor
+	if c.interval == 0 {
+		governor = nil
+	}
+	if governor != nil {
+		if quarantine, quarantined := governor.quarantined(c.id); quarantined {
+			return errQuarantined{quarantine: quarantine}
+		}
+	}
+
 	// Lock the GIL and release it at the end of the run
 	gstate, err := newStickyLock()
 	if err != nil {
@@ -100,7 +112,26 @@ func (c *PythonCheck) runCheckImpl(commitMetrics bool) error {
 
 	log.Debugf("Running python check %s (version: '%s', id: '%s')", c.ModuleName, c.version, c.id)
 
+	// the check runs on this thread, whose allocations are accounted separately
+	var memBefore, memAfter C.pymem_stats_t
+	var watchdog *runWatchdog
+	if governor != nil {
+		C.get_pymem_thread_stats(rtloader, &memBefore)
+		if governor.budget.maxRunTime > 0 {
+			watchdog = startRunWatchdog(c.id, C.get_thread_id(rtloader), governor.budget.maxRunTime)
+		}
+	}
+	start := time.Now()
+
 	cResult := C.run_check(rtloader, c.instance)
+
+	if governor != nil {
+		if watchdog != nil {
+			watchdog.stop()
+		}
+		C.get_pymem_thread_stats(rtloader, &memAfter)
+		governor.recordRun(c.id, time.Since(start), int64(memAfter.inuse)-int64(memBefore.inuse))
+	}
 	if cResult == nil {
 		if err := getRtLoaderError(); err != nil {
 			return err
@@ -127,6 +158,57 @@ func (c *PythonCheck) runCheckImpl(commitMetrics bool) error {
 	return errors.New(checkErrStr)
 }
 
+// runWatchdog interrupts a check run lasting longer than the run time budget, by raising a TimeoutError in the
+// thread running the check. A run blocked in a C call, like a socket read, is interrupted once the call returns.
+type runWatchdog struct {
+	threadID C.ulong
+	timer    *time.Timer
+
+	mu          sync.Mutex
+	running     bool
+	interrupted bool
+}
+
+func startRunWatchdog(id checkid.ID, threadID C.ulong, maxRunTime time.Duration) *runWatchdog {
+	w := &runWatchdog{
+		threadID: threadID,
+		running:  true,
+	}
+	w.timer = time.AfterFunc(maxRunTime, func() {
+		// the GIL is acquired before the mutex, as stop is called with the GIL held
+		glock, err := newStickyLock()
+		if err != nil {
+			log.Warnf("Could not interrupt python check %s: %s", id, err)
+			return
+		}
+		defer glock.unlock()
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if !w.running {
+			return
+		}
+		if C.interrupt_thread(rtloader, w.threadID) == 1 {
+			w.interrupted = true
+			log.Warnf("Interrupting python check %s, its run is taking longer than the budget of %s", id, maxRunTime)
+		}
+	})
+	return w
+}
+
+// stop stops the watchdog once the run returned, it must be called with the GIL held
+func (w *runWatchdog) stop() {
+	w.timer.Stop()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.running = false
+	if w.interrupted {
+		// the run may have returned before the exception was raised, it mustn't be raised in a later run
+		C.clear_thread_interrupt(rtloader, w.threadID)
+	}
+}
+
 func (c *PythonCheck) runCheck(commitMetrics bool) error {
 	ctx := context.Background()
 	var err error
@@ -164,6 +246,10 @@ func (c *PythonCheck) Cancel() {
 	if err := getRtLoaderError(); err != nil {
 		log.Warnf("failed to cancel check %s: %s", c.id, err)
 	}
+
+	if checkGovernor != nil {
+		checkGovernor.forget(c.id)
+	}
 }
 
 // String representation (for debug and logging)
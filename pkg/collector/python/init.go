@@ -428,6 +428,12 @@ func Initialize(paths ...string) error {
 		}
 	}
 
+	// Should we enforce resource budgets on python checks?
+	if pkgconfigsetup.Datadog().GetBool("python_check_budget.enabled") {
+		initPymemStats()
+		checkGovernor = newResourceGovernor(newCheckBudget(pkgconfigsetup.Datadog()))
+	}
+
 	// Set the PYTHONPATH if needed.
 	for _, p := range paths {
 		// bounded but never released allocations with CString
@@ -483,8 +489,17 @@ func GetRtLoader() *C.rtloader_t {
 	return rtloader
 }
 
+var pymemStatsOnce sync.Once
+
+// initPymemStats installs the allocators tracking the memory of the interpreter, at most once
+func initPymemStats() {
+	pymemStatsOnce.Do(func() {
+		C.init_pymem_stats(rtloader)
+	})
+}
+
 func initPymemTelemetry(d time.Duration) {
-	C.init_pymem_stats(rtloader)
+	initPymemStats()
 
 	// "alloc" for consistency with go memstats and mallochook metrics.
 	alloc := telemetry.NewSimpleCounter("pymem", "alloc", "Total number of bytes allocated by the python interpreter since the start of the agent.")
@@ -74,6 +74,7 @@ func init() {
 	pyLoaderStats = expvar.NewMap("pyLoader")
 	pyLoaderStats.Set("ConfigureErrors", expvar.Func(expvarConfigureErrors))
 	pyLoaderStats.Set("Py3Warnings", expvar.Func(expvarPy3Warnings))
+	pyLoaderStats.Set("QuarantinedChecks", expvar.Func(expvarQuarantinedChecks))
 
 	agentVersionTags = []string{}
 	if agentVersion, err := version.Agent(); err == nil {
@@ -8,6 +8,7 @@ package expvars
 
 import (
 	"expvar"
+	"sort"
 	"sync"
 	"time"
 
@@ -25,12 +26,17 @@ const (
 	runnerExpvarKey = "runner"
 
 	// Nested keys
-	checksExpvarKey        = "Checks"
-	errorsExpvarKey        = "Errors"
-	runningChecksExpvarKey = "RunningChecks"
-	runsExpvarKey          = "Runs"
-	runningExpvarKey       = "Running"
-	warningsExpvarKey      = "Warnings"
+	checksExpvarKey               = "Checks"
+	errorsExpvarKey               = "Errors"
+	runningChecksExpvarKey        = "RunningChecks"
+	runsExpvarKey                 = "Runs"
+	runningExpvarKey              = "Running"
+	warningsExpvarKey             = "Warnings"
+	topResourceConsumersExpvarKey = "TopResourceConsumers"
+
+	// topResourceConsumersCount is the number of check instances listed in the
+	// top resource consumers
+	topResourceConsumersCount = 5
 )
 
 var (
@@ -50,6 +56,7 @@ func init() {
 
 	runnerStats = expvar.NewMap(runnerExpvarKey)
 	runnerStats.Set(checksExpvarKey, expvar.Func(expCheckStatsFunc))
+	runnerStats.Set(topResourceConsumersExpvarKey, expvar.Func(expTopResourceConsumersFunc))
 	runnerStats.Set(runningExpvarKey, runningChecksStats)
 
 	newWorkersExpvar(runnerStats)
@@ -65,6 +72,10 @@ func expCheckStatsFunc() interface{} {
 	return GetCheckStats()
 }
 
+func expTopResourceConsumersFunc() interface{} {
+	return TopResourceConsumers(topResourceConsumersCount)
+}
+
 // Reset clears all stats collected so far (useful in testing)
 func Reset() {
 	log.Warnf("Resetting all check stats")
@@ -111,6 +122,7 @@ func AddCheckStats(c check.Check,
 	err error,
 	warnings []error,
 	mStats checkstats.SenderStats,
+	usage checkstats.ResourceUsage,
 	haagent haagent.Component,
 ) {
 
@@ -135,6 +147,52 @@ func AddCheckStats(c check.Check,
 	}
 
 	s.Add(execTime, err, warnings, mStats, haagent)
+	s.AddResourceUsage(usage)
+}
+
+// ResourceConsumer is a check instance, with the resources used by its runs
+type ResourceConsumer struct {
+	CheckName           string
+	CheckID             checkid.ID
+	TotalCPUTime        int64 // in milliseconds
+	TotalAllocatedBytes uint64
+}
+
+// TopResourceConsumers returns the n check instances which used the most CPU
+// time since they were scheduled, then the most memory
+func TopResourceConsumers(n int) []ResourceConsumer {
+	checkStats.statsLock.RLock()
+	defer checkStats.statsLock.RUnlock()
+
+	var consumers []ResourceConsumer
+	for _, stats := range checkStats.stats {
+		for id, s := range stats {
+			if s.TotalCPUTime == 0 && s.TotalAllocatedBytes == 0 {
+				continue
+			}
+			consumers = append(consumers, ResourceConsumer{
+				CheckName:           s.CheckName,
+				CheckID:             id,
+				TotalCPUTime:        s.TotalCPUTime,
+				TotalAllocatedBytes: s.TotalAllocatedBytes,
+			})
+		}
+	}
+
+	sort.Slice(consumers, func(i, j int) bool {
+		if consumers[i].TotalCPUTime != consumers[j].TotalCPUTime {
+			return consumers[i].TotalCPUTime > consumers[j].TotalCPUTime
+		}
+		if consumers[i].TotalAllocatedBytes != consumers[j].TotalAllocatedBytes {
+			return consumers[i].TotalAllocatedBytes > consumers[j].TotalAllocatedBytes
+		}
+		return consumers[i].CheckID < consumers[j].CheckID
+	})
+
+	if len(consumers) > n {
+		consumers = consumers[:n]
+	}
+	return consumers
 }
 
 // RemoveCheckStats removes a check from the check stats map
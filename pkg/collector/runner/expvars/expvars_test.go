@@ -172,7 +172,7 @@ func TestExpvarsReset(t *testing.T) {
 			testCheck := newTestCheck(checkID)
 
 			for runIdx := 0; runIdx < numCheckRuns; runIdx++ {
-				AddCheckStats(testCheck, 12345, nil, []error{}, stats.SenderStats{}, haagentmock.NewMockHaAgent())
+				AddCheckStats(testCheck, 12345, nil, []error{}, stats.SenderStats{}, stats.ResourceUsage{}, haagentmock.NewMockHaAgent())
 			}
 		}
 	}
@@ -244,7 +244,7 @@ func TestExpvarsCheckStats(t *testing.T) {
 
 					<-start
 
-					AddCheckStats(testCheck, duration, err, warnings, expectedStats, haagentmock.NewMockHaAgent())
+					AddCheckStats(testCheck, duration, err, warnings, expectedStats, stats.ResourceUsage{}, haagentmock.NewMockHaAgent())
 
 					actualStats, found := CheckStats(testCheck.ID())
 					require.True(t, found)
@@ -320,7 +320,7 @@ func TestExpvarsGetChecksStatsClone(t *testing.T) {
 			testCheck := newTestCheck(checkID)
 
 			for runIdx := 0; runIdx < numCheckRuns; runIdx++ {
-				AddCheckStats(testCheck, 12345, nil, []error{}, stats.SenderStats{}, haagentmock.NewMockHaAgent())
+				AddCheckStats(testCheck, 12345, nil, []error{}, stats.SenderStats{}, stats.ResourceUsage{}, haagentmock.NewMockHaAgent())
 			}
 		}
 	}
@@ -339,6 +339,34 @@ func TestExpvarsGetChecksStatsClone(t *testing.T) {
 	assert.Equal(t, numCheckInstances, len(getCheckStatsExpvarMap(t)["testcheck1"]))
 }
 
+func TestTopResourceConsumers(t *testing.T) {
+	setUp()
+
+	for idx, usage := range []stats.ResourceUsage{
+		{CPUTime: 20 * time.Millisecond, AllocatedBytes: 100},
+		{CPUTime: 50 * time.Millisecond, AllocatedBytes: 10},
+		{AllocatedBytes: 1000},
+		{},
+		{CPUTime: 20 * time.Millisecond, AllocatedBytes: 200},
+	} {
+		testCheck := newTestCheck(fmt.Sprintf("testcheck:%d", idx))
+		AddCheckStats(testCheck, 12345, nil, []error{}, stats.SenderStats{}, usage, haagentmock.NewMockHaAgent())
+		AddCheckStats(testCheck, 12345, nil, []error{}, stats.SenderStats{}, usage, haagentmock.NewMockHaAgent())
+	}
+
+	assert.Equal(t, []ResourceConsumer{
+		{CheckName: "testcheck", CheckID: "testcheck:1", TotalCPUTime: 100, TotalAllocatedBytes: 20},
+		{CheckName: "testcheck", CheckID: "testcheck:4", TotalCPUTime: 40, TotalAllocatedBytes: 400},
+		{CheckName: "testcheck", CheckID: "testcheck:0", TotalCPUTime: 40, TotalAllocatedBytes: 200},
+	}, TopResourceConsumers(3))
+	assert.Len(t, TopResourceConsumers(10), 4)
+
+	checkStats, found := CheckStats("testcheck:1")
+	require.True(t, found)
+	assert.Equal(t, int64(50), checkStats.LastCPUTime)
+	assert.Equal(t, uint64(10), checkStats.LastAllocatedBytes)
+}
+
 func TestExpvarsRunningStats(t *testing.T) {
 	loc, err := time.LoadLocation("America/New_York")
 	require.Nil(t, err)
@@ -425,7 +453,7 @@ func TestGetCheckStatsRace(t *testing.T) {
 
 					warnings := []error{errors.New("error1"), errors.New("error2"), errors.New("error3")}
 					for runIdx := 0; runIdx < numCheckRuns; runIdx++ {
-						AddCheckStats(testCheck, 12345, nil, warnings, stats.SenderStats{}, haagentmock.NewMockHaAgent())
+						AddCheckStats(testCheck, 12345, nil, warnings, stats.SenderStats{}, stats.ResourceUsage{}, haagentmock.NewMockHaAgent())
 					}
 				}
 			}
@@ -51,6 +51,7 @@ type Runner struct {
 	checksTracker       *tracker.RunningChecksTracker // Tracker in charge of maintaining the running check list
 	scheduler           *scheduler.Scheduler          // Scheduler runner operates on
 	schedulerLock       sync.RWMutex                  // Lock around operations on the scheduler
+	replacementWorkers  int                           // Workers added by AddReplacementWorker not retired yet
 }
 
 // NewRunner takes the number of desired goroutines processing incoming checks.
@@ -116,6 +117,47 @@ func (r *Runner) AddWorker() {
 	}
 }
 
+// AddReplacementWorker adds a worker to replace the workers stuck running checks, up to MaxNumWorkers workers. It is
+// used to restart the runner when the scheduler can't hand the checks over to the workers anymore. The check
+// goroutines can't be interrupted, so the stuck workers are left running and a worker is retired instead once the
+// pool has a spare worker again, see retireWorker.
+func (r *Runner) AddReplacementWorker() error {
+	r.workersLock.Lock()
+	defer r.workersLock.Unlock()
+
+	if len(r.workers) >= pkgconfigsetup.MaxNumWorkers {
+		return fmt.Errorf("runner %d already has %d workers", r.id, len(r.workers))
+	}
+
+	worker, err := r.newWorker()
+	if err != nil {
+		return err
+	}
+	r.workers[worker.ID] = worker
+	r.replacementWorkers++
+
+	log.Warnf("Runner %d added a worker to replace the workers stuck running checks (total: %d)", r.id, len(r.workers))
+	return nil
+}
+
+// retireWorker is called by the workers after each check run. It returns true, removing the worker from the pool,
+// when a replacement worker was added and the other workers are enough to run the running checks with a spare one,
+// i.e. when the workers which were stuck are available again.
+func (r *Runner) retireWorker(id int) bool {
+	r.workersLock.Lock()
+	defer r.workersLock.Unlock()
+
+	if r.replacementWorkers == 0 || len(r.checksTracker.RunningChecks()) >= len(r.workers)-1 {
+		return false
+	}
+
+	delete(r.workers, id)
+	r.replacementWorkers--
+
+	log.Infof("Runner %d retired a replacement worker (total: %d)", r.id, len(r.workers))
+	return true
+}
+
 // addWorker adds a new worker running in a separate goroutine
 func (r *Runner) newWorker() (*worker.Worker, error) {
 	worker, err := worker.NewWorker(
@@ -132,6 +174,10 @@ func (r *Runner) newWorker() (*worker.Worker, error) {
 		return nil, err
 	}
 
+	worker.ShouldRetire = func() bool {
+		return r.retireWorker(worker.ID)
+	}
+
 	go func() {
 		defer r.removeWorker(worker.ID)
 
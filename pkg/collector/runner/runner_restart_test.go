@@ -0,0 +1,82 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	haagentmock "github.com/DataDog/datadog-agent/comp/haagent/mock"
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/collector/runner/expvars"
+	"github.com/DataDog/datadog-agent/pkg/collector/scheduler"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/status/health"
+)
+
+// TestSchedulerStuckQueueRestartsRunner checks that a queue of the scheduler stuck because the only worker runs a
+// stuck check gets a replacement worker from the health supervisor, and that the pool shrinks back once the stuck
+// check is done.
+func TestSchedulerStuckQueueRestartsRunner(t *testing.T) {
+	testSetUp(t)
+	pkgconfigsetup.Datadog().SetWithoutSource("check_runners", "1")
+	health.SetRestartsEnabled(true)
+	defer health.SetRestartsEnabled(false)
+
+	r := NewRunner(aggregator.NewNoOpSenderManager(), haagentmock.NewMockHaAgent())
+	require.NotNil(t, r)
+	defer r.Stop()
+	assertAsyncWorkerCount(t, 1)
+
+	s := scheduler.NewScheduler(r.GetChan())
+	r.SetScheduler(s)
+	s.SetRunnerRestarter(r.AddReplacementWorker)
+	s.Run()
+	defer s.Stop() //nolint:errcheck
+
+	stuck := newCheck(t, "stuck:123", false, nil)
+	stuck.RunLock.Lock()
+	other := newCheck(t, "other:123", false, nil)
+	require.NoError(t, s.Enter(stuck))
+	require.NoError(t, s.Enter(other))
+
+	// the checks notify each of their runs, don't let the notifications block them
+	done := make(chan struct{})
+	defer close(done)
+	for _, c := range []*testCheck{stuck, other} {
+		go func(started chan struct{}) {
+			for {
+				select {
+				case <-started:
+				case <-done:
+					return
+				}
+			}
+		}(c.StartedChan())
+	}
+
+	// the only worker is stuck, so the queue can't hand the other check over
+	assert.Eventually(t, func() bool { return stuck.RunCount() == 0 && len(r.checksTracker.RunningChecks()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(1500 * time.Millisecond)
+	assert.Zero(t, other.RunCount())
+
+	// the queue is unhealthy for consecutive pings, the supervisor restarts the runner by adding a worker
+	assert.Eventually(t, func() bool {
+		health.SuperviseLivenessNow()
+		return other.RunCount() > 0
+	}, 10*time.Second, 100*time.Millisecond)
+	assert.Equal(t, 2, expvars.GetWorkerCount())
+
+	// once the stuck check is done, the pool shrinks back
+	stuck.RunLock.Unlock()
+	assertAsyncWorkerCount(t, 1)
+	assert.Eventually(t, func() bool { return other.RunCount() > 1 }, 5*time.Second, 100*time.Millisecond)
+}
@@ -7,6 +7,7 @@ package runner
 
 import (
 	"fmt"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -178,6 +179,22 @@ func TestRunnerAddWorker(t *testing.T) {
 	assertAsyncWorkerCount(t, 4)
 }
 
+func TestRunnerAddReplacementWorker(t *testing.T) {
+	testSetUp(t)
+	pkgconfigsetup.Datadog().SetWithoutSource("check_runners", strconv.Itoa(pkgconfigsetup.MaxNumWorkers-1))
+
+	r := NewRunner(aggregator.NewNoOpSenderManager(), haagentmock.NewMockHaAgent())
+	require.NotNil(t, r)
+	defer r.Stop()
+
+	require.NoError(t, r.AddReplacementWorker())
+	assertAsyncWorkerCount(t, pkgconfigsetup.MaxNumWorkers)
+
+	// the number of workers is capped
+	assert.Error(t, r.AddReplacementWorker())
+	assertAsyncWorkerCount(t, pkgconfigsetup.MaxNumWorkers)
+}
+
 func TestRunnerStaticUpdateNumWorkers(t *testing.T) {
 	testSetUp(t)
 	pkgconfigsetup.Datadog().SetWithoutSource("check_runners", "2")
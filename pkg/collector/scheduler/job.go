@@ -73,13 +73,13 @@ type jobQueue struct {
 	mu                  sync.RWMutex // to protect critical sections in struct's fields
 }
 
-// newJobQueue creates a new jobQueue instance
-func newJobQueue(interval time.Duration) *jobQueue {
+// newJobQueue creates a new jobQueue instance, the options are applied to its health check
+func newJobQueue(interval time.Duration, options ...health.Option) *jobQueue {
 	jq := &jobQueue{
 		interval:     interval,
 		stop:         make(chan bool),
 		stopped:      make(chan bool),
-		health:       health.RegisterLiveness(fmt.Sprintf("collector-queue-%vs", interval.Seconds())),
+		health:       health.RegisterLiveness(fmt.Sprintf("collector-queue-%vs", interval.Seconds()), options...),
 		bucketTicker: time.NewTicker(time.Second),
 	}
 
@@ -198,7 +198,7 @@ func (jq *jobQueue) process(s *Scheduler) bool {
 		log.Tracef("Jobs in bucket: %v", jobs)
 
 		for _, check := range jobs {
-			if !s.IsCheckScheduled(check.ID()) {
+			if !s.IsCheckScheduled(check.ID()) || s.IsCheckPaused(check.ID()) {
 				continue
 			}
 
@@ -6,6 +6,7 @@
 package scheduler
 
 import (
+	"errors"
 	"expvar"
 	"fmt"
 	"sync"
@@ -13,6 +14,7 @@ import (
 
 	"go.uber.org/atomic"
 
+	"github.com/DataDog/datadog-agent/pkg/status/health"
 	"github.com/DataDog/datadog-agent/pkg/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
@@ -57,8 +59,17 @@ type Scheduler struct {
 	// metadata provider can call 'IsCheckScheduled' without creating a deadlock.
 	checkToQueueMutex sync.RWMutex
 
+	// paused holds the checks whose scheduled runs are skipped. It is not cleared when a check is
+	// cancelled so that a check rescheduled with the same ID, e.g. on a config reload, stays paused.
+	paused      map[checkid.ID]struct{}
+	pausedMutex sync.RWMutex
+
 	cancelOneTime chan bool      // Used to internally communicate a cancel signal to one-time schedule goroutines
 	wgOneTime     sync.WaitGroup // WaitGroup to track the exit of one-time schedule goroutines
+
+	// runnerRestarter is called by the health supervisor when a queue is stuck, see SetRunnerRestarter
+	runnerRestarter     func() error
+	runnerRestarterLock sync.RWMutex
 }
 
 // NewScheduler create a Scheduler and returns a pointer to it.
@@ -71,6 +82,7 @@ func NewScheduler(checksPipe chan<- check.Check) *Scheduler {
 		jobQueues:        make(map[time.Duration]*jobQueue),
 		checkToQueue:     make(map[checkid.ID]*jobQueue),
 		tlmTrackedChecks: make(map[checkid.ID]string),
+		paused:           make(map[checkid.ID]struct{}),
 		running:          atomic.NewBool(false),
 		cancelOneTime:    make(chan bool),
 		wgOneTime:        sync.WaitGroup{},
@@ -97,7 +109,7 @@ func (s *Scheduler) Enter(check check.Check) error {
 	defer s.mu.Unlock()
 
 	if _, ok := s.jobQueues[check.Interval()]; !ok {
-		s.jobQueues[check.Interval()] = newJobQueue(check.Interval())
+		s.jobQueues[check.Interval()] = newJobQueue(check.Interval(), health.WithRestart(s.restartRunner))
 		s.startQueue(s.jobQueues[check.Interval()])
 		if check.IsTelemetryEnabled() {
 			tlmQueuesCount.Inc()
@@ -121,6 +133,26 @@ func (s *Scheduler) Enter(check check.Check) error {
 	return nil
 }
 
+// SetRunnerRestarter sets the function restarting the check runner. It is called by the health supervisor when a
+// queue is stuck because no worker of the runner is available to run its checks anymore.
+func (s *Scheduler) SetRunnerRestarter(restart func() error) {
+	s.runnerRestarterLock.Lock()
+	defer s.runnerRestarterLock.Unlock()
+
+	s.runnerRestarter = restart
+}
+
+func (s *Scheduler) restartRunner() error {
+	s.runnerRestarterLock.RLock()
+	restart := s.runnerRestarter
+	s.runnerRestarterLock.RUnlock()
+
+	if restart == nil {
+		return errors.New("no check runner to restart")
+	}
+	return restart()
+}
+
 // Cancel remove a Check from the scheduled queue. If the check is not
 // in the scheduler, this is a noop.
 func (s *Scheduler) Cancel(id checkid.ID) error {
@@ -218,6 +250,57 @@ func (s *Scheduler) IsCheckScheduled(id checkid.ID) bool {
 	return found
 }
 
+// Pause skips the scheduled runs of a check until Resume is called. Pausing a check
+// which is not in the schedule yet is allowed, it will be paused once it gets scheduled.
+func (s *Scheduler) Pause(id checkid.ID) {
+	s.pausedMutex.Lock()
+	defer s.pausedMutex.Unlock()
+
+	log.Infof("Pausing check %s", string(id))
+	s.paused[id] = struct{}{}
+}
+
+// Resume resumes the scheduled runs of a paused check, and returns whether the check was paused
+func (s *Scheduler) Resume(id checkid.ID) bool {
+	s.pausedMutex.Lock()
+	defer s.pausedMutex.Unlock()
+
+	if _, found := s.paused[id]; !found {
+		return false
+	}
+	log.Infof("Resuming check %s", string(id))
+	delete(s.paused, id)
+	return true
+}
+
+// IsCheckPaused returns whether the scheduled runs of a check are paused
+func (s *Scheduler) IsCheckPaused(id checkid.ID) bool {
+	s.pausedMutex.RLock()
+	defer s.pausedMutex.RUnlock()
+
+	_, found := s.paused[id]
+	return found
+}
+
+// PausedChecks returns the IDs of the paused checks
+func (s *Scheduler) PausedChecks() []checkid.ID {
+	s.pausedMutex.RLock()
+	defer s.pausedMutex.RUnlock()
+
+	ids := make([]checkid.ID, 0, len(s.paused))
+	for id := range s.paused {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// RunNow sends a check to the execution pipeline right away, regardless of its schedule and of
+// whether it is paused. The check keeps its regular schedule.
+func (s *Scheduler) RunNow(check check.Check) {
+	log.Infof("Scheduling check %v for immediate execution", check)
+	s.sendOnce(check)
+}
+
 // stopQueues shuts down the timers for each active queue
 // Blocks until all the queues have fully stopped
 func (s *Scheduler) stopQueues() {
@@ -261,6 +344,13 @@ func (s *Scheduler) startQueue(q *jobQueue) {
 // The queuing can be cancelled by closing the `cancelOneTime` channel.
 func (s *Scheduler) enqueueOnce(check check.Check) {
 	log.Infof("Scheduling check %v for one-time execution", check)
+	s.sendOnce(check)
+	schedulerChecksEntered.Add(1)
+}
+
+// sendOnce sends a check to the checksPipe without blocking the caller.
+// The sending can be cancelled by closing the `cancelOneTime` channel.
+func (s *Scheduler) sendOnce(check check.Check) {
 	s.wgOneTime.Add(1)
 
 	go func(cancelOneTime <-chan bool) {
@@ -270,8 +360,6 @@ func (s *Scheduler) enqueueOnce(check check.Check) {
 		case <-cancelOneTime:
 		}
 	}(s.cancelOneTime)
-
-	schedulerChecksEntered.Add(1)
 }
 
 // expQueues return a function to get the stats for the queues
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
 	"github.com/DataDog/datadog-agent/pkg/collector/check/stub"
 )
 
@@ -199,3 +200,72 @@ func TestStopOneTimeSchedule(t *testing.T) {
 	// sleep to make the runtime schedule the hanging goroutines, if there are any
 	time.Sleep(time.Millisecond)
 }
+
+func TestPauseResume(t *testing.T) {
+	s := getScheduler()
+	chk := &TestCheck{intl: 10 * time.Second}
+
+	assert.False(t, s.IsCheckPaused(chk.ID()))
+	assert.False(t, s.Resume(chk.ID()))
+
+	s.Pause(chk.ID())
+	assert.True(t, s.IsCheckPaused(chk.ID()))
+	assert.Equal(t, []checkid.ID{chk.ID()}, s.PausedChecks())
+
+	// the paused state outlives the schedule of the check, e.g. across a config reload
+	assert.NoError(t, s.Enter(chk))
+	assert.NoError(t, s.Cancel(chk.ID()))
+	assert.NoError(t, s.Enter(chk))
+	assert.True(t, s.IsCheckPaused(chk.ID()))
+
+	assert.True(t, s.Resume(chk.ID()))
+	assert.False(t, s.IsCheckPaused(chk.ID()))
+	assert.Empty(t, s.PausedChecks())
+}
+
+func TestPausedCheckIsNotEnqueued(t *testing.T) {
+	ch := make(chan check.Check, 10)
+	s := NewScheduler(ch)
+	defer s.Stop()
+
+	chk := &TestCheck{intl: 1 * time.Second}
+	s.Pause(chk.ID())
+	assert.NoError(t, s.Enter(chk))
+	s.Run()
+
+	select {
+	case <-ch:
+		assert.Fail(t, "a paused check should not be enqueued")
+	case <-time.After(1500 * time.Millisecond):
+	}
+
+	// running the check now bypasses the pause
+	s.RunNow(chk)
+	select {
+	case enqueued := <-ch:
+		assert.Equal(t, chk.ID(), enqueued.ID())
+	case <-time.After(time.Second):
+		assert.Fail(t, "the check should have been enqueued")
+	}
+
+	s.Resume(chk.ID())
+	select {
+	case enqueued := <-ch:
+		assert.Equal(t, chk.ID(), enqueued.ID())
+	case <-time.After(2 * time.Second):
+		assert.Fail(t, "a resumed check should be enqueued")
+	}
+}
+
+func TestRestartRunner(t *testing.T) {
+	s := getScheduler()
+	assert.Error(t, s.restartRunner())
+
+	restarts := 0
+	s.SetRunnerRestarter(func() error {
+		restarts++
+		return nil
+	})
+	assert.NoError(t, s.restartRunner())
+	assert.Equal(t, 1, restarts)
+}
@@ -33,7 +33,7 @@ func newTestCheck(id string) *stubCheck {
 }
 
 func addExpvarsCheckStats(c check.Check) {
-	expvars.AddCheckStats(c, 0, nil, nil, stats.SenderStats{}, haagentmock.NewMockHaAgent())
+	expvars.AddCheckStats(c, 0, nil, nil, stats.SenderStats{}, stats.ResourceUsage{}, haagentmock.NewMockHaAgent())
 }
 
 func setUp() {
@@ -14,6 +14,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
+	checkstats "github.com/DataDog/datadog-agent/pkg/collector/check/stats"
 	"github.com/DataDog/datadog-agent/pkg/collector/runner/expvars"
 	"github.com/DataDog/datadog-agent/pkg/collector/runner/tracker"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
@@ -55,6 +56,10 @@ type Worker struct {
 	shouldAddCheckStatsFunc func(id checkid.ID) bool
 	utilizationTickInterval time.Duration
 	haAgent                 haagent.Component
+
+	// ShouldRetire, if set, is called after each check run and the worker stops processing checks when it
+	// returns true. It lets the runner shrink its pool of workers.
+	ShouldRetire func() bool
 }
 
 // NewWorker returns an instance of a `Worker` after parameter sanity checks are passed
@@ -162,8 +167,10 @@ func (w *Worker) Run() {
 		utilizationTracker.Started()
 
 		// Run the check
-		checkErr := check.Run()
-
+		var checkErr error
+		resourceUsage := checkstats.MeasureResources(func() {
+			checkErr = check.Run()
+		})
 		utilizationTracker.Finished()
 
 		expvars.DeleteRunningStats(check.ID())
@@ -213,11 +220,16 @@ func (w *Worker) Run() {
 			// otherwise only do so if the check is in the scheduler
 			if w.shouldAddCheckStatsFunc(check.ID()) {
 				sStats, _ := check.GetSenderStats()
-				expvars.AddCheckStats(check, time.Since(checkStartTime), checkErr, checkWarnings, sStats, w.haAgent)
+				expvars.AddCheckStats(check, time.Since(checkStartTime), checkErr, checkWarnings, sStats, resourceUsage, w.haAgent)
 			}
 		}
 
 		checkLogger.CheckFinished()
+
+		if w.ShouldRetire != nil && w.ShouldRetire() {
+			log.Debugf("Runner %d, worker %d: Retired", w.runnerID, w.ID)
+			break
+		}
 	}
 
 	log.Debugf("Runner %d, worker %d: Finished processing checks.", w.runnerID, w.ID)
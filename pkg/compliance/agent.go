@@ -67,6 +67,22 @@ type AgentOptions struct {
 	// defined.
 	ConfigDir string
 
+	// CustomBenchmarksDir is an optional directory holding user-provided
+	// benchmarks files and assets, Rego or XCCDF, that are run along with
+	// the ones of ConfigDir. Its content is loaded again before each run so
+	// that benchmarks can be added or updated at runtime.
+	CustomBenchmarksDir string
+
+	// RemoteBenchmarks is an optional source of user-provided benchmarks
+	// received through remote configuration. Like the custom benchmarks,
+	// they are loaded again before each run.
+	RemoteBenchmarks *RemoteBenchmarks
+
+	// OpenSCAPResultsDir is an optional directory holding XCCDF result files
+	// produced by an external OpenSCAP run, typically with `oscap xccdf eval
+	// --results`. Their rule results are reported as check events.
+	OpenSCAPResultsDir string
+
 	// Reporter is the output interface of the events that are gathered by the
 	// agent.
 	Reporter *LogReporter
@@ -243,6 +259,18 @@ func (a *Agent) Start() error {
 		wg.Done()
 	}()
 
+	if a.opts.RemoteBenchmarks != nil {
+		a.opts.RemoteBenchmarks.Start()
+	}
+
+	if a.opts.OpenSCAPResultsDir != "" {
+		wg.Add(1)
+		go func() {
+			a.runOpenSCAPResultsIngestion(ctx)
+			wg.Done()
+		}()
+	}
+
 	for _, conf := range a.opts.EnabledConfigurationExporters {
 		switch conf {
 		case AptExporter:
@@ -282,6 +310,9 @@ func (a *Agent) Start() error {
 func (a *Agent) Stop() {
 	log.Tracef("shutting down compliance agent")
 	a.cancel()
+	if a.opts.RemoteBenchmarks != nil {
+		a.opts.RemoteBenchmarks.Stop()
+	}
 	select {
 	case <-time.After(20 * time.Second):
 	case <-a.finish:
@@ -291,14 +322,15 @@ func (a *Agent) Stop() {
 }
 
 func (a *Agent) runRegoBenchmarks(ctx context.Context) {
-	benchmarks, err := LoadBenchmarks(a.opts.ConfigDir, "*.yaml", func(r *Rule) bool {
+	regoRuleFilter := func(r *Rule) bool {
 		return r.IsRego() && a.opts.RuleFilter(r)
-	})
+	}
+	benchmarks, err := a.loadBenchmarks(regoRuleFilter)
 	if err != nil {
 		log.Warnf("could not load rego benchmarks: %v", err)
 		return
 	}
-	if len(benchmarks) == 0 {
+	if len(benchmarks) == 0 && !a.hasDynamicBenchmarks() {
 		log.Infof("no rego benchmark to run")
 		return
 	}
@@ -312,6 +344,9 @@ func (a *Agent) runRegoBenchmarks(ctx context.Context) {
 
 	log.Debugf("will be executing %d rego benchmarks every %s", len(benchmarks), checkInterval)
 	for runCount := uint64(0); ; runCount++ {
+		if runCount > 0 && a.hasDynamicBenchmarks() {
+			benchmarks = a.reloadBenchmarks(benchmarks, regoRuleFilter)
+		}
 		for _, benchmark := range benchmarks {
 			if sleepRandomJitter(ctx, checkInterval, runCount, a.opts.Hostname, benchmark.FrameworkID) {
 				return
@@ -347,14 +382,15 @@ func (a *Agent) runXCCDFBenchmarks(ctx context.Context) {
 	if !xccdfEnabled() {
 		return
 	}
-	benchmarks, err := LoadBenchmarks(a.opts.ConfigDir, "*.yaml", func(r *Rule) bool {
+	xccdfRuleFilter := func(r *Rule) bool {
 		return r.IsXCCDF() && a.opts.RuleFilter(r)
-	})
+	}
+	benchmarks, err := a.loadBenchmarks(xccdfRuleFilter)
 	if err != nil {
 		log.Warnf("could not load xccdf benchmarks: %v", err)
 		return
 	}
-	if len(benchmarks) == 0 {
+	if len(benchmarks) == 0 && !a.hasDynamicBenchmarks() {
 		log.Infof("no xccdf benchmark to run")
 		return
 	}
@@ -368,6 +404,9 @@ func (a *Agent) runXCCDFBenchmarks(ctx context.Context) {
 
 	log.Debugf("will be executing %d XCCDF benchmarks every %s", len(benchmarks), checkInterval)
 	for runCount := uint64(0); ; runCount++ {
+		if runCount > 0 && a.hasDynamicBenchmarks() {
+			benchmarks = a.reloadBenchmarks(benchmarks, xccdfRuleFilter)
+		}
 		for _, benchmark := range benchmarks {
 			if sleepRandomJitter(ctx, checkInterval, runCount, a.opts.Hostname, benchmark.FrameworkID) {
 				FinishXCCDFBenchmark(ctx, benchmark)
@@ -389,6 +428,73 @@ func (a *Agent) runXCCDFBenchmarks(ctx context.Context) {
 	}
 }
 
+// runOpenSCAPResultsIngestion periodically reports the results of the XCCDF
+// result files of the OpenSCAP results directory. All the files are reported
+// at each run, so that their findings don't expire while they are present.
+func (a *Agent) runOpenSCAPResultsIngestion(ctx context.Context) {
+	checkInterval := a.opts.CheckInterval
+	runTicker := time.NewTicker(checkInterval)
+	defer runTicker.Stop()
+
+	for runCount := uint64(0); ; runCount++ {
+		if sleepRandomJitter(ctx, checkInterval, runCount, a.opts.Hostname, "openscap-results") {
+			return
+		}
+		benchmarks, events, err := LoadOpenSCAPResults(a.opts.OpenSCAPResultsDir, a.opts.Hostname)
+		if err != nil {
+			log.Warnf("could not load OpenSCAP results: %v", err)
+		} else {
+			a.addBenchmarks(benchmarks...)
+			a.reportCheckEvents(checkInterval, events...)
+		}
+		if sleepAborted(ctx, runTicker.C) {
+			return
+		}
+	}
+}
+
+// hasDynamicBenchmarks returns whether benchmarks can be added at runtime,
+// through the custom benchmarks directory or remote configuration.
+func (a *Agent) hasDynamicBenchmarks() bool {
+	return a.opts.CustomBenchmarksDir != "" || a.opts.RemoteBenchmarks != nil
+}
+
+// loadBenchmarks loads the benchmarks of the configuration directory, of
+// the custom benchmarks directory and of remote configuration, if any.
+func (a *Agent) loadBenchmarks(ruleFilter RuleFilter) ([]*Benchmark, error) {
+	benchmarks, err := LoadBenchmarks(a.opts.ConfigDir, "*.yaml", ruleFilter)
+	if err != nil {
+		return nil, err
+	}
+	if a.opts.CustomBenchmarksDir != "" {
+		customBenchmarks, err := LoadBenchmarks(a.opts.CustomBenchmarksDir, "*.yaml", ruleFilter)
+		if err != nil {
+			return nil, fmt.Errorf("could not load custom benchmarks: %w", err)
+		}
+		benchmarks = append(benchmarks, customBenchmarks...)
+	}
+	if a.opts.RemoteBenchmarks != nil {
+		remoteBenchmarks, err := a.opts.RemoteBenchmarks.LoadBenchmarks(ruleFilter)
+		if err != nil {
+			return nil, fmt.Errorf("could not load remote benchmarks: %w", err)
+		}
+		benchmarks = append(benchmarks, remoteBenchmarks...)
+	}
+	return benchmarks, nil
+}
+
+// reloadBenchmarks loads the benchmarks again to pick up the changes of the
+// custom and remote benchmarks. The previous benchmarks are kept if they can't be loaded.
+func (a *Agent) reloadBenchmarks(benchmarks []*Benchmark, ruleFilter RuleFilter) []*Benchmark {
+	reloaded, err := a.loadBenchmarks(ruleFilter)
+	if err != nil {
+		log.Warnf("could not reload benchmarks, keeping the previous ones: %v", err)
+		return benchmarks
+	}
+	a.addBenchmarks(reloaded...)
+	return reloaded
+}
+
 func (a *Agent) runKubernetesConfigurationsExport(ctx context.Context) {
 	if !env.IsKubernetes() {
 		return
@@ -0,0 +1,181 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package compliance
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// openSCAPResultsGlob is the pattern of the XCCDF result files ingested from
+// the OpenSCAP results directory.
+const openSCAPResultsGlob = "*.xml"
+
+// xccdfTestResult is the TestResult element of an XCCDF results document, as
+// produced by `oscap xccdf eval --results` or embedded in an ARF report.
+type xccdfTestResult struct {
+	ID        string `xml:"id,attr"`
+	Benchmark struct {
+		ID   string `xml:"id,attr"`
+		Href string `xml:"href,attr"`
+	} `xml:"benchmark"`
+	Profile struct {
+		IDRef string `xml:"idref,attr"`
+	} `xml:"profile"`
+	Target      string            `xml:"target"`
+	RuleResults []xccdfRuleResult `xml:"rule-result"`
+
+	benchmarkID string
+	filename    string
+}
+
+type xccdfRuleResult struct {
+	IDRef    string `xml:"idref,attr"`
+	Severity string `xml:"severity,attr"`
+	Time     string `xml:"time,attr"`
+	Result   string `xml:"result"`
+}
+
+// parseXCCDFResults returns the test results held by an XCCDF results
+// document. The elements are matched by their local name so that the XCCDF
+// 1.1 and 1.2 namespaces, and the ARF reports are all supported.
+func parseXCCDFResults(r io.Reader) ([]*xccdfTestResult, error) {
+	var (
+		results     []*xccdfTestResult
+		benchmarkID string
+	)
+
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "Benchmark":
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "id" {
+					benchmarkID = attr.Value
+				}
+			}
+		case "TestResult":
+			var result xccdfTestResult
+			if err := decoder.DecodeElement(&result, &start); err != nil {
+				return nil, err
+			}
+			result.benchmarkID = benchmarkID
+			if result.Benchmark.ID != "" {
+				result.benchmarkID = result.Benchmark.ID
+			}
+			results = append(results, &result)
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, errors.New("no XCCDF test result found")
+	}
+	return results, nil
+}
+
+// LoadOpenSCAPResults reads the XCCDF result files contained in the given
+// directory and returns them as benchmarks along with their check events.
+// Each results document is reported as a benchmark identified by the XCCDF
+// benchmark ID, or by the name of the file if it does not reference one.
+func LoadOpenSCAPResults(rootDir, hostname string) ([]*Benchmark, []*CheckEvent, error) {
+	var (
+		benchmarks []*Benchmark
+		events     []*CheckEvent
+	)
+
+	for _, filename := range listBenchmarksFilenames(rootDir, openSCAPResultsGlob) {
+		// a file being written by an OpenSCAP run, or an invalid one, must
+		// not prevent the other files from being reported
+		f, err := os.Open(filepath.Join(rootDir, filename))
+		if err != nil {
+			log.Warnf("could not open XCCDF results file %s: %v", filename, err)
+			continue
+		}
+		results, err := parseXCCDFResults(f)
+		f.Close()
+		if err != nil {
+			log.Warnf("could not parse XCCDF results file %s: %v", filename, err)
+			continue
+		}
+
+		for _, result := range results {
+			result.filename = filename
+			benchmark, benchmarkEvents := openSCAPResultEvents(result, hostname)
+			if len(benchmark.Rules) == 0 {
+				continue
+			}
+			benchmarks = append(benchmarks, benchmark)
+			events = append(events, benchmarkEvents...)
+		}
+	}
+	return benchmarks, events, nil
+}
+
+func openSCAPResultEvents(result *xccdfTestResult, hostname string) (*Benchmark, []*CheckEvent) {
+	frameworkID := result.benchmarkID
+	if frameworkID == "" {
+		frameworkID = strings.TrimSuffix(result.filename, filepath.Ext(result.filename))
+	}
+	benchmark := &Benchmark{
+		Name:        frameworkID,
+		FrameworkID: frameworkID,
+		Source:      result.filename,
+	}
+
+	resourceID := hostname
+	if resourceID == "" {
+		resourceID = result.Target
+	}
+
+	var events []*CheckEvent
+	for _, ruleResult := range result.RuleResults {
+		rule := &Rule{ID: ruleResult.IDRef}
+		data := map[string]interface{}{
+			"profile":     result.Profile.IDRef,
+			"test_result": result.ID,
+			"severity":    ruleResult.Severity,
+			"time":        ruleResult.Time,
+		}
+
+		var event *CheckEvent
+		switch ruleResult.Result {
+		case "pass", "fixed":
+			event = NewCheckEvent(XCCDFEvaluator, CheckPassed, data, resourceID, "host", rule, benchmark)
+		case "fail":
+			event = NewCheckEvent(XCCDFEvaluator, CheckFailed, data, resourceID, "host", rule, benchmark)
+		case "error", "unknown":
+			event = NewCheckError(XCCDFEvaluator, fmt.Errorf("XCCDF_RESULT_%s", strings.ToUpper(ruleResult.Result)), resourceID, "host", rule, benchmark)
+		case "notapplicable":
+			event = NewCheckSkipped(XCCDFEvaluator, errors.New("XCCDF_RESULT_NOT_APPLICABLE"), resourceID, "host", rule, benchmark)
+		default:
+			// notchecked, notselected and informational results are not
+			// reported, like when running the benchmarks with oscap-io.
+			continue
+		}
+		benchmark.Rules = append(benchmark.Rules, rule)
+		events = append(events, event)
+	}
+	return benchmark, events
+}
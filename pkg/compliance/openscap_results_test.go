@@ -0,0 +1,135 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package compliance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const xccdfResults = `<?xml version="1.0" encoding="UTF-8"?>
+<Benchmark xmlns="http://checklists.nist.gov/xccdf/1.2" id="xccdf_com.example_benchmark_hardening" resolved="1">
+  <title>Internal hardening standard</title>
+  <Rule id="xccdf_com.example_rule_sshd_disable_root_login" severity="high"/>
+  <TestResult id="xccdf_org.open-scap_testresult_default" start-time="2024-05-02T10:00:00" end-time="2024-05-02T10:01:00">
+    <benchmark href="#xccdf_com.example_benchmark_hardening" id="xccdf_com.example_benchmark_hardening"/>
+    <profile idref="xccdf_com.example_profile_default"/>
+    <target>scanned-host</target>
+    <rule-result idref="xccdf_com.example_rule_sshd_disable_root_login" severity="high" time="2024-05-02T10:00:10">
+      <result>fail</result>
+    </rule-result>
+    <rule-result idref="xccdf_com.example_rule_audit_enabled" severity="medium" time="2024-05-02T10:00:11">
+      <result>pass</result>
+    </rule-result>
+    <rule-result idref="xccdf_com.example_rule_selinux" severity="low" time="2024-05-02T10:00:12">
+      <result>notapplicable</result>
+    </rule-result>
+    <rule-result idref="xccdf_com.example_rule_partitions" severity="low" time="2024-05-02T10:00:13">
+      <result>notselected</result>
+    </rule-result>
+    <rule-result idref="xccdf_com.example_rule_kernel" severity="low" time="2024-05-02T10:00:14">
+      <result>error</result>
+    </rule-result>
+  </TestResult>
+</Benchmark>
+`
+
+func TestLoadOpenSCAPResults(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hardening.xml"), []byte(xccdfResults), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0600))
+
+	benchmarks, events, err := LoadOpenSCAPResults(dir, "my-host")
+	require.NoError(t, err)
+
+	require.Len(t, benchmarks, 1)
+	assert.Equal(t, "xccdf_com.example_benchmark_hardening", benchmarks[0].FrameworkID)
+	assert.Equal(t, "hardening.xml", benchmarks[0].Source)
+	assert.Len(t, benchmarks[0].Rules, 4)
+
+	require.Len(t, events, 4)
+	results := make(map[string]CheckResult)
+	for _, event := range events {
+		assert.Equal(t, XCCDFEvaluator, event.Evaluator)
+		assert.Equal(t, "xccdf_com.example_benchmark_hardening", event.FrameworkID)
+		assert.Equal(t, "my-host", event.ResourceID)
+		assert.Equal(t, "host", event.ResourceType)
+		results[event.RuleID] = event.Result
+	}
+	assert.Equal(t, map[string]CheckResult{
+		"xccdf_com.example_rule_sshd_disable_root_login": CheckFailed,
+		"xccdf_com.example_rule_audit_enabled":           CheckPassed,
+		"xccdf_com.example_rule_selinux":                 CheckSkipped,
+		"xccdf_com.example_rule_kernel":                  CheckError,
+	}, results)
+	assert.Equal(t, "xccdf_com.example_profile_default", events[0].Data["profile"])
+	assert.Equal(t, "high", events[0].Data["severity"])
+}
+
+func TestLoadOpenSCAPResultsErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	benchmarks, events, err := LoadOpenSCAPResults(dir, "my-host")
+	require.NoError(t, err)
+	assert.Empty(t, benchmarks)
+	assert.Empty(t, events)
+
+	// the invalid files are skipped, without discarding the valid ones
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "empty.xml"), []byte(`<Benchmark id="foo"></Benchmark>`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "truncated.xml"), []byte(`<Benchmark id="foo"><TestResult>`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hardening.xml"), []byte(xccdfResults), 0600))
+	benchmarks, events, err = LoadOpenSCAPResults(dir, "my-host")
+	require.NoError(t, err)
+	require.Len(t, benchmarks, 1)
+	assert.Equal(t, "xccdf_com.example_benchmark_hardening", benchmarks[0].FrameworkID)
+	assert.Len(t, events, 4)
+}
+
+func TestLoadCustomBenchmarks(t *testing.T) {
+	configDir, customDir := t.TempDir(), t.TempDir()
+	benchmark := func(framework, ruleID string) []byte {
+		return []byte(`schema:
+  version: 1.0.0
+name: ` + framework + `
+framework: ` + framework + `
+version: 1.0.0
+rules:
+  - id: ` + ruleID + `
+    input:
+      - constants:
+          foo: bar
+`)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "cis.yaml"), benchmark("cis", "cis-1"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(customDir, "internal.yaml"), benchmark("internal", "internal-1"), 0600))
+
+	a := &Agent{
+		opts: AgentOptions{
+			ConfigDir:           configDir,
+			CustomBenchmarksDir: customDir,
+		},
+		statuses: make(map[string]*CheckStatus),
+	}
+	benchmarks, err := a.loadBenchmarks(nil)
+	require.NoError(t, err)
+	require.Len(t, benchmarks, 2)
+	assert.Equal(t, "cis", benchmarks[0].FrameworkID)
+	assert.Equal(t, "internal", benchmarks[1].FrameworkID)
+
+	// new custom benchmarks are picked up, and invalid ones don't discard the previous ones
+	require.NoError(t, os.WriteFile(filepath.Join(customDir, "other.yaml"), benchmark("other", "other-1"), 0600))
+	benchmarks = a.reloadBenchmarks(benchmarks, nil)
+	assert.Len(t, benchmarks, 3)
+	assert.Contains(t, a.statuses, "other-1")
+
+	require.NoError(t, os.WriteFile(filepath.Join(customDir, "invalid.yaml"), []byte("rules: {"), 0600))
+	benchmarks = a.reloadBenchmarks(benchmarks, nil)
+	assert.Len(t, benchmarks, 3)
+}
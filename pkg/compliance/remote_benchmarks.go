@@ -0,0 +1,187 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package compliance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/api/security"
+	"github.com/DataDog/datadog-agent/pkg/config/remote/client"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+	"github.com/DataDog/datadog-agent/pkg/security/utils"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const remoteBenchmarksPollInterval = 30 * time.Second
+
+// remoteBenchmarkConfig is the content of a remote configuration of the
+// ProductCSMComplianceCustom product: the benchmark files and their assets,
+// Rego modules or XCCDF datastreams, by file name.
+type remoteBenchmarkConfig struct {
+	Files map[string]string `json:"files"`
+}
+
+// RemoteBenchmarks holds the user-provided benchmarks received through remote
+// configuration. Each configuration is written in its own subdirectory of
+// the given directory, so that the benchmarks assets can be read when
+// evaluating the rules like the ones of the configuration directory.
+type RemoteBenchmarks struct {
+	client *client.Client
+	dir    string
+
+	mu sync.RWMutex
+	// dirs are the subdirectories of the applied configurations
+	dirs []string
+}
+
+// NewRemoteBenchmarks returns the source of the benchmarks received through
+// remote configuration, written in the given directory.
+func NewRemoteBenchmarks(dir string) (*RemoteBenchmarks, error) {
+	agentVersion, err := utils.GetAgentSemverVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse agent version: %w", err)
+	}
+	ipcAddress, err := pkgconfigsetup.GetIPCAddress(pkgconfigsetup.Datadog())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ipc address: %w", err)
+	}
+
+	c, err := client.NewGRPCClient(ipcAddress, pkgconfigsetup.GetIPCPort(), func() (string, error) { return security.FetchAuthToken(pkgconfigsetup.Datadog()) },
+		client.WithAgent("security-agent", agentVersion.String()),
+		client.WithProducts(state.ProductCSMComplianceCustom),
+		client.WithPollInterval(remoteBenchmarksPollInterval),
+		client.WithDirectorRootOverride(pkgconfigsetup.Datadog().GetString("site"), pkgconfigsetup.Datadog().GetString("remote_configuration.director_root")),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRemoteBenchmarks(c, dir)
+}
+
+func newRemoteBenchmarks(c *client.Client, dir string) (*RemoteBenchmarks, error) {
+	// the configurations of a previous run are received again on start
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &RemoteBenchmarks{client: c, dir: dir}, nil
+}
+
+// Start subscribes to the remote configurations of the benchmarks
+func (r *RemoteBenchmarks) Start() {
+	r.client.Subscribe(state.ProductCSMComplianceCustom, r.update)
+	r.client.Start()
+}
+
+// Stop stops the remote configuration client
+func (r *RemoteBenchmarks) Stop() {
+	r.client.Close()
+}
+
+// LoadBenchmarks loads the benchmarks of the applied configurations
+func (r *RemoteBenchmarks) LoadBenchmarks(ruleFilter RuleFilter) ([]*Benchmark, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var benchmarks []*Benchmark
+	for _, dir := range r.dirs {
+		dirBenchmarks, err := LoadBenchmarks(dir, "*.yaml", ruleFilter)
+		if err != nil {
+			return nil, err
+		}
+		benchmarks = append(benchmarks, dirBenchmarks...)
+	}
+	return benchmarks, nil
+}
+
+// update writes the received configurations in their subdirectory. A
+// configuration is only applied once its benchmarks are loaded successfully,
+// and the subdirectories of the removed configurations are deleted. The
+// subdirectories are named after the configuration version, so that the
+// applied ones are never rewritten while their rules are evaluated.
+func (r *RemoteBenchmarks) update(configs map[string]state.RawConfig, applyStateCallback func(string, state.ApplyStatus)) {
+	r.mu.RLock()
+	current := r.dirs
+	r.mu.RUnlock()
+
+	var dirs []string
+	for _, cfgPath := range slices.Sorted(maps.Keys(configs)) {
+		config := configs[cfgPath]
+		dir := filepath.Join(r.dir, filepath.Base(config.Metadata.ID)+"-v"+strconv.FormatUint(config.Metadata.Version, 10))
+		if slices.Contains(current, dir) {
+			dirs = append(dirs, dir)
+			continue
+		}
+
+		if err := writeRemoteBenchmark(dir, config.Config); err != nil {
+			log.Warnf("could not apply the remote compliance benchmark %s: %v", cfgPath, err)
+			os.RemoveAll(dir)
+			applyStateCallback(cfgPath, state.ApplyStatus{State: state.ApplyStateError, Error: err.Error()})
+			continue
+		}
+		dirs = append(dirs, dir)
+		applyStateCallback(cfgPath, state.ApplyStatus{State: state.ApplyStateAcknowledged})
+	}
+
+	r.mu.Lock()
+	previous := r.dirs
+	r.dirs = dirs
+	r.mu.Unlock()
+
+	for _, dir := range previous {
+		if !slices.Contains(dirs, dir) {
+			os.RemoveAll(dir)
+		}
+	}
+	log.Infof("%d remote compliance benchmark configurations applied", len(dirs))
+}
+
+func writeRemoteBenchmark(dir string, raw []byte) error {
+	var config remoteBenchmarkConfig
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if len(config.Files) == 0 {
+		return errors.New("the configuration has no file")
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	for name, content := range config.Files {
+		if name != filepath.Base(name) || name == "." || name == ".." {
+			return fmt.Errorf("invalid file name %q", name)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			return err
+		}
+	}
+
+	benchmarks, err := LoadBenchmarks(dir, "*.yaml", nil)
+	if err != nil {
+		return fmt.Errorf("invalid benchmark: %w", err)
+	}
+	if len(benchmarks) == 0 {
+		return errors.New("the configuration has no benchmark")
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package compliance
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+)
+
+func TestRemoteBenchmarks(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "remote")
+	r, err := newRemoteBenchmarks(nil, dir)
+	require.NoError(t, err)
+
+	rawConfig := func(id string, version uint64, files map[string]string) state.RawConfig {
+		raw, err := json.Marshal(remoteBenchmarkConfig{Files: files})
+		require.NoError(t, err)
+		return state.RawConfig{Config: raw, Metadata: state.Metadata{ID: id, Version: version}}
+	}
+	benchmark := `schema:
+  version: 1.0.0
+name: internal
+framework: internal
+version: 1.0.0
+rules:
+  - id: internal-1
+    input:
+      - constants:
+          foo: bar
+`
+	statuses := make(map[string]state.ApplyStatus)
+	applyStateCallback := func(cfgPath string, status state.ApplyStatus) { statuses[cfgPath] = status }
+
+	r.update(map[string]state.RawConfig{
+		"datadog/2/CSM_COMPLIANCE_CUSTOM/internal/config": rawConfig("internal", 1, map[string]string{
+			"internal.yaml":   benchmark,
+			"internal-1.rego": "package datadog",
+		}),
+		"datadog/2/CSM_COMPLIANCE_CUSTOM/invalid/config": rawConfig("invalid", 1, map[string]string{
+			"invalid.yaml": "rules: {",
+		}),
+		"datadog/2/CSM_COMPLIANCE_CUSTOM/escape/config": rawConfig("escape", 1, map[string]string{
+			"../escape.yaml": benchmark,
+		}),
+	}, applyStateCallback)
+
+	assert.Equal(t, state.ApplyStateAcknowledged, statuses["datadog/2/CSM_COMPLIANCE_CUSTOM/internal/config"].State)
+	assert.Equal(t, state.ApplyStateError, statuses["datadog/2/CSM_COMPLIANCE_CUSTOM/invalid/config"].State)
+	assert.Equal(t, state.ApplyStateError, statuses["datadog/2/CSM_COMPLIANCE_CUSTOM/escape/config"].State)
+	assert.NoFileExists(t, filepath.Join(dir, "escape.yaml"))
+
+	benchmarks, err := r.LoadBenchmarks(nil)
+	require.NoError(t, err)
+	require.Len(t, benchmarks, 1)
+	assert.Equal(t, "internal", benchmarks[0].FrameworkID)
+	assert.FileExists(t, filepath.Join(benchmarks[0].dirname, "internal-1.rego"))
+
+	// the remote benchmarks are run along with the other ones
+	a := &Agent{
+		opts: AgentOptions{
+			ConfigDir:        t.TempDir(),
+			RemoteBenchmarks: r,
+		},
+		statuses: make(map[string]*CheckStatus),
+	}
+	benchmarks, err = a.loadBenchmarks(nil)
+	require.NoError(t, err)
+	assert.Len(t, benchmarks, 1)
+
+	// the removed configurations are deleted
+	previousDir := benchmarks[0].dirname
+	r.update(map[string]state.RawConfig{}, applyStateCallback)
+	benchmarks, err = r.LoadBenchmarks(nil)
+	require.NoError(t, err)
+	assert.Empty(t, benchmarks)
+	_, err = os.Stat(previousDir)
+	assert.True(t, os.IsNotExist(err))
+}
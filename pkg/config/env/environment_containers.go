@@ -10,6 +10,7 @@ package env
 import (
 	"os"
 	"path"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
@@ -28,6 +29,7 @@ const (
 	defaultLinuxCrioSocket             = "/var/run/crio/crio.sock"
 	defaultHostMountPrefix             = "/host"
 	defaultPodmanContainersStoragePath = "/var/lib/containers/storage"
+	defaultPodmanRootlessRunPattern    = "/run/user/*/containers"
 	unixSocketPrefix                   = "unix://"
 	winNamedPipePrefix                 = "npipe://"
 	defaultNVMLLibraryName             = "libnvidia-ml.so.1"
@@ -144,6 +146,18 @@ func detectCriRuntimes(features FeatureMap, cfg model.Reader) {
 			features[Crio] = struct{}{}
 		}
 	}
+
+	// Additional CRI sockets are only queried through the CRI, they don't
+	// enable the runtime specific features.
+	if _, found := features[Cri]; found || !isCriSupported() {
+		return
+	}
+	for _, additionalCriSocket := range cfg.GetStringSlice("cri_socket_paths") {
+		if checkCriSocket(additionalCriSocket) != "" {
+			features[Cri] = struct{}{}
+			break
+		}
+	}
 }
 
 func checkCriSocket(socketPath string) string {
@@ -230,6 +244,14 @@ func detectPodman(features FeatureMap, cfg model.Reader) {
 			return
 		}
 	}
+	// rootless Podman keeps its state in the home directory of the users,
+	// their sessions are found from the runtime directories
+	for _, prefix := range getHostMountPrefixes() {
+		if matches, _ := filepath.Glob(path.Join(prefix, defaultPodmanRootlessRunPattern)); len(matches) > 0 {
+			features[Podman] = struct{}{}
+			return
+		}
+	}
 }
 
 func detectPodResources(features FeatureMap, cfg model.Reader) {
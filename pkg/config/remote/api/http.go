@@ -9,10 +9,12 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,9 +27,12 @@ import (
 )
 
 const (
-	pollEndpoint      = "/api/v0.1/configurations"
-	orgDataEndpoint   = "/api/v0.1/org"
-	orgStatusEndpoint = "/api/v0.1/status"
+	// PollEndpoint is the path of the endpoint returning the latest configurations
+	PollEndpoint = "/api/v0.1/configurations"
+	// OrgDataEndpoint is the path of the endpoint returning the org data
+	OrgDataEndpoint = "/api/v0.1/org"
+	// OrgStatusEndpoint is the path of the endpoint returning the org and key status
+	OrgStatusEndpoint = "/api/v0.1/status"
 )
 
 var (
@@ -82,9 +87,10 @@ func NewHTTPClient(auth Auth, cfg model.Reader, baseURL *url.URL) (*HTTPClient,
 		header["DD-Application-Key"] = []string{auth.AppKey}
 	}
 
-	transport := httputils.CreateHTTPTransport(cfg)
-	// Set the keep-alive timeout to 30s instead of the default 90s, so the http RC client is not closed by the backend
-	transport.IdleConnTimeout = 30 * time.Second
+	transport := httputils.NewTransport(cfg, httputils.RemoteConfigDestination, func(t *http.Transport) {
+		// Set the keep-alive timeout to 30s instead of the default 90s, so the http RC client is not closed by the backend
+		t.IdleConnTimeout = 30 * time.Second
+	})
 
 	httpClient := &http.Client{
 		Transport: transport,
@@ -102,6 +108,35 @@ func NewHTTPClient(auth Auth, cfg model.Reader, baseURL *url.URL) (*HTTPClient,
 	}, nil
 }
 
+// NewMirrorHTTPClient returns a new HTTP configuration client fetching the configurations from a
+// mirror: an agent serving its Remote Configuration repositories to other agents over its IPC API.
+//
+// The IPC API certificate of the mirror is self-signed, so it isn't verified. The repositories are
+// signed and verified by the uptane client whatever the transport.
+func NewMirrorHTTPClient(baseURL *url.URL, authToken string) (*HTTPClient, error) {
+	if baseURL.Scheme != "https" {
+		return nil, fmt.Errorf("remote Configuration mirror URL %s is invalid as the agent IPC API is only served over TLS", baseURL)
+	}
+
+	header := http.Header{
+		"Content-Type":  []string{"application/x-protobuf"},
+		"Authorization": []string{"Bearer " + authToken},
+	}
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+			IdleConnTimeout: 30 * time.Second,
+		},
+	}
+	return &HTTPClient{
+		client:  httpClient,
+		header:  header,
+		baseURL: strings.TrimSuffix(baseURL.String(), "/"),
+	}, nil
+}
+
 // Fetch remote configuration
 func (c *HTTPClient) Fetch(ctx context.Context, request *pbgo.LatestConfigsRequest) (*pbgo.LatestConfigsResponse, error) {
 	body, err := proto.Marshal(request)
@@ -109,7 +144,7 @@ func (c *HTTPClient) Fetch(ctx context.Context, request *pbgo.LatestConfigsReque
 		return nil, err
 	}
 
-	url := c.baseURL + pollEndpoint
+	url := c.baseURL + PollEndpoint
 	log.Debugf("fetching configurations at %s", url)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
 	if err != nil {
@@ -156,7 +191,7 @@ func (c *HTTPClient) Fetch(ctx context.Context, request *pbgo.LatestConfigsReque
 
 // FetchOrgData org data
 func (c *HTTPClient) FetchOrgData(ctx context.Context) (*pbgo.OrgDataResponse, error) {
-	url := c.baseURL + orgDataEndpoint
+	url := c.baseURL + OrgDataEndpoint
 	log.Debugf("fetching org data at %s", url)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, &bytes.Buffer{})
 	if err != nil {
@@ -194,7 +229,7 @@ func (c *HTTPClient) FetchOrgData(ctx context.Context) (*pbgo.OrgDataResponse, e
 
 // FetchOrgStatus returns the org and key status
 func (c *HTTPClient) FetchOrgStatus(ctx context.Context) (*pbgo.OrgStatusResponse, error) {
-	url := c.baseURL + orgStatusEndpoint
+	url := c.baseURL + OrgStatusEndpoint
 	log.Debugf("fetching org status at %s", url)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, &bytes.Buffer{})
 	if err != nil {
@@ -16,6 +16,8 @@ import (
 
 type client struct {
 	lastSeen time.Time
+	// ttl overrides the TTL of the clients when set
+	ttl      time.Duration
 	pbClient *pbgo.Client
 }
 
@@ -50,6 +52,9 @@ func (c *cacheBypassClients) Limit() bool {
 }
 
 func (c *client) expired(clock clock.Clock, ttl time.Duration) bool {
+	if c.ttl != 0 {
+		ttl = c.ttl
+	}
 	return clock.Now().UTC().After(c.lastSeen.Add(ttl))
 }
 
@@ -72,12 +77,18 @@ func newClients(clock clock.Clock, clientsTTL time.Duration) *clients {
 
 // seen marks the given client as active
 func (c *clients) seen(pbClient *pbgo.Client) {
+	c.seenFor(pbClient, 0)
+}
+
+// seenFor marks the given client as active for the given TTL, instead of the TTL of the clients
+func (c *clients) seenFor(pbClient *pbgo.Client, ttl time.Duration) {
 	c.m.Lock()
 	defer c.m.Unlock()
 	now := c.clock.Now().UTC()
 	pbClient.LastSeen = uint64(now.UnixMilli())
 	c.clients[pbClient.Id] = &client{
 		lastSeen: now,
+		ttl:      ttl,
 		pbClient: pbClient,
 	}
 }
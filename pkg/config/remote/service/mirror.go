@@ -0,0 +1,113 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/DataDog/datadog-agent/pkg/config/remote/api"
+	rdata "github.com/DataDog/datadog-agent/pkg/config/remote/data"
+	pbgo "github.com/DataDog/datadog-agent/pkg/proto/pbgo/core"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// mirroredClientsTTLFactor is the TTL of the clients of the downstream agents, in refresh intervals
+const mirroredClientsTTLFactor = 3
+
+// ServeMirror serves the Remote Configuration backend API to downstream agents configured to
+// use this agent as their mirror, out of the local repositories.
+func (s *CoreAgentService) ServeMirror(w http.ResponseWriter, r *http.Request) {
+	var response proto.Message
+	var err error
+	switch {
+	case strings.HasSuffix(r.URL.Path, api.PollEndpoint) && r.Method == http.MethodPost:
+		response, err = s.mirrorFetch(r)
+	case strings.HasSuffix(r.URL.Path, api.OrgDataEndpoint):
+		response, err = s.mirrorOrgData()
+	case strings.HasSuffix(r.URL.Path, api.OrgStatusEndpoint):
+		response, err = s.mirrorOrgStatus()
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Debugf("[%s] Could not serve %s to downstream agent: %v", s.rcType, r.URL.Path, err)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	raw, err := proto.Marshal(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Write(raw)
+}
+
+func (s *CoreAgentService) mirrorFetch(r *http.Request) (*pbgo.LatestConfigsResponse, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	request := &pbgo.LatestConfigsRequest{}
+	if err := proto.Unmarshal(body, request); err != nil {
+		return nil, fmt.Errorf("failed to decode request: %w", err)
+	}
+
+	// The products and clients of the downstream agents are requested to the backend along with
+	// the local ones, so that the director repository holds their configurations. When they are
+	// new, the backend is requested on their behalf before the repositories are served, like for
+	// the new local clients.
+	s.Lock()
+	newClients := false
+	for _, product := range append(request.Products, request.NewProducts...) {
+		if _, ok := s.products[rdata.Product(product)]; !ok {
+			s.newProducts[rdata.Product(product)] = struct{}{}
+			newClients = true
+		}
+	}
+	// The downstream agents only poll the mirror once per refresh interval, their clients must
+	// outlive it to be requested on every refresh of the mirror.
+	ttl := mirroredClientsTTLFactor * s.defaultRefreshInterval
+	for _, client := range request.ActiveClients {
+		if !s.clients.active(client) {
+			newClients = true
+		}
+		s.clients.seenFor(client, ttl)
+	}
+	s.Unlock()
+
+	if newClients {
+		s.bypassCache()
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	return s.uptane.Mirror(request.CurrentConfigRootVersion, request.CurrentDirectorRootVersion)
+}
+
+func (s *CoreAgentService) mirrorOrgData() (*pbgo.OrgDataResponse, error) {
+	uuid, err := s.uptane.StoredOrgUUID()
+	if err != nil {
+		return nil, err
+	}
+	return &pbgo.OrgDataResponse{Uuid: uuid}, nil
+}
+
+func (s *CoreAgentService) mirrorOrgStatus() (*pbgo.OrgStatusResponse, error) {
+	status := s.previousOrgStatus
+	if status == nil {
+		return nil, errors.New("the organization status wasn't fetched yet")
+	}
+	return status, nil
+}
@@ -0,0 +1,98 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/DataDog/datadog-agent/pkg/config/remote/api"
+	rdata "github.com/DataDog/datadog-agent/pkg/config/remote/data"
+	pbgo "github.com/DataDog/datadog-agent/pkg/proto/pbgo/core"
+)
+
+func TestServeMirror(t *testing.T) {
+	uptaneClient := &mockCoreAgentUptane{}
+	clock := clock.NewMock()
+	service := newTestService(t, &mockAPI{}, uptaneClient, clock)
+	service.clients.clock = clock
+
+	// the backend is requested on behalf of the new downstream clients before the repositories are served
+	var bypasses atomic.Int32
+	go func() {
+		for response := range service.cacheBypassClients.requests {
+			bypasses.Add(1)
+			close(response)
+		}
+	}()
+
+	mirrored := &pbgo.LatestConfigsResponse{
+		ConfigMetas:   &pbgo.ConfigMetas{Timestamp: &pbgo.TopMeta{Version: 5, Raw: []byte(`{"signed":{"version":5}}`)}},
+		DirectorMetas: &pbgo.DirectorMetas{Targets: &pbgo.TopMeta{Version: 7, Raw: []byte(`{"signed":{"version":7}}`)}},
+		TargetFiles:   []*pbgo.File{{Path: "datadog/2/APM_SAMPLING/id/1", Raw: []byte(`config`)}},
+	}
+	uptaneClient.On("Mirror", uint64(3), uint64(2)).Return(mirrored, nil)
+	uptaneClient.On("StoredOrgUUID").Return("abcdef", nil)
+
+	var authorization string
+	mirror := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		service.ServeMirror(w, r)
+	}))
+	defer mirror.Close()
+
+	mirrorURL, err := url.Parse(mirror.URL + "/agent/remote-config/mirror/")
+	require.NoError(t, err)
+	downstream, err := api.NewMirrorHTTPClient(mirrorURL, "token")
+	require.NoError(t, err)
+
+	client := &pbgo.Client{Id: "downstream-client", State: &pbgo.ClientState{}, Products: []string{string(rdata.ProductAPMSampling)}}
+	response, err := downstream.Fetch(context.Background(), &pbgo.LatestConfigsRequest{
+		CurrentConfigRootVersion:   3,
+		CurrentDirectorRootVersion: 2,
+		NewProducts:                []string{string(rdata.ProductAPMSampling)},
+		ActiveClients:              []*pbgo.Client{client},
+	})
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(mirrored, response))
+	assert.Equal(t, "Bearer token", authorization)
+
+	// the downstream products and clients are requested to the backend
+	assert.Contains(t, service.newProducts, rdata.ProductAPMSampling)
+	assert.True(t, service.clients.active(client))
+	assert.EqualValues(t, 1, bypasses.Load())
+
+	// the downstream clients outlive the poll interval of the downstream agents
+	clock.Add(2 * defaultRefreshInterval)
+	assert.True(t, service.clients.active(client))
+	clock.Add(2 * defaultRefreshInterval)
+	assert.False(t, service.clients.active(client))
+
+	orgData, err := downstream.FetchOrgData(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "abcdef", orgData.Uuid)
+
+	// the mirror can't answer before it fetched the org status
+	_, err = downstream.FetchOrgStatus(context.Background())
+	assert.Error(t, err)
+
+	service.previousOrgStatus = &pbgo.OrgStatusResponse{Enabled: true, Authorized: true}
+	orgStatus, err := downstream.FetchOrgStatus(context.Background())
+	require.NoError(t, err)
+	assert.True(t, orgStatus.Enabled)
+	assert.True(t, orgStatus.Authorized)
+
+	uptaneClient.AssertExpectations(t)
+}
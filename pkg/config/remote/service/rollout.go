@@ -0,0 +1,209 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package service
+
+import (
+	"expvar"
+	"hash/fnv"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DataDog/go-tuf/data"
+	"github.com/benbjohnson/clock"
+
+	rdata "github.com/DataDog/datadog-agent/pkg/config/remote/data"
+	pbgo "github.com/DataDog/datadog-agent/pkg/proto/pbgo/core"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// rolloutBuckets is the number of buckets agents are spread into to select a
+// percentage cohort, allowing percentages with two decimals
+const rolloutBuckets = 10000
+
+var exportedHaltedRollouts = expvar.String{}
+
+// ConfigRollout is the staged rollout of a config: only the agents part of
+// its canary cohort get it.
+type ConfigRollout struct {
+	// ID identifies the rollout, a config pushed as several stages keeps the
+	// same ID so that agents stay in the cohort they were put in
+	ID string `json:"id"`
+	// Percentage is the share of agents, between 0 and 100, part of the
+	// cohort. A nil percentage selects every agent.
+	Percentage *float64 `json:"percentage,omitempty"`
+	// Tags selects the agents having all these host tags
+	Tags []string `json:"tags,omitempty"`
+}
+
+// rolloutHaltTTL is how long a rollout stays halted if no new version of its
+// configs is pushed
+const rolloutHaltTTL = 24 * time.Hour
+
+// rollouts tracks the staged rollouts of the configs served to the clients.
+//
+// A rollout is halted as soon as a client reports an error applying one of
+// its configs: its configs aren't served anymore, so that the clients
+// revert them. The halted rollouts are reported to the backend so that it
+// stops widening them. A halted rollout is resumed once a new version of one
+// of its configs is pushed, the fix being expected to come as a new version,
+// or after rolloutHaltTTL.
+type rollouts struct {
+	clock      clock.Clock
+	hostname   string
+	tagsGetter func() []string
+
+	halted map[string]haltedRollout
+}
+
+// haltedRollout is the state of a halted rollout
+type haltedRollout struct {
+	// versions are the versions of the configs of the rollout, by path, when
+	// it was halted
+	versions map[string]uint64
+	expires  time.Time
+}
+
+func newRollouts(clock clock.Clock, hostname string, tagsGetter func() []string) *rollouts {
+	return &rollouts{
+		clock:      clock,
+		hostname:   hostname,
+		tagsGetter: tagsGetter,
+		halted:     make(map[string]haltedRollout),
+	}
+}
+
+// inCohort returns whether the agent is part of the cohort of the rollout
+func (r *rollouts) inCohort(rollout *ConfigRollout) bool {
+	if rollout.Percentage != nil {
+		h := fnv.New32a()
+		h.Write([]byte(rollout.ID + "/" + r.hostname))
+		if float64(h.Sum32()%rolloutBuckets) >= *rollout.Percentage*rolloutBuckets/100 {
+			return false
+		}
+	}
+
+	if len(rollout.Tags) > 0 {
+		hostTags := r.tagsGetter()
+		for _, tag := range rollout.Tags {
+			if !slices.Contains(hostTags, tag) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// recordClientState halts the rollouts of the configs the client failed to apply
+func (r *rollouts) recordClientState(client *pbgo.Client, directorTargets data.TargetFiles) {
+	r.resume(directorTargets)
+
+	if client.State == nil {
+		return
+	}
+
+	for _, configState := range client.State.ConfigStates {
+		if configState.ApplyState != uint64(state.ApplyStateError) {
+			continue
+		}
+		configMetadata, ok := findConfigMetadata(configState, directorTargets)
+		if !ok || configMetadata.Rollout == nil || configMetadata.Version != configState.Version {
+			// errors on a previous version of the config don't halt its new version
+			continue
+		}
+		rollout := configMetadata.Rollout
+		if _, halted := r.halted[rollout.ID]; halted {
+			continue
+		}
+
+		log.Warnf("Halting the rollout %s of remote config %s/%s, client %s failed to apply it: %s", rollout.ID, configState.Product, configState.Id, client.Id, configState.ApplyError)
+		r.halted[rollout.ID] = haltedRollout{
+			versions: rolloutVersions(rollout.ID, directorTargets),
+			expires:  r.clock.Now().Add(rolloutHaltTTL),
+		}
+		r.exportHalted()
+	}
+}
+
+// resume resumes the halted rollouts which expired or had a new version of
+// one of their configs pushed
+func (r *rollouts) resume(directorTargets data.TargetFiles) {
+	now := r.clock.Now()
+	for id, halted := range r.halted {
+		if now.After(halted.expires) {
+			log.Infof("Resuming the rollout %s of remote config, halted since %s", id, halted.expires.Add(-rolloutHaltTTL).Format(time.RFC3339))
+		} else if !maps.Equal(halted.versions, rolloutVersions(id, directorTargets)) {
+			log.Infof("Resuming the rollout %s of remote config, a new version of its configs was pushed", id)
+		} else {
+			continue
+		}
+		delete(r.halted, id)
+		r.exportHalted()
+	}
+}
+
+// filter returns the configs the agent should get, out of the given ones
+func (r *rollouts) filter(configs []string, directorTargets data.TargetFiles) ([]string, error) {
+	filtered := make([]string, 0, len(configs))
+	for _, path := range configs {
+		configMetadata, err := parseFileMetaCustom(directorTargets[path].Custom)
+		if err != nil {
+			return nil, err
+		}
+
+		if rollout := configMetadata.Rollout; rollout != nil {
+			if _, halted := r.halted[rollout.ID]; halted || !r.inCohort(rollout) {
+				continue
+			}
+		}
+		filtered = append(filtered, path)
+	}
+	return filtered, nil
+}
+
+// haltedIDs returns the sorted IDs of the halted rollouts, reported to the backend
+func (r *rollouts) haltedIDs() []string {
+	if len(r.halted) == 0 {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(r.halted))
+}
+
+func (r *rollouts) exportHalted() {
+	exportedHaltedRollouts.Set(strings.Join(r.haltedIDs(), ", "))
+}
+
+// rolloutVersions returns the versions of the configs of the rollout, by path
+func rolloutVersions(id string, directorTargets data.TargetFiles) map[string]uint64 {
+	versions := make(map[string]uint64)
+	for path, meta := range directorTargets {
+		configMetadata, err := parseFileMetaCustom(meta.Custom)
+		if err != nil || configMetadata.Rollout == nil || configMetadata.Rollout.ID != id {
+			continue
+		}
+		versions[path] = configMetadata.Version
+	}
+	return versions
+}
+
+// findConfigMetadata returns the metadata of the config reported by a client
+func findConfigMetadata(configState *pbgo.ConfigState, directorTargets data.TargetFiles) (ConfigFileMetaCustom, bool) {
+	for path, meta := range directorTargets {
+		pathMeta, err := rdata.ParseConfigPath(path)
+		if err != nil || pathMeta.Product != configState.Product || pathMeta.ConfigID != configState.Id {
+			continue
+		}
+		configMetadata, err := parseFileMetaCustom(meta.Custom)
+		if err != nil {
+			return ConfigFileMetaCustom{}, false
+		}
+		return configMetadata, true
+	}
+	return ConfigFileMetaCustom{}, false
+}
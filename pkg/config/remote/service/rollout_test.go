@@ -0,0 +1,128 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DataDog/go-tuf/data"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pbgo "github.com/DataDog/datadog-agent/pkg/proto/pbgo/core"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+)
+
+func percentage(p float64) *float64 {
+	return &p
+}
+
+func rolloutMeta(rollout *ConfigRollout, version uint64) *json.RawMessage {
+	raw, _ := json.Marshal(ConfigFileMetaCustom{Rollout: rollout, Version: version})
+	msg := json.RawMessage(raw)
+	return &msg
+}
+
+func TestRolloutInCohort(t *testing.T) {
+	r := newRollouts(clock.NewMock(), "my-host", getHostTags)
+
+	assert.True(t, r.inCohort(&ConfigRollout{ID: "rollout"}))
+	assert.True(t, r.inCohort(&ConfigRollout{ID: "rollout", Percentage: percentage(100)}))
+	assert.False(t, r.inCohort(&ConfigRollout{ID: "rollout", Percentage: percentage(0)}))
+
+	assert.True(t, r.inCohort(&ConfigRollout{ID: "rollout", Tags: []string{"dogo_state:hungry"}}))
+	assert.False(t, r.inCohort(&ConfigRollout{ID: "rollout", Tags: []string{"dogo_state:hungry", "env:staging"}}))
+	assert.False(t, r.inCohort(&ConfigRollout{ID: "rollout", Percentage: percentage(0), Tags: []string{"dogo_state:hungry"}}))
+
+	// an agent stays in the cohort while the rollout widens
+	rollout := &ConfigRollout{ID: "rollout", Percentage: percentage(10)}
+	inCohort := 0
+	for i := 0; i < 1000; i++ {
+		host := newRollouts(clock.NewMock(), fmt.Sprintf("host-%d", i), getHostTags)
+		rollout.Percentage = percentage(10)
+		if host.inCohort(rollout) {
+			inCohort++
+			rollout.Percentage = percentage(50)
+			assert.True(t, host.inCohort(rollout))
+		}
+	}
+	assert.InDelta(t, 100, inCohort, 40)
+}
+
+func TestRolloutHalt(t *testing.T) {
+	clock := clock.NewMock()
+	r := newRollouts(clock, "my-host", getHostTags)
+
+	directorTargets := data.TargetFiles{
+		"datadog/2/APM_SAMPLING/1/config": {Custom: rolloutMeta(&ConfigRollout{ID: "canary", Percentage: percentage(100)}, 1)},
+		"datadog/2/APM_SAMPLING/2/config": {Custom: rolloutMeta(&ConfigRollout{ID: "canary", Percentage: percentage(100)}, 1)},
+		"datadog/2/APM_SAMPLING/3/config": {Custom: rolloutMeta(&ConfigRollout{ID: "excluded", Percentage: percentage(0)}, 1)},
+		"datadog/2/APPSEC/1/config":       {Custom: customMeta(nil, 0)},
+	}
+	configs := []string{
+		"datadog/2/APM_SAMPLING/1/config",
+		"datadog/2/APM_SAMPLING/2/config",
+		"datadog/2/APM_SAMPLING/3/config",
+		"datadog/2/APPSEC/1/config",
+	}
+
+	filtered, err := r.filter(configs, directorTargets)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"datadog/2/APM_SAMPLING/1/config",
+		"datadog/2/APM_SAMPLING/2/config",
+		"datadog/2/APPSEC/1/config",
+	}, filtered)
+
+	client := &pbgo.Client{
+		Id: "client-id",
+		State: &pbgo.ClientState{
+			ConfigStates: []*pbgo.ConfigState{
+				{Id: "1", Product: "APM_SAMPLING", Version: 1, ApplyState: uint64(state.ApplyStateAcknowledged)},
+				{Id: "1", Product: "APPSEC", Version: 1, ApplyState: uint64(state.ApplyStateError)},
+			},
+		},
+	}
+	r.recordClientState(client, directorTargets)
+	assert.Empty(t, r.halted)
+
+	client.State.ConfigStates[0].ApplyState = uint64(state.ApplyStateError)
+	r.recordClientState(client, directorTargets)
+	assert.Contains(t, r.halted, "canary")
+	assert.Equal(t, "canary", exportedHaltedRollouts.Value())
+	assert.Equal(t, []string{"canary"}, r.haltedIDs())
+
+	filtered, err = r.filter(configs, directorTargets)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"datadog/2/APPSEC/1/config"}, filtered)
+
+	// the rollout stays halted until a new version of its configs is pushed
+	r.recordClientState(&pbgo.Client{Id: "client-id", State: &pbgo.ClientState{}}, directorTargets)
+	assert.Contains(t, r.halted, "canary")
+
+	directorTargets["datadog/2/APM_SAMPLING/1/config"] = data.TargetFileMeta{Custom: rolloutMeta(&ConfigRollout{ID: "canary", Percentage: percentage(100)}, 2)}
+	r.recordClientState(client, directorTargets)
+	assert.Empty(t, r.halted, "errors on the previous version don't halt the new one")
+	assert.Empty(t, exportedHaltedRollouts.Value())
+	assert.Nil(t, r.haltedIDs())
+
+	filtered, err = r.filter(configs, directorTargets)
+	require.NoError(t, err)
+	assert.Len(t, filtered, 3)
+
+	// or until the halt expires
+	client.State.ConfigStates[0].Version = 2
+	r.recordClientState(client, directorTargets)
+	assert.Contains(t, r.halted, "canary")
+
+	clock.Add(rolloutHaltTTL + time.Second)
+	r.recordClientState(&pbgo.Client{Id: "client-id", State: &pbgo.ClientState{}}, directorTargets)
+	assert.Empty(t, r.halted)
+}
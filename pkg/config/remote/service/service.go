@@ -157,6 +157,7 @@ type CoreAgentService struct {
 	newProducts        map[rdata.Product]struct{}
 	clients            *clients
 	cacheBypassClients cacheBypassClients
+	rollouts           *rollouts
 
 	// Used to report metrics on cache bypass requests
 	telemetryReporter RcTelemetryReporter
@@ -194,6 +195,7 @@ type uptaneClient interface {
 type coreAgentUptaneClient interface {
 	uptaneClient
 	Update(response *pbgo.LatestConfigsResponse) error
+	Mirror(configRootVersion, directorRootVersion uint64) (*pbgo.LatestConfigsResponse, error)
 }
 
 // cdnUptaneClient provides functions to get TUF/uptane repo data and update the agent's state via the CDN.
@@ -216,6 +218,7 @@ func init() {
 	exportedMapStatus.Set("orgEnabled", &exportedStatusOrgEnabled)
 	exportedMapStatus.Set("apiKeyScoped", &exportedStatusKeyAuthorized)
 	exportedMapStatus.Set("lastError", &exportedLastUpdateErr)
+	exportedMapStatus.Set("haltedRollouts", &exportedHaltedRollouts)
 }
 
 type options struct {
@@ -234,6 +237,8 @@ type options struct {
 	maxBackoff                     time.Duration
 	clientTTL                      time.Duration
 	disableConfigPollLoop          bool
+	mirrorURL                      string
+	mirrorAuthToken                string
 }
 
 var defaultOptions = options{
@@ -251,6 +256,8 @@ var defaultOptions = options{
 	maxBackoff:                     minimalMaxBackoffTime,
 	clientTTL:                      defaultClientsTTL,
 	disableConfigPollLoop:          false,
+	mirrorURL:                      "",
+	mirrorAuthToken:                "",
 }
 
 // Option is a service option
@@ -372,6 +379,15 @@ func WithAgentPollLoopDisabled() func(s *options) {
 	}
 }
 
+// WithMirror fetches the configurations from a mirror, another agent serving its repositories,
+// instead of the Remote Configuration backend
+func WithMirror(mirrorURL string, authToken string) func(s *options) {
+	return func(s *options) {
+		s.mirrorURL = mirrorURL
+		s.mirrorAuthToken = authToken
+	}
+}
+
 // NewService instantiates a new remote configuration management service
 func NewService(cfg model.Reader, rcType, baseRawURL, hostname string, tagsGetter func() []string, telemetryReporter RcTelemetryReporter, agentVersion string, opts ...Option) (*CoreAgentService, error) {
 	options := defaultOptions
@@ -405,9 +421,21 @@ func NewService(cfg model.Reader, rcType, baseRawURL, hostname string, tagsGette
 	if err != nil {
 		return nil, err
 	}
-	http, err := api.NewHTTPClient(authKeys.apiAuth(), cfg, baseURL)
-	if err != nil {
-		return nil, err
+	var http *api.HTTPClient
+	if options.mirrorURL != "" {
+		mirrorURL, err := url.Parse(options.mirrorURL)
+		if err != nil {
+			return nil, err
+		}
+		http, err = api.NewMirrorHTTPClient(mirrorURL, options.mirrorAuthToken)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		http, err = api.NewHTTPClient(authKeys.apiAuth(), cfg, baseURL)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	databaseFilePath := cfg.GetString("run_path")
@@ -459,6 +487,7 @@ func NewService(cfg model.Reader, rcType, baseRawURL, hostname string, tagsGette
 		api:                            http,
 		uptane:                         uptaneClient,
 		clients:                        newClients(clock, options.clientTTL),
+		rollouts:                       newRollouts(clock, hostname, tagsGetter),
 		cacheBypassClients: cacheBypassClients{
 			clock:    clock,
 			requests: make(chan chan struct{}),
@@ -651,7 +680,7 @@ func (s *CoreAgentService) refresh() error {
 		return err
 	}
 
-	request := buildLatestConfigsRequest(s.hostname, s.agentVersion, s.tagsGetter(), s.traceAgentEnv, orgUUID, previousState, activeClients, s.products, s.newProducts, s.lastUpdateErr, clientState)
+	request := buildLatestConfigsRequest(s.hostname, s.agentVersion, s.tagsGetter(), s.traceAgentEnv, orgUUID, previousState, activeClients, s.products, s.newProducts, s.lastUpdateErr, clientState, s.rollouts.haltedIDs())
 	s.Unlock()
 	ctx := context.Background()
 	response, err := s.api.Fetch(ctx, request)
@@ -769,6 +798,34 @@ func (s *CoreAgentService) flushCacheResponse() (*pbgo.ClientGetConfigsResponse,
 	}, nil
 }
 
+// bypassCache triggers a bypass to directly get configurations from the backend for new clients,
+// and waits for it. It must be called without holding the service lock.
+// This will timeout to avoid blocking the tracer if:
+// - The previous request is still pending
+// - The triggered request takes too long
+func (s *CoreAgentService) bypassCache() {
+	response := make(chan struct{})
+	bypassStart := time.Now()
+
+	// Timeout in case the previous request is still pending
+	// and we can't request another one
+	select {
+	case s.cacheBypassClients.requests <- response:
+	case <-time.After(newClientBlockTTL):
+		// No need to add telemetry here, it'll be done in the second
+		// timeout case that will automatically be triggered
+	}
+
+	partialNewClientBlockTTL := newClientBlockTTL - time.Since(bypassStart)
+
+	// Timeout if the response is taking too long
+	select {
+	case <-response:
+	case <-time.After(partialNewClientBlockTTL):
+		s.telemetryReporter.IncTimeout()
+	}
+}
+
 // ClientGetConfigs is the polling API called by tracers and agents to get the latest configurations
 //
 //nolint:revive // TODO(RC) Fix revive linter
@@ -781,35 +838,9 @@ func (s *CoreAgentService) ClientGetConfigs(_ context.Context, request *pbgo.Cli
 	}
 
 	if !s.clients.active(request.Client) {
-		// Trigger a bypass to directly get configurations from the agent.
-		// This will timeout to avoid blocking the tracer if:
-		// - The previous request is still pending
-		// - The triggered request takes too long
-
 		s.clients.seen(request.Client)
 		s.Unlock()
-
-		response := make(chan struct{})
-		bypassStart := time.Now()
-
-		// Timeout in case the previous request is still pending
-		// and we can't request another one
-		select {
-		case s.cacheBypassClients.requests <- response:
-		case <-time.After(newClientBlockTTL):
-			// No need to add telemetry here, it'll be done in the second
-			// timeout case that will automatically be triggered
-		}
-
-		partialNewClientBlockTTL := newClientBlockTTL - time.Since(bypassStart)
-
-		// Timeout if the response is taking too long
-		select {
-		case <-response:
-		case <-time.After(partialNewClientBlockTTL):
-			s.telemetryReporter.IncTimeout()
-		}
-
+		s.bypassCache()
 		s.Lock()
 	}
 	if s.disableConfigPollLoop && s.lastUpdateErr != nil {
@@ -843,10 +874,15 @@ func (s *CoreAgentService) ClientGetConfigs(_ context.Context, request *pbgo.Cli
 	if err != nil {
 		return nil, err
 	}
+	s.rollouts.recordClientState(request.Client, directorTargets)
 	matchedClientConfigs, err := executeTracerPredicates(request.Client, directorTargets)
 	if err != nil {
 		return nil, err
 	}
+	matchedClientConfigs, err = s.rollouts.filter(matchedClientConfigs, directorTargets)
+	if err != nil {
+		return nil, err
+	}
 
 	neededFiles, err := filterNeededTargetFiles(matchedClientConfigs, request.CachedTargetFiles, directorTargets)
 	if err != nil {
@@ -90,6 +90,11 @@ func (m *mockCoreAgentUptane) Update(response *pbgo.LatestConfigsResponse) error
 	return args.Error(0)
 }
 
+func (m *mockCoreAgentUptane) Mirror(configRootVersion, directorRootVersion uint64) (*pbgo.LatestConfigsResponse, error) {
+	args := m.Called(configRootVersion, directorRootVersion)
+	return args.Get(0).(*pbgo.LatestConfigsResponse), args.Error(1)
+}
+
 func (m *mockCDNUptane) Update(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
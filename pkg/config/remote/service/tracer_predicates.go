@@ -20,6 +20,8 @@ import (
 type ConfigFileMetaCustom struct {
 	Predicates *pbgo.TracerPredicates `json:"tracer-predicates,omitempty"`
 	Expires    int64                  `json:"expires"`
+	Rollout    *ConfigRollout         `json:"rollout,omitempty"`
+	Version    uint64                 `json:"v"`
 }
 
 // Given the hostname and state will parse predicates and execute them
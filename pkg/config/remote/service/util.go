@@ -192,7 +192,7 @@ func getRemoteConfigAuthKeys(apiKey string, rcKey string, parJWT string) (remote
 	}, nil
 }
 
-func buildLatestConfigsRequest(hostname string, agentVersion string, tags []string, traceAgentEnv string, orgUUID string, state uptane.TUFVersions, activeClients []*pbgo.Client, products map[data.Product]struct{}, newProducts map[data.Product]struct{}, lastUpdateErr error, clientState []byte) *pbgo.LatestConfigsRequest {
+func buildLatestConfigsRequest(hostname string, agentVersion string, tags []string, traceAgentEnv string, orgUUID string, state uptane.TUFVersions, activeClients []*pbgo.Client, products map[data.Product]struct{}, newProducts map[data.Product]struct{}, lastUpdateErr error, clientState []byte, haltedRollouts []string) *pbgo.LatestConfigsRequest {
 	productsList := make([]data.Product, len(products))
 	i := 0
 	for k := range products {
@@ -226,6 +226,7 @@ func buildLatestConfigsRequest(hostname string, agentVersion string, tags []stri
 		TraceAgentEnv:                traceAgentEnv,
 		OrgUuid:                      orgUUID,
 		Tags:                         tags,
+		HaltedRollouts:               haltedRollouts,
 	}
 }
 
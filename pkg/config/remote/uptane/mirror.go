@@ -0,0 +1,154 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package uptane
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	pbgo "github.com/DataDog/datadog-agent/pkg/proto/pbgo/core"
+)
+
+// Mirror returns the current verified state of the config and director repositories as a backend
+// response, so that the uptane client of another agent can be updated from it.
+//
+// Only the roots more recent than the given versions are included. Every target file is included as
+// downstream agents can't tell which of them they already have: they only send cached target files
+// metadata for their own clients.
+func (c *Client) Mirror(configRootVersion, directorRootVersion uint64) (*pbgo.LatestConfigsResponse, error) {
+	c.Lock()
+	defer c.Unlock()
+	err := c.verify()
+	if err != nil {
+		return nil, err
+	}
+
+	configMetas, err := c.mirrorConfigMetas(configRootVersion)
+	if err != nil {
+		return nil, fmt.Errorf("could not mirror config repository: %w", err)
+	}
+	directorMetas, err := c.mirrorDirectorMetas(directorRootVersion)
+	if err != nil {
+		return nil, fmt.Errorf("could not mirror director repository: %w", err)
+	}
+
+	targets, err := c.directorTUFClient.Targets()
+	if err != nil {
+		return nil, err
+	}
+	targetFiles := make([]*pbgo.File, 0, len(targets))
+	for path := range targets {
+		raw, err := c.unsafeTargetFile(path)
+		if err != nil {
+			return nil, err
+		}
+		targetFiles = append(targetFiles, &pbgo.File{Path: path, Raw: raw})
+	}
+
+	return &pbgo.LatestConfigsResponse{
+		ConfigMetas:   configMetas,
+		DirectorMetas: directorMetas,
+		TargetFiles:   targetFiles,
+	}, nil
+}
+
+func (c *Client) mirrorConfigMetas(rootVersion uint64) (*pbgo.ConfigMetas, error) {
+	metas, err := c.configLocalStore.GetMeta()
+	if err != nil {
+		return nil, err
+	}
+	roots, err := mirrorRoots(c.configLocalStore, metas, rootVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	configMetas := &pbgo.ConfigMetas{Roots: roots}
+	for name, raw := range metas {
+		meta, err := mirrorTopMeta(raw)
+		if err != nil {
+			return nil, err
+		}
+		switch name {
+		case metaRoot:
+		case metaTimestamp:
+			configMetas.Timestamp = meta
+		case metaSnapshot:
+			configMetas.Snapshot = meta
+		case metaTargets:
+			configMetas.TopTargets = meta
+		default:
+			configMetas.DelegatedTargets = append(configMetas.DelegatedTargets, &pbgo.DelegatedMeta{
+				Version: meta.Version,
+				Role:    strings.TrimSuffix(name, ".json"),
+				Raw:     meta.Raw,
+			})
+		}
+	}
+	return configMetas, nil
+}
+
+func (c *Client) mirrorDirectorMetas(rootVersion uint64) (*pbgo.DirectorMetas, error) {
+	metas, err := c.directorLocalStore.GetMeta()
+	if err != nil {
+		return nil, err
+	}
+	roots, err := mirrorRoots(c.directorLocalStore, metas, rootVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	directorMetas := &pbgo.DirectorMetas{Roots: roots}
+	for name, raw := range metas {
+		meta, err := mirrorTopMeta(raw)
+		if err != nil {
+			return nil, err
+		}
+		switch name {
+		case metaTimestamp:
+			directorMetas.Timestamp = meta
+		case metaSnapshot:
+			directorMetas.Snapshot = meta
+		case metaTargets:
+			directorMetas.Targets = meta
+		}
+	}
+	return directorMetas, nil
+}
+
+// mirrorRoots returns the roots of a repository more recent than the given version
+func mirrorRoots(store *localStore, metas map[string]json.RawMessage, fromVersion uint64) ([]*pbgo.TopMeta, error) {
+	currentRoot, found := metas[metaRoot]
+	if !found {
+		return nil, fmt.Errorf("empty root meta in local store")
+	}
+	currentVersion, err := unsafeMetaVersion(currentRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []*pbgo.TopMeta
+	for version := fromVersion + 1; version <= currentVersion; version++ {
+		root, found, err := store.GetRoot(version)
+		if err != nil {
+			return nil, err
+		}
+		// the roots older than the embedded one were never stored
+		if !found {
+			continue
+		}
+		roots = append(roots, &pbgo.TopMeta{Version: version, Raw: root})
+	}
+	return roots, nil
+}
+
+func mirrorTopMeta(raw json.RawMessage) (*pbgo.TopMeta, error) {
+	version, err := unsafeMetaVersion(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &pbgo.TopMeta{Version: version, Raw: raw}, nil
+}
@@ -0,0 +1,45 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package uptane
+
+import (
+	"testing"
+
+	"github.com/DataDog/go-tuf/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pbgo "github.com/DataDog/datadog-agent/pkg/proto/pbgo/core"
+)
+
+func TestClientMirror(t *testing.T) {
+	target1content, target1 := generateTarget()
+	targets := data.TargetFiles{
+		"datadog/2/APM_SAMPLING/id/1": target1,
+	}
+	testRepository := newTestRepository(2, 1, targets, targets, []*pbgo.File{{Path: "datadog/2/APM_SAMPLING/id/1", Raw: target1content}})
+	cfg := newTestConfig(t, testRepository)
+
+	mirror, err := newTestClient(getTestDB(t), cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, mirror.Update(testRepository.toUpdate()))
+
+	response, err := mirror.Mirror(1, 1)
+	require.NoError(t, err)
+	assert.Empty(t, response.ConfigMetas.Roots)
+	assert.Empty(t, response.DirectorMetas.Roots)
+	assert.Equal(t, uint64(testRepository.directorTargetsVersion), response.DirectorMetas.Targets.Version)
+	assert.Equal(t, uint64(testRepository.configSnapshotVersion), response.ConfigMetas.Snapshot.Version)
+
+	downstream, err := newTestClient(getTestDB(t), cfg)
+	require.NoError(t, err)
+	require.NoError(t, downstream.Update(response))
+
+	targetFile, err := downstream.TargetFile("datadog/2/APM_SAMPLING/id/1")
+	require.NoError(t, err)
+	assert.Equal(t, target1content, targetFile)
+}
@@ -20,6 +20,12 @@ type Client interface {
 	List() (map[string]settings.RuntimeSettingResponse, error)
 	FullConfig() (string, error)
 	FullConfigBySource() (string, error)
+	Stage(values map[string]string) (map[string]interface{}, error)
+	Staged() (map[string]interface{}, error)
+	Commit() ([]settings.AppliedOverride, error)
+	Rollback() error
+	Overrides() ([]settings.AppliedOverride, error)
+	Provenance(onlyChanged bool) ([]settings.SettingProvenance, error)
 	HTTPClient() *http.Client
 }
 
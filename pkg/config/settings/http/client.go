@@ -143,6 +143,105 @@ func (rc *runtimeSettingsHTTPClient) Set(key string, value string) (bool, error)
 	return hidden, nil
 }
 
+func (rc *runtimeSettingsHTTPClient) doPost(url string, body []byte) ([]byte, error) {
+	r, err := util.DoPost(rc.c, url, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		errMap := make(map[string]string)
+		_ = json.Unmarshal(r, &errMap)
+		// If the error has been marshalled into a json object, check it and return it properly
+		if e, found := errMap["error"]; found {
+			return nil, errors.New(e)
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+func (rc *runtimeSettingsHTTPClient) Stage(values map[string]string) (map[string]interface{}, error) {
+	body, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := rc.doPost(fmt.Sprintf("%s/transaction/stage", rc.baseURL), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Staged map[string]interface{} `json:"staged"`
+	}
+	if err := json.Unmarshal(r, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Staged, nil
+}
+
+func (rc *runtimeSettingsHTTPClient) Staged() (map[string]interface{}, error) {
+	r, err := rc.doGet(fmt.Sprintf("%s/transaction/staged", rc.baseURL), false)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Staged map[string]interface{} `json:"staged"`
+	}
+	if err := json.Unmarshal([]byte(r), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Staged, nil
+}
+
+func (rc *runtimeSettingsHTTPClient) Commit() ([]settingsComponent.AppliedOverride, error) {
+	r, err := rc.doPost(fmt.Sprintf("%s/transaction/commit", rc.baseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Applied []settingsComponent.AppliedOverride `json:"applied"`
+	}
+	if err := json.Unmarshal(r, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Applied, nil
+}
+
+func (rc *runtimeSettingsHTTPClient) Rollback() error {
+	_, err := rc.doPost(fmt.Sprintf("%s/transaction/rollback", rc.baseURL), nil)
+	return err
+}
+
+func (rc *runtimeSettingsHTTPClient) Overrides() ([]settingsComponent.AppliedOverride, error) {
+	r, err := rc.doGet(fmt.Sprintf("%s/transaction/overrides", rc.baseURL), false)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Overrides []settingsComponent.AppliedOverride `json:"overrides"`
+	}
+	if err := json.Unmarshal([]byte(r), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Overrides, nil
+}
+
+func (rc *runtimeSettingsHTTPClient) Provenance(onlyChanged bool) ([]settingsComponent.SettingProvenance, error) {
+	r, err := rc.doGet(fmt.Sprintf("%s/provenance/all?diff=%t", rc.baseURL, onlyChanged), true)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Settings []settingsComponent.SettingProvenance `json:"settings"`
+	}
+	if err := json.Unmarshal([]byte(r), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Settings, nil
+}
+
 func (rc *runtimeSettingsHTTPClient) HTTPClient() *http.Client {
 	return rc.c
 }
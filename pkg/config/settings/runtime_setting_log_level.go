@@ -6,6 +6,8 @@
 package settings
 
 import (
+	"fmt"
+
 	"github.com/DataDog/datadog-agent/comp/core/config"
 	"github.com/DataDog/datadog-agent/pkg/config/model"
 	pkgconfigutils "github.com/DataDog/datadog-agent/pkg/config/utils"
@@ -48,6 +50,16 @@ func (l *LogLevelRuntimeSetting) Get(_ config.Component) (interface{}, error) {
 	return level.String(), nil
 }
 
+// Validate checks that the given value is a valid log level
+func (l *LogLevelRuntimeSetting) Validate(v interface{}) error {
+	level, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("log level must be a string, got %T", v)
+	}
+	_, err := log.ValidateLogLevel(level)
+	return err
+}
+
 // Set changes the value of the runtime setting
 func (l *LogLevelRuntimeSetting) Set(config config.Component, v interface{}, source model.Source) error {
 	level := v.(string)
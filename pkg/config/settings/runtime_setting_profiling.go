@@ -78,16 +78,17 @@ func (l *ProfilingRuntimeSetting) Set(config config.Component, v interface{}, so
 		}
 
 		// allow full url override for development use
-		site := fmt.Sprintf(profiling.ProfilingURLTemplate, s)
-		if config.IsSet(l.ConfigPrefix + "internal_profiling.profile_dd_url") {
-			site = config.GetString(l.ConfigPrefix + "internal_profiling.profile_dd_url")
-		}
+		site := profiling.IntakeURL(
+			config.GetString(l.ConfigPrefix+"internal_profiling.profile_dd_url"),
+			config.GetBool(l.ConfigPrefix+"internal_profiling.use_trace_agent_proxy"),
+			traceAgentReceiverPort(config),
+			s,
+		)
 
 		// Note that we must derive a new profiling.Settings on every
 		// invocation, as many of these settings may have changed at runtime.
 
-		tags := config.GetStringSlice(l.ConfigPrefix + "internal_profiling.extra_tags")
-		tags = append(tags, fmt.Sprintf("version:%v", version.AgentVersion))
+		tags := profiling.Tags(config.GetStringSlice(l.ConfigPrefix+"internal_profiling.extra_tags"), l.Service, version.AgentVersion)
 
 		settings := profiling.Settings{
 			ProfilingURL:         site,
@@ -116,3 +117,12 @@ func (l *ProfilingRuntimeSetting) Set(config config.Component, v interface{}, so
 
 	return nil
 }
+
+// traceAgentReceiverPort returns the receiver port of the local trace-agent. It is read from the Agent configuration
+// when the given configuration doesn't include it, like the system-probe one.
+func traceAgentReceiverPort(config config.Component) int {
+	if config.IsKnown("apm_config.receiver_port") {
+		return config.GetInt("apm_config.receiver_port")
+	}
+	return pkgconfigsetup.Datadog().GetInt("apm_config.receiver_port")
+}
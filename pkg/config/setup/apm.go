@@ -12,6 +12,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	pkgconfigmodel "github.com/DataDog/datadog-agent/pkg/config/model"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -82,6 +83,7 @@ func setupAPM(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("apm_config.windows_pipe_security_descriptor", "D:AI(A;;GA;;;WD)", "DD_APM_WINDOWS_PIPE_SECURITY_DESCRIPTOR") //nolint:errcheck
 	config.BindEnvAndSetDefault("apm_config.peer_service_aggregation", true, "DD_APM_PEER_SERVICE_AGGREGATION")                               //nolint:errcheck
 	config.BindEnvAndSetDefault("apm_config.peer_tags_aggregation", true, "DD_APM_PEER_TAGS_AGGREGATION")                                     //nolint:errcheck
+	config.BindEnvAndSetDefault("apm_config.default_peer_tags_aggregation", true, "DD_APM_DEFAULT_PEER_TAGS_AGGREGATION")                     //nolint:errcheck
 	config.BindEnvAndSetDefault("apm_config.compute_stats_by_span_kind", true, "DD_APM_COMPUTE_STATS_BY_SPAN_KIND")                           //nolint:errcheck
 	config.BindEnvAndSetDefault("apm_config.instrumentation.enabled", false, "DD_APM_INSTRUMENTATION_ENABLED")
 	config.BindEnvAndSetDefault("apm_config.instrumentation.enabled_namespaces", []string{}, "DD_APM_INSTRUMENTATION_ENABLED_NAMESPACES")
@@ -125,6 +127,7 @@ func setupAPM(config pkgconfigmodel.Setup) {
 	config.BindEnv("apm_config.decoders", "DD_APM_DECODERS")
 	config.BindEnv("apm_config.max_connections", "DD_APM_MAX_CONNECTIONS")
 	config.BindEnv("apm_config.decoder_timeout", "DD_APM_DECODER_TIMEOUT")
+	config.BindEnv("apm_config.drain_timeout", "DD_APM_DRAIN_TIMEOUT")
 	config.BindEnv("apm_config.log_file", "DD_APM_LOG_FILE")
 	config.BindEnv("apm_config.max_events_per_second", "DD_APM_MAX_EPS", "DD_MAX_EPS")
 	config.BindEnv("apm_config.max_traces_per_second", "DD_APM_MAX_TPS", "DD_MAX_TPS") // deprecated
@@ -133,10 +136,24 @@ func setupAPM(config pkgconfigmodel.Setup) {
 	config.BindEnv("apm_config.enable_rare_sampler", "DD_APM_ENABLE_RARE_SAMPLER")
 	config.BindEnv("apm_config.disable_rare_sampler", "DD_APM_DISABLE_RARE_SAMPLER") // Deprecated
 	config.BindEnv("apm_config.max_remote_traces_per_second", "DD_APM_MAX_REMOTE_TPS")
+	config.BindEnvAndSetDefault("apm_config.sampler_state.enabled", false, "DD_APM_SAMPLER_STATE_ENABLED")
+	config.BindEnvAndSetDefault("apm_config.sampler_state.path", "", "DD_APM_SAMPLER_STATE_PATH") // defaults to <run_path>/trace_sampler_state.json
+	config.BindEnvAndSetDefault("apm_config.sampler_state.interval", time.Minute, "DD_APM_SAMPLER_STATE_INTERVAL")
+	config.BindEnvAndSetDefault("apm_config.sampler_state.max_age", 10*time.Minute, "DD_APM_SAMPLER_STATE_MAX_AGE")
 	config.BindEnv("apm_config.probabilistic_sampler.enabled", "DD_APM_PROBABILISTIC_SAMPLER_ENABLED")
 	config.BindEnv("apm_config.probabilistic_sampler.sampling_percentage", "DD_APM_PROBABILISTIC_SAMPLER_SAMPLING_PERCENTAGE")
 	config.BindEnv("apm_config.probabilistic_sampler.hash_seed", "DD_APM_PROBABILISTIC_SAMPLER_HASH_SEED")
 	config.BindEnvAndSetDefault("apm_config.error_tracking_standalone.enabled", false, "DD_APM_ERROR_TRACKING_STANDALONE_ENABLED")
+	config.BindEnvAndSetDefault("apm_config.error_fingerprints.enabled", false, "DD_APM_ERROR_FINGERPRINTS_ENABLED")
+	config.BindEnvAndSetDefault("apm_config.error_fingerprints.stack_frames", 5, "DD_APM_ERROR_FINGERPRINTS_STACK_FRAMES")
+	config.BindEnv("apm_config.ingestion_quotas", "DD_APM_INGESTION_QUOTAS")
+	config.ParseEnvAsSlice("apm_config.ingestion_quotas", func(in string) []interface{} {
+		var quotas []interface{}
+		if err := json.Unmarshal([]byte(in), &quotas); err != nil {
+			log.Errorf(`"apm_config.ingestion_quotas" can not be parsed: %v`, err)
+		}
+		return quotas
+	})
 
 	config.BindEnv("apm_config.max_memory", "DD_APM_MAX_MEMORY")
 	config.BindEnv("apm_config.max_cpu_percent", "DD_APM_MAX_CPU_PERCENT")
@@ -171,6 +188,8 @@ func setupAPM(config pkgconfigmodel.Setup) {
 	config.BindEnv("apm_config.debugger_dd_url", "DD_APM_DEBUGGER_DD_URL")
 	config.BindEnv("apm_config.debugger_api_key", "DD_APM_DEBUGGER_API_KEY")
 	config.BindEnv("apm_config.debugger_additional_endpoints", "DD_APM_DEBUGGER_ADDITIONAL_ENDPOINTS")
+	config.BindEnvAndSetDefault("apm_config.debugger_dedup_window", "0s", "DD_APM_DEBUGGER_DEDUP_WINDOW")
+	config.BindEnvAndSetDefault("apm_config.debugger_probe_rate_limit", 100.0, "DD_APM_DEBUGGER_PROBE_RATE_LIMIT")
 	config.BindEnv("apm_config.debugger_diagnostics_dd_url", "DD_APM_DEBUGGER_DIAGNOSTICS_DD_URL")
 	config.BindEnv("apm_config.debugger_diagnostics_api_key", "DD_APM_DEBUGGER_DIAGNOSTICS_API_KEY")
 	config.BindEnv("apm_config.debugger_diagnostics_additional_endpoints", "DD_APM_DEBUGGER_DIAGNOSTICS_ADDITIONAL_ENDPOINTS")
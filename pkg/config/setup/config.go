@@ -122,6 +122,9 @@ const (
 
 	// DefaultNetworkPathMaxTTL defines the default maximum TTL for traceroute tests
 	DefaultNetworkPathMaxTTL = 30
+
+	// DefaultNetworkPathProbesPerHop defines the default number of probes sent to each hop for traceroute tests
+	DefaultNetworkPathProbesPerHop = 1
 )
 
 // datadog is the global configuration object
@@ -328,6 +331,14 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	// library support will not work reliably in those environments)
 	config.BindEnvAndSetDefault("allow_python_path_heuristics_failure", false)
 
+	// Resource budgets of the Python check instances. An instance whose runs grow the interpreter
+	// memory by more than max_memory_growth bytes, or whose max_run_time_violations last runs took
+	// longer than max_run_time, is quarantined and not run anymore until it is rescheduled.
+	config.BindEnvAndSetDefault("python_check_budget.enabled", false)
+	config.BindEnvAndSetDefault("python_check_budget.max_memory_growth", 256*1024*1024)
+	config.BindEnvAndSetDefault("python_check_budget.max_run_time", 5*time.Minute)
+	config.BindEnvAndSetDefault("python_check_budget.max_run_time_violations", 3)
+
 	// if/when the default is changed to true, make the default platform
 	// dependent; default should remain false on Windows to maintain backward
 	// compatibility with Agent5 behavior/win
@@ -337,6 +348,10 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	// canonical hostname, otherwise the instance-id is used as canonical hostname.
 	config.BindEnvAndSetDefault("hostname_force_config_as_canonical", false)
 
+	// Ordered list of the providers used to resolve the hostname, e.g. ["configuration", "aws:2s", "os"]. Each
+	// provider can be given a timeout. When empty, the default resolution is used.
+	config.BindEnvAndSetDefault("hostname_providers", []string{})
+
 	// By default the Agent does not trust the hostname value retrieved from non-root UTS namespace.
 	// When enabled, the Agent will trust the value retrieved from non-root UTS namespace instead of failing
 	// hostname resolution.
@@ -349,6 +364,8 @@ func InitConfig(config pkgconfigmodel.Setup) {
 
 	// secrets backend
 	config.BindEnvAndSetDefault("secret_backend_command", "")
+	config.BindEnvAndSetDefault("secret_backend_type", "")
+	config.SetKnown("secret_backend_config")
 	config.BindEnvAndSetDefault("secret_backend_arguments", []string{})
 	config.BindEnvAndSetDefault("secret_backend_output_max_size", 0)
 	config.BindEnvAndSetDefault("secret_backend_timeout", 0)
@@ -373,6 +390,15 @@ func InitConfig(config pkgconfigmodel.Setup) {
 
 	config.BindEnvAndSetDefault("flare.rc_streamlogs.duration", 60*time.Second)
 
+	// flare redaction: custom patterns, file exclusions and directory size caps
+	config.BindEnv("flare.redaction.custom_patterns")
+	config.BindEnvAndSetDefault("flare.redaction.exclude_files", []string{})
+	config.BindEnvAndSetDefault("flare.redaction.max_dir_size", int64(0))
+
+	// status configs
+	config.BindEnvAndSetDefault("status.provider_cache_ttl", time.Duration(0))
+	config.BindEnvAndSetDefault("status.provider_timeout", 10*time.Second)
+
 	// Docker
 	config.BindEnvAndSetDefault("docker_query_timeout", int64(5))
 	config.BindEnvAndSetDefault("docker_labels_as_tags", map[string]string{})
@@ -541,6 +567,11 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("cluster_agent.language_detection.cleanup.language_ttl", "30m")
 	// language annotation cleanup period
 	config.BindEnvAndSetDefault("cluster_agent.language_detection.cleanup.period", "10m")
+	config.BindEnvAndSetDefault("cluster_agent.security_profiles.enabled", false)
+	// maximum number of security profiles shared between the nodes, one per image name
+	config.BindEnvAndSetDefault("cluster_agent.security_profiles.max_profiles", 1000)
+	// expiration deadline (TTL) of the security profiles that are no longer uploaded by any node
+	config.BindEnvAndSetDefault("cluster_agent.security_profiles.ttl", "72h")
 	config.BindEnvAndSetDefault("cluster_agent.kube_metadata_collection.enabled", false)
 	// list of kubernetes resources for which we collect metadata
 	// each resource is specified in the format `{group}/{version}/{resource}` or `{group}/{resource}`
@@ -559,6 +590,9 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	// - nodes
 	config.BindEnvAndSetDefault("cluster_agent.kube_metadata_collection.resources", []string{})
 	config.BindEnvAndSetDefault("cluster_agent.kube_metadata_collection.resource_annotations_exclude", []string{})
+	// list of group resources, like `endpoints` or `deployments.apps`, whose informers are not started
+	// by the cluster agent, to reduce the load on the API server when the related features aren't used
+	config.BindEnvAndSetDefault("cluster_agent.disabled_informers", []string{})
 	config.BindEnvAndSetDefault("cluster_agent.cluster_tagger.grpc_max_message_size", 4<<20) // 4 MB
 	// the entity id, typically set by dca admisson controller config mutator, used for external origin detection
 	config.SetKnown("entity_id")
@@ -671,6 +705,8 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("internal_profiling.enabled", false)
 	config.BindEnv("internal_profiling.profile_dd_url")
 	config.BindEnvAndSetDefault("internal_profiling.unix_socket", "") // file system path to a unix socket, e.g. `/var/run/datadog/apm.socket`
+	// send the profiles through the profiling proxy of the local trace-agent instead of the profiling intake
+	config.BindEnvAndSetDefault("internal_profiling.use_trace_agent_proxy", false)
 	config.BindEnvAndSetDefault("internal_profiling.period", 5*time.Minute)
 	config.BindEnvAndSetDefault("internal_profiling.cpu_duration", 1*time.Minute)
 	config.BindEnvAndSetDefault("internal_profiling.block_profile_rate", 0)
@@ -720,6 +756,10 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("external_metrics_provider.split_batches_with_backoff", false)  // Splits batches and runs queries with errors individually with an exponential backoff
 	config.BindEnvAndSetDefault("external_metrics_provider.num_workers", 2)                     // Number of workers spawned by controller (only when CRD is used)
 	config.BindEnvAndSetDefault("external_metrics_provider.max_parallel_queries", 10)           // Maximum number of parallel queries sent to Datadog simultaneously
+
+	config.BindEnvAndSetDefault("external_metrics_provider.prometheus.endpoint", "")                 // Prometheus compatible endpoint evaluating the queries of the DatadogMetrics annotated with the prometheus query backend
+	config.BindEnvAndSetDefault("external_metrics_provider.prometheus.timeout", 10)                  // value in seconds. Timeout of the queries sent to the Prometheus endpoint
+	config.BindEnvAndSetDefault("external_metrics_provider.prometheus.headers", map[string]string{}) // HTTP headers added to the queries sent to the Prometheus endpoint, such as X-Scope-OrgID
 	pkgconfigmodel.AddOverrideFunc(sanitizeExternalMetricsProviderChunkSize)
 	// Cluster check Autodiscovery
 	config.BindEnvAndSetDefault("cluster_checks.support_hybrid_ignore_ad_tags", false) // TODO(CINT)(Agent 7.53+) Remove this flag when hybrid ignore_ad_tags is fully deprecated
@@ -732,6 +772,8 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("cluster_checks.advanced_dispatching_enabled", false)
 	config.BindEnvAndSetDefault("cluster_checks.rebalance_with_utilization", false)        // Experimental. Subject to change. Uses the runners utilization to balance.
 	config.BindEnvAndSetDefault("cluster_checks.rebalance_min_percentage_improvement", 10) // Experimental. Subject to change. Rebalance only if the distribution found improves the current one by this.
+	config.BindEnvAndSetDefault("cluster_checks.rebalance_with_resource_usage", false)     // Experimental. Subject to change. Uses the CPU and memory usage of the runners to balance.
+	config.BindEnvAndSetDefault("cluster_checks.rebalance_resource_usage_tolerance", 0.2)  // Experimental. Subject to change. Move checks away from runners using this ratio more CPU or memory than the average.
 	config.BindEnvAndSetDefault("cluster_checks.clc_runners_port", 5005)
 	config.BindEnvAndSetDefault("cluster_checks.exclude_checks", []string{})
 	config.BindEnvAndSetDefault("cluster_checks.exclude_checks_from_dispatching", []string{})
@@ -745,10 +787,22 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("clc_runner_server_write_timeout", 15)
 	config.BindEnvAndSetDefault("clc_runner_server_readheader_timeout", 10)
 	config.BindEnvAndSetDefault("clc_runner_remote_tagger_enabled", false)
+	// Filter expression on the entities streamed to the remote tagger of the CLC runner, see comp/core/tagger/types.ParseFilterExpression
+	config.BindEnvAndSetDefault("clc_runner_remote_tagger_filter", "")
 
 	// Remote tagger
 	config.BindEnvAndSetDefault("remote_tagger.max_concurrent_sync", 3)
 
+	// Tagger cardinality budget: the number of unique values of each tag a source can emit, 0 is unlimited
+	config.BindEnvAndSetDefault("tagger_cardinality_budget.max_values", 0)
+	config.BindEnvAndSetDefault("tagger_cardinality_budget.max_values_by_source", map[string]int{})
+
+	// Tagger snapshot: the tags of the containers and pods are persisted across restarts
+	config.BindEnvAndSetDefault("tagger_snapshot.enabled", false)
+	config.BindEnvAndSetDefault("tagger_snapshot.path", "") // defaults to <run_path>/tagger_snapshot.json
+	config.BindEnvAndSetDefault("tagger_snapshot.max_age", 10*time.Minute)
+	config.BindEnvAndSetDefault("tagger_snapshot.ttl", 2*time.Minute)
+
 	// CSI driver
 	config.BindEnvAndSetDefault("csi.enabled", false)
 	config.BindEnvAndSetDefault("csi.driver", "k8s.csi.datadoghq.com")
@@ -788,6 +842,7 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.patcher.enabled", false)
 	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.patcher.fallback_to_file_provider", false)                                // to be enabled only in e2e tests
 	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.patcher.file_provider_path", "/etc/datadog-agent/patch/auto-instru.json") // to be used only in e2e tests
+	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.remote_config.enabled", false)                                            // allows overriding the library versions and the namespaces through remote config
 	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.inject_auto_detected_libraries", true)                                    // allows injecting libraries for languages detected by automatic language detection feature
 	config.BindEnv("admission_controller.auto_instrumentation.init_resources.cpu")
 	config.BindEnv("admission_controller.auto_instrumentation.init_resources.memory")
@@ -796,6 +851,17 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	config.BindEnv("admission_controller.auto_instrumentation.iast.enabled", "DD_ADMISSION_CONTROLLER_AUTO_INSTRUMENTATION_IAST_ENABLED")           // config for IAST which is implemented in the client libraries
 	config.BindEnv("admission_controller.auto_instrumentation.asm_sca.enabled", "DD_ADMISSION_CONTROLLER_AUTO_INSTRUMENTATION_APPSEC_SCA_ENABLED")  // config for SCA
 	config.BindEnv("admission_controller.auto_instrumentation.profiling.enabled", "DD_ADMISSION_CONTROLLER_AUTO_INSTRUMENTATION_PROFILING_ENABLED") // config for profiling
+
+	config.BindEnvAndSetDefault("admission_controller.resource_defaults.enabled", false)
+	config.BindEnvAndSetDefault("admission_controller.resource_defaults.endpoint", "/resourcedefaults")
+	config.BindEnvAndSetDefault("admission_controller.resource_defaults.enabled_namespaces", []string{})
+	config.BindEnvAndSetDefault("admission_controller.resource_defaults.disabled_namespaces", []string{})
+	config.BindEnvAndSetDefault("admission_controller.resource_defaults.audit_only", false)
+	config.BindEnvAndSetDefault("admission_controller.resource_defaults.usage_window", 86400) // in seconds
+	config.BindEnvAndSetDefault("admission_controller.resource_defaults.cache_validity", 60)  // in minutes
+	config.BindEnvAndSetDefault("admission_controller.resource_defaults.request_margin", 0.1)
+	config.BindEnvAndSetDefault("admission_controller.resource_defaults.limit_margin", 0.2)
+
 	config.BindEnvAndSetDefault("admission_controller.cws_instrumentation.enabled", false)
 	config.BindEnvAndSetDefault("admission_controller.cws_instrumentation.pod_endpoint", "/inject-pod-cws")
 	config.BindEnvAndSetDefault("admission_controller.cws_instrumentation.command_endpoint", "/inject-command-cws")
@@ -839,6 +905,8 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("orchestrator_explorer.container_scrubbing.enabled", true)
 	config.BindEnvAndSetDefault("orchestrator_explorer.custom_sensitive_words", []string{})
 	config.BindEnvAndSetDefault("orchestrator_explorer.custom_sensitive_annotations_labels", []string{})
+	config.BindEnv("orchestrator_explorer.env_var_redaction.patterns")
+	config.BindEnv("orchestrator_explorer.env_var_redaction.cluster_overrides")
 	config.BindEnvAndSetDefault("orchestrator_explorer.collector_discovery.enabled", true)
 	config.BindEnv("orchestrator_explorer.max_per_message")
 	config.BindEnv("orchestrator_explorer.max_message_bytes")
@@ -850,6 +918,14 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("orchestrator_explorer.manifest_collection.buffer_flush_interval", 20*time.Second)
 	config.BindEnvAndSetDefault("orchestrator_explorer.terminated_resources.enabled", false)
 	config.BindEnvAndSetDefault("orchestrator_explorer.terminated_pods.enabled", false)
+	// Emit the startup latency of the pods running on the node, computed from their conditions
+	config.BindEnvAndSetDefault("orchestrator_explorer.pod_startup_metrics.enabled", false)
+	// Skip the ReplicaSets scaled to zero and older than min_age, after reporting them once as deleted
+	config.BindEnvAndSetDefault("orchestrator_explorer.replicaset_pruning.enabled", false)
+	config.BindEnvAndSetDefault("orchestrator_explorer.replicaset_pruning.min_age", 24*time.Hour)
+	// Resources are reported again after ttl even if unchanged, the cache holds at most max_entries resources
+	config.BindEnvAndSetDefault("orchestrator_explorer.resource_cache.ttl", 3*time.Minute)
+	config.BindEnvAndSetDefault("orchestrator_explorer.resource_cache.max_entries", 100000)
 
 	// Container lifecycle configuration
 	config.BindEnvAndSetDefault("container_lifecycle.enabled", true)
@@ -858,6 +934,8 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	// Container image configuration
 	config.BindEnvAndSetDefault("container_image.enabled", true)
 	bindEnvAndSetLogsConfigKeys(config, "container_image.")
+	// List of {prefix, provider, ...} entries configuring the credentials used to retrieve the content of images from private registries
+	config.SetKnown("container_image.registry_credentials")
 
 	// Remote process collector
 	config.BindEnvAndSetDefault("workloadmeta.local_process_collector.collection_interval", DefaultLocalProcessCollectorInterval)
@@ -927,6 +1005,10 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	// debug config to enable a remote client to receive data from the workloadmeta agent without a timeout
 	config.BindEnvAndSetDefault("workloadmeta.remote.recv_without_timeout", true)
 
+	// external processes attaching metadata to the workloadmeta entities, see
+	// the WorkloadmetaEnricher gRPC service
+	config.BindEnv("workloadmeta.remote_enrichers")
+
 	config.BindEnvAndSetDefault("security_agent.internal_profiling.enabled", false, "DD_SECURITY_AGENT_INTERNAL_PROFILING_ENABLED")
 	config.BindEnvAndSetDefault("security_agent.internal_profiling.site", DefaultSite, "DD_SECURITY_AGENT_INTERNAL_PROFILING_SITE", "DD_SITE")
 	config.BindEnvAndSetDefault("security_agent.internal_profiling.profile_dd_url", "", "DD_SECURITY_AGENT_INTERNAL_PROFILING_DD_URL", "DD_APM_INTERNAL_PROFILING_DD_URL")
@@ -941,6 +1023,7 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("security_agent.internal_profiling.enable_mutex_profiling", false)
 	config.BindEnvAndSetDefault("security_agent.internal_profiling.delta_profiles", true)
 	config.BindEnvAndSetDefault("security_agent.internal_profiling.unix_socket", "")
+	config.BindEnvAndSetDefault("security_agent.internal_profiling.use_trace_agent_proxy", false)
 	config.BindEnvAndSetDefault("security_agent.internal_profiling.extra_tags", []string{})
 
 	// Datadog security agent (compliance)
@@ -951,6 +1034,9 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("compliance_config.check_interval", 20*time.Minute)
 	config.BindEnvAndSetDefault("compliance_config.check_max_events_per_run", 100)
 	config.BindEnvAndSetDefault("compliance_config.dir", "/etc/datadog-agent/compliance.d")
+	config.BindEnvAndSetDefault("compliance_config.custom_benchmarks.dir", "")
+	config.BindEnvAndSetDefault("compliance_config.custom_benchmarks.remote_config.enabled", false)
+	config.BindEnvAndSetDefault("compliance_config.openscap_results.dir", "")
 	config.BindEnv("compliance_config.run_commands_as")
 	bindEnvAndSetLogsConfigKeys(config, "compliance_config.endpoints.")
 	config.BindEnvAndSetDefault("compliance_config.metrics.enabled", false)
@@ -1076,6 +1162,8 @@ func agent(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("sslkeylogfile", "")
 	config.BindEnv("tls_handshake_timeout")
 	config.BindEnv("http_dial_fallback_delay")
+	// Settings of the HTTP clients by destination, see pkg/util/http.Destination
+	config.BindEnvAndSetDefault("http_destinations", map[string]interface{}{})
 	config.BindEnvAndSetDefault("hostname", "")
 	config.BindEnvAndSetDefault("hostname_file", "")
 	config.BindEnvAndSetDefault("tags", []string{})
@@ -1130,6 +1218,7 @@ func agent(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("auth_init_timeout", 10*time.Second)
 	config.BindEnv("bind_host")
 	config.BindEnvAndSetDefault("health_port", int64(0))
+	config.BindEnvAndSetDefault("health_supervisor.enabled", true)
 	config.BindEnvAndSetDefault("disable_py3_validation", false)
 	config.BindEnvAndSetDefault("python_version", DefaultPython)
 	config.BindEnvAndSetDefault("win_skip_com_init", false)
@@ -1200,6 +1289,10 @@ func remoteconfig(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("remote_configuration.max_backoff_interval", 5*time.Minute)
 	config.BindEnvAndSetDefault("remote_configuration.clients.ttl_seconds", 30*time.Second)
 	config.BindEnvAndSetDefault("remote_configuration.clients.cache_bypass_limit", 5)
+	// Mirror: serve the repositories to downstream agents, or get them from an upstream agent
+	config.BindEnvAndSetDefault("remote_configuration.mirror.enabled", false)
+	config.BindEnvAndSetDefault("remote_configuration.mirror.url", "")
+	config.BindEnvAndSetDefault("remote_configuration.mirror.auth_token", "")
 	// Remote config products
 	config.BindEnvAndSetDefault("remote_configuration.apm_sampling.enabled", true)
 	config.BindEnvAndSetDefault("remote_configuration.agent_integrations.enabled", false)
@@ -1314,6 +1407,12 @@ func telemetry(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("telemetry.dogstatsd.aggregator_channel_latency_buckets", []string{})
 	// The histogram buckets use to track the time in nanoseconds it takes for a DogStatsD listeners to push data to the server
 	config.BindEnvAndSetDefault("telemetry.dogstatsd.listeners_channel_latency_buckets", []string{})
+	// Export the internal telemetry, the metrics exposed by the /telemetry endpoint, to an OTLP/HTTP endpoint
+	config.BindEnvAndSetDefault("telemetry.otlp.enabled", false)
+	config.BindEnvAndSetDefault("telemetry.otlp.endpoint", "")
+	config.BindEnvAndSetDefault("telemetry.otlp.headers", map[string]string{})
+	config.BindEnvAndSetDefault("telemetry.otlp.export_interval", 60*time.Second)
+	config.BindEnvAndSetDefault("telemetry.otlp.timeout", 10*time.Second)
 
 	// Agent Telemetry
 	config.BindEnvAndSetDefault("agent_telemetry.enabled", true)
@@ -1357,6 +1456,8 @@ func aggregator(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("basic_telemetry_add_container_tags", false) // configure adding the agent container tags to the basic agent telemetry metrics (e.g. `datadog.agent.running`)
 	config.BindEnvAndSetDefault("aggregator_flush_metrics_and_serialize_in_parallel_chan_size", 200)
 	config.BindEnvAndSetDefault("aggregator_flush_metrics_and_serialize_in_parallel_buffer_size", 4000)
+	// Rules renaming, dropping or removing tags from the metrics before they are aggregated
+	config.SetKnown("metric_transformations")
 }
 
 func serverless(config pkgconfigmodel.Setup) {
@@ -1424,6 +1525,10 @@ func dogstatsd(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("dogstatsd_non_local_traffic", false)
 	config.BindEnvAndSetDefault("dogstatsd_socket", defaultStatsdSocket) // Only enabled on unix systems
 	config.BindEnvAndSetDefault("dogstatsd_stream_socket", "")           // Experimental || Notice: empty means feature disabled
+	// The version 2 of the stream protocol acknowledges the frames and advertises a window of bytes the clients may
+	// send without waiting for an acknowledgement, the clients negotiate it when they connect.
+	config.BindEnvAndSetDefault("dogstatsd_stream_v2_enabled", true)
+	config.BindEnvAndSetDefault("dogstatsd_stream_v2_window_size", 256*1024)
 	config.BindEnvAndSetDefault("dogstatsd_pipeline_autoadjust", false)
 	config.BindEnvAndSetDefault("dogstatsd_pipeline_count", 1)
 	config.BindEnvAndSetDefault("dogstatsd_stats_port", 5000)
@@ -1456,6 +1561,11 @@ func dogstatsd(config pkgconfigmodel.Setup) {
 	// Depth of the channel the capture writer reads before persisting to disk.
 	// Default is 0 - blocking channel
 	config.BindEnvAndSetDefault("dogstatsd_capture_depth", 0)
+	// Duration of the dogstatsd traffic kept in memory, so that it can be dumped
+	// to a capture file after the fact. Default is 0 - the ring buffer is disabled
+	config.BindEnvAndSetDefault("dogstatsd_capture_ring_buffer_duration", 0*time.Second)
+	// Maximum size in bytes of the dogstatsd traffic kept in memory by the ring buffer
+	config.BindEnvAndSetDefault("dogstatsd_capture_ring_buffer_max_size", 64*1024*1024)
 	// Enable the no-aggregation pipeline.
 	config.BindEnvAndSetDefault("dogstatsd_no_aggregation_pipeline", true)
 	// How many metrics maximum in payloads sent by the no-aggregation pipeline to the intake.
@@ -1491,9 +1601,20 @@ func dogstatsd(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("statsd_metric_namespace_blacklist", StandardStatsdPrefixes)
 	config.BindEnvAndSetDefault("statsd_metric_blocklist", []string{})
 	config.BindEnvAndSetDefault("statsd_metric_blocklist_match_prefix", false)
+	// Per origin (container or process) quotas, 0 means unlimited
+	config.BindEnvAndSetDefault("dogstatsd_origin_quota.packets_per_second", 0)
+	config.BindEnvAndSetDefault("dogstatsd_origin_quota.max_contexts", 0)
+
+	// Internal statsd clients of the agent components, modes are: buffered, direct, noop.
+	config.BindEnvAndSetDefault("internal_statsd.mode", "buffered")
+	config.BindEnvAndSetDefault("internal_statsd.sample_rate", 1.0)
+	// Per component overrides of the mode and sample rate, by component name
+	config.BindEnv("internal_statsd.components")
 
 	config.BindEnvAndSetDefault("histogram_copy_to_distribution", false)
 	config.BindEnvAndSetDefault("histogram_copy_to_distribution_prefix", "")
+	// Prefixes of the dogstatsd histograms and timers aggregated into sketches, like distributions
+	config.BindEnvAndSetDefault("dogstatsd_histogram_to_sketch_prefixes", []string{})
 	config.BindEnvAndSetDefault("histogram_aggregates", []string{"max", "median", "avg", "count"})
 	config.BindEnvAndSetDefault("histogram_percentiles", []string{"0.95"})
 }
@@ -1581,6 +1702,12 @@ func logsagent(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("logs_config.stop_grace_period", 30)
 	config.BindEnvAndSetDefault("logs_config.message_channel_size", 100)
 	config.BindEnvAndSetDefault("logs_config.payload_channel_size", 10)
+	// Spool the payloads on disk when the intake can't keep up, so that they survive intake
+	// outages and agent restarts. The max size is shared by all the pipelines, and the path
+	// defaults to the disk_buffer directory of the logs run path.
+	config.BindEnvAndSetDefault("logs_config.disk_buffer.enabled", false)
+	config.BindEnvAndSetDefault("logs_config.disk_buffer.path", "")
+	config.BindEnvAndSetDefault("logs_config.disk_buffer.max_size", 1024*1024*1024) // 1 GiB
 
 	// maximum time that the unix tailer will hold a log file open after it has been rotated
 	config.BindEnvAndSetDefault("logs_config.close_timeout", 60)
@@ -1694,6 +1821,11 @@ func cri(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("cri_socket_path", "")              // empty is disabled
 	config.BindEnvAndSetDefault("cri_connection_timeout", int64(1)) // in seconds
 	config.BindEnvAndSetDefault("cri_query_timeout", int64(5))      // in seconds
+	config.BindEnvAndSetDefault("cri_socket_paths", []string{})
+	config.BindEnvAndSetDefault("cri_stats_cache", false)
+	config.BindEnvAndSetDefault("cri_query_timeout_overrides", map[string]string{}) // in seconds, by query
+	config.BindEnvAndSetDefault("cri_circuit_breaker_threshold", 3)                 // 0 is disabled
+	config.BindEnvAndSetDefault("cri_circuit_breaker_skipped_intervals", 4)
 }
 
 func kubernetes(config pkgconfigmodel.Setup) {
@@ -2273,6 +2405,8 @@ func ResolveSecrets(config pkgconfigmodel.Config, secretResolver secrets.Compone
 	// anything.
 	secretResolver.Configure(secrets.ConfigParams{
 		Command:          config.GetString("secret_backend_command"),
+		BackendType:      config.GetString("secret_backend_type"),
+		BackendConfig:    config.GetStringMap("secret_backend_config"),
 		Arguments:        config.GetStringSlice("secret_backend_arguments"),
 		Timeout:          config.GetInt("secret_backend_timeout"),
 		MaxSize:          config.GetInt("secret_backend_output_max_size"),
@@ -2283,7 +2417,7 @@ func ResolveSecrets(config pkgconfigmodel.Config, secretResolver secrets.Compone
 		AuditFileMaxSize: config.GetInt("secret_audit_file_max_size"),
 	})
 
-	if config.GetString("secret_backend_command") != "" {
+	if config.GetString("secret_backend_command") != "" || config.GetString("secret_backend_type") != "" {
 		// Viper doesn't expose the final location of the file it
 		// loads. Since we are searching for 'datadog.yaml' in multiple
 		// locations we let viper determine the one to use before
@@ -17,6 +17,8 @@ const (
 	OTLPTracesEnabled         = OTLPSection + "." + OTLPTracesSubSectionKey + ".enabled"
 	OTLPLogsSubSectionKey     = "logs"
 	OTLPLogsEnabled           = OTLPSection + "." + OTLPLogsSubSectionKey + ".enabled"
+	OTLPLogsResourceAttrsTags = OTLPSection + "." + OTLPLogsSubSectionKey + ".resource_attributes_as_tags"
+	OTLPLogsProcessingRules   = OTLPSection + "." + OTLPLogsSubSectionKey + ".processing_rules"
 	OTLPReceiverSubSectionKey = "receiver"
 	OTLPReceiverSection       = OTLPSection + "." + OTLPReceiverSubSectionKey
 	OTLPMetricsSubSectionKey  = "metrics"
@@ -25,6 +27,10 @@ const (
 	OTLPTagCardinalityKey     = OTLPMetrics + ".tag_cardinality"
 	OTLPDebugKey              = "debug"
 	OTLPDebug                 = OTLPSection + "." + OTLPDebugKey
+	OTLPResourceAttributes    = OTLPSection + ".resource_attributes"
+	OTLPResourceAttrsRename   = OTLPResourceAttributes + ".rename"
+	OTLPResourceAttrsDrop     = OTLPResourceAttributes + ".drop"
+	OTLPHostnameAttributes    = OTLPResourceAttributes + ".hostname_attributes"
 )
 
 // OTLP related configuration.
@@ -33,6 +39,11 @@ func OTLP(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault(OTLPMetricsEnabled, true)
 	config.BindEnvAndSetDefault(OTLPTracesEnabled, true)
 	config.BindEnvAndSetDefault(OTLPLogsEnabled, false)
+	config.BindEnvAndSetDefault(OTLPLogsResourceAttrsTags, map[string]string{})
+	config.BindEnv(OTLPLogsProcessingRules)
+	config.BindEnvAndSetDefault(OTLPResourceAttrsRename, map[string]string{})
+	config.BindEnvAndSetDefault(OTLPResourceAttrsDrop, []string{})
+	config.BindEnvAndSetDefault(OTLPHostnameAttributes, []string{})
 
 	// NOTE: This only partially works.
 	// The environment variable is also manually checked in comp/otelcol/otlp/config.go
@@ -75,6 +86,7 @@ func setupOTLPEnvironmentVariables(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("otlp_config.traces.ignore_missing_datadog_fields", false, "DD_OTLP_CONFIG_IGNORE_MISSING_DATADOG_FIELDS")
 	config.BindEnvAndSetDefault("otlp_config.traces.probabilistic_sampler.sampling_percentage", 100.,
 		"DD_OTLP_CONFIG_TRACES_PROBABILISTIC_SAMPLER_SAMPLING_PERCENTAGE")
+	config.BindEnvAndSetDefault("otlp_config.traces.sampling_parity", false, "DD_OTLP_CONFIG_TRACES_SAMPLING_PARITY")
 
 	// HTTP settings
 	config.BindEnv(OTLPSection + ".receiver.protocols.http.endpoint")
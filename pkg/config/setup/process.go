@@ -115,6 +115,8 @@ func setupProcesses(config pkgconfigmodel.Setup) {
 	procBindEnv(config, "process_config.enabled")
 	procBindEnvAndSetDefault(config, "process_config.container_collection.enabled", true)
 	procBindEnvAndSetDefault(config, "process_config.process_collection.enabled", false)
+	// Collects the I/O stats and open file descriptors from procfs when the system-probe process module is disabled
+	procBindEnvAndSetDefault(config, "process_config.process_collection.io_stats_fallback.enabled", false)
 
 	// This allows for the process check to run in the core agent but is for linux only
 	procBindEnvAndSetDefault(config, "process_config.run_in_core_agent.enabled", runtime.GOOS == "linux")
@@ -164,6 +166,8 @@ func setupProcesses(config pkgconfigmodel.Setup) {
 		"DD_STRIP_PROCESS_ARGS",
 		"DD_PROCESS_CONFIG_STRIP_PROC_ARGUMENTS",
 		"DD_PROCESS_AGENT_STRIP_PROC_ARGUMENTS")
+	procBindEnvAndSetDefault(config, "process_config.scrub_default_sensitive_words", true)
+	procBindEnv(config, "process_config.scrubbing_rules")
 	// Use PDH API to collect performance counter data for process check on Windows
 	procBindEnvAndSetDefault(config, "process_config.windows.use_perf_counters", false)
 	config.BindEnvAndSetDefault("process_config.additional_endpoints", make(map[string][]string),
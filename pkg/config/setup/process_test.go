@@ -46,6 +46,14 @@ func TestProcessDefaultConfig(t *testing.T) {
 			key:          "process_config.remote_tagger",
 			defaultValue: false,
 		},
+		{
+			key:          "process_config.process_collection.io_stats_fallback.enabled",
+			defaultValue: false,
+		},
+		{
+			key:          "process_config.scrub_default_sensitive_words",
+			defaultValue: true,
+		},
 		{
 			key:          "process_config.remote_workloadmeta",
 			defaultValue: false,
@@ -334,6 +342,12 @@ func TestEnvVarOverride(t *testing.T) {
 			value:    "true",
 			expected: true,
 		},
+		{
+			key:      "process_config.process_collection.io_stats_fallback.enabled",
+			env:      "DD_PROCESS_CONFIG_PROCESS_COLLECTION_IO_STATS_FALLBACK_ENABLED",
+			value:    "true",
+			expected: true,
+		},
 		{
 			key:      "process_config.internal_profiling.enabled",
 			env:      "DD_PROCESS_CONFIG_INTERNAL_PROFILING_ENABLED",
@@ -420,6 +434,12 @@ func TestEnvVarOverride(t *testing.T) {
 			expType:  "boolean",
 			expected: true,
 		},
+		{
+			key:      "process_config.scrub_default_sensitive_words",
+			env:      "DD_PROCESS_CONFIG_SCRUB_DEFAULT_SENSITIVE_WORDS",
+			value:    "false",
+			expected: false,
+		},
 		{
 			key:      "process_config.event_collection.store.max_items",
 			env:      "DD_PROCESS_CONFIG_EVENT_COLLECTION_STORE_MAX_ITEMS",
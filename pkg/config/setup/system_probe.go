@@ -46,6 +46,9 @@ const (
 	// defaultBTFOutputDir is the default path for extracted BTF
 	defaultBTFOutputDir = "/var/tmp/datadog-agent/system-probe/btf"
 
+	// defaultRemotePrebuiltCacheDir is the default path where eBPF artifacts fetched from the remote mirror are cached
+	defaultRemotePrebuiltCacheDir = "/var/tmp/datadog-agent/system-probe/remote-prebuilt"
+
 	// defaultAptConfigDirSuffix is the default path under `/etc` to the apt config directory
 	defaultAptConfigDirSuffix = "/apt"
 
@@ -112,6 +115,8 @@ func InitSystemProbeConfig(cfg pkgconfigmodel.Config) {
 
 	// secrets backend
 	cfg.BindEnvAndSetDefault("secret_backend_command", "")
+	cfg.BindEnvAndSetDefault("secret_backend_type", "")
+	cfg.SetKnown("secret_backend_config")
 	cfg.BindEnvAndSetDefault("secret_backend_arguments", []string{})
 	cfg.BindEnvAndSetDefault("secret_backend_output_max_size", 0)
 	cfg.BindEnvAndSetDefault("secret_backend_timeout", 0)
@@ -145,6 +150,7 @@ func InitSystemProbeConfig(cfg pkgconfigmodel.Config) {
 	cfg.BindEnvAndSetDefault(join(spNS, "internal_profiling.delta_profiles"), true)
 	cfg.BindEnvAndSetDefault(join(spNS, "internal_profiling.custom_attributes"), []string{"module", "rule_id"})
 	cfg.BindEnvAndSetDefault(join(spNS, "internal_profiling.unix_socket"), "")
+	cfg.BindEnvAndSetDefault(join(spNS, "internal_profiling.use_trace_agent_proxy"), false)
 	cfg.BindEnvAndSetDefault(join(spNS, "internal_profiling.extra_tags"), []string{})
 
 	cfg.BindEnvAndSetDefault(join(spNS, "memory_controller.enabled"), false)
@@ -165,6 +171,12 @@ func InitSystemProbeConfig(cfg pkgconfigmodel.Config) {
 	cfg.BindEnv(join(spNS, "allow_precompiled_fallback"), "DD_ALLOW_PRECOMPILED_FALLBACK")
 	cfg.BindEnv(join(spNS, "allow_prebuilt_fallback"), "DD_ALLOW_PREBUILT_FALLBACK")
 	cfg.BindEnvAndSetDefault(join(spNS, "allow_runtime_compiled_fallback"), true, "DD_ALLOW_RUNTIME_COMPILED_FALLBACK")
+	// fallback of the oom_kill and tcp_queue_length probes only, the other probes keep their own fallbacks
+	cfg.BindEnvAndSetDefault(join(spNS, "remote_prebuilt_artifacts.enabled"), false)
+	cfg.BindEnvAndSetDefault(join(spNS, "remote_prebuilt_artifacts.url"), "")
+	cfg.BindEnvAndSetDefault(join(spNS, "remote_prebuilt_artifacts.public_key"), "")
+	cfg.BindEnvAndSetDefault(join(spNS, "remote_prebuilt_artifacts.cache_dir"), defaultRemotePrebuiltCacheDir)
+	cfg.BindEnvAndSetDefault(join(spNS, "remote_prebuilt_artifacts.timeout"), 30*time.Second)
 	cfg.BindEnvAndSetDefault(join(spNS, "runtime_compiler_output_dir"), defaultRuntimeCompilerOutputDir, "DD_RUNTIME_COMPILER_OUTPUT_DIR")
 	cfg.BindEnv(join(spNS, "enable_kernel_header_download"), "DD_ENABLE_KERNEL_HEADER_DOWNLOAD")
 	cfg.BindEnvAndSetDefault(join(spNS, "kernel_header_dirs"), []string{}, "DD_KERNEL_HEADER_DIRS")
@@ -324,6 +336,8 @@ func InitSystemProbeConfig(cfg pkgconfigmodel.Config) {
 
 	// windows config
 	cfg.BindEnvAndSetDefault(join(spNS, "windows.enable_monotonic_count"), false)
+	cfg.BindEnvAndSetDefault(join(spNS, "windows.enable_etw_dns"), false)
+	cfg.BindEnvAndSetDefault(join(spNS, "windows.enable_etw_tcp"), false)
 
 	// oom_kill module
 	cfg.BindEnvAndSetDefault(join(spNS, "enable_oom_kill"), false)
@@ -372,6 +386,7 @@ func InitSystemProbeConfig(cfg pkgconfigmodel.Config) {
 	eventMonitorBindEnvAndSetDefault(cfg, join(evNS, "pid_cache_size"), 10000)
 	eventMonitorBindEnvAndSetDefault(cfg, join(evNS, "dns_resolution.cache_size"), 1024)
 	eventMonitorBindEnvAndSetDefault(cfg, join(evNS, "dns_resolution.enabled"), true)
+	eventMonitorBindEnvAndSetDefault(cfg, join(evNS, "dns_resolution.min_ttl"), "1m")
 	eventMonitorBindEnvAndSetDefault(cfg, join(evNS, "events_stats.tags_cardinality"), "high")
 	eventMonitorBindEnvAndSetDefault(cfg, join(evNS, "custom_sensitive_words"), []string{})
 	eventMonitorBindEnvAndSetDefault(cfg, join(evNS, "erpc_dentry_resolution_enabled"), true)
@@ -26,6 +26,13 @@ func initCWSSystemProbeConfig(cfg pkgconfigmodel.Config) {
 	cfg.BindEnvAndSetDefault("runtime_security_config.event_server.burst", 40)
 	cfg.BindEnvAndSetDefault("runtime_security_config.event_server.retention", "6s")
 	cfg.BindEnvAndSetDefault("runtime_security_config.event_server.rate", 10)
+	cfg.BindEnvAndSetDefault("runtime_security_config.event_local_sink.enabled", false)
+	cfg.BindEnvAndSetDefault("runtime_security_config.event_local_sink.type", "unixgram")
+	cfg.BindEnvAndSetDefault("runtime_security_config.event_local_sink.address", "")
+	cfg.BindEnvAndSetDefault("runtime_security_config.event_local_sink.rate", 100)
+	cfg.BindEnvAndSetDefault("runtime_security_config.event_local_sink.burst", 100)
+	cfg.BindEnvAndSetDefault("runtime_security_config.event_local_sink.rule_tags", []string{})
+	cfg.BindEnvAndSetDefault("runtime_security_config.event_local_sink.queue_size", 1000)
 	cfg.BindEnvAndSetDefault("runtime_security_config.cookie_cache_size", 100)
 	cfg.BindEnvAndSetDefault("runtime_security_config.internal_monitoring.enabled", false)
 	cfg.BindEnvAndSetDefault("runtime_security_config.log_patterns", []string{})
@@ -91,6 +98,14 @@ func initCWSSystemProbeConfig(cfg pkgconfigmodel.Config) {
 	cfg.BindEnvAndSetDefault("runtime_security_config.security_profile.max_count", 400)
 	cfg.BindEnvAndSetDefault("runtime_security_config.security_profile.dns_match_max_depth", 3)
 
+	// CWS - Security Profiles sharing
+	cfg.BindEnvAndSetDefault("runtime_security_config.security_profile.cluster_agent.enabled", false)
+	cfg.BindEnvAndSetDefault("runtime_security_config.security_profile.cluster_agent.upload_period", "10m")
+
+	// CWS - Security Profiles drift
+	cfg.BindEnvAndSetDefault("runtime_security_config.security_profile.drift.stable_score_threshold", 1.0)
+	cfg.BindEnvAndSetDefault("runtime_security_config.security_profile.drift.stable_period", "6h")
+
 	// CWS - Auto suppression
 	cfg.BindEnvAndSetDefault("runtime_security_config.security_profile.auto_suppression.enabled", true)
 	cfg.BindEnvAndSetDefault("runtime_security_config.security_profile.auto_suppression.event_types", []string{"exec", "dns"})
@@ -148,4 +163,14 @@ func initCWSSystemProbeConfig(cfg pkgconfigmodel.Config) {
 	cfg.BindEnvAndSetDefault("runtime_security_config.enforcement.disarmer.executable.period", "1m")
 
 	cfg.BindEnvAndSetDefault("runtime_security_config.network_monitoring.enabled", false)
+
+	// CWS - Kubernetes audit
+	cfg.BindEnvAndSetDefault("runtime_security_config.k8s_audit.enabled", false)
+	cfg.BindEnvAndSetDefault("runtime_security_config.k8s_audit.mode", "file")
+	cfg.BindEnvAndSetDefault("runtime_security_config.k8s_audit.file.path", "/var/log/kubernetes/audit/audit.log")
+	cfg.BindEnvAndSetDefault("runtime_security_config.k8s_audit.webhook.address", "127.0.0.1:8443")
+	cfg.BindEnvAndSetDefault("runtime_security_config.k8s_audit.webhook.tls_cert_file", "")
+	cfg.BindEnvAndSetDefault("runtime_security_config.k8s_audit.webhook.tls_key_file", "")
+	cfg.BindEnvAndSetDefault("runtime_security_config.k8s_audit.webhook.tls_client_ca_file", "")
+	cfg.BindEnvAndSetDefault("runtime_security_config.k8s_audit.policy_file", "")
 }
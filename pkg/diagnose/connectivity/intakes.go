@@ -0,0 +1,361 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package connectivity
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	logsConfig "github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/config/utils"
+	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+	"github.com/DataDog/datadog-agent/pkg/util/scrubber"
+)
+
+// intakeProbeTimeout bounds the time spent probing a single intake
+const intakeProbeTimeout = 20 * time.Second
+
+// intake is a Datadog intake the Agent sends data to
+type intake struct {
+	// name is the product sending data to the intake
+	name string
+	url  string
+	// destination selects the settings of the HTTP clients sending to the intake
+	destination httputils.Destination
+}
+
+// getIntakes returns the intakes of the products enabled in the configuration
+func getIntakes() ([]intake, error) {
+	cfg := pkgconfigsetup.Datadog()
+
+	intakes := []intake{
+		{name: "metrics", url: utils.GetInfraEndpoint(cfg), destination: httputils.ForwarderDestination},
+	}
+
+	if cfg.GetBool("logs_enabled") && !getLogsUseTCP() {
+		endpoints, err := getLogsEndpoints(false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid logs endpoints: %w", err)
+		}
+		intakes = append(intakes, intake{name: "logs", url: logsEndpointURL(endpoints.Main), destination: httputils.LogsDestination})
+	}
+
+	if cfg.GetBool("apm_config.enabled") {
+		intakes = append(intakes, intake{name: "apm", url: utils.GetMainEndpoint(cfg, "https://trace.agent.", "apm_config.apm_dd_url"), destination: httputils.APMDestination})
+	}
+
+	if cfg.GetBool("process_config.process_collection.enabled") || cfg.GetBool("process_config.container_collection.enabled") {
+		intakes = append(intakes, intake{name: "processes", url: utils.GetMainEndpoint(cfg, "https://process.", "process_config.process_dd_url"), destination: httputils.ForwarderDestination})
+	}
+
+	if cfg.GetBool("runtime_security_config.enabled") {
+		cwsURL, err := eventPlatformIntakeURL("runtime_security_config.endpoints.", "runtime-security-http-intake.logs.", "cws")
+		if err != nil {
+			return nil, fmt.Errorf("invalid CWS endpoints: %w", err)
+		}
+		intakes = append(intakes, intake{name: "cws", url: cwsURL, destination: httputils.LogsDestination})
+	}
+
+	// database monitoring is enabled through the configuration of the
+	// database checks, so its intake is always checked
+	dbmURL, err := eventPlatformIntakeURL("database_monitoring.metrics.", "dbm-metrics-intake.", "dbmmetrics")
+	if err != nil {
+		return nil, fmt.Errorf("invalid DBM endpoints: %w", err)
+	}
+	intakes = append(intakes, intake{name: "dbm", url: dbmURL, destination: httputils.LogsDestination})
+
+	return intakes, nil
+}
+
+// eventPlatformIntakeURL returns the URL of the main endpoint of an intake
+// configured like the logs intake
+func eventPlatformIntakeURL(configPrefix string, hostnamePrefix string, intakeTrackType logsConfig.IntakeTrackType) (string, error) {
+	cfg := pkgconfigsetup.Datadog()
+	configKeys := logsConfig.NewLogsConfigKeys(configPrefix, cfg)
+	endpoints, err := logsConfig.BuildHTTPEndpointsWithConfig(cfg, configKeys, hostnamePrefix, intakeTrackType, logsConfig.DefaultIntakeProtocol, logsConfig.DefaultIntakeOrigin)
+	if err != nil {
+		return "", err
+	}
+	return logsEndpointURL(endpoints.Main), nil
+}
+
+func logsEndpointURL(endpoint logsConfig.Endpoint) string {
+	scheme := "https"
+	if !endpoint.UseSSL() {
+		scheme = "http"
+	}
+	if endpoint.Port == 0 {
+		return scheme + "://" + endpoint.Host
+	}
+	return scheme + "://" + net.JoinHostPort(endpoint.Host, strconv.Itoa(endpoint.Port))
+}
+
+// DiagnoseIntakes checks the connectivity to the intake of every enabled
+// product, reporting the proxy used, the validity of the TLS certificate chain
+// and the latency of each step of the connection.
+func DiagnoseIntakes() []diagnosis.Diagnosis {
+	intakes, err := getIntakes()
+	if err != nil {
+		return []diagnosis.Diagnosis{
+			{
+				Result:      diagnosis.DiagnosisFail,
+				Name:        "Endpoints configuration",
+				Diagnosis:   "Misconfiguration of agent endpoints",
+				Remediation: "Please validate Agent configuration",
+				RawError:    err.Error(),
+			},
+		}
+	}
+
+	var diagnoses []diagnosis.Diagnosis
+	for _, in := range intakes {
+		// each intake is probed with the settings of the clients sending to it
+		transport := httputils.NewTransport(pkgconfigsetup.Datadog(), in.destination)
+		res, err := probeIntake(context.Background(), transport, in.url)
+		diagnoses = append(diagnoses, createIntakeDiagnosis(in, res, err))
+	}
+	return diagnoses
+}
+
+// probeResult holds what was learned while contacting an intake
+type probeResult struct {
+	sync.Mutex
+
+	// proxy is the proxy used to reach the intake, without credentials
+	proxy string
+	// proxyConnect is the status of the proxy response to the CONNECT request
+	proxyConnect string
+	remoteAddr   string
+
+	// tlsState is nil when TLS is not used
+	tlsState  *tls.ConnectionState
+	tlsErr    error
+	certChain []string
+	chainErr  error
+
+	dnsDuration     time.Duration
+	connectDuration time.Duration
+	tlsDuration     time.Duration
+	firstByte       time.Duration
+	total           time.Duration
+
+	statusCode int
+}
+
+// probeIntake sends a request to an intake on a new connection, tracing each
+// step of the connection. The intake is reachable as soon as it answers,
+// whatever the status code.
+func probeIntake(ctx context.Context, transport *http.Transport, rawURL string) (*probeResult, error) {
+	res := &probeResult{}
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return res, fmt.Errorf("invalid URL: %s", scrubber.ScrubLine(err.Error()))
+	}
+
+	if transport.Proxy != nil {
+		proxyURL, err := transport.Proxy(req)
+		if err != nil {
+			return res, fmt.Errorf("cannot determine the proxy to use: %s", scrubber.ScrubLine(err.Error()))
+		}
+		if proxyURL != nil {
+			res.proxy = proxyURL.Scheme + "://" + proxyURL.Host
+		}
+	}
+
+	// each probe measures the establishment of a new connection
+	transport = transport.Clone()
+	transport.DisableKeepAlives = true
+	transport.OnProxyConnectResponse = func(_ context.Context, _ *url.URL, _ *http.Request, connectRes *http.Response) error {
+		res.Lock()
+		defer res.Unlock()
+		res.proxyConnect = connectRes.Status
+		return nil
+	}
+
+	var dnsStart, connectStart, tlsStart time.Time
+	start := time.Now()
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			res.Lock()
+			defer res.Unlock()
+			dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			res.Lock()
+			defer res.Unlock()
+			res.dnsDuration = time.Since(dnsStart)
+		},
+		ConnectStart: func(_, _ string) {
+			res.Lock()
+			defer res.Unlock()
+			connectStart = time.Now()
+		},
+		ConnectDone: func(_, addr string, _ error) {
+			res.Lock()
+			defer res.Unlock()
+			res.connectDuration = time.Since(connectStart)
+			res.remoteAddr = addr
+		},
+		TLSHandshakeStart: func() {
+			res.Lock()
+			defer res.Unlock()
+			tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			res.Lock()
+			defer res.Unlock()
+			res.tlsDuration = time.Since(tlsStart)
+			res.tlsState = &state
+			res.tlsErr = err
+		},
+		GotFirstResponseByte: func() {
+			res.Lock()
+			defer res.Unlock()
+			res.firstByte = time.Since(start)
+		},
+	}
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   intakeProbeTimeout,
+		CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req.WithContext(httptrace.WithClientTrace(ctx, trace)))
+
+	res.Lock()
+	defer res.Unlock()
+	res.total = time.Since(start)
+
+	if res.tlsState != nil && res.tlsErr == nil {
+		var roots *x509.CertPool
+		if transport.TLSClientConfig != nil {
+			roots = transport.TLSClientConfig.RootCAs
+		}
+		res.certChain, res.chainErr = verifyCertificateChain(res.tlsState, req.URL.Hostname(), roots)
+	}
+
+	if err != nil {
+		return res, fmt.Errorf("cannot send the HTTP request to '%v' : %v", scrubber.ScrubLine(rawURL), scrubber.ScrubLine(err.Error()))
+	}
+	_ = resp.Body.Close()
+	res.statusCode = resp.StatusCode
+
+	return res, nil
+}
+
+// verifyCertificateChain validates the certificates presented by the intake
+// against the given roots, or the system roots when nil, independently of
+// `skip_ssl_validation`. It returns a description of the chain.
+func verifyCertificateChain(state *tls.ConnectionState, serverName string, roots *x509.CertPool) ([]string, error) {
+	if len(state.PeerCertificates) == 0 {
+		return nil, errors.New("no certificate presented by the server")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	chain := state.PeerCertificates
+	chains, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	if err == nil && len(chains) > 0 {
+		chain = chains[0]
+	}
+
+	description := make([]string, 0, len(chain))
+	for _, cert := range chain {
+		description = append(description, fmt.Sprintf("%s, issued by %s, expires %s",
+			cert.Subject.String(), cert.Issuer.String(), cert.NotAfter.UTC().Format(time.RFC3339)))
+	}
+	return description, err
+}
+
+func createIntakeDiagnosis(in intake, res *probeResult, err error) diagnosis.Diagnosis {
+	logURL := scrubber.ScrubLine(in.url)
+	d := diagnosis.Diagnosis{
+		Category: in.name,
+		Name:     "Connectivity to " + logURL,
+	}
+
+	var report []string
+
+	proxy := "none"
+	if res.proxy != "" {
+		proxy = res.proxy
+		if res.proxyConnect != "" {
+			proxy += fmt.Sprintf(" (CONNECT answered %q)", res.proxyConnect)
+		}
+	}
+	report = append(report, "Proxy: "+proxy)
+
+	if res.remoteAddr != "" {
+		report = append(report, "Remote address: "+res.remoteAddr)
+	}
+
+	if res.tlsErr != nil {
+		report = append(report, "TLS: handshake failed: "+scrubber.ScrubLine(res.tlsErr.Error()))
+	} else if res.tlsState != nil {
+		chainStatus := "certificate chain valid"
+		if res.chainErr != nil {
+			chainStatus = "certificate chain invalid: " + res.chainErr.Error()
+		}
+		report = append(report, fmt.Sprintf("TLS: %s, %s", tls.VersionName(res.tlsState.Version), chainStatus))
+		for _, cert := range res.certChain {
+			report = append(report, "  - "+cert)
+		}
+	} else if strings.HasPrefix(in.url, "http://") {
+		report = append(report, "TLS: not used")
+	}
+
+	report = append(report, fmt.Sprintf("Latency: DNS %v, connect %v, TLS %v, first byte %v, total %v",
+		res.dnsDuration.Round(time.Millisecond),
+		res.connectDuration.Round(time.Millisecond),
+		res.tlsDuration.Round(time.Millisecond),
+		res.firstByte.Round(time.Millisecond),
+		res.total.Round(time.Millisecond)))
+
+	if err == nil {
+		report = append(report, fmt.Sprintf("Received status code %v from the endpoint", res.statusCode))
+	}
+
+	switch {
+	case err != nil:
+		d.Result = diagnosis.DiagnosisFail
+		d.Diagnosis = createDiagnosisString(fmt.Sprintf("Connection to `%s` failed", logURL), strings.Join(report, "\n"))
+		d.Remediation = "Please validate Agent configuration, proxy and firewall to access " + logURL
+		d.RawError = err.Error()
+	case res.chainErr != nil:
+		d.Result = diagnosis.DiagnosisWarning
+		d.Diagnosis = createDiagnosisString(fmt.Sprintf("Connectivity to `%s` is Ok but its certificate chain is not trusted", logURL), strings.Join(report, "\n"))
+		d.Remediation = "The connection only succeeds because the certificates are not verified (`skip_ssl_validation`). Please verify that no proxy intercepts the TLS connections"
+		d.RawError = res.chainErr.Error()
+	default:
+		d.Result = diagnosis.DiagnosisSuccess
+		d.Diagnosis = createDiagnosisString(fmt.Sprintf("Connectivity to `%s` is Ok", logURL), strings.Join(report, "\n"))
+	}
+
+	return d
+}
@@ -0,0 +1,116 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package connectivity
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+)
+
+func TestGetIntakes(t *testing.T) {
+	mockConfig := configmock.New(t)
+	mockConfig.SetWithoutSource("site", "datadoghq.eu")
+	mockConfig.SetWithoutSource("logs_enabled", true)
+	mockConfig.SetWithoutSource("apm_config.enabled", true)
+	mockConfig.SetWithoutSource("process_config.process_dd_url", "https://process.example.com")
+	mockConfig.SetWithoutSource("process_config.process_collection.enabled", true)
+	mockConfig.SetWithoutSource("runtime_security_config.enabled", true)
+
+	intakes, err := getIntakes()
+	require.NoError(t, err)
+
+	assert.Equal(t, []intake{
+		{name: "metrics", url: "https://app.datadoghq.eu", destination: httputils.ForwarderDestination},
+		{name: "logs", url: "https://agent-http-intake.logs.datadoghq.eu", destination: httputils.LogsDestination},
+		{name: "apm", url: "https://trace.agent.datadoghq.eu", destination: httputils.APMDestination},
+		{name: "processes", url: "https://process.example.com", destination: httputils.ForwarderDestination},
+		{name: "cws", url: "https://runtime-security-http-intake.logs.datadoghq.eu", destination: httputils.LogsDestination},
+		{name: "dbm", url: "https://dbm-metrics-intake.datadoghq.eu", destination: httputils.LogsDestination},
+	}, intakes)
+}
+
+func TestProbeIntakeTLS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ts.Certificate())
+
+	in := intake{name: "metrics", url: ts.URL}
+
+	// trusted chain
+	res, err := probeIntake(context.Background(), &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}, ts.URL)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, res.statusCode)
+	assert.Empty(t, res.proxy)
+	require.NotNil(t, res.tlsState)
+	assert.NoError(t, res.chainErr)
+	assert.NotEmpty(t, res.certChain)
+	assert.NotEmpty(t, res.remoteAddr)
+	assert.NotZero(t, res.total)
+
+	d := createIntakeDiagnosis(in, res, err)
+	assert.Equal(t, diagnosis.DiagnosisSuccess, d.Result)
+	assert.Equal(t, "metrics", d.Category)
+	assert.Contains(t, d.Diagnosis, "certificate chain valid")
+	assert.Contains(t, d.Diagnosis, "Received status code 403 from the endpoint")
+
+	// untrusted chain, only reachable because the certificates are not verified
+	res, err = probeIntake(context.Background(), &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}, ts.URL)
+	require.NoError(t, err)
+	assert.Error(t, res.chainErr)
+
+	d = createIntakeDiagnosis(in, res, err)
+	assert.Equal(t, diagnosis.DiagnosisWarning, d.Result)
+	assert.Contains(t, d.Diagnosis, "certificate chain invalid")
+
+	// untrusted chain
+	res, err = probeIntake(context.Background(), &http.Transport{}, ts.URL)
+	require.Error(t, err)
+	assert.Error(t, res.tlsErr)
+
+	d = createIntakeDiagnosis(in, res, err)
+	assert.Equal(t, diagnosis.DiagnosisFail, d.Result)
+	assert.Contains(t, d.Diagnosis, "TLS: handshake failed")
+}
+
+func TestProbeIntakeProxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "intake.example.com", r.Host)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+	proxyURL.User = url.UserPassword("user", "password")
+
+	res, err := probeIntake(context.Background(), &http.Transport{Proxy: http.ProxyURL(proxyURL)}, "http://intake.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.statusCode)
+	assert.Equal(t, "http://"+proxyURL.Host, res.proxy)
+	assert.Nil(t, res.tlsState)
+
+	d := createIntakeDiagnosis(intake{name: "apm", url: "http://intake.example.com"}, res, err)
+	assert.Equal(t, diagnosis.DiagnosisSuccess, d.Result)
+	assert.Contains(t, d.Diagnosis, "Proxy: http://"+proxyURL.Host)
+	assert.Contains(t, d.Diagnosis, "TLS: not used")
+	assert.NotContains(t, d.Diagnosis, "password")
+}
@@ -491,6 +491,7 @@ func buildSuites(diagCfg diagnosis.Config, checkDatadog func() []diagnosis.Diagn
 		RegisterConnectivityDatadogCoreEndpoints(diagCfg),
 		RegisterConnectivityAutodiscovery,
 		RegisterConnectivityDatadogEventPlatform,
+		RegisterConnectivityDatadogIntakes,
 		RegisterPortConflict,
 	)
 }
@@ -527,6 +528,11 @@ func RegisterConnectivityDatadogEventPlatform(catalog *diagnosis.Catalog) {
 	catalog.Register("connectivity-datadog-event-platform", eventplatformimpl.Diagnose)
 }
 
+// RegisterConnectivityDatadogIntakes registers the connectivity-datadog-intakes diagnose suite.
+func RegisterConnectivityDatadogIntakes(catalog *diagnosis.Catalog) {
+	catalog.Register("connectivity-datadog-intakes", connectivity.DiagnoseIntakes)
+}
+
 // RegisterPortConflict registers the port-conflict diagnose suite.
 func RegisterPortConflict(catalog *diagnosis.Catalog) {
 	catalog.Register("port-conflict", ports.DiagnosePortSuite)
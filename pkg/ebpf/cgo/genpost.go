@@ -47,6 +47,7 @@ func processFile(rdr io.Reader, out io.Writer) error {
 		"Buf",
 		"Cgroup",
 		"Cgroup_name",
+		"Client_id",
 		"LocalAddr",
 		"LocalAddress",
 		"Probe_id",
@@ -53,11 +53,12 @@ func GetBTFLoaderInfo() (string, error) {
 	return infoStr, nil
 }
 
-func (c *coreAssetLoader) loadCOREAsset(filename string, startFn func(bytecode.AssetReader, manager.Options) error) error {
+func (c *coreAssetLoader) loadCOREAsset(filename string, startFn func(bytecode.AssetReader, manager.Options) error) (err error) {
 	var result COREResult
 	base := strings.TrimSuffix(filename, path.Ext(filename))
 	defer func() {
 		c.reportTelemetry(base, result)
+		StoreLoadOutcome(base, LoadMethodCORE, err)
 	}()
 
 	ret, result, err := c.btfLoader.Get()
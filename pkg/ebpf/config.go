@@ -6,6 +6,8 @@
 package ebpf
 
 import (
+	"time"
+
 	sysconfig "github.com/DataDog/datadog-agent/cmd/system-probe/config"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
 	"github.com/DataDog/datadog-agent/pkg/util/kernel"
@@ -74,6 +76,22 @@ type Config struct {
 	// AllowRuntimeCompiledFallback indicates whether we are allowed to fallback to runtime compilation if CO-RE fails.
 	AllowRuntimeCompiledFallback bool
 
+	// EnableRemotePrebuiltArtifacts enables fetching prebuilt eBPF artifacts matching the kernel from a remote mirror
+	// when CO-RE loading fails, instead of falling back to runtime compilation.
+	EnableRemotePrebuiltArtifacts bool
+
+	// RemotePrebuiltArtifactsURL is the base URL of the mirror serving the prebuilt eBPF artifacts
+	RemotePrebuiltArtifactsURL string
+
+	// RemotePrebuiltArtifactsPublicKey is the base64 encoded ed25519 public key used to verify the artifacts signatures
+	RemotePrebuiltArtifactsPublicKey string
+
+	// RemotePrebuiltArtifactsCacheDir is the directory where the verified artifacts are cached
+	RemotePrebuiltArtifactsCacheDir string
+
+	// RemotePrebuiltArtifactsTimeout is the timeout of a single artifact download
+	RemotePrebuiltArtifactsTimeout time.Duration
+
 	// AttachKprobesWithKprobeEventsABI uses the kprobe_events ABI to attach kprobes rather than the newer perf ABI.
 	AttachKprobesWithKprobeEventsABI bool
 
@@ -110,6 +128,12 @@ func NewConfig() *Config {
 		AllowPrebuiltFallback:        cfg.GetBool(sysconfig.FullKeyPath(spNS, "allow_prebuilt_fallback")),
 		AllowRuntimeCompiledFallback: cfg.GetBool(sysconfig.FullKeyPath(spNS, "allow_runtime_compiled_fallback")),
 
+		EnableRemotePrebuiltArtifacts:    cfg.GetBool(sysconfig.FullKeyPath(spNS, "remote_prebuilt_artifacts.enabled")),
+		RemotePrebuiltArtifactsURL:       cfg.GetString(sysconfig.FullKeyPath(spNS, "remote_prebuilt_artifacts.url")),
+		RemotePrebuiltArtifactsPublicKey: cfg.GetString(sysconfig.FullKeyPath(spNS, "remote_prebuilt_artifacts.public_key")),
+		RemotePrebuiltArtifactsCacheDir:  cfg.GetString(sysconfig.FullKeyPath(spNS, "remote_prebuilt_artifacts.cache_dir")),
+		RemotePrebuiltArtifactsTimeout:   cfg.GetDuration(sysconfig.FullKeyPath(spNS, "remote_prebuilt_artifacts.timeout")),
+
 		AttachKprobesWithKprobeEventsABI: cfg.GetBool(sysconfig.FullKeyPath(spNS, "attach_kprobes_with_kprobe_events_abi")),
 	}
 
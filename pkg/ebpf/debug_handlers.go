@@ -6,6 +6,7 @@
 package ebpf
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -23,3 +24,12 @@ func HandleBTFLoaderInfo(w http.ResponseWriter, _ *http.Request) {
 
 	io.WriteString(w, info)
 }
+
+// HandleLoadOutcomes responds with how each eBPF asset was loaded, including the
+// fallback chain that was followed when the preferred method failed
+func HandleLoadOutcomes(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(GetLoadOutcomes()); err != nil {
+		w.WriteHeader(500)
+	}
+}
@@ -0,0 +1,99 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package ebpf
+
+import (
+	"sync"
+	"time"
+)
+
+// LoadMethod is the method used to obtain the bytecode of an eBPF asset
+type LoadMethod string
+
+const (
+	// LoadMethodCORE is used for CO-RE assets relocated against the kernel BTF
+	LoadMethodCORE LoadMethod = "co-re"
+	// LoadMethodRemotePrebuilt is used for assets fetched from the remote prebuilt artifacts mirror
+	LoadMethodRemotePrebuilt LoadMethod = "remote_prebuilt"
+	// LoadMethodRuntimeCompiled is used for assets compiled on-host
+	LoadMethodRuntimeCompiled LoadMethod = "runtime_compiled"
+	// LoadMethodPrebuilt is used for the prebuilt assets shipped with the agent
+	LoadMethodPrebuilt LoadMethod = "prebuilt"
+)
+
+// maxLoadAttemptsPerAsset bounds the history kept for assets which are reloaded, e.g. on module restarts
+const maxLoadAttemptsPerAsset = 10
+
+// LoadAttempt is the outcome of an attempt to load an eBPF asset with a given method
+type LoadAttempt struct {
+	Method    LoadMethod `json:"method"`
+	Success   bool       `json:"success"`
+	Error     string     `json:"error,omitempty"`
+	Timestamp time.Time  `json:"timestamp"`
+}
+
+// LoadOutcome describes how an eBPF asset ended up being loaded, or why it couldn't be
+type LoadOutcome struct {
+	// Method is the method of the last successful attempt, empty if every attempt failed
+	Method   LoadMethod    `json:"method,omitempty"`
+	Attempts []LoadAttempt `json:"attempts"`
+}
+
+var loadOutcomes = struct {
+	sync.Mutex
+	byAsset map[string]*LoadOutcome
+}{byAsset: make(map[string]*LoadOutcome)}
+
+// StoreLoadOutcome records the outcome of an attempt to load the given asset with the given method.
+// Attempts are kept in order so that the fallback chain which led to the final method can be reported.
+func StoreLoadOutcome(assetName string, method LoadMethod, err error) {
+	attempt := LoadAttempt{
+		Method:    method,
+		Success:   err == nil,
+		Timestamp: time.Now(),
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	loadOutcomes.Lock()
+	defer loadOutcomes.Unlock()
+
+	outcome, ok := loadOutcomes.byAsset[assetName]
+	if !ok {
+		outcome = &LoadOutcome{}
+		loadOutcomes.byAsset[assetName] = outcome
+	}
+	outcome.Attempts = append(outcome.Attempts, attempt)
+	if len(outcome.Attempts) > maxLoadAttemptsPerAsset {
+		outcome.Attempts = outcome.Attempts[len(outcome.Attempts)-maxLoadAttemptsPerAsset:]
+	}
+	if attempt.Success {
+		outcome.Method = method
+	}
+}
+
+// GetLoadOutcomes returns a copy of the load outcomes of every eBPF asset
+func GetLoadOutcomes() map[string]LoadOutcome {
+	loadOutcomes.Lock()
+	defer loadOutcomes.Unlock()
+
+	result := make(map[string]LoadOutcome, len(loadOutcomes.byAsset))
+	for assetName, outcome := range loadOutcomes.byAsset {
+		result[assetName] = LoadOutcome{
+			Method:   outcome.Method,
+			Attempts: append([]LoadAttempt(nil), outcome.Attempts...),
+		}
+	}
+	return result
+}
+
+// ResetLoadOutcomes clears all recorded load outcomes, for testing purposes
+func ResetLoadOutcomes() {
+	loadOutcomes.Lock()
+	defer loadOutcomes.Unlock()
+	loadOutcomes.byAsset = make(map[string]*LoadOutcome)
+}
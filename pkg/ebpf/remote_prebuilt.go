@@ -0,0 +1,188 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package ebpf
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// signatureSuffix is appended to the artifact URL to get its detached signature
+	signatureSuffix = ".sig"
+	// maxRemoteArtifactSize bounds the size of a downloaded artifact
+	maxRemoteArtifactSize = 64 << 20
+	// manifestVersion is the first line of the signed manifests
+	manifestVersion = "datadog-ebpf-prebuilt-manifest-v1"
+)
+
+var errInvalidSignature = errors.New("invalid signature")
+
+// remotePrebuiltFetcher downloads prebuilt eBPF artifacts built for a specific kernel from a mirror.
+//
+// Artifacts are served at <url>/<arch>/<kernel release>/<asset>, along with a detached ed25519 signature
+// of the manifest of the artifact, base64 encoded, at <url>/<arch>/<kernel release>/<asset>.sig. The manifest
+// binds the architecture, the kernel release and the asset name to the SHA-256 digest of the artifact, so that
+// a validly signed artifact can't be served for another kernel or in place of another asset, see
+// artifactManifest. Verified artifacts are cached on disk, and their signature is verified again every time
+// they are read from the cache.
+type remotePrebuiltFetcher struct {
+	baseURL   *url.URL
+	publicKey ed25519.PublicKey
+	cacheDir  string
+	arch      string
+	release   string
+	client    *http.Client
+}
+
+func newRemotePrebuiltFetcher(cfg *Config, arch, release string) (*remotePrebuiltFetcher, error) {
+	if cfg.RemotePrebuiltArtifactsURL == "" {
+		return nil, errors.New("no remote prebuilt artifacts URL configured")
+	}
+	baseURL, err := url.Parse(cfg.RemotePrebuiltArtifactsURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote prebuilt artifacts URL: %w", err)
+	}
+	if baseURL.Scheme != "https" && baseURL.Scheme != "http" {
+		return nil, fmt.Errorf("unsupported remote prebuilt artifacts URL scheme %q", baseURL.Scheme)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(cfg.RemotePrebuiltArtifactsPublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote prebuilt artifacts public key: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid remote prebuilt artifacts public key: expected %d bytes, got %d", ed25519.PublicKeySize, len(key))
+	}
+
+	return &remotePrebuiltFetcher{
+		baseURL:   baseURL,
+		publicKey: ed25519.PublicKey(key),
+		cacheDir:  filepath.Join(cfg.RemotePrebuiltArtifactsCacheDir, arch, release),
+		arch:      arch,
+		release:   release,
+		client:    &http.Client{Timeout: cfg.RemotePrebuiltArtifactsTimeout},
+	}, nil
+}
+
+// Fetch returns the verified content of the given artifact, from the cache if possible
+func (f *remotePrebuiltFetcher) Fetch(ctx context.Context, filename string) ([]byte, error) {
+	if filename != filepath.Base(filename) {
+		return nil, fmt.Errorf("invalid artifact name %q", filename)
+	}
+
+	cachePath := filepath.Join(f.cacheDir, filename)
+	if content, err := f.readCached(cachePath); err == nil {
+		return content, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		log.Warnf("discarding cached eBPF artifact %s: %s", cachePath, err)
+	}
+
+	artifactURL := f.baseURL.JoinPath(f.arch, f.release, filename).String()
+	content, err := f.download(ctx, artifactURL)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := f.download(ctx, artifactURL+signatureSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading signature: %w", err)
+	}
+	if err := f.verify(filename, content, signature); err != nil {
+		return nil, fmt.Errorf("%s: %w", artifactURL, err)
+	}
+
+	if err := f.writeCached(cachePath, content, signature); err != nil {
+		log.Warnf("unable to cache eBPF artifact %s: %s", cachePath, err)
+	}
+	return content, nil
+}
+
+func (f *remotePrebuiltFetcher) download(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d fetching %s", resp.StatusCode, u)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteArtifactSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxRemoteArtifactSize {
+		return nil, fmt.Errorf("%s exceeds the maximum artifact size of %d bytes", u, maxRemoteArtifactSize)
+	}
+	return content, nil
+}
+
+// artifactManifest returns the manifest signed for an artifact, made of one field per line:
+//
+//	datadog-ebpf-prebuilt-manifest-v1
+//	arch: <arch>
+//	kernel: <kernel release>
+//	asset: <asset>
+//	sha256: <hex encoded SHA-256 digest of the artifact>
+func artifactManifest(arch, release, filename string, content []byte) []byte {
+	digest := sha256.Sum256(content)
+	return []byte(fmt.Sprintf("%s\narch: %s\nkernel: %s\nasset: %s\nsha256: %s\n",
+		manifestVersion, arch, release, filename, hex.EncodeToString(digest[:])))
+}
+
+func (f *remotePrebuiltFetcher) verify(filename string, content, signature []byte) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return errInvalidSignature
+	}
+	if !ed25519.Verify(f.publicKey, artifactManifest(f.arch, f.release, filename, content), sig) {
+		return errInvalidSignature
+	}
+	return nil
+}
+
+func (f *remotePrebuiltFetcher) readCached(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := os.ReadFile(path + signatureSuffix)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.verify(filepath.Base(path), content, signature); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func (f *remotePrebuiltFetcher) writeCached(path string, content, signature []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// the signature is written first so that a partially written cache entry never verifies
+	if err := os.WriteFile(path+signatureSuffix, signature, 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0600)
+}
@@ -0,0 +1,109 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package ebpf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	manager "github.com/DataDog/ebpf-manager"
+	bpflib "github.com/cilium/ebpf"
+
+	"github.com/DataDog/datadog-agent/pkg/ebpf/bytecode"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/kernel"
+)
+
+var remotePrebuiltTelemetry = struct {
+	success telemetry.Counter
+	error   telemetry.Counter
+}{
+	telemetry.NewCounter("ebpf__remote_prebuilt__load", "success", []string{"kernel", "arch", "asset"}, "count of remote prebuilt load successes"),
+	telemetry.NewCounter("ebpf__remote_prebuilt__load", "error", []string{"kernel", "arch", "asset", "error_type"}, "count of remote prebuilt load errors"),
+}
+
+// LoadRemotePrebuiltAsset fetches the prebuilt version of the given asset matching the running kernel from the
+// configured mirror, verifies its signature, and then calls the callback function with it. It is meant to be used
+// as a fallback when CO-RE loading fails, instead of compiling the asset on-host. Only the oom-kill and
+// tcp-queue-length probes use it for now, the other CO-RE assets keep their prebuilt or runtime compiled fallbacks.
+func LoadRemotePrebuiltAsset(cfg *Config, filename string, startFn func(bytecode.AssetReader, manager.Options) error) (err error) {
+	if !cfg.EnableRemotePrebuiltArtifacts {
+		return errors.New("remote prebuilt artifacts are disabled")
+	}
+
+	base := strings.TrimSuffix(filename, path.Ext(filename))
+	errorType := ""
+	defer func() {
+		StoreLoadOutcome(base, LoadMethodRemotePrebuilt, err)
+		reportRemotePrebuiltTelemetry(base, errorType)
+	}()
+
+	release, err := kernel.Release()
+	if err != nil {
+		errorType = "kernel"
+		return fmt.Errorf("error detecting kernel release: %w", err)
+	}
+	arch, err := kernel.Machine()
+	if err != nil {
+		errorType = "kernel"
+		return fmt.Errorf("error detecting kernel architecture: %w", err)
+	}
+
+	fetcher, err := newRemotePrebuiltFetcher(cfg, arch, release)
+	if err != nil {
+		errorType = "config"
+		return err
+	}
+	content, err := fetcher.Fetch(context.Background(), filename)
+	if err != nil {
+		if errors.Is(err, errInvalidSignature) {
+			errorType = "signature"
+		} else {
+			errorType = "fetch"
+		}
+		return fmt.Errorf("error fetching remote prebuilt %s: %w", filename, err)
+	}
+
+	// the artifact was built against the headers of this exact kernel, so no relocation is needed
+	err = startFn(nopAssetReader{bytes.NewReader(content)}, manager.Options{})
+	if err != nil {
+		var ve *bpflib.VerifierError
+		if errors.As(err, &ve) {
+			errorType = "verifier"
+		} else {
+			errorType = "loader"
+		}
+	}
+	return err
+}
+
+func reportRemotePrebuiltTelemetry(assetName string, errorType string) {
+	kernelVersion, err := kernel.Release()
+	if err != nil {
+		return
+	}
+	arch, err := kernel.Machine()
+	if err != nil {
+		return
+	}
+	if errorType == "" {
+		remotePrebuiltTelemetry.success.Inc(kernelVersion, arch, assetName)
+		return
+	}
+	remotePrebuiltTelemetry.error.Inc(kernelVersion, arch, assetName, errorType)
+}
+
+type nopAssetReader struct {
+	*bytes.Reader
+}
+
+func (nopAssetReader) Close() error { return nil }
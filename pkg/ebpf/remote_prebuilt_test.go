@@ -0,0 +1,149 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package ebpf
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMirror(t *testing.T, files map[string][]byte) (*httptest.Server, *int) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		content, ok := files[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(content)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestRemotePrebuiltFetcher(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	artifact := []byte("\x7fELF bytecode")
+	sign := func(arch, release, filename string) []byte {
+		return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, artifactManifest(arch, release, filename, artifact))))
+	}
+	signature := sign("x86_64", "5.4.0-1", "oom-kill.o")
+	srv, requests := newTestMirror(t, map[string][]byte{
+		"/mirror/x86_64/5.4.0-1/oom-kill.o":                artifact,
+		"/mirror/x86_64/5.4.0-1/oom-kill.o.sig":            append(signature, '\n'),
+		"/mirror/x86_64/5.4.0-1/tampered.o":                []byte("tampered"),
+		"/mirror/x86_64/5.4.0-1/tampered.o.sig":            sign("x86_64", "5.4.0-1", "tampered.o"),
+		"/mirror/x86_64/5.4.0-1/missing-signature.o":       artifact,
+		"/mirror/x86_64/5.4.0-1/malformed-signature.o":     artifact,
+		"/mirror/x86_64/5.4.0-1/malformed-signature.o.sig": []byte("not base64"),
+		// validly signed artifacts served for another asset or another kernel
+		"/mirror/x86_64/5.4.0-1/renamed.o":          artifact,
+		"/mirror/x86_64/5.4.0-1/renamed.o.sig":      signature,
+		"/mirror/x86_64/5.4.0-1/other-kernel.o":     artifact,
+		"/mirror/x86_64/5.4.0-1/other-kernel.o.sig": sign("x86_64", "5.15.0-1", "other-kernel.o"),
+		"/mirror/x86_64/5.4.0-1/other-arch.o":       artifact,
+		"/mirror/x86_64/5.4.0-1/other-arch.o.sig":   sign("aarch64", "5.4.0-1", "other-arch.o"),
+	})
+
+	cfg := &Config{
+		RemotePrebuiltArtifactsURL:       srv.URL + "/mirror",
+		RemotePrebuiltArtifactsPublicKey: base64.StdEncoding.EncodeToString(pub),
+		RemotePrebuiltArtifactsCacheDir:  t.TempDir(),
+		RemotePrebuiltArtifactsTimeout:   5 * time.Second,
+	}
+	f, err := newRemotePrebuiltFetcher(cfg, "x86_64", "5.4.0-1")
+	require.NoError(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		content, err := f.Fetch(context.Background(), "oom-kill.o")
+		require.NoError(t, err)
+		assert.Equal(t, artifact, content)
+
+		// the second fetch is served from the cache
+		before := *requests
+		content, err = f.Fetch(context.Background(), "oom-kill.o")
+		require.NoError(t, err)
+		assert.Equal(t, artifact, content)
+		assert.Equal(t, before, *requests)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		_, err := f.Fetch(context.Background(), "tampered.o")
+		assert.ErrorIs(t, err, errInvalidSignature)
+		_, err = f.Fetch(context.Background(), "malformed-signature.o")
+		assert.ErrorIs(t, err, errInvalidSignature)
+	})
+
+	t.Run("signed for another asset", func(t *testing.T) {
+		for _, filename := range []string{"renamed.o", "other-kernel.o", "other-arch.o"} {
+			_, err := f.Fetch(context.Background(), filename)
+			assert.ErrorIs(t, err, errInvalidSignature, filename)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := f.Fetch(context.Background(), "missing-signature.o")
+		assert.Error(t, err)
+		_, err = f.Fetch(context.Background(), "unknown.o")
+		assert.Error(t, err)
+	})
+
+	t.Run("path traversal", func(t *testing.T) {
+		_, err := f.Fetch(context.Background(), "../../etc/passwd")
+		assert.Error(t, err)
+	})
+}
+
+func TestRemotePrebuiltFetcherConfig(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	key := base64.StdEncoding.EncodeToString(pub)
+
+	_, err = newRemotePrebuiltFetcher(&Config{RemotePrebuiltArtifactsPublicKey: key}, "x86_64", "5.4.0")
+	assert.Error(t, err, "missing URL")
+	_, err = newRemotePrebuiltFetcher(&Config{RemotePrebuiltArtifactsURL: "ftp://mirror", RemotePrebuiltArtifactsPublicKey: key}, "x86_64", "5.4.0")
+	assert.Error(t, err, "unsupported scheme")
+	_, err = newRemotePrebuiltFetcher(&Config{RemotePrebuiltArtifactsURL: "https://mirror", RemotePrebuiltArtifactsPublicKey: "c2hvcnQ="}, "x86_64", "5.4.0")
+	assert.Error(t, err, "short key")
+}
+
+func TestLoadOutcomes(t *testing.T) {
+	ResetLoadOutcomes()
+	t.Cleanup(ResetLoadOutcomes)
+
+	StoreLoadOutcome("oom-kill", LoadMethodCORE, assert.AnError)
+	StoreLoadOutcome("oom-kill", LoadMethodRemotePrebuilt, nil)
+	StoreLoadOutcome("tcp-queue-length", LoadMethodCORE, assert.AnError)
+
+	outcomes := GetLoadOutcomes()
+	require.Len(t, outcomes, 2)
+
+	oomKill := outcomes["oom-kill"]
+	assert.Equal(t, LoadMethodRemotePrebuilt, oomKill.Method)
+	require.Len(t, oomKill.Attempts, 2)
+	assert.Equal(t, LoadMethodCORE, oomKill.Attempts[0].Method)
+	assert.False(t, oomKill.Attempts[0].Success)
+	assert.Equal(t, assert.AnError.Error(), oomKill.Attempts[0].Error)
+	assert.True(t, oomKill.Attempts[1].Success)
+
+	assert.Empty(t, outcomes["tcp-queue-length"].Method)
+
+	for i := 0; i < 2*maxLoadAttemptsPerAsset; i++ {
+		StoreLoadOutcome("oom-kill", LoadMethodCORE, nil)
+	}
+	assert.Len(t, GetLoadOutcomes()["oom-kill"].Attempts, maxLoadAttemptsPerAsset)
+}
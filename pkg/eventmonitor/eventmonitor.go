@@ -24,6 +24,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/security/probe"
 	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
 	"github.com/DataDog/datadog-agent/pkg/security/seclog"
+	grpcutil "github.com/DataDog/datadog-agent/pkg/util/grpc"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
@@ -237,7 +238,7 @@ func NewEventMonitor(config *config.Config, secconfig *secconfig.Config, opts Op
 		Config:       config,
 		Probe:        probe,
 		StatsdClient: opts.StatsdClient,
-		GRPCServer:   grpc.NewServer(),
+		GRPCServer:   grpc.NewServer(grpcutil.ServerKeepaliveOptions()...),
 
 		ctx:           ctx,
 		cancelFnc:     cancelFnc,
@@ -0,0 +1,171 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package fips
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// approvedCipherSuites are the TLS 1.2 cipher suites allowed in FIPS mode. TLS 1.3 cipher suites
+// aren't configurable and are handled by the crypto backend.
+var approvedCipherSuites = map[uint16]struct{}{
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256:   {},
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384:   {},
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256: {},
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384: {},
+}
+
+// approvedCurves are the key exchange curves allowed in FIPS mode
+var approvedCurves = map[tls.CurveID]struct{}{
+	tls.CurveP256: {},
+	tls.CurveP384: {},
+	tls.CurveP521: {},
+}
+
+// SubsystemStatus is the FIPS compliance of the TLS configuration of an Agent subsystem
+type SubsystemStatus struct {
+	Name string `json:"name"`
+	// Endpoint is the URL the subsystem connects to, if it was registered with one
+	Endpoint   string   `json:"endpoint,omitempty"`
+	Compliant  bool     `json:"compliant"`
+	Violations []string `json:"violations,omitempty"`
+}
+
+// Report is the FIPS compliance of the Agent, as exposed to auditors
+type Report struct {
+	// Status is the FIPS mode of the build and runtime, see Status
+	Status     string            `json:"status"`
+	Enabled    bool              `json:"enabled"`
+	Error      string            `json:"error,omitempty"`
+	Subsystems []SubsystemStatus `json:"subsystems"`
+}
+
+// subsystem is the TLS configuration of a subsystem, and the endpoint it connects to
+type subsystem struct {
+	tlsConfig *tls.Config
+	endpoint  string
+}
+
+var (
+	subsystemsMutex sync.Mutex
+	subsystems      = map[string]subsystem{}
+)
+
+// CheckTLSConfig returns why the given TLS configuration would use non FIPS-approved crypto, if it does
+func CheckTLSConfig(cfg *tls.Config) []string {
+	if cfg == nil {
+		return nil
+	}
+
+	var violations []string
+	// a zero MinVersion defaults to TLS 1.2
+	if cfg.MinVersion != 0 && cfg.MinVersion < tls.VersionTLS12 {
+		violations = append(violations, fmt.Sprintf("minimum TLS version %s is not approved", tls.VersionName(cfg.MinVersion)))
+	}
+	for _, suite := range cfg.CipherSuites {
+		if _, ok := approvedCipherSuites[suite]; !ok {
+			violations = append(violations, fmt.Sprintf("cipher suite %s is not approved", tls.CipherSuiteName(suite)))
+		}
+	}
+	for _, curve := range cfg.CurvePreferences {
+		if _, ok := approvedCurves[curve]; !ok {
+			violations = append(violations, fmt.Sprintf("curve %s is not approved", curve))
+		}
+	}
+	return violations
+}
+
+// CheckEndpoint returns why connecting to the given endpoint would not use FIPS-approved crypto, if it
+// wouldn't. Endpoints reached without TLS are only allowed on the loopback interface, e.g. the FIPS proxy.
+func CheckEndpoint(endpoint string) []string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return []string{fmt.Sprintf("endpoint %s is invalid: %v", endpoint, err)}
+	}
+	if strings.EqualFold(u.Scheme, "https") {
+		return nil
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); (ip != nil && ip.IsLoopback()) || strings.EqualFold(host, "localhost") {
+		return nil
+	}
+	return []string{fmt.Sprintf("endpoint %s is not reached over TLS", u.Redacted())}
+}
+
+// RegisterTLSConfig records the TLS configuration used by a subsystem (forwarder, API server, ...), to be
+// checked by Verify and reported by GetReport. Registering a subsystem again replaces its configuration.
+func RegisterTLSConfig(name string, cfg *tls.Config) {
+	subsystemsMutex.Lock()
+	defer subsystemsMutex.Unlock()
+	subsystems[name] = subsystem{tlsConfig: cfg}
+}
+
+// RegisterEndpoint records the endpoint a subsystem connects to, along with the TLS configuration used to
+// connect to it, so that Verify also checks that the endpoint is reached over TLS.
+func RegisterEndpoint(name string, endpoint string, cfg *tls.Config) {
+	subsystemsMutex.Lock()
+	defer subsystemsMutex.Unlock()
+	subsystems[name] = subsystem{tlsConfig: cfg, endpoint: endpoint}
+}
+
+// GetReport returns the FIPS compliance of the registered subsystems
+func GetReport() Report {
+	enabled, err := Enabled()
+	report := Report{
+		Status:     Status(),
+		Enabled:    enabled,
+		Subsystems: []SubsystemStatus{},
+	}
+	if err != nil {
+		report.Error = err.Error()
+	}
+
+	subsystemsMutex.Lock()
+	defer subsystemsMutex.Unlock()
+	for name, sub := range subsystems {
+		violations := CheckTLSConfig(sub.tlsConfig)
+		if sub.endpoint != "" {
+			violations = append(violations, CheckEndpoint(sub.endpoint)...)
+		}
+		report.Subsystems = append(report.Subsystems, SubsystemStatus{
+			Name:       name,
+			Endpoint:   sub.endpoint,
+			Compliant:  len(violations) == 0,
+			Violations: violations,
+		})
+	}
+	sort.Slice(report.Subsystems, func(i, j int) bool {
+		return report.Subsystems[i].Name < report.Subsystems[j].Name
+	})
+	return report
+}
+
+// Verify returns an error when FIPS mode is enabled and a registered subsystem would use non FIPS-approved
+// crypto. It is meant to be called on startup, once the subsystems are set up, so that the Agent doesn't run
+// out of compliance.
+func Verify() error {
+	report := GetReport()
+	if !report.Enabled {
+		return nil
+	}
+
+	var errs []string
+	for _, subsystem := range report.Subsystems {
+		if !subsystem.Compliant {
+			errs = append(errs, fmt.Sprintf("%s: %s", subsystem.Name, strings.Join(subsystem.Violations, ", ")))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("FIPS mode is enabled but some subsystems would use non FIPS-approved crypto: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package fips
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTLSConfig(t *testing.T) {
+	assert.Empty(t, CheckTLSConfig(nil))
+	assert.Empty(t, CheckTLSConfig(&tls.Config{}))
+	assert.Empty(t, CheckTLSConfig(&tls.Config{
+		MinVersion:       tls.VersionTLS12,
+		CipherSuites:     []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+		CurvePreferences: []tls.CurveID{tls.CurveP256},
+	}))
+
+	violations := CheckTLSConfig(&tls.Config{
+		MinVersion:       tls.VersionTLS10,
+		CipherSuites:     []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256},
+		CurvePreferences: []tls.CurveID{tls.X25519},
+	})
+	assert.Equal(t, []string{
+		"minimum TLS version TLS 1.0 is not approved",
+		"cipher suite TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256 is not approved",
+		"curve X25519 is not approved",
+	}, violations)
+}
+
+func TestCheckEndpoint(t *testing.T) {
+	assert.Empty(t, CheckEndpoint("https://app.datadoghq.com"))
+	assert.Empty(t, CheckEndpoint("http://localhost:9804"))
+	assert.Empty(t, CheckEndpoint("http://127.0.0.1:9804"))
+	assert.Equal(t, []string{"endpoint http://intake.example.com is not reached over TLS"}, CheckEndpoint("http://intake.example.com"))
+}
+
+func TestGetReport(t *testing.T) {
+	t.Cleanup(func() {
+		subsystems = map[string]subsystem{}
+	})
+	RegisterTLSConfig("forwarder", &tls.Config{MinVersion: tls.VersionTLS11})
+	RegisterTLSConfig("api_server", &tls.Config{})
+	RegisterEndpoint("forwarder http://intake.example.com", "http://intake.example.com", &tls.Config{})
+
+	report := GetReport()
+	assert.Equal(t, Status(), report.Status)
+	require.Len(t, report.Subsystems, 3)
+	assert.Equal(t, SubsystemStatus{Name: "api_server", Compliant: true}, report.Subsystems[0])
+	assert.Equal(t, "forwarder", report.Subsystems[1].Name)
+	assert.False(t, report.Subsystems[1].Compliant)
+	assert.Len(t, report.Subsystems[1].Violations, 1)
+	assert.Equal(t, SubsystemStatus{
+		Name:       "forwarder http://intake.example.com",
+		Endpoint:   "http://intake.example.com",
+		Violations: []string{"endpoint http://intake.example.com is not reached over TLS"},
+	}, report.Subsystems[2])
+
+	if !report.Enabled {
+		assert.NoError(t, Verify())
+	} else {
+		assert.Error(t, Verify())
+	}
+}
@@ -2,7 +2,16 @@ module github.com/DataDog/datadog-agent/pkg/fips
 
 go 1.23.0
 
-require golang.org/x/sys v0.31.0
+require (
+	github.com/stretchr/testify v1.10.0
+	golang.org/x/sys v0.31.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
 
 // This section was automatically added by 'invoke modules.add-all-replace' command, do not edit manually
 
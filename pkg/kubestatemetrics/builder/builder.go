@@ -28,6 +28,7 @@ import (
 	ksmbuild "k8s.io/kube-state-metrics/v2/pkg/builder"
 	ksmtypes "k8s.io/kube-state-metrics/v2/pkg/builder/types"
 	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
 	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
 	"k8s.io/kube-state-metrics/v2/pkg/options"
@@ -48,6 +49,7 @@ type Builder struct {
 	fieldSelectorFilter   string
 	ctx                   context.Context
 	allowDenyList         generator.FamilyGeneratorFilter
+	labelsAllowList       store.LabelsAllowList
 	metrics               *watch.ListWatchMetrics
 
 	resync time.Duration
@@ -137,6 +139,13 @@ func (b *Builder) WithAllowAnnotations(l map[string][]string) {
 	_ = b.ksmBuilder.WithAllowAnnotations(l)
 }
 
+// WithLabelsAllowList configures the label and annotation keys of the
+// metadata metrics that are kept in the stores built by the Builder. Keys that
+// are not allowed are dropped before being stored, to reduce memory usage.
+func (b *Builder) WithLabelsAllowList(l store.LabelsAllowList) {
+	b.labelsAllowList = l
+}
+
 // WithPodCollectionFromKubelet configures the builder to collect pods from the
 // Kubelet instead of the API server. This has no effect if pod collection is
 // disabled.
@@ -212,7 +221,7 @@ func GenerateStores[T any](
 	}
 
 	if b.namespaces.IsAllNamespaces() {
-		store := store.NewMetricsStore(composedMetricGenFuncs, reflect.TypeOf(expectedType).String())
+		store := b.newMetricsStore(composedMetricGenFuncs, reflect.TypeOf(expectedType).String())
 
 		if isPod {
 			// Pods are handled differently because depending on the configuration
@@ -228,7 +237,7 @@ func GenerateStores[T any](
 
 	stores := make([]cache.Store, 0, len(b.namespaces))
 	for _, ns := range b.namespaces {
-		store := store.NewMetricsStore(composedMetricGenFuncs, reflect.TypeOf(expectedType).String())
+		store := b.newMetricsStore(composedMetricGenFuncs, reflect.TypeOf(expectedType).String())
 		if isPod {
 			// Pods are handled differently because depending on the configuration
 			// they're collected from the API server or the Kubelet.
@@ -253,6 +262,14 @@ func (b *Builder) GenerateStores(
 	return GenerateStores(b, metricFamilies, expectedType, b.kubeClient, listWatchFunc, useAPIServerCache)
 }
 
+// newMetricsStore returns a new metrics store configured with the labels
+// allowlist of the Builder.
+func (b *Builder) newMetricsStore(generateFunc func(interface{}) []metric.FamilyInterface, metricsType string) *store.MetricsStore {
+	s := store.NewMetricsStore(generateFunc, metricsType)
+	s.WithLabelsAllowList(b.labelsAllowList)
+	return s
+}
+
 func (b *Builder) getCustomResourceClient(resourceName string) interface{} {
 	if client, ok := b.customResourceClients[resourceName]; ok {
 		return client
@@ -382,14 +399,14 @@ func generateConfigMapStores(
 
 	if b.namespaces.IsAllNamespaces() {
 		log.Infof("Using NamespaceAll for ConfigMap collection.")
-		store := store.NewMetricsStore(composedMetricGenFuncs, "configmap")
+		store := b.newMetricsStore(composedMetricGenFuncs, "configmap")
 		listWatcher := createConfigMapListWatch(metadataClient, gvr, v1.NamespaceAll)
 		b.startReflector(&corev1.ConfigMap{}, store, listWatcher, useAPIServerCache)
 		return []cache.Store{store}, nil
 	}
 
 	for _, ns := range b.namespaces {
-		store := store.NewMetricsStore(composedMetricGenFuncs, "configmap")
+		store := b.newMetricsStore(composedMetricGenFuncs, "configmap")
 		listWatcher := createConfigMapListWatch(metadataClient, gvr, ns)
 		b.startReflector(&corev1.ConfigMap{}, store, listWatcher, useAPIServerCache)
 		stores = append(stores, store)
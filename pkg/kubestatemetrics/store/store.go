@@ -11,6 +11,7 @@ package store
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -31,10 +32,65 @@ type MetricsStore struct {
 	// generateMetricsFunc generates metrics based on a given Kubernetes object
 	// and returns them grouped by metric family.
 	generateMetricsFunc func(interface{}) []metric.FamilyInterface
+	// labelsAllowList restricts the label and annotation keys kept for the
+	// metadata metric families. A nil allowlist keeps everything.
+	labelsAllowList LabelsAllowList
+	// footprints tracks the size of the labels generated and stored per
+	// Kubernetes object id. It is only populated when labelsAllowList is set.
+	footprints map[types.UID]LabelsFootprint
 
 	MetricsType string
 }
 
+// LabelsAllowList restricts which label and annotation keys of the metadata
+// metric families (kube_<resource>_labels and kube_<resource>_annotations) are
+// kept in memory. It maps a metric family name to the set of allowed keys, a
+// nil set allowing all of them. Metadata families absent from the allowlist
+// don't keep any label or annotation key.
+// Only keys prefixed by "label_" or "annotation_" are subject to filtering,
+// the other keys (namespace, resource name...) are always kept.
+type LabelsAllowList map[string]map[string]struct{}
+
+// isAllowed returns whether the key of a metric in the given family should be
+// kept in the store.
+func (l LabelsAllowList) isAllowed(family, key string) bool {
+	if l == nil || !isMetadataFamily(family) {
+		return true
+	}
+
+	if !strings.HasPrefix(key, "label_") && !strings.HasPrefix(key, "annotation_") {
+		return true
+	}
+
+	allowed, found := l[family]
+	if !found {
+		return false
+	}
+
+	if allowed == nil {
+		return true
+	}
+
+	_, found = allowed[key]
+	return found
+}
+
+func isMetadataFamily(family string) bool {
+	return strings.HasPrefix(family, "kube_") && (strings.HasSuffix(family, "_labels") || strings.HasSuffix(family, "_annotations"))
+}
+
+// LabelsFootprint is an estimation, in bytes, of the memory used by the metric
+// labels before (Generated) and after (Stored) applying the labels allowlist.
+type LabelsFootprint struct {
+	Generated int
+	Stored    int
+}
+
+func (f *LabelsFootprint) add(other LabelsFootprint) {
+	f.Generated += other.Generated
+	f.Stored += other.Stored
+}
+
 // DDMetric represents the data we care about for a context.
 type DDMetric struct {
 	Labels map[string]string
@@ -57,14 +113,37 @@ func NewMetricsStore(generateFunc func(interface{}) []metric.FamilyInterface, mt
 	}
 }
 
-func (d *DDMetricsFam) extract(f metric.Family) {
+// WithLabelsAllowList configures the store to only keep the label and
+// annotation keys allowed by the given allowlist.
+// It must be called before the store is populated.
+func (s *MetricsStore) WithLabelsAllowList(l LabelsAllowList) {
+	s.labelsAllowList = l
+	if l != nil {
+		s.footprints = map[types.UID]LabelsFootprint{}
+	}
+}
+
+// LabelsFootprint returns an estimation of the memory used by the labels of
+// the metrics held by the store, before and after applying the allowlist.
+func (s *MetricsStore) LabelsFootprint() LabelsFootprint {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	total := LabelsFootprint{}
+	for _, f := range s.footprints {
+		total.add(f)
+	}
+	return total
+}
+
+func (d *DDMetricsFam) extract(f metric.Family, allowList LabelsAllowList, footprint *LabelsFootprint) {
 	// f.Type is not extracted (value of gauge, counter etc) as we only support gauges.
 	d.Name = f.Name
 	for _, m := range f.Metrics {
 		var err error
 		s := DDMetric{}
 		s.Val = m.Value
-		s.Labels, err = buildTags(m)
+		s.Labels, err = buildTags(m, f.Name, allowList, footprint)
 		if err != nil {
 			// TODO test how verbose that could be.
 			log.Errorf("Could not retrieve the labels for %s: %v", f.Name, err)
@@ -83,6 +162,11 @@ func (s *MetricsStore) Add(obj interface{}) error {
 		return err
 	}
 
+	var footprint *LabelsFootprint
+	if s.labelsAllowList != nil {
+		footprint = &LabelsFootprint{}
+	}
+
 	metricsForUID := s.generateMetricsFunc(obj)
 	convertedMetricsForUID := make([]DDMetricsFam, len(metricsForUID))
 	for i, f := range metricsForUID {
@@ -90,23 +174,41 @@ func (s *MetricsStore) Add(obj interface{}) error {
 			// Used to build a map to easily identify the Object associated with the metrics
 			Type: s.MetricsType,
 		}
-		f.Inspect(metricConvertedList.extract)
+		f.Inspect(func(family metric.Family) {
+			metricConvertedList.extract(family, s.labelsAllowList, footprint)
+		})
 		convertedMetricsForUID[i] = metricConvertedList
 	}
 	// We need to keep the store with UID as a key to handle the lifecycle of the objects and the metrics attached.
 	s.mutex.Lock()
 	s.metrics[o.GetUID()] = convertedMetricsForUID
+	if footprint != nil {
+		s.footprints[o.GetUID()] = *footprint
+	}
 	s.mutex.Unlock()
 
 	return nil
 }
 
-func buildTags(metrics *metric.Metric) (map[string]string, error) {
+// buildTags converts the labels of a metric into a map, dropping the keys not
+// allowed by the allowlist. The footprint, when not nil, is incremented with
+// the size of the generated and the stored labels.
+func buildTags(metrics *metric.Metric, family string, allowList LabelsAllowList, footprint *LabelsFootprint) (map[string]string, error) {
 	if len(metrics.LabelKeys) != len(metrics.LabelValues) {
 		return nil, fmt.Errorf("LabelKeys and LabelValues not same size")
 	}
 	tags := make(map[string]string, len(metrics.LabelValues))
 	for i, key := range metrics.LabelKeys {
+		size := len(key) + len(metrics.LabelValues[i])
+		if footprint != nil {
+			footprint.Generated += size
+		}
+		if !allowList.isAllowed(family, key) {
+			continue
+		}
+		if footprint != nil {
+			footprint.Stored += size
+		}
 		tags[key] = metrics.LabelValues[i]
 	}
 	return tags, nil
@@ -129,6 +231,7 @@ func (s *MetricsStore) Delete(obj interface{}) error {
 	defer s.mutex.Unlock()
 
 	delete(s.metrics, o.GetUID())
+	delete(s.footprints, o.GetUID())
 
 	return nil
 }
@@ -158,6 +261,9 @@ func (s *MetricsStore) GetByKey(_ string) (item interface{}, exists bool, err er
 func (s *MetricsStore) Replace(list []interface{}, _ string) error {
 	s.mutex.Lock()
 	s.metrics = map[types.UID][]DDMetricsFam{}
+	if s.footprints != nil {
+		s.footprints = map[types.UID]LabelsFootprint{}
+	}
 	s.mutex.Unlock()
 
 	for _, o := range list {
@@ -106,7 +106,7 @@ func TestBuildTags(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			m, err := buildTags(test.in)
+			m, err := buildTags(test.in, "kube_node_created", nil, nil)
 			if err != nil {
 				assert.Error(t, err, test.err)
 			}
@@ -118,6 +118,76 @@ func TestBuildTags(t *testing.T) {
 	}
 }
 
+func TestLabelsAllowList(t *testing.T) {
+	allowList := LabelsAllowList{
+		"kube_pod_labels":      {"label_app": {}},
+		"kube_node_labels":     nil,
+		"kube_pod_annotations": {},
+	}
+
+	tests := []struct {
+		name     string
+		family   string
+		key      string
+		expected bool
+	}{
+		{name: "allowed label", family: "kube_pod_labels", key: "label_app", expected: true},
+		{name: "not allowed label", family: "kube_pod_labels", key: "label_team", expected: false},
+		{name: "non label key is kept", family: "kube_pod_labels", key: "namespace", expected: true},
+		{name: "all labels allowed", family: "kube_node_labels", key: "label_team", expected: true},
+		{name: "empty set", family: "kube_pod_annotations", key: "annotation_foo", expected: false},
+		{name: "family absent from allowlist", family: "kube_deployment_labels", key: "label_app", expected: false},
+		{name: "non metadata family", family: "kube_pod_info", key: "label_app", expected: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, allowList.isAllowed(test.family, test.key))
+		})
+	}
+
+	var nilAllowList LabelsAllowList
+	assert.True(t, nilAllowList.isAllowed("kube_pod_labels", "label_team"))
+}
+
+func TestAddWithLabelsAllowList(t *testing.T) {
+	genFunc := func(interface{}) []metric.FamilyInterface {
+		return []metric.FamilyInterface{
+			&metric.Family{
+				Name: "kube_pod_labels",
+				Metrics: []*metric.Metric{
+					{
+						LabelKeys:   []string{"namespace", "pod", "label_app", "label_pod_template_hash"},
+						LabelValues: []string{"default", "foo", "web", "6d4cf56db6"},
+						Value:       1,
+					},
+				},
+			},
+		}
+	}
+
+	ms := NewMetricsStore(genFunc, "*v1.Pod")
+	ms.WithLabelsAllowList(LabelsAllowList{"kube_pod_labels": {"label_app": {}}})
+
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", UID: "uid"}}
+	require.NoError(t, ms.Add(pod))
+
+	res := ms.Push(GetAllFamilies, GetAllMetrics)
+	require.Len(t, res["kube_pod_labels"], 1)
+	require.Len(t, res["kube_pod_labels"][0].ListMetrics, 1)
+	assert.Equal(t, map[string]string{
+		"namespace": "default",
+		"pod":       "foo",
+		"label_app": "web",
+	}, res["kube_pod_labels"][0].ListMetrics[0].Labels)
+
+	footprint := ms.LabelsFootprint()
+	assert.Equal(t, len("namespacedefaultpodfoolabel_appweblabel_pod_template_hash6d4cf56db6"), footprint.Generated)
+	assert.Equal(t, len("namespacedefaultpodfoolabel_appweb"), footprint.Stored)
+
+	require.NoError(t, ms.Delete(pod))
+	assert.Equal(t, LabelsFootprint{}, ms.LabelsFootprint())
+}
+
 func TestPush(t *testing.T) {
 	storeName := "test"
 	tests := []struct {
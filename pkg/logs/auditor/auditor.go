@@ -8,6 +8,7 @@ package auditor
 import (
 	"encoding/json"
 	"fmt"
+	"hash/crc32"
 	"os"
 	"path/filepath"
 	"sync"
@@ -25,6 +26,17 @@ const DefaultRegistryFilename = "registry.json"
 
 const defaultFlushPeriod = 1 * time.Second
 const defaultCleanupPeriod = 300 * time.Second
+const defaultCompactionPeriod = 60 * time.Second
+
+// the journal is compacted early when it grows past maxJournalSize
+const maxJournalSize = 1024 * 1024
+const maxJournalRecordSize = 1024 * 1024
+
+// number of previous snapshots of the registry kept as backups
+const registryBackupCount = 2
+
+const journalFileSuffix = ".journal"
+const corruptedFileSuffix = ".corrupted"
 
 // latest version of the API used by the auditor to retrieve the registry from disk.
 const registryAPIVersion = 2
@@ -69,17 +81,25 @@ type RegistryAuditor struct {
 	registryDirPath string
 	registryTmpFile string
 	registryMutex   sync.Mutex
-	entryTTL        time.Duration
-	done            chan struct{}
+	// dirty holds the identifiers of the entries updated since the last flush
+	dirty       map[string]struct{}
+	journalPath string
+	journal     *os.File
+	journalSize int64
+	entryTTL    time.Duration
+	done        chan struct{}
 }
 
 // New returns an initialized Auditor
 func New(runPath string, filename string, ttl time.Duration, health *health.Handle) *RegistryAuditor {
+	registryPath := filepath.Join(runPath, filename)
 	return &RegistryAuditor{
 		health:          health,
-		registryPath:    filepath.Join(runPath, filename),
+		registryPath:    registryPath,
 		registryDirPath: runPath,
 		registryTmpFile: filepath.Base(filename) + ".tmp",
+		dirty:           make(map[string]struct{}),
+		journalPath:     registryPath + journalFileSuffix,
 		entryTTL:        ttl,
 	}
 }
@@ -88,7 +108,7 @@ func New(runPath string, filename string, ttl time.Duration, health *health.Hand
 func (a *RegistryAuditor) Start() {
 	a.createChannels()
 	a.registry = a.recoverRegistry()
-	a.cleanupRegistry()
+	a.cleanupAndCompactRegistry()
 	go a.run()
 }
 
@@ -96,9 +116,10 @@ func (a *RegistryAuditor) Start() {
 func (a *RegistryAuditor) Stop() {
 	a.closeChannels()
 	a.cleanupRegistry()
-	if err := a.flushRegistry(); err != nil {
+	if err := a.compactRegistry(); err != nil {
 		log.Warn(err)
 	}
+	a.closeJournal()
 }
 
 func (a *RegistryAuditor) createChannels() {
@@ -154,14 +175,26 @@ func (a *RegistryAuditor) GetTailingMode(identifier string) string {
 func (a *RegistryAuditor) run() {
 	cleanUpTicker := time.NewTicker(defaultCleanupPeriod)
 	flushTicker := time.NewTicker(defaultFlushPeriod)
+	compactionTicker := time.NewTicker(defaultCompactionPeriod)
+	var fileError sync.Once
 	defer func() {
 		// clean the context
 		cleanUpTicker.Stop()
 		flushTicker.Stop()
+		compactionTicker.Stop()
 		a.done <- struct{}{}
 	}()
 
-	var fileError sync.Once
+	logFileError := func(err error) {
+		if os.IsPermission(err) || os.IsNotExist(err) {
+			fileError.Do(func() {
+				log.Warn(err)
+			})
+		} else {
+			log.Warn(err)
+		}
+	}
+
 	for {
 		select {
 		case <-a.health.C:
@@ -176,53 +209,119 @@ func (a *RegistryAuditor) run() {
 			}
 		case <-cleanUpTicker.C:
 			// remove expired offsets from registry
-			a.cleanupRegistry()
+			a.cleanupAndCompactRegistry()
 		case <-flushTicker.C:
-			// saves current registry into disk
-			err := a.flushRegistry()
-			if err != nil {
-				if os.IsPermission(err) || os.IsNotExist(err) {
-					fileError.Do(func() {
-						log.Warn(err)
-					})
-				} else {
-					log.Warn(err)
+			// appends the updated entries to the journal on disk
+			if err := a.flushRegistry(); err != nil {
+				logFileError(err)
+			}
+			if a.journalSize >= maxJournalSize {
+				if err := a.compactRegistry(); err != nil {
+					logFileError(err)
 				}
 			}
+		case <-compactionTicker.C:
+			// saves current registry into disk, and truncates the journal
+			if err := a.compactRegistry(); err != nil {
+				logFileError(err)
+			}
 		}
 	}
 }
 
-// recoverRegistry rebuilds the registry from the state file found at path
+// recoverRegistry rebuilds the registry from the last good snapshot found at
+// path or in its backups, and the journal of the entries updated since
 func (a *RegistryAuditor) recoverRegistry() map[string]*RegistryEntry {
-	mr, err := os.ReadFile(a.registryPath)
+	registry := a.recoverSnapshot()
+
+	replayed, corrupted, err := replayJournal(a.journalPath, registry)
 	if err != nil {
-		if os.IsNotExist(err) {
-			log.Infof("Could not find state file at %q, will start with default offsets", a.registryPath)
-		} else {
-			log.Error(err)
+		log.Errorf("Could not read the registry journal at %q: %v", a.journalPath, err)
+	}
+	if corrupted > 0 {
+		log.Warnf("Skipped %d corrupted records of the registry journal at %q", corrupted, a.journalPath)
+	}
+	if replayed > 0 {
+		log.Debugf("Replayed %d records of the registry journal at %q", replayed, a.journalPath)
+	}
+	return registry
+}
+
+// recoverSnapshot returns the registry of the last good snapshot. The corrupted
+// snapshot is kept aside for troubleshooting, and the backups are tried instead.
+func (a *RegistryAuditor) recoverSnapshot() map[string]*RegistryEntry {
+	found := false
+	for i := 0; i <= registryBackupCount; i++ {
+		path := a.backupPath(i)
+		mr, err := os.ReadFile(path)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				log.Error(err)
+			}
+			continue
 		}
-		return make(map[string]*RegistryEntry)
+		found = true
+
+		r, err := a.unmarshalRegistry(mr)
+		if err != nil {
+			log.Errorf("Registry at %q is corrupted: %v, falling back to the previous snapshot", path, err)
+			if i == 0 {
+				if err := os.Rename(path, path+corruptedFileSuffix); err != nil {
+					log.Warn(err)
+				}
+			}
+			continue
+		}
+		if i > 0 {
+			log.Warnf("Recovered the registry from the backup at %q", path)
+		}
+		return r
 	}
-	r, err := a.unmarshalRegistry(mr)
-	if err != nil {
-		log.Error(err)
-		return make(map[string]*RegistryEntry)
+
+	if !found {
+		log.Infof("Could not find state file at %q, will start with default offsets", a.registryPath)
+	} else {
+		log.Errorf("Could not recover the registry from %q or its backups, will start with default offsets", a.registryPath)
 	}
-	return r
+	return make(map[string]*RegistryEntry)
+}
+
+// backupPath returns the path of the i-th previous snapshot, 0 being the current one
+func (a *RegistryAuditor) backupPath(i int) string {
+	if i == 0 {
+		return a.registryPath
+	}
+	return fmt.Sprintf("%s.%d", a.registryPath, i)
 }
 
-// cleanupRegistry removes expired entries from the registry
-func (a *RegistryAuditor) cleanupRegistry() {
+// cleanupRegistry removes expired entries from the registry, and returns the
+// number of removed entries
+func (a *RegistryAuditor) cleanupRegistry() int {
 	a.registryMutex.Lock()
 	defer a.registryMutex.Unlock()
 	expireBefore := time.Now().UTC().Add(-a.entryTTL)
+	removed := 0
 	for path, entry := range a.registry {
 		if entry.LastUpdated.Before(expireBefore) {
 			log.Debugf("TTL for %s expired, removing from registry.", path)
 			delete(a.registry, path)
+			delete(a.dirty, path)
+			removed++
 		}
 	}
+	return removed
+}
+
+// cleanupAndCompactRegistry removes expired entries from the registry. The
+// journal only records updates, so the registry is compacted when entries are
+// removed: their records would otherwise be replayed on the next start.
+func (a *RegistryAuditor) cleanupAndCompactRegistry() {
+	if a.cleanupRegistry() == 0 {
+		return
+	}
+	if err := a.compactRegistry(); err != nil {
+		log.Warn(err)
+	}
 }
 
 // updateRegistry updates the registry entry matching identifier with new the offset and timestamp
@@ -250,6 +349,7 @@ func (a *RegistryAuditor) updateRegistry(identifier string, offset string, taili
 		TailingMode:        tailingMode,
 		IngestionTimestamp: ingestionTimestamp,
 	}
+	a.dirty[identifier] = struct{}{}
 }
 
 // readOnlyRegistryCopy returns a read only copy of the registry
@@ -273,8 +373,74 @@ func (a *RegistryAuditor) readOnlyRegistryEntryCopy(identifier string) (Registry
 	return *entry, true
 }
 
-// flushRegistry writes on disk the registry at the given path
+// dirtyRegistryEntries returns a copy of the entries updated since the last
+// flush, and resets them
+func (a *RegistryAuditor) dirtyRegistryEntries() map[string]RegistryEntry {
+	a.registryMutex.Lock()
+	defer a.registryMutex.Unlock()
+	r := make(map[string]RegistryEntry, len(a.dirty))
+	for identifier := range a.dirty {
+		if entry, exists := a.registry[identifier]; exists {
+			r[identifier] = *entry
+		}
+	}
+	clear(a.dirty)
+	return r
+}
+
+// flushRegistry appends the entries updated since the last flush to the journal
 func (a *RegistryAuditor) flushRegistry() error {
+	r := a.dirtyRegistryEntries()
+	if len(r) == 0 {
+		return nil
+	}
+
+	var buf []byte
+	for identifier, entry := range r {
+		record, err := marshalJournalRecord(identifier, entry)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, record...)
+	}
+
+	if a.journal == nil {
+		f, err := os.OpenFile(a.journalPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			return err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+		a.journal = f
+		a.journalSize = info.Size()
+	}
+	n, err := a.journal.Write(buf)
+	a.journalSize += int64(n)
+	return err
+}
+
+// closeJournal closes the journal, it's reopened on the next flush
+func (a *RegistryAuditor) closeJournal() {
+	if a.journal == nil {
+		return
+	}
+	if err := a.journal.Close(); err != nil {
+		log.Warn(err)
+	}
+	a.journal = nil
+}
+
+// compactRegistry writes on disk a snapshot of the registry,
+// and truncates the journal. The previous snapshots are rotated as backups.
+func (a *RegistryAuditor) compactRegistry() error {
+	// the entries are all written to the snapshot
+	a.registryMutex.Lock()
+	clear(a.dirty)
+	a.registryMutex.Unlock()
+
 	r := a.readOnlyRegistryCopy()
 	mr, err := a.marshalRegistry(r)
 	if err != nil {
@@ -302,15 +468,40 @@ func (a *RegistryAuditor) flushRegistry() error {
 	if err = f.Close(); err != nil {
 		return err
 	}
-	err = os.Rename(tmpName, a.registryPath)
-	return err
+
+	a.rotateBackups()
+	if err = os.Rename(tmpName, a.registryPath); err != nil {
+		return err
+	}
+
+	// the records of the journal are now in the snapshot
+	a.closeJournal()
+	if err := os.Truncate(a.journalPath, 0); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	a.journalSize = 0
+	return nil
+}
+
+// rotateBackups keeps the current snapshot as the most recent backup
+func (a *RegistryAuditor) rotateBackups() {
+	for i := registryBackupCount; i > 0; i-- {
+		if err := os.Rename(a.backupPath(i-1), a.backupPath(i)); err != nil && !os.IsNotExist(err) {
+			log.Debugf("Could not rotate the registry backup %q: %v", a.backupPath(i-1), err)
+		}
+	}
 }
 
-// marshalRegistry marshals a registry
+// marshalRegistry marshals a registry with its checksum
 func (a *RegistryAuditor) marshalRegistry(registry map[string]RegistryEntry) ([]byte, error) {
-	r := JSONRegistry{
+	mr, err := json.Marshal(registry)
+	if err != nil {
+		return nil, err
+	}
+	r := checksummedRegistry{
 		Version:  registryAPIVersion,
-		Registry: registry,
+		Checksum: crc32.Checksum(mr, crcTable),
+		Registry: mr,
 	}
 	return json.Marshal(r)
 }
@@ -326,6 +517,16 @@ func (a *RegistryAuditor) unmarshalRegistry(b []byte) (map[string]*RegistryEntry
 	if !exists {
 		return nil, fmt.Errorf("registry retrieved from disk must have a version number")
 	}
+	// the registries written before the checksums were introduced have none
+	if _, exists := r["Checksum"]; exists {
+		var cr checksummedRegistry
+		if err := json.Unmarshal(b, &cr); err != nil {
+			return nil, err
+		}
+		if crc32.Checksum(cr.Registry, crcTable) != cr.Checksum {
+			return nil, errChecksumMismatch
+		}
+	}
 	// ensure backward compatibility
 	switch int(version) {
 	case 2:
@@ -6,6 +6,8 @@
 package auditor
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -68,10 +70,10 @@ func (suite *AuditorTestSuite) TestAuditorFlushesAndRecoversRegistry() {
 		Offset:      "42",
 		TailingMode: "end",
 	}
-	suite.NoError(suite.a.flushRegistry())
+	suite.NoError(suite.a.compactRegistry())
 	r, err := os.ReadFile(suite.testRegistryPath)
 	suite.NoError(err)
-	suite.Equal("{\"Version\":2,\"Registry\":{\"testpath\":{\"LastUpdated\":\"2006-01-12T01:01:01.000000001Z\",\"Offset\":\"42\",\"TailingMode\":\"end\",\"IngestionTimestamp\":0}}}", string(r))
+	suite.Equal("{\"Version\":2,\"Checksum\":914086808,\"Registry\":{\"testpath\":{\"LastUpdated\":\"2006-01-12T01:01:01.000000001Z\",\"Offset\":\"42\",\"TailingMode\":\"end\",\"IngestionTimestamp\":0}}}", string(r))
 
 	suite.a.registry = make(map[string]*RegistryEntry)
 	suite.a.registry = suite.a.recoverRegistry()
@@ -112,6 +114,185 @@ func (suite *AuditorTestSuite) TestAuditorCleansupRegistry() {
 	suite.Equal("43", suite.a.registry[otherpath].Offset)
 }
 
+func (suite *AuditorTestSuite) TestAuditorCompactsRegistryAfterCleanup() {
+	suite.a.registry = make(map[string]*RegistryEntry)
+	suite.a.updateRegistry(suite.source.Config.Path, "42", "end", 0)
+	suite.a.updateRegistry("otherpath", "43", "end", 0)
+	suite.NoError(suite.a.flushRegistry())
+	suite.a.registry[suite.source.Config.Path].LastUpdated = time.Date(2006, time.January, 12, 1, 1, 1, 1, time.UTC)
+
+	suite.a.cleanupAndCompactRegistry()
+	suite.Equal(1, len(suite.a.registry))
+
+	// the removed entry isn't replayed from the journal
+	journal, err := os.ReadFile(suite.testRegistryPath + journalFileSuffix)
+	suite.NoError(err)
+	suite.Empty(journal)
+	registry := suite.a.recoverRegistry()
+	suite.NotContains(registry, suite.source.Config.Path)
+	suite.Equal("43", registry["otherpath"].Offset)
+}
+
+func (suite *AuditorTestSuite) TestAuditorJournalsRegistryUpdates() {
+	suite.a.registry = make(map[string]*RegistryEntry)
+	suite.a.updateRegistry(suite.source.Config.Path, "42", "end", 0)
+	suite.NoError(suite.a.compactRegistry())
+
+	suite.a.updateRegistry(suite.source.Config.Path, "43", "end", 1)
+	suite.a.updateRegistry("otherpath", "10", "beginning", 1)
+	suite.NoError(suite.a.flushRegistry())
+	// nothing was updated since the last flush
+	suite.NoError(suite.a.flushRegistry())
+	suite.a.updateRegistry("otherpath", "11", "beginning", 2)
+	suite.NoError(suite.a.flushRegistry())
+	suite.a.closeJournal()
+
+	journal, err := os.ReadFile(suite.testRegistryPath + journalFileSuffix)
+	suite.NoError(err)
+	suite.Len(bytes.Split(bytes.TrimSpace(journal), []byte("\n")), 3)
+
+	// the snapshot is left untouched by the flushes
+	registry := suite.a.recoverSnapshot()
+	suite.Equal("42", registry[suite.source.Config.Path].Offset)
+
+	registry = suite.a.recoverRegistry()
+	suite.Equal("43", registry[suite.source.Config.Path].Offset)
+	suite.Equal("11", registry["otherpath"].Offset)
+
+	suite.NoError(suite.a.compactRegistry())
+	journal, err = os.ReadFile(suite.testRegistryPath + journalFileSuffix)
+	suite.NoError(err)
+	suite.Empty(journal)
+	suite.Equal("11", suite.a.recoverRegistry()["otherpath"].Offset)
+}
+
+func (suite *AuditorTestSuite) TestAuditorSkipsCorruptedJournalRecords() {
+	suite.a.registry = make(map[string]*RegistryEntry)
+	suite.a.updateRegistry(suite.source.Config.Path, "42", "end", 0)
+	suite.a.updateRegistry("otherpath", "10", "end", 0)
+	suite.NoError(suite.a.flushRegistry())
+	suite.a.closeJournal()
+
+	journalPath := suite.testRegistryPath + journalFileSuffix
+	journal, err := os.ReadFile(journalPath)
+	suite.NoError(err)
+	// corrupt the first record, and truncate the last one as if the agent crashed while writing it
+	journal = bytes.Replace(journal, []byte(`"Offset":"`), []byte(`"Offset":"9`), 1)
+	torn, err := marshalJournalRecord("tornpath", RegistryEntry{Offset: "7"})
+	suite.NoError(err)
+	journal = append(journal, torn[:len(torn)/2]...)
+	suite.NoError(os.WriteFile(journalPath, journal, 0644))
+
+	registry := make(map[string]*RegistryEntry)
+	replayed, corrupted, err := replayJournal(journalPath, registry)
+	suite.NoError(err)
+	suite.Equal(1, replayed)
+	suite.Equal(2, corrupted)
+	suite.Len(registry, 1)
+	suite.NotContains(registry, "tornpath")
+}
+
+func (suite *AuditorTestSuite) TestAuditorSkipsStaleJournalRecords() {
+	suite.a.registry = make(map[string]*RegistryEntry)
+	suite.a.updateRegistry(suite.source.Config.Path, "42", "end", 0)
+	suite.NoError(suite.a.flushRegistry())
+	suite.a.closeJournal()
+
+	// the agent stopped after the snapshot was written, before the journal was truncated
+	suite.a.registry[suite.source.Config.Path].LastUpdated = time.Now().UTC().Add(time.Minute)
+	suite.a.registry[suite.source.Config.Path].Offset = "43"
+	mr, err := suite.a.marshalRegistry(suite.a.readOnlyRegistryCopy())
+	suite.NoError(err)
+	suite.NoError(os.WriteFile(suite.testRegistryPath, mr, 0644))
+
+	suite.Equal("43", suite.a.recoverRegistry()[suite.source.Config.Path].Offset)
+}
+
+func (suite *AuditorTestSuite) TestAuditorRecoversCorruptedRegistryFromBackup() {
+	suite.a.registry = make(map[string]*RegistryEntry)
+	suite.a.updateRegistry(suite.source.Config.Path, "41", "end", 0)
+	suite.NoError(suite.a.compactRegistry())
+	suite.a.updateRegistry(suite.source.Config.Path, "42", "end", 1)
+	suite.NoError(suite.a.compactRegistry())
+	suite.a.updateRegistry(suite.source.Config.Path, "43", "end", 2)
+	suite.NoError(suite.a.compactRegistry())
+	suite.a.updateRegistry(suite.source.Config.Path, "44", "end", 3)
+	suite.NoError(suite.a.compactRegistry())
+
+	// only the last snapshots are kept
+	for i := 1; i <= registryBackupCount; i++ {
+		suite.FileExists(suite.a.backupPath(i))
+	}
+	suite.NoFileExists(suite.a.backupPath(registryBackupCount + 1))
+
+	r, err := os.ReadFile(suite.testRegistryPath)
+	suite.NoError(err)
+	suite.NoError(os.WriteFile(suite.testRegistryPath, bytes.Replace(r, []byte(`"44"`), []byte(`"45"`), 1), 0644))
+
+	registry := suite.a.recoverRegistry()
+	suite.Equal("43", registry[suite.source.Config.Path].Offset)
+	// the corrupted registry is kept for troubleshooting
+	suite.FileExists(suite.testRegistryPath + corruptedFileSuffix)
+	suite.NoFileExists(suite.testRegistryPath)
+
+	suite.NoError(os.WriteFile(suite.a.backupPath(1), []byte("{\"Version\":2,"), 0644))
+	suite.Equal("42", suite.a.recoverRegistry()[suite.source.Config.Path].Offset)
+
+	suite.NoError(os.Remove(suite.a.backupPath(2)))
+	suite.Empty(suite.a.recoverRegistry())
+}
+
+func (suite *AuditorTestSuite) TestAuditorRecoversRegistryWithoutChecksum() {
+	suite.NoError(os.WriteFile(suite.testRegistryPath, []byte(`{"Version":2,"Registry":{"testpath":{"Offset":"42"}}}`), 0644))
+	suite.Equal("42", suite.a.recoverRegistry()[suite.source.Config.Path].Offset)
+}
+
+func (suite *AuditorTestSuite) TestAuditorExportsRegistry() {
+	suite.a.registry = make(map[string]*RegistryEntry)
+	suite.a.updateRegistry("journald:default", "cursor", "end", 1)
+
+	exported := suite.a.ExportRegistry()
+	suite.Equal(registryAPIVersion, exported.Version)
+	suite.Equal("cursor", exported.Registry["journald:default"].Offset)
+
+	// the export is a copy of the registry
+	suite.a.updateRegistry("journald:default", "next cursor", "end", 2)
+	suite.Equal("cursor", exported.Registry["journald:default"].Offset)
+}
+
+func (suite *AuditorTestSuite) TestImportRegistry() {
+	suite.a.registry = make(map[string]*RegistryEntry)
+	suite.a.updateRegistry("journald:default", "old cursor", "end", 1)
+	suite.a.updateRegistry(suite.source.Config.Path, "42", "end", 1)
+	suite.NoError(suite.a.compactRegistry())
+	suite.a.updateRegistry("file:/var/log/app.log", "10", "end", 1)
+	suite.NoError(suite.a.flushRegistry())
+	suite.a.closeJournal()
+
+	exported := New(suite.T().TempDir(), DefaultRegistryFilename, time.Hour, nil)
+	exported.registry = make(map[string]*RegistryEntry)
+	exported.updateRegistry("journald:default", "new cursor", "end", 2)
+	exported.updateRegistry("file:/var/log/other.log", "20", "beginning", 2)
+	b, err := json.Marshal(exported.ExportRegistry())
+	suite.NoError(err)
+
+	imported, err := ImportRegistry(suite.testRunPathDir, DefaultRegistryFilename, b)
+	suite.NoError(err)
+	suite.Equal(2, imported)
+
+	registry := suite.a.recoverRegistry()
+	suite.Len(registry, 4)
+	suite.Equal("new cursor", registry["journald:default"].Offset)
+	suite.Equal("20", registry["file:/var/log/other.log"].Offset)
+	suite.Equal("beginning", registry["file:/var/log/other.log"].TailingMode)
+	// the entries of the journal are kept
+	suite.Equal("10", registry["file:/var/log/app.log"].Offset)
+	suite.Equal("42", registry[suite.source.Config.Path].Offset)
+
+	_, err = ImportRegistry(suite.testRunPathDir, DefaultRegistryFilename, []byte(`{"Registry":{}}`))
+	suite.Error(err)
+}
+
 func TestScannerTestSuite(t *testing.T) {
 	suite.Run(t, new(AuditorTestSuite))
 }
@@ -0,0 +1,53 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package auditor
+
+import (
+	"os"
+)
+
+// RegistryExporter is implemented by the auditors able to export their registry.
+type RegistryExporter interface {
+	// ExportRegistry returns a copy of the registry.
+	ExportRegistry() JSONRegistry
+}
+
+// ExportRegistry returns a copy of the registry, in a format accepted by
+// ImportRegistry.
+func (a *RegistryAuditor) ExportRegistry() JSONRegistry {
+	return JSONRegistry{
+		Version:  registryAPIVersion,
+		Registry: a.readOnlyRegistryCopy(),
+	}
+}
+
+// ImportRegistry merges an exported registry, or a registry file of another
+// agent, into the registry stored in runPath. The imported entries replace the
+// existing ones, so that the tailers resume from the imported offsets and
+// journald cursors. It must only be called while the logs agent is stopped, as
+// its auditor would overwrite the registry. It returns the number of imported
+// entries.
+func ImportRegistry(runPath string, filename string, b []byte) (int, error) {
+	a := New(runPath, filename, 0, nil)
+	imported, err := a.unmarshalRegistry(b)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(runPath, 0755); err != nil {
+		return 0, err
+	}
+	a.registry = a.recoverRegistry()
+	for identifier, entry := range imported {
+		a.registry[identifier] = entry
+	}
+	err = a.compactRegistry()
+	a.closeJournal()
+	if err != nil {
+		return 0, err
+	}
+	return len(imported), nil
+}
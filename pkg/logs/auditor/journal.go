@@ -0,0 +1,109 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package auditor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"strconv"
+)
+
+// The registry is persisted as a snapshot, registry.json, and a journal,
+// registry.json.journal, to which the entries updated since the snapshot are
+// appended. The journal is compacted into a new snapshot periodically, the
+// previous snapshots being kept as backups.
+//
+// The snapshot and each record of the journal are checksummed, so that a
+// registry corrupted by a crash is detected, and recovered from the last good
+// snapshot and the valid records of the journal, instead of re-reading all the
+// log files from their beginning.
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+var errChecksumMismatch = errors.New("checksum mismatch")
+
+// checksummedRegistry is the format of the snapshots of the registry, the
+// checksum is computed on the registry as written on disk. It's compatible
+// with JSONRegistry.
+type checksummedRegistry struct {
+	Version  int
+	Checksum uint32
+	Registry json.RawMessage
+}
+
+// journalRecord is a registry entry updated since the last snapshot
+type journalRecord struct {
+	Identifier string
+	Entry      RegistryEntry
+}
+
+// marshalJournalRecord marshals a journal record as a line prefixed with its checksum
+func marshalJournalRecord(identifier string, entry RegistryEntry) ([]byte, error) {
+	b, err := json.Marshal(journalRecord{Identifier: identifier, Entry: entry})
+	if err != nil {
+		return nil, err
+	}
+	line := make([]byte, 0, len(b)+10)
+	line = fmt.Appendf(line, "%08x ", crc32.Checksum(b, crcTable))
+	line = append(line, b...)
+	return append(line, '\n'), nil
+}
+
+// unmarshalJournalRecord unmarshals a line of the journal, and validates its checksum
+func unmarshalJournalRecord(line []byte) (journalRecord, error) {
+	var record journalRecord
+	checksum, b, found := bytes.Cut(line, []byte(" "))
+	if !found {
+		return record, fmt.Errorf("malformed journal record")
+	}
+	expected, err := strconv.ParseUint(string(checksum), 16, 32)
+	if err != nil {
+		return record, fmt.Errorf("malformed journal record checksum: %w", err)
+	}
+	if crc32.Checksum(b, crcTable) != uint32(expected) {
+		return record, errChecksumMismatch
+	}
+	err = json.Unmarshal(b, &record)
+	return record, err
+}
+
+// replayJournal applies the valid records of the journal found at path to the
+// registry. The records older than the entries of the registry are skipped: they
+// were already compacted into the snapshot when the agent stopped before
+// truncating the journal.
+func replayJournal(path string, registry map[string]*RegistryEntry) (replayed int, corrupted int, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJournalRecordSize)
+	for scanner.Scan() {
+		record, err := unmarshalJournalRecord(scanner.Bytes())
+		if err != nil {
+			// the records hold the whole entry, the next ones are still valid
+			corrupted++
+			continue
+		}
+		if current, ok := registry[record.Identifier]; ok && record.Entry.LastUpdated.Before(current.LastUpdated) {
+			continue
+		}
+		entry := record.Entry
+		registry[record.Identifier] = &entry
+		replayed++
+	}
+	return replayed, corrupted, scanner.Err()
+}
@@ -401,7 +401,7 @@ func httpClientFactory(timeout time.Duration, cfg pkgconfigmodel.Reader) func()
 	switch transportConfig {
 	case "http1":
 		// Use default ALPN auto-negotiation to negotiate up to http/1.1
-		transport = httputils.CreateHTTPTransport(cfg)
+		transport = httputils.NewTransport(cfg, httputils.LogsDestination)
 	case "auto":
 		fallthrough
 	default:
@@ -409,7 +409,7 @@ func httpClientFactory(timeout time.Duration, cfg pkgconfigmodel.Reader) func()
 			log.Warnf("Invalid http_protocol '%v', falling back to 'auto'", transportConfig)
 		}
 		// Use default ALPN auto-negotiation and negotiate to HTTP/2 if possible, if not it will automatically fallback to best available protocol
-		transport = httputils.CreateHTTPTransport(cfg, httputils.WithHTTP2())
+		transport = httputils.NewTransport(cfg, httputils.LogsDestination, httputils.WithHTTP2())
 	}
 
 	return func() *http.Client {
@@ -7,6 +7,7 @@
 package file
 
 import (
+	"errors"
 	"regexp"
 	"time"
 
@@ -24,6 +25,7 @@ import (
 	status "github.com/DataDog/datadog-agent/pkg/logs/status/utils"
 	"github.com/DataDog/datadog-agent/pkg/logs/tailers"
 	tailer "github.com/DataDog/datadog-agent/pkg/logs/tailers/file"
+	"github.com/DataDog/datadog-agent/pkg/status/health"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/procfilestats"
 	"github.com/DataDog/datadog-agent/pkg/util/startstop"
@@ -150,6 +152,7 @@ func (s *Launcher) cleanup() {
 func (s *Launcher) scan() {
 	files := s.fileProvider.FilesToTail(s.validatePodContainerID, s.activeSources)
 	filesTailed := make(map[string]bool)
+	failedTailers := make(map[string]bool)
 	var allFiles []string
 
 	log.Debugf("Scan - got %d files from FilesToTail and currently tailing %d files\n", len(files), s.tailers.Count())
@@ -171,7 +174,9 @@ func (s *Launcher) scan() {
 		scanKey := file.GetScanKey()
 		tailer, isTailed := s.tailers.Get(scanKey)
 		if isTailed && tailer.IsFinished() {
-			// skip this tailer as it must be stopped
+			// skip this tailer as it must be stopped, it stopped reading the file on its own because of an error so
+			// it is restarted below
+			failedTailers[scanKey] = true
 			continue
 		}
 
@@ -218,6 +223,9 @@ func (s *Launcher) scan() {
 		if !isTailed && tailersLen < s.tailingLimit {
 			// create a new tailer tailing from the beginning of the file if no offset has been recorded
 			succeeded := s.startNewTailer(file, config.Beginning)
+			if failedTailers[scanKey] {
+				recordTailerRestart(file, succeeded)
+			}
 			if !succeeded {
 				// the setup failed, let's try to tail this file in the next scan
 				continue
@@ -236,6 +244,16 @@ func (s *Launcher) scan() {
 	}
 }
 
+// recordTailerRestart lists the restart of a tailer which stopped reading its file with the restarts of the
+// components supervised by the health checks
+func recordTailerRestart(file *tailer.File, succeeded bool) {
+	var err error
+	if !succeeded {
+		err = errors.New("could not start a new tailer, retrying at the next scan")
+	}
+	health.RecordRestart("logs-tailer:"+file.Path, "the tailer stopped reading the file", err)
+}
+
 // cleanUpRotatedTailers removes any rotated tailers that have stopped from the list
 func (s *Launcher) cleanUpRotatedTailers() {
 	pendingTailers := []*tailer.Tailer{}
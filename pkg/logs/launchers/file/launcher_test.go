@@ -31,6 +31,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/logs/status"
 	"github.com/DataDog/datadog-agent/pkg/logs/tailers"
 	filetailer "github.com/DataDog/datadog-agent/pkg/logs/tailers/file"
+	"github.com/DataDog/datadog-agent/pkg/status/health"
 
 	//nolint:revive // TODO(AML) Fix revive linter
 	tailer "github.com/DataDog/datadog-agent/pkg/logs/tailers/file"
@@ -265,6 +266,47 @@ func TestLauncherScanStartNewTailer(t *testing.T) {
 	}
 }
 
+func TestLauncherRestartsFinishedTailer(t *testing.T) {
+	fakeTagger := taggerMock.SetupFakeTagger(t)
+	testDir := t.TempDir()
+
+	fc := flareController.NewFlareController()
+	launcher := NewLauncher(2, 20*time.Millisecond, false, 10*time.Second, "by_name", fc, fakeTagger)
+	launcher.pipelineProvider = mock.NewMockProvider()
+	launcher.registry = auditor.NewRegistry()
+	outputChan := launcher.pipelineProvider.NextPipelineChan()
+	source := sources.NewLogSource("", &config.LogsConfig{Type: config.FileType, Path: fmt.Sprintf("%s/*.log", testDir)})
+	launcher.activeSources = append(launcher.activeSources, source)
+	status.Clear()
+	status.InitStatus(pkgconfigsetup.Datadog(), util.CreateSources([]*sources.LogSource{source}))
+	defer status.Clear()
+
+	path := fmt.Sprintf("%s/test.log", testDir)
+	assert.Nil(t, os.WriteFile(path, []byte("hello\n"), 0o644))
+
+	launcher.scan()
+	assert.Equal(t, 1, launcher.tailers.Count())
+	msg := <-outputChan
+	assert.Equal(t, "hello", string(msg.GetContent()))
+
+	// the tailer stops reading the file without being stopped by the launcher
+	failed := launcher.tailers.All()[0]
+	failed.Stop()
+	assert.True(t, failed.IsFinished())
+
+	launcher.scan()
+	assert.Equal(t, 1, launcher.tailers.Count())
+	assert.NotSame(t, failed, launcher.tailers.All()[0])
+
+	events := health.GetRestartEvents()
+	if assert.NotEmpty(t, events) {
+		event := events[len(events)-1]
+		assert.Equal(t, "logs-tailer:"+path, event.Component)
+		assert.Equal(t, "the tailer stopped reading the file", event.Reason)
+		assert.Empty(t, event.Error)
+	}
+}
+
 func TestLauncherWithConcurrentContainerTailer(t *testing.T) {
 	testDir := t.TempDir()
 	path := fmt.Sprintf("%s/container.log", testDir)
@@ -0,0 +1,188 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+// Package kubernetesevents launches tailers converting the Kubernetes events into logs
+package kubernetesevents
+
+import (
+	"slices"
+
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/logs/auditor"
+	"github.com/DataDog/datadog-agent/pkg/logs/launchers"
+	"github.com/DataDog/datadog-agent/pkg/logs/pipeline"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+	"github.com/DataDog/datadog-agent/pkg/logs/tailers"
+	tailer "github.com/DataDog/datadog-agent/pkg/logs/tailers/kubernetesevents"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/leaderelection"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// allNamespaces is the key of the informer watching the events of the whole cluster
+const allNamespaces = ""
+
+// Launcher is in charge of starting and stopping the Kubernetes events tailers.
+// The tailers share the events informers: the sources including namespaces
+// only watch the events of these namespaces, the other ones share an informer
+// watching the events of the whole cluster. When the leader election is
+// enabled, only the leader forwards the events.
+type Launcher struct {
+	addedSources      chan *sources.LogSource
+	removedSources    chan *sources.LogSource
+	pipelineProvider  pipeline.Provider
+	apiClient         *apiserver.APIClient
+	isLeader          func() bool
+	informerFactories map[string]informers.SharedInformerFactory
+	tailers           map[*sources.LogSource]*tailer.Tailer
+	stop              chan struct{}
+	informerStop      chan struct{}
+}
+
+// NewLauncher returns a new Launcher.
+func NewLauncher() *Launcher {
+	return &Launcher{
+		informerFactories: make(map[string]informers.SharedInformerFactory),
+		tailers:           make(map[*sources.LogSource]*tailer.Tailer),
+		stop:              make(chan struct{}),
+		informerStop:      make(chan struct{}),
+	}
+}
+
+// Start starts the launcher.
+func (l *Launcher) Start(sourceProvider launchers.SourceProvider, pipelineProvider pipeline.Provider, _ auditor.Registry, _ *tailers.TailerTracker) {
+	l.pipelineProvider = pipelineProvider
+	l.addedSources, l.removedSources = sourceProvider.SubscribeForType(config.KubernetesEventsType)
+	go l.run()
+}
+
+// run starts and stops the tailers.
+func (l *Launcher) run() {
+	for {
+		select {
+		case source := <-l.addedSources:
+			l.startTailer(source)
+		case source := <-l.removedSources:
+			if t, exists := l.tailers[source]; exists {
+				t.Stop()
+				delete(l.tailers, source)
+			}
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+// Stop stops all the tailers and the events informer.
+func (l *Launcher) Stop() {
+	l.stop <- struct{}{}
+	for source, t := range l.tailers {
+		t.Stop()
+		delete(l.tailers, source)
+	}
+	close(l.informerStop)
+	for _, factory := range l.informerFactories {
+		factory.Shutdown()
+	}
+}
+
+// startTailer starts a tailer for source, and the events informers if needed.
+func (l *Launcher) startTailer(source *sources.LogSource) {
+	if _, exists := l.tailers[source]; exists {
+		return
+	}
+
+	if err := l.init(); err != nil {
+		log.Warnf("Could not connect to the Kubernetes API server to collect the events as logs: %v", err)
+		source.Status.Error(err)
+		return
+	}
+
+	var eventInformers []cache.SharedIndexInformer
+	for _, namespace := range watchedNamespaces(source) {
+		eventInformers = append(eventInformers, l.informerFor(namespace))
+	}
+
+	t, err := tailer.NewTailer(source, eventInformers, l.isLeader, l.pipelineProvider.NextPipelineChan())
+	if err == nil {
+		err = t.Start()
+	}
+	if err != nil {
+		log.Warnf("Could not start the Kubernetes events tailer: %v", err)
+		source.Status.Error(err)
+		return
+	}
+	l.tailers[source] = t
+	// starting the factories again only starts the informers that aren't running yet
+	for _, factory := range l.informerFactories {
+		factory.Start(l.informerStop)
+	}
+	source.Status.Success()
+}
+
+// init connects to the API server and to the leader election engine, on the
+// first tailer started.
+func (l *Launcher) init() error {
+	if l.apiClient != nil {
+		return nil
+	}
+
+	apiClient, err := apiserver.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	isLeader := func() bool { return true }
+	if pkgconfigsetup.Datadog().GetBool("leader_election") {
+		le, err := leaderelection.GetLeaderEngine()
+		if err != nil {
+			return err
+		}
+		if err := le.EnsureLeaderElectionRuns(); err != nil {
+			return err
+		}
+		isLeader = le.IsLeader
+	}
+
+	l.apiClient = apiClient
+	l.isLeader = isLeader
+	return nil
+}
+
+// informerFor returns the events informer of the namespace, allNamespaces for
+// the events of the whole cluster.
+func (l *Launcher) informerFor(namespace string) cache.SharedIndexInformer {
+	factory, exists := l.informerFactories[namespace]
+	if !exists {
+		var options []informers.SharedInformerOption
+		if namespace != allNamespaces {
+			options = append(options, informers.WithNamespace(namespace))
+		}
+		factory = l.apiClient.GetInformerWithOptions(nil, options...)
+		l.informerFactories[namespace] = factory
+	}
+	return factory.Core().V1().Events().Informer()
+}
+
+// watchedNamespaces returns the namespaces whose events are watched for the
+// source: its included namespaces that aren't excluded, or the whole cluster.
+func watchedNamespaces(source *sources.LogSource) []string {
+	if len(source.Config.IncludeNamespaces) == 0 {
+		return []string{allNamespaces}
+	}
+	var namespaces []string
+	for _, namespace := range source.Config.IncludeNamespaces {
+		if !slices.Contains(source.Config.ExcludeNamespaces, namespace) && !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
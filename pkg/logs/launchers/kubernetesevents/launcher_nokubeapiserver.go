@@ -0,0 +1,31 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build !kubeapiserver
+
+// Package kubernetesevents launches tailers converting the Kubernetes events into logs
+package kubernetesevents
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/logs/auditor"
+	"github.com/DataDog/datadog-agent/pkg/logs/launchers"
+	"github.com/DataDog/datadog-agent/pkg/logs/pipeline"
+	"github.com/DataDog/datadog-agent/pkg/logs/tailers"
+)
+
+// Launcher is not supported without the kubeapiserver build tag.
+type Launcher struct{}
+
+// NewLauncher returns a new Launcher
+func NewLauncher() *Launcher {
+	return &Launcher{}
+}
+
+// Start does nothing
+func (l *Launcher) Start(_ launchers.SourceProvider, _ pipeline.Provider, _ auditor.Registry, _ *tailers.TailerTracker) {
+}
+
+// Stop does nothing
+func (l *Launcher) Stop() {}
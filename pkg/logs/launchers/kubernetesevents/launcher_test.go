@@ -0,0 +1,40 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package kubernetesevents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+)
+
+func TestWatchedNamespaces(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		include  []string
+		exclude  []string
+		expected []string
+	}{
+		{name: "whole cluster", expected: []string{allNamespaces}},
+		{name: "excluded only", exclude: []string{"kube-system"}, expected: []string{allNamespaces}},
+		{name: "included", include: []string{"prod", "staging", "prod"}, expected: []string{"prod", "staging"}},
+		{name: "included and excluded", include: []string{"prod", "staging"}, exclude: []string{"staging"}, expected: []string{"prod"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			source := sources.NewLogSource("", &config.LogsConfig{
+				Type:              config.KubernetesEventsType,
+				IncludeNamespaces: tc.include,
+				ExcludeNamespaces: tc.exclude,
+			})
+			assert.Equal(t, tc.expected, watchedNamespaces(source))
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package listener
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/pipeline"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+	"github.com/DataDog/datadog-agent/pkg/logs/tailers/syslog"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/startstop"
+)
+
+// A SyslogListener receives syslog messages over UDP, TCP or TLS, and
+// delegates their parsing to a tailer per connection.
+type SyslogListener struct {
+	pipelineProvider pipeline.Provider
+	source           *sources.LogSource
+	idleTimeout      time.Duration
+	frameSize        int
+	listener         net.Listener
+	tailers          []*syslog.Tailer
+	mu               sync.Mutex
+	stopped          bool
+}
+
+// NewSyslogListener returns an initialized SyslogListener
+func NewSyslogListener(pipelineProvider pipeline.Provider, source *sources.LogSource, frameSize int) *SyslogListener {
+	var idleTimeout time.Duration
+	if source.Config.IdleTimeout != "" {
+		var err error
+		idleTimeout, err = time.ParseDuration(source.Config.IdleTimeout)
+		if err != nil {
+			log.Errorf("Error parsing log's idle_timeout as a duration: %s", err)
+			idleTimeout = 0
+		}
+	}
+
+	return &SyslogListener{
+		pipelineProvider: pipelineProvider,
+		source:           source,
+		idleTimeout:      idleTimeout,
+		frameSize:        frameSize,
+	}
+}
+
+// Start starts receiving syslog messages.
+func (l *SyslogListener) Start() {
+	log.Infof("Starting syslog %s forwarder on port %d, with max message size: %d", l.protocol(), l.source.Config.Port, l.frameSize)
+	var err error
+	if l.source.Config.Protocol == config.UDPType {
+		err = l.startPacketTailer()
+	} else {
+		err = l.startListener()
+	}
+	if err != nil {
+		log.Errorf("Can't start syslog %s forwarder on port %d: %v", l.protocol(), l.source.Config.Port, err)
+		l.source.Status.Error(err)
+		return
+	}
+	l.source.Status.Success()
+}
+
+// Stop stops accepting new connections and all the active tailers.
+func (l *SyslogListener) Stop() {
+	log.Infof("Stopping syslog %s forwarder on port %d", l.protocol(), l.source.Config.Port)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stopped = true
+	if l.listener != nil {
+		l.listener.Close()
+	}
+	stopper := startstop.NewParallelStopper()
+	for _, tailer := range l.tailers {
+		stopper.Add(tailer)
+	}
+	stopper.Stop()
+	l.tailers = nil
+}
+
+// protocol returns the protocol of the listener, for logging purposes.
+func (l *SyslogListener) protocol() string {
+	if l.source.Config.TLSCertFile != "" {
+		return "tls"
+	}
+	return l.source.Config.Protocol
+}
+
+// startPacketTailer starts a single tailer reading the datagrams sent to the port.
+func (l *SyslogListener) startPacketTailer() error {
+	udpAddr, err := net.ResolveUDPAddr("udp", fmt.Sprintf(":%d", l.source.Config.Port))
+	if err != nil {
+		return err
+	}
+	conn, err := net.ListenUDP("udp", udpAddr)
+	if err != nil {
+		return err
+	}
+	l.addTailer(syslog.NewPacketTailer(l.source, conn, l.pipelineProvider.NextPipelineChan(), l.frameSize))
+	return nil
+}
+
+// startListener starts listening for TCP connections, using TLS when a
+// certificate is configured.
+func (l *SyslogListener) startListener() error {
+	address := fmt.Sprintf(":%d", l.source.Config.Port)
+	var listener net.Listener
+	if l.source.Config.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(l.source.Config.TLSCertFile, l.source.Config.TLSKeyFile)
+		if err != nil {
+			return fmt.Errorf("can't load the TLS certificate: %v", err)
+		}
+		listener, err = tls.Listen("tcp", address, &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+		listener, err = net.Listen("tcp", address)
+		if err != nil {
+			return err
+		}
+	}
+	l.listener = listener
+	go l.run()
+	return nil
+}
+
+// run accepts new connections and creates a dedicated tailer for each.
+func (l *SyslogListener) run() {
+	for {
+		conn, err := l.listener.Accept()
+		switch {
+		case err != nil && isClosedConnError(err):
+			return
+		case err != nil:
+			log.Warnf("Can't accept syslog connection on port %d: %v", l.source.Config.Port, err)
+			l.source.Status.Error(err)
+			return
+		}
+		l.addTailer(syslog.NewStreamTailer(l.source, conn, l.pipelineProvider.NextPipelineChan(), l.idleTimeout, l.frameSize))
+		l.source.Status.Success()
+	}
+}
+
+// addTailer starts a tailer, and removes it from the active tailers once its
+// connection is closed.
+func (l *SyslogListener) addTailer(tailer *syslog.Tailer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	tailer.Start()
+	if l.stopped {
+		// the connection was accepted while the listener was stopping
+		tailer.Stop()
+		return
+	}
+	l.tailers = append(l.tailers, tailer)
+
+	go func() {
+		<-tailer.Done()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for i, t := range l.tailers {
+			if t == tailer {
+				l.tailers = append(l.tailers[:i], l.tailers[i+1:]...)
+				break
+			}
+		}
+	}()
+}
@@ -0,0 +1,46 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package listener
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/pipeline/mock"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+)
+
+func TestSyslogTCPShouldReceivesMessages(t *testing.T) {
+	pp := mock.NewMockProvider()
+	msgChan := pp.NextPipelineChan()
+	listener := NewSyslogListener(pp, sources.NewLogSource("", &config.LogsConfig{Type: config.SyslogType, Protocol: config.TCPType}), 9000)
+	listener.Start()
+	conn, err := net.Dial("tcp", listener.listener.Addr().String())
+	assert.Nil(t, err)
+	var msg *message.Message
+
+	fmt.Fprint(conn, "<14>1 2025-01-02T03:04:05Z host app - - - hello world\n")
+	msg = <-msgChan
+	assert.Equal(t, "hello world", string(msg.GetContent()))
+	assert.Equal(t, "app", msg.Origin.Service())
+	assert.Equal(t, 1, len(listener.tailers))
+
+	// the tailer is removed once the connection is closed
+	conn.Close()
+	assert.Eventually(t, func() bool {
+		listener.mu.Lock()
+		defer listener.mu.Unlock()
+		return len(listener.tailers) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	listener.Stop()
+}
@@ -170,6 +170,23 @@ func (m *MessageContent) SetContent(content []byte) {
 	}
 }
 
+// GetStructuredContent returns the structured content of a message in
+// StateStructured, nil otherwise.
+func (m *MessageContent) GetStructuredContent() StructuredContent {
+	if m.State != StateStructured {
+		return nil
+	}
+	return m.structuredContent
+}
+
+// SetStructuredContent sets the structured content for the MessageContent and
+// sets MessageContent state to structured.
+func (m *MessageContent) SetStructuredContent(content StructuredContent) {
+	m.content = nil
+	m.structuredContent = content
+	m.State = StateStructured
+}
+
 // SetRendered sets the content for the MessageContent and sets MessageContent state to rendered.
 func (m *MessageContent) SetRendered(content []byte) {
 	m.content = content
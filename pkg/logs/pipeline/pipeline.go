@@ -8,6 +8,7 @@ package pipeline
 
 import (
 	"context"
+	"path/filepath"
 	"strconv"
 	"sync"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/logs/sender"
 	"github.com/DataDog/datadog-agent/pkg/logs/status/statusinterface"
 	compressioncommon "github.com/DataDog/datadog-agent/pkg/util/compression"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
 // Pipeline processes and sends messages to the backend
@@ -34,6 +36,7 @@ type Pipeline struct {
 	flushChan       chan struct{}
 	processor       *processor.Processor
 	strategy        sender.Strategy
+	diskSpool       *sender.DiskSpool
 	sender          *sender.Sender
 	serverless      bool
 	flushWg         *sync.WaitGroup
@@ -81,7 +84,20 @@ func NewPipeline(outputChan chan *message.Payload,
 		encoder = processor.RawEncoder
 	}
 
-	strategy := getStrategy(strategyInput, senderInput, flushChan, endpoints, serverless, flushWg, pipelineMonitor, compression)
+	strategyOutput := senderInput
+	var diskSpool *sender.DiskSpool
+	if !serverless && cfg.GetBool("logs_config.disk_buffer.enabled") {
+		spoolInput := make(chan *message.Payload, 1)
+		var err error
+		diskSpool, err = newDiskSpool(spoolInput, senderInput, outputChan, pipelineID, cfg, pipelineMonitor)
+		if err != nil {
+			log.Errorf("Couldn't open the logs disk buffer, the payloads are only buffered in memory: %v", err)
+		} else {
+			strategyOutput = spoolInput
+		}
+	}
+
+	strategy := getStrategy(strategyInput, strategyOutput, flushChan, endpoints, serverless, flushWg, pipelineMonitor, compression)
 	logsSender = sender.NewSender(cfg, senderInput, outputChan, mainDestinations, pkgconfigsetup.Datadog().GetInt("logs_config.payload_channel_size"), senderDoneChan, flushWg, pipelineMonitor)
 
 	inputChan := make(chan *message.Message, pkgconfigsetup.Datadog().GetInt("logs_config.message_channel_size"))
@@ -94,6 +110,7 @@ func NewPipeline(outputChan chan *message.Payload,
 		flushChan:       flushChan,
 		processor:       processor,
 		strategy:        strategy,
+		diskSpool:       diskSpool,
 		sender:          logsSender,
 		serverless:      serverless,
 		flushWg:         flushWg,
@@ -104,6 +121,9 @@ func NewPipeline(outputChan chan *message.Payload,
 // Start launches the pipeline
 func (p *Pipeline) Start() {
 	p.sender.Start()
+	if p.diskSpool != nil {
+		p.diskSpool.Start()
+	}
 	p.strategy.Start()
 	p.processor.Start()
 }
@@ -112,6 +132,9 @@ func (p *Pipeline) Start() {
 func (p *Pipeline) Stop() {
 	p.processor.Stop()
 	p.strategy.Stop()
+	if p.diskSpool != nil {
+		p.diskSpool.Stop()
+	}
 	p.sender.Stop()
 }
 
@@ -126,6 +149,17 @@ func (p *Pipeline) Flush(ctx context.Context) {
 	}
 }
 
+// newDiskSpool returns the disk spool of a pipeline, which gets its own
+// directory and an equal share of the disk buffer max size.
+func newDiskSpool(inputChan chan *message.Payload, outputChan chan *message.Payload, auditorChan chan *message.Payload, pipelineID int, cfg pkgconfigmodel.Reader, pipelineMonitor metrics.PipelineMonitor) (*sender.DiskSpool, error) {
+	dir := cfg.GetString("logs_config.disk_buffer.path")
+	if dir == "" {
+		dir = filepath.Join(cfg.GetString("logs_config.run_path"), "disk_buffer")
+	}
+	maxSize := cfg.GetInt64("logs_config.disk_buffer.max_size") / int64(max(1, cfg.GetInt("logs_config.pipelines")))
+	return sender.NewDiskSpool(inputChan, outputChan, auditorChan, filepath.Join(dir, strconv.Itoa(pipelineID)), maxSize, pipelineMonitor)
+}
+
 func getDestinations(endpoints *config.Endpoints, destinationsContext *client.DestinationsContext, pipelineMonitor metrics.PipelineMonitor, serverless bool, senderDoneChan chan *sync.WaitGroup, status statusinterface.Status, cfg pkgconfigmodel.Reader) *client.Destinations {
 	reliable := []client.Destination{}
 	additionals := []client.Destination{}
@@ -0,0 +1,104 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package processor
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// applyParsingRules extracts attributes from the content of the message with
+// the first parsing rule matching it, the global rules being tried before the
+// rules of the source. The parsing happens once the content is redacted, so
+// that the attributes never hold masked data.
+func (p *Processor) applyParsingRules(msg *message.Message) {
+	for _, rules := range [][]*config.ProcessingRule{p.processingRules, msg.Origin.LogSource.Config.ProcessingRules} {
+		for _, rule := range rules {
+			if !config.IsParsingRule(rule) {
+				continue
+			}
+			if attributes := parseContent(rule, msg.GetContent()); attributes != nil {
+				setParsedAttributes(msg, attributes)
+				return
+			}
+		}
+	}
+}
+
+// parseContent returns the attributes extracted by a parsing rule, or nil if
+// the rule doesn't match the content.
+func parseContent(rule *config.ProcessingRule, content []byte) map[string]interface{} {
+	match := rule.Regex.FindSubmatch(content)
+	if match == nil {
+		return nil
+	}
+	attributes := make(map[string]interface{})
+	for i, field := range rule.Fields {
+		// the optional parts of the pattern that didn't match are left out
+		if field.Name == "" || match[i] == nil {
+			continue
+		}
+		setAttribute(attributes, field.Name, parsedValue(field, string(match[i])))
+	}
+	return attributes
+}
+
+// parsedValue converts an extracted value to the type of its field, it's kept
+// as a string when it can't be converted.
+func parsedValue(field config.ParsedField, value string) interface{} {
+	switch field.Type {
+	case config.ParsedFieldInt:
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	case config.ParsedFieldFloat:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return value
+}
+
+// setAttribute sets a value in the attributes, the dots of its name
+// separating the nested attributes.
+func setAttribute(attributes map[string]interface{}, name string, value interface{}) {
+	parts := strings.Split(name, ".")
+	for _, part := range parts[:len(parts)-1] {
+		nested, ok := attributes[part].(map[string]interface{})
+		if !ok {
+			nested = make(map[string]interface{})
+			attributes[part] = nested
+		}
+		attributes = nested
+	}
+	attributes[parts[len(parts)-1]] = value
+}
+
+// setParsedAttributes adds the attributes to the message, turning an
+// unstructured message into a structured one whose content stays in the
+// "message" attribute unless the rule extracted it.
+func setParsedAttributes(msg *message.Message, attributes map[string]interface{}) {
+	switch msg.State {
+	case message.StateUnstructured:
+		if _, ok := attributes["message"]; !ok {
+			attributes["message"] = string(msg.GetContent())
+		}
+		msg.SetStructuredContent(&message.BasicStructuredContent{Data: attributes})
+	case message.StateStructured:
+		content, ok := msg.GetStructuredContent().(*message.BasicStructuredContent)
+		if !ok {
+			log.Debug("Can't add the parsed attributes to a structured log of this source")
+			return
+		}
+		for name, value := range attributes {
+			content.Data[name] = value
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package processor
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+)
+
+func newParsingSource(t *testing.T, rules ...*config.ProcessingRule) sources.LogSource {
+	require.NoError(t, config.ValidateProcessingRules(rules))
+	require.NoError(t, config.CompileProcessingRules(rules))
+	return sources.LogSource{Config: &config.LogsConfig{ProcessingRules: rules}}
+}
+
+func renderAttributes(t *testing.T, msg *message.Message) map[string]interface{} {
+	rendered, err := msg.Render()
+	require.NoError(t, err)
+	var attributes map[string]interface{}
+	require.NoError(t, json.Unmarshal(rendered, &attributes))
+	return attributes
+}
+
+func TestParsingUnstructuredMessage(t *testing.T) {
+	source := newParsingSource(t, &config.ProcessingRule{
+		Type:    config.GrokParser,
+		Name:    "access",
+		Pattern: `%{IP:network.client.ip} %{WORD:http.method} %{NOTSPACE:http.url} %{INT:http.status_code:int} %{NUMBER:duration:float}`,
+	})
+	p := &Processor{}
+
+	msg := newMessage([]byte("10.0.0.1 GET /index.html 200 0.5"), &source, "")
+	p.applyParsingRules(msg)
+
+	assert.Equal(t, message.StateStructured, msg.State)
+	assert.Equal(t, "10.0.0.1 GET /index.html 200 0.5", string(msg.GetContent()))
+	assert.Equal(t, map[string]interface{}{
+		"message": "10.0.0.1 GET /index.html 200 0.5",
+		"network": map[string]interface{}{"client": map[string]interface{}{"ip": "10.0.0.1"}},
+		"http": map[string]interface{}{
+			"method":      "GET",
+			"url":         "/index.html",
+			"status_code": float64(200),
+		},
+		"duration": 0.5,
+	}, renderAttributes(t, msg))
+}
+
+func TestParsingStructuredMessage(t *testing.T) {
+	source := newParsingSource(t, &config.ProcessingRule{
+		Type:    config.DissectParser,
+		Name:    "app",
+		Pattern: `%{level} %{message}`,
+	})
+	p := &Processor{}
+
+	msg := newStructuredMessage([]byte("ERROR connection refused"), &source, "")
+	msg.GetStructuredContent().(*message.BasicStructuredContent).Data["host"] = "web-1"
+	p.applyParsingRules(msg)
+
+	assert.Equal(t, "connection refused", string(msg.GetContent()))
+	assert.Equal(t, map[string]interface{}{
+		"message": "connection refused",
+		"level":   "ERROR",
+		"host":    "web-1",
+	}, renderAttributes(t, msg))
+}
+
+func TestParsingFirstMatchingRule(t *testing.T) {
+	source := newParsingSource(t,
+		&config.ProcessingRule{Type: config.GrokParser, Name: "numbers", Pattern: `%{INT:count:int}`},
+		&config.ProcessingRule{Type: config.GrokParser, Name: "words", Pattern: `%{WORD:first} %{GREEDYDATA}`},
+		&config.ProcessingRule{Type: config.GrokParser, Name: "anything", Pattern: `%{GREEDYDATA:anything}`},
+	)
+	p := &Processor{}
+
+	msg := newMessage([]byte("hello world"), &source, "")
+	p.applyParsingRules(msg)
+	assert.Equal(t, map[string]interface{}{"message": "hello world", "first": "hello"}, renderAttributes(t, msg))
+}
+
+func TestParsingWithoutMatch(t *testing.T) {
+	source := newParsingSource(t, &config.ProcessingRule{Type: config.GrokParser, Name: "numbers", Pattern: `%{INT:count:int}`})
+	p := &Processor{}
+
+	msg := newMessage([]byte("hello world"), &source, "")
+	p.applyParsingRules(msg)
+	assert.Equal(t, message.StateUnstructured, msg.State)
+	assert.Equal(t, "hello world", string(msg.GetContent()))
+}
+
+func TestParsingAfterMasking(t *testing.T) {
+	source := newParsingSource(t,
+		&config.ProcessingRule{Type: config.GrokParser, Name: "login", Pattern: `user %{NOTSPACE:user} logged in with %{NOTSPACE:password}`},
+		&config.ProcessingRule{Type: config.MaskSequences, Name: "password", Pattern: `password=\S+`, ReplacePlaceholder: "password=[masked]"},
+	)
+	p := &Processor{}
+
+	msg := newMessage([]byte("user alice logged in with password=hunter2"), &source, "")
+	require.True(t, p.applyRedactingRules(msg))
+	p.applyParsingRules(msg)
+	assert.Equal(t, "password=[masked]", renderAttributes(t, msg)["password"])
+}
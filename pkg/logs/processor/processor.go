@@ -233,6 +233,8 @@ func (p *Processor) processMessage(msg *message.Message) {
 		metrics.LogsProcessed.Add(1)
 		metrics.TlmLogsProcessed.Inc()
 
+		p.applyParsingRules(msg)
+
 		// render the message
 		rendered, err := msg.Render()
 		if err != nil {
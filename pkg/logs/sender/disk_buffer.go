@@ -0,0 +1,330 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package sender
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// segmentExtension is the extension of the segment files of a disk buffer.
+	segmentExtension = ".seg"
+	// defaultSegmentSize is the size after which a new segment is started.
+	defaultSegmentSize = 16 * 1024 * 1024
+	// recordHeaderSize is the size of the header of a record: the length of
+	// its body followed by its checksum.
+	recordHeaderSize = 8
+)
+
+var (
+	errDiskBufferFull   = errors.New("disk buffer is full")
+	errDiskBufferClosed = errors.New("disk buffer is closed")
+
+	crcTable = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// diskBuffer is a FIFO queue of payloads stored in a directory, in segment
+// files holding a sequence of checksummed records. The segments are only read
+// once they are sealed, and deleted once all their records are read. A
+// corrupted or truncated record, typically left by a crash, ends the reading
+// of its segment.
+type diskBuffer struct {
+	dir         string
+	maxSize     int64
+	segmentSize int64
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	// segments are the sealed segments, from the oldest to the newest.
+	segments []segment
+	// size is the size of all the segments, including the one being written.
+	size   int64
+	closed bool
+
+	writer     *os.File
+	writerID   uint64
+	writerSize int64
+
+	reader     *bufio.Reader
+	readerFile *os.File
+	// next is the payload read from the oldest segment but not committed yet.
+	next *message.Payload
+}
+
+type segment struct {
+	id   uint64
+	size int64
+}
+
+// newDiskBuffer opens the disk buffer stored in dir, creating it if needed.
+// The segments left by a previous run are read first.
+func newDiskBuffer(dir string, maxSize int64, segmentSize int64) (*diskBuffer, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &diskBuffer{
+		dir:         dir,
+		maxSize:     maxSize,
+		segmentSize: segmentSize,
+	}
+	b.cond = sync.NewCond(&b.mu)
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExtension) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExtension), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		b.segments = append(b.segments, segment{id: id, size: info.Size()})
+		b.size += info.Size()
+	}
+	slices.SortFunc(b.segments, func(a, b segment) int { return cmp.Compare(a.id, b.id) })
+	if len(b.segments) > 0 {
+		b.writerID = b.segments[len(b.segments)-1].id + 1
+	}
+	return b, nil
+}
+
+// put appends the payload to the buffer, or returns errDiskBufferFull if it
+// would exceed the max size of the buffer.
+func (b *diskBuffer) put(payload *message.Payload) error {
+	record := encodeRecord(payload)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return errDiskBufferClosed
+	}
+	if b.size+int64(len(record)) > b.maxSize {
+		return errDiskBufferFull
+	}
+
+	if b.writer == nil {
+		writer, err := os.OpenFile(b.segmentPath(b.writerID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return err
+		}
+		b.writer = writer
+		b.writerSize = 0
+	}
+	n, err := b.writer.Write(record)
+	b.writerSize += int64(n)
+	b.size += int64(n)
+	if err != nil {
+		// the partial record is skipped by the reader
+		b.sealWriter()
+		return err
+	}
+	if b.writerSize >= b.segmentSize {
+		b.sealWriter()
+	}
+	b.cond.Signal()
+	return nil
+}
+
+// peek returns the oldest payload of the buffer, without removing it. It
+// blocks until a payload is available, or returns errDiskBufferClosed once the
+// buffer is closed.
+func (b *diskBuffer) peek() (*message.Payload, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for {
+		if b.closed {
+			return nil, errDiskBufferClosed
+		}
+		if b.next != nil {
+			return b.next, nil
+		}
+
+		if b.reader == nil && len(b.segments) == 0 && b.writer != nil {
+			// the segment being written is sealed early to be read
+			b.sealWriter()
+		}
+		if b.reader == nil && len(b.segments) > 0 {
+			if err := b.openReader(); err != nil {
+				log.Warnf("Couldn't read the logs disk buffer segment %d, skipping it: %v", b.segments[0].id, err)
+				b.removeOldestSegment()
+				continue
+			}
+		}
+		if b.reader == nil {
+			b.cond.Wait()
+			continue
+		}
+
+		payload, err := decodeRecord(b.reader)
+		if err != nil {
+			if err != io.EOF {
+				tlmDiskBufferCorrupted.Inc()
+				log.Warnf("Found a corrupted record in the logs disk buffer segment %d, skipping the rest of the segment: %v", b.segments[0].id, err)
+			}
+			b.removeOldestSegment()
+			continue
+		}
+		b.next = payload
+	}
+}
+
+// commit removes the payload returned by peek from the buffer. Its space is
+// released once the rest of its segment is read.
+func (b *diskBuffer) commit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.next = nil
+}
+
+// isEmpty returns whether the buffer holds no payload.
+func (b *diskBuffer) isEmpty() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.next == nil && b.reader == nil && len(b.segments) == 0 && b.writer == nil
+}
+
+// close closes the buffer, the payloads it still holds are read on the next
+// run.
+func (b *diskBuffer) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	if b.writer != nil {
+		b.sealWriter()
+	}
+	if b.readerFile != nil {
+		b.readerFile.Close()
+		b.readerFile = nil
+		b.reader = nil
+	}
+	b.cond.Broadcast()
+}
+
+// sealWriter closes the segment being written, so that it can be read.
+func (b *diskBuffer) sealWriter() {
+	if err := b.writer.Close(); err != nil {
+		log.Warnf("Couldn't close the logs disk buffer segment %d: %v", b.writerID, err)
+	}
+	b.writer = nil
+	b.segments = append(b.segments, segment{id: b.writerID, size: b.writerSize})
+	b.writerID++
+	b.writerSize = 0
+}
+
+// openReader opens the oldest segment.
+func (b *diskBuffer) openReader() error {
+	file, err := os.Open(b.segmentPath(b.segments[0].id))
+	if err != nil {
+		return err
+	}
+	b.readerFile = file
+	b.reader = bufio.NewReader(file)
+	return nil
+}
+
+// removeOldestSegment deletes the oldest segment, once it has been read or
+// when it can't be read.
+func (b *diskBuffer) removeOldestSegment() {
+	oldest := b.segments[0]
+	if b.readerFile != nil {
+		b.readerFile.Close()
+		b.readerFile = nil
+		b.reader = nil
+	}
+	if err := os.Remove(b.segmentPath(oldest.id)); err != nil && !os.IsNotExist(err) {
+		log.Warnf("Couldn't remove the logs disk buffer segment %d: %v", oldest.id, err)
+	}
+	b.segments = b.segments[1:]
+	b.size -= oldest.size
+}
+
+func (b *diskBuffer) segmentPath(id uint64) string {
+	return filepath.Join(b.dir, fmt.Sprintf("%020d%s", id, segmentExtension))
+}
+
+// encodeRecord returns the record of a payload: the length and the CRC32-C of
+// its body, followed by its body holding the encoding, the unencoded size and
+// the encoded bytes of the payload.
+func encodeRecord(payload *message.Payload) []byte {
+	bodySize := 2 + len(payload.Encoding) + 4 + len(payload.Encoded)
+	record := make([]byte, recordHeaderSize, recordHeaderSize+bodySize)
+
+	record = binary.LittleEndian.AppendUint16(record, uint16(len(payload.Encoding)))
+	record = append(record, payload.Encoding...)
+	record = binary.LittleEndian.AppendUint32(record, uint32(payload.UnencodedSize))
+	record = append(record, payload.Encoded...)
+
+	body := record[recordHeaderSize:]
+	binary.LittleEndian.PutUint32(record[0:4], uint32(len(body)))
+	binary.LittleEndian.PutUint32(record[4:8], crc32.Checksum(body, crcTable))
+	return record
+}
+
+// decodeRecord reads the next record and returns its payload. It returns
+// io.EOF at the end of a segment, and an error if the record is truncated or
+// corrupted.
+func decodeRecord(r *bufio.Reader) (*message.Payload, error) {
+	var header [recordHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("truncated record header")
+		}
+		return nil, err
+	}
+	bodySize := binary.LittleEndian.Uint32(header[0:4])
+	if bodySize < 6 || bodySize > maxRecordBodySize {
+		return nil, fmt.Errorf("invalid record size %d", bodySize)
+	}
+	body := make([]byte, bodySize)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("truncated record: %v", err)
+	}
+	if crc32.Checksum(body, crcTable) != binary.LittleEndian.Uint32(header[4:8]) {
+		return nil, errors.New("invalid record checksum")
+	}
+
+	encodingSize := int(binary.LittleEndian.Uint16(body[0:2]))
+	if 2+encodingSize+4 > len(body) {
+		return nil, errors.New("invalid record encoding size")
+	}
+	payload := &message.Payload{
+		Encoding:      string(body[2 : 2+encodingSize]),
+		UnencodedSize: int(binary.LittleEndian.Uint32(body[2+encodingSize : 2+encodingSize+4])),
+		Encoded:       body[2+encodingSize+4:],
+	}
+	return payload, nil
+}
+
+// maxRecordBodySize is the maximum size of the body of a record, above which
+// its length is considered corrupted.
+const maxRecordBodySize = 256 * 1024 * 1024
@@ -0,0 +1,170 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package sender
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+)
+
+func newPayload(content string) *message.Payload {
+	return &message.Payload{
+		Encoded:       []byte(content),
+		Encoding:      "gzip",
+		UnencodedSize: len(content) * 2,
+	}
+}
+
+func TestDiskBufferRoundTrip(t *testing.T) {
+	b, err := newDiskBuffer(t.TempDir(), 1024*1024, 64)
+	require.NoError(t, err)
+
+	for _, content := range []string{"first", "second", "third"} {
+		require.NoError(t, b.put(newPayload(content)))
+	}
+
+	payload, err := b.peek()
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(payload.Encoded))
+	assert.Equal(t, "gzip", payload.Encoding)
+	assert.Equal(t, 10, payload.UnencodedSize)
+
+	// the payload stays in the buffer until it's committed
+	payload, err = b.peek()
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(payload.Encoded))
+	b.commit()
+
+	for _, expected := range []string{"second", "third"} {
+		payload, err = b.peek()
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(payload.Encoded))
+		b.commit()
+	}
+	b.close()
+}
+
+func TestDiskBufferMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	record := len(encodeRecord(newPayload("0123456789")))
+	b, err := newDiskBuffer(dir, int64(2*record), 1024)
+	require.NoError(t, err)
+
+	require.NoError(t, b.put(newPayload("0123456789")))
+	require.NoError(t, b.put(newPayload("0123456789")))
+	assert.Equal(t, errDiskBufferFull, b.put(newPayload("0123456789")))
+
+	// the space is released once the segment is read entirely
+	for i := 0; i < 2; i++ {
+		_, err := b.peek()
+		require.NoError(t, err)
+		b.commit()
+	}
+	done := make(chan *message.Payload)
+	go func() {
+		// peek releases the segment and waits for the next payload
+		payload, err := b.peek()
+		assert.NoError(t, err)
+		done <- payload
+	}()
+	require.Eventually(t, func() bool {
+		return b.put(newPayload("new")) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	payload := <-done
+	assert.Equal(t, "new", string(payload.Encoded))
+	b.close()
+}
+
+func TestDiskBufferPersistence(t *testing.T) {
+	dir := t.TempDir()
+	b, err := newDiskBuffer(dir, 1024*1024, 64)
+	require.NoError(t, err)
+	for _, content := range []string{"first", "second", "third"} {
+		require.NoError(t, b.put(newPayload(content)))
+	}
+	_, err = b.peek()
+	require.NoError(t, err)
+	b.commit()
+	b.close()
+
+	// the segments are deleted once read entirely, so the payloads of the
+	// segment being read are sent again
+	b, err = newDiskBuffer(dir, 1024*1024, 64)
+	require.NoError(t, err)
+	for _, expected := range []string{"first", "second", "third"} {
+		payload, err := b.peek()
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(payload.Encoded))
+		b.commit()
+	}
+	require.NoError(t, b.put(newPayload("fourth")))
+	payload, err := b.peek()
+	require.NoError(t, err)
+	assert.Equal(t, "fourth", string(payload.Encoded))
+	b.close()
+}
+
+func TestDiskBufferCorruptedSegment(t *testing.T) {
+	dir := t.TempDir()
+	b, err := newDiskBuffer(dir, 1024*1024, 1024*1024)
+	require.NoError(t, err)
+	require.NoError(t, b.put(newPayload("first")))
+	require.NoError(t, b.put(newPayload("second")))
+	b.close()
+
+	// a truncated record is left by a crash in the middle of a write
+	record := encodeRecord(newPayload("third"))
+	f, err := os.OpenFile(filepath.Join(dir, "00000000000000000000.seg"), os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.Write(record[:len(record)-2])
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// a flipped bit is detected by the checksum
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000001.seg"), append(encodeRecord(newPayload("lost")), encodeRecord(newPayload("lost too"))...), 0600))
+	data, err := os.ReadFile(filepath.Join(dir, "00000000000000000001.seg"))
+	require.NoError(t, err)
+	data[recordHeaderSize+3] ^= 0x01
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000001.seg"), data, 0600))
+
+	b, err = newDiskBuffer(dir, 1024*1024, 1024*1024)
+	require.NoError(t, err)
+	require.NoError(t, b.put(newPayload("fourth")))
+
+	for _, expected := range []string{"first", "second", "fourth"} {
+		payload, err := b.peek()
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(payload.Encoded))
+		b.commit()
+	}
+	b.close()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	// only the last segment, being read, is left
+	assert.Len(t, entries, 1)
+}
+
+func TestDiskBufferClose(t *testing.T) {
+	b, err := newDiskBuffer(t.TempDir(), 1024*1024, 64)
+	require.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		_, err := b.peek()
+		done <- err
+	}()
+	b.close()
+	assert.Equal(t, errDiskBufferClosed, <-done)
+	assert.Equal(t, errDiskBufferClosed, b.put(newPayload("first")))
+}
@@ -0,0 +1,126 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package sender
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/metrics"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var (
+	tlmDiskBufferSpooled   = telemetry.NewCounter("logs_disk_buffer", "payloads_spooled", nil, "Payloads written to the disk buffer")
+	tlmDiskBufferUnspooled = telemetry.NewCounter("logs_disk_buffer", "payloads_unspooled", nil, "Payloads read from the disk buffer")
+	tlmDiskBufferFull      = telemetry.NewCounter("logs_disk_buffer", "full", nil, "Count of the payloads that couldn't be spooled because the disk buffer was full")
+	tlmDiskBufferCorrupted = telemetry.NewCounter("logs_disk_buffer", "corrupted_records", nil, "Count of the corrupted records found in the disk buffer")
+)
+
+// DiskSpool sits between a strategy and a sender. It forwards the payloads to
+// the sender while it keeps up, and spools them on disk while it's blocked,
+// for instance during an intake outage. The spooled payloads are sent in order
+// once the sender is available again, including after a restart of the agent.
+//
+// The messages of a spooled payload are reported to the auditor as soon as the
+// payload is on disk, as the payload is read from the disk after a restart.
+// The spooled payloads are sent at least once.
+type DiskSpool struct {
+	inputChan   chan *message.Payload
+	outputChan  chan *message.Payload
+	auditorChan chan *message.Payload
+	buffer      *diskBuffer
+	inputDone   chan struct{}
+	drainDone   chan struct{}
+
+	pipelineMonitor metrics.PipelineMonitor
+}
+
+// NewDiskSpool returns a new DiskSpool storing its payloads in dir, up to
+// maxSize bytes. The messages of the spooled payloads are sent to auditorChan.
+func NewDiskSpool(inputChan chan *message.Payload, outputChan chan *message.Payload, auditorChan chan *message.Payload, dir string, maxSize int64, pipelineMonitor metrics.PipelineMonitor) (*DiskSpool, error) {
+	buffer, err := newDiskBuffer(dir, maxSize, min(defaultSegmentSize, maxSize/4))
+	if err != nil {
+		return nil, err
+	}
+	return &DiskSpool{
+		inputChan:   inputChan,
+		outputChan:  outputChan,
+		auditorChan: auditorChan,
+		buffer:      buffer,
+		inputDone:   make(chan struct{}),
+		drainDone:   make(chan struct{}),
+
+		pipelineMonitor: pipelineMonitor,
+	}, nil
+}
+
+// Start starts forwarding the payloads.
+func (s *DiskSpool) Start() {
+	go s.forwardInput()
+	go s.drainBuffer()
+}
+
+// Stop stops the spool once its input is flushed. The payloads still on disk
+// are sent on the next start.
+func (s *DiskSpool) Stop() {
+	close(s.inputChan)
+	<-s.inputDone
+	s.buffer.close()
+	<-s.drainDone
+}
+
+// forwardInput forwards the payloads to the sender when the buffer is empty
+// and the sender is ready, and spools them otherwise so that they stay in order.
+func (s *DiskSpool) forwardInput() {
+	defer close(s.inputDone)
+	for payload := range s.inputChan {
+		if s.buffer.isEmpty() {
+			select {
+			case s.outputChan <- payload:
+				continue
+			default:
+			}
+		}
+
+		err := s.buffer.put(payload)
+		if err != nil {
+			if err == errDiskBufferFull {
+				tlmDiskBufferFull.Inc()
+			} else {
+				log.Warnf("Couldn't write a payload to the logs disk buffer: %v", err)
+			}
+			// fall back to waiting for the sender, as without a disk buffer
+			s.outputChan <- payload
+			continue
+		}
+		tlmDiskBufferSpooled.Inc()
+		// the payload read from the disk holds no message, its content leaves the sender here
+		s.pipelineMonitor.ReportComponentEgress(payload, "sender")
+		if len(payload.Messages) > 0 {
+			s.auditorChan <- &message.Payload{Messages: payload.Messages}
+		}
+	}
+}
+
+// drainBuffer sends the spooled payloads to the sender, until the buffer is
+// closed.
+func (s *DiskSpool) drainBuffer() {
+	defer close(s.drainDone)
+	for {
+		payload, err := s.buffer.peek()
+		if err != nil {
+			return
+		}
+		select {
+		case s.outputChan <- payload:
+			s.buffer.commit()
+			tlmDiskBufferUnspooled.Inc()
+		case <-s.inputDone:
+			// the payload is sent on the next start
+			return
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package sender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/metrics"
+)
+
+func TestDiskSpoolPassthrough(t *testing.T) {
+	input := make(chan *message.Payload)
+	output := make(chan *message.Payload, 1)
+	auditor := make(chan *message.Payload, 1)
+
+	spool, err := NewDiskSpool(input, output, auditor, t.TempDir(), 1024*1024, metrics.NewNoopPipelineMonitor(""))
+	require.NoError(t, err)
+	spool.Start()
+
+	// the payload is forwarded as is while the sender is ready
+	payload := newMessage([]byte("first"), nil, "")
+	input <- payload
+	assert.Same(t, payload, <-output)
+	assert.Len(t, auditor, 0)
+
+	spool.Stop()
+}
+
+func TestDiskSpoolSpoolsWhileSenderIsBlocked(t *testing.T) {
+	input := make(chan *message.Payload)
+	output := make(chan *message.Payload)
+	auditor := make(chan *message.Payload, 10)
+
+	spool, err := NewDiskSpool(input, output, auditor, t.TempDir(), 1024*1024, metrics.NewNoopPipelineMonitor(""))
+	require.NoError(t, err)
+	spool.Start()
+
+	// nothing reads the output, the payloads are spooled
+	contents := []string{"first", "second", "third"}
+	for _, content := range contents {
+		input <- newMessage([]byte(content), nil, "")
+	}
+	for _, content := range contents {
+		audited := <-auditor
+		require.Len(t, audited.Messages, 1)
+		assert.Equal(t, content, string(audited.Messages[0].GetContent()))
+	}
+
+	// the payloads are sent in order once the sender is available
+	for _, content := range contents {
+		payload := <-output
+		assert.Equal(t, content, string(payload.Encoded))
+		assert.Equal(t, "identity", payload.Encoding)
+		assert.Empty(t, payload.Messages)
+	}
+
+	spool.Stop()
+}
+
+func TestDiskSpoolKeepsPayloadsAcrossRestarts(t *testing.T) {
+	dir := t.TempDir()
+	input := make(chan *message.Payload)
+	output := make(chan *message.Payload)
+	auditor := make(chan *message.Payload, 10)
+
+	spool, err := NewDiskSpool(input, output, auditor, dir, 1024*1024, metrics.NewNoopPipelineMonitor(""))
+	require.NoError(t, err)
+	spool.Start()
+	input <- newMessage([]byte("first"), nil, "")
+	input <- newMessage([]byte("second"), nil, "")
+	<-auditor
+	<-auditor
+	spool.Stop()
+
+	input = make(chan *message.Payload)
+	spool, err = NewDiskSpool(input, output, auditor, dir, 1024*1024, metrics.NewNoopPipelineMonitor(""))
+	require.NoError(t, err)
+	spool.Start()
+
+	for _, content := range []string{"first", "second"} {
+		payload := <-output
+		assert.Equal(t, content, string(payload.Encoded))
+	}
+
+	// new payloads are sent after the spooled ones
+	input <- newMessage([]byte("third"), nil, "")
+	payload := <-output
+	assert.Equal(t, "third", string(payload.Encoded))
+
+	spool.Stop()
+}
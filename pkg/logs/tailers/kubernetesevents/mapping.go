@@ -0,0 +1,127 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package kubernetesevents
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// eventFields are the fields of an event that can be mapped to the attributes
+// of its log, keyed by their name in the field_mapping option.
+var eventFields = map[string]func(ev *v1.Event) interface{}{
+	"name":                        func(ev *v1.Event) interface{} { return ev.Name },
+	"namespace":                   func(ev *v1.Event) interface{} { return ev.Namespace },
+	"uid":                         func(ev *v1.Event) interface{} { return string(ev.UID) },
+	"type":                        func(ev *v1.Event) interface{} { return ev.Type },
+	"reason":                      func(ev *v1.Event) interface{} { return ev.Reason },
+	"action":                      func(ev *v1.Event) interface{} { return ev.Action },
+	"count":                       func(ev *v1.Event) interface{} { return ev.Count },
+	"first_timestamp":             func(ev *v1.Event) interface{} { return formatTime(ev.FirstTimestamp) },
+	"last_timestamp":              func(ev *v1.Event) interface{} { return formatTime(ev.LastTimestamp) },
+	"event_time":                  func(ev *v1.Event) interface{} { return formatMicroTime(ev.EventTime) },
+	"source.component":            func(ev *v1.Event) interface{} { return ev.Source.Component },
+	"source.host":                 func(ev *v1.Event) interface{} { return ev.Source.Host },
+	"reporting_controller":        func(ev *v1.Event) interface{} { return ev.ReportingController },
+	"reporting_instance":          func(ev *v1.Event) interface{} { return ev.ReportingInstance },
+	"involved_object.kind":        func(ev *v1.Event) interface{} { return ev.InvolvedObject.Kind },
+	"involved_object.name":        func(ev *v1.Event) interface{} { return ev.InvolvedObject.Name },
+	"involved_object.namespace":   func(ev *v1.Event) interface{} { return ev.InvolvedObject.Namespace },
+	"involved_object.uid":         func(ev *v1.Event) interface{} { return string(ev.InvolvedObject.UID) },
+	"involved_object.api_version": func(ev *v1.Event) interface{} { return ev.InvolvedObject.APIVersion },
+	"involved_object.field_path":  func(ev *v1.Event) interface{} { return ev.InvolvedObject.FieldPath },
+}
+
+// defaultAttributePrefix is the attribute under which the event fields are
+// set when they are not mapped by the field_mapping option.
+const defaultAttributePrefix = "kubernetes_event."
+
+// buildFieldMapping returns the attribute of each event field, applying the
+// overrides of the field_mapping option on top of the default mapping. A field
+// mapped to an empty attribute is dropped.
+func buildFieldMapping(overrides map[string]string) (map[string]string, error) {
+	mapping := make(map[string]string, len(eventFields))
+	for field := range eventFields {
+		mapping[field] = defaultAttributePrefix + field
+	}
+
+	for field, attribute := range overrides {
+		if _, found := eventFields[field]; !found {
+			return nil, fmt.Errorf("unknown kubernetes event field %q in field_mapping, supported fields are: %s", field, strings.Join(supportedFields(), ", "))
+		}
+		if attribute == "" {
+			delete(mapping, field)
+			continue
+		}
+		mapping[field] = attribute
+	}
+	return mapping, nil
+}
+
+// supportedFields returns the sorted names of the event fields.
+func supportedFields() []string {
+	fields := make([]string, 0, len(eventFields))
+	for field := range eventFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// setAttribute sets value in data at the dot-separated path of attribute,
+// creating the intermediate objects. An attribute conflicting with a
+// non-object value is not set.
+func setAttribute(data map[string]interface{}, attribute string, value interface{}) {
+	parts := strings.Split(attribute, ".")
+	for _, part := range parts[:len(parts)-1] {
+		child, found := data[part]
+		if !found {
+			child = make(map[string]interface{})
+			data[part] = child
+		}
+		childMap, ok := child.(map[string]interface{})
+		if !ok {
+			return
+		}
+		data = childMap
+	}
+	data[parts[len(parts)-1]] = value
+}
+
+// isEmpty returns whether value is the zero value of an event field, in which
+// case it's not set.
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case int32:
+		return v == 0
+	}
+	return value == nil
+}
+
+// formatTime returns the RFC3339 representation of t, or an empty string if t
+// is not set.
+func formatTime(t metav1.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// formatMicroTime returns the RFC3339 representation of t with its
+// microseconds, or an empty string if t is not set.
+func formatMicroTime(t metav1.MicroTime) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
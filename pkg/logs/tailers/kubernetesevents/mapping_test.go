@@ -0,0 +1,54 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package kubernetesevents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFieldMapping(t *testing.T) {
+	mapping, err := buildFieldMapping(map[string]string{
+		"reason":               "evt.name",
+		"involved_object.name": "kube_name",
+		"reporting_instance":   "",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "evt.name", mapping["reason"])
+	assert.Equal(t, "kube_name", mapping["involved_object.name"])
+	assert.Equal(t, "kubernetes_event.involved_object.kind", mapping["involved_object.kind"])
+	assert.NotContains(t, mapping, "reporting_instance")
+	assert.Len(t, mapping, len(eventFields)-1)
+}
+
+func TestBuildFieldMappingUnknownField(t *testing.T) {
+	_, err := buildFieldMapping(map[string]string{"spec.unknown": "foo"})
+	assert.ErrorContains(t, err, "spec.unknown")
+}
+
+func TestSetAttribute(t *testing.T) {
+	data := map[string]interface{}{"message": "hello"}
+
+	setAttribute(data, "kubernetes_event.reason", "Started")
+	setAttribute(data, "kubernetes_event.involved_object.kind", "Pod")
+	setAttribute(data, "reason", "Started")
+	// an attribute can't be nested under a value
+	setAttribute(data, "message.reason", "Started")
+
+	assert.Equal(t, map[string]interface{}{
+		"message": "hello",
+		"reason":  "Started",
+		"kubernetes_event": map[string]interface{}{
+			"reason": "Started",
+			"involved_object": map[string]interface{}{
+				"kind": "Pod",
+			},
+		},
+	}, data)
+}
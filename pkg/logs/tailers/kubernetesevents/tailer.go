@@ -0,0 +1,191 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Package kubernetesevents implements a tailer converting the Kubernetes
+// events into structured logs.
+package kubernetesevents
+
+import (
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// defaultSource is the source of the logs when none is configured.
+const defaultSource = "kubernetes"
+
+// Tailer receives the Kubernetes events from informers, and forwards the
+// events of the selected namespaces as logs.
+type Tailer struct {
+	source *sources.LogSource
+	// informers watch the events of the included namespaces, or of the whole
+	// cluster when no namespace is included
+	informers         []cache.SharedIndexInformer
+	isLeader          func() bool
+	outputChan        chan *message.Message
+	fieldMapping      map[string]string
+	includeNamespaces map[string]struct{}
+	excludeNamespaces map[string]struct{}
+	// startTime is the time the tailer started at, the events seen for the
+	// last time before it are not forwarded when the informer lists them.
+	startTime     time.Time
+	registrations []cache.ResourceEventHandlerRegistration
+}
+
+// NewTailer returns a new Tailer, or an error if the field mapping of the
+// source is invalid. The events are only forwarded while isLeader returns
+// true, so that a single agent of the cluster forwards them.
+func NewTailer(source *sources.LogSource, informers []cache.SharedIndexInformer, isLeader func() bool, outputChan chan *message.Message) (*Tailer, error) {
+	fieldMapping, err := buildFieldMapping(source.Config.FieldMapping)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tailer{
+		source:            source,
+		informers:         informers,
+		isLeader:          isLeader,
+		outputChan:        outputChan,
+		fieldMapping:      fieldMapping,
+		includeNamespaces: toSet(source.Config.IncludeNamespaces),
+		excludeNamespaces: toSet(source.Config.ExcludeNamespaces),
+	}, nil
+}
+
+// Start starts forwarding the events received by the informers
+func (t *Tailer) Start() error {
+	// the event timestamps have a precision of a second
+	t.startTime = time.Now().Truncate(time.Second)
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if ev, ok := obj.(*v1.Event); ok && !lastSeen(ev).Before(t.startTime) {
+				t.forward(ev)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldEv, ok := oldObj.(*v1.Event)
+			if !ok {
+				return
+			}
+			newEv, ok := newObj.(*v1.Event)
+			// the informer resyncs send updates for the events that didn't change
+			if ok && newEv.ResourceVersion != oldEv.ResourceVersion {
+				t.forward(newEv)
+			}
+		},
+	}
+	for _, informer := range t.informers {
+		registration, err := informer.AddEventHandler(handler)
+		if err != nil {
+			t.Stop()
+			return fmt.Errorf("can't watch the kubernetes events: %w", err)
+		}
+		t.registrations = append(t.registrations, registration)
+	}
+	return nil
+}
+
+// Stop stops forwarding the events
+func (t *Tailer) Stop() {
+	for i, registration := range t.registrations {
+		if err := t.informers[i].RemoveEventHandler(registration); err != nil {
+			log.Warnf("Couldn't stop watching the kubernetes events: %v", err)
+		}
+	}
+	t.registrations = nil
+}
+
+// forward sends the event to the output channel if its namespace is selected
+// and the agent is the leader.
+func (t *Tailer) forward(ev *v1.Event) {
+	if !t.isLeader() || !t.isNamespaceSelected(ev.InvolvedObject.Namespace) {
+		return
+	}
+	msg := t.toMessage(ev)
+	t.source.RecordBytes(int64(len(ev.Message)))
+	t.outputChan <- msg
+}
+
+// isNamespaceSelected returns whether the events of namespace are forwarded.
+// The events of the cluster-scoped objects are considered part of the ""
+// namespace.
+func (t *Tailer) isNamespaceSelected(namespace string) bool {
+	if len(t.includeNamespaces) > 0 {
+		if _, found := t.includeNamespaces[namespace]; !found {
+			return false
+		}
+	}
+	_, excluded := t.excludeNamespaces[namespace]
+	return !excluded
+}
+
+// toMessage returns a structured message holding the mapped fields of the event.
+func (t *Tailer) toMessage(ev *v1.Event) *message.Message {
+	content := message.BasicStructuredContent{
+		Data: make(map[string]interface{}),
+	}
+	for field, attribute := range t.fieldMapping {
+		if value := eventFields[field](ev); !isEmpty(value) {
+			setAttribute(content.Data, attribute, value)
+		}
+	}
+	content.SetContent([]byte(ev.Message))
+
+	status := message.StatusInfo
+	if ev.Type == v1.EventTypeWarning {
+		status = message.StatusWarning
+	}
+
+	return message.NewStructuredMessage(&content, t.getOrigin(ev), status, time.Now().UnixNano())
+}
+
+// getOrigin returns the message origin. The source and the service of the
+// message are still overridden by the integration config when defined.
+func (t *Tailer) getOrigin(ev *v1.Event) *message.Origin {
+	origin := message.NewOrigin(t.source)
+	origin.SetSource(defaultSource)
+	if ev.Source.Component != "" {
+		origin.SetService(ev.Source.Component)
+	} else if ev.ReportingController != "" {
+		origin.SetService(ev.ReportingController)
+	}
+
+	tags := []string{
+		"kube_kind:" + ev.InvolvedObject.Kind,
+		"kube_name:" + ev.InvolvedObject.Name,
+		"event_reason:" + ev.Reason,
+	}
+	if ev.InvolvedObject.Namespace != "" {
+		tags = append(tags, "kube_namespace:"+ev.InvolvedObject.Namespace)
+	}
+	origin.SetTags(tags)
+	return origin
+}
+
+// lastSeen returns the last time the event was seen.
+func lastSeen(ev *v1.Event) time.Time {
+	switch {
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.Time
+	default:
+		return ev.CreationTimestamp.Time
+	}
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,208 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package kubernetesevents
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+)
+
+func newEvent(namespace, name, reason string, lastTimestamp time.Time) *v1.Event {
+	return &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		InvolvedObject: v1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: namespace,
+			Name:      "redis",
+		},
+		Reason:        reason,
+		Message:       "Back-off restarting failed container",
+		Type:          v1.EventTypeWarning,
+		Count:         1,
+		Source:        v1.EventSource{Component: "kubelet"},
+		LastTimestamp: metav1.NewTime(lastTimestamp),
+	}
+}
+
+func renderData(t *testing.T, msg *message.Message) map[string]interface{} {
+	rendered, err := msg.Render()
+	require.NoError(t, err)
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal(rendered, &data))
+	return data
+}
+
+func startTailer(t *testing.T, logsConfig *config.LogsConfig, existing ...*v1.Event) (*fake.Clientset, chan *message.Message) {
+	return startTailerWithLeader(t, logsConfig, func() bool { return true }, existing...)
+}
+
+func startTailerWithLeader(t *testing.T, logsConfig *config.LogsConfig, isLeader func() bool, existing ...*v1.Event) (*fake.Clientset, chan *message.Message) {
+	var objects []runtime.Object
+	for _, ev := range existing {
+		objects = append(objects, ev)
+	}
+	client := fake.NewSimpleClientset(objects...)
+	// the fake clientset drops the objects created before the informer watches them
+	watchStarted := make(chan struct{})
+	client.PrependWatchReactor("*", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		w, err := client.Tracker().Watch(action.GetResource(), action.GetNamespace())
+		if err != nil {
+			return false, nil, err
+		}
+		select {
+		case <-watchStarted:
+		default:
+			close(watchStarted)
+		}
+		return true, w, nil
+	})
+	factory := informers.NewSharedInformerFactory(client, 0)
+	informer := factory.Core().V1().Events().Informer()
+
+	outputChan := make(chan *message.Message, 10)
+	tailer, err := NewTailer(sources.NewLogSource("", logsConfig), []cache.SharedIndexInformer{informer}, isLeader, outputChan)
+	require.NoError(t, err)
+	require.NoError(t, tailer.Start())
+	t.Cleanup(tailer.Stop)
+
+	stop := make(chan struct{})
+	t.Cleanup(func() { close(stop) })
+	factory.Start(stop)
+	factory.WaitForCacheSync(stop)
+	<-watchStarted
+
+	return client, outputChan
+}
+
+func TestTailerForwardsEvents(t *testing.T) {
+	client, outputChan := startTailer(t, &config.LogsConfig{
+		Type:         config.KubernetesEventsType,
+		FieldMapping: map[string]string{"involved_object.name": "pod_name"},
+	})
+
+	ev := newEvent("default", "redis.1", "BackOff", time.Now())
+	_, err := client.CoreV1().Events("default").Create(context.Background(), ev, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	var msg *message.Message
+	require.Eventually(t, func() bool {
+		select {
+		case msg = <-outputChan:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "Back-off restarting failed container", string(msg.GetContent()))
+	assert.Equal(t, message.StatusWarning, msg.GetStatus())
+	assert.Equal(t, "kubernetes", msg.Origin.Source())
+	assert.Equal(t, "kubelet", msg.Origin.Service())
+	assert.ElementsMatch(t, []string{"kube_kind:Pod", "kube_name:redis", "event_reason:BackOff", "kube_namespace:default"}, msg.Origin.Tags(nil))
+
+	data := renderData(t, msg)
+	assert.Equal(t, "redis", data["pod_name"])
+	kubeEvent := data["kubernetes_event"].(map[string]interface{})
+	assert.Equal(t, "BackOff", kubeEvent["reason"])
+	assert.Equal(t, float64(1), kubeEvent["count"])
+	assert.Equal(t, map[string]interface{}{"kind": "Pod", "namespace": "default"}, kubeEvent["involved_object"])
+}
+
+func TestTailerSkipsEventsSeenBeforeStart(t *testing.T) {
+	old := newEvent("default", "redis.1", "BackOff", time.Now().Add(-time.Hour))
+	client, outputChan := startTailer(t, &config.LogsConfig{Type: config.KubernetesEventsType}, old)
+
+	// the old event is forwarded again once it is seen again
+	old.Count = 2
+	old.LastTimestamp = metav1.Now()
+	old.ResourceVersion = "2"
+	_, err := client.CoreV1().Events("default").Update(context.Background(), old, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	var msg *message.Message
+	require.Eventually(t, func() bool {
+		select {
+		case msg = <-outputChan:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+	data := renderData(t, msg)
+	assert.Equal(t, float64(2), data["kubernetes_event"].(map[string]interface{})["count"])
+	assert.Empty(t, outputChan)
+}
+
+func TestTailerNamespaceFiltering(t *testing.T) {
+	client, outputChan := startTailer(t, &config.LogsConfig{
+		Type:              config.KubernetesEventsType,
+		IncludeNamespaces: []string{"prod", "staging"},
+		ExcludeNamespaces: []string{"staging"},
+	})
+
+	for _, namespace := range []string{"default", "staging", "prod"} {
+		ev := newEvent(namespace, "redis.1", "BackOff", time.Now())
+		_, err := client.CoreV1().Events(namespace).Create(context.Background(), ev, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	var msg *message.Message
+	require.Eventually(t, func() bool {
+		select {
+		case msg = <-outputChan:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(t, msg.Origin.Tags(nil), "kube_namespace:prod")
+	assert.Never(t, func() bool { return len(outputChan) > 0 }, 200*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestTailerForwardsOnlyWhenLeader(t *testing.T) {
+	var leader atomic.Bool
+	client, outputChan := startTailerWithLeader(t, &config.LogsConfig{Type: config.KubernetesEventsType}, leader.Load)
+
+	ev := newEvent("default", "redis.1", "BackOff", time.Now())
+	_, err := client.CoreV1().Events("default").Create(context.Background(), ev, metav1.CreateOptions{})
+	require.NoError(t, err)
+	assert.Never(t, func() bool { return len(outputChan) > 0 }, 200*time.Millisecond, 10*time.Millisecond)
+
+	leader.Store(true)
+	ev = newEvent("default", "redis.2", "BackOff", time.Now())
+	_, err = client.CoreV1().Events("default").Create(context.Background(), ev, metav1.CreateOptions{})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return len(outputChan) == 1 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestNewTailerInvalidFieldMapping(t *testing.T) {
+	_, err := NewTailer(sources.NewLogSource("", &config.LogsConfig{
+		Type:         config.KubernetesEventsType,
+		FieldMapping: map[string]string{"spec": "foo"},
+	}), nil, nil, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,89 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package syslog
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// maxFrameLengthDigits is the maximum number of digits of the length prefixing
+// an octet-counted frame.
+const maxFrameLengthDigits = 9
+
+// readFrame reads the next syslog frame of a stream, as described by RFC6587:
+// the frames starting with a digit are octet-counted ("MSG-LEN SP SYSLOG-MSG"),
+// and the other ones are terminated by a newline. The frames longer than
+// maxSize are truncated, and their trailing bytes are discarded.
+func readFrame(r *bufio.Reader, maxSize int) ([]byte, error) {
+	first, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+	if first[0] >= '0' && first[0] <= '9' {
+		return readOctetCountedFrame(r, maxSize)
+	}
+	return readNewlineFrame(r, maxSize)
+}
+
+// readOctetCountedFrame reads a frame prefixed by its length.
+func readOctetCountedFrame(r *bufio.Reader, maxSize int) ([]byte, error) {
+	prefix, err := r.ReadSlice(' ')
+	if err == bufio.ErrBufferFull || len(prefix) > maxFrameLengthDigits+1 {
+		return nil, fmt.Errorf("invalid octet-counted frame length %q", prefix)
+	}
+	if err != nil {
+		return nil, noEOF(err)
+	}
+	length, err := strconv.Atoi(string(prefix[:len(prefix)-1]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid octet-counted frame length %q", prefix)
+	}
+
+	frame := make([]byte, min(length, maxSize))
+	if _, err := io.ReadFull(r, frame); err != nil {
+		return nil, noEOF(err)
+	}
+	if length > maxSize {
+		if _, err := r.Discard(length - maxSize); err != nil {
+			return nil, noEOF(err)
+		}
+	}
+	return bytes.TrimSuffix(frame, []byte{'\n'}), nil
+}
+
+// readNewlineFrame reads a frame terminated by a newline. The last frame of the
+// stream is returned even if it is not terminated.
+func readNewlineFrame(r *bufio.Reader, maxSize int) ([]byte, error) {
+	var frame []byte
+	for {
+		line, err := r.ReadSlice('\n')
+		if len(frame) < maxSize {
+			frame = append(frame, line[:min(len(line), maxSize-len(frame))]...)
+		}
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && len(frame) > 0:
+			return bytes.TrimRight(frame, "\r\n"), nil
+		case err != nil:
+			return nil, err
+		}
+		return bytes.TrimRight(frame, "\r\n"), nil
+	}
+}
+
+// noEOF returns io.ErrUnexpectedEOF instead of io.EOF, as the stream ended in
+// the middle of a frame.
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
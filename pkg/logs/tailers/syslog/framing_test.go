@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package syslog
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readFrames(t *testing.T, stream string, maxSize int) []string {
+	r := bufio.NewReader(strings.NewReader(stream))
+	var frames []string
+	for {
+		frame, err := readFrame(r, maxSize)
+		if err == io.EOF {
+			return frames
+		}
+		require.NoError(t, err)
+		frames = append(frames, string(frame))
+	}
+}
+
+func TestReadFrameNewline(t *testing.T) {
+	frames := readFrames(t, "<13>1 - - - - - - first\n<13>second\r\n\n<13>last", 1024)
+	assert.Equal(t, []string{"<13>1 - - - - - - first", "<13>second", "", "<13>last"}, frames)
+}
+
+func TestReadFrameOctetCounting(t *testing.T) {
+	frames := readFrames(t, "23 <13>1 - - - - - - first18 <13>multi\nline\nmsg", 1024)
+	assert.Equal(t, []string{"<13>1 - - - - - - first", "<13>multi\nline\nmsg"}, frames)
+}
+
+func TestReadFrameMixed(t *testing.T) {
+	frames := readFrames(t, "9 <13>first<13>second\n", 1024)
+	assert.Equal(t, []string{"<13>first", "<13>second"}, frames)
+}
+
+func TestReadFrameTruncated(t *testing.T) {
+	frames := readFrames(t, "12 <13>long msg<13>a long newline message\n<13>next\n", 8)
+	assert.Equal(t, []string{"<13>long", "<13>a lo", "<13>next"}, frames)
+}
+
+func TestReadFrameErrors(t *testing.T) {
+	for _, stream := range []string{
+		"1234567890 <13>too long length",
+		"12a <13>invalid length",
+		"20 <13>incomplete",
+	} {
+		_, err := readFrame(bufio.NewReader(strings.NewReader(stream)), 1024)
+		assert.Error(t, err, stream)
+		assert.NotEqual(t, io.EOF, err, stream)
+	}
+}
@@ -0,0 +1,264 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package syslog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// nilValue is the value used by RFC5424 for the header fields and the
+// structured data that are not set.
+const nilValue = "-"
+
+// utf8BOM is the byte order mark that can prefix an RFC5424 message.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// rfc3164TimestampLayout is the layout of the timestamps of the RFC3164
+// messages, which do not contain the year.
+const rfc3164TimestampLayout = time.Stamp
+
+// Message is a syslog message parsed from an RFC3164 or RFC5424 frame.
+type Message struct {
+	Facility int
+	Severity int
+	// Version is 0 for RFC3164 messages.
+	Version   int
+	Timestamp time.Time
+	Hostname  string
+	AppName   string
+	ProcID    string
+	MsgID     string
+	// StructuredData maps the SD-IDs of an RFC5424 message to their parameters.
+	StructuredData map[string]map[string]string
+	Msg            []byte
+}
+
+// Parse parses a syslog frame, without its trailing newline. RFC5424 messages
+// are recognized by their version following the priority, the other messages
+// are parsed as RFC3164. As RFC3164 only describes the common practices of the
+// syslog daemons, the parsing of these messages is lenient: the fields that
+// can't be parsed are left empty and the rest of the frame is kept as the
+// message.
+func Parse(frame []byte, now time.Time) (Message, error) {
+	var msg Message
+
+	rest, err := parsePriority(frame, &msg)
+	if err != nil {
+		return msg, err
+	}
+
+	if version, after, ok := parseVersion(rest); ok {
+		msg.Version = version
+		err = parseRFC5424(after, &msg)
+	} else {
+		parseRFC3164(rest, now, &msg)
+	}
+	return msg, err
+}
+
+// parsePriority parses the <PRI> part of a frame, and returns what follows it.
+func parsePriority(frame []byte, msg *Message) ([]byte, error) {
+	if len(frame) == 0 || frame[0] != '<' {
+		return nil, errors.New("missing priority")
+	}
+	end := bytes.IndexByte(frame, '>')
+	if end < 2 || end > 4 {
+		return nil, errors.New("invalid priority")
+	}
+	// the priority is made of digits only, strconv.Atoi would accept a sign
+	for _, c := range frame[1:end] {
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("invalid priority %q", frame[1:end])
+		}
+	}
+	priority, err := strconv.Atoi(string(frame[1:end]))
+	if err != nil || priority < 0 || priority > 191 {
+		return nil, fmt.Errorf("invalid priority %q", frame[1:end])
+	}
+	msg.Facility = priority / 8
+	msg.Severity = priority % 8
+	return frame[end+1:], nil
+}
+
+// parseVersion parses the version following the priority of an RFC5424 message.
+func parseVersion(rest []byte) (int, []byte, bool) {
+	i := 0
+	for i < len(rest) && i < 3 && rest[i] >= '0' && rest[i] <= '9' {
+		i++
+	}
+	if i == 0 || i >= len(rest) || rest[i] != ' ' || rest[0] == '0' {
+		return 0, rest, false
+	}
+	version, _ := strconv.Atoi(string(rest[:i]))
+	return version, rest[i+1:], true
+}
+
+// parseRFC5424 parses the header, the structured data and the message of an
+// RFC5424 frame, following its version.
+func parseRFC5424(rest []byte, msg *Message) error {
+	var timestamp string
+	for _, field := range []*string{&timestamp, &msg.Hostname, &msg.AppName, &msg.ProcID, &msg.MsgID} {
+		var value []byte
+		value, rest = nextField(rest)
+		if len(value) == 0 {
+			return errors.New("truncated header")
+		}
+		if string(value) != nilValue {
+			*field = string(value)
+		}
+	}
+
+	if timestamp != "" {
+		ts, err := time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil {
+			return fmt.Errorf("invalid timestamp %q", timestamp)
+		}
+		msg.Timestamp = ts
+	}
+
+	rest, err := parseStructuredData(rest, msg)
+	if err != nil {
+		return err
+	}
+
+	if len(rest) > 0 {
+		if rest[0] != ' ' {
+			return errors.New("missing space after the structured data")
+		}
+		rest = rest[1:]
+	}
+	msg.Msg = bytes.TrimPrefix(rest, utf8BOM)
+	return nil
+}
+
+// nextField returns the bytes until the next space, and what follows the space.
+func nextField(rest []byte) ([]byte, []byte) {
+	if i := bytes.IndexByte(rest, ' '); i >= 0 {
+		return rest[:i], rest[i+1:]
+	}
+	return rest, nil
+}
+
+// parseStructuredData parses the structured data of an RFC5424 message, and
+// returns what follows it.
+func parseStructuredData(rest []byte, msg *Message) ([]byte, error) {
+	if len(rest) == 0 {
+		return nil, errors.New("missing structured data")
+	}
+	if rest[0] == '-' {
+		return rest[1:], nil
+	}
+
+	for len(rest) > 0 && rest[0] == '[' {
+		end := bytes.IndexAny(rest, " ]")
+		if end < 0 {
+			return nil, errors.New("unterminated structured data element")
+		}
+		id := string(rest[1:end])
+		if id == "" {
+			return nil, errors.New("missing structured data element id")
+		}
+		rest = rest[end:]
+
+		params := make(map[string]string)
+		for len(rest) > 0 && rest[0] == ' ' {
+			var name, value string
+			var err error
+			name, value, rest, err = parseParam(rest[1:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid structured data element %q: %v", id, err)
+			}
+			params[name] = value
+		}
+		if len(rest) == 0 || rest[0] != ']' {
+			return nil, fmt.Errorf("unterminated structured data element %q", id)
+		}
+		rest = rest[1:]
+
+		if msg.StructuredData == nil {
+			msg.StructuredData = make(map[string]map[string]string)
+		}
+		msg.StructuredData[id] = params
+	}
+	return rest, nil
+}
+
+// parseParam parses a PARAM-NAME="PARAM-VALUE" pair, unescaping the '"', '\'
+// and ']' characters of the value.
+func parseParam(rest []byte) (string, string, []byte, error) {
+	eq := bytes.Index(rest, []byte(`="`))
+	if eq <= 0 {
+		return "", "", nil, errors.New("missing parameter value")
+	}
+	name := string(rest[:eq])
+	rest = rest[eq+2:]
+
+	var value []byte
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case '\\':
+			if i+1 < len(rest) && (rest[i+1] == '"' || rest[i+1] == '\\' || rest[i+1] == ']') {
+				i++
+			}
+			value = append(value, rest[i])
+		case '"':
+			return name, string(value), rest[i+1:], nil
+		default:
+			value = append(value, rest[i])
+		}
+	}
+	return "", "", nil, fmt.Errorf("unterminated value for parameter %q", name)
+}
+
+// parseRFC3164 parses the timestamp, the hostname and the tag of an RFC3164
+// frame, following its priority. The year of the timestamp is taken from now.
+func parseRFC3164(rest []byte, now time.Time, msg *Message) {
+	msg.Msg = rest
+	if len(rest) < len(rfc3164TimestampLayout)+1 || rest[len(rfc3164TimestampLayout)] != ' ' {
+		return
+	}
+	ts, err := time.ParseInLocation(rfc3164TimestampLayout, string(rest[:len(rfc3164TimestampLayout)]), now.Location())
+	if err != nil {
+		return
+	}
+	ts = ts.AddDate(now.Year(), 0, 0)
+	// a message sent at the end of the year can be received at the beginning of the next one
+	if ts.After(now.Add(24 * time.Hour)) {
+		ts = ts.AddDate(-1, 0, 0)
+	}
+	msg.Timestamp = ts
+	rest = rest[len(rfc3164TimestampLayout)+1:]
+
+	hostname, after := nextField(rest)
+	// the hostname is optional, a message can follow the timestamp right away
+	if len(after) == 0 || bytes.HasSuffix(hostname, []byte(":")) || bytes.ContainsAny(hostname, "[]") {
+		msg.Msg = rest
+		parseTag(rest, msg)
+		return
+	}
+	msg.Hostname = string(hostname)
+	msg.Msg = after
+	parseTag(after, msg)
+}
+
+// parseTag parses the TAG[PID]: prefix of an RFC3164 message.
+func parseTag(rest []byte, msg *Message) {
+	colon := bytes.IndexByte(rest, ':')
+	if colon <= 0 || colon > 48 || bytes.IndexByte(rest[:colon], ' ') >= 0 {
+		return
+	}
+	tag := rest[:colon]
+	if open := bytes.IndexByte(tag, '['); open > 0 && tag[len(tag)-1] == ']' {
+		msg.ProcID = string(tag[open+1 : len(tag)-1])
+		tag = tag[:open]
+	}
+	msg.AppName = string(tag)
+	msg.Msg = bytes.TrimPrefix(rest[colon+1:], []byte(" "))
+}
@@ -0,0 +1,118 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package syslog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRFC5424(t *testing.T) {
+	frame := `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3" eventSource="Application" eventID="1011"][examplePriority@32473 class="high"] ` + "\xEF\xBB\xBF" + `An application event log entry...`
+
+	msg, err := Parse([]byte(frame), time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, 20, msg.Facility)
+	assert.Equal(t, 5, msg.Severity)
+	assert.Equal(t, 1, msg.Version)
+	assert.Equal(t, time.Date(2003, 10, 11, 22, 14, 15, 3000000, time.UTC), msg.Timestamp)
+	assert.Equal(t, "mymachine.example.com", msg.Hostname)
+	assert.Equal(t, "evntslog", msg.AppName)
+	assert.Equal(t, "", msg.ProcID)
+	assert.Equal(t, "ID47", msg.MsgID)
+	assert.Equal(t, map[string]map[string]string{
+		"exampleSDID@32473":     {"iut": "3", "eventSource": "Application", "eventID": "1011"},
+		"examplePriority@32473": {"class": "high"},
+	}, msg.StructuredData)
+	assert.Equal(t, "An application event log entry...", string(msg.Msg))
+}
+
+func TestParseRFC5424NilValues(t *testing.T) {
+	msg, err := Parse([]byte(`<34>1 - - - - - -`), time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, msg.Facility)
+	assert.Equal(t, 2, msg.Severity)
+	assert.True(t, msg.Timestamp.IsZero())
+	assert.Empty(t, msg.Hostname)
+	assert.Empty(t, msg.StructuredData)
+	assert.Empty(t, msg.Msg)
+}
+
+func TestParseRFC5424EscapedStructuredData(t *testing.T) {
+	msg, err := Parse([]byte(`<13>1 2025-01-02T03:04:05+01:00 host app 1234 - [sd@1 a="quote \" backslash \\ bracket \]" b=""] msg`), time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, "1234", msg.ProcID)
+	assert.Equal(t, map[string]map[string]string{
+		"sd@1": {"a": `quote " backslash \ bracket ]`, "b": ""},
+	}, msg.StructuredData)
+	assert.Equal(t, "msg", string(msg.Msg))
+}
+
+func TestParseRFC3164(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	msg, err := Parse([]byte(`<34>Oct 11 22:14:15 mymachine su[123]: 'su root' failed for lonvick on /dev/pts/8`), now)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, msg.Facility)
+	assert.Equal(t, 2, msg.Severity)
+	assert.Equal(t, 0, msg.Version)
+	assert.Equal(t, time.Date(2024, 10, 11, 22, 14, 15, 0, time.UTC), msg.Timestamp)
+	assert.Equal(t, "mymachine", msg.Hostname)
+	assert.Equal(t, "su", msg.AppName)
+	assert.Equal(t, "123", msg.ProcID)
+	assert.Equal(t, "'su root' failed for lonvick on /dev/pts/8", string(msg.Msg))
+}
+
+func TestParseRFC3164WithoutHostname(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	msg, err := Parse([]byte(`<13>Feb  5 17:32:18 sshd: Accepted publickey`), now)
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Date(2025, 2, 5, 17, 32, 18, 0, time.UTC), msg.Timestamp)
+	assert.Empty(t, msg.Hostname)
+	assert.Equal(t, "sshd", msg.AppName)
+	assert.Equal(t, "Accepted publickey", string(msg.Msg))
+}
+
+func TestParseRFC3164Lenient(t *testing.T) {
+	msg, err := Parse([]byte(`<189>some device message`), time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, 23, msg.Facility)
+	assert.Equal(t, 5, msg.Severity)
+	assert.True(t, msg.Timestamp.IsZero())
+	assert.Equal(t, "some device message", string(msg.Msg))
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, frame := range []string{
+		``,
+		`no priority`,
+		`<>1 - - - - - -`,
+		`<192>1 - - - - - -`,
+		`<-1>1 - - - - - -`,
+		`<-1>Oct 11 22:14:15 host app: msg`,
+		`<+13>1 - - - - - -`,
+		`<abc>1 - - - - - -`,
+		`<13>1 - host`,
+		`<13>1 yesterday host app - - -`,
+		`<13>1 - host app - - [unterminated`,
+		`<13>1 - host app - - [sd a="unterminated]`,
+		`<13>1 - host app - - [sd a=b]`,
+		`<13>1 - host app - - -msg`,
+	} {
+		_, err := Parse([]byte(frame), time.Now())
+		assert.Error(t, err, frame)
+	}
+}
@@ -0,0 +1,206 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Package syslog implements a tailer parsing the RFC3164 and RFC5424 syslog
+// messages received on a connection.
+package syslog
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// defaultSource is the source of the messages when none is configured.
+const defaultSource = "syslog"
+
+// severityStatusMapping represents the 1:1 mapping between syslog severities and statuses.
+var severityStatusMapping = []string{
+	message.StatusEmergency,
+	message.StatusAlert,
+	message.StatusCritical,
+	message.StatusError,
+	message.StatusWarning,
+	message.StatusNotice,
+	message.StatusInfo,
+	message.StatusDebug,
+}
+
+// Tailer reads syslog messages from a connection, either from a stream, in
+// which case the messages are framed as described by RFC6587, or from
+// datagrams holding one message each.
+type Tailer struct {
+	source      *sources.LogSource
+	conn        net.Conn
+	outputChan  chan *message.Message
+	idleTimeout time.Duration
+	// read returns the next frame and the address of its sender.
+	read func() ([]byte, string, error)
+	done chan struct{}
+}
+
+// NewStreamTailer returns a new Tailer reading the messages of a TCP or TLS
+// connection. The connection is closed when it stays idle for idleTimeout,
+// if set.
+func NewStreamTailer(source *sources.LogSource, conn net.Conn, outputChan chan *message.Message, idleTimeout time.Duration, frameSize int) *Tailer {
+	t := newTailer(source, conn, outputChan, idleTimeout)
+	reader := bufio.NewReader(conn)
+	t.read = func() ([]byte, string, error) {
+		frame, err := readFrame(reader, frameSize)
+		return frame, conn.RemoteAddr().String(), err
+	}
+	return t
+}
+
+// NewPacketTailer returns a new Tailer reading the messages of a UDP
+// connection. The messages longer than frameSize are truncated.
+func NewPacketTailer(source *sources.LogSource, conn *net.UDPConn, outputChan chan *message.Message, frameSize int) *Tailer {
+	t := newTailer(source, conn, outputChan, 0)
+	t.read = func() ([]byte, string, error) {
+		frame := make([]byte, frameSize)
+		for {
+			n, addr, err := conn.ReadFromUDP(frame)
+			switch {
+			case errors.Is(err, net.ErrClosed):
+				return nil, "", err
+			case err != nil:
+				// a datagram error doesn't affect the next ones
+				log.Warnf("Couldn't read syslog datagram: %v", err)
+				continue
+			}
+			return trimNewlines(frame[:n]), addr.String(), nil
+		}
+	}
+	return t
+}
+
+func newTailer(source *sources.LogSource, conn net.Conn, outputChan chan *message.Message, idleTimeout time.Duration) *Tailer {
+	return &Tailer{
+		source:      source,
+		conn:        conn,
+		outputChan:  outputChan,
+		idleTimeout: idleTimeout,
+		done:        make(chan struct{}),
+	}
+}
+
+// Start starts reading the messages of the connection
+func (t *Tailer) Start() {
+	go t.readForever()
+}
+
+// Stop closes the connection and waits for the tailer to stop
+func (t *Tailer) Stop() {
+	t.conn.Close()
+	<-t.done
+}
+
+// Done returns a channel closed once the tailer stopped reading, either
+// because it was stopped or because the connection was closed.
+func (t *Tailer) Done() <-chan struct{} {
+	return t.done
+}
+
+// readForever reads and forwards the messages of the connection until it's closed.
+func (t *Tailer) readForever() {
+	defer func() {
+		t.conn.Close()
+		close(t.done)
+	}()
+	for {
+		if t.idleTimeout > 0 {
+			t.conn.SetReadDeadline(time.Now().Add(t.idleTimeout)) //nolint:errcheck
+		}
+		frame, addr, err := t.read()
+		switch {
+		case err == io.EOF || errors.Is(err, net.ErrClosed):
+			return
+		case err != nil:
+			log.Warnf("Couldn't read syslog message from connection: %v", err)
+			t.source.Status.Error(err)
+			return
+		case len(frame) == 0:
+			continue
+		}
+		t.source.RecordBytes(int64(len(frame)))
+
+		msg, err := Parse(frame, time.Now())
+		if err != nil {
+			// the frame is kept as is so that the messages sent by non-compliant clients aren't lost
+			log.Debugf("Couldn't parse syslog message from %s: %v", addr, err)
+			msg = Message{Severity: 6, Msg: frame}
+		}
+		t.outputChan <- t.toMessage(msg, addr)
+	}
+}
+
+// toMessage returns a structured message holding the syslog fields in a
+// "syslog" attribute.
+func (t *Tailer) toMessage(msg Message, addr string) *message.Message {
+	fields := map[string]interface{}{
+		"facility": msg.Facility,
+		"severity": msg.Severity,
+	}
+	if msg.Version > 0 {
+		fields["version"] = msg.Version
+	}
+	if !msg.Timestamp.IsZero() {
+		fields["timestamp"] = msg.Timestamp.Format(time.RFC3339Nano)
+	}
+	for key, value := range map[string]string{
+		"hostname": msg.Hostname,
+		"appname":  msg.AppName,
+		"procid":   msg.ProcID,
+		"msgid":    msg.MsgID,
+	} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	if len(msg.StructuredData) > 0 {
+		fields["structured_data"] = msg.StructuredData
+	}
+
+	content := message.BasicStructuredContent{
+		Data: map[string]interface{}{
+			"syslog": fields,
+		},
+	}
+	content.SetContent(msg.Msg)
+
+	return message.NewStructuredMessage(&content, t.getOrigin(msg, addr), severityStatusMapping[msg.Severity], time.Now().UnixNano())
+}
+
+// getOrigin returns the message origin. The source and the service of the
+// message are still overridden by the integration config when defined.
+func (t *Tailer) getOrigin(msg Message, addr string) *message.Origin {
+	origin := message.NewOrigin(t.source)
+	origin.SetSource(defaultSource)
+	if msg.AppName != "" {
+		origin.SetService(msg.AppName)
+	}
+	if addr != "" && pkgconfigsetup.Datadog().GetBool("logs_config.use_sourcehost_tag") {
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			addr = host
+		}
+		origin.SetTags([]string{"source_host:" + addr})
+	}
+	return origin
+}
+
+// trimNewlines removes the trailing newlines of a datagram.
+func trimNewlines(frame []byte) []byte {
+	for len(frame) > 0 && (frame[len(frame)-1] == '\n' || frame[len(frame)-1] == '\r') {
+		frame = frame[:len(frame)-1]
+	}
+	return frame
+}
@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package syslog
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+)
+
+func TestStreamTailer(t *testing.T) {
+	source := sources.NewLogSource("", &config.LogsConfig{Type: config.SyslogType, Protocol: config.TCPType, Service: "network"})
+	outputChan := make(chan *message.Message, 10)
+
+	server, client := net.Pipe()
+	tailer := NewStreamTailer(source, server, outputChan, 0, 1024)
+	tailer.Start()
+
+	fmt.Fprint(client, "<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 [sd@1 iut=\"3\"] first\n")
+	fmt.Fprint(client, "39 <12>Oct 11 22:14:15 router sshd: second")
+
+	msg := <-outputChan
+	assert.Equal(t, "first", string(msg.GetContent()))
+	assert.Equal(t, message.StatusNotice, msg.GetStatus())
+	assert.Equal(t, "syslog", msg.Origin.Source())
+	// the service of the config overrides the app name
+	assert.Equal(t, "network", msg.Origin.Service())
+	rendered, err := msg.Render()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"message": "first",
+		"syslog": {
+			"facility": 20,
+			"severity": 5,
+			"version": 1,
+			"timestamp": "2003-10-11T22:14:15.003Z",
+			"hostname": "mymachine",
+			"appname": "evntslog",
+			"msgid": "ID47",
+			"structured_data": {"sd@1": {"iut": "3"}}
+		}
+	}`, string(rendered))
+
+	msg = <-outputChan
+	assert.Equal(t, "second", string(msg.GetContent()))
+	assert.Equal(t, message.StatusWarning, msg.GetStatus())
+
+	// the tailer stops once the connection is closed by the client
+	client.Close()
+	<-tailer.Done()
+	tailer.Stop()
+}
+
+func TestPacketTailer(t *testing.T) {
+	source := sources.NewLogSource("", &config.LogsConfig{Type: config.SyslogType, Protocol: config.UDPType})
+	outputChan := make(chan *message.Message, 10)
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	tailer := NewPacketTailer(source, conn, outputChan, 1024)
+	tailer.Start()
+	defer tailer.Stop()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	fmt.Fprint(client, "<11>Oct 11 22:14:15 switch01 kernel: link down\n")
+	msg := <-outputChan
+	assert.Equal(t, "link down", string(msg.GetContent()))
+	assert.Equal(t, message.StatusError, msg.GetStatus())
+	assert.Equal(t, "syslog", msg.Origin.Source())
+	assert.Equal(t, "kernel", msg.Origin.Service())
+
+	// invalid frames are forwarded as is
+	fmt.Fprint(client, "not a syslog message")
+	msg = <-outputChan
+	assert.Equal(t, "not a syslog message", string(msg.GetContent()))
+	assert.Equal(t, message.StatusInfo, msg.GetStatus())
+}
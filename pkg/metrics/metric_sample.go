@@ -6,6 +6,8 @@
 package metrics
 
 import (
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/quantile"
+
 	taggertypes "github.com/DataDog/datadog-agent/pkg/tagger/types"
 	"github.com/DataDog/datadog-agent/pkg/tagset"
 )
@@ -108,6 +110,8 @@ type MetricSample struct {
 	ListenerID      string
 	NoIndex         bool
 	Source          MetricSource
+	// Sketch holds the values of a pre-aggregated distribution, Value is ignored when it is set
+	Sketch *quantile.Sketch
 }
 
 // Implement the MetricSampleContext interface
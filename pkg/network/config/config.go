@@ -233,6 +233,14 @@ type Config struct {
 	// EnableMonotonicCount (Windows only) determines if we will calculate send/recv bytes of connections with headers and retransmits
 	EnableMonotonicCount bool
 
+	// EnableETWDNS (Windows only) enables the Microsoft-Windows-DNS-Client ETW provider as an additional
+	// source of DNS resolutions, covering queries answered from the local DNS client cache or hosts file
+	EnableETWDNS bool
+
+	// EnableETWTCP (Windows only) enables the Microsoft-Windows-Kernel-Network ETW provider to account for
+	// TCP retransmits on flows for which the driver does not report them
+	EnableETWTCP bool
+
 	// EnableGatewayLookup enables looking up gateway information for connection destinations
 	EnableGatewayLookup bool
 
@@ -382,6 +390,8 @@ func New() *Config {
 		EnableGatewayLookup: cfg.GetBool(sysconfig.FullKeyPath(netNS, "enable_gateway_lookup")),
 
 		EnableMonotonicCount: cfg.GetBool(sysconfig.FullKeyPath(spNS, "windows.enable_monotonic_count")),
+		EnableETWDNS:         cfg.GetBool(sysconfig.FullKeyPath(spNS, "windows.enable_etw_dns")),
+		EnableETWTCP:         cfg.GetBool(sysconfig.FullKeyPath(spNS, "windows.enable_etw_tcp")),
 
 		RecordedQueryTypes: cfg.GetStringSlice(sysconfig.FullKeyPath(netNS, "dns_recorded_query_types")),
 
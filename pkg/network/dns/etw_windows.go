@@ -0,0 +1,346 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build windows && npm
+
+package dns
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/DataDog/datadog-agent/comp/etw"
+	etwimpl "github.com/DataDog/datadog-agent/comp/etw/impl"
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/winutil"
+)
+
+const (
+	etwDNSSessionName = "SystemProbeNPM_DNS"
+
+	// Microsoft-Windows-DNS-Client {1c95126e-7eea-49a9-a3fe-a378b03ddb4d}
+	//     https://github.com/repnz/etw-providers-docs/blob/master/Manifests-Win10-18990/Microsoft-Windows-DNS-Client.xml
+	etwDNSClientProviderGUID = "{1c95126e-7eea-49a9-a3fe-a378b03ddb4d}"
+
+	// etwDNSQueryStarted is emitted when a query is issued, along with the DNS servers it is sent to
+	// and whether it is answered from the network.
+	etwDNSQueryStarted = 3006
+	// etwDNSQueryCompleted is emitted by the DNS client service once a query has been answered,
+	// whether the answer came from the network, the local cache or the hosts file.
+	etwDNSQueryCompleted = 3008
+
+	// recordTypePrefix prefixes non-address records (e.g. CNAMEs) in the QueryResults field
+	recordTypePrefix = "type:"
+
+	// statusRcodeBase is DNS_ERROR_RESPONSE_CODES_BASE, the QueryStatus of a failed response is
+	// the base plus its response code, up to DNS_ERROR_RCODE_LAST
+	statusRcodeBase = 9000
+	statusRcodeLast = 9018
+	// statusNoRecords is DNS_INFO_NO_RECORDS, the QueryStatus of a successful response without records
+	statusNoRecords = 9501
+)
+
+var etwDNSTelemetry = struct {
+	events       *telemetry.StatCounterWrapper
+	translations *telemetry.StatCounterWrapper
+	failures     *telemetry.StatCounterWrapper
+}{
+	telemetry.NewStatCounterWrapper(dnsModuleName, "etw_events", []string{}, "Counter measuring the number of DNS query completion events received from ETW"),
+	telemetry.NewStatCounterWrapper(dnsModuleName, "etw_translations", []string{}, "Counter measuring the number of DNS translations added to the cache from ETW"),
+	telemetry.NewStatCounterWrapper(dnsModuleName, "etw_failures", []string{}, "Counter measuring the number of failed DNS queries reported by ETW"),
+}
+
+// etwQuery is a query reported by ETW which hasn't completed yet
+type etwQuery struct {
+	key     Key
+	id      uint16
+	started time.Time
+}
+
+// etwDNSSource consumes the Microsoft-Windows-DNS-Client ETW provider and feeds the resolutions
+// it reports into the reverse DNS cache. Unlike the driver packet source it also observes queries
+// which never reach the network, such as queries answered from the DNS client cache or the hosts file.
+//
+// When the DNS stats are collected, the source also records the result of each query in its stats
+// keeper, like the packet source does from the DNS packets. The DNS client service doesn't report
+// the socket a query is sent from, so the stats are keyed by the DNS server only and go to a DNS
+// connection to that server.
+type etwDNSSource struct {
+	cache *reverseDNSCache
+	// stats is nil when the DNS stats are not collected
+	stats           *dnsStatKeeper
+	collectLocalDNS bool
+	timeout         time.Duration
+
+	session etw.Session
+	guid    windows.GUID
+	wg      sync.WaitGroup
+
+	// queries holds the started queries by activity ID, which is shared by the events of a query.
+	// It is only accessed by the event callback.
+	queries map[etw.DDGUID]etwQuery
+	nextID  uint16
+}
+
+// newETWDNSSource sets up the ETW session, the events are consumed once start is called
+func newETWDNSSource(cfg *config.Config) (*etwDNSSource, error) {
+	etwcomp, err := etwimpl.NewEtw()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &etwDNSSource{
+		collectLocalDNS: cfg.CollectLocalDNS,
+		timeout:         cfg.DNSTimeout,
+		queries:         make(map[etw.DDGUID]etwQuery),
+	}
+	s.session, err = etwcomp.NewSession(etwDNSSessionName, func(_ *etw.SessionConfiguration) {})
+	if err != nil {
+		return nil, err
+	}
+
+	s.guid, err = windows.GUIDFromString(etwDNSClientProviderGUID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating GUID for DNS-Client ETW provider: %w", err)
+	}
+	s.session.ConfigureProvider(s.guid, func(cfg *etw.ProviderConfiguration) {
+		cfg.TraceLevel = etw.TRACE_LEVEL_INFORMATION
+		cfg.EnabledIDs = []uint16{etwDNSQueryStarted, etwDNSQueryCompleted}
+	})
+	if err := s.session.EnableProvider(s.guid); err != nil {
+		_ = s.session.StopTracing()
+		return nil, fmt.Errorf("error enabling DNS-Client ETW provider: %w", err)
+	}
+
+	if cfg.CollectDNSStats {
+		s.stats = newDNSStatkeeper(cfg.DNSTimeout, int64(cfg.MaxDNSStats))
+	}
+	return s, nil
+}
+
+// start consumes the events, adding the resolutions to the given cache
+func (s *etwDNSSource) start(cache *reverseDNSCache) {
+	s.cache = cache
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		// StartTracing blocks until StopTracing is called
+		if err := s.session.StartTracing(s.onEvent); err != nil {
+			log.Errorf("ETW DNS-Client subscription failed with error %v", err)
+			return
+		}
+		log.Infof("ETW DNS-Client subscription completed")
+	}()
+}
+
+func (s *etwDNSSource) onEvent(e *etw.DDEventRecord) {
+	switch e.EventHeader.EventDescriptor.ID {
+	case etwDNSQueryStarted:
+		s.onQueryStarted(e)
+	case etwDNSQueryCompleted:
+		s.onQueryCompleted(e)
+	}
+}
+
+func (s *etwDNSSource) onQueryStarted(e *etw.DDEventRecord) {
+	if s.stats == nil {
+		return
+	}
+
+	// QueryName (wstring), QueryType (uint32), QueryOptions (uint64), ServerList (wstring), IsNetworkQuery (uint32), ...
+	data := etwimpl.GetUserData(e)
+	queryName, offset, found, _ := data.ParseUnicodeString(0)
+	if !found || offset+12 > data.Length() {
+		return
+	}
+	queryType := data.GetUint32(offset)
+	serverList, offset, found, _ := data.ParseUnicodeString(offset + 12)
+	if !found || offset+4 > data.Length() {
+		return
+	}
+	isNetworkQuery := data.GetUint32(offset) != 0
+
+	// the ServerList field has the format of the QueryResults one
+	servers := parseQueryResults(serverList)
+	if len(servers) == 0 {
+		return
+	}
+	// like the queries sent to a local resolver on Linux, the queries answered from the DNS
+	// client cache or the hosts file are only accounted when collecting the local DNS
+	if (!isNetworkQuery || servers[0].IsLoopback()) && !s.collectLocalDNS {
+		return
+	}
+
+	ts := eventTime(e)
+	if len(s.queries) >= maxStateMapSize {
+		s.expireQueries(ts)
+		if len(s.queries) >= maxStateMapSize {
+			return
+		}
+	}
+	q := etwQuery{
+		key:     Key{ServerIP: servers[0], Protocol: syscall.IPPROTO_UDP},
+		id:      s.nextID,
+		started: ts,
+	}
+	s.nextID++
+	s.queries[e.EventHeader.ActivityID] = q
+
+	s.stats.ProcessPacketInfo(dnsPacketInfo{
+		transactionID: q.id,
+		key:           q.key,
+		pktType:       query,
+		question:      ToHostname(strings.ToLower(queryName)),
+		queryType:     QueryType(queryType),
+	}, ts)
+}
+
+func (s *etwDNSSource) onQueryCompleted(e *etw.DDEventRecord) {
+	etwDNSTelemetry.events.Inc()
+
+	// QueryName (wstring), QueryType (uint32), QueryOptions (uint64), QueryStatus (uint32), QueryResults (wstring)
+	data := etwimpl.GetUserData(e)
+	queryName, offset, found, _ := data.ParseUnicodeString(0)
+	if !found || offset+16 > data.Length() {
+		return
+	}
+	status := data.GetUint32(offset + 12)
+	s.recordQueryResult(e, status)
+	if status != 0 {
+		etwDNSTelemetry.failures.Inc()
+		return
+	}
+	results, _, found, _ := data.ParseUnicodeString(offset + 16)
+	if !found {
+		return
+	}
+
+	t := &translation{
+		dns: ToHostname(strings.ToLower(queryName)),
+		ips: make(map[util.Address]time.Time),
+	}
+	for _, addr := range parseQueryResults(results) {
+		t.add(addr, dnsCacheExpirationPeriod)
+	}
+	if len(t.ips) > 0 && s.cache.Add(t) {
+		etwDNSTelemetry.translations.Add(int64(len(t.ips)))
+	}
+}
+
+// recordQueryResult records the response of a started query in the DNS stats. The queries which
+// completed without a DNS response, e.g. on a timeout, are accounted as timeouts once they expire,
+// like the unanswered queries seen by the packet source.
+func (s *etwDNSSource) recordQueryResult(e *etw.DDEventRecord, status uint32) {
+	q, ok := s.queries[e.EventHeader.ActivityID]
+	if !ok {
+		return
+	}
+	delete(s.queries, e.EventHeader.ActivityID)
+
+	rCode, ok := statusRcode(status)
+	if !ok {
+		return
+	}
+	pktType := successfulResponse
+	if rCode != 0 {
+		pktType = failedResponse
+	}
+	s.stats.ProcessPacketInfo(dnsPacketInfo{
+		transactionID: q.id,
+		key:           q.key,
+		pktType:       pktType,
+		rCode:         rCode,
+	}, eventTime(e))
+}
+
+// expireQueries drops the queries which didn't complete within the DNS timeout, the stats keeper
+// accounts them as timeouts
+func (s *etwDNSSource) expireQueries(now time.Time) {
+	for activityID, q := range s.queries {
+		if now.Sub(q.started) > s.timeout {
+			delete(s.queries, activityID)
+		}
+	}
+}
+
+// statusRcode returns the response code of a DNS response from the QueryStatus of a completed
+// query, it returns false when the query didn't get a DNS response
+func statusRcode(status uint32) (uint8, bool) {
+	switch {
+	case status == 0 || status == statusNoRecords:
+		return 0, true
+	case status > statusRcodeBase && status <= statusRcodeLast:
+		return uint8(status - statusRcodeBase), true
+	}
+	return 0, false
+}
+
+func eventTime(e *etw.DDEventRecord) time.Time {
+	return time.Unix(0, int64(winutil.FileTimeToUnixNano(e.EventHeader.TimeStamp)))
+}
+
+// Close stops the ETW session and waits for the event loop to exit
+func (s *etwDNSSource) Close() {
+	_ = s.session.StopTracing()
+	s.wg.Wait()
+	if s.stats != nil {
+		s.stats.Close()
+	}
+}
+
+// parseQueryResults extracts the addresses from the QueryResults field of a DNS-Client event.
+// The field is a semicolon separated list where IPv4 addresses are reported as IPv4-mapped IPv6
+// addresses and other record types are prefixed with their type, e.g.
+// "type:  5 edge.example.com;::ffff:192.0.2.10;2001:db8::1;"
+func parseQueryResults(results string) []util.Address {
+	var addrs []util.Address
+	for _, entry := range strings.Split(results, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, recordTypePrefix) {
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			continue
+		}
+		addrs = append(addrs, util.Address{Addr: addr.Unmap()})
+	}
+	return addrs
+}
+
+// etwSnooper is a socketFilterSnooper complemented by an ETW DNS-Client source. The DNS stats
+// are collected by the ETW source, which also sees the queries answered locally.
+type etwSnooper struct {
+	*socketFilterSnooper
+	etwSource *etwDNSSource
+}
+
+// GetDNSStats gets the latest Stats collected by the ETW source
+func (s *etwSnooper) GetDNSStats() StatsByKeyByNameByType {
+	if s.etwSource.stats == nil {
+		return nil
+	}
+	return s.etwSource.stats.GetAndResetAllStats()
+}
+
+// WaitForDomain is used in tests to ensure a domain has been seen by the ETW source
+func (s *etwSnooper) WaitForDomain(domain string) error {
+	return s.etwSource.stats.WaitForDomain(domain)
+}
+
+// Close terminates the ETW source before the underlying snooper, which owns the cache
+func (s *etwSnooper) Close() {
+	s.etwSource.Close()
+	s.socketFilterSnooper.Close()
+}
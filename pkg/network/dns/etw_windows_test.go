@@ -0,0 +1,27 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build windows && npm
+
+package dns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+)
+
+func TestParseQueryResults(t *testing.T) {
+	addrs := parseQueryResults("type:  5 edge.example.com;::ffff:192.0.2.10;2001:db8::1;;garbage;")
+	assert.Equal(t, []util.Address{
+		util.AddressFromString("192.0.2.10"),
+		util.AddressFromString("2001:db8::1"),
+	}, addrs)
+
+	assert.Empty(t, parseQueryResults(""))
+	assert.Empty(t, parseQueryResults("type:  5 edge.example.com;"))
+}
@@ -10,6 +10,7 @@ package dns
 import (
 	"github.com/DataDog/datadog-agent/comp/core/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
 // NewReverseDNS starts snooping on DNS traffic to allow IP -> domain reverse resolution
@@ -18,5 +19,33 @@ func NewReverseDNS(cfg *config.Config, telemetrycomp telemetry.Component) (Rever
 	if err != nil {
 		return nil, err
 	}
-	return newSocketFilterSnooper(cfg, packetSrc)
+	var etwSource *etwDNSSource
+	if cfg.EnableETWDNS {
+		if etwSource, err = newETWDNSSource(cfg); err != nil {
+			// the driver packet source is still functional, so don't fail the whole DNS monitoring
+			log.Warnf("could not start ETW DNS-Client source, falling back to the driver packet source only: %s", err)
+		}
+	}
+
+	snooperCfg := cfg
+	if etwSource != nil && cfg.CollectDNSStats {
+		// the DNS stats are collected by the ETW source, which also sees the queries answered locally
+		c := *cfg
+		c.CollectDNSStats = false
+		snooperCfg = &c
+	}
+	snooper, err := newSocketFilterSnooper(snooperCfg, packetSrc)
+	if err != nil {
+		if etwSource != nil {
+			etwSource.Close()
+		}
+		return nil, err
+	}
+	if etwSource == nil {
+		return snooper, nil
+	}
+
+	etwSource.start(snooper.cache)
+	log.Infof("ETW DNS-Client source has been enabled")
+	return &etwSnooper{socketFilterSnooper: snooper, etwSource: etwSource}, nil
 }
@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build windows || linux_bpf
+
+package dns
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+)
+
+const (
+	// maxTrackedServers limits the number of DNS servers tagged in the telemetry,
+	// the responses of the other servers are tagged with otherServerTag
+	maxTrackedServers = 64
+	otherServerTag    = "other"
+)
+
+// serverTelemetry breaks down the DNS responses and timeouts by DNS server in
+// the system-probe telemetry. The latencies aren't broken down: they're
+// reported by the latency sums of the DNS stats of each connection, whose
+// remote address is the DNS server.
+var serverTelemetry = struct {
+	responses telemetry.Counter
+	timeouts  telemetry.Counter
+}{
+	telemetry.NewCounter(dnsStatKeeperModuleName, "server_responses", []string{"server", "rcode"}, "Counter measuring the number of DNS responses by DNS server and response code"),
+	telemetry.NewCounter(dnsStatKeeperModuleName, "server_timeouts", []string{"server"}, "Counter measuring the number of DNS queries without response by DNS server"),
+}
+
+// serverTags keeps the tags of the DNS servers, bounding their number
+type serverTags struct {
+	tags map[util.Address]string
+	max  int
+}
+
+func newServerTags(maxServers int) *serverTags {
+	return &serverTags{
+		tags: make(map[util.Address]string),
+		max:  maxServers,
+	}
+}
+
+// get returns the tag of the given DNS server
+func (s *serverTags) get(server util.Address) string {
+	if tag, ok := s.tags[server]; ok {
+		return tag
+	}
+	if len(s.tags) >= s.max {
+		return otherServerTag
+	}
+	tag := server.String()
+	s.tags[server] = tag
+	return tag
+}
+
+// recordServerResponse records the response code of a DNS response
+func recordServerResponse(server string, rCode uint8) {
+	serverTelemetry.responses.Inc(server, rcodeTag(rCode))
+}
+
+// recordServerTimeout records a DNS query without response
+func recordServerTimeout(server string) {
+	serverTelemetry.timeouts.Inc(server)
+}
+
+// rcodeTag returns the tag of the response codes breaking down the DNS failures
+func rcodeTag(rCode uint8) string {
+	switch rCode {
+	case 0:
+		return "noerror"
+	case 1:
+		return "formerr"
+	case 2:
+		return "servfail"
+	case 3:
+		return "nxdomain"
+	case 5:
+		return "refused"
+	default:
+		return "other"
+	}
+}
@@ -76,6 +76,8 @@ type dnsStatKeeper struct {
 	processedStats   int64
 	droppedStats     int64
 	maxStats         int64
+	// servers tags the per-server telemetry
+	servers *serverTags
 }
 
 func newDNSStatkeeper(timeout time.Duration, maxStats int64) *dnsStatKeeper {
@@ -86,6 +88,7 @@ func newDNSStatkeeper(timeout time.Duration, maxStats int64) *dnsStatKeeper {
 		exit:             make(chan struct{}),
 		maxSize:          maxStateMapSize,
 		maxStats:         maxStats,
+		servers:          newServerTags(maxTrackedServers),
 	}
 
 	ticker := time.NewTicker(statsKeeper.expirationPeriod)
@@ -134,6 +137,14 @@ func (d *dnsStatKeeper) ProcessPacketInfo(info dnsPacketInfo, ts time.Time) {
 	d.deleteCount++
 
 	latency := microSecs(ts) - start.ts
+	timedOut := latency > uint64(d.expirationPeriod.Microseconds())
+
+	// the per-server telemetry isn't bounded by the number of stats
+	if timedOut {
+		recordServerTimeout(d.servers.get(info.key.ServerIP))
+	} else {
+		recordServerResponse(d.servers.get(info.key.ServerIP), info.rCode)
+	}
 
 	allStats, ok := d.stats[info.key]
 	if !ok {
@@ -160,7 +171,7 @@ func (d *dnsStatKeeper) ProcessPacketInfo(info dnsPacketInfo, ts time.Time) {
 		statsTelemetry.processedStats.Inc()
 	}
 
-	if latency > uint64(d.expirationPeriod.Microseconds()) {
+	if timedOut {
 		byqtype.Timeouts++
 	} else {
 		byqtype.CountByRcode[uint32(info.rCode)]++
@@ -252,6 +263,7 @@ func (d *dnsStatKeeper) removeExpiredStates(earliestTs time.Time) {
 		if v.ts < threshold {
 			delete(d.state, k)
 			d.deleteCount++
+			recordServerTimeout(d.servers.get(k.key.ServerIP))
 			// When we expire a state, we need to increment timeout count for that key:domain
 			allStats, ok := d.stats[k.key]
 			if !ok {
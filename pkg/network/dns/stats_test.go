@@ -106,6 +106,34 @@ func TestExpiredStateRemoval(t *testing.T) {
 	assert.Equal(t, uint32(1), stats[key][d][TypeA].Timeouts)
 }
 
+func TestServerTelemetry(t *testing.T) {
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000)
+	key := getSampleDNSKey()
+	var d = ToHostname("abc.com")
+
+	nxdomain := serverTelemetry.responses.WithValues("8.8.8.8", "nxdomain")
+	timeouts := serverTelemetry.timeouts.WithValues("8.8.8.8")
+	prevNXDomain, prevTimeouts := nxdomain.Get(), timeouts.Get()
+
+	now := time.Now()
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 1, pktType: query, key: key, question: d, queryType: TypeA}, now)
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 1, pktType: failedResponse, rCode: 3, key: key, queryType: TypeA}, now.Add(time.Millisecond))
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 2, pktType: query, key: key, question: d, queryType: TypeA}, now)
+	sk.removeExpiredStates(now.Add(DNSTimeoutSecs * time.Second))
+
+	assert.Equal(t, prevNXDomain+1, nxdomain.Get())
+	assert.Equal(t, prevTimeouts+1, timeouts.Get())
+}
+
+func TestServerTags(t *testing.T) {
+	servers := newServerTags(2)
+	assert.Equal(t, "8.8.8.8", servers.get(util.AddressFromString("8.8.8.8")))
+	assert.Equal(t, "1.1.1.1", servers.get(util.AddressFromString("1.1.1.1")))
+	// the number of servers is bounded
+	assert.Equal(t, otherServerTag, servers.get(util.AddressFromString("9.9.9.9")))
+	assert.Equal(t, "8.8.8.8", servers.get(util.AddressFromString("8.8.8.8")))
+}
+
 func BenchmarkStats(b *testing.B) {
 	key := getSampleDNSKey()
 
@@ -1637,6 +1637,9 @@ static __always_inline bool kafka_process(conn_tuple_t *tup, kafka_info_t *kafka
         if (!is_valid_client_id(pkt, offset, kafka_header.client_id_size)) {
             return false;
         }
+        // Keeping the client ID to attribute the latency to the producers and consumers.
+        pktbuf_load_bytes_with_telemetry(pkt, offset, kafka_transaction->client_id, CLIENT_ID_SIZE_TO_VALIDATE);
+        kafka_transaction->client_id_size = kafka_header.client_id_size < CLIENT_ID_SIZE_TO_VALIDATE ? kafka_header.client_id_size : CLIENT_ID_SIZE_TO_VALIDATE;
         offset += kafka_header.client_id_size;
     } else if (kafka_header.client_id_size < -1) {
         return false;
@@ -31,6 +31,9 @@ typedef struct kafka_transaction_t {
     __u8 tags;
     char topic_name[TOPIC_NAME_MAX_STRING_SIZE];
     __s8 error_code;
+    // The client ID is truncated to CLIENT_ID_SIZE_TO_VALIDATE bytes, client_id_size holds the truncated size.
+    __u8 client_id_size;
+    char client_id[CLIENT_ID_SIZE_TO_VALIDATE];
 } kafka_transaction_t;
 
 typedef struct kafka_event_t {
@@ -57,6 +57,22 @@ static __always_inline void handle_new_query(pktbuf_t pkt, conn_tuple_t *conn_tu
     bpf_map_update_elem(&postgres_in_flight, conn_tuple, &new_transaction, BPF_ANY);
 }
 
+// Returns true if the message is an error response sent by the server. The clients send Execute messages with the same
+// tag, but an error response always starts with the severity field.
+// Error response format - https://www.postgresql.org/docs/current/protocol-message-formats.html#PROTOCOL-MESSAGE-FORMATS-ERRORRESPONSE
+static __always_inline bool is_error_response(pktbuf_t pkt, struct pg_message_header *header) {
+    if (header->message_tag != POSTGRES_ERROR_RESPONSE_MAGIC_BYTE || header->message_len < POSTGRES_ERROR_RESPONSE_MIN_LEN) {
+        return false;
+    }
+    u32 data_off = pktbuf_data_offset(pkt) + sizeof(struct pg_message_header);
+    if (data_off + sizeof(__u8) > pktbuf_data_end(pkt)) {
+        return false;
+    }
+    __u8 field_type = 0;
+    pktbuf_load_bytes(pkt, data_off, &field_type, sizeof(field_type));
+    return field_type == POSTGRES_ERROR_SEVERITY_FIELD;
+}
+
 // Handles a command complete message by enqueuing the transaction and deleting it from the in-flight map.
 // The format of the command complete message is described here: https://www.postgresql.org/docs/current/protocol-message-formats.html#PROTOCOL-MESSAGE-FORMATS-COMMANDCOMPLETE
 static __always_inline void handle_command_complete(conn_tuple_t *conn_tuple, postgres_transaction_t *transaction) {
@@ -237,13 +253,14 @@ static __always_inline void postgres_handle_parse_message(pktbuf_t pkt, conn_tup
     return;
 }
 
-// Handles Postgres command complete messages by examining packet data for both plaintext and TLS traffic.
+// Handles Postgres command complete and error response messages by examining packet data for both plaintext and TLS traffic.
 // This function handles multiple messages within a single packet, processing up to POSTGRES_MAX_MESSAGES_PER_TAIL_CALL
 // messages per call. When more messages exist beyond this limit, it uses tail call chaining to continue processing.
 static __always_inline bool handle_response(pktbuf_t pkt, conn_tuple_t conn_tuple, postgres_kernel_msg_count_t* pg_msg_counts) {
     const __u32 zero = 0;
     bool read_result = false;
     bool found_command_complete = false;
+    bool found_error_response = false;
     struct pg_message_header header;
 
     postgres_tail_call_state_t *iteration_value = bpf_map_lookup_elem(&postgres_iterations, &zero);
@@ -278,6 +295,10 @@ static __always_inline bool handle_response(pktbuf_t pkt, conn_tuple_t conn_tupl
             found_command_complete = true;
             break;
         }
+        if (is_error_response(pkt, &header)) {
+            found_error_response = true;
+            break;
+        }
         // We didn't find a command complete message, so we advance the data offset to the end of the message.
         // reminder, the message length includes the size of the payload, 4 bytes of the message length itself, but not
         // the message tag. So we need to add 1 to the message length to jump over the entire message.
@@ -285,7 +306,8 @@ static __always_inline bool handle_response(pktbuf_t pkt, conn_tuple_t conn_tupl
     }
     iteration_value->total_msg_count += messages_count;
 
-    if (found_command_complete) {
+    if (found_command_complete || found_error_response) {
+        transaction->is_error = found_error_response;
         handle_command_complete(&conn_tuple, transaction);
         update_msg_count_telemetry(pg_msg_counts, iteration_value->total_msg_count);
 
@@ -29,6 +29,12 @@
 #define POSTGRES_QUERY_MAGIC_BYTE 'Q'
 #define POSTGRES_PARSE_MAGIC_BYTE 'P'
 #define POSTGRES_COMMAND_COMPLETE_MAGIC_BYTE 'C'
+#define POSTGRES_ERROR_RESPONSE_MAGIC_BYTE 'E'
+
+// The first field of an error response is the severity: | byte 'S' | string severity |
+#define POSTGRES_ERROR_SEVERITY_FIELD 'S'
+// Minimum length of an error response: 4 bytes of length, the severity field ("SERROR\0") and the terminator.
+#define POSTGRES_ERROR_RESPONSE_MIN_LEN 12
 
 #define POSTGRES_PING_BODY "-- ping"
 #define NULL_TERMINATOR '\0'
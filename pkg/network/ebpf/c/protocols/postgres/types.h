@@ -24,6 +24,8 @@ typedef struct {
     // The actual size of the query stored in request_fragment.
     __u32 original_query_size;
     __u8 tags;
+    // Set if the server replied with an error response instead of a command complete.
+    __u8 is_error;
 } postgres_transaction_t;
 
 // The struct we send to userspace, containing the connection tuple and the transaction information.
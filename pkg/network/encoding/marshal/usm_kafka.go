@@ -10,15 +10,26 @@ import (
 	"io"
 
 	model "github.com/DataDog/agent-payload/v5/process"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"github.com/DataDog/datadog-agent/pkg/network"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/kafka"
 	"github.com/DataDog/datadog-agent/pkg/network/types"
 )
 
+const (
+	// dataStreamsKafkaAggregationsField is the number of the kafkaAggregations field of the DataStreamsAggregations message
+	dataStreamsKafkaAggregationsField protowire.Number = 3
+	// kafkaAggregationClientIDField is the number of the client ID field of the KafkaAggregation message. The
+	// generated builders don't expose it, so it's appended to the message encoded by the builder.
+	kafkaAggregationClientIDField protowire.Number = 5
+)
+
 type kafkaEncoder struct {
-	kafkaAggregationsBuilder *model.DataStreamsAggregationsBuilder
-	byConnection             *USMConnectionIndex[kafka.Key, *kafka.RequestStats]
+	kafkaAggregationBuilder *model.KafkaAggregationBuilder
+	aggregationBuf          bytes.Buffer
+	scratch                 []byte
+	byConnection            *USMConnectionIndex[kafka.Key, *kafka.RequestStats]
 }
 
 func newKafkaEncoder(kafkaPayloads map[kafka.Key]*kafka.RequestStats) *kafkaEncoder {
@@ -27,7 +38,7 @@ func newKafkaEncoder(kafkaPayloads map[kafka.Key]*kafka.RequestStats) *kafkaEnco
 	}
 
 	return &kafkaEncoder{
-		kafkaAggregationsBuilder: model.NewDataStreamsAggregationsBuilder(nil),
+		kafkaAggregationBuilder: model.NewKafkaAggregationBuilder(nil),
 		byConnection: GroupByConnection("kafka", kafkaPayloads, func(key kafka.Key) types.ConnectionKey {
 			return key.ConnectionKey
 		}),
@@ -53,37 +64,47 @@ func (e *kafkaEncoder) WriteKafkaAggregations(c network.ConnectionStats, builder
 
 func (e *kafkaEncoder) encodeData(connectionData *USMConnectionData[kafka.Key, *kafka.RequestStats], w io.Writer) uint64 {
 	var staticTags uint64
-	e.kafkaAggregationsBuilder.Reset(w)
 
 	for _, kv := range connectionData.Data {
 		key := kv.Key
 		stats := kv.Value
-		e.kafkaAggregationsBuilder.AddKafkaAggregations(func(builder *model.KafkaAggregationBuilder) {
-			builder.SetHeader(func(header *model.KafkaRequestHeaderBuilder) {
-				header.SetRequest_type(uint32(key.RequestAPIKey))
-				header.SetRequest_version(uint32(key.RequestVersion))
-			})
-			builder.SetTopic(key.TopicName.Get())
-			for statusCode, requestStat := range stats.ErrorCodeToStat {
-				if requestStat.Count == 0 {
-					continue
-				}
-				builder.AddStatsByErrorCode(func(statsByErrorCodeBuilder *model.KafkaAggregation_StatsByErrorCodeEntryBuilder) {
-					statsByErrorCodeBuilder.SetKey(statusCode)
-					statsByErrorCodeBuilder.SetValue(func(kafkaStatsBuilder *model.KafkaStatsBuilder) {
-						kafkaStatsBuilder.SetCount(uint32(requestStat.Count))
-						if latencies := requestStat.Latencies; latencies != nil {
-							kafkaStatsBuilder.SetLatencies(func(b *bytes.Buffer) {
-								latencies.EncodeProto(b)
-							})
-						} else {
-							kafkaStatsBuilder.SetFirstLatencySample(requestStat.FirstLatencySample)
-						}
-					})
-				})
-				staticTags |= requestStat.StaticTags
-			}
+
+		e.aggregationBuf.Reset()
+		e.kafkaAggregationBuilder.Reset(&e.aggregationBuf)
+		e.kafkaAggregationBuilder.SetHeader(func(header *model.KafkaRequestHeaderBuilder) {
+			header.SetRequest_type(uint32(key.RequestAPIKey))
+			header.SetRequest_version(uint32(key.RequestVersion))
 		})
+		e.kafkaAggregationBuilder.SetTopic(key.TopicName.Get())
+		for statusCode, requestStat := range stats.ErrorCodeToStat {
+			if requestStat.Count == 0 {
+				continue
+			}
+			e.kafkaAggregationBuilder.AddStatsByErrorCode(func(statsByErrorCodeBuilder *model.KafkaAggregation_StatsByErrorCodeEntryBuilder) {
+				statsByErrorCodeBuilder.SetKey(statusCode)
+				statsByErrorCodeBuilder.SetValue(func(kafkaStatsBuilder *model.KafkaStatsBuilder) {
+					kafkaStatsBuilder.SetCount(uint32(requestStat.Count))
+					if latencies := requestStat.Latencies; latencies != nil {
+						kafkaStatsBuilder.SetLatencies(func(b *bytes.Buffer) {
+							latencies.EncodeProto(b)
+						})
+					} else {
+						kafkaStatsBuilder.SetFirstLatencySample(requestStat.FirstLatencySample)
+					}
+				})
+			})
+			staticTags |= requestStat.StaticTags
+		}
+		if key.ClientID != nil {
+			e.scratch = protowire.AppendTag(e.scratch[:0], kafkaAggregationClientIDField, protowire.BytesType)
+			e.scratch = protowire.AppendString(e.scratch, key.ClientID.Get())
+			e.aggregationBuf.Write(e.scratch)
+		}
+
+		e.scratch = protowire.AppendTag(e.scratch[:0], dataStreamsKafkaAggregationsField, protowire.BytesType)
+		e.scratch = protowire.AppendVarint(e.scratch, uint64(e.aggregationBuf.Len()))
+		w.Write(e.scratch)
+		w.Write(e.aggregationBuf.Bytes())
 	}
 	return staticTags
 }
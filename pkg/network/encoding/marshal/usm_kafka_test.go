@@ -16,10 +16,12 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	model "github.com/DataDog/agent-payload/v5/process"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"github.com/DataDog/datadog-agent/pkg/network"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/kafka"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/intern"
 )
 
 func skipIfNotLinux(t *testing.T) {
@@ -128,6 +130,74 @@ func (s *KafkaSuite) TestFormatKafkaStats() {
 	assert.ElementsMatch(t, out.KafkaAggregations, aggregations.KafkaAggregations)
 }
 
+func (s *KafkaSuite) TestFormatKafkaClientID() {
+	t := s.T()
+
+	interner := intern.NewStringInterner()
+	in := map[kafka.Key]*kafka.RequestStats{}
+	for _, clientID := range []string{"producer-1", "producer-2", ""} {
+		key := kafka.NewKey(localhost, localhost, clientPort, serverPort, topicName, kafka.ProduceAPIKey, apiVersion1)
+		if clientID != "" {
+			key.ClientID = interner.GetString(clientID)
+		}
+		in[key] = &kafka.RequestStats{ErrorCodeToStat: map[int32]*kafka.RequestStat{0: {Count: 3}}}
+	}
+
+	encoder := newKafkaEncoder(in)
+	t.Cleanup(encoder.Close)
+
+	streamer := NewProtoTestStreamer[*model.Connection]()
+	encoder.WriteKafkaAggregations(defaultConnection, model.NewConnectionBuilder(streamer))
+	var conn model.Connection
+	streamer.Unwrap(t, &conn)
+	assert.ElementsMatch(t, []string{"producer-1", "producer-2", ""}, decodeKafkaClientIDs(t, conn.DataStreamsAggregations))
+
+	// the aggregations are still decoded by the model, which skips the client ID
+	var aggregations model.DataStreamsAggregations
+	require.NoError(t, proto.Unmarshal(conn.DataStreamsAggregations, &aggregations))
+	require.Len(t, aggregations.KafkaAggregations, 3)
+	for _, aggregation := range aggregations.KafkaAggregations {
+		assert.Equal(t, topicName, aggregation.Topic)
+		assert.Equal(t, uint32(3), aggregation.StatsByErrorCode[0].Count)
+	}
+}
+
+// decodeKafkaClientIDs returns the client ID of each Kafka aggregation, which isn't part of the generated model
+func decodeKafkaClientIDs(t *testing.T, b []byte) []string {
+	var clientIDs []string
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+		if num != dataStreamsKafkaAggregationsField {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			require.GreaterOrEqual(t, n, 0)
+			b = b[n:]
+			continue
+		}
+
+		aggregation, n := protowire.ConsumeBytes(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+
+		clientID := ""
+		for len(aggregation) > 0 {
+			num, typ, n := protowire.ConsumeTag(aggregation)
+			require.GreaterOrEqual(t, n, 0)
+			aggregation = aggregation[n:]
+			if num == kafkaAggregationClientIDField {
+				clientID, n = protowire.ConsumeString(aggregation)
+			} else {
+				n = protowire.ConsumeFieldValue(num, typ, aggregation)
+			}
+			require.GreaterOrEqual(t, n, 0)
+			aggregation = aggregation[n:]
+		}
+		clientIDs = append(clientIDs, clientID)
+	}
+	return clientIDs
+}
+
 func (s *KafkaSuite) TestKafkaIDCollisionRegression() {
 	t := s.T()
 	assert := assert.New(t)
@@ -10,14 +10,29 @@ import (
 	"io"
 
 	model "github.com/DataDog/agent-payload/v5/process"
+	"google.golang.org/protobuf/encoding/protowire"
+
 	"github.com/DataDog/datadog-agent/pkg/network"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/postgres"
 	"github.com/DataDog/datadog-agent/pkg/network/types"
 )
 
+const (
+	// databaseAggregationsField is the number of the aggregations field of the DatabaseAggregations message
+	databaseAggregationsField protowire.Number = 1
+	// databaseStatsPostgresField is the number of the postgres field of the DatabaseStats message
+	databaseStatsPostgresField protowire.Number = 1
+	// postgresStatsErrorCountField is the number of the error count field of the PostgresStats message. The
+	// generated builders don't expose it, so it's appended to the message encoded by the builder.
+	postgresStatsErrorCountField protowire.Number = 6
+)
+
 type postgresEncoder struct {
-	postgresAggregationsBuilder *model.DatabaseAggregationsBuilder
-	byConnection                *USMConnectionIndex[postgres.Key, *postgres.RequestStat]
+	postgresStatsBuilder *model.PostgresStatsBuilder
+	statsBuf             bytes.Buffer
+	aggregationBuf       bytes.Buffer
+	scratch              []byte
+	byConnection         *USMConnectionIndex[postgres.Key, *postgres.RequestStat]
 }
 
 func newPostgresEncoder(postgresPayloads map[postgres.Key]*postgres.RequestStat) *postgresEncoder {
@@ -26,7 +41,7 @@ func newPostgresEncoder(postgresPayloads map[postgres.Key]*postgres.RequestStat)
 	}
 
 	return &postgresEncoder{
-		postgresAggregationsBuilder: model.NewDatabaseAggregationsBuilder(nil),
+		postgresStatsBuilder: model.NewPostgresStatsBuilder(nil),
 		byConnection: GroupByConnection("postgres", postgresPayloads, func(key postgres.Key) types.ConnectionKey {
 			return key.ConnectionKey
 		}),
@@ -52,26 +67,41 @@ func (e *postgresEncoder) WritePostgresAggregations(c network.ConnectionStats, b
 
 func (e *postgresEncoder) encodeData(connectionData *USMConnectionData[postgres.Key, *postgres.RequestStat], w io.Writer) uint64 {
 	var staticTags uint64
-	e.postgresAggregationsBuilder.Reset(w)
 
 	for _, kv := range connectionData.Data {
 		key := kv.Key
 		stats := kv.Value
 		staticTags |= stats.StaticTags
-		e.postgresAggregationsBuilder.AddAggregations(func(builder *model.DatabaseStatsBuilder) {
-			builder.SetPostgres(func(statsBuilder *model.PostgresStatsBuilder) {
-				statsBuilder.SetTableName(key.Parameters)
-				statsBuilder.SetOperation(uint64(toPostgresModelOperation(key.Operation)))
-				if latencies := stats.Latencies; latencies != nil {
-					statsBuilder.SetLatencies(func(b *bytes.Buffer) {
-						latencies.EncodeProto(b)
-					})
-				} else {
-					statsBuilder.SetFirstLatencySample(stats.FirstLatencySample)
-				}
-				statsBuilder.SetCount(uint32(stats.Count))
+
+		e.statsBuf.Reset()
+		e.postgresStatsBuilder.Reset(&e.statsBuf)
+		e.postgresStatsBuilder.SetTableName(key.Parameters)
+		e.postgresStatsBuilder.SetOperation(uint64(toPostgresModelOperation(key.Operation)))
+		if latencies := stats.Latencies; latencies != nil {
+			e.postgresStatsBuilder.SetLatencies(func(b *bytes.Buffer) {
+				latencies.EncodeProto(b)
 			})
-		})
+		} else {
+			e.postgresStatsBuilder.SetFirstLatencySample(stats.FirstLatencySample)
+		}
+		e.postgresStatsBuilder.SetCount(uint32(stats.Count))
+		if stats.ErrorCount > 0 {
+			e.scratch = protowire.AppendTag(e.scratch[:0], postgresStatsErrorCountField, protowire.VarintType)
+			e.scratch = protowire.AppendVarint(e.scratch, uint64(stats.ErrorCount))
+			e.statsBuf.Write(e.scratch)
+		}
+
+		// wrap the stats in a DatabaseStats message, added to the DatabaseAggregations message
+		e.aggregationBuf.Reset()
+		e.scratch = protowire.AppendTag(e.scratch[:0], databaseStatsPostgresField, protowire.BytesType)
+		e.scratch = protowire.AppendVarint(e.scratch, uint64(e.statsBuf.Len()))
+		e.aggregationBuf.Write(e.scratch)
+		e.aggregationBuf.Write(e.statsBuf.Bytes())
+
+		e.scratch = protowire.AppendTag(e.scratch[:0], databaseAggregationsField, protowire.BytesType)
+		e.scratch = protowire.AppendVarint(e.scratch, uint64(e.aggregationBuf.Len()))
+		w.Write(e.scratch)
+		w.Write(e.aggregationBuf.Bytes())
 	}
 
 	return staticTags
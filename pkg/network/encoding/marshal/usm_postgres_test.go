@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	model "github.com/DataDog/agent-payload/v5/process"
 
@@ -361,6 +362,79 @@ func (s *PostgresSuite) TestPostgresLocalhostScenario() {
 	}
 }
 
+func (s *PostgresSuite) TestPostgresErrorCount() {
+	t := s.T()
+
+	selectKey := postgres.NewKey(localhost, localhost, postgresClientPort, postgresServerPort, postgres.SelectOP, tableName)
+	insertKey := postgres.NewKey(localhost, localhost, postgresClientPort, postgresServerPort, postgres.InsertOP, tableName)
+	in := &network.Connections{
+		BufferedData: network.BufferedData{
+			Conns: []network.ConnectionStats{postgresDefaultConnection},
+		},
+		Postgres: map[postgres.Key]*postgres.RequestStat{
+			selectKey: {Count: 10, ErrorCount: 3, FirstLatencySample: 2},
+			insertKey: {Count: 5, FirstLatencySample: 1},
+		},
+	}
+
+	encoder := newPostgresEncoder(in.Postgres)
+	t.Cleanup(encoder.Close)
+
+	streamer := NewProtoTestStreamer[*model.Connection]()
+	encoder.WritePostgresAggregations(postgresDefaultConnection, model.NewConnectionBuilder(streamer))
+	var conn model.Connection
+	streamer.Unwrap(t, &conn)
+	assert.ElementsMatch(t, []uint64{3, 0}, decodePostgresErrorCounts(t, conn.DatabaseAggregations))
+
+	// the aggregations are still decoded by the model, which skips the error count
+	var aggregations model.DatabaseAggregations
+	require.NoError(t, proto.Unmarshal(conn.DatabaseAggregations, &aggregations))
+	require.Len(t, aggregations.Aggregations, 2)
+	for _, aggregation := range aggregations.Aggregations {
+		assert.Equal(t, tableName, aggregation.GetPostgres().GetTableName())
+	}
+}
+
+// decodePostgresErrorCounts returns the error count of each Postgres aggregation, which isn't part of the generated model
+func decodePostgresErrorCounts(t *testing.T, b []byte) []uint64 {
+	var errorCounts []uint64
+	for len(b) > 0 {
+		aggregation := consumeMessageField(t, &b, databaseAggregationsField)
+		stats := consumeMessageField(t, &aggregation, databaseStatsPostgresField)
+		require.Empty(t, aggregation)
+
+		errorCount := uint64(0)
+		for len(stats) > 0 {
+			num, typ, n := protowire.ConsumeTag(stats)
+			require.GreaterOrEqual(t, n, 0)
+			stats = stats[n:]
+			if num == postgresStatsErrorCountField {
+				errorCount, n = protowire.ConsumeVarint(stats)
+			} else {
+				n = protowire.ConsumeFieldValue(num, typ, stats)
+			}
+			require.GreaterOrEqual(t, n, 0)
+			stats = stats[n:]
+		}
+		errorCounts = append(errorCounts, errorCount)
+	}
+	return errorCounts
+}
+
+// consumeMessageField consumes the next field of b, which must be the message field num, and returns its content
+func consumeMessageField(t *testing.T, b *[]byte, num protowire.Number) []byte {
+	fieldNum, typ, n := protowire.ConsumeTag(*b)
+	require.GreaterOrEqual(t, n, 0)
+	require.Equal(t, num, fieldNum)
+	require.Equal(t, protowire.BytesType, typ)
+	*b = (*b)[n:]
+
+	content, n := protowire.ConsumeBytes(*b)
+	require.GreaterOrEqual(t, n, 0)
+	*b = (*b)[n:]
+	return content
+}
+
 func getPostgresAggregations(t *testing.T, encoder *postgresEncoder, c network.ConnectionStats) *model.DatabaseAggregations {
 	streamer := NewProtoTestStreamer[*model.Connection]()
 	encoder.WritePostgresAggregations(c, model.NewConnectionBuilder(streamer))
@@ -19,6 +19,7 @@ type RequestSummary struct {
 	Server    Address
 	Operation string
 	TopicName string
+	ClientID  string
 	ByStatus  map[int8]Stats
 }
 
@@ -50,6 +51,11 @@ func Kafka(stats map[kafka.Key]*kafka.RequestStats) []RequestSummary {
 			operationName = "fetch"
 		}
 
+		clientID := ""
+		if key.ClientID != nil {
+			clientID = key.ClientID.Get()
+		}
+
 		debug := RequestSummary{
 			Client: Address{
 				IP:   clientAddr.String(),
@@ -62,6 +68,7 @@ func Kafka(stats map[kafka.Key]*kafka.RequestStats) []RequestSummary {
 
 			Operation: operationName,
 			TopicName: key.TopicName.Get(),
+			ClientID:  clientID,
 			ByStatus:  make(map[int8]Stats, len(requestStat.ErrorCodeToStat)),
 		}
 
@@ -25,6 +25,9 @@ type StatKeeper struct {
 	// topicNames stores interned versions of the all topics currently stored in
 	// the `StatKeeper`
 	topicNames *intern.StringInterner
+	// clientIDs stores interned versions of the all client IDs currently stored
+	// in the `StatKeeper`
+	clientIDs *intern.StringInterner
 }
 
 // NewStatkeeper creates a new StatKeeper
@@ -34,6 +37,7 @@ func NewStatkeeper(c *config.Config, telemetry *Telemetry) *StatKeeper {
 		maxEntries: c.MaxKafkaStatsBuffered,
 		telemetry:  telemetry,
 		topicNames: intern.NewStringInterner(),
+		clientIDs:  intern.NewStringInterner(),
 	}
 }
 
@@ -52,6 +56,7 @@ func (statKeeper *StatKeeper) Process(tx *EbpfTx) {
 		RequestAPIKey:  tx.APIKey(),
 		RequestVersion: tx.APIVersion(),
 		TopicName:      statKeeper.extractTopicName(&tx.Transaction),
+		ClientID:       statKeeper.extractClientID(&tx.Transaction),
 		ConnectionKey:  tx.ConnTuple(),
 	}
 
@@ -89,3 +94,15 @@ func (statKeeper *StatKeeper) extractTopicName(tx *KafkaTransaction) *intern.Str
 
 	return statKeeper.topicNames.Get(b)
 }
+
+func (statKeeper *StatKeeper) extractClientID(tx *KafkaTransaction) *intern.StringValue {
+	if tx.Client_id_size == 0 {
+		return nil
+	}
+	// Limit tx.Client_id_size to not exceed the actual length of tx.Client_id
+	if int(tx.Client_id_size) > len(tx.Client_id) {
+		tx.Client_id_size = uint8(len(tx.Client_id))
+	}
+
+	return statKeeper.clientIDs.Get(tx.Client_id[:tx.Client_id_size])
+}
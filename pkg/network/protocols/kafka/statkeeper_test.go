@@ -77,6 +77,52 @@ func TestStatKeeper_extractTopicName(t *testing.T) {
 	}
 }
 
+func TestStatKeeper_extractClientID(t *testing.T) {
+	statKeeper := &StatKeeper{
+		clientIDs: intern.NewStringInterner(),
+	}
+
+	tx := &KafkaTransaction{}
+	assert.Nil(t, statKeeper.extractClientID(tx))
+
+	copy(tx.Client_id[:], "consumer-1")
+	tx.Client_id_size = uint8(len("consumer-1"))
+	assert.Equal(t, "consumer-1", statKeeper.extractClientID(tx).Get())
+
+	copy(tx.Client_id[:], strings.Repeat("*", len(tx.Client_id)))
+	tx.Client_id_size = 200
+	assert.Equal(t, strings.Repeat("*", len(tx.Client_id)), statKeeper.extractClientID(tx).Get())
+}
+
+func TestProcessKafkaTransactionsByClientID(t *testing.T) {
+	cfg := &config.Config{MaxKafkaStatsBuffered: 1000}
+	tel := NewTelemetry()
+	sk := NewStatkeeper(cfg, tel)
+
+	sourceIP := util.AddressFromString("1.1.1.1")
+	destIP := util.AddressFromString("2.2.2.2")
+	for i, clientID := range []string{"producer-1", "producer-2", ""} {
+		for j := 0; j < 5; j++ {
+			tx := generateKafkaTransaction(sourceIP, destIP, 1234+i, 9092, "test-topic", 0, uint32(10), time.Millisecond)
+			copy(tx.Transaction.Client_id[:], clientID)
+			tx.Transaction.Client_id_size = uint8(len(clientID))
+			sk.Process(tx)
+		}
+	}
+
+	stats := sk.GetAndResetAllStats()
+	require.Len(t, stats, 3)
+	clientIDs := make(map[string]int)
+	for key, stats := range stats {
+		clientID := ""
+		if key.ClientID != nil {
+			clientID = key.ClientID.Get()
+		}
+		clientIDs[clientID] = stats.ErrorCodeToStat[0].Count
+	}
+	assert.Equal(t, map[string]int{"producer-1": 50, "producer-2": 50, "": 50}, clientIDs)
+}
+
 func TestProcessKafkaTransactions(t *testing.T) {
 	cfg := &config.Config{MaxKafkaStatsBuffered: 1000}
 	tel := NewTelemetry()
@@ -29,6 +29,9 @@ type Key struct {
 	RequestAPIKey  uint16
 	RequestVersion uint16
 	TopicName      *intern.StringValue
+	// ClientID is the client ID of the producer or consumer, it is nil when the
+	// client didn't set one
+	ClientID *intern.StringValue
 	types.ConnectionKey
 }
 
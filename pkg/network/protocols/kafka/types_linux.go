@@ -42,7 +42,8 @@ type KafkaTransaction struct {
 	Tags                uint8
 	Topic_name          [80]byte
 	Error_code          int8
-	Pad_cgo_0           [7]byte
+	Client_id_size      uint8
+	Client_id           [30]byte
 }
 
 type KafkaResponseContext struct {
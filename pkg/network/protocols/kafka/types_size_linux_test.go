@@ -0,0 +1,26 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package kafka
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTypesSizeMatchKernel ensures the generated types keep the size of their
+// pkg/network/ebpf/c/protocols/kafka/types.h counterparts, as the eBPF maps and
+// the perf events are decoded into them. types_linux.go must be regenerated
+// with cgo -godefs whenever types.h changes.
+func TestTypesSizeMatchKernel(t *testing.T) {
+	assert.EqualValues(t, 56, unsafe.Sizeof(KafkaTransactionKey{}), "kafka_transaction_key_t")
+	assert.EqualValues(t, 136, unsafe.Sizeof(KafkaTransaction{}), "kafka_transaction_t")
+	assert.EqualValues(t, 184, unsafe.Sizeof(EbpfTx{}), "kafka_event_t")
+	assert.EqualValues(t, 184, unsafe.Sizeof(KafkaResponseContext{}), "kafka_response_context_t")
+}
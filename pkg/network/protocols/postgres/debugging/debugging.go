@@ -30,6 +30,7 @@ type key struct {
 // Stats consolidates request count and latency information for a certain status code
 type Stats struct {
 	Count              int
+	ErrorCount         int
 	FirstLatencySample float64
 	LatencyP50         float64
 	latencies          *ddsketch.DDSketch
@@ -68,6 +69,7 @@ func Postgres(stats map[postgres.Key]*postgres.RequestStat) []RequestSummary {
 		}
 		currentStats := resMap[tempKey][k.Operation.String()]
 		currentStats.Count += requestStat.Count
+		currentStats.ErrorCount += requestStat.ErrorCount
 		if currentStats.FirstLatencySample == 0 {
 			currentStats.FirstLatencySample = requestStat.FirstLatencySample
 		}
@@ -140,16 +140,22 @@ func (e *EventWrapper) RequestLatency() float64 {
 	return protocols.NSTimestampToFloat(e.Tx.Response_last_seen - e.Tx.Request_started)
 }
 
+// IsError returns true if the server replied to the request with an error response
+func (e *EventWrapper) IsError() bool {
+	return e.Tx.Is_error != 0
+}
+
 const template = `
 ebpfTx{
 	Operation: %q,
 	Table Name: %q,
-	Latency: %f
+	Latency: %f,
+	Error: %t
 }`
 
 // String returns a string representation of the underlying event
 func (e *EventWrapper) String() string {
 	var output strings.Builder
-	output.WriteString(fmt.Sprintf(template, e.Operation(), e.Parameters(), e.RequestLatency()))
+	output.WriteString(fmt.Sprintf(template, e.Operation(), e.Parameters(), e.RequestLatency(), e.IsError()))
 	return output.String()
 }
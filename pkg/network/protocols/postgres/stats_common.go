@@ -38,6 +38,7 @@ type RequestStat struct {
 	Latencies          *ddsketch.DDSketch
 	FirstLatencySample float64
 	Count              int
+	ErrorCount         int
 	StaticTags         uint64
 }
 
@@ -45,6 +46,7 @@ type RequestStat struct {
 // newStats is kept as it is, while the method receiver gets mutated
 func (r *RequestStat) CombineWith(newStats *RequestStat) {
 	r.Count += newStats.Count
+	r.ErrorCount += newStats.ErrorCount
 	r.StaticTags |= newStats.StaticTags
 	// If the receiver has no latency sample, use the newStats sample
 	if r.FirstLatencySample == 0 {
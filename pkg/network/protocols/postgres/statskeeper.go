@@ -50,6 +50,9 @@ func (s *StatKeeper) Process(tx *EventWrapper) {
 	}
 	requestStats.StaticTags = uint64(tx.Tx.Tags)
 	requestStats.Count++
+	if tx.IsError() {
+		requestStats.ErrorCount++
+	}
 	if requestStats.Count == 1 {
 		requestStats.FirstLatencySample = tx.RequestLatency()
 		return
@@ -43,3 +43,30 @@ func TestStatKeeperProcess(t *testing.T) {
 		require.Equal(t, float64(20), stat.Latencies.GetCount())
 	}
 }
+
+func TestStatKeeperProcessErrors(t *testing.T) {
+	cfg := config.New()
+	cfg.MaxPostgresStatsBuffered = 100
+	s := NewStatkeeper(cfg)
+	for i := 0; i < 10; i++ {
+		s.Process(&EventWrapper{
+			EbpfEvent: &ebpf.EbpfEvent{
+				Tx: ebpf.EbpfTx{
+					Request_started:    1,
+					Response_last_seen: 10,
+					Is_error:           uint8(i % 2),
+				},
+			},
+			operationSet:  true,
+			operation:     InsertOP,
+			parametersSet: true,
+			parameters:    "dummy",
+		})
+	}
+
+	require.Equal(t, 1, len(s.stats))
+	for _, stat := range s.stats {
+		require.Equal(t, 10, stat.Count)
+		require.Equal(t, 5, stat.ErrorCount)
+	}
+}
@@ -1334,6 +1334,14 @@ func (a *connectionAggregator) dns(c *ConnectionStats) map[dns.Hostname]map[dns.
 		return stats
 	}
 
+	// the stats of the queries whose client socket isn't known, like the ones
+	// reported by ETW on Windows, go to the first DNS connection to their server
+	key.ClientIP, key.ClientPort = util.Address{}, 0
+	if stats, ok := a.dnsStats[key]; ok {
+		delete(a.dnsStats, key)
+		return stats
+	}
+
 	return nil
 }
 
@@ -2877,6 +2877,44 @@ func TestDNSPIDCollision(t *testing.T) {
 	assert.Empty(t, delta.Conns[1].DNSStats, "dns stats should not be empty")
 }
 
+func TestDNSStatsWithoutClientSocket(t *testing.T) {
+	conns := []ConnectionStats{
+		{ConnectionTuple: ConnectionTuple{
+			Source:    util.AddressFromString("10.1.1.1"),
+			Dest:      util.AddressFromString("8.8.8.8"),
+			Pid:       1,
+			SPort:     1000,
+			DPort:     53,
+			Type:      UDP,
+			Family:    AFINET,
+			Direction: OUTGOING,
+		},
+			Cookie: 1,
+			Monotonic: StatCounters{
+				RecvBytes: 2,
+			},
+		},
+	}
+
+	// the client socket of the queries reported by ETW on Windows isn't known
+	dnsStats := dns.StatsByKeyByNameByType{
+		dns.Key{
+			ServerIP: util.AddressFromString("8.8.8.8"),
+			Protocol: syscall.IPPROTO_UDP,
+		}: map[dns.Hostname]map[dns.QueryType]dns.Stats{
+			dns.ToHostname("foo.com"): {
+				dns.TypeA: {CountByRcode: map[uint32]uint32{0: 1}},
+			},
+		},
+	}
+
+	state := newDefaultState()
+	state.RegisterClient("foo")
+	delta := state.GetDelta("foo", 0, conns, dnsStats, nil)
+	require.Len(t, delta.Conns, 1)
+	assert.EqualValues(t, 1, delta.Conns[0].DNSStats[dns.ToHostname("foo.com")][dns.TypeA].CountByRcode[0])
+}
+
 func generateRandConnections(n int) []ConnectionStats {
 	cs := make([]ConnectionStats, 0, n)
 	for i := 0; i < n; i++ {
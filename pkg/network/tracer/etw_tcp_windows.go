@@ -0,0 +1,202 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build windows && npm
+
+package tracer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/DataDog/datadog-agent/comp/etw"
+	etwimpl "github.com/DataDog/datadog-agent/comp/etw/impl"
+	"github.com/DataDog/datadog-agent/pkg/network"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	etwTCPSessionName = "SystemProbeNPM_TCP"
+
+	// Microsoft-Windows-Kernel-Network {7dd42a49-5329-4832-8dfd-43d979153a88}
+	//     https://github.com/repnz/etw-providers-docs/blob/master/Manifests-Win10-18990/Microsoft-Windows-Kernel-Network.xml
+	etwKernelNetworkProviderGUID = "{7dd42a49-5329-4832-8dfd-43d979153a88}"
+
+	etwKernelNetworkKeywordIPv4 = 0x10
+	etwKernelNetworkKeywordIPv6 = 0x20
+
+	etwTCPv4Retransmit = 14
+	etwTCPv6Retransmit = 30
+
+	// retransmit counters which haven't been updated for this long are dropped
+	etwTCPEntryTTL = 2 * time.Minute
+
+	etwTCPModuleName = "network_tracer__etw_tcp"
+)
+
+var etwTCPTelemetry = struct {
+	retransmits *telemetry.StatCounterWrapper
+	tracked     *telemetry.StatGaugeWrapper
+}{
+	telemetry.NewStatCounterWrapper(etwTCPModuleName, "retransmits", []string{"ip_proto"}, "Counter measuring the number of TCP retransmit events received from ETW"),
+	telemetry.NewStatGaugeWrapper(etwTCPModuleName, "tracked_connections", []string{}, "Gauge measuring the number of TCP connections with ETW retransmit counters"),
+}
+
+// etwTCPKey identifies a connection by its tuple. The PID of the Kernel-Network events is the one of the
+// thread the packet was sent from, which is often not the owner of the connection, e.g. for the retransmits
+// sent from a timer, so it is not part of the key.
+type etwTCPKey struct {
+	local  util.Address
+	remote util.Address
+	lport  uint16
+	rport  uint16
+}
+
+type etwTCPEntry struct {
+	retransmits uint32
+	lastSeen    time.Time
+}
+
+// etwTCPMonitor accounts for TCP retransmits reported by the Microsoft-Windows-Kernel-Network ETW provider.
+// The counters are only used for flows for which the driver does not report any retransmit, bringing the
+// TCP metrics of those flows on par with the ones collected by the eBPF tracer on Linux.
+type etwTCPMonitor struct {
+	session etw.Session
+	guid    windows.GUID
+	wg      sync.WaitGroup
+
+	mu    sync.Mutex
+	conns map[etwTCPKey]*etwTCPEntry
+}
+
+func newETWTCPMonitor() (*etwTCPMonitor, error) {
+	etwcomp, err := etwimpl.NewEtw()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &etwTCPMonitor{conns: make(map[etwTCPKey]*etwTCPEntry)}
+	m.session, err = etwcomp.NewSession(etwTCPSessionName, func(_ *etw.SessionConfiguration) {})
+	if err != nil {
+		return nil, err
+	}
+
+	m.guid, err = windows.GUIDFromString(etwKernelNetworkProviderGUID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating GUID for Kernel-Network ETW provider: %w", err)
+	}
+	m.session.ConfigureProvider(m.guid, func(cfg *etw.ProviderConfiguration) {
+		cfg.TraceLevel = etw.TRACE_LEVEL_INFORMATION
+		cfg.MatchAnyKeyword = etwKernelNetworkKeywordIPv4 | etwKernelNetworkKeywordIPv6
+		cfg.EnabledIDs = []uint16{etwTCPv4Retransmit, etwTCPv6Retransmit}
+	})
+	if err := m.session.EnableProvider(m.guid); err != nil {
+		_ = m.session.StopTracing()
+		return nil, fmt.Errorf("error enabling Kernel-Network ETW provider: %w", err)
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		// StartTracing blocks until StopTracing is called
+		if err := m.session.StartTracing(m.onEvent); err != nil {
+			log.Errorf("ETW Kernel-Network subscription failed with error %v", err)
+			return
+		}
+		log.Infof("ETW Kernel-Network subscription completed")
+	}()
+	return m, nil
+}
+
+func (m *etwTCPMonitor) onEvent(e *etw.DDEventRecord) {
+	var (
+		key  etwTCPKey
+		ok   bool
+		data = etwimpl.GetUserData(e)
+	)
+	switch e.EventHeader.EventDescriptor.ID {
+	case etwTCPv4Retransmit:
+		key, ok = parseTCPRetransmitEvent(data.Bytes(0, data.Length()), 4)
+		etwTCPTelemetry.retransmits.Inc("ipv4")
+	case etwTCPv6Retransmit:
+		key, ok = parseTCPRetransmitEvent(data.Bytes(0, data.Length()), 16)
+		etwTCPTelemetry.retransmits.Inc("ipv6")
+	}
+	if !ok {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, found := m.conns[key]
+	if !found {
+		entry = &etwTCPEntry{}
+		m.conns[key] = entry
+	}
+	entry.retransmits++
+	entry.lastSeen = time.Now()
+}
+
+// parseTCPRetransmitEvent decodes the leading fields shared by the Kernel-Network TCP events:
+// PID (uint32), size (uint32), daddr, saddr, dport (network order), sport (network order).
+// The PID and the size are skipped.
+func parseTCPRetransmitEvent(data []byte, addrLen int) (etwTCPKey, bool) {
+	if len(data) < 8+2*addrLen+4 {
+		return etwTCPKey{}, false
+	}
+	remote, _ := netip.AddrFromSlice(data[8 : 8+addrLen])
+	local, _ := netip.AddrFromSlice(data[8+addrLen : 8+2*addrLen])
+	ports := data[8+2*addrLen:]
+	return etwTCPKey{
+		remote: util.Address{Addr: remote},
+		local:  util.Address{Addr: local},
+		rport:  binary.BigEndian.Uint16(ports[0:2]),
+		lport:  binary.BigEndian.Uint16(ports[2:4]),
+	}, true
+}
+
+// Enrich sets the retransmit count of the TCP connections for which the driver didn't report any
+func (m *etwTCPMonitor) Enrich(conns []network.ConnectionStats) {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range conns {
+		c := &conns[i]
+		if c.Type != network.TCP || c.Monotonic.Retransmits != 0 {
+			continue
+		}
+		key := etwTCPKey{local: c.Source, remote: c.Dest, lport: c.SPort, rport: c.DPort}
+		if entry, ok := m.conns[key]; ok {
+			c.Monotonic.Retransmits = entry.retransmits
+			// keep the counter alive as long as the connection is reported
+			entry.lastSeen = now
+		}
+	}
+}
+
+// Expire drops the counters which were neither updated nor reported for the last etwTCPEntryTTL
+func (m *etwTCPMonitor) Expire(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, entry := range m.conns {
+		if now.Sub(entry.lastSeen) > etwTCPEntryTTL {
+			delete(m.conns, key)
+		}
+	}
+	etwTCPTelemetry.tracked.Set(int64(len(m.conns)))
+}
+
+// Stop stops the ETW session and waits for the event loop to exit
+func (m *etwTCPMonitor) Stop() {
+	_ = m.session.StopTracing()
+	m.wg.Wait()
+}
@@ -0,0 +1,56 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build windows && npm
+
+package tracer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/network"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+)
+
+func TestETWTCPRetransmits(t *testing.T) {
+	// PID 42, size 0, daddr 10.0.0.2, saddr 10.0.0.1, dport 443, sport 50000
+	event := []byte{
+		42, 0, 0, 0,
+		0, 0, 0, 0,
+		10, 0, 0, 2,
+		10, 0, 0, 1,
+		0x01, 0xbb,
+		0xc3, 0x50,
+	}
+	key, ok := parseTCPRetransmitEvent(event, 4)
+	require.True(t, ok)
+	assert.Equal(t, util.AddressFromString("10.0.0.1"), key.local)
+	assert.Equal(t, util.AddressFromString("10.0.0.2"), key.remote)
+	assert.Equal(t, uint16(50000), key.lport)
+	assert.Equal(t, uint16(443), key.rport)
+
+	_, ok = parseTCPRetransmitEvent(event[:10], 4)
+	assert.False(t, ok)
+
+	m := &etwTCPMonitor{conns: map[etwTCPKey]*etwTCPEntry{
+		key: {retransmits: 3, lastSeen: time.Now()},
+	}}
+	// the counters are matched by tuple, whatever the PID of the event
+	conns := []network.ConnectionStats{
+		{ConnectionTuple: network.ConnectionTuple{Type: network.TCP, Source: key.local, Dest: key.remote, SPort: key.lport, DPort: key.rport, Pid: 1234}},
+		{ConnectionTuple: network.ConnectionTuple{Type: network.TCP, Source: key.local, Dest: key.remote, SPort: key.lport, DPort: key.rport, Pid: 7}},
+	}
+	conns[1].Monotonic.Retransmits = 7
+	m.Enrich(conns)
+	assert.Equal(t, uint32(3), conns[0].Monotonic.Retransmits)
+	assert.Equal(t, uint32(7), conns[1].Monotonic.Retransmits, "driver counters take precedence")
+
+	m.Expire(time.Now().Add(etwTCPEntryTTL + time.Second))
+	assert.Empty(t, m.conns)
+}
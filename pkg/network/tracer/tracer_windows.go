@@ -49,6 +49,7 @@ type Tracer struct {
 	state           network.State
 	reverseDNS      dns.ReverseDNS
 	usmMonitor      usm.Monitor
+	etwTCPMonitor   *etwTCPMonitor
 
 	closedBuffer *network.ConnectionBuffer
 	connLock     sync.Mutex
@@ -121,6 +122,11 @@ func NewTracer(config *config.Config, telemetry telemetry.Component, _ statsd.Cl
 		destExcludes:         network.ParseConnectionFilters(config.ExcludedDestinationConnections),
 		hStopClosedLoopEvent: stopEvent,
 	}
+	if config.EnableETWTCP {
+		if tr.etwTCPMonitor, err = newETWTCPMonitor(); err != nil {
+			log.Warnf("could not start ETW Kernel-Network monitor, TCP retransmits will only be reported by the driver: %s", err)
+		}
+	}
 	if config.EnableProcessEventMonitoring {
 		if tr.processCache, err = newProcessCache(config.MaxProcessesTracked); err != nil {
 			return nil, fmt.Errorf("could not create process cache; %w", err)
@@ -158,6 +164,9 @@ func NewTracer(config *config.Config, telemetry telemetry.Component, _ statsd.Cl
 					return !tr.shouldSkipConnection(c)
 				})
 				closedConnStats := tr.closedBuffer.Connections()
+				if tr.etwTCPMonitor != nil {
+					tr.etwTCPMonitor.Enrich(closedConnStats)
+				}
 
 				for i := range closedConnStats {
 					tr.addProcessInfo(&closedConnStats[i])
@@ -183,6 +192,9 @@ func (t *Tracer) Stop() {
 		_ = t.usmMonitor.Stop()
 	}
 	t.reverseDNS.Close()
+	if t.etwTCPMonitor != nil {
+		t.etwTCPMonitor.Stop()
+	}
 
 	windows.SetEvent(t.hStopClosedLoopEvent)
 	t.closedEventLoop.Wait()
@@ -227,6 +239,11 @@ func (t *Tracer) GetActiveConnections(clientID string) (*network.Connections, fu
 	for i := range activeConnStats {
 		t.addProcessInfo(&activeConnStats[i])
 	}
+	if t.etwTCPMonitor != nil {
+		t.etwTCPMonitor.Enrich(activeConnStats)
+		t.etwTCPMonitor.Enrich(closedConnStats)
+		t.etwTCPMonitor.Expire(time.Now())
+	}
 	for i := range closedConnStats {
 		t.addProcessInfo(&closedConnStats[i])
 		t.state.StoreClosedConnection(&closedConnStats[i])
@@ -0,0 +1,165 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package profiledefinition
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ProfileDefinitionMap maps profile names (without the `.yaml` extension) to their definition
+type ProfileDefinitionMap map[string]*ProfileDefinition
+
+// ResolveExtends returns a copy of the profile `name` with the profiles it
+// extends merged in, recursively (see ExpandBaseProfiles). This allows layering
+// profiles: base -> vendor -> site override. The metrics of all the layers are
+// collected, FindMetricConflicts reports the ones defined with different OIDs.
+func ResolveExtends(name string, profiles ProfileDefinitionMap, defaultProfiles ProfileDefinitionMap) (*ProfileDefinition, error) {
+	profile, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile does not exist: `%s`", name)
+	}
+
+	resolved := profile.Clone()
+	if err := ExpandBaseProfiles(name, resolved, profile.Extends, []string{}, profiles, defaultProfiles); err != nil {
+		return nil, err
+	}
+	resolved.Extends = nil
+	return resolved, nil
+}
+
+// ExpandBaseProfiles merges the profiles listed in `extends` into `definition`,
+// recursively. Profiles are looked up in `profiles`, then in `defaultProfiles`.
+func ExpandBaseProfiles(parentExtend string, definition *ProfileDefinition, extends []string, extendsHistory []string, profiles ProfileDefinitionMap, defaultProfiles ProfileDefinitionMap) error {
+	for _, extendEntry := range extends {
+		extendEntry = strings.TrimSuffix(extendEntry, ".yaml")
+
+		var baseDefinition *ProfileDefinition
+		// User profile can extend default profile by extending the default profile.
+		// If the extend entry has the same name as the profile name, we assume the extend entry is referring to a default profile.
+		if extendEntry == parentExtend {
+			profile, ok := defaultProfiles[extendEntry]
+			if !ok {
+				return fmt.Errorf("extend does not exist: `%s`", extendEntry)
+			}
+			baseDefinition = profile
+		} else {
+			profile, ok := profiles[extendEntry]
+			if !ok {
+				profile, ok = defaultProfiles[extendEntry]
+				if !ok {
+					return fmt.Errorf("extend does not exist: `%s`", extendEntry)
+				}
+			}
+			baseDefinition = profile
+		}
+		if slices.Contains(extendsHistory, extendEntry) {
+			return fmt.Errorf("cyclic profile extend detected, `%s` has already been extended, extendsHistory=`%v`", extendEntry, extendsHistory)
+		}
+
+		MergeProfileDefinition(definition, baseDefinition)
+
+		newExtendsHistory := append(slices.Clone(extendsHistory), extendEntry)
+		err := ExpandBaseProfiles(extendEntry, definition, baseDefinition.Extends, newExtendsHistory, profiles, defaultProfiles)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MergeProfileDefinition merges the definitions of a profile extended by the
+// target profile into it. The metadata fields of the target take precedence.
+func MergeProfileDefinition(targetDefinition *ProfileDefinition, baseDefinition *ProfileDefinition) {
+	targetDefinition.Metrics = append(targetDefinition.Metrics, baseDefinition.Metrics...)
+	targetDefinition.MetricTags = append(targetDefinition.MetricTags, baseDefinition.MetricTags...)
+	targetDefinition.StaticTags = append(targetDefinition.StaticTags, baseDefinition.StaticTags...)
+	if targetDefinition.Metadata == nil {
+		targetDefinition.Metadata = make(MetadataConfig)
+	}
+	for baseResName, baseResource := range baseDefinition.Metadata {
+		if _, ok := targetDefinition.Metadata[baseResName]; !ok {
+			targetDefinition.Metadata[baseResName] = NewMetadataResourceConfig()
+		}
+		if resource, ok := targetDefinition.Metadata[baseResName]; ok {
+			for _, tagConfig := range baseResource.IDTags {
+				resource.IDTags = append(targetDefinition.Metadata[baseResName].IDTags, tagConfig)
+			}
+
+			if resource.Fields == nil {
+				resource.Fields = make(map[string]MetadataField, len(baseResource.Fields))
+			}
+			for field, symbol := range baseResource.Fields {
+				if _, ok := resource.Fields[field]; !ok {
+					resource.Fields[field] = symbol
+				}
+			}
+
+			targetDefinition.Metadata[baseResName] = resource
+		}
+	}
+}
+
+// symbolNames returns the names of the metrics collected by a metric definition
+func (m *MetricsConfig) symbolNames() []string {
+	if m.IsColumn() {
+		names := make([]string, 0, len(m.Symbols))
+		for _, symbol := range m.Symbols {
+			names = append(names, symbol.Name)
+		}
+		return names
+	}
+	// legacy scalar syntax, metrics are merged before being normalized
+	if m.Symbol.Name == "" && m.Name != "" {
+		return []string{m.Name}
+	}
+	if m.Symbol.Name != "" {
+		return []string{m.Symbol.Name}
+	}
+	return nil
+}
+
+// symbolOID returns the OID of the symbol `name` collected by a metric definition
+func (m *MetricsConfig) symbolOID(name string) string {
+	if m.IsColumn() {
+		for _, symbol := range m.Symbols {
+			if symbol.Name == name {
+				return symbol.OID
+			}
+		}
+		return ""
+	}
+	if m.Symbol.OID == "" {
+		return m.OID
+	}
+	return m.Symbol.OID
+}
+
+// FindMetricConflicts returns the metrics collected by several definitions of
+// a profile with different OIDs, which are very likely mistakes made while
+// layering profiles.
+func FindMetricConflicts(metrics []MetricsConfig) []string {
+	oidsByName := make(map[string][]string)
+	for i := range metrics {
+		for _, name := range metrics[i].symbolNames() {
+			oid := metrics[i].symbolOID(name)
+			if !slices.Contains(oidsByName[name], oid) {
+				oidsByName[name] = append(oidsByName[name], oid)
+			}
+		}
+	}
+
+	var conflicts []string
+	for name, oids := range oidsByName {
+		if len(oids) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf("metric `%s` is collected from several OIDs: %s", name, strings.Join(oids, ", ")))
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package profiledefinition
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scalarMetric(oid, name string) MetricsConfig {
+	return MetricsConfig{Symbol: SymbolConfig{OID: oid, Name: name}}
+}
+
+func TestResolveExtends(t *testing.T) {
+	defaultProfiles := ProfileDefinitionMap{
+		"_base": {
+			Metrics:    []MetricsConfig{scalarMetric("1.1", "sysUpTime"), scalarMetric("1.2", "cpu")},
+			StaticTags: []string{"layer:base"},
+		},
+		"vendor": {
+			Extends:      []string{"_base.yaml"},
+			SysObjectIDs: StringArray{"1.3.6.1.4.1.9.*"},
+			Metrics:      []MetricsConfig{scalarMetric("1.3", "cpu"), scalarMetric("1.4", "memory")},
+		},
+	}
+	profiles := ProfileDefinitionMap{
+		"_base": defaultProfiles["_base"],
+		"vendor": {
+			Extends: []string{"vendor.yaml"},
+			Metrics: []MetricsConfig{scalarMetric("1.5", "memory")},
+		},
+	}
+
+	resolved, err := ResolveExtends("vendor", profiles, defaultProfiles)
+	require.NoError(t, err)
+	assert.Empty(t, resolved.Extends)
+	// the metrics of all the layers are collected
+	assert.Equal(t, []MetricsConfig{
+		scalarMetric("1.5", "memory"),
+		scalarMetric("1.3", "cpu"),
+		scalarMetric("1.4", "memory"),
+		scalarMetric("1.1", "sysUpTime"),
+		scalarMetric("1.2", "cpu"),
+	}, resolved.Metrics)
+	assert.Equal(t, []string{
+		"metric `cpu` is collected from several OIDs: 1.3, 1.2",
+		"metric `memory` is collected from several OIDs: 1.5, 1.4",
+	}, FindMetricConflicts(resolved.Metrics))
+	assert.Equal(t, []string{"layer:base"}, resolved.StaticTags)
+	// the profiles are left untouched
+	assert.Equal(t, []MetricsConfig{scalarMetric("1.5", "memory")}, profiles["vendor"].Metrics)
+
+	_, err = ResolveExtends("unknown", profiles, defaultProfiles)
+	assert.Error(t, err)
+
+	profiles["cyclic"] = &ProfileDefinition{Extends: []string{"other"}}
+	profiles["other"] = &ProfileDefinition{Extends: []string{"cyclic"}}
+	_, err = ResolveExtends("cyclic", profiles, defaultProfiles)
+	assert.ErrorContains(t, err, "cyclic profile extend detected")
+
+	profiles["missing"] = &ProfileDefinition{Extends: []string{"_missing"}}
+	_, err = ResolveExtends("missing", profiles, defaultProfiles)
+	assert.ErrorContains(t, err, "extend does not exist: `_missing`")
+}
+
+func TestFindMetricConflicts(t *testing.T) {
+	assert.Empty(t, FindMetricConflicts([]MetricsConfig{
+		scalarMetric("1.1", "metric1"),
+		scalarMetric("1.1", "metric1"),
+		scalarMetric("1.2", "metric2"),
+	}))
+
+	assert.Equal(t, []string{
+		"metric `ifInErrors` is collected from several OIDs: 1.3.6.1.2.1.2.2.1.14, 1.4",
+		"metric `metric1` is collected from several OIDs: 1.1, 1.3",
+	}, FindMetricConflicts([]MetricsConfig{
+		scalarMetric("1.1", "metric1"),
+		scalarMetric("1.3", "metric1"),
+		{
+			Table:   SymbolConfig{OID: "1.3.6.1.2.1.2.2", Name: "ifTable"},
+			Symbols: []SymbolConfig{{OID: "1.3.6.1.2.1.2.2.1.14", Name: "ifInErrors"}},
+		},
+		scalarMetric("1.4", "ifInErrors"),
+	}))
+}
@@ -0,0 +1,180 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Package cmd implements a cobra command for resolving and validating layered profiles.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/networkdevice/profile/profiledefinition"
+)
+
+// rootCmd represents the base command when called without any subcommands
+var rootCmd = &cobra.Command{
+	Use:   "resolve_cmd [PROFILE...]",
+	Short: "Resolve and validate layered profiles.",
+	Long: `resolve_cmd is a tool for resolving and validating layered profiles.
+
+	Profiles are loaded from the default profiles directory, then from the user
+	profiles directory which overrides the default profiles of the same name.
+	Each profile passed in (all the non-abstract profiles by default) is fully
+	resolved, merging in the profiles it extends, and printed. Validation errors
+	and metrics collected from conflicting OIDs are reported, and make the
+	command fail.`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defaultDir, err := cmd.Flags().GetString("default-profiles")
+		if err != nil {
+			return err
+		}
+		userDir, err := cmd.Flags().GetString("user-profiles")
+		if err != nil {
+			return err
+		}
+		useJSON, err := cmd.Flags().GetBool("json")
+		if err != nil {
+			return err
+		}
+		quiet, err := cmd.Flags().GetBool("quiet")
+		if err != nil {
+			return err
+		}
+
+		defaultProfiles, err := LoadProfiles(defaultDir)
+		if err != nil {
+			return err
+		}
+		userProfiles, err := LoadProfiles(userDir)
+		if err != nil {
+			return err
+		}
+		profiles := make(profiledefinition.ProfileDefinitionMap, len(defaultProfiles)+len(userProfiles))
+		for name, profile := range defaultProfiles {
+			profiles[name] = profile
+		}
+		for name, profile := range userProfiles {
+			profiles[name] = profile
+		}
+
+		names := args
+		if len(names) == 0 {
+			for name := range profiles {
+				// abstract profiles are only meant to be extended
+				if !strings.HasPrefix(name, "_") {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+		}
+
+		failed := false
+		for _, name := range names {
+			def, errors := ResolveProfile(strings.TrimSuffix(name, ".yaml"), profiles, defaultProfiles)
+			if len(errors) > 0 {
+				failed = true
+				fmt.Printf("*** %d error(s) in profile %q ***\n", len(errors), name)
+				for _, e := range errors {
+					fmt.Println("  ", e)
+				}
+				fmt.Println()
+				continue
+			}
+			if quiet {
+				continue
+			}
+			if err := PrintProfile(def, name, useJSON); err != nil {
+				return err
+			}
+		}
+		if failed {
+			return fmt.Errorf("some profiles are invalid")
+		}
+		return nil
+	},
+}
+
+// LoadProfiles parses the profiles of a directory, by name. An empty directory
+// path returns no profiles.
+func LoadProfiles(dir string) (profiledefinition.ProfileDefinitionMap, error) {
+	profiles := make(profiledefinition.ProfileDefinitionMap)
+	if dir == "" {
+		return profiles, nil
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read profiles directory: %w", err)
+	}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".yaml" {
+			continue
+		}
+		filePath := filepath.Join(dir, file.Name())
+		buf, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read file %s: %w", filePath, err)
+		}
+		def := profiledefinition.NewProfileDefinition()
+		if err := yaml.Unmarshal(buf, def); err != nil {
+			return nil, fmt.Errorf("unable to parse profile %s: %w", filePath, err)
+		}
+		profiles[strings.TrimSuffix(file.Name(), ".yaml")] = def
+	}
+	return profiles, nil
+}
+
+// ResolveProfile resolves a profile, and validates the resolved profile.
+func ResolveProfile(name string, profiles, defaultProfiles profiledefinition.ProfileDefinitionMap) (*profiledefinition.ProfileDefinition, []string) {
+	def, err := profiledefinition.ResolveExtends(name, profiles, defaultProfiles)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	errors := profiledefinition.ValidateEnrichProfile(def)
+	errors = append(errors, profiledefinition.FindMetricConflicts(def.Metrics)...)
+	if len(errors) > 0 {
+		return nil, errors
+	}
+	return def, nil
+}
+
+// PrintProfile prints a resolved profile to stdout.
+func PrintProfile(def *profiledefinition.ProfileDefinition, name string, useJSON bool) error {
+	var data []byte
+	var err error
+	if useJSON {
+		data, err = json.MarshalIndent(def, "", "  ")
+	} else {
+		data, err = yaml.Marshal(def)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to marshal profile %s: %w", name, err)
+	}
+	fmt.Printf("# %s\n%s\n", name, data)
+	return nil
+}
+
+// Execute runs the command.
+func Execute() {
+	err := rootCmd.Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootCmd.Flags().StringP("default-profiles", "d", "", "Directory of the default profiles.")
+	rootCmd.Flags().StringP("user-profiles", "u", "", "Directory of the user profiles, overriding the default ones.")
+	rootCmd.Flags().BoolP("json", "j", false, "Output as JSON")
+	rootCmd.Flags().BoolP("quiet", "q", false, "Only report errors, without printing the resolved profiles")
+}
@@ -42,6 +42,21 @@ type NetworkPathHop struct {
 
 	RTT       float64 `json:"rtt,omitempty"`
 	Reachable bool    `json:"reachable"`
+
+	// RTTStats is the distribution of the round-trip times when several probes are sent to each hop
+	RTTStats *NetworkPathHopRTTStats `json:"rtt_stats,omitempty"`
+}
+
+// NetworkPathHopRTTStats encapsulates the round-trip times
+// of the probes sent to a single hop, in milliseconds
+type NetworkPathHopRTTStats struct {
+	ProbesSent     int       `json:"probes_sent"`
+	ProbesReceived int       `json:"probes_received"`
+	Min            float64   `json:"min,omitempty"`
+	Max            float64   `json:"max,omitempty"`
+	Avg            float64   `json:"avg,omitempty"`
+	StdDev         float64   `json:"stddev,omitempty"`
+	Samples        []float64 `json:"samples,omitempty"`
 }
 
 // NetworkPathSource encapsulates information
@@ -20,6 +20,9 @@ type Config struct {
 	DestHostname string
 	// Destination Port number
 	DestPort uint16
+	// Source Port number of the probes, only used
+	// by UDP traceroutes, random when not set
+	SourcePort uint16
 	// Destination service name
 	DestinationService string
 	// Source service name
@@ -30,6 +33,10 @@ type Config struct {
 	MaxTTL uint8
 	// Timeout for each hop
 	Timeout time.Duration
+	// Number of probes sent to each hop, the
+	// round-trip times of all the probes are
+	// reported in the hop RTT stats
+	ProbesPerHop uint8
 	// Protocol is the protocol to use
 	// for traceroute, default is UDP
 	Protocol payload.Protocol
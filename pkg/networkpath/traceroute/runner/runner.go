@@ -9,10 +9,12 @@ package runner
 import (
 	"context"
 	"fmt"
+	"maps"
 	"math"
 	"math/rand"
 	"net"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/vishvananda/netns"
@@ -166,14 +168,16 @@ func (r *Runner) runTCP(cfg config.Config, hname string, target net.IP, maxTTL u
 		destPort = 80 // TODO: is this the default we want?
 	}
 
-	tr := tcp.NewTCPv4(target, destPort, DefaultNumPaths, DefaultMinTTL, maxTTL, time.Duration(DefaultDelay)*time.Millisecond, timeout)
+	pathResult, err := runProbes(cfg.ProbesPerHop, func() (payload.NetworkPath, error) {
+		tr := tcp.NewTCPv4(target, destPort, DefaultNumPaths, DefaultMinTTL, maxTTL, time.Duration(DefaultDelay)*time.Millisecond, timeout)
 
-	results, err := tr.TracerouteSequential()
-	if err != nil {
-		return payload.NetworkPath{}, err
-	}
+		results, err := tr.TracerouteSequential()
+		if err != nil {
+			return payload.NetworkPath{}, err
+		}
 
-	pathResult, err := r.processResults(results, payload.ProtocolTCP, hname, cfg.DestHostname)
+		return r.processResults(results, payload.ProtocolTCP, hname, cfg.DestHostname)
+	})
 	if err != nil {
 		return payload.NetworkPath{}, err
 	}
@@ -182,6 +186,81 @@ func (r *Runner) runTCP(cfg config.Config, hname string, target net.IP, maxTTL u
 	return pathResult, nil
 }
 
+// runProbes runs a traceroute once per probe sent to each hop and
+// merges the hops of the different runs
+func runProbes(probesPerHop uint8, run func() (payload.NetworkPath, error)) (payload.NetworkPath, error) {
+	if probesPerHop <= 1 {
+		return run()
+	}
+
+	paths := make([]payload.NetworkPath, 0, probesPerHop)
+	for i := uint8(0); i < probesPerHop; i++ {
+		path, err := run()
+		if err != nil {
+			return payload.NetworkPath{}, err
+		}
+		paths = append(paths, path)
+	}
+	return mergePaths(paths), nil
+}
+
+// mergePaths merges the hops with the same TTL of several traceroutes
+// to the same destination, the other fields are taken from the first one
+func mergePaths(paths []payload.NetworkPath) payload.NetworkPath {
+	hopsByTTL := make(map[int][]payload.NetworkPathHop)
+	for _, path := range paths {
+		for _, hop := range path.Hops {
+			hopsByTTL[hop.TTL] = append(hopsByTTL[hop.TTL], hop)
+		}
+	}
+
+	merged := paths[0]
+	merged.Hops = make([]payload.NetworkPathHop, 0, len(hopsByTTL))
+	for _, ttl := range slices.Sorted(maps.Keys(hopsByTTL)) {
+		merged.Hops = append(merged.Hops, mergeHops(hopsByTTL[ttl]))
+	}
+	return merged
+}
+
+// mergeHops merges the responses of the probes sent to a single hop, the
+// address is the one of the first response and the RTT is the average RTT
+func mergeHops(hops []payload.NetworkPathHop) payload.NetworkPathHop {
+	merged := hops[0]
+	stats := &payload.NetworkPathHopRTTStats{
+		ProbesSent: len(hops),
+	}
+	for _, hop := range hops {
+		if !hop.Reachable {
+			continue
+		}
+		if !merged.Reachable {
+			merged = hop
+		}
+		stats.Samples = append(stats.Samples, hop.RTT)
+	}
+	stats.ProbesReceived = len(stats.Samples)
+
+	if len(stats.Samples) > 0 {
+		var sum float64
+		for _, rtt := range stats.Samples {
+			sum += rtt
+		}
+		stats.Min = slices.Min(stats.Samples)
+		stats.Max = slices.Max(stats.Samples)
+		stats.Avg = sum / float64(len(stats.Samples))
+
+		var variance float64
+		for _, rtt := range stats.Samples {
+			variance += (rtt - stats.Avg) * (rtt - stats.Avg)
+		}
+		stats.StdDev = math.Sqrt(variance / float64(len(stats.Samples)))
+	}
+
+	merged.RTT = stats.Avg
+	merged.RTTStats = stats
+	return merged
+}
+
 func (r *Runner) processResults(res *common.Results, protocol payload.Protocol, hname string, destinationHost string) (payload.NetworkPath, error) {
 	if res == nil {
 		return payload.NetworkPath{}, nil
@@ -244,7 +323,7 @@ func (r *Runner) processResults(res *common.Results, protocol payload.Protocol,
 	return traceroutePath, nil
 }
 
-func getPorts(configDestPort uint16) (uint16, uint16, bool) {
+func getPorts(configDestPort uint16, configSourcePort uint16) (uint16, uint16, bool) {
 	var destPort uint16
 	var srcPort uint16
 	var useSourcePort bool
@@ -257,7 +336,13 @@ func getPorts(configDestPort uint16) (uint16, uint16, bool) {
 		destPort = DefaultDestPort + uint16(rand.Intn(30))
 		useSourcePort = false
 	}
-	srcPort = DefaultSourcePort + uint16(rand.Intn(10000))
+	if configSourcePort > 0 {
+		// Fixed Source Port
+		srcPort = configSourcePort
+	} else {
+		// Random Source Port
+		srcPort = DefaultSourcePort + uint16(rand.Intn(10000))
+	}
 	return destPort, srcPort, useSourcePort
 }
 
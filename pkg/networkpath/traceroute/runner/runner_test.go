@@ -21,15 +21,81 @@ import (
 )
 
 func TestGetPorts(t *testing.T) {
-	destPort, sourcePort, useSourcePort := getPorts(0)
+	destPort, sourcePort, useSourcePort := getPorts(0, 0)
 	assert.GreaterOrEqual(t, destPort, uint16(DefaultDestPort))
 	assert.GreaterOrEqual(t, sourcePort, uint16(DefaultSourcePort))
 	assert.False(t, useSourcePort)
 
-	destPort, sourcePort, useSourcePort = getPorts(80)
+	destPort, sourcePort, useSourcePort = getPorts(80, 0)
 	assert.Equal(t, destPort, uint16(80))
 	assert.GreaterOrEqual(t, sourcePort, uint16(DefaultSourcePort))
 	assert.True(t, useSourcePort)
+
+	destPort, sourcePort, useSourcePort = getPorts(80, 4242)
+	assert.Equal(t, destPort, uint16(80))
+	assert.Equal(t, sourcePort, uint16(4242))
+	assert.True(t, useSourcePort)
+}
+
+func TestRunProbes(t *testing.T) {
+	runs := []payload.NetworkPath{
+		{
+			PathtraceID: "first",
+			Hops: []payload.NetworkPathHop{
+				{TTL: 1, IPAddress: "10.0.0.1", RTT: 1, Reachable: true},
+				{TTL: 2, IPAddress: "unknown_hop_2"},
+				{TTL: 3, IPAddress: "8.8.8.8", RTT: 10, Reachable: true},
+			},
+		},
+		{
+			PathtraceID: "second",
+			Hops: []payload.NetworkPathHop{
+				{TTL: 1, IPAddress: "10.0.0.1", RTT: 3, Reachable: true},
+				{TTL: 2, IPAddress: "172.0.0.255", RTT: 5, Reachable: true},
+				{TTL: 3, IPAddress: "unknown_hop_3"},
+				{TTL: 4, IPAddress: "8.8.8.8", RTT: 12, Reachable: true},
+			},
+		},
+	}
+
+	var i int
+	path, err := runProbes(2, func() (payload.NetworkPath, error) {
+		run := runs[i]
+		i++
+		return run, nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "first", path.PathtraceID)
+	assert.Equal(t, []payload.NetworkPathHop{
+		{
+			TTL: 1, IPAddress: "10.0.0.1", RTT: 2, Reachable: true,
+			RTTStats: &payload.NetworkPathHopRTTStats{ProbesSent: 2, ProbesReceived: 2, Min: 1, Max: 3, Avg: 2, StdDev: 1, Samples: []float64{1, 3}},
+		},
+		{
+			TTL: 2, IPAddress: "172.0.0.255", RTT: 5, Reachable: true,
+			RTTStats: &payload.NetworkPathHopRTTStats{ProbesSent: 2, ProbesReceived: 1, Min: 5, Max: 5, Avg: 5, Samples: []float64{5}},
+		},
+		{
+			TTL: 3, IPAddress: "8.8.8.8", RTT: 10, Reachable: true,
+			RTTStats: &payload.NetworkPathHopRTTStats{ProbesSent: 2, ProbesReceived: 1, Min: 10, Max: 10, Avg: 10, Samples: []float64{10}},
+		},
+		{
+			TTL: 4, IPAddress: "8.8.8.8", RTT: 12, Reachable: true,
+			RTTStats: &payload.NetworkPathHopRTTStats{ProbesSent: 1, ProbesReceived: 1, Min: 12, Max: 12, Avg: 12, Samples: []float64{12}},
+		},
+	}, path.Hops)
+
+	// a single probe per hop keeps the traceroute result
+	i = 0
+	path, err = runProbes(1, func() (payload.NetworkPath, error) {
+		run := runs[i]
+		i++
+		return run, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, runs[0], path)
+	assert.Equal(t, 1, i)
 }
 
 func TestProcessResults(t *testing.T) {
@@ -24,27 +24,30 @@ import (
 
 // runUDP runs a UDP traceroute using the Dublin Traceroute library.
 func (r *Runner) runUDP(cfg config.Config, hname string, dest net.IP, maxTTL uint8, timeout time.Duration) (payload.NetworkPath, error) {
-	destPort, srcPort, useSourcePort := getPorts(cfg.DestPort)
-
-	dt := &probev4.UDPv4{
-		Target:     dest,
-		SrcPort:    srcPort,
-		DstPort:    destPort,
-		UseSrcPort: useSourcePort,
-		NumPaths:   uint16(DefaultNumPaths),
-		MinTTL:     uint8(DefaultMinTTL), // TODO: what's a good value?
-		MaxTTL:     maxTTL,
-		Delay:      time.Duration(DefaultDelay) * time.Millisecond, // TODO: what's a good value?
-		Timeout:    timeout,                                        // TODO: what's a good value?
-		BrokenNAT:  false,
-	}
+	// the ports are picked once so that all the probes follow the same path
+	destPort, srcPort, useSourcePort := getPorts(cfg.DestPort, cfg.SourcePort)
+
+	pathResult, err := runProbes(cfg.ProbesPerHop, func() (payload.NetworkPath, error) {
+		dt := &probev4.UDPv4{
+			Target:     dest,
+			SrcPort:    srcPort,
+			DstPort:    destPort,
+			UseSrcPort: useSourcePort,
+			NumPaths:   uint16(DefaultNumPaths),
+			MinTTL:     uint8(DefaultMinTTL), // TODO: what's a good value?
+			MaxTTL:     maxTTL,
+			Delay:      time.Duration(DefaultDelay) * time.Millisecond, // TODO: what's a good value?
+			Timeout:    timeout,                                        // TODO: what's a good value?
+			BrokenNAT:  false,
+		}
 
-	results, err := dt.Traceroute()
-	if err != nil {
-		return payload.NetworkPath{}, fmt.Errorf("traceroute run failed: %s", err.Error())
-	}
+		results, err := dt.Traceroute()
+		if err != nil {
+			return payload.NetworkPath{}, fmt.Errorf("traceroute run failed: %s", err.Error())
+		}
 
-	pathResult, err := r.processDublinResults(results, hname, cfg.DestHostname, destPort, dest)
+		return r.processDublinResults(results, hname, cfg.DestHostname, destPort, dest)
+	})
 	if err != nil {
 		return payload.NetworkPath{}, err
 	}
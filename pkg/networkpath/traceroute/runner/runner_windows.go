@@ -23,13 +23,15 @@ func (r *Runner) runUDP(cfg config.Config, hname string, target net.IP, maxTTL u
 		destPort = 33434 // TODO: is this the default we want?
 	}
 
-	tr := udp.NewUDPv4(target, destPort, DefaultNumPaths, uint8(DefaultMinTTL), maxTTL, time.Duration(DefaultDelay)*time.Millisecond, timeout)
-	results, err := tr.TracerouteSequential()
-	if err != nil {
-		return payload.NetworkPath{}, err
-	}
+	pathResult, err := runProbes(cfg.ProbesPerHop, func() (payload.NetworkPath, error) {
+		tr := udp.NewUDPv4(target, destPort, DefaultNumPaths, uint8(DefaultMinTTL), maxTTL, time.Duration(DefaultDelay)*time.Millisecond, timeout)
+		results, err := tr.TracerouteSequential()
+		if err != nil {
+			return payload.NetworkPath{}, err
+		}
 
-	pathResult, err := r.processResults(results, payload.ProtocolUDP, hname, cfg.DestHostname)
+		return r.processResults(results, payload.ProtocolUDP, hname, cfg.DestHostname)
+	})
 	if err != nil {
 		return payload.NetworkPath{}, err
 	}
@@ -10,7 +10,6 @@ import (
 	"time"
 
 	"github.com/patrickmn/go-cache"
-	"k8s.io/apimachinery/pkg/types"
 
 	pkgorchestratormodel "github.com/DataDog/datadog-agent/pkg/orchestrator/model"
 	"github.com/DataDog/datadog-agent/pkg/telemetry"
@@ -34,12 +33,17 @@ var (
 	sendExpVars = expvar.NewMap("orchestrator-sends")
 	cacheMiss   = map[pkgorchestratormodel.NodeType]*expvar.Int{}
 
-	// KubernetesResourceCache provides an in-memory key:value store similar to memcached for kubernetes resources.
+	// number of resources held by the resource version cache
+	cacheSize = expvar.NewInt("orchestrator-cache-size")
+
+	// KubernetesResourceCache provides an in-memory key:value store similar to memcached for the
+	// orchestrator check statistics and the cluster metadata.
 	KubernetesResourceCache = cache.New(defaultExpire, defaultPurge)
 
 	// Telemetry
 	tlmCacheHits   = telemetry.NewCounter("orchestrator", "cache_hits", []string{"orchestrator", "resource"}, "Number of cache hits")
 	tlmCacheMisses = telemetry.NewCounter("orchestrator", "cache_misses", []string{"orchestrator", "resource"}, "Number of cache misses")
+	tlmCacheSize   = telemetry.NewGauge("orchestrator", "cache_size", nil, "Number of resources held by the resource version cache")
 )
 
 func init() {
@@ -51,27 +55,19 @@ func init() {
 	}
 }
 
-// SkipKubernetesResource checks with a global kubernetes cache whether the resource was already reported.
-// It will return true in case the UID is in the cache and the resourceVersion did not change. Else it will return false.
-// 0 == defaultDuration
-func SkipKubernetesResource(uid types.UID, resourceVersion string, nodeType pkgorchestratormodel.NodeType) bool {
-	cacheKey := string(uid)
-	value, hit := KubernetesResourceCache.Get(cacheKey)
-
-	if !hit {
-		KubernetesResourceCache.Set(cacheKey, resourceVersion, 0)
-		incCacheMiss(nodeType)
-		return false
-	} else if value != resourceVersion {
-		incCacheMiss(nodeType)
-		KubernetesResourceCache.Set(cacheKey, resourceVersion, 0)
-		return false
-	}
-	incCacheHit(nodeType)
-	return true
+// SetCacheSize records the number of resources held by the resource version cache of the processors
+func SetCacheSize(size int) {
+	cacheSize.Set(int64(size))
+	tlmCacheSize.Set(float64(size))
+}
+
+// CacheSize returns the number of resources held by the resource version cache of the processors
+func CacheSize() int64 {
+	return cacheSize.Value()
 }
 
-func incCacheHit(nodeType pkgorchestratormodel.NodeType) {
+// IncCacheHit records a resource skipped because it was already reported
+func IncCacheHit(nodeType pkgorchestratormodel.NodeType) {
 	if nodeType.String() == "" {
 		log.Errorf("Unknown NodeType %v will not update cache hits", nodeType)
 		return
@@ -81,7 +77,8 @@ func incCacheHit(nodeType pkgorchestratormodel.NodeType) {
 	tlmCacheHits.Inc(nodeType.TelemetryTags()...)
 }
 
-func incCacheMiss(nodeType pkgorchestratormodel.NodeType) {
+// IncCacheMiss records a resource reported because it changed or wasn't reported yet
+func IncCacheMiss(nodeType pkgorchestratormodel.NodeType) {
 	if nodeType.String() == "" {
 		log.Errorf("Unknown NodeType %v will not update cache misses", nodeType)
 		return
@@ -47,6 +47,11 @@ type OrchestratorConfig struct {
 	IsManifestCollectionEnabled    bool
 	BufferedManifestEnabled        bool
 	ManifestBufferFlushInterval    time.Duration
+	PodStartupMetricsEnabled       bool
+	ReplicaSetPruningEnabled       bool
+	ReplicaSetPruningMinAge        time.Duration
+	ResourceCacheTTL               time.Duration
+	ResourceCacheMaxEntries        int
 }
 
 // NewDefaultOrchestratorConfig returns an NewDefaultOrchestratorConfig using a configuration file. It can be nil
@@ -117,16 +122,43 @@ func (oc *OrchestratorConfig) Load() error {
 	// Orchestrator Explorer
 	oc.OrchestrationCollectionEnabled, oc.KubeClusterName = IsOrchestratorEnabled()
 
+	// The patterns matched against environment variable names to redact their values,
+	// a cluster specific list takes precedence over the global one
+	if patterns, found := sensitiveEnvVarPatterns(oc.KubeClusterName); found {
+		oc.Scrubber.SetSensitiveEnvVarPatterns(patterns)
+	}
+
 	oc.CollectorDiscoveryEnabled = pkgconfigsetup.Datadog().GetBool(OrchestratorNSKey("collector_discovery.enabled"))
 	oc.IsScrubbingEnabled = pkgconfigsetup.Datadog().GetBool(OrchestratorNSKey("container_scrubbing.enabled"))
 	oc.ExtraTags = pkgconfigsetup.Datadog().GetStringSlice(OrchestratorNSKey("extra_tags"))
 	oc.IsManifestCollectionEnabled = pkgconfigsetup.Datadog().GetBool(OrchestratorNSKey("manifest_collection.enabled"))
 	oc.BufferedManifestEnabled = pkgconfigsetup.Datadog().GetBool(OrchestratorNSKey("manifest_collection.buffer_manifest"))
 	oc.ManifestBufferFlushInterval = pkgconfigsetup.Datadog().GetDuration(OrchestratorNSKey("manifest_collection.buffer_flush_interval"))
+	oc.PodStartupMetricsEnabled = pkgconfigsetup.Datadog().GetBool(OrchestratorNSKey("pod_startup_metrics.enabled"))
+	oc.ReplicaSetPruningEnabled = pkgconfigsetup.Datadog().GetBool(OrchestratorNSKey("replicaset_pruning.enabled"))
+	oc.ReplicaSetPruningMinAge = pkgconfigsetup.Datadog().GetDuration(OrchestratorNSKey("replicaset_pruning.min_age"))
+	oc.ResourceCacheTTL = pkgconfigsetup.Datadog().GetDuration(OrchestratorNSKey("resource_cache.ttl"))
+	oc.ResourceCacheMaxEntries = pkgconfigsetup.Datadog().GetInt(OrchestratorNSKey("resource_cache.max_entries"))
 
 	return nil
 }
 
+// sensitiveEnvVarPatterns returns the environment variable name patterns configured for the given
+// cluster, falling back to the ones configured for all clusters. It returns false if none are configured.
+func sensitiveEnvVarPatterns(clusterName string) ([]string, bool) {
+	if k := OrchestratorNSKey("env_var_redaction", "cluster_overrides"); clusterName != "" && pkgconfigsetup.Datadog().IsSet(k) {
+		if patterns, found := pkgconfigsetup.Datadog().GetStringMapStringSlice(k)[clusterName]; found {
+			return patterns, true
+		}
+	}
+
+	if k := OrchestratorNSKey("env_var_redaction", "patterns"); pkgconfigsetup.Datadog().IsSet(k) {
+		return pkgconfigsetup.Datadog().GetStringSlice(k), true
+	}
+
+	return nil, false
+}
+
 func extractOrchestratorAdditionalEndpoints(URL *url.URL, orchestratorEndpoints *[]apicfg.Endpoint) error {
 	if k := OrchestratorNSKey("orchestrator_additional_endpoints"); pkgconfigsetup.Datadog().IsSet(k) {
 		if err := extractEndpoints(URL, k, orchestratorEndpoints); err != nil {
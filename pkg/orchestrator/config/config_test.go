@@ -313,6 +313,28 @@ func (suite *YamlConfigTestSuite) TestOnlyEnvContainsConfigArgsScrubbing() {
 	}
 }
 
+func (suite *YamlConfigTestSuite) TestSensitiveEnvVarPatterns() {
+	_, found := sensitiveEnvVarPatterns("my-cluster")
+	suite.False(found)
+
+	suite.config.SetWithoutSource("orchestrator_explorer.env_var_redaction.patterns", []string{"*TOKEN*", "VAULT_*"})
+	suite.config.SetWithoutSource("orchestrator_explorer.env_var_redaction.cluster_overrides", map[string][]string{
+		"my-cluster": {"*_CERT"},
+	})
+
+	patterns, found := sensitiveEnvVarPatterns("my-cluster")
+	suite.True(found)
+	suite.Equal([]string{"*_CERT"}, patterns)
+
+	patterns, found = sensitiveEnvVarPatterns("other-cluster")
+	suite.True(found)
+	suite.Equal([]string{"*TOKEN*", "VAULT_*"}, patterns)
+
+	patterns, found = sensitiveEnvVarPatterns("")
+	suite.True(found)
+	suite.Equal([]string{"*TOKEN*", "VAULT_*"}, patterns)
+}
+
 func TestYamlConfigTestSuite(t *testing.T) {
 	suite.Run(t, new(YamlConfigTestSuite))
 }
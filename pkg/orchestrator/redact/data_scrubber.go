@@ -30,6 +30,11 @@ var (
 		"access_token", "auth_token",
 		"api_key", "apikey", "pwd",
 		"secret", "credentials", "stripetoken"}
+
+	// DefaultSensitiveEnvVarPatterns are the patterns matched against environment
+	// variable names to redact their values in pod and workload manifests. KEY is
+	// only matched as a whole word so that names like MONKEY or KEYCLOAK_URL are kept.
+	DefaultSensitiveEnvVarPatterns = []string{"*SECRET*", "*TOKEN*", "*PASSWORD*", "KEY", "KEY_*", "*_KEY", "*_KEY_*"}
 )
 
 // DataScrubber allows the agent to block cmdline arguments that match
@@ -41,6 +46,7 @@ type DataScrubber struct {
 	// LiteralSensitivePatterns are custom words which use to match against
 	LiteralSensitivePatterns         []string
 	regexSensitiveWordsInAnnotations []*regexp.Regexp
+	sensitiveEnvVarPatterns          []*regexp.Regexp
 	scrubbedCmdLines                 map[string][]string
 }
 
@@ -54,6 +60,7 @@ func NewDefaultDataScrubber() *DataScrubber {
 	}
 
 	newDataScrubber.setupAnnotationRegexps(defaultSensitiveWords)
+	newDataScrubber.SetSensitiveEnvVarPatterns(DefaultSensitiveEnvVarPatterns)
 
 	return newDataScrubber
 }
@@ -76,6 +83,21 @@ func (ds *DataScrubber) ContainsSensitiveWord(s string) bool {
 	return false
 }
 
+// IsSensitiveEnvVar returns true if the value of the environment variable
+// with the given name must be redacted, either because its name contains a
+// sensitive word or because it matches one of the environment variable patterns
+func (ds *DataScrubber) IsSensitiveEnvVar(name string) bool {
+	if ds.ContainsSensitiveWord(name) {
+		return true
+	}
+	for _, r := range ds.sensitiveEnvVarPatterns {
+		if r.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
 // ScrubAnnotationValue obfuscate sensitive information from an annotation
 // value.
 func (ds *DataScrubber) ScrubAnnotationValue(annotationValue string) string {
@@ -186,6 +208,25 @@ func (ds *DataScrubber) AddCustomSensitiveRegex(words []string) {
 	ds.RegexSensitivePatterns = append(ds.RegexSensitivePatterns, r...)
 }
 
+// SetSensitiveEnvVarPatterns replaces the patterns matched against environment
+// variable names on the DataScrubber object. Patterns are case insensitive and
+// match the whole name, '*' matching any sequence of characters (e.g. "*_TOKEN").
+func (ds *DataScrubber) SetSensitiveEnvVarPatterns(patterns []string) {
+	compiledRegexps := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+
+		parts := strings.Split(pattern, "*")
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+		compiledRegexps = append(compiledRegexps, regexp.MustCompile("(?i)^"+strings.Join(parts, ".*")+"$"))
+	}
+	ds.sensitiveEnvVarPatterns = compiledRegexps
+}
+
 // compileStringsToRegex compile each word in the slice into a regex pattern to match
 // against the cmdline arguments (originally imported from pkg/process/config)
 // The word must contain only word characters ([a-zA-z0-9_]) or wildcards *
@@ -286,6 +286,51 @@ func TestScrubAnnotationValueWithCustomWords(t *testing.T) {
 	}
 }
 
+func TestIsSensitiveEnvVar(t *testing.T) {
+	scrubber := NewDefaultDataScrubber()
+
+	for name, expected := range map[string]bool{
+		"GITHUB_TOKEN":    true,
+		"client_secret":   true,
+		"DB_PASSWORD_V2":  true,
+		"AWS_ACCESS_KEY":  true,
+		"DD_API_KEY":      true,
+		"KEY_ID":          true,
+		"AWS_KEY_ID":      true,
+		"key":             true,
+		"pwd":             true,
+		"LOG_LEVEL":       false,
+		"DD_SITE":         false,
+		"VAULT_AUTH_PATH": false,
+		"MONKEY":          false,
+		"KEYCLOAK_URL":    false,
+		"TURKEY_FEED":     false,
+		"HOTKEYS":         false,
+	} {
+		assert.Equal(t, expected, scrubber.IsSensitiveEnvVar(name), name)
+	}
+}
+
+func TestSetSensitiveEnvVarPatterns(t *testing.T) {
+	scrubber := NewDefaultDataScrubber()
+	scrubber.SetSensitiveEnvVarPatterns([]string{"VAULT_*", "*_CERT", "", "exact.name"})
+
+	for name, expected := range map[string]bool{
+		"VAULT_AUTH_PATH": true,
+		"vault_addr":      true,
+		"TLS_CERT":        true,
+		"TLS_CERT_PATH":   false,
+		"exact.name":      true,
+		"exactXname":      false,
+		// the default patterns are replaced
+		"GITHUB_TOKEN": false,
+		// sensitive words still apply
+		"password": true,
+	} {
+		assert.Equal(t, expected, scrubber.IsSensitiveEnvVar(name), name)
+	}
+}
+
 type annotationTestCase struct {
 	annotationValue         string
 	expectedAnnotationValue string
@@ -8,16 +8,15 @@ package checks
 import (
 	"fmt"
 	"math"
-	"net/http"
 	"sync"
 	"time"
 
 	model "github.com/DataDog/agent-payload/v5/process"
 	"github.com/DataDog/datadog-go/v5/statsd"
 
-	"github.com/DataDog/datadog-agent/cmd/system-probe/api/client"
 	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
 	pkgconfigmodel "github.com/DataDog/datadog-agent/pkg/config/model"
+	"github.com/DataDog/datadog-agent/pkg/process/net"
 	proccontainers "github.com/DataDog/datadog-agent/pkg/process/util/containers"
 	"github.com/DataDog/datadog-agent/pkg/util/flavor"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -52,7 +51,7 @@ type ContainerCheck struct {
 	maxBatchSize int
 	wmeta        workloadmeta.Component
 
-	sysprobeClient *http.Client
+	sysprobeClient *net.RPCClient
 	statsd         statsd.ClientInterface
 }
 
@@ -66,7 +65,7 @@ func (c *ContainerCheck) Init(syscfg *SysProbeConfig, info *HostInfo, _ bool) er
 	c.hostInfo = info
 
 	if syscfg.NetworkTracerModuleEnabled {
-		c.sysprobeClient = client.Get(syscfg.SystemProbeAddress)
+		c.sysprobeClient = net.GetRPCClient(syscfg.SystemProbeAddress)
 	}
 
 	networkID, err := retryGetNetworkID(c.sysprobeClient)
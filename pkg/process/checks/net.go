@@ -8,7 +8,6 @@ package checks
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"runtime"
 	"sort"
 	"time"
@@ -16,7 +15,6 @@ import (
 	model "github.com/DataDog/agent-payload/v5/process"
 	"github.com/benbjohnson/clock"
 
-	sysprobeclient "github.com/DataDog/datadog-agent/cmd/system-probe/api/client"
 	sysconfig "github.com/DataDog/datadog-agent/cmd/system-probe/config"
 	sysconfigtypes "github.com/DataDog/datadog-agent/cmd/system-probe/config/types"
 	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
@@ -37,6 +35,10 @@ import (
 const (
 	maxResolverPidCacheSize  = 32768
 	maxResolverAddrCacheSize = 4096
+
+	// connectionsCallTimeout bounds the time to retrieve the connections, which
+	// can take longer than the other system-probe calls on busy hosts
+	connectionsCallTimeout = 20 * time.Second
 )
 
 var (
@@ -75,7 +77,7 @@ type ConnectionsCheck struct {
 
 	npCollector npcollector.Component
 
-	sysprobeClient *http.Client
+	sysprobeClient *net.RPCClient
 }
 
 // Init initializes a ConnectionsCheck instance.
@@ -83,7 +85,7 @@ func (c *ConnectionsCheck) Init(syscfg *SysProbeConfig, hostInfo *HostInfo, _ bo
 	c.hostInfo = hostInfo
 	c.maxConnsPerMessage = syscfg.MaxConnsPerMessage
 	c.notInitializedLogLimit = log.NewLogLimit(1, time.Minute*10)
-	c.sysprobeClient = sysprobeclient.Get(syscfg.SystemProbeAddress)
+	c.sysprobeClient = net.GetRPCClient(syscfg.SystemProbeAddress)
 
 	// Register process agent as a system probe's client
 	// This ensures we start recording data from now to the first call to `Run`
@@ -185,47 +187,31 @@ func (c *ConnectionsCheck) Cleanup() {
 }
 
 func (c *ConnectionsCheck) register() error {
-	url := sysprobeclient.ModuleURL(sysconfig.NetworkTracerModule, "/register?client_id="+ProcessAgentClientID)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return err
-	}
-
-	resp, err := c.sysprobeClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("conn request failed: url: %s, status code: %d", req.URL, resp.StatusCode)
-	}
-	return nil
+	_, _, err := c.sysprobeClient.Call(context.Background(), net.Request{
+		Module:   sysconfig.NetworkTracerModule,
+		Endpoint: "/register?client_id=" + ProcessAgentClientID,
+	})
+	return err
 }
 
 func (c *ConnectionsCheck) getConnections() (*model.Connections, error) {
-	url := sysprobeclient.ModuleURL(sysconfig.NetworkTracerModule, "/connections?client_id="+ProcessAgentClientID)
-	req, err := http.NewRequest("GET", url, nil)
+	resp, err := c.sysprobeClient.Stream(context.Background(), net.Request{
+		Module:   sysconfig.NetworkTracerModule,
+		Endpoint: "/connections?client_id=" + ProcessAgentClientID,
+		Accept:   "application/protobuf",
+		Timeout:  connectionsCallTimeout,
+	})
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Close()
 
-	req.Header.Set("Accept", "application/protobuf")
-	resp, err := c.sysprobeClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("conn request failed: url: %s, status code: %d", req.URL, resp.StatusCode)
-	}
-
-	body, err := sysprobeclient.ReadAllResponseBody(resp)
+	body, err := resp.ReadAll()
 	if err != nil {
 		return nil, err
 	}
 
-	contentType := resp.Header.Get("Content-type")
-	return netEncoding.GetUnmarshaler(contentType).Unmarshal(body)
+	return netEncoding.GetUnmarshaler(resp.ContentType).Unmarshal(body)
 }
 
 func convertDNSEntry(dnstable map[string]*model.DNSDatabaseEntry, namemap map[string]int32, namedb *[]string, ip string, entry *model.DNSEntry) {
@@ -494,7 +480,7 @@ func convertAndEnrichWithServiceCtx(tags []string, tagOffsets []uint32, serviceC
 }
 
 // retryGetNetworkID attempts to fetch the network_id maxRetries times before failing
-func retryGetNetworkID(sysProbeClient *http.Client) (string, error) {
+func retryGetNetworkID(sysProbeClient *net.RPCClient) (string, error) {
 	const maxRetries = 3
 	var err error
 	var networkID string
@@ -515,7 +501,7 @@ func retryGetNetworkID(sysProbeClient *http.Client) (string, error) {
 }
 
 // getNetworkID fetches network_id from the current netNS or from the system probe if necessary, where the root netNS is used
-func getNetworkID(sysProbeClient *http.Client) (string, error) {
+func getNetworkID(sysProbeClient *net.RPCClient) (string, error) {
 	networkID, err := cloudproviders.GetNetworkID(context.TODO())
 	if err != nil && sysProbeClient != nil {
 		log.Debugf("no network ID detected. retrying via system-probe: %s", err)
@@ -9,7 +9,6 @@ import (
 	"errors"
 	"fmt"
 	"math"
-	"net/http"
 	"regexp"
 	"strings"
 	"time"
@@ -18,11 +17,11 @@ import (
 	"github.com/DataDog/datadog-go/v5/statsd"
 	"github.com/shirou/gopsutil/v4/cpu"
 
-	"github.com/DataDog/datadog-agent/cmd/system-probe/api/client"
 	workloadmetacomp "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
 	gpusubscriber "github.com/DataDog/datadog-agent/comp/process/gpusubscriber/def"
 	pkgconfigmodel "github.com/DataDog/datadog-agent/pkg/config/model"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/config/structure"
 	"github.com/DataDog/datadog-agent/pkg/process/metadata"
 	"github.com/DataDog/datadog-agent/pkg/process/metadata/parser"
 	"github.com/DataDog/datadog-agent/pkg/process/metadata/workloadmeta"
@@ -42,6 +41,9 @@ const (
 	configStripProcArgs        = configPrefix + "strip_proc_arguments"
 	configDisallowList         = configPrefix + "blacklist_patterns"
 	configIgnoreZombies        = configPrefix + "ignore_zombie_processes"
+	configScrubDefaultWords    = configPrefix + "scrub_default_sensitive_words"
+	configScrubbingRules       = configPrefix + "scrubbing_rules"
+	configIOStatsFallback      = configPrefix + "process_collection.io_stats_fallback.enabled"
 )
 
 // NewProcessCheck returns an instance of the ProcessCheck.
@@ -124,7 +126,7 @@ type ProcessCheck struct {
 
 	wmeta workloadmetacomp.Component
 
-	sysprobeClient *http.Client
+	sysprobeClient *net.RPCClient
 	statsd         statsd.ClientInterface
 
 	gpuSubscriber gpusubscriber.Component
@@ -135,7 +137,7 @@ func (p *ProcessCheck) Init(syscfg *SysProbeConfig, info *HostInfo, oneShot bool
 	p.hostInfo = info
 	p.sysProbeConfig = syscfg
 	p.probe = newProcessProbe(p.config,
-		procutil.WithPermission(syscfg.ProcessModuleEnabled),
+		procutil.WithPermission(collectStatsWithPerm(p.config, syscfg)),
 		procutil.WithIgnoreZombieProcesses(p.config.GetBool(configIgnoreZombies)))
 	sharedContainerProvider, err := proccontainers.GetSharedContainerProvider()
 	if err != nil {
@@ -146,7 +148,7 @@ func (p *ProcessCheck) Init(syscfg *SysProbeConfig, info *HostInfo, oneShot bool
 	p.notInitializedLogLimit = log.NewLogLimit(1, time.Minute*10)
 
 	if syscfg.NetworkTracerModuleEnabled || syscfg.ProcessModuleEnabled {
-		p.sysprobeClient = client.Get(syscfg.SystemProbeAddress)
+		p.sysprobeClient = net.GetRPCClient(syscfg.SystemProbeAddress)
 	}
 
 	networkID, err := retryGetNetworkID(p.sysprobeClient)
@@ -190,6 +192,21 @@ func (p *ProcessCheck) Init(syscfg *SysProbeConfig, info *HostInfo, oneShot bool
 	return nil
 }
 
+// collectStatsWithPerm returns whether the probe reads the stats requiring elevated permissions, the I/O stats and
+// the open file descriptors. They are read when the system-probe process module is enabled, or from procfs by the
+// check itself as a fallback when the module is disabled, for instance on hosts where system-probe can't run.
+// Without elevated permissions, the fallback only reads the stats of the processes of the agent user.
+func collectStatsWithPerm(config pkgconfigmodel.Reader, syscfg *SysProbeConfig) bool {
+	if syscfg.ProcessModuleEnabled {
+		return true
+	}
+	if config.GetBool(configIOStatsFallback) {
+		log.Info("System-probe process module is disabled, collecting the process I/O stats and open file descriptors from procfs")
+		return true
+	}
+	return false
+}
+
 // IsEnabled returns true if the check is enabled by configuration
 func (p *ProcessCheck) IsEnabled() bool {
 	if p.config.GetBool("process_config.run_in_core_agent.enabled") && flavor.GetFlavor() == flavor.ProcessAgent {
@@ -652,6 +669,13 @@ func mergeProcWithSysprobeStats(procs map[int32]*procutil.Process, pStats *model
 	}
 }
 
+// NewDataScrubber returns a DataScrubber configured like the one of the process check
+func NewDataScrubber(config pkgconfigmodel.Reader) *procutil.DataScrubber {
+	scrubber := procutil.NewDefaultDataScrubber()
+	initScrubber(config, scrubber)
+	return scrubber
+}
+
 func initScrubber(config pkgconfigmodel.Reader, scrubber *procutil.DataScrubber) {
 	// Enable/Disable the DataScrubber to obfuscate process args
 	if config.IsSet(configScrubArgs) {
@@ -662,6 +686,12 @@ func initScrubber(config pkgconfigmodel.Reader, scrubber *procutil.DataScrubber)
 		log.Debug("Starting process collection with Scrubber enabled")
 	}
 
+	// The default sensitive words can be replaced by custom words and scrubbing rules
+	if !config.GetBool(configScrubDefaultWords) {
+		log.Debug("Default sensitive words disabled in Scrubber")
+		scrubber.SensitivePatterns = nil
+	}
+
 	// A custom word list to enhance the default one used by the DataScrubber
 	if config.IsSet(configCustomSensitiveWords) {
 		words := config.GetStringSlice(configCustomSensitiveWords)
@@ -669,6 +699,17 @@ func initScrubber(config pkgconfigmodel.Reader, scrubber *procutil.DataScrubber)
 		log.Debug("Adding custom sensitives words to Scrubber:", words)
 	}
 
+	// Structured rules applied after the sensitive words
+	if config.IsSet(configScrubbingRules) {
+		var rules []procutil.ScrubbingRule
+		if err := structure.UnmarshalKey(config, configScrubbingRules, &rules); err != nil {
+			log.Warnf("Ignoring invalid %s: %v", configScrubbingRules, err)
+		} else {
+			scrubber.AddScrubbingRules(rules)
+			log.Debugf("Adding %d scrubbing rules to Scrubber", len(rules))
+		}
+	}
+
 	// Strips all process arguments
 	if config.GetBool(configStripProcArgs) {
 		log.Debug("Strip all process arguments enabled")
@@ -6,7 +6,6 @@
 package checks
 
 import (
-	"net/http"
 	"time"
 
 	model "github.com/DataDog/agent-payload/v5/process"
@@ -157,7 +156,7 @@ func calculateRate(cur, prev uint64, before time.Time) float32 {
 }
 
 // mergeStatWithSysprobeStats takes a process by PID map and fill the stats from system probe into the processes in the map
-func mergeStatWithSysprobeStats(pids []int32, stats map[int32]*procutil.Stats, client *http.Client) {
+func mergeStatWithSysprobeStats(pids []int32, stats map[int32]*procutil.Stats, client *net.RPCClient) {
 	pStats, err := net.GetProcStats(client, pids)
 	if err == nil {
 		for pid, stats := range stats {
@@ -312,6 +312,42 @@ func TestOnlyEnvConfigArgsScrubbingEnabled(t *testing.T) {
 	}
 }
 
+func TestConfigScrubbingRules(t *testing.T) {
+	cfg := configmock.NewFromYAML(t, `
+process_config:
+  scrub_default_sensitive_words: false
+  custom_sensitive_words: ["consul_token"]
+  scrubbing_rules:
+    - name: env
+      env_vars: ["TOKEN"]
+    - name: java
+      exe: java
+      pattern: '-Dkey=(\S+)'
+`)
+
+	scrubber := procutil.NewDefaultDataScrubber()
+	initScrubber(cfg, scrubber)
+
+	cases := []struct {
+		cmdline       []string
+		parsedCmdline []string
+	}{
+		{
+			[]string{"env", "TOKEN=1234", "./server", "--password=1234", "-consul_token=1234"},
+			[]string{"env", "TOKEN=********", "./server", "--password=1234", "-consul_token=********"},
+		},
+		{
+			[]string{"java", "-Dkey=1234", "-jar", "app.jar"},
+			[]string{"java", "-Dkey=********", "-jar", "app.jar"},
+		},
+	}
+
+	for i := range cases {
+		cases[i].cmdline, _ = scrubber.ScrubCommand(cases[i].cmdline)
+		assert.Equal(t, cases[i].parsedCmdline, cases[i].cmdline)
+	}
+}
+
 func TestOnlyEnvConfigArgsScrubbingDisabled(t *testing.T) {
 	cfg := configmock.New(t)
 
@@ -603,3 +639,24 @@ func TestProcessContextCollection(t *testing.T) {
 	require.NoError(t, err)
 	assert.ElementsMatch(t, expected, actual.Payloads())
 }
+
+func TestCollectStatsWithPerm(t *testing.T) {
+	for _, tc := range []struct {
+		name                 string
+		processModuleEnabled bool
+		fallbackEnabled      bool
+		expected             bool
+	}{
+		{name: "process module enabled", processModuleEnabled: true, expected: true},
+		{name: "process module enabled with fallback", processModuleEnabled: true, fallbackEnabled: true, expected: true},
+		{name: "fallback enabled", fallbackEnabled: true, expected: true},
+		{name: "both disabled", expected: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := configmock.New(t)
+			cfg.SetWithoutSource("process_config.process_collection.io_stats_fallback.enabled", tc.fallbackEnabled)
+			syscfg := &SysProbeConfig{ProcessModuleEnabled: tc.processModuleEnabled}
+			assert.Equal(t, tc.expected, collectStatsWithPerm(cfg, syscfg))
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package checks
+
+import (
+	"encoding/json"
+
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// scrubbingRulesConfig is the content of a PROCESS_SCRUBBING_RULES remote configuration
+type scrubbingRulesConfig struct {
+	Rules []procutil.ScrubbingRule `json:"rules"`
+}
+
+// OnScrubbingRulesUpdate applies the command line scrubbing rules received through remote config
+func (p *ProcessCheck) OnScrubbingRulesUpdate(updates map[string]state.RawConfig, applyStateCallback func(string, state.ApplyStatus)) {
+	updateRemoteScrubbingRules(p.scrubber, updates, applyStateCallback)
+}
+
+// OnScrubbingRulesUpdate applies the command line scrubbing rules received through remote config
+func (d *ProcessDiscoveryCheck) OnScrubbingRulesUpdate(updates map[string]state.RawConfig, applyStateCallback func(string, state.ApplyStatus)) {
+	updateRemoteScrubbingRules(d.scrubber, updates, applyStateCallback)
+}
+
+// updateRemoteScrubbingRules replaces the remote scrubbing rules of the scrubber with the rules of
+// all the valid configurations. The rules configured in process_config.scrubbing_rules are kept.
+func updateRemoteScrubbingRules(scrubber *procutil.DataScrubber, updates map[string]state.RawConfig, applyStateCallback func(string, state.ApplyStatus)) {
+	var rules []procutil.ScrubbingRule
+	for configPath, rawConfig := range updates {
+		var config scrubbingRulesConfig
+		err := json.Unmarshal(rawConfig.Config, &config)
+		if err == nil {
+			err = procutil.ValidateScrubbingRules(config.Rules)
+		}
+		if err != nil {
+			log.Warnf("Skipping invalid PROCESS_SCRUBBING_RULES update %s: %v", configPath, err)
+			applyStateCallback(configPath, state.ApplyStatus{
+				State: state.ApplyStateError,
+				Error: err.Error(),
+			})
+			continue
+		}
+
+		rules = append(rules, config.Rules...)
+		applyStateCallback(configPath, state.ApplyStatus{
+			State: state.ApplyStateAcknowledged,
+		})
+	}
+
+	if err := scrubber.SetRemoteScrubbingRules(rules); err != nil {
+		// the rules are validated above
+		log.Errorf("Couldn't apply the remote scrubbing rules: %v", err)
+		return
+	}
+	log.Infof("%d command line scrubbing rules received through remote config", len(rules))
+}
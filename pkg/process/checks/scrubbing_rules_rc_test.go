@@ -0,0 +1,46 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build !windows
+
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+)
+
+func TestUpdateRemoteScrubbingRules(t *testing.T) {
+	scrubber := procutil.NewDefaultDataScrubber()
+	scrubber.AddScrubbingRules([]procutil.ScrubbingRule{{Name: "config", EnvVars: []string{"API"}}})
+
+	applied := map[string]state.ApplyStatus{}
+	applyStateCallback := func(configPath string, status state.ApplyStatus) {
+		applied[configPath] = status
+	}
+
+	updateRemoteScrubbingRules(scrubber, map[string]state.RawConfig{
+		"valid":   {Config: []byte(`{"rules":[{"name":"env","env_vars":["TOKEN"]}]}`)},
+		"invalid": {Config: []byte(`{"rules":[{"name":"pattern","pattern":"(secret"}]}`)},
+		"corrupt": {Config: []byte(`{"rules":`)},
+	}, applyStateCallback)
+
+	assert.Equal(t, state.ApplyStateAcknowledged, applied["valid"].State)
+	assert.Equal(t, state.ApplyStateError, applied["invalid"].State)
+	assert.NotEmpty(t, applied["invalid"].Error)
+	assert.Equal(t, state.ApplyStateError, applied["corrupt"].State)
+
+	cmdline, _ := scrubber.ScrubCommand([]string{"env", "TOKEN=abcd", "API=efgh", "SECRET=ijkl"})
+	assert.Equal(t, []string{"env", "TOKEN=********", "API=********", "SECRET=ijkl"}, cmdline)
+
+	// the remote rules are removed with their configurations, the rules from the config are kept
+	updateRemoteScrubbingRules(scrubber, map[string]state.RawConfig{}, applyStateCallback)
+	cmdline, _ = scrubber.ScrubCommand([]string{"env", "TOKEN=abcd", "API=efgh"})
+	assert.Equal(t, []string{"env", "TOKEN=abcd", "API=********"}, cmdline)
+}
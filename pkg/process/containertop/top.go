@@ -0,0 +1,202 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package containertop lists the processes running in a container, along with their
+// resource usage, using the process collection machinery of the agent.
+package containertop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+	"github.com/DataDog/datadog-agent/pkg/process/checks"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/metrics/provider"
+	"github.com/DataDog/datadog-agent/pkg/util/option"
+)
+
+const (
+	// DefaultInterval is the default time between the two samples used to compute the CPU usage
+	DefaultInterval = time.Second
+	// MaxInterval bounds the time a request can spend sampling the CPU usage
+	MaxInterval = 10 * time.Second
+
+	pidsCacheValidity = 2 * time.Second
+)
+
+var (
+	// ErrContainerNotFound is returned when no running container matches the requested ID
+	ErrContainerNotFound = errors.New("container not found")
+	// ErrAmbiguousContainer is returned when a short ID or a name matches several containers
+	ErrAmbiguousContainer = errors.New("several containers match")
+)
+
+// Process is a process running in a container
+type Process struct {
+	PID        int32   `json:"pid"`
+	PPID       int32   `json:"ppid"`
+	UID        int32   `json:"uid"`
+	State      string  `json:"state"`
+	Threads    int32   `json:"threads"`
+	CPUPercent float64 `json:"cpu_pct"`
+	RSS        uint64  `json:"rss"`
+	VMS        uint64  `json:"vms"`
+	Command    string  `json:"command"`
+}
+
+// Response is the process list of a container
+type Response struct {
+	ContainerID   string        `json:"container_id"`
+	ContainerName string        `json:"container_name"`
+	Runtime       string        `json:"runtime"`
+	Interval      time.Duration `json:"interval"`
+	Processes     []Process     `json:"processes"`
+}
+
+// Collector collects the process list of containers on demand
+type Collector struct {
+	wmeta    workloadmeta.Component
+	scrubber *procutil.DataScrubber
+	newProbe func() procutil.Probe
+	getPIDs  func(container *workloadmeta.Container) ([]int, error)
+}
+
+// NewCollector returns a Collector scrubbing the command lines with the process_config settings
+func NewCollector(wmeta workloadmeta.Component, cfg model.Reader) *Collector {
+	return &Collector{
+		wmeta:    wmeta,
+		scrubber: checks.NewDataScrubber(cfg),
+		newProbe: func() procutil.Probe { return procutil.NewProcessProbe() },
+		getPIDs: func(container *workloadmeta.Container) ([]int, error) {
+			collector := metrics.GetProvider(option.New(wmeta)).GetCollector(provider.NewRuntimeMetadata(string(container.Runtime), string(container.RuntimeFlavor)))
+			if collector == nil {
+				return nil, fmt.Errorf("no metrics collector available for runtime %s", container.Runtime)
+			}
+			return collector.GetPIDs(container.Namespace, container.ID, pidsCacheValidity)
+		},
+	}
+}
+
+// Top returns the processes running in the given container. The container can be designated
+// by its full ID, a unique prefix of its ID, or its name. The CPU usage is computed from two
+// samples taken interval apart.
+func (c *Collector) Top(ctx context.Context, containerID string, interval time.Duration) (*Response, error) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	interval = min(interval, MaxInterval)
+
+	container, err := c.findContainer(containerID)
+	if err != nil {
+		return nil, err
+	}
+	pids, err := c.getPIDs(container)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the processes of container %s: %w", container.ID, err)
+	}
+
+	probe := c.newProbe()
+	defer probe.Close()
+
+	start := time.Now()
+	all, err := probe.ProcessesByPID(start, true)
+	if err != nil {
+		return nil, err
+	}
+	procs := make(map[int32]*procutil.Process, len(pids))
+	statsPIDs := make([]int32, 0, len(pids))
+	for _, pid := range pids {
+		if p, ok := all[int32(pid)]; ok && p.Stats != nil {
+			procs[p.Pid] = p
+			statsPIDs = append(statsPIDs, p.Pid)
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(interval):
+	}
+
+	end := time.Now()
+	stats, err := probe.StatsForPIDs(statsPIDs, end)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &Response{
+		ContainerID:   container.ID,
+		ContainerName: container.Name,
+		Runtime:       string(container.Runtime),
+		Interval:      interval,
+		Processes:     make([]Process, 0, len(procs)),
+	}
+	elapsed := end.Sub(start).Seconds()
+	for pid, p := range procs {
+		// the process exited during the sampling
+		s, ok := stats[pid]
+		if !ok || s.CreateTime != p.Stats.CreateTime {
+			continue
+		}
+		resp.Processes = append(resp.Processes, c.newProcess(p, s, elapsed))
+	}
+	sort.Slice(resp.Processes, func(i, j int) bool {
+		if resp.Processes[i].CPUPercent != resp.Processes[j].CPUPercent {
+			return resp.Processes[i].CPUPercent > resp.Processes[j].CPUPercent
+		}
+		return resp.Processes[i].PID < resp.Processes[j].PID
+	})
+	return resp, nil
+}
+
+func (c *Collector) newProcess(p *procutil.Process, now *procutil.Stats, elapsed float64) Process {
+	proc := Process{
+		PID:     p.Pid,
+		PPID:    p.Ppid,
+		State:   now.Status,
+		Threads: now.NumThreads,
+		Command: strings.Join(c.scrubber.ScrubProcessCommand(p), " "),
+	}
+	if len(p.Uids) > 0 {
+		proc.UID = p.Uids[0]
+	}
+	if now.MemInfo != nil {
+		proc.RSS = now.MemInfo.RSS
+		proc.VMS = now.MemInfo.VMS
+	}
+	if before := p.Stats.CPUTime; before != nil && now.CPUTime != nil && elapsed > 0 {
+		delta := (now.CPUTime.User + now.CPUTime.System) - (before.User + before.System)
+		proc.CPUPercent = max(delta, 0) / elapsed * 100
+	}
+	return proc
+}
+
+func (c *Collector) findContainer(id string) (*workloadmeta.Container, error) {
+	if container, err := c.wmeta.GetContainer(id); err == nil {
+		return container, nil
+	}
+
+	var matches []*workloadmeta.Container
+	for _, container := range c.wmeta.ListContainers() {
+		if strings.HasPrefix(container.ID, id) || container.Name == id {
+			matches = append(matches, container)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrContainerNotFound, id)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrAmbiguousContainer, id)
+	}
+}
@@ -0,0 +1,128 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package containertop
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+
+	compConfig "github.com/DataDog/datadog-agent/comp/core/config"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	workloadmetafxmock "github.com/DataDog/datadog-agent/comp/core/workloadmeta/fx-mock"
+	workloadmetamock "github.com/DataDog/datadog-agent/comp/core/workloadmeta/mock"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil/mocks"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func newTestCollector(t *testing.T, containers ...*workloadmeta.Container) (*Collector, *mocks.Probe) {
+	wmeta := fxutil.Test[workloadmetamock.Mock](t, fx.Options(
+		fx.Provide(func() log.Component { return logmock.New(t) }),
+		compConfig.MockModule(),
+		fx.Supply(context.Background()),
+		workloadmetafxmock.MockModule(workloadmeta.NewParams()),
+	))
+	for _, container := range containers {
+		wmeta.Set(container)
+	}
+
+	probe := mocks.NewProbe(t)
+	c := NewCollector(wmeta, configmock.New(t))
+	c.newProbe = func() procutil.Probe { return probe }
+	c.getPIDs = func(container *workloadmeta.Container) ([]int, error) {
+		if container.ID == "abcdef123456" {
+			return []int{10, 11, 12}, nil
+		}
+		return nil, nil
+	}
+	return c, probe
+}
+
+func newContainer(id, name string) *workloadmeta.Container {
+	return &workloadmeta.Container{
+		EntityID:   workloadmeta.EntityID{Kind: workloadmeta.KindContainer, ID: id},
+		EntityMeta: workloadmeta.EntityMeta{Name: name},
+		Runtime:    workloadmeta.ContainerRuntimeContainerd,
+	}
+}
+
+func TestTop(t *testing.T) {
+	c, probe := newTestCollector(t, newContainer("abcdef123456", "web"))
+
+	probe.EXPECT().ProcessesByPID(mock.Anything, true).Return(map[int32]*procutil.Process{
+		1: {Pid: 1, Cmdline: []string{"/sbin/init"}, Stats: &procutil.Stats{CPUTime: &procutil.CPUTimesStat{}}},
+		10: {Pid: 10, Ppid: 1, Uids: []int32{1000}, Cmdline: []string{"server", "--password", "secret"}, Stats: &procutil.Stats{
+			CreateTime: 1, CPUTime: &procutil.CPUTimesStat{User: 1, System: 1},
+		}},
+		11: {Pid: 11, Ppid: 10, Cmdline: []string{"worker"}, Stats: &procutil.Stats{
+			CreateTime: 2, CPUTime: &procutil.CPUTimesStat{User: 5},
+		}},
+		12: {Pid: 12, Ppid: 10, Cmdline: []string{"exited"}, Stats: &procutil.Stats{
+			CreateTime: 3, CPUTime: &procutil.CPUTimesStat{},
+		}},
+	}, nil)
+	probe.EXPECT().StatsForPIDs(mock.MatchedBy(func(pids []int32) bool {
+		return assert.ElementsMatch(t, []int32{10, 11, 12}, pids)
+	}), mock.Anything).Return(map[int32]*procutil.Stats{
+		10: {CreateTime: 1, Status: "S", NumThreads: 4, CPUTime: &procutil.CPUTimesStat{User: 1, System: 1}, MemInfo: &procutil.MemoryInfoStat{RSS: 2048, VMS: 4096}},
+		11: {CreateTime: 2, Status: "R", NumThreads: 1, CPUTime: &procutil.CPUTimesStat{User: 5.5}, MemInfo: &procutil.MemoryInfoStat{RSS: 1024}},
+		// PID reused by another process
+		12: {CreateTime: 4, CPUTime: &procutil.CPUTimesStat{}},
+	}, nil)
+	probe.EXPECT().Close()
+
+	resp, err := c.Top(context.Background(), "abcdef", 50*time.Millisecond)
+	require.NoError(t, err)
+
+	assert.Equal(t, "abcdef123456", resp.ContainerID)
+	assert.Equal(t, "web", resp.ContainerName)
+	assert.Equal(t, "containerd", resp.Runtime)
+	require.Len(t, resp.Processes, 2)
+
+	worker := resp.Processes[0]
+	assert.Equal(t, int32(11), worker.PID)
+	assert.Equal(t, "R", worker.State)
+	assert.Equal(t, uint64(1024), worker.RSS)
+	// 0.5s of CPU time over at least the 50ms interval
+	assert.Greater(t, worker.CPUPercent, 0.0)
+	assert.LessOrEqual(t, worker.CPUPercent, 1000.0)
+
+	server := resp.Processes[1]
+	assert.Equal(t, int32(10), server.PID)
+	assert.Equal(t, int32(1000), server.UID)
+	assert.Equal(t, int32(4), server.Threads)
+	assert.Equal(t, 0.0, server.CPUPercent)
+	assert.Equal(t, "server --password ********", server.Command)
+}
+
+func TestTopContainerResolution(t *testing.T) {
+	c, _ := newTestCollector(t, newContainer("abcdef123456", "web"), newContainer("abc999", "db"))
+
+	container, err := c.findContainer("abcdef123456")
+	require.NoError(t, err)
+	assert.Equal(t, "web", container.Name)
+
+	container, err = c.findContainer("db")
+	require.NoError(t, err)
+	assert.Equal(t, "abc999", container.ID)
+
+	_, err = c.findContainer("abc")
+	assert.ErrorIs(t, err, ErrAmbiguousContainer)
+
+	_, err = c.Top(context.Background(), "unknown", 0)
+	assert.ErrorIs(t, err, ErrContainerNotFound)
+}
@@ -53,17 +53,19 @@ var (
 	streamServerError   = telemetry.NewSimpleCounter(subsystem, "stream_send_errors", "The number of times the grpc server has failed to send an entity diff to the core agent.")
 )
 
-// NewGRPCServer creates a new instance of a GRPCServer
+// NewGRPCServer creates a new instance of a GRPCServer. It accepts the pings of
+// the remote process collector of the core agent, created with
+// grpcutil.ClientDialOptions.
 func NewGRPCServer(config pkgconfigmodel.Reader, extractor *WorkloadMetaExtractor) *GRPCServer {
 	l := &GRPCServer{
 		config:    config,
 		extractor: extractor,
-		server: grpc.NewServer(
+		server: grpc.NewServer(append([]grpc.ServerOption{
 			grpc.Creds(insecure.NewCredentials()),
 			grpc.KeepaliveParams(keepalive.ServerParameters{
 				Time: keepaliveInterval,
 			}),
-		),
+		}, grpcutil.ServerKeepaliveOptions()...)...),
 		streamMutex: &sync.Mutex{},
 	}
 
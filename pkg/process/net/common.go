@@ -8,13 +8,12 @@
 package net
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 
 	model "github.com/DataDog/agent-payload/v5/process"
 
-	sysprobeclient "github.com/DataDog/datadog-agent/cmd/system-probe/api/client"
 	sysconfig "github.com/DataDog/datadog-agent/cmd/system-probe/config"
 	procEncoding "github.com/DataDog/datadog-agent/pkg/process/encoding"
 	reqEncoding "github.com/DataDog/datadog-agent/pkg/process/encoding/request"
@@ -22,7 +21,7 @@ import (
 )
 
 // GetProcStats returns a set of process stats by querying system-probe
-func GetProcStats(client *http.Client, pids []int32) (*model.ProcStatsWithPermByPID, error) {
+func GetProcStats(client *RPCClient, pids []int32) (*model.ProcStatsWithPermByPID, error) {
 	procReq := &pbgo.ProcessStatRequest{
 		Pids: pids,
 	}
@@ -32,30 +31,18 @@ func GetProcStats(client *http.Client, pids []int32) (*model.ProcStatsWithPermBy
 		return nil, err
 	}
 
-	url := sysprobeclient.ModuleURL(sysconfig.ProcessModule, "/stats")
-	req, err := http.NewRequest("POST", url, bytes.NewReader(reqBody))
+	body, contentType, err := client.Call(context.Background(), Request{
+		Method:      http.MethodPost,
+		Module:      sysconfig.ProcessModule,
+		Endpoint:    "/stats",
+		Body:        reqBody,
+		ContentType: procEncoding.ContentTypeProtobuf,
+		Accept:      procEncoding.ContentTypeProtobuf,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Accept", procEncoding.ContentTypeProtobuf)
-	req.Header.Set("Content-Type", procEncoding.ContentTypeProtobuf)
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("proc_stats request failed: url: %s, status code: %d", req.URL, resp.StatusCode)
-	}
-
-	body, err := sysprobeclient.ReadAllResponseBody(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	contentType := resp.Header.Get("Content-type")
 	results, err := procEncoding.GetUnmarshaler(contentType).Unmarshal(body)
 	if err != nil {
 		return nil, err
@@ -65,27 +52,14 @@ func GetProcStats(client *http.Client, pids []int32) (*model.ProcStatsWithPermBy
 }
 
 // GetNetworkID fetches the network_id (vpc_id) from system-probe
-func GetNetworkID(client *http.Client) (string, error) {
-	url := sysprobeclient.ModuleURL(sysconfig.NetworkTracerModule, "/network_id")
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Accept", "text/plain")
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("network ID request failed: url: %s, status code: %d", req.URL, resp.StatusCode)
-	}
-
-	body, err := sysprobeclient.ReadAllResponseBody(resp)
+func GetNetworkID(client *RPCClient) (string, error) {
+	body, _, err := client.Call(context.Background(), Request{
+		Module:   sysconfig.NetworkTracerModule,
+		Endpoint: "/network_id",
+		Accept:   "text/plain",
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return "", fmt.Errorf("network ID request failed: %w", err)
 	}
 
 	return string(body), nil
@@ -9,17 +9,16 @@ package net
 
 import (
 	"errors"
-	"net/http"
 
 	model "github.com/DataDog/agent-payload/v5/process"
 )
 
 // GetProcStats returns a set of process stats by querying system-probe
-func GetProcStats(_ *http.Client, _ []int32) (*model.ProcStatsWithPermByPID, error) {
+func GetProcStats(_ *RPCClient, _ []int32) (*model.ProcStatsWithPermByPID, error) {
 	return nil, errors.New("unsupported platform")
 }
 
 // GetNetworkID fetches the network_id (vpc_id) from system-probe
-func GetNetworkID(_ *http.Client) (string, error) {
+func GetNetworkID(_ *RPCClient) (string, error) {
 	return "", errors.New("unsupported platform")
 }
@@ -0,0 +1,232 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package net
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"time"
+
+	sysprobeclient "github.com/DataDog/datadog-agent/cmd/system-probe/api/client"
+	"github.com/DataDog/datadog-agent/cmd/system-probe/config/types"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/funcs"
+)
+
+const (
+	// DefaultCallTimeout bounds the calls which don't set a timeout, it matches
+	// the timeout of the system-probe HTTP client
+	DefaultCallTimeout = 10 * time.Second
+
+	moduleLabelName       = "module"
+	reusedLabelName       = "reused"
+	rpcTelemetrySubsystem = "process__sysprobe_rpc"
+)
+
+var rpcTelemetry = struct {
+	calls            telemetry.Counter
+	failedCalls      telemetry.Counter
+	deadlineExceeded telemetry.Counter
+	connections      telemetry.Counter
+	latency          telemetry.Histogram
+}{
+	telemetry.NewCounter(rpcTelemetrySubsystem, "calls__total", []string{moduleLabelName}, "Counter measuring how many calls were made to system-probe"),
+	telemetry.NewCounter(rpcTelemetrySubsystem, "calls__failed", []string{moduleLabelName}, "Counter measuring how many calls to system-probe failed"),
+	telemetry.NewCounter(rpcTelemetrySubsystem, "calls__deadline_exceeded", []string{moduleLabelName}, "Counter measuring how many calls to system-probe exceeded their deadline"),
+	telemetry.NewCounter(rpcTelemetrySubsystem, "connections", []string{moduleLabelName, reusedLabelName}, "Counter measuring how many calls to system-probe used a new or a reused connection"),
+	telemetry.NewHistogram(rpcTelemetrySubsystem, "calls__latency_seconds", []string{moduleLabelName}, "Histogram of the time to first byte of the calls to system-probe", []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}),
+}
+
+// Request describes a call to an endpoint of a system-probe module
+type Request struct {
+	Method   string
+	Module   types.ModuleName
+	Endpoint string
+	// Body is sent with the ContentType content type, if set
+	Body        []byte
+	ContentType string
+	Accept      string
+	// Timeout bounds the call, including reading the response. It defaults to
+	// DefaultCallTimeout. An earlier deadline of the context of the call takes precedence.
+	Timeout time.Duration
+}
+
+// Response is the response of a call, streamed from system-probe. It must be closed.
+type Response struct {
+	ContentType string
+	// ContentLength is -1 if the length of the body is unknown
+	ContentLength int64
+	Body          io.Reader
+
+	body   io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// ReadAll reads the whole body of the response, in a buffer pre-allocated
+// when the length of the body is known
+func (r *Response) ReadAll() ([]byte, error) {
+	if r.ContentLength <= 0 {
+		return io.ReadAll(r.Body)
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(int(r.ContentLength))
+	if _, err := buf.ReadFrom(r.Body); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Close releases the connection of the call, so that it can be reused
+func (r *Response) Close() error {
+	defer r.cancel()
+	return r.body.Close()
+}
+
+// RPCClient calls system-probe modules over the system-probe socket. Its
+// connections are shared with the system-probe HTTP client of the same socket.
+//
+// The deadline of each call is sent to system-probe, which stops working on
+// the calls its caller gave up on instead of holding one of the few in-flight
+// requests allowed per endpoint.
+type RPCClient struct {
+	client *http.Client
+}
+
+// GetRPCClient returns the RPC client of a system-probe socket
+var GetRPCClient = funcs.MemoizeArgNoError[string, *RPCClient](newRPCClient)
+
+func newRPCClient(socketPath string) *RPCClient {
+	return NewRPCClient(sysprobeclient.Get(socketPath))
+}
+
+// NewRPCClient returns a RPC client using the connections of the given
+// system-probe HTTP client. The calls are only bounded by their own timeout.
+func NewRPCClient(client *http.Client) *RPCClient {
+	return &RPCClient{
+		client: &http.Client{Transport: client.Transport},
+	}
+}
+
+// Stream sends a call to system-probe, and returns the response as soon as its
+// headers are received. The body is streamed from system-probe, bounded by the
+// deadline of the call, so that large responses such as the connections of a
+// busy host aren't cut by a fixed client timeout.
+func (c *RPCClient) Stream(ctx context.Context, r Request) (*Response, error) {
+	module := string(r.Module)
+	rpcTelemetry.calls.Inc(module)
+
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCallTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+
+	resp, err := c.do(ctx, r)
+	if err != nil {
+		cancel()
+		rpcTelemetry.failedCalls.Inc(module)
+		if errors.Is(err, context.DeadlineExceeded) {
+			rpcTelemetry.deadlineExceeded.Inc(module)
+		}
+		return nil, err
+	}
+
+	return &Response{
+		ContentType:   resp.Header.Get("Content-Type"),
+		ContentLength: resp.ContentLength,
+		Body:          &deadlineReader{reader: resp.Body, module: module},
+		body:          resp.Body,
+		cancel:        cancel,
+	}, nil
+}
+
+// Call sends a call to system-probe, and returns the whole body of the response
+// with its content type
+func (c *RPCClient) Call(ctx context.Context, r Request) ([]byte, string, error) {
+	resp, err := c.Stream(ctx, r)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Close()
+
+	body, err := resp.ReadAll()
+	if err != nil {
+		return nil, "", err
+	}
+	return body, resp.ContentType, nil
+}
+
+func (c *RPCClient) do(ctx context.Context, r Request) (*http.Response, error) {
+	module := string(r.Module)
+
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			rpcTelemetry.connections.Inc(module, strconv.FormatBool(info.Reused))
+		},
+	})
+
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	var body io.Reader
+	if r.Body != nil {
+		body = bytes.NewReader(r.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, sysprobeclient.ModuleURL(r.Module, r.Endpoint), body)
+	if err != nil {
+		return nil, err
+	}
+	if r.ContentType != "" {
+		req.Header.Set("Content-Type", r.ContentType)
+	}
+	if r.Accept != "" {
+		req.Header.Set("Accept", r.Accept)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Header.Set(sysprobeclient.TimeoutHeader, strconv.FormatInt(time.Until(deadline).Milliseconds(), 10))
+	}
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	rpcTelemetry.latency.Observe(time.Since(start).Seconds(), module)
+
+	if resp.StatusCode != http.StatusOK {
+		// drain the body so that the connection can be reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s request failed: url: %s, status code: %d", r.Module, req.URL, resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// deadlineReader counts the calls whose deadline expired while their response was read
+type deadlineReader struct {
+	reader  io.Reader
+	module  string
+	expired bool
+}
+
+func (r *deadlineReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if !r.expired && errors.Is(err, context.DeadlineExceeded) {
+		r.expired = true
+		rpcTelemetry.failedCalls.Inc(r.module)
+		rpcTelemetry.deadlineExceeded.Inc(r.module)
+	}
+	return n, err
+}
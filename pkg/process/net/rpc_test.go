@@ -0,0 +1,113 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package net
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sysprobeclient "github.com/DataDog/datadog-agent/cmd/system-probe/api/client"
+)
+
+func newTestRPCClient(t *testing.T, handler http.HandlerFunc) *RPCClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewRPCClient(&http.Client{
+		Timeout: 10 * time.Millisecond,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "tcp", server.Listener.Addr().String())
+			},
+		},
+	})
+}
+
+func TestRPCClientCall(t *testing.T) {
+	var timeoutMs int64
+	client := newTestRPCClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/test_module/endpoint", r.URL.Path)
+		assert.Equal(t, "text/plain", r.Header.Get("Accept"))
+		timeoutMs, _ = strconv.ParseInt(r.Header.Get(sysprobeclient.TimeoutHeader), 10, 64)
+
+		// the calls aren't bounded by the timeout of the HTTP client
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("response"))
+	})
+
+	body, contentType, err := client.Call(context.Background(), Request{
+		Module:   "test_module",
+		Endpoint: "/endpoint",
+		Accept:   "text/plain",
+		Timeout:  2 * time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "response", string(body))
+	assert.Equal(t, "text/plain", contentType)
+	assert.InDelta(t, 2000, timeoutMs, 100)
+
+	_, _, err = client.Call(context.Background(), Request{Module: "test_module", Endpoint: "/endpoint", Accept: "text/plain"})
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), rpcTelemetry.connections.WithValues("test_module", "false").Get())
+	assert.Equal(t, float64(1), rpcTelemetry.connections.WithValues("test_module", "true").Get())
+	assert.Equal(t, float64(2), rpcTelemetry.calls.WithValues("test_module").Get())
+}
+
+func TestRPCClientErrors(t *testing.T) {
+	client := newTestRPCClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/error_module/slow":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		default:
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	})
+
+	_, _, err := client.Call(context.Background(), Request{Module: "error_module", Endpoint: "/busy"})
+	assert.ErrorContains(t, err, "status code: 429")
+
+	_, _, err = client.Call(context.Background(), Request{Module: "error_module", Endpoint: "/slow", Timeout: 50 * time.Millisecond})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.Equal(t, float64(2), rpcTelemetry.failedCalls.WithValues("error_module").Get())
+	assert.Equal(t, float64(1), rpcTelemetry.deadlineExceeded.WithValues("error_module").Get())
+}
+
+func TestRPCClientStream(t *testing.T) {
+	client := newTestRPCClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("first"))
+		w.(http.Flusher).Flush()
+		time.Sleep(time.Second)
+		_, _ = w.Write([]byte("second"))
+	})
+
+	resp, err := client.Stream(context.Background(), Request{Module: "stream_module", Endpoint: "/stream", Timeout: 200 * time.Millisecond})
+	require.NoError(t, err)
+	defer resp.Close()
+
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(resp.Body, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(buf))
+
+	// the deadline of the call bounds the whole response
+	_, err = io.ReadAll(resp.Body)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, float64(1), rpcTelemetry.deadlineExceeded.WithValues("stream_module").Get())
+}
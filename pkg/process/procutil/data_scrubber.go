@@ -10,6 +10,8 @@ import (
 	"bytes"
 	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -39,6 +41,13 @@ type DataScrubber struct {
 	scrubbedCmdlines  map[processCacheKey][]string
 	cacheCycles       uint32 // used to control the cache age
 	cacheMaxCycles    uint32 // number of cycles before resetting the cache content
+
+	// rulesMu protects the structured scrubbing rules, applied after the sensitive words.
+	// The remote rules are updated through remote config while processes are scrubbed.
+	rulesMu      sync.RWMutex
+	configRules  []compiledScrubbingRule
+	remoteRules  []compiledScrubbingRule
+	rulesUpdated atomic.Bool // the cached cmdlines are scrubbed again once the rules are updated
 }
 
 // NewDefaultDataScrubber creates a DataScrubber with the default behavior: enabled
@@ -136,6 +145,10 @@ func (ds *DataScrubber) ScrubProcessCommand(p *Process) []string {
 		return p.Cmdline
 	}
 
+	if ds.rulesUpdated.CompareAndSwap(true, false) {
+		ds.resetCache()
+	}
+
 	pKey := createProcessKey(p)
 	if _, ok := ds.seenProcess[pKey]; !ok {
 		ds.seenProcess[pKey] = struct{}{}
@@ -155,13 +168,18 @@ func (ds *DataScrubber) ScrubProcessCommand(p *Process) []string {
 func (ds *DataScrubber) IncrementCacheAge() {
 	ds.cacheCycles++
 	if ds.cacheCycles == ds.cacheMaxCycles {
-		ds.seenProcess = make(map[processCacheKey]struct{})
-		ds.scrubbedCmdlines = make(map[processCacheKey][]string)
-		ds.cacheCycles = 0
+		ds.resetCache()
 	}
 }
 
-// ScrubCommand hides the argument value for any key which matches a "sensitive word" pattern.
+func (ds *DataScrubber) resetCache() {
+	ds.seenProcess = make(map[processCacheKey]struct{})
+	ds.scrubbedCmdlines = make(map[processCacheKey][]string)
+	ds.cacheCycles = 0
+}
+
+// ScrubCommand hides the argument value for any key which matches a "sensitive word" pattern,
+// then applies the structured scrubbing rules.
 // It returns the updated cmdline, as well as a boolean representing whether it was scrubbed
 func (ds *DataScrubber) ScrubCommand(cmdline []string) ([]string, bool) {
 	newCmdline := cmdline
@@ -180,6 +198,11 @@ func (ds *DataScrubber) ScrubCommand(cmdline []string) ([]string, bool) {
 		}
 	}
 
+	if scrubbed, ok := ds.applyScrubbingRules(cmdline, rawCmdline); ok {
+		changed = true
+		rawCmdline = scrubbed
+	}
+
 	if changed {
 		newCmdline = strings.Split(rawCmdline, " ")
 	}
@@ -192,6 +215,75 @@ func (ds *DataScrubber) AddCustomSensitiveWords(words []string) {
 	ds.SensitivePatterns = append(ds.SensitivePatterns, newPatterns...)
 }
 
+// AddScrubbingRules adds structured scrubbing rules on the DataScrubber object,
+// the invalid rules are skipped
+func (ds *DataScrubber) AddScrubbingRules(rules []ScrubbingRule) {
+	compiled := make([]compiledScrubbingRule, 0, len(rules))
+	for _, rule := range rules {
+		r, err := compileScrubbingRule(rule)
+		if err != nil {
+			log.Warnf("data scrubber: %v", err)
+			continue
+		}
+		compiled = append(compiled, r)
+	}
+
+	ds.rulesMu.Lock()
+	defer ds.rulesMu.Unlock()
+	ds.configRules = append(ds.configRules, compiled...)
+	ds.rulesUpdated.Store(true)
+}
+
+// SetRemoteScrubbingRules replaces the scrubbing rules received through remote config.
+// It returns an error without updating the rules if one of them is invalid.
+func (ds *DataScrubber) SetRemoteScrubbingRules(rules []ScrubbingRule) error {
+	compiled := make([]compiledScrubbingRule, 0, len(rules))
+	for _, rule := range rules {
+		r, err := compileScrubbingRule(rule)
+		if err != nil {
+			return err
+		}
+		compiled = append(compiled, r)
+	}
+
+	ds.rulesMu.Lock()
+	defer ds.rulesMu.Unlock()
+	ds.remoteRules = compiled
+	ds.rulesUpdated.Store(true)
+	return nil
+}
+
+// ValidateScrubbingRules returns an error if one of the scrubbing rules is invalid
+func ValidateScrubbingRules(rules []ScrubbingRule) error {
+	for _, rule := range rules {
+		if _, err := compileScrubbingRule(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyScrubbingRules applies the structured scrubbing rules matching the
+// executable of the cmdline to the raw cmdline
+func (ds *DataScrubber) applyScrubbingRules(cmdline []string, rawCmdline string) (string, bool) {
+	ds.rulesMu.RLock()
+	defer ds.rulesMu.RUnlock()
+
+	changed := false
+	for _, rules := range [][]compiledScrubbingRule{ds.configRules, ds.remoteRules} {
+		for i := range rules {
+			if !rules[i].matchesExe(cmdline) {
+				continue
+			}
+			if scrubbed, ok := rules[i].scrub(rawCmdline); ok {
+				rawCmdline = scrubbed
+				changed = true
+			}
+		}
+	}
+	return rawCmdline, changed
+}
+
 // wordToFastChecker returns a string that can be used to do a first fast lookup before doing the full
 // regex search
 // for example `wordToFastChecker("*aa*bbb*") = "bbb"`
@@ -0,0 +1,147 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package procutil
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const redactedValue = "********"
+
+// envVarNameRegex matches valid environment variable names
+var envVarNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ScrubbingRule is a structured rule scrubbing the command line of processes,
+// configured with process_config.scrubbing_rules or through remote config.
+type ScrubbingRule struct {
+	// Name identifies the rule in the logs
+	Name string `mapstructure:"name" json:"name"`
+	// Exe restricts the rule to the processes whose executable name matches
+	// this glob pattern, the rule applies to all processes when it's empty
+	Exe string `mapstructure:"exe" json:"exe"`
+	// EnvVars are the environment variables whose value is redacted when they
+	// are set in the command line, such as `env TOKEN=value` or `-e TOKEN=value`
+	EnvVars []string `mapstructure:"env_vars" json:"env_vars"`
+	// Pattern is a regex whose capture groups are redacted, keeping the rest
+	// of the match. The whole match is redacted when it has no capture group.
+	Pattern string `mapstructure:"pattern" json:"pattern"`
+}
+
+// compiledScrubbingRule is a ScrubbingRule ready to be applied
+type compiledScrubbingRule struct {
+	exe      string
+	patterns []*regexp.Regexp
+}
+
+// compileScrubbingRule validates and compiles a scrubbing rule
+func compileScrubbingRule(rule ScrubbingRule) (compiledScrubbingRule, error) {
+	compiled := compiledScrubbingRule{
+		exe: rule.Exe,
+	}
+	if rule.Name == "" {
+		return compiled, errors.New("scrubbing rules must have a name")
+	}
+	if len(rule.EnvVars) == 0 && rule.Pattern == "" {
+		return compiled, fmt.Errorf("scrubbing rule %s: env_vars or pattern must be set", rule.Name)
+	}
+	if rule.Exe != "" {
+		if _, err := path.Match(rule.Exe, ""); err != nil {
+			return compiled, fmt.Errorf("scrubbing rule %s: invalid exe pattern %q: %v", rule.Name, rule.Exe, err)
+		}
+	}
+
+	if len(rule.EnvVars) > 0 {
+		names := make([]string, 0, len(rule.EnvVars))
+		for _, name := range rule.EnvVars {
+			if !envVarNameRegex.MatchString(name) {
+				return compiled, fmt.Errorf("scrubbing rule %s: invalid environment variable name %q", rule.Name, name)
+			}
+			names = append(names, name)
+		}
+		// NAME=value at the start of an argument, or after a flag such as --env=NAME=value
+		re := regexp.MustCompile(`(?:^|[ =])(?:` + strings.Join(names, "|") + `)=("[^"]*"|'[^']*'|[^\s]*)`)
+		compiled.patterns = append(compiled.patterns, re)
+	}
+
+	if rule.Pattern != "" {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return compiled, fmt.Errorf("scrubbing rule %s: invalid pattern: %v", rule.Name, err)
+		}
+		compiled.patterns = append(compiled.patterns, re)
+	}
+	return compiled, nil
+}
+
+// matchesExe returns whether the rule applies to the process running the given command line
+func (r *compiledScrubbingRule) matchesExe(cmdline []string) bool {
+	if r.exe == "" {
+		return true
+	}
+	if len(cmdline) == 0 {
+		return false
+	}
+	matched, _ := path.Match(r.exe, filepath.Base(cmdline[0]))
+	return matched
+}
+
+// scrub redacts the matches of the rule in the raw command line, it returns
+// whether the command line changed
+func (r *compiledScrubbingRule) scrub(rawCmdline string) (string, bool) {
+	changed := false
+	for _, re := range r.patterns {
+		scrubbed, ok := redactMatches(re, rawCmdline)
+		if ok {
+			rawCmdline = scrubbed
+			changed = true
+		}
+	}
+	return rawCmdline, changed
+}
+
+// redactMatches redacts the capture groups of each match of the regex, or the
+// whole match if the regex has no capture group
+func redactMatches(re *regexp.Regexp, s string) (string, bool) {
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, false
+	}
+
+	var b strings.Builder
+	last := 0
+	changed := false
+	for _, match := range matches {
+		if re.NumSubexp() == 0 {
+			b.WriteString(s[last:match[0]])
+			b.WriteString(redactedValue)
+			last = match[1]
+			changed = true
+			continue
+		}
+		for group := 1; group <= re.NumSubexp(); group++ {
+			start, end := match[2*group], match[2*group+1]
+			// skip the groups that didn't participate in the match, and the
+			// groups nested in a group already redacted
+			if start < 0 || start < last || start == end {
+				continue
+			}
+			b.WriteString(s[last:start])
+			b.WriteString(redactedValue)
+			last = end
+			changed = true
+		}
+	}
+	if !changed {
+		return s, false
+	}
+	b.WriteString(s[last:])
+	return b.String(), true
+}
@@ -0,0 +1,126 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build !windows
+
+package procutil
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrubbingRules(t *testing.T) {
+	cases := []struct {
+		name          string
+		rule          ScrubbingRule
+		cmdline       []string
+		parsedCmdline []string
+	}{
+		{
+			name:          "env var",
+			rule:          ScrubbingRule{Name: "env", EnvVars: []string{"TOKEN", "DB_URL"}},
+			cmdline:       []string{"env", "TOKEN=abcd", "DB_URL='postgres://u:p@db'", "OTHER=1", "./server"},
+			parsedCmdline: []string{"env", "TOKEN=********", "DB_URL=********", "OTHER=1", "./server"},
+		},
+		{
+			name:          "env var flag",
+			rule:          ScrubbingRule{Name: "env", EnvVars: []string{"TOKEN"}},
+			cmdline:       []string{"docker", "run", "-e", "TOKEN=abcd", "--env=TOKEN=efgh", "-e", "MY_TOKEN=1", "image"},
+			parsedCmdline: []string{"docker", "run", "-e", "TOKEN=********", "--env=TOKEN=********", "-e", "MY_TOKEN=1", "image"},
+		},
+		{
+			name:          "capture groups are redacted",
+			rule:          ScrubbingRule{Name: "dsn", Pattern: `://([^:/]+):([^@]+)@`},
+			cmdline:       []string{"worker", "--db", "postgres://user:pass@db:5432/app"},
+			parsedCmdline: []string{"worker", "--db", "postgres://********:********@db:5432/app"},
+		},
+		{
+			name:          "whole match is redacted without capture group",
+			rule:          ScrubbingRule{Name: "base64", Pattern: `[A-Za-z0-9+/]{32,}={0,2}`},
+			cmdline:       []string{"job", "--payload", "c2VjcmV0LXRva2VuLXZhbHVlLWZvci10aGUtam9i", "--retries", "3"},
+			parsedCmdline: []string{"job", "--payload", "********", "--retries", "3"},
+		},
+		{
+			name:          "optional group",
+			rule:          ScrubbingRule{Name: "key", Pattern: `--key(?:=(\S+))?`},
+			cmdline:       []string{"app", "--key", "--key=abcd"},
+			parsedCmdline: []string{"app", "--key", "--key=********"},
+		},
+		{
+			name:          "matching exe",
+			rule:          ScrubbingRule{Name: "java", Exe: "java*", Pattern: `-Dtoken=(\S+)`},
+			cmdline:       []string{"/usr/bin/java11", "-Dtoken=abcd", "-jar", "app.jar"},
+			parsedCmdline: []string{"/usr/bin/java11", "-Dtoken=********", "-jar", "app.jar"},
+		},
+		{
+			name:          "other exe",
+			rule:          ScrubbingRule{Name: "java", Exe: "java*", Pattern: `-Dtoken=(\S+)`},
+			cmdline:       []string{"/usr/bin/python3", "-Dtoken=abcd"},
+			parsedCmdline: []string{"/usr/bin/python3", "-Dtoken=abcd"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			scrubber := NewDefaultDataScrubber()
+			scrubber.AddScrubbingRules([]ScrubbingRule{tc.rule})
+			cmdline, changed := scrubber.ScrubCommand(tc.cmdline)
+			assert.Equal(t, tc.parsedCmdline, cmdline)
+			assert.Equal(t, !slices.Equal(tc.cmdline, tc.parsedCmdline), changed)
+		})
+	}
+}
+
+func TestScrubbingRulesWithSensitiveWords(t *testing.T) {
+	scrubber := NewDefaultDataScrubber()
+	scrubber.AddScrubbingRules([]ScrubbingRule{{Name: "env", EnvVars: []string{"TOKEN"}}})
+
+	cmdline, changed := scrubber.ScrubCommand([]string{"env", "TOKEN=abcd", "./server", "--password=1234"})
+	assert.True(t, changed)
+	assert.Equal(t, []string{"env", "TOKEN=********", "./server", "--password=********"}, cmdline)
+}
+
+func TestInvalidScrubbingRules(t *testing.T) {
+	for _, rule := range []ScrubbingRule{
+		{Pattern: "secret"},
+		{Name: "empty"},
+		{Name: "exe", Exe: "[java", Pattern: "secret"},
+		{Name: "env", EnvVars: []string{"MY-TOKEN"}},
+		{Name: "pattern", Pattern: "(secret"},
+	} {
+		assert.Error(t, ValidateScrubbingRules([]ScrubbingRule{rule}), rule.Name)
+	}
+
+	// the invalid rules are skipped
+	scrubber := NewDefaultDataScrubber()
+	scrubber.AddScrubbingRules([]ScrubbingRule{
+		{Name: "pattern", Pattern: "(secret"},
+		{Name: "env", EnvVars: []string{"TOKEN"}},
+	})
+	cmdline, _ := scrubber.ScrubCommand([]string{"env", "TOKEN=abcd"})
+	assert.Equal(t, []string{"env", "TOKEN=********"}, cmdline)
+}
+
+func TestRemoteScrubbingRules(t *testing.T) {
+	scrubber := NewDefaultDataScrubber()
+	p := &Process{Pid: 1, Cmdline: []string{"env", "TOKEN=abcd", "API=efgh"}, Stats: &Stats{CreateTime: 1}}
+
+	assert.Equal(t, p.Cmdline, scrubber.ScrubProcessCommand(p))
+
+	require.NoError(t, scrubber.SetRemoteScrubbingRules([]ScrubbingRule{{Name: "env", EnvVars: []string{"TOKEN"}}}))
+	// the cached cmdline is scrubbed again with the new rules
+	assert.Equal(t, []string{"env", "TOKEN=********", "API=efgh"}, scrubber.ScrubProcessCommand(p))
+
+	// an invalid update leaves the rules unchanged
+	assert.Error(t, scrubber.SetRemoteScrubbingRules([]ScrubbingRule{{Name: "env", EnvVars: []string{"API"}}, {Name: "invalid"}}))
+	assert.Equal(t, []string{"env", "TOKEN=********", "API=efgh"}, scrubber.ScrubProcessCommand(p))
+
+	require.NoError(t, scrubber.SetRemoteScrubbingRules(nil))
+	assert.Equal(t, p.Cmdline, scrubber.ScrubProcessCommand(p))
+}
@@ -2514,9 +2514,9 @@ func (z *GetStateConfigResponse) Msgsize() (s int) {
 // MarshalMsg implements msgp.Marshaler
 func (z *LatestConfigsRequest) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 15
+	// map header, size 16
 	// string "Hostname"
-	o = append(o, 0x8f, 0xa8, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65)
+	o = append(o, 0xde, 0x0, 0x10, 0xa8, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65)
 	o = msgp.AppendString(o, z.Hostname)
 	// string "AgentVersion"
 	o = append(o, 0xac, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e)
@@ -2580,6 +2580,12 @@ func (z *LatestConfigsRequest) MarshalMsg(b []byte) (o []byte, err error) {
 	// string "AgentUuid"
 	o = append(o, 0xa9, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x55, 0x75, 0x69, 0x64)
 	o = msgp.AppendString(o, z.AgentUuid)
+	// string "HaltedRollouts"
+	o = append(o, 0xae, 0x48, 0x61, 0x6c, 0x74, 0x65, 0x64, 0x52, 0x6f, 0x6c, 0x6c, 0x6f, 0x75, 0x74, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.HaltedRollouts)))
+	for za0005 := range z.HaltedRollouts {
+		o = msgp.AppendString(o, z.HaltedRollouts[za0005])
+	}
 	return
 }
 
@@ -2754,6 +2760,25 @@ func (z *LatestConfigsRequest) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				err = msgp.WrapError(err, "AgentUuid")
 				return
 			}
+		case "HaltedRollouts":
+			var zb0006 uint32
+			zb0006, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "HaltedRollouts")
+				return
+			}
+			if cap(z.HaltedRollouts) >= int(zb0006) {
+				z.HaltedRollouts = (z.HaltedRollouts)[:zb0006]
+			} else {
+				z.HaltedRollouts = make([]string, zb0006)
+			}
+			for za0005 := range z.HaltedRollouts {
+				z.HaltedRollouts[za0005], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "HaltedRollouts", za0005)
+					return
+				}
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -2768,7 +2793,7 @@ func (z *LatestConfigsRequest) UnmarshalMsg(bts []byte) (o []byte, err error) {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *LatestConfigsRequest) Msgsize() (s int) {
-	s = 1 + 9 + msgp.StringPrefixSize + len(z.Hostname) + 13 + msgp.StringPrefixSize + len(z.AgentVersion) + 29 + msgp.Uint64Size + 25 + msgp.Uint64Size + 27 + msgp.Uint64Size + 9 + msgp.ArrayHeaderSize
+	s = 3 + 9 + msgp.StringPrefixSize + len(z.Hostname) + 13 + msgp.StringPrefixSize + len(z.AgentVersion) + 29 + msgp.Uint64Size + 25 + msgp.Uint64Size + 27 + msgp.Uint64Size + 9 + msgp.ArrayHeaderSize
 	for za0001 := range z.Products {
 		s += msgp.StringPrefixSize + len(z.Products[za0001])
 	}
@@ -2788,7 +2813,10 @@ func (z *LatestConfigsRequest) Msgsize() (s int) {
 	for za0004 := range z.Tags {
 		s += msgp.StringPrefixSize + len(z.Tags[za0004])
 	}
-	s += 10 + msgp.StringPrefixSize + len(z.AgentUuid)
+	s += 10 + msgp.StringPrefixSize + len(z.AgentUuid) + 15 + msgp.ArrayHeaderSize
+	for za0005 := range z.HaltedRollouts {
+		s += msgp.StringPrefixSize + len(z.HaltedRollouts[za0005])
+	}
 	return
 }
 
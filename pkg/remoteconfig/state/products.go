@@ -35,6 +35,12 @@ var validProducts = map[string]struct{}{
 	ProductOrchestratorK8sCRDs:          {},
 	ProductHaAgent:                      {},
 	ProductNDMDeviceProfilesCustom:      {},
+	ProductNDMDeviceProfilesDD:          {},
+	ProductMetricControl:                {},
+	ProductProcessScrubbingRules:        {},
+	ProductAPMInstrumentation:           {},
+	ProductMetricTransformations:        {},
+	ProductCSMComplianceCustom:          {},
 }
 
 const (
@@ -96,4 +102,16 @@ const (
 	ProductHaAgent = "HA_AGENT"
 	// ProductNDMDeviceProfilesCustom receives user-created SNMP profiles for network device monitoring
 	ProductNDMDeviceProfilesCustom = "NDM_DEVICE_PROFILES_CUSTOM"
+	// ProductNDMDeviceProfilesDD receives the SNMP profiles maintained by Datadog, updating the profiles bundled with the agent
+	ProductNDMDeviceProfilesDD = "NDM_DEVICE_PROFILES_DD"
+	// ProductMetricControl receives the metrics the dogstatsd server must drop
+	ProductMetricControl = "METRIC_CONTROL"
+	// ProductProcessScrubbingRules receives the rules scrubbing the command line of the collected processes
+	ProductProcessScrubbingRules = "PROCESS_SCRUBBING_RULES"
+	// ProductAPMInstrumentation receives the library versions and namespaces of the admission controller auto instrumentation
+	ProductAPMInstrumentation = "APM_INSTRUMENTATION"
+	// ProductMetricTransformations receives the rules renaming, dropping or removing tags from the metrics before they are aggregated
+	ProductMetricTransformations = "METRIC_TRANSFORMATIONS"
+	// ProductCSMComplianceCustom receives the user-provided compliance benchmarks run by the security agent
+	ProductCSMComplianceCustom = "CSM_COMPLIANCE_CUSTOM"
 )
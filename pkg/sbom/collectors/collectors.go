@@ -29,6 +29,8 @@ const (
 	ContainerdCollector = "containerd"
 	// CrioCollector is the name of the containerd collector
 	CrioCollector = "crio"
+	// CriCollector is the name of the CRI collector
+	CriCollector = "cri"
 	// DockerCollector is the name of the docker collector
 	DockerCollector = "docker"
 	// HostCollector is the name of the host collector
@@ -53,6 +55,13 @@ type Collector interface {
 	Shutdown()
 }
 
+// ImageScanRequestBuilder is implemented by the container image collectors
+// supporting on-demand scans
+type ImageScanRequestBuilder interface {
+	// NewImageScanRequest returns a scan request for the given image ID
+	NewImageScanRequest(imageID string) sbom.ScanRequest
+}
+
 // Collectors values
 var Collectors map[string]Collector
 
@@ -80,6 +89,11 @@ func GetCrioScanner() Collector {
 	return Collectors[CrioCollector]
 }
 
+// GetCriScanner returns the CRI scanner
+func GetCriScanner() Collector {
+	return Collectors[CriCollector]
+}
+
 // GetHostScanner returns the host scanner
 func GetHostScanner() Collector {
 	return Collectors[HostCollector]
@@ -133,6 +133,11 @@ func (c *Collector) Scan(ctx context.Context, request sbom.ScanRequest) sbom.Sca
 	return scanResult
 }
 
+// NewImageScanRequest returns a scan request for the given image ID
+func (c *Collector) NewImageScanRequest(imageID string) sbom.ScanRequest {
+	return NewScanRequest(imageID)
+}
+
 // Type returns the container image scan type
 func (c *Collector) Type() collectors.ScanType {
 	return collectors.ContainerImageScanType
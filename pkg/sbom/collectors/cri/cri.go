@@ -0,0 +1,215 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri && crio && trivy && linux
+
+package cri
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	imgspecs "github.com/opencontainers/image-spec/specs-go/v1"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/sbom"
+	"github.com/DataDog/datadog-agent/pkg/sbom/collectors"
+	crioUtil "github.com/DataDog/datadog-agent/pkg/util/crio"
+	"github.com/DataDog/datadog-agent/pkg/util/option"
+	"github.com/DataDog/datadog-agent/pkg/util/trivy"
+)
+
+const resultChanSize = 1000
+
+// scanRequest defines a scan request. This struct should be
+// hashable to be pushed in the work queue for processing.
+type scanRequest struct {
+	imageID string
+}
+
+// NewScanRequest creates a new scan request
+func NewScanRequest(imageID string) sbom.ScanRequest {
+	return scanRequest{imageID: imageID}
+}
+
+// Collector returns the collector name for the scan request
+func (r scanRequest) Collector() string {
+	return collectors.CriCollector
+}
+
+// Type returns the scan request type based on ScanOptions
+func (r scanRequest) Type(_ sbom.ScanOptions) string {
+	return sbom.ScanFilesystemType
+}
+
+// ID returns the scan request ID
+func (r scanRequest) ID() string {
+	return r.imageID
+}
+
+// Collector defines a CRI SBOM collector.
+//
+// The CRI image service doesn't give access to the content of the images, so
+// the layers of an image are read from the containers/storage image store of
+// the runtime, e.g. CRI-O, located with the diff IDs of its verbose image
+// status. The images of the runtimes using another image store can't be
+// scanned.
+type Collector struct {
+	trivyCollector *trivy.Collector
+	resChan        chan sbom.ScanResult
+	opts           sbom.ScanOptions
+	criClient      crioUtil.Client
+	wmeta          option.Option[workloadmeta.Component]
+
+	closed bool
+}
+
+// CleanCache cleans the cache in the trivy collector
+func (c *Collector) CleanCache() error {
+	return c.trivyCollector.CleanCache()
+}
+
+// Init initializes the collector with configuration and workloadmeta component
+func (c *Collector) Init(cfg config.Component, wmeta option.Option[workloadmeta.Component]) error {
+	trivyCollector, err := trivy.GetGlobalCollector(cfg, wmeta)
+	if err != nil {
+		return err
+	}
+	c.wmeta = wmeta
+	c.trivyCollector = trivyCollector
+	c.opts = sbom.ScanOptionsFromConfigForContainers(cfg)
+	return nil
+}
+
+// Scan performs the scan of the layers of the image in the image store
+func (c *Collector) Scan(ctx context.Context, request sbom.ScanRequest) sbom.ScanResult {
+	imageID := request.ID()
+
+	if c.criClient == nil {
+		cl, err := crioUtil.NewCRIOClient()
+		if err != nil {
+			return sbom.ScanResult{Error: fmt.Errorf("error creating CRI client: %w", err)}
+		}
+		c.criClient = cl
+	}
+
+	wmeta, ok := c.wmeta.Get()
+	if !ok {
+		return sbom.ScanResult{Error: fmt.Errorf("workloadmeta store is not initialized")}
+	}
+
+	imageMeta, err := wmeta.GetImage(imageID)
+	if err != nil {
+		return sbom.ScanResult{Error: fmt.Errorf("image metadata not found for image ID %s: %w", imageID, err)}
+	}
+
+	scannedImage, err := c.withImageLayers(ctx, imageMeta)
+	if err != nil {
+		return sbom.ScanResult{Error: err, ImgMeta: imageMeta}
+	}
+
+	report, err := c.trivyCollector.ScanCRIOImageFromOverlayFS(ctx, scannedImage, c.criClient, c.opts)
+
+	return sbom.ScanResult{
+		Error:   err,
+		Report:  report,
+		ImgMeta: imageMeta,
+	}
+}
+
+// imageStatusInfo is the part of the verbose CRI image status describing the image config
+type imageStatusInfo struct {
+	ImageSpec imgspecs.Image `json:"imageSpec"`
+}
+
+// withImageLayers returns a copy of the image metadata with the layers of its
+// config, as the CRI image list doesn't report them
+func (c *Collector) withImageLayers(ctx context.Context, imageMeta *workloadmeta.ContainerImageMetadata) (*workloadmeta.ContainerImageMetadata, error) {
+	status, err := c.criClient.GetContainerImage(ctx, &criv1.ImageSpec{Image: imageMeta.ID}, true)
+	if err != nil {
+		return nil, err
+	}
+	rawInfo, ok := status.GetInfo()["info"]
+	if !ok {
+		return nil, fmt.Errorf("the runtime doesn't report the config of image %s", imageMeta.ID)
+	}
+	var info imageStatusInfo
+	if err := json.Unmarshal([]byte(rawInfo), &info); err != nil {
+		return nil, fmt.Errorf("unable to parse the config of image %s: %w", imageMeta.ID, err)
+	}
+	if len(info.ImageSpec.RootFS.DiffIDs) == 0 {
+		return nil, fmt.Errorf("no layers found in the config of image %s", imageMeta.ID)
+	}
+
+	scannedImage := imageMeta.DeepCopy().(*workloadmeta.ContainerImageMetadata)
+	scannedImage.OS = info.ImageSpec.OS
+	scannedImage.Architecture = info.ImageSpec.Architecture
+	scannedImage.Layers = imageLayers(info.ImageSpec)
+	return scannedImage, nil
+}
+
+// imageLayers returns the non-empty layers of the image config with their history
+func imageLayers(spec imgspecs.Image) []workloadmeta.ContainerImageLayer {
+	var nonEmptyHistory []imgspecs.History
+	for _, history := range spec.History {
+		if !history.EmptyLayer {
+			nonEmptyHistory = append(nonEmptyHistory, history)
+		}
+	}
+
+	layers := make([]workloadmeta.ContainerImageLayer, 0, len(spec.RootFS.DiffIDs))
+	for i, diffID := range spec.RootFS.DiffIDs {
+		history := &imgspecs.History{Created: &time.Time{}}
+		if i < len(nonEmptyHistory) {
+			*history = nonEmptyHistory[i]
+			if history.Created == nil {
+				history.Created = &time.Time{}
+			}
+		}
+		layers = append(layers, workloadmeta.ContainerImageLayer{
+			Digest:  diffID.String(),
+			History: history,
+		})
+	}
+	return layers
+}
+
+// NewImageScanRequest returns a scan request for the given image ID
+func (c *Collector) NewImageScanRequest(imageID string) sbom.ScanRequest {
+	return NewScanRequest(imageID)
+}
+
+// Type returns the container image scan type
+func (c *Collector) Type() collectors.ScanType {
+	return collectors.ContainerImageScanType
+}
+
+// Channel returns the channel to send scan results
+func (c *Collector) Channel() chan sbom.ScanResult {
+	return c.resChan
+}
+
+// Options returns the collector options
+func (c *Collector) Options() sbom.ScanOptions {
+	return c.opts
+}
+
+// Shutdown shuts down the collector
+func (c *Collector) Shutdown() {
+	if c.resChan != nil && !c.closed {
+		close(c.resChan)
+	}
+	c.closed = true
+}
+
+func init() {
+	collectors.RegisterCollector(collectors.CriCollector, &Collector{
+		resChan: make(chan sbom.ScanResult, resultChanSize),
+	})
+}
@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package scanner
+
+import (
+	"errors"
+	"fmt"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/config/env"
+	"github.com/DataDog/datadog-agent/pkg/sbom/collectors"
+)
+
+// ErrImageNotFound is returned when the image of an on-demand scan isn't known to workloadmeta
+var ErrImageNotFound = errors.New("image not found")
+
+// imageCollectorsByFeature lists, by order of preference, the container image collectors
+// used for on-demand scans. The CRI collector is only used when no other runtime socket is
+// reachable, consistently with the workloadmeta collectors.
+var imageCollectorsByFeature = []struct {
+	feature   env.Feature
+	collector string
+}{
+	{env.Containerd, collectors.ContainerdCollector},
+	{env.Crio, collectors.CrioCollector},
+	{env.Docker, collectors.DockerCollector},
+	{env.Cri, collectors.CriCollector},
+}
+
+// ScanImage enqueues an on-demand scan of a container image. The result is sent to the
+// collector channel, like for any other scan, which stores it in workloadmeta.
+// When collectorName is empty, the collector of the detected container runtime is used.
+func (s *Scanner) ScanImage(imageID string, collectorName string) error {
+	if _, err := s.getImage(imageID); err != nil {
+		return err
+	}
+
+	if collectorName == "" {
+		collectorName = defaultImageCollector()
+		if collectorName == "" {
+			return errors.New("no container runtime detected")
+		}
+	}
+	collector := s.GetCollector(collectorName)
+	if collector == nil {
+		return fmt.Errorf("unknown SBOM collector '%s'", collectorName)
+	}
+	builder, ok := collector.(collectors.ImageScanRequestBuilder)
+	if !ok {
+		return fmt.Errorf("SBOM collector '%s' doesn't support on-demand container image scans", collectorName)
+	}
+
+	// on-demand scans aren't delayed by the backoff of previous failures
+	request := builder.NewImageScanRequest(imageID)
+	s.scanQueue.Forget(request)
+	return s.Scan(request)
+}
+
+// GetImageSBOM returns the SBOM of a container image, as stored in workloadmeta
+func (s *Scanner) GetImageSBOM(imageID string) (*workloadmeta.SBOM, error) {
+	image, err := s.getImage(imageID)
+	if err != nil {
+		return nil, err
+	}
+	return image.SBOM, nil
+}
+
+func (s *Scanner) getImage(imageID string) (*workloadmeta.ContainerImageMetadata, error) {
+	store, ok := s.wmeta.Get()
+	if !ok {
+		return nil, errors.New("workloadmeta store is not initialized")
+	}
+	image, err := store.GetImage(imageID)
+	if err != nil || image == nil {
+		return nil, fmt.Errorf("%w: %s", ErrImageNotFound, imageID)
+	}
+	return image, nil
+}
+
+func defaultImageCollector() string {
+	for _, c := range imageCollectorsByFeature {
+		if env.IsFeaturePresent(c.feature) {
+			return c.collector
+		}
+	}
+	return ""
+}
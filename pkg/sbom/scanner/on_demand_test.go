@@ -0,0 +1,99 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package scanner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+
+	compConfig "github.com/DataDog/datadog-agent/comp/core/config"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	workloadmetafxmock "github.com/DataDog/datadog-agent/comp/core/workloadmeta/fx-mock"
+	workloadmetamock "github.com/DataDog/datadog-agent/comp/core/workloadmeta/mock"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+	"github.com/DataDog/datadog-agent/pkg/sbom"
+	"github.com/DataDog/datadog-agent/pkg/sbom/collectors"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+	"github.com/DataDog/datadog-agent/pkg/util/option"
+)
+
+type mockImageCollector struct {
+	*collectors.MockCollector
+}
+
+func (m mockImageCollector) NewImageScanRequest(imageID string) sbom.ScanRequest {
+	return &scanRequest{collectorName: "image", id: imageID, scanRequestType: sbom.ScanFilesystemType}
+}
+
+func TestScanImage(t *testing.T) {
+	cfg := configmock.New(t)
+	cfg.Set("sbom.cache.clean_interval", "10s", model.SourceAgentRuntime) // Required for the ticker
+
+	workloadmetaStore := fxutil.Test[workloadmetamock.Mock](t, fx.Options(
+		fx.Provide(func() log.Component { return logmock.New(t) }),
+		compConfig.MockModule(),
+		fx.Supply(context.Background()),
+		workloadmetafxmock.MockModule(workloadmeta.NewParams()),
+	))
+
+	imageID := "id"
+	workloadmetaStore.Set(&workloadmeta.ContainerImageMetadata{
+		EntityID: workloadmeta.EntityID{
+			ID:   imageID,
+			Kind: workloadmeta.KindContainerImageMetadata,
+		},
+		SBOM: &workloadmeta.SBOM{Status: workloadmeta.Pending},
+	})
+
+	imageCollector := mockImageCollector{collectors.NewMockCollector()}
+	resultCh := make(chan sbom.ScanResult, 1)
+	expectedResult := sbom.ScanResult{Report: mockReport{id: imageID}}
+	imageCollector.On("Options").Return(sbom.ScanOptions{})
+	imageCollector.On("Scan", mock.Anything, mock.Anything).Return(expectedResult).Once()
+	imageCollector.On("Channel").Return(resultCh)
+	imageCollector.On("Type").Return(collectors.ContainerImageScanType)
+	imageCollector.On("Shutdown")
+
+	hostCollector := collectors.NewMockCollector()
+	hostCollector.On("Shutdown")
+
+	scanner := NewScanner(cfg, map[string]collectors.Collector{
+		"image": imageCollector,
+		"host":  hostCollector,
+	}, option.New[workloadmeta.Component](workloadmetaStore))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scanner.Start(ctx)
+
+	assert.ErrorIs(t, scanner.ScanImage("unknown", "image"), ErrImageNotFound)
+	assert.ErrorContains(t, scanner.ScanImage(imageID, "unknown"), "unknown SBOM collector")
+	assert.ErrorContains(t, scanner.ScanImage(imageID, "host"), "doesn't support on-demand")
+
+	require.NoError(t, scanner.ScanImage(imageID, "image"))
+	select {
+	case res := <-resultCh:
+		assert.Equal(t, expectedResult.Report, res.Report)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no scan result received")
+	}
+
+	sbom, err := scanner.GetImageSBOM(imageID)
+	require.NoError(t, err)
+	assert.Equal(t, workloadmeta.Pending, sbom.Status)
+	_, err = scanner.GetImageSBOM("unknown")
+	assert.ErrorIs(t, err, ErrImageNotFound)
+}
@@ -12,15 +12,14 @@ import (
 	"fmt"
 	"net"
 	"runtime"
-	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/backoff"
 	"google.golang.org/grpc/credentials/insecure"
 
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
 	"github.com/DataDog/datadog-agent/pkg/security/config"
 	"github.com/DataDog/datadog-agent/pkg/security/proto/api"
+	grpcutil "github.com/DataDog/datadog-agent/pkg/util/grpc"
 )
 
 // RuntimeSecurityClient is used to send request to security module
@@ -193,18 +192,14 @@ func NewRuntimeSecurityClient() (*RuntimeSecurityClient, error) {
 		return nil, fmt.Errorf("unix sockets are not supported on Windows")
 	}
 
-	conn, err := grpc.Dial( //nolint:staticcheck // TODO (ASC) fix grpc.Dial is deprecated
+	conn, err := grpcutil.NewClientConn(
+		context.Background(),
+		"runtime_security",
 		socketPath,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(api.VTProtoCodecName)),
 		grpc.WithContextDialer(func(_ context.Context, url string) (net.Conn, error) {
 			return net.Dial(family, url)
-		}),
-		grpc.WithConnectParams(grpc.ConnectParams{
-			Backoff: backoff.Config{
-				BaseDelay: time.Second,
-				MaxDelay:  time.Second,
-			},
 		}))
 	if err != nil {
 		return nil, err
@@ -8,6 +8,7 @@ package config
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math"
 	"net"
@@ -60,6 +61,20 @@ type RuntimeSecurityConfig struct {
 	EventServerRate int
 	// EventServerRetention defines an event retention period so that some fields can be resolved
 	EventServerRetention time.Duration
+	// EventLocalSinkEnabled defines whether the events are mirrored to a local sink
+	EventLocalSinkEnabled bool
+	// EventLocalSinkType defines the type of the local sink, one of "unix", "unixgram" or "syslog"
+	EventLocalSinkType string
+	// EventLocalSinkAddress defines the path of the unix socket of the local sink
+	EventLocalSinkAddress string
+	// EventLocalSinkRate defines the rate at which events can be mirrored to the local sink
+	EventLocalSinkRate int
+	// EventLocalSinkBurst defines the maximum burst of events that can be mirrored to the local sink
+	EventLocalSinkBurst int
+	// EventLocalSinkRuleTags restricts the events mirrored to the local sink to the rules having one of these tags
+	EventLocalSinkRuleTags []string
+	// EventLocalSinkQueueSize defines the maximum number of events waiting to be written to the local sink
+	EventLocalSinkQueueSize int
 	// FIMEnabled determines whether fim rules will be loaded
 	FIMEnabled bool
 	// SelfTestEnabled defines if the self tests should be executed at startup or not
@@ -164,6 +179,18 @@ type RuntimeSecurityConfig struct {
 	SecurityProfileMaxCount int
 	// SecurityProfileDNSMatchMaxDepth defines the max depth of subdomain to be matched for DNS anomaly detection (0 to match everything)
 	SecurityProfileDNSMatchMaxDepth int
+	// SecurityProfileDriftStableScoreThreshold defines the drift score (number of nodes added to a profile during the
+	// last hour) at or below which a profile is stabilizing
+	SecurityProfileDriftStableScoreThreshold float64
+	// SecurityProfileDriftStablePeriod defines the amount of time during which the drift score of a profile has to stay
+	// at or below the threshold for the profile to be considered stable
+	SecurityProfileDriftStablePeriod time.Duration
+	// SecurityProfileClusterAgentEnabled defines if the stable Security Profiles should be shared with the other nodes
+	// of the cluster through the cluster agent
+	SecurityProfileClusterAgentEnabled bool
+	// SecurityProfileClusterAgentUploadPeriod defines the period at which the stable Security Profiles are uploaded to
+	// the cluster agent
+	SecurityProfileClusterAgentUploadPeriod time.Duration
 
 	// SecurityProfileAutoSuppressionEnabled do not send event if part of a profile
 	SecurityProfileAutoSuppressionEnabled bool
@@ -289,6 +316,23 @@ type RuntimeSecurityConfig struct {
 
 	// SendEventFromSystemProbe defines when the event are sent directly from system-probe
 	SendEventFromSystemProbe bool
+
+	// K8sAuditEnabled defines if the rules should be evaluated on the Kubernetes audit events
+	K8sAuditEnabled bool
+	// K8sAuditMode defines how the Kubernetes audit events are received, "file" or "webhook"
+	K8sAuditMode string
+	// K8sAuditFilePath defines the path of the audit log file written by the log backend of the API server
+	K8sAuditFilePath string
+	// K8sAuditWebhookAddress defines the address the audit events sent by the webhook backend of the API server are received on
+	K8sAuditWebhookAddress string
+	// K8sAuditWebhookTLSCertFile defines the certificate file of the webhook receiver
+	K8sAuditWebhookTLSCertFile string
+	// K8sAuditWebhookTLSKeyFile defines the key file of the webhook receiver
+	K8sAuditWebhookTLSKeyFile string
+	// K8sAuditWebhookTLSClientCAFile defines the CA file the client certificates presented to the webhook receiver are verified with
+	K8sAuditWebhookTLSClientCAFile string
+	// K8sAuditPolicyFile defines the policy file overriding or extending the default Kubernetes audit rules
+	K8sAuditPolicyFile string
 }
 
 // Config defines a security config
@@ -358,6 +402,14 @@ func NewRuntimeSecurityConfig() (*RuntimeSecurityConfig, error) {
 		EventServerRate:      pkgconfigsetup.SystemProbe().GetInt("runtime_security_config.event_server.rate"),
 		EventServerRetention: pkgconfigsetup.SystemProbe().GetDuration("runtime_security_config.event_server.retention"),
 
+		EventLocalSinkEnabled:   pkgconfigsetup.SystemProbe().GetBool("runtime_security_config.event_local_sink.enabled"),
+		EventLocalSinkType:      pkgconfigsetup.SystemProbe().GetString("runtime_security_config.event_local_sink.type"),
+		EventLocalSinkAddress:   pkgconfigsetup.SystemProbe().GetString("runtime_security_config.event_local_sink.address"),
+		EventLocalSinkRate:      pkgconfigsetup.SystemProbe().GetInt("runtime_security_config.event_local_sink.rate"),
+		EventLocalSinkBurst:     pkgconfigsetup.SystemProbe().GetInt("runtime_security_config.event_local_sink.burst"),
+		EventLocalSinkRuleTags:  pkgconfigsetup.SystemProbe().GetStringSlice("runtime_security_config.event_local_sink.rule_tags"),
+		EventLocalSinkQueueSize: pkgconfigsetup.SystemProbe().GetInt("runtime_security_config.event_local_sink.queue_size"),
+
 		SelfTestEnabled:                 pkgconfigsetup.SystemProbe().GetBool("runtime_security_config.self_test.enabled"),
 		SelfTestSendReport:              pkgconfigsetup.SystemProbe().GetBool("runtime_security_config.self_test.send_report"),
 		RemoteConfigurationEnabled:      isRemoteConfigEnabled(),
@@ -440,6 +492,14 @@ func NewRuntimeSecurityConfig() (*RuntimeSecurityConfig, error) {
 		SecurityProfileMaxCount:         pkgconfigsetup.SystemProbe().GetInt("runtime_security_config.security_profile.max_count"),
 		SecurityProfileDNSMatchMaxDepth: pkgconfigsetup.SystemProbe().GetInt("runtime_security_config.security_profile.dns_match_max_depth"),
 
+		// security profiles drift
+		SecurityProfileDriftStableScoreThreshold: pkgconfigsetup.SystemProbe().GetFloat64("runtime_security_config.security_profile.drift.stable_score_threshold"),
+		SecurityProfileDriftStablePeriod:         pkgconfigsetup.SystemProbe().GetDuration("runtime_security_config.security_profile.drift.stable_period"),
+
+		// security profiles sharing
+		SecurityProfileClusterAgentEnabled:      pkgconfigsetup.SystemProbe().GetBool("runtime_security_config.security_profile.cluster_agent.enabled"),
+		SecurityProfileClusterAgentUploadPeriod: pkgconfigsetup.SystemProbe().GetDuration("runtime_security_config.security_profile.cluster_agent.upload_period"),
+
 		// auto suppression
 		SecurityProfileAutoSuppressionEnabled:    pkgconfigsetup.SystemProbe().GetBool("runtime_security_config.security_profile.auto_suppression.enabled"),
 		SecurityProfileAutoSuppressionEventTypes: parseEventTypeStringSlice(pkgconfigsetup.SystemProbe().GetStringSlice("runtime_security_config.security_profile.auto_suppression.event_types")),
@@ -482,6 +542,16 @@ func NewRuntimeSecurityConfig() (*RuntimeSecurityConfig, error) {
 
 		// direct sender
 		SendEventFromSystemProbe: pkgconfigsetup.SystemProbe().GetBool("runtime_security_config.direct_send_from_system_probe"),
+
+		// kubernetes audit
+		K8sAuditEnabled:                pkgconfigsetup.SystemProbe().GetBool("runtime_security_config.k8s_audit.enabled"),
+		K8sAuditMode:                   pkgconfigsetup.SystemProbe().GetString("runtime_security_config.k8s_audit.mode"),
+		K8sAuditFilePath:               pkgconfigsetup.SystemProbe().GetString("runtime_security_config.k8s_audit.file.path"),
+		K8sAuditWebhookAddress:         pkgconfigsetup.SystemProbe().GetString("runtime_security_config.k8s_audit.webhook.address"),
+		K8sAuditWebhookTLSCertFile:     pkgconfigsetup.SystemProbe().GetString("runtime_security_config.k8s_audit.webhook.tls_cert_file"),
+		K8sAuditWebhookTLSKeyFile:      pkgconfigsetup.SystemProbe().GetString("runtime_security_config.k8s_audit.webhook.tls_key_file"),
+		K8sAuditWebhookTLSClientCAFile: pkgconfigsetup.SystemProbe().GetString("runtime_security_config.k8s_audit.webhook.tls_client_ca_file"),
+		K8sAuditPolicyFile:             pkgconfigsetup.SystemProbe().GetString("runtime_security_config.k8s_audit.policy_file"),
 	}
 
 	activityDumpRateLimiter := pkgconfigsetup.SystemProbe().GetInt("runtime_security_config.activity_dump.rate_limiter")
@@ -569,6 +639,14 @@ func (c *RuntimeSecurityConfig) sanitize() error {
 		return fmt.Errorf("invalid value for runtime_security_config.enforcement.disarmer.executable.max_allowed: %d", c.EnforcementDisarmerExecutableMaxAllowed)
 	}
 
+	if c.K8sAuditEnabled && c.K8sAuditMode != "file" && c.K8sAuditMode != "webhook" {
+		return fmt.Errorf("invalid value for runtime_security_config.k8s_audit.mode: %s, must be `file` or `webhook`", c.K8sAuditMode)
+	}
+
+	if c.K8sAuditEnabled && c.K8sAuditMode == "webhook" && (c.K8sAuditWebhookTLSCertFile == "" || c.K8sAuditWebhookTLSKeyFile == "" || c.K8sAuditWebhookTLSClientCAFile == "") {
+		return errors.New("runtime_security_config.k8s_audit.webhook requires tls_cert_file, tls_key_file and tls_client_ca_file to be set")
+	}
+
 	c.sanitizePlatform()
 
 	return c.sanitizeRuntimeSecurityConfigActivityDump()
@@ -0,0 +1,286 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package events holds events related files
+package events
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"go.uber.org/atomic"
+	"golang.org/x/time/rate"
+
+	"github.com/DataDog/datadog-agent/pkg/security/metrics"
+	"github.com/DataDog/datadog-agent/pkg/security/seclog"
+)
+
+const (
+	// LocalSinkTypeUnix streams newline delimited JSON events over a unix stream socket
+	LocalSinkTypeUnix = "unix"
+	// LocalSinkTypeUnixgram sends one JSON event per datagram over a unix datagram socket
+	LocalSinkTypeUnixgram = "unixgram"
+	// LocalSinkTypeSyslog sends RFC 5424 syslog messages, with the JSON event as message, to the local syslog daemon
+	LocalSinkTypeSyslog = "syslog"
+
+	defaultSyslogAddress = "/dev/log"
+	// syslog priority of the events: facility local0 (16), severity warning (4)
+	syslogPriority = 16*8 + 4
+	syslogAppName  = "datadog-cws"
+
+	localSinkDialTimeout  = time.Second
+	localSinkWriteTimeout = time.Second
+
+	defaultLocalSinkQueueSize = 1000
+)
+
+// LocalSinkOpts defines the options of a local sink
+type LocalSinkOpts struct {
+	// Type is the type of the sink, one of "unix", "unixgram" or "syslog"
+	Type string
+	// Address is the path of the unix socket the events are written to
+	Address string
+	// Rate is the maximum number of events per second mirrored to the sink, 0 means no limit
+	Rate int
+	// Burst is the maximum burst of events mirrored to the sink
+	Burst int
+	// RuleTags restricts the mirrored events to the ones triggered by rules having one of these tags, in the
+	// `key:value` format. All events are mirrored if empty.
+	RuleTags []string
+	// QueueSize is the maximum number of events waiting to be written to the sink, the events are dropped when
+	// the queue is full
+	QueueSize int
+}
+
+// LocalSink mirrors security events, in JSON, to a local unix socket or to the local syslog daemon so that
+// they can be consumed by on-prem collectors without a round-trip to the backend. The events are written by a
+// dedicated goroutine so that a slow or missing consumer never delays the sending of the events to the backend.
+type LocalSink struct {
+	opts     LocalSinkOpts
+	limiter  *rate.Limiter
+	ruleTags map[string]struct{}
+	hostname string
+	pid      int
+
+	queue     chan []byte
+	stop      chan struct{}
+	done      chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+
+	// conn is only used by the goroutine writing the events, it is kept open between events
+	conn net.Conn
+
+	// stats
+	sent      *atomic.Uint64
+	dropped   *atomic.Uint64
+	queueFull *atomic.Uint64
+	filtered  *atomic.Uint64
+	errors    *atomic.Uint64
+}
+
+// NewLocalSink returns a new local sink
+func NewLocalSink(opts LocalSinkOpts) (*LocalSink, error) {
+	switch opts.Type {
+	case LocalSinkTypeUnix, LocalSinkTypeUnixgram:
+		if opts.Address == "" {
+			return nil, fmt.Errorf("an address is required for the %s local sink", opts.Type)
+		}
+	case LocalSinkTypeSyslog:
+		if opts.Address == "" {
+			opts.Address = defaultSyslogAddress
+		}
+	default:
+		return nil, fmt.Errorf("unknown local sink type `%s`", opts.Type)
+	}
+
+	limit := rate.Inf
+	if opts.Rate > 0 {
+		limit = rate.Limit(opts.Rate)
+	}
+	burst := opts.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultLocalSinkQueueSize
+	}
+
+	ruleTags := make(map[string]struct{}, len(opts.RuleTags))
+	for _, tag := range opts.RuleTags {
+		ruleTags[tag] = struct{}{}
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	return &LocalSink{
+		opts:      opts,
+		limiter:   rate.NewLimiter(limit, burst),
+		ruleTags:  ruleTags,
+		hostname:  hostname,
+		pid:       os.Getpid(),
+		queue:     make(chan []byte, opts.QueueSize),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+		sent:      atomic.NewUint64(0),
+		dropped:   atomic.NewUint64(0),
+		queueFull: atomic.NewUint64(0),
+		filtered:  atomic.NewUint64(0),
+		errors:    atomic.NewUint64(0),
+	}, nil
+}
+
+// Start starts the goroutine writing the queued events to the sink
+func (s *LocalSink) Start() {
+	s.startOnce.Do(func() {
+		go s.run()
+	})
+}
+
+func (s *LocalSink) run() {
+	defer close(s.done)
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case payload := <-s.queue:
+			if err := s.write(payload); err != nil {
+				s.errors.Inc()
+				seclog.Debugf("failed to forward event to the local sink: %v", err)
+				continue
+			}
+			s.sent.Inc()
+		}
+	}
+}
+
+// Forward queues the given event to be mirrored to the sink if it matches the rule tags filter and is allowed by the
+// rate limiter. It never blocks: the event is dropped if the queue is full.
+func (s *LocalSink) Forward(ruleID string, data []byte, tags []string) {
+	if !s.match(tags) {
+		s.filtered.Inc()
+		return
+	}
+
+	if !s.limiter.Allow() {
+		s.dropped.Inc()
+		return
+	}
+
+	select {
+	case s.queue <- s.format(data):
+	default:
+		s.queueFull.Inc()
+		seclog.Tracef("local sink queue full, dropping event of rule `%s`", ruleID)
+	}
+}
+
+// match returns whether the event tags match the rule tags filter
+func (s *LocalSink) match(tags []string) bool {
+	if len(s.ruleTags) == 0 {
+		return true
+	}
+
+	for _, tag := range tags {
+		if _, found := s.ruleTags[tag]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// format returns the payload written to the sink for the given event
+func (s *LocalSink) format(data []byte) []byte {
+	var buf bytes.Buffer
+
+	switch s.opts.Type {
+	case LocalSinkTypeSyslog:
+		// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+		fmt.Fprintf(&buf, "<%d>1 %s %s %s %d - - ", syslogPriority, time.Now().UTC().Format(time.RFC3339Nano), s.hostname, syslogAppName, s.pid)
+		buf.Write(data)
+	case LocalSinkTypeUnix:
+		buf.Write(data)
+		buf.WriteByte('\n')
+	default:
+		buf.Write(data)
+	}
+
+	return buf.Bytes()
+}
+
+// write sends the payload to the sink, dialing the socket if needed. The connection is reset on error so that
+// it is dialed again for the next event, which makes the sink resilient to the consumer restarting.
+func (s *LocalSink) write(payload []byte) error {
+	if s.conn == nil {
+		network := "unixgram"
+		if s.opts.Type == LocalSinkTypeUnix {
+			network = "unix"
+		}
+
+		conn, err := net.DialTimeout(network, s.opts.Address, localSinkDialTimeout)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	_ = s.conn.SetWriteDeadline(time.Now().Add(localSinkWriteTimeout))
+	if _, err := s.conn.Write(payload); err != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+// SendStats sends statistics about the events mirrored to the sink
+func (s *LocalSink) SendStats(client statsd.ClientInterface) error {
+	tags := []string{"sink_type:" + s.opts.Type}
+
+	for metric, counter := range map[string]*atomic.Uint64{
+		metrics.MetricLocalSinkSent:      s.sent,
+		metrics.MetricLocalSinkDropped:   s.dropped,
+		metrics.MetricLocalSinkQueueFull: s.queueFull,
+		metrics.MetricLocalSinkFiltered:  s.filtered,
+		metrics.MetricLocalSinkError:     s.errors,
+	} {
+		if value := counter.Swap(0); value > 0 {
+			if err := client.Count(metric, int64(value), tags, 1.0); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Close stops the goroutine writing the events, dropping the queued ones, and closes the connection to the sink
+func (s *LocalSink) Close() error {
+	var err error
+	s.stopOnce.Do(func() {
+		close(s.stop)
+
+		// wait for the goroutine to release the connection, if it was started
+		started := true
+		s.startOnce.Do(func() { started = false })
+		if started {
+			<-s.done
+		}
+
+		if s.conn != nil {
+			err = s.conn.Close()
+			s.conn = nil
+		}
+	})
+	return err
+}
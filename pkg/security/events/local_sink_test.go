@@ -0,0 +1,179 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build unix
+
+package events
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listenUnixgram(t *testing.T) (*net.UnixConn, string) {
+	t.Helper()
+
+	address := filepath.Join(t.TempDir(), "sink.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: address, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, address
+}
+
+func readDatagram(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+
+	buf := make([]byte, 4096)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	return string(buf[:n])
+}
+
+func newTestLocalSink(t *testing.T, opts LocalSinkOpts) *LocalSink {
+	t.Helper()
+
+	sink, err := NewLocalSink(opts)
+	require.NoError(t, err)
+	sink.Start()
+	t.Cleanup(func() { sink.Close() })
+	return sink
+}
+
+func TestLocalSinkOpts(t *testing.T) {
+	_, err := NewLocalSink(LocalSinkOpts{Type: "tcp", Address: "/tmp/sink.sock"})
+	assert.Error(t, err)
+
+	_, err = NewLocalSink(LocalSinkOpts{Type: LocalSinkTypeUnixgram})
+	assert.Error(t, err)
+
+	sink, err := NewLocalSink(LocalSinkOpts{Type: LocalSinkTypeSyslog})
+	require.NoError(t, err)
+	assert.Equal(t, defaultSyslogAddress, sink.opts.Address)
+	assert.Equal(t, defaultLocalSinkQueueSize, cap(sink.queue))
+	assert.NoError(t, sink.Close())
+}
+
+func TestLocalSinkForward(t *testing.T) {
+	conn, address := listenUnixgram(t)
+
+	sink := newTestLocalSink(t, LocalSinkOpts{
+		Type:     LocalSinkTypeUnixgram,
+		Address:  address,
+		RuleTags: []string{"team:secops"},
+	})
+
+	sink.Forward("rule_a", []byte(`{"rule":"a"}`), []string{"rule_id:rule_a", "team:other"})
+	assert.Equal(t, uint64(1), sink.filtered.Load())
+
+	sink.Forward("rule_b", []byte(`{"rule":"b"}`), []string{"rule_id:rule_b", "team:secops"})
+	assert.Equal(t, `{"rule":"b"}`, readDatagram(t, conn))
+	assert.Eventually(t, func() bool { return sink.sent.Load() == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestLocalSinkRateLimit(t *testing.T) {
+	conn, address := listenUnixgram(t)
+
+	sink := newTestLocalSink(t, LocalSinkOpts{
+		Type:    LocalSinkTypeUnixgram,
+		Address: address,
+		Rate:    1,
+		Burst:   2,
+	})
+
+	for i := 0; i != 5; i++ {
+		sink.Forward("rule_a", []byte(`{}`), nil)
+	}
+
+	assert.Equal(t, uint64(3), sink.dropped.Load())
+	assert.Equal(t, `{}`, readDatagram(t, conn))
+	assert.Eventually(t, func() bool { return sink.sent.Load() == 2 }, time.Second, 10*time.Millisecond)
+}
+
+func TestLocalSinkQueueFull(t *testing.T) {
+	_, address := listenUnixgram(t)
+
+	// the sink isn't started, nothing is dequeued
+	sink, err := NewLocalSink(LocalSinkOpts{
+		Type:      LocalSinkTypeUnixgram,
+		Address:   address,
+		QueueSize: 2,
+	})
+	require.NoError(t, err)
+	defer sink.Close()
+
+	for i := 0; i != 5; i++ {
+		sink.Forward("rule_a", []byte(`{}`), nil)
+	}
+
+	assert.Len(t, sink.queue, 2)
+	assert.Equal(t, uint64(3), sink.queueFull.Load())
+	assert.Zero(t, sink.sent.Load())
+}
+
+func TestLocalSinkForwardDoesNotBlock(t *testing.T) {
+	// nobody listens on the address, the events can't be written
+	sink := newTestLocalSink(t, LocalSinkOpts{
+		Type:    LocalSinkTypeUnix,
+		Address: filepath.Join(t.TempDir(), "missing.sock"),
+	})
+
+	start := time.Now()
+	for i := 0; i != 100; i++ {
+		sink.Forward("rule_a", []byte(`{}`), nil)
+	}
+	assert.Less(t, time.Since(start), localSinkDialTimeout)
+	assert.Eventually(t, func() bool { return sink.errors.Load() > 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestLocalSinkSyslog(t *testing.T) {
+	conn, address := listenUnixgram(t)
+
+	sink := newTestLocalSink(t, LocalSinkOpts{
+		Type:    LocalSinkTypeSyslog,
+		Address: address,
+	})
+
+	sink.Forward("rule_a", []byte(`{"rule":"a"}`), nil)
+
+	msg := readDatagram(t, conn)
+	assert.True(t, strings.HasPrefix(msg, "<132>1 "), msg)
+	assert.Contains(t, msg, " "+syslogAppName+" ")
+	assert.True(t, strings.HasSuffix(msg, ` - - {"rule":"a"}`), msg)
+}
+
+func TestLocalSinkReconnect(t *testing.T) {
+	conn, address := listenUnixgram(t)
+
+	sink := newTestLocalSink(t, LocalSinkOpts{
+		Type:    LocalSinkTypeUnixgram,
+		Address: address,
+	})
+
+	sink.Forward("rule_a", []byte(`{}`), nil)
+	assert.Equal(t, `{}`, readDatagram(t, conn))
+
+	// the consumer goes away, the event can't be written
+	conn.Close()
+	sink.Forward("rule_a", []byte(`{}`), nil)
+	assert.Eventually(t, func() bool { return sink.errors.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	// the consumer comes back on the same path, the sink dials again
+	require.NoError(t, os.Remove(address))
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: address, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sink.Forward("rule_a", []byte(`{"rule":"a"}`), nil)
+	assert.Equal(t, `{"rule":"a"}`, readDatagram(t, conn))
+}
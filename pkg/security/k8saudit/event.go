@@ -0,0 +1,201 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package k8saudit evaluates rules on the Kubernetes audit logs, and reports the matching audit events as runtime
+// security events
+package k8saudit
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/security/events"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+)
+
+const (
+	// EventType is the SECL event type of the Kubernetes audit events
+	EventType eval.EventType = "k8s_audit"
+
+	// stageResponseComplete is the stage of the audit events logged once the response was sent
+	stageResponseComplete = "ResponseComplete"
+	// stagePanic is the stage of the audit events logged when the API server panicked
+	stagePanic = "Panic"
+)
+
+// UserInfo is the user of an audit event
+type UserInfo struct {
+	Username string   `json:"username"`
+	UID      string   `json:"uid,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+}
+
+// ObjectReference is the object an audit event applies to
+type ObjectReference struct {
+	Resource    string `json:"resource,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name,omitempty"`
+	APIGroup    string `json:"apiGroup,omitempty"`
+	APIVersion  string `json:"apiVersion,omitempty"`
+	Subresource string `json:"subresource,omitempty"`
+}
+
+// ResponseStatus is the status of the response of an audit event
+type ResponseStatus struct {
+	Code   int    `json:"code,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// roleRef is the role referenced by a RoleBinding or ClusterRoleBinding request
+type roleRef struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// Event is a Kubernetes audit event, as defined by the audit.k8s.io/v1 API
+type Event struct {
+	AuditID                  string           `json:"auditID"`
+	Stage                    string           `json:"stage"`
+	RequestURI               string           `json:"requestURI"`
+	Verb                     string           `json:"verb"`
+	User                     UserInfo         `json:"user"`
+	ImpersonatedUser         *UserInfo        `json:"impersonatedUser,omitempty"`
+	SourceIPs                []string         `json:"sourceIPs,omitempty"`
+	UserAgent                string           `json:"userAgent,omitempty"`
+	ObjectRef                *ObjectReference `json:"objectRef,omitempty"`
+	ResponseStatus           *ResponseStatus  `json:"responseStatus,omitempty"`
+	RequestObject            json.RawMessage  `json:"requestObject,omitempty"`
+	RequestReceivedTimestamp time.Time        `json:"requestReceivedTimestamp"`
+	StageTimestamp           time.Time        `json:"stageTimestamp"`
+
+	// roleRef is decoded from the request object on first use
+	roleRef        roleRef
+	roleRefDecoded bool
+}
+
+// eventList is the payload sent by the webhook audit backend of the API server
+type eventList struct {
+	Items []*Event `json:"items"`
+}
+
+// isFinal returns whether the event is logged at a stage when the outcome of the request is known. The
+// other stages are ignored so that a request matches a rule once.
+func (e *Event) isFinal() bool {
+	return e.Stage == stageResponseComplete || e.Stage == stagePanic
+}
+
+func (e *Event) objectRef() *ObjectReference {
+	if e.ObjectRef == nil {
+		return &ObjectReference{}
+	}
+	return e.ObjectRef
+}
+
+func (e *Event) impersonatedUser() *UserInfo {
+	if e.ImpersonatedUser == nil {
+		return &UserInfo{}
+	}
+	return e.ImpersonatedUser
+}
+
+func (e *Event) responseCode() int {
+	if e.ResponseStatus == nil {
+		return 0
+	}
+	return e.ResponseStatus.Code
+}
+
+func (e *Event) requestRoleRef() roleRef {
+	if !e.roleRefDecoded {
+		e.roleRefDecoded = true
+		if len(e.RequestObject) > 0 {
+			var obj struct {
+				RoleRef roleRef `json:"roleRef"`
+			}
+			if err := json.Unmarshal(e.RequestObject, &obj); err == nil {
+				e.roleRef = obj.RoleRef
+			}
+		}
+	}
+	return e.roleRef
+}
+
+// Init implements the eval.Event interface
+func (e *Event) Init() {}
+
+// GetType returns the type of the event
+func (e *Event) GetType() string {
+	return EventType
+}
+
+// GetTags returns the tags of the event
+func (e *Event) GetTags() []string {
+	tags := []string{"type:" + EventType}
+	if ref := e.objectRef(); ref.Namespace != "" {
+		tags = append(tags, "kube_namespace:"+ref.Namespace)
+	}
+	return tags
+}
+
+// GetWorkloadID returns the workload id of the event, audit events aren't tied to a workload
+func (e *Event) GetWorkloadID() string {
+	return ""
+}
+
+// GetActionReports returns the reports of the actions triggered by the event, there is none
+func (e *Event) GetActionReports() []model.ActionReport {
+	return nil
+}
+
+// GetFieldMetadata returns the metadata of a field
+func (e *Event) GetFieldMetadata(field eval.Field) (eval.EventType, reflect.Kind, string, error) {
+	switch {
+	case stringFields[field] != nil:
+		return EventType, reflect.String, "string", nil
+	case stringArrayFields[field] != nil:
+		return EventType, reflect.String, "[]string", nil
+	case intFields[field] != nil:
+		return EventType, reflect.Int, "int", nil
+	}
+	return "", reflect.Invalid, "", &eval.ErrFieldNotFound{Field: field}
+}
+
+// GetFieldValue returns the value of a field
+func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
+	if fnc := stringFields[field]; fnc != nil {
+		return fnc(e), nil
+	}
+	if fnc := stringArrayFields[field]; fnc != nil {
+		return fnc(e), nil
+	}
+	if fnc := intFields[field]; fnc != nil {
+		return fnc(e), nil
+	}
+	return nil, &eval.ErrFieldNotFound{Field: field}
+}
+
+// SetFieldValue implements the eval.Event interface, the fields of the audit events are read-only
+func (e *Event) SetFieldValue(field eval.Field, _ interface{}) error {
+	return &eval.ErrFieldReadOnly{Field: field}
+}
+
+// securityEvent is the payload of the runtime security event sent for an audit event
+type securityEvent struct {
+	events.CustomEventCommonFields
+	Audit *Event `json:"k8s_audit"`
+}
+
+// ToJSON marshals the runtime security event of the audit event. The request object isn't sent as it
+// may contain sensitive data, e.g. the content of a secret.
+func (e *Event) ToJSON() ([]byte, error) {
+	audit := *e
+	audit.RequestObject = nil
+	evt := securityEvent{Audit: &audit}
+	evt.FillCustomEventCommonFields(nil)
+	evt.Timestamp = e.StageTimestamp
+	return json.Marshal(evt)
+}
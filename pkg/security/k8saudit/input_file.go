@@ -0,0 +1,182 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package k8saudit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/security/seclog"
+)
+
+const (
+	// filePollPeriod is the period at which the audit log file is checked for new lines, rotation or truncation
+	filePollPeriod = time.Second
+	// maxLineSize is the maximum size of an audit event, the longer lines are skipped
+	maxLineSize = 1024 * 1024
+)
+
+// fileInput tails the JSON lines audit log file written by the log backend of the API server. It starts at the
+// end of the file, and follows it when it's rotated or truncated.
+type fileInput struct {
+	path       string
+	pollPeriod time.Duration
+
+	file    *os.File
+	reader  *bufio.Reader
+	offset  int64
+	partial []byte
+}
+
+func newFileInput(path string) *fileInput {
+	return &fileInput{
+		path:       path,
+		pollPeriod: filePollPeriod,
+	}
+}
+
+// seekEnd opens the audit log file at its end, so that only the events logged from now on are read. If the
+// file doesn't exist yet, it's read from its start once created.
+func (f *fileInput) seekEnd() {
+	if err := f.open(true); err != nil && !errors.Is(err, os.ErrNotExist) {
+		seclog.Warnf("failed to open kubernetes audit log %s: %s", f.path, err)
+	}
+}
+
+// run tails the audit log file until the context is cancelled
+func (f *fileInput) run(ctx context.Context, handler func(*Event)) {
+	defer f.close()
+
+	ticker := time.NewTicker(f.pollPeriod)
+	defer ticker.Stop()
+
+	for {
+		f.poll(handler)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// open opens the audit log file, at its end if seekEnd is set
+func (f *fileInput) open(seekEnd bool) error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+
+	var offset int64
+	if seekEnd {
+		if offset, err = file.Seek(0, io.SeekEnd); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	f.file = file
+	f.reader = bufio.NewReader(file)
+	f.offset = offset
+	f.partial = f.partial[:0]
+	return nil
+}
+
+func (f *fileInput) close() {
+	if f.file != nil {
+		f.file.Close()
+		f.file = nil
+	}
+}
+
+// poll reads the lines appended to the audit log file, then reopens it from the start if it was rotated or
+// truncated
+func (f *fileInput) poll(handler func(*Event)) {
+	if f.file == nil {
+		// the file didn't exist yet, or was rotated away, all of the new file has to be read
+		if err := f.open(false); err != nil {
+			return
+		}
+	}
+
+	f.readLines(handler)
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return
+	}
+	current, err := f.file.Stat()
+	if err != nil {
+		return
+	}
+
+	switch {
+	case !os.SameFile(info, current):
+		// the lines written to the file before it was rotated were read above
+		f.close()
+		if err := f.open(false); err == nil {
+			f.readLines(handler)
+		}
+	case info.Size() < f.offset:
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			f.close()
+			return
+		}
+		f.reader.Reset(f.file)
+		f.offset = 0
+		f.partial = f.partial[:0]
+		f.readLines(handler)
+	}
+}
+
+// readLines reads the complete lines available in the audit log file. A line which isn't terminated yet is
+// kept until the rest of it is written.
+func (f *fileInput) readLines(handler func(*Event)) {
+	for {
+		line, err := f.reader.ReadBytes('\n')
+		f.offset += int64(len(line))
+		if err != nil {
+			if len(f.partial)+len(line) <= maxLineSize {
+				f.partial = append(f.partial, line...)
+			}
+			return
+		}
+
+		if len(f.partial) > 0 {
+			line = append(f.partial, line...)
+			f.partial = f.partial[:0]
+		}
+		if len(line) > maxLineSize {
+			seclog.Debugf("kubernetes audit event too large in %s, skipping it", f.path)
+			continue
+		}
+		if e := parseEventLine(line); e != nil {
+			handler(e)
+		}
+	}
+}
+
+// parseEventLine decodes an audit event logged on a line, it returns nil if the line is invalid
+func parseEventLine(line []byte) *Event {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil
+	}
+
+	var e Event
+	if err := json.Unmarshal(line, &e); err != nil {
+		seclog.Debugf("invalid kubernetes audit event: %s", err)
+		return nil
+	}
+	return &e
+}
@@ -0,0 +1,147 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package k8saudit
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/security/seclog"
+)
+
+const (
+	// maxWebhookBodySize is the maximum size of a batch of audit events sent by the API server
+	maxWebhookBodySize = 32 * 1024 * 1024
+	// webhookShutdownTimeout is the time given to the requests being handled to complete on stop
+	webhookShutdownTimeout = 5 * time.Second
+)
+
+// webhookInput receives the batches of audit events sent by the webhook backend of the API server. The events are
+// only received over TLS, from the clients presenting a certificate signed by the client CA, as anyone able to send
+// events could trigger the rules.
+type webhookInput struct {
+	address      string
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	listener net.Listener
+	server   *http.Server
+}
+
+func newWebhookInput(address, certFile, keyFile, clientCAFile string) *webhookInput {
+	return &webhookInput{
+		address:      address,
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+	}
+}
+
+// tlsConfig returns the TLS configuration of the receiver, requiring the clients to present a certificate signed by
+// the client CA
+func (w *webhookInput) tlsConfig() (*tls.Config, error) {
+	if w.certFile == "" || w.keyFile == "" || w.clientCAFile == "" {
+		return nil, errors.New("a TLS certificate, key and client CA file are required")
+	}
+
+	certificate, err := tls.LoadX509KeyPair(w.certFile, w.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the TLS certificate: %w", err)
+	}
+
+	clientCA, err := os.ReadFile(w.clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the client CA file: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(clientCA) {
+		return nil, fmt.Errorf("no certificate found in the client CA file %s", w.clientCAFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// listen binds the address of the webhook receiver
+func (w *webhookInput) listen(handler func(*Event)) error {
+	tlsConfig, err := w.tlsConfig()
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", w.address)
+	if err != nil {
+		return err
+	}
+
+	w.listener = tls.NewListener(listener, tlsConfig)
+	w.server = &http.Server{
+		Handler:           w.handler(handler),
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig:         tlsConfig,
+	}
+	return nil
+}
+
+// serve handles the requests until the receiver is shut down
+func (w *webhookInput) serve() {
+	if err := w.server.Serve(w.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		seclog.Errorf("kubernetes audit webhook receiver stopped: %s", err)
+	}
+}
+
+func (w *webhookInput) shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookShutdownTimeout)
+	defer cancel()
+
+	if err := w.server.Shutdown(ctx); err != nil {
+		seclog.Warnf("failed to shut down the kubernetes audit webhook receiver: %s", err)
+	}
+}
+
+func (w *webhookInput) handler(handler func(*Event)) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			rw.Header().Set("Allow", http.MethodPost)
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, maxWebhookBodySize))
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		var list eventList
+		if err := json.Unmarshal(body, &list); err != nil {
+			seclog.Debugf("invalid kubernetes audit event list: %s", err)
+			http.Error(rw, "invalid event list", http.StatusBadRequest)
+			return
+		}
+
+		for _, e := range list.Items {
+			if e != nil {
+				handler(e)
+			}
+		}
+		rw.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,80 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package k8saudit
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+)
+
+// stringFields are the SECL fields of the audit events holding a string
+var stringFields = map[eval.Field]func(e *Event) string{
+	"k8s_audit.verb":                   func(e *Event) string { return e.Verb },
+	"k8s_audit.stage":                  func(e *Event) string { return e.Stage },
+	"k8s_audit.request_uri":            func(e *Event) string { return e.RequestURI },
+	"k8s_audit.user_agent":             func(e *Event) string { return e.UserAgent },
+	"k8s_audit.user.name":              func(e *Event) string { return e.User.Username },
+	"k8s_audit.impersonated_user.name": func(e *Event) string { return e.impersonatedUser().Username },
+	"k8s_audit.object.resource":        func(e *Event) string { return e.objectRef().Resource },
+	"k8s_audit.object.subresource":     func(e *Event) string { return e.objectRef().Subresource },
+	"k8s_audit.object.namespace":       func(e *Event) string { return e.objectRef().Namespace },
+	"k8s_audit.object.name":            func(e *Event) string { return e.objectRef().Name },
+	"k8s_audit.object.api_group":       func(e *Event) string { return e.objectRef().APIGroup },
+	"k8s_audit.request.role_ref.kind":  func(e *Event) string { return e.requestRoleRef().Kind },
+	"k8s_audit.request.role_ref.name":  func(e *Event) string { return e.requestRoleRef().Name },
+}
+
+// stringArrayFields are the SECL fields of the audit events holding a list of strings
+var stringArrayFields = map[eval.Field]func(e *Event) []string{
+	"k8s_audit.user.groups":              func(e *Event) []string { return e.User.Groups },
+	"k8s_audit.impersonated_user.groups": func(e *Event) []string { return e.impersonatedUser().Groups },
+	"k8s_audit.source_ips":               func(e *Event) []string { return e.SourceIPs },
+}
+
+// intFields are the SECL fields of the audit events holding an integer
+var intFields = map[eval.Field]func(e *Event) int{
+	"k8s_audit.response.code": func(e *Event) int { return e.responseCode() },
+}
+
+// Model is the SECL model of the Kubernetes audit events
+type Model struct{}
+
+// NewEvent returns a new audit event
+func (m *Model) NewEvent() eval.Event {
+	return &Event{}
+}
+
+// GetEvaluator returns the evaluator of a field
+func (m *Model) GetEvaluator(field eval.Field, _ eval.RegisterID) (eval.Evaluator, error) {
+	if fnc := stringFields[field]; fnc != nil {
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string { return fnc(ctx.Event.(*Event)) },
+			Field:   field,
+		}, nil
+	}
+	if fnc := stringArrayFields[field]; fnc != nil {
+		return &eval.StringArrayEvaluator{
+			EvalFnc: func(ctx *eval.Context) []string { return fnc(ctx.Event.(*Event)) },
+			Field:   field,
+		}, nil
+	}
+	if fnc := intFields[field]; fnc != nil {
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int { return fnc(ctx.Event.(*Event)) },
+			Field:   field,
+		}, nil
+	}
+	return nil, &eval.ErrFieldNotFound{Field: field}
+}
+
+// ValidateField validates the value of a field, any value is valid
+func (m *Model) ValidateField(_ eval.Field, _ eval.FieldValue) error {
+	return nil
+}
+
+// GetFieldRestrictions returns the event types a field is restricted to, there is one event type
+func (m *Model) GetFieldRestrictions(_ eval.Field) []eval.EventType {
+	return nil
+}
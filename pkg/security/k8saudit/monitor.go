@@ -0,0 +1,117 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package k8saudit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/DataDog/datadog-agent/pkg/security/config"
+	"github.com/DataDog/datadog-agent/pkg/security/events"
+	"github.com/DataDog/datadog-agent/pkg/security/seclog"
+)
+
+// Monitor receives the Kubernetes audit events, evaluates the rules on them and sends the matching ones as runtime
+// security events
+type Monitor struct {
+	sync.Mutex
+	config  *config.RuntimeSecurityConfig
+	ruleSet *RuleSet
+	sender  events.EventSender
+
+	wg        sync.WaitGroup
+	cancelFnc context.CancelFunc
+	webhook   *webhookInput
+}
+
+// NewMonitor returns a new Kubernetes audit monitor, sending the matching events to the sender
+func NewMonitor(cfg *config.RuntimeSecurityConfig, sender events.EventSender) (*Monitor, error) {
+	var policyRules []*RuleDefinition
+	if cfg.K8sAuditPolicyFile != "" {
+		var err error
+		if policyRules, err = LoadPolicy(cfg.K8sAuditPolicyFile); err != nil {
+			return nil, fmt.Errorf("failed to load kubernetes audit policy: %w", err)
+		}
+	}
+
+	ruleSet, err := NewRuleSet(policyRules)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Monitor{
+		config:  cfg,
+		ruleSet: ruleSet,
+		sender:  sender,
+	}, nil
+}
+
+// Start starts receiving the audit events
+func (m *Monitor) Start() error {
+	ctx, cancelFnc := context.WithCancel(context.Background())
+
+	switch m.config.K8sAuditMode {
+	case "webhook":
+		webhook := newWebhookInput(m.config.K8sAuditWebhookAddress, m.config.K8sAuditWebhookTLSCertFile, m.config.K8sAuditWebhookTLSKeyFile, m.config.K8sAuditWebhookTLSClientCAFile)
+		if err := webhook.listen(m.HandleEvent); err != nil {
+			cancelFnc()
+			return fmt.Errorf("failed to start the kubernetes audit webhook receiver: %w", err)
+		}
+		m.webhook = webhook
+
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			webhook.serve()
+		}()
+		seclog.Infof("kubernetes audit webhook receiver listening on %s", m.config.K8sAuditWebhookAddress)
+	default:
+		file := newFileInput(m.config.K8sAuditFilePath)
+		file.seekEnd()
+
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			file.run(ctx, m.HandleEvent)
+		}()
+		seclog.Infof("tailing kubernetes audit log %s", m.config.K8sAuditFilePath)
+	}
+
+	m.cancelFnc = cancelFnc
+	return nil
+}
+
+// Stop stops receiving the audit events
+func (m *Monitor) Stop() {
+	if m.cancelFnc == nil {
+		return
+	}
+	m.cancelFnc()
+	if m.webhook != nil {
+		m.webhook.shutdown()
+	}
+	m.wg.Wait()
+}
+
+// HandleEvent evaluates the rules on an audit event, and sends it for each matching rule
+func (m *Monitor) HandleEvent(e *Event) {
+	if !e.isFinal() {
+		return
+	}
+
+	m.Lock()
+	matches := m.ruleSet.Evaluate(e)
+	m.Unlock()
+
+	for _, rule := range matches {
+		if !rule.limiter.Allow(e) {
+			seclog.Tracef("Event on rule %s was dropped due to rate limiting", rule.ID)
+			continue
+		}
+		m.sender.SendEvent(rule.Rule, e, nil, "")
+	}
+}
@@ -0,0 +1,216 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package k8saudit
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/security/config"
+	"github.com/DataDog/datadog-agent/pkg/security/events"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+type mockSender struct {
+	sync.Mutex
+	sent []string
+}
+
+func (s *mockSender) SendEvent(rule *rules.Rule, _ events.Event, _ func() []string, _ string) {
+	s.Lock()
+	defer s.Unlock()
+	s.sent = append(s.sent, rule.ID)
+}
+
+func (s *mockSender) rules() []string {
+	s.Lock()
+	defer s.Unlock()
+	return append([]string(nil), s.sent...)
+}
+
+func appendLines(t *testing.T, path string, lines ...string) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	defer f.Close()
+	for _, line := range lines {
+		_, err := f.WriteString(line)
+		require.NoError(t, err)
+	}
+}
+
+func TestFileInput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	appendLines(t, path, podExecEvent+"\n")
+
+	var ids []string
+	handler := func(e *Event) { ids = append(ids, e.AuditID) }
+
+	f := newFileInput(path)
+	require.NoError(t, f.open(true))
+	defer f.close()
+
+	// the events logged before the start are skipped, and a partial line is read once complete
+	f.poll(handler)
+	assert.Empty(t, ids)
+	appendLines(t, path, secretReadEvent+"\n", "not json\n", rbacEscalationEvent[:20])
+	f.poll(handler)
+	assert.Equal(t, []string{"2"}, ids)
+	appendLines(t, path, rbacEscalationEvent[20:]+"\n")
+	f.poll(handler)
+	assert.Equal(t, []string{"2", "3"}, ids)
+
+	// truncation
+	require.NoError(t, os.WriteFile(path, []byte(podExecEvent+"\n"), 0o644))
+	f.poll(handler)
+	assert.Equal(t, []string{"2", "3", "1"}, ids)
+
+	// rotation, the lines written before the rotation are read first
+	appendLines(t, path, secretReadEvent+"\n")
+	require.NoError(t, os.Rename(path, path+".1"))
+	appendLines(t, path, rbacEscalationEvent+"\n")
+	f.poll(handler)
+	assert.Equal(t, []string{"2", "3", "1", "2", "3"}, ids)
+}
+
+func TestWebhookInput(t *testing.T) {
+	sender := &mockSender{}
+	m, err := NewMonitor(&config.RuntimeSecurityConfig{K8sAuditMode: "webhook"}, sender)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(newWebhookInput("", "", "", "").handler(m.HandleEvent))
+	defer server.Close()
+
+	body := `{"kind":"EventList","apiVersion":"audit.k8s.io/v1","items":[` + strings.Join([]string{
+		podExecEvent,
+		strings.Replace(secretReadEvent, "ResponseComplete", "ResponseStarted", 1),
+		secretReadEvent,
+	}, ",") + `]}`
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	// the intermediate stages aren't evaluated
+	assert.Equal(t, []string{"k8s_audit_pod_exec", "k8s_audit_secret_read"}, sender.rules())
+
+	resp, err = http.Post(server.URL, "application/json", strings.NewReader("{"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+// writeCert writes a certificate and its key signed by the parent, or self-signed when the parent is nil
+func writeCert(t *testing.T, name string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(t.TempDir(), name+".crt")
+	keyFile := filepath.Join(t.TempDir(), name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return cert, key, certFile, keyFile
+}
+
+func TestWebhookInputMutualTLS(t *testing.T) {
+	ca, caKey, caFile, _ := writeCert(t, "ca", true, nil, nil)
+	_, _, serverCert, serverKey := writeCert(t, "server", false, ca, caKey)
+	_, _, clientCert, clientKey := writeCert(t, "client", false, ca, caKey)
+	_, _, rogueCert, rogueKey := writeCert(t, "rogue", false, nil, nil)
+
+	// the receiver doesn't start without TLS and client certificate verification
+	assert.Error(t, newWebhookInput("127.0.0.1:0", "", "", "").listen(func(*Event) {}))
+	assert.Error(t, newWebhookInput("127.0.0.1:0", serverCert, serverKey, "").listen(func(*Event) {}))
+
+	sender := &mockSender{}
+	m, err := NewMonitor(&config.RuntimeSecurityConfig{K8sAuditMode: "webhook"}, sender)
+	require.NoError(t, err)
+	webhook := newWebhookInput("127.0.0.1:0", serverCert, serverKey, caFile)
+	require.NoError(t, webhook.listen(m.HandleEvent))
+	go webhook.serve()
+	defer webhook.shutdown()
+
+	post := func(certFile, keyFile string) error {
+		roots := x509.NewCertPool()
+		roots.AddCert(ca)
+		tlsConfig := &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+		if certFile != "" {
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			require.NoError(t, err)
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+		resp, err := client.Post("https://"+webhook.listener.Addr().String(), "application/json", strings.NewReader(`{"items":[`+podExecEvent+`]}`))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	assert.Error(t, post("", ""))
+	assert.Error(t, post(rogueCert, rogueKey))
+	assert.NoError(t, post(clientCert, clientKey))
+	assert.Equal(t, []string{"k8s_audit_pod_exec"}, sender.rules())
+}
+
+func TestMonitorFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sender := &mockSender{}
+	m, err := NewMonitor(&config.RuntimeSecurityConfig{K8sAuditMode: "file", K8sAuditFilePath: path}, sender)
+	require.NoError(t, err)
+	require.NoError(t, m.Start())
+	defer m.Stop()
+
+	// the file is created after the start, it's read from its start
+	appendLines(t, path, rbacEscalationEvent+"\n")
+	assert.Eventually(t, func() bool {
+		return len(sender.rules()) == 1
+	}, 5*filePollPeriod, filePollPeriod/10)
+	assert.Equal(t, []string{"k8s_audit_rbac_escalation"}, sender.rules())
+}
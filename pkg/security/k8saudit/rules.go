@@ -0,0 +1,143 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package k8saudit
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/time/rate"
+	"gopkg.in/yaml.v3"
+
+	"github.com/DataDog/datadog-agent/pkg/security/events"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/ast"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+const (
+	// ruleEvery and ruleBurst rate limit the events sent for each rule, like the default rate limiter of the
+	// CWS rules
+	ruleEvery = 100 * time.Millisecond
+	ruleBurst = 40
+)
+
+// RuleDefinition is the definition of a rule evaluated on the audit events
+type RuleDefinition struct {
+	ID          string `yaml:"id"`
+	Description string `yaml:"description"`
+	Expression  string `yaml:"expression"`
+	Disabled    bool   `yaml:"disabled"`
+}
+
+// policy is the format of the policy file of the audit rules
+type policy struct {
+	Rules []*RuleDefinition `yaml:"rules"`
+}
+
+// DefaultRules are the rules evaluated on the audit events unless they are disabled by the policy file
+var DefaultRules = []*RuleDefinition{
+	{
+		ID:          "k8s_audit_pod_exec",
+		Description: "A user executed a command or attached to a container of a pod",
+		Expression:  `k8s_audit.object.resource == "pods" && k8s_audit.object.subresource in ["exec", "attach"] && k8s_audit.response.code < 400`,
+	},
+	{
+		ID:          "k8s_audit_secret_read",
+		Description: "A user read a secret",
+		Expression:  `k8s_audit.verb in ["get", "list", "watch"] && k8s_audit.object.resource == "secrets" && k8s_audit.user.name not in ["system:apiserver", "system:kube-controller-manager", "system:kube-scheduler", ~"system:node:*"] && k8s_audit.response.code < 400`,
+	},
+	{
+		ID:          "k8s_audit_rbac_escalation",
+		Description: "A user bound a subject to the cluster-admin role",
+		Expression:  `k8s_audit.verb in ["create", "update"] && k8s_audit.object.resource in ["clusterrolebindings", "rolebindings"] && k8s_audit.request.role_ref.name == "cluster-admin" && k8s_audit.response.code < 400`,
+	},
+}
+
+// LoadPolicy reads the rules of a policy file
+func LoadPolicy(path string) ([]*RuleDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("malformed policy %s: %w", path, err)
+	}
+	return p.Rules, nil
+}
+
+// Rule is a compiled rule
+type Rule struct {
+	*rules.Rule
+	evalRule *eval.Rule
+	limiter  *events.StdLimiter
+}
+
+// RuleSet is a set of rules evaluated on the audit events
+type RuleSet struct {
+	rules []*Rule
+	ctx   *eval.Context
+}
+
+// NewRuleSet compiles the default rules, overridden by the rules with the same ID of the policy, and the
+// other rules of the policy. It returns an error if a rule is invalid.
+func NewRuleSet(policyRules []*RuleDefinition) (*RuleSet, error) {
+	defs := make([]*RuleDefinition, 0, len(DefaultRules)+len(policyRules))
+	index := make(map[string]int)
+	for _, def := range append(append(defs, DefaultRules...), policyRules...) {
+		if i, ok := index[def.ID]; ok {
+			defs[i] = def
+			continue
+		}
+		index[def.ID] = len(defs)
+		defs = append(defs, def)
+	}
+
+	rs := &RuleSet{ctx: eval.NewContext(nil)}
+	model := &Model{}
+	for _, def := range defs {
+		if def.Disabled {
+			continue
+		}
+		if def.ID == "" {
+			return nil, fmt.Errorf("rule without ID: %s", def.Expression)
+		}
+		evalRule, err := eval.NewRule(def.ID, def.Expression, ast.NewParsingContext(false), &eval.Opts{})
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule %s: %w", def.ID, err)
+		}
+		if err := evalRule.GenEvaluator(model); err != nil {
+			return nil, fmt.Errorf("invalid rule %s: %w", def.ID, err)
+		}
+		rs.rules = append(rs.rules, &Rule{
+			Rule:     events.NewCustomRule(def.ID, def.Description),
+			evalRule: evalRule,
+			limiter:  events.NewStdLimiter(rate.Every(ruleEvery), ruleBurst),
+		})
+	}
+	return rs, nil
+}
+
+// Len returns the number of rules of the set
+func (rs *RuleSet) Len() int {
+	return len(rs.rules)
+}
+
+// Evaluate returns the rules matching an audit event. It isn't thread safe.
+func (rs *RuleSet) Evaluate(e *Event) []*Rule {
+	rs.ctx.Reset()
+	rs.ctx.SetEvent(e)
+
+	var matches []*Rule
+	for _, rule := range rs.rules {
+		if rule.evalRule.Eval(rs.ctx) {
+			matches = append(matches, rule)
+		}
+	}
+	return matches
+}
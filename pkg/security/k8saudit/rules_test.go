@@ -0,0 +1,99 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package k8saudit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	podExecEvent        = `{"auditID":"1","stage":"ResponseComplete","verb":"create","requestURI":"/api/v1/namespaces/default/pods/web/exec?command=sh","user":{"username":"alice","groups":["dev"]},"objectRef":{"resource":"pods","namespace":"default","name":"web","subresource":"exec"},"responseStatus":{"code":101}}`
+	secretReadEvent     = `{"auditID":"2","stage":"ResponseComplete","verb":"get","user":{"username":"bob"},"objectRef":{"resource":"secrets","namespace":"prod","name":"db"},"responseStatus":{"code":200}}`
+	rbacEscalationEvent = `{"auditID":"3","stage":"ResponseComplete","verb":"create","user":{"username":"carol"},"objectRef":{"resource":"clusterrolebindings","apiGroup":"rbac.authorization.k8s.io","name":"carol-admin"},"responseStatus":{"code":201},"requestObject":{"kind":"ClusterRoleBinding","roleRef":{"apiGroup":"rbac.authorization.k8s.io","kind":"ClusterRole","name":"cluster-admin"},"subjects":[{"kind":"User","name":"carol"}]}}`
+)
+
+func matchingRules(t *testing.T, rs *RuleSet, line string) []string {
+	e := parseEventLine([]byte(line))
+	require.NotNil(t, e)
+
+	var ids []string
+	for _, rule := range rs.Evaluate(e) {
+		ids = append(ids, rule.ID)
+	}
+	return ids
+}
+
+func TestDefaultRules(t *testing.T) {
+	rs, err := NewRuleSet(nil)
+	require.NoError(t, err)
+	assert.Equal(t, len(DefaultRules), rs.Len())
+
+	assert.Equal(t, []string{"k8s_audit_pod_exec"}, matchingRules(t, rs, podExecEvent))
+	assert.Equal(t, []string{"k8s_audit_secret_read"}, matchingRules(t, rs, secretReadEvent))
+	assert.Equal(t, []string{"k8s_audit_rbac_escalation"}, matchingRules(t, rs, rbacEscalationEvent))
+
+	// denied requests and control plane users don't match
+	assert.Empty(t, matchingRules(t, rs, `{"stage":"ResponseComplete","verb":"create","user":{"username":"alice"},"objectRef":{"resource":"pods","subresource":"exec"},"responseStatus":{"code":403}}`))
+	assert.Empty(t, matchingRules(t, rs, `{"stage":"ResponseComplete","verb":"list","user":{"username":"system:kube-controller-manager"},"objectRef":{"resource":"secrets"},"responseStatus":{"code":200}}`))
+	assert.Empty(t, matchingRules(t, rs, `{"stage":"ResponseComplete","verb":"get","user":{"username":"system:node:worker-1"},"objectRef":{"resource":"secrets"},"responseStatus":{"code":200}}`))
+	// service accounts do
+	assert.Equal(t, []string{"k8s_audit_secret_read"}, matchingRules(t, rs, `{"stage":"ResponseComplete","verb":"list","user":{"username":"system:serviceaccount:kube-system:default"},"objectRef":{"resource":"secrets"},"responseStatus":{"code":200}}`))
+	assert.Empty(t, matchingRules(t, rs, `{"stage":"ResponseComplete","verb":"create","user":{"username":"carol"},"objectRef":{"resource":"rolebindings"},"responseStatus":{"code":201},"requestObject":{"roleRef":{"kind":"Role","name":"view"}}}`))
+}
+
+func TestPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`rules:
+  - id: k8s_audit_secret_read
+    disabled: true
+  - id: k8s_audit_pod_exec
+    expression: k8s_audit.object.subresource == "exec" && "admins" in k8s_audit.user.groups
+  - id: k8s_audit_prod_access
+    description: A user accessed the prod namespace
+    expression: k8s_audit.object.namespace == "prod"
+`), 0o644))
+
+	policyRules, err := LoadPolicy(path)
+	require.NoError(t, err)
+	rs, err := NewRuleSet(policyRules)
+	require.NoError(t, err)
+	assert.Equal(t, 3, rs.Len())
+
+	assert.Empty(t, matchingRules(t, rs, podExecEvent), "the default rule is overridden")
+	assert.Equal(t, []string{"k8s_audit_prod_access"}, matchingRules(t, rs, secretReadEvent))
+	assert.Equal(t, []string{"k8s_audit_rbac_escalation"}, matchingRules(t, rs, rbacEscalationEvent))
+
+	_, err = NewRuleSet([]*RuleDefinition{{ID: "invalid", Expression: `k8s_audit.unknown == "foo"`}})
+	assert.Error(t, err)
+	_, err = NewRuleSet([]*RuleDefinition{{Expression: `k8s_audit.verb == "get"`}})
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("rules: {"), 0o644))
+	_, err = LoadPolicy(path)
+	assert.Error(t, err)
+}
+
+func TestEventJSON(t *testing.T) {
+	e := parseEventLine([]byte(rbacEscalationEvent))
+	require.NotNil(t, e)
+
+	data, err := e.ToJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"k8s_audit":{"auditID":"3"`)
+	assert.NotContains(t, string(data), "requestObject")
+	assert.Equal(t, []string{"type:k8s_audit"}, e.GetTags())
+
+	value, err := e.GetFieldValue("k8s_audit.request.role_ref.name")
+	require.NoError(t, err)
+	assert.Equal(t, "cluster-admin", value)
+	_, err = e.GetFieldValue("k8s_audit.unknown")
+	assert.Error(t, err)
+}
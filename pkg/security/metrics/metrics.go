@@ -24,6 +24,28 @@ var (
 	// Tags: -
 	MetricProcessEventsServerExpired = newRuntimeMetric(".event_server.process_events_expired")
 
+	// Event local sink metrics
+
+	// MetricLocalSinkSent is the name of the metric used to count the number of events mirrored to the local sink
+	// Tags: sink_type
+	MetricLocalSinkSent = newRuntimeMetric(".event_local_sink.sent")
+	// MetricLocalSinkDropped is the name of the metric used to count the number of events dropped by the rate limiter
+	// of the local sink
+	// Tags: sink_type
+	MetricLocalSinkDropped = newRuntimeMetric(".event_local_sink.dropped")
+	// MetricLocalSinkQueueFull is the name of the metric used to count the number of events dropped because the queue
+	// of the local sink was full
+	// Tags: sink_type
+	MetricLocalSinkQueueFull = newRuntimeMetric(".event_local_sink.queue_full")
+	// MetricLocalSinkFiltered is the name of the metric used to count the number of events not matching the rule tags
+	// filter of the local sink
+	// Tags: sink_type
+	MetricLocalSinkFiltered = newRuntimeMetric(".event_local_sink.filtered")
+	// MetricLocalSinkError is the name of the metric used to count the number of events that failed to be written to
+	// the local sink
+	// Tags: sink_type
+	MetricLocalSinkError = newRuntimeMetric(".event_local_sink.error")
+
 	// Rate limiter metrics
 
 	// MetricRateLimiterDrop is the name of the metric used to count the amount of events dropped by the rate limiter
@@ -324,12 +346,27 @@ var (
 	// of the Profile directory provider
 	// Tags: -
 	MetricSecurityProfileDirectoryProviderCount = newAgentMetric(".activity_dump.directory_provider.count")
+	// MetricSecurityProfileClusterAgentUploaded is the name of the metric used to track the count of profiles uploaded
+	// to the cluster agent
+	// Tags: -
+	MetricSecurityProfileClusterAgentUploaded = newAgentMetric(".security_profile.cluster_agent.uploaded")
+	// MetricSecurityProfileClusterAgentFetched is the name of the metric used to track the count of profiles fetched
+	// from the cluster agent
+	// Tags: -
+	MetricSecurityProfileClusterAgentFetched = newAgentMetric(".security_profile.cluster_agent.fetched")
 	// MetricSecurityProfileEvictedVersions is the name of the metric used to track the evicted profile versions
 	// Tags: image_name, image_tag
 	MetricSecurityProfileEvictedVersions = newAgentMetric(".security_profile.evicted_versions")
 	// MetricSecurityProfileVersions is the name of the metric used to track the number of versions a profile can have
 	// Tags: security_profile_image_name
 	MetricSecurityProfileVersions = newAgentMetric(".security_profile.versions")
+	// MetricSecurityProfileDriftScore is the name of the metric used to report the drift score of a profile, i.e. the
+	// number of nodes added to the profile during the last hour
+	// Tags: security_profile_image_name, stability
+	MetricSecurityProfileDriftScore = newAgentMetric(".security_profile.drift_score")
+	// MetricSecurityProfileStability is the name of the metric used to report the count of profiles per stability state
+	// Tags: stability
+	MetricSecurityProfileStability = newAgentMetric(".security_profile.stability")
 
 	// Hash resolver metrics
 
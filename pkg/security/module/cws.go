@@ -21,6 +21,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/eventmonitor"
 	"github.com/DataDog/datadog-agent/pkg/security/config"
 	"github.com/DataDog/datadog-agent/pkg/security/events"
+	"github.com/DataDog/datadog-agent/pkg/security/k8saudit"
 	"github.com/DataDog/datadog-agent/pkg/security/metrics"
 	"github.com/DataDog/datadog-agent/pkg/security/probe"
 	"github.com/DataDog/datadog-agent/pkg/security/probe/selftests"
@@ -65,6 +66,7 @@ type CWSConsumer struct {
 	selfTestPassed bool
 	reloader       ReloaderInterface
 	crtelemetry    *telemetry.ContainersRunningTelemetry
+	k8sAudit       *k8saudit.Monitor
 }
 
 // NewCWSConsumer initializes the module with options
@@ -118,6 +120,13 @@ func NewCWSConsumer(evm *eventmonitor.EventMonitor, cfg *config.RuntimeSecurityC
 		c.eventSender = c.APIServer()
 	}
 
+	if cfg.K8sAuditEnabled {
+		c.k8sAudit, err = k8saudit.NewMonitor(cfg, c.eventSender)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	seclog.Infof("Instantiating CWS rule engine")
 
 	var listeners []rules.RuleSetListener
@@ -189,6 +198,12 @@ func (c *CWSConsumer) Start() error {
 		return err
 	}
 
+	if c.k8sAudit != nil {
+		if err := c.k8sAudit.Start(); err != nil {
+			return err
+		}
+	}
+
 	c.wg.Add(1)
 	go c.statsSender()
 
@@ -301,6 +316,10 @@ func (c *CWSConsumer) Stop() {
 
 	c.cancelFnc()
 
+	if c.k8sAudit != nil {
+		c.k8sAudit.Stop()
+	}
+
 	c.ruleEngine.Stop()
 
 	c.wg.Wait()
@@ -15,6 +15,7 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/DataDog/datadog-agent/pkg/security/seclog"
+	grpcutil "github.com/DataDog/datadog-agent/pkg/util/grpc"
 )
 
 // GRPCServer defines a gRPC server
@@ -37,7 +38,7 @@ func NewGRPCServer(family string, address string) *GRPCServer {
 	return &GRPCServer{
 		family:  family,
 		address: address,
-		server:  grpc.NewServer(),
+		server:  grpc.NewServer(grpcutil.ServerKeepaliveOptions()...),
 	}
 }
 
@@ -127,6 +127,7 @@ type APIServer struct {
 	policiesStatusLock sync.RWMutex
 	policiesStatus     []*api.PolicyStatus
 	msgSender          MsgSender
+	localSink          *events.LocalSink
 	connEstablished    *atomic.Bool
 
 	// os release data
@@ -293,7 +294,7 @@ func (a *APIServer) start(ctx context.Context) {
 				}
 				a.updateMsgTags(m, false)
 
-				a.msgSender.Send(m, a.expireEvent)
+				a.sendMsg(m)
 
 				return true
 			})
@@ -420,10 +421,18 @@ func (a *APIServer) SendEvent(rule *rules.Rule, event events.Event, extTagsCb fu
 		}
 		a.updateMsgTags(m, true)
 
-		a.msgSender.Send(m, a.expireEvent)
+		a.sendMsg(m)
 	}
 }
 
+// sendMsg sends the message to the security-agent or the backend, mirroring it to the local sink if enabled
+func (a *APIServer) sendMsg(msg *api.SecurityEventMessage) {
+	if a.localSink != nil {
+		a.localSink.Forward(msg.RuleID, msg.Data, msg.Tags)
+	}
+	a.msgSender.Send(msg, a.expireEvent)
+}
+
 // expireEvent updates the count of expired messages for the appropriate rule
 func (a *APIServer) expireEvent(msg *api.SecurityEventMessage) {
 	a.expiredEventsLock.RLock()
@@ -467,6 +476,10 @@ func (a *APIServer) SendStats() error {
 			}
 		}
 	}
+
+	if a.localSink != nil {
+		return a.localSink.SendStats(a.statsdClient)
+	}
 	return nil
 }
 
@@ -553,6 +566,12 @@ func (a *APIServer) GetSECLVariables() map[string]*api.SECLVariableState {
 // Stop stops the API server
 func (a *APIServer) Stop() {
 	a.stopper.Stop()
+
+	if a.localSink != nil {
+		if err := a.localSink.Close(); err != nil {
+			seclog.Debugf("failed to close the event local sink: %v", err)
+		}
+	}
 }
 
 // SetCWSConsumer sets the CWS consumer
@@ -612,5 +631,22 @@ func NewAPIServer(cfg *config.RuntimeSecurityConfig, probe *sprobe.Probe, msgSen
 		}
 	}
 
+	if cfg.EventLocalSinkEnabled {
+		localSink, err := events.NewLocalSink(events.LocalSinkOpts{
+			Type:      cfg.EventLocalSinkType,
+			Address:   cfg.EventLocalSinkAddress,
+			Rate:      cfg.EventLocalSinkRate,
+			Burst:     cfg.EventLocalSinkBurst,
+			RuleTags:  cfg.EventLocalSinkRuleTags,
+			QueueSize: cfg.EventLocalSinkQueueSize,
+		})
+		if err != nil {
+			log.Errorf("failed to setup the event local sink: %v", err)
+		} else {
+			localSink.Start()
+			as.localSink = localSink
+		}
+	}
+
 	return as, nil
 }
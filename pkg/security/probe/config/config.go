@@ -158,6 +158,10 @@ type Config struct {
 
 	// DNSResolutionEnabled resolving DNS names from IP addresses
 	DNSResolutionEnabled bool
+
+	// DNSResolverMinTTL is the minimum time an IP address resolves to the hostnames of a DNS response, whatever
+	// the TTL of the records
+	DNSResolverMinTTL time.Duration
 }
 
 // NewConfig returns a new Config object
@@ -202,6 +206,7 @@ func NewConfig() (*Config, error) {
 		SyscallsMonitorEnabled:      getBool("syscalls_monitor.enabled"),
 		DNSResolverCacheSize:        getInt("dns_resolution.cache_size"),
 		DNSResolutionEnabled:        getBool("dns_resolution.enabled"),
+		DNSResolverMinTTL:           getDuration("dns_resolution.min_ttl"),
 
 		// event server
 		SocketPath:       pkgconfigsetup.SystemProbe().GetString(join(evNS, "socket")),
@@ -767,7 +767,7 @@ func (p *EBPFProbe) unmarshalDNSResponse(data []byte) {
 		} else {
 			ip, ok := netip.AddrFromSlice(answer.IP)
 			if ok {
-				p.Resolvers.DNSResolver.AddNew(string(answer.Name), ip)
+				p.Resolvers.DNSResolver.AddNew(string(answer.Name), ip, time.Duration(answer.TTL)*time.Second)
 			} else {
 				seclog.Errorf("DNS response with an invalid IP received: %v", ip)
 			}
@@ -114,6 +114,14 @@ func (p *EBPFLessProbe) handleClientMsg(cl *client, msg *ebpfless.Message) {
 	}
 }
 
+func copyXAttrAttributes(src *ebpfless.XAttrSyscallMsg, dst *model.SetXAttrEvent) {
+	dst.Name = src.Name
+	if ns, _, found := strings.Cut(src.Name, "."); found {
+		dst.Namespace = ns
+	}
+	copyFileAttributes(&src.File, &dst.File)
+}
+
 func copyFileAttributes(src *ebpfless.FileSyscallMsg, dst *model.FileEvent) {
 	if strings.HasPrefix(src.Filename, "memfd:") {
 		dst.SetPathnameStr("")
@@ -337,6 +345,41 @@ func (p *EBPFLessProbe) handleSyscallMsg(cl *client, syscallMsg *ebpfless.Syscal
 		event.Bind.AddrFamily = syscallMsg.Bind.AddressFamily
 		event.Bind.Protocol = syscallMsg.Bind.Protocol
 		event.Bind.Retval = syscallMsg.Retval
+
+	case ebpfless.SyscallTypeSetXAttr:
+		event.Type = uint32(model.FileSetXAttrEventType)
+		event.SetXAttr.Retval = syscallMsg.Retval
+		copyXAttrAttributes(syscallMsg.SetXAttr, &event.SetXAttr)
+
+	case ebpfless.SyscallTypeRemoveXAttr:
+		event.Type = uint32(model.FileRemoveXAttrEventType)
+		event.RemoveXAttr.Retval = syscallMsg.Retval
+		copyXAttrAttributes(syscallMsg.RemoveXAttr, &event.RemoveXAttr)
+
+	case ebpfless.SyscallTypeDNS:
+		event.Type = uint32(model.DNSEventType)
+		event.DNS = model.DNSEvent{
+			ID:    syscallMsg.DNS.ID,
+			Name:  syscallMsg.DNS.Name,
+			Type:  syscallMsg.DNS.Type,
+			Class: syscallMsg.DNS.Class,
+			Size:  syscallMsg.DNS.Size,
+			Count: syscallMsg.DNS.Count,
+		}
+		event.NetworkContext = model.NetworkContext{
+			L4Protocol: unix.IPPROTO_UDP,
+			Destination: model.IPPortContext{
+				IPNet: *eval.IPNetFromIP(syscallMsg.DNS.Addr),
+				Port:  syscallMsg.DNS.Port,
+			},
+			NetworkDirection: uint32(model.Egress),
+			Size:             uint32(syscallMsg.DNS.Size),
+		}
+		if syscallMsg.DNS.AddressFamily == unix.AF_INET6 {
+			event.NetworkContext.L3Protocol = unix.ETH_P_IPV6
+		} else {
+			event.NetworkContext.L3Protocol = unix.ETH_P_IP
+		}
 	}
 
 	// container context
@@ -97,6 +97,12 @@ const (
 	SyscallTypeConnect
 	// SyscallTypeBind bind
 	SyscallTypeBind
+	// SyscallTypeSetXAttr setxattr/lsetxattr/fsetxattr type
+	SyscallTypeSetXAttr
+	// SyscallTypeRemoveXAttr removexattr/lremovexattr/fremovexattr type
+	SyscallTypeRemoveXAttr
+	// SyscallTypeDNS sendto/sendmsg/sendmmsg of a DNS request type
+	SyscallTypeDNS
 )
 
 // ContainerContext defines a container context
@@ -335,6 +341,23 @@ type AcceptSyscallMsg struct {
 	SocketFd int32
 }
 
+// XAttrSyscallMsg defines a setxattr/removexattr message
+type XAttrSyscallMsg struct {
+	File FileSyscallMsg
+	Name string
+}
+
+// DNSSyscallMsg defines a DNS request message
+type DNSSyscallMsg struct {
+	MsgSocketInfo
+	ID    uint16
+	Name  string
+	Type  uint16
+	Class uint16
+	Size  uint16
+	Count uint16
+}
+
 // SyscallMsg defines a syscall message
 type SyscallMsg struct {
 	Type         SyscallType
@@ -369,6 +392,9 @@ type SyscallMsg struct {
 	Bind         *BindSyscallMsg         `json:",omitempty"`
 	Connect      *ConnectSyscallMsg      `json:",omitempty"`
 	Accept       *AcceptSyscallMsg       `json:",omitempty"`
+	SetXAttr     *XAttrSyscallMsg        `json:",omitempty"`
+	RemoveXAttr  *XAttrSyscallMsg        `json:",omitempty"`
+	DNS          *DNSSyscallMsg          `json:",omitempty"`
 
 	// internals
 	Dup    *DupSyscallFakeMsg    `json:",omitempty"`
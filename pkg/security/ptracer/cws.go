@@ -53,6 +53,7 @@ type Opts struct {
 	ProcScanDisabled bool
 	ScanProcEvery    time.Duration
 	SeccompDisabled  bool
+	DNSEnabled       bool
 	AttachedCb       func()
 
 	// internal
@@ -280,12 +281,12 @@ func (ctx *CWSPtracerCtx) handleClientConnection() {
 
 }
 
-func registerSyscallHandlers() (map[int]syscallHandler, []string) {
+func registerSyscallHandlers(opts *Opts) (map[int]syscallHandler, []string) {
 	handlers := make(map[int]syscallHandler)
 	syscalls := registerFIMHandlers(handlers)
 	syscalls = append(syscalls, registerProcessHandlers(handlers)...)
 	syscalls = append(syscalls, registerERPCHandlers(handlers)...)
-	syscalls = append(syscalls, registerNetworkHandlers(handlers)...)
+	syscalls = append(syscalls, registerNetworkHandlers(handlers, opts.DNSEnabled)...)
 	return handlers, syscalls
 }
 
@@ -311,7 +312,7 @@ func (ctx *CWSPtracerCtx) initCtxCommon() error {
 		return err
 	}
 
-	ctx.syscallHandlers, ctx.PtracedSyscalls = registerSyscallHandlers()
+	ctx.syscallHandlers, ctx.PtracedSyscalls = registerSyscallHandlers(ctx.opts)
 
 	ctx.msgDataChan = make(chan []byte, 100000)
 
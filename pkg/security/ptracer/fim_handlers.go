@@ -251,6 +251,42 @@ func registerFIMHandlers(handlers map[int]syscallHandler) []string {
 			ShouldSend: isAcceptedRetval,
 			RetFunc:    nil,
 		},
+		{
+			ID:         syscallID{ID: SetxattrNr, Name: "setxattr"},
+			Func:       handleSetXAttr,
+			ShouldSend: isAcceptedRetval,
+			RetFunc:    nil,
+		},
+		{
+			ID:         syscallID{ID: LsetxattrNr, Name: "lsetxattr"},
+			Func:       handleSetXAttr,
+			ShouldSend: isAcceptedRetval,
+			RetFunc:    nil,
+		},
+		{
+			ID:         syscallID{ID: FsetxattrNr, Name: "fsetxattr"},
+			Func:       handleFsetXAttr,
+			ShouldSend: isAcceptedRetval,
+			RetFunc:    nil,
+		},
+		{
+			ID:         syscallID{ID: RemovexattrNr, Name: "removexattr"},
+			Func:       handleRemoveXAttr,
+			ShouldSend: isAcceptedRetval,
+			RetFunc:    nil,
+		},
+		{
+			ID:         syscallID{ID: LremovexattrNr, Name: "lremovexattr"},
+			Func:       handleRemoveXAttr,
+			ShouldSend: isAcceptedRetval,
+			RetFunc:    nil,
+		},
+		{
+			ID:         syscallID{ID: FremovexattrNr, Name: "fremovexattr"},
+			Func:       handleFremoveXAttr,
+			ShouldSend: isAcceptedRetval,
+			RetFunc:    nil,
+		},
 		{
 			ID:      syscallID{ID: PipeNr, Name: "pipe"},
 			Func:    handlePipe2,
@@ -1108,6 +1144,80 @@ func handleFchownAt(tracer *Tracer, process *Process, msg *ebpfless.SyscallMsg,
 	return fillFileMetadata(tracer, filename, &msg.Chown.File, disableStats)
 }
 
+func handleSetXAttr(tracer *Tracer, process *Process, msg *ebpfless.SyscallMsg, regs syscall.PtraceRegs, disableStats bool) error {
+	filename, err := tracer.ReadArgString(process.Pid, regs, 0)
+	if err != nil {
+		return err
+	}
+
+	filename, err = getFullPathFromFilename(process, filename)
+	if err != nil {
+		return err
+	}
+
+	return fillXAttrMsg(tracer, process, msg, regs, filename, ebpfless.SyscallTypeSetXAttr, disableStats)
+}
+
+func handleFsetXAttr(tracer *Tracer, process *Process, msg *ebpfless.SyscallMsg, regs syscall.PtraceRegs, disableStats bool) error {
+	fd := tracer.ReadArgInt32(regs, 0)
+
+	filename, err := process.GetFilenameFromFd(fd)
+	if err != nil {
+		return fmt.Errorf("FD cache incomplete: %w", err)
+	}
+
+	return fillXAttrMsg(tracer, process, msg, regs, filename, ebpfless.SyscallTypeSetXAttr, disableStats)
+}
+
+func handleRemoveXAttr(tracer *Tracer, process *Process, msg *ebpfless.SyscallMsg, regs syscall.PtraceRegs, disableStats bool) error {
+	filename, err := tracer.ReadArgString(process.Pid, regs, 0)
+	if err != nil {
+		return err
+	}
+
+	filename, err = getFullPathFromFilename(process, filename)
+	if err != nil {
+		return err
+	}
+
+	return fillXAttrMsg(tracer, process, msg, regs, filename, ebpfless.SyscallTypeRemoveXAttr, disableStats)
+}
+
+func handleFremoveXAttr(tracer *Tracer, process *Process, msg *ebpfless.SyscallMsg, regs syscall.PtraceRegs, disableStats bool) error {
+	fd := tracer.ReadArgInt32(regs, 0)
+
+	filename, err := process.GetFilenameFromFd(fd)
+	if err != nil {
+		return fmt.Errorf("FD cache incomplete: %w", err)
+	}
+
+	return fillXAttrMsg(tracer, process, msg, regs, filename, ebpfless.SyscallTypeRemoveXAttr, disableStats)
+}
+
+// fillXAttrMsg fills a setxattr or removexattr message, the name of the extended attribute is the second argument
+// of all the variants of the syscalls
+func fillXAttrMsg(tracer *Tracer, process *Process, msg *ebpfless.SyscallMsg, regs syscall.PtraceRegs, filename string, syscallType ebpfless.SyscallType, disableStats bool) error {
+	name, err := tracer.ReadArgString(process.Pid, regs, 1)
+	if err != nil {
+		return err
+	}
+
+	xattrMsg := &ebpfless.XAttrSyscallMsg{
+		File: ebpfless.FileSyscallMsg{
+			Filename: filename,
+		},
+		Name: name,
+	}
+
+	msg.Type = syscallType
+	if syscallType == ebpfless.SyscallTypeSetXAttr {
+		msg.SetXAttr = xattrMsg
+	} else {
+		msg.RemoveXAttr = xattrMsg
+	}
+	return fillFileMetadata(tracer, filename, &xattrMsg.File, disableStats)
+}
+
 func handleMount(tracer *Tracer, process *Process, msg *ebpfless.SyscallMsg, regs syscall.PtraceRegs, _ bool) error {
 	source, err := tracer.ReadArgString(process.Pid, regs, 0)
 	if err != nil {
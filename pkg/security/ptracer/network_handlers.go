@@ -11,14 +11,31 @@ package ptracer
 import (
 	"encoding/binary"
 	"errors"
+	"golang.org/x/net/dns/dnsmessage"
 	"golang.org/x/sys/unix"
 	"net"
+	"strings"
 	"syscall"
 
 	"github.com/DataDog/datadog-agent/pkg/security/proto/ebpfless"
 )
 
-func registerNetworkHandlers(handlers map[int]syscallHandler) []string {
+const (
+	// dnsPort is the destination port of the DNS requests
+	dnsPort = 53
+	// maxDNSPayloadSize is the size of the DNS payload read from the tracee, enough to hold the header and the question
+	maxDNSPayloadSize = 512
+	// msghdrSize is the size of a struct msghdr
+	msghdrSize = 56
+	// mmsghdrSize is the size of a struct mmsghdr
+	mmsghdrSize = 64
+	// iovecSize is the size of a struct iovec
+	iovecSize = 16
+)
+
+// registerNetworkHandlers registers the network syscall handlers. The send syscalls are only traced to capture
+// the DNS requests when dnsEnabled is set, as they are among the most frequent syscalls of the network programs.
+func registerNetworkHandlers(handlers map[int]syscallHandler, dnsEnabled bool) []string {
 	processHandlers := []syscallHandler{
 		{
 			ID:         syscallID{ID: AcceptNr, Name: "accept"},
@@ -52,6 +69,29 @@ func registerNetworkHandlers(handlers map[int]syscallHandler) []string {
 		},
 	}
 
+	if dnsEnabled {
+		processHandlers = append(processHandlers, []syscallHandler{
+			{
+				ID:         syscallID{ID: SendtoNr, Name: "sendto"},
+				Func:       handleSendto,
+				ShouldSend: shouldSendDNS,
+				RetFunc:    nil,
+			},
+			{
+				ID:         syscallID{ID: SendmsgNr, Name: "sendmsg"},
+				Func:       handleSendmsg,
+				ShouldSend: shouldSendDNS,
+				RetFunc:    nil,
+			},
+			{
+				ID:         syscallID{ID: SendmmsgNr, Name: "sendmmsg"},
+				Func:       handleSendmmsg,
+				ShouldSend: shouldSendDNS,
+				RetFunc:    nil,
+			},
+		}...)
+	}
+
 	syscallList := []string{}
 	for _, h := range processHandlers {
 		if h.ID.ID >= 0 { // insert only available syscalls
@@ -82,6 +122,27 @@ func parseAddrInfo(tracer *Tracer, process *Process, regs syscall.PtraceRegs, ad
 		return nil, err
 	}
 
+	return decodeAddrInfo(data)
+}
+
+func readAddrInfo(tracer *Tracer, process *Process, ptr uint64, addrlen uint32) (*addrInfo, error) {
+	if addrlen < 16 {
+		return nil, errors.New("invalid address length")
+	}
+
+	if addrlen > 28 {
+		addrlen = 28
+	}
+
+	data, err := tracer.readData(process.Pid, ptr, uint(addrlen))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeAddrInfo(data)
+}
+
+func decodeAddrInfo(data []byte) (*addrInfo, error) {
 	var addr addrInfo
 
 	addr.af = binary.NativeEndian.Uint16(data[0:2])
@@ -90,7 +151,7 @@ func parseAddrInfo(tracer *Tracer, process *Process, regs syscall.PtraceRegs, ad
 	if addr.af == unix.AF_INET {
 		addr.ip = data[4:8]
 	} else if addr.af == unix.AF_INET6 {
-		if addrlen < 28 {
+		if len(data) < 28 {
 			return nil, errors.New("invalid address length")
 		}
 
@@ -170,6 +231,11 @@ func handleConnect(tracer *Tracer, process *Process, msg *ebpfless.SyscallMsg, r
 		return errors.New("unable to find protocol")
 	}
 
+	// keep track of the remote address, the DNS requests sent on connected sockets don't provide it
+	m.RemoteAddr = addr.ip
+	m.RemotePort = addr.port
+	process.FdToSocket[socketfd] = m
+
 	msg.Type = ebpfless.SyscallTypeConnect
 	msg.Connect = &ebpfless.ConnectSyscallMsg{
 		MsgSocketInfo: ebpfless.MsgSocketInfo{
@@ -262,6 +328,136 @@ func handleSocketRet(tracer *Tracer, process *Process, msg *ebpfless.SyscallMsg,
 	return nil
 }
 
+func handleSendto(tracer *Tracer, process *Process, msg *ebpfless.SyscallMsg, regs syscall.PtraceRegs, _ bool) error {
+	fd := tracer.ReadArgInt32(regs, 0)
+	bufPtr, size := tracer.ReadArgUint64(regs, 1), tracer.ReadArgUint64(regs, 2)
+
+	return fillDNSMsg(tracer, process, msg, fd, bufPtr, size, tracer.ReadArgUint64(regs, 4), tracer.ReadArgUint32(regs, 5))
+}
+
+func handleSendmsg(tracer *Tracer, process *Process, msg *ebpfless.SyscallMsg, regs syscall.PtraceRegs, _ bool) error {
+	return handleMsghdr(tracer, process, msg, tracer.ReadArgInt32(regs, 0), tracer.ReadArgUint64(regs, 1))
+}
+
+// handleSendmmsg only reports the first message of the vector, the resolvers sending several requests at once
+// usually query the same name for different types
+func handleSendmmsg(tracer *Tracer, process *Process, msg *ebpfless.SyscallMsg, regs syscall.PtraceRegs, _ bool) error {
+	if tracer.ReadArgUint32(regs, 2) == 0 {
+		return nil
+	}
+	return handleMsghdr(tracer, process, msg, tracer.ReadArgInt32(regs, 0), tracer.ReadArgUint64(regs, 1))
+}
+
+func handleMsghdr(tracer *Tracer, process *Process, msg *ebpfless.SyscallMsg, fd int32, msghdrPtr uint64) error {
+	if !isDNSSocket(process, fd) {
+		return nil
+	}
+
+	msghdr, err := tracer.readData(process.Pid, msghdrPtr, msghdrSize)
+	if err != nil {
+		return err
+	}
+
+	namePtr := binary.NativeEndian.Uint64(msghdr[0:8])
+	namelen := binary.NativeEndian.Uint32(msghdr[8:12])
+	iovPtr := binary.NativeEndian.Uint64(msghdr[16:24])
+	iovlen := binary.NativeEndian.Uint64(msghdr[24:32])
+	if iovlen == 0 {
+		return nil
+	}
+
+	// the DNS request is expected to be held by the first buffer
+	iovec, err := tracer.readData(process.Pid, iovPtr, iovecSize)
+	if err != nil {
+		return err
+	}
+
+	return fillDNSMsg(tracer, process, msg, fd, binary.NativeEndian.Uint64(iovec[0:8]), binary.NativeEndian.Uint64(iovec[8:16]), namePtr, namelen)
+}
+
+// isDNSSocket returns whether the fd is an UDP socket that may be used to send DNS requests
+func isDNSSocket(process *Process, fd int32) bool {
+	socketInfo, ok := process.FdToSocket[fd]
+	return ok && socketInfo.Protocol == unix.IPPROTO_UDP && (socketInfo.AddressFamily == unix.AF_INET || socketInfo.AddressFamily == unix.AF_INET6)
+}
+
+// fillDNSMsg fills a DNS message from the payload sent on an UDP socket, to the given destination address or to the
+// remote address the socket is connected to. The requests sent with write(2) on a connected socket aren't reported.
+func fillDNSMsg(tracer *Tracer, process *Process, msg *ebpfless.SyscallMsg, fd int32, bufPtr uint64, size uint64, namePtr uint64, namelen uint32) error {
+	if !isDNSSocket(process, fd) {
+		return nil
+	}
+	socketInfo := process.FdToSocket[fd]
+
+	var addr *addrInfo
+	if namePtr != 0 {
+		var err error
+		if addr, err = readAddrInfo(tracer, process, namePtr, namelen); err != nil {
+			return err
+		}
+	} else if socketInfo.RemoteAddr != nil {
+		addr = &addrInfo{
+			ip:   socketInfo.RemoteAddr,
+			port: socketInfo.RemotePort,
+			af:   socketInfo.AddressFamily,
+		}
+	} else {
+		return nil
+	}
+
+	if addr.port != dnsPort {
+		return nil
+	}
+
+	payload, err := tracer.readData(process.Pid, bufPtr, uint(min(size, maxDNSPayloadSize)))
+	if err != nil {
+		return err
+	}
+
+	dnsMsg, err := parseDNSRequest(payload)
+	if err != nil {
+		return err
+	}
+	dnsMsg.MsgSocketInfo = ebpfless.MsgSocketInfo{
+		AddressFamily: addr.af,
+		Addr:          addr.ip,
+		Port:          addr.port,
+	}
+	dnsMsg.Size = uint16(min(size, uint64(^uint16(0))))
+
+	msg.Type = ebpfless.SyscallTypeDNS
+	msg.DNS = dnsMsg
+
+	return nil
+}
+
+// parseDNSRequest parses the header and the first question of a DNS request
+func parseDNSRequest(payload []byte) (*ebpfless.DNSSyscallMsg, error) {
+	var parser dnsmessage.Parser
+
+	header, err := parser.Start(payload)
+	if err != nil {
+		return nil, err
+	}
+	if header.Response {
+		return nil, errors.New("not a DNS request")
+	}
+
+	question, err := parser.Question()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ebpfless.DNSSyscallMsg{
+		ID:    header.ID,
+		Name:  strings.TrimSuffix(question.Name.String(), "."),
+		Type:  uint16(question.Type),
+		Class: uint16(question.Class),
+		// the question count is the third field of the header
+		Count: binary.BigEndian.Uint16(payload[4:6]),
+	}, nil
+}
+
 // Should send messages
 func shouldSendConnect(msg *ebpfless.SyscallMsg) bool {
 	return msg.Retval >= 0 || msg.Retval == -int64(syscall.EACCES) || msg.Retval == -int64(syscall.EPERM) || msg.Retval == -int64(syscall.ECONNREFUSED) || msg.Retval == -int64(syscall.ETIMEDOUT) || msg.Retval == -int64(syscall.EINPROGRESS)
@@ -274,3 +470,7 @@ func shouldSendAccept(msg *ebpfless.SyscallMsg) bool {
 func shouldSendBind(msg *ebpfless.SyscallMsg) bool {
 	return msg.Retval >= 0 || msg.Retval == -int64(syscall.EACCES) || msg.Retval == -int64(syscall.EPERM) || msg.Retval == -int64(syscall.EADDRINUSE) || msg.Retval == -int64(syscall.EFAULT)
 }
+
+func shouldSendDNS(msg *ebpfless.SyscallMsg) bool {
+	return msg.Type == ebpfless.SyscallTypeDNS && msg.Retval >= 0
+}
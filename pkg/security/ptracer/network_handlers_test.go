@@ -0,0 +1,47 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package ptracer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestParseDNSRequest(t *testing.T) {
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: 1234, RecursionDesired: true})
+	require.NoError(t, builder.StartQuestions())
+	require.NoError(t, builder.Question(dnsmessage.Question{
+		Name:  dnsmessage.MustNewName("tunnel.us.ngrok.io."),
+		Type:  dnsmessage.TypeAAAA,
+		Class: dnsmessage.ClassINET,
+	}))
+	payload, err := builder.Finish()
+	require.NoError(t, err)
+
+	msg, err := parseDNSRequest(payload)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(1234), msg.ID)
+	assert.Equal(t, "tunnel.us.ngrok.io", msg.Name)
+	assert.Equal(t, uint16(dnsmessage.TypeAAAA), msg.Type)
+	assert.Equal(t, uint16(dnsmessage.ClassINET), msg.Class)
+	assert.Equal(t, uint16(1), msg.Count)
+
+	// truncated payload
+	_, err = parseDNSRequest(payload[:len(payload)-3])
+	assert.Error(t, err)
+
+	// responses aren't reported
+	builder = dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: 1234, Response: true})
+	payload, err = builder.Finish()
+	require.NoError(t, err)
+	_, err = parseDNSRequest(payload)
+	assert.Error(t, err)
+}
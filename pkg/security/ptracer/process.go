@@ -12,6 +12,7 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"net"
 	"os"
 	"slices"
 	"strings"
@@ -58,6 +59,8 @@ type SocketInfo struct {
 	AddressFamily uint16
 	Protocol      uint16
 	BoundToPort   uint16
+	RemoteAddr    net.IP
+	RemotePort    uint16
 }
 
 // Process represents a process context
@@ -81,6 +81,15 @@ const (
 	AcceptNr         = unix.SYS_ACCEPT            // AcceptNr defines the syscall ID for amd64
 	BindNr           = unix.SYS_BIND              // BindNr defines the syscall ID for amd64
 	SocketNr         = unix.SYS_SOCKET            // SocketNr defines the syscall ID for amd64
+	SetxattrNr       = unix.SYS_SETXATTR          // SetxattrNr defines the syscall ID for amd64
+	LsetxattrNr      = unix.SYS_LSETXATTR         // LsetxattrNr defines the syscall ID for amd64
+	FsetxattrNr      = unix.SYS_FSETXATTR         // FsetxattrNr defines the syscall ID for amd64
+	RemovexattrNr    = unix.SYS_REMOVEXATTR       // RemovexattrNr defines the syscall ID for amd64
+	LremovexattrNr   = unix.SYS_LREMOVEXATTR      // LremovexattrNr defines the syscall ID for amd64
+	FremovexattrNr   = unix.SYS_FREMOVEXATTR      // FremovexattrNr defines the syscall ID for amd64
+	SendtoNr         = unix.SYS_SENDTO            // SendtoNr defines the syscall ID for amd64
+	SendmsgNr        = unix.SYS_SENDMSG           // SendmsgNr defines the syscall ID for amd64
+	SendmmsgNr       = unix.SYS_SENDMMSG          // SendmmsgNr defines the syscall ID for amd64
 )
 
 // https://github.com/torvalds/linux/blob/v5.0/arch/x86/entry/entry_64.S#L126
@@ -63,6 +63,15 @@ const (
 	AcceptNr         = unix.SYS_ACCEPT            // AcceptNr defines the syscall ID for arm64
 	Accept4Nr        = unix.SYS_ACCEPT4           // Accept4Nr defines the syscall ID for arm64
 	SocketNr         = unix.SYS_SOCKET            // SocketNr defines the syscall ID for arm64
+	SetxattrNr       = unix.SYS_SETXATTR          // SetxattrNr defines the syscall ID for arm64
+	LsetxattrNr      = unix.SYS_LSETXATTR         // LsetxattrNr defines the syscall ID for arm64
+	FsetxattrNr      = unix.SYS_FSETXATTR         // FsetxattrNr defines the syscall ID for arm64
+	RemovexattrNr    = unix.SYS_REMOVEXATTR       // RemovexattrNr defines the syscall ID for arm64
+	LremovexattrNr   = unix.SYS_LREMOVEXATTR      // LremovexattrNr defines the syscall ID for arm64
+	FremovexattrNr   = unix.SYS_FREMOVEXATTR      // FremovexattrNr defines the syscall ID for arm64
+	SendtoNr         = unix.SYS_SENDTO            // SendtoNr defines the syscall ID for arm64
+	SendmsgNr        = unix.SYS_SENDMSG           // SendmsgNr defines the syscall ID for arm64
+	SendmmsgNr       = unix.SYS_SENDMMSG          // SendmmsgNr defines the syscall ID for arm64
 
 	OpenNr      = -1  // OpenNr not available on arm64
 	ForkNr      = -2  // ForkNr not available on arm64
@@ -17,6 +17,8 @@ import (
 	"go.uber.org/atomic"
 	"net/netip"
 	"slices"
+	"strings"
+	"time"
 )
 
 // CacheStats defines metrics for the LRU
@@ -27,13 +29,21 @@ type CacheStats struct {
 	cacheEvictions  atomic.Int64
 }
 
+// hostRecord is a hostname an IP address resolves to, until the record expires
+type hostRecord struct {
+	hostname string
+	expires  time.Time
+}
+
 // Resolver defines a DNS resolver
 type Resolver struct {
-	cache         *lru.Cache[netip.Addr, []string]
+	cache         *lru.Cache[netip.Addr, []hostRecord]
 	cnameCache    *lru.Cache[string, []string]
 	statsdClient  statsd.ClientInterface
 	resolverStats *CacheStats
 	cnameStats    *CacheStats
+	minTTL        time.Duration
+	now           func() time.Time
 }
 
 // NewDNSResolver returns a new resolver
@@ -42,9 +52,11 @@ func NewDNSResolver(cfg *config.Config, statsdClient statsd.ClientInterface) (*R
 		statsdClient:  statsdClient,
 		resolverStats: &CacheStats{},
 		cnameStats:    &CacheStats{},
+		minTTL:        cfg.DNSResolverMinTTL,
+		now:           time.Now,
 	}
 
-	cbResolver := func(netip.Addr, []string) {
+	cbResolver := func(netip.Addr, []hostRecord) {
 		ret.resolverStats.cacheEvictions.Inc()
 	}
 
@@ -54,7 +66,7 @@ func NewDNSResolver(cfg *config.Config, statsdClient statsd.ClientInterface) (*R
 
 	var err error
 
-	ret.cache, err = lru.NewWithEvict[netip.Addr, []string](cfg.DNSResolverCacheSize, cbResolver)
+	ret.cache, err = lru.NewWithEvict[netip.Addr, []hostRecord](cfg.DNSResolverCacheSize, cbResolver)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize DNS cache: %w", err)
 	}
@@ -87,41 +99,58 @@ func (r *Resolver) fillWithCnames(hostname string, hostnames *[]string, depth in
 	}
 }
 
-// HostListFromIP gets a hostname from an IP address if cached
+// HostListFromIP gets the hostnames an IP address resolves to if cached, along with their cname aliases. The
+// records which expired are ignored.
 func (r *Resolver) HostListFromIP(addr netip.Addr) []string {
-	hostnames, ok := r.cache.Get(addr)
+	records, ok := r.cache.Get(addr)
 	if ok {
-		r.resolverStats.cacheHits.Inc()
+		now := r.now()
 
 		var allHosts []string
-		for _, hostname := range hostnames {
-			allHosts = append(allHosts, hostname)
-			r.fillWithCnames(hostname, &allHosts, 2)
+		for _, record := range records {
+			if now.After(record.expires) {
+				continue
+			}
+			allHosts = append(allHosts, record.hostname)
+			r.fillWithCnames(record.hostname, &allHosts, 2)
 		}
 
-		return allHosts
+		if len(allHosts) > 0 {
+			r.resolverStats.cacheHits.Inc()
+			return allHosts
+		}
+		r.cache.Remove(addr)
 	}
 
 	r.resolverStats.cacheMisses.Inc()
 	return nil
 }
 
-// AddNew add new ip address to the resolver cache
-func (r *Resolver) AddNew(hostname string, ip netip.Addr) {
-	hostnames, ok := r.cache.Get(ip)
+// AddNew add new ip address to the resolver cache, resolving to the hostname for the TTL of the record, or the
+// minimum TTL if longer
+func (r *Resolver) AddNew(hostname string, ip netip.Addr, ttl time.Duration) {
+	hostname = NormalizeHostname(hostname)
+	expires := r.now().Add(max(ttl, r.minTTL))
 
+	records, ok := r.cache.Get(ip)
 	if !ok {
 		r.resolverStats.cacheInsertions.Inc()
-		hostnames = []string{hostname}
-	} else if !slices.Contains(hostnames, hostname) {
-		hostnames = append(hostnames, hostname)
+		records = []hostRecord{{hostname: hostname, expires: expires}}
+	} else if i := slices.IndexFunc(records, func(record hostRecord) bool { return record.hostname == hostname }); i >= 0 {
+		records = slices.Clone(records)
+		records[i].expires = expires
+	} else {
+		records = append(records, hostRecord{hostname: hostname, expires: expires})
 	}
 
-	r.cache.Add(ip, hostnames)
+	r.cache.Add(ip, records)
 }
 
 // AddNewCname add new cname alias to the cache
 func (r *Resolver) AddNewCname(cname string, hostname string) {
+	cname = NormalizeHostname(cname)
+	hostname = NormalizeHostname(hostname)
+
 	hostnames, ok := r.cnameCache.Get(cname)
 
 	if !ok {
@@ -134,6 +163,12 @@ func (r *Resolver) AddNewCname(cname string, hostname string) {
 	r.cnameCache.Add(cname, hostnames)
 }
 
+// NormalizeHostname returns the canonical form of a hostname, lower case and without the trailing dot of the
+// fully qualified names, so that rules can match it whatever the case of the DNS response
+func NormalizeHostname(hostname string) string {
+	return strings.ToLower(strings.TrimSuffix(hostname, "."))
+}
+
 // SendStats sends the DNS resolver metrics
 func (r *Resolver) SendStats() error {
 	entry := []string{
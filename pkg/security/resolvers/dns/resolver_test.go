@@ -0,0 +1,49 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package dns
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/security/probe/config"
+)
+
+func TestHostListFromIP(t *testing.T) {
+	r, err := NewDNSResolver(&config.Config{DNSResolverCacheSize: 10, DNSResolverMinTTL: time.Minute}, &statsd.NoOpClient{})
+	require.NoError(t, err)
+
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	ip := netip.MustParseAddr("3.125.102.39")
+	r.AddNewCname("Tunnel.us.NGROK.io.", "abc123.ngrok.io.")
+	r.AddNew("tunnel.us.ngrok.io.", ip, 10*time.Minute)
+	r.AddNew("api.example.com", ip, 0)
+
+	assert.Equal(t, []string{"tunnel.us.ngrok.io", "abc123.ngrok.io", "api.example.com"}, r.HostListFromIP(ip))
+	assert.Empty(t, r.HostListFromIP(netip.MustParseAddr("10.0.0.1")))
+
+	// the records expire after their TTL, or the minimum TTL
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, []string{"tunnel.us.ngrok.io", "abc123.ngrok.io"}, r.HostListFromIP(ip))
+
+	// a new response extends the TTL
+	r.AddNew("api.example.com", ip, time.Hour)
+	now = now.Add(30 * time.Minute)
+	assert.Equal(t, []string{"api.example.com"}, r.HostListFromIP(ip))
+
+	now = now.Add(time.Hour)
+	assert.Empty(t, r.HostListFromIP(ip))
+	assert.False(t, r.cache.Contains(ip))
+}
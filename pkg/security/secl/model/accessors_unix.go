@@ -101,6 +101,7 @@ func (_ *Model) GetEvaluator(field eval.Field, regID eval.RegisterID) (eval.Eval
 		}, nil
 	case "accept.addr.hostname":
 		return &eval.StringArrayEvaluator{
+			OpOverrides: HostnameCmp,
 			EvalFnc: func(ctx *eval.Context) []string {
 				ctx.AppendResolvedField(field)
 				ev := ctx.Event.(*Event)
@@ -1147,6 +1148,7 @@ func (_ *Model) GetEvaluator(field eval.Field, regID eval.RegisterID) (eval.Eval
 		}, nil
 	case "connect.addr.hostname":
 		return &eval.StringArrayEvaluator{
+			OpOverrides: HostnameCmp,
 			EvalFnc: func(ctx *eval.Context) []string {
 				ctx.AppendResolvedField(field)
 				ev := ctx.Event.(*Event)
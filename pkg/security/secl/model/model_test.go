@@ -15,6 +15,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/ast"
 	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
 )
 
@@ -232,3 +233,54 @@ func TestSetFieldValue(t *testing.T) {
 		}
 	}
 }
+
+func TestHostnameNormalization(t *testing.T) {
+	for _, expr := range []string{
+		`connect.addr.hostname == "API.Example.com."`,
+		`connect.addr.hostname in ["other.example.com", "api.example.com."]`,
+		`connect.addr.hostname =~ "*.EXAMPLE.com"`,
+		`connect.addr.hostname in [~"*.example.COM."]`,
+	} {
+		rule, err := eval.NewRule("hostname", expr, ast.NewParsingContext(false), &eval.Opts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rule.GenEvaluator(&Model{}); err != nil {
+			t.Fatalf("%s should be valid: %s", expr, err)
+		}
+
+		event := NewFakeEvent()
+		event.Type = uint32(ConnectEventType)
+		event.Connect.Hostnames = []string{"api.example.com"}
+		if !rule.Eval(eval.NewContext(event)) {
+			t.Errorf("%s should match api.example.com", expr)
+		}
+	}
+}
+
+func TestHostnameMatching(t *testing.T) {
+	rule, err := eval.NewRule("ngrok", `connect.addr.hostname =~ "*.ngrok.io"`, ast.NewParsingContext(false), &eval.Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rule.GenEvaluator(&Model{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for hostnames, expected := range map[string]bool{
+		"tunnel.us.ngrok.io":              true,
+		"api.example.com,abc123.ngrok.io": true,
+		"api.example.com":                 false,
+		"ngrok.io.example.com":            false,
+		"":                                false,
+	} {
+		event := NewFakeEvent()
+		event.Type = uint32(ConnectEventType)
+		if hostnames != "" {
+			event.Connect.Hostnames = strings.Split(hostnames, ",")
+		}
+		if rule.Eval(eval.NewContext(event)) != expected {
+			t.Errorf("expected %v for %s", expected, hostnames)
+		}
+	}
+}
@@ -772,19 +772,19 @@ type BindEvent struct {
 type ConnectEvent struct {
 	SyscallEvent
 
-	Addr       IPPortContext `field:"addr"`          // Connection address
-	Hostnames  []string      `field:"addr.hostname"` // SECLDoc[addr.hostname] Definition:`Address hostname (if available)`
-	AddrFamily uint16        `field:"addr.family"`   // SECLDoc[addr.family] Definition:`Address family`
-	Protocol   uint16        `field:"protocol"`      // SECLDoc[protocol] Definition:`Socket Protocol`
+	Addr       IPPortContext `field:"addr"`                                    // Connection address
+	Hostnames  []string      `field:"addr.hostname" op_override:"HostnameCmp"` // SECLDoc[addr.hostname] Definition:`Address hostname (if available)` Example:`connect.addr.hostname =~ "*.ngrok.io"` Description:`Matches the connections to the hosts resolved from a subdomain of ngrok.io. The hostnames are resolved from the DNS responses, in lower case and without trailing dot, and the hostnames of the rule are normalized the same way.`
+	AddrFamily uint16        `field:"addr.family"`                             // SECLDoc[addr.family] Definition:`Address family`
+	Protocol   uint16        `field:"protocol"`                                // SECLDoc[protocol] Definition:`Socket Protocol`
 }
 
 // AcceptEvent represents an accept event
 type AcceptEvent struct {
 	SyscallEvent
 
-	Addr       IPPortContext `field:"addr"`          // Connection address
-	Hostnames  []string      `field:"addr.hostname"` // SECLDoc[addr.hostname] Definition:`Address hostname (if available)`
-	AddrFamily uint16        `field:"addr.family"`   // SECLDoc[addr.family] Definition:`Address family`
+	Addr       IPPortContext `field:"addr"`                                    // Connection address
+	Hostnames  []string      `field:"addr.hostname" op_override:"HostnameCmp"` // SECLDoc[addr.hostname] Definition:`Address hostname (if available)`
+	AddrFamily uint16        `field:"addr.family"`                             // SECLDoc[addr.family] Definition:`Address family`
 }
 
 // NetDevice represents a network device
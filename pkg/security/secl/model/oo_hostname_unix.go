@@ -0,0 +1,47 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build unix
+
+// Package model holds model related files
+package model
+
+import (
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+)
+
+// normalizeHostname returns the hostname in the form of the resolved hostnames, in lower case and without trailing dot
+func normalizeHostname(hostname string) string {
+	return strings.TrimSuffix(strings.ToLower(hostname), ".")
+}
+
+var (
+	// HostnameCmp normalizes the hostnames provided in the rules, so that they match the resolved hostnames
+	HostnameCmp = &eval.OpOverrides{
+		StringArrayContains: func(a *eval.StringEvaluator, b *eval.StringArrayEvaluator, state *eval.State) (*eval.BoolEvaluator, error) {
+			if a.EvalFnc == nil && a.ValueType != eval.RegexpValueType {
+				a.Value = normalizeHostname(a.Value)
+			}
+
+			return eval.StringArrayContains(a, b, state)
+		},
+		StringArrayMatches: func(a *eval.StringArrayEvaluator, b *eval.StringValuesEvaluator, state *eval.State) (*eval.BoolEvaluator, error) {
+			if b.EvalFnc == nil {
+				var values eval.StringValues
+				for _, value := range b.Values.GetFieldValues() {
+					if hostname, ok := value.Value.(string); ok && value.Type != eval.RegexpValueType {
+						value.Value = normalizeHostname(hostname)
+					}
+					values.AppendFieldValue(value)
+				}
+				b.Values = values
+			}
+
+			return eval.StringArrayMatches(a, b, state)
+		},
+	}
+)
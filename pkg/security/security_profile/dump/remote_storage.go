@@ -55,7 +55,7 @@ func NewActivityDumpRemoteStorage() (ActivityDumpStorage, error) {
 	storage := &ActivityDumpRemoteStorage{
 		tooLargeEntities: make(map[tooLargeEntityStatsEntry]*atomic.Uint64),
 		client: &http.Client{
-			Transport: ddhttputil.CreateHTTPTransport(pkgconfigsetup.Datadog()),
+			Transport: ddhttputil.NewTransport(pkgconfigsetup.Datadog(), ddhttputil.LogsDestination),
 		},
 	}
 
@@ -0,0 +1,167 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build linux
+
+// Package profile holds profile related files
+package profile
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// driftWindow is the window over which the drift score of a profile is computed
+	driftWindow = time.Hour
+	// driftBucketsCount is the number of buckets of the drift window
+	driftBucketsCount = 60
+	// driftBucketDuration is the duration of a bucket of the drift window
+	driftBucketDuration = driftWindow / driftBucketsCount
+)
+
+// Stability defines the stability state of a security profile, computed from its drift score
+type Stability uint8
+
+const (
+	// Learning is the state of a profile which keeps learning new behaviors
+	Learning Stability = iota
+	// Stabilizing is the state of a profile whose drift score is at or below the stable threshold, for less than the
+	// stable period
+	Stabilizing
+	// Stable is the state of a profile whose drift score stayed at or below the stable threshold for the stable
+	// period, it can be switched from learning to enforcement
+	Stable
+	// Drifting is the state of a profile which was stable, and whose drift score went above the stable threshold again
+	Drifting
+)
+
+// AllStabilities is the list of all Stability
+var AllStabilities = []Stability{Learning, Stabilizing, Stable, Drifting}
+
+// String returns the string representation of the Stability
+func (s Stability) String() string {
+	switch s {
+	case Learning:
+		return "learning"
+	case Stabilizing:
+		return "stabilizing"
+	case Stable:
+		return "stable"
+	case Drifting:
+		return "drifting"
+	}
+	return ""
+}
+
+// ToTag returns the tag representation of the Stability
+func (s Stability) ToTag() string {
+	return "stability:" + s.String()
+}
+
+// profileDrift tracks the nodes added to a profile, to compute its drift score and stability
+type profileDrift struct {
+	sync.Mutex
+
+	// buckets holds the count of nodes added per bucket of the drift window, indexed by bucket modulo the count
+	buckets [driftBucketsCount]uint64
+	// lastBucket is the bucket of the last added node, in bucket durations since the epoch
+	lastBucket int64
+
+	stability Stability
+	// wasStable is true once the profile reached the stable state
+	wasStable bool
+	// stableSince is the time since which the drift score is at or below the stable threshold
+	stableSince time.Time
+}
+
+func driftBucket(t time.Time) int64 {
+	return t.UnixNano() / int64(driftBucketDuration)
+}
+
+// rotate resets the buckets which went out of the drift window
+func (d *profileDrift) rotate(bucket int64) {
+	if bucket <= d.lastBucket {
+		return
+	}
+	if bucket-d.lastBucket >= driftBucketsCount {
+		d.buckets = [driftBucketsCount]uint64{}
+	} else {
+		for b := d.lastBucket + 1; b <= bucket; b++ {
+			d.buckets[b%driftBucketsCount] = 0
+		}
+	}
+	d.lastBucket = bucket
+}
+
+// addNode records a node added to the profile
+func (d *profileDrift) addNode(now time.Time) {
+	d.Lock()
+	defer d.Unlock()
+
+	bucket := driftBucket(now)
+	d.rotate(bucket)
+	if bucket > d.lastBucket-driftBucketsCount {
+		d.buckets[bucket%driftBucketsCount]++
+	}
+}
+
+// scoreLocked returns the drift score of the profile: the number of nodes added during the last hour
+func (d *profileDrift) scoreLocked(now time.Time) float64 {
+	d.rotate(driftBucket(now))
+
+	var count uint64
+	for _, c := range d.buckets {
+		count += c
+	}
+	return float64(count)
+}
+
+// update computes the drift score of the profile, and updates its stability state accordingly
+func (d *profileDrift) update(now time.Time, threshold float64, stablePeriod time.Duration) (float64, Stability) {
+	d.Lock()
+	defer d.Unlock()
+
+	score := d.scoreLocked(now)
+	if score > threshold {
+		d.stableSince = time.Time{}
+		if d.wasStable {
+			d.stability = Drifting
+		} else {
+			d.stability = Learning
+		}
+		return score, d.stability
+	}
+
+	if d.stableSince.IsZero() {
+		d.stableSince = now
+	}
+	if now.Sub(d.stableSince) >= stablePeriod {
+		d.stability = Stable
+		d.wasStable = true
+	} else {
+		d.stability = Stabilizing
+	}
+	return score, d.stability
+}
+
+// reset forgets the nodes added to the profile and its stability state
+func (d *profileDrift) reset() {
+	d.Lock()
+	defer d.Unlock()
+
+	d.buckets = [driftBucketsCount]uint64{}
+	d.lastBucket = 0
+	d.stability = Learning
+	d.wasStable = false
+	d.stableSince = time.Time{}
+}
+
+// get returns the drift score and the stability state of the profile, as of their last update
+func (d *profileDrift) get(now time.Time) (float64, Stability) {
+	d.Lock()
+	defer d.Unlock()
+	return d.scoreLocked(now), d.stability
+}
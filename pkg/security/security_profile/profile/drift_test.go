@@ -0,0 +1,67 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build linux
+
+// Package profile holds profile related files
+package profile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileDriftScore(t *testing.T) {
+	var d profileDrift
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 10; i++ {
+		d.addNode(t0.Add(time.Duration(i) * time.Minute))
+	}
+	score, _ := d.get(t0.Add(10 * time.Minute))
+	assert.Equal(t, 10.0, score)
+
+	// the nodes added more than an hour ago don't count anymore
+	score, _ = d.get(t0.Add(time.Hour + 5*time.Minute))
+	assert.Equal(t, 4.0, score)
+
+	score, _ = d.get(t0.Add(3 * time.Hour))
+	assert.Equal(t, 0.0, score)
+}
+
+func TestProfileStability(t *testing.T) {
+	var d profileDrift
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	threshold, stablePeriod := 1.0, 2*time.Hour
+
+	update := func(now time.Time) Stability {
+		_, stability := d.update(now, threshold, stablePeriod)
+		return stability
+	}
+
+	d.addNode(t0)
+	d.addNode(t0)
+	assert.Equal(t, Learning, update(t0))
+
+	// the score goes below the threshold
+	assert.Equal(t, Stabilizing, update(t0.Add(time.Hour)))
+	assert.Equal(t, Stabilizing, update(t0.Add(2*time.Hour)))
+	assert.Equal(t, Stable, update(t0.Add(3*time.Hour)))
+
+	// a stable profile which learns again is drifting
+	t1 := t0.Add(4 * time.Hour)
+	d.addNode(t1)
+	d.addNode(t1)
+	assert.Equal(t, Drifting, update(t1))
+	assert.Equal(t, Stabilizing, update(t1.Add(time.Hour)))
+	d.addNode(t1.Add(time.Hour))
+	d.addNode(t1.Add(time.Hour))
+	assert.Equal(t, Drifting, update(t1.Add(time.Hour)))
+
+	d.reset()
+	assert.Equal(t, Stabilizing, update(t1.Add(time.Hour)))
+}
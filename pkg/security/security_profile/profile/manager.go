@@ -181,6 +181,11 @@ func NewSecurityProfileManager(config *config.Config, statsdClient statsd.Client
 		m.onLocalStorageCleanup = dirProvider.OnLocalStorageCleanup
 	}
 
+	// instantiate cluster agent provider
+	if config.RuntimeSecurity.SecurityProfileClusterAgentEnabled {
+		m.providers = append(m.providers, NewClusterAgentProvider(config.RuntimeSecurity.SecurityProfileClusterAgentUploadPeriod, m.stableProfiles))
+	}
+
 	m.initMetricsMap()
 
 	// register the manager to the provider(s)
@@ -235,6 +240,26 @@ func (m *SecurityProfileManager) Start(ctx context.Context) {
 	m.stop()
 }
 
+// stableProfiles returns the protobuf representation of the stable profiles loaded in kernel space
+func (m *SecurityProfileManager) stableProfiles() []*proto.SecurityProfile {
+	m.profilesLock.Lock()
+	defer m.profilesLock.Unlock()
+
+	var profiles []*proto.SecurityProfile
+	for _, profile := range m.profiles {
+		profile.Lock()
+		if profile.loadedInKernel && profile.ActivityTree != nil {
+			profile.versionContextsLock.Lock()
+			if profile.getGlobalState() == model.StableEventType {
+				profiles = append(profiles, SecurityProfileToProto(profile))
+			}
+			profile.versionContextsLock.Unlock()
+		}
+		profile.Unlock()
+	}
+	return profiles
+}
+
 // propagateWorkloadSelectorsToProviders (thread unsafe) propagates the list of workload selectors to the Security
 // Profiles providers.
 func (m *SecurityProfileManager) propagateWorkloadSelectorsToProviders() {
@@ -543,8 +568,10 @@ func (m *SecurityProfileManager) SendStats() error {
 	m.pendingCacheLock.Lock()
 	defer m.pendingCacheLock.Unlock()
 
+	now := time.Now()
 	profilesLoadedInKernel := 0
 	profileVersions := make(map[string]int)
+	profileStabilities := make(map[Stability]int)
 	for selector, profile := range m.profiles {
 		if profile.loadedInKernel { // make sure the profile is loaded
 			profileVersions[selector.Image] = len(profile.versionContexts)
@@ -552,6 +579,19 @@ func (m *SecurityProfileManager) SendStats() error {
 				return fmt.Errorf("couldn't send metrics for [%s]: %w", profile.selector.String(), err)
 			}
 			profilesLoadedInKernel++
+
+			score, stability := profile.drift.update(now, m.config.RuntimeSecurity.SecurityProfileDriftStableScoreThreshold, m.config.RuntimeSecurity.SecurityProfileDriftStablePeriod)
+			profileStabilities[stability]++
+			t := []string{"security_profile_image_name:" + selector.Image, stability.ToTag()}
+			if err := m.statsdClient.Gauge(metrics.MetricSecurityProfileDriftScore, score, t, 1.0); err != nil {
+				return fmt.Errorf("couldn't send MetricSecurityProfileDriftScore: %w", err)
+			}
+		}
+	}
+
+	for _, stability := range AllStabilities {
+		if err := m.statsdClient.Gauge(metrics.MetricSecurityProfileStability, float64(profileStabilities[stability]), []string{stability.ToTag()}, 1.0); err != nil {
+			return fmt.Errorf("couldn't send MetricSecurityProfileStability: %w", err)
 		}
 	}
 
@@ -864,6 +904,11 @@ func (m *SecurityProfileManager) tryAutolearn(profile *SecurityProfile, ctx *Ver
 			eventState.lastAnomalyNano = event.TimestampRaw
 		}
 
+		// the nodes learned during the warmup of a workload aren't a drift of its profile
+		if nodeType == activity_tree.ProfileDrift {
+			profile.drift.addNode(event.ResolveEventTime())
+		}
+
 		// if a previous version of this profile was stable for this event type,
 		// and a new entry was added, trigger an anomaly detection
 		globalEventTypeState := profile.GetGlobalEventTypeState(event.GetEventType())
@@ -68,6 +68,7 @@ type SecurityProfile struct {
 	versionContextsLock sync.Mutex
 	versionContexts     map[string]*VersionContext
 	pathsReducer        *activity_tree.PathsReducer
+	drift               profileDrift
 
 	// Instances is the list of workload instances to which the profile should apply
 	Instances []*tags.Workload
@@ -145,6 +146,7 @@ func (p *SecurityProfile) reset() {
 	p.profileCookie = 0
 	p.versionContexts = make(map[string]*VersionContext)
 	p.Instances = nil
+	p.drift.reset()
 }
 
 // generateCookies computes random cookies for all the entries in the profile that require one
@@ -221,6 +223,8 @@ func (p *SecurityProfile) ToSecurityProfileMessage() *api.SecurityProfileMessage
 		imageTags = imageTags + key
 	}
 
+	driftScore, stability := p.drift.get(time.Now())
+
 	msg := &api.SecurityProfileMessage{
 		LoadedInKernel:          p.loadedInKernel,
 		LoadedInKernelTimestamp: p.timeResolver.ResolveMonotonicTimestamp(p.loadedNano).String(),
@@ -234,6 +238,8 @@ func (p *SecurityProfile) ToSecurityProfileMessage() *api.SecurityProfileMessage
 		},
 		ProfileGlobalState: p.getGlobalState().String(),
 		ProfileContexts:    make(map[string]*api.ProfileContextMessage),
+		DriftScore:         driftScore,
+		Stability:          stability.String(),
 	}
 	for imageTag, ctx := range p.versionContexts {
 		msgCtx := &api.ProfileContextMessage{
@@ -0,0 +1,292 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+// Package profile holds profile related files
+package profile
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	proto "github.com/DataDog/agent-payload/v5/cws/dumpsv1"
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"go.uber.org/atomic"
+
+	"github.com/DataDog/datadog-agent/pkg/security/metrics"
+	cgroupModel "github.com/DataDog/datadog-agent/pkg/security/resolvers/cgroup/model"
+	"github.com/DataDog/datadog-agent/pkg/security/seclog"
+	"github.com/DataDog/datadog-agent/pkg/util/clusteragent"
+)
+
+var (
+	// clusterAgentFetchRetryPeriod is the minimum period between two fetches of the profile of the same image
+	clusterAgentFetchRetryPeriod = 10 * time.Minute
+	// clusterAgentQueryTimeout is the timeout of the queries sent to the cluster agent
+	clusterAgentQueryTimeout = 30 * time.Second
+	// clusterAgentReuploadPeriod is the period after which an unchanged profile is uploaded again, well within the TTL
+	// of the profiles in the cluster agent, so that the profiles are restored after a restart or a leader change
+	clusterAgentReuploadPeriod = 6 * time.Hour
+)
+
+// make sure the ClusterAgentProvider implements Provider
+var _ Provider = (*ClusterAgentProvider)(nil)
+
+// clusterAgentClient is the subset of the cluster agent client used by the ClusterAgentProvider
+type clusterAgentClient interface {
+	PostSecurityProfile(ctx context.Context, data []byte) error
+	GetSecurityProfile(ctx context.Context, imageName string) ([]byte, error)
+}
+
+// ClusterAgentProvider is a ProfileProvider that shares the stable Security Profiles of this node with the other nodes
+// of the cluster through the cluster agent
+type ClusterAgentProvider struct {
+	sync.Mutex
+	uploadPeriod      time.Duration
+	getStableProfiles func() []*proto.SecurityProfile
+	getClient         func() (clusterAgentClient, error)
+	cancelFnc         func()
+	wg                sync.WaitGroup
+
+	onNewProfileCallback func(selector cgroupModel.WorkloadSelector, profile *proto.SecurityProfile)
+
+	// selectors is used to select the profiles we currently care about
+	selectors []cgroupModel.WorkloadSelector
+	// selectorsUpdated is used to notify the fetch loop that the selectors changed, without blocking the caller
+	selectorsUpdated chan struct{}
+	// lastFetch holds the last time the profile of an image was requested to the cluster agent
+	lastFetch map[string]time.Time
+	// uploaded holds the last profile uploaded for an image
+	uploaded map[string]uploadedProfile
+
+	uploadedProfiles *atomic.Uint64
+	fetchedProfiles  *atomic.Uint64
+}
+
+// uploadedProfile holds the hash of a profile uploaded to the cluster agent and the time of the upload
+type uploadedProfile struct {
+	hash       [sha256.Size]byte
+	uploadedAt time.Time
+}
+
+// NewClusterAgentProvider returns a new instance of ClusterAgentProvider
+func NewClusterAgentProvider(uploadPeriod time.Duration, getStableProfiles func() []*proto.SecurityProfile) *ClusterAgentProvider {
+	return &ClusterAgentProvider{
+		uploadPeriod:      uploadPeriod,
+		getStableProfiles: getStableProfiles,
+		getClient: func() (clusterAgentClient, error) {
+			return clusteragent.GetClusterAgentClient()
+		},
+		selectorsUpdated: make(chan struct{}, 1),
+		lastFetch:        make(map[string]time.Time),
+		uploaded:         make(map[string]uploadedProfile),
+		uploadedProfiles: atomic.NewUint64(0),
+		fetchedProfiles:  atomic.NewUint64(0),
+	}
+}
+
+// Start runs the cluster agent provider
+func (cp *ClusterAgentProvider) Start(ctx context.Context) error {
+	var childContext context.Context
+	childContext, cp.cancelFnc = context.WithCancel(ctx)
+
+	cp.wg.Add(1)
+	go func() {
+		defer cp.wg.Done()
+		cp.run(childContext)
+	}()
+	return nil
+}
+
+// Stop closes the cluster agent provider
+func (cp *ClusterAgentProvider) Stop() error {
+	if cp.cancelFnc != nil {
+		cp.cancelFnc()
+	}
+	cp.wg.Wait()
+	return nil
+}
+
+func (cp *ClusterAgentProvider) run(ctx context.Context) {
+	uploadTicker := time.NewTicker(cp.uploadPeriod)
+	defer uploadTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-cp.selectorsUpdated:
+			cp.fetchProfiles(ctx)
+		case <-uploadTicker.C:
+			cp.uploadProfiles(ctx)
+		}
+	}
+}
+
+// UpdateWorkloadSelectors updates the selectors used to query profiles
+func (cp *ClusterAgentProvider) UpdateWorkloadSelectors(selectors []cgroupModel.WorkloadSelector) {
+	cp.Lock()
+	cp.selectors = selectors
+	cp.Unlock()
+
+	// this function is called with the profile manager lock held, the profiles are fetched asynchronously to prevent
+	// a deadlock with the call to the onNewProfileCallback
+	select {
+	case cp.selectorsUpdated <- struct{}{}:
+	default:
+	}
+}
+
+// SetOnNewProfileCallback sets the onNewProfileCallback function
+func (cp *ClusterAgentProvider) SetOnNewProfileCallback(onNewProfileCallback func(selector cgroupModel.WorkloadSelector, profile *proto.SecurityProfile)) {
+	cp.onNewProfileCallback = onNewProfileCallback
+}
+
+// imagesToFetch returns the image names of the selectors whose profile wasn't requested recently
+func (cp *ClusterAgentProvider) imagesToFetch(now time.Time) []string {
+	cp.Lock()
+	defer cp.Unlock()
+
+	var images []string
+	for _, selector := range cp.selectors {
+		if selector.Image == "" {
+			continue
+		}
+		if last, ok := cp.lastFetch[selector.Image]; ok && now.Sub(last) < clusterAgentFetchRetryPeriod {
+			continue
+		}
+		cp.lastFetch[selector.Image] = now
+		images = append(images, selector.Image)
+	}
+	return images
+}
+
+func (cp *ClusterAgentProvider) fetchProfiles(ctx context.Context) {
+	if cp.onNewProfileCallback == nil {
+		return
+	}
+
+	images := cp.imagesToFetch(time.Now())
+	if len(images) == 0 {
+		return
+	}
+
+	client, err := cp.getClient()
+	if err != nil {
+		seclog.Debugf("couldn't get the cluster agent client: %v", err)
+		return
+	}
+
+	for _, image := range images {
+		profile, err := cp.fetchProfile(ctx, client, image)
+		if err != nil {
+			seclog.Debugf("couldn't fetch the security profile of %s from the cluster agent: %v", image, err)
+
+			// the cluster agent may have lost the profile uploaded by this node, upload it again on the next tick
+			cp.Lock()
+			delete(cp.uploaded, image)
+			cp.Unlock()
+			continue
+		}
+
+		selector, err := cgroupModel.NewWorkloadSelector(image, "*")
+		if err != nil {
+			continue
+		}
+
+		cp.fetchedProfiles.Inc()
+		cp.onNewProfileCallback(selector, profile)
+	}
+}
+
+func (cp *ClusterAgentProvider) fetchProfile(ctx context.Context, client clusterAgentClient, image string) (*proto.SecurityProfile, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, clusterAgentQueryTimeout)
+	defer cancel()
+
+	data, err := client.GetSecurityProfile(queryCtx, image)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &proto.SecurityProfile{}
+	if err := profile.UnmarshalVT(data); err != nil {
+		return nil, fmt.Errorf("couldn't decode profile: %w", err)
+	}
+
+	if profile.GetSelector().GetImageName() != image {
+		return nil, fmt.Errorf("unexpected image name %s", profile.GetSelector().GetImageName())
+	}
+	return profile, nil
+}
+
+func (cp *ClusterAgentProvider) uploadProfiles(ctx context.Context) {
+	profiles := cp.getStableProfiles()
+	if len(profiles) == 0 {
+		return
+	}
+
+	client, err := cp.getClient()
+	if err != nil {
+		seclog.Debugf("couldn't get the cluster agent client: %v", err)
+		return
+	}
+
+	for _, profile := range profiles {
+		image := profile.GetSelector().GetImageName()
+		if image == "" {
+			continue
+		}
+
+		data, err := profile.MarshalVT()
+		if err != nil {
+			seclog.Warnf("couldn't encode the security profile of %s: %v", image, err)
+			continue
+		}
+
+		// don't upload the profiles that didn't change since the last upload, unless it was a while ago
+		hash := sha256.Sum256(data)
+		now := time.Now()
+		cp.Lock()
+		previous, ok := cp.uploaded[image]
+		cp.Unlock()
+		if ok && previous.hash == hash && now.Sub(previous.uploadedAt) < clusterAgentReuploadPeriod {
+			continue
+		}
+
+		queryCtx, cancel := context.WithTimeout(ctx, clusterAgentQueryTimeout)
+		err = client.PostSecurityProfile(queryCtx, data)
+		cancel()
+		if err != nil {
+			seclog.Debugf("couldn't upload the security profile of %s to the cluster agent: %v", image, err)
+			continue
+		}
+
+		cp.Lock()
+		cp.uploaded[image] = uploadedProfile{hash: hash, uploadedAt: now}
+		cp.Unlock()
+		cp.uploadedProfiles.Inc()
+	}
+}
+
+// SendStats sends the metrics of the cluster agent provider
+func (cp *ClusterAgentProvider) SendStats(client statsd.ClientInterface) error {
+	if value := cp.uploadedProfiles.Swap(0); value > 0 {
+		if err := client.Count(metrics.MetricSecurityProfileClusterAgentUploaded, int64(value), []string{}, 1.0); err != nil {
+			return fmt.Errorf("couldn't send %s metric: %w", metrics.MetricSecurityProfileClusterAgentUploaded, err)
+		}
+	}
+
+	if value := cp.fetchedProfiles.Swap(0); value > 0 {
+		if err := client.Count(metrics.MetricSecurityProfileClusterAgentFetched, int64(value), []string{}, 1.0); err != nil {
+			return fmt.Errorf("couldn't send %s metric: %w", metrics.MetricSecurityProfileClusterAgentFetched, err)
+		}
+	}
+
+	return nil
+}
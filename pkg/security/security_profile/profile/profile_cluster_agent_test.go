@@ -0,0 +1,116 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+// Package profile holds profile related files
+package profile
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	proto "github.com/DataDog/agent-payload/v5/cws/dumpsv1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cgroupModel "github.com/DataDog/datadog-agent/pkg/security/resolvers/cgroup/model"
+)
+
+type fakeClusterAgentClient struct {
+	profiles map[string][]byte
+	posted   int
+}
+
+func (c *fakeClusterAgentClient) PostSecurityProfile(_ context.Context, data []byte) error {
+	profile := &proto.SecurityProfile{}
+	if err := profile.UnmarshalVT(data); err != nil {
+		return err
+	}
+	c.profiles[profile.GetSelector().GetImageName()] = data
+	c.posted++
+	return nil
+}
+
+func (c *fakeClusterAgentClient) GetSecurityProfile(_ context.Context, imageName string) ([]byte, error) {
+	data, ok := c.profiles[imageName]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
+
+func newTestProtoProfile(imageName string, versions ...string) *proto.SecurityProfile {
+	profile := &proto.SecurityProfile{
+		Selector:        &proto.ProfileSelector{ImageName: imageName, ImageTag: "*"},
+		ProfileContexts: make(map[string]*proto.ProfileContext),
+	}
+	for _, version := range versions {
+		profile.ProfileContexts[version] = &proto.ProfileContext{}
+	}
+	return profile
+}
+
+func TestClusterAgentProvider(t *testing.T) {
+	client := &fakeClusterAgentClient{profiles: make(map[string][]byte)}
+	stableProfiles := []*proto.SecurityProfile{newTestProtoProfile("nginx", "1.25")}
+
+	cp := NewClusterAgentProvider(time.Minute, func() []*proto.SecurityProfile { return stableProfiles })
+	cp.getClient = func() (clusterAgentClient, error) { return client, nil }
+
+	var received []cgroupModel.WorkloadSelector
+	cp.SetOnNewProfileCallback(func(selector cgroupModel.WorkloadSelector, profile *proto.SecurityProfile) {
+		assert.Equal(t, selector.Image, profile.GetSelector().GetImageName())
+		received = append(received, selector)
+	})
+
+	// the stable profiles are uploaded only when they change
+	cp.uploadProfiles(context.Background())
+	assert.Equal(t, 1, client.posted)
+	cp.uploadProfiles(context.Background())
+	assert.Equal(t, 1, client.posted)
+	stableProfiles = []*proto.SecurityProfile{newTestProtoProfile("nginx", "1.25", "1.26")}
+	cp.uploadProfiles(context.Background())
+	assert.Equal(t, 2, client.posted)
+
+	// the unchanged profiles are uploaded again periodically
+	uploaded := cp.uploaded["nginx"]
+	uploaded.uploadedAt = uploaded.uploadedAt.Add(-clusterAgentReuploadPeriod)
+	cp.uploaded["nginx"] = uploaded
+	cp.uploadProfiles(context.Background())
+	assert.Equal(t, 3, client.posted)
+
+	// the profiles of the new selectors are fetched
+	nginx, err := cgroupModel.NewWorkloadSelector("nginx", "1.26")
+	require.NoError(t, err)
+	redis, err := cgroupModel.NewWorkloadSelector("redis", "7.2")
+	require.NoError(t, err)
+	cp.UpdateWorkloadSelectors([]cgroupModel.WorkloadSelector{nginx, redis})
+	cp.fetchProfiles(context.Background())
+	require.Len(t, received, 1)
+	assert.Equal(t, "nginx", received[0].Image)
+	assert.Equal(t, "*", received[0].Tag)
+
+	// the profiles the cluster agent lost are uploaded again
+	delete(client.profiles, "nginx")
+	cp.lastFetch["nginx"] = time.Now().Add(-clusterAgentFetchRetryPeriod)
+	cp.fetchProfiles(context.Background())
+	cp.uploadProfiles(context.Background())
+	assert.Equal(t, 4, client.posted)
+	assert.Contains(t, client.profiles, "nginx")
+
+	// the profiles that were fetched recently aren't requested again
+	client.profiles["redis"], err = newTestProtoProfile("redis", "7.2").MarshalVT()
+	require.NoError(t, err)
+	cp.fetchProfiles(context.Background())
+	assert.Len(t, received, 1)
+
+	cp.lastFetch["redis"] = time.Now().Add(-clusterAgentFetchRetryPeriod)
+	cp.fetchProfiles(context.Background())
+	require.Len(t, received, 2)
+	assert.Equal(t, "redis", received[1].Image)
+}
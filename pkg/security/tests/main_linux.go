@@ -162,6 +162,7 @@ func preTestsHook() {
 		opts := ptracer.Opts{
 			Async:           true,
 			SeccompDisabled: disableSeccomp,
+			DNSEnabled:      true,
 			Debug:           true,
 		}
 
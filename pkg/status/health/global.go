@@ -7,6 +7,7 @@ package health
 
 import (
 	"errors"
+	"sort"
 	"time"
 )
 
@@ -64,6 +65,30 @@ func GetStartup() Status {
 	return startupOnlyCatalog.getStatus()
 }
 
+// RecordRestart records the restart of a component which isn't restarted by the supervisor but by its own parent,
+// like the log tailers restarted by their launcher, so that it is listed with the other restart events
+func RecordRestart(name string, reason string, err error) {
+	event := RestartEvent{
+		Component: name,
+		Time:      time.Now(),
+		Reason:    reason,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	readinessAndLivenessCatalog.Lock()
+	defer readinessAndLivenessCatalog.Unlock()
+	readinessAndLivenessCatalog.recordRestartEvent(event)
+}
+
+// GetRestartEvents returns the restarts of the liveness and readiness components by the supervisor, oldest first
+func GetRestartEvents() []RestartEvent {
+	events := append(readinessAndLivenessCatalog.getRestartEvents(), readinessOnlyCatalog.getRestartEvents()...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events
+}
+
 // getStatusNonBlocking allows to query the health status of the agent
 // and is guaranteed to return under 500ms.
 func getStatusNonBlocking(getStatus func() Status) (Status, error) {
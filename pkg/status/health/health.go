@@ -31,12 +31,23 @@ type component struct {
 	healthy    bool
 	// if set to true, once the check is healthy, we mark it as healthy forever and we stop checking it
 	once bool
+	// probe, if set, is called at every ping and the component is unhealthy while it returns an error
+	probe    func() error
+	probeErr error
+	// restart, if set, is called by the supervisor to restart the component once it is unhealthy
+	restart        func() error
+	restarting     bool
+	failures       int
+	lastRestart    time.Time
+	nextRestart    time.Time
+	restartBackoff time.Duration
 }
 
 type catalog struct {
 	sync.RWMutex
-	components map[*Handle]*component
-	latestRun  time.Time
+	components    map[*Handle]*component
+	latestRun     time.Time
+	restartEvents []RestartEvent
 }
 
 func newCatalog() *catalog {
@@ -86,10 +97,12 @@ func (c *catalog) run() {
 
 	for {
 		t := <-pingTicker.C
+		c.probeComponents()
 		empty := c.pingComponents(t.Add(mulDuration(pingFrequency, bufferSize)))
 		if empty {
 			break
 		}
+		c.superviseComponents(time.Now())
 	}
 	pingTicker.Stop()
 }
@@ -110,7 +123,7 @@ func (c *catalog) pingComponents(healthDeadline time.Time) bool {
 		}
 		select {
 		case component.healthChan <- healthDeadline:
-			component.healthy = true
+			component.healthy = component.probeErr == nil
 		default:
 			component.healthy = false
 		}
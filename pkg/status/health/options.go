@@ -13,3 +13,20 @@ type Option func(*component)
 func Once(c *component) {
 	c.once = true
 }
+
+// WithProbe registers a liveness probe for the component. The probe is called at every ping, in addition to the
+// health channel being read, and the component is unhealthy while it returns an error. It must return quickly since
+// it is called from the healthcheck goroutine.
+func WithProbe(probe func() error) Option {
+	return func(c *component) {
+		c.probe = probe
+	}
+}
+
+// WithRestart allows the supervisor to restart the component, without restarting the agent, once it has been
+// unhealthy for several consecutive pings. Restarts only happen once they are enabled with SetRestartsEnabled.
+func WithRestart(restart func() error) Option {
+	return func(c *component) {
+		c.restart = restart
+	}
+}
@@ -0,0 +1,146 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package health
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// restartThreshold is the number of consecutive unhealthy pings before a component is restarted
+	restartThreshold = 2
+	// minRestartBackoff is the minimum delay between two restarts of the same component, it doubles after each
+	// restart up to maxRestartBackoff
+	minRestartBackoff = time.Minute
+	maxRestartBackoff = 15 * time.Minute
+	// maxRestartEvents is the number of restart events kept by each catalog
+	maxRestartEvents = 50
+)
+
+var restartsEnabled atomic.Bool
+
+// SetRestartsEnabled enables or disables the restart of the unhealthy components registered with WithRestart
+func SetRestartsEnabled(enabled bool) {
+	restartsEnabled.Store(enabled)
+}
+
+// RestartsEnabled returns true if the unhealthy components registered with WithRestart are restarted
+func RestartsEnabled() bool {
+	return restartsEnabled.Load()
+}
+
+// RestartEvent records the restart of a component by the supervisor
+type RestartEvent struct {
+	Component string    `json:"component"`
+	Time      time.Time `json:"time"`
+	// Reason is why the component was considered unhealthy
+	Reason string `json:"reason"`
+	// Error is set when the restart failed
+	Error string `json:"error,omitempty"`
+}
+
+// probeComponents calls the liveness probes of the components. The probes are called without holding the lock so
+// that a slow probe doesn't block the registration of the other components.
+func (c *catalog) probeComponents() {
+	c.RLock()
+	probed := make([]*component, 0, len(c.components))
+	for _, component := range c.components {
+		if component.probe != nil {
+			probed = append(probed, component)
+		}
+	}
+	c.RUnlock()
+
+	for _, component := range probed {
+		err := component.probe()
+
+		c.Lock()
+		component.probeErr = err
+		c.Unlock()
+	}
+}
+
+// superviseComponents restarts the components which have been unhealthy for restartThreshold consecutive pings,
+// waiting for an exponential backoff between two restarts of the same component.
+func (c *catalog) superviseComponents(now time.Time) {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, component := range c.components {
+		if component.restart == nil || component.restarting {
+			continue
+		}
+
+		if component.healthy {
+			component.failures = 0
+			// only forget about the previous restarts once the component has been stable for a while, so that a
+			// flapping component isn't restarted in a loop
+			if now.After(component.lastRestart.Add(maxRestartBackoff)) {
+				component.restartBackoff = 0
+			}
+			continue
+		}
+
+		component.failures++
+		if !restartsEnabled.Load() || component.failures < restartThreshold || now.Before(component.nextRestart) {
+			continue
+		}
+
+		if component.restartBackoff == 0 {
+			component.restartBackoff = minRestartBackoff
+		}
+		component.restarting = true
+		component.lastRestart = now
+		component.nextRestart = now.Add(component.restartBackoff)
+		component.restartBackoff = min(2*component.restartBackoff, maxRestartBackoff)
+
+		go c.restartComponent(component, unhealthyReason(component), now)
+	}
+}
+
+// restartComponent restarts the component and records the restart event
+func (c *catalog) restartComponent(component *component, reason string, now time.Time) {
+	event := RestartEvent{
+		Component: component.name,
+		Time:      now,
+		Reason:    reason,
+	}
+	if err := component.restart(); err != nil {
+		event.Error = err.Error()
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	component.restarting = false
+	component.failures = 0
+	c.recordRestartEvent(event)
+}
+
+// recordRestartEvent keeps the last maxRestartEvents restart events. It must be called with the catalog locked.
+func (c *catalog) recordRestartEvent(event RestartEvent) {
+	c.restartEvents = append(c.restartEvents, event)
+	if len(c.restartEvents) > maxRestartEvents {
+		c.restartEvents = c.restartEvents[len(c.restartEvents)-maxRestartEvents:]
+	}
+}
+
+// getRestartEvents returns a copy of the restart events of the catalog
+func (c *catalog) getRestartEvents() []RestartEvent {
+	c.RLock()
+	defer c.RUnlock()
+
+	return append([]RestartEvent(nil), c.restartEvents...)
+}
+
+func unhealthyReason(component *component) string {
+	if component.probeErr != nil {
+		return fmt.Sprintf("liveness probe failed: %s", component.probeErr)
+	}
+	return fmt.Sprintf("unresponsive for %d consecutive health checks", component.failures)
+}
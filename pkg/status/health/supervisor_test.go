@@ -0,0 +1,147 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package health
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func enableRestarts(t *testing.T) {
+	SetRestartsEnabled(true)
+	t.Cleanup(func() { SetRestartsEnabled(false) })
+}
+
+func TestProbe(t *testing.T) {
+	cat := newCatalog()
+	var probeErr error
+	token := cat.register("test1", WithProbe(func() error { return probeErr }))
+
+	// the component reads its channel but its probe fails
+	probeErr = errors.New("too many read errors")
+	<-token.C
+	cat.probeComponents()
+	cat.pingComponents(time.Time{})
+	assert.Contains(t, cat.getStatus().Unhealthy, "test1")
+
+	probeErr = nil
+	<-token.C
+	cat.probeComponents()
+	cat.pingComponents(time.Time{})
+	assert.Contains(t, cat.getStatus().Healthy, "test1")
+}
+
+func TestSupervisorRestartsUnhealthyComponent(t *testing.T) {
+	enableRestarts(t)
+
+	cat := newCatalog()
+	var restarts atomic.Int32
+	cat.register("test1", WithRestart(func() error {
+		restarts.Add(1)
+		return nil
+	}))
+
+	now := time.Now()
+
+	// the component never reads its channel
+	cat.pingComponents(time.Time{})
+	cat.superviseComponents(now)
+	assert.Empty(t, cat.getRestartEvents())
+
+	cat.pingComponents(time.Time{})
+	cat.superviseComponents(now)
+	assert.Eventually(t, func() bool { return len(cat.getRestartEvents()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.EqualValues(t, 1, restarts.Load())
+
+	events := cat.getRestartEvents()
+	assert.Equal(t, "test1", events[0].Component)
+	assert.Equal(t, now, events[0].Time)
+	assert.Equal(t, "unresponsive for 2 consecutive health checks", events[0].Reason)
+	assert.Empty(t, events[0].Error)
+
+	// the next restart waits for the backoff
+	for i := 0; i < restartThreshold; i++ {
+		cat.pingComponents(time.Time{})
+		cat.superviseComponents(now.Add(30 * time.Second))
+	}
+	assert.EqualValues(t, 1, restarts.Load())
+
+	// and the backoff doubles after each restart
+	cat.superviseComponents(now.Add(minRestartBackoff))
+	assert.Eventually(t, func() bool { return len(cat.getRestartEvents()) == 2 }, time.Second, 10*time.Millisecond)
+	for _, component := range cat.components {
+		assert.Equal(t, 4*minRestartBackoff, component.restartBackoff)
+	}
+}
+
+func TestSupervisorRecordsFailedRestart(t *testing.T) {
+	enableRestarts(t)
+
+	cat := newCatalog()
+	token := cat.register("test1",
+		WithProbe(func() error { return errors.New("socket closed") }),
+		WithRestart(func() error { return errors.New("address already in use") }),
+	)
+
+	for i := 0; i < restartThreshold; i++ {
+		<-token.C
+		cat.probeComponents()
+		cat.pingComponents(time.Time{})
+		cat.superviseComponents(time.Now())
+	}
+
+	require.Eventually(t, func() bool { return len(cat.getRestartEvents()) == 1 }, time.Second, 10*time.Millisecond)
+	event := cat.getRestartEvents()[0]
+	assert.Equal(t, "liveness probe failed: socket closed", event.Reason)
+	assert.Equal(t, "address already in use", event.Error)
+}
+
+func TestSupervisorDisabled(t *testing.T) {
+	cat := newCatalog()
+	var restarts atomic.Int32
+	cat.register("test1", WithRestart(func() error {
+		restarts.Add(1)
+		return nil
+	}))
+
+	for i := 0; i < 2*restartThreshold; i++ {
+		cat.pingComponents(time.Time{})
+		cat.superviseComponents(time.Now())
+	}
+
+	assert.Zero(t, restarts.Load())
+	assert.Empty(t, cat.getRestartEvents())
+}
+
+func TestRestartEventsAreCapped(t *testing.T) {
+	cat := newCatalog()
+	component := &component{name: "test1", restart: func() error { return nil }}
+
+	for i := 0; i < maxRestartEvents+10; i++ {
+		cat.restartComponent(component, "unresponsive", time.Unix(int64(i), 0))
+	}
+
+	events := cat.getRestartEvents()
+	require.Len(t, events, maxRestartEvents)
+	assert.Equal(t, time.Unix(10, 0), events[0].Time)
+}
+
+func TestRecordRestart(t *testing.T) {
+	RecordRestart("logs-tailer:/var/log/app.log", "the tailer stopped reading the file", nil)
+	RecordRestart("logs-tailer:/var/log/db.log", "the tailer stopped reading the file", errors.New("permission denied"))
+
+	events := GetRestartEvents()
+	require.Len(t, events, 2)
+	assert.Equal(t, "logs-tailer:/var/log/app.log", events[0].Component)
+	assert.Empty(t, events[0].Error)
+	assert.Equal(t, "logs-tailer:/var/log/db.log", events[1].Component)
+	assert.Equal(t, "permission denied", events[1].Error)
+}
@@ -0,0 +1,19 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package health
+
+import "time"
+
+// SuperviseLivenessNow pings and supervises the liveness components without waiting for the ping ticker, so that the
+// restart of the unhealthy components can be tested from other packages
+func SuperviseLivenessNow() {
+	c := readinessAndLivenessCatalog
+	c.probeComponents()
+	c.pingComponents(time.Now().Add(mulDuration(pingFrequency, bufferSize)))
+	c.superviseComponents(time.Now())
+}
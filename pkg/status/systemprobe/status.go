@@ -20,8 +20,7 @@ import (
 
 // GetStatus returns the expvar stats of the system probe
 func GetStatus(stats map[string]interface{}, socketPath string) {
-	client := sysprobeclient.Get(socketPath)
-	systemProbeDetails, err := getStats(client)
+	systemProbeDetails, err := GetStats(socketPath)
 	if err != nil {
 		stats["systemProbeStats"] = map[string]interface{}{
 			"Errors": fmt.Sprintf("issue querying stats from system probe: %v", err),
@@ -31,6 +30,12 @@ func GetStatus(stats map[string]interface{}, socketPath string) {
 	stats["systemProbeStats"] = systemProbeDetails
 }
 
+// GetStats returns the stats of the system probe, namespaced by module. The
+// modules that failed to start only report an "Error".
+func GetStats(socketPath string) (map[string]interface{}, error) {
+	return getStats(sysprobeclient.Get(socketPath))
+}
+
 func getStats(client *http.Client) (map[string]interface{}, error) {
 	url := sysprobeclient.DebugURL("/stats")
 	req, err := http.NewRequest("GET", url, nil)
@@ -94,6 +94,8 @@ type Agent struct {
 	RareSampler           *sampler.RareSampler
 	NoPrioritySampler     *sampler.NoPrioritySampler
 	ProbabilisticSampler  *sampler.ProbabilisticSampler
+	QuotaEnforcer         *sampler.QuotaEnforcer
+	SamplerState          *sampler.StatePersister
 	SamplerMetrics        *sampler.Metrics
 	EventProcessor        *event.Processor
 	TraceWriter           TraceWriter
@@ -120,6 +122,9 @@ type Agent struct {
 	// In takes incoming payloads to be processed by the agent.
 	In chan *api.Payload
 
+	// workersWG waits for the workers processing the payloads of In
+	workersWG sync.WaitGroup
+
 	// config
 	conf *config.AgentConfig
 
@@ -127,6 +132,9 @@ type Agent struct {
 	ctx context.Context
 
 	firstSpanMap sync.Map
+
+	// errorAggregator fingerprints the error spans, it's nil unless error fingerprints are enabled
+	errorAggregator *errorAggregator
 }
 
 // SpanModifier is an interface that allows to modify spans while they are
@@ -157,6 +165,7 @@ func NewAgent(ctx context.Context, conf *config.AgentConfig, telemetryCollector
 		RareSampler:           sampler.NewRareSampler(conf),
 		NoPrioritySampler:     sampler.NewNoPrioritySampler(conf),
 		ProbabilisticSampler:  sampler.NewProbabilisticSampler(conf),
+		QuotaEnforcer:         sampler.NewQuotaEnforcer(conf),
 		SamplerMetrics:        sampler.NewMetrics(statsd),
 		EventProcessor:        newEventProcessor(conf, statsd),
 		StatsWriter:           statsWriter,
@@ -168,9 +177,14 @@ func NewAgent(ctx context.Context, conf *config.AgentConfig, telemetryCollector
 		Statsd:                statsd,
 		Timing:                timing,
 	}
-	agnt.SamplerMetrics.Add(agnt.PrioritySampler, agnt.ErrorsSampler, agnt.NoPrioritySampler, agnt.RareSampler)
+	agnt.SamplerMetrics.Add(agnt.PrioritySampler, agnt.ErrorsSampler, agnt.NoPrioritySampler, agnt.RareSampler, agnt.QuotaEnforcer)
+	agnt.SamplerState = sampler.NewStatePersister(conf, agnt.PrioritySampler, agnt.RareSampler)
+	if conf.ErrorFingerprintsEnabled {
+		agnt.errorAggregator = newErrorAggregator(conf.ErrorFingerprintStackFrames)
+	}
 	agnt.Receiver = api.NewHTTPReceiver(conf, dynConf, in, agnt, telemetryCollector, statsd, timing)
 	agnt.OTLPReceiver = api.NewOTLPReceiver(in, conf, statsd, timing)
+	agnt.OTLPReceiver.SetRateByService(&dynConf.RateByService)
 	agnt.RemoteConfigHandler = remoteconfighandler.New(conf, agnt.PrioritySampler, agnt.RareSampler, agnt.ErrorsSampler)
 	agnt.TraceWriter = writer.NewTraceWriter(conf, agnt.PrioritySampler, agnt.ErrorsSampler, agnt.RareSampler, telemetryCollector, statsd, timing, comp)
 	return agnt
@@ -181,6 +195,7 @@ func (a *Agent) Run() {
 	a.Timing.Start()
 	defer a.Timing.Stop()
 	for _, starter := range []interface{ Start() }{
+		a.SamplerState, // restore the samplers before receiving traces
 		a.Receiver,
 		a.Concentrator,
 		a.ClientStatsAggregator,
@@ -206,7 +221,11 @@ func (a *Agent) Run() {
 
 	log.Infof("Processing Pipeline configured with %d workers", workers)
 	for i := 0; i < workers; i++ {
-		go a.work()
+		a.workersWG.Add(1)
+		go func() {
+			defer a.workersWG.Done()
+			a.work()
+		}()
 	}
 
 	a.loop()
@@ -260,6 +279,10 @@ func (a *Agent) loop() {
 	a.OTLPReceiver.Stop() // Stop OTLPReceiver before Receiver to avoid sending to closed channel
 	if err := a.Receiver.Stop(); err != nil {
 		log.Error(err)
+	} else if a.conf.ReceiverEnabled && a.conf.ReceiverPort != 0 {
+		// The receiver was drained and closed In, let the workers process the
+		// payloads left before the writers are flushed.
+		a.workersWG.Wait()
 	}
 	for _, stopper := range []interface{ Stop() }{
 		a.Concentrator,
@@ -267,6 +290,7 @@ func (a *Agent) loop() {
 		a.TraceWriter,
 		a.StatsWriter,
 		a.SamplerMetrics,
+		a.SamplerState,
 		a.EventProcessor,
 		a.obfuscator,
 		a.DebugServer,
@@ -378,6 +402,7 @@ func (a *Agent) Process(p *api.Payload) {
 			}
 		}
 		a.Replacer.Replace(chunk.Spans)
+		a.errorAggregator.fingerprint(chunk.Spans)
 
 		a.setRootSpanTags(root)
 		if !p.ClientComputedTopLevel {
@@ -393,7 +418,9 @@ func (a *Agent) Process(p *api.Payload) {
 			statsInput.Traces = append(statsInput.Traces, *pt.Clone())
 		}
 
+		spans := pt.TraceChunk.Spans
 		keep, numEvents := a.sample(now, ts, pt)
+		a.errorAggregator.record(spans, pt.TraceChunk.Spans)
 		if !keep && len(pt.TraceChunk.Spans) == 0 {
 			// The entire trace was dropped and no spans were kept.
 			p.RemoveChunk(i)
@@ -596,6 +623,12 @@ func (a *Agent) sample(now time.Time, ts *info.TagStats, pt *traceutil.Processed
 		}
 	}
 
+	// The ingestion quotas apply to whatever is about to be sent.
+	if len(pt.TraceChunk.Spans) > 0 && !a.QuotaEnforcer.Apply(now, pt) {
+		pt.TraceChunk.Spans = nil
+		return false, 0
+	}
+
 	return keep, len(events)
 }
 
@@ -0,0 +1,200 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package agent
+
+import (
+	"container/list"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/traceutil"
+)
+
+const (
+	// tagErrorFingerprint is the meta key of the fingerprint of an error span.
+	tagErrorFingerprint = "_dd.error.fingerprint"
+	// tagErrorOccurrences is the metric key of the number of occurrences of the fingerprint
+	// an error span stands for: itself, plus the ones dropped by sampling since the last one kept.
+	tagErrorOccurrences = "_dd.error.occurrences"
+
+	// maxErrorFingerprints is the maximum number of fingerprints tracked by the aggregator.
+	maxErrorFingerprints = 10000
+)
+
+// errorFingerprint returns the fingerprint of an error span, computed from its service, the error
+// type, the normalized error message and the normalized head of the stack trace. It returns false
+// if the span isn't an error or doesn't carry any information about the error.
+func errorFingerprint(s *pb.Span, stackFrames int) (string, bool) {
+	if s.Error == 0 {
+		return "", false
+	}
+	errType := s.Meta["error.type"]
+	msg, ok := s.Meta["error.message"]
+	if !ok {
+		msg = s.Meta["error.msg"]
+	}
+	stack := stackHead(s.Meta["error.stack"], stackFrames)
+	if errType == "" && msg == "" && stack == "" {
+		return "", false
+	}
+
+	h := fnv.New64a()
+	for _, part := range []string{s.Service, errType, normalizeErrorText(msg), stack} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return strconv.FormatUint(h.Sum64(), 16), true
+}
+
+// stackHead returns the first frames of a stack trace, normalized so that it doesn't depend on
+// line numbers or addresses.
+func stackHead(stack string, frames int) string {
+	var b strings.Builder
+	for _, line := range strings.Split(stack, "\n") {
+		if frames <= 0 {
+			break
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		b.WriteString(normalizeErrorText(line))
+		b.WriteByte('\n')
+		frames--
+	}
+	return b.String()
+}
+
+// normalizeErrorText replaces the parts of an error text which vary between occurrences of the
+// same error, the quoted strings and the tokens containing digits (ids, numbers, addresses,
+// uuids...), with a "?".
+func normalizeErrorText(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for i := 0; i < len(text); {
+		c := text[i]
+		// an apostrophe within a word doesn't start a quoted string
+		if (c == '"' || c == '\'' || c == '`') && (i == 0 || !isErrorTokenChar(text[i-1])) {
+			if end := strings.IndexByte(text[i+1:], c); end >= 0 {
+				b.WriteByte('?')
+				i += end + 2
+				continue
+			}
+		}
+		if !isErrorTokenChar(c) {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+		j, hasDigit := i, false
+		for ; j < len(text) && isErrorTokenChar(text[j]); j++ {
+			hasDigit = hasDigit || (text[j] >= '0' && text[j] <= '9')
+		}
+		if hasDigit {
+			b.WriteByte('?')
+		} else {
+			b.WriteString(text[i:j])
+		}
+		i = j
+	}
+	return b.String()
+}
+
+func isErrorTokenChar(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '-' || c == '_'
+}
+
+// errorAggregator counts the occurrences of the error fingerprints dropped by sampling, so that
+// the next error span kept with the same fingerprint reports them. This allows deduplicating the
+// errors while still knowing how often they happen, even when their spans are heavily sampled.
+// The least recently seen fingerprints are evicted when more than maxEntries are tracked.
+type errorAggregator struct {
+	mu          sync.Mutex
+	stackFrames int
+	maxEntries  int
+	pending     map[string]*list.Element
+	ll          *list.List
+}
+
+type pendingErrors struct {
+	fingerprint string
+	count       int64
+}
+
+func newErrorAggregator(stackFrames int) *errorAggregator {
+	return &errorAggregator{
+		stackFrames: stackFrames,
+		maxEntries:  maxErrorFingerprints,
+		pending:     make(map[string]*list.Element),
+		ll:          list.New(),
+	}
+}
+
+// fingerprint sets the fingerprint tag on the error spans of a chunk.
+func (e *errorAggregator) fingerprint(spans []*pb.Span) {
+	if e == nil {
+		return
+	}
+	for _, s := range spans {
+		if fp, ok := errorFingerprint(s, e.stackFrames); ok {
+			traceutil.SetMeta(s, tagErrorFingerprint, fp)
+		}
+	}
+}
+
+// record counts the fingerprinted spans which were dropped, and sets on the ones kept the number
+// of occurrences they stand for.
+func (e *errorAggregator) record(spans, kept []*pb.Span) {
+	if e == nil {
+		return
+	}
+	keptSet := make(map[*pb.Span]struct{}, len(kept))
+	for _, s := range kept {
+		keptSet[s] = struct{}{}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, s := range spans {
+		fp, ok := s.Meta[tagErrorFingerprint]
+		if !ok {
+			continue
+		}
+		if _, ok := keptSet[s]; ok {
+			traceutil.SetMetric(s, tagErrorOccurrences, float64(e.take(fp)+1))
+		} else {
+			e.add(fp)
+		}
+	}
+}
+
+// add counts a dropped occurrence of a fingerprint.
+func (e *errorAggregator) add(fp string) {
+	if el, ok := e.pending[fp]; ok {
+		el.Value.(*pendingErrors).count++
+		e.ll.MoveToFront(el)
+		return
+	}
+	e.pending[fp] = e.ll.PushFront(&pendingErrors{fingerprint: fp, count: 1})
+	if e.ll.Len() > e.maxEntries {
+		del := e.ll.Remove(e.ll.Back()).(*pendingErrors)
+		delete(e.pending, del.fingerprint)
+	}
+}
+
+// take returns and resets the dropped occurrences of a fingerprint.
+func (e *errorAggregator) take(fp string) int64 {
+	el, ok := e.pending[fp]
+	if !ok {
+		return 0
+	}
+	e.ll.Remove(el)
+	delete(e.pending, fp)
+	return el.Value.(*pendingErrors).count
+}
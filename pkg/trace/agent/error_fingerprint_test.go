@@ -0,0 +1,142 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/api"
+	"github.com/DataDog/datadog-agent/pkg/trace/config"
+	"github.com/DataDog/datadog-agent/pkg/trace/info"
+	"github.com/DataDog/datadog-agent/pkg/trace/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/trace/testutil"
+)
+
+func errorSpan(service, errType, msg, stack string) *pb.Span {
+	return &pb.Span{
+		Service: service,
+		Error:   1,
+		Meta:    map[string]string{"error.type": errType, "error.message": msg, "error.stack": stack},
+	}
+}
+
+func TestNormalizeErrorText(t *testing.T) {
+	for in, out := range map[string]string{
+		"connection refused":                          "connection refused",
+		"user 1234 not found":                         "user ? not found",
+		`can't find key "foo" in 'bar'`:               "can't find key ? in ?",
+		"request 3f2a9c1e-7b4d-4e8a-9c6b-2d1f0e5a7c3b": "request ?",
+		"panic at 0xc000123abc":                       "panic at ?",
+		`unterminated "quote`:                         `unterminated "quote`,
+		"main.go:42 main.handler()":                   "main.go:? main.handler()",
+	} {
+		assert.Equal(t, out, normalizeErrorText(in), in)
+	}
+}
+
+func TestErrorFingerprint(t *testing.T) {
+	stack := "at com.example.Foo.bar(Foo.java:12)\n  at com.example.Foo.baz(Foo.java:34)\n\n  at com.example.Main.main(Main.java:56)"
+	fp, ok := errorFingerprint(errorSpan("web", "NullPointerException", "user 12 is null", stack), 2)
+	require.True(t, ok)
+
+	// the variable parts of the message and the frames beyond the stack head are ignored
+	other, ok := errorFingerprint(errorSpan("web", "NullPointerException", "user 34 is null", stack+"\n  at Other.java:78"), 2)
+	require.True(t, ok)
+	assert.Equal(t, fp, other)
+	other, _ = errorFingerprint(errorSpan("web", "NullPointerException", "user 12 is null", "at com.example.Foo.bar(Foo.java:13)\n  at com.example.Foo.baz(Foo.java:34)\n  at Other.java:78"), 2)
+	assert.Equal(t, fp, other)
+
+	for _, s := range []*pb.Span{
+		errorSpan("api", "NullPointerException", "user 12 is null", stack),
+		errorSpan("web", "IllegalStateException", "user 12 is null", stack),
+		errorSpan("web", "NullPointerException", "user is missing", stack),
+		errorSpan("web", "NullPointerException", "user 12 is null", "at com.example.Foo.qux(Foo.java:12)"),
+	} {
+		other, ok := errorFingerprint(s, 2)
+		assert.True(t, ok)
+		assert.NotEqual(t, fp, other)
+	}
+
+	_, ok = errorFingerprint(&pb.Span{Service: "web", Meta: map[string]string{"error.message": "boom"}}, 2)
+	assert.False(t, ok, "not an error")
+	_, ok = errorFingerprint(&pb.Span{Service: "web", Error: 1}, 2)
+	assert.False(t, ok, "no error information")
+}
+
+func TestErrorAggregator(t *testing.T) {
+	e := newErrorAggregator(5)
+	e.maxEntries = 2
+	newSpans := func() []*pb.Span {
+		spans := []*pb.Span{errorSpan("web", "A", "a", ""), errorSpan("web", "B", "b", ""), {Service: "web"}}
+		e.fingerprint(spans)
+		return spans
+	}
+
+	// the errors are dropped twice, then kept
+	e.record(newSpans(), nil)
+	e.record(newSpans(), nil)
+	spans := newSpans()
+	assert.Contains(t, spans[0].Meta, tagErrorFingerprint)
+	assert.NotContains(t, spans[2].Meta, tagErrorFingerprint)
+	e.record(spans, spans[:1])
+	assert.Equal(t, 3.0, spans[0].Metrics[tagErrorOccurrences])
+	assert.NotContains(t, spans[1].Metrics, tagErrorOccurrences)
+	assert.NotContains(t, spans[2].Metrics, tagErrorOccurrences)
+
+	// the count of the fingerprint starts over once reported
+	spans = newSpans()
+	e.record(spans, spans)
+	assert.Equal(t, 1.0, spans[0].Metrics[tagErrorOccurrences])
+	assert.Equal(t, 4.0, spans[1].Metrics[tagErrorOccurrences])
+
+	// the least recently seen fingerprints are evicted
+	for _, msg := range []string{"c", "d", "e"} {
+		e.record([]*pb.Span{{Meta: map[string]string{tagErrorFingerprint: msg}}}, nil)
+	}
+	assert.Equal(t, 2, e.ll.Len())
+	assert.Equal(t, int64(0), e.take("c"))
+	assert.Equal(t, int64(1), e.take("e"))
+
+	var disabled *errorAggregator
+	disabled.fingerprint(spans)
+	disabled.record(spans, nil)
+}
+
+func TestProcessErrorFingerprints(t *testing.T) {
+	cfg := config.New()
+	cfg.Endpoints[0].APIKey = "test"
+	cfg.ErrorFingerprintsEnabled = true
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	agnt := NewTestAgent(ctx, cfg, telemetry.NewNoopCollector())
+
+	span := testutil.RandomSpan()
+	span.Error = 1
+	span.Meta["error.type"] = "TimeoutError"
+	span.Meta["error.message"] = "request timed out after 30s"
+	fp, ok := errorFingerprint(span, cfg.ErrorFingerprintStackFrames)
+	require.True(t, ok)
+	// two occurrences were dropped before
+	agnt.errorAggregator.add(fp)
+	agnt.errorAggregator.add(fp)
+
+	chunk := testutil.TraceChunkWithSpan(span)
+	chunk.Priority = 2
+	agnt.Process(&api.Payload{
+		TracerPayload: testutil.TracerPayloadWithChunk(chunk),
+		Source:        agnt.Receiver.Stats.GetTagStats(info.Tags{}),
+	})
+	payloads := agnt.TraceWriter.(*mockTraceWriter).payloads
+	require.NotEmpty(t, payloads, "no payloads were written")
+	kept := payloads[0].TracerPayload.Chunks[0].Spans[0]
+	assert.Equal(t, fp, kept.Meta[tagErrorFingerprint])
+	assert.Equal(t, 3.0, kept.Metrics[tagErrorOccurrences])
+}
@@ -105,6 +105,10 @@ type HTTPReceiver struct {
 	wg   sync.WaitGroup // waits for all requests to be processed
 	exit chan struct{}
 
+	// drainMu guards draining, which is set when the receiver stops
+	drainMu  sync.RWMutex
+	draining bool
+
 	// recvsem is a semaphore that controls the number goroutines that can
 	// be simultaneously deserializing incoming payloads.
 	// It is important to control this in order to prevent decoding incoming
@@ -119,6 +123,11 @@ type HTTPReceiver struct {
 	timing   timing.Reporter
 	info     *watchdog.CurrentInfo
 	Handlers map[string]http.Handler
+
+	// ListenerStatsd receives the connection metrics of the listeners, so that
+	// they can be sampled or dropped independently. It defaults to the client
+	// of the receiver, and must be set before Start.
+	ListenerStatsd statsd.ClientInterface
 }
 
 // NewHTTPReceiver returns a pointer to a new HTTPReceiver
@@ -174,6 +183,8 @@ func NewHTTPReceiver(
 		timing:   timing,
 		info:     watchdog.NewCurrentInfo(),
 		Handlers: make(map[string]http.Handler),
+
+		ListenerStatsd: statsd,
 	}
 }
 
@@ -202,7 +213,7 @@ func (r *HTTPReceiver) buildMux() *http.ServeMux {
 		if e.TimeoutOverride != nil {
 			timeout = e.TimeoutOverride(r.conf)
 		}
-		h := replyWithVersion(hash, r.conf.AgentVersion, timeoutMiddleware(timeout, e.Handler(r)))
+		h := replyWithVersion(hash, r.conf.AgentVersion, r.drainMiddleware(e, timeoutMiddleware(timeout, e.Handler(r))))
 		r.Handlers[e.Pattern] = h
 		mux.Handle(e.Pattern, h)
 	}
@@ -307,7 +318,7 @@ func (r *HTTPReceiver) Start() {
 		pipepath := `\\.\pipe\` + path
 		bufferSize := r.conf.PipeBufferSize
 		secdec := r.conf.PipeSecurityDescriptor
-		ln, err := listenPipe(pipepath, secdec, bufferSize, r.conf.MaxConnections, r.statsd)
+		ln, err := listenPipe(pipepath, secdec, bufferSize, r.conf.MaxConnections, r.ListenerStatsd)
 		if err != nil {
 			r.telemetryCollector.SendStartupError(telemetry.CantStartWindowsPipeServer, err)
 			killProcess("Error creating %q named pipe: %v", pipepath, err)
@@ -347,7 +358,7 @@ func (r *HTTPReceiver) listenUnix(path string) (net.Listener, error) {
 	if err := os.Chmod(path, 0o722); err != nil {
 		return nil, fmt.Errorf("error setting socket permissions: %v", err)
 	}
-	return NewMeasuredListener(ln, "uds_connections", r.conf.MaxConnections, r.statsd), err
+	return NewMeasuredListener(ln, "uds_connections", r.conf.MaxConnections, r.ListenerStatsd), err
 }
 
 // listenTCP creates a new net.Listener on the provided TCP address.
@@ -357,21 +368,22 @@ func (r *HTTPReceiver) listenTCP(addr string) (net.Listener, error) {
 		return nil, err
 	}
 	if climit := r.conf.ConnectionLimit; climit > 0 {
-		ln, err := newRateLimitedListener(tcpln, climit, r.statsd)
+		ln, err := newRateLimitedListener(tcpln, climit, r.ListenerStatsd)
 		go func() {
 			defer watchdog.LogOnPanic(r.statsd)
 			ln.Refresh(climit)
 		}()
 		return ln, err
 	}
-	return NewMeasuredListener(tcpln, "tcp_connections", r.conf.MaxConnections, r.statsd), err
+	return NewMeasuredListener(tcpln, "tcp_connections", r.conf.MaxConnections, r.ListenerStatsd), err
 }
 
-// Stop stops the receiver and shuts down the HTTP server.
+// Stop drains the receiver and shuts down the HTTP server.
 func (r *HTTPReceiver) Stop() error {
 	if !r.conf.ReceiverEnabled || r.conf.ReceiverPort == 0 {
 		return nil
 	}
+	r.drain()
 	r.exit <- struct{}{}
 	<-r.exit
 
@@ -33,9 +33,13 @@ const (
 )
 
 // debuggerLogsProxyHandler returns an http.Handler proxying Dynamic Instrumentation dynamic logs
-// to the logs intake.
+// to the logs intake. Duplicated and rate limited probe results are dropped before being proxied.
 func (r *HTTPReceiver) debuggerLogsProxyHandler() http.Handler {
-	return r.debuggerProxyHandler(logsIntakeURLTemplate, r.conf.DebuggerProxy)
+	proxy := r.debuggerProxyHandler(logsIntakeURLTemplate, r.conf.DebuggerProxy)
+	if f := newDebuggerFilter(r.conf.DebuggerProxy.DedupWindow, r.conf.DebuggerProxy.ProbeRateLimit, r.conf.MaxRequestBytes, r.statsd); f != nil {
+		return f.handler(proxy)
+	}
+	return proxy
 }
 
 // debuggerDiagnosticsProxyHandler returns an http.Handler proxying Dynamic Instrumentation diagnostic messages
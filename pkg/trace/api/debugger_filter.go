@@ -0,0 +1,234 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"golang.org/x/time/rate"
+
+	"github.com/DataDog/datadog-agent/pkg/trace/api/apiutil"
+	"github.com/DataDog/datadog-agent/pkg/trace/log"
+)
+
+const (
+	// debuggerFilterGCInterval is the minimum interval between two purges of the deduplication and rate limiting state.
+	debuggerFilterGCInterval = time.Minute
+	// debuggerLimiterIdleTimeout is the duration after which the rate limiter of a probe that didn't send any result
+	// is forgotten.
+	debuggerLimiterIdleTimeout = 5 * time.Minute
+)
+
+// debuggerProbeResult holds the fields of a probe result (snapshot or dynamic log) used to deduplicate and rate limit it.
+type debuggerProbeResult struct {
+	Service  string `json:"service"`
+	Message  string `json:"message"`
+	Debugger struct {
+		Snapshot struct {
+			Probe struct {
+				ID string `json:"id"`
+			} `json:"probe"`
+		} `json:"snapshot"`
+	} `json:"debugger"`
+}
+
+type probeLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// debuggerFilter drops the probe results that are identical to a result already sent within the deduplication window,
+// and the results of a probe exceeding its rate limit, so that a probe placed in a hot loop doesn't saturate the uplink.
+type debuggerFilter struct {
+	dedupWindow time.Duration
+	probeRate   float64
+	// maxBodySize is the maximum size of the request bodies read to be filtered, 0 means no limit
+	maxBodySize int64
+	statsd      statsd.ClientInterface
+
+	mu       sync.Mutex
+	seen     map[uint64]time.Time
+	limiters map[string]*probeLimiter
+	lastGC   time.Time
+	now      func() time.Time
+}
+
+// newDebuggerFilter returns a new debuggerFilter, or nil if both deduplication and rate limiting are disabled.
+func newDebuggerFilter(dedupWindow time.Duration, probeRate float64, maxBodySize int64, statsd statsd.ClientInterface) *debuggerFilter {
+	if dedupWindow <= 0 && probeRate <= 0 {
+		return nil
+	}
+	return &debuggerFilter{
+		dedupWindow: dedupWindow,
+		probeRate:   probeRate,
+		maxBodySize: maxBodySize,
+		statsd:      statsd,
+		seen:        make(map[uint64]time.Time),
+		limiters:    make(map[string]*probeLimiter),
+		now:         time.Now,
+	}
+}
+
+// handler returns an http.Handler filtering the probe results of the request body before passing it to next.
+// Bodies which aren't an uncompressed JSON array of probe results are passed through untouched.
+func (f *debuggerFilter) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Body == nil || req.Body == http.NoBody || !isIdentityEncoding(req.Header.Get("Content-Encoding")) {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		reader := req.Body
+		if f.maxBodySize > 0 {
+			reader = apiutil.NewLimitedReader(req.Body, f.maxBodySize)
+		}
+		body, err := io.ReadAll(reader)
+		req.Body.Close()
+		if err == apiutil.ErrLimitedReaderLimitReached {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err != nil {
+			http.Error(w, "error reading request body", http.StatusBadRequest)
+			return
+		}
+
+		filtered, kept, dropped := f.filter(body)
+		if kept == 0 && dropped > 0 {
+			// nothing left to proxy, act as if the intake accepted the payload
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(filtered))
+		req.ContentLength = int64(len(filtered))
+		req.Header.Set("Content-Length", strconv.Itoa(len(filtered)))
+		next.ServeHTTP(w, req)
+	})
+}
+
+// filter returns the body without the duplicated and rate limited probe results, along with the number of results
+// kept and dropped.
+func (f *debuggerFilter) filter(body []byte) ([]byte, int, int) {
+	var results []json.RawMessage
+	if trimmed := bytes.TrimSpace(body); len(trimmed) == 0 || trimmed[0] != '[' || json.Unmarshal(trimmed, &results) != nil {
+		return body, 0, 0
+	}
+
+	f.mu.Lock()
+	now := f.now()
+	f.gcLocked(now)
+
+	kept := results[:0]
+	var duplicates, rateLimited int64
+	for _, raw := range results {
+		var result debuggerProbeResult
+		if err := json.Unmarshal(raw, &result); err != nil || result.Debugger.Snapshot.Probe.ID == "" {
+			// not a probe result, keep it
+			kept = append(kept, raw)
+			continue
+		}
+
+		switch {
+		case f.isDuplicateLocked(now, &result):
+			duplicates++
+		case !f.allowLocked(now, result.Debugger.Snapshot.Probe.ID):
+			rateLimited++
+		default:
+			kept = append(kept, raw)
+		}
+	}
+	f.mu.Unlock()
+
+	if duplicates > 0 {
+		_ = f.statsd.Count("datadog.trace_agent.debugger.dropped", duplicates, []string{"reason:duplicate"}, 1)
+	}
+	if rateLimited > 0 {
+		_ = f.statsd.Count("datadog.trace_agent.debugger.dropped", rateLimited, []string{"reason:rate_limited"}, 1)
+	}
+
+	dropped := int(duplicates + rateLimited)
+	if dropped == 0 {
+		return body, len(kept), 0
+	}
+
+	filtered, err := json.Marshal(kept)
+	if err != nil {
+		log.Debugf("Unable to encode filtered debugger payload, proxying it unfiltered: %v", err)
+		return body, len(results), 0
+	}
+	return filtered, len(kept), dropped
+}
+
+// isDuplicateLocked returns whether an identical result of the same probe was already seen within the deduplication
+// window, and records the result otherwise. The caller must hold the lock.
+func (f *debuggerFilter) isDuplicateLocked(now time.Time, result *debuggerProbeResult) bool {
+	if f.dedupWindow <= 0 {
+		return false
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(result.Debugger.Snapshot.Probe.ID))
+	h.Write([]byte{0})
+	h.Write([]byte(result.Service))
+	h.Write([]byte{0})
+	h.Write([]byte(result.Message))
+	key := h.Sum64()
+
+	if expiry, found := f.seen[key]; found && now.Before(expiry) {
+		return true
+	}
+	f.seen[key] = now.Add(f.dedupWindow)
+	return false
+}
+
+// allowLocked returns whether the probe didn't exceed its rate limit. The caller must hold the lock.
+func (f *debuggerFilter) allowLocked(now time.Time, probeID string) bool {
+	if f.probeRate <= 0 {
+		return true
+	}
+
+	l, found := f.limiters[probeID]
+	if !found {
+		burst := int(math.Max(1, math.Ceil(f.probeRate)))
+		l = &probeLimiter{limiter: rate.NewLimiter(rate.Limit(f.probeRate), burst)}
+		f.limiters[probeID] = l
+	}
+	l.lastSeen = now
+	return l.limiter.AllowN(now, 1)
+}
+
+// gcLocked forgets the expired deduplication entries and the idle rate limiters. The caller must hold the lock.
+func (f *debuggerFilter) gcLocked(now time.Time) {
+	if now.Sub(f.lastGC) < debuggerFilterGCInterval {
+		return
+	}
+	f.lastGC = now
+
+	for key, expiry := range f.seen {
+		if !now.Before(expiry) {
+			delete(f.seen, key)
+		}
+	}
+	for probeID, l := range f.limiters {
+		if now.Sub(l.lastSeen) > debuggerLimiterIdleTimeout {
+			delete(f.limiters, probeID)
+		}
+	}
+}
+
+func isIdentityEncoding(encoding string) bool {
+	return encoding == "" || encoding == "identity"
+}
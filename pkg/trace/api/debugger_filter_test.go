@@ -0,0 +1,156 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/trace/teststatsd"
+)
+
+func probeResult(probeID, message string) string {
+	return fmt.Sprintf(`{"service":"svc","message":%q,"debugger":{"snapshot":{"probe":{"id":%q}}}}`, message, probeID)
+}
+
+func TestDebuggerFilterDisabled(t *testing.T) {
+	assert.Nil(t, newDebuggerFilter(0, 0, 0, &teststatsd.Client{}))
+}
+
+func TestDebuggerFilterDedup(t *testing.T) {
+	stats := &teststatsd.Client{}
+	f := newDebuggerFilter(time.Second, 0, 0, stats)
+	now := time.Now()
+	f.now = func() time.Time { return now }
+
+	body := "[" + strings.Join([]string{
+		probeResult("p1", "hello"),
+		probeResult("p1", "hello"),
+		probeResult("p1", "world"),
+		probeResult("p2", "hello"),
+	}, ",") + "]"
+
+	filtered, kept, dropped := f.filter([]byte(body))
+	assert.Equal(t, 3, kept)
+	assert.Equal(t, 1, dropped)
+
+	var results []json.RawMessage
+	require.NoError(t, json.Unmarshal(filtered, &results))
+	assert.Len(t, results, 3)
+
+	// still within the window
+	_, kept, dropped = f.filter([]byte("[" + probeResult("p1", "hello") + "]"))
+	assert.Equal(t, 0, kept)
+	assert.Equal(t, 1, dropped)
+
+	// the window expired
+	now = now.Add(time.Second)
+	_, kept, dropped = f.filter([]byte("[" + probeResult("p1", "hello") + "]"))
+	assert.Equal(t, 1, kept)
+	assert.Equal(t, 0, dropped)
+
+	summary := stats.GetCountSummaries()["datadog.trace_agent.debugger.dropped"]
+	require.NotNil(t, summary)
+	assert.EqualValues(t, 2, summary.Sum)
+}
+
+func TestDebuggerFilterRateLimit(t *testing.T) {
+	stats := &teststatsd.Client{}
+	f := newDebuggerFilter(0, 2, 0, stats)
+	now := time.Now()
+	f.now = func() time.Time { return now }
+
+	results := make([]string, 0, 5)
+	for i := 0; i < 5; i++ {
+		results = append(results, probeResult("p1", "hello"))
+	}
+	results = append(results, probeResult("p2", "hello"))
+
+	_, kept, dropped := f.filter([]byte("[" + strings.Join(results, ",") + "]"))
+	assert.Equal(t, 3, kept)
+	assert.Equal(t, 3, dropped)
+
+	// tokens are refilled over time
+	now = now.Add(time.Second)
+	_, kept, dropped = f.filter([]byte("[" + probeResult("p1", "hello") + "]"))
+	assert.Equal(t, 1, kept)
+	assert.Equal(t, 0, dropped)
+}
+
+func TestDebuggerFilterPassThrough(t *testing.T) {
+	f := newDebuggerFilter(time.Second, 1, 0, &teststatsd.Client{})
+
+	for _, body := range []string{
+		`not json`,
+		`{"message":"an object"}`,
+		`[{"message":"no probe"},{"message":"no probe"}]`,
+	} {
+		filtered, _, dropped := f.filter([]byte(body))
+		assert.Equal(t, body, string(filtered))
+		assert.Equal(t, 0, dropped)
+	}
+}
+
+func TestDebuggerFilterHandler(t *testing.T) {
+	var proxied []string
+	next := http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.EqualValues(t, len(body), req.ContentLength)
+		proxied = append(proxied, string(body))
+	})
+	handler := newDebuggerFilter(time.Minute, 0, 0, &teststatsd.Client{}).handler(next)
+
+	body := "[" + probeResult("p1", "hello") + "," + probeResult("p1", "hello") + "]"
+	req := httptest.NewRequest("POST", "/debugger/v1/input", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Len(t, proxied, 1)
+	assert.Equal(t, "["+probeResult("p1", "hello")+"]", proxied[0])
+
+	// everything is filtered out, nothing is proxied
+	req = httptest.NewRequest("POST", "/debugger/v1/input", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Len(t, proxied, 1)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	// compressed payloads are proxied untouched
+	req = httptest.NewRequest("POST", "/debugger/v1/input", strings.NewReader(body))
+	req.Header.Set("Content-Encoding", "gzip")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Len(t, proxied, 2)
+	assert.Equal(t, body, proxied[1])
+}
+
+func TestDebuggerFilterHandlerMaxBodySize(t *testing.T) {
+	proxied := false
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) { proxied = true })
+	body := "[" + probeResult("p1", "hello") + "]"
+
+	handler := newDebuggerFilter(time.Minute, 0, int64(len(body)-1), &teststatsd.Client{}).handler(next)
+	req := httptest.NewRequest("POST", "/debugger/v1/input", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.False(t, proxied)
+
+	handler = newDebuggerFilter(time.Minute, 0, int64(len(body)), &teststatsd.Client{}).handler(next)
+	req = httptest.NewRequest("POST", "/debugger/v1/input", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.True(t, proxied)
+}
@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/trace/config"
+	"github.com/DataDog/datadog-agent/pkg/trace/log"
+)
+
+// When the receiver stops, it first drains: the endpoints which aren't exempt
+// reply to the new requests with 503 and a Retry-After hint, so that the
+// clients send their payloads again to the next trace-agent, while the
+// requests in flight are processed.
+
+func getConfiguredDrainTimeoutDuration(conf *config.AgentConfig) time.Duration {
+	timeout := 5 * time.Second
+	if conf.DrainTimeout > 0 {
+		timeout = time.Duration(conf.DrainTimeout) * time.Second
+	}
+	return timeout
+}
+
+// drainMiddleware rejects the requests received while the receiver drains,
+// unless the endpoint is exempt, and tracks the other requests so that the
+// receiver waits for them.
+func (r *HTTPReceiver) drainMiddleware(e Endpoint, h http.Handler) http.Handler {
+	retryAfter := strconv.Itoa(int(getConfiguredDrainTimeoutDuration(r.conf).Seconds()))
+	tags := []string{"endpoint:" + e.Pattern}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.drainMu.RLock()
+		if r.draining && !e.DrainExempt {
+			r.drainMu.RUnlock()
+			w.Header().Set("Retry-After", retryAfter)
+			http.Error(w, "trace-agent is shutting down", http.StatusServiceUnavailable)
+			_ = r.statsd.Count("datadog.trace_agent.receiver.drain_rejected", 1, tags, 1)
+			return
+		}
+		// the request is added while holding the lock, so that it's never added
+		// once drain waits for the requests in flight
+		r.wg.Add(1)
+		r.drainMu.RUnlock()
+		defer r.wg.Done()
+		h.ServeHTTP(w, req)
+	})
+}
+
+// drain stops accepting the requests of the endpoints which aren't exempt, and
+// waits for the requests in flight to be processed, up to the drain timeout.
+func (r *HTTPReceiver) drain() {
+	r.drainMu.Lock()
+	r.draining = true
+	r.drainMu.Unlock()
+
+	timeout := getConfiguredDrainTimeoutDuration(r.conf)
+	log.Infof("Draining the receiver, waiting up to %s for the payloads in flight", timeout)
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		log.Info("Receiver drained")
+	case <-time.After(timeout):
+		log.Warnf("Timed out draining the receiver after %s, some payloads may be dropped", timeout)
+	}
+}
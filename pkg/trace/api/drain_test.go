@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/testutil"
+)
+
+func TestDrainMiddleware(t *testing.T) {
+	conf := newTestReceiverConfig()
+	conf.DrainTimeout = 3
+	r := newTestReceiverFromConfig(conf)
+	mux := r.buildMux()
+	data := msgpTraces(t, pb.Traces{testutil.RandomTrace(10, 20)})
+
+	post := func(path string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("POST", path, bytes.NewReader(data))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/msgpack")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, post("/v0.4/traces").Code)
+	<-r.out
+
+	r.drain()
+
+	rec := post("/v0.4/traces")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "3", rec.Header().Get("Retry-After"))
+	assert.Empty(t, r.out)
+
+	// the exempt endpoints keep replying
+	assert.Equal(t, http.StatusOK, post("/v0.4/services").Code)
+}
+
+func TestDrainWaitsForRequestsInFlight(t *testing.T) {
+	conf := newTestReceiverConfig()
+	conf.DrainTimeout = 1
+	r := NewHTTPReceiver(conf, nil, make(chan *Payload), noopStatsProcessor{}, nil, &statsd.NoOpClient{}, nil)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	h := r.drainMiddleware(Endpoint{Pattern: "/test"}, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	go h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/test", nil))
+	<-started
+
+	time.AfterFunc(100*time.Millisecond, func() { close(release) })
+	start := time.Now()
+	r.drain()
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, 100*time.Millisecond)
+	assert.Less(t, elapsed, time.Second)
+}
@@ -31,6 +31,10 @@ type Endpoint struct {
 	// IsEnabled specifies a function which reports whether this endpoint should be enabled
 	// based on the given config conf.
 	IsEnabled func(conf *config.AgentConfig) bool
+
+	// DrainExempt reports whether this endpoint keeps accepting requests while the
+	// receiver drains on shutdown, instead of replying with 503 and a Retry-After hint.
+	DrainExempt bool
 }
 
 // AttachEndpoint attaches an additional endpoint to the trace-agent. It is not thread-safe
@@ -46,9 +50,10 @@ var endpoints = []Endpoint{
 		Hidden:  true,
 	},
 	{
-		Pattern: "/services",
-		Handler: func(r *HTTPReceiver) http.Handler { return r.handleWithVersion(v01, r.handleServices) },
-		Hidden:  true,
+		Pattern:     "/services",
+		Handler:     func(r *HTTPReceiver) http.Handler { return r.handleWithVersion(v01, r.handleServices) },
+		Hidden:      true,
+		DrainExempt: true,
 	},
 	{
 		Pattern: "/v0.1/spans",
@@ -56,9 +61,10 @@ var endpoints = []Endpoint{
 		Hidden:  true,
 	},
 	{
-		Pattern: "/v0.1/services",
-		Handler: func(r *HTTPReceiver) http.Handler { return r.handleWithVersion(v01, r.handleServices) },
-		Hidden:  true,
+		Pattern:     "/v0.1/services",
+		Handler:     func(r *HTTPReceiver) http.Handler { return r.handleWithVersion(v01, r.handleServices) },
+		Hidden:      true,
+		DrainExempt: true,
 	},
 	{
 		Pattern: "/v0.2/traces",
@@ -66,25 +72,28 @@ var endpoints = []Endpoint{
 		Hidden:  true,
 	},
 	{
-		Pattern: "/v0.2/services",
-		Handler: func(r *HTTPReceiver) http.Handler { return r.handleWithVersion(v02, r.handleServices) },
-		Hidden:  true,
+		Pattern:     "/v0.2/services",
+		Handler:     func(r *HTTPReceiver) http.Handler { return r.handleWithVersion(v02, r.handleServices) },
+		Hidden:      true,
+		DrainExempt: true,
 	},
 	{
 		Pattern: "/v0.3/traces",
 		Handler: func(r *HTTPReceiver) http.Handler { return r.handleWithVersion(v03, r.handleTraces) },
 	},
 	{
-		Pattern: "/v0.3/services",
-		Handler: func(r *HTTPReceiver) http.Handler { return r.handleWithVersion(v03, r.handleServices) },
+		Pattern:     "/v0.3/services",
+		Handler:     func(r *HTTPReceiver) http.Handler { return r.handleWithVersion(v03, r.handleServices) },
+		DrainExempt: true,
 	},
 	{
 		Pattern: "/v0.4/traces",
 		Handler: func(r *HTTPReceiver) http.Handler { return r.handleWithVersion(v04, r.handleTraces) },
 	},
 	{
-		Pattern: "/v0.4/services",
-		Handler: func(r *HTTPReceiver) http.Handler { return r.handleWithVersion(v04, r.handleServices) },
+		Pattern:     "/v0.4/services",
+		Handler:     func(r *HTTPReceiver) http.Handler { return r.handleWithVersion(v04, r.handleServices) },
+		DrainExempt: true,
 	},
 	{
 		Pattern: "/v0.5/traces",
@@ -59,6 +59,7 @@ type OTLPReceiver struct {
 	statsd         statsd.ClientInterface
 	timing         timing.Reporter
 	ignoreResNames map[string]struct{}
+	rateByService  *sampler.RateByService // the agent priority rates, applied in sampling parity mode
 }
 
 // NewOTLPReceiver returns a new OTLPReceiver which sends any incoming traces down the out channel.
@@ -193,6 +194,9 @@ func (o *OTLPReceiver) processRequest(ctx context.Context, header http.Header, i
 // in a distributed system.
 const knuthFactor = uint64(1111111111111111111)
 
+// agentRateKey is the metric of the root span holding the agent rate applied by a tracer.
+const agentRateKey = "_dd.agent_psr"
+
 // samplingRate returns the rate as defined by the probabilistic sampler.
 func (o *OTLPReceiver) samplingRate() float64 {
 	rate := o.conf.OTLPReceiver.ProbabilisticSampling / 100
@@ -206,7 +210,12 @@ func (o *OTLPReceiver) samplingRate() float64 {
 
 // sample returns the sampling priority to apply to a trace with the trace ID tid.
 func (o *OTLPReceiver) sample(tid uint64) sampler.SamplingPriority {
-	rate := o.samplingRate()
+	return sampleByRate(tid, o.samplingRate())
+}
+
+// sampleByRate returns the sampling priority to apply to a trace with the trace ID tid
+// when it is sampled at the given rate.
+func sampleByRate(tid uint64, rate float64) sampler.SamplingPriority {
 	if rate == 1 {
 		return sampler.PriorityAutoKeep
 	}
@@ -220,6 +229,32 @@ func (o *OTLPReceiver) sample(tid uint64) sampler.SamplingPriority {
 	return sampler.PriorityAutoDrop
 }
 
+// SetRateByService sets the agent priority sampling rates used to sample the
+// traces in sampling parity mode (see config.OTLP.SamplingParity).
+func (o *OTLPReceiver) SetRateByService(rates *sampler.RateByService) {
+	o.rateByService = rates
+}
+
+// samplingParity reports whether the traces without a user priority are sampled
+// with the agent priority sampling rates, like the traces of Datadog tracers.
+func (o *OTLPReceiver) samplingParity() bool {
+	return o.conf.OTLPReceiver.SamplingParity && o.rateByService != nil && !o.conf.ProbabilisticSamplerEnabled
+}
+
+// sampleByService returns the sampling priority a Datadog tracer would apply to the
+// trace with the trace ID tid, using the agent rate of the service of its root span.
+// The applied rate is set on the root span, so that the priority sampler counts the
+// trace and weighs it in the rates it computes, as for tracer traces.
+func (o *OTLPReceiver) sampleByService(tid uint64, spans pb.Trace, env string) sampler.SamplingPriority {
+	if env == "" {
+		env = o.conf.DefaultEnv
+	}
+	root := traceutil.GetRoot(spans)
+	rate := o.rateByService.GetRate(root.Service, env)
+	traceutil.SetMetric(root, agentRateKey, rate)
+	return sampleByRate(tid, rate)
+}
+
 // SetOTelAttributeTranslator sets the attribute translator to be used by this OTLPReceiver
 func (o *OTLPReceiver) SetOTelAttributeTranslator(attrstrans *attributes.Translator) {
 	o.conf.OTLPReceiver.AttributesTranslator = attrstrans
@@ -323,7 +358,7 @@ func (o *OTLPReceiver) receiveResourceSpansV2(ctx context.Context, rspans ptrace
 	}
 	p.TracerPayload = &pb.TracerPayload{
 		Hostname:      hostname,
-		Chunks:        o.createChunks(tracesByID, priorityByID),
+		Chunks:        o.createChunks(tracesByID, priorityByID, env),
 		Env:           env,
 		ContainerID:   containerID,
 		LanguageName:  tagstats.Lang,
@@ -448,6 +483,7 @@ func (o *OTLPReceiver) receiveResourceSpansV1(ctx context.Context, rspans ptrace
 	if env == "" {
 		env = o.conf.DefaultEnv
 	}
+	env = traceutil.NormalizeTagValue(env)
 
 	// Get the hostname or set to empty if source is empty
 	var hostname string
@@ -466,8 +502,8 @@ func (o *OTLPReceiver) receiveResourceSpansV1(ctx context.Context, rspans ptrace
 	}
 	p.TracerPayload = &pb.TracerPayload{
 		Hostname:        hostname,
-		Chunks:          o.createChunks(tracesByID, priorityByID),
-		Env:             traceutil.NormalizeTagValue(env),
+		Chunks:          o.createChunks(tracesByID, priorityByID, env),
+		Env:             env,
 		ContainerID:     containerID,
 		LanguageName:    tagstats.Lang,
 		LanguageVersion: tagstats.LangVersion,
@@ -517,7 +553,8 @@ func flatten(m map[string]string) *strings.Builder {
 // createChunks creates a set of pb.TraceChunk's based on two maps:
 // - a map from trace ID to the spans sharing that trace ID
 // - a map of user-set sampling priorities by trace ID, if set
-func (o *OTLPReceiver) createChunks(tracesByID map[uint64]pb.Trace, prioritiesByID map[uint64]sampler.SamplingPriority) []*pb.TraceChunk {
+// The env of the traces is used to look up their sampling rate in sampling parity mode.
+func (o *OTLPReceiver) createChunks(tracesByID map[uint64]pb.Trace, prioritiesByID map[uint64]sampler.SamplingPriority, env string) []*pb.TraceChunk {
 	traceChunks := make([]*pb.TraceChunk, 0, len(tracesByID))
 	for k, spans := range tracesByID {
 		if len(spans) == 0 {
@@ -542,13 +579,20 @@ func (o *OTLPReceiver) createChunks(tracesByID map[uint64]pb.Trace, prioritiesBy
 			// a manual decision has been made by the user
 			samplingPriorty = p
 			decisionMaker = "-4"
+		} else if o.samplingParity() {
+			// we apply the agent rates, like a Datadog tracer, so that the trace goes
+			// through the priority, rare and error samplers like tracer traces
+			samplingPriorty = o.sampleByService(k, spans, env)
+			decisionMaker = "-1"
+			delete(chunk.Tags, "_dd.otlp_sr")
 		} else {
 			// we use the probabilistic sampler to decide
 			samplingPriorty = o.sample(k)
 			decisionMaker = "-9"
 		}
 		// `_dd.p.dm` must not be set even if a drop decision is applied to the trace here.
-		// Traces with a drop decision by the OTLPReceiver’s probabilistic sampler are re-evaluated by ErrorsSampler later.
+		// Traces with a drop decision by the OTLPReceiver’s probabilistic sampler, or by the agent rates
+		// in sampling parity mode, are re-evaluated by ErrorsSampler later.
 		if samplingPriorty.IsKeep() {
 			traceutil.SetMeta(spans[0], "_dd.p.dm", decisionMaker)
 		}
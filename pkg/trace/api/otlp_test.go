@@ -751,7 +751,7 @@ func TestCreateChunks(t *testing.T) {
 			priorities := map[uint64]sampler.SamplingPriority{
 				traceID3: sampler.PriorityUserKeep,
 			}
-			chunks := o.createChunks(traces, priorities)
+			chunks := o.createChunks(traces, priorities, "")
 			require.Len(t, chunks, len(traces))
 			for _, c := range chunks {
 				if tt.probabilisticSamplerEnabled {
@@ -778,6 +778,69 @@ func TestCreateChunks(t *testing.T) {
 	}
 }
 
+func TestCreateChunksSamplingParity(t *testing.T) {
+	cfg := NewTestConfig(t)
+	cfg.DefaultEnv = "agent-env"
+	cfg.OTLPReceiver.ProbabilisticSampling = 100
+	cfg.OTLPReceiver.SamplingParity = true
+	o := NewOTLPReceiver(nil, cfg, &statsd.NoOpClient{}, &timing.NoopReporter{})
+
+	var rates sampler.RateByService
+	rates.SetAll(map[sampler.ServiceSignature]float64{
+		{Name: "parity", Env: "agent-env"}: 0.5,
+		{}:                                 0.1,
+	})
+	o.SetRateByService(&rates)
+
+	const (
+		traceID1 = 123           // sampled by 50% rate
+		traceID2 = 1237892138897 // not sampled by 50% rate
+		traceID3 = 1237892138898 // not sampled by 50% rate
+		traceID4 = 1237892138899
+	)
+	traces := map[uint64]pb.Trace{
+		traceID1: {{TraceID: traceID1, SpanID: 1, Service: "parity"}, {TraceID: traceID1, SpanID: 2, ParentID: 1}},
+		traceID2: {{TraceID: traceID2, SpanID: 1, Service: "parity"}, {TraceID: traceID2, SpanID: 2, ParentID: 1}},
+		traceID3: {{TraceID: traceID3, SpanID: 1, Service: "parity"}},
+		traceID4: {{TraceID: traceID4, SpanID: 1, Service: "other"}},
+	}
+	priorities := map[uint64]sampler.SamplingPriority{
+		traceID3: sampler.PriorityUserKeep,
+	}
+	chunks := o.createChunks(traces, priorities, "")
+	require.Len(t, chunks, len(traces))
+	for _, c := range chunks {
+		root := c.Spans[0]
+		switch root.TraceID {
+		case traceID1:
+			assert.Equal(t, "-1", root.Meta["_dd.p.dm"])
+			assert.Equal(t, int32(sampler.PriorityAutoKeep), c.Priority)
+			assert.Equal(t, 0.5, root.Metrics["_dd.agent_psr"])
+			assert.NotContains(t, c.Tags, "_dd.otlp_sr")
+		case traceID2:
+			assert.Empty(t, root.Meta["_dd.p.dm"])
+			assert.Equal(t, int32(sampler.PriorityAutoDrop), c.Priority)
+			assert.Equal(t, 0.5, root.Metrics["_dd.agent_psr"])
+		case traceID3:
+			// the decision of the user is followed
+			assert.Equal(t, "-4", root.Meta["_dd.p.dm"])
+			assert.Equal(t, int32(sampler.PriorityUserKeep), c.Priority)
+			assert.NotContains(t, root.Metrics, "_dd.agent_psr")
+		case traceID4:
+			// the default rate applies to unknown services
+			assert.Equal(t, 0.1, root.Metrics["_dd.agent_psr"])
+		}
+	}
+
+	t.Run("probabilistic sampler", func(t *testing.T) {
+		cfg.ProbabilisticSamplerEnabled = true
+		defer func() { cfg.ProbabilisticSamplerEnabled = false }()
+		chunks := o.createChunks(map[uint64]pb.Trace{traceID1: traces[traceID1]}, nil, "")
+		require.Len(t, chunks, 1)
+		assert.Equal(t, int32(sampler.PriorityNone), chunks[0].Priority)
+	})
+}
+
 func TestOTLPReceiveResourceSpans(t *testing.T) {
 	t.Run("ReceiveResourceSpansV1", func(t *testing.T) {
 		testOTLPReceiveResourceSpans(false, t)
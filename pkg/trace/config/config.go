@@ -79,6 +79,13 @@ type OTLP struct {
 	// decision is followed.
 	ProbabilisticSampling float64
 
+	// SamplingParity specifies whether the traces without a user sampling priority are sampled with
+	// the agent priority sampling rates, like the traces of Datadog tracers, instead of the probabilistic
+	// sampling percentage. Sampled this way, the traces go through the same priority, rare and error
+	// samplers as Datadog traces, and are weighed in the priority rates the agent computes.
+	// It is ignored when the agent probabilistic sampler is enabled.
+	SamplingParity bool `mapstructure:"sampling_parity"`
+
 	// AttributesTranslator specifies an OTLP to Datadog attributes translator.
 	AttributesTranslator *attributes.Translator `mapstructure:"-"`
 
@@ -209,6 +216,32 @@ type ReplaceRule struct {
 	Repl string `mapstructure:"repl"`
 }
 
+// IngestionQuota limits the spans and bytes ingested for an env, or a service of an env, over a period.
+type IngestionQuota struct {
+	// Env specifies the env the quota applies to.
+	Env string `mapstructure:"env"`
+
+	// Service specifies the service the quota applies to. When empty, the quota applies to all the
+	// services of Env which don't have their own quota.
+	Service string `mapstructure:"service"`
+
+	// Period specifies when the usage is reset, either "daily" or "monthly" (UTC).
+	Period string `mapstructure:"period"`
+
+	// MaxSpans specifies the number of spans allowed over the period. 0 means no limit.
+	MaxSpans int64 `mapstructure:"max_spans"`
+
+	// MaxBytes specifies the number of bytes allowed over the period. 0 means no limit.
+	MaxBytes int64 `mapstructure:"max_bytes"`
+
+	// Spillover specifies what happens to the traffic over the quota: "downsample" keeps
+	// SpilloverSampleRate of the traces, "tag" keeps them all with a spillover tag.
+	Spillover string `mapstructure:"spillover"`
+
+	// SpilloverSampleRate specifies the rate of the traces kept over the quota when downsampling.
+	SpilloverSampleRate float64 `mapstructure:"spillover_sample_rate"`
+}
+
 // WriterConfig specifies configuration for an API writer.
 type WriterConfig struct {
 	// ConnectionLimit specifies the maximum number of concurrent outgoing
@@ -291,6 +324,10 @@ type DebuggerProxyConfig struct {
 	APIKey string `json:"-"` // Never marshal this field
 	// AdditionalEndpoints is a map of additional Datadog sites to API keys.
 	AdditionalEndpoints map[string][]string `json:"-"` // Never marshal this field
+	// DedupWindow is the window within which identical probe results are sent only once. 0 disables deduplication.
+	DedupWindow time.Duration
+	// ProbeRateLimit is the maximum number of results per second proxied for each probe. 0 disables rate limiting.
+	ProbeRateLimit float64
 }
 
 // SymDBProxyConfig ...
@@ -336,6 +373,9 @@ type AgentConfig struct {
 	PeerTagsAggregation    bool          // enables/disables stats aggregation for peer entity tags, used by Concentrator and ClientStatsAggregator
 	ComputeStatsBySpanKind bool          // enables/disables the computing of stats based on a span's `span.kind` field
 	PeerTags               []string      // additional tags to use for peer entity stats aggregation
+	// DefaultPeerTagsAggregation enables/disables the aggregation on the default peer tags (peer.service, db.system...).
+	// When disabled, the stats are only aggregated on PeerTags.
+	DefaultPeerTagsAggregation bool
 
 	// Sampler configuration
 	ExtraSampleRate float64
@@ -350,6 +390,13 @@ type AgentConfig struct {
 	RareSamplerCooldownPeriod time.Duration
 	RareSamplerCardinality    int
 
+	// Sampler state persistence: the state of the priority and rare samplers is periodically
+	// written to SamplerStatePath, and restored at startup if it's not older than SamplerStateMaxAge.
+	SamplerStateEnabled  bool
+	SamplerStatePath     string
+	SamplerStateInterval time.Duration
+	SamplerStateMaxAge   time.Duration
+
 	// Probabilistic Sampler configuration
 	ProbabilisticSamplerEnabled            bool
 	ProbabilisticSamplerHashSeed           uint32
@@ -358,6 +405,14 @@ type AgentConfig struct {
 	// Error Tracking Standalone
 	ErrorTrackingStandalone bool
 
+	// ErrorFingerprintsEnabled enables the computation of the fingerprints of the error spans, from
+	// the first ErrorFingerprintStackFrames frames of their stack trace among others.
+	ErrorFingerprintsEnabled    bool
+	ErrorFingerprintStackFrames int
+
+	// IngestionQuotas limits the spans and bytes ingested per env or service
+	IngestionQuotas []*IngestionQuota
+
 	// Receiver
 	ReceiverEnabled bool // specifies whether Receiver listeners are enabled. Unless OTLPReceiver is used, this should always be true.
 	ReceiverHost    string
@@ -370,6 +425,7 @@ type AgentConfig struct {
 	Decoders        int   // specifies the number of traces that can be concurrently decoded.
 	MaxConnections  int   // specifies the maximum number of concurrent incoming connections allowed.
 	DecoderTimeout  int   // specifies the maximum time in milliseconds that the decoders will wait for a turn to accept a payload before returning 429
+	DrainTimeout    int   // specifies the maximum time in seconds the receiver waits for in-flight payloads when shutting down
 
 	WindowsPipeName        string
 	PipeBufferSize         int
@@ -559,8 +615,13 @@ func New() *AgentConfig {
 		RareSamplerCooldownPeriod: 5 * time.Minute,
 		RareSamplerCardinality:    200,
 
+		SamplerStateInterval: time.Minute,
+		SamplerStateMaxAge:   10 * time.Minute,
+
 		ErrorTrackingStandalone: false,
 
+		ErrorFingerprintStackFrames: 5,
+
 		ReceiverEnabled:        true,
 		ReceiverHost:           "localhost",
 		ReceiverPort:           8126,
@@ -608,9 +669,10 @@ func New() *AgentConfig {
 			Enabled: true,
 		},
 
-		Features:               make(map[string]struct{}),
-		PeerTagsAggregation:    true,
-		ComputeStatsBySpanKind: true,
+		Features:                   make(map[string]struct{}),
+		PeerTagsAggregation:        true,
+		DefaultPeerTagsAggregation: true,
+		ComputeStatsBySpanKind:     true,
 	}
 }
 
@@ -711,6 +773,10 @@ func (c *AgentConfig) ConfiguredPeerTags() []string {
 	if !c.PeerTagsAggregation {
 		return nil
 	}
+	if !c.DefaultPeerTagsAggregation {
+		// the base service isn't a peer tag, it's kept so that service overrides are still aggregated on
+		return preparePeerTags(append([]string{"_dd.base_service"}, c.PeerTags...))
+	}
 	return preparePeerTags(append(basePeerTags, c.PeerTags...))
 }
 
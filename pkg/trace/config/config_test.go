@@ -64,4 +64,15 @@ func TestPeerTagsAggregation(t *testing.T) {
 		cfg.PeerTags = basePeerTags[:2]
 		assert.Equal(t, basePeerTags, cfg.ConfiguredPeerTags())
 	})
+	t.Run("default-disabled-user-tags", func(t *testing.T) {
+		cfg := New()
+		cfg.DefaultPeerTagsAggregation = false
+		cfg.PeerTags = []string{"messaging.destination", "peer.service"}
+		assert.Equal(t, []string{"_dd.base_service", "messaging.destination", "peer.service"}, cfg.ConfiguredPeerTags())
+	})
+	t.Run("default-disabled-no-user-tags", func(t *testing.T) {
+		cfg := New()
+		cfg.DefaultPeerTagsAggregation = false
+		assert.Equal(t, []string{"_dd.base_service"}, cfg.ConfiguredPeerTags())
+	})
 }
@@ -91,3 +91,14 @@ func (cat *serviceKeyCatalog) ratesByService(agentEnv string, rates map[Signatur
 	rbs[ServiceSignature{}] = defaultRate
 	return rbs
 }
+
+// signatures returns the service signatures of the catalog by their hash.
+func (cat *serviceKeyCatalog) signatures() map[Signature]ServiceSignature {
+	cat.mu.Lock()
+	defer cat.mu.Unlock()
+	sigs := make(map[Signature]ServiceSignature, len(cat.items))
+	for key, el := range cat.items {
+		sigs[el.Value.(catalogEntry).sig] = key
+	}
+	return sigs
+}
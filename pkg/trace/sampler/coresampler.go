@@ -262,6 +262,40 @@ func (s *Sampler) defaultRate() float64 {
 	return rate
 }
 
+// exportRates returns the sampling rates of the signatures, without the extra rate, and the lowest rate.
+func (s *Sampler) exportRates() (map[Signature]float64, float64) {
+	s.muRates.RLock()
+	defer s.muRates.RUnlock()
+	rates := make(map[Signature]float64, len(s.rates))
+	for sig, rate := range s.rates {
+		rates[sig] = rate
+	}
+	return rates, s.lowestRate
+}
+
+// importRates restores sampling rates computed before a restart. The signatures are counted as
+// seen without traffic, so that the rates of the signatures which don't receive traffic anymore
+// increase progressively, instead of being discarded at the next rates update.
+func (s *Sampler) importRates(rates map[Signature]float64, lowestRate float64) {
+	s.muSeen.Lock()
+	defer s.muSeen.Unlock()
+	s.muRates.Lock()
+	defer s.muRates.Unlock()
+
+	if s.rates == nil {
+		s.rates = make(map[Signature]float64, len(rates))
+	}
+	for sig, rate := range rates {
+		if _, ok := s.seen[sig]; !ok {
+			s.seen[sig] = [numBuckets]float32{}
+		}
+		s.rates[sig] = rate
+	}
+	if len(rates) > 0 {
+		s.lowestRate = lowestRate
+	}
+}
+
 func (s *Sampler) size() int64 {
 	s.muSeen.RLock()
 	defer s.muSeen.RUnlock()
@@ -108,6 +108,22 @@ func (rbs *RateByService) GetNewState(version string) State {
 	return ret
 }
 
+// GetRate returns the rate a client library applies to the traces of a
+// service and env: the rate of the service/env tuple, or else the default
+// rate. It returns 1 until rates have been computed.
+func (rbs *RateByService) GetRate(service, env string) float64 {
+	rbs.mu.RLock()
+	defer rbs.mu.RUnlock()
+
+	if v, ok := rbs.rates[ServiceSignature{Name: service, Env: env}.String()]; ok {
+		return v.r
+	}
+	if v, ok := rbs.rates[defaultServiceRateKey]; ok {
+		return v.r
+	}
+	return 1
+}
+
 var localVersion atomic.Int64
 
 func newVersion() string {
@@ -120,6 +120,27 @@ func TestRateByServiceDefaults(t *testing.T) {
 	}, rbc.GetNewState("").Rates)
 }
 
+func TestRateByServiceGetRate(t *testing.T) {
+	assert := assert.New(t)
+
+	var rbc RateByService
+	assert.Equal(1., rbc.GetRate("one", "prod"))
+
+	rbc.SetAll(map[ServiceSignature]float64{
+		{"one", "prod"}: 0.5,
+		{"two", ""}:     0.4,
+	})
+	assert.Equal(0.5, rbc.GetRate("one", "prod"))
+	assert.Equal(0.4, rbc.GetRate("two", ""))
+	assert.Equal(1., rbc.GetRate("one", "test"))
+
+	rbc.SetAll(map[ServiceSignature]float64{
+		{"one", "prod"}: 0.5,
+		{}:              0.2,
+	})
+	assert.Equal(0.2, rbc.GetRate("one", "test"))
+}
+
 func TestVersionChanges(t *testing.T) {
 	rbc := RateByService{}
 	rates := map[ServiceSignature]float64{
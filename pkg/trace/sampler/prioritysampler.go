@@ -74,6 +74,46 @@ func (s *PrioritySampler) GetTargetTPS() float64 {
 	return s.sampler.targetTPS.Load()
 }
 
+// serviceRate is the sampling rate of a service persisted across restarts.
+type serviceRate struct {
+	Service string  `json:"service"`
+	Env     string  `json:"env"`
+	Rate    float64 `json:"rate"`
+}
+
+// priorityState is the state of the priority sampler persisted across restarts.
+type priorityState struct {
+	Rates      []serviceRate `json:"rates"`
+	LowestRate float64       `json:"lowest_rate"`
+}
+
+// exportState returns the sampling rates of the services known to the sampler.
+func (s *PrioritySampler) exportState() priorityState {
+	rates, lowestRate := s.sampler.exportRates()
+	state := priorityState{Rates: make([]serviceRate, 0, len(rates)), LowestRate: lowestRate}
+	for sig, svcSig := range s.catalog.signatures() {
+		if rate, ok := rates[sig]; ok {
+			state.Rates = append(state.Rates, serviceRate{Service: svcSig.Name, Env: svcSig.Env, Rate: rate})
+		}
+	}
+	return state
+}
+
+// importState restores the sampling rates of the services and shares them with the clients.
+// It returns the number of rates restored.
+func (s *PrioritySampler) importState(state priorityState) int {
+	rates := make(map[Signature]float64, len(state.Rates))
+	for _, r := range state.Rates {
+		if r.Rate <= 0 || r.Rate > 1 {
+			continue
+		}
+		rates[s.catalog.register(ServiceSignature{Name: r.Service, Env: r.Env})] = r.Rate
+	}
+	s.sampler.importRates(rates, state.LowestRate)
+	s.updateRates()
+	return len(rates)
+}
+
 // update sampling rates
 func (s *PrioritySampler) updateRates() {
 	s.rateByService.SetAll(s.ratesByService())
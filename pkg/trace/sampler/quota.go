@@ -0,0 +1,173 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sampler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/trace/config"
+	"github.com/DataDog/datadog-agent/pkg/trace/traceutil"
+	"github.com/DataDog/datadog-go/v5/statsd"
+)
+
+const (
+	// MetricsQuotaSpans is the metric name for the number of spans ingested under an ingestion quota.
+	MetricsQuotaSpans = "datadog.trace_agent.quota.spans"
+	// MetricsQuotaBytes is the metric name for the number of bytes ingested under an ingestion quota.
+	MetricsQuotaBytes = "datadog.trace_agent.quota.bytes"
+	// MetricsQuotaSpilloverSpans is the metric name for the number of spans ingested over their quota.
+	MetricsQuotaSpilloverSpans = "datadog.trace_agent.quota.spillover_spans"
+	// MetricsQuotaDroppedSpans is the metric name for the number of spans dropped because they were over their quota.
+	MetricsQuotaDroppedSpans = "datadog.trace_agent.quota.dropped_spans"
+	// MetricsQuotaUsage is the metric name for the fraction of the quota used in the current period.
+	MetricsQuotaUsage = "datadog.trace_agent.quota.usage"
+
+	// QuotaPeriodDaily resets the usage of a quota every day at midnight UTC.
+	QuotaPeriodDaily = "daily"
+	// QuotaPeriodMonthly resets the usage of a quota on the first day of every month at midnight UTC.
+	QuotaPeriodMonthly = "monthly"
+
+	// QuotaSpilloverDownsample keeps a fraction of the traces over the quota.
+	QuotaSpilloverDownsample = "downsample"
+	// QuotaSpilloverTag keeps all the traces over the quota, tagged with TagQuotaSpillover.
+	QuotaSpilloverTag = "tag"
+
+	// TagQuotaSpillover is the chunk tag set on the traces ingested over their quota.
+	TagQuotaSpillover = "_dd.quota.spillover"
+)
+
+type quotaKey struct {
+	env     string
+	service string
+}
+
+// quotaState is the usage of a quota in the current period.
+type quotaState struct {
+	conf        *config.IngestionQuota
+	periodStart time.Time
+	spans       int64
+	bytes       int64
+
+	// counters reset at each report
+	ingestedSpans  int64
+	ingestedBytes  int64
+	spilloverSpans int64
+	droppedSpans   int64
+}
+
+// QuotaEnforcer tracks the spans and bytes ingested per env or service against the configured
+// ingestion quotas. The traffic over a quota is either downsampled or tagged as spillover.
+// The usage is kept in memory, it starts over when the agent restarts.
+type QuotaEnforcer struct {
+	mu     sync.Mutex
+	quotas map[quotaKey]*quotaState
+}
+
+// NewQuotaEnforcer returns an enforcer of the ingestion quotas of the configuration.
+func NewQuotaEnforcer(conf *config.AgentConfig) *QuotaEnforcer {
+	q := &QuotaEnforcer{quotas: make(map[quotaKey]*quotaState, len(conf.IngestionQuotas))}
+	for _, quota := range conf.IngestionQuotas {
+		q.quotas[quotaKey{env: quota.Env, service: quota.Service}] = &quotaState{conf: quota}
+	}
+	return q
+}
+
+// Apply counts the spans of the chunk towards the quota of its env and service. It returns false
+// when the chunk is over the quota and must be dropped.
+func (q *QuotaEnforcer) Apply(now time.Time, pt *traceutil.ProcessedTrace) bool {
+	if q == nil || len(q.quotas) == 0 {
+		return true
+	}
+	state, ok := q.quotas[quotaKey{env: pt.TracerEnv, service: pt.Root.Service}]
+	if !ok {
+		if state, ok = q.quotas[quotaKey{env: pt.TracerEnv}]; !ok {
+			return true
+		}
+	}
+	spans := int64(len(pt.TraceChunk.Spans))
+	bytes := int64(pt.TraceChunk.Msgsize())
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if start := periodStart(state.conf.Period, now); !start.Equal(state.periodStart) {
+		state.periodStart = start
+		state.spans = 0
+		state.bytes = 0
+	}
+
+	if state.exceeded() {
+		if state.conf.Spillover == QuotaSpilloverTag {
+			if pt.TraceChunk.Tags == nil {
+				pt.TraceChunk.Tags = make(map[string]string)
+			}
+			pt.TraceChunk.Tags[TagQuotaSpillover] = "true"
+		} else if !SampleByRate(pt.Root.TraceID, state.conf.SpilloverSampleRate) {
+			state.droppedSpans += spans
+			return false
+		}
+		state.spilloverSpans += spans
+	}
+
+	state.spans += spans
+	state.bytes += bytes
+	state.ingestedSpans += spans
+	state.ingestedBytes += bytes
+	return true
+}
+
+// exceeded returns whether the usage of the current period reached one of the limits.
+func (s *quotaState) exceeded() bool {
+	return (s.conf.MaxSpans > 0 && s.spans >= s.conf.MaxSpans) || (s.conf.MaxBytes > 0 && s.bytes >= s.conf.MaxBytes)
+}
+
+// usage returns the fraction of the most used limit.
+func (s *quotaState) usage() float64 {
+	var usage float64
+	if s.conf.MaxSpans > 0 {
+		usage = float64(s.spans) / float64(s.conf.MaxSpans)
+	}
+	if s.conf.MaxBytes > 0 {
+		usage = max(usage, float64(s.bytes)/float64(s.conf.MaxBytes))
+	}
+	return usage
+}
+
+func (s *quotaState) tags() []string {
+	tags := []string{"target_env:" + s.conf.Env, "period:" + s.conf.Period}
+	if s.conf.Service != "" {
+		tags = append(tags, "target_service:"+s.conf.Service)
+	}
+	return tags
+}
+
+// periodStart returns the start of the quota period containing now.
+func periodStart(period string, now time.Time) time.Time {
+	now = now.UTC()
+	if period == QuotaPeriodMonthly {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (q *QuotaEnforcer) report(statsd statsd.ClientInterface) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, state := range q.quotas {
+		tags := state.tags()
+		_ = statsd.Count(MetricsQuotaSpans, state.ingestedSpans, tags, 1)
+		_ = statsd.Count(MetricsQuotaBytes, state.ingestedBytes, tags, 1)
+		_ = statsd.Count(MetricsQuotaSpilloverSpans, state.spilloverSpans, tags, 1)
+		_ = statsd.Count(MetricsQuotaDroppedSpans, state.droppedSpans, tags, 1)
+		_ = statsd.Gauge(MetricsQuotaUsage, state.usage(), tags, 1)
+		state.ingestedSpans = 0
+		state.ingestedBytes = 0
+		state.spilloverSpans = 0
+		state.droppedSpans = 0
+	}
+}
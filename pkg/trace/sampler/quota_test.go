@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sampler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/config"
+	"github.com/DataDog/datadog-agent/pkg/trace/teststatsd"
+	"github.com/DataDog/datadog-agent/pkg/trace/traceutil"
+)
+
+func quotaTestTrace(env, service string, traceID uint64, spans int) *traceutil.ProcessedTrace {
+	chunk := &pb.TraceChunk{Tags: map[string]string{}}
+	for i := 0; i < spans; i++ {
+		chunk.Spans = append(chunk.Spans, &pb.Span{TraceID: traceID, SpanID: uint64(i + 1), Service: service})
+	}
+	return &traceutil.ProcessedTrace{TraceChunk: chunk, Root: chunk.Spans[0], TracerEnv: env}
+}
+
+func TestQuotaEnforcerSpillover(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	conf := config.New()
+	conf.IngestionQuotas = []*config.IngestionQuota{
+		{Env: "prod", Period: QuotaPeriodDaily, MaxSpans: 4, Spillover: QuotaSpilloverDownsample},
+		{Env: "prod", Service: "billing", Period: QuotaPeriodMonthly, MaxSpans: 2, Spillover: QuotaSpilloverTag},
+	}
+	q := NewQuotaEnforcer(conf)
+
+	t.Run("downsample", func(t *testing.T) {
+		assert.True(t, q.Apply(now, quotaTestTrace("prod", "web", 1, 2)))
+		assert.True(t, q.Apply(now, quotaTestTrace("prod", "web", 2, 2)))
+		// the quota is reached and the spillover sample rate is 0
+		assert.False(t, q.Apply(now, quotaTestTrace("prod", "web", 3, 2)))
+		// the usage is reset the next day
+		assert.True(t, q.Apply(now.Add(12*time.Hour), quotaTestTrace("prod", "web", 4, 2)))
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		pt := quotaTestTrace("prod", "billing", 1, 2)
+		assert.True(t, q.Apply(now, pt))
+		assert.NotContains(t, pt.TraceChunk.Tags, TagQuotaSpillover)
+
+		pt = quotaTestTrace("prod", "billing", 2, 2)
+		assert.True(t, q.Apply(now.Add(24*time.Hour), pt))
+		assert.Equal(t, "true", pt.TraceChunk.Tags[TagQuotaSpillover])
+	})
+
+	t.Run("no-quota", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			assert.True(t, q.Apply(now, quotaTestTrace("staging", "web", uint64(i), 2)))
+		}
+	})
+}
+
+func TestQuotaEnforcerDownsampleRate(t *testing.T) {
+	now := time.Now()
+	conf := config.New()
+	conf.IngestionQuotas = []*config.IngestionQuota{
+		{Env: "prod", Period: QuotaPeriodDaily, MaxBytes: 1, Spillover: QuotaSpilloverDownsample, SpilloverSampleRate: 0.5},
+	}
+	q := NewQuotaEnforcer(conf)
+	assert.True(t, q.Apply(now, quotaTestTrace("prod", "web", 0, 1)))
+
+	kept := 0
+	for i := 1; i <= 1000; i++ {
+		if q.Apply(now, quotaTestTrace("prod", "web", uint64(i)*0x9E3779B97F4A7C15, 1)) {
+			kept++
+		}
+	}
+	assert.InDelta(t, 500, kept, 100)
+}
+
+func TestQuotaEnforcerReport(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	conf := config.New()
+	conf.IngestionQuotas = []*config.IngestionQuota{
+		{Env: "prod", Service: "web", Period: QuotaPeriodDaily, MaxSpans: 4, Spillover: QuotaSpilloverTag},
+	}
+	q := NewQuotaEnforcer(conf)
+	for i := 0; i < 3; i++ {
+		q.Apply(now, quotaTestTrace("prod", "web", uint64(i), 2))
+	}
+
+	statsdClient := &teststatsd.Client{}
+	q.report(statsdClient)
+
+	tags := []string{"target_env:prod", "period:daily", "target_service:web"}
+	counts := statsdClient.GetCountSummaries()
+	assert.Equal(t, int64(6), counts[MetricsQuotaSpans].Sum)
+	assert.Equal(t, tags, counts[MetricsQuotaSpans].Calls[0].Tags)
+	assert.Equal(t, int64(2), counts[MetricsQuotaSpilloverSpans].Sum)
+	assert.Equal(t, int64(0), counts[MetricsQuotaDroppedSpans].Sum)
+	assert.Equal(t, 1.5, statsdClient.GetGaugeSummaries()[MetricsQuotaUsage].Last)
+}
@@ -160,6 +160,67 @@ func (e *RareSampler) loadSeenSpans(shardSig Signature) *seenSpans {
 	return s
 }
 
+// rareShardState is the state of the spans seen for a combination of (env, service) persisted
+// across restarts.
+type rareShardState struct {
+	Shard Signature `json:"shard"`
+	// Cardinality is the limit which the hashes were shrunk to, 0 if they weren't.
+	Cardinality int                    `json:"cardinality,omitempty"`
+	Expires     map[spanHash]time.Time `json:"expires"`
+}
+
+// exportState returns the spans seen by the sampler which didn't expire yet.
+func (e *RareSampler) exportState(now time.Time) []rareShardState {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	state := make([]rareShardState, 0, len(e.seen))
+	for shard, ss := range e.seen {
+		shardState := rareShardState{Shard: shard, Expires: make(map[spanHash]time.Time)}
+		ss.mu.RLock()
+		if ss.shrunk {
+			shardState.Cardinality = ss.cardinality
+		}
+		for h, expire := range ss.expires {
+			if expire.After(now) {
+				shardState.Expires[h] = expire
+			}
+		}
+		ss.mu.RUnlock()
+		if len(shardState.Expires) > 0 {
+			state = append(state, shardState)
+		}
+	}
+	return state
+}
+
+// importState restores the spans seen before a restart, so that they aren't sampled again before
+// their cooldown expires. It returns the number of spans restored.
+func (e *RareSampler) importState(now time.Time, state []rareShardState) int {
+	var n int
+	for _, shardState := range state {
+		// the hashes shrunk to another cardinality don't match the ones computed now
+		if shardState.Cardinality != 0 && shardState.Cardinality != e.cardinality {
+			continue
+		}
+		ss := e.loadSeenSpans(shardState.Shard)
+		ss.mu.Lock()
+		if shardState.Cardinality != 0 {
+			ss.shrunk = true
+		}
+		for h, expire := range shardState.Expires {
+			if expire.After(now) {
+				ss.expires[h] = expire
+				n++
+			}
+		}
+		if len(ss.expires) > ss.cardinality {
+			ss.shrink()
+		}
+		ss.mu.Unlock()
+	}
+	return n
+}
+
 func (e *RareSampler) report(statsd statsd.ClientInterface) {
 	_ = statsd.Count(MetricsRareHits, e.hits.Swap(0), nil, 1)
 	_ = statsd.Count(MetricsRareMisses, e.misses.Swap(0), nil, 1)
@@ -0,0 +1,167 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sampler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/trace/config"
+	"github.com/DataDog/datadog-agent/pkg/trace/log"
+)
+
+// The sampling rates computed by the priority sampler and the spans seen by the rare sampler
+// converge over a few minutes of traffic. They are periodically persisted to disk and restored at
+// startup, so that the sampling decisions don't skew while the samplers warm up after a restart.
+
+const samplerStateVersion = 1
+
+// samplerState is the format of the sampler state file.
+type samplerState struct {
+	Version   int              `json:"version"`
+	CreatedAt time.Time        `json:"created_at"`
+	Priority  priorityState    `json:"priority"`
+	Rare      []rareShardState `json:"rare,omitempty"`
+}
+
+// StatePersister periodically writes the state of the priority and rare samplers to a file, and
+// restores it when it starts.
+type StatePersister struct {
+	enabled  bool
+	path     string
+	interval time.Duration
+	maxAge   time.Duration
+
+	priority *PrioritySampler
+	rare     *RareSampler
+
+	exit chan struct{}
+	wg   sync.WaitGroup
+	now  func() time.Time
+}
+
+// NewStatePersister returns a StatePersister of the given samplers.
+func NewStatePersister(conf *config.AgentConfig, priority *PrioritySampler, rare *RareSampler) *StatePersister {
+	return &StatePersister{
+		enabled:  conf.SamplerStateEnabled && conf.SamplerStatePath != "",
+		path:     conf.SamplerStatePath,
+		interval: conf.SamplerStateInterval,
+		maxAge:   conf.SamplerStateMaxAge,
+		priority: priority,
+		rare:     rare,
+		exit:     make(chan struct{}),
+		now:      time.Now,
+	}
+}
+
+// Start restores the state of the samplers and starts persisting it periodically.
+func (p *StatePersister) Start() {
+	if !p.enabled {
+		return
+	}
+	if err := p.restore(); err != nil {
+		if os.IsNotExist(err) {
+			log.Debugf("No sampler state to restore at %s", p.path)
+		} else {
+			log.Warnf("Could not restore the sampler state from %s: %v", p.path, err)
+		}
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.persistOrWarn()
+			case <-p.exit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic persistence and persists the state one last time.
+func (p *StatePersister) Stop() {
+	if !p.enabled {
+		return
+	}
+	close(p.exit)
+	p.wg.Wait()
+	p.persistOrWarn()
+}
+
+func (p *StatePersister) persistOrWarn() {
+	if err := p.persist(); err != nil {
+		log.Warnf("Could not persist the sampler state to %s: %v", p.path, err)
+	}
+}
+
+// persist writes the state of the samplers to the state file.
+func (p *StatePersister) persist() error {
+	now := p.now()
+	state := samplerState{
+		Version:   samplerStateVersion,
+		CreatedAt: now,
+		Priority:  p.priority.exportState(),
+	}
+	if p.rare.IsEnabled() {
+		state.Rare = p.rare.exportState(now)
+	}
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	// the state is written to a temporary file first, so that it's never left half-written
+	f, err := os.CreateTemp(filepath.Dir(p.path), filepath.Base(p.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p.path)
+}
+
+// restore reads the state file and restores the state of the samplers. States older than the
+// maximum age are ignored, as the traffic may have changed since.
+func (p *StatePersister) restore() error {
+	b, err := os.ReadFile(p.path)
+	if err != nil {
+		return err
+	}
+	var state samplerState
+	if err := json.Unmarshal(b, &state); err != nil {
+		return fmt.Errorf("malformed sampler state: %w", err)
+	}
+	if state.Version != samplerStateVersion {
+		return fmt.Errorf("unsupported sampler state version %d", state.Version)
+	}
+	now := p.now()
+	if age := now.Sub(state.CreatedAt); age > p.maxAge {
+		return fmt.Errorf("sampler state is too old: %s", age.Truncate(time.Second))
+	}
+
+	rates := p.priority.importState(state.Priority)
+	var spans int
+	if p.rare.IsEnabled() {
+		spans = p.rare.importState(now, state.Rare)
+	}
+	log.Infof("Restored the sampling rates of %d services and %d rare spans from %s", rates, spans, p.path)
+	return nil
+}
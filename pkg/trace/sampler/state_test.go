@@ -0,0 +1,98 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sampler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/config"
+)
+
+func newTestStatePersister(path string, now time.Time) (*StatePersister, *DynamicConfig) {
+	conf := config.New()
+	conf.TargetTPS = 1
+	conf.RareSamplerEnabled = true
+	conf.SamplerStateEnabled = true
+	conf.SamplerStatePath = path
+	dynConf := NewDynamicConfig()
+	p := NewStatePersister(conf, NewPrioritySampler(conf, dynConf), NewRareSampler(conf))
+	p.now = func() time.Time { return now }
+	return p, dynConf
+}
+
+func TestStatePersisterRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	now := time.Unix(1767225600, 0)
+	p, dynConf := newTestStatePersister(path, now)
+
+	// 100 traces per bucket for a target of 1 trace per second
+	root := &pb.Span{Service: "web", Metrics: map[string]float64{"_top_level": 1}}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 100; j++ {
+			sig := p.priority.catalog.register(ServiceSignature{Name: "web", Env: "prod"})
+			p.priority.countSignature(now.Add(time.Duration(i)*bucketDuration), root, sig, 0)
+		}
+	}
+	rate := dynConf.RateByService.GetRate("web", "prod")
+	assert.InDelta(t, 0.05, rate, 0.001)
+	assert.True(t, p.rare.Sample(now, getTraceChunkWithSpanAndPriority(root, PriorityNone), "prod"))
+	require.NoError(t, p.persist())
+
+	restored, restoredDynConf := newTestStatePersister(path, now.Add(time.Minute))
+	require.NoError(t, restored.restore())
+	assert.Equal(t, rate, restoredDynConf.RateByService.GetRate("web", "prod"))
+	// the span is still in its cooldown period
+	assert.False(t, restored.rare.Sample(now.Add(time.Minute), getTraceChunkWithSpanAndPriority(root, PriorityNone), "prod"))
+
+	// without traffic, the restored rate increases progressively
+	restored.priority.countSignature(now.Add(2*time.Minute), root, restored.priority.catalog.register(ServiceSignature{Name: "api", Env: "prod"}), 0)
+	assert.InDelta(t, rate*maxRateIncrease, restoredDynConf.RateByService.GetRate("web", "prod"), 0.001)
+}
+
+func TestStatePersisterRestoreErrors(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Unix(1767225600, 0)
+
+	t.Run("missing", func(t *testing.T) {
+		p, _ := newTestStatePersister(filepath.Join(dir, "missing.json"), now)
+		assert.True(t, os.IsNotExist(p.restore()))
+	})
+
+	t.Run("too-old", func(t *testing.T) {
+		path := filepath.Join(dir, "old.json")
+		p, _ := newTestStatePersister(path, now)
+		require.NoError(t, p.persist())
+
+		p.now = func() time.Time { return now.Add(p.maxAge + time.Second) }
+		assert.ErrorContains(t, p.restore(), "too old")
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		path := filepath.Join(dir, "malformed.json")
+		require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+		p, _ := newTestStatePersister(path, now)
+		assert.ErrorContains(t, p.restore(), "malformed")
+	})
+}
+
+func TestStatePersisterStartStop(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	p, _ := newTestStatePersister(path, time.Now())
+	p.Start()
+	p.Stop()
+	assert.FileExists(t, path)
+
+	disabled := NewStatePersister(config.New(), nil, nil)
+	disabled.Start()
+	disabled.Stop()
+}
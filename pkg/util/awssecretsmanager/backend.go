@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Package awssecretsmanager implements the aws.secrets_manager secret backend, it's registered by importing it
+package awssecretsmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+
+	"github.com/DataDog/datadog-agent/comp/core/secrets/secretsimpl"
+)
+
+// BackendType is the secret_backend_type of the backend
+const BackendType = "aws.secrets_manager"
+
+func init() {
+	secretsimpl.RegisterBackend(BackendType, newBackend)
+}
+
+// config is the secret_backend_config of the backend
+type config struct {
+	Region   string `json:"region"`
+	Endpoint string `json:"endpoint"`
+}
+
+// backend reads the secrets from AWS Secrets Manager. The secret IDs are the name or the ARN of the secrets.
+//
+// The region and the credentials are loaded by the default configuration of the AWS SDK: from the environment
+// variables, the shared configuration files, the web identity token of the EKS service account, the ECS
+// container credentials or the EC2 instance profile.
+type backend struct {
+	client *secretsmanager.Client
+}
+
+func newBackend(rawConfig map[string]interface{}, timeout time.Duration) (secretsimpl.Backend, error) {
+	var cfg config
+	raw, err := json.Marshal(rawConfig)
+	if err == nil {
+		err = json.Unmarshal(raw, &cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret_backend_config: %s", err)
+	}
+
+	options := []func(*awsconfig.LoadOptions) error{
+		awsconfig.WithHTTPClient(&http.Client{Timeout: timeout}),
+	}
+	if cfg.Region != "" {
+		options = append(options, awsconfig.WithRegion(cfg.Region))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("could not load the AWS configuration: %s", err)
+	}
+	if awsCfg.Region == "" {
+		return nil, errors.New("the aws.secrets_manager secret backend requires a region")
+	}
+
+	client := secretsmanager.NewFromConfig(awsCfg, func(o *secretsmanager.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+	})
+	return &backend{client: client}, nil
+}
+
+// FetchSecret returns the value of the secret, binary secrets are returned as is
+func (b *backend) FetchSecret(ctx context.Context, id string) (string, error) {
+	output, err := b.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(id)})
+	if err != nil {
+		return "", fmt.Errorf("could not read AWS secret '%s': %s", id, err)
+	}
+	if output.SecretString != nil {
+		return *output.SecretString, nil
+	}
+	return string(output.SecretBinary), nil
+}
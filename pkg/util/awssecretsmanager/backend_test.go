@@ -0,0 +1,85 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package awssecretsmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setAWSEnv(t *testing.T) {
+	for _, env := range []string{
+		"AWS_REGION", "AWS_DEFAULT_REGION", "AWS_PROFILE", "AWS_WEB_IDENTITY_TOKEN_FILE", "AWS_ROLE_ARN",
+		"AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "AWS_CONTAINER_CREDENTIALS_FULL_URI",
+	} {
+		t.Setenv(env, "")
+	}
+	t.Setenv("AWS_CONFIG_FILE", "/nonexistent")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/nonexistent")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+}
+
+func TestNewBackend(t *testing.T) {
+	setAWSEnv(t)
+
+	_, err := newBackend(map[string]interface{}{}, time.Second)
+	assert.EqualError(t, err, "the aws.secrets_manager secret backend requires a region")
+
+	t.Setenv("AWS_DEFAULT_REGION", "eu-west-1")
+	_, err = newBackend(map[string]interface{}{}, time.Second)
+	assert.NoError(t, err)
+}
+
+func TestFetchSecret(t *testing.T) {
+	setAWSEnv(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/us-east-1/secretsmanager/aws4_request")
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		switch body["SecretId"] {
+		case "datadog":
+			fmt.Fprint(w, `{"SecretString": "{\"api_key\": \"123456\"}"}`)
+		case "plain":
+			fmt.Fprint(w, `{"SecretBinary": "cGFzc3dvcmQ="}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"__type": "ResourceNotFoundException", "message": "not found"}`)
+		}
+	}))
+	defer server.Close()
+
+	b, err := newBackend(map[string]interface{}{"region": "us-east-1", "endpoint": server.URL}, 5*time.Second)
+	require.NoError(t, err)
+
+	secret, err := b.FetchSecret(context.Background(), "datadog")
+	require.NoError(t, err)
+	assert.Equal(t, `{"api_key": "123456"}`, secret)
+
+	secret, err = b.FetchSecret(context.Background(), "plain")
+	require.NoError(t, err)
+	assert.Equal(t, "password", secret)
+
+	_, err = b.FetchSecret(context.Background(), "missing")
+	assert.ErrorContains(t, err, "could not read AWS secret 'missing'")
+	assert.ErrorContains(t, err, "ResourceNotFoundException")
+}
@@ -25,10 +25,11 @@ Client to query the Datadog Cluster Level Check Runner API.
 */
 
 const (
-	clcRunnerPath        = "api/v1/clcrunner"
-	clcRunnerVersionPath = "version"
-	clcRunnerStatsPath   = "stats"
-	clcRunnerWorkersPath = "workers"
+	clcRunnerPath          = "api/v1/clcrunner"
+	clcRunnerVersionPath   = "version"
+	clcRunnerStatsPath     = "stats"
+	clcRunnerWorkersPath   = "workers"
+	clcRunnerResourcesPath = "resources"
 )
 
 var globalCLCRunnerClient *CLCRunnerClient
@@ -38,6 +39,7 @@ type CLCRunnerClientInterface interface {
 	GetVersion(IP string) (version.Version, error)
 	GetRunnerStats(IP string) (types.CLCRunnersStats, error)
 	GetRunnerWorkers(IP string) (types.Workers, error)
+	GetRunnerResources(IP string) (types.RunnerResources, error)
 }
 
 // CLCRunnerClient is required to query the API of Datadog Cluster Level Check Runner
@@ -178,6 +180,38 @@ func (c *CLCRunnerClient) GetRunnerWorkers(IP string) (types.Workers, error) {
 	return workers, err
 }
 
+// GetRunnerResources fetches the resource usage exposed by the Cluster Level Check Runner
+func (c *CLCRunnerClient) GetRunnerResources(IP string) (types.RunnerResources, error) {
+	var resources types.RunnerResources
+
+	rawURL := fmt.Sprintf("https://%s:%d/%s/%s", IP, c.clcRunnerPort, clcRunnerPath, clcRunnerResourcesPath)
+
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return resources, err
+	}
+	req.Header = c.clcRunnerAPIRequestHeaders
+
+	resp, err := c.clcRunnerAPIClient.Do(req)
+	if err != nil {
+		return resources, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return resources, fmt.Errorf("unexpected status code from CLC runner: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resources, err
+	}
+
+	err = json.Unmarshal(body, &resources)
+
+	return resources, err
+}
+
 // init globalCLCRunnerClient
 func init() {
 	globalCLCRunnerClient = &CLCRunnerClient{}
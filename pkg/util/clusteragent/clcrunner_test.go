@@ -46,9 +46,10 @@ func newDummyCLCRunner() (*dummyCLCRunner, error) {
 	resetGlobalCLCRunnerClient()
 	clcRunner := &dummyCLCRunner{
 		rawResponses: map[string]string{
-			"/api/v1/clcrunner/version": `{"Major":0, "Minor":0, "Patch":0, "Pre":"test", "Meta":"test", "Commit":"1337"}`,
-			"/api/v1/clcrunner/stats":   `{"http_check:My Nginx Service:b0041608e66d20ba":{"AverageExecutionTime":241,"MetricSamples":3},"kube_apiserver_metrics:c5d2d20ccb4bb880":{"AverageExecutionTime":858,"MetricSamples":1562},"":{"AverageExecutionTime":100,"MetricSamples":10}}`,
-			"/api/v1/clcrunner/workers": `{"Count":2,"Instances":{"worker_1":{"Utilization":0.1},"worker_2":{"Utilization":0.2}}}`,
+			"/api/v1/clcrunner/version":   `{"Major":0, "Minor":0, "Patch":0, "Pre":"test", "Meta":"test", "Commit":"1337"}`,
+			"/api/v1/clcrunner/stats":     `{"http_check:My Nginx Service:b0041608e66d20ba":{"AverageExecutionTime":241,"MetricSamples":3},"kube_apiserver_metrics:c5d2d20ccb4bb880":{"AverageExecutionTime":858,"MetricSamples":1562},"":{"AverageExecutionTime":100,"MetricSamples":10}}`,
+			"/api/v1/clcrunner/workers":   `{"Count":2,"Instances":{"worker_1":{"Utilization":0.1},"worker_2":{"Utilization":0.2}}}`,
+			"/api/v1/clcrunner/resources": `{"CPUUsage":0.75,"MemoryUsage":268435456}`,
 		},
 		token:    pkgconfigsetup.Datadog().GetString("cluster_agent.auth_token"),
 		requests: make(chan *http.Request, 100),
@@ -221,6 +222,31 @@ func (suite *clcRunnerSuite) TestGetRunnerWorkers() {
 	})
 }
 
+func (suite *clcRunnerSuite) TestGetRunnerResources() {
+	clcRunner, err := newDummyCLCRunner()
+	require.NoError(suite.T(), err)
+
+	ts, p, err := clcRunner.StartTLS()
+	require.NoError(suite.T(), err)
+	defer ts.Close()
+
+	c, err := GetCLCRunnerClient()
+	require.NoError(suite.T(), err)
+
+	c.(*CLCRunnerClient).clcRunnerPort = p
+
+	expected := types.RunnerResources{
+		CPUUsage:    0.75,
+		MemoryUsage: 256 * 1024 * 1024,
+	}
+
+	suite.T().Run("", func(t *testing.T) {
+		resources, err := c.GetRunnerResources("127.0.0.1")
+		require.NoError(suite.T(), err)
+		assert.Equal(t, expected, resources)
+	})
+}
+
 func TestCLCRunnerSuite(t *testing.T) {
 	clcRunnerAuthTokenFilename := "cluster_agent.auth_token"
 
@@ -14,6 +14,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
@@ -40,6 +41,7 @@ const (
 	// RealIPHeader refers to the cluster level check runner ip passed in the request headers
 	RealIPHeader          = "X-Real-Ip"
 	languageDetectionPath = "api/v1/languagedetection"
+	securityProfilesPath  = "api/v1/security/profiles"
 )
 
 var globalClusterAgentClient *DCAClient
@@ -74,6 +76,9 @@ type DCAClientInterface interface {
 
 	PostLanguageMetadata(ctx context.Context, data *pbgo.ParentLanguageAnnotationRequest) error
 	SupportsNamespaceMetadataCollection() bool
+
+	PostSecurityProfile(ctx context.Context, data []byte) error
+	GetSecurityProfile(ctx context.Context, imageName string) ([]byte, error)
 }
 
 // DCAClient is required to query the API of Datadog cluster agent
@@ -440,6 +445,21 @@ func (c *DCAClient) PostLanguageMetadata(ctx context.Context, data *pbgo.ParentL
 	return err
 }
 
+// PostSecurityProfile is called by the runtime security agent to share a stable security profile, encoded in
+// protobuf, with the other nodes of the cluster
+func (c *DCAClient) PostSecurityProfile(ctx context.Context, data []byte) error {
+	// query https://host:port/api/v1/security/profiles without expecting a response
+	_, err := c.doQuery(ctx, securityProfilesPath, "POST", bytes.NewBuffer(data), false, false)
+	return err
+}
+
+// GetSecurityProfile returns the protobuf encoded security profile shared by another node of the cluster for the
+// given image name
+func (c *DCAClient) GetSecurityProfile(ctx context.Context, imageName string) ([]byte, error) {
+	query := url.Values{"image_name": []string{imageName}}
+	return c.doQuery(ctx, securityProfilesPath+"?"+query.Encode(), "GET", nil, true, false)
+}
+
 // SupportsNamespaceMetadataCollection returns true only if the cluster agent supports collecting namespace metadata
 func (c *DCAClient) SupportsNamespaceMetadataCollection() bool {
 	dcaVersion := c.Version(false)
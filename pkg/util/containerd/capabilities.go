@@ -0,0 +1,34 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build containerd
+
+package containerd
+
+import (
+	"context"
+
+	"github.com/DataDog/datadog-agent/pkg/util/containers/capabilities"
+)
+
+func init() {
+	capabilities.RegisterProber("containerd", probe)
+}
+
+func probe(_ context.Context) (string, []capabilities.Capability, error) {
+	client, err := NewContainerdUtil()
+	if err != nil {
+		return "", nil, err
+	}
+	defer client.Close()
+
+	v, err := client.Metadata()
+	if err != nil {
+		return "", nil, err
+	}
+
+	// the agent doesn't run commands in the containerd tasks
+	return v.Version, []capabilities.Capability{capabilities.Stats, capabilities.Events}, nil
+}
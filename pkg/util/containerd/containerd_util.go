@@ -24,16 +24,20 @@ import (
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
 	dderrors "github.com/DataDog/datadog-agent/pkg/errors"
 	"github.com/DataDog/datadog-agent/pkg/util/containers/image"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/registryauth"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/retry"
 
 	"github.com/containerd/containerd"
 	"github.com/containerd/containerd/api/types"
 	"github.com/containerd/containerd/containers"
+	"github.com/containerd/containerd/images"
 	"github.com/containerd/containerd/leases"
 	"github.com/containerd/containerd/mount"
 	"github.com/containerd/containerd/namespaces"
 	"github.com/containerd/containerd/oci"
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
 	"github.com/containerd/errdefs"
 )
 
@@ -78,6 +82,10 @@ type ContainerdItf interface {
 	Mounts(ctx context.Context, expiration time.Duration, namespace string, img containerd.Image) ([]mount.Mount, error)
 }
 
+// registryPullNamespace is the namespace in which the content of the images retrieved from their
+// registry with the agent credentials is stored
+const registryPullNamespace = "datadog-agent"
+
 // ContainerdUtil is the util used to interact with the Containerd api.
 type ContainerdUtil struct {
 	cl                *containerd.Client
@@ -85,6 +93,7 @@ type ContainerdUtil struct {
 	initRetry         retry.Retrier
 	queryTimeout      time.Duration
 	connectionTimeout time.Duration
+	registryAuth      *registryauth.Chain
 }
 
 // NewContainerdUtil creates the Containerd util containing the Containerd client and implementing the ContainerdItf
@@ -102,6 +111,11 @@ func NewContainerdUtil() (ContainerdItf, error) {
 		log.Info("No socket path was specified, defaulting to /var/run/containerd/containerd.sock")
 		containerdUtil.socketPath = containerdDefaultSocketPath
 	}
+	registryAuth, err := registryauth.NewChainFromConfig(pkgconfigsetup.Datadog())
+	if err != nil {
+		log.Errorf("Images content won't be retrieved from their registry: %v", err)
+	}
+	containerdUtil.registryAuth = registryAuth
 	// Initialize the client in the connect method
 	containerdUtil.initRetry.SetupRetrier(&retry.Config{ //nolint:errcheck
 		Name:              "containerdutil",
@@ -400,13 +414,30 @@ func (c *ContainerdUtil) getMounts(ctx context.Context, expiration time.Duration
 	if err != nil {
 		return nil, nil, fmt.Errorf("unable to check if image named: %s is unpacked, err: %w", img.Name(), err)
 	}
+	removePrivateImage := func(context.Context) {}
 	if !imgUnpacked {
-		return nil, nil, fmt.Errorf("unable to scan image named: %s, image is not unpacked", img.Name())
+		if c.registryAuth.Empty() {
+			return nil, nil, fmt.Errorf("unable to scan image named: %s, image is not unpacked", img.Name())
+		}
+		// the content retrieved with the agent credentials is kept in a namespace of the agent, so that it
+		// isn't made available to the users of the namespace of the image who may not be allowed to pull it
+		privateImg, err := c.fetchAndUnpack(ctx, img, snapshotter)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to scan image named: %s, image is not unpacked and cannot be retrieved from its registry: %w", img.Name(), err)
+		}
+		namespace, img = registryPullNamespace, privateImg
+		ctx = namespaces.WithNamespace(ctx, namespace)
+		removePrivateImage = func(ctx context.Context) {
+			if err := c.cl.ImageService().Delete(namespaces.WithNamespace(ctx, registryPullNamespace), img.Name()); err != nil && !errdefs.IsNotFound(err) {
+				log.Warnf("Unable to delete image %s from namespace %s, err: %v", img.Name(), registryPullNamespace, err)
+			}
+		}
 	}
 
 	// Getting image id
 	imgConfig, err := img.Config(ctx)
 	if err != nil {
+		removePrivateImage(ctx)
 		return nil, nil, fmt.Errorf("unable to get image config for image named: %s, err: %w", img.Name(), err)
 	}
 	imageID := imgConfig.Digest.String()
@@ -420,6 +451,7 @@ func (c *ContainerdUtil) getMounts(ctx context.Context, expiration time.Duration
 		}),
 	)
 	if err != nil && !errdefs.IsAlreadyExists(err) {
+		removePrivateImage(ctx)
 		return nil, nil, fmt.Errorf("unable to get a lease, err: %w", err)
 	}
 
@@ -429,6 +461,7 @@ func (c *ContainerdUtil) getMounts(ctx context.Context, expiration time.Duration
 		if err := done(ctx); err != nil {
 			log.Warnf("Unable to cancel containerd lease with id: %s, err: %v", imageID, err)
 		}
+		removePrivateImage(ctx)
 		return nil, nil, fmt.Errorf("unable to get layers digests for image: %s, err: %w", imageID, err)
 	}
 	chainID := identity.ChainID(diffIDs).String()
@@ -439,6 +472,7 @@ func (c *ContainerdUtil) getMounts(ctx context.Context, expiration time.Duration
 		if err := done(ctx); err != nil {
 			log.Warnf("Unable to cancel containerd lease with id: %s, err: %v", imageID, err)
 		}
+		removePrivateImage(ctx)
 		return nil, nil, fmt.Errorf("unable to build snapshot for image: %s, err: %w", imageID, err)
 	}
 	cleanSnapshot := func(ctx context.Context) error {
@@ -453,6 +487,7 @@ func (c *ContainerdUtil) getMounts(ctx context.Context, expiration time.Duration
 		if err := done(ctx); err != nil {
 			log.Warnf("Unable to cancel containerd lease with id: %s, err: %v", imageID, err)
 		}
+		removePrivateImage(ctx)
 		return nil, nil, fmt.Errorf("No snapshots returned for image: %s", imageID)
 	}
 
@@ -493,10 +528,66 @@ func (c *ContainerdUtil) getMounts(ctx context.Context, expiration time.Duration
 		if err := done(ctx); err != nil {
 			log.Warnf("Unable to cancel containerd lease with id: %s, err: %v", imageID, err)
 		}
+		removePrivateImage(ctx)
 		return nil
 	}, nil
 }
 
+// fetchAndUnpack retrieves the content of an image missing from the content store, e.g. when it
+// was garbage collected after the image was unpacked on another snapshotter, from its registry
+// with the configured registry credentials, and unpacks it. The content is retrieved in the
+// registryPullNamespace namespace and the returned image is the record created in it.
+func (c *ContainerdUtil) fetchAndUnpack(ctx context.Context, img containerd.Image, snapshotter string) (containerd.Image, error) {
+	ctx = namespaces.WithNamespace(ctx, registryPullNamespace)
+	ctx, done, err := c.cl.WithLease(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get a lease, err: %w", err)
+	}
+	defer func() {
+		if err := done(ctx); err != nil {
+			log.Warnf("Unable to cancel containerd lease for image %s, err: %v", img.Name(), err)
+		}
+	}()
+
+	resolver := docker.NewResolver(docker.ResolverOptions{
+		Hosts: docker.ConfigureDefaultRegistries(
+			docker.WithAuthorizer(docker.NewDockerAuthorizer(
+				docker.WithAuthCreds(c.registryAuth.HostCredentials(ctx, img.Name())),
+			)),
+		),
+	})
+	fetcher, err := resolver.Fetcher(ctx, img.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	// only the content of the target of the image for the current platform is retrieved
+	store := c.cl.ContentStore()
+	handler := images.Handlers(
+		remotes.FetchHandler(store, fetcher),
+		images.LimitManifests(images.FilterPlatforms(images.ChildrenHandler(store), img.Platform()), img.Platform(), 1),
+	)
+	if err := images.Dispatch(ctx, handler, nil, img.Target()); err != nil {
+		return nil, err
+	}
+
+	// the image record references the content so that it isn't garbage collected once the lease is released
+	record := images.Image{Name: img.Name(), Target: img.Target()}
+	created, err := c.cl.ImageService().Create(ctx, record)
+	if errdefs.IsAlreadyExists(err) {
+		created, err = c.cl.ImageService().Update(ctx, record, "target")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to create image record, err: %w", err)
+	}
+
+	privateImg := containerd.NewImageWithPlatform(c.cl, created, img.Platform())
+	if err := privateImg.Unpack(ctx, snapshotter); err != nil {
+		return nil, err
+	}
+	return privateImg, nil
+}
+
 // Mounts returns the mounts for an image
 func (c *ContainerdUtil) Mounts(ctx context.Context, expiration time.Duration, namespace string, img containerd.Image) ([]mount.Mount, error) {
 	mounts, clean, err := c.getMounts(ctx, expiration, namespace, img)
@@ -0,0 +1,172 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Package capabilities discovers the container runtimes available on the host,
+// and what the agent can collect from each of them, so that the reason why
+// container data is missing on a host can be seen at a glance.
+package capabilities
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config/env"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Capability is a kind of data the agent can collect from a runtime
+type Capability string
+
+const (
+	// Stats is the collection of the resource usage of the containers
+	Stats Capability = "stats"
+	// Events is the collection of the lifecycle events of the containers
+	Events Capability = "events"
+	// Exec is the execution of commands in the containers
+	Exec Capability = "exec"
+)
+
+const (
+	// probeTimeout bounds the probe of each runtime
+	probeTimeout = 5 * time.Second
+	// discoveryTTL is how long the discovered runtimes are cached, so that
+	// the runtimes started, restarted or upgraded afterwards are reported
+	discoveryTTL = 10 * time.Minute
+)
+
+// Runtime is the result of the probe of a container runtime
+type Runtime struct {
+	Name string `json:"name"`
+	// Detected is true if the runtime was detected by the environment detection
+	Detected bool `json:"detected"`
+	// Available is true if the agent could connect to the runtime
+	Available    bool         `json:"available"`
+	Version      string       `json:"version,omitempty"`
+	Capabilities []Capability `json:"capabilities"`
+	Error        string       `json:"error,omitempty"`
+}
+
+// Prober connects to a runtime, and returns its version and the capabilities
+// the agent can use
+type Prober func(ctx context.Context) (version string, capabilities []Capability, err error)
+
+// runtimes are the runtimes to probe, in the order they are reported
+var runtimes = []struct {
+	name    string
+	feature env.Feature
+}{
+	{"docker", env.Docker},
+	{"containerd", env.Containerd},
+	{"cri", env.Cri},
+	{"podman", env.Podman},
+}
+
+var (
+	probersMutex sync.RWMutex
+	probers      = map[string]Prober{}
+
+	// discoverLock serializes the discoveries, it is a channel so that the
+	// callers waiting for the discovery in progress honor their context
+	discoverLock  = make(chan struct{}, 1)
+	lastDiscovery atomic.Pointer[discovery]
+)
+
+// discovery is the result of a discovery of the runtimes
+type discovery struct {
+	runtimes []Runtime
+	at       time.Time
+}
+
+// RegisterProber registers the prober of a runtime. The probers are registered
+// by the packages of the runtimes built in the agent.
+func RegisterProber(name string, prober Prober) {
+	probersMutex.Lock()
+	defer probersMutex.Unlock()
+	probers[name] = prober
+}
+
+func getProber(name string) (Prober, bool) {
+	probersMutex.RLock()
+	defer probersMutex.RUnlock()
+	prober, ok := probers[name]
+	return prober, ok
+}
+
+// Discover probes the runtimes detected on the host
+func Discover(ctx context.Context) []Runtime {
+	result := make([]Runtime, 0, len(runtimes))
+	for _, r := range runtimes {
+		runtime := Runtime{
+			Name:         r.name,
+			Detected:     env.IsFeaturePresent(r.feature),
+			Capabilities: []Capability{},
+		}
+
+		prober, ok := getProber(r.name)
+		switch {
+		case !ok:
+			runtime.Error = "not supported by this build"
+		case !runtime.Detected:
+			runtime.Error = "not detected on this host"
+		default:
+			probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+			version, capabilities, err := prober(probeCtx)
+			cancel()
+			if err != nil {
+				runtime.Error = err.Error()
+				log.Infof("Container runtime %s was detected but is not available: %v", r.name, err)
+				break
+			}
+			runtime.Available = true
+			runtime.Version = version
+			if capabilities != nil {
+				runtime.Capabilities = capabilities
+			}
+		}
+
+		result = append(result, runtime)
+	}
+	return result
+}
+
+// Get returns the runtimes of the host. They are discovered on the first call,
+// and again once the previous discovery is older than discoveryTTL. If the
+// context is done before the runtimes are discovered, the ones of the previous
+// discovery are returned, if any.
+func Get(ctx context.Context) []Runtime {
+	last := lastDiscovery.Load()
+	select {
+	case discoverLock <- struct{}{}:
+	case <-ctx.Done():
+		return last.get()
+	}
+	defer func() { <-discoverLock }()
+
+	last = lastDiscovery.Load()
+	if last != nil && time.Since(last.at) < discoveryTTL {
+		return last.runtimes
+	}
+
+	runtimes := Discover(ctx)
+	if ctx.Err() != nil {
+		// the probes were interrupted, their errors don't describe the runtimes
+		// and aren't cached
+		if last != nil {
+			return last.runtimes
+		}
+		return runtimes
+	}
+	lastDiscovery.Store(&discovery{runtimes: runtimes, at: time.Now()})
+	return runtimes
+}
+
+func (d *discovery) get() []Runtime {
+	if d == nil {
+		return nil
+	}
+	return d.runtimes
+}
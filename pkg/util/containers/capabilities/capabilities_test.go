@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build test
+
+package capabilities
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/config/env"
+)
+
+func TestDiscover(t *testing.T) {
+	env.SetFeatures(t, env.Docker, env.Containerd, env.Podman)
+	t.Cleanup(func() { probers = map[string]Prober{} })
+
+	RegisterProber("docker", func(context.Context) (string, []Capability, error) {
+		return "27.3.1", []Capability{Stats, Events, Exec}, nil
+	})
+	RegisterProber("containerd", func(context.Context) (string, []Capability, error) {
+		return "", nil, errors.New("permission denied")
+	})
+	RegisterProber("cri", func(context.Context) (string, []Capability, error) {
+		t.Error("the runtimes which weren't detected mustn't be probed")
+		return "", nil, nil
+	})
+
+	assert.Equal(t, []Runtime{
+		{Name: "docker", Detected: true, Available: true, Version: "27.3.1", Capabilities: []Capability{Stats, Events, Exec}},
+		{Name: "containerd", Detected: true, Capabilities: []Capability{}, Error: "permission denied"},
+		{Name: "cri", Capabilities: []Capability{}, Error: "not detected on this host"},
+		{Name: "podman", Detected: true, Capabilities: []Capability{}, Error: "not supported by this build"},
+	}, Discover(context.Background()))
+}
+
+func TestGet(t *testing.T) {
+	env.SetFeatures(t, env.Docker)
+	t.Cleanup(func() {
+		probers = map[string]Prober{}
+		lastDiscovery.Store(nil)
+	})
+
+	version := "27.3.1"
+	probes := 0
+	RegisterProber("docker", func(ctx context.Context) (string, []Capability, error) {
+		probes++
+		return version, nil, ctx.Err()
+	})
+
+	assert.Equal(t, "27.3.1", Get(context.Background())[0].Version)
+	assert.Equal(t, "27.3.1", Get(context.Background())[0].Version)
+	assert.Equal(t, 1, probes, "the runtimes are cached")
+
+	// the runtimes are discovered again once the cache expires
+	version = "28.0.0"
+	lastDiscovery.Load().at = time.Now().Add(-discoveryTTL)
+	assert.Equal(t, "28.0.0", Get(context.Background())[0].Version)
+	assert.Equal(t, 2, probes)
+
+	// an interrupted discovery isn't cached
+	lastDiscovery.Load().at = time.Now().Add(-discoveryTTL)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	runtimes := Get(ctx)
+	assert.Equal(t, "28.0.0", runtimes[0].Version)
+	assert.True(t, runtimes[0].Available)
+	assert.GreaterOrEqual(t, time.Since(lastDiscovery.Load().at), discoveryTTL)
+}
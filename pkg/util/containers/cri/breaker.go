@@ -0,0 +1,126 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+
+package cri
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// breakerInterval is the collection interval the circuit breaker skips the queries for
+const breakerInterval = 15 * time.Second
+
+// ErrCircuitOpen is returned by the queries skipped because the runtime timed out repeatedly
+var ErrCircuitOpen = errors.New("CRI queries are skipped after consecutive timeouts")
+
+type breakerStateValue int
+
+const (
+	breakerClosed breakerStateValue = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker stops querying an overloaded runtime, whose queries would
+// block their caller until they time out. Once threshold consecutive queries
+// timed out, the queries are skipped for openDuration. A single query is then
+// let through as a probe, the other ones being skipped until it completes:
+// the breaker closes if it succeeds, and opens again otherwise.
+type circuitBreaker struct {
+	socketPath   string
+	threshold    int
+	openDuration time.Duration
+
+	sync.Mutex
+	state               breakerStateValue
+	consecutiveTimeouts int
+	openUntil           time.Time
+	// probeUntil bounds the wait for the outcome of the probe, in case its
+	// query never completes (e.g. the connection couldn't be established)
+	probeUntil time.Time
+}
+
+// newCircuitBreaker returns a circuit breaker, or nil if threshold is not positive
+func newCircuitBreaker(socketPath string, threshold int, openDuration time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+
+	breakerState.Set(float64(breakerClosed), socketPath)
+	return &circuitBreaker{
+		socketPath:   socketPath,
+		threshold:    threshold,
+		openDuration: openDuration,
+	}
+}
+
+// allow returns whether a query can be sent to the runtime
+func (b *circuitBreaker) allow() bool {
+	if b == nil {
+		return true
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	now := time.Now()
+	switch b.state {
+	case breakerOpen:
+		if now.Before(b.openUntil) {
+			breakerSkippedQueries.Inc(b.socketPath)
+			return false
+		}
+		b.setState(breakerHalfOpen)
+	case breakerHalfOpen:
+		// a probe is in flight
+		if now.Before(b.probeUntil) {
+			breakerSkippedQueries.Inc(b.socketPath)
+			return false
+		}
+	default:
+		return true
+	}
+
+	b.probeUntil = now.Add(b.openDuration)
+	return true
+}
+
+// record updates the breaker with the outcome of a query
+func (b *circuitBreaker) record(timedOut bool) {
+	if b == nil {
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	if !timedOut {
+		b.consecutiveTimeouts = 0
+		if b.state != breakerClosed {
+			log.Infof("CRI socket %s is responsive again, resuming the queries", b.socketPath)
+			b.setState(breakerClosed)
+		}
+		return
+	}
+
+	b.consecutiveTimeouts++
+	if b.state == breakerHalfOpen || (b.state == breakerClosed && b.consecutiveTimeouts >= b.threshold) {
+		log.Warnf("%d consecutive queries to CRI socket %s timed out, skipping the queries for %s", b.consecutiveTimeouts, b.socketPath, b.openDuration)
+		b.openUntil = time.Now().Add(b.openDuration)
+		b.setState(breakerOpen)
+	}
+}
+
+// setState updates the state of the breaker. The caller must hold the lock.
+func (b *circuitBreaker) setState(state breakerStateValue) {
+	b.state = state
+	breakerState.Set(float64(state), b.socketPath)
+}
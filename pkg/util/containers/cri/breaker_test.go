@@ -0,0 +1,134 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+
+package cri
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	assert.Nil(t, newCircuitBreaker("disabled.sock", 0, time.Minute))
+	var disabled *circuitBreaker
+	disabled.record(true)
+	assert.True(t, disabled.allow())
+
+	breaker := newCircuitBreaker("test.sock", 2, 50*time.Millisecond)
+
+	breaker.record(true)
+	breaker.record(false)
+	breaker.record(true)
+	assert.True(t, breaker.allow(), "the timeouts must be consecutive")
+
+	breaker.record(true)
+	assert.False(t, breaker.allow())
+	assert.Equal(t, float64(breakerOpen), breakerState.WithValues("test.sock").Get())
+
+	// a single query is let through once the breaker was open long enough
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, breaker.allow())
+	assert.Equal(t, float64(breakerHalfOpen), breakerState.WithValues("test.sock").Get())
+	assert.False(t, breaker.allow(), "only one probe can be in flight")
+	breaker.record(true)
+	assert.False(t, breaker.allow())
+
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, breaker.allow())
+	breaker.record(false)
+	assert.True(t, breaker.allow())
+	assert.Equal(t, float64(breakerClosed), breakerState.WithValues("test.sock").Get())
+}
+
+func TestCircuitBreakerConcurrentProbes(t *testing.T) {
+	breaker := newCircuitBreaker("concurrent.sock", 1, 50*time.Millisecond)
+	breaker.record(true)
+	time.Sleep(100 * time.Millisecond)
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if breaker.allow() {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), allowed.Load())
+
+	// another probe is let through if the outcome of the first one is lost
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, breaker.allow())
+	assert.False(t, breaker.allow())
+	breaker.record(false)
+	assert.True(t, breaker.allow())
+}
+
+// slowRuntime is a CRI runtime which only answers the version queries in time
+type slowRuntime struct {
+	criv1.UnimplementedRuntimeServiceServer
+}
+
+func (slowRuntime) Version(context.Context, *criv1.VersionRequest) (*criv1.VersionResponse, error) {
+	return &criv1.VersionResponse{RuntimeName: "slow"}, nil
+}
+
+func (slowRuntime) ListContainerStats(ctx context.Context, _ *criv1.ListContainerStatsRequest) (*criv1.ListContainerStatsResponse, error) {
+	<-ctx.Done()
+	return nil, status.FromContextError(ctx.Err()).Err()
+}
+
+func (slowRuntime) ListContainers(context.Context, *criv1.ListContainersRequest) (*criv1.ListContainersResponse, error) {
+	return &criv1.ListContainersResponse{}, nil
+}
+
+func TestCRIUtilQueryTimeouts(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "cri.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	criv1.RegisterRuntimeServiceServer(server, &slowRuntime{})
+	go server.Serve(listener) //nolint:errcheck
+	defer server.Stop()
+
+	util := newTestCRIUtil(t, socketPath)
+	util.queryTimeout = 10 * time.Second
+	util.queryTimeouts = map[string]time.Duration{"list_container_stats": 50 * time.Millisecond}
+	util.breaker = newCircuitBreaker(socketPath, 2, time.Hour)
+	require.NoError(t, util.ready())
+
+	for i := 0; i < 2; i++ {
+		start := time.Now()
+		_, err = util.ListContainerStats()
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+		assert.Less(t, time.Since(start), 5*time.Second, "the query should time out with its own timeout")
+	}
+	assert.Equal(t, float64(2), queryTimeouts.WithValues(socketPath, "list_container_stats").Get())
+
+	// all the queries are skipped once the breaker is open
+	_, err = util.ListContainerStats()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	_, err = util.ListContainers()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, float64(2), breakerSkippedQueries.WithValues(socketPath).Get())
+}
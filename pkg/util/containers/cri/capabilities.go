@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build cri
+
+package cri
+
+import (
+	"context"
+
+	"github.com/DataDog/datadog-agent/pkg/util/containers/capabilities"
+)
+
+func init() {
+	capabilities.RegisterProber("cri", probe)
+}
+
+// probe connects to the CRI. The agent polls the CRI for the containers, it
+// doesn't use the container events of the runtime.
+func probe(_ context.Context) (string, []capabilities.Capability, error) {
+	util, err := GetUtil()
+	if err != nil {
+		return "", nil, err
+	}
+
+	version := util.GetRuntime() + " " + util.GetRuntimeVersion()
+
+	// some runtimes don't implement the stats of the CRI
+	var caps []capabilities.Capability
+	if _, err := util.ListContainerStats(); err == nil {
+		caps = append(caps, capabilities.Stats)
+	}
+
+	return version, caps, nil
+}
@@ -30,6 +30,41 @@ func (m *MockCRIClient) GetContainerStats(containerID string) (*criv1.ContainerS
 	return args.Get(0).(*criv1.ContainerStats), args.Error(1)
 }
 
+// ListPodSandboxStats sends a ListPodSandboxStatsRequest to the server, and parses the returned response
+func (m *MockCRIClient) ListPodSandboxStats() (map[string]*criv1.PodSandboxStats, error) {
+	args := m.Called()
+	return args.Get(0).(map[string]*criv1.PodSandboxStats), args.Error(1)
+}
+
+// ListContainers sends a ListContainersRequest to the server, and returns the containers known by the runtime
+func (m *MockCRIClient) ListContainers() ([]*criv1.Container, error) {
+	args := m.Called()
+	return args.Get(0).([]*criv1.Container), args.Error(1)
+}
+
+// GetContainerStatus returns the status of the container with the given ID
+func (m *MockCRIClient) GetContainerStatus(containerID string) (*criv1.ContainerStatus, error) {
+	args := m.Called(containerID)
+	return args.Get(0).(*criv1.ContainerStatus), args.Error(1)
+}
+
+// ListImages sends a ListImagesRequest to the server, and returns the images known by the runtime
+func (m *MockCRIClient) ListImages() ([]*criv1.Image, error) {
+	args := m.Called()
+	return args.Get(0).([]*criv1.Image), args.Error(1)
+}
+
+// ImageFsInfo returns the usage of the filesystems storing the images
+func (m *MockCRIClient) ImageFsInfo() (*criv1.ImageFsInfoResponse, error) {
+	args := m.Called()
+	return args.Get(0).(*criv1.ImageFsInfoResponse), args.Error(1)
+}
+
+// GetContainerRuntime is a mock of GetContainerRuntime
+func (m *MockCRIClient) GetContainerRuntime(string) string {
+	return m.GetRuntime()
+}
+
 // GetRuntime is a mock of GetRuntime
 func (m *MockCRIClient) GetRuntime() string {
 	return "fakeruntime"
@@ -0,0 +1,308 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+
+package cri
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var errNoReachableSocket = errors.New("none of the CRI sockets is reachable")
+
+// multiCRIUtil implements CRIClient on top of several CRI sockets, for nodes
+// running several runtimes (e.g. containerd and a sandboxed runtime exposing
+// its own socket). Sockets that can't be reached are ignored until their
+// connection succeeds, and the results of the reachable ones are merged.
+type multiCRIUtil struct {
+	utils []*CRIUtil
+
+	// runtime of the containers, by container ID, refreshed by ListContainers
+	// and completed by the stats queries
+	containerRuntimesLock sync.RWMutex
+	containerRuntimes     map[string]string
+}
+
+func newMultiCRIUtil(utils []*CRIUtil) *multiCRIUtil {
+	return &multiCRIUtil{
+		utils:             utils,
+		containerRuntimes: make(map[string]string),
+	}
+}
+
+// ready returns nil if at least one of the sockets is connected
+func (m *multiCRIUtil) ready() error {
+	var errs []error
+	for _, util := range m.utils {
+		err := util.ready()
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", util.socketPath, err))
+	}
+	return fmt.Errorf("%w: %w", errNoReachableSocket, errors.Join(errs...))
+}
+
+func (m *multiCRIUtil) stopStatsCaches() {
+	for _, util := range m.utils {
+		util.stopStatsCaches()
+	}
+}
+
+// readyUtils returns the CRIUtil of the sockets which are connected
+func (m *multiCRIUtil) readyUtils() []*CRIUtil {
+	utils := make([]*CRIUtil, 0, len(m.utils))
+	for _, util := range m.utils {
+		if err := util.ready(); err != nil {
+			log.Debugf("Skipping CRI socket %s: %s", util.socketPath, err)
+			continue
+		}
+		utils = append(utils, util)
+	}
+	return utils
+}
+
+// ListContainerStats returns the stats of the containers of all the runtimes
+func (m *multiCRIUtil) ListContainerStats() (map[string]*criv1.ContainerStats, error) {
+	return mergeResults(m.readyUtils(), func(util *CRIUtil) (map[string]*criv1.ContainerStats, error) {
+		stats, err := util.ListContainerStats()
+		if err != nil {
+			return nil, err
+		}
+
+		containerIDs := make([]string, 0, len(stats))
+		for containerID := range stats {
+			containerIDs = append(containerIDs, containerID)
+		}
+		m.recordContainerRuntimes(util.GetRuntime(), containerIDs)
+		return stats, nil
+	})
+}
+
+// GetContainerStats returns the stats for the container with the given ID
+func (m *multiCRIUtil) GetContainerStats(containerID string) (*criv1.ContainerStats, error) {
+	return findResult(m.readyUtils(), func(util *CRIUtil) (*criv1.ContainerStats, error) {
+		return util.GetContainerStats(containerID)
+	})
+}
+
+// ListPodSandboxStats returns the stats of the pod sandboxes of all the runtimes
+func (m *multiCRIUtil) ListPodSandboxStats() (map[string]*criv1.PodSandboxStats, error) {
+	return mergeResults(m.readyUtils(), func(util *CRIUtil) (map[string]*criv1.PodSandboxStats, error) {
+		stats, err := util.ListPodSandboxStats()
+		if err != nil {
+			return nil, err
+		}
+
+		var containerIDs []string
+		for _, podStats := range stats {
+			for _, containerStats := range podStats.GetLinux().GetContainers() {
+				containerIDs = append(containerIDs, containerStats.GetAttributes().GetId())
+			}
+		}
+		m.recordContainerRuntimes(util.GetRuntime(), containerIDs)
+		return stats, nil
+	})
+}
+
+// recordContainerRuntimes records the runtime of containers seen in the stats
+// of a socket, so that they can be tagged before ListContainers sees them.
+func (m *multiCRIUtil) recordContainerRuntimes(runtime string, containerIDs []string) {
+	m.containerRuntimesLock.Lock()
+	defer m.containerRuntimesLock.Unlock()
+
+	for _, containerID := range containerIDs {
+		if containerID != "" {
+			m.containerRuntimes[containerID] = runtime
+		}
+	}
+}
+
+// ListContainers returns the containers of all the runtimes. It only fails if
+// all the reachable sockets failed.
+func (m *multiCRIUtil) ListContainers() ([]*criv1.Container, error) {
+	utils := m.readyUtils()
+	if len(utils) == 0 {
+		return nil, errNoReachableSocket
+	}
+
+	var containers []*criv1.Container
+	var errs []error
+	containerRuntimes := make(map[string]string)
+	for _, util := range utils {
+		utilContainers, err := util.ListContainers()
+		if err != nil {
+			log.Debugf("Unable to list containers of CRI socket %s: %s", util.socketPath, err)
+			errs = append(errs, fmt.Errorf("%s: %w", util.socketPath, err))
+			continue
+		}
+
+		runtime := util.GetRuntime()
+		for _, container := range utilContainers {
+			containerRuntimes[container.GetId()] = runtime
+		}
+		containers = append(containers, utilContainers...)
+	}
+
+	if len(errs) == len(utils) {
+		return nil, errors.Join(errs...)
+	}
+
+	m.containerRuntimesLock.Lock()
+	m.containerRuntimes = containerRuntimes
+	m.containerRuntimesLock.Unlock()
+
+	return containers, nil
+}
+
+// GetContainerStatus returns the status of the container with the given ID
+func (m *multiCRIUtil) GetContainerStatus(containerID string) (*criv1.ContainerStatus, error) {
+	return findResult(m.readyUtils(), func(util *CRIUtil) (*criv1.ContainerStatus, error) {
+		return util.GetContainerStatus(containerID)
+	})
+}
+
+// GetContainerRuntime returns the CRI runtime running the container with the
+// given ID, as seen by the last queries. It falls back to the runtime of the
+// first reachable socket for unknown containers.
+func (m *multiCRIUtil) GetContainerRuntime(containerID string) string {
+	if runtime, found := m.LookupContainerRuntime(containerID); found {
+		return runtime
+	}
+	return m.GetRuntime()
+}
+
+// LookupContainerRuntime returns the CRI runtime running the container with
+// the given ID, and whether one of the sockets reported it.
+func (m *multiCRIUtil) LookupContainerRuntime(containerID string) (string, bool) {
+	m.containerRuntimesLock.RLock()
+	defer m.containerRuntimesLock.RUnlock()
+
+	runtime, found := m.containerRuntimes[containerID]
+	return runtime, found
+}
+
+// GetRuntime returns the CRI runtime of the first reachable socket
+func (m *multiCRIUtil) GetRuntime() string {
+	if utils := m.readyUtils(); len(utils) > 0 {
+		return utils[0].GetRuntime()
+	}
+	return ""
+}
+
+// GetRuntimeVersion returns the CRI runtime version of the first reachable socket
+func (m *multiCRIUtil) GetRuntimeVersion() string {
+	if utils := m.readyUtils(); len(utils) > 0 {
+		return utils[0].GetRuntimeVersion()
+	}
+	return ""
+}
+
+// ListImages returns the images of all the runtimes. Images stored by several
+// runtimes are only returned once.
+func (m *multiCRIUtil) ListImages() ([]*criv1.Image, error) {
+	imagesByID, err := mergeResults(m.readyUtils(), func(util *CRIUtil) (map[string]*criv1.Image, error) {
+		images, err := util.ListImages()
+		if err != nil {
+			return nil, err
+		}
+
+		imagesByID := make(map[string]*criv1.Image, len(images))
+		for _, image := range images {
+			imagesByID[image.GetId()] = image
+		}
+		return imagesByID, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]*criv1.Image, 0, len(imagesByID))
+	for _, image := range imagesByID {
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+// ImageFsInfo returns the usage of the image filesystems of all the runtimes.
+// It only fails if all the reachable sockets failed.
+func (m *multiCRIUtil) ImageFsInfo() (*criv1.ImageFsInfoResponse, error) {
+	utils := m.readyUtils()
+	if len(utils) == 0 {
+		return nil, errNoReachableSocket
+	}
+
+	merged := &criv1.ImageFsInfoResponse{}
+	var errs []error
+	for _, util := range utils {
+		info, err := util.ImageFsInfo()
+		if err != nil {
+			log.Debugf("Unable to get image filesystems of CRI socket %s: %s", util.socketPath, err)
+			errs = append(errs, fmt.Errorf("%s: %w", util.socketPath, err))
+			continue
+		}
+
+		merged.ImageFilesystems = append(merged.ImageFilesystems, info.GetImageFilesystems()...)
+		merged.ContainerFilesystems = append(merged.ContainerFilesystems, info.GetContainerFilesystems()...)
+	}
+
+	if len(errs) == len(utils) {
+		return nil, errors.Join(errs...)
+	}
+	return merged, nil
+}
+
+// mergeResults merges the results of list across the given sockets. It only
+// fails if list failed for all of them.
+func mergeResults[T any](utils []*CRIUtil, list func(*CRIUtil) (map[string]T, error)) (map[string]T, error) {
+	if len(utils) == 0 {
+		return nil, errNoReachableSocket
+	}
+
+	merged := make(map[string]T)
+	var errs []error
+	for _, util := range utils {
+		results, err := list(util)
+		if err != nil {
+			log.Debugf("Unable to query CRI socket %s: %s", util.socketPath, err)
+			errs = append(errs, fmt.Errorf("%s: %w", util.socketPath, err))
+			continue
+		}
+
+		for id, result := range results {
+			merged[id] = result
+		}
+	}
+
+	if len(errs) == len(utils) {
+		return nil, errors.Join(errs...)
+	}
+	return merged, nil
+}
+
+// findResult returns the first successful result of get across the given sockets
+func findResult[T any](utils []*CRIUtil, get func(*CRIUtil) (T, error)) (T, error) {
+	var zero T
+	if len(utils) == 0 {
+		return zero, errNoReachableSocket
+	}
+
+	var errs []error
+	for _, util := range utils {
+		result, err := get(util)
+		if err == nil {
+			return result, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", util.socketPath, err))
+	}
+	return zero, errors.Join(errs...)
+}
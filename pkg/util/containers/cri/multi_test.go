@@ -0,0 +1,183 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+
+package cri
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	fakeremote "github.com/DataDog/datadog-agent/internal/third_party/kubernetes/pkg/kubelet/cri/remote/fake"
+	"github.com/DataDog/datadog-agent/pkg/util/retry"
+)
+
+func newTestCRIUtil(t *testing.T, socketPath string) *CRIUtil {
+	util := &CRIUtil{
+		queryTimeout:      1 * time.Second,
+		connectionTimeout: 1 * time.Second,
+		socketPath:        socketPath,
+	}
+	require.NoError(t, util.initRetry.SetupRetrier(&retry.Config{
+		Name:              "criutil",
+		AttemptMethod:     util.init,
+		Strategy:          retry.Backoff,
+		InitialRetryDelay: 1 * time.Second,
+		MaxRetryDelay:     5 * time.Minute,
+	}))
+	return util
+}
+
+func createContainer(t *testing.T, fakeRuntime *fakeremote.RemoteRuntime, podName, containerName string) string {
+	sandboxConfig := &criv1.PodSandboxConfig{
+		Metadata: &criv1.PodSandboxMetadata{Name: podName, Uid: podName + "-uid", Namespace: "default"},
+	}
+	sandboxID, err := fakeRuntime.RuntimeService.RunPodSandbox(context.Background(), sandboxConfig, "")
+	require.NoError(t, err)
+
+	containerID, err := fakeRuntime.RuntimeService.CreateContainer(context.Background(), sandboxID, &criv1.ContainerConfig{
+		Metadata: &criv1.ContainerMetadata{Name: containerName},
+		Image:    &criv1.ImageSpec{Image: "busybox"},
+	}, sandboxConfig)
+	require.NoError(t, err)
+	return containerID
+}
+
+func TestMultiCRIUtil(t *testing.T) {
+	containerdRuntime, containerdEndpoint := createAndStartFakeRemoteRuntime(t)
+	defer containerdRuntime.Stop()
+	kataRuntime, kataEndpoint := createAndStartFakeRemoteRuntime(t)
+	defer kataRuntime.Stop()
+
+	containerd := newTestCRIUtil(t, strings.TrimPrefix(containerdEndpoint, "unix://"))
+	kata := newTestCRIUtil(t, strings.TrimPrefix(kataEndpoint, "unix://"))
+	unreachable := newTestCRIUtil(t, filepath.Join(t.TempDir(), "missing.sock"))
+
+	util := newMultiCRIUtil([]*CRIUtil{containerd, kata, unreachable})
+	require.NoError(t, util.ready())
+	require.NoError(t, kata.ready())
+
+	// both fake runtimes report the same name
+	containerd.runtime = "containerd"
+	kata.runtime = "kata"
+
+	appID := createContainer(t, containerdRuntime, "app", "app")
+	sandboxedID := createContainer(t, kataRuntime, "sandboxed", "sandboxed")
+
+	containers, err := util.ListContainers()
+	require.NoError(t, err)
+	ids := make([]string, 0, len(containers))
+	for _, container := range containers {
+		ids = append(ids, container.GetId())
+	}
+	assert.ElementsMatch(t, []string{appID, sandboxedID}, ids)
+
+	assert.Equal(t, "containerd", util.GetContainerRuntime(appID))
+	assert.Equal(t, "kata", util.GetContainerRuntime(sandboxedID))
+	assert.Equal(t, "containerd", util.GetContainerRuntime("unknown"))
+	assert.Equal(t, "containerd", util.GetRuntime())
+
+	status, err := util.GetContainerStatus(sandboxedID)
+	require.NoError(t, err)
+	assert.Equal(t, sandboxedID, status.GetId())
+
+	_, err = util.GetContainerStatus("unknown")
+	assert.Error(t, err)
+
+	containerdRuntime.RuntimeService.SetFakeContainerStats([]*criv1.ContainerStats{
+		{Attributes: &criv1.ContainerAttributes{Id: appID}},
+	})
+	kataRuntime.RuntimeService.SetFakeContainerStats([]*criv1.ContainerStats{
+		{Attributes: &criv1.ContainerAttributes{Id: sandboxedID}},
+	})
+
+	stats, err := util.ListContainerStats()
+	require.NoError(t, err)
+	assert.Len(t, stats, 2)
+	assert.Contains(t, stats, appID)
+	assert.Contains(t, stats, sandboxedID)
+
+	containerStats, err := util.GetContainerStats(sandboxedID)
+	require.NoError(t, err)
+	assert.Equal(t, sandboxedID, containerStats.GetAttributes().GetId())
+
+	containerdRuntime.ImageService.SetFakeImages([]string{"nginx:latest", "pause:3.9"})
+	kataRuntime.ImageService.SetFakeImages([]string{"busybox:latest", "pause:3.9"})
+
+	images, err := util.ListImages()
+	require.NoError(t, err)
+	imageIDs := make([]string, 0, len(images))
+	for _, image := range images {
+		imageIDs = append(imageIDs, image.GetId())
+	}
+	assert.ElementsMatch(t, []string{"nginx:latest", "busybox:latest", "pause:3.9"}, imageIDs)
+
+	containerdRuntime.ImageService.SetFakeFilesystemUsage([]*criv1.FilesystemUsage{
+		{FsId: &criv1.FilesystemIdentifier{Mountpoint: "/var/lib/containerd"}, UsedBytes: &criv1.UInt64Value{Value: 1000}},
+	})
+	kataRuntime.ImageService.SetFakeFilesystemUsage([]*criv1.FilesystemUsage{
+		{FsId: &criv1.FilesystemIdentifier{Mountpoint: "/var/lib/kata"}, UsedBytes: &criv1.UInt64Value{Value: 2000}},
+	})
+
+	info, err := util.ImageFsInfo()
+	require.NoError(t, err)
+	assert.Len(t, info.GetImageFilesystems(), 2)
+}
+
+func TestMultiCRIUtilNoReachableSocket(t *testing.T) {
+	util := newMultiCRIUtil([]*CRIUtil{
+		newTestCRIUtil(t, filepath.Join(t.TempDir(), "missing.sock")),
+	})
+
+	assert.ErrorIs(t, util.ready(), errNoReachableSocket)
+
+	_, err := util.ListContainers()
+	assert.ErrorIs(t, err, errNoReachableSocket)
+	_, err = util.ListContainerStats()
+	assert.ErrorIs(t, err, errNoReachableSocket)
+	assert.Empty(t, util.GetRuntime())
+}
+
+func TestMultiCRIUtilStatsRecordRuntime(t *testing.T) {
+	containerdRuntime, containerdEndpoint := createAndStartFakeRemoteRuntime(t)
+	defer containerdRuntime.Stop()
+	kataRuntime, kataEndpoint := createAndStartFakeRemoteRuntime(t)
+	defer kataRuntime.Stop()
+
+	containerd := newTestCRIUtil(t, strings.TrimPrefix(containerdEndpoint, "unix://"))
+	kata := newTestCRIUtil(t, strings.TrimPrefix(kataEndpoint, "unix://"))
+
+	util := newMultiCRIUtil([]*CRIUtil{containerd, kata})
+	require.NoError(t, containerd.ready())
+	require.NoError(t, kata.ready())
+
+	containerd.runtime = "containerd"
+	kata.runtime = "kata"
+
+	// the stats are queried before the containers are listed
+	sandboxedID := createContainer(t, kataRuntime, "sandboxed", "sandboxed")
+	kataRuntime.RuntimeService.SetFakeContainerStats([]*criv1.ContainerStats{
+		{Attributes: &criv1.ContainerAttributes{Id: sandboxedID}},
+	})
+	_, err := util.ListContainerStats()
+	require.NoError(t, err)
+
+	runtime, found := util.LookupContainerRuntime(sandboxedID)
+	assert.True(t, found)
+	assert.Equal(t, "kata", runtime)
+	assert.Equal(t, "kata", util.GetContainerRuntime(sandboxedID))
+
+	_, found = util.LookupContainerRuntime("unknown")
+	assert.False(t, found)
+	assert.Equal(t, "containerd", util.GetContainerRuntime("unknown"))
+}
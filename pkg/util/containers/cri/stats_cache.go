@@ -0,0 +1,284 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+
+package cri
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// statsCacheRefreshInterval is the maximum age of the cached container stats
+	statsCacheRefreshInterval = 10 * time.Second
+	// statsCacheMaxBackoff is the maximum delay between two subscriptions to the container events
+	statsCacheMaxBackoff = 1 * time.Minute
+	// statsCacheIdleTimeout is the delay after which the subscription is
+	// closed if the stats are no longer queried
+	statsCacheIdleTimeout = 3 * statsCacheRefreshInterval
+)
+
+// statsCache serves the container stats from a cache, which is still polled:
+// it's refreshed by listing the stats of all the containers with a single
+// ListContainerStats call once it's older than statsCacheRefreshInterval,
+// instead of querying the stats of each container. The CRI doesn't stream the
+// stats themselves, the container events streamed by the runtime (evented
+// PLEG) only add and remove the containers between two refreshes. The cache
+// is only used while subscribed to the events, the stats of each container are
+// queried otherwise, and for good if the runtime doesn't support them.
+//
+// The subscription runs in the background while the stats are queried, it is
+// closed once they haven't been queried for statsCacheIdleTimeout, or when
+// stop is called.
+type statsCache struct {
+	util *CRIUtil
+
+	sync.Mutex
+	// cancel stops the subscription routine, it is nil when it isn't running
+	cancel      context.CancelFunc
+	unsupported bool
+	lastQuery   time.Time
+	subscribed  bool
+	stats       map[string]*criv1.ContainerStats
+	lastRefresh time.Time
+}
+
+func newStatsCache(util *CRIUtil) *statsCache {
+	return &statsCache{util: util}
+}
+
+// start subscribes to the container events in the background, unless the
+// subscription is already running or the runtime doesn't support it
+func (s *statsCache) start() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.startLocked()
+}
+
+// startLocked starts the subscription routine. The caller must hold the lock.
+func (s *statsCache) startLocked() {
+	if s.cancel != nil || s.unsupported {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.lastQuery = time.Now()
+	go s.run(ctx)
+	go s.stopWhenIdle(ctx)
+}
+
+// stop closes the subscription and drops the cache
+func (s *statsCache) stop() {
+	if s == nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+	s.subscribed = false
+	s.stats = nil
+}
+
+// enabled returns whether the stats can be served from the cache. It records
+// the query, and starts the subscription again if it was closed while idle.
+func (s *statsCache) enabled() bool {
+	if s == nil {
+		return false
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.lastQuery = time.Now()
+	s.startLocked()
+	return s.subscribed
+}
+
+// stopWhenIdle stops the subscription once the stats are no longer queried
+func (s *statsCache) stopWhenIdle(ctx context.Context) {
+	ticker := time.NewTicker(statsCacheRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Lock()
+			idle := time.Since(s.lastQuery) >= statsCacheIdleTimeout
+			s.Unlock()
+
+			if idle {
+				log.Debugf("CRI container stats of socket %s are no longer queried, closing the container events stream", s.util.socketPath)
+				s.stop()
+				return
+			}
+		}
+	}
+}
+
+func (s *statsCache) run(ctx context.Context) {
+	var backoff time.Duration
+	for {
+		received, err := s.subscribe(ctx)
+		s.setSubscribed(false)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if status.Code(err) == codes.Unimplemented {
+			log.Infof("CRI runtime of socket %s doesn't stream container events, polling the container stats", s.util.socketPath)
+			s.setUnsupported()
+			return
+		}
+
+		if received {
+			backoff = 0
+		}
+		backoff = min(max(2*backoff, redialInitialBackoff), statsCacheMaxBackoff)
+		log.Debugf("CRI container events stream of socket %s closed, subscribing again in %s: %v", s.util.socketPath, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// subscribe consumes the container events until the stream is closed. It
+// returns whether any event was received.
+func (s *statsCache) subscribe(ctx context.Context) (bool, error) {
+	client, err := s.util.getClient()
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.GetContainerEvents(ctx, &criv1.GetEventsRequest{})
+	if err != nil {
+		return false, err
+	}
+	s.setSubscribed(true)
+
+	received := false
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return received, err
+		}
+		received = true
+		s.handleEvent(event)
+	}
+}
+
+// setUnsupported stops the subscription for good
+func (s *statsCache) setUnsupported() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.unsupported = true
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+func (s *statsCache) setSubscribed(subscribed bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.subscribed = subscribed
+	if !subscribed {
+		// events may be missed until the next subscription
+		s.stats = nil
+	}
+}
+
+// handleEvent updates the cache with the lifecycle event of a container
+func (s *statsCache) handleEvent(event *criv1.ContainerEventResponse) {
+	containerID := event.GetContainerId()
+
+	switch event.GetContainerEventType() {
+	case criv1.ContainerEventType_CONTAINER_STOPPED_EVENT, criv1.ContainerEventType_CONTAINER_DELETED_EVENT:
+		s.Lock()
+		delete(s.stats, containerID)
+		s.Unlock()
+	case criv1.ContainerEventType_CONTAINER_CREATED_EVENT, criv1.ContainerEventType_CONTAINER_STARTED_EVENT:
+		stats, err := s.util.listContainerStatsWithFilter(&criv1.ContainerStatsFilter{Id: containerID})
+		if err != nil {
+			log.Debugf("Unable to get stats of new container %s: %v", containerID, err)
+			return
+		}
+
+		s.Lock()
+		// the container is added by the next refresh if the cache isn't populated yet
+		if s.stats != nil && stats[containerID] != nil {
+			s.stats[containerID] = stats[containerID]
+		}
+		s.Unlock()
+	}
+}
+
+// listContainerStats returns a copy of the cached stats, refreshed if they
+// are older than statsCacheRefreshInterval
+func (s *statsCache) listContainerStats() (map[string]*criv1.ContainerStats, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+	return maps.Clone(s.stats), nil
+}
+
+// getContainerStats returns the cached stats of the given container
+func (s *statsCache) getContainerStats(containerID string) (*criv1.ContainerStats, bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.refresh(); err != nil {
+		return nil, false, err
+	}
+	stats, found := s.stats[containerID]
+	return stats, found, nil
+}
+
+// refresh lists the stats of all the containers if the cache is outdated.
+// The caller must hold the lock.
+func (s *statsCache) refresh() error {
+	if s.stats != nil && time.Since(s.lastRefresh) < statsCacheRefreshInterval {
+		return nil
+	}
+
+	stats, err := s.util.listContainerStatsWithFilter(&criv1.ContainerStatsFilter{})
+	if err != nil {
+		return err
+	}
+
+	s.stats = stats
+	s.lastRefresh = time.Now()
+	return nil
+}
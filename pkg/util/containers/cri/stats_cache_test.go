@@ -0,0 +1,193 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+
+package cri
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+func countCalls(calls []string, name string) int {
+	count := 0
+	for _, call := range calls {
+		if call == name {
+			count++
+		}
+	}
+	return count
+}
+
+func TestStatsCache(t *testing.T) {
+	fakeRuntime, endpoint := createAndStartFakeRemoteRuntime(t)
+	defer fakeRuntime.Stop()
+
+	util := newTestCRIUtil(t, strings.TrimPrefix(endpoint, "unix://"))
+	require.NoError(t, util.ready())
+	util.statsCache = newStatsCache(util)
+	// the subscription is driven by the test
+	util.statsCache.cancel = func() {}
+
+	fooID := createContainer(t, fakeRuntime, "foo", "foo")
+	barID := createContainer(t, fakeRuntime, "bar", "bar")
+	fakeRuntime.RuntimeService.SetFakeContainerStats([]*criv1.ContainerStats{
+		{Attributes: &criv1.ContainerAttributes{Id: fooID}},
+		{Attributes: &criv1.ContainerAttributes{Id: barID}},
+	})
+
+	// stats are polled until the stream is subscribed
+	_, err := util.GetContainerStats(fooID)
+	require.NoError(t, err)
+	_, err = util.GetContainerStats(barID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, countCalls(fakeRuntime.RuntimeService.GetCalls(), "ListContainerStats"))
+
+	util.statsCache.setSubscribed(true)
+
+	stats, err := util.ListContainerStats()
+	require.NoError(t, err)
+	assert.Len(t, stats, 2)
+	fooStats, err := util.GetContainerStats(fooID)
+	require.NoError(t, err)
+	assert.Equal(t, fooID, fooStats.GetAttributes().GetId())
+	_, err = util.GetContainerStats(barID)
+	require.NoError(t, err)
+	// the stats of all the containers are listed once
+	assert.Equal(t, 3, countCalls(fakeRuntime.RuntimeService.GetCalls(), "ListContainerStats"))
+
+	// callers can't modify the cache
+	delete(stats, fooID)
+	stats, err = util.ListContainerStats()
+	require.NoError(t, err)
+	assert.Len(t, stats, 2)
+
+	require.NoError(t, fakeRuntime.RuntimeService.RemoveContainer(context.Background(), fooID))
+	bazID := createContainer(t, fakeRuntime, "baz", "baz")
+	fakeRuntime.RuntimeService.SetFakeContainerStats([]*criv1.ContainerStats{
+		{Attributes: &criv1.ContainerAttributes{Id: barID}},
+		{Attributes: &criv1.ContainerAttributes{Id: bazID}},
+	})
+	util.statsCache.handleEvent(&criv1.ContainerEventResponse{
+		ContainerId:        fooID,
+		ContainerEventType: criv1.ContainerEventType_CONTAINER_DELETED_EVENT,
+	})
+	util.statsCache.handleEvent(&criv1.ContainerEventResponse{
+		ContainerId:        bazID,
+		ContainerEventType: criv1.ContainerEventType_CONTAINER_STARTED_EVENT,
+	})
+
+	stats, err = util.ListContainerStats()
+	require.NoError(t, err)
+	assert.Len(t, stats, 2)
+	assert.Contains(t, stats, barID)
+	assert.Contains(t, stats, bazID)
+
+	// the cache is dropped when the stream is closed
+	util.statsCache.setSubscribed(false)
+	assert.False(t, util.statsCache.enabled())
+	_, err = util.GetContainerStats(fooID)
+	assert.Error(t, err)
+}
+
+// eventlessRuntime is a CRI runtime which doesn't implement the container events
+type eventlessRuntime struct {
+	criv1.UnimplementedRuntimeServiceServer
+}
+
+func (eventlessRuntime) Version(context.Context, *criv1.VersionRequest) (*criv1.VersionResponse, error) {
+	return &criv1.VersionResponse{RuntimeName: "eventless"}, nil
+}
+
+func TestStatsCacheUnimplemented(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "cri.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	criv1.RegisterRuntimeServiceServer(server, &eventlessRuntime{})
+	go server.Serve(listener) //nolint:errcheck
+	defer server.Stop()
+
+	util := newTestCRIUtil(t, socketPath)
+	require.NoError(t, util.ready())
+	stream := newStatsCache(util)
+
+	done := make(chan struct{})
+	go func() {
+		stream.run(context.Background())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the stream should stop when the runtime doesn't support the container events")
+	}
+	assert.False(t, stream.enabled())
+
+	// the subscription isn't started again
+	stream.Lock()
+	defer stream.Unlock()
+	assert.Nil(t, stream.cancel)
+}
+
+// blockingEventsRuntime is a CRI runtime which streams no container events
+// until the subscription is closed
+type blockingEventsRuntime struct {
+	criv1.UnimplementedRuntimeServiceServer
+	closed chan struct{}
+}
+
+func (blockingEventsRuntime) Version(context.Context, *criv1.VersionRequest) (*criv1.VersionResponse, error) {
+	return &criv1.VersionResponse{RuntimeName: "blocking"}, nil
+}
+
+func (r blockingEventsRuntime) GetContainerEvents(_ *criv1.GetEventsRequest, stream criv1.RuntimeService_GetContainerEventsServer) error {
+	<-stream.Context().Done()
+	close(r.closed)
+	return stream.Context().Err()
+}
+
+func TestStatsCacheStop(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "cri.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	eventsRuntime := &blockingEventsRuntime{closed: make(chan struct{})}
+	server := grpc.NewServer()
+	criv1.RegisterRuntimeServiceServer(server, eventsRuntime)
+	go server.Serve(listener) //nolint:errcheck
+	defer server.Stop()
+
+	util := newTestCRIUtil(t, socketPath)
+	require.NoError(t, util.ready())
+	stream := newStatsCache(util)
+
+	// querying the stats subscribes to the container events
+	assert.Eventually(t, stream.enabled, 5*time.Second, 10*time.Millisecond)
+
+	stream.stop()
+	select {
+	case <-eventsRuntime.closed:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the subscription should be closed when the stream is stopped")
+	}
+
+	stream.Lock()
+	defer stream.Unlock()
+	assert.Nil(t, stream.cancel)
+	assert.False(t, stream.subscribed)
+}
@@ -0,0 +1,43 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+
+package cri
+
+import "github.com/DataDog/datadog-agent/pkg/telemetry"
+
+const (
+	subsystem = "cri"
+)
+
+var (
+	// queryTimeouts tracks the CRI queries which timed out.
+	queryTimeouts = telemetry.NewCounterWithOpts(
+		subsystem,
+		"query_timeouts",
+		[]string{"socket", "query"},
+		"Count of CRI queries which timed out, by socket and query.",
+		telemetry.Options{NoDoubleUnderscoreSep: true},
+	)
+
+	// breakerState tracks the state of the circuit breaker of each CRI socket.
+	breakerState = telemetry.NewGaugeWithOpts(
+		subsystem,
+		"circuit_breaker_state",
+		[]string{"socket"},
+		"State of the circuit breaker of the CRI socket: 0 when closed, 1 when open, 2 when half-open.",
+		telemetry.Options{NoDoubleUnderscoreSep: true},
+	)
+
+	// breakerSkippedQueries tracks the CRI queries skipped while the circuit breaker was open.
+	breakerSkippedQueries = telemetry.NewCounterWithOpts(
+		subsystem,
+		"circuit_breaker_skipped_queries",
+		[]string{"socket"},
+		"Count of CRI queries skipped because the circuit breaker of the socket was open.",
+		telemetry.Options{NoDoubleUnderscoreSep: true},
+	)
+)
@@ -12,11 +12,17 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	"github.com/DataDog/datadog-agent/internal/third_party/kubernetes/pkg/kubelet/cri/remote/util"
@@ -26,16 +32,44 @@ import (
 )
 
 var (
-	globalCRIUtil *CRIUtil
+	globalCRIUtil readyClient
 	once          sync.Once
 )
 
+const (
+	// redialInitialBackoff is the minimum delay between two attempts to re-dial an unhealthy connection
+	redialInitialBackoff = 1 * time.Second
+	// redialMaxBackoff is the maximum delay between two attempts to re-dial an unhealthy connection
+	redialMaxBackoff = 1 * time.Minute
+)
+
 // CRIClient abstracts the CRI client methods
 type CRIClient interface {
 	ListContainerStats() (map[string]*criv1.ContainerStats, error)
 	GetContainerStats(containerID string) (*criv1.ContainerStats, error)
+	ListPodSandboxStats() (map[string]*criv1.PodSandboxStats, error)
+	ListContainers() ([]*criv1.Container, error)
+	GetContainerStatus(containerID string) (*criv1.ContainerStatus, error)
+	GetContainerRuntime(containerID string) string
 	GetRuntime() string
 	GetRuntimeVersion() string
+	ListImages() ([]*criv1.Image, error)
+	ImageFsInfo() (*criv1.ImageFsInfoResponse, error)
+}
+
+// ContainerRuntimeLookup is implemented by the CRIClient querying several CRI
+// sockets, which knows the runtime of the containers reported by each socket.
+type ContainerRuntimeLookup interface {
+	LookupContainerRuntime(containerID string) (string, bool)
+}
+
+// readyClient is a CRIClient which connects to the CRI lazily
+type readyClient interface {
+	CRIClient
+	// ready returns nil once the client is connected, it triggers a connection attempt otherwise
+	ready() error
+	// stopStatsCaches closes the container events subscriptions of the stats caches
+	stopStatsCaches()
 }
 
 // CRIUtil wraps interactions with the CRI and implements CRIClient
@@ -45,12 +79,27 @@ type CRIUtil struct {
 	initRetry retry.Retrier
 
 	sync.Mutex
+	conn              *grpc.ClientConn
 	clientV1          criv1.RuntimeServiceClient
+	imageClientV1     criv1.ImageServiceClient
 	runtime           string
 	runtimeVersion    string
 	queryTimeout      time.Duration
 	connectionTimeout time.Duration
 	socketPath        string
+
+	// timeouts of the queries overriding queryTimeout, by query name
+	queryTimeouts map[string]time.Duration
+
+	// re-dial state of the connection, used once the initial connection is established
+	redialBackoff time.Duration
+	nextRedial    time.Time
+
+	// serves the container stats when cri_stats_cache is enabled
+	statsCache *statsCache
+
+	// skips the queries after consecutive timeouts, nil if disabled
+	breaker *circuitBreaker
 }
 
 // init makes an empty CRIUtil bootstrap itself.
@@ -60,6 +109,22 @@ func (c *CRIUtil) init() error {
 		return fmt.Errorf("no cri_socket_path was set")
 	}
 
+	c.Lock()
+	defer c.Unlock()
+
+	if err := c.connect(); err != nil {
+		return err
+	}
+
+	if c.statsCache != nil {
+		c.statsCache.start()
+	}
+	return nil
+}
+
+// connect dials the CRI socket and replaces the current connection, if any.
+// The caller must hold the lock.
+func (c *CRIUtil) connect() error {
 	var protocol string
 	if runtime.GOOS == "windows" {
 		protocol = "npipe"
@@ -80,10 +145,13 @@ func (c *CRIUtil) init() error {
 		return fmt.Errorf("failed to dial: %v", err)
 	}
 
+	// the clients are only replaced once the new connection answered, so that
+	// the queries running concurrently keep using the previous ones meanwhile
+	clientV1 := criv1.NewRuntimeServiceClient(conn)
 	var v *criv1.VersionResponse
-	err = c.detectAPIVersion(conn)
+	err = c.detectAPIVersion(clientV1)
 	if err == nil {
-		v, err = c.version()
+		v, err = c.version(clientV1)
 	}
 
 	if err != nil {
@@ -97,6 +165,16 @@ func (c *CRIUtil) init() error {
 		return err
 	}
 
+	if c.conn != nil {
+		if connErr := c.conn.Close(); connErr != nil {
+			log.Debugf("failed to close previous gRPC connection: %s", connErr)
+		}
+	}
+
+	c.conn = conn
+	c.clientV1 = clientV1
+	// the image service is served on the same socket
+	c.imageClientV1 = criv1.NewImageServiceClient(conn)
 	c.runtime = v.RuntimeName
 	c.runtimeVersion = v.RuntimeVersion
 	log.Debugf("Successfully connected to CRI %s %s", c.runtime, c.runtimeVersion)
@@ -104,32 +182,195 @@ func (c *CRIUtil) init() error {
 	return nil
 }
 
-// GetUtil returns a ready to use CRIUtil. It is backed by a shared singleton.
-func GetUtil() (*CRIUtil, error) {
+// getClient returns the CRI client, re-dialing the socket if the connection
+// is unhealthy. Re-dial attempts are spaced by an exponential backoff so that
+// a runtime that is down isn't hammered on every query.
+func (c *CRIUtil) getClient() (criv1.RuntimeServiceClient, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.conn != nil && isHealthy(c.conn) {
+		return c.clientV1, nil
+	}
+
+	now := time.Now()
+	if now.Before(c.nextRedial) {
+		return nil, fmt.Errorf("connection to the CRI socket %s is unhealthy, next re-dial in %s", c.socketPath, c.nextRedial.Sub(now).Round(time.Millisecond))
+	}
+
+	log.Debugf("Connection to the CRI socket %s is unhealthy, re-dialing", c.socketPath)
+	if err := c.connect(); err != nil {
+		if c.redialBackoff == 0 {
+			c.redialBackoff = redialInitialBackoff
+		} else {
+			c.redialBackoff = min(2*c.redialBackoff, redialMaxBackoff)
+		}
+		// the backoff starts once the attempt is over, as dialing can take up to the connection timeout
+		c.nextRedial = time.Now().Add(c.redialBackoff)
+		return nil, fmt.Errorf("failed to re-dial the CRI socket %s: %w", c.socketPath, err)
+	}
+
+	c.redialBackoff = 0
+	c.nextRedial = time.Time{}
+	return c.clientV1, nil
+}
+
+// invalidate marks the connection as unhealthy so that it's re-dialed on the
+// next query. It's called when the runtime is unavailable, for instance when
+// it restarted and re-created its socket.
+func (c *CRIUtil) invalidate(client criv1.RuntimeServiceClient) {
+	c.Lock()
+	defer c.Unlock()
+
+	// the connection may have already been replaced by a concurrent query
+	if c.conn == nil || c.clientV1 != client {
+		return
+	}
+
+	if err := c.conn.Close(); err != nil {
+		log.Debugf("failed to close gRPC connection: %s", err)
+	}
+	c.conn = nil
+}
+
+// isHealthy returns whether the connection can still be used
+func isHealthy(conn *grpc.ClientConn) bool {
+	state := conn.GetState()
+	return state != connectivity.TransientFailure && state != connectivity.Shutdown
+}
+
+// GetUtil returns a ready to use CRIClient. It is backed by a shared singleton.
+// When additional sockets are set with cri_socket_paths, the client queries all
+// the reachable runtimes and merges their results.
+func GetUtil() (CRIClient, error) {
 	once.Do(func() {
-		globalCRIUtil = &CRIUtil{
-			queryTimeout:      pkgconfigsetup.Datadog().GetDuration("cri_query_timeout") * time.Second,
-			connectionTimeout: pkgconfigsetup.Datadog().GetDuration("cri_connection_timeout") * time.Second,
-			socketPath:        pkgconfigsetup.Datadog().GetString("cri_socket_path"),
+		socketPaths := getSocketPaths()
+		switch len(socketPaths) {
+		case 0:
+			globalCRIUtil = newCRIUtil("")
+			return
+		case 1:
+			globalCRIUtil = newCRIUtil(socketPaths[0])
+			return
 		}
-		globalCRIUtil.initRetry.SetupRetrier(&retry.Config{ //nolint:errcheck
-			Name:              "criutil",
-			AttemptMethod:     globalCRIUtil.init,
-			Strategy:          retry.Backoff,
-			InitialRetryDelay: 1 * time.Second,
-			MaxRetryDelay:     5 * time.Minute,
-		})
+
+		utils := make([]*CRIUtil, 0, len(socketPaths))
+		for _, socketPath := range socketPaths {
+			utils = append(utils, newCRIUtil(socketPath))
+		}
+		globalCRIUtil = newMultiCRIUtil(utils)
 	})
 
-	if err := globalCRIUtil.initRetry.TriggerRetry(); err != nil {
+	if err := globalCRIUtil.ready(); err != nil {
 		log.Debugf("CRI init error: %s", err)
 		return nil, err
 	}
 	return globalCRIUtil, nil
 }
 
+// StopStatsCaches closes the container events subscriptions used to cache the
+// container stats of the shared CRIClient, if any. They are subscribed again
+// if the stats are queried afterwards. It must be called after GetUtil.
+func StopStatsCaches() {
+	if globalCRIUtil != nil {
+		globalCRIUtil.stopStatsCaches()
+	}
+}
+
+// getSocketPaths returns the deduplicated list of the configured CRI sockets,
+// cri_socket_path first.
+func getSocketPaths() []string {
+	candidates := append([]string{pkgconfigsetup.Datadog().GetString("cri_socket_path")}, pkgconfigsetup.Datadog().GetStringSlice("cri_socket_paths")...)
+
+	socketPaths := make([]string, 0, len(candidates))
+	seen := make(map[string]struct{}, len(candidates))
+	for _, socketPath := range candidates {
+		if socketPath == "" {
+			continue
+		}
+		if _, found := seen[socketPath]; found {
+			continue
+		}
+		seen[socketPath] = struct{}{}
+		socketPaths = append(socketPaths, socketPath)
+	}
+	return socketPaths
+}
+
+// queryNames lists the queries whose timeout can be overridden
+var queryNames = []string{
+	"list_container_stats",
+	"list_pod_sandbox_stats",
+	"list_containers",
+	"container_status",
+	"list_images",
+	"image_fs_info",
+}
+
+// getQueryTimeouts returns the timeouts of cri_query_timeout_overrides, in seconds by query name
+func getQueryTimeouts() map[string]time.Duration {
+	timeouts := make(map[string]time.Duration)
+	for name, value := range pkgconfigsetup.Datadog().GetStringMapString("cri_query_timeout_overrides") {
+		if !slices.Contains(queryNames, name) {
+			log.Warnf("Ignoring the timeout of unknown CRI query %q, known queries are: %s", name, strings.Join(queryNames, ", "))
+			continue
+		}
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			log.Warnf("Ignoring invalid timeout %q of CRI query %q, it must be a positive number of seconds", value, name)
+			continue
+		}
+		timeouts[name] = time.Duration(seconds) * time.Second
+	}
+	return timeouts
+}
+
+func newCRIUtil(socketPath string) *CRIUtil {
+	util := &CRIUtil{
+		queryTimeout:      pkgconfigsetup.Datadog().GetDuration("cri_query_timeout") * time.Second,
+		connectionTimeout: pkgconfigsetup.Datadog().GetDuration("cri_connection_timeout") * time.Second,
+		queryTimeouts:     getQueryTimeouts(),
+		socketPath:        socketPath,
+		breaker: newCircuitBreaker(
+			socketPath,
+			pkgconfigsetup.Datadog().GetInt("cri_circuit_breaker_threshold"),
+			time.Duration(pkgconfigsetup.Datadog().GetInt("cri_circuit_breaker_skipped_intervals"))*breakerInterval,
+		),
+	}
+	util.initRetry.SetupRetrier(&retry.Config{ //nolint:errcheck
+		Name:              "criutil",
+		AttemptMethod:     util.init,
+		Strategy:          retry.Backoff,
+		InitialRetryDelay: 1 * time.Second,
+		MaxRetryDelay:     5 * time.Minute,
+	})
+	if pkgconfigsetup.Datadog().GetBool("cri_stats_cache") {
+		util.statsCache = newStatsCache(util)
+	}
+	return util
+}
+
+func (c *CRIUtil) ready() error {
+	// TriggerRetry returns a *retry.Error, which must not be returned as a non-nil error interface
+	if err := c.initRetry.TriggerRetry(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *CRIUtil) stopStatsCaches() {
+	c.statsCache.stop()
+}
+
 // GetContainerStats returns the stats for the container with the given ID
 func (c *CRIUtil) GetContainerStats(containerID string) (*criv1.ContainerStats, error) {
+	if c.statsCache.enabled() {
+		// containers missing from the cache are queried below
+		if containerStats, found, err := c.statsCache.getContainerStats(containerID); err == nil && found {
+			return containerStats, nil
+		}
+	}
+
 	stats, err := c.listContainerStatsWithFilter(&criv1.ContainerStatsFilter{Id: containerID})
 	if err != nil {
 		return nil, err
@@ -145,44 +386,188 @@ func (c *CRIUtil) GetContainerStats(containerID string) (*criv1.ContainerStats,
 
 // ListContainerStats sends a ListContainerStatsRequest to the server, and parses the returned response
 func (c *CRIUtil) ListContainerStats() (map[string]*criv1.ContainerStats, error) {
+	if c.statsCache.enabled() {
+		return c.statsCache.listContainerStats()
+	}
 	return c.listContainerStatsWithFilter(&criv1.ContainerStatsFilter{})
 }
 
+// ListPodSandboxStats sends a ListPodSandboxStatsRequest to the server, and returns the stats indexed by pod sandbox ID
+func (c *CRIUtil) ListPodSandboxStats() (map[string]*criv1.PodSandboxStats, error) {
+	var r *criv1.ListPodSandboxStatsResponse
+
+	err := c.query("list_pod_sandbox_stats", func(ctx context.Context, client criv1.RuntimeServiceClient) (err error) {
+		r, err = client.ListPodSandboxStats(ctx, &criv1.ListPodSandboxStatsRequest{Filter: &criv1.PodSandboxStatsFilter{}})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]*criv1.PodSandboxStats, len(r.GetStats()))
+	for _, s := range r.GetStats() {
+		stats[s.GetAttributes().GetId()] = s
+	}
+	return stats, nil
+}
+
+// ListContainers sends a ListContainersRequest to the server, and returns the containers known by the runtime
+func (c *CRIUtil) ListContainers() ([]*criv1.Container, error) {
+	var r *criv1.ListContainersResponse
+
+	err := c.query("list_containers", func(ctx context.Context, client criv1.RuntimeServiceClient) (err error) {
+		r, err = client.ListContainers(ctx, &criv1.ListContainersRequest{Filter: &criv1.ContainerFilter{}})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetContainers(), nil
+}
+
+// GetContainerStatus returns the status of the container with the given ID
+func (c *CRIUtil) GetContainerStatus(containerID string) (*criv1.ContainerStatus, error) {
+	var r *criv1.ContainerStatusResponse
+
+	err := c.query("container_status", func(ctx context.Context, client criv1.RuntimeServiceClient) (err error) {
+		r, err = client.ContainerStatus(ctx, &criv1.ContainerStatusRequest{ContainerId: containerID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if r.GetStatus() == nil {
+		return nil, fmt.Errorf("could not get status for container with ID %s", containerID)
+	}
+	return r.GetStatus(), nil
+}
+
+// ListImages sends a ListImagesRequest to the server, and returns the images known by the runtime
+func (c *CRIUtil) ListImages() ([]*criv1.Image, error) {
+	var r *criv1.ListImagesResponse
+
+	err := c.queryImages("list_images", func(ctx context.Context, client criv1.ImageServiceClient) (err error) {
+		r, err = client.ListImages(ctx, &criv1.ListImagesRequest{Filter: &criv1.ImageFilter{}})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetImages(), nil
+}
+
+// ImageFsInfo returns the usage of the filesystems storing the images
+func (c *CRIUtil) ImageFsInfo() (*criv1.ImageFsInfoResponse, error) {
+	var r *criv1.ImageFsInfoResponse
+
+	err := c.queryImages("image_fs_info", func(ctx context.Context, client criv1.ImageServiceClient) (err error) {
+		r, err = client.ImageFsInfo(ctx, &criv1.ImageFsInfoRequest{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetContainerRuntime returns the CRI runtime running the container with the given ID
+func (c *CRIUtil) GetContainerRuntime(string) string {
+	return c.GetRuntime()
+}
+
 // GetRuntime returns the CRI runtime
 func (c *CRIUtil) GetRuntime() string {
+	c.Lock()
+	defer c.Unlock()
+
 	return c.runtime
 }
 
 // GetRuntimeVersion returns the CRI runtime version
 func (c *CRIUtil) GetRuntimeVersion() string {
+	c.Lock()
+	defer c.Unlock()
+
 	return c.runtimeVersion
 }
 
-func (c *CRIUtil) detectAPIVersion(conn *grpc.ClientConn) error {
+func (c *CRIUtil) detectAPIVersion(client criv1.RuntimeServiceClient) error {
 	ctx, cancel := context.WithTimeout(context.Background(), c.connectionTimeout)
 	defer cancel()
 
-	c.clientV1 = criv1.NewRuntimeServiceClient(conn)
-
-	_, err := c.clientV1.Version(ctx, &criv1.VersionRequest{})
+	_, err := client.Version(ctx, &criv1.VersionRequest{})
 	return err
 }
 
-func (c *CRIUtil) version() (*criv1.VersionResponse, error) {
+func (c *CRIUtil) version(client criv1.RuntimeServiceClient) (*criv1.VersionResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.queryTimeout)
 	defer cancel()
 
-	return c.clientV1.Version(ctx, &criv1.VersionRequest{})
+	return client.Version(ctx, &criv1.VersionRequest{})
 }
 
-func (c *CRIUtil) listContainerStatsWithFilter(filter *criv1.ContainerStatsFilter) (map[string]*criv1.ContainerStats, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), c.queryTimeout)
-	defer cancel()
+// query runs the given CRI call, bounded by the timeout of the query. If the
+// runtime is unavailable, the connection is invalidated and the call is
+// attempted once more on a new connection. The call is skipped while the
+// circuit breaker of the socket is open.
+func (c *CRIUtil) query(name string, call func(ctx context.Context, client criv1.RuntimeServiceClient) error) error {
+	if !c.breaker.allow() {
+		return fmt.Errorf("%w: %s", ErrCircuitOpen, c.socketPath)
+	}
+
+	timeout := c.queryTimeout
+	if override, found := c.queryTimeouts[name]; found {
+		timeout = override
+	}
 
-	var r *criv1.ListContainerStatsResponse
 	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		var client criv1.RuntimeServiceClient
+		if client, err = c.getClient(); err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err = call(ctx, client)
+		cancel()
+
+		timedOut := status.Code(err) == codes.DeadlineExceeded
+		if timedOut {
+			queryTimeouts.Inc(c.socketPath, name)
+		}
+		c.breaker.record(timedOut)
+
+		if status.Code(err) != codes.Unavailable {
+			return err
+		}
+		c.invalidate(client)
+	}
+	return err
+}
+
+// queryImages runs the given CRI image service call, with the same retry as query
+func (c *CRIUtil) queryImages(name string, call func(ctx context.Context, client criv1.ImageServiceClient) error) error {
+	return c.query(name, func(ctx context.Context, _ criv1.RuntimeServiceClient) error {
+		c.Lock()
+		client := c.imageClientV1
+		c.Unlock()
 
-	if r, err = c.clientV1.ListContainerStats(ctx, &criv1.ListContainerStatsRequest{Filter: filter}); err != nil {
+		return call(ctx, client)
+	})
+}
+
+func (c *CRIUtil) listContainerStatsWithFilter(filter *criv1.ContainerStatsFilter) (map[string]*criv1.ContainerStats, error) {
+	var r *criv1.ListContainerStatsResponse
+
+	err := c.query("list_container_stats", func(ctx context.Context, client criv1.RuntimeServiceClient) (err error) {
+		r, err = client.ListContainerStats(ctx, &criv1.ListContainerStatsRequest{Filter: filter})
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -12,6 +12,8 @@
 package cri
 
 import (
+	"context"
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -19,6 +21,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	fakeremote "github.com/DataDog/datadog-agent/internal/third_party/kubernetes/pkg/kubelet/cri/remote/fake"
 )
@@ -56,6 +59,202 @@ func TestCRIUtilListContainerStats(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCRIUtilListPodSandboxStats(t *testing.T) {
+	fakeRuntime, endpoint := createAndStartFakeRemoteRuntime(t)
+	defer fakeRuntime.Stop()
+	socketFile := strings.TrimPrefix(endpoint, "unix://")
+	util := &CRIUtil{
+		queryTimeout:      1 * time.Second,
+		connectionTimeout: 1 * time.Second,
+		socketPath:        socketFile,
+	}
+	err := util.init()
+	require.NoError(t, err)
+
+	sandboxID, err := fakeRuntime.RuntimeService.RunPodSandbox(context.Background(), &criv1.PodSandboxConfig{
+		Metadata: &criv1.PodSandboxMetadata{Name: "pod", Uid: "pod-uid", Namespace: "default"},
+	}, "")
+	require.NoError(t, err)
+	fakeRuntime.RuntimeService.SetFakePodSandboxStats([]*criv1.PodSandboxStats{
+		{
+			Attributes: &criv1.PodSandboxAttributes{Id: sandboxID},
+			Linux: &criv1.LinuxPodSandboxStats{
+				Network: &criv1.NetworkUsage{
+					DefaultInterface: &criv1.NetworkInterfaceUsage{Name: "eth0", RxBytes: &criv1.UInt64Value{Value: 10}},
+				},
+			},
+		},
+	})
+
+	stats, err := util.ListPodSandboxStats()
+	require.NoError(t, err)
+	require.Contains(t, stats, sandboxID)
+	assert.Equal(t, uint64(10), stats[sandboxID].GetLinux().GetNetwork().GetDefaultInterface().GetRxBytes().GetValue())
+}
+
+func TestCRIUtilListContainers(t *testing.T) {
+	fakeRuntime, endpoint := createAndStartFakeRemoteRuntime(t)
+	defer fakeRuntime.Stop()
+	socketFile := strings.TrimPrefix(endpoint, "unix://")
+	util := &CRIUtil{
+		queryTimeout:      1 * time.Second,
+		connectionTimeout: 1 * time.Second,
+		socketPath:        socketFile,
+	}
+	err := util.init()
+	require.NoError(t, err)
+
+	sandboxConfig := &criv1.PodSandboxConfig{
+		Metadata: &criv1.PodSandboxMetadata{Name: "pod", Uid: "pod-uid", Namespace: "default"},
+	}
+	sandboxID, err := fakeRuntime.RuntimeService.RunPodSandbox(context.Background(), sandboxConfig, "")
+	require.NoError(t, err)
+	containerID, err := fakeRuntime.RuntimeService.CreateContainer(context.Background(), sandboxID, &criv1.ContainerConfig{
+		Metadata: &criv1.ContainerMetadata{Name: "app"},
+		Image:    &criv1.ImageSpec{Image: "nginx:latest"},
+		Labels:   map[string]string{"io.kubernetes.pod.namespace": "default"},
+	}, sandboxConfig)
+	require.NoError(t, err)
+	require.NoError(t, fakeRuntime.RuntimeService.StartContainer(context.Background(), containerID))
+
+	containers, err := util.ListContainers()
+	require.NoError(t, err)
+	require.Len(t, containers, 1)
+	assert.Equal(t, containerID, containers[0].GetId())
+	assert.Equal(t, "default", containers[0].GetLabels()["io.kubernetes.pod.namespace"])
+
+	status, err := util.GetContainerStatus(containerID)
+	require.NoError(t, err)
+	assert.Equal(t, criv1.ContainerState_CONTAINER_RUNNING, status.GetState())
+	assert.Equal(t, "nginx:latest", status.GetImage().GetImage())
+	assert.NotZero(t, status.GetCreatedAt())
+
+	_, err = util.GetContainerStatus("unknown")
+	assert.Error(t, err)
+}
+
+func TestCRIUtilListImages(t *testing.T) {
+	fakeRuntime, endpoint := createAndStartFakeRemoteRuntime(t)
+	defer fakeRuntime.Stop()
+	socketFile := strings.TrimPrefix(endpoint, "unix://")
+	util := &CRIUtil{
+		queryTimeout:      1 * time.Second,
+		connectionTimeout: 1 * time.Second,
+		socketPath:        socketFile,
+	}
+	err := util.init()
+	require.NoError(t, err)
+
+	fakeRuntime.ImageService.SetFakeImageSize(100)
+	fakeRuntime.ImageService.SetFakeImages([]string{"nginx:latest", "busybox:latest"})
+
+	images, err := util.ListImages()
+	require.NoError(t, err)
+	require.Len(t, images, 2)
+	for _, image := range images {
+		assert.Equal(t, uint64(100), image.GetSize_())
+		assert.Equal(t, []string{image.GetId()}, image.GetRepoTags())
+	}
+
+	fakeRuntime.ImageService.SetFakeFilesystemUsage([]*criv1.FilesystemUsage{
+		{
+			FsId:       &criv1.FilesystemIdentifier{Mountpoint: "/var/lib/containerd"},
+			UsedBytes:  &criv1.UInt64Value{Value: 1000},
+			InodesUsed: &criv1.UInt64Value{Value: 10},
+		},
+	})
+
+	info, err := util.ImageFsInfo()
+	require.NoError(t, err)
+	require.Len(t, info.GetImageFilesystems(), 1)
+	assert.Equal(t, "/var/lib/containerd", info.GetImageFilesystems()[0].GetFsId().GetMountpoint())
+	assert.Equal(t, uint64(1000), info.GetImageFilesystems()[0].GetUsedBytes().GetValue())
+}
+
+func TestCRIUtilReconnect(t *testing.T) {
+	fakeRuntime, endpoint := createAndStartFakeRemoteRuntime(t)
+	socketFile := strings.TrimPrefix(endpoint, "unix://")
+	util := &CRIUtil{
+		queryTimeout:      1 * time.Second,
+		connectionTimeout: 1 * time.Second,
+		socketPath:        socketFile,
+	}
+	err := util.init()
+	require.NoError(t, err)
+
+	// the runtime restarts and re-creates its socket
+	fakeRuntime.Stop()
+	require.NoError(t, os.RemoveAll(socketFile))
+	fakeRuntime = fakeremote.NewFakeRemoteRuntime()
+	require.NoError(t, fakeRuntime.Start(endpoint))
+	defer fakeRuntime.Stop()
+
+	_, err = util.ListContainerStats()
+	require.NoError(t, err)
+}
+
+func TestCRIUtilRedialBackoff(t *testing.T) {
+	fakeRuntime, endpoint := createAndStartFakeRemoteRuntime(t)
+	socketFile := strings.TrimPrefix(endpoint, "unix://")
+	util := &CRIUtil{
+		queryTimeout:      1 * time.Second,
+		connectionTimeout: 1 * time.Second,
+		socketPath:        socketFile,
+	}
+	err := util.init()
+	require.NoError(t, err)
+
+	// the runtime goes away
+	fakeRuntime.Stop()
+	require.NoError(t, os.RemoveAll(socketFile))
+
+	_, err = util.ListContainerStats()
+	require.Error(t, err)
+	assert.Equal(t, redialInitialBackoff, util.redialBackoff)
+
+	// no re-dial is attempted before the backoff expires
+	_, err = util.GetContainerStats("foo")
+	require.ErrorContains(t, err, "next re-dial")
+
+	// the runtime comes back, the connection is re-dialed once the backoff expired
+	fakeRuntime = fakeremote.NewFakeRemoteRuntime()
+	require.NoError(t, fakeRuntime.Start(endpoint))
+	defer fakeRuntime.Stop()
+
+	util.nextRedial = time.Now()
+	_, err = util.ListContainerStats()
+	require.NoError(t, err)
+	assert.Zero(t, util.redialBackoff)
+}
+
+func TestCRIUtilFailedRedialKeepsConnection(t *testing.T) {
+	fakeRuntime, endpoint := createAndStartFakeRemoteRuntime(t)
+	defer fakeRuntime.Stop()
+	socketFile := strings.TrimPrefix(endpoint, "unix://")
+	util := &CRIUtil{
+		queryTimeout:      1 * time.Second,
+		connectionTimeout: 1 * time.Second,
+		socketPath:        socketFile,
+	}
+	err := util.init()
+	require.NoError(t, err)
+	conn, client, imageClient := util.conn, util.clientV1, util.imageClientV1
+
+	// the new connection is established but doesn't answer
+	fakeRuntime.RuntimeService.InjectError("Version", errors.New("not ready"))
+	util.Lock()
+	err = util.connect()
+	util.Unlock()
+	require.Error(t, err)
+
+	// the previous connection and clients are still in use
+	assert.Same(t, conn, util.conn)
+	assert.Equal(t, client, util.clientV1)
+	assert.Equal(t, imageClient, util.imageClientV1)
+	_, err = util.ListContainers()
+	require.NoError(t, err)
+}
+
 // createAndStartFakeRemoteRuntime creates and starts fakeremote.RemoteRuntime.
 // It returns the RemoteRuntime, endpoint on success.
 // Users should call fakeRuntime.Stop() to cleanup the server.
@@ -8,6 +8,8 @@
 package cri
 
 import (
+	"hash/fnv"
+	"sync"
 	"time"
 
 	v1 "k8s.io/cri-api/pkg/apis/runtime/v1"
@@ -16,6 +18,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/config/env"
 	"github.com/DataDog/datadog-agent/pkg/util/containers/cri"
 	"github.com/DataDog/datadog-agent/pkg/util/containers/metrics/provider"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/option"
 	"github.com/DataDog/datadog-agent/pkg/util/pointer"
 )
@@ -23,6 +26,11 @@ import (
 const (
 	collectorID       = "cri"
 	collectorPriority = 3
+
+	contNetStatsCachePrefix = "cns-"
+	refreshCacheKey         = "refresh"
+
+	criCacheGCInterval = 30 * time.Second
 )
 
 func init() {
@@ -35,7 +43,9 @@ func init() {
 }
 
 type criCollector struct {
-	client cri.CRIClient
+	client      cri.CRIClient
+	statsCache  provider.Cache
+	refreshLock sync.Mutex
 }
 
 func newCRICollector(cache *provider.Cache) (provider.CollectorMetadata, error) {
@@ -50,9 +60,13 @@ func newCRICollector(cache *provider.Cache) (provider.CollectorMetadata, error)
 		return collectorMetadata, provider.ConvertRetrierErr(err)
 	}
 
-	collector := &criCollector{client: client}
+	collector := &criCollector{
+		client:     client,
+		statsCache: *provider.NewCache(criCacheGCInterval),
+	}
 	collectors := &provider.Collectors{
-		Stats: provider.MakeRef[provider.ContainerStatsGetter](collector, collectorPriority),
+		Stats:   provider.MakeRef[provider.ContainerStatsGetter](collector, collectorPriority),
+		Network: provider.MakeRef[provider.ContainerNetworkStatsGetter](collector, collectorPriority),
 	}
 
 	return provider.CollectorMetadata{
@@ -93,6 +107,78 @@ func (collector *criCollector) GetContainerStats(containerNS, containerID string
 	return containerStats, nil
 }
 
+// GetContainerNetworkStats returns network stats by container ID.
+// Network stats are collected per pod sandbox, all the containers of a pod share the same stats.
+func (collector *criCollector) GetContainerNetworkStats(_, containerID string, cacheValidity time.Duration) (*provider.ContainerNetworkStats, error) {
+	currentTime := time.Now()
+
+	containerNetworkStats, found, err := collector.statsCache.Get(currentTime, contNetStatsCachePrefix+containerID, cacheValidity)
+	if found {
+		if containerNetworkStats != nil {
+			return containerNetworkStats.(*provider.ContainerNetworkStats), err
+		}
+		return nil, err
+	}
+
+	// Item missing from cache
+	if err := collector.refreshPodSandboxCache(currentTime, cacheValidity); err != nil {
+		return nil, err
+	}
+
+	containerNetworkStats, found, err = collector.statsCache.Get(currentTime, contNetStatsCachePrefix+containerID, cacheValidity)
+	if found {
+		if containerNetworkStats != nil {
+			return containerNetworkStats.(*provider.ContainerNetworkStats), err
+		}
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (collector *criCollector) refreshPodSandboxCache(currentTime time.Time, cacheValidity time.Duration) error {
+	collector.refreshLock.Lock()
+	defer collector.refreshLock.Unlock()
+
+	// Not refreshing if last refresh is within cacheValidity
+	_, found, err := collector.statsCache.Get(currentTime, refreshCacheKey, cacheValidity)
+	if found {
+		return err
+	}
+
+	podStats, err := collector.client.ListPodSandboxStats()
+	if err == nil {
+		collector.processPodSandboxStats(currentTime, podStats)
+	} else {
+		log.Debugf("Unable to get pod sandbox stats from CRI, err: %v", err)
+	}
+
+	collector.statsCache.Store(currentTime, refreshCacheKey, nil, err)
+	return err
+}
+
+func (collector *criCollector) processPodSandboxStats(currentTime time.Time, podStats map[string]*v1.PodSandboxStats) {
+	for _, pod := range podStats {
+		linuxStats := pod.GetLinux()
+		if linuxStats == nil || len(linuxStats.GetContainers()) == 0 {
+			continue
+		}
+
+		// All containers of a pod share its network namespace, the pod UID is used to generate the isolation group.
+		podNetworkStats := &provider.ContainerNetworkStats{}
+		convertNetworkStats(linuxStats.GetNetwork(), podNetworkStats)
+		if uid := pod.GetAttributes().GetMetadata().GetUid(); uid != "" {
+			podNetworkStats.NetworkIsolationGroupID = pointer.Ptr(networkIDFromPODUID(uid))
+		}
+
+		for _, container := range linuxStats.GetContainers() {
+			if cID := container.GetAttributes().GetId(); cID != "" {
+				collector.statsCache.Store(currentTime, contNetStatsCachePrefix+cID, podNetworkStats, nil)
+			}
+		}
+	}
+}
+
 func (collector *criCollector) getCriContainerStats(containerID string) (*v1.ContainerStats, error) {
 	stats, err := collector.client.GetContainerStats(containerID)
 	if err != nil {
@@ -109,3 +195,49 @@ func convertRuntimeUInt64Value(v *v1.UInt64Value) *float64 {
 
 	return pointer.Ptr(float64(v.GetValue()))
 }
+
+func convertNetworkStats(podNetworkStats *v1.NetworkUsage, outNetworkStats *provider.ContainerNetworkStats) {
+	if podNetworkStats == nil {
+		return
+	}
+
+	interfaces := podNetworkStats.GetInterfaces()
+	if len(interfaces) == 0 && podNetworkStats.GetDefaultInterface() != nil {
+		interfaces = []*v1.NetworkInterfaceUsage{podNetworkStats.GetDefaultInterface()}
+	}
+
+	var sumBytesSent, sumBytesRcvd float64
+	outNetworkStats.Timestamp = time.Unix(0, podNetworkStats.GetTimestamp())
+	outNetworkStats.Interfaces = make(map[string]provider.InterfaceNetStats, len(interfaces))
+
+	for _, interfaceStats := range interfaces {
+		fieldSet := false
+		outInterfaceStats := provider.InterfaceNetStats{}
+
+		if interfaceStats.GetTxBytes() != nil {
+			fieldSet = true
+			sumBytesSent += float64(interfaceStats.GetTxBytes().GetValue())
+			outInterfaceStats.BytesSent = convertRuntimeUInt64Value(interfaceStats.GetTxBytes())
+		}
+		if interfaceStats.GetRxBytes() != nil {
+			fieldSet = true
+			sumBytesRcvd += float64(interfaceStats.GetRxBytes().GetValue())
+			outInterfaceStats.BytesRcvd = convertRuntimeUInt64Value(interfaceStats.GetRxBytes())
+		}
+
+		if fieldSet {
+			outNetworkStats.Interfaces[interfaceStats.GetName()] = outInterfaceStats
+		}
+	}
+
+	if len(outNetworkStats.Interfaces) > 0 {
+		outNetworkStats.BytesSent = &sumBytesSent
+		outNetworkStats.BytesRcvd = &sumBytesRcvd
+	}
+}
+
+func networkIDFromPODUID(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
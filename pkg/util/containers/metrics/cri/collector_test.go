@@ -15,6 +15,7 @@ import (
 	pb "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	"github.com/DataDog/datadog-agent/pkg/util/containers/cri/crimock"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/metrics/provider"
 	"github.com/DataDog/datadog-agent/pkg/util/pointer"
 )
 
@@ -59,3 +60,63 @@ func TestGetContainerStats(t *testing.T) {
 	assert.Equal(t, pointer.Ptr(2048.0), stats.Memory.UsageTotal)
 	assert.Equal(t, pointer.Ptr(512.0), stats.Memory.RSS)
 }
+
+func TestGetContainerNetworkStats(t *testing.T) {
+	mockedCriClient := new(crimock.MockCRIClient)
+	mockedCriClient.On("ListPodSandboxStats").Return(
+		map[string]*pb.PodSandboxStats{
+			"sandbox1": {
+				Attributes: &pb.PodSandboxAttributes{
+					Id:       "sandbox1",
+					Metadata: &pb.PodSandboxMetadata{Uid: "pod-uid"},
+				},
+				Linux: &pb.LinuxPodSandboxStats{
+					Network: &pb.NetworkUsage{
+						Timestamp: 1000,
+						Interfaces: []*pb.NetworkInterfaceUsage{
+							{
+								Name:    "eth0",
+								RxBytes: &pb.UInt64Value{Value: 10},
+								TxBytes: &pb.UInt64Value{Value: 20},
+							},
+							{
+								Name:    "eth1",
+								RxBytes: &pb.UInt64Value{Value: 1},
+								TxBytes: &pb.UInt64Value{Value: 2},
+							},
+						},
+					},
+					Containers: []*pb.ContainerStats{
+						{Attributes: &pb.ContainerAttributes{Id: "cID1"}},
+						{Attributes: &pb.ContainerAttributes{Id: "cID2"}},
+					},
+				},
+			},
+		},
+		nil,
+	)
+
+	collector := criCollector{
+		client:     mockedCriClient,
+		statsCache: *provider.NewCache(criCacheGCInterval),
+	}
+
+	stats, err := collector.GetContainerNetworkStats("", "cID1", 10*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, pointer.Ptr(11.0), stats.BytesRcvd)
+	assert.Equal(t, pointer.Ptr(22.0), stats.BytesSent)
+	assert.Equal(t, pointer.Ptr(10.0), stats.Interfaces["eth0"].BytesRcvd)
+	assert.Equal(t, pointer.Ptr(2.0), stats.Interfaces["eth1"].BytesSent)
+	assert.Equal(t, pointer.Ptr(networkIDFromPODUID("pod-uid")), stats.NetworkIsolationGroupID)
+
+	// Containers of the same pod share the network stats, the pod stats are listed only once
+	stats2, err := collector.GetContainerNetworkStats("", "cID2", 10*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, stats, stats2)
+
+	stats3, err := collector.GetContainerNetworkStats("", "unknown", 10*time.Second)
+	assert.NoError(t, err)
+	assert.Nil(t, stats3)
+
+	mockedCriClient.AssertNumberOfCalls(t, "ListPodSandboxStats", 1)
+}
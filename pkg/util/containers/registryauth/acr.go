@@ -0,0 +1,172 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package registryauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	azureIMDSEndpoint    = "http://169.254.169.254"
+	azureAuthorityHost   = "https://login.microsoftonline.com/"
+	azureManagementScope = "https://management.azure.com/"
+
+	// acrUsername is the username used to authenticate against ACR with a refresh token
+	acrUsername = "00000000-0000-0000-0000-000000000000"
+	// acrRefreshTokenLifetime is the lifetime of the refresh tokens issued by ACR
+	acrRefreshTokenLifetime = 3 * time.Hour
+)
+
+// acrProvider exchanges an Azure AD token of the identity of the agent for an ACR refresh token.
+// The AD token is obtained with the federated token of the AKS workload identity when it is
+// configured, and from the managed identity of the node otherwise.
+type acrProvider struct {
+	client       *http.Client
+	clientID     string
+	imdsEndpoint string
+	scheme       string
+	now          func() time.Time
+}
+
+func newACRProvider(client *http.Client, clientID string) *acrProvider {
+	return &acrProvider{
+		client:       client,
+		clientID:     clientID,
+		imdsEndpoint: azureIMDSEndpoint,
+		scheme:       "https",
+		now:          time.Now,
+	}
+}
+
+func (p *acrProvider) credentials(ctx context.Context, host string) (*Credentials, error) {
+	aadToken, aadExpiresAt, err := p.aadToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve an Azure AD token: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type":   {"access_token"},
+		"service":      {host},
+		"access_token": {aadToken},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.scheme+"://"+host+"/oauth2/exchange", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var resp struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := doJSONRequest(p.client, req, &resp); err != nil {
+		return nil, fmt.Errorf("could not exchange the Azure AD token: %w", err)
+	}
+	if resp.RefreshToken == "" {
+		return nil, errors.New("no refresh token returned by ACR")
+	}
+
+	expiresAt := p.now().Add(acrRefreshTokenLifetime)
+	if aadExpiresAt.Before(expiresAt) {
+		expiresAt = aadExpiresAt
+	}
+	return &Credentials{
+		Username:  acrUsername,
+		Password:  resp.RefreshToken,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// aadTokenResponse is the token returned by Azure AD and IMDS, the latter returning expires_in as a string
+type aadTokenResponse struct {
+	AccessToken string      `json:"access_token"`
+	ExpiresIn   json.Number `json:"expires_in"`
+}
+
+func (p *acrProvider) aadToken(ctx context.Context) (string, time.Time, error) {
+	var req *http.Request
+	var err error
+	if tokenFile := os.Getenv("AZURE_FEDERATED_TOKEN_FILE"); tokenFile != "" {
+		req, err = p.workloadIdentityRequest(ctx, tokenFile)
+	} else {
+		req, err = p.managedIdentityRequest(ctx)
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	var token aadTokenResponse
+	if err := doJSONRequest(p.client, req, &token); err != nil {
+		return "", time.Time{}, err
+	}
+	if token.AccessToken == "" {
+		return "", time.Time{}, errors.New("no access token returned")
+	}
+	expiresIn, err := strconv.ParseInt(token.ExpiresIn.String(), 10, 64)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid token expiration: %w", err)
+	}
+	return token.AccessToken, p.now().Add(time.Duration(expiresIn) * time.Second), nil
+}
+
+// workloadIdentityRequest returns the request exchanging the federated token of the AKS workload identity
+func (p *acrProvider) workloadIdentityRequest(ctx context.Context, tokenFile string) (*http.Request, error) {
+	assertion, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return nil, err
+	}
+	clientID := p.clientID
+	if clientID == "" {
+		clientID = os.Getenv("AZURE_CLIENT_ID")
+	}
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	if clientID == "" || tenantID == "" {
+		return nil, errors.New("AZURE_CLIENT_ID and AZURE_TENANT_ID must be set to use the workload identity")
+	}
+	authorityHost := os.Getenv("AZURE_AUTHORITY_HOST")
+	if authorityHost == "" {
+		authorityHost = azureAuthorityHost
+	}
+
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_id":             {clientID},
+		"scope":                 {azureManagementScope + ".default"},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {strings.TrimSpace(string(assertion))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(authorityHost, "/")+"/"+tenantID+"/oauth2/v2.0/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
+// managedIdentityRequest returns the request retrieving a token of the managed identity from IMDS
+func (p *acrProvider) managedIdentityRequest(ctx context.Context) (*http.Request, error) {
+	query := url.Values{
+		"api-version": {"2018-02-01"},
+		"resource":    {azureManagementScope},
+	}
+	if p.clientID != "" {
+		query.Set("client_id", p.clientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.imdsEndpoint+"/metadata/identity/oauth2/token?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata", "true")
+	return req, nil
+}
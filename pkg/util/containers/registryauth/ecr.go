@@ -0,0 +1,83 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package registryauth
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+)
+
+// ecrHostPattern matches the hosts of the ECR private registries, e.g. 123456789012.dkr.ecr.us-east-1.amazonaws.com
+var ecrHostPattern = regexp.MustCompile(`^\d{12}\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(\.cn)?$`)
+
+// ecrProvider retrieves an authorization token from ECR with the AWS credentials of the agent.
+// The credentials are loaded by the default configuration of the AWS SDK: from the environment
+// variables, the shared configuration files, the web identity token of the EKS service account,
+// the ECS container credentials or the EC2 instance profile.
+type ecrProvider struct {
+	client   *http.Client
+	region   string
+	endpoint string
+}
+
+func newECRProvider(client *http.Client, region string) *ecrProvider {
+	return &ecrProvider{
+		client: client,
+		region: region,
+	}
+}
+
+func (p *ecrProvider) credentials(ctx context.Context, host string) (*Credentials, error) {
+	region := p.region
+	if m := ecrHostPattern.FindStringSubmatch(host); m != nil && region == "" {
+		region = m[1]
+	}
+	if region == "" {
+		return nil, fmt.Errorf("no region configured for the ECR registry %s", host)
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region), awsconfig.WithHTTPClient(p.client))
+	if err != nil {
+		return nil, fmt.Errorf("could not load the AWS configuration: %w", err)
+	}
+	client := ecr.NewFromConfig(awsCfg, func(o *ecr.Options) {
+		if p.endpoint != "" {
+			o.BaseEndpoint = aws.String(p.endpoint)
+		}
+	})
+
+	output, err := client.GetAuthorizationToken(ctx, &ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		return nil, err
+	}
+	if len(output.AuthorizationData) == 0 || output.AuthorizationData[0].AuthorizationToken == nil {
+		return nil, errors.New("no authorization data returned by ECR")
+	}
+
+	data := output.AuthorizationData[0]
+	token, err := base64.StdEncoding.DecodeString(*data.AuthorizationToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ECR authorization token: %w", err)
+	}
+	username, password, found := strings.Cut(string(token), ":")
+	if !found {
+		return nil, errors.New("invalid ECR authorization token")
+	}
+	creds := &Credentials{Username: username, Password: password}
+	if data.ExpiresAt != nil {
+		creds.ExpiresAt = *data.ExpiresAt
+	}
+	return creds, nil
+}
@@ -0,0 +1,65 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package registryauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	gcpMetadataEndpoint = "http://metadata.google.internal"
+	// gcrUsername is the username used to authenticate against GCR and Artifact Registry with an access token
+	gcrUsername = "oauth2accesstoken"
+)
+
+// gcrProvider retrieves an access token of the service account of the agent from the metadata server.
+// On GKE with workload identity, the metadata server returns a token of the service account bound
+// to the Kubernetes service account of the agent.
+type gcrProvider struct {
+	client           *http.Client
+	metadataEndpoint string
+	now              func() time.Time
+}
+
+func newGCRProvider(client *http.Client) *gcrProvider {
+	p := &gcrProvider{
+		client:           client,
+		metadataEndpoint: gcpMetadataEndpoint,
+		now:              time.Now,
+	}
+	if host := os.Getenv("GCE_METADATA_HOST"); host != "" {
+		p.metadataEndpoint = "http://" + host
+	}
+	return p
+}
+
+func (p *gcrProvider) credentials(ctx context.Context, _ string) (*Credentials, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.metadataEndpoint+"/computeMetadata/v1/instance/service-accounts/default/token", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := doJSONRequest(p.client, req, &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("no access token returned by the metadata server")
+	}
+	return &Credentials{
+		Username:  gcrUsername,
+		Password:  token.AccessToken,
+		ExpiresAt: p.now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}, nil
+}
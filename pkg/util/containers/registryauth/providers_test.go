@@ -0,0 +1,130 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package registryauth
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestECRProvider(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	expiresAt := time.Now().Add(12 * time.Hour).Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "AmazonEC2ContainerRegistry_V20150921.GetAuthorizationToken", r.Header.Get("X-Amz-Target"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-3/ecr/aws4_request")
+		token := base64.StdEncoding.EncodeToString([]byte("AWS:ecr-password"))
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		fmt.Fprintf(w, `{"authorizationData": [{"authorizationToken": "%s", "expiresAt": %d}]}`, token, expiresAt.Unix())
+	}))
+	defer server.Close()
+
+	p := newECRProvider(server.Client(), "")
+	p.endpoint = server.URL
+
+	creds, err := p.credentials(context.Background(), "123456789012.dkr.ecr.eu-west-3.amazonaws.com")
+	require.NoError(t, err)
+	assert.Equal(t, "AWS", creds.Username)
+	assert.Equal(t, "ecr-password", creds.Password)
+	assert.True(t, expiresAt.Equal(creds.ExpiresAt))
+
+	_, err = p.credentials(context.Background(), "registry.example.com")
+	assert.EqualError(t, err, "no region configured for the ECR registry registry.example.com")
+}
+
+func TestGCRProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+		assert.Equal(t, "/computeMetadata/v1/instance/service-accounts/default/token", r.URL.Path)
+		fmt.Fprint(w, `{"access_token": "gcp-token", "expires_in": 3599, "token_type": "Bearer"}`)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	p := newGCRProvider(server.Client())
+	p.metadataEndpoint = server.URL
+	p.now = func() time.Time { return now }
+
+	creds, err := p.credentials(context.Background(), "gcr.io")
+	require.NoError(t, err)
+	assert.Equal(t, &Credentials{Username: "oauth2accesstoken", Password: "gcp-token", ExpiresAt: now.Add(3599 * time.Second)}, creds)
+}
+
+func TestACRProviderManagedIdentity(t *testing.T) {
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/metadata/identity/oauth2/token":
+			assert.Equal(t, "true", r.Header.Get("Metadata"))
+			assert.Equal(t, "client", r.URL.Query().Get("client_id"))
+			// IMDS returns expires_in as a string
+			fmt.Fprint(w, `{"access_token": "aad-token", "expires_in": "86399"}`)
+		case "/oauth2/exchange":
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "aad-token", r.Form.Get("access_token"))
+			assert.Equal(t, r.Host, r.Form.Get("service"))
+			fmt.Fprint(w, `{"refresh_token": "acr-token"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	p := newACRProvider(server.Client(), "client")
+	p.imdsEndpoint = server.URL
+	p.scheme = "http"
+	p.now = func() time.Time { return now }
+
+	creds, err := p.credentials(context.Background(), strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	assert.Equal(t, &Credentials{Username: acrUsername, Password: "acr-token", ExpiresAt: now.Add(acrRefreshTokenLifetime)}, creds)
+}
+
+func TestACRProviderWorkloadIdentity(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("federated-token"), 0600))
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", tokenFile)
+	t.Setenv("AZURE_CLIENT_ID", "client")
+	t.Setenv("AZURE_TENANT_ID", "tenant")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "/tenant/oauth2/v2.0/token", r.URL.Path)
+		assert.Equal(t, "federated-token", r.Form.Get("client_assertion"))
+		assert.Equal(t, "client", r.Form.Get("client_id"))
+		fmt.Fprint(w, `{"access_token": "aad-token", "expires_in": 600}`)
+	}))
+	defer server.Close()
+	t.Setenv("AZURE_AUTHORITY_HOST", server.URL)
+
+	now := time.Now()
+	p := newACRProvider(server.Client(), "")
+	p.now = func() time.Time { return now }
+
+	token, expiresAt, err := p.aadToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "aad-token", token)
+	assert.Equal(t, now.Add(10*time.Minute), expiresAt)
+}
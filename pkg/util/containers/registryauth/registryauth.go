@@ -0,0 +1,257 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package registryauth provides the credentials used to retrieve the content of container
+// images from private registries. The credentials are configured per registry prefix with
+// container_image.registry_credentials and come from the cloud provider identity of the
+// agent (ECR, GCR/Artifact Registry, ACR) or from static credentials.
+package registryauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+	"github.com/DataDog/datadog-agent/pkg/config/structure"
+)
+
+const (
+	// ConfigKey is the configuration key holding the registry credentials
+	ConfigKey = "container_image.registry_credentials"
+
+	// refreshWindow is how long before their expiration the credentials are retrieved again
+	refreshWindow = 5 * time.Minute
+
+	defaultTimeout  = 10 * time.Second
+	maxResponseSize = 1024 * 1024
+
+	dockerHubRegistry = "docker.io"
+	dockerHubHost     = "registry-1.docker.io"
+)
+
+// Credentials are the credentials used to authenticate against a registry. The expiration of
+// static credentials is zero.
+type Credentials struct {
+	Username  string
+	Password  string
+	ExpiresAt time.Time
+}
+
+// RegistryConfig is an entry of container_image.registry_credentials
+type RegistryConfig struct {
+	// Prefix is matched against the image references, e.g. "123456789012.dkr.ecr.us-east-1.amazonaws.com"
+	// or "europe-docker.pkg.dev/my-project". The longest matching prefix is used.
+	Prefix string `mapstructure:"prefix"`
+	// Provider is one of ecr, gcr, acr or basic
+	Provider string `mapstructure:"provider"`
+	// Region of the ECR registry, guessed from its host when empty
+	Region string `mapstructure:"region"`
+	// ClientID of the Azure managed identity, the system assigned identity is used when empty
+	ClientID string `mapstructure:"client_id"`
+	// Username and Password are the static credentials of the basic provider
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// provider retrieves the credentials of a registry host
+type provider interface {
+	credentials(ctx context.Context, host string) (*Credentials, error)
+}
+
+type entry struct {
+	prefix   string
+	provider provider
+}
+
+type cacheKey struct {
+	prefix string
+	host   string
+}
+
+// Chain resolves the credentials of image references, caching them until they are about to expire
+type Chain struct {
+	entries []entry
+	now     func() time.Time
+
+	mu    sync.Mutex
+	cache map[cacheKey]*Credentials
+}
+
+// NewChainFromConfig returns the chain configured with container_image.registry_credentials
+func NewChainFromConfig(cfg model.Reader) (*Chain, error) {
+	var configs []RegistryConfig
+	if err := structure.UnmarshalKey(cfg, ConfigKey, &configs); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ConfigKey, err)
+	}
+	return NewChain(configs, &http.Client{Timeout: defaultTimeout})
+}
+
+// NewChain returns a chain resolving the credentials with the given configuration
+func NewChain(configs []RegistryConfig, client *http.Client) (*Chain, error) {
+	c := &Chain{
+		now:   time.Now,
+		cache: make(map[cacheKey]*Credentials),
+	}
+	for _, cfg := range configs {
+		prefix := strings.TrimSuffix(cfg.Prefix, "/")
+		if prefix == "" {
+			return nil, fmt.Errorf("missing prefix for the %s registry credentials provider", cfg.Provider)
+		}
+
+		var p provider
+		switch cfg.Provider {
+		case "ecr":
+			p = newECRProvider(client, cfg.Region)
+		case "gcr":
+			p = newGCRProvider(client)
+		case "acr":
+			p = newACRProvider(client, cfg.ClientID)
+		case "basic":
+			p = &basicProvider{creds: Credentials{Username: cfg.Username, Password: cfg.Password}}
+		default:
+			return nil, fmt.Errorf("unknown registry credentials provider '%s' for prefix %s", cfg.Provider, prefix)
+		}
+		c.entries = append(c.entries, entry{prefix: prefix, provider: p})
+	}
+
+	sort.SliceStable(c.entries, func(i, j int) bool {
+		return len(c.entries[i].prefix) > len(c.entries[j].prefix)
+	})
+	return c, nil
+}
+
+// Empty returns true when no registry credentials are configured
+func (c *Chain) Empty() bool {
+	return c == nil || len(c.entries) == 0
+}
+
+// Credentials returns the credentials of the registry of the given image reference, or nil when
+// no credentials are configured for it and the registry must be accessed anonymously.
+func (c *Chain) Credentials(ctx context.Context, ref string) (*Credentials, error) {
+	if c.Empty() {
+		return nil, nil
+	}
+
+	name := normalizeReference(ref)
+	for _, e := range c.entries {
+		if !matchPrefix(name, e.prefix) {
+			continue
+		}
+
+		host := registryHost(name)
+		key := cacheKey{prefix: e.prefix, host: host}
+
+		c.mu.Lock()
+		creds, ok := c.cache[key]
+		c.mu.Unlock()
+		if ok && (creds.ExpiresAt.IsZero() || c.now().Add(refreshWindow).Before(creds.ExpiresAt)) {
+			return creds, nil
+		}
+
+		// the lock isn't held while the credentials are retrieved so that a slow provider doesn't
+		// block the pulls of the images of the other registries
+		creds, err := e.provider.credentials(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve the credentials of %s: %w", host, err)
+		}
+		c.mu.Lock()
+		c.cache[key] = creds
+		c.mu.Unlock()
+		return creds, nil
+	}
+	return nil, nil
+}
+
+// HostCredentials returns a callback returning the credentials of the registry hosts serving the
+// given image reference, in the format expected by the containerd resolvers
+func (c *Chain) HostCredentials(ctx context.Context, ref string) func(host string) (string, string, error) {
+	registry := registryHost(normalizeReference(ref))
+	return func(host string) (string, string, error) {
+		// the credentials of the registry must not be sent to the other hosts it may redirect to
+		if host != registry && !(registry == dockerHubRegistry && host == dockerHubHost) {
+			return "", "", nil
+		}
+		creds, err := c.Credentials(ctx, ref)
+		if err != nil || creds == nil {
+			return "", "", err
+		}
+		return creds.Username, creds.Password, nil
+	}
+}
+
+// normalizeReference returns the reference without tag nor digest, prefixed with its registry
+func normalizeReference(ref string) string {
+	name := ref
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 1 || !(strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		if len(parts) == 1 {
+			name = "library/" + name
+		}
+		name = dockerHubRegistry + "/" + name
+	}
+	return name
+}
+
+func registryHost(name string) string {
+	return strings.SplitN(name, "/", 2)[0]
+}
+
+// matchPrefix returns true if the prefix matches whole path components of the name
+func matchPrefix(name, prefix string) bool {
+	return name == prefix || strings.HasPrefix(name, prefix+"/")
+}
+
+// basicProvider returns static credentials
+type basicProvider struct {
+	creds Credentials
+}
+
+func (p *basicProvider) credentials(context.Context, string) (*Credentials, error) {
+	creds := p.creds
+	return &creds, nil
+}
+
+type httpError struct {
+	statusCode int
+	body       string
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("unexpected status code %d: %s", e.statusCode, e.body)
+}
+
+func doJSONRequest(client *http.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("error reading the response of %s: %s", req.URL.Host, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &httpError{statusCode: resp.StatusCode, body: strings.TrimSpace(string(body))}
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("could not unmarshal the response of %s: %s", req.URL.Host, err)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package registryauth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+)
+
+type countingProvider struct {
+	calls int
+	creds Credentials
+}
+
+func (p *countingProvider) credentials(_ context.Context, host string) (*Credentials, error) {
+	p.calls++
+	creds := p.creds
+	creds.Username = host
+	return &creds, nil
+}
+
+// blockingProvider blocks until its context is cancelled
+type blockingProvider struct {
+	started chan struct{}
+}
+
+func (p *blockingProvider) credentials(ctx context.Context, _ string) (*Credentials, error) {
+	close(p.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestChainCredentialsConcurrentProviders(t *testing.T) {
+	blocking := &blockingProvider{started: make(chan struct{})}
+	c := &Chain{
+		entries: []entry{
+			{prefix: "slow.example.com", provider: blocking},
+			{prefix: "fast.example.com", provider: &countingProvider{}},
+		},
+		now:   time.Now,
+		cache: make(map[cacheKey]*Credentials),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+	go func() {
+		_, err := c.Credentials(ctx, "slow.example.com/app")
+		errs <- err
+	}()
+	<-blocking.started
+
+	// the credentials of the other registries are retrieved while the slow provider is called
+	creds, err := c.Credentials(context.Background(), "fast.example.com/app")
+	require.NoError(t, err)
+	assert.Equal(t, "fast.example.com", creds.Username)
+
+	cancel()
+	assert.ErrorIs(t, <-errs, context.Canceled)
+}
+
+func TestNormalizeReference(t *testing.T) {
+	for ref, expected := range map[string]string{
+		"redis":                     "docker.io/library/redis",
+		"redis:7":                   "docker.io/library/redis",
+		"datadog/agent:7":           "docker.io/datadog/agent",
+		"localhost/app":             "localhost/app",
+		"localhost:5000/app:latest": "localhost:5000/app",
+		"123456789012.dkr.ecr.us-east-1.amazonaws.com/app@sha256:abcd": "123456789012.dkr.ecr.us-east-1.amazonaws.com/app",
+		"europe-docker.pkg.dev/project/repo/app:1.0@sha256:abcd":       "europe-docker.pkg.dev/project/repo/app",
+	} {
+		assert.Equal(t, expected, normalizeReference(ref), ref)
+	}
+}
+
+func TestChainCredentials(t *testing.T) {
+	registry := &countingProvider{creds: Credentials{Password: "registry"}}
+	project := &countingProvider{creds: Credentials{Password: "project"}}
+	c := &Chain{
+		entries: []entry{
+			{prefix: "europe-docker.pkg.dev/project", provider: project},
+			{prefix: "europe-docker.pkg.dev", provider: registry},
+		},
+		now:   time.Now,
+		cache: make(map[cacheKey]*Credentials),
+	}
+
+	creds, err := c.Credentials(context.Background(), "europe-docker.pkg.dev/project/app:1")
+	require.NoError(t, err)
+	assert.Equal(t, "project", creds.Password)
+
+	creds, err = c.Credentials(context.Background(), "europe-docker.pkg.dev/project-2/app:1")
+	require.NoError(t, err)
+	assert.Equal(t, "registry", creds.Password)
+
+	creds, err = c.Credentials(context.Background(), "gcr.io/project/app:1")
+	require.NoError(t, err)
+	assert.Nil(t, creds)
+
+	// static credentials are never retrieved again
+	_, err = c.Credentials(context.Background(), "europe-docker.pkg.dev/project/other")
+	require.NoError(t, err)
+	assert.Equal(t, 1, project.calls)
+}
+
+func TestChainCredentialsRefresh(t *testing.T) {
+	now := time.Now()
+	p := &countingProvider{creds: Credentials{Password: "token", ExpiresAt: now.Add(time.Hour)}}
+	c := &Chain{
+		entries: []entry{{prefix: "myregistry.azurecr.io", provider: p}},
+		now:     func() time.Time { return now },
+		cache:   make(map[cacheKey]*Credentials),
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := c.Credentials(context.Background(), "myregistry.azurecr.io/app")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, p.calls)
+
+	// the credentials are retrieved again before they expire
+	now = now.Add(time.Hour - time.Minute)
+	_, err := c.Credentials(context.Background(), "myregistry.azurecr.io/app")
+	require.NoError(t, err)
+	assert.Equal(t, 2, p.calls)
+}
+
+func TestHostCredentials(t *testing.T) {
+	c, err := NewChain([]RegistryConfig{
+		{Prefix: "docker.io/myorg", Provider: "basic", Username: "user", Password: "pass"},
+	}, http.DefaultClient)
+	require.NoError(t, err)
+
+	creds := c.HostCredentials(context.Background(), "myorg/app:1")
+	username, password, err := creds("registry-1.docker.io")
+	require.NoError(t, err)
+	assert.Equal(t, "user", username)
+	assert.Equal(t, "pass", password)
+
+	// credentials aren't sent to other hosts
+	username, password, err = creds("auth.example.com")
+	require.NoError(t, err)
+	assert.Empty(t, username)
+	assert.Empty(t, password)
+}
+
+func TestNewChainFromConfig(t *testing.T) {
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource(ConfigKey, []map[string]interface{}{
+		{"prefix": "123456789012.dkr.ecr.us-east-1.amazonaws.com", "provider": "ecr"},
+		{"prefix": "gcr.io/project/", "provider": "gcr"},
+		{"prefix": "myregistry.azurecr.io", "provider": "acr", "client_id": "client"},
+	})
+
+	c, err := NewChainFromConfig(cfg)
+	require.NoError(t, err)
+	require.Len(t, c.entries, 3)
+	assert.Equal(t, "123456789012.dkr.ecr.us-east-1.amazonaws.com", c.entries[0].prefix)
+	assert.Equal(t, "myregistry.azurecr.io", c.entries[1].prefix)
+	assert.Equal(t, "gcr.io/project", c.entries[2].prefix)
+	assert.Equal(t, "client", c.entries[1].provider.(*acrProvider).clientID)
+
+	cfg.SetWithoutSource(ConfigKey, []map[string]interface{}{{"prefix": "quay.io", "provider": "quay"}})
+	_, err = NewChainFromConfig(cfg)
+	assert.EqualError(t, err, "unknown registry credentials provider 'quay' for prefix quay.io")
+
+	cfg.SetWithoutSource(ConfigKey, []map[string]interface{}{})
+	c, err = NewChainFromConfig(cfg)
+	require.NoError(t, err)
+	assert.True(t, c.Empty())
+}
@@ -0,0 +1,33 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build docker
+
+package docker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/util/containers/capabilities"
+)
+
+func init() {
+	capabilities.RegisterProber("docker", probe)
+}
+
+func probe(ctx context.Context) (string, []capabilities.Capability, error) {
+	du, err := GetDockerUtil()
+	if err != nil {
+		return "", nil, fmt.Errorf("error connecting to docker: %w", err)
+	}
+
+	v, err := du.RawClient().ServerVersion(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("error getting the docker version: %w", err)
+	}
+
+	return v.Version, []capabilities.Capability{capabilities.Stats, capabilities.Events, capabilities.Exec}, nil
+}
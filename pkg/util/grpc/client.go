@@ -0,0 +1,124 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/keepalive"
+
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// clientKeepaliveTime is the time after which the internal clients ping
+	// the server when they didn't receive anything, so that a stalled
+	// connection is detected even while a stream is idle.
+	clientKeepaliveTime = 20 * time.Second
+	// clientKeepaliveTimeout is the time the internal clients wait for the
+	// answer to a ping before closing the connection.
+	clientKeepaliveTimeout = 10 * time.Second
+	// clientMaxMessageSize is the maximum size of the messages sent and
+	// received by the internal clients.
+	clientMaxMessageSize = 64 << 20 // 64 MB
+	// serverKeepaliveMinTime is the minimum time between two pings accepted by
+	// the internal servers. It must be lower than clientKeepaliveTime, else the
+	// servers close the connections of the clients pinging too often.
+	serverKeepaliveMinTime = 10 * time.Second
+)
+
+// clientBackoffConfig is the backoff used by the internal clients to reconnect
+var clientBackoffConfig = backoff.Config{
+	BaseDelay:  1 * time.Second,
+	Multiplier: 1.6,
+	Jitter:     0.2,
+	MaxDelay:   5 * time.Second,
+}
+
+var (
+	tlmConnectionState = telemetry.NewGauge("grpc_client", "connection_state",
+		[]string{"client", "target", "state"}, "Set to 1 for the current state of the connection of an internal gRPC client")
+	tlmStateChanges = telemetry.NewCounter("grpc_client", "state_changes",
+		[]string{"client", "target", "state"}, "Count of the state changes of the connection of an internal gRPC client")
+)
+
+// ClientDialOptions returns the dial options shared by the internal gRPC
+// clients: keepalive, reconnection backoff and max message sizes.
+func ClientDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                clientKeepaliveTime,
+			Timeout:             clientKeepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           clientBackoffConfig,
+			MinConnectTimeout: 5 * time.Second,
+		}),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(clientMaxMessageSize),
+			grpc.MaxCallSendMsgSize(clientMaxMessageSize),
+		),
+	}
+}
+
+// ServerKeepaliveOptions returns the server options accepting the pings of the
+// clients created with ClientDialOptions.
+func ServerKeepaliveOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             serverKeepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
+	}
+}
+
+// NewClientConn creates a connection to an internal gRPC server using the
+// ClientDialOptions, followed by opts. The connection isn't blocking, and its
+// state is reported in the grpc_client telemetry, tagged with the client name
+// and the target, until it's closed.
+func NewClientConn(ctx context.Context, client string, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	conn, err := grpc.DialContext(ctx, target, append(ClientDialOptions(), opts...)...) //nolint:staticcheck // TODO (ASC) fix grpc.DialContext is deprecated
+	if err != nil {
+		return nil, err
+	}
+
+	go watchConnectionState(conn, func(previous, state connectivity.State) {
+		if previous != state {
+			tlmConnectionState.Delete(client, target, previous.String())
+		}
+		if state == connectivity.Shutdown {
+			return
+		}
+		tlmConnectionState.Set(1, client, target, state.String())
+		tlmStateChanges.Inc(client, target, state.String())
+		log.Debugf("grpc %s client connection to %s is %s", client, target, state)
+	})
+
+	return conn, nil
+}
+
+// watchConnectionState calls onChange with the previous and the new state of
+// the connection each time it changes, until the connection is closed. The
+// first call receives the initial state as both the previous and the new one.
+func watchConnectionState(conn *grpc.ClientConn, onChange func(previous, state connectivity.State)) {
+	previous := conn.GetState()
+	onChange(previous, previous)
+
+	for previous != connectivity.Shutdown {
+		if !conn.WaitForStateChange(context.Background(), previous) {
+			return
+		}
+		state := conn.GetState()
+		onChange(previous, state)
+		previous = state
+	}
+}
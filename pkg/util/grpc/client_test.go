@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func startServer(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := grpc.NewServer(ServerKeepaliveOptions()...)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	return lis.Addr().String()
+}
+
+func TestNewClientConn(t *testing.T) {
+	target := startServer(t)
+
+	conn, err := NewClientConn(context.Background(), "test", target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	assert.Eventually(t, func() bool {
+		return conn.GetState() == connectivity.Ready
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestWatchConnectionState(t *testing.T) {
+	target := startServer(t)
+
+	conn, err := grpc.DialContext(context.Background(), target, grpc.WithTransportCredentials(insecure.NewCredentials())) //nolint:staticcheck // TODO (ASC) fix grpc.DialContext is deprecated
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var states []connectivity.State
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watchConnectionState(conn, func(previous, state connectivity.State) {
+			mu.Lock()
+			defer mu.Unlock()
+			if len(states) > 0 {
+				assert.Equal(t, states[len(states)-1], previous)
+			}
+			states = append(states, state)
+		})
+	}()
+
+	conn.Connect()
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(states) > 0 && states[len(states)-1] == connectivity.Ready
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the watcher stops once the connection is closed
+	require.NoError(t, conn.Close())
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the connection state is still watched after the connection was closed")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, connectivity.Shutdown, states[len(states)-1])
+}
@@ -44,10 +44,10 @@ func NewMockGrpcSecureServer(port string) (*grpc.Server, string, error) {
 		return nil, "", err
 	}
 
-	serverOpts := []grpc.ServerOption{
+	serverOpts := append([]grpc.ServerOption{
 		grpc.Creds(credentials.NewServerTLSFromCert(tlsKeyPair)),
 		grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(StaticAuthInterceptor(authToken))),
-	}
+	}, ServerKeepaliveOptions()...)
 
 	// Start dummy gRPc server mocking the core agent
 	serverListener, err := net.Listen("tcp", "127.0.0.1:"+port)
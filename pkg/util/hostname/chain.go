@@ -0,0 +1,197 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build !serverless
+
+package hostname
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"strings"
+	"time"
+
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/persistentcache"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// persistentCacheKeyPrefix prefixes the key of the hostname persisted in the run path
+const persistentCacheKeyPrefix = "resolved_"
+
+// chainEntry is a provider listed in 'hostname_providers'
+type chainEntry struct {
+	provider provider
+	// timeout bounds the time spent in the provider, 0 means no timeout
+	timeout time.Duration
+}
+
+// getChainProvider returns the provider with the given name, as displayed in the status page.
+//
+// The providers of a configured chain aren't coupled: the first one finding a hostname wins. This
+// means that the EC2 instance ID is used whenever 'aws' is reached.
+func getChainProvider(name string, legacyHostnameResolution bool) (provider, bool) {
+	switch name {
+	case configProvider.name:
+		return configProvider, true
+	case hostnameFileProvider.name:
+		return hostnameFileProvider, true
+	case fargateProvider.name:
+		return fargateProvider, true
+	case gceProvider.name:
+		return gceProvider, true
+	case azureProvider.name:
+		return azureProvider, true
+	case fqdnProvider.name:
+		return fqdnProvider, true
+	case containerProvider.name:
+		return containerProvider, true
+	case osProvider.name:
+		return osProvider, true
+	case ec2Provider.name:
+		return provider{
+			name: ec2Provider.name,
+			cb: func(ctx context.Context, _ string) (string, error) {
+				return getValidEC2Hostname(ctx, legacyHostnameResolution)
+			},
+			expvarName: ec2Provider.expvarName,
+		}, true
+	}
+	return provider{}, false
+}
+
+// parseProviderChain parses the 'hostname_providers' entries: a provider name, optionally followed by
+// the provider timeout, e.g. 'aws:2s'
+func parseProviderChain(entries []string, legacyHostnameResolution bool) ([]chainEntry, error) {
+	chain := make([]chainEntry, 0, len(entries))
+	for _, entry := range entries {
+		name, rawTimeout, hasTimeout := strings.Cut(strings.TrimSpace(entry), ":")
+
+		p, ok := getChainProvider(name, legacyHostnameResolution)
+		if !ok {
+			return nil, fmt.Errorf("unknown hostname provider '%s'", name)
+		}
+
+		var timeout time.Duration
+		if hasTimeout {
+			var err error
+			timeout, err = time.ParseDuration(rawTimeout)
+			if err != nil || timeout <= 0 {
+				return nil, fmt.Errorf("invalid timeout '%s' for hostname provider '%s'", rawTimeout, name)
+			}
+		}
+		chain = append(chain, chainEntry{provider: p, timeout: timeout})
+	}
+	return chain, nil
+}
+
+// getConfiguredChain returns the providers listed in 'hostname_providers', if any
+func getConfiguredChain(legacyHostnameResolution bool) ([]chainEntry, bool) {
+	entries := pkgconfigsetup.Datadog().GetStringSlice("hostname_providers")
+	if len(entries) == 0 {
+		return nil, false
+	}
+
+	chain, err := parseProviderChain(entries, legacyHostnameResolution)
+	if err != nil {
+		log.Errorf("Ignoring 'hostname_providers', falling back to the default hostname resolution: %s", err)
+		return nil, false
+	}
+	return chain, true
+}
+
+// callWithTimeout calls the provider, returning early if it doesn't answer before the timeout
+func callWithTimeout(ctx context.Context, p provider, timeout time.Duration) (string, error) {
+	if timeout == 0 {
+		return p.cb(ctx, "")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		hostname string
+		err      error
+	}
+	// some providers ignore the context, e.g. when running a command
+	done := make(chan result, 1)
+	go func() {
+		hostname, err := p.cb(ctx, "")
+		done <- result{hostname: hostname, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.hostname, r.err
+	case <-ctx.Done():
+		return "", fmt.Errorf("timed out after %s", timeout)
+	}
+}
+
+// resolveFromChain goes through the providers listed in 'hostname_providers', in order, until one finds
+// a hostname. The hostname found is persisted, to be used after a restart if no provider succeeds.
+func resolveFromChain(ctx context.Context, chain []chainEntry, keyCache string, diagnosis *Diagnosis) (Data, error) {
+	diagnosis.Configured = true
+
+	for _, entry := range chain {
+		p := entry.provider
+		log.Debugf("trying to get hostname from '%s' provider", p.name)
+		diagnosis.Chain = append(diagnosis.Chain, p.name)
+
+		start := time.Now()
+		hostname, err := callWithTimeout(ctx, p, entry.timeout)
+		diagnosis.addAttempt(p.name, hostname, err, time.Since(start))
+		if err != nil {
+			expErr := new(expvar.String)
+			expErr.Set(err.Error())
+			hostnameErrors.Set(p.expvarName, expErr)
+			log.Debugf("unable to get the hostname from '%s' provider: %s", p.name, err)
+			continue
+		}
+
+		log.Debugf("hostname provider '%s' succeeded, stopping here with hostname '%s'", p.name, hostname)
+		data := Data{Hostname: hostname, Provider: p.name}
+		persistHostname(keyCache, data)
+		diagnosis.setResult(hostname, p.name)
+		return data, nil
+	}
+
+	if data, found := readPersistedHostname(keyCache); found {
+		log.Warnf("No hostname provider succeeded, using the hostname '%s' found by the '%s' provider before the Agent restarted", data.Hostname, data.Provider)
+		diagnosis.FromPersistedCache = true
+		diagnosis.setResult(data.Hostname, data.Provider)
+		return data, nil
+	}
+
+	err := fmt.Errorf("unable to determine the host name with the providers listed in 'hostname_providers': %s", strings.Join(diagnosis.Chain, ", "))
+	expErr := new(expvar.String)
+	expErr.Set(err.Error())
+	hostnameErrors.Set("all", expErr)
+	return Data{}, err
+}
+
+func persistHostname(keyCache string, data Data) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	if err := persistentcache.Write(persistentCacheKeyPrefix+keyCache, string(raw)); err != nil {
+		log.Debugf("could not persist the hostname: %s", err)
+	}
+}
+
+func readPersistedHostname(keyCache string) (Data, bool) {
+	raw, err := persistentcache.Read(persistentCacheKeyPrefix + keyCache)
+	if err != nil || raw == "" {
+		return Data{}, false
+	}
+	var data Data
+	if err := json.Unmarshal([]byte(raw), &data); err != nil || data.Provider == "" {
+		return Data{}, false
+	}
+	return data, true
+}
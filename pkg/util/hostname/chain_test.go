@@ -0,0 +1,154 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build !serverless
+
+package hostname
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/util/cache"
+)
+
+func setupChainTest(t *testing.T, tc testCase, providers ...string) {
+	setupHostnameTest(t, tc)
+	pkgconfigsetup.Datadog().SetWithoutSource("run_path", t.TempDir())
+	pkgconfigsetup.Datadog().SetWithoutSource("hostname_providers", providers)
+}
+
+func TestChainOrder(t *testing.T) {
+	setupChainTest(t, testCase{
+		configHostname: true,
+		GCE:            true,
+		OS:             true,
+		EC2:            true,
+	}, "os", "aws", "configuration")
+
+	data, err := GetWithProvider(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, "hostname-from-os", data.Hostname)
+	assert.Equal(t, "os", data.Provider)
+
+	diagnosis, err := GetDiagnosis(context.TODO())
+	require.NoError(t, err)
+	assert.True(t, diagnosis.Configured)
+	assert.Equal(t, []string{"os"}, diagnosis.Chain)
+	assert.Equal(t, "os", diagnosis.Provider)
+}
+
+func TestChainSkipsFailingProviders(t *testing.T) {
+	setupChainTest(t, testCase{
+		EC2: true,
+	}, "gce", "azure", "aws", "os")
+
+	data, err := GetWithProvider(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, "hostname-from-ec2", data.Hostname)
+	assert.Equal(t, "aws", data.Provider)
+
+	diagnosis, err := GetDiagnosis(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gce", "azure", "aws"}, diagnosis.Chain)
+	require.Len(t, diagnosis.Attempts, 3)
+	assert.NotEmpty(t, diagnosis.Attempts[0].Error)
+	assert.NotEmpty(t, diagnosis.Attempts[1].Error)
+	assert.Equal(t, "hostname-from-ec2", diagnosis.Attempts[2].Hostname)
+}
+
+func TestChainTimeout(t *testing.T) {
+	setupChainTest(t, testCase{
+		OS: true,
+	}, "gce:10ms", "os")
+
+	block := make(chan struct{})
+	defer close(block)
+	gceGetHostname = func(context.Context) (string, error) {
+		<-block
+		return "hostname-from-gce", nil
+	}
+
+	data, err := GetWithProvider(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, "hostname-from-os", data.Hostname)
+
+	diagnosis, err := GetDiagnosis(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, diagnosis.Attempts, 2)
+	assert.Equal(t, "timed out after 10ms", diagnosis.Attempts[0].Error)
+}
+
+func TestChainPersistedFallback(t *testing.T) {
+	setupChainTest(t, testCase{
+		GCE: true,
+	}, "gce")
+
+	data, err := GetWithProvider(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, "hostname-from-gce", data.Hostname)
+
+	// restart of the Agent: the in-memory cache is gone and no provider succeeds
+	cache.Cache.Delete(cache.BuildAgentKey("hostname"))
+	gceGetHostname = func(context.Context) (string, error) { return "", fmt.Errorf("some error") }
+
+	data, err = GetWithProvider(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, "hostname-from-gce", data.Hostname)
+	assert.Equal(t, "gce", data.Provider)
+
+	diagnosis, err := GetDiagnosis(context.TODO())
+	require.NoError(t, err)
+	assert.True(t, diagnosis.FromPersistedCache)
+}
+
+func TestChainAllFailing(t *testing.T) {
+	setupChainTest(t, testCase{}, "gce", "azure")
+
+	_, err := GetWithProvider(context.TODO())
+	assert.Error(t, err)
+
+	diagnosis, err := GetDiagnosis(context.TODO())
+	assert.Error(t, err)
+	assert.Len(t, diagnosis.Attempts, 2)
+	assert.Empty(t, diagnosis.Provider)
+}
+
+func TestChainInvalidFallsBackToDefault(t *testing.T) {
+	setupChainTest(t, testCase{
+		configHostname: true,
+		OS:             true,
+	}, "os", "unknown")
+
+	data, err := GetWithProvider(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, "hostname-from-configuration", data.Hostname)
+
+	diagnosis, err := GetDiagnosis(context.TODO())
+	require.NoError(t, err)
+	assert.False(t, diagnosis.Configured)
+}
+
+func TestParseProviderChain(t *testing.T) {
+	chain, err := parseProviderChain([]string{"configuration", "aws:2s", " os "}, false)
+	require.NoError(t, err)
+	require.Len(t, chain, 3)
+	assert.Equal(t, "configuration", chain[0].provider.name)
+	assert.Zero(t, chain[0].timeout)
+	assert.Equal(t, "aws", chain[1].provider.name)
+	assert.Equal(t, 2*time.Second, chain[1].timeout)
+	assert.Equal(t, "os", chain[2].provider.name)
+
+	_, err = parseProviderChain([]string{"aws:abc"}, false)
+	assert.Error(t, err)
+	_, err = parseProviderChain([]string{"aws:-1s"}, false)
+	assert.Error(t, err)
+}
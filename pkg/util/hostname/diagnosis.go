@@ -0,0 +1,74 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package hostname
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ProviderAttempt is the outcome of a hostname provider
+type ProviderAttempt struct {
+	Provider string `json:"provider"`
+	Hostname string `json:"hostname,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Diagnosis explains how the hostname of the Agent was resolved
+type Diagnosis struct {
+	Hostname string `json:"hostname"`
+	Provider string `json:"provider"`
+	// Configured is true when the providers are the ones listed in 'hostname_providers'
+	Configured bool `json:"configured"`
+	// Chain is the ordered list of providers the hostname is resolved with
+	Chain []string `json:"chain"`
+	// FromPersistedCache is true when no provider succeeded and the hostname resolved before the last
+	// restart of the Agent is used instead
+	FromPersistedCache bool              `json:"from_persisted_cache"`
+	Attempts           []ProviderAttempt `json:"attempts"`
+}
+
+var (
+	diagnosisMutex sync.Mutex
+	lastDiagnosis  Diagnosis
+)
+
+func (d *Diagnosis) addAttempt(provider, hostname string, err error, duration time.Duration) {
+	attempt := ProviderAttempt{
+		Provider: provider,
+		Duration: duration.String(),
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	} else {
+		attempt.Hostname = hostname
+	}
+	d.Attempts = append(d.Attempts, attempt)
+}
+
+func (d *Diagnosis) setResult(hostname, provider string) {
+	d.Hostname = hostname
+	d.Provider = provider
+}
+
+func setDiagnosis(d *Diagnosis) {
+	diagnosisMutex.Lock()
+	defer diagnosisMutex.Unlock()
+	lastDiagnosis = *d
+}
+
+// GetDiagnosis returns how the hostname of the Agent was resolved: which providers were tried, in which
+// order, and which one won. The diagnosis is returned along with the error when no hostname was found.
+func GetDiagnosis(ctx context.Context) (Diagnosis, error) {
+	// the hostname is resolved once, and then cached
+	_, err := GetWithProvider(ctx)
+
+	diagnosisMutex.Lock()
+	defer diagnosisMutex.Unlock()
+	return lastDiagnosis, err
+}
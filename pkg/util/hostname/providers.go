@@ -11,6 +11,7 @@ import (
 	"context"
 	"expvar"
 	"fmt"
+	"time"
 
 	"github.com/DataDog/datadog-agent/comp/core/hostname/hostnameinterface"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
@@ -197,14 +198,36 @@ func getHostname(ctx context.Context, keyCache string, legacyHostnameResolution
 		return cacheHostname.(Data), nil
 	}
 
+	diagnosis := &Diagnosis{}
+	var data Data
+	var err error
+	if chain, configured := getConfiguredChain(legacyHostnameResolution); configured {
+		data, err = resolveFromChain(ctx, chain, keyCache, diagnosis)
+	} else {
+		data, err = resolveFromCatalog(ctx, legacyHostnameResolution, diagnosis)
+	}
+	if !legacyHostnameResolution {
+		setDiagnosis(diagnosis)
+	}
+	if err != nil {
+		return Data{}, err
+	}
+	return saveHostname(cacheHostnameKey, data.Hostname, data.Provider, legacyHostnameResolution), nil
+}
+
+// resolveFromCatalog goes through the default list of providers
+func resolveFromCatalog(ctx context.Context, legacyHostnameResolution bool, diagnosis *Diagnosis) (Data, error) {
 	var err error
 	var hostname string
 	var providerName string
 
 	for _, p := range getProviderCatalog(legacyHostnameResolution) {
 		log.Debugf("trying to get hostname from '%s' provider", p.name)
+		diagnosis.Chain = append(diagnosis.Chain, p.name)
 
+		start := time.Now()
 		detectedHostname, err := p.cb(ctx, hostname)
+		diagnosis.addAttempt(p.name, detectedHostname, err, time.Since(start))
 		if err != nil {
 			expErr := new(expvar.String)
 			expErr.Set(err.Error())
@@ -219,7 +242,8 @@ func getHostname(ctx context.Context, keyCache string, legacyHostnameResolution
 
 		if p.stopIfSuccessful {
 			log.Debugf("hostname provider '%s' succeeded, stoping here with hostname '%s'", p.name, detectedHostname)
-			return saveHostname(cacheHostnameKey, hostname, p.name, legacyHostnameResolution), nil
+			diagnosis.setResult(hostname, p.name)
+			return Data{Hostname: hostname, Provider: p.name}, nil
 
 		}
 	}
@@ -227,7 +251,8 @@ func getHostname(ctx context.Context, keyCache string, legacyHostnameResolution
 	warnAboutFQDN(ctx, hostname)
 
 	if hostname != "" {
-		return saveHostname(cacheHostnameKey, hostname, providerName, legacyHostnameResolution), nil
+		diagnosis.setResult(hostname, providerName)
+		return Data{Hostname: hostname, Provider: providerName}, nil
 	}
 
 	err = fmt.Errorf("unable to reliably determine the host name. You can define one in the agent config file or in your hosts file")
@@ -0,0 +1,332 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+
+	pkgconfigmodel "github.com/DataDog/datadog-agent/pkg/config/model"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Destination identifies a kind of outbound connection of the agent, the
+// settings of its HTTP clients can be overridden in the `http_destinations`
+// configuration, e.g.:
+//
+//	http_destinations:
+//	  logs:
+//	    min_tls_version: tlsv1.3
+//	    ca_certs: [/etc/ssl/certs/corporate.pem]
+//	    http2: false
+//	  remote_config:
+//	    use_proxy: false
+//
+// The settings of the `default` destination apply to all the destinations.
+type Destination string
+
+const (
+	// DefaultDestination is the destination of the clients which don't set one
+	DefaultDestination Destination = "default"
+	// ForwarderDestination is the destination of the metrics, events and service checks forwarder
+	ForwarderDestination Destination = "forwarder"
+	// LogsDestination is the destination of the logs pipelines
+	LogsDestination Destination = "logs"
+	// RemoteConfigDestination is the destination of the remote configuration
+	RemoteConfigDestination Destination = "remote_config"
+	// FlareDestination is the destination of the flares
+	FlareDestination Destination = "flare"
+	// AgentTelemetryDestination is the destination of the agent telemetry
+	AgentTelemetryDestination Destination = "agent_telemetry"
+	// APMDestination is the destination of the traces, stats and profiles of the trace-agent
+	APMDestination Destination = "apm"
+)
+
+const destinationsConfigKey = "http_destinations"
+
+// DestinationConfig holds the settings of the HTTP clients of a destination
+type DestinationConfig struct {
+	MinTLSVersion uint16
+	// CACertFiles are the PEM files of the CAs trusted in addition to the ones of the system
+	CACertFiles []string
+	// Proxies is nil when the destination is reached without a proxy
+	Proxies *pkgconfigmodel.Proxy
+	// HTTP2 forces HTTP/2 on or off, it's left to the caller when nil
+	HTTP2               *bool
+	KeepAlive           time.Duration
+	IdleConnTimeout     time.Duration
+	MaxIdleConnsPerHost int
+
+	// configured are the settings set in the configuration of the destination
+	configured map[string]bool
+}
+
+// GetDestinationConfig returns the settings of the HTTP clients of a destination:
+// the global settings, overridden by the ones of the `default` destination, then
+// by the ones of the destination itself.
+func GetDestinationConfig(cfg pkgconfigmodel.Reader, destination Destination) DestinationConfig {
+	dc := DestinationConfig{
+		MinTLSVersion:       minTLSVersionFromConfig(cfg),
+		Proxies:             cfg.GetProxies(),
+		KeepAlive:           30 * time.Second,
+		IdleConnTimeout:     45 * time.Second,
+		MaxIdleConnsPerHost: 5,
+		configured:          map[string]bool{},
+	}
+
+	destinations := cfg.GetStringMap(destinationsConfigKey)
+	for _, name := range []Destination{DefaultDestination, destination} {
+		value, ok := destinations[string(name)]
+		if !ok {
+			continue
+		}
+		settings, err := cast.ToStringMapE(value)
+		if err != nil {
+			log.Warnf("Invalid `%s.%s` settings: %v", destinationsConfigKey, name, err)
+			continue
+		}
+		dc.apply(string(name), settings)
+		if destination == DefaultDestination {
+			break
+		}
+	}
+	return dc
+}
+
+// apply overrides the settings with the ones set for a destination
+func (dc *DestinationConfig) apply(name string, settings map[string]interface{}) {
+	for key, value := range settings {
+		var err error
+		switch key {
+		case "min_tls_version":
+			var version uint16
+			if version, err = parseTLSVersion(cast.ToString(value)); err == nil {
+				dc.MinTLSVersion = version
+			}
+		case "ca_certs":
+			var caCertFiles []string
+			if caCertFiles, err = cast.ToStringSliceE(value); err == nil {
+				dc.CACertFiles = caCertFiles
+			}
+		case "use_proxy":
+			var useProxy bool
+			if useProxy, err = cast.ToBoolE(value); err == nil && !useProxy {
+				dc.Proxies = nil
+			}
+		case "proxy":
+			var proxy map[string]interface{}
+			if proxy, err = cast.ToStringMapE(value); err == nil {
+				dc.Proxies = &pkgconfigmodel.Proxy{
+					HTTP:    cast.ToString(proxy["http"]),
+					HTTPS:   cast.ToString(proxy["https"]),
+					NoProxy: cast.ToStringSlice(proxy["no_proxy"]),
+				}
+			}
+		case "http2":
+			var http2 bool
+			if http2, err = cast.ToBoolE(value); err == nil {
+				dc.HTTP2 = &http2
+			}
+		case "keep_alive":
+			var keepAlive time.Duration
+			if keepAlive, err = toDuration(value); err == nil {
+				dc.KeepAlive = keepAlive
+			}
+		case "idle_conn_timeout":
+			var idleConnTimeout time.Duration
+			if idleConnTimeout, err = toDuration(value); err == nil {
+				dc.IdleConnTimeout = idleConnTimeout
+			}
+		case "max_idle_conns_per_host":
+			var maxIdleConnsPerHost int
+			if maxIdleConnsPerHost, err = cast.ToIntE(value); err == nil {
+				dc.MaxIdleConnsPerHost = maxIdleConnsPerHost
+			}
+		default:
+			err = fmt.Errorf("unknown setting")
+		}
+		if err != nil {
+			log.Warnf("Invalid `%s.%s.%s` setting %#v: %v", destinationsConfigKey, name, key, value, err)
+			continue
+		}
+		dc.configured[key] = true
+	}
+}
+
+// NewTransport creates an *http.Transport for a destination of the agent. The
+// transport options are applied before the settings of the destination, so
+// that the settings configured by the user take precedence.
+func NewTransport(cfg pkgconfigmodel.Reader, destination Destination, transportOptions ...func(*http.Transport)) *http.Transport {
+	dc := GetDestinationConfig(cfg, destination)
+	tlsConfig := newTLSConfig(cfg, dc)
+
+	// Most of the following timeouts are a copy of Golang http.DefaultTransport
+	// They are mostly used to act as safeguards in case we forget to add a general
+	// timeout to our http clients.  Setting DialContext and TLSClientConfig has the
+	// desirable side-effect of disabling http/2; if removing those fields then
+	// consider the implication of the protocol switch for intakes and other http
+	// servers. See ForceAttemptHTTP2 in https://pkg.go.dev/net/http#Transport.
+
+	var tlsHandshakeTimeout time.Duration
+	if cfg.IsSet("tls_handshake_timeout") {
+		tlsHandshakeTimeout = cfg.GetDuration("tls_handshake_timeout")
+	} else {
+		tlsHandshakeTimeout = 10 * time.Second
+	}
+
+	// Control whether to disable RFC 6555 Fast Fallback ("Happy Eyeballs")
+	// By default this is disabled (set to a negative value).
+	// It can be set to 0 to use the default value, or an explicit duration.
+	fallbackDelay := -1 * time.Nanosecond
+	if cfg.IsSet("http_dial_fallback_delay") {
+		fallbackDelay = cfg.GetDuration("http_dial_fallback_delay")
+	}
+
+	transport := &http.Transport{
+		TLSClientConfig: tlsConfig,
+		DialContext: (&net.Dialer{
+			Timeout: 30 * time.Second,
+			// Enables TCP keepalives to detect broken connections
+			KeepAlive:     dc.KeepAlive,
+			FallbackDelay: fallbackDelay,
+		}).DialContext,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: dc.MaxIdleConnsPerHost,
+		// This parameter is set to avoid connections sitting idle in the pool indefinitely
+		IdleConnTimeout:       dc.IdleConnTimeout,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	if dc.Proxies != nil {
+		transport.Proxy = GetProxyTransportFunc(dc.Proxies, cfg)
+	}
+
+	for _, transportOption := range transportOptions {
+		transportOption(transport)
+	}
+
+	// the settings configured for the destination take precedence over the options
+	if dc.configured["idle_conn_timeout"] {
+		transport.IdleConnTimeout = dc.IdleConnTimeout
+	}
+	if dc.configured["max_idle_conns_per_host"] {
+		transport.MaxIdleConnsPerHost = dc.MaxIdleConnsPerHost
+	}
+	if dc.HTTP2 != nil {
+		if *dc.HTTP2 {
+			WithHTTP2()(transport)
+		} else {
+			withoutHTTP2(transport)
+		}
+	}
+
+	return transport
+}
+
+// NewClient creates an *http.Client for a destination of the agent
+func NewClient(cfg pkgconfigmodel.Reader, destination Destination, timeout time.Duration, transportOptions ...func(*http.Transport)) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: NewTransport(cfg, destination, transportOptions...),
+	}
+}
+
+func newTLSConfig(cfg pkgconfigmodel.Reader, dc DestinationConfig) *tls.Config {
+	// It’s OK to reuse the same file for all the http.Transport objects we create
+	// because all the writes to that file are protected by a global mutex.
+	// See https://github.com/golang/go/blob/go1.17.3/src/crypto/tls/common.go#L1316-L1318
+	keyLogWriterInit.Do(func() {
+		sslKeyLogFile := cfg.GetString("sslkeylogfile")
+		if sslKeyLogFile != "" {
+			var err error
+			keyLogWriter, err = os.OpenFile(sslKeyLogFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+			if err != nil {
+				log.Warnf("Failed to open %s for writing NSS keys: %v", sslKeyLogFile, err)
+			}
+		}
+	})
+
+	tlsConfig := &tls.Config{
+		KeyLogWriter:       keyLogWriter,
+		InsecureSkipVerify: cfg.GetBool("skip_ssl_validation"),
+		MinVersion:         dc.MinTLSVersion,
+	}
+
+	if len(dc.CACertFiles) > 0 {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			log.Warnf("Could not load the system CAs, only the configured CAs are trusted: %v", err)
+			rootCAs = x509.NewCertPool()
+		}
+		for _, caCertFile := range dc.CACertFiles {
+			caCert, err := os.ReadFile(caCertFile)
+			if err != nil {
+				log.Warnf("Could not read the CA file %s: %v", caCertFile, err)
+				continue
+			}
+			if !rootCAs.AppendCertsFromPEM(caCert) {
+				log.Warnf("Could not find any PEM certificate in the CA file %s", caCertFile)
+			}
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	return tlsConfig
+}
+
+// withoutHTTP2 disables HTTP/2, even if the transport was configured for it
+func withoutHTTP2(transport *http.Transport) {
+	transport.ForceAttemptHTTP2 = false
+	transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	if transport.TLSClientConfig != nil {
+		nextProtos := transport.TLSClientConfig.NextProtos[:0]
+		for _, proto := range transport.TLSClientConfig.NextProtos {
+			if proto != "h2" {
+				nextProtos = append(nextProtos, proto)
+			}
+		}
+		transport.TLSClientConfig.NextProtos = nextProtos
+	}
+}
+
+// toDuration converts a setting to a duration, numbers are a count of seconds
+// as for the other durations of the configuration
+func toDuration(value interface{}) (time.Duration, error) {
+	if s, ok := value.(string); ok {
+		if d, err := time.ParseDuration(s); err == nil {
+			return d, nil
+		}
+	}
+	seconds, err := cast.ToFloat64E(value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// parseTLSVersion parses a TLS version as set in the `min_tls_version` setting
+func parseTLSVersion(version string) (uint16, error) {
+	switch strings.ToLower(version) {
+	case "tlsv1.0":
+		return tls.VersionTLS10, nil
+	case "tlsv1.1":
+		return tls.VersionTLS11, nil
+	case "tlsv1.2":
+		return tls.VersionTLS12, nil
+	case "tlsv1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("unknown TLS version")
+}
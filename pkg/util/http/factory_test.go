@@ -0,0 +1,154 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package http
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	pkgconfigmodel "github.com/DataDog/datadog-agent/pkg/config/model"
+)
+
+func TestGetDestinationConfigDefaults(t *testing.T) {
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource("min_tls_version", "tlsv1.3")
+	cfg.SetWithoutSource("proxy.https", "https://proxy.com:3128")
+
+	dc := GetDestinationConfig(cfg, LogsDestination)
+	assert.Equal(t, uint16(tls.VersionTLS13), dc.MinTLSVersion)
+	require.NotNil(t, dc.Proxies)
+	assert.Equal(t, "https://proxy.com:3128", dc.Proxies.HTTPS)
+	assert.Nil(t, dc.HTTP2)
+	assert.Empty(t, dc.CACertFiles)
+	assert.Equal(t, 30*time.Second, dc.KeepAlive)
+	assert.Equal(t, 45*time.Second, dc.IdleConnTimeout)
+	assert.Equal(t, 5, dc.MaxIdleConnsPerHost)
+}
+
+func TestGetDestinationConfigOverrides(t *testing.T) {
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource("proxy.https", "https://proxy.com:3128")
+	cfg.SetWithoutSource("http_destinations", map[string]interface{}{
+		"default": map[string]interface{}{
+			"min_tls_version": "tlsv1.3",
+			"keep_alive":      10,
+		},
+		"logs": map[string]interface{}{
+			"http2":                   false,
+			"idle_conn_timeout":       "1m",
+			"max_idle_conns_per_host": 10,
+			"ca_certs":                []interface{}{"/etc/ssl/corporate.pem"},
+		},
+		"remote_config": map[string]interface{}{
+			"use_proxy":       false,
+			"min_tls_version": "tlsv1.2",
+		},
+		"flare": map[string]interface{}{
+			"proxy": map[string]interface{}{
+				"https":    "https://flare-proxy.com:3128",
+				"no_proxy": []interface{}{"localhost"},
+			},
+		},
+	})
+
+	dc := GetDestinationConfig(cfg, LogsDestination)
+	assert.Equal(t, uint16(tls.VersionTLS13), dc.MinTLSVersion)
+	require.NotNil(t, dc.HTTP2)
+	assert.False(t, *dc.HTTP2)
+	assert.Equal(t, 10*time.Second, dc.KeepAlive)
+	assert.Equal(t, time.Minute, dc.IdleConnTimeout)
+	assert.Equal(t, 10, dc.MaxIdleConnsPerHost)
+	assert.Equal(t, []string{"/etc/ssl/corporate.pem"}, dc.CACertFiles)
+	assert.Equal(t, "https://proxy.com:3128", dc.Proxies.HTTPS)
+
+	dc = GetDestinationConfig(cfg, RemoteConfigDestination)
+	assert.Equal(t, uint16(tls.VersionTLS12), dc.MinTLSVersion)
+	assert.Nil(t, dc.Proxies)
+	assert.Equal(t, 45*time.Second, dc.IdleConnTimeout)
+
+	dc = GetDestinationConfig(cfg, FlareDestination)
+	assert.Equal(t, &pkgconfigmodel.Proxy{HTTPS: "https://flare-proxy.com:3128", NoProxy: []string{"localhost"}}, dc.Proxies)
+
+	// invalid settings are ignored
+	cfg.SetWithoutSource("http_destinations", map[string]interface{}{
+		"apm": map[string]interface{}{
+			"min_tls_version": "tlsv1.9",
+			"keep_alive":      "forever",
+			"unknown":         true,
+		},
+	})
+	dc = GetDestinationConfig(cfg, APMDestination)
+	assert.Equal(t, uint16(tls.VersionTLS12), dc.MinTLSVersion)
+	assert.Equal(t, 30*time.Second, dc.KeepAlive)
+}
+
+func TestNewTransport(t *testing.T) {
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource("proxy.https", "https://proxy.com:3128")
+	cfg.SetWithoutSource("http_destinations", map[string]interface{}{
+		"remote_config": map[string]interface{}{
+			"use_proxy":         false,
+			"idle_conn_timeout": 10,
+		},
+		"logs": map[string]interface{}{
+			"http2": false,
+		},
+	})
+
+	transport := NewTransport(cfg, ForwarderDestination)
+	assert.NotNil(t, transport.Proxy)
+	assert.Equal(t, 45*time.Second, transport.IdleConnTimeout)
+
+	// the settings of the destination take precedence over the options
+	transport = NewTransport(cfg, RemoteConfigDestination, func(t *http.Transport) { t.IdleConnTimeout = 30 * time.Second })
+	assert.Nil(t, transport.Proxy)
+	assert.Equal(t, 10*time.Second, transport.IdleConnTimeout)
+
+	transport = NewTransport(cfg, LogsDestination, WithHTTP2())
+	assert.NotNil(t, transport.TLSNextProto)
+	assert.Empty(t, transport.TLSNextProto)
+	assert.NotContains(t, transport.TLSClientConfig.NextProtos, "h2")
+
+	transport = NewTransport(cfg, ForwarderDestination, WithHTTP2())
+	assert.Contains(t, transport.TLSClientConfig.NextProtos, "h2")
+}
+
+func TestNewClientCACerts(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, caCert, 0600))
+
+	cfg := configmock.New(t)
+
+	// the certificate of the server isn't trusted by default
+	_, err := NewClient(cfg, DefaultDestination, 5*time.Second).Get(server.URL)
+	assert.Error(t, err)
+
+	cfg.SetWithoutSource("http_destinations", map[string]interface{}{
+		"default": map[string]interface{}{
+			"ca_certs": []string{caFile},
+		},
+	})
+	resp, err := NewClient(cfg, AgentTelemetryDestination, 5*time.Second).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
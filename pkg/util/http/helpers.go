@@ -48,7 +48,7 @@ func parseResponse(res *http.Response, method string, URL string) (string, error
 // Get is a high level helper to query an URL and return its body as a string
 func Get(ctx context.Context, URL string, headers map[string]string, timeout time.Duration, cfg pkgconfigmodel.Reader) (string, error) {
 	client := http.Client{
-		Transport: CreateHTTPTransport(cfg),
+		Transport: NewTransport(cfg, DefaultDestination),
 		Timeout:   timeout,
 	}
 
@@ -72,7 +72,7 @@ func Get(ctx context.Context, URL string, headers map[string]string, timeout tim
 // Put is a high level helper to query an URL using the PUT method and return its body as a string
 func Put(ctx context.Context, URL string, headers map[string]string, body []byte, timeout time.Duration, cfg pkgconfigmodel.Reader) (string, error) {
 	client := http.Client{
-		Transport: CreateHTTPTransport(cfg),
+		Transport: NewTransport(cfg, DefaultDestination),
 		Timeout:   timeout,
 	}
 
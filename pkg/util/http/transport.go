@@ -9,13 +9,10 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
 	"sync"
-	"time"
 
 	"golang.org/x/net/http/httpproxy"
 	"golang.org/x/net/http2"
@@ -41,18 +38,9 @@ func logSafeURLString(url *url.URL) string {
 //
 // The returned result is one of the `tls.VersionTLSxxx` constants.
 func minTLSVersionFromConfig(cfg pkgconfigmodel.Reader) uint16 {
-	var min uint16
 	minTLSVersion := cfg.GetString("min_tls_version")
-	switch strings.ToLower(minTLSVersion) {
-	case "tlsv1.0":
-		min = tls.VersionTLS10
-	case "tlsv1.1":
-		min = tls.VersionTLS11
-	case "tlsv1.2":
-		min = tls.VersionTLS12
-	case "tlsv1.3":
-		min = tls.VersionTLS13
-	default:
+	min, err := parseTLSVersion(minTLSVersion)
+	if err != nil {
 		min = tls.VersionTLS12
 		if minTLSVersion != "" {
 			log.Warnf("Invalid `min_tls_version` %#v; using default", minTLSVersion)
@@ -61,76 +49,10 @@ func minTLSVersionFromConfig(cfg pkgconfigmodel.Reader) uint16 {
 	return min
 }
 
-// CreateHTTPTransport creates an *http.Transport for use in the agent
+// CreateHTTPTransport creates an *http.Transport for use in the agent, with the
+// settings of the default destination. See NewTransport.
 func CreateHTTPTransport(cfg pkgconfigmodel.Reader, transportOptions ...func(*http.Transport)) *http.Transport {
-	// It’s OK to reuse the same file for all the http.Transport objects we create
-	// because all the writes to that file are protected by a global mutex.
-	// See https://github.com/golang/go/blob/go1.17.3/src/crypto/tls/common.go#L1316-L1318
-	keyLogWriterInit.Do(func() {
-		sslKeyLogFile := cfg.GetString("sslkeylogfile")
-		if sslKeyLogFile != "" {
-			var err error
-			keyLogWriter, err = os.OpenFile(sslKeyLogFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
-			if err != nil {
-				log.Warnf("Failed to open %s for writing NSS keys: %v", sslKeyLogFile, err)
-			}
-		}
-	})
-
-	tlsConfig := &tls.Config{
-		KeyLogWriter:       keyLogWriter,
-		InsecureSkipVerify: cfg.GetBool("skip_ssl_validation"),
-	}
-
-	tlsConfig.MinVersion = minTLSVersionFromConfig(cfg)
-
-	// Most of the following timeouts are a copy of Golang http.DefaultTransport
-	// They are mostly used to act as safeguards in case we forget to add a general
-	// timeout to our http clients.  Setting DialContext and TLSClientConfig has the
-	// desirable side-effect of disabling http/2; if removing those fields then
-	// consider the implication of the protocol switch for intakes and other http
-	// servers. See ForceAttemptHTTP2 in https://pkg.go.dev/net/http#Transport.
-
-	var tlsHandshakeTimeout time.Duration
-	if cfg.IsSet("tls_handshake_timeout") {
-		tlsHandshakeTimeout = cfg.GetDuration("tls_handshake_timeout")
-	} else {
-		tlsHandshakeTimeout = 10 * time.Second
-	}
-
-	// Control whether to disable RFC 6555 Fast Fallback ("Happy Eyeballs")
-	// By default this is disabled (set to a negative value).
-	// It can be set to 0 to use the default value, or an explicit duration.
-	fallbackDelay := -1 * time.Nanosecond
-	if cfg.IsSet("http_dial_fallback_delay") {
-		fallbackDelay = cfg.GetDuration("http_dial_fallback_delay")
-	}
-
-	transport := &http.Transport{
-		TLSClientConfig: tlsConfig,
-		DialContext: (&net.Dialer{
-			Timeout: 30 * time.Second,
-			// Enables TCP keepalives to detect broken connections
-			KeepAlive:     30 * time.Second,
-			FallbackDelay: fallbackDelay,
-		}).DialContext,
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 5,
-		// This parameter is set to avoid connections sitting idle in the pool indefinitely
-		IdleConnTimeout:       45 * time.Second,
-		TLSHandshakeTimeout:   tlsHandshakeTimeout,
-		ExpectContinueTimeout: 1 * time.Second,
-	}
-
-	if proxies := cfg.GetProxies(); proxies != nil {
-		transport.Proxy = GetProxyTransportFunc(proxies, cfg)
-	}
-
-	for _, transportOption := range transportOptions {
-		transportOption(transport)
-	}
-
-	return transport
+	return NewTransport(cfg, DefaultDestination, transportOptions...)
 }
 
 // GetProxyTransportFunc return a proxy function for a http.Transport that
@@ -378,6 +378,7 @@ func (c *APIClient) connect() error {
 
 	// Creating informers
 	c.InformerFactory = c.GetInformerWithOptions(nil)
+	TrackInformerFactory(c.InformerFactory)
 
 	if pkgconfigsetup.Datadog().GetBool("admission_controller.enabled") ||
 		pkgconfigsetup.Datadog().GetBool("compliance_config.enabled") ||
@@ -387,6 +388,7 @@ func (c *APIClient) connect() error {
 		pkgconfigsetup.Datadog().GetBool("cluster_checks.enabled") ||
 		pkgconfigsetup.Datadog().GetBool("autoscaling.workload.enabled") {
 		c.DynamicInformerFactory = dynamicinformer.NewDynamicSharedInformerFactory(c.DynamicInformerCl, c.defaultInformerResyncPeriod)
+		TrackDynamicInformerFactory(c.DynamicInformerFactory)
 	}
 
 	if pkgconfigsetup.Datadog().GetBool("admission_controller.enabled") {
@@ -407,6 +409,8 @@ func (c *APIClient) connect() error {
 			nil,
 			informers.WithTweakListOptions(optionsForWebhook),
 		)
+		TrackInformerFactory(c.CertificateSecretInformerFactory)
+		TrackInformerFactory(c.WebhookConfigInformerFactory)
 	}
 
 	// Try to get apiserver version to confim connectivity
@@ -128,8 +128,16 @@ func StartControllers(ctx *ControllerContext) k8serrors.Aggregate {
 
 // startMetadataController starts the informers needed for metadata collection.
 // The synchronization of the informers is handled by the controller.
-func startMetadataController(ctx *ControllerContext, _ chan error) {
+func startMetadataController(ctx *ControllerContext, c chan error) {
 	useEndpointSlices := pkgconfigsetup.Datadog().GetBool("kubernetes_use_endpoint_slices")
+	endpointsResource := "endpoints"
+	if useEndpointSlices {
+		endpointsResource = "endpointslices.discovery.k8s.io"
+	}
+	if err := apiserver.CheckInformersEnabled(pkgconfigsetup.Datadog(), endpointsResource); err != nil {
+		c <- fmt.Errorf("cannot start the metadata controller: %w", err)
+		return
+	}
 	metaController := newMetadataController(
 		ctx.InformerFactory,
 		ctx.WorkloadMeta,
@@ -170,6 +178,10 @@ func startAutoscalersController(ctx *ControllerContext, c chan error) {
 
 // registerServicesInformer registers the services informer.
 func registerServicesInformer(ctx *ControllerContext, _ chan error) {
+	if apiserver.IsInformerDisabled(pkgconfigsetup.Datadog(), "services") {
+		log.Info("The services informer is disabled by cluster_agent.disabled_informers")
+		return
+	}
 	informer := ctx.InformerFactory.Core().V1().Services().Informer()
 
 	ctx.informersMutex.Lock()
@@ -179,6 +191,10 @@ func registerServicesInformer(ctx *ControllerContext, _ chan error) {
 
 // registerEndpointsInformer registers the endpoints informer.
 func registerEndpointsInformer(ctx *ControllerContext, _ chan error) {
+	if apiserver.IsInformerDisabled(pkgconfigsetup.Datadog(), "endpoints") {
+		log.Info("The endpoints informer is disabled by cluster_agent.disabled_informers")
+		return
+	}
 	informer := ctx.InformerFactory.Core().V1().Endpoints().Informer()
 
 	ctx.informersMutex.Lock()
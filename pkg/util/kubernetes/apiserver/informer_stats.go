@@ -0,0 +1,442 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+)
+
+// ResourceInformerStats describes the load a resource kind puts on the API server, through the
+// list and watch requests of the agent, and the footprint of the informer caches holding it
+type ResourceInformerStats struct {
+	// Resource is the group resource, like pods or deployments.apps
+	Resource string `json:"resource"`
+	// Disabled is true when the informers of the resource are disabled by configuration
+	Disabled bool `json:"disabled"`
+	// CachedObjects is the number of objects of the resource held by the tracked informer caches
+	CachedObjects int `json:"cached_objects"`
+	// EstimatedCacheBytes is the serialized size of the cached objects, a lower bound of their memory footprint,
+	// extrapolated from a sample of the objects
+	EstimatedCacheBytes int64 `json:"estimated_cache_bytes"`
+	// Streams are the distinct list and watch requests of the resource, one per informer
+	Streams []WatchStreamStats `json:"streams"`
+}
+
+// WatchStreamStats describes the traffic of the list and watch requests sharing the same
+// namespace and selectors, which is the traffic of a single informer
+type WatchStreamStats struct {
+	Namespace     string `json:"namespace,omitempty"`
+	LabelSelector string `json:"label_selector,omitempty"`
+	FieldSelector string `json:"field_selector,omitempty"`
+	Lists         int64  `json:"lists"`
+	// Relists is the number of list requests after the first one, caused by expired watches,
+	// resyncs or restarts of the informer
+	Relists int64 `json:"relists"`
+	// MeanRelistIntervalSeconds is the mean duration between two list requests
+	MeanRelistIntervalSeconds float64   `json:"mean_relist_interval_seconds"`
+	LastList                  time.Time `json:"last_list"`
+	Watches                   int64     `json:"watches"`
+	ActiveWatches             int64     `json:"active_watches"`
+	ListBytes                 int64     `json:"list_bytes"`
+	WatchBytes                int64     `json:"watch_bytes"`
+}
+
+// watchStream identifies the list and watch requests of an informer
+type watchStream struct {
+	groupResource string
+	namespace     string
+	labelSelector string
+	fieldSelector string
+}
+
+type watchStreamCounters struct {
+	lists         int64
+	relists       int64
+	relistTime    time.Duration
+	lastList      time.Time
+	watches       int64
+	activeWatches atomic.Int64
+	listBytes     atomic.Int64
+	watchBytes    atomic.Int64
+}
+
+// trafficRecorder accounts for the list and watch requests sent to the API server
+type trafficRecorder struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	streams map[watchStream]*watchStreamCounters
+}
+
+func newTrafficRecorder(now func() time.Time) *trafficRecorder {
+	return &trafficRecorder{
+		now:     now,
+		streams: make(map[watchStream]*watchStreamCounters),
+	}
+}
+
+// informerTraffic records the traffic of all the clients created by GetClientConfig
+var informerTraffic = newTrafficRecorder(time.Now)
+
+// requestKind is the kind of a list or watch request
+type requestKind int
+
+const (
+	// listRequest is a list, or the first page of a paginated list
+	listRequest requestKind = iota
+	// listPageRequest is a following page of a paginated list, requested with a continue token
+	listPageRequest
+	// watchRequest is a watch
+	watchRequest
+)
+
+// parseCollectionRequest returns the stream and the kind of a list or watch request, and false for other requests
+func parseCollectionRequest(request *http.Request) (stream watchStream, kind requestKind, ok bool) {
+	if request.Method != http.MethodGet {
+		return watchStream{}, listRequest, false
+	}
+
+	// collections are served at /api/{version}/[namespaces/{namespace}/]{resource}
+	// and /apis/{group}/{version}/[namespaces/{namespace}/]{resource}
+	segments := strings.Split(strings.Trim(request.URL.Path, "/"), "/")
+	var group string
+	switch {
+	case len(segments) >= 3 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) >= 4 && segments[0] == "apis":
+		group = segments[1]
+		segments = segments[3:]
+	default:
+		return watchStream{}, listRequest, false
+	}
+	if len(segments) == 3 && segments[0] == "namespaces" {
+		stream.namespace = segments[1]
+		segments = segments[2:]
+	}
+	if len(segments) != 1 {
+		return watchStream{}, listRequest, false
+	}
+
+	query := request.URL.Query()
+	stream.groupResource = schema.GroupResource{Group: group, Resource: segments[0]}.String()
+	stream.labelSelector = query.Get("labelSelector")
+	stream.fieldSelector = query.Get("fieldSelector")
+	switch {
+	case query.Get("watch") == "true" || query.Get("watch") == "1":
+		kind = watchRequest
+	case query.Get("continue") != "":
+		kind = listPageRequest
+	default:
+		kind = listRequest
+	}
+	return stream, kind, true
+}
+
+// record accounts for a request and returns the counters of its stream. The following pages of a paginated list
+// aren't counted as lists, only their bytes are.
+func (r *trafficRecorder) record(stream watchStream, kind requestKind) *watchStreamCounters {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counters, found := r.streams[stream]
+	if !found {
+		counters = &watchStreamCounters{}
+		r.streams[stream] = counters
+	}
+
+	switch kind {
+	case watchRequest:
+		counters.watches++
+		return counters
+	case listPageRequest:
+		return counters
+	}
+
+	now := r.now()
+	if counters.lists > 0 {
+		counters.relists++
+		counters.relistTime += now.Sub(counters.lastList)
+	}
+	counters.lists++
+	counters.lastList = now
+	return counters
+}
+
+// trackResponse counts the bytes of the response body of a request, and the watches being served
+func (r *trafficRecorder) trackResponse(counters *watchStreamCounters, isWatch bool, body io.ReadCloser) io.ReadCloser {
+	if !isWatch {
+		return &countingBody{ReadCloser: body, bytes: &counters.listBytes}
+	}
+
+	counters.activeWatches.Add(1)
+	return &countingBody{
+		ReadCloser: body,
+		bytes:      &counters.watchBytes,
+		onClose:    func() { counters.activeWatches.Add(-1) },
+	}
+}
+
+// stats returns the traffic of the recorded streams, by group resource
+func (r *trafficRecorder) stats() map[string][]WatchStreamStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make(map[string][]WatchStreamStats)
+	for stream, counters := range r.streams {
+		s := WatchStreamStats{
+			Namespace:     stream.namespace,
+			LabelSelector: stream.labelSelector,
+			FieldSelector: stream.fieldSelector,
+			Lists:         counters.lists,
+			Relists:       counters.relists,
+			LastList:      counters.lastList,
+			Watches:       counters.watches,
+			ActiveWatches: counters.activeWatches.Load(),
+			ListBytes:     counters.listBytes.Load(),
+			WatchBytes:    counters.watchBytes.Load(),
+		}
+		if counters.relists > 0 {
+			s.MeanRelistIntervalSeconds = (counters.relistTime / time.Duration(counters.relists)).Seconds()
+		}
+		stats[stream.groupResource] = append(stats[stream.groupResource], s)
+	}
+	for _, streams := range stats {
+		sort.Slice(streams, func(i, j int) bool {
+			if streams[i].Namespace != streams[j].Namespace {
+				return streams[i].Namespace < streams[j].Namespace
+			}
+			if streams[i].LabelSelector != streams[j].LabelSelector {
+				return streams[i].LabelSelector < streams[j].LabelSelector
+			}
+			return streams[i].FieldSelector < streams[j].FieldSelector
+		})
+	}
+	return stats
+}
+
+// countingBody counts the bytes read from a response body
+type countingBody struct {
+	io.ReadCloser
+	bytes   *atomic.Int64
+	onClose func()
+	closed  sync.Once
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes.Add(int64(n))
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	if b.onClose != nil {
+		b.closed.Do(b.onClose)
+	}
+	return b.ReadCloser.Close()
+}
+
+var trackedFactories = struct {
+	mu      sync.Mutex
+	typed   []informers.SharedInformerFactory
+	dynamic []dynamicinformer.DynamicSharedInformerFactory
+}{}
+
+// TrackInformerFactory adds the caches of the informers of the factory to the informer stats
+func TrackInformerFactory(factory informers.SharedInformerFactory) {
+	trackedFactories.mu.Lock()
+	defer trackedFactories.mu.Unlock()
+	trackedFactories.typed = append(trackedFactories.typed, factory)
+}
+
+// TrackDynamicInformerFactory adds the caches of the informers of the factory to the informer stats
+func TrackDynamicInformerFactory(factory dynamicinformer.DynamicSharedInformerFactory) {
+	trackedFactories.mu.Lock()
+	defer trackedFactories.mu.Unlock()
+	trackedFactories.dynamic = append(trackedFactories.dynamic, factory)
+}
+
+type cacheFootprint struct {
+	objects int
+	bytes   int64
+}
+
+const (
+	// footprintSampleSize is the number of objects of each cache whose size is estimated
+	footprintSampleSize = 100
+	// footprintCacheTTL is the period during which the footprints are served without being computed again
+	footprintCacheTTL = time.Minute
+)
+
+var footprintsCache = struct {
+	mu         sync.Mutex
+	computedAt time.Time
+	footprints map[string]cacheFootprint
+}{}
+
+// cacheFootprints returns the footprint of the caches of the started informers of the tracked factories, computed
+// at most once per footprintCacheTTL as the status may be queried often
+func cacheFootprints() map[string]cacheFootprint {
+	footprintsCache.mu.Lock()
+	defer footprintsCache.mu.Unlock()
+
+	if footprintsCache.footprints == nil || time.Since(footprintsCache.computedAt) > footprintCacheTTL {
+		footprintsCache.footprints = computeCacheFootprints()
+		footprintsCache.computedAt = time.Now()
+	}
+	return footprintsCache.footprints
+}
+
+// computeCacheFootprints returns the footprint of the caches of the started informers of the tracked factories.
+// The size of the objects is extrapolated from the size of the first footprintSampleSize objects of each cache.
+func computeCacheFootprints() map[string]cacheFootprint {
+	trackedFactories.mu.Lock()
+	defer trackedFactories.mu.Unlock()
+
+	// with a closed channel, WaitForCacheSync returns the started informers without waiting
+	started := make(chan struct{})
+	close(started)
+
+	footprints := make(map[string]cacheFootprint)
+	addStore := func(groupResource string, store cache.Store) {
+		objects := store.List()
+		sample := objects[:min(len(objects), footprintSampleSize)]
+
+		var sampleBytes int64
+		for _, obj := range sample {
+			sampleBytes += estimateObjectSize(obj)
+		}
+
+		footprint := footprints[groupResource]
+		footprint.objects += len(objects)
+		if len(sample) > 0 {
+			footprint.bytes += sampleBytes * int64(len(objects)) / int64(len(sample))
+		}
+		footprints[groupResource] = footprint
+	}
+
+	for _, factory := range trackedFactories.typed {
+		for informerType := range factory.WaitForCacheSync(started) {
+			if informerType.Kind() != reflect.Pointer {
+				continue
+			}
+			obj, ok := reflect.New(informerType.Elem()).Interface().(runtime.Object)
+			if !ok {
+				continue
+			}
+			gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+			if err != nil || len(gvks) == 0 {
+				continue
+			}
+			gvr, _ := meta.UnsafeGuessKindToResource(gvks[0])
+			// the informer exists as it was started, so it is returned without being created
+			addStore(gvr.GroupResource().String(), factory.InformerFor(obj, nil).GetStore())
+		}
+	}
+
+	for _, factory := range trackedFactories.dynamic {
+		for gvr := range factory.WaitForCacheSync(started) {
+			addStore(gvr.GroupResource().String(), factory.ForResource(gvr).Informer().GetStore())
+		}
+	}
+
+	return footprints
+}
+
+// estimateObjectSize returns the serialized size of a cached object
+func estimateObjectSize(obj interface{}) int64 {
+	if sized, ok := obj.(interface{ Size() int }); ok {
+		return int64(sized.Size())
+	}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		data, err := u.MarshalJSON()
+		if err != nil {
+			return 0
+		}
+		return int64(len(data))
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
+
+// IsInformerDisabled returns whether the informers of the group resource, like pods or
+// deployments.apps, are disabled by the cluster_agent.disabled_informers setting
+func IsInformerDisabled(cfg model.Reader, groupResource string) bool {
+	return slices.Contains(cfg.GetStringSlice("cluster_agent.disabled_informers"), groupResource)
+}
+
+// CheckInformersEnabled returns an error if the informers of one of the group resources are disabled by the
+// cluster_agent.disabled_informers setting, for the features that can't run without them
+func CheckInformersEnabled(cfg model.Reader, groupResources ...string) error {
+	for _, groupResource := range groupResources {
+		if IsInformerDisabled(cfg, groupResource) {
+			return fmt.Errorf("the %s informer is disabled by cluster_agent.disabled_informers", groupResource)
+		}
+	}
+	return nil
+}
+
+// GetInformerStats returns the list and watch traffic of the agent and the footprint of the
+// informer caches, by group resource
+func GetInformerStats(cfg model.Reader) []ResourceInformerStats {
+	return buildInformerStats(informerTraffic.stats(), cacheFootprints(), cfg.GetStringSlice("cluster_agent.disabled_informers"))
+}
+
+func buildInformerStats(traffic map[string][]WatchStreamStats, footprints map[string]cacheFootprint, disabled []string) []ResourceInformerStats {
+	resources := make(map[string]*ResourceInformerStats)
+	get := func(groupResource string) *ResourceInformerStats {
+		if stats, found := resources[groupResource]; found {
+			return stats
+		}
+		stats := &ResourceInformerStats{Resource: groupResource, Streams: []WatchStreamStats{}}
+		resources[groupResource] = stats
+		return stats
+	}
+
+	for groupResource, streams := range traffic {
+		get(groupResource).Streams = streams
+	}
+	for groupResource, footprint := range footprints {
+		stats := get(groupResource)
+		stats.CachedObjects = footprint.objects
+		stats.EstimatedCacheBytes = footprint.bytes
+	}
+	for _, groupResource := range disabled {
+		get(groupResource).Disabled = true
+	}
+
+	result := make([]ResourceInformerStats, 0, len(resources))
+	for _, stats := range resources {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Resource < result[j].Resource })
+	return result
+}
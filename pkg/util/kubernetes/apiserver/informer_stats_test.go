@@ -0,0 +1,195 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package apiserver
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+)
+
+func TestParseCollectionRequest(t *testing.T) {
+	for _, tc := range []struct {
+		method       string
+		url          string
+		isCollection bool
+		kind         requestKind
+		stream       watchStream
+	}{
+		{
+			method:       http.MethodGet,
+			url:          "/api/v1/pods?limit=500&resourceVersion=0",
+			isCollection: true,
+			stream:       watchStream{groupResource: "pods"},
+		},
+		{
+			method:       http.MethodGet,
+			url:          "/api/v1/namespaces/default/secrets?fieldSelector=metadata.name%3Dwebhook-certificate&watch=true",
+			isCollection: true,
+			kind:         watchRequest,
+			stream:       watchStream{groupResource: "secrets", namespace: "default", fieldSelector: "metadata.name=webhook-certificate"},
+		},
+		{
+			method:       http.MethodGet,
+			url:          "/apis/apps/v1/deployments?labelSelector=app%3Dweb&watch=1",
+			isCollection: true,
+			kind:         watchRequest,
+			stream:       watchStream{groupResource: "deployments.apps", labelSelector: "app=web"},
+		},
+		{
+			method:       http.MethodGet,
+			url:          "/api/v1/pods?continue=token&limit=500",
+			isCollection: true,
+			kind:         listPageRequest,
+			stream:       watchStream{groupResource: "pods"},
+		},
+		{
+			method:       http.MethodGet,
+			url:          "/api/v1/namespaces",
+			isCollection: true,
+			stream:       watchStream{groupResource: "namespaces"},
+		},
+		// single objects, subresources, non-GET requests and other endpoints aren't collections
+		{method: http.MethodGet, url: "/api/v1/namespaces/default"},
+		{method: http.MethodGet, url: "/api/v1/namespaces/default/pods/web"},
+		{method: http.MethodGet, url: "/apis/apps/v1/namespaces/default/deployments/web/scale"},
+		{method: http.MethodPost, url: "/api/v1/namespaces/default/events"},
+		{method: http.MethodGet, url: "/version"},
+		{method: http.MethodGet, url: "/apis/apps/v1"},
+	} {
+		t.Run(tc.method+" "+tc.url, func(t *testing.T) {
+			request, err := http.NewRequest(tc.method, "https://apiserver"+tc.url, nil)
+			require.NoError(t, err)
+
+			stream, kind, isCollection := parseCollectionRequest(request)
+			assert.Equal(t, tc.isCollection, isCollection)
+			assert.Equal(t, tc.kind, kind)
+			assert.Equal(t, tc.stream, stream)
+		})
+	}
+}
+
+func TestTrafficRecorder(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	recorder := newTrafficRecorder(func() time.Time { return now })
+	pods := watchStream{groupResource: "pods"}
+
+	counters := recorder.record(pods, listRequest)
+	body := recorder.trackResponse(counters, false, io.NopCloser(strings.NewReader("0123456789")))
+	_, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+
+	// the following pages of the list aren't relists
+	counters = recorder.record(pods, listPageRequest)
+	body = recorder.trackResponse(counters, false, io.NopCloser(strings.NewReader("01234")))
+	_, err = io.ReadAll(body)
+	require.NoError(t, err)
+
+	counters = recorder.record(pods, watchRequest)
+	watch := recorder.trackResponse(counters, true, io.NopCloser(strings.NewReader("event")))
+	_, err = io.ReadAll(watch)
+	require.NoError(t, err)
+
+	now = now.Add(10 * time.Minute)
+	recorder.record(pods, listRequest)
+	now = now.Add(20 * time.Minute)
+	recorder.record(pods, listRequest)
+
+	recorder.record(watchStream{groupResource: "pods", namespace: "default"}, listRequest)
+
+	stats := recorder.stats()
+	require.Len(t, stats["pods"], 2)
+	assert.Equal(t, WatchStreamStats{
+		Lists:                     3,
+		Relists:                   2,
+		MeanRelistIntervalSeconds: 900,
+		LastList:                  now,
+		Watches:                   1,
+		ActiveWatches:             1,
+		ListBytes:                 15,
+		WatchBytes:                5,
+	}, stats["pods"][0])
+	assert.Equal(t, "default", stats["pods"][1].Namespace)
+
+	// closing a watch more than once only ends it once
+	require.NoError(t, watch.Close())
+	require.NoError(t, watch.Close())
+	assert.Equal(t, int64(0), recorder.stats()["pods"][0].ActiveWatches)
+}
+
+func TestBuildInformerStats(t *testing.T) {
+	stats := buildInformerStats(
+		map[string][]WatchStreamStats{"pods": {{Lists: 1}}},
+		map[string]cacheFootprint{"pods": {objects: 2, bytes: 300}, "nodes": {objects: 1, bytes: 100}},
+		[]string{"endpoints"},
+	)
+
+	assert.Equal(t, []ResourceInformerStats{
+		{Resource: "endpoints", Disabled: true, Streams: []WatchStreamStats{}},
+		{Resource: "nodes", CachedObjects: 1, EstimatedCacheBytes: 100, Streams: []WatchStreamStats{}},
+		{Resource: "pods", CachedObjects: 2, EstimatedCacheBytes: 300, Streams: []WatchStreamStats{{Lists: 1}}},
+	}, stats)
+}
+
+func TestCacheFootprints(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+	)
+	factory := informers.NewSharedInformerFactory(client, 0)
+	nodes := factory.Core().V1().Nodes().Informer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	factory.Start(ctx.Done())
+	require.True(t, cache.WaitForCacheSync(ctx.Done(), nodes.HasSynced))
+
+	// an informer which isn't started doesn't hold any cache
+	factory.Core().V1().Services().Informer()
+
+	trackedFactories.mu.Lock()
+	previous := trackedFactories.typed
+	trackedFactories.typed = nil
+	trackedFactories.mu.Unlock()
+	defer func() {
+		trackedFactories.mu.Lock()
+		trackedFactories.typed = previous
+		trackedFactories.mu.Unlock()
+	}()
+	TrackInformerFactory(factory)
+
+	footprints := computeCacheFootprints()
+	require.Contains(t, footprints, "nodes")
+	assert.Equal(t, 2, footprints["nodes"].objects)
+	assert.Positive(t, footprints["nodes"].bytes)
+	assert.NotContains(t, footprints, "services")
+}
+
+func TestIsInformerDisabled(t *testing.T) {
+	cfg := configmock.New(t)
+	assert.False(t, IsInformerDisabled(cfg, "pods"))
+
+	cfg.SetWithoutSource("cluster_agent.disabled_informers", []string{"pods", "deployments.apps"})
+	assert.True(t, IsInformerDisabled(cfg, "pods"))
+	assert.True(t, IsInformerDisabled(cfg, "deployments.apps"))
+	assert.False(t, IsInformerDisabled(cfg, "deployments"))
+}
@@ -0,0 +1,56 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package apiserver
+
+import (
+	"embed"
+	"io"
+
+	"github.com/DataDog/datadog-agent/comp/core/status"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+)
+
+// InformersStatusProvider provides the informer stats section of the status output
+type InformersStatusProvider struct{}
+
+//go:embed status_templates
+var templatesFS embed.FS
+
+// Name returns the name
+func (InformersStatusProvider) Name() string {
+	return "Kubernetes API Server Informers"
+}
+
+// Section return the section
+func (InformersStatusProvider) Section() string {
+	return "Kubernetes API Server Informers"
+}
+
+// JSON populates the status map
+func (InformersStatusProvider) JSON(_ bool, stats map[string]interface{}) error {
+	populateInformersStatus(stats)
+
+	return nil
+}
+
+// Text renders the text output
+func (InformersStatusProvider) Text(_ bool, buffer io.Writer) error {
+	stats := make(map[string]interface{})
+	populateInformersStatus(stats)
+
+	return status.RenderText(templatesFS, "informers.tmpl", buffer, stats)
+}
+
+// HTML renders the html output
+func (InformersStatusProvider) HTML(_ bool, _ io.Writer) error {
+	return nil
+}
+
+func populateInformersStatus(stats map[string]interface{}) {
+	stats["informers"] = GetInformerStats(pkgconfigsetup.Datadog())
+}
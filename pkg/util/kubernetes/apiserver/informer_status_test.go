@@ -0,0 +1,32 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package apiserver
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInformersStatus(t *testing.T) {
+	informerTraffic.record(watchStream{groupResource: "services.test", namespace: "default"}, listRequest)
+
+	provider := InformersStatusProvider{}
+
+	stats := make(map[string]interface{})
+	require.NoError(t, provider.JSON(false, stats))
+	assert.NotEmpty(t, stats["informers"])
+
+	b := new(bytes.Buffer)
+	require.NoError(t, provider.Text(false, b))
+	assert.Contains(t, b.String(), "services.test")
+	assert.Contains(t, b.String(), "- Namespace: default")
+	assert.Contains(t, b.String(), "Lists: 1 (0 bytes), relists: 0")
+}
@@ -33,16 +33,27 @@ func NewCustomRoundTripper(rt http.RoundTripper, timeout time.Duration) *CustomR
 	}
 }
 
-// RoundTrip implements http.RoundTripper. It adds logging on request timeouts with more context.
+// RoundTrip implements http.RoundTripper. It adds logging on request timeouts with more context,
+// and accounts for the list and watch requests in the informer stats.
 func (rt *CustomRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
 	start := time.Now()
 
+	var counters *watchStreamCounters
+	stream, kind, isCollection := parseCollectionRequest(request)
+	if isCollection {
+		counters = informerTraffic.record(stream, kind)
+	}
+
 	response, err := rt.rt.RoundTrip(request)
 	if err, ok := err.(net.Error); ok && err.Timeout() || errors.Is(err, context.DeadlineExceeded) {
 		clientTimeouts.Inc()
 		log.Warnf("timeout trying to make the request in %v (kubernetes_apiserver_client_timeout: %v)", time.Since(start), rt.timeout)
 	}
 
+	if counters != nil && err == nil && response.Body != nil {
+		response.Body = informerTraffic.trackResponse(counters, kind == watchRequest, response.Body)
+	}
+
 	return response, err
 }
 
@@ -0,0 +1,229 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package autoscalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	le "github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/leaderelection/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	prometheusQueryEndpoint = "/api/v1/query"
+	// maxPrometheusResponseSize limits the size of the responses read from Prometheus
+	maxPrometheusResponseSize = 10 * 1024 * 1024
+)
+
+var (
+	prometheusRequests = telemetry.NewCounterWithOpts("", "prometheus_requests",
+		[]string{"status", le.JoinLeaderLabel}, "Counter of requests made to the Prometheus endpoint",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
+
+	errPrometheusNotConfigured = errors.New("Prometheus endpoint is not configured, set external_metrics_provider.prometheus.endpoint")
+)
+
+// QueryProcessor queries the value of external metrics
+type QueryProcessor interface {
+	QueryExternalMetric(queries []string, timeWindow time.Duration) map[string]Point
+}
+
+// PrometheusProcessor evaluates external metric queries against a Prometheus compatible endpoint,
+// such as an in-cluster Prometheus, Thanos or Mimir, instead of the Datadog API.
+type PrometheusProcessor struct {
+	endpoint        string
+	headers         map[string]string
+	client          *http.Client
+	parallelQueries int
+}
+
+// prometheusResponse is the response of the Prometheus instant query API
+type prometheusResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// prometheusSample is a sample of an instant vector
+type prometheusSample struct {
+	Metric map[string]string `json:"metric"`
+	Value  []interface{}     `json:"value"`
+}
+
+// NewPrometheusProcessor returns a new PrometheusProcessor
+func NewPrometheusProcessor() *PrometheusProcessor {
+	parallelQueries := pkgconfigsetup.Datadog().GetInt("external_metrics_provider.max_parallel_queries")
+	if parallelQueries > maxParallelQueries || parallelQueries <= 0 {
+		parallelQueries = maxParallelQueries
+	}
+
+	return &PrometheusProcessor{
+		endpoint: strings.TrimSuffix(pkgconfigsetup.Datadog().GetString("external_metrics_provider.prometheus.endpoint"), "/"),
+		headers:  pkgconfigsetup.Datadog().GetStringMapString("external_metrics_provider.prometheus.headers"),
+		client: &http.Client{
+			Timeout: time.Duration(pkgconfigsetup.Datadog().GetInt("external_metrics_provider.prometheus.timeout")) * time.Second,
+		},
+		parallelQueries: parallelQueries,
+	}
+}
+
+// QueryExternalMetric evaluates the PromQL queries at the current time. Queries are not batched, each query is
+// sent in its own request. The time window is not used, it is expressed by the range selectors of the queries.
+func (p *PrometheusProcessor) QueryExternalMetric(queries []string, _ time.Duration) map[string]Point {
+	if len(queries) == 0 {
+		return nil
+	}
+	responses := make(map[string]Point, len(queries))
+	if p.endpoint == "" {
+		for _, q := range queries {
+			responses[q] = Point{Error: errPrometheusNotConfigured}
+		}
+		return responses
+	}
+
+	currentTime := time.Now()
+	responsesLock := sync.Mutex{}
+
+	var group errgroup.Group
+	group.SetLimit(p.parallelQueries)
+
+	for _, query := range queries {
+		group.Go(func() error {
+			point, err := p.queryPrometheus(currentTime, query)
+			if err != nil {
+				prometheusRequests.Inc("error", le.JoinLeaderValue)
+				log.Debugf("Error while executing Prometheus query %q, err: %v", query, err)
+				point = Point{Error: err}
+			} else {
+				prometheusRequests.Inc("success", le.JoinLeaderValue)
+			}
+
+			responsesLock.Lock()
+			defer responsesLock.Unlock()
+			responses[query] = point
+			return nil
+		})
+	}
+	// Errors are handled in `responses`, so we don't need to check the group error
+	_ = group.Wait()
+
+	return responses
+}
+
+// queryPrometheus runs an instant query, the query must return a single sample
+func (p *PrometheusProcessor) queryPrometheus(currentTime time.Time, query string) (Point, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("time", strconv.FormatInt(currentTime.Unix(), 10))
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, p.endpoint+prometheusQueryEndpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return Point{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for name, value := range p.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Point{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPrometheusResponseSize))
+	if err != nil {
+		return Point{}, err
+	}
+
+	var promResp prometheusResponse
+	if err := json.Unmarshal(body, &promResp); err != nil {
+		return Point{}, fmt.Errorf("unable to decode Prometheus response (status code %d): %v", resp.StatusCode, err)
+	}
+	if promResp.Status != "success" {
+		return Point{}, fmt.Errorf("Prometheus query failed (%s): %s", promResp.ErrorType, promResp.Error)
+	}
+
+	return parsePrometheusResult(promResp.Data.ResultType, promResp.Data.Result)
+}
+
+// parsePrometheusResult converts the result of an instant query to a Point
+func parsePrometheusResult(resultType string, result json.RawMessage) (Point, error) {
+	var value []interface{}
+	switch resultType {
+	case "scalar":
+		if err := json.Unmarshal(result, &value); err != nil {
+			return Point{}, fmt.Errorf("unable to decode Prometheus scalar: %v", err)
+		}
+	case "vector":
+		var samples []prometheusSample
+		if err := json.Unmarshal(result, &samples); err != nil {
+			return Point{}, fmt.Errorf("unable to decode Prometheus vector: %v", err)
+		}
+		// We expect the query to result in a single sample, otherwise we are not able
+		// to determine which value we should take for autoscaling
+		if len(samples) == 0 {
+			return Point{}, NewProcessingError("Prometheus query returned no data")
+		}
+		if len(samples) > 1 {
+			return Point{}, NewProcessingError(fmt.Sprintf("Prometheus query returned %d series instead of 1, aggregate the query", len(samples)))
+		}
+		value = samples[0].Value
+	default:
+		return Point{}, NewProcessingError(fmt.Sprintf("unsupported Prometheus result type %q, the query must return a scalar or an instant vector", resultType))
+	}
+
+	return parsePrometheusValue(value)
+}
+
+// parsePrometheusValue parses a [<unix_time>, "<value>"] pair
+func parsePrometheusValue(value []interface{}) (Point, error) {
+	if len(value) != 2 {
+		return Point{}, fmt.Errorf("invalid Prometheus value: %v", value)
+	}
+	ts, ok := value[0].(float64)
+	if !ok {
+		return Point{}, fmt.Errorf("invalid Prometheus timestamp: %v", value[0])
+	}
+	rawValue, ok := value[1].(string)
+	if !ok {
+		return Point{}, fmt.Errorf("invalid Prometheus value: %v", value[1])
+	}
+	v, err := strconv.ParseFloat(rawValue, 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("invalid Prometheus value %q: %v", rawValue, err)
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return Point{}, NewProcessingError("Prometheus query returned a non finite value: " + rawValue)
+	}
+
+	return Point{
+		Value:     v,
+		Timestamp: int64(ts),
+		Valid:     true,
+	}, nil
+}
@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package autoscalers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+)
+
+func TestPrometheusQueryExternalMetric(t *testing.T) {
+	responses := map[string]string{
+		"scalar(up)": `{"status":"success","data":{"resultType":"scalar","result":[1700000000.5,"3"]}}`,
+		"sum(rate(http_requests_total[1m]))": `{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{},"value":[1700000000,"12.5"]}]}}`,
+		"rate(http_requests_total[1m])": `{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"pod":"a"},"value":[1700000000,"1"]},{"metric":{"pod":"b"},"value":[1700000000,"2"]}]}}`,
+		"absent_metric":  `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+		"nan":            `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"NaN"]}}`,
+		"http_requests[": `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/prometheus/api/v1/query", r.URL.Path)
+		assert.Equal(t, "tenant", r.Header.Get("X-Scope-OrgID"))
+		assert.NoError(t, r.ParseForm())
+		response, found := responses[r.PostForm.Get("query")]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource("external_metrics_provider.prometheus.endpoint", server.URL+"/prometheus/")
+	cfg.SetWithoutSource("external_metrics_provider.prometheus.headers", map[string]string{"X-Scope-OrgID": "tenant"})
+
+	queries := []string{"unknown"}
+	for query := range responses {
+		queries = append(queries, query)
+	}
+	points := NewPrometheusProcessor().QueryExternalMetric(queries, time.Minute)
+	require.Len(t, points, len(queries))
+
+	assert.Equal(t, Point{Value: 3, Timestamp: 1700000000, Valid: true}, points["scalar(up)"])
+	assert.Equal(t, Point{Value: 12.5, Timestamp: 1700000000, Valid: true}, points["sum(rate(http_requests_total[1m]))"])
+	for _, query := range []string{"rate(http_requests_total[1m])", "absent_metric", "nan", "http_requests[", "unknown"} {
+		assert.False(t, points[query].Valid, query)
+		assert.Error(t, points[query].Error, query)
+	}
+}
+
+func TestPrometheusQueryExternalMetricNotConfigured(t *testing.T) {
+	configmock.New(t)
+
+	points := NewPrometheusProcessor().QueryExternalMetric([]string{"scalar(up)"}, time.Minute)
+	require.Len(t, points, 1)
+	assert.False(t, points["scalar(up)"].Valid)
+	assert.ErrorIs(t, points["scalar(up)"].Error, errPrometheusNotConfigured)
+}
@@ -64,25 +64,7 @@ func (pu *podUnmarshaller) filteringDecoder(ptr unsafe.Pointer, iter *jsoniter.I
 		pod := &Pod{}
 		iter.ReadVal(pod)
 
-		// Quick exit for running/pending containers
-		if pod.Status.Phase == "Running" || pod.Status.Phase == "Pending" {
-			p.Items = append(p.Items, pod)
-			return true
-		}
-
-		// Only keep terminated pods where at least one container
-		// terminated after the cutoffTime
-		expired := true
-		for _, ctr := range pod.Status.Containers {
-			if ctr.State.Terminated == nil ||
-				ctr.State.Terminated.FinishedAt.IsZero() ||
-				ctr.State.Terminated.FinishedAt.After(cutoffTime) {
-				expired = false
-				break
-			}
-		}
-
-		if !expired {
+		if !isPodExpired(pod, cutoffTime) {
 			p.Items = append(p.Items, pod)
 		} else {
 			p.ExpiredCount++
@@ -103,3 +85,42 @@ func (pu *podUnmarshaller) filteringDecoder(ptr unsafe.Pointer, iter *jsoniter.I
 		return true
 	})
 }
+
+// filterExpired removes the pods which expired since the podlist was unmarshalled,
+// for podlists which are reused when the kubelet response didn't change.
+func (pu *podUnmarshaller) filterExpired(p *PodList) {
+	if pu.podExpirationDuration <= 0 {
+		return
+	}
+	cutoffTime := pu.timeNowFunction().Add(-1 * pu.podExpirationDuration)
+
+	items := make([]*Pod, 0, len(p.Items))
+	for _, pod := range p.Items {
+		if isPodExpired(pod, cutoffTime) {
+			p.ExpiredCount++
+			continue
+		}
+		items = append(items, pod)
+	}
+	p.Items = items
+}
+
+// isPodExpired returns whether the pod is terminated and all its
+// containers terminated before the cutoffTime
+func isPodExpired(pod *Pod, cutoffTime time.Time) bool {
+	// Quick exit for running/pending containers
+	if pod.Status.Phase == "Running" || pod.Status.Phase == "Pending" {
+		return false
+	}
+
+	// Only keep terminated pods where at least one container
+	// terminated after the cutoffTime
+	for _, ctr := range pod.Status.Containers {
+		if ctr.State.Terminated == nil ||
+			ctr.State.Terminated.FinishedAt.IsZero() ||
+			ctr.State.Terminated.FinishedAt.After(cutoffTime) {
+			return false
+		}
+	}
+	return true
+}
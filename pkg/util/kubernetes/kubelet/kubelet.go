@@ -11,7 +11,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"net/http"
+	"slices"
 	"sync"
 	"time"
 
@@ -55,6 +57,11 @@ type KubeUtil struct {
 	waitOnMissingContainer time.Duration
 	podUnmarshaller        *podUnmarshaller
 	podResourcesClient     *PodResourcesClient
+
+	// the last parsed podlist is reused as long as the kubelet response doesn't change
+	lastPodListMutex    sync.Mutex
+	lastPodListChecksum uint64
+	lastPodList         *PodList
 }
 
 func (ku *KubeUtil) init() error {
@@ -204,9 +211,27 @@ func (ku *KubeUtil) getLocalPodList(ctx context.Context) (*PodList, error) {
 		return nil, errors.NewRetriable("podlist", fmt.Errorf("unexpected status code %d on %s%s: %s", code, ku.kubeletClient.kubeletURL, kubeletPodPath, string(data)))
 	}
 
-	err = ku.podUnmarshaller.unmarshal(data, &pods)
-	if err != nil {
-		return nil, errors.NewRetriable("podlist", fmt.Errorf("unable to unmarshal podlist, invalid or null: %w", err))
+	// The kubelet doesn't set ETags on /pods, the last parsed podlist is reused
+	// when the response is identical.
+	checksum := podListChecksum(data)
+	parsed, reused := ku.lastParsedPodList(checksum)
+	if reused {
+		unchangedPodLists.Inc()
+	} else {
+		parsed = &PodList{}
+		err = ku.podUnmarshaller.unmarshal(data, parsed)
+		if err != nil {
+			return nil, errors.NewRetriable("podlist", fmt.Errorf("unable to unmarshal podlist, invalid or null: %w", err))
+		}
+		ku.storePodList(checksum, parsed)
+	}
+
+	// The parsed podlist is kept for the next queries, so the pods are copied
+	// before being enriched and handed to the callers.
+	pods = copyPodList(parsed)
+	if reused {
+		// pods may have expired since the podlist was parsed
+		ku.podUnmarshaller.filterExpired(&pods)
 	}
 
 	err = ku.addContainerResourcesData(ctx, pods.Items)
@@ -214,24 +239,21 @@ func (ku *KubeUtil) getLocalPodList(ctx context.Context) (*PodList, error) {
 		log.Errorf("Error adding container resources data: %s", err)
 	}
 
-	// ensure we dont have nil pods
 	tmpSlice := make([]*Pod, 0, len(pods.Items))
 	for _, pod := range pods.Items {
-		if pod != nil {
-			// Validate allocation size.
-			// Limits hardcoded here are huge enough to never be hit.
-			if len(pod.Status.Containers) > 10000 ||
-				len(pod.Status.InitContainers) > 10000 {
-				log.Errorf("Pod %s has a crazy number of containers: %d or init containers: %d. Skipping it!",
-					pod.Metadata.UID, len(pod.Status.Containers), len(pod.Status.InitContainers))
-				continue
-			}
-			allContainers := make([]ContainerStatus, 0, len(pod.Status.InitContainers)+len(pod.Status.Containers))
-			allContainers = append(allContainers, pod.Status.InitContainers...)
-			allContainers = append(allContainers, pod.Status.Containers...)
-			pod.Status.AllContainers = allContainers
-			tmpSlice = append(tmpSlice, pod)
+		// Validate allocation size.
+		// Limits hardcoded here are huge enough to never be hit.
+		if len(pod.Status.Containers) > 10000 ||
+			len(pod.Status.InitContainers) > 10000 {
+			log.Errorf("Pod %s has a crazy number of containers: %d or init containers: %d. Skipping it!",
+				pod.Metadata.UID, len(pod.Status.Containers), len(pod.Status.InitContainers))
+			continue
 		}
+		allContainers := make([]ContainerStatus, 0, len(pod.Status.InitContainers)+len(pod.Status.Containers))
+		allContainers = append(allContainers, pod.Status.InitContainers...)
+		allContainers = append(allContainers, pod.Status.Containers...)
+		pod.Status.AllContainers = allContainers
+		tmpSlice = append(tmpSlice, pod)
 	}
 	pods.Items = tmpSlice
 
@@ -241,6 +263,63 @@ func (ku *KubeUtil) getLocalPodList(ctx context.Context) (*PodList, error) {
 	return &pods, nil
 }
 
+// podListChecksum returns a checksum of the raw podlist returned by the kubelet
+func podListChecksum(data []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(data) //nolint:errcheck
+	return h.Sum64()
+}
+
+// lastParsedPodList returns the last parsed podlist if the kubelet response didn't
+// change since then, saving its parsing on nodes running many pods. The returned
+// podlist must not be modified.
+func (ku *KubeUtil) lastParsedPodList(checksum uint64) (*PodList, bool) {
+	ku.lastPodListMutex.Lock()
+	defer ku.lastPodListMutex.Unlock()
+
+	if ku.lastPodList == nil || ku.lastPodListChecksum != checksum {
+		return nil, false
+	}
+	return ku.lastPodList, true
+}
+
+func (ku *KubeUtil) storePodList(checksum uint64, pods *PodList) {
+	ku.lastPodListMutex.Lock()
+	defer ku.lastPodListMutex.Unlock()
+
+	ku.lastPodListChecksum = checksum
+	ku.lastPodList = pods
+}
+
+// copyPodList copies the pods of the podlist, skipping nil pods, so that their
+// container statuses can be modified without altering the original podlist.
+// The other fields of the pods are shared and must be treated as read-only.
+func copyPodList(podList *PodList) PodList {
+	pods := PodList{
+		Items:        make([]*Pod, 0, len(podList.Items)),
+		ExpiredCount: podList.ExpiredCount,
+	}
+	for _, pod := range podList.Items {
+		if pod == nil {
+			continue
+		}
+		podCopy := *pod
+		podCopy.Status.Containers = copyContainerStatuses(pod.Status.Containers)
+		podCopy.Status.InitContainers = copyContainerStatuses(pod.Status.InitContainers)
+		podCopy.Status.AllContainers = nil
+		pods.Items = append(pods.Items, &podCopy)
+	}
+	return pods
+}
+
+func copyContainerStatuses(containers []ContainerStatus) []ContainerStatus {
+	containersCopy := slices.Clone(containers)
+	for i := range containersCopy {
+		containersCopy[i].AllocatedResources = slices.Clone(containersCopy[i].AllocatedResources)
+	}
+	return containersCopy
+}
+
 // addContainerResourcesData modifies the given pod list, populating the
 // resources field of each container. If the pod resources API is not available,
 // this is a no-op.
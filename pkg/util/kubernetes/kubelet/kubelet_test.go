@@ -829,6 +829,64 @@ func (suite *KubeletTestSuite) TestPodListExpire() {
 	assert.Equal(suite.T(), expectedNames, podNames)
 }
 
+func (suite *KubeletTestSuite) TestPodListReusedWhenUnchanged() {
+	ctx := context.Background()
+	mockConfig := configmock.New(suite.T())
+	mockConfig.SetWithoutSource("kubernetes_pod_expiration_duration", 15*60)
+
+	kubelet, err := newDummyKubelet("./testdata/podlist_expired.json")
+	require.Nil(suite.T(), err)
+	ts, kubeletPort, err := kubelet.Start()
+	require.Nil(suite.T(), err)
+	defer ts.Close()
+
+	mockConfig.SetWithoutSource("kubernetes_kubelet_host", "localhost")
+	mockConfig.SetWithoutSource("kubernetes_http_kubelet_port", kubeletPort)
+	mockConfig.SetWithoutSource("kubernetes_https_kubelet_port", -1)
+	mockConfig.SetWithoutSource("kubelet_tls_verify", false)
+	mockConfig.SetWithoutSource("kubelet_auth_token_path", "")
+
+	kubeutil := suite.getCustomKubeUtil()
+	kubelet.dropRequests() // Throwing away first GETs
+
+	now, _ := time.Parse(time.RFC3339, "2019-02-18T16:00:06Z")
+	kubeutil.(*KubeUtil).podUnmarshaller.timeNowFunction = func() time.Time { return now }
+
+	pods, err := kubeutil.ForceGetLocalPodList(ctx)
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), pods.Items, 3)
+	assert.Equal(suite.T(), 1, pods.ExpiredCount)
+	kubelet.dropRequests()
+
+	// The kubelet response didn't change, copies of the pods parsed previously are returned
+	pods.Items[0].Status.Containers[0].AllocatedResources = append(pods.Items[0].Status.Containers[0].AllocatedResources, ContainerAllocatedResource{Name: "nvidia.com/gpu", ID: "GPU-0"})
+	reused, err := kubeutil.ForceGetLocalPodList(ctx)
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), reused.Items, 3)
+	for i := range pods.Items {
+		assert.NotSame(suite.T(), pods.Items[i], reused.Items[i])
+		assert.Equal(suite.T(), pods.Items[i].Metadata.UID, reused.Items[i].Metadata.UID)
+		assert.Len(suite.T(), reused.Items[i].Status.AllContainers, len(reused.Items[i].Status.InitContainers)+len(reused.Items[i].Status.Containers))
+	}
+	assert.Empty(suite.T(), reused.Items[0].Status.Containers[0].AllocatedResources)
+	kubelet.dropRequests()
+
+	// Pods which expired since then are still filtered out
+	now = now.Add(2 * time.Hour)
+	reused, err = kubeutil.ForceGetLocalPodList(ctx)
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), reused.Items, 1)
+	assert.Equal(suite.T(), "dd-agent-ntepl", reused.Items[0].Metadata.Name)
+	assert.Equal(suite.T(), 3, reused.ExpiredCount)
+	kubelet.dropRequests()
+
+	// A new response is parsed again
+	require.Nil(suite.T(), kubelet.loadPodList("./testdata/podlist_1.8-2.json"))
+	pods, err = kubeutil.ForceGetLocalPodList(ctx)
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), pods.Items, 7)
+}
+
 func TestKubeletTestSuite(t *testing.T) {
 	pkglogsetup.SetupLogger(
 		pkglogsetup.LoggerName("test"),
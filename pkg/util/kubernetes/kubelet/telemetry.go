@@ -22,4 +22,13 @@ var (
 		"Count of kubelet queries by path and response code. The response code defaults to 0 for unachieved queries. (The metric doesn't include kubelet check queries).",
 		telemetry.Options{NoDoubleUnderscoreSep: true},
 	)
+
+	// unchangedPodLists tracks the podlist queries whose response didn't change, and wasn't parsed again.
+	unchangedPodLists = telemetry.NewCounterWithOpts(
+		subsystem,
+		"unchanged_podlists",
+		[]string{},
+		"Count of kubelet podlist queries whose response didn't change since the previous query, and whose parsing was skipped.",
+		telemetry.Options{NoDoubleUnderscoreSep: true},
+	)
 )
@@ -41,6 +41,8 @@ import (
 const (
 	ctrName     = "ctr"
 	allCtrsName = "all-ctrs"
+	podName     = "pod"
+	allPodsName = "allPods"
 	configName  = "config"
 	stateName   = "state"
 	openTimeout = 30 * time.Second
@@ -49,6 +51,8 @@ const (
 var (
 	ctrBkt     = []byte(ctrName)
 	allCtrsBkt = []byte(allCtrsName)
+	podBkt     = []byte(podName)
+	allPodsBkt = []byte(allPodsName)
 	configKey  = []byte(configName)
 	stateKey   = []byte(stateName)
 )
@@ -134,6 +138,56 @@ func (client *DBClient) GetAllContainers() ([]Container, error) {
 	return res, nil
 }
 
+// GetAllPods returns all the pods present in the DB
+func (client *DBClient) GetAllPods() ([]Pod, error) {
+	var res []Pod
+
+	db, err := client.getDBCon()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if errClose := db.Close(); errClose != nil {
+			log.Warnf("failed to close libpod db: %q", err)
+		}
+	}()
+
+	err = db.View(func(tx *bolt.Tx) error {
+		allPodsBucket := tx.Bucket(allPodsBkt)
+		podBucket := tx.Bucket(podBkt)
+		if allPodsBucket == nil || podBucket == nil {
+			// no pod was ever created
+			return nil
+		}
+
+		return allPodsBucket.ForEach(func(id, _ []byte) error {
+			bkt := podBucket.Bucket(id)
+			if bkt == nil {
+				return fmt.Errorf("state is inconsistent - pod ID %s in all pods, but pod not found", string(id))
+			}
+
+			rawPodConfig := bkt.Get(configKey)
+			if rawPodConfig == nil {
+				return fmt.Errorf("pod %s missing config key in DB", string(id))
+			}
+
+			var podConfig PodConfig
+			if err := json.Unmarshal(rawPodConfig, &podConfig); err != nil {
+				return fmt.Errorf("error unmarshalling pod config: %s", err)
+			}
+
+			res = append(res, Pod{Config: &podConfig})
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 // Note: original function comes from https://github.com/containers/podman/blob/v3.4.1/libpod/boltdb_state_internal.go
 // It was adapted as we don't need to write any information to the DB.
 func (client *DBClient) getDBCon() (*bolt.DB, error) {
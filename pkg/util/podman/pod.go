@@ -0,0 +1,29 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+//go:build podman
+
+package podman
+
+// Note: PodConfig has been copied from
+// https://github.com/containers/podman/blob/v3.4.1/libpod/pod.go with only
+// the attributes that we need.
+
+// Pod holds the configuration of a pod, a group of containers sharing some
+// namespaces
+type Pod struct {
+	Config *PodConfig
+}
+
+// PodConfig represents a pod's static configuration
+type PodConfig struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Namespace the pod is in
+	Namespace string `json:"namespace,omitempty"`
+
+	// Labels contains labels applied to the pod
+	Labels map[string]string `json:"labels"`
+}
@@ -25,7 +25,7 @@ import (
 // The functions in this file have been copied from
 // https://github.com/containers/podman/blob/v5.0.0/libpod/sqlite_state.go
 // The code has been adapted a bit to our needs. The only functions of that file
-// that we need are AllContainers() and NewSqliteState().
+// that we need are AllContainers(), AllPods() and NewSqliteState().
 //
 // This code could break in future versions of Podman. This has been tried with
 // v4.9.2 and v5.0.0.
@@ -112,3 +112,43 @@ func (client *SQLDBClient) GetAllContainers() ([]Container, error) {
 
 	return res, nil
 }
+
+// GetAllPods retrieves all the pods in the database.
+func (client *SQLDBClient) GetAllPods() ([]Pod, error) {
+	var res []Pod
+
+	conn, err := client.getDBCon()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if errClose := conn.Close(); errClose != nil {
+			log.Warnf("failed to close sqlite db: %q", err)
+		}
+	}()
+
+	rows, err := conn.Query("SELECT JSON FROM PodConfig;")
+	if err != nil {
+		return nil, fmt.Errorf("retrieving all pods from database: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var configJSON string
+		if err := rows.Scan(&configJSON); err != nil {
+			return nil, fmt.Errorf("scanning pod from database: %w", err)
+		}
+
+		pod := Pod{Config: new(PodConfig)}
+		if err := json.Unmarshal([]byte(configJSON), pod.Config); err != nil {
+			return nil, fmt.Errorf("unmarshalling pod config: %w", err)
+		}
+
+		res = append(res, pod)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -7,6 +7,9 @@
 package profiling
 
 import (
+	"fmt"
+	"net"
+	"strconv"
 	"sync"
 
 	"gopkg.in/DataDog/dd-trace-go.v1/profiler"
@@ -26,6 +29,40 @@ const (
 	ProfilingLocalURLTemplate = "http://%v/profiling/v1/input"
 )
 
+// TraceAgentProxyURL returns the URL of the profiling proxy endpoint of the local trace-agent listening on the given
+// receiver port. Sending the profiles through the trace-agent doesn't require the Agent process to reach the intake.
+func TraceAgentProxyURL(receiverPort int) string {
+	return fmt.Sprintf(ProfilingLocalURLTemplate, net.JoinHostPort("localhost", strconv.Itoa(receiverPort)))
+}
+
+// IntakeURL returns the URL the profiles of an Agent process are sent to: the profileDDURL override if set, otherwise
+// the profiling proxy of the local trace-agent if useTraceAgentProxy is set and the trace-agent receiver is enabled,
+// otherwise the profiling intake of the site.
+func IntakeURL(profileDDURL string, useTraceAgentProxy bool, receiverPort int, site string) string {
+	if profileDDURL != "" {
+		return profileDDURL
+	}
+	if useTraceAgentProxy {
+		if receiverPort > 0 {
+			return TraceAgentProxyURL(receiverPort)
+		}
+		log.Warnf("The trace-agent receiver is disabled, sending the internal profiles to the profiling intake instead of the trace-agent")
+	}
+	return fmt.Sprintf(ProfilingURLTemplate, site)
+}
+
+// Tags returns the tags attached to the profiles of an Agent process: the user-defined extra tags, the Agent version and
+// the Agent subsystem running in the process.
+func Tags(extraTags []string, subsystem string, agentVersion string) []string {
+	tags := make([]string, 0, len(extraTags)+2)
+	tags = append(tags, extraTags...)
+	tags = append(tags, "version:"+agentVersion)
+	if subsystem != "" {
+		tags = append(tags, "subsystem:"+subsystem)
+	}
+	return tags
+}
+
 // Start initiates profiling with the supplied parameters;
 // this function is thread-safe.
 func Start(settings Settings) error {
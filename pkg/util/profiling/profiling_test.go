@@ -30,3 +30,21 @@ func TestProfiling(t *testing.T) {
 
 	Stop()
 }
+
+func TestTraceAgentProxyURL(t *testing.T) {
+	assert.Equal(t, "http://localhost:8126/profiling/v1/input", TraceAgentProxyURL(8126))
+}
+
+func TestIntakeURL(t *testing.T) {
+	assert.Equal(t, "https://intake.profile.datadoghq.eu/v1/input", IntakeURL("", false, 8126, "datadoghq.eu"))
+	assert.Equal(t, "http://localhost:8126/profiling/v1/input", IntakeURL("", true, 8126, "datadoghq.eu"))
+	// the trace-agent receiver is disabled
+	assert.Equal(t, "https://intake.profile.datadoghq.eu/v1/input", IntakeURL("", true, 0, "datadoghq.eu"))
+	// an explicit URL takes precedence
+	assert.Equal(t, "https://profiling.example.com/v1/input", IntakeURL("https://profiling.example.com/v1/input", true, 8126, "datadoghq.eu"))
+}
+
+func TestTags(t *testing.T) {
+	assert.Equal(t, []string{"team:agent", "version:7.0.0", "subsystem:process-agent"}, Tags([]string{"team:agent"}, "process-agent", "7.0.0"))
+	assert.Equal(t, []string{"version:7.0.0"}, Tags(nil, "", "7.0.0"))
+}
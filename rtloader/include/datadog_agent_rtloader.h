@@ -667,6 +667,43 @@ DATADOG_AGENT_RTLOADER_API void init_pymem_stats(rtloader_t *);
 */
 DATADOG_AGENT_RTLOADER_API void get_pymem_stats(rtloader_t *, pymem_stats_t *);
 
+/*! \fn void get_pymem_thread_stats(rtloader_t *, pymem_stats_t *)
+    \brief Retrieve a snapshot of the memory allocated and freed by the calling thread in the python interpreter.
+    \param rtloader A pointer to the RtLoader instance.
+    \param stats A pointer to pymem_stats_t structure that will be updated with the new values.
+
+    The checks run on a thread of their own during their runs, the difference between two snapshots taken before
+    and after a run is the memory allocated by the check during the run.
+*/
+DATADOG_AGENT_RTLOADER_API void get_pymem_thread_stats(rtloader_t *, pymem_stats_t *);
+
+/*! \fn unsigned long get_thread_id(rtloader_t *)
+    \brief Returns the python identifier of the calling thread.
+    \param rtloader A pointer to the RtLoader instance.
+    \return The python identifier of the calling thread.
+*/
+DATADOG_AGENT_RTLOADER_API unsigned long get_thread_id(rtloader_t *);
+
+/*! \fn int interrupt_thread(rtloader_t *, unsigned long)
+    \brief Raises a TimeoutError in the python code run by a thread, to interrupt a check running for too long.
+    \param rtloader A pointer to the RtLoader instance.
+    \param thread_id The python identifier of the thread, returned by get_thread_id.
+    \return 1 if the thread was found, 0 otherwise.
+
+    The exception is raised when the thread runs python code again, a thread blocked in a C call isn't interrupted.
+    The caller must hold the GIL.
+*/
+DATADOG_AGENT_RTLOADER_API int interrupt_thread(rtloader_t *, unsigned long);
+
+/*! \fn void clear_thread_interrupt(rtloader_t *, unsigned long)
+    \brief Clears the TimeoutError raised by interrupt_thread, if it wasn't raised yet.
+    \param rtloader A pointer to the RtLoader instance.
+    \param thread_id The python identifier of the thread, returned by get_thread_id.
+
+    The caller must hold the GIL.
+*/
+DATADOG_AGENT_RTLOADER_API void clear_thread_interrupt(rtloader_t *, unsigned long);
+
 /*! \fn void set_obfuscate_mongodb_string_cb(rtloader_t *, cb_obfuscate_mongodb_string_t)
     \brief Sets a callback to be used by rtloader to allow retrieving a value for a given
     check instance.
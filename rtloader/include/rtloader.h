@@ -496,6 +496,47 @@ public:
     {
     }
 
+    //! getPymemThreadStats member.
+    /*!
+      \param stats Stats snapshot output.
+
+      Retrieve a snapshot of the python allocator statistics of the calling thread.
+    */
+    virtual void getPymemThreadStats(pymem_stats_t &stats)
+    {
+    }
+
+    //! getThreadId member.
+    /*!
+      \return The python identifier of the calling thread.
+    */
+    virtual unsigned long getThreadId()
+    {
+        return 0;
+    }
+
+    //! interruptThread member.
+    /*!
+      \param thread_id The python identifier of the thread.
+      \return A boolean indicating whether the thread was found.
+
+      Raise a TimeoutError in the python code run by the thread. The GIL must be held.
+    */
+    virtual bool interruptThread(unsigned long thread_id)
+    {
+        return false;
+    }
+
+    //! clearThreadInterrupt member.
+    /*!
+      \param thread_id The python identifier of the thread.
+
+      Clear the exception raised by interruptThread if it wasn't raised yet. The GIL must be held.
+    */
+    virtual void clearThreadInterrupt(unsigned long thread_id)
+    {
+    }
+
     //! setObfuscateMongoDBStringCb member.
     /*!
       \param A cb_obfuscate_mongodb_string_t function pointer to the CGO callback.
@@ -602,3 +602,29 @@ void get_pymem_stats(rtloader_t *rtloader, pymem_stats_t *stats)
     }
     AS_TYPE(RtLoader, rtloader)->getPymemStats(*stats);
 }
+
+void get_pymem_thread_stats(rtloader_t *rtloader, pymem_stats_t *stats)
+{
+    if (stats == NULL) {
+        return;
+    }
+    AS_TYPE(RtLoader, rtloader)->getPymemThreadStats(*stats);
+}
+
+/*
+ * python threads API
+ */
+unsigned long get_thread_id(rtloader_t *rtloader)
+{
+    return AS_TYPE(RtLoader, rtloader)->getThreadId();
+}
+
+int interrupt_thread(rtloader_t *rtloader, unsigned long thread_id)
+{
+    return AS_TYPE(RtLoader, rtloader)->interruptThread(thread_id) ? 1 : 0;
+}
+
+void clear_thread_interrupt(rtloader_t *rtloader, unsigned long thread_id)
+{
+    AS_TYPE(RtLoader, rtloader)->clearThreadInterrupt(thread_id);
+}
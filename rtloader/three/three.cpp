@@ -243,6 +243,21 @@ rtloader_gilstate_t Three::GILEnsure()
     return DATADOG_AGENT_RTLOADER_GIL_UNLOCKED;
 }
 
+unsigned long Three::getThreadId()
+{
+    return PyThread_get_thread_ident();
+}
+
+bool Three::interruptThread(unsigned long thread_id)
+{
+    return PyThreadState_SetAsyncExc(thread_id, PyExc_TimeoutError) > 0;
+}
+
+void Three::clearThreadInterrupt(unsigned long thread_id)
+{
+    PyThreadState_SetAsyncExc(thread_id, NULL);
+}
+
 void Three::GILRelease(rtloader_gilstate_t state)
 {
     if (state == DATADOG_AGENT_RTLOADER_GIL_LOCKED) {
@@ -112,6 +112,11 @@ public:
 
     void initPymemStats();
     void getPymemStats(pymem_stats_t &);
+    void getPymemThreadStats(pymem_stats_t &);
+
+    unsigned long getThreadId();
+    bool interruptThread(unsigned long thread_id);
+    void clearThreadInterrupt(unsigned long thread_id);
 
     // _util API
     virtual void setSubprocessOutputCb(cb_get_subprocess_output_t);
@@ -23,6 +23,14 @@
 #    include <malloc/malloc.h>
 #endif
 
+// The allocations of the calling thread. The checks run on a thread of
+// their own during their runs, which makes it possible to account for the
+// memory allocated by each check. The memory allocated by a thread may be
+// freed by another one, so only the differences between two snapshots are
+// meaningful.
+static thread_local size_t pymemThreadInuse = 0;
+static thread_local size_t pymemThreadAlloc = 0;
+
 void Three::initPymemStats()
 {
     PyObject_GetArenaAllocator(&_pymallocPrev);
@@ -49,6 +57,12 @@ void Three::getPymemStats(pymem_stats_t &s)
     s.alloc = _pymemAlloc;
 }
 
+void Three::getPymemThreadStats(pymem_stats_t &s)
+{
+    s.inuse = pymemThreadInuse;
+    s.alloc = pymemThreadAlloc;
+}
+
 // Tracking allocations by Pymalloc. Pymalloc is the optimized
 // allocator used for small-sized allocations in OBJ and MEM
 // domains. These functions track the amount of memory requested by the
@@ -61,6 +75,8 @@ void *Three::pymallocAlloc(size_t size)
     if (ptr != NULL) {
         _pymemInuse += size;
         _pymemAlloc += size;
+        pymemThreadInuse += size;
+        pymemThreadAlloc += size;
     }
     return ptr;
 }
@@ -69,6 +85,7 @@ void Three::pymallocFree(void *ptr, size_t size)
 {
     _pymallocPrev.free(_pymallocPrev.ctx, ptr, size);
     _pymemInuse -= size;
+    pymemThreadInuse -= size;
 }
 
 void *Three::pymallocAllocCb(void *ctx, size_t size)
@@ -112,6 +129,8 @@ void Three::pyrawTrackAlloc(void *ptr)
     size_t size = pyrawAllocSize(ptr);
     _pymemInuse += size;
     _pymemAlloc += size;
+    pymemThreadInuse += size;
+    pymemThreadAlloc += size;
 }
 
 void Three::pyrawTrackFree(void *ptr)
@@ -121,6 +140,7 @@ void Three::pyrawTrackFree(void *ptr)
     }
     size_t size = pyrawAllocSize(ptr);
     _pymemInuse -= size;
+    pymemThreadInuse -= size;
 }
 
 void *Three::pyrawMalloc(size_t size)